@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/momokii/go-cli-notes/internal/service"
+)
+
+// runBackup runs a backup command: create (default), list or restore <name>
+func runBackup(svc *service.BackupService, args []string) error {
+	command := "create"
+	if len(args) > 0 {
+		command = args[0]
+	}
+
+	ctx := context.Background()
+	switch command {
+	case "create":
+		result, err := svc.Create(ctx)
+		if err != nil {
+			return err
+		}
+		slog.Info("Backup written", "name", result.Name, "bytes", result.Size)
+
+	case "list":
+		backups, err := svc.List(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "CREATED AT\tSIZE\tNAME")
+		for _, backup := range backups {
+			fmt.Fprintf(w, "%s\t%d\t%s\n", backup.CreatedAt.Format("2006-01-02 15:04:05"), backup.Size, backup.Name)
+		}
+		return w.Flush()
+
+	case "restore":
+		if len(args) < 2 {
+			return fmt.Errorf("usage: backup restore <name>, see backup list")
+		}
+		result, err := svc.Restore(ctx, args[1])
+		if err != nil {
+			return err
+		}
+		tables := make([]string, 0, len(result.Restored))
+		for table := range result.Restored {
+			tables = append(tables, table)
+		}
+		sort.Strings(tables)
+		for _, table := range tables {
+			slog.Info("Restored table", "table", table, "rows", result.Restored[table], "already_present", result.Skipped[table])
+		}
+
+	default:
+		return fmt.Errorf("unknown backup command %q, expected create, list or restore", command)
+	}
+
+	return nil
+}
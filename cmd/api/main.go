@@ -6,17 +6,26 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 	"github.com/joho/godotenv"
 	"github.com/momokii/go-cli-notes/internal/api/handler"
 	"github.com/momokii/go-cli-notes/internal/api/middleware"
+	"github.com/momokii/go-cli-notes/internal/api/openapi"
 	"github.com/momokii/go-cli-notes/internal/api/router"
+	"github.com/momokii/go-cli-notes/internal/clipper"
 	"github.com/momokii/go-cli-notes/internal/config"
+	"github.com/momokii/go-cli-notes/internal/embedding"
+	"github.com/momokii/go-cli-notes/internal/events"
+	"github.com/momokii/go-cli-notes/internal/llm"
+	"github.com/momokii/go-cli-notes/internal/mail"
 	"github.com/momokii/go-cli-notes/internal/repository"
 	"github.com/momokii/go-cli-notes/internal/service"
+	"github.com/momokii/go-cli-notes/internal/storage"
 	"github.com/momokii/go-cli-notes/internal/util"
+	"github.com/momokii/go-cli-notes/internal/webhook"
 )
 
 const (
@@ -40,12 +49,7 @@ func main() {
 	slog.Info("Starting Knowledge Garden API", "version", API_VERSION)
 
 	// Connect to database
-	db, err := repository.NewDB(
-		cfg.Database.DSN(),
-		cfg.Database.MaxOpenConns,
-		cfg.Database.MaxIdleConns,
-		cfg.Database.ConnMaxLifetime,
-	)
+	db, err := repository.NewDB(cfg.Database)
 	if err != nil {
 		slog.Error("Failed to connect to database", "error", err)
 		os.Exit(1)
@@ -53,11 +57,32 @@ func main() {
 	defer db.Close()
 
 	// Log database connection info
-	if cfg.Database.DatabaseURL != "" {
+	if cfg.Database.Driver == "sqlite" {
+		slog.Info("Opened SQLite database", "path", cfg.Database.SQLitePath)
+	} else if cfg.Database.DatabaseURL != "" {
 		slog.Info("Connected to database", "connection", "DATABASE_URL")
 	} else {
 		slog.Info("Connected to database", "host", cfg.Database.Host, "port", cfg.Database.Port)
 	}
+	if db.Replica != nil {
+		slog.Info("Connected to read replica")
+	}
+
+	// `api migrate [up|down|status]` runs the embedded migrations and exits
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(db, os.Args[2:]); err != nil {
+			slog.Error("Migration failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if cfg.Database.AutoMigrate {
+		if err := runMigrate(db, []string{"up"}); err != nil {
+			slog.Error("Failed to apply migrations", "error", err)
+			os.Exit(1)
+		}
+	}
 
 	// Initialize repositories
 	repos := repository.NewRepository(db)
@@ -70,11 +95,157 @@ func main() {
 		cfg.JWT.RefreshExpiration,
 	)
 	linkParser := util.NewLinkParser()
+	linkSigner := util.NewLinkSigner(cfg.JWT.Secret)
+
+	// Initialize attachment storage
+	store, err := storage.New(cfg.Storage)
+	if err != nil {
+		slog.Error("Failed to initialize storage", "error", err)
+		os.Exit(1)
+	}
+	slog.Info("Attachment storage ready", "driver", cfg.Storage.Driver)
+
+	// Backups of all user data, to local disk or an S3 bucket
+	backupStore, err := storage.New(cfg.Backup.Store(cfg.Storage))
+	if err != nil {
+		slog.Error("Failed to initialize backup storage", "error", err)
+		os.Exit(1)
+	}
+
+	// Outgoing email (verification and password reset tokens)
+	mailer, err := mail.New(cfg.Mail)
+	if err != nil {
+		slog.Error("Failed to initialize mail sender", "error", err)
+		os.Exit(1)
+	}
+	slog.Info("Mail sender ready", "driver", cfg.Mail.Driver)
+
+	// Optional embeddings provider for semantic search, which also needs pgvector
+	embedder, err := embedding.New(cfg.Embedding)
+	if err != nil {
+		slog.Error("Failed to initialize embeddings provider", "error", err)
+		os.Exit(1)
+	}
+	if embedder != nil {
+		available, err := repos.Embedding.Available(context.Background())
+		if err != nil {
+			slog.Error("Failed to check note embeddings", "error", err)
+			os.Exit(1)
+		}
+		if !available {
+			slog.Warn("Semantic search disabled: the note_embeddings table is missing, install pgvector and run migrations")
+			embedder = nil
+		} else {
+			slog.Info("Semantic search ready", "provider", cfg.Embedding.Provider, "model", embedder.Model())
+		}
+	}
+
+	// Optional chat model provider for note summaries
+	summarizer, err := llm.New(cfg.LLM)
+	if err != nil {
+		slog.Error("Failed to initialize LLM provider", "error", err)
+		os.Exit(1)
+	}
+	if summarizer != nil {
+		slog.Info("Summarization ready", "provider", cfg.LLM.Provider, "model", summarizer.Model())
+	}
+
+	// Live update events are fanned out in-process to connected clients
+	broker := events.NewBroker()
 
 	// Initialize services
-	authService := service.NewAuthService(repos.User, repos.RefreshToken, hasher, jwtManager)
-	noteService := service.NewNoteService(repos.Note, repos.Tag, repos.Link, repos.Activity, linkParser)
-	tagService := service.NewTagService(repos.Tag, repos.Note, repos.Activity)
+	authService := service.NewAuthService(
+		repos.User, repos.RefreshToken, repos.PasswordReset, repos.EmailVerification, repos.DeviceAuth, repos.Audit,
+		hasher, jwtManager, mailer,
+		cfg.Auth.ResetExpiration, cfg.Auth.VerificationExpiration, cfg.Auth.DeviceCodeExpiration, cfg.Auth.RequireEmailVerification,
+		cfg.Server.PublicURL,
+	)
+	noteService := service.NewNoteService(db, repos.Note, repos.Tag, repos.Link, repos.Activity, repos.Revision, repos.Settings, repos.Task, repos.Card, repos.NoteType, linkParser, broker)
+	tagService := service.NewTagService(repos.Tag, repos.Note, repos.Activity, repos.Settings, broker)
+	attachmentService := service.NewAttachmentService(repos.Attachment, repos.Note, store, cfg.Storage.MaxUploadSize)
+	embeddingService := service.NewEmbeddingService(repos.Embedding, embedder, cfg.Embedding.BatchSize)
+	summaryService := service.NewSummaryService(repos.Note, summarizer, broker)
+	adminService := service.NewAdminService(repos.User, repos.RefreshToken, repos.Audit)
+	backupService := service.NewBackupService(db, backupStore, cfg.Backup.Keep)
+	clipService := service.NewClipService(noteService, tagService, clipper.New(cfg.Clip))
+	publicLinkService := service.NewPublicLinkService(repos.PublicLink, repos.Note, repos.Link, linkParser, linkSigner, cfg.Server.PublicURL)
+	noteShareService := service.NewNoteShareService(repos.NoteShare, repos.Note, repos.User)
+	webhookSender := webhook.New(cfg.Webhook)
+	webhookService := service.NewWebhookService(repos.Webhook, repos.Note, repos.Tag, webhookSender, cfg.Webhook.MaxAttempts, cfg.Webhook.Timeout)
+	digestService := service.NewDigestService(repos.Settings, repos.Activity, webhookSender)
+
+	// `api backup [create|list|restore <name>]` manages backups and exits
+	if len(os.Args) > 1 && os.Args[1] == "backup" {
+		if err := runBackup(backupService, os.Args[2:]); err != nil {
+			slog.Error("Backup failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// `api seed` fills the database with demo data and exits
+	if len(os.Args) > 1 && os.Args[1] == "seed" {
+		seed := &seeder{
+			users:      repos.User,
+			activities: repos.Activity,
+			auth:       authService,
+			notes:      noteService,
+			tags:       tagService,
+		}
+		if err := runSeed(seed, os.Args[2:]); err != nil {
+			slog.Error("Seeding failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Accounts listed in ADMIN_EMAILS get the admin role, register them first
+	promoted, err := adminService.PromoteAdmins(context.Background(), cfg.Auth.AdminEmails)
+	if err != nil {
+		slog.Error("Failed to promote admins", "error", err)
+		os.Exit(1)
+	}
+	if promoted > 0 {
+		slog.Info("Promoted users to admin", "count", promoted)
+	}
+
+	// Warn when queries wait for a free database connection
+	monitorCtx, stopMonitor := context.WithCancel(context.Background())
+	defer stopMonitor()
+	go db.MonitorPool(monitorCtx, time.Minute)
+
+	// Embed new and changed notes in the background
+	indexCtx, stopIndexing := context.WithCancel(context.Background())
+	defer stopIndexing()
+	go embeddingService.Run(indexCtx, cfg.Embedding.IndexInterval)
+
+	// Write the nightly backup
+	backupCtx, stopBackups := context.WithCancel(context.Background())
+	defer stopBackups()
+	if cfg.Backup.Schedule != "" {
+		hour, minute, _ := cfg.Backup.ScheduleTime() // Validated by config.Load
+		go backupService.Run(backupCtx, hour, minute)
+		slog.Info("Scheduled backups enabled", "at", cfg.Backup.Schedule+" UTC", "driver", cfg.Backup.Driver, "keep", cfg.Backup.Keep)
+	}
+
+	// Queue events for webhooks and send their deliveries
+	broker.Listen(webhookService.Notify)
+	webhookCtx, stopWebhooks := context.WithCancel(context.Background())
+	defer stopWebhooks()
+	go webhookService.Run(webhookCtx)
+	// Post the daily digests of users who set a digest_url
+	go digestService.Run(webhookCtx)
+
+	// Generate the OpenAPI document served at /api/v1/openapi.json
+	spec, err := openapi.Generate(API_VERSION)
+	if err != nil {
+		slog.Error("Failed to generate OpenAPI document", "error", err)
+		os.Exit(1)
+	}
+
+	if cfg.Server.ProxyHeader != "" && len(cfg.Server.TrustedProxies) == 0 {
+		slog.Warn("SERVER_PROXY_HEADER is ignored without SERVER_TRUSTED_PROXIES, client IPs are connection IPs", "header", cfg.Server.ProxyHeader)
+	}
 
 	// Setup Fiber app
 	app := fiber.New(fiber.Config{
@@ -82,26 +253,55 @@ func main() {
 		ReadTimeout:  cfg.Server.ReadTimeout,
 		WriteTimeout: cfg.Server.WriteTimeout,
 		ErrorHandler: customErrorHandler,
+		// Client IP header set by a reverse proxy (used by the rate limiter), empty = connection IP
+		ProxyHeader: cfg.Server.ProxyHeader,
+		// Only honor ProxyHeader from these proxies, none when unlisted: any client can set the header
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          cfg.Server.TrustedProxies,
+		// Skip header values that aren't IPs
+		EnableIPValidation: true,
+		// Leave room for multipart overhead on top of the largest attachment
+		BodyLimit: int(cfg.Storage.MaxUploadSize) + 1<<20,
 	})
 
 	// Global middleware
 	app.Use(recover.New())
+	app.Use(middleware.RequestID()) // Before Logger, which logs the request ID
 	app.Use(middleware.Logger())
-	app.Use(middleware.CORS())
-	app.Use(middleware.RequestID())
+	app.Use(middleware.CORS(cfg.CORS))
+	app.Use(middleware.Compress())
 
 	// Setup handlers
 	handlers := &handler.Handlers{
-		Auth:     handler.NewAuthHandler(authService),
-		Note:     handler.NewNoteHandler(noteService),
-		Tag:      handler.NewTagHandler(tagService),
-		Search:   handler.NewSearchHandler(noteService),
-		Link:     handler.NewLinkHandler(noteService),
-		Activity: handler.NewActivityHandler(repos.Activity, noteService),
+		Auth:       handler.NewAuthHandler(authService),
+		Note:       handler.NewNoteHandler(noteService),
+		Tag:        handler.NewTagHandler(tagService),
+		Search:     handler.NewSearchHandler(noteService, embeddingService),
+		Link:       handler.NewLinkHandler(noteService),
+		Activity:   handler.NewActivityHandler(repos.Activity, noteService),
+		Audit:      handler.NewAuditHandler(authService, noteService),
+		Settings:   handler.NewSettingsHandler(noteService),
+		Attachment: handler.NewAttachmentHandler(attachmentService),
+		Task:       handler.NewTaskHandler(noteService),
+		Card:       handler.NewCardHandler(noteService),
+		NoteType:   handler.NewNoteTypeHandler(noteService),
+		Event:      handler.NewEventHandler(broker),
+		Docs:       handler.NewDocsHandler(spec),
+		User:       handler.NewUserHandler(authService),
+		Summary:    handler.NewSummaryHandler(summaryService),
+		Admin:      handler.NewAdminHandler(adminService),
+		Backup:     handler.NewBackupHandler(backupService),
+		Clip:       handler.NewClipHandler(clipService),
+		PublicLink: handler.NewPublicLinkHandler(publicLinkService),
+		NoteShare:  handler.NewNoteShareHandler(noteShareService),
+		Webhook:    handler.NewWebhookHandler(webhookService),
+	}
+	if cfg.Server.MetricsEnabled {
+		handlers.Metrics = handler.NewMetricsHandler(db)
 	}
 
 	// Setup routes
-	router.Setup(app, handlers, jwtManager)
+	router.Setup(app, handlers, jwtManager, adminService, cfg.RateLimit)
 
 	// Start server in goroutine
 	go func() {
@@ -118,6 +318,12 @@ func main() {
 
 	slog.Info("Shutting down server...")
 
+	// End open event streams, otherwise shutdown waits for them forever
+	broker.Close()
+	stopIndexing()
+	stopBackups()
+	stopWebhooks()
+
 	// Graceful shutdown
 	if err := app.ShutdownWithContext(context.Background()); err != nil {
 		slog.Error("Shutdown error", "error", err)
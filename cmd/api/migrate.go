@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"text/tabwriter"
+
+	"github.com/momokii/go-cli-notes/internal/repository"
+)
+
+// runMigrate runs a migration command against the database: up (default), down or status
+func runMigrate(db *repository.DB, args []string) error {
+	command := "up"
+	if len(args) > 0 {
+		command = args[0]
+	}
+
+	migrator, err := db.NewMigrator()
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	ctx := context.Background()
+	switch command {
+	case "up":
+		versions, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		if len(versions) == 0 {
+			slog.Info("Database schema is up to date")
+			return nil
+		}
+		slog.Info("Applied migrations", "count", len(versions), "latest", versions[len(versions)-1])
+
+	case "down":
+		version, err := migrator.Down(ctx)
+		if err != nil {
+			return err
+		}
+		slog.Info("Rolled back migration", "version", version)
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "APPLIED AT\tMIGRATION")
+		for _, status := range statuses {
+			appliedAt := "Pending"
+			if !status.AppliedAt.IsZero() {
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%s\t%s\n", appliedAt, status.Source.Path)
+		}
+		return w.Flush()
+
+	default:
+		return fmt.Errorf("unknown migrate command %q, expected up, down or status", command)
+	}
+
+	return nil
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+	"github.com/momokii/go-cli-notes/internal/repository"
+	"github.com/momokii/go-cli-notes/internal/service"
+)
+
+// Demo account created by `api seed`
+const (
+	seedEmail    = "demo@example.com"
+	seedUsername = "demo"
+	seedPassword = "demo-password"
+)
+
+// seedTopics are the subjects of the seeded notes
+var seedTopics = []string{
+	"Go concurrency patterns", "PostgreSQL indexing", "Weekly review", "Book: Deep Work",
+	"Garden planning", "Sourdough starter", "Trip to Lisbon", "Home network setup",
+	"Running log", "Team retro", "API design notes", "Terminal UI ideas",
+	"Budget", "Learning Japanese", "Reading list", "Side project ideas",
+}
+
+// seedSentences fill the bodies of the seeded notes
+var seedSentences = []string{
+	"Small, focused steps beat big rewrites.",
+	"Write it down before it slips away.",
+	"This came up again during the weekly sync.",
+	"Worth revisiting once the first draft is done.",
+	"The tricky part is keeping it simple.",
+	"Measure first, then optimize the slow path.",
+	"A good default saves a lot of configuration.",
+	"Link this to the earlier notes on the topic.",
+	"Questions left open are listed below.",
+	"Borrowed from a talk I watched last month.",
+}
+
+// seedTags are the tags of the seeded notes; "/" nests a tag under its parent
+var seedTags = []string{
+	"work", "work/meetings", "programming", "programming/go", "databases",
+	"reading", "ideas", "health", "travel", "cooking", "finance", "learning",
+}
+
+// seeder creates demo data through the services, so links, word counts and tasks
+// are derived the same way as for notes written by hand
+type seeder struct {
+	users      repository.UserRepository
+	activities repository.ActivityRepository
+	auth       *service.AuthService
+	notes      *service.NoteService
+	tags       *service.TagService
+	rng        *rand.Rand
+}
+
+// runSeed creates a demo user with interlinked notes, tags and activity history
+func runSeed(s *seeder, args []string) error {
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	noteCount := flags.Int("notes", 300, "number of notes to create")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *noteCount < 1 {
+		return fmt.Errorf("-notes must be at least 1")
+	}
+
+	ctx := context.Background()
+	// A fixed seed gives every contributor the same data set
+	s.rng = rand.New(rand.NewPCG(42, 7))
+
+	user, err := s.auth.Register(ctx, &model.RegisterRequest{
+		Email:    seedEmail,
+		Username: seedUsername,
+		Password: seedPassword,
+	})
+	if err != nil {
+		if errors.Is(err, model.ErrAPIEmailExists) || errors.Is(err, model.ErrAPIUsernameExists) {
+			return fmt.Errorf("the demo user already exists, the database is already seeded")
+		}
+		return fmt.Errorf("create demo user: %w", err)
+	}
+	if err := s.users.MarkVerified(ctx, user.ID); err != nil {
+		return fmt.Errorf("verify demo user: %w", err)
+	}
+
+	tagIDs := make([]uuid.UUID, 0, len(seedTags))
+	for _, name := range seedTags {
+		tag, err := s.tags.Create(ctx, user.ID, &model.CreateTagRequest{Name: name})
+		if err != nil {
+			return fmt.Errorf("create tag %s: %w", name, err)
+		}
+		tagIDs = append(tagIDs, tag.ID)
+	}
+
+	titles := make([]string, 0, *noteCount)
+	for i := range *noteCount {
+		title := fmt.Sprintf("%s %d", seedTopics[i%len(seedTopics)], i/len(seedTopics)+1)
+
+		note, err := s.notes.Create(ctx, user.ID, &model.CreateNoteRequest{
+			Title:    title,
+			Content:  s.noteContent(title, titles),
+			NoteType: s.noteType(),
+		})
+		if err != nil {
+			return fmt.Errorf("create note %q: %w", title, err)
+		}
+		titles = append(titles, title)
+
+		for _, idx := range s.rng.Perm(len(tagIDs))[:1+s.rng.IntN(3)] {
+			if err := s.tags.AddToNote(ctx, user.ID, note.ID, tagIDs[idx]); err != nil {
+				return fmt.Errorf("tag note %q: %w", title, err)
+			}
+		}
+
+		if err := s.noteHistory(ctx, user.ID, note.ID); err != nil {
+			return err
+		}
+	}
+
+	slog.Info("Seeded demo data",
+		"email", seedEmail,
+		"password", seedPassword,
+		"notes", len(titles),
+		"tags", len(tagIDs),
+	)
+	return nil
+}
+
+// noteContent writes a markdown body linking to a few of the earlier notes
+// Now and then it links to a note that doesn't exist yet, to show unresolved links.
+func (s *seeder) noteContent(title string, earlier []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", title)
+
+	for range 2 + s.rng.IntN(4) {
+		b.WriteString(seedSentences[s.rng.IntN(len(seedSentences))])
+		b.WriteByte(' ')
+	}
+	b.WriteString("\n\n")
+
+	if len(earlier) > 0 {
+		links := make([]string, 0, 4)
+		for range 1 + s.rng.IntN(4) {
+			links = append(links, "[["+earlier[s.rng.IntN(len(earlier))]+"]]")
+		}
+		if s.rng.IntN(10) == 0 {
+			links = append(links, "[[Someday: "+seedTopics[s.rng.IntN(len(seedTopics))]+"]]")
+		}
+		fmt.Fprintf(&b, "Related: %s\n\n", strings.Join(links, ", "))
+	}
+
+	if s.rng.IntN(4) == 0 {
+		b.WriteString("- [ ] Follow up on this\n- [x] Write the first draft\n")
+	}
+
+	return b.String()
+}
+
+// noteType picks mostly plain notes, with some ideas and meetings
+func (s *seeder) noteType() model.NoteType {
+	switch s.rng.IntN(10) {
+	case 0:
+		return model.NoteTypeIdea
+	case 1:
+		return model.NoteTypeMeeting
+	default:
+		return model.NoteTypeNote
+	}
+}
+
+// noteHistory logs views and edits of a note spread over the last 90 days
+func (s *seeder) noteHistory(ctx context.Context, userID, noteID uuid.UUID) error {
+	for range s.rng.IntN(8) {
+		action := model.ActionView
+		if s.rng.IntN(4) == 0 {
+			action = model.ActionUpdate
+		}
+
+		activity := &model.Activity{
+			UserID:    userID,
+			NoteID:    &noteID,
+			Action:    action,
+			CreatedAt: time.Now().Add(-time.Duration(s.rng.Int64N(int64(90 * 24 * time.Hour)))),
+		}
+		if err := s.activities.Create(ctx, activity); err != nil {
+			return fmt.Errorf("log activity: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/momokii/go-cli-notes/cmd/cli/client"
+)
+
+var accountCmd = &cobra.Command{
+	Use:   "account",
+	Short: "Manage your account",
+}
+
+// accountPasswordCmd changes the password of the signed-in account
+var accountPasswordCmd = &cobra.Command{
+	Use:   "password",
+	Short: "Change your password",
+	Long: `Change the account password. Every other device is signed out;
+this one stays logged in with new tokens.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !authState.IsAuthenticated() {
+			return fmt.Errorf("not authenticated. Please run 'kg-cli login'")
+		}
+
+		current, err := readPassword("Current Password: ")
+		if err != nil {
+			return fmt.Errorf("read password: %w", err)
+		}
+
+		// New password with confirmation loop
+		var password string
+		for {
+			pw, err := readPassword("New Password: ")
+			if err != nil {
+				return fmt.Errorf("read password: %w", err)
+			}
+
+			confirm, err := readPassword("Confirm Password: ")
+			if err != nil {
+				return fmt.Errorf("read password: %w", err)
+			}
+
+			if pw == confirm {
+				password = pw
+				break
+			}
+
+			fmt.Println("Passwords do not match. Please try again.")
+		}
+
+		authResp, err := apiClient.ChangePassword(current, password)
+		if err != nil {
+			return fmt.Errorf("change password: %w", err)
+		}
+
+		// Keep this device signed in with the newly issued tokens
+		authState.AccessToken = authResp.AccessToken
+		authState.RefreshToken = authResp.RefreshToken
+		if err := client.SaveAuthState(config.ActiveProfile, authState); err != nil {
+			return fmt.Errorf("save auth state: %w", err)
+		}
+
+		fmt.Println("Password changed. Other devices have been signed out.")
+		return nil
+	},
+}
+
+// accountDeleteCmd deletes the signed-in account, optionally exporting notes first
+var accountDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete your account and all of its notes",
+	Long: `Delete the account. All notes are deleted and every device is signed out.
+
+Use --export to download a zip of your notes before the account is deleted.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !authState.IsAuthenticated() {
+			return fmt.Errorf("not authenticated. Please run 'kg-cli login'")
+		}
+
+		// Export first, while the notes are still reachable
+		if output, _ := cmd.Flags().GetString("export"); output != "" {
+			f, err := os.Create(output)
+			if err != nil {
+				return fmt.Errorf("create export file: %w", err)
+			}
+
+			n, err := apiClient.ExportNotes(f, false)
+			f.Close()
+			if err != nil {
+				os.Remove(output)
+				return fmt.Errorf("export notes: %w", err)
+			}
+
+			fmt.Printf("Notes exported to %s (%d bytes)\n", output, n)
+		}
+
+		if yes, _ := cmd.Flags().GetBool("yes"); !yes {
+			var confirm string
+			fmt.Printf("Delete account %s and all of its notes? This cannot be undone. (y/N): ", authState.Email)
+			fmt.Scanln(&confirm)
+
+			if strings.ToLower(strings.TrimSpace(confirm)) != "y" {
+				fmt.Println("Cancelled")
+				return nil
+			}
+		}
+
+		password, err := promptPassword(cmd, "Password: ")
+		if err != nil {
+			return fmt.Errorf("read password: %w", err)
+		}
+
+		if err := apiClient.DeleteAccount(password); err != nil {
+			return fmt.Errorf("delete account: %w", err)
+		}
+
+		// Drop cached notes and local credentials of the deleted account
+		if cache := apiClient.Cache(); cache != nil {
+			_ = cache.Clear()
+		}
+
+		if err := client.ClearAuthState(config.ActiveProfile); err != nil {
+			return fmt.Errorf("clear auth state: %w", err)
+		}
+
+		fmt.Println("Account deleted")
+		return nil
+	},
+}
+
+func init() {
+	accountDeleteCmd.Flags().StringP("export", "e", "", "Export notes to this zip file before deleting")
+	accountDeleteCmd.Flags().BoolP("yes", "y", false, "Skip the confirmation prompt")
+	accountDeleteCmd.Flags().Bool("password-stdin", false, "Read the password from stdin")
+
+	accountCmd.AddCommand(accountPasswordCmd)
+	accountCmd.AddCommand(accountDeleteCmd)
+	rootCmd.AddCommand(accountCmd)
+}
@@ -0,0 +1,291 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+)
+
+var adminCmd = &cobra.Command{
+	Use:   "admin",
+	Short: "Administer the server (admin accounts only)",
+	Long: `Manage the accounts of a multi-user server.
+
+Only admins can use these commands. Accounts become admins when their email
+is listed in the server's ADMIN_EMAILS setting.`,
+}
+
+// adminUsersCmd lists every account on the server
+var adminUsersCmd = &cobra.Command{
+	Use:   "users",
+	Short: "List all users",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !authState.IsAuthenticated() {
+			return fmt.Errorf("not authenticated. Please run 'kg-cli login' first")
+		}
+
+		page, _ := cmd.Flags().GetInt("page")
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		users, total, err := apiClient.AdminListUsers(page, limit)
+		if err != nil {
+			return fmt.Errorf("list users: %w", err)
+		}
+
+		if len(users) == 0 {
+			fmt.Println("No users found")
+			return nil
+		}
+
+		fmt.Printf("Found %d user(s), page %d:\n\n", total, page)
+		for _, user := range users {
+			status := "active"
+			switch {
+			case user.DeletedAt != nil:
+				status = "deleted"
+			case !user.IsActive:
+				status = "deactivated"
+			}
+
+			fmt.Printf("ID: %s\n", user.ID)
+			fmt.Printf("Username: %s\n", user.Username)
+			fmt.Printf("Email: %s\n", user.Email)
+			fmt.Printf("Role: %s\n", user.Role)
+			fmt.Printf("Status: %s\n", status)
+			fmt.Printf("Notes: %d\n", user.NoteCount)
+			fmt.Printf("Registered: %s\n", user.CreatedAt.Format("2006-01-02 15:04"))
+			if user.LastLoginAt != nil {
+				fmt.Printf("Last Login: %s\n", user.LastLoginAt.Format("2006-01-02 15:04"))
+			}
+			fmt.Println("---")
+		}
+
+		return nil
+	},
+}
+
+// adminDeactivateCmd blocks a user from signing in
+var adminDeactivateCmd = &cobra.Command{
+	Use:   "deactivate <user-id>",
+	Short: "Block a user from signing in",
+	Long: `Deactivate a user and revoke their sessions. Their notes are kept.
+
+The user's current access token stays valid until it expires.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := uuid.Parse(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid user ID: %w", err)
+		}
+
+		if err := apiClient.AdminDeactivateUser(id); err != nil {
+			return fmt.Errorf("deactivate user: %w", err)
+		}
+
+		fmt.Println("User deactivated")
+		return nil
+	},
+}
+
+// adminActivateCmd lets a deactivated user sign in again
+var adminActivateCmd = &cobra.Command{
+	Use:   "activate <user-id>",
+	Short: "Let a deactivated user sign in again",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := uuid.Parse(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid user ID: %w", err)
+		}
+
+		if err := apiClient.AdminActivateUser(id); err != nil {
+			return fmt.Errorf("activate user: %w", err)
+		}
+
+		fmt.Println("User activated")
+		return nil
+	},
+}
+
+// adminStatsCmd shows statistics across all users
+var adminStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show server-wide statistics",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !authState.IsAuthenticated() {
+			return fmt.Errorf("not authenticated. Please run 'kg-cli login' first")
+		}
+
+		stats, err := apiClient.AdminGetStats()
+		if err != nil {
+			return fmt.Errorf("get stats: %w", err)
+		}
+
+		fmt.Println("Server Statistics")
+		fmt.Println("=================")
+		fmt.Printf("Total Users: %d\n", stats.TotalUsers)
+		fmt.Printf("Active Users: %d\n", stats.ActiveUsers)
+		fmt.Printf("New Users This Week: %d\n", stats.NewUsersWeek)
+		fmt.Printf("Signed In This Week: %d\n", stats.ActiveUsersWeek)
+		fmt.Printf("Total Notes: %d\n", stats.TotalNotes)
+		fmt.Printf("Total Words: %d\n", stats.TotalWords)
+		fmt.Printf("Total Tags: %d\n", stats.TotalTags)
+		fmt.Printf("Total Links: %d\n", stats.TotalLinks)
+		fmt.Printf("Attachments: %d (%d bytes)\n", stats.TotalAttachments, stats.AttachmentBytes)
+
+		return nil
+	},
+}
+
+// adminAuditCmd shows the security events of every account
+var adminAuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Show logins and other security events of all users",
+	Long: `Show the audit log of the server, newest first, including failed logins
+with emails that have no account. Use --user to see one account's events.
+
+Events: ` + auditEventList(),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !authState.IsAuthenticated() {
+			return fmt.Errorf("not authenticated. Please run 'kg-cli login' first")
+		}
+
+		filter, err := auditFilterFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+		if userStr, _ := cmd.Flags().GetString("user"); userStr != "" {
+			userID, err := uuid.Parse(userStr)
+			if err != nil {
+				return fmt.Errorf("invalid user ID: %w", err)
+			}
+			filter.UserID = &userID
+		}
+
+		entries, total, err := apiClient.AdminListAuditLog(filter)
+		if err != nil {
+			return fmt.Errorf("list audit log: %w", err)
+		}
+
+		printAuditEntries(entries, total, filter.Page, true)
+		return nil
+	},
+}
+
+// adminBackupCmd backs up all user data on the server
+var adminBackupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Back up all user data on the server now",
+	Long: `Write a backup of every account's notes, tags, links, settings and activity
+to the server's backup store (BACKUP_DRIVER). Attachment files are not included.
+
+The server also writes one every night when BACKUP_SCHEDULE is set.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !authState.IsAuthenticated() {
+			return fmt.Errorf("not authenticated. Please run 'kg-cli login' first")
+		}
+
+		result, err := apiClient.AdminCreateBackup()
+		if err != nil {
+			return fmt.Errorf("create backup: %w", err)
+		}
+
+		var rows int64
+		for _, count := range result.Tables {
+			rows += count
+		}
+		fmt.Printf("Backup written: %s (%d bytes, %d rows)\n", result.Name, result.Size, rows)
+		return nil
+	},
+}
+
+// adminBackupListCmd lists the server's backups
+var adminBackupListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the server's backups, newest first",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !authState.IsAuthenticated() {
+			return fmt.Errorf("not authenticated. Please run 'kg-cli login' first")
+		}
+
+		backups, err := apiClient.AdminListBackups()
+		if err != nil {
+			return fmt.Errorf("list backups: %w", err)
+		}
+
+		if len(backups) == 0 {
+			fmt.Println("No backups found")
+			return nil
+		}
+
+		for _, backup := range backups {
+			fmt.Printf("%s  %10d bytes  %s\n", backup.CreatedAt.Local().Format("2006-01-02 15:04"), backup.Size, backup.Name)
+		}
+		return nil
+	},
+}
+
+// adminBackupRestoreCmd restores the rows of a backup that are missing on the server
+var adminBackupRestoreCmd = &cobra.Command{
+	Use:   "restore <name>",
+	Short: "Restore what is missing from a backup",
+	Long: `Add the rows of a backup that are missing from the server's database, such as
+deleted accounts or notes. Rows that still exist are kept as they are, so newer
+edits are never overwritten. See 'kg-cli admin backup list' for the names.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !authState.IsAuthenticated() {
+			return fmt.Errorf("not authenticated. Please run 'kg-cli login' first")
+		}
+
+		if yes, _ := cmd.Flags().GetBool("yes"); !yes {
+			fmt.Printf("Restore %s into the server's database? (y/N): ", args[0])
+			var confirm string
+			fmt.Scanln(&confirm)
+
+			if strings.ToLower(confirm) != "y" {
+				fmt.Println("Restore cancelled")
+				return nil
+			}
+		}
+
+		result, err := apiClient.AdminRestoreBackup(args[0])
+		if err != nil {
+			return fmt.Errorf("restore backup: %w", err)
+		}
+
+		tables := make([]string, 0, len(result.Restored))
+		for table := range result.Restored {
+			tables = append(tables, table)
+		}
+		sort.Strings(tables)
+
+		fmt.Printf("Restored %s:\n", result.Name)
+		for _, table := range tables {
+			fmt.Printf("  %-18s %d restored, %d already present\n", table, result.Restored[table], result.Skipped[table])
+		}
+		return nil
+	},
+}
+
+func init() {
+	adminUsersCmd.Flags().IntP("page", "p", 1, "Page number")
+	adminUsersCmd.Flags().IntP("limit", "l", 20, "Users per page")
+	adminBackupRestoreCmd.Flags().BoolP("yes", "y", false, "Skip the confirmation prompt")
+	addAuditFlags(adminAuditCmd)
+	adminAuditCmd.Flags().StringP("user", "u", "", "Only show events of the user with this ID")
+
+	adminBackupCmd.AddCommand(adminBackupListCmd)
+	adminBackupCmd.AddCommand(adminBackupRestoreCmd)
+
+	adminCmd.AddCommand(adminUsersCmd)
+	adminCmd.AddCommand(adminDeactivateCmd)
+	adminCmd.AddCommand(adminActivateCmd)
+	adminCmd.AddCommand(adminStatsCmd)
+	adminCmd.AddCommand(adminAuditCmd)
+	adminCmd.AddCommand(adminBackupCmd)
+	rootCmd.AddCommand(adminCmd)
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+// auditCmd shows the security events of the account
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Show logins and other security events of your account",
+	Long: `Show the audit log of your account: logins, failed logins, token refreshes,
+password changes, 2FA changes and revoked sessions, newest first.
+
+Events: ` + auditEventList(),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !authState.IsAuthenticated() {
+			return fmt.Errorf("not authenticated. Please run 'kg-cli login' first")
+		}
+
+		filter, err := auditFilterFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		entries, total, err := apiClient.ListAuditLog(filter)
+		if err != nil {
+			return fmt.Errorf("list audit log: %w", err)
+		}
+
+		printAuditEntries(entries, total, filter.Page, false)
+		return nil
+	},
+}
+
+// auditFilterFromFlags reads the --event, --page and --limit flags of an audit command
+func auditFilterFromFlags(cmd *cobra.Command) (model.AuditFilter, error) {
+	page, _ := cmd.Flags().GetInt("page")
+	limit, _ := cmd.Flags().GetInt("limit")
+	filter := model.AuditFilter{Page: page, Limit: limit}
+
+	if eventStr, _ := cmd.Flags().GetString("event"); eventStr != "" {
+		event := model.AuditEvent(eventStr)
+		if !event.IsValid() {
+			return filter, fmt.Errorf("unknown event %q (one of %s)", eventStr, auditEventList())
+		}
+		filter.Event = &event
+	}
+
+	return filter, nil
+}
+
+// printAuditEntries prints a page of audit log entries, one per line, with who they are about when withUser
+func printAuditEntries(entries []*model.AuditEntry, total int64, page int, withUser bool) {
+	if len(entries) == 0 {
+		fmt.Println("No audit log entries")
+		return
+	}
+
+	fmt.Printf("Found %d event(s), page %d:\n\n", total, page)
+	for _, entry := range entries {
+		line := fmt.Sprintf("%s  %-16s", entry.CreatedAt.Local().Format("2006-01-02 15:04:05"), entry.Event)
+		if withUser {
+			who := entry.Email
+			if who == "" && entry.UserID != nil {
+				who = entry.UserID.String()
+			}
+			line += "  " + who
+		}
+		if entry.IPAddress != "" {
+			line += "  from " + entry.IPAddress
+		}
+		fmt.Println(line)
+
+		if details := auditDetails(entry.Metadata); details != "" {
+			fmt.Printf("    %s\n", details)
+		}
+	}
+}
+
+// auditDetails writes the metadata of an entry as sorted key: value pairs
+func auditDetails(metadata model.AuditMetadata) string {
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = fmt.Sprintf("%s: %v", key, metadata[key])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// auditEventList lists the audit events for help and error messages
+func auditEventList() string {
+	names := make([]string, len(model.AuditEvents))
+	for i, event := range model.AuditEvents {
+		names[i] = string(event)
+	}
+	return strings.Join(names, ", ")
+}
+
+// addAuditFlags registers the flags shared by the audit commands
+func addAuditFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("event", "e", "", "Only show this event")
+	cmd.Flags().IntP("page", "p", 1, "Page number")
+	cmd.Flags().IntP("limit", "l", 20, "Events per page (max 100)")
+}
+
+func init() {
+	addAuditFlags(auditCmd)
+	rootCmd.AddCommand(auditCmd)
+}
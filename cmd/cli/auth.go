@@ -0,0 +1,152 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/momokii/go-cli-notes/cmd/cli/client"
+)
+
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Approve device logins and manage how login tokens are stored",
+}
+
+// authMigrateStorageCmd moves the tokens of the current profile between the OS keychain and the auth file
+var authMigrateStorageCmd = &cobra.Command{
+	Use:   "migrate-storage",
+	Short: "Move login tokens to the OS keychain, or back to the auth file",
+	Long: `Move the login tokens of the current profile to the OS credential manager
+(macOS Keychain, Windows Credential Manager or the Secret Service on Linux),
+or with --to file back to auth.json, and remove them from where they were.
+
+The auth.token_storage setting is changed to match, so later logins keep
+using the new storage. By default (auto) tokens go to the keychain when one
+is available and to auth.json otherwise; logins made before the keychain was
+supported stay in auth.json until moved with this command.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		to, _ := cmd.Flags().GetString("to")
+		storage := client.TokenStorage(to)
+		if storage != client.StorageKeychain && storage != client.StorageFile {
+			return fmt.Errorf("--to must be keychain or file")
+		}
+
+		moved, err := client.MigrateAuthState(config.ActiveProfile, storage)
+		if err != nil {
+			return fmt.Errorf("migrate tokens: %w", err)
+		}
+
+		config.Auth.TokenStorage = string(storage)
+		if err := SaveConfig(config); err != nil {
+			return fmt.Errorf("save config: %w", err)
+		}
+		if err := client.SetTokenStorage(storage); err != nil {
+			return err
+		}
+
+		if moved {
+			fmt.Printf("Tokens moved to %s\n", client.AuthStateLocation(config.ActiveProfile))
+		} else {
+			fmt.Printf("No tokens to move, new logins are stored in %s\n", client.AuthStateLocation(config.ActiveProfile))
+		}
+		return nil
+	},
+}
+
+// authApproveCmd approves a device login from this signed-in device
+var authApproveCmd = &cobra.Command{
+	Use:   "approve <code>",
+	Short: "Sign in a device showing a code from 'kg-cli login --device'",
+	Long: `Sign the device showing the code in to your account, instead of entering the
+code at the login page. Only approve codes of logins you started yourself.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !authState.IsAuthenticated() {
+			return fmt.Errorf("not authenticated. Please run 'kg-cli login'")
+		}
+		deny, _ := cmd.Flags().GetBool("deny")
+
+		auth, err := apiClient.ApproveDevice(args[0], deny)
+		if err != nil {
+			return fmt.Errorf("approve device: %w", err)
+		}
+
+		device := auth.UserAgent
+		if device == "" {
+			device = "unknown device"
+		}
+		if deny {
+			fmt.Printf("Denied the login of %s (%s)\n", device, auth.IPAddress)
+		} else {
+			fmt.Printf("Signed in %s (%s)\n", device, auth.IPAddress)
+		}
+		return nil
+	},
+}
+
+// deviceLogin logs in with a code approved from another device, polling until it is
+func deviceLogin() error {
+	codes, err := apiClient.StartDeviceLogin()
+	if err != nil {
+		return fmt.Errorf("login failed: %w", err)
+	}
+
+	fmt.Printf("To sign in, open %s\n", codes.VerificationURIComplete)
+	fmt.Printf("or go to %s and enter the code: %s\n", codes.VerificationURI, codes.UserCode)
+	fmt.Printf("You can also run 'kg-cli auth approve %s' where you are signed in.\n\n", codes.UserCode)
+	fmt.Println("Waiting for approval...")
+
+	interval := time.Duration(codes.Interval) * time.Second
+	deadline := time.Now().Add(time.Duration(codes.ExpiresIn) * time.Second)
+	for {
+		time.Sleep(interval)
+
+		authResp, err := apiClient.PollDeviceLogin(codes.DeviceCode)
+		switch {
+		case errors.Is(err, client.ErrAuthorizationPending):
+			if time.Now().After(deadline) {
+				return fmt.Errorf("login failed: the code expired, run 'kg-cli login --device' again")
+			}
+			continue
+		case errors.Is(err, client.ErrSlowDown):
+			interval += 5 * time.Second
+			continue
+		case errors.Is(err, client.ErrDeviceCodeExpired):
+			return fmt.Errorf("login failed: the code expired, run 'kg-cli login --device' again")
+		case errors.Is(err, client.ErrAccessDenied):
+			return fmt.Errorf("login failed: the login was denied")
+		case err != nil:
+			return fmt.Errorf("login failed: %w", err)
+		}
+
+		authState = &client.AuthState{
+			AccessToken:  authResp.AccessToken,
+			RefreshToken: authResp.RefreshToken,
+		}
+		if authResp.User != nil {
+			authState.UserID = authResp.User.ID.String()
+			authState.Email = authResp.User.Email
+		}
+
+		if err := client.SaveAuthState(config.ActiveProfile, authState); err != nil {
+			return fmt.Errorf("save auth state: %w", err)
+		}
+
+		fmt.Println("Login successful!")
+		return nil
+	}
+}
+
+func init() {
+	authApproveCmd.Flags().Bool("deny", false, "Refuse the login instead")
+	authCmd.AddCommand(authApproveCmd)
+
+	authMigrateStorageCmd.Flags().String("to", string(client.StorageKeychain), "Where to move the tokens: keychain or file")
+
+	authCmd.AddCommand(authMigrateStorageCmd)
+	rootCmd.AddCommand(authCmd)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+// captureCmd appends a quick thought to the Inbox or today's daily note
+var captureCmd = &cobra.Command{
+	Use:   "capture [text...]",
+	Short: "Capture a fleeting thought into your Inbox or today's daily note",
+	Long: `Append text as a list item to the note titled "Inbox", or to today's daily
+note with --daily, without any prompts. The note is created if it doesn't
+exist yet. Without --daily or --inbox the capture_target setting decides.
+
+The words after 'capture' are the text; with none, the text is read from stdin:
+
+  kg-cli capture "call the dentist"
+  pbpaste | kg-cli capture --daily`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !authState.IsAuthenticated() {
+			return fmt.Errorf("not authenticated. Please run 'kg-cli login'")
+		}
+
+		daily, _ := cmd.Flags().GetBool("daily")
+		inbox, _ := cmd.Flags().GetBool("inbox")
+		if daily && inbox {
+			return fmt.Errorf("--daily and --inbox cannot be used together")
+		}
+		target := ""
+		if daily {
+			target = model.CaptureTargetDaily
+		} else if inbox {
+			target = model.CaptureTargetInbox
+		}
+
+		text := strings.Join(args, " ")
+		if text == "" && !term.IsTerminal(int(os.Stdin.Fd())) {
+			data, err := io.ReadAll(os.Stdin)
+			if err != nil {
+				return fmt.Errorf("read stdin: %w", err)
+			}
+			text = string(data)
+		}
+		if strings.TrimSpace(text) == "" {
+			return fmt.Errorf("nothing to capture, e.g. kg-cli capture \"a fleeting thought\"")
+		}
+
+		note, isCreated, err := apiClient.Capture(text, target)
+		if err != nil {
+			return fmt.Errorf("capture: %w", err)
+		}
+
+		if isCreated {
+			fmt.Printf("Captured to %s (new note, ID: %s)\n", note.Title, note.ID)
+		} else {
+			fmt.Printf("Captured to %s\n", note.Title)
+		}
+		return nil
+	},
+}
+
+func init() {
+	captureCmd.Flags().BoolP("daily", "d", false, "Append to today's daily note")
+	captureCmd.Flags().BoolP("inbox", "i", false, "Append to the Inbox note")
+	rootCmd.AddCommand(captureCmd)
+}
@@ -5,8 +5,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"mime"
+	"mime/multipart"
 	"net/http"
+	"net/textproto"
 	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
 	"strings"
 	"time"
 
@@ -20,6 +27,12 @@ type APIClient struct {
 	httpClient *http.Client
 	token      string
 	refreshToken string
+	cache      *Cache
+	passphrase string // For client-side encrypted notes, never sent to the API
+	userAgent  string // Shown in the session list, so other devices can be told apart
+	settings   *model.UserSettings // Account preferences, fetched once by Settings
+	noteTypes  []*model.NoteTypeDefinition // Built-in and custom note types, fetched once by NoteTypes
+	validators *validatorCache     // ETags of GET responses, to skip unchanged ones
 }
 
 // AuthResponse holds authentication tokens
@@ -28,11 +41,7 @@ type AuthResponse struct {
 	RefreshToken string `json:"refresh_token"`
 	TokenType    string `json:"token_type"`
 	ExpiresIn    int    `json:"expires_in"`
-}
-
-// apiErrorResponse represents an error response from the API
-type apiErrorResponse struct {
-	Error string `json:"error"`
+	User         *model.User `json:"user,omitempty"`
 }
 
 // NewAPIClient creates a new API client
@@ -42,13 +51,26 @@ func NewAPIClient(baseURL string, timeout time.Duration) *APIClient {
 		httpClient: &http.Client{
 			Timeout: timeout,
 		},
+		userAgent:  defaultUserAgent(),
+		validators: newValidatorCache(),
+	}
+}
+
+// defaultUserAgent identifies this machine, e.g. "kg-cli (linux/amd64; laptop)"
+func defaultUserAgent() string {
+	agent := "kg-cli (" + runtime.GOOS + "/" + runtime.GOARCH
+	if host, err := os.Hostname(); err == nil && host != "" {
+		agent += "; " + host
 	}
+	return agent + ")"
 }
 
 // SetTokens sets the authentication tokens
 func (c *APIClient) SetTokens(accessToken, refreshToken string) {
 	c.token = accessToken
 	c.refreshToken = refreshToken
+	// Cached responses belong to the previous account
+	c.validators.clear()
 }
 
 // GetToken returns the current access token
@@ -80,81 +102,70 @@ func (c *APIClient) ValidateToken() bool {
 	return resp.StatusCode == 200
 }
 
+// Rate limit handling: wait as long as the server asks, a few times, before giving up
+const (
+	maxRateLimitRetries = 3
+	maxRateLimitWait    = 30 * time.Second
+)
+
 // makeRequest makes an HTTP request with authentication
+// Requests rejected with 429 Too Many Requests are retried after the server's Retry-After delay.
 func (c *APIClient) makeRequest(method, path string, body interface{}, authenticated bool) (*http.Response, error) {
-	var reqBody io.Reader
+	return c.makeRequestWithHeaders(method, path, body, authenticated, nil)
+}
+
+// makeRequestWithHeaders makes an HTTP request with authentication and extra headers
+// Accept-Encoding is left to the transport, which asks for gzip and decompresses the response.
+func (c *APIClient) makeRequestWithHeaders(method, path string, body interface{}, authenticated bool, headers map[string]string) (*http.Response, error) {
+	var jsonBody []byte
 	if body != nil {
-		jsonBody, err := json.Marshal(body)
+		var err error
+		jsonBody, err = json.Marshal(body)
 		if err != nil {
 			return nil, fmt.Errorf("marshal request body: %w", err)
 		}
-		reqBody = bytes.NewReader(jsonBody)
 	}
 
 	url := c.baseURL + path
-	req, err := http.NewRequest(method, url, reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-
-	if authenticated && c.token != "" {
-		req.Header.Set("Authorization", "Bearer "+c.token)
-	}
-
-	return c.httpClient.Do(req)
-}
+	for attempt := 0; ; attempt++ {
+		var reqBody io.Reader
+		if jsonBody != nil {
+			reqBody = bytes.NewReader(jsonBody)
+		}
 
-// formatAPIError converts an API error response into a user-friendly message
-func formatAPIError(statusCode int, body []byte) error {
-	// Try to parse as JSON error response
-	var apiErr apiErrorResponse
-	if json.Unmarshal(body, &apiErr) == nil && apiErr.Error != "" {
-		// Clean up the error message
-		errMsg := apiErr.Error
+		req, err := http.NewRequest(method, url, reqBody)
+		if err != nil {
+			return nil, fmt.Errorf("create request: %w", err)
+		}
 
-		// Remove common prefixes
-		errMsg = strings.TrimPrefix(errMsg, "Internal server error: ")
-		errMsg = strings.TrimPrefix(errMsg, "validation failed: ")
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", c.userAgent)
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
 
-		// Capitalize first letter
-		if len(errMsg) > 0 {
-			errMsg = strings.ToUpper(string(errMsg[0])) + errMsg[1:]
+		if authenticated && c.token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.token)
 		}
 
-		// Map common errors to friendly messages
-		// Order matters - check more specific patterns first
-		switch {
-		case strings.Contains(strings.ToLower(errMsg), "username is required"):
-			return fmt.Errorf("username is required")
-		case strings.Contains(strings.ToLower(errMsg), "email is required"):
-			return fmt.Errorf("email is required")
-		case strings.Contains(strings.ToLower(errMsg), "password is required"):
-			return fmt.Errorf("password is required")
-		// Check for the exact API response first
-		case errMsg == "Invalid email or password" || errMsg == "invalid email or password":
-			return fmt.Errorf("invalid email or password")
-		case strings.Contains(strings.ToLower(errMsg), "invalid credentials"):
-			return fmt.Errorf("invalid email or password")
-		case strings.Contains(strings.ToLower(errMsg), "invalid email") && !strings.Contains(strings.ToLower(errMsg), "credentials") && !strings.Contains(strings.ToLower(errMsg), "invalid email or password"):
-			return fmt.Errorf("invalid email format")
-		case strings.Contains(strings.ToLower(errMsg), "unauthorized"):
-			return fmt.Errorf("not authenticated. Please run 'kg-cli login'")
-		case strings.Contains(strings.ToLower(errMsg), "user already exists"):
-			return fmt.Errorf("user with this email or username already exists")
-		case strings.Contains(strings.ToLower(errMsg), "username already exists"):
-			return fmt.Errorf("username already exists")
-		case strings.Contains(strings.ToLower(errMsg), "not found"):
-			return fmt.Errorf("resource not found")
-		default:
-			// Return cleaned error message
-			return fmt.Errorf("%s", strings.TrimSuffix(errMsg, "; "))
+		resp, err := c.httpClient.Do(req)
+		if err != nil || resp.StatusCode != http.StatusTooManyRequests || attempt >= maxRateLimitRetries {
+			return resp, err
 		}
+
+		wait := retryAfter(resp)
+		resp.Body.Close()
+		time.Sleep(wait)
 	}
+}
 
-	// Fallback for non-JSON errors
-	return fmt.Errorf("API error (status %d): %s", statusCode, string(body))
+// retryAfter reads the Retry-After header (seconds) of a 429 response
+func retryAfter(resp *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 1 {
+		return time.Second
+	}
+	return min(time.Duration(seconds)*time.Second, maxRateLimitWait)
 }
 
 // decodeResponse decodes a JSON response
@@ -192,11 +203,15 @@ func (c *APIClient) Register(username, email, password string) error {
 }
 
 // Login authenticates a user
-func (c *APIClient) Login(email, password string) (*AuthResponse, error) {
+// totpCode is only needed for accounts with two-factor authentication (pass "" otherwise).
+func (c *APIClient) Login(email, password, totpCode string) (*AuthResponse, error) {
 	payload := map[string]string{
 		"email":    email,
 		"password": password,
 	}
+	if totpCode != "" {
+		payload["totp_code"] = totpCode
+	}
 
 	resp, err := c.makeRequest("POST", "/api/v1/auth/login", payload, false)
 	if err != nil {
@@ -212,6 +227,60 @@ func (c *APIClient) Login(email, password string) (*AuthResponse, error) {
 	return &authResp, nil
 }
 
+// StartDeviceLogin starts a login that is approved from another device, for machines
+// where typing a password is awkward; poll PollDeviceLogin with the device code
+func (c *APIClient) StartDeviceLogin() (*model.DeviceCodeResponse, error) {
+	resp, err := c.makeRequest("POST", "/api/v1/auth/device", nil, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var codes model.DeviceCodeResponse
+	if err := decodeResponse(resp, &codes); err != nil {
+		return nil, err
+	}
+
+	return &codes, nil
+}
+
+// PollDeviceLogin asks once for the tokens of a device login
+// It fails with ErrAuthorizationPending until the login is approved.
+func (c *APIClient) PollDeviceLogin(deviceCode string) (*AuthResponse, error) {
+	payload := map[string]string{
+		"device_code": deviceCode,
+	}
+
+	resp, err := c.makeRequest("POST", "/api/v1/auth/device/token", payload, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var authResp AuthResponse
+	if err := decodeResponse(resp, &authResp); err != nil {
+		return nil, err
+	}
+
+	c.SetTokens(authResp.AccessToken, authResp.RefreshToken)
+	return &authResp, nil
+}
+
+// ApproveDevice signs the device showing userCode in to this account, or denies its login
+func (c *APIClient) ApproveDevice(userCode string, deny bool) (*model.DeviceAuthorization, error) {
+	payload := &model.DeviceApproveRequest{UserCode: userCode, Deny: deny}
+
+	resp, err := c.makeRequest("POST", "/api/v1/auth/device/approve", payload, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var auth model.DeviceAuthorization
+	if err := decodeResponse(resp, &auth); err != nil {
+		return nil, err
+	}
+
+	return &auth, nil
+}
+
 // RefreshToken refreshes the access token
 func (c *APIClient) RefreshToken() error {
 	if c.refreshToken == "" {
@@ -238,7 +307,12 @@ func (c *APIClient) RefreshToken() error {
 
 // Logout logs out the user
 func (c *APIClient) Logout() error {
-	resp, err := c.makeRequest("POST", "/api/v1/auth/logout", nil, true)
+	var payload any
+	if c.refreshToken != "" {
+		payload = model.RefreshRequest{RefreshToken: c.refreshToken}
+	}
+
+	resp, err := c.makeRequest("POST", "/api/v1/auth/logout", payload, true)
 	if err != nil {
 		return err
 	}
@@ -248,78 +322,62 @@ func (c *APIClient) Logout() error {
 	return decodeResponse(resp, nil)
 }
 
-// CreateNote creates a new note
-func (c *APIClient) CreateNote(req *model.CreateNoteRequest) (*model.Note, error) {
-	resp, err := c.makeRequest("POST", "/api/v1/notes", req, true)
+// ListSessions lists the devices signed in to the account, newest first
+func (c *APIClient) ListSessions() ([]*model.RefreshToken, error) {
+	resp, err := c.makeRequest("GET", "/api/v1/auth/sessions", nil, true)
 	if err != nil {
 		return nil, err
 	}
 
-	var note model.Note
-	if err := decodeResponse(resp, &note); err != nil {
+	var result struct {
+		Sessions []*model.RefreshToken `json:"sessions"`
+		Count    int                   `json:"count"`
+	}
+
+	if err := decodeResponse(resp, &result); err != nil {
 		return nil, err
 	}
 
-	return &note, nil
+	return result.Sessions, nil
 }
 
-// ListNotes lists notes with optional filters
-func (c *APIClient) ListNotes(filter model.NoteFilter) ([]*model.Note, int64, error) {
-	// Build query string
-	path := "/api/v1/notes?page=" + fmt.Sprint(filter.Page) + "&limit=" + fmt.Sprint(filter.Limit)
-	if filter.SortBy != "" {
-		path += "&sort_by=" + filter.SortBy
-	}
-	if filter.Search != "" {
-		path += "&search=" + filter.Search
-	}
-	if filter.TagID != nil && *filter.TagID != "" {
-		path += "&tag=" + *filter.TagID
-	}
-	if filter.NoteType != nil {
-		path += "&type=" + string(*filter.NoteType)
-	}
-
-	resp, err := c.makeRequest("GET", path, nil, true)
+// RevokeSession signs out one device
+func (c *APIClient) RevokeSession(id uuid.UUID) error {
+	resp, err := c.makeRequest("DELETE", "/api/v1/auth/sessions/"+id.String(), nil, true)
 	if err != nil {
-		return nil, 0, err
+		return err
 	}
 
-	var result struct {
-		Notes      []*model.Note `json:"notes"`
-		Pagination struct {
-			Page       int   `json:"page"`
-			Limit      int   `json:"limit"`
-			Total      int64 `json:"total"`
-			TotalPages int   `json:"total_pages"`
-		} `json:"pagination"`
-	}
+	return decodeResponse(resp, nil)
+}
 
-	if err := decodeResponse(resp, &result); err != nil {
-		return nil, 0, err
+// RevokeAllSessions revokes the refresh tokens of every device, including this one
+func (c *APIClient) RevokeAllSessions() error {
+	resp, err := c.makeRequest("DELETE", "/api/v1/auth/sessions", nil, true)
+	if err != nil {
+		return err
 	}
 
-	return result.Notes, result.Pagination.Total, nil
+	return decodeResponse(resp, nil)
 }
 
-// GetNote retrieves a single note by ID
-func (c *APIClient) GetNote(id uuid.UUID) (*model.Note, error) {
-	resp, err := c.makeRequest("GET", "/api/v1/notes/"+id.String(), nil, true)
-	if err != nil {
-		return nil, err
-	}
+// VerifyEmail confirms the account's email address with a token from the verification email
+func (c *APIClient) VerifyEmail(token string) error {
+	payload := model.VerifyEmailRequest{Token: token}
 
-	var note model.Note
-	if err := decodeResponse(resp, &note); err != nil {
-		return nil, err
+	resp, err := c.makeRequest("POST", "/api/v1/auth/verify-email", payload, false)
+	if err != nil {
+		return err
 	}
 
-	return &note, nil
+	return decodeResponse(resp, nil)
 }
 
-// UpdateNote updates an existing note
-func (c *APIClient) UpdateNote(id uuid.UUID, req *model.UpdateNoteRequest) error {
-	resp, err := c.makeRequest("PUT", "/api/v1/notes/"+id.String(), req, true)
+// ResendVerification asks the server to email a new verification token
+func (c *APIClient) ResendVerification(email string) error {
+	payload := model.ResendVerificationRequest{Email: email}
+
+	resp, err := c.makeRequest("POST", "/api/v1/auth/resend-verification", payload, false)
 	if err != nil {
 		return err
 	}
@@ -327,9 +385,11 @@ func (c *APIClient) UpdateNote(id uuid.UUID, req *model.UpdateNoteRequest) error
 	return decodeResponse(resp, nil)
 }
 
-// DeleteNote deletes a note
-func (c *APIClient) DeleteNote(id uuid.UUID) error {
-	resp, err := c.makeRequest("DELETE", "/api/v1/notes/"+id.String(), nil, true)
+// ForgotPassword asks the server to email a password reset token
+func (c *APIClient) ForgotPassword(email string) error {
+	payload := model.ForgotPasswordRequest{Email: email}
+
+	resp, err := c.makeRequest("POST", "/api/v1/auth/forgot-password", payload, false)
 	if err != nil {
 		return err
 	}
@@ -337,84 +397,74 @@ func (c *APIClient) DeleteNote(id uuid.UUID) error {
 	return decodeResponse(resp, nil)
 }
 
-// SearchNotes searches notes using full-text search
-func (c *APIClient) SearchNotes(query string, page, limit int) (*model.SearchResponse, error) {
-	path := fmt.Sprintf("/api/v1/search?q=%s&page=%d&limit=%d", url.QueryEscape(query), page, limit)
+// ResetPassword sets a new password using a token from the reset email
+func (c *APIClient) ResetPassword(token, password string) error {
+	payload := model.ResetPasswordRequest{Token: token, Password: password}
 
-	resp, err := c.makeRequest("GET", path, nil, true)
+	resp, err := c.makeRequest("POST", "/api/v1/auth/reset-password", payload, false)
 	if err != nil {
-		return nil, err
-	}
-
-	var searchResp model.SearchResponse
-	if err := decodeResponse(resp, &searchResp); err != nil {
-		return nil, err
+		return err
 	}
 
-	return &searchResp, nil
+	return decodeResponse(resp, nil)
 }
 
-// GetTags retrieves all tags
-func (c *APIClient) GetTags() ([]*model.Tag, error) {
-	resp, err := c.makeRequest("GET", "/api/v1/tags", nil, true)
+// ChangePassword changes the account password
+// The server signs out every other device and returns new tokens for this one.
+func (c *APIClient) ChangePassword(currentPassword, newPassword string) (*AuthResponse, error) {
+	payload := model.ChangePasswordRequest{CurrentPassword: currentPassword, NewPassword: newPassword}
+
+	resp, err := c.makeRequest("PUT", "/api/v1/users/me/password", payload, true)
 	if err != nil {
 		return nil, err
 	}
 
-	var result struct {
-		Tags       []*model.Tag `json:"tags"`
-		Pagination struct {
-			Page       int `json:"page"`
-			Limit      int `json:"limit"`
-			Total      int64 `json:"total"`
-			TotalPages int `json:"total_pages"`
-		} `json:"pagination"`
-	}
-
-	if err := decodeResponse(resp, &result); err != nil {
+	var authResp AuthResponse
+	if err := decodeResponse(resp, &authResp); err != nil {
 		return nil, err
 	}
 
-	return result.Tags, nil
+	c.SetTokens(authResp.AccessToken, authResp.RefreshToken)
+	return &authResp, nil
 }
 
-// CreateTag creates a new tag
-func (c *APIClient) CreateTag(name string) (*model.Tag, error) {
-	payload := map[string]string{"name": name}
+// DeleteAccount deletes the account and all of its notes
+func (c *APIClient) DeleteAccount(password string) error {
+	payload := model.DeleteAccountRequest{Password: password}
 
-	resp, err := c.makeRequest("POST", "/api/v1/tags", payload, true)
+	resp, err := c.makeRequest("DELETE", "/api/v1/users/me", payload, true)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	var tag model.Tag
-	if err := decodeResponse(resp, &tag); err != nil {
-		return nil, err
+	if err := decodeResponse(resp, nil); err != nil {
+		return err
 	}
 
-	return &tag, nil
+	c.SetTokens("", "")
+	return nil
 }
 
-// UpdateTag updates an existing tag
-func (c *APIClient) UpdateTag(id uuid.UUID, name string) (*model.Tag, error) {
-	payload := map[string]string{"name": name}
-
-	resp, err := c.makeRequest("PUT", "/api/v1/tags/"+id.String(), payload, true)
+// SetupTOTP starts two-factor setup and returns the new secret
+func (c *APIClient) SetupTOTP() (*model.TOTPSetupResponse, error) {
+	resp, err := c.makeRequest("POST", "/api/v1/auth/2fa/setup", nil, true)
 	if err != nil {
 		return nil, err
 	}
 
-	var tag model.Tag
-	if err := decodeResponse(resp, &tag); err != nil {
+	var setup model.TOTPSetupResponse
+	if err := decodeResponse(resp, &setup); err != nil {
 		return nil, err
 	}
 
-	return &tag, nil
+	return &setup, nil
 }
 
-// DeleteTag deletes a tag
-func (c *APIClient) DeleteTag(id uuid.UUID) error {
-	resp, err := c.makeRequest("DELETE", "/api/v1/tags/"+id.String(), nil, true)
+// VerifyTOTP confirms a code from the authenticator app and enables two-factor authentication
+func (c *APIClient) VerifyTOTP(code string) error {
+	payload := model.TOTPVerifyRequest{Code: code}
+
+	resp, err := c.makeRequest("POST", "/api/v1/auth/2fa/verify", payload, true)
 	if err != nil {
 		return err
 	}
@@ -422,125 +472,1364 @@ func (c *APIClient) DeleteTag(id uuid.UUID) error {
 	return decodeResponse(resp, nil)
 }
 
-// GetNoteTags retrieves tags for a specific note
-func (c *APIClient) GetNoteTags(noteID uuid.UUID) ([]*model.Tag, error) {
-	resp, err := c.makeRequest("GET", "/api/v1/notes/"+noteID.String()+"/tags", nil, true)
+// CreateNote creates a new note
+func (c *APIClient) CreateNote(req *model.CreateNoteRequest) (*model.Note, error) {
+	resp, err := c.makeRequest("POST", "/api/v1/notes", req, true)
 	if err != nil {
+		if c.cache != nil && isOffline(err) {
+			return c.queueCreateNote(req)
+		}
 		return nil, err
 	}
 
-	var result struct {
-		Tags []*model.Tag `json:"tags"`
-	}
-
-	if err := decodeResponse(resp, &result); err != nil {
+	var note model.Note
+	if err := decodeResponse(resp, &note); err != nil {
 		return nil, err
 	}
 
-	return result.Tags, nil
+	c.cacheNotes(&note)
+	return &note, nil
 }
 
-// AddTagToNote adds a tag to a note
-func (c *APIClient) AddTagToNote(noteID, tagID uuid.UUID) error {
-	resp, err := c.makeRequest("POST", "/api/v1/notes/"+noteID.String()+"/tags/"+tagID.String(), nil, true)
-	if err != nil {
-		return err
-	}
+// createBatchSize is how many notes CreateNotes sends per request
+const createBatchSize = 100
 
-	return decodeResponse(resp, nil)
-}
+// CreateNotes creates many notes using the batch endpoint
+// Requests are sent in chunks; result indexes refer to the position in reqs.
+func (c *APIClient) CreateNotes(reqs []*model.CreateNoteRequest) (*model.BatchCreateResponse, error) {
+	all := &model.BatchCreateResponse{Results: make([]*model.BatchNoteResult, 0, len(reqs))}
 
-// RemoveTagFromNote removes a tag from a note
-func (c *APIClient) RemoveTagFromNote(noteID, tagID uuid.UUID) error {
-	resp, err := c.makeRequest("DELETE", "/api/v1/notes/"+noteID.String()+"/tags/"+tagID.String(), nil, true)
-	if err != nil {
-		return err
+	for start := 0; start < len(reqs); start += createBatchSize {
+		end := min(start+createBatchSize, len(reqs))
+
+		resp, err := c.makeRequest("POST", "/api/v1/notes/batch", reqs[start:end], true)
+		if err != nil {
+			return all, err
+		}
+
+		var batch model.BatchCreateResponse
+		if err := decodeResponse(resp, &batch); err != nil {
+			return all, err
+		}
+
+		for _, result := range batch.Results {
+			result.Index += start
+			if result.Note != nil {
+				c.cacheNotes(result.Note)
+			}
+		}
+
+		all.Results = append(all.Results, batch.Results...)
+		all.Created += batch.Created
+		all.Failed += batch.Failed
 	}
 
-	return decodeResponse(resp, nil)
+	return all, nil
 }
 
-// GetTagNotes retrieves notes for a specific tag
-func (c *APIClient) GetTagNotes(tagID uuid.UUID) ([]*model.Note, error) {
-	resp, err := c.makeRequest("GET", "/api/v1/tags/"+tagID.String()+"/notes", nil, true)
+// ListNotes lists notes with optional filters
+func (c *APIClient) ListNotes(filter model.NoteFilter) ([]*model.Note, int64, error) {
+	path := "/api/v1/notes?" + noteFilterQuery(filter, fmt.Sprint(filter.Limit))
+
+	resp, err := c.makeRequest("GET", path, nil, true)
 	if err != nil {
-		return nil, err
+		if c.cache != nil && isOffline(err) {
+			return c.cache.ListNotes(filter)
+		}
+		return nil, 0, err
 	}
 
 	var result struct {
-		Notes []*model.Note `json:"notes"`
+		Notes      []*model.Note `json:"notes"`
+		Pagination struct {
+			Page       int   `json:"page"`
+			Limit      int   `json:"limit"`
+			Total      int64 `json:"total"`
+			TotalPages int   `json:"total_pages"`
+		} `json:"pagination"`
 	}
 
 	if err := decodeResponse(resp, &result); err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	return result.Notes, nil
+	c.cacheNotes(result.Notes...)
+	return result.Notes, result.Pagination.Total, nil
 }
 
-// GetGraph retrieves the knowledge graph
-func (c *APIClient) GetGraph() (*model.GraphResponse, error) {
-	resp, err := c.makeRequest("GET", "/api/v1/notes/graph", nil, true)
+// StreamNotes calls fn for every note matching the filter (pagination is ignored)
+// The server streams the notes as NDJSON, so even very large vaults are never held in memory at once.
+// Iteration stops at the first error returned by fn.
+func (c *APIClient) StreamNotes(filter model.NoteFilter, fn func(*model.Note) error) error {
+	resp, err := c.makeRequest("GET", "/api/v1/notes?"+noteFilterQuery(filter, "all"), nil, true)
 	if err != nil {
-		return nil, err
+		return err
 	}
+	defer resp.Body.Close()
 
-	var graph model.GraphResponse
-	if err := decodeResponse(resp, &graph); err != nil {
-		return nil, err
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return formatAPIError(resp.StatusCode, body)
 	}
 
-	return &graph, nil
-}
+	dec := json.NewDecoder(resp.Body)
+	for {
+		// A failure after the stream started arrives as a final {"error": "..."} line
+		var line struct {
+			model.Note
+			Error string `json:"error"`
+		}
+		if err := dec.Decode(&line); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("read note stream: %w", err)
+		}
 
-// GetLinks retrieves outgoing links from a note
-func (c *APIClient) GetLinks(id uuid.UUID) ([]*model.LinkDetail, error) {
-	resp, err := c.makeRequest("GET", "/api/v1/notes/"+id.String()+"/links", nil, true)
+		if line.Error != "" {
+			return fmt.Errorf("stream notes: %s", line.Error)
+		}
+
+		note := line.Note
+		if err := fn(&note); err != nil {
+			return err
+		}
+	}
+}
+
+// noteFilterQuery builds the query string of a note listing
+func noteFilterQuery(filter model.NoteFilter, limit string) string {
+	params := url.Values{}
+	params.Set("page", fmt.Sprint(filter.Page))
+	params.Set("limit", limit)
+	if filter.SortBy != "" {
+		params.Set("sort_by", filter.SortBy)
+	}
+	if filter.SortOrder != "" {
+		params.Set("sort_order", filter.SortOrder)
+	}
+	if filter.Search != "" {
+		params.Set("search", filter.Search)
+	}
+	if filter.TagID != nil && *filter.TagID != "" {
+		params.Set("tag", *filter.TagID)
+	}
+	if filter.NoteType != nil {
+		params.Set("type", string(*filter.NoteType))
+	}
+	if filter.Status != nil {
+		params.Set("status", string(*filter.Status))
+	}
+	var include []string
+	if filter.IncludeTags {
+		include = append(include, "tags")
+	}
+	if filter.IncludeLinkCounts {
+		include = append(include, "link_counts")
+	}
+	if len(include) > 0 {
+		params.Set("include", strings.Join(include, ","))
+	}
+	return params.Encode()
+}
+
+// GetNote retrieves a single note by ID
+func (c *APIClient) GetNote(id uuid.UUID) (*model.Note, error) {
+	var note model.Note
+	if err := c.getConditional("/api/v1/notes/"+id.String(), &note); err != nil {
+		if c.cache != nil && isOffline(err) {
+			return c.cache.GetNote(id)
+		}
+		return nil, err
+	}
+
+	c.cacheNotes(&note)
+	return &note, nil
+}
+
+// GetNoteExpanded retrieves a single note by ID with ExpandedContent set, its ![[...]] embeds expanded
+// Offline, the cached note is returned, with the embeds expanded when it was cached.
+func (c *APIClient) GetNoteExpanded(id uuid.UUID) (*model.Note, error) {
+	var note model.Note
+	if err := c.getConditional("/api/v1/notes/"+id.String()+"?expand=embeds", &note); err != nil {
+		if c.cache != nil && isOffline(err) {
+			return c.cache.GetNote(id)
+		}
+		return nil, err
+	}
+
+	c.cacheNotes(&note)
+	return &note, nil
+}
+
+// UpdateNote updates an existing note
+func (c *APIClient) UpdateNote(id uuid.UUID, req *model.UpdateNoteRequest) error {
+	resp, err := c.makeRequest("PUT", "/api/v1/notes/"+id.String(), req, true)
+	if err != nil {
+		if c.cache != nil && isOffline(err) {
+			return c.queueUpdateNote(id, req)
+		}
+		return err
+	}
+
+	if err := decodeResponse(resp, nil); err != nil {
+		return err
+	}
+
+	c.applyCachedUpdate(id, req)
+	return nil
+}
+
+// UpdateNoteMetadata sets keys of a note's metadata, a nil value removes a key
+func (c *APIClient) UpdateNoteMetadata(id uuid.UUID, metadata model.Metadata) (*model.Note, error) {
+	resp, err := c.makeRequest("PATCH", "/api/v1/notes/"+id.String()+"/metadata", metadata, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var note model.Note
+	if err := decodeResponse(resp, &note); err != nil {
+		return nil, err
+	}
+
+	c.cacheNotes(&note)
+	return &note, nil
+}
+
+// AppendNote adds text to the end of a note on the server
+// Unlike a read-modify-write through UpdateNote, concurrent appends don't overwrite each other.
+func (c *APIClient) AppendNote(id uuid.UUID, text string) (*model.Note, error) {
+	return c.concatNote(id, "append", text)
+}
+
+// PrependNote adds text to the start of a note on the server
+func (c *APIClient) PrependNote(id uuid.UUID, text string) (*model.Note, error) {
+	return c.concatNote(id, "prepend", text)
+}
+
+// concatNote sends text to the append or prepend endpoint of a note
+func (c *APIClient) concatNote(id uuid.UUID, op, text string) (*model.Note, error) {
+	resp, err := c.makeRequest("PATCH", "/api/v1/notes/"+id.String()+"/"+op, &model.AppendNoteRequest{Content: text}, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var note model.Note
+	if err := decodeResponse(resp, &note); err != nil {
+		return nil, err
+	}
+
+	if c.cache != nil {
+		_ = c.cache.PutNotes([]*model.Note{&note})
+	}
+	return &note, nil
+}
+
+// DeleteNote deletes a note
+func (c *APIClient) DeleteNote(id uuid.UUID) error {
+	resp, err := c.makeRequest("DELETE", "/api/v1/notes/"+id.String(), nil, true)
+	if err != nil {
+		return err
+	}
+
+	if err := decodeResponse(resp, nil); err != nil {
+		return err
+	}
+
+	if c.cache != nil {
+		_ = c.cache.DeleteNote(id)
+	}
+	return nil
+}
+
+// RestoreNote restores a deleted note
+func (c *APIClient) RestoreNote(id uuid.UUID) (*model.Note, error) {
+	resp, err := c.makeRequest("POST", "/api/v1/notes/"+id.String()+"/restore", nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var note model.Note
+	if err := decodeResponse(resp, &note); err != nil {
+		return nil, err
+	}
+
+	c.cacheNotes(&note)
+	return &note, nil
+}
+
+// ExportNotes downloads all notes as a zip of Markdown files and writes it to w
+// With expandEmbeds, each note is written with its ![[...]] embeds expanded. Returns the number of bytes written
+func (c *APIClient) ExportNotes(w io.Writer, expandEmbeds bool) (int64, error) {
+	path := "/api/v1/notes/export"
+	if expandEmbeds {
+		path += "?expand=embeds"
+	}
+	resp, err := c.makeRequest("GET", path, nil, true)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return 0, formatAPIError(resp.StatusCode, body)
+	}
+
+	n, err := io.Copy(w, resp.Body)
+	if err != nil {
+		return n, fmt.Errorf("download export: %w", err)
+	}
+
+	return n, nil
+}
+
+// UploadAttachment uploads a local file as an attachment of a note
+func (c *APIClient) UploadAttachment(noteID uuid.UUID, path string) (*model.Attachment, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open file: %w", err)
+	}
+	defer file.Close()
+
+	contentType := mime.TypeByExtension(filepath.Ext(path))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	// Stream the multipart body so large files are not buffered in memory
+	pr, pw := io.Pipe()
+	form := multipart.NewWriter(pw)
+	go func() {
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", mime.FormatMediaType("form-data", map[string]string{
+			"name":     "file",
+			"filename": filepath.Base(path),
+		}))
+		header.Set("Content-Type", contentType)
+
+		part, err := form.CreatePart(header)
+		if err == nil {
+			_, err = io.Copy(part, file)
+		}
+		if err == nil {
+			err = form.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	req, err := http.NewRequest("POST", c.baseURL+"/api/v1/notes/"+noteID.String()+"/attachments", pr)
+	if err != nil {
+		pr.Close()
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var attachment model.Attachment
+	if err := decodeResponse(resp, &attachment); err != nil {
+		return nil, err
+	}
+
+	return &attachment, nil
+}
+
+// ListAttachments retrieves the attachments of a note
+func (c *APIClient) ListAttachments(noteID uuid.UUID) ([]*model.Attachment, error) {
+	resp, err := c.makeRequest("GET", "/api/v1/notes/"+noteID.String()+"/attachments", nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Attachments []*model.Attachment `json:"attachments"`
+		Count       int                 `json:"count"`
+	}
+
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Attachments, nil
+}
+
+// DownloadAttachment downloads an attachment and writes its contents to w
+// Returns the number of bytes written
+func (c *APIClient) DownloadAttachment(noteID, attachmentID uuid.UUID, w io.Writer) (int64, error) {
+	path := fmt.Sprintf("/api/v1/notes/%s/attachments/%s", noteID, attachmentID)
+
+	resp, err := c.makeRequest("GET", path, nil, true)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return 0, formatAPIError(resp.StatusCode, body)
+	}
+
+	n, err := io.Copy(w, resp.Body)
+	if err != nil {
+		return n, fmt.Errorf("download attachment: %w", err)
+	}
+
+	return n, nil
+}
+
+// DeleteAttachment removes an attachment from a note
+func (c *APIClient) DeleteAttachment(noteID, attachmentID uuid.UUID) error {
+	path := fmt.Sprintf("/api/v1/notes/%s/attachments/%s", noteID, attachmentID)
+
+	resp, err := c.makeRequest("DELETE", path, nil, true)
+	if err != nil {
+		return err
+	}
+
+	return decodeResponse(resp, nil)
+}
+
+// PublishNote publishes a note at a public, read-only link
+// The bool is true when the link was created, false when the note was already published.
+func (c *APIClient) PublishNote(noteID uuid.UUID) (*model.PublicLink, bool, error) {
+	resp, err := c.makeRequest("POST", "/api/v1/notes/"+noteID.String()+"/share", nil, true)
+	if err != nil {
+		return nil, false, err
+	}
+	created := resp.StatusCode == http.StatusCreated
+
+	var link model.PublicLink
+	if err := decodeResponse(resp, &link); err != nil {
+		return nil, false, err
+	}
+
+	return &link, created, nil
+}
+
+// UnpublishNote revokes the public link of a note
+func (c *APIClient) UnpublishNote(noteID uuid.UUID) error {
+	resp, err := c.makeRequest("DELETE", "/api/v1/notes/"+noteID.String()+"/share", nil, true)
+	if err != nil {
+		return err
+	}
+
+	return decodeResponse(resp, nil)
+}
+
+// ListPublicLinks retrieves the published notes with their public links
+func (c *APIClient) ListPublicLinks() ([]*model.PublicLink, error) {
+	resp, err := c.makeRequest("GET", "/api/v1/public-links", nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Links []*model.PublicLink `json:"links"`
+		Count int                 `json:"count"`
+	}
+
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Links, nil
+}
+
+// ShareNoteWith shares a note with another user, by username or email
+// The bool is true when the share was created, false when its permission was changed.
+func (c *APIClient) ShareNoteWith(noteID uuid.UUID, user string, permission model.SharePermission) (*model.NoteShare, bool, error) {
+	payload := model.ShareNoteRequest{User: user, Permission: permission}
+
+	resp, err := c.makeRequest("POST", "/api/v1/notes/"+noteID.String()+"/shares", payload, true)
+	if err != nil {
+		return nil, false, err
+	}
+	created := resp.StatusCode == http.StatusCreated
+
+	var share model.NoteShare
+	if err := decodeResponse(resp, &share); err != nil {
+		return nil, false, err
+	}
+
+	return &share, created, nil
+}
+
+// ListNoteShares retrieves the users a note is shared with
+func (c *APIClient) ListNoteShares(noteID uuid.UUID) ([]*model.NoteShare, error) {
+	resp, err := c.makeRequest("GET", "/api/v1/notes/"+noteID.String()+"/shares", nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Shares []*model.NoteShare `json:"shares"`
+		Count  int                `json:"count"`
+	}
+
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Shares, nil
+}
+
+// UnshareNoteWith stops sharing a note with a user
+func (c *APIClient) UnshareNoteWith(noteID, userID uuid.UUID) error {
+	resp, err := c.makeRequest("DELETE", "/api/v1/notes/"+noteID.String()+"/shares/"+userID.String(), nil, true)
+	if err != nil {
+		return err
+	}
+
+	return decodeResponse(resp, nil)
+}
+
+// ListSharedWithMe retrieves the notes other users share with the current user
+func (c *APIClient) ListSharedWithMe() ([]*model.Note, error) {
+	resp, err := c.makeRequest("GET", "/api/v1/notes/shared", nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Notes []*model.Note `json:"notes"`
+		Count int           `json:"count"`
+	}
+
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Notes, nil
+}
+
+// CreateWebhook registers a webhook for the events, returning it with its signing secret
+func (c *APIClient) CreateWebhook(webhookURL string, events []string) (*model.Webhook, error) {
+	payload := model.CreateWebhookRequest{URL: webhookURL, Events: events}
+
+	resp, err := c.makeRequest("POST", "/api/v1/webhooks", payload, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var hook model.Webhook
+	if err := decodeResponse(resp, &hook); err != nil {
+		return nil, err
+	}
+
+	return &hook, nil
+}
+
+// ListWebhooks retrieves the registered webhooks
+func (c *APIClient) ListWebhooks() ([]*model.Webhook, error) {
+	resp, err := c.makeRequest("GET", "/api/v1/webhooks", nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Webhooks []*model.Webhook `json:"webhooks"`
+		Count    int              `json:"count"`
+	}
+
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Webhooks, nil
+}
+
+// UpdateWebhook changes a webhook, nil fields are kept
+func (c *APIClient) UpdateWebhook(id uuid.UUID, req *model.UpdateWebhookRequest) (*model.Webhook, error) {
+	resp, err := c.makeRequest("PUT", "/api/v1/webhooks/"+id.String(), req, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var hook model.Webhook
+	if err := decodeResponse(resp, &hook); err != nil {
+		return nil, err
+	}
+
+	return &hook, nil
+}
+
+// DeleteWebhook deletes a webhook and its delivery history
+func (c *APIClient) DeleteWebhook(id uuid.UUID) error {
+	resp, err := c.makeRequest("DELETE", "/api/v1/webhooks/"+id.String(), nil, true)
+	if err != nil {
+		return err
+	}
+
+	return decodeResponse(resp, nil)
+}
+
+// ListWebhookDeliveries retrieves the latest deliveries of a webhook, newest first
+func (c *APIClient) ListWebhookDeliveries(id uuid.UUID, limit int) ([]*model.WebhookDelivery, error) {
+	path := fmt.Sprintf("/api/v1/webhooks/%s/deliveries?limit=%d", id, limit)
+	resp, err := c.makeRequest("GET", path, nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Deliveries []*model.WebhookDelivery `json:"deliveries"`
+		Count      int                      `json:"count"`
+	}
+
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Deliveries, nil
+}
+
+// SearchNotes searches notes using full-text search
+// With fuzzy, a search that matches nothing returns the notes with similarly spelled titles.
+func (c *APIClient) SearchNotes(query string, page, limit int, fuzzy bool) (*model.SearchResponse, error) {
+	path := fmt.Sprintf("/api/v1/search?q=%s&page=%d&limit=%d", url.QueryEscape(query), page, limit)
+	if fuzzy {
+		path += "&fuzzy=true"
+	}
+
+	resp, err := c.makeRequest("GET", path, nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var searchResp model.SearchResponse
+	if err := decodeResponse(resp, &searchResp); err != nil {
+		return nil, err
+	}
+
+	return &searchResp, nil
+}
+
+// GetSearchHistory gets the user's recent search queries, most recent first
+func (c *APIClient) GetSearchHistory(limit int) (*model.SearchHistoryResponse, error) {
+	resp, err := c.makeRequest("GET", fmt.Sprintf("/api/v1/search/history?limit=%d", limit), nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var historyResp model.SearchHistoryResponse
+	if err := decodeResponse(resp, &historyResp); err != nil {
+		return nil, err
+	}
+
+	return &historyResp, nil
+}
+
+// SemanticSearchNotes finds notes closest in meaning to the query
+func (c *APIClient) SemanticSearchNotes(query string, limit int) (*model.SemanticSearchResponse, error) {
+	resp, err := c.makeRequest("POST", "/api/v1/search/semantic", &model.SemanticSearchRequest{Query: query, Limit: limit}, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var searchResp model.SemanticSearchResponse
+	if err := decodeResponse(resp, &searchResp); err != nil {
+		return nil, err
+	}
+
+	return &searchResp, nil
+}
+
+// GetTags retrieves all tags
+func (c *APIClient) GetTags() ([]*model.Tag, error) {
+	// Request the API maximum so whole tag hierarchies come back in one page
+	resp, err := c.makeRequest("GET", "/api/v1/tags?limit=100", nil, true)
+	if err != nil {
+		if c.cache != nil && isOffline(err) {
+			return c.cache.GetTags()
+		}
+		return nil, err
+	}
+
+	var result struct {
+		Tags       []*model.Tag `json:"tags"`
+		Pagination struct {
+			Page       int `json:"page"`
+			Limit      int `json:"limit"`
+			Total      int64 `json:"total"`
+			TotalPages int `json:"total_pages"`
+		} `json:"pagination"`
+	}
+
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	if c.cache != nil {
+		_ = c.cache.PutTags(result.Tags)
+	}
+	return result.Tags, nil
+}
+
+// GetTagAnalytics retrieves how tags were used over the last months months and the pairs most used together
+func (c *APIClient) GetTagAnalytics(months, pairs int) (*model.TagAnalytics, error) {
+	resp, err := c.makeRequest("GET", fmt.Sprintf("/api/v1/tags/analytics?months=%d&pairs=%d", months, pairs), nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var analytics model.TagAnalytics
+	if err := decodeResponse(resp, &analytics); err != nil {
+		return nil, err
+	}
+
+	return &analytics, nil
+}
+
+// CreateTag creates a new tag
+func (c *APIClient) CreateTag(name string) (*model.Tag, error) {
+	payload := map[string]string{"name": name}
+
+	resp, err := c.makeRequest("POST", "/api/v1/tags", payload, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var tag model.Tag
+	if err := decodeResponse(resp, &tag); err != nil {
+		return nil, err
+	}
+
+	return &tag, nil
+}
+
+// UpdateTag updates an existing tag
+func (c *APIClient) UpdateTag(id uuid.UUID, name string) (*model.Tag, error) {
+	payload := map[string]string{"name": name}
+
+	resp, err := c.makeRequest("PUT", "/api/v1/tags/"+id.String(), payload, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var tag model.Tag
+	if err := decodeResponse(resp, &tag); err != nil {
+		return nil, err
+	}
+
+	return &tag, nil
+}
+
+// DeleteTag deletes a tag
+func (c *APIClient) DeleteTag(id uuid.UUID) error {
+	resp, err := c.makeRequest("DELETE", "/api/v1/tags/"+id.String(), nil, true)
+	if err != nil {
+		return err
+	}
+
+	return decodeResponse(resp, nil)
+}
+
+// GetNoteTags retrieves tags for a specific note
+func (c *APIClient) GetNoteTags(noteID uuid.UUID) ([]*model.Tag, error) {
+	resp, err := c.makeRequest("GET", "/api/v1/notes/"+noteID.String()+"/tags", nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Tags []*model.Tag `json:"tags"`
+	}
+
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Tags, nil
+}
+
+// AddTagToNote adds a tag to a note
+func (c *APIClient) AddTagToNote(noteID, tagID uuid.UUID) error {
+	resp, err := c.makeRequest("POST", "/api/v1/notes/"+noteID.String()+"/tags/"+tagID.String(), nil, true)
+	if err != nil {
+		return err
+	}
+
+	return decodeResponse(resp, nil)
+}
+
+// RemoveTagFromNote removes a tag from a note
+func (c *APIClient) RemoveTagFromNote(noteID, tagID uuid.UUID) error {
+	resp, err := c.makeRequest("DELETE", "/api/v1/notes/"+noteID.String()+"/tags/"+tagID.String(), nil, true)
+	if err != nil {
+		return err
+	}
+
+	return decodeResponse(resp, nil)
+}
+
+// BulkTagNotes adds or removes a tag on many notes in one request
+// action is model.BulkTagAdd or model.BulkTagRemove; the number of changed notes is returned.
+func (c *APIClient) BulkTagNotes(tagID uuid.UUID, noteIDs []uuid.UUID, action string) (int64, error) {
+	req := &model.BulkTagRequest{
+		TagID:   tagID.String(),
+		NoteIDs: make([]string, len(noteIDs)),
+		Action:  action,
+	}
+	for i, id := range noteIDs {
+		req.NoteIDs[i] = id.String()
+	}
+
+	resp, err := c.makeRequest("POST", "/api/v1/notes/tags/bulk", req, true)
+	if err != nil {
+		return 0, err
+	}
+
+	var result model.BulkTagResponse
+	if err := decodeResponse(resp, &result); err != nil {
+		return 0, err
+	}
+
+	return result.Updated, nil
+}
+
+// GetTagNotes retrieves notes for a specific tag
+func (c *APIClient) GetTagNotes(tagID uuid.UUID) ([]*model.Note, error) {
+	resp, err := c.makeRequest("GET", "/api/v1/tags/"+tagID.String()+"/notes", nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Notes []*model.Note `json:"notes"`
+	}
+
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Notes, nil
+}
+
+// GetGraph retrieves the knowledge graph
+func (c *APIClient) GetGraph() (*model.GraphResponse, error) {
+	var graph model.GraphResponse
+	if err := c.getConditional("/api/v1/notes/graph", &graph); err != nil {
+		return nil, err
+	}
+
+	return &graph, nil
+}
+
+// GetLocalGraph retrieves the part of the graph within depth hops of a note
+func (c *APIClient) GetLocalGraph(root uuid.UUID, depth int) (*model.GraphResponse, error) {
+	path := fmt.Sprintf("/api/v1/notes/graph?root=%s&depth=%d", root, depth)
+	var graph model.GraphResponse
+	if err := c.getConditional(path, &graph); err != nil {
+		return nil, err
+	}
+
+	return &graph, nil
+}
+
+// GetGraphPath finds the shortest chain of links between two notes
+func (c *APIClient) GetGraphPath(from, to uuid.UUID) (*model.GraphPath, error) {
+	resp, err := c.makeRequest("GET", fmt.Sprintf("/api/v1/notes/graph/path?from=%s&to=%s", from, to), nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var path model.GraphPath
+	if err := decodeResponse(resp, &path); err != nil {
+		return nil, err
+	}
+
+	return &path, nil
+}
+
+// GetLinks retrieves outgoing links from a note
+func (c *APIClient) GetLinks(id uuid.UUID) ([]*model.LinkDetail, error) {
+	resp, err := c.makeRequest("GET", "/api/v1/notes/"+id.String()+"/links", nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var links []*model.LinkDetail
+	if err := decodeResponse(resp, &links); err != nil {
+		return nil, err
+	}
+
+	return links, nil
+}
+
+// GetBacklinks retrieves backlinks to a note
+func (c *APIClient) GetBacklinks(id uuid.UUID) ([]*model.LinkDetail, error) {
+	resp, err := c.makeRequest("GET", "/api/v1/notes/"+id.String()+"/backlinks", nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var links []*model.LinkDetail
+	if err := decodeResponse(resp, &links); err != nil {
+		return nil, err
+	}
+
+	return links, nil
+}
+
+// GetRelatedNotes retrieves up to limit notes related to a note, best first
+func (c *APIClient) GetRelatedNotes(id uuid.UUID, limit int) ([]*model.RelatedNote, error) {
+	path := fmt.Sprintf("/api/v1/notes/%s/related?limit=%d", id, limit)
+	resp, err := c.makeRequest("GET", path, nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var related []*model.RelatedNote
+	if err := decodeResponse(resp, &related); err != nil {
+		return nil, err
+	}
+
+	return related, nil
+}
+
+// GetNoteStats retrieves how often a note was viewed and edited, and how many links it has
+func (c *APIClient) GetNoteStats(id uuid.UUID) (*model.NoteStats, error) {
+	resp, err := c.makeRequest("GET", fmt.Sprintf("/api/v1/notes/%s/stats", id), nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var stats model.NoteStats
+	if err := decodeResponse(resp, &stats); err != nil {
+		return nil, err
+	}
+
+	return &stats, nil
+}
+
+// LogFocus logs a completed focus session on a note
+func (c *APIClient) LogFocus(id uuid.UUID, duration time.Duration) (*model.Activity, error) {
+	req := &model.LogFocusRequest{DurationSeconds: int(duration.Seconds())}
+	resp, err := c.makeRequest("POST", "/api/v1/notes/"+id.String()+"/focus", req, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var activity model.Activity
+	if err := decodeResponse(resp, &activity); err != nil {
+		return nil, err
+	}
+
+	return &activity, nil
+}
+
+// GetUnresolvedLinks retrieves wiki links that point at notes which don't exist yet
+func (c *APIClient) GetUnresolvedLinks() ([]*model.UnresolvedLink, error) {
+	resp, err := c.makeRequest("GET", "/api/v1/links/unresolved", nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var links []*model.UnresolvedLink
+	if err := decodeResponse(resp, &links); err != nil {
+		return nil, err
+	}
+
+	return links, nil
+}
+
+// GetTasks retrieves tasks extracted from note checkboxes (status: open, done or all)
+func (c *APIClient) GetTasks(status string) ([]*model.Task, error) {
+	path := "/api/v1/tasks"
+	if status != "" {
+		path += "?status=" + url.QueryEscape(status)
+	}
+
+	resp, err := c.makeRequest("GET", path, nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Tasks []*model.Task `json:"tasks"`
+		Count int           `json:"count"`
+	}
+
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Tasks, nil
+}
+
+// GetDueCards retrieves up to limit flashcards due for review today
+func (c *APIClient) GetDueCards(limit int) (*model.DueCards, error) {
+	resp, err := c.makeRequest("GET", "/api/v1/review/due?limit="+strconv.Itoa(limit), nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var due model.DueCards
+	if err := decodeResponse(resp, &due); err != nil {
+		return nil, err
+	}
+
+	return &due, nil
+}
+
+// ReviewCard grades a review of a flashcard (0-5) and returns it with its next due date
+func (c *APIClient) ReviewCard(id uuid.UUID, grade int) (*model.Card, error) {
+	req := &model.ReviewCardRequest{Grade: &grade}
+	resp, err := c.makeRequest("POST", "/api/v1/review/due/"+id.String(), req, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var card model.Card
+	if err := decodeResponse(resp, &card); err != nil {
+		return nil, err
+	}
+
+	return &card, nil
+}
+
+// GetNoteRevisions retrieves the revision history of a note, newest first
+func (c *APIClient) GetNoteRevisions(id uuid.UUID) ([]*model.NoteRevision, error) {
+	resp, err := c.makeRequest("GET", "/api/v1/notes/"+id.String()+"/revisions", nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Revisions []*model.NoteRevision `json:"revisions"`
+		Count     int                   `json:"count"`
+	}
+
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Revisions, nil
+}
+
+// RestoreNoteRevision restores a note to a previous revision
+func (c *APIClient) RestoreNoteRevision(id uuid.UUID, revision int) (*model.Note, error) {
+	path := fmt.Sprintf("/api/v1/notes/%s/revisions/%d/restore", id, revision)
+
+	resp, err := c.makeRequest("POST", path, nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var note model.Note
+	if err := decodeResponse(resp, &note); err != nil {
+		return nil, err
+	}
+
+	return &note, nil
+}
+
+// SummarizeNote generates a summary of a note, which the server stores in its metadata
+func (c *APIClient) SummarizeNote(id uuid.UUID) (*model.NoteSummary, error) {
+	path := fmt.Sprintf("/api/v1/notes/%s/summarize", id)
+
+	resp, err := c.makeRequest("POST", path, nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var summary model.NoteSummary
+	if err := decodeResponse(resp, &summary); err != nil {
+		return nil, err
+	}
+
+	return &summary, nil
+}
+
+// GetDailyNote gets or creates a daily note for a given date
+func (c *APIClient) GetDailyNote(date string) (*model.Note, bool, error) {
+	resp, err := c.makeRequest("GET", "/api/v1/notes/daily/"+date, nil, true)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var result struct {
+		Note       *model.Note `json:"note"`
+		IsCreated  bool        `json:"is_created"`
+		Date       string      `json:"date"`
+	}
+
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, false, err
+	}
+
+	return result.Note, result.IsCreated, nil
+}
+
+// ListDailyNotes retrieves the days of a month (YYYY-MM) that have a daily note
+func (c *APIClient) ListDailyNotes(month string) ([]*model.DailyNoteDay, error) {
+	resp, err := c.makeRequest("GET", "/api/v1/notes/daily?month="+url.QueryEscape(month), nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Days []*model.DailyNoteDay `json:"days"`
+	}
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Days, nil
+}
+
+// CreateWeeklyReview writes the review note of the week of date (YYYY-MM-DD, empty for this week)
+// Returns the note, the review it summarizes and whether the note is new.
+func (c *APIClient) CreateWeeklyReview(date string) (*model.Note, *model.WeeklyReview, bool, error) {
+	resp, err := c.makeRequest("POST", "/api/v1/notes/review", &model.WeeklyReviewRequest{Date: date}, true)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	var result struct {
+		Note      *model.Note         `json:"note"`
+		Review    *model.WeeklyReview `json:"review"`
+		IsCreated bool                `json:"is_created"`
+	}
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, nil, false, err
+	}
+
+	return result.Note, result.Review, result.IsCreated, nil
+}
+
+// Capture appends text to the Inbox note or today's daily note, creating the note if needed
+// An empty target uses the capture_target setting; the bool is true when the note was created.
+func (c *APIClient) Capture(text, target string) (*model.Note, bool, error) {
+	resp, err := c.makeRequest("POST", "/api/v1/notes/capture", &model.CaptureRequest{Text: text, Target: target}, true)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var result struct {
+		Note      *model.Note `json:"note"`
+		IsCreated bool        `json:"is_created"`
+	}
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, false, err
+	}
+
+	return result.Note, result.IsCreated, nil
+}
+
+// ClipPage saves the article of a web page as a note, fetched by the server
+func (c *APIClient) ClipPage(pageURL string, tags []string) (*model.Note, error) {
+	resp, err := c.makeRequest("POST", "/api/v1/clip", &model.ClipRequest{URL: pageURL, Tags: tags}, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var note model.Note
+	if err := decodeResponse(resp, &note); err != nil {
+		return nil, err
+	}
+
+	c.cacheNotes(&note)
+	return &note, nil
+}
+
+// GetInbox retrieves how many captured items are waiting in the Inbox note
+func (c *APIClient) GetInbox() (*model.InboxStatus, error) {
+	resp, err := c.makeRequest("GET", "/api/v1/notes/inbox", nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var result model.InboxStatus
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// GetDailyTemplate retrieves the template used for new daily notes
+func (c *APIClient) GetDailyTemplate() (*model.DailyTemplateResponse, error) {
+	resp, err := c.makeRequest("GET", "/api/v1/settings/daily-template", nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var result model.DailyTemplateResponse
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// SetDailyTemplate sets the template used for new daily notes (empty resets to the default)
+func (c *APIClient) SetDailyTemplate(template string) (*model.DailyTemplateResponse, error) {
+	req := &model.UpdateDailyTemplateRequest{Template: template}
+	resp, err := c.makeRequest("PUT", "/api/v1/settings/daily-template", req, true)
 	if err != nil {
 		return nil, err
 	}
 
-	var links []*model.LinkDetail
-	if err := decodeResponse(resp, &links); err != nil {
+	var result model.DailyTemplateResponse
+	if err := decodeResponse(resp, &result); err != nil {
 		return nil, err
 	}
 
-	return links, nil
+	return &result, nil
 }
 
-// GetBacklinks retrieves backlinks to a note
-func (c *APIClient) GetBacklinks(id uuid.UUID) ([]*model.LinkDetail, error) {
-	resp, err := c.makeRequest("GET", "/api/v1/notes/"+id.String()+"/backlinks", nil, true)
+// GetSettings retrieves the account preferences
+func (c *APIClient) GetSettings() (*model.UserSettings, error) {
+	resp, err := c.makeRequest("GET", "/api/v1/settings", nil, true)
 	if err != nil {
 		return nil, err
 	}
 
-	var links []*model.LinkDetail
-	if err := decodeResponse(resp, &links); err != nil {
+	var settings model.UserSettings
+	if err := decodeResponse(resp, &settings); err != nil {
 		return nil, err
 	}
 
-	return links, nil
+	c.settings = &settings
+	return &settings, nil
 }
 
-// GetDailyNote gets or creates a daily note for a given date
-func (c *APIClient) GetDailyNote(date string) (*model.Note, bool, error) {
-	resp, err := c.makeRequest("GET", "/api/v1/notes/daily/"+date, nil, true)
+// UpdateSettings changes the account preferences set in the request
+func (c *APIClient) UpdateSettings(req *model.UpdateSettingsRequest) (*model.UserSettings, error) {
+	resp, err := c.makeRequest("PUT", "/api/v1/settings", req, true)
 	if err != nil {
-		return nil, false, err
+		return nil, err
 	}
 
-	var result struct {
-		Note       *model.Note `json:"note"`
-		IsCreated  bool        `json:"is_created"`
-		Date       string      `json:"date"`
+	var settings model.UserSettings
+	if err := decodeResponse(resp, &settings); err != nil {
+		return nil, err
+	}
+
+	c.settings = &settings
+	return &settings, nil
+}
+
+// Settings returns the account preferences, fetching them on first use
+// Falls back to the defaults when they can't be fetched (offline or not logged in).
+func (c *APIClient) Settings() *model.UserSettings {
+	if c.settings != nil {
+		return c.settings
+	}
+
+	if c.token != "" {
+		if settings, err := c.GetSettings(); err == nil {
+			return settings
+		}
+	}
+
+	c.settings = model.DefaultUserSettings(uuid.Nil)
+	return c.settings
+}
+
+// ListNoteTypes retrieves the built-in and custom note types
+func (c *APIClient) ListNoteTypes() ([]*model.NoteTypeDefinition, error) {
+	resp, err := c.makeRequest("GET", "/api/v1/note-types", nil, true)
+	if err != nil {
+		return nil, err
 	}
 
+	var result struct {
+		NoteTypes []*model.NoteTypeDefinition `json:"note_types"`
+	}
 	if err := decodeResponse(resp, &result); err != nil {
-		return nil, false, err
+		return nil, err
 	}
 
-	return result.Note, result.IsCreated, nil
+	c.noteTypes = result.NoteTypes
+	return result.NoteTypes, nil
+}
+
+// NoteTypes returns the note types, fetching them on first use
+// Falls back to the built-in types when they can't be fetched (offline or not logged in).
+func (c *APIClient) NoteTypes() []*model.NoteTypeDefinition {
+	if c.noteTypes != nil {
+		return c.noteTypes
+	}
+
+	if c.token != "" {
+		if noteTypes, err := c.ListNoteTypes(); err == nil {
+			return noteTypes
+		}
+	}
+
+	noteTypes := make([]*model.NoteTypeDefinition, len(model.BuiltInNoteTypes))
+	for i := range model.BuiltInNoteTypes {
+		noteType := model.BuiltInNoteTypes[i]
+		noteTypes[i] = &noteType
+	}
+	return noteTypes
+}
+
+// CreateNoteType defines a custom note type
+func (c *APIClient) CreateNoteType(req *model.CreateNoteTypeRequest) (*model.NoteTypeDefinition, error) {
+	resp, err := c.makeRequest("POST", "/api/v1/note-types", req, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var noteType model.NoteTypeDefinition
+	if err := decodeResponse(resp, &noteType); err != nil {
+		return nil, err
+	}
+
+	c.noteTypes = nil
+	return &noteType, nil
+}
+
+// UpdateNoteType changes the icon or color of a custom note type
+func (c *APIClient) UpdateNoteType(name string, req *model.UpdateNoteTypeRequest) (*model.NoteTypeDefinition, error) {
+	resp, err := c.makeRequest("PUT", "/api/v1/note-types/"+url.PathEscape(name), req, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var noteType model.NoteTypeDefinition
+	if err := decodeResponse(resp, &noteType); err != nil {
+		return nil, err
+	}
+
+	c.noteTypes = nil
+	return &noteType, nil
+}
+
+// SetNoteTypeFields replaces the typed custom fields of a note type
+func (c *APIClient) SetNoteTypeFields(name string, req *model.SetNoteTypeFieldsRequest) (*model.NoteTypeDefinition, error) {
+	resp, err := c.makeRequest("PUT", "/api/v1/note-types/"+url.PathEscape(name)+"/fields", req, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var noteType model.NoteTypeDefinition
+	if err := decodeResponse(resp, &noteType); err != nil {
+		return nil, err
+	}
+
+	c.noteTypes = nil
+	return &noteType, nil
+}
+
+// DeleteNoteType deletes a custom note type
+func (c *APIClient) DeleteNoteType(name string) error {
+	resp, err := c.makeRequest("DELETE", "/api/v1/note-types/"+url.PathEscape(name), nil, true)
+	if err != nil {
+		return err
+	}
+
+	c.noteTypes = nil
+	return decodeResponse(resp, nil)
 }
 
 // GetStats retrieves user statistics
@@ -558,6 +1847,56 @@ func (c *APIClient) GetStats() (*model.UserStats, error) {
 	return &stats, nil
 }
 
+// GetActivityHeatmap retrieves the notes created or updated per day over the last weeks weeks
+func (c *APIClient) GetActivityHeatmap(weeks int) (*model.ActivityHeatmap, error) {
+	path := fmt.Sprintf("/api/v1/activity/heatmap?weeks=%d", weeks)
+	resp, err := c.makeRequest("GET", path, nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var heatmap model.ActivityHeatmap
+	if err := decodeResponse(resp, &heatmap); err != nil {
+		return nil, err
+	}
+
+	return &heatmap, nil
+}
+
+// GetWritingStreak retrieves the writing streak and today's progress toward the daily word goal
+func (c *APIClient) GetWritingStreak() (*model.WritingStreak, error) {
+	resp, err := c.makeRequest("GET", "/api/v1/stats/streak", nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var streak model.WritingStreak
+	if err := decodeResponse(resp, &streak); err != nil {
+		return nil, err
+	}
+
+	return &streak, nil
+}
+
+// GetDailyWords retrieves the words written on each of the last days days, oldest first
+func (c *APIClient) GetDailyWords(days int) ([]*model.DailyWords, int, error) {
+	path := fmt.Sprintf("/api/v1/stats/daily-words?days=%d", days)
+	resp, err := c.makeRequest("GET", path, nil, true)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var result struct {
+		Days      []*model.DailyWords `json:"days"`
+		DailyGoal int                 `json:"daily_goal"`
+	}
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, 0, err
+	}
+
+	return result.Days, result.DailyGoal, nil
+}
+
 // GetRecentActivity retrieves recent activity
 func (c *APIClient) GetRecentActivity(limit int) ([]*model.Activity, error) {
 	path := fmt.Sprintf("/api/v1/activity/recent?limit=%d", limit)
@@ -578,6 +1917,41 @@ func (c *APIClient) GetRecentActivity(limit int) ([]*model.Activity, error) {
 	return result.Activities, nil
 }
 
+// ListActivity retrieves a page of activities matching the filter, with the total count
+func (c *APIClient) ListActivity(filter model.ActivityFilter) ([]*model.Activity, int64, error) {
+	params := url.Values{}
+	params.Set("page", fmt.Sprint(filter.Page))
+	params.Set("limit", fmt.Sprint(filter.Limit))
+	if filter.Action != nil {
+		params.Set("action", string(*filter.Action))
+	}
+	if filter.NoteID != nil {
+		params.Set("note_id", filter.NoteID.String())
+	}
+	if filter.From != nil {
+		params.Set("from", filter.From.Format(time.RFC3339))
+	}
+	if filter.To != nil {
+		params.Set("to", filter.To.Format(time.RFC3339))
+	}
+
+	resp, err := c.makeRequest("GET", "/api/v1/activity?"+params.Encode(), nil, true)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var result struct {
+		Activities []*model.Activity `json:"activities"`
+		Pagination model.Pagination  `json:"pagination"`
+	}
+
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, 0, err
+	}
+
+	return result.Activities, result.Pagination.Total, nil
+}
+
 // GetTrendingNotes retrieves trending notes
 func (c *APIClient) GetTrendingNotes(limit int) ([]*model.TrendingNote, error) {
 	path := fmt.Sprintf("/api/v1/notes/trending?limit=%d", limit)
@@ -599,8 +1973,12 @@ func (c *APIClient) GetTrendingNotes(limit int) ([]*model.TrendingNote, error) {
 }
 
 // GetForgottenNotes retrieves forgotten notes
+// days 0 uses the account's forgotten_days setting.
 func (c *APIClient) GetForgottenNotes(days, limit int) ([]*model.ForgottenNote, error) {
-	path := fmt.Sprintf("/api/v1/notes/forgotten?days=%d&limit=%d", days, limit)
+	path := fmt.Sprintf("/api/v1/notes/forgotten?limit=%d", limit)
+	if days > 0 {
+		path += fmt.Sprintf("&days=%d", days)
+	}
 
 	resp, err := c.makeRequest("GET", path, nil, true)
 	if err != nil {
@@ -617,3 +1995,185 @@ func (c *APIClient) GetForgottenNotes(days, limit int) ([]*model.ForgottenNote,
 
 	return result.Forgotten, nil
 }
+
+// GetReadingNotes retrieves the notes started but not read to the end, most recently read first
+func (c *APIClient) GetReadingNotes(limit int) ([]*model.Note, error) {
+	resp, err := c.makeRequest("GET", fmt.Sprintf("/api/v1/notes/reading?limit=%d", limit), nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Notes []*model.Note `json:"notes"`
+	}
+
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Notes, nil
+}
+
+// SetReadProgress saves how far down a note has been read, in percent
+func (c *APIClient) SetReadProgress(id uuid.UUID, percent int) (*model.ReadProgress, error) {
+	req := &model.SetReadProgressRequest{Percent: &percent}
+	resp, err := c.makeRequest("PUT", "/api/v1/notes/"+id.String()+"/progress", req, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var progress model.ReadProgress
+	if err := decodeResponse(resp, &progress); err != nil {
+		return nil, err
+	}
+
+	return &progress, nil
+}
+
+// ListAuditLog retrieves a page of the account's security events matching the filter, with the total count
+func (c *APIClient) ListAuditLog(filter model.AuditFilter) ([]*model.AuditEntry, int64, error) {
+	return c.listAuditLog("/api/v1/audit", filter)
+}
+
+// AdminListAuditLog retrieves a page of the security events of every user, or of filter.UserID
+func (c *APIClient) AdminListAuditLog(filter model.AuditFilter) ([]*model.AuditEntry, int64, error) {
+	return c.listAuditLog("/api/v1/admin/audit", filter)
+}
+
+// listAuditLog retrieves a page of audit log entries from path
+func (c *APIClient) listAuditLog(path string, filter model.AuditFilter) ([]*model.AuditEntry, int64, error) {
+	params := url.Values{}
+	params.Set("page", fmt.Sprint(filter.Page))
+	params.Set("limit", fmt.Sprint(filter.Limit))
+	if filter.UserID != nil {
+		params.Set("user_id", filter.UserID.String())
+	}
+	if filter.Event != nil {
+		params.Set("event", string(*filter.Event))
+	}
+	if filter.From != nil {
+		params.Set("from", filter.From.Format(time.RFC3339))
+	}
+	if filter.To != nil {
+		params.Set("to", filter.To.Format(time.RFC3339))
+	}
+
+	resp, err := c.makeRequest("GET", path+"?"+params.Encode(), nil, true)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var result struct {
+		Entries    []*model.AuditEntry `json:"entries"`
+		Pagination model.Pagination    `json:"pagination"`
+	}
+
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, 0, err
+	}
+
+	return result.Entries, result.Pagination.Total, nil
+}
+
+// AdminListUsers retrieves a page of every user on the instance, with the total count
+func (c *APIClient) AdminListUsers(page, limit int) ([]*model.AdminUser, int64, error) {
+	path := fmt.Sprintf("/api/v1/admin/users?page=%d&limit=%d", page, limit)
+
+	resp, err := c.makeRequest("GET", path, nil, true)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var result struct {
+		Users      []*model.AdminUser `json:"users"`
+		Pagination model.Pagination   `json:"pagination"`
+	}
+
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, 0, err
+	}
+
+	return result.Users, result.Pagination.Total, nil
+}
+
+// AdminDeactivateUser blocks a user from signing in
+func (c *APIClient) AdminDeactivateUser(id uuid.UUID) error {
+	resp, err := c.makeRequest("POST", "/api/v1/admin/users/"+id.String()+"/deactivate", nil, true)
+	if err != nil {
+		return err
+	}
+
+	return decodeResponse(resp, nil)
+}
+
+// AdminActivateUser lets a deactivated user sign in again
+func (c *APIClient) AdminActivateUser(id uuid.UUID) error {
+	resp, err := c.makeRequest("POST", "/api/v1/admin/users/"+id.String()+"/activate", nil, true)
+	if err != nil {
+		return err
+	}
+
+	return decodeResponse(resp, nil)
+}
+
+// AdminGetStats retrieves statistics across all users
+func (c *APIClient) AdminGetStats() (*model.AdminStats, error) {
+	resp, err := c.makeRequest("GET", "/api/v1/admin/stats", nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var stats model.AdminStats
+	if err := decodeResponse(resp, &stats); err != nil {
+		return nil, err
+	}
+
+	return &stats, nil
+}
+
+// AdminListBackups lists the server's backups, newest first
+func (c *APIClient) AdminListBackups() ([]*model.Backup, error) {
+	resp, err := c.makeRequest("GET", "/api/v1/admin/backups", nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Backups []*model.Backup `json:"backups"`
+	}
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Backups, nil
+}
+
+// AdminCreateBackup backs up all user data on the server now
+func (c *APIClient) AdminCreateBackup() (*model.BackupResult, error) {
+	resp, err := c.makeRequest("POST", "/api/v1/admin/backups", nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var result model.BackupResult
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// AdminRestoreBackup adds the rows of a backup that are missing from the server's database
+func (c *APIClient) AdminRestoreBackup(name string) (*model.RestoreResult, error) {
+	resp, err := c.makeRequest("POST", "/api/v1/admin/backups/"+url.PathEscape(name)+"/restore", nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var result model.RestoreResult
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
@@ -23,20 +23,172 @@ type AuthState struct {
 
 const authFileName = "auth.json"
 
-// getAuthFilePath returns the path to the auth file
-func getAuthFilePath() (string, error) {
+// DefaultProfile is the profile used without --profile, its files are kept
+// directly in the config directory
+const DefaultProfile = "default"
+
+// ProfileDir returns the directory holding the tokens and offline cache of a profile
+// The default profile uses ~/.config/kg-cli, other profiles ~/.config/kg-cli/profiles/<name>.
+func ProfileDir(profile string) (string, error) {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		return "", fmt.Errorf("get home dir: %w", err)
 	}
 
 	configDir := filepath.Join(homeDir, ".config", "kg-cli")
-	return filepath.Join(configDir, authFileName), nil
+	if profile == "" || profile == DefaultProfile {
+		return configDir, nil
+	}
+	return filepath.Join(configDir, "profiles", profile), nil
+}
+
+// getAuthFilePath returns the path to the auth file of a profile
+func getAuthFilePath(profile string) (string, error) {
+	profileDir, err := ProfileDir(profile)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(profileDir, authFileName), nil
+}
+
+// TokenStorage is where the authentication state is kept
+type TokenStorage string
+
+const (
+	// StorageAuto uses the OS keychain when one is available and the auth file otherwise
+	StorageAuto TokenStorage = "auto"
+	// StorageKeychain only uses the OS keychain (macOS Keychain, Windows Credential
+	// Manager or the Secret Service on Linux)
+	StorageKeychain TokenStorage = "keychain"
+	// StorageFile only uses the auth file, readable by the user only
+	StorageFile TokenStorage = "file"
+)
+
+// tokenStorage is the storage chosen in the configuration
+var tokenStorage = StorageAuto
+
+// SetTokenStorage chooses where LoadAuthState and SaveAuthState keep tokens
+func SetTokenStorage(storage TokenStorage) error {
+	switch storage {
+	case "":
+		storage = StorageAuto
+	case StorageAuto, StorageKeychain, StorageFile:
+	default:
+		return fmt.Errorf("invalid token storage %q (use auto, keychain or file)", storage)
+	}
+
+	tokenStorage = storage
+	return nil
+}
+
+// LoadAuthState loads the authentication state of a profile
+// With auto storage, a state still in the auth file is used until it is moved to
+// the keychain by the next login or 'kg-cli auth migrate-storage'.
+func LoadAuthState(profile string) (*AuthState, error) {
+	if tokenStorage != StorageFile {
+		state, err := loadKeychainState(profile)
+		if err == nil {
+			return state, nil
+		}
+		if tokenStorage == StorageKeychain {
+			if errors.Is(err, errKeychainEmpty) {
+				return &AuthState{}, nil
+			}
+			return nil, err
+		}
+	}
+
+	return loadAuthFile(profile)
+}
+
+// SaveAuthState saves the authentication state of a profile
+// With auto storage the auth file is only written when the keychain can't be used,
+// and removed once the state is in the keychain.
+func SaveAuthState(profile string, state *AuthState) error {
+	if tokenStorage != StorageFile {
+		err := saveKeychainState(profile, state)
+		if err == nil {
+			return removeAuthFile(profile)
+		}
+		if tokenStorage == StorageKeychain {
+			return err
+		}
+	}
+
+	return saveAuthFile(profile, state)
+}
+
+// ClearAuthState removes the authentication state of a profile from the keychain and the auth file
+func ClearAuthState(profile string) error {
+	if tokenStorage != StorageFile {
+		if err := deleteKeychainState(profile); err != nil && tokenStorage == StorageKeychain {
+			return err
+		}
+	}
+
+	return removeAuthFile(profile)
+}
+
+// AuthStateLocation describes where the authentication state of a profile is kept
+func AuthStateLocation(profile string) string {
+	if tokenStorage != StorageFile {
+		if _, err := loadKeychainState(profile); err == nil || tokenStorage == StorageKeychain {
+			return "OS keychain"
+		}
+	}
+
+	authPath, err := getAuthFilePath(profile)
+	if err != nil {
+		return "auth file"
+	}
+	return authPath
+}
+
+// MigrateAuthState moves the authentication state of a profile to the keychain, or
+// back to the auth file, and removes it from where it was
+// It returns false when there was nothing to move.
+func MigrateAuthState(profile string, to TokenStorage) (bool, error) {
+	switch to {
+	case StorageKeychain:
+		state, err := loadAuthFile(profile)
+		if err != nil {
+			return false, err
+		}
+		if !state.IsAuthenticated() {
+			// Still make sure later logins can use the keychain
+			if _, err := loadKeychainState(profile); err != nil && !errors.Is(err, errKeychainEmpty) {
+				return false, err
+			}
+			return false, nil
+		}
+
+		if err := saveKeychainState(profile, state); err != nil {
+			return false, err
+		}
+		return true, removeAuthFile(profile)
+
+	case StorageFile:
+		// A keychain that can't be reached holds nothing to move, and switching to the
+		// file is how to stop using it
+		state, err := loadKeychainState(profile)
+		if err != nil {
+			return false, nil
+		}
+
+		if err := saveAuthFile(profile, state); err != nil {
+			return false, err
+		}
+		return true, deleteKeychainState(profile)
+
+	default:
+		return false, fmt.Errorf("tokens can be moved to keychain or file, not %q", to)
+	}
 }
 
-// LoadAuthState loads the authentication state from disk
-func LoadAuthState() (*AuthState, error) {
-	authPath, err := getAuthFilePath()
+// loadAuthFile loads the authentication state of a profile from the auth file
+func loadAuthFile(profile string) (*AuthState, error) {
+	authPath, err := getAuthFilePath(profile)
 	if err != nil {
 		return nil, err
 	}
@@ -58,9 +210,9 @@ func LoadAuthState() (*AuthState, error) {
 	return &state, nil
 }
 
-// SaveAuthState saves the authentication state to disk
-func SaveAuthState(state *AuthState) error {
-	authPath, err := getAuthFilePath()
+// saveAuthFile saves the authentication state of a profile to the auth file
+func saveAuthFile(profile string, state *AuthState) error {
+	authPath, err := getAuthFilePath(profile)
 	if err != nil {
 		return err
 	}
@@ -84,9 +236,9 @@ func SaveAuthState(state *AuthState) error {
 	return nil
 }
 
-// ClearAuthState removes the authentication state from disk
-func ClearAuthState() error {
-	authPath, err := getAuthFilePath()
+// removeAuthFile removes the auth file of a profile
+func removeAuthFile(profile string) error {
+	authPath, err := getAuthFilePath(profile)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,386 @@
+package client
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/momokii/go-cli-notes/internal/model"
+
+	_ "modernc.org/sqlite"
+)
+
+const cacheFileName = "cache.db"
+
+// Pending operation types queued while offline
+const (
+	OpCreateNote = "create_note"
+	OpUpdateNote = "update_note"
+)
+
+// ErrNotCached is returned when a resource is not available in the local cache
+var ErrNotCached = errors.New("not available offline")
+
+// cacheSchema creates the local cache tables
+const cacheSchema = `
+CREATE TABLE IF NOT EXISTS notes (
+	id TEXT PRIMARY KEY,
+	title TEXT NOT NULL,
+	content TEXT NOT NULL DEFAULT '',
+	note_type TEXT NOT NULL DEFAULT 'note',
+	created_at TIMESTAMP NOT NULL,
+	data TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_notes_created_at ON notes(created_at DESC);
+
+CREATE TABLE IF NOT EXISTS tags (
+	id TEXT PRIMARY KEY,
+	name TEXT NOT NULL,
+	data TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS pending_ops (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	op TEXT NOT NULL,
+	note_id TEXT NOT NULL,
+	payload TEXT NOT NULL,
+	created_at TIMESTAMP NOT NULL
+);
+`
+
+// Cache is a local SQLite store of notes and tags used when the API is unreachable
+type Cache struct {
+	db *sql.DB
+}
+
+// PendingOp is a write operation queued while offline
+type PendingOp struct {
+	ID        int64
+	Op        string
+	NoteID    uuid.UUID
+	Payload   []byte
+	CreatedAt time.Time
+}
+
+// getCacheFilePath returns the path to the cache database of a profile
+func getCacheFilePath(profile string) (string, error) {
+	profileDir, err := ProfileDir(profile)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(profileDir, cacheFileName), nil
+}
+
+// OpenCache opens (or creates) the local cache database of a profile
+// Each profile has its own cache, so notes of different servers never mix.
+func OpenCache(profile string) (*Cache, error) {
+	cachePath, err := getCacheFilePath(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create config directory if it doesn't exist
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0700); err != nil {
+		return nil, fmt.Errorf("create config dir: %w", err)
+	}
+
+	db, err := sql.Open("sqlite", cachePath)
+	if err != nil {
+		return nil, fmt.Errorf("open cache: %w", err)
+	}
+
+	if _, err := db.Exec(cacheSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("init cache schema: %w", err)
+	}
+
+	// Cache contains note content - keep it private like auth.json
+	_ = os.Chmod(cachePath, 0600)
+
+	return &Cache{db: db}, nil
+}
+
+// Close closes the cache database
+func (c *Cache) Close() error {
+	return c.db.Close()
+}
+
+// Clear removes all cached data and pending operations
+func (c *Cache) Clear() error {
+	for _, table := range []string{"notes", "tags", "pending_ops"} {
+		if _, err := c.db.Exec("DELETE FROM " + table); err != nil {
+			return fmt.Errorf("clear %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// PutNotes stores notes in the cache, replacing existing copies
+func (c *Cache) PutNotes(notes []*model.Note) error {
+	tx, err := c.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin cache tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, note := range notes {
+		data, err := json.Marshal(note)
+		if err != nil {
+			return fmt.Errorf("marshal note: %w", err)
+		}
+
+		_, err = tx.Exec(`
+			INSERT INTO notes (id, title, content, note_type, created_at, data)
+			VALUES (?, ?, ?, ?, ?, ?)
+			ON CONFLICT(id) DO UPDATE SET
+				title = excluded.title,
+				content = excluded.content,
+				note_type = excluded.note_type,
+				created_at = excluded.created_at,
+				data = excluded.data
+		`, note.ID.String(), note.Title, note.Content, string(note.NoteType), note.CreatedAt, string(data))
+		if err != nil {
+			return fmt.Errorf("cache note: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetNote gets a cached note by ID
+func (c *Cache) GetNote(id uuid.UUID) (*model.Note, error) {
+	var data string
+	err := c.db.QueryRow("SELECT data FROM notes WHERE id = ?", id.String()).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotCached
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get cached note: %w", err)
+	}
+
+	var note model.Note
+	if err := json.Unmarshal([]byte(data), &note); err != nil {
+		return nil, fmt.Errorf("unmarshal cached note: %w", err)
+	}
+
+	return &note, nil
+}
+
+// DeleteNote removes a note from the cache
+func (c *Cache) DeleteNote(id uuid.UUID) error {
+	if _, err := c.db.Exec("DELETE FROM notes WHERE id = ?", id.String()); err != nil {
+		return fmt.Errorf("delete cached note: %w", err)
+	}
+	return nil
+}
+
+// ListNotes lists cached notes using the same filter as the API
+// Tag filtering is not supported offline because note-tag associations are not cached.
+func (c *Cache) ListNotes(filter model.NoteFilter) ([]*model.Note, int64, error) {
+	if filter.TagID != nil && *filter.TagID != "" {
+		return nil, 0, fmt.Errorf("tag filter: %w", ErrNotCached)
+	}
+
+	where := " WHERE 1 = 1"
+	args := []any{}
+
+	if filter.NoteType != nil {
+		where += " AND note_type = ?"
+		args = append(args, string(*filter.NoteType))
+	}
+	if filter.Status != nil {
+		if *filter.Status == model.NoteStatusAny {
+			where += " AND json_extract(data, '$.metadata.status') IS NOT NULL"
+		} else {
+			where += " AND json_extract(data, '$.metadata.status') = ?"
+			args = append(args, string(*filter.Status))
+		}
+	}
+	if filter.Search != "" {
+		where += " AND (title LIKE ? OR content LIKE ?)"
+		pattern := "%" + filter.Search + "%"
+		args = append(args, pattern, pattern)
+	}
+
+	var total int64
+	if err := c.db.QueryRow("SELECT COUNT(*) FROM notes"+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count cached notes: %w", err)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	page := filter.Page
+	if page < 1 {
+		page = 1
+	}
+
+	order := "DESC"
+	if strings.EqualFold(filter.SortOrder, "asc") {
+		order = "ASC"
+	}
+
+	query := "SELECT data FROM notes" + where + " ORDER BY created_at " + order + " LIMIT ? OFFSET ?"
+	args = append(args, limit, (page-1)*limit)
+
+	rows, err := c.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list cached notes: %w", err)
+	}
+	defer rows.Close()
+
+	notes := []*model.Note{}
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, 0, fmt.Errorf("scan cached note: %w", err)
+		}
+		var note model.Note
+		if err := json.Unmarshal([]byte(data), &note); err != nil {
+			return nil, 0, fmt.Errorf("unmarshal cached note: %w", err)
+		}
+		notes = append(notes, &note)
+	}
+
+	return notes, total, rows.Err()
+}
+
+// PutTags replaces the cached tag list
+func (c *Cache) PutTags(tags []*model.Tag) error {
+	tx, err := c.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin cache tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM tags"); err != nil {
+		return fmt.Errorf("clear cached tags: %w", err)
+	}
+
+	for _, tag := range tags {
+		data, err := json.Marshal(tag)
+		if err != nil {
+			return fmt.Errorf("marshal tag: %w", err)
+		}
+		if _, err := tx.Exec("INSERT INTO tags (id, name, data) VALUES (?, ?, ?)", tag.ID.String(), tag.Name, string(data)); err != nil {
+			return fmt.Errorf("cache tag: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetTags returns all cached tags
+func (c *Cache) GetTags() ([]*model.Tag, error) {
+	rows, err := c.db.Query("SELECT data FROM tags ORDER BY name ASC")
+	if err != nil {
+		return nil, fmt.Errorf("list cached tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := []*model.Tag{}
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("scan cached tag: %w", err)
+		}
+		var tag model.Tag
+		if err := json.Unmarshal([]byte(data), &tag); err != nil {
+			return nil, fmt.Errorf("unmarshal cached tag: %w", err)
+		}
+		tags = append(tags, &tag)
+	}
+
+	return tags, rows.Err()
+}
+
+// QueueOp queues a write operation to be replayed by Sync
+func (c *Cache) QueueOp(op string, noteID uuid.UUID, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+
+	_, err = c.db.Exec(
+		"INSERT INTO pending_ops (op, note_id, payload, created_at) VALUES (?, ?, ?, ?)",
+		op, noteID.String(), string(data), time.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("queue operation: %w", err)
+	}
+
+	return nil
+}
+
+// PendingOps returns queued operations in the order they were made
+func (c *Cache) PendingOps() ([]PendingOp, error) {
+	rows, err := c.db.Query("SELECT id, op, note_id, payload, created_at FROM pending_ops ORDER BY id ASC")
+	if err != nil {
+		return nil, fmt.Errorf("list pending operations: %w", err)
+	}
+	defer rows.Close()
+
+	ops := []PendingOp{}
+	for rows.Next() {
+		var op PendingOp
+		var noteID, payload string
+		if err := rows.Scan(&op.ID, &op.Op, &noteID, &payload, &op.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan pending operation: %w", err)
+		}
+		op.NoteID, _ = uuid.Parse(noteID)
+		op.Payload = []byte(payload)
+		ops = append(ops, op)
+	}
+
+	return ops, rows.Err()
+}
+
+// PendingCount returns the number of queued operations
+func (c *Cache) PendingCount() (int, error) {
+	var count int
+	if err := c.db.QueryRow("SELECT COUNT(*) FROM pending_ops").Scan(&count); err != nil {
+		return 0, fmt.Errorf("count pending operations: %w", err)
+	}
+	return count, nil
+}
+
+// RemoveOp removes a replayed operation from the queue
+func (c *Cache) RemoveOp(id int64) error {
+	if _, err := c.db.Exec("DELETE FROM pending_ops WHERE id = ?", id); err != nil {
+		return fmt.Errorf("remove pending operation: %w", err)
+	}
+	return nil
+}
+
+// RebaseNote records the version of a note on the server once a queued change to it is synced:
+// queued updates of the note are based on it from then on, and so is the cached copy
+func (c *Cache) RebaseNote(id uuid.UUID, updatedAt time.Time) error {
+	version := updatedAt.Format(time.RFC3339Nano)
+	if _, err := c.db.Exec(
+		"UPDATE pending_ops SET payload = json_set(payload, '$.base_updated_at', ?) WHERE note_id = ? AND op = ?",
+		version, id.String(), OpUpdateNote,
+	); err != nil {
+		return fmt.Errorf("update pending operations: %w", err)
+	}
+	if _, err := c.db.Exec("UPDATE notes SET data = json_set(data, '$.updated_at', ?) WHERE id = ?", version, id.String()); err != nil {
+		return fmt.Errorf("update cached note: %w", err)
+	}
+	return nil
+}
+
+// ReplaceNoteID rewrites queued operations that refer to a locally generated note ID
+// once the server has assigned the real one
+func (c *Cache) ReplaceNoteID(oldID, newID uuid.UUID) error {
+	if _, err := c.db.Exec("UPDATE pending_ops SET note_id = ? WHERE note_id = ?", newID.String(), oldID.String()); err != nil {
+		return fmt.Errorf("update pending operations: %w", err)
+	}
+	return c.DeleteNote(oldID)
+}
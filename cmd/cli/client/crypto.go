@@ -0,0 +1,48 @@
+package client
+
+import (
+	"errors"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+	"github.com/momokii/go-cli-notes/internal/util"
+)
+
+// ErrNoPassphrase is returned when an encrypted note is used without a passphrase
+var ErrNoPassphrase = errors.New("note is encrypted: set KG_CLI_PASSPHRASE or enter the passphrase when prompted")
+
+// SetPassphrase sets the passphrase used to encrypt and decrypt note content
+// The passphrase never leaves the client.
+func (c *APIClient) SetPassphrase(passphrase string) {
+	c.passphrase = passphrase
+}
+
+// HasPassphrase returns true if a passphrase has been set
+func (c *APIClient) HasPassphrase() bool {
+	return c.passphrase != ""
+}
+
+// EncryptContent encrypts note content with the client passphrase
+func (c *APIClient) EncryptContent(content string) (string, error) {
+	if c.passphrase == "" {
+		return "", ErrNoPassphrase
+	}
+	return util.EncryptContent(content, c.passphrase)
+}
+
+// DecryptNote replaces the content of an encrypted note with its plaintext
+// Notes that aren't encrypted are left untouched.
+func (c *APIClient) DecryptNote(note *model.Note) error {
+	if !note.Encrypted || !util.IsEncryptedContent(note.Content) {
+		return nil
+	}
+	if c.passphrase == "" {
+		return ErrNoPassphrase
+	}
+
+	content, err := util.DecryptContent(note.Content, c.passphrase)
+	if err != nil {
+		return err
+	}
+	note.Content = content
+	return nil
+}
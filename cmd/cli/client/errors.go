@@ -0,0 +1,156 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+// Errors an *APIError unwraps to, so callers can branch with errors.Is
+var (
+	ErrUnauthorized     = errors.New("not authenticated")
+	ErrForbidden        = errors.New("forbidden")
+	ErrNotFound         = errors.New("resource not found")
+	ErrConflict         = errors.New("resource already exists")
+	ErrValidation       = errors.New("validation failed")
+	ErrRateLimited      = errors.New("too many requests")
+	ErrEmailNotVerified = errors.New("email not verified")
+	ErrNoteChanged      = errors.New("note changed on the server")
+)
+
+// ErrTOTPRequired is returned by Login when the account has two-factor authentication enabled
+// and no code was given; retry with the code from the user's authenticator app.
+var ErrTOTPRequired = errors.New("two-factor code required")
+
+// Errors of PollDeviceLogin: keep polling on ErrAuthorizationPending, and wait 5 more
+// seconds between polls after ErrSlowDown; the other two end the login.
+var (
+	ErrAuthorizationPending = errors.New("waiting for the login to be approved")
+	ErrSlowDown             = errors.New("polling too fast")
+	ErrDeviceCodeExpired    = errors.New("device code expired")
+	ErrAccessDenied         = errors.New("login denied")
+)
+
+// APIError is an error response from the API
+// Use errors.Is with the Err* values above to branch on the kind of error,
+// or errors.As to read the invalid Fields of a validation error.
+type APIError struct {
+	StatusCode int
+	Code       string            // Machine-readable code, e.g. "VALIDATION_FAILED"; empty for older servers
+	Message    string            // The server's message
+	Fields     map[string]string // JSON field name -> what is wrong with it, for validation errors
+}
+
+// apiErrorResponse represents an error response from the API
+type apiErrorResponse struct {
+	Error  string            `json:"error"`
+	Code   string            `json:"code"`
+	Fields map[string]string `json:"fields"`
+}
+
+// Error returns a user-friendly message
+func (e *APIError) Error() string {
+	switch {
+	case errors.Is(e, ErrTOTPRequired):
+		return ErrTOTPRequired.Error()
+	case errors.Is(e, ErrEmailNotVerified):
+		return "email not verified. Check your inbox and run 'kg-cli verify-email'"
+	case e.StatusCode == http.StatusUnauthorized && isCredentialsMessage(e.Message):
+		return "invalid email or password"
+	case e.StatusCode == http.StatusUnauthorized:
+		return "not authenticated. Please run 'kg-cli login'"
+	}
+
+	// Clean up the server's message
+	msg := strings.TrimPrefix(e.Message, "Internal server error: ")
+	msg = strings.TrimPrefix(msg, "validation failed: ")
+	msg = strings.TrimSuffix(msg, "; ")
+	if msg == "" {
+		return fmt.Sprintf("API error (status %d)", e.StatusCode)
+	}
+
+	// Capitalize first letter
+	return strings.ToUpper(msg[:1]) + msg[1:]
+}
+
+// Unwrap returns the Err* value matching the error code, or the status code for older servers
+func (e *APIError) Unwrap() error {
+	switch e.Code {
+	case model.CodeTOTPRequired:
+		return ErrTOTPRequired
+	case model.CodeEmailNotVerified:
+		return ErrEmailNotVerified
+	case model.CodeNoteChanged:
+		return ErrNoteChanged
+	case model.CodeValidation:
+		return ErrValidation
+	case model.CodeUnauthorized:
+		return ErrUnauthorized
+	case model.CodeForbidden:
+		return ErrForbidden
+	case model.CodeNotFound:
+		return ErrNotFound
+	case model.CodeConflict, model.ErrAPIEmailExists.Code, model.ErrAPIUsernameExists.Code:
+		return ErrConflict
+	case model.CodeRateLimited:
+		return ErrRateLimited
+	case model.CodeAuthorizationPending:
+		return ErrAuthorizationPending
+	case model.CodeSlowDown:
+		return ErrSlowDown
+	case model.CodeExpiredToken:
+		return ErrDeviceCodeExpired
+	case model.CodeAccessDenied:
+		return ErrAccessDenied
+	}
+
+	// Servers without error codes only tell these apart by their message
+	switch e.Message {
+	case "Two-factor code required":
+		return ErrTOTPRequired
+	case "Email not verified":
+		return ErrEmailNotVerified
+	}
+
+	switch e.StatusCode {
+	case http.StatusBadRequest:
+		return ErrValidation
+	case http.StatusUnauthorized:
+		return ErrUnauthorized
+	case http.StatusForbidden:
+		return ErrForbidden
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusConflict:
+		return ErrConflict
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	}
+	return nil
+}
+
+// isCredentialsMessage reports whether a 401 message is about a wrong email or password
+func isCredentialsMessage(msg string) bool {
+	msg = strings.ToLower(msg)
+	return strings.Contains(msg, "invalid email or password") || strings.Contains(msg, "invalid credentials")
+}
+
+// formatAPIError converts an API error response into an *APIError
+// Bodies that aren't JSON error responses become a plain error with the raw body.
+func formatAPIError(statusCode int, body []byte) error {
+	var resp apiErrorResponse
+	if json.Unmarshal(body, &resp) != nil || resp.Error == "" {
+		return fmt.Errorf("API error (status %d): %s", statusCode, string(body))
+	}
+
+	return &APIError{
+		StatusCode: statusCode,
+		Code:       resp.Code,
+		Message:    resp.Error,
+		Fields:     resp.Fields,
+	}
+}
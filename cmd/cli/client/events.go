@@ -0,0 +1,81 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+// SubscribeEvents opens the live update stream (GET /api/v1/events)
+// Events are delivered on the returned channel, which is closed when the stream ends:
+// ctx is cancelled, the connection drops or the server shuts down. Callers reconnect as needed.
+func (c *APIClient) SubscribeEvents(ctx context.Context) (<-chan *model.Event, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/api/v1/events", nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "text/event-stream")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	// The stream stays open indefinitely, so it can't use the client's request timeout
+	streamClient := &http.Client{Transport: c.httpClient.Transport}
+
+	resp, err := streamClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return nil, formatAPIError(resp.StatusCode, body)
+	}
+
+	events := make(chan *model.Event)
+	go func() {
+		defer close(events)
+		defer resp.Body.Close()
+
+		var data strings.Builder
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+
+			switch {
+			case line == "":
+				// Blank line ends an event
+				if data.Len() == 0 {
+					continue
+				}
+				var event model.Event
+				if err := json.Unmarshal([]byte(data.String()), &event); err == nil {
+					select {
+					case events <- &event:
+					case <-ctx.Done():
+						return
+					}
+				}
+				data.Reset()
+			case strings.HasPrefix(line, "data:"):
+				if data.Len() > 0 {
+					data.WriteByte('\n')
+				}
+				data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+			default:
+				// Comments (": ping"), "event:" and "retry:" fields need no handling;
+				// the event type is also in the data payload
+			}
+		}
+	}()
+
+	return events, nil
+}
@@ -0,0 +1,65 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/zalando/go-keyring"
+)
+
+// keychainService is the service name the tokens are stored under in the OS keychain,
+// with the profile name as the account
+const keychainService = "kg-cli"
+
+// errKeychainEmpty is returned when the keychain holds no tokens for a profile
+var errKeychainEmpty = errors.New("no tokens in the keychain")
+
+// loadKeychainState loads the authentication state of a profile from the OS keychain
+func loadKeychainState(profile string) (*AuthState, error) {
+	data, err := keyring.Get(keychainService, keychainAccount(profile))
+	if err != nil {
+		if errors.Is(err, keyring.ErrNotFound) {
+			return nil, errKeychainEmpty
+		}
+		return nil, fmt.Errorf("read keychain: %w", err)
+	}
+
+	var state AuthState
+	if err := json.Unmarshal([]byte(data), &state); err != nil {
+		return nil, fmt.Errorf("unmarshal auth state: %w", err)
+	}
+
+	return &state, nil
+}
+
+// saveKeychainState saves the authentication state of a profile to the OS keychain
+func saveKeychainState(profile string, state *AuthState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("marshal auth state: %w", err)
+	}
+
+	if err := keyring.Set(keychainService, keychainAccount(profile), string(data)); err != nil {
+		return fmt.Errorf("write keychain: %w", err)
+	}
+
+	return nil
+}
+
+// deleteKeychainState removes the authentication state of a profile from the OS keychain
+func deleteKeychainState(profile string) error {
+	if err := keyring.Delete(keychainService, keychainAccount(profile)); err != nil && !errors.Is(err, keyring.ErrNotFound) {
+		return fmt.Errorf("delete from keychain: %w", err)
+	}
+
+	return nil
+}
+
+// keychainAccount is the keychain account of a profile
+func keychainAccount(profile string) string {
+	if profile == "" {
+		return DefaultProfile
+	}
+	return profile
+}
@@ -0,0 +1,338 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+// PendingSyncKey is set in note metadata for notes created while offline
+const PendingSyncKey = "pending_sync"
+
+// conflictedCopySuffix ends the title of the copy of a note changed both offline and on the server
+const conflictedCopySuffix = " (conflicted copy)"
+
+// SyncResult summarizes a sync of queued offline operations
+type SyncResult struct {
+	Synced int
+	Failed []error
+	// Notes changed both offline and on the server: the offline version is saved as a new note
+	Conflicts []*SyncConflict
+}
+
+// SyncConflict is a note changed on the server since it was changed offline
+type SyncConflict struct {
+	Note *model.Note // The note on the server, unchanged by the sync
+	Copy *model.Note // The offline version, saved as a new note
+}
+
+// queuedUpdate is a note update queued while offline, with the updated_at of the version it was made to
+// Sync sends it as If-Match, so an update of a note changed on the server since doesn't overwrite it.
+// Updates queued by older versions have no base and are applied whatever the version.
+type queuedUpdate struct {
+	model.UpdateNoteRequest
+	BaseUpdatedAt *time.Time `json:"base_updated_at,omitempty"`
+}
+
+// SetCache enables the local cache for offline reads and queued writes
+func (c *APIClient) SetCache(cache *Cache) {
+	c.cache = cache
+}
+
+// Cache returns the local cache, or nil if offline mode is disabled
+func (c *APIClient) Cache() *Cache {
+	return c.cache
+}
+
+// isOffline reports whether err means the server could not be reached
+func isOffline(err error) bool {
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// cacheNotes writes notes fetched from the API through to the cache
+func (c *APIClient) cacheNotes(notes ...*model.Note) {
+	if c.cache == nil || len(notes) == 0 {
+		return
+	}
+	_ = c.cache.PutNotes(notes)
+}
+
+// applyCachedUpdate applies an update to the cached copy of a note, if any
+func (c *APIClient) applyCachedUpdate(id uuid.UUID, req *model.UpdateNoteRequest) *model.Note {
+	if c.cache == nil {
+		return nil
+	}
+
+	note, err := c.cache.GetNote(id)
+	if err != nil {
+		return nil
+	}
+
+	if req.Title != nil {
+		note.Title = *req.Title
+	}
+	if req.Content != nil {
+		note.Content = *req.Content
+	}
+	if req.NoteType != nil {
+		note.NoteType = *req.NoteType
+	}
+	for key, value := range req.Metadata {
+		if note.Metadata == nil {
+			note.Metadata = model.Metadata{}
+		}
+		if value == nil {
+			delete(note.Metadata, key)
+		} else {
+			note.Metadata[key] = value
+		}
+	}
+	note.UpdatedAt = time.Now()
+
+	_ = c.cache.PutNotes([]*model.Note{note})
+	return note
+}
+
+// queueCreateNote queues a note creation and returns a local placeholder note
+func (c *APIClient) queueCreateNote(req *model.CreateNoteRequest) (*model.Note, error) {
+	now := time.Now()
+	note := &model.Note{
+		ID:        uuid.New(),
+		Title:     req.Title,
+		Content:   req.Content,
+		NoteType:  req.NoteType,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Metadata:  model.Metadata{PendingSyncKey: true},
+	}
+	if note.NoteType == "" {
+		note.NoteType = model.NoteTypeNote
+	}
+
+	if err := c.cache.QueueOp(OpCreateNote, note.ID, req); err != nil {
+		return nil, err
+	}
+	if err := c.cache.PutNotes([]*model.Note{note}); err != nil {
+		return nil, err
+	}
+
+	return note, nil
+}
+
+// queueUpdateNote queues a note update and applies it to the cached copy
+// The cached copy keeps the updated time of its version on the server while changes to it are
+// pending, it is the version the update is based on.
+func (c *APIClient) queueUpdateNote(id uuid.UUID, req *model.UpdateNoteRequest) error {
+	update := &queuedUpdate{UpdateNoteRequest: *req}
+	if cached, err := c.cache.GetNote(id); err == nil {
+		update.BaseUpdatedAt = &cached.UpdatedAt
+	}
+	if err := c.cache.QueueOp(OpUpdateNote, id, update); err != nil {
+		return err
+	}
+
+	if note := c.applyCachedUpdate(id, req); note != nil {
+		if update.BaseUpdatedAt != nil {
+			note.UpdatedAt = *update.BaseUpdatedAt
+		}
+		if note.Metadata == nil {
+			note.Metadata = model.Metadata{}
+		}
+		note.Metadata[PendingSyncKey] = true
+		_ = c.cache.PutNotes([]*model.Note{note})
+	}
+
+	return nil
+}
+
+// saveConflictedCopy saves the cached, offline version of a note changed on the server as a new note
+// The note on the server is fetched again, so the cache holds it instead of the offline version.
+func (c *APIClient) saveConflictedCopy(id uuid.UUID) (*SyncConflict, error) {
+	local, err := c.cache.GetNote(id)
+	if err != nil {
+		return nil, fmt.Errorf("read offline version: %w", err)
+	}
+
+	// Titles are at most 500 characters
+	title := local.Title
+	if maxTitle := 500 - len(conflictedCopySuffix); len(title) > maxTitle {
+		title = strings.ToValidUTF8(title[:maxTitle], "")
+	}
+
+	resp, err := c.makeRequest("POST", "/api/v1/notes", &model.CreateNoteRequest{
+		Title:     title + conflictedCopySuffix,
+		Content:   local.Content,
+		NoteType:  local.NoteType,
+		Encrypted: local.Encrypted,
+	}, true)
+	if err != nil {
+		return nil, err
+	}
+	conflict := &SyncConflict{Copy: &model.Note{}}
+	if err := decodeResponse(resp, conflict.Copy); err != nil {
+		return nil, err
+	}
+	c.cacheNotes(conflict.Copy)
+
+	if conflict.Note, err = c.GetNote(id); err != nil {
+		return nil, err
+	}
+	return conflict, nil
+}
+
+// PendingChanges returns the number of operations waiting to be synced
+func (c *APIClient) PendingChanges() int {
+	if c.cache == nil {
+		return 0
+	}
+	count, _ := c.cache.PendingCount()
+	return count
+}
+
+// Sync replays operations queued while offline, in the order they were made
+// Stops at the first connection error; operations rejected by the server are
+// reported in the result and dropped so they don't block the queue.
+// A note changed on the server since it was changed offline is left as it is there, and the
+// offline version is saved as a conflicted copy; the note's later queued updates are dropped,
+// the copy has them.
+func (c *APIClient) Sync() (*SyncResult, error) {
+	result := &SyncResult{}
+	if c.cache == nil {
+		return result, nil
+	}
+
+	ops, err := c.cache.PendingOps()
+	if err != nil {
+		return result, err
+	}
+
+	// Maps locally generated note IDs to the IDs assigned by the server
+	idMap := make(map[uuid.UUID]uuid.UUID)
+	// Versions of notes on the server after the changes synced so far; RebaseNote records them
+	// for later syncs, but ops were read before
+	versions := make(map[uuid.UUID]time.Time)
+	// Notes whose offline version was saved as a conflicted copy
+	conflicted := make(map[uuid.UUID]bool)
+
+	for _, op := range ops {
+		noteID := op.NoteID
+		if realID, ok := idMap[noteID]; ok {
+			noteID = realID
+		}
+
+		var opErr error
+		switch op.Op {
+		case OpCreateNote:
+			var req model.CreateNoteRequest
+			if err := json.Unmarshal(op.Payload, &req); err != nil {
+				opErr = fmt.Errorf("decode queued note: %w", err)
+				break
+			}
+
+			resp, err := c.makeRequest("POST", "/api/v1/notes", &req, true)
+			if err != nil {
+				if isOffline(err) {
+					return result, err
+				}
+				opErr = err
+				break
+			}
+
+			var note model.Note
+			if err := decodeResponse(resp, &note); err != nil {
+				opErr = fmt.Errorf("create note %q: %w", req.Title, err)
+				break
+			}
+
+			idMap[op.NoteID] = note.ID
+			if err := c.cache.ReplaceNoteID(op.NoteID, note.ID); err != nil {
+				return result, err
+			}
+			c.cacheNotes(&note)
+			if err := c.cache.RebaseNote(note.ID, note.UpdatedAt); err != nil {
+				return result, err
+			}
+			versions[note.ID] = note.UpdatedAt
+
+		case OpUpdateNote:
+			if conflicted[noteID] {
+				if err := c.cache.RemoveOp(op.ID); err != nil {
+					return result, err
+				}
+				continue
+			}
+
+			var update queuedUpdate
+			if err := json.Unmarshal(op.Payload, &update); err != nil {
+				opErr = fmt.Errorf("decode queued update: %w", err)
+				break
+			}
+			if version, ok := versions[noteID]; ok {
+				update.BaseUpdatedAt = &version
+			}
+
+			var headers map[string]string
+			if update.BaseUpdatedAt != nil {
+				headers = map[string]string{"If-Match": model.NoteVersion(*update.BaseUpdatedAt)}
+			}
+			resp, err := c.makeRequestWithHeaders("PUT", "/api/v1/notes/"+noteID.String(), &update.UpdateNoteRequest, true, headers)
+			if err != nil {
+				if isOffline(err) {
+					return result, err
+				}
+				opErr = err
+				break
+			}
+
+			var note model.Note
+			err = decodeResponse(resp, &note)
+			if errors.Is(err, ErrNoteChanged) {
+				// Keep the offline version, and the update queued until it is saved
+				conflict, err := c.saveConflictedCopy(noteID)
+				if err != nil {
+					if isOffline(err) {
+						return result, err
+					}
+					result.Failed = append(result.Failed, fmt.Errorf("save conflicted copy of note %s: %w", noteID, err))
+					continue
+				}
+				conflicted[noteID] = true
+				result.Conflicts = append(result.Conflicts, conflict)
+				if err := c.cache.RemoveOp(op.ID); err != nil {
+					return result, err
+				}
+				continue
+			}
+			if err != nil {
+				opErr = fmt.Errorf("update note %s: %w", noteID, err)
+				break
+			}
+			if err := c.cache.RebaseNote(noteID, note.UpdatedAt); err != nil {
+				return result, err
+			}
+			versions[noteID] = note.UpdatedAt
+
+		default:
+			opErr = fmt.Errorf("unknown queued operation %q", op.Op)
+		}
+
+		if opErr != nil {
+			result.Failed = append(result.Failed, opErr)
+		} else {
+			result.Synced++
+		}
+
+		if err := c.cache.RemoveOp(op.ID); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,95 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// maxValidatorEntries bounds the responses kept in memory for conditional requests
+const maxValidatorEntries = 200
+
+// validatorCache remembers the ETag and body of GET responses
+// A cached response is sent again as If-None-Match, so the server can answer
+// 304 Not Modified instead of the whole note or graph.
+type validatorCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedResponse // Request path -> last response
+}
+
+// cachedResponse is a response body with its ETag
+type cachedResponse struct {
+	etag string
+	body []byte
+}
+
+// newValidatorCache creates an empty validator cache
+func newValidatorCache() *validatorCache {
+	return &validatorCache{entries: make(map[string]cachedResponse)}
+}
+
+// get returns the cached response for a path
+func (v *validatorCache) get(path string) (cachedResponse, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	entry, ok := v.entries[path]
+	return entry, ok
+}
+
+// put caches the response for a path
+func (v *validatorCache) put(path, etag string, body []byte) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	// Start over rather than track usage; the cache only saves bandwidth
+	if len(v.entries) >= maxValidatorEntries {
+		clear(v.entries)
+	}
+	v.entries[path] = cachedResponse{etag: etag, body: body}
+}
+
+// clear drops all cached responses
+func (v *validatorCache) clear() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	clear(v.entries)
+}
+
+// getConditional makes an authenticated GET request and decodes the JSON response into v
+// The ETag of the last response for the path is sent along, and a 304 Not Modified
+// answer is decoded from the cached body.
+func (c *APIClient) getConditional(path string, v interface{}) error {
+	var headers map[string]string
+	cached, ok := c.validators.get(path)
+	if ok {
+		headers = map[string]string{"If-None-Match": cached.etag}
+	}
+
+	resp, err := c.makeRequestWithHeaders("GET", path, nil, true, headers)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body := cached.body
+	switch {
+	case resp.StatusCode == http.StatusNotModified && ok:
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		body, _ := io.ReadAll(resp.Body)
+		return formatAPIError(resp.StatusCode, body)
+	default:
+		body, err = io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("read response: %w", err)
+		}
+		if etag := resp.Header.Get("ETag"); etag != "" {
+			c.validators.put(path, etag, body)
+		}
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
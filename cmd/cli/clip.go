@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// clipCmd saves a web page as a note
+var clipCmd = &cobra.Command{
+	Use:   "clip <url>",
+	Short: "Save the article of a web page as a note",
+	Long: `Have the server fetch a web page, extract its main article as Markdown and
+save it as a note tagged "clipped". The note opens with a line crediting the
+page, its author and the date it was clipped.
+
+  kg-cli clip https://example.com/how-gardens-grow
+  kg-cli clip https://example.com/post --tag reading --tag gardening`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !authState.IsAuthenticated() {
+			return fmt.Errorf("not authenticated. Please run 'kg-cli login'")
+		}
+
+		tags, _ := cmd.Flags().GetStringSlice("tag")
+
+		pageURL := args[0]
+		if !strings.Contains(pageURL, "://") {
+			pageURL = "https://" + pageURL
+		}
+
+		note, err := apiClient.ClipPage(pageURL, tags)
+		if err != nil {
+			return fmt.Errorf("clip: %w", err)
+		}
+
+		names := make([]string, len(note.Tags))
+		for i, tag := range note.Tags {
+			names[i] = tag.Name
+		}
+
+		fmt.Printf("Clipped: %s\n", note.Title)
+		fmt.Printf("ID: %s\n", note.ID)
+		fmt.Printf("Words: %d\n", note.WordCount)
+		if len(names) > 0 {
+			fmt.Printf("Tags: %s\n", strings.Join(names, ", "))
+		}
+		return nil
+	},
+}
+
+func init() {
+	clipCmd.Flags().StringSliceP("tag", "t", nil, "Extra tag for the clipped note (repeatable)")
+	rootCmd.AddCommand(clipCmd)
+}
@@ -0,0 +1,85 @@
+// Package clipboard copies text to the system clipboard for the CLI and the TUI
+package clipboard
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	"github.com/aymanbagabas/go-osc52/v2"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+// Field is the part of a note that is copied
+type Field string
+
+const (
+	FieldContent Field = "content"
+	FieldTitle   Field = "title"
+	FieldLink    Field = "link" // Wiki link to the note, [[Title]]
+	FieldID      Field = "id"
+)
+
+// Fields lists the fields that can be copied, in the order they are offered
+var Fields = []Field{FieldContent, FieldTitle, FieldLink, FieldID}
+
+// ParseField returns the field named name
+func ParseField(name string) (Field, error) {
+	for _, field := range Fields {
+		if string(field) == name {
+			return field, nil
+		}
+	}
+	return "", fmt.Errorf("unknown field %q (use content, title, link or id)", name)
+}
+
+// NoteText returns the text of a note to copy for field
+// Encrypted notes must be decrypted first for their content to be copied.
+func NoteText(note *model.Note, field Field) string {
+	switch field {
+	case FieldTitle:
+		return note.Title
+	case FieldLink:
+		return "[[" + note.Title + "]]"
+	case FieldID:
+		return note.ID.String()
+	default:
+		return note.Content
+	}
+}
+
+// Copy puts text on the clipboard
+// Over SSH, or without a clipboard tool, the text is sent to the terminal as an
+// OSC52 sequence instead; terminals without OSC52 support ignore it silently.
+func Copy(text string) error {
+	if clipboard.Unsupported || isRemote() {
+		return copyOSC52(text)
+	}
+	if err := clipboard.WriteAll(text); err != nil {
+		// No xclip, xsel or wl-copy installed
+		return copyOSC52(text)
+	}
+	return nil
+}
+
+// isRemote reports whether the CLI runs in an SSH session, where the system
+// clipboard belongs to the remote machine and not to the user
+func isRemote() bool {
+	return os.Getenv("SSH_TTY") != "" || os.Getenv("SSH_CONNECTION") != ""
+}
+
+// copyOSC52 asks the terminal to copy text, wrapped for tmux and screen when they run in between
+func copyOSC52(text string) error {
+	seq := osc52.New(text)
+	if os.Getenv("TMUX") != "" {
+		seq = seq.Tmux()
+	} else if strings.HasPrefix(os.Getenv("TERM"), "screen") {
+		seq = seq.Screen()
+	}
+	if _, err := seq.WriteTo(os.Stderr); err != nil {
+		return fmt.Errorf("write OSC52 sequence: %w", err)
+	}
+	return nil
+}
@@ -4,48 +4,97 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
 
 	"github.com/spf13/viper"
+
+	"github.com/momokii/go-cli-notes/cmd/cli/client"
 )
 
 // Config holds the application configuration
 type Config struct {
-	API         APIConfig         `mapstructure:"api"`
-	Editor      EditorConfig      `mapstructure:"editor"`
-	Preferences PreferencesConfig `mapstructure:"preferences"`
+	Profile     string                   `mapstructure:"profile"` // Profile used without --profile
+	API         APIConfig                `mapstructure:"api"`
+	Auth        AuthConfig               `mapstructure:"auth"`
+	Editor      EditorConfig             `mapstructure:"editor"`
+	Preferences PreferencesConfig        `mapstructure:"preferences"`
+	Layout      LayoutConfig             `mapstructure:"layout"`
+	Profiles    map[string]ProfileConfig `mapstructure:"profiles"`
+
+	// ActiveProfile is the profile this run uses, its API settings are already applied to API
+	ActiveProfile string `mapstructure:"-"`
+}
+
+// ProfileConfig holds the settings of a named profile, such as a work server
+// Each profile signs in separately and has its own offline cache.
+type ProfileConfig struct {
+	API APIConfig `mapstructure:"api"`
 }
 
+// profileNamePattern restricts profile names to what is safe in a directory name
+var profileNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
 // APIConfig holds API-related configuration
 type APIConfig struct {
 	BaseURL string `mapstructure:"base_url"`
 	Timeout int    `mapstructure:"timeout"` // in seconds
 }
 
+// AuthConfig holds where login tokens are kept
+type AuthConfig struct {
+	TokenStorage string `mapstructure:"token_storage"` // auto, keychain or file
+}
+
 // EditorConfig holds editor-related configuration
 type EditorConfig struct {
 	ExternalEditor string `mapstructure:"external_editor"`
+	VimMode        bool   `mapstructure:"vim_mode"` // Vim-style modal editing in the TUI note editor
 }
 
 // PreferencesConfig holds user preferences
 type PreferencesConfig struct {
-	DefaultNoteType  string `mapstructure:"default_note_type"`
-	AutoSaveInterval int    `mapstructure:"auto_save_interval"` // in seconds
-	Theme            string `mapstructure:"theme"`
+	DefaultNoteType  string            `mapstructure:"default_note_type"`
+	AutoSaveInterval int               `mapstructure:"auto_save_interval"` // in seconds
+	Theme            string            `mapstructure:"theme"`              // TUI theme; empty uses the account's theme setting
+	CustomTheme      map[string]string `mapstructure:"custom_theme"`       // Colors of the "custom" theme
+	OfflineCache     bool              `mapstructure:"offline_cache"`      // cache notes locally for offline use
+}
+
+// LayoutConfig holds the TUI view layouts, saved whenever they are changed in the TUI
+type LayoutConfig struct {
+	NoteList NoteListLayoutConfig `mapstructure:"note_list"`
+}
+
+// NoteListLayoutConfig holds the layout of the TUI note list
+type NoteListLayoutConfig struct {
+	ShowDescription bool `mapstructure:"show_description"` // tags or a content preview under each title
+	PageSize        int  `mapstructure:"page_size"`        // 0 uses the account's page_size setting
+	PreviewWidth    int  `mapstructure:"preview_width"`    // percentage of the width for the preview pane, 0 hides it
 }
 
 // LoadConfig loads configuration from file and environment variables
-func LoadConfig() (*Config, error) {
+// profile selects a profile from the profiles section; when empty the profile setting
+// of the file is used, and without one the default profile.
+func LoadConfig(profile string) (*Config, error) {
 	// Set default values
 	viper.SetDefault("api.base_url", "http://localhost:8080") // for localhost testing
 	// viper.SetDefault("api.base_url", "API_SERVER") // change here for 'prod' server
 	viper.SetDefault("api.timeout", 30)
+	viper.SetDefault("auth.token_storage", string(client.StorageAuto))
 	viper.SetDefault("editor.external_editor", os.Getenv("EDITOR"))
 	if viper.GetString("editor.external_editor") == "" {
 		viper.SetDefault("editor.external_editor", "vi")
 	}
+	viper.SetDefault("editor.vim_mode", false)
 	viper.SetDefault("preferences.default_note_type", "note")
 	viper.SetDefault("preferences.auto_save_interval", 30)
-	viper.SetDefault("preferences.theme", "dark")
+	viper.SetDefault("preferences.theme", "")
+	viper.SetDefault("preferences.offline_cache", true)
+	viper.SetDefault("layout.note_list.show_description", false)
+	viper.SetDefault("layout.note_list.page_size", 0)
+	viper.SetDefault("layout.note_list.preview_width", 0)
 
 	// Set config file path
 	homeDir, err := os.UserHomeDir()
@@ -82,9 +131,57 @@ func LoadConfig() (*Config, error) {
 		return nil, fmt.Errorf("unmarshal config: %w", err)
 	}
 
+	if err := config.applyProfile(profile, configFile); err != nil {
+		return nil, err
+	}
+
 	return &config, nil
 }
 
+// applyProfile selects the active profile and applies its API settings
+func (c *Config) applyProfile(profile, configFile string) error {
+	if profile == "" {
+		profile = c.Profile
+	}
+	// Viper lowercases the keys of the profiles section
+	profile = strings.ToLower(profile)
+	if profile == "" || profile == client.DefaultProfile {
+		c.ActiveProfile = client.DefaultProfile
+		return nil
+	}
+
+	if !profileNamePattern.MatchString(profile) {
+		return fmt.Errorf("invalid profile name %q (use letters, digits, - and _)", profile)
+	}
+
+	p, ok := c.Profiles[profile]
+	if !ok {
+		return fmt.Errorf("unknown profile %q (profiles: %s), add it under profiles in %s", profile, strings.Join(c.ProfileNames(), ", "), configFile)
+	}
+	if p.API.BaseURL == "" {
+		return fmt.Errorf("profile %q has no api.base_url in %s", profile, configFile)
+	}
+
+	c.ActiveProfile = profile
+	c.API.BaseURL = p.API.BaseURL
+	if p.API.Timeout > 0 {
+		c.API.Timeout = p.API.Timeout
+	}
+	return nil
+}
+
+// ProfileNames returns the default profile followed by the configured ones, sorted
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		if name != client.DefaultProfile {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return append([]string{client.DefaultProfile}, names...)
+}
+
 // SaveConfig saves the current configuration to file
 func SaveConfig(config *Config) error {
 	homeDir, err := os.UserHomeDir()
@@ -100,13 +197,23 @@ func SaveConfig(config *Config) error {
 		return fmt.Errorf("create config dir: %w", err)
 	}
 
-	// Set config values
-	viper.Set("api.base_url", config.API.BaseURL)
-	viper.Set("api.timeout", config.API.Timeout)
+	// Set config values; API holds the active profile's server, which must not
+	// replace the default one
+	if config.ActiveProfile == "" || config.ActiveProfile == client.DefaultProfile {
+		viper.Set("api.base_url", config.API.BaseURL)
+		viper.Set("api.timeout", config.API.Timeout)
+	}
+	viper.Set("auth.token_storage", config.Auth.TokenStorage)
 	viper.Set("editor.external_editor", config.Editor.ExternalEditor)
+	viper.Set("editor.vim_mode", config.Editor.VimMode)
 	viper.Set("preferences.default_note_type", config.Preferences.DefaultNoteType)
 	viper.Set("preferences.auto_save_interval", config.Preferences.AutoSaveInterval)
 	viper.Set("preferences.theme", config.Preferences.Theme)
+	viper.Set("preferences.custom_theme", config.Preferences.CustomTheme)
+	viper.Set("preferences.offline_cache", config.Preferences.OfflineCache)
+	viper.Set("layout.note_list.show_description", config.Layout.NoteList.ShowDescription)
+	viper.Set("layout.note_list.page_size", config.Layout.NoteList.PageSize)
+	viper.Set("layout.note_list.preview_width", config.Layout.NoteList.PreviewWidth)
 
 	// Write config file
 	if err := viper.SafeWriteConfigAs(configFile); err != nil {
@@ -0,0 +1,514 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+
+	"github.com/momokii/go-cli-notes/cmd/cli/client"
+	"github.com/momokii/go-cli-notes/internal/model"
+	"github.com/momokii/go-cli-notes/internal/util"
+)
+
+// syncGitCmd mirrors the notes as Markdown files in a git working tree
+var syncGitCmd = &cobra.Command{
+	Use:   "git",
+	Short: "Mirror your notes as Markdown files in a git repository",
+	Long: `Export every note as a Markdown file (with a YAML frontmatter header) into the
+top level of a git working tree and commit what changed, one commit per sync.
+The repository is created if needed.
+
+With --pull, remote changes are merged first and the Markdown files they touched
+are imported: edited files update their note (the previous version is kept as a
+revision), new files become notes and deleted files delete their note, which
+'kg-cli note restore' can undo. Files are matched to notes by the id in their
+frontmatter. With --push, the commits are pushed afterwards.
+
+Other files in the repository are left alone. Encrypted notes are written as
+ciphertext.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !authState.IsAuthenticated() {
+			return fmt.Errorf("not authenticated. Please run 'kg-cli login'")
+		}
+
+		repo, _ := cmd.Flags().GetString("repo")
+		pull, _ := cmd.Flags().GetBool("pull")
+		push, _ := cmd.Flags().GetBool("push")
+		if repo == "" {
+			return fmt.Errorf("repository is required (use --repo flag)")
+		}
+		if _, err := exec.LookPath("git"); err != nil {
+			return fmt.Errorf("git is not installed")
+		}
+
+		if err := ensureGitRepo(repo); err != nil {
+			return err
+		}
+
+		// Commit the server's notes first, so remote changes merge into them
+		if err := exportVault(repo, nil); err != nil {
+			return err
+		}
+		subject, err := commitVault(repo)
+		if err != nil {
+			return err
+		}
+		if subject != "" {
+			fmt.Printf("Committed: %s\n", subject)
+		}
+
+		if pull {
+			imported, err := pullVault(repo)
+			if err != nil {
+				return err
+			}
+
+			// Write the imported notes back with the IDs and timestamps the server gave them
+			if len(imported) > 0 {
+				if err := exportVault(repo, imported); err != nil {
+					return err
+				}
+				subject, err := commitVault(repo)
+				if err != nil {
+					return err
+				}
+				if subject != "" {
+					fmt.Printf("Committed: %s\n", subject)
+				}
+			}
+		}
+
+		if push {
+			if _, err := runGit(repo, "push"); err != nil {
+				return err
+			}
+			fmt.Println("Pushed")
+		}
+
+		if subject == "" && !pull && !push {
+			fmt.Println("Nothing to commit, the repository is up to date")
+		}
+		return nil
+	},
+}
+
+// runGit runs a git command in the repository and returns its trimmed output
+func runGit(repo string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", repo}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// ensureGitRepo creates the directory and initializes a repository in it when needed
+func ensureGitRepo(repo string) error {
+	if err := os.MkdirAll(repo, 0755); err != nil {
+		return fmt.Errorf("create repository directory: %w", err)
+	}
+	if _, err := runGit(repo, "rev-parse", "--is-inside-work-tree"); err == nil {
+		return nil
+	}
+
+	if _, err := runGit(repo, "init"); err != nil {
+		return err
+	}
+	fmt.Printf("Initialized a git repository in %s\n", repo)
+	return nil
+}
+
+// exportVault writes every note into the repository as <title>.md
+// Files of notes that were deleted or renamed are removed, as are the given files
+// (imported from a pull and now written under their note's own name).
+func exportVault(repo string, replaced []string) error {
+	var buf bytes.Buffer
+	if _, err := apiClient.ExportNotes(&buf, false); err != nil {
+		return fmt.Errorf("export notes: %w", err)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		return fmt.Errorf("read export: %w", err)
+	}
+
+	written := make(map[string]bool)
+	for _, f := range archive.File {
+		data, err := readZipFile(f)
+		if err != nil {
+			return fmt.Errorf("read %s: %w", f.Name, err)
+		}
+		written[f.Name] = true
+
+		// Unchanged files are left untouched
+		path := filepath.Join(repo, f.Name)
+		if current, err := os.ReadFile(path); err == nil && bytes.Equal(current, data) {
+			continue
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return fmt.Errorf("write %s: %w", f.Name, err)
+		}
+	}
+
+	stale := make(map[string]bool)
+	for _, name := range replaced {
+		stale[name] = true
+	}
+
+	entries, err := os.ReadDir(repo)
+	if err != nil {
+		return fmt.Errorf("read repository: %w", err)
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".md") || written[name] {
+			continue
+		}
+		// Only remove files written by a sync, recognizable by the note id in their frontmatter
+		if !stale[name] && vaultNoteID(filepath.Join(repo, name)) == uuid.Nil {
+			continue
+		}
+		if err := os.Remove(filepath.Join(repo, name)); err != nil {
+			return fmt.Errorf("remove %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// readZipFile returns the contents of an archive entry
+func readZipFile(f *zip.File) ([]byte, error) {
+	r, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// vaultNoteID returns the note id in the frontmatter of a Markdown file, uuid.Nil if it has none
+func vaultNoteID(path string) uuid.UUID {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return uuid.Nil
+	}
+	fm, _, err := util.ParseMarkdown(data)
+	if err != nil {
+		return uuid.Nil
+	}
+	id, err := uuid.Parse(fm.ID)
+	if err != nil {
+		return uuid.Nil
+	}
+	return id
+}
+
+// commitVault commits the Markdown files that changed and returns the commit subject
+// It returns an empty subject when nothing changed.
+func commitVault(repo string) (string, error) {
+	if _, err := runGit(repo, "add", "-A", "--", ":(glob)*.md"); err != nil {
+		return "", err
+	}
+	status, err := runGit(repo, "diff", "--cached", "--name-status", "--no-renames")
+	if err != nil {
+		return "", err
+	}
+	if status == "" {
+		return "", nil
+	}
+
+	message := vaultCommitMessage(status)
+	if _, err := runGit(repo, "commit", "-q", "-m", message); err != nil {
+		return "", err
+	}
+	return strings.SplitN(message, "\n", 2)[0], nil
+}
+
+// vaultCommitMessage describes staged changes (git diff --name-status) by note title
+// A single change gets a subject of its own, like "Update Meeting notes".
+func vaultCommitMessage(status string) string {
+	var added, updated, deleted []string
+	for _, line := range strings.Split(status, "\n") {
+		parts := strings.SplitN(line, "\t", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		title := strings.TrimSuffix(parts[1], ".md")
+		switch parts[0] {
+		case "A":
+			added = append(added, title)
+		case "D":
+			deleted = append(deleted, title)
+		default:
+			updated = append(updated, title)
+		}
+	}
+
+	total := len(added) + len(updated) + len(deleted)
+	if total == 1 {
+		switch {
+		case len(added) == 1:
+			return "Add " + added[0]
+		case len(deleted) == 1:
+			return "Delete " + deleted[0]
+		default:
+			return "Update " + updated[0]
+		}
+	}
+
+	var counts []string
+	if len(added) > 0 {
+		counts = append(counts, fmt.Sprintf("add %d", len(added)))
+	}
+	if len(updated) > 0 {
+		counts = append(counts, fmt.Sprintf("update %d", len(updated)))
+	}
+	if len(deleted) > 0 {
+		counts = append(counts, fmt.Sprintf("delete %d", len(deleted)))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Sync %d notes: %s\n", total, strings.Join(counts, ", "))
+	for _, group := range []struct {
+		label  string
+		titles []string
+	}{{"Added", added}, {"Updated", updated}, {"Deleted", deleted}} {
+		if len(group.titles) == 0 {
+			continue
+		}
+		sort.Strings(group.titles)
+		fmt.Fprintf(&b, "\n%s:\n", group.label)
+		for _, title := range group.titles {
+			fmt.Fprintf(&b, "- %s\n", title)
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// pullVault merges the remote changes and imports the Markdown files they touched
+// It returns the imported files that were created as new notes.
+func pullVault(repo string) ([]string, error) {
+	if remotes, err := runGit(repo, "remote"); err != nil {
+		return nil, err
+	} else if remotes == "" {
+		fmt.Println("No remote configured, skipping pull")
+		return nil, nil
+	}
+
+	before, _ := runGit(repo, "rev-parse", "--verify", "-q", "HEAD")
+	if _, err := runGit(repo, "pull", "--no-rebase", "--no-edit"); err != nil {
+		return nil, fmt.Errorf("%w (resolve conflicts in %s, commit, then sync again)", err, repo)
+	}
+	after, _ := runGit(repo, "rev-parse", "--verify", "-q", "HEAD")
+	if before == after {
+		fmt.Println("Pulled: already up to date")
+		return nil, nil
+	}
+
+	var changes string
+	var err error
+	if before == "" {
+		changes, err = runGit(repo, "diff", "--name-status", "--no-renames", "4b825dc642cb6eb9a060e54bf8d69288fbee4904", after, "--", ":(glob)*.md")
+	} else {
+		changes, err = runGit(repo, "diff", "--name-status", "--no-renames", before, after, "--", ":(glob)*.md")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tagCache := make(map[string]uuid.UUID)
+	tags, err := apiClient.GetTags()
+	if err != nil {
+		return nil, fmt.Errorf("get tags: %w", err)
+	}
+	for _, t := range tags {
+		tagCache[strings.ToLower(t.Name)] = t.ID
+	}
+
+	var created []string
+	counts := map[string]int{}
+	for _, line := range strings.Split(changes, "\n") {
+		parts := strings.SplitN(line, "\t", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		status, name := parts[0], parts[1]
+
+		var action string
+		var err error
+		if status == "D" {
+			action, err = importDeletedFile(repo, before, name)
+		} else {
+			action, err = importVaultFile(repo, name, tagCache)
+		}
+		if err != nil {
+			fmt.Printf("Failed %s: %v\n", name, err)
+			counts["failed"]++
+			continue
+		}
+		if action == "" {
+			continue
+		}
+		if action == "created" {
+			created = append(created, name)
+		}
+		counts[action]++
+		fmt.Printf("%s: %s\n", strings.ToUpper(action[:1])+action[1:], strings.TrimSuffix(name, ".md"))
+	}
+
+	fmt.Printf("Pulled: %d created, %d updated, %d deleted", counts["created"], counts["updated"], counts["deleted"])
+	if counts["failed"] > 0 {
+		fmt.Printf(", %d failed", counts["failed"])
+	}
+	fmt.Println()
+	return created, nil
+}
+
+// importVaultFile creates or updates the note of an added or modified file
+// It returns "created", "updated", or "" when the note already matched the file.
+func importVaultFile(repo, name string, tagCache map[string]uuid.UUID) (string, error) {
+	data, err := os.ReadFile(filepath.Join(repo, name))
+	if err != nil {
+		return "", err
+	}
+	fm, body, err := util.ParseMarkdown(data)
+	if err != nil {
+		return "", err
+	}
+	title := fm.Title
+	if title == "" {
+		title = strings.TrimSuffix(name, ".md")
+	}
+
+	var note *model.Note
+	if id, err := uuid.Parse(fm.ID); err == nil {
+		note, err = apiClient.GetNote(id)
+		if err != nil && !errors.Is(err, client.ErrNotFound) {
+			return "", err
+		}
+	}
+
+	action := "updated"
+	if note == nil {
+		noteType := apiClient.Settings().DefaultNoteType
+		for _, t := range apiClient.NoteTypes() {
+			if string(t.Name) == fm.Type {
+				noteType = t.Name
+			}
+		}
+		note, err = apiClient.CreateNote(&model.CreateNoteRequest{
+			Title:     title,
+			Content:   body,
+			NoteType:  noteType,
+			Encrypted: fm.Encrypted,
+			Metadata:  fm.Metadata,
+		})
+		if err != nil {
+			return "", err
+		}
+		action = "created"
+	} else if note.Title != title || strings.TrimRight(note.Content, "\n") != strings.TrimRight(body, "\n") {
+		if err := apiClient.UpdateNote(note.ID, &model.UpdateNoteRequest{Title: &title, Content: &body}); err != nil {
+			return "", err
+		}
+	} else {
+		action = ""
+	}
+
+	changed, err := syncNoteTags(note.ID, fm.Tags, tagCache)
+	if err != nil {
+		return "", fmt.Errorf("tags: %w", err)
+	}
+	if action == "" && changed {
+		action = "updated"
+	}
+	return action, nil
+}
+
+// importDeletedFile deletes the note of a file that was deleted remotely
+func importDeletedFile(repo, before, name string) (string, error) {
+	data, err := runGit(repo, "show", before+":"+name)
+	if err != nil {
+		return "", err
+	}
+	fm, _, err := util.ParseMarkdown([]byte(data))
+	if err != nil {
+		return "", err
+	}
+	id, err := uuid.Parse(fm.ID)
+	if err != nil {
+		return "", nil
+	}
+
+	if err := apiClient.DeleteNote(id); err != nil {
+		if errors.Is(err, client.ErrNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+	return "deleted", nil
+}
+
+// syncNoteTags gives a note exactly the named tags and reports whether any changed
+func syncNoteTags(noteID uuid.UUID, names []string, tagCache map[string]uuid.UUID) (bool, error) {
+	current, err := apiClient.GetNoteTags(noteID)
+	if err != nil {
+		return false, err
+	}
+
+	want := make(map[string]bool)
+	for _, name := range names {
+		want[strings.ToLower(name)] = true
+	}
+	have := make(map[string]bool)
+	changed := false
+	for _, tag := range current {
+		have[strings.ToLower(tag.Name)] = true
+		if !want[strings.ToLower(tag.Name)] {
+			if err := apiClient.RemoveTagFromNote(noteID, tag.ID); err != nil {
+				return changed, err
+			}
+			changed = true
+		}
+	}
+
+	for _, name := range names {
+		if have[strings.ToLower(name)] {
+			continue
+		}
+		tagID, err := ensureTag(tagCache, name)
+		if err != nil {
+			return changed, err
+		}
+		if err := apiClient.AddTagToNote(noteID, tagID); err != nil {
+			return changed, err
+		}
+		have[strings.ToLower(name)] = true
+		changed = true
+	}
+
+	return changed, nil
+}
+
+func init() {
+	syncGitCmd.Flags().StringP("repo", "r", "", "Path of the git working tree (created if needed)")
+	syncGitCmd.Flags().Bool("pull", false, "Pull and import remote changes before committing")
+	syncGitCmd.Flags().Bool("push", false, "Push the commits after syncing")
+
+	syncCmd.AddCommand(syncGitCmd)
+}
@@ -0,0 +1,407 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/momokii/go-cli-notes/internal/importer"
+	"github.com/momokii/go-cli-notes/internal/model"
+	"github.com/momokii/go-cli-notes/internal/util"
+	"github.com/spf13/cobra"
+)
+
+// importedNote tracks a note created during an import run
+type importedNote struct {
+	id      uuid.UUID
+	title   string
+	content string
+}
+
+// noteImportCmd imports a directory of Markdown files (e.g. an Obsidian vault),
+// or an Evernote or Notion export
+var noteImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import notes from Markdown files (e.g. an Obsidian vault), Evernote or Notion",
+	Long: `Import notes from another app. The --format flag selects the source:
+
+  markdown  a directory of .md files with optional YAML frontmatter (--dir)
+  enex      an Evernote .enex export, or a directory of them (--file)
+  notion    a Notion "Markdown & CSV" export zip, or its unzipped directory (--file)
+
+Evernote notebooks and top-level Notion pages become tags, and notes keep their
+creation dates. Links between imported notes are resolved once all of them exist.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		dir, _ := cmd.Flags().GetString("dir")
+		file, _ := cmd.Flags().GetString("file")
+		defaultType, _ := cmd.Flags().GetString("type")
+		skipTags, _ := cmd.Flags().GetBool("skip-tags")
+		if defaultType == "" {
+			defaultType = string(apiClient.Settings().DefaultNoteType)
+		}
+
+		var notes []*importer.Note
+		var source string
+		failed := 0
+
+		switch strings.ToLower(format) {
+		case "", "markdown", "md":
+			if dir == "" {
+				return fmt.Errorf("directory is required (use --dir flag)")
+			}
+			source = dir
+
+			var err error
+			notes, failed, err = readMarkdownDir(dir)
+			if err != nil {
+				return err
+			}
+		default:
+			if file == "" {
+				file = dir
+			}
+			if file == "" {
+				return fmt.Errorf("export file is required (use --file flag)")
+			}
+			source = file
+
+			imp, err := importer.New(format)
+			if err != nil {
+				return err
+			}
+			notes, err = imp.Read(file)
+			if err != nil {
+				return fmt.Errorf("read %s export: %w", format, err)
+			}
+		}
+
+		if len(notes) == 0 && failed == 0 {
+			fmt.Println("No notes found")
+			return nil
+		}
+
+		fmt.Printf("Found %d note(s) in %s\n\n", len(notes)+failed, source)
+
+		// Cache existing tags by lowercase name so we only create missing ones
+		tagCache := make(map[string]uuid.UUID)
+		if !skipTags {
+			tags, err := apiClient.GetTags()
+			if err != nil {
+				return fmt.Errorf("get tags: %w", err)
+			}
+			for _, t := range tags {
+				tagCache[strings.ToLower(t.Name)] = t.ID
+			}
+		}
+
+		// Frontmatter types the user doesn't have fall back to the default type
+		noteTypes := make(map[model.NoteType]bool)
+		for _, noteType := range apiClient.NoteTypes() {
+			noteTypes[noteType.Name] = true
+		}
+
+		// First pass: create every note in batches. Links to notes imported later
+		// in this run may not resolve yet, because the target note does not exist.
+		imported := make([]*importedNote, 0, len(notes))
+		titles := make(map[string]bool)
+
+		reqs := make([]*model.CreateNoteRequest, len(notes))
+		for i, n := range notes {
+			noteType := model.NoteType(defaultType)
+			if noteTypes[model.NoteType(n.Type)] {
+				noteType = model.NoteType(n.Type)
+			}
+
+			reqs[i] = &model.CreateNoteRequest{
+				Title:     n.Title,
+				Content:   n.Content,
+				NoteType:  noteType,
+				Encrypted: n.Encrypted,
+				Metadata:  n.Metadata,
+			}
+			if !n.Created.IsZero() && n.Created.Before(time.Now()) {
+				created := n.Created
+				reqs[i].CreatedAt = &created
+			}
+		}
+
+		var results []*model.BatchNoteResult
+		if len(reqs) > 0 {
+			resp, err := apiClient.CreateNotes(reqs)
+			if err != nil {
+				return fmt.Errorf("create notes: %w", err)
+			}
+			results = resp.Results
+		}
+
+		for _, result := range results {
+			path := notes[result.Index].Source
+			if result.Note == nil {
+				fmt.Printf("Failed %s: %s\n", path, result.Error)
+				failed++
+				continue
+			}
+			note := result.Note
+
+			if !skipTags {
+				for _, name := range notes[result.Index].Tags {
+					tagID, err := ensureTag(tagCache, name)
+					if err != nil {
+						fmt.Printf("Warning: tag '%s' on %s: %v\n", name, note.Title, err)
+						continue
+					}
+					if err := apiClient.AddTagToNote(note.ID, tagID); err != nil {
+						fmt.Printf("Warning: tag '%s' on %s: %v\n", name, note.Title, err)
+					}
+				}
+			}
+
+			imported = append(imported, &importedNote{
+				id:      note.ID,
+				title:   note.Title,
+				content: reqs[result.Index].Content,
+			})
+			titles[note.Title] = true
+			fmt.Printf("Imported: %s\n", note.Title)
+		}
+
+		// Second pass: re-save notes that link to other imported notes so the
+		// API re-processes their wiki links now that all targets exist.
+		parser := util.NewLinkParser()
+		relinked := 0
+		for _, n := range imported {
+			needsRelink := false
+			for _, link := range parser.ExtractLinks(n.content) {
+				if titles[link.Title] {
+					needsRelink = true
+					break
+				}
+			}
+			if !needsRelink {
+				continue
+			}
+
+			content := n.content
+			if err := apiClient.UpdateNote(n.id, &model.UpdateNoteRequest{Content: &content}); err != nil {
+				fmt.Printf("Warning: could not resolve links in %s: %v\n", n.title, err)
+				continue
+			}
+			relinked++
+		}
+
+		fmt.Println("---")
+		fmt.Printf("Imported: %d\n", len(imported))
+		fmt.Printf("Links resolved in: %d note(s)\n", relinked)
+		if failed > 0 {
+			fmt.Printf("Failed: %d\n", failed)
+		}
+
+		return nil
+	},
+}
+
+// readMarkdownDir reads the Markdown files of a directory as notes to import
+// Files that can't be read are reported and counted as failed.
+func readMarkdownDir(dir string) ([]*importer.Note, int, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, 0, fmt.Errorf("open directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, 0, fmt.Errorf("%s is not a directory", dir)
+	}
+
+	files, err := findMarkdownFiles(dir)
+	if err != nil {
+		return nil, 0, fmt.Errorf("scan directory: %w", err)
+	}
+
+	var notes []*importer.Note
+	failed := 0
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Printf("Skipped %s: %v\n", path, err)
+			failed++
+			continue
+		}
+
+		fm, body, err := util.ParseMarkdown(data)
+		if err != nil {
+			fmt.Printf("Skipped %s: %v\n", path, err)
+			failed++
+			continue
+		}
+
+		title := fm.Title
+		if title == "" {
+			title = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		}
+
+		notes = append(notes, &importer.Note{
+			Title:     title,
+			Content:   body,
+			Type:      fm.Type,
+			Encrypted: fm.Encrypted,
+			Tags:      fm.Tags,
+			Created:   fm.Created,
+			Metadata:  fm.Metadata,
+			Source:    path,
+		})
+	}
+
+	return notes, failed, nil
+}
+
+// noteImportJSONCmd creates notes from a JSON file in one batch request per chunk
+var noteImportJSONCmd = &cobra.Command{
+	Use:   "import-json <file>",
+	Short: "Import notes from a JSON array or NDJSON file",
+	Long: `Import notes from a file of note objects, either a JSON array or one object
+per line (NDJSON). Each object uses the same fields as the create API:
+
+  {"title": "Meeting", "content": "...", "note_type": "meeting"}
+
+Notes are sent in batches, so large files only take a few requests.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		defaultType, _ := cmd.Flags().GetString("type")
+
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			return fmt.Errorf("read file: %w", err)
+		}
+
+		reqs, err := parseNoteRequests(data)
+		if err != nil {
+			return fmt.Errorf("parse %s: %w", args[0], err)
+		}
+
+		if len(reqs) == 0 {
+			fmt.Println("No notes found")
+			return nil
+		}
+
+		for _, req := range reqs {
+			if req.NoteType == "" {
+				req.NoteType = model.NoteType(defaultType)
+			}
+		}
+
+		resp, err := apiClient.CreateNotes(reqs)
+		if err != nil {
+			return fmt.Errorf("create notes: %w", err)
+		}
+
+		for _, result := range resp.Results {
+			if result.Note == nil {
+				fmt.Printf("Failed #%d (%s): %s\n", result.Index+1, reqs[result.Index].Title, result.Error)
+			}
+		}
+
+		fmt.Println("---")
+		fmt.Printf("Imported: %d\n", resp.Created)
+		if resp.Failed > 0 {
+			fmt.Printf("Failed: %d\n", resp.Failed)
+		}
+
+		return nil
+	},
+}
+
+// parseNoteRequests decodes a JSON array or NDJSON stream of create requests
+func parseNoteRequests(data []byte) ([]*model.CreateNoteRequest, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, nil
+	}
+
+	if trimmed[0] == '[' {
+		var reqs []*model.CreateNoteRequest
+		if err := json.Unmarshal(trimmed, &reqs); err != nil {
+			return nil, err
+		}
+		return reqs, nil
+	}
+
+	var reqs []*model.CreateNoteRequest
+	dec := json.NewDecoder(bytes.NewReader(trimmed))
+	for dec.More() {
+		var req model.CreateNoteRequest
+		if err := dec.Decode(&req); err != nil {
+			return nil, fmt.Errorf("note %d: %w", len(reqs)+1, err)
+		}
+		reqs = append(reqs, &req)
+	}
+	return reqs, nil
+}
+
+// findMarkdownFiles walks dir and returns all .md files, skipping hidden directories
+// such as .obsidian and .git
+func findMarkdownFiles(dir string) ([]string, error) {
+	var files []string
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.EqualFold(filepath.Ext(path), ".md") {
+			files = append(files, path)
+		}
+		return nil
+	})
+
+	return files, err
+}
+
+// ensureTag returns the ID of the named tag, creating it if needed
+func ensureTag(cache map[string]uuid.UUID, name string) (uuid.UUID, error) {
+	key := strings.ToLower(name)
+	if id, ok := cache[key]; ok {
+		return id, nil
+	}
+
+	tag, err := apiClient.CreateTag(name)
+	if err != nil {
+		// The tag may already exist beyond the first page of GetTags
+		tags, listErr := apiClient.GetTags()
+		if listErr == nil {
+			for _, t := range tags {
+				if strings.EqualFold(t.Name, name) {
+					cache[key] = t.ID
+					return t.ID, nil
+				}
+			}
+		}
+		return uuid.Nil, err
+	}
+
+	cache[key] = tag.ID
+	return tag.ID, nil
+}
+
+func init() {
+	noteImportCmd.Flags().String("format", "markdown", "Source format: markdown, enex or notion")
+	noteImportCmd.Flags().StringP("dir", "d", "", "Directory containing Markdown files (markdown format)")
+	noteImportCmd.Flags().StringP("file", "f", "", "Export file or directory (enex and notion formats)")
+	noteImportCmd.Flags().StringP("type", "T", "", "Note type for files without a type in frontmatter (default: default_note_type setting)")
+	noteImportCmd.Flags().Bool("skip-tags", false, "Do not import tags from frontmatter")
+
+	noteImportJSONCmd.Flags().StringP("type", "T", "", "Note type for notes without note_type (default: default_note_type setting)")
+
+	noteCmd.AddCommand(noteImportCmd)
+	noteCmd.AddCommand(noteImportJSONCmd)
+}
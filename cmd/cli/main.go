@@ -1,14 +1,19 @@
 package main
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 	"time"
 
 	"golang.org/x/term"
 
 	"github.com/momokii/go-cli-notes/cmd/cli/client"
 	"github.com/momokii/go-cli-notes/cmd/cli/tui"
+	"github.com/momokii/go-cli-notes/cmd/cli/tui/models"
 	"github.com/spf13/cobra"
 )
 
@@ -23,10 +28,65 @@ func readPassword(prompt string) (string, error) {
 	return string(password), err
 }
 
+// readPasswordStdin reads a password piped to stdin, for scripts
+// Only the first line is used, without its line ending.
+func readPasswordStdin() (string, error) {
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// promptPassword reads the password from stdin with --password-stdin, or asks for it
+func promptPassword(cmd *cobra.Command, prompt string) (string, error) {
+	if fromStdin, _ := cmd.Flags().GetBool("password-stdin"); fromStdin {
+		return readPasswordStdin()
+	}
+	return readPassword(prompt)
+}
+
+// promptNewPassword reads a new password from stdin with --password-stdin, or asks
+// for it twice until both entries match
+func promptNewPassword(cmd *cobra.Command, prompt string) (string, error) {
+	if fromStdin, _ := cmd.Flags().GetBool("password-stdin"); fromStdin {
+		return readPasswordStdin()
+	}
+
+	for {
+		pw, err := readPassword(prompt)
+		if err != nil {
+			return "", err
+		}
+
+		confirm, err := readPassword("Confirm Password: ")
+		if err != nil {
+			return "", err
+		}
+
+		if pw == confirm {
+			return pw, nil
+		}
+
+		fmt.Println("Passwords do not match. Please try again.")
+	}
+}
+
+// promptValue returns the value of a flag, asking for it when the flag is empty
+func promptValue(cmd *cobra.Command, flag, prompt string) string {
+	value, _ := cmd.Flags().GetString(flag)
+	if value == "" {
+		fmt.Print(prompt)
+		fmt.Scanln(&value)
+	}
+	return strings.TrimSpace(value)
+}
+
 var (
 	config = &Config{}
 	apiClient *client.APIClient
 	authState = &client.AuthState{}
+	profileFlag string
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -37,8 +97,12 @@ var rootCmd = &cobra.Command{
 	Long: `A terminal-based note-taking application with wiki-style links,
 full-text search, and knowledge graph visualization.`,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-		// Load configuration
-		cfg, err := LoadConfig()
+		// Load configuration, for the profile given by --profile or KG_CLI_PROFILE
+		profile := profileFlag
+		if profile == "" {
+			profile = os.Getenv("KG_CLI_PROFILE")
+		}
+		cfg, err := LoadConfig(profile)
 		if err != nil {
 			return fmt.Errorf("load config: %w", err)
 		}
@@ -50,8 +114,16 @@ full-text search, and knowledge graph visualization.`,
 			time.Duration(cfg.API.Timeout)*time.Second,
 		)
 
-		// Load authentication state
-		state, err := client.LoadAuthState()
+		// Passphrase for encrypted notes (prompted for on demand when unset)
+		if passphrase := os.Getenv("KG_CLI_PASSPHRASE"); passphrase != "" {
+			apiClient.SetPassphrase(passphrase)
+		}
+
+		// Load authentication state, from the OS keychain or the auth file
+		if err := client.SetTokenStorage(client.TokenStorage(cfg.Auth.TokenStorage)); err != nil {
+			return fmt.Errorf("load config: auth.token_storage: %w", err)
+		}
+		state, err := client.LoadAuthState(cfg.ActiveProfile)
 		if err != nil {
 			return fmt.Errorf("load auth state: %w", err)
 		}
@@ -62,21 +134,48 @@ full-text search, and knowledge graph visualization.`,
 			state.ApplyToClient(apiClient)
 		}
 
+		// Open local cache for offline mode (non-fatal if unavailable)
+		if cfg.Preferences.OfflineCache {
+			if cache, err := client.OpenCache(cfg.ActiveProfile); err == nil {
+				apiClient.SetCache(cache)
+			}
+		}
+
+		// Push changes made while offline once the server is reachable again
+		if state.IsAuthenticated() && cmd != syncCmd && apiClient.PendingChanges() > 0 {
+			if result, err := apiClient.Sync(); err == nil {
+				if result.Synced > 0 {
+					fmt.Fprintf(os.Stderr, "Synced %d offline change(s)\n", result.Synced)
+				}
+				printSyncConflicts(os.Stderr, result)
+			}
+		}
+
 		return nil
 	},
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		if apiClient != nil && apiClient.Cache() != nil {
+			apiClient.Cache().Close()
+		}
+	},
 }
 
 // loginCmd handles user login
 var loginCmd = &cobra.Command{
 	Use:   "login",
 	Short: "Login to your account",
+	Long: `Login to your account with your email and password.
+
+With --device, nothing is typed in here: a code is printed to approve from a
+browser or a signed-in kg-cli on another device, which suits headless servers.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		var email string
+		if device, _ := cmd.Flags().GetBool("device"); device {
+			return deviceLogin()
+		}
 
-		fmt.Print("Email: ")
-		fmt.Scanln(&email)
+		email := promptValue(cmd, "email", "Email: ")
 
-		password, err := readPassword("Password: ")
+		password, err := promptPassword(cmd, "Password: ")
 		if err != nil {
 			return fmt.Errorf("read password: %w", err)
 		}
@@ -85,8 +184,20 @@ var loginCmd = &cobra.Command{
 			return fmt.Errorf("email and password are required")
 		}
 
-		// Attempt login
-		authResp, err := apiClient.Login(email, password)
+		// Attempt login, asking for a 2FA code if the account requires one
+		code, _ := cmd.Flags().GetString("code")
+		authResp, err := apiClient.Login(email, password, code)
+		if errors.Is(err, client.ErrTOTPRequired) && code == "" {
+			// stdin already held the password, so there is nothing to ask the code from
+			if fromStdin, _ := cmd.Flags().GetBool("password-stdin"); fromStdin {
+				return fmt.Errorf("login failed: two-factor code required (use --code)")
+			}
+
+			fmt.Print("Two-factor code: ")
+			fmt.Scanln(&code)
+
+			authResp, err = apiClient.Login(email, password, code)
+		}
 		if err != nil {
 			return fmt.Errorf("login failed: %w", err)
 		}
@@ -98,7 +209,7 @@ var loginCmd = &cobra.Command{
 			Email:        email,
 		}
 
-		if err := client.SaveAuthState(authState); err != nil {
+		if err := client.SaveAuthState(config.ActiveProfile, authState); err != nil {
 			return fmt.Errorf("save auth state: %w", err)
 		}
 
@@ -112,45 +223,119 @@ var registerCmd = &cobra.Command{
 	Use:   "register",
 	Short: "Register a new account",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		var username, email string
+		username := promptValue(cmd, "username", "Username: ")
+		email := promptValue(cmd, "email", "Email: ")
+
+		password, err := promptNewPassword(cmd, "Password: ")
+		if err != nil {
+			return fmt.Errorf("read password: %w", err)
+		}
+
+		if username == "" || email == "" || password == "" {
+			return fmt.Errorf("username, email and password are required")
+		}
+
+		// Attempt registration
+		if err := apiClient.Register(username, email, password); err != nil {
+			return fmt.Errorf("registration failed: %w", err)
+		}
+
+		fmt.Println("Registration successful! Please login with `kg-cli login`")
+		fmt.Println("A verification email is on its way; confirm it with `kg-cli verify-email`")
+		return nil
+	},
+}
 
-		fmt.Print("Username: ")
-		fmt.Scanln(&username)
+// verifyEmailCmd confirms the account's email address
+var verifyEmailCmd = &cobra.Command{
+	Use:   "verify-email",
+	Short: "Verify your email address",
+	Long: `Verify your email address with the token sent after registration.
 
-		fmt.Print("Email: ")
-		fmt.Scanln(&email)
+Use --resend to ask for a new token if the email got lost or the token expired.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		token, _ := cmd.Flags().GetString("token")
+		resend, _ := cmd.Flags().GetBool("resend")
 
-		// Password with confirmation loop
-		var password string
-		for {
-			pw, err := readPassword("Password: ")
-			if err != nil {
-				return fmt.Errorf("read password: %w", err)
+		if resend {
+			email := promptValue(cmd, "email", "Email: ")
+			if email == "" {
+				return fmt.Errorf("email is required")
 			}
 
-			confirm, err := readPassword("Confirm Password: ")
-			if err != nil {
-				return fmt.Errorf("read password: %w", err)
+			if err := apiClient.ResendVerification(email); err != nil {
+				return fmt.Errorf("resend verification: %w", err)
 			}
 
-			if pw == confirm {
-				password = pw
-				break
+			fmt.Println("If the email is registered and unverified, a new token is on its way.")
+			return nil
+		}
+
+		if token == "" {
+			fmt.Print("Verification token: ")
+			fmt.Scanln(&token)
+		}
+
+		token = strings.TrimSpace(token)
+		if token == "" {
+			return fmt.Errorf("verification token is required")
+		}
+
+		if err := apiClient.VerifyEmail(token); err != nil {
+			return fmt.Errorf("verify email: %w", err)
+		}
+
+		fmt.Println("Email verified!")
+		return nil
+	},
+}
+
+// resetPasswordCmd recovers an account through an emailed reset token
+var resetPasswordCmd = &cobra.Command{
+	Use:   "reset-password",
+	Short: "Reset a forgotten password",
+	Long: `Reset your password with a token sent to your email address.
+
+Without --token, asks for your email and requests a token first, then prompts
+for the token and a new password. Resetting signs out all other devices.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		token, _ := cmd.Flags().GetString("token")
+
+		if token == "" {
+			email := promptValue(cmd, "email", "Email: ")
+			if email == "" {
+				return fmt.Errorf("email is required")
 			}
 
-			fmt.Println("Passwords do not match. Please try again.")
+			if err := apiClient.ForgotPassword(email); err != nil {
+				return fmt.Errorf("request reset token: %w", err)
+			}
+
+			fmt.Println("If the email is registered, a reset token is on its way.")
+			// The token only arrives by email, so a script has to come back with --token
+			if fromStdin, _ := cmd.Flags().GetBool("password-stdin"); fromStdin {
+				fmt.Println("Run reset-password again with --token once it arrives.")
+				return nil
+			}
+			fmt.Print("Reset token: ")
+			fmt.Scanln(&token)
 		}
 
-		if username == "" || email == "" || password == "" {
-			return fmt.Errorf("username, email and password are required")
+		token = strings.TrimSpace(token)
+		if token == "" {
+			return fmt.Errorf("reset token is required")
 		}
 
-		// Attempt registration
-		if err := apiClient.Register(username, email, password); err != nil {
-			return fmt.Errorf("registration failed: %w", err)
+		password, err := promptNewPassword(cmd, "New Password: ")
+		if err != nil {
+			return fmt.Errorf("read password: %w", err)
 		}
 
-		fmt.Println("Registration successful! Please login with `kg-cli login`")
+		if err := apiClient.ResetPassword(token, password); err != nil {
+			return fmt.Errorf("reset password: %w", err)
+		}
+
+		fmt.Println("Password reset! Please login with `kg-cli login`")
 		return nil
 	},
 }
@@ -165,13 +350,29 @@ var logoutCmd = &cobra.Command{
 			return nil
 		}
 
+		// Sign out every device first, while this token still works
+		if all, _ := cmd.Flags().GetBool("all"); all {
+			if err := apiClient.RevokeAllSessions(); err != nil {
+				return fmt.Errorf("revoke sessions: %w", err)
+			}
+			fmt.Println("Revoked all sessions")
+		}
+
 		// Call API logout
 		if err := apiClient.Logout(); err != nil {
 			fmt.Printf("Warning: API logout failed: %v\n", err)
 		}
 
+		// Drop cached notes so the next account doesn't see them
+		if cache := apiClient.Cache(); cache != nil {
+			if pending := apiClient.PendingChanges(); pending > 0 {
+				fmt.Printf("Warning: discarding %d unsynced offline change(s)\n", pending)
+			}
+			_ = cache.Clear()
+		}
+
 		// Clear local auth state
-		if err := client.ClearAuthState(); err != nil {
+		if err := client.ClearAuthState(config.ActiveProfile); err != nil {
 			return fmt.Errorf("clear auth state: %w", err)
 		}
 
@@ -189,7 +390,9 @@ var statusCmd = &cobra.Command{
 		fmt.Println("==========================")
 
 		// Config info
+		fmt.Printf("Profile: %s\n", config.ActiveProfile)
 		fmt.Printf("API URL: %s\n", config.API.BaseURL)
+		fmt.Printf("Token storage: %s\n", client.AuthStateLocation(config.ActiveProfile))
 
 		// Auth status - validate with server
 		if authState.IsAuthenticated() {
@@ -209,10 +412,80 @@ var statusCmd = &cobra.Command{
 			fmt.Println("\nUse 'kg-cli login' to authenticate")
 		}
 
+		if pending := apiClient.PendingChanges(); pending > 0 {
+			fmt.Printf("Pending offline changes: %d (run 'kg-cli sync')\n", pending)
+		}
+
 		return nil
 	},
 }
 
+// syncCmd pushes changes made while offline to the server
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Sync changes made while offline",
+	Long: `Replay notes created or edited while the server was unreachable.
+
+When the API can't be reached, the CLI reads notes and tags from a local
+cache and queues creates/updates. Queued changes are synced automatically
+on the next command once the server is back; use this to sync explicitly.
+
+An edit of a note that was also changed on the server doesn't overwrite
+it: your offline version is saved as a new "(conflicted copy)" note.
+
+To mirror your notes in a git repository, see 'kg-cli sync git'.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !authState.IsAuthenticated() {
+			return fmt.Errorf("not authenticated. Please run 'kg-cli login'")
+		}
+		if apiClient.Cache() == nil {
+			return fmt.Errorf("offline cache is disabled (preferences.offline_cache)")
+		}
+
+		pending := apiClient.PendingChanges()
+		if pending == 0 {
+			fmt.Println("Nothing to sync")
+			return nil
+		}
+
+		result, err := apiClient.Sync()
+		if err != nil {
+			if result != nil && result.Synced > 0 {
+				fmt.Printf("Synced: %d\n", result.Synced)
+			}
+			return fmt.Errorf("sync: %w (%d change(s) still pending)", err, apiClient.PendingChanges())
+		}
+
+		fmt.Printf("Synced: %d\n", result.Synced)
+		printSyncConflicts(os.Stdout, result)
+		if len(result.Failed) > 0 {
+			fmt.Printf("Failed: %d\n", len(result.Failed))
+			for _, e := range result.Failed {
+				fmt.Printf("  - %v\n", e)
+			}
+		}
+
+		return nil
+	},
+}
+
+// printSyncConflicts lists the notes changed both offline and on the server, and where the offline version went
+func printSyncConflicts(w io.Writer, result *client.SyncResult) {
+	if len(result.Conflicts) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "Conflicts: %d\n", len(result.Conflicts))
+	for _, conflict := range result.Conflicts {
+		title := conflict.Copy.Title
+		if conflict.Note != nil {
+			title = conflict.Note.Title
+		}
+		fmt.Fprintf(w, "  - %q changed on the server, your offline version is saved as %q (%s)\n",
+			title, conflict.Copy.Title, conflict.Copy.ID)
+	}
+}
+
 // tuiCmd launches the interactive Terminal User Interface
 var tuiCmd = &cobra.Command{
 	Use:   "tui",
@@ -245,7 +518,20 @@ Navigation:
 		}
 
 		// Run the TUI
-		if err := tui.Run(apiClient, authState); err != nil {
+		tuiConfig := tui.Config{
+			Theme:       config.Preferences.Theme,
+			ThemeColors: config.Preferences.CustomTheme,
+			VimMode:     config.Editor.VimMode,
+			DraftSave:   time.Duration(config.Preferences.AutoSaveInterval) * time.Second,
+			Layout: tui.Layout{
+				NoteList: models.NoteListLayout(config.Layout.NoteList),
+			},
+			SaveLayout: func(layout tui.Layout) error {
+				config.Layout.NoteList = NoteListLayoutConfig(layout.NoteList)
+				return SaveConfig(config)
+			},
+		}
+		if err := tui.Run(apiClient, authState, tuiConfig); err != nil {
 			return fmt.Errorf("TUI error: %w", err)
 		}
 
@@ -259,11 +545,31 @@ func Execute() error {
 }
 
 func init() {
+	rootCmd.PersistentFlags().StringVar(&profileFlag, "profile", "", "Configuration profile to use, e.g. a work server (default: the profile setting, or \"default\")")
+
+	loginCmd.Flags().StringP("email", "e", "", "Account email (asked for when omitted)")
+	loginCmd.Flags().Bool("password-stdin", false, "Read the password from stdin")
+	loginCmd.Flags().String("code", "", "Two-factor code, for accounts with 2FA enabled")
+	loginCmd.Flags().Bool("device", false, "Login by approving a code from another device")
+	registerCmd.Flags().StringP("username", "u", "", "Username (asked for when omitted)")
+	registerCmd.Flags().StringP("email", "e", "", "Account email (asked for when omitted)")
+	registerCmd.Flags().Bool("password-stdin", false, "Read the password from stdin")
+	logoutCmd.Flags().Bool("all", false, "Also sign out all other devices")
+	verifyEmailCmd.Flags().StringP("token", "t", "", "Verification token from the email")
+	verifyEmailCmd.Flags().Bool("resend", false, "Request a new verification email")
+	verifyEmailCmd.Flags().StringP("email", "e", "", "Account email for --resend (asked for when omitted)")
+	resetPasswordCmd.Flags().StringP("token", "t", "", "Reset token from the email (skips requesting a new one)")
+	resetPasswordCmd.Flags().StringP("email", "e", "", "Account email to send the token to (asked for when omitted)")
+	resetPasswordCmd.Flags().Bool("password-stdin", false, "Read the new password from stdin")
+
 	// Add subcommands
 	rootCmd.AddCommand(loginCmd)
 	rootCmd.AddCommand(registerCmd)
+	rootCmd.AddCommand(verifyEmailCmd)
+	rootCmd.AddCommand(resetPasswordCmd)
 	rootCmd.AddCommand(logoutCmd)
 	rootCmd.AddCommand(statusCmd)
+	rootCmd.AddCommand(syncCmd)
 	rootCmd.AddCommand(tuiCmd)
 }
 
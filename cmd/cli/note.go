@@ -2,15 +2,22 @@ package main
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/momokii/go-cli-notes/cmd/cli/client"
+	"github.com/momokii/go-cli-notes/cmd/cli/clipboard"
 	"github.com/momokii/go-cli-notes/internal/model"
+	"github.com/momokii/go-cli-notes/internal/util"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 var noteCmd = &cobra.Command{
@@ -27,11 +34,27 @@ var noteListCmd = &cobra.Command{
 		limit, _ := cmd.Flags().GetInt("limit")
 		search, _ := cmd.Flags().GetString("search")
 		tag, _ := cmd.Flags().GetString("tag")
+		all, _ := cmd.Flags().GetBool("all")
+		sortBy, _ := cmd.Flags().GetString("sort")
+		sortOrder, _ := cmd.Flags().GetString("order")
+		if limit <= 0 {
+			limit = apiClient.Settings().PageSize
+		}
 
 		filter := model.NoteFilter{
-			Page:   page,
-			Limit:  limit,
-			Search: search,
+			Page:        page,
+			Limit:       limit,
+			Search:      search,
+			SortBy:      sortBy,
+			SortOrder:   sortOrder,
+			IncludeTags: true,
+		}
+
+		if err := filter.ValidateSort(); err != nil {
+			return err
+		}
+		if filter.SortBy == model.SortRelevance && filter.Search == "" {
+			return fmt.Errorf("--sort relevance needs a --search query")
 		}
 
 		// Handle tag filtering - support both tag ID and tag name
@@ -62,6 +85,26 @@ var noteListCmd = &cobra.Command{
 			}
 		}
 
+		// Print notes as they arrive instead of loading every one first
+		if all {
+			count := 0
+			err := apiClient.StreamNotes(filter, func(note *model.Note) error {
+				printNoteSummary(note)
+				count++
+				return nil
+			})
+			if err != nil {
+				return fmt.Errorf("list notes: %w", err)
+			}
+
+			if count == 0 {
+				fmt.Println("No notes found")
+			} else {
+				fmt.Printf("\nListed %d note(s)\n", count)
+			}
+			return nil
+		}
+
 		notes, total, err := apiClient.ListNotes(filter)
 		if err != nil {
 			return fmt.Errorf("list notes: %w", err)
@@ -74,18 +117,30 @@ var noteListCmd = &cobra.Command{
 
 		fmt.Printf("Found %d note(s):\n\n", total)
 		for _, note := range notes {
-			fmt.Printf("ID: %s\n", note.ID)
-			fmt.Printf("Title: %s\n", note.Title)
-			fmt.Printf("Type: %s\n", note.NoteType)
-			fmt.Printf("Words: %d\n", note.WordCount)
-			fmt.Printf("Created: %s\n", note.CreatedAt.Format("2006-01-02 15:04"))
-			fmt.Println("---")
+			printNoteSummary(note)
 		}
 
 		return nil
 	},
 }
 
+// printNoteSummary prints one entry of a note listing
+func printNoteSummary(note *model.Note) {
+	fmt.Printf("ID: %s\n", note.ID)
+	fmt.Printf("Title: %s\n", note.Title)
+	fmt.Printf("Type: %s\n", note.NoteType)
+	if len(note.Tags) > 0 {
+		names := make([]string, len(note.Tags))
+		for i, tag := range note.Tags {
+			names[i] = "#" + tag.Name
+		}
+		fmt.Printf("Tags: %s\n", strings.Join(names, " "))
+	}
+	fmt.Printf("Words: %d\n", note.WordCount)
+	fmt.Printf("Created: %s\n", note.CreatedAt.Format("2006-01-02 15:04"))
+	fmt.Println("---")
+}
+
 // noteGetCmd gets a single note
 var noteGetCmd = &cobra.Command{
 	Use:   "get <id>",
@@ -97,21 +152,154 @@ var noteGetCmd = &cobra.Command{
 			return fmt.Errorf("invalid note ID: %w", err)
 		}
 
-		note, err := apiClient.GetNote(id)
+		expand, _ := cmd.Flags().GetBool("expand")
+		getNote := apiClient.GetNote
+		if expand {
+			getNote = apiClient.GetNoteExpanded
+		}
+		note, err := getNote(id)
 		if err != nil {
 			return fmt.Errorf("get note: %w", err)
 		}
 
+		if note.Encrypted {
+			if err := ensurePassphrase(false); err != nil {
+				return err
+			}
+			if err := apiClient.DecryptNote(note); err != nil {
+				return fmt.Errorf("decrypt note: %w", err)
+			}
+		}
+
 		fmt.Printf("Title: %s\n", note.Title)
 		fmt.Printf("Type: %s\n", note.NoteType)
 		fmt.Printf("Word Count: %d\n", note.WordCount)
 		fmt.Printf("Reading Time: %d min\n", note.ReadingTimeMinutes)
+		if note.Encrypted {
+			fmt.Println("Encrypted: yes")
+		}
+		if note.SharedBy != "" {
+			fmt.Printf("Shared by: %s (%s)\n", note.SharedBy, note.Permission)
+		}
 		fmt.Printf("Created: %s\n", note.CreatedAt.Format("2006-01-02 15:04:05"))
 		fmt.Printf("Updated: %s\n", note.UpdatedAt.Format("2006-01-02 15:04:05"))
+		if summary := note.Metadata.Summary(); summary != nil {
+			fmt.Println("\nSummary:")
+			fmt.Println(summary.Summary)
+		}
+		if keys := note.Metadata.Keys(); len(keys) > 0 {
+			fmt.Println("\nMetadata:")
+			for _, key := range keys {
+				fmt.Printf("  %s: %s\n", key, note.Metadata.Format(key))
+			}
+		}
 		fmt.Println("\nContent:")
 		fmt.Println("---")
-		fmt.Println(note.Content)
+		if note.ExpandedContent != nil {
+			fmt.Println(*note.ExpandedContent)
+		} else {
+			fmt.Println(note.Content)
+		}
+
+		return nil
+	},
+}
+
+// noteMetaCmd shows or sets the metadata of a note
+var noteMetaCmd = &cobra.Command{
+	Use:   "meta <id> [key=value...]",
+	Short: "Show or set a note's metadata",
+	Long: `Show or set the key-value metadata of a note.
+
+Values are read as JSON when they parse as JSON (numbers, true/false, lists, objects)
+and as text otherwise. --unset removes keys.
+
+Examples:
+  kg-cli note meta <id>
+  kg-cli note meta <id> source=book rating=4 reviewed=true
+  kg-cli note meta <id> --unset rating`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := uuid.Parse(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid note ID: %w", err)
+		}
+		unset, _ := cmd.Flags().GetStringSlice("unset")
+
+		metadata := model.Metadata{}
+		for _, arg := range args[1:] {
+			key, value, ok := strings.Cut(arg, "=")
+			if !ok || strings.TrimSpace(key) == "" {
+				return fmt.Errorf("invalid metadata %q, expected key=value", arg)
+			}
+			var parsed any
+			if err := json.Unmarshal([]byte(value), &parsed); err != nil {
+				parsed = value
+			}
+			metadata[strings.TrimSpace(key)] = parsed
+		}
+		for _, key := range unset {
+			metadata[key] = nil
+		}
+
+		var note *model.Note
+		if len(metadata) == 0 {
+			note, err = apiClient.GetNote(id)
+		} else {
+			note, err = apiClient.UpdateNoteMetadata(id, metadata)
+		}
+		if err != nil {
+			return fmt.Errorf("note metadata: %w", err)
+		}
+
+		keys := note.Metadata.Keys()
+		if len(keys) == 0 {
+			fmt.Println("No metadata.")
+			return nil
+		}
+		for _, key := range keys {
+			fmt.Printf("%s: %s\n", key, note.Metadata.Format(key))
+		}
+		return nil
+	},
+}
+
+// noteCopyCmd copies a part of a note to the clipboard
+var noteCopyCmd = &cobra.Command{
+	Use:   "copy <id>",
+	Short: "Copy a note's content, title, [[link]] or ID to the clipboard",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := uuid.Parse(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid note ID: %w", err)
+		}
+
+		fieldName, _ := cmd.Flags().GetString("field")
+		field, err := clipboard.ParseField(fieldName)
+		if err != nil {
+			return err
+		}
+
+		note, err := apiClient.GetNote(id)
+		if err != nil {
+			return fmt.Errorf("get note: %w", err)
+		}
 
+		if field == clipboard.FieldContent && note.Encrypted {
+			if err := ensurePassphrase(false); err != nil {
+				return err
+			}
+			if err := apiClient.DecryptNote(note); err != nil {
+				return fmt.Errorf("decrypt note: %w", err)
+			}
+		}
+
+		if err := clipboard.Copy(clipboard.NoteText(note, field)); err != nil {
+			return fmt.Errorf("copy to clipboard: %w", err)
+		}
+
+		fmt.Printf("Copied %s of %q to the clipboard\n", field, note.Title)
 		return nil
 	},
 }
@@ -122,18 +310,37 @@ var noteCreateCmd = &cobra.Command{
 	Short: "Create a new note",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		title, _ := cmd.Flags().GetString("title")
-		content, _ := cmd.Flags().GetString("content")
 		noteType, _ := cmd.Flags().GetString("type")
+		encrypt, _ := cmd.Flags().GetBool("encrypt")
+		content, err := contentFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+		if noteType == "" {
+			noteType = string(apiClient.Settings().DefaultNoteType)
+		}
 
 		if title == "" {
 			return fmt.Errorf("title is required (use --title flag)")
 		}
 
+		if encrypt {
+			if err := ensurePassphrase(true); err != nil {
+				return err
+			}
+			encrypted, err := apiClient.EncryptContent(content)
+			if err != nil {
+				return fmt.Errorf("encrypt content: %w", err)
+			}
+			content = encrypted
+		}
+
 		noteTypeEnum := model.NoteType(noteType)
 		req := &model.CreateNoteRequest{
-			Title:    title,
-			Content:  content,
-			NoteType: noteTypeEnum,
+			Title:     title,
+			Content:   content,
+			NoteType:  noteTypeEnum,
+			Encrypted: encrypt,
 		}
 
 		note, err := apiClient.CreateNote(req)
@@ -144,6 +351,9 @@ var noteCreateCmd = &cobra.Command{
 		fmt.Printf("Note created successfully!\n")
 		fmt.Printf("ID: %s\n", note.ID)
 		fmt.Printf("Title: %s\n", note.Title)
+		if note.Metadata[client.PendingSyncKey] == true {
+			fmt.Println("Server unreachable - saved offline, will sync when connected")
+		}
 
 		return nil
 	},
@@ -158,8 +368,17 @@ var noteSearchCmd = &cobra.Command{
 		query := args[0]
 		page, _ := cmd.Flags().GetInt("page")
 		limit, _ := cmd.Flags().GetInt("limit")
+		semantic, _ := cmd.Flags().GetBool("semantic")
+		fuzzy, _ := cmd.Flags().GetBool("fuzzy")
+		if limit <= 0 {
+			limit = apiClient.Settings().PageSize
+		}
 
-		result, err := apiClient.SearchNotes(query, page, limit)
+		if semantic {
+			return semanticSearch(query, limit)
+		}
+
+		result, err := apiClient.SearchNotes(query, page, limit, fuzzy)
 		if err != nil {
 			return fmt.Errorf("search notes: %w", err)
 		}
@@ -169,11 +388,15 @@ var noteSearchCmd = &cobra.Command{
 			return nil
 		}
 
-		fmt.Printf("Found %d result(s) for '%s':\n\n", len(result.Results), query)
+		if result.Fuzzy {
+			fmt.Printf("No exact matches for '%s', found %d note(s) with a similar title:\n\n", query, len(result.Results))
+		} else {
+			fmt.Printf("Found %d result(s) for '%s':\n\n", len(result.Results), query)
+		}
 		for _, r := range result.Results {
 			fmt.Printf("ID: %s\n", r.Note.ID)
 			fmt.Printf("Title: %s\n", r.Note.Title)
-			fmt.Printf("Snippet: %s\n", r.Snippet)
+			fmt.Printf("Snippet: %s\n", snippetMarkers.Replace(r.Snippet))
 			fmt.Println("---")
 		}
 
@@ -181,15 +404,137 @@ var noteSearchCmd = &cobra.Command{
 	},
 }
 
+// semanticSearch prints the notes closest in meaning to query
+func semanticSearch(query string, limit int) error {
+	if limit > 50 {
+		limit = 50
+	}
+
+	result, err := apiClient.SemanticSearchNotes(query, limit)
+	if err != nil {
+		return fmt.Errorf("semantic search: %w", err)
+	}
+
+	if len(result.Results) == 0 {
+		fmt.Println("No results found (new notes are embedded in the background and may not be searchable yet)")
+		return nil
+	}
+
+	fmt.Printf("Found %d note(s) related to '%s':\n\n", len(result.Results), query)
+	for _, r := range result.Results {
+		fmt.Printf("ID: %s\n", r.Note.ID)
+		fmt.Printf("Title: %s\n", r.Note.Title)
+		fmt.Printf("Similarity: %.2f\n", r.Rank)
+		if r.Snippet != "" {
+			fmt.Printf("Snippet: %s\n", snippetMarkers.Replace(r.Snippet))
+		}
+		fmt.Println("---")
+	}
+
+	return nil
+}
+
+// snippetMarkers strips the match markers from search snippets for plain output
+var snippetMarkers = strings.NewReplacer(model.HighlightStart, "", model.HighlightStop, "")
+
+// noteDailyTemplateCmd shows or changes the daily note template
+var noteDailyTemplateCmd = &cobra.Command{
+	Use:   "daily-template",
+	Short: "Show or change the template used for new daily notes",
+	Long: `Show or change the template used for new daily notes.
+
+Placeholders: {{date}}, {{weekday}}, {{yesterday}}, {{tomorrow}}, {{title}}`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, _ := cmd.Flags().GetString("file")
+		editTemplate, _ := cmd.Flags().GetBool("edit")
+		reset, _ := cmd.Flags().GetBool("reset")
+
+		if reset {
+			if _, err := apiClient.SetDailyTemplate(""); err != nil {
+				return fmt.Errorf("reset daily template: %w", err)
+			}
+			fmt.Println("Daily template reset to default.")
+			return nil
+		}
+
+		current, err := apiClient.GetDailyTemplate()
+		if err != nil {
+			return fmt.Errorf("get daily template: %w", err)
+		}
+
+		var template string
+		switch {
+		case file != "":
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return fmt.Errorf("read template file: %w", err)
+			}
+			template = string(data)
+		case editTemplate:
+			// Edit the current template in $EDITOR
+			tmpFile := filepath.Join(os.TempDir(), "kg-cli-daily-template.md")
+			if err := os.WriteFile(tmpFile, []byte(current.Template), 0644); err != nil {
+				return fmt.Errorf("create temp file: %w", err)
+			}
+			defer os.Remove(tmpFile)
+
+			editor := os.Getenv("EDITOR")
+			if editor == "" {
+				editor = "vi" // Default to vi
+			}
+
+			editorCmd := exec.Command(editor, tmpFile)
+			editorCmd.Stdin = os.Stdin
+			editorCmd.Stdout = os.Stdout
+			editorCmd.Stderr = os.Stderr
+
+			if err := editorCmd.Run(); err != nil {
+				return fmt.Errorf("editor failed: %w", err)
+			}
+
+			data, err := os.ReadFile(tmpFile)
+			if err != nil {
+				return fmt.Errorf("read edited template: %w", err)
+			}
+			template = string(data)
+			if template == current.Template {
+				fmt.Println("No changes made.")
+				return nil
+			}
+		default:
+			// Just show the current template
+			if current.IsDefault {
+				fmt.Println("Daily template (default):")
+			} else {
+				fmt.Println("Daily template:")
+			}
+			fmt.Println(current.Template)
+			return nil
+		}
+
+		if _, err := apiClient.SetDailyTemplate(template); err != nil {
+			return fmt.Errorf("set daily template: %w", err)
+		}
+		fmt.Println("Daily template updated.")
+		return nil
+	},
+}
+
 // noteDailyCmd gets or creates a daily note
 var noteDailyCmd = &cobra.Command{
 	Use:   "daily [date]",
-	Short: "Get or create a daily note (YYYY-MM-DD format, or today if omitted)",
+	Short: "Get or create a daily note (YYYY-MM-DD, today, yesterday, tomorrow or +N/-N days)",
 	Args:  cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		date := "today"
+		expr := "today"
 		if len(args) > 0 {
-			date = args[0]
+			expr = args[0]
+		}
+
+		// Resolve relative dates locally so "today" follows the timezone setting
+		date, err := util.ResolveDailyDate(expr, time.Now().In(apiClient.Settings().Location()))
+		if err != nil {
+			return err
 		}
 
 		note, isCreated, err := apiClient.GetDailyNote(date)
@@ -203,6 +548,15 @@ var noteDailyCmd = &cobra.Command{
 			fmt.Printf("Found existing daily note for %s\n", date)
 		}
 
+		if note.Encrypted {
+			if err := ensurePassphrase(false); err != nil {
+				return err
+			}
+			if err := apiClient.DecryptNote(note); err != nil {
+				return fmt.Errorf("decrypt note: %w", err)
+			}
+		}
+
 		fmt.Printf("ID: %s\n", note.ID)
 		fmt.Printf("Title: %s\n", note.Title)
 		fmt.Printf("Content:\n%s\n", note.Content)
@@ -223,16 +577,44 @@ var noteUpdateCmd = &cobra.Command{
 		}
 
 		title, _ := cmd.Flags().GetString("title")
-		content, _ := cmd.Flags().GetString("content")
+		encrypt, _ := cmd.Flags().GetBool("encrypt")
+		decrypt, _ := cmd.Flags().GetBool("decrypt")
+		content, err := contentFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+		noteType, _ := cmd.Flags().GetString("type")
+		status, _ := cmd.Flags().GetString("status")
+
+		if encrypt && decrypt {
+			return fmt.Errorf("--encrypt and --decrypt cannot be used together")
+		}
 
 		// If flags provided, use flag-based update (for automation)
-		if title != "" || content != "" {
+		if title != "" || content != "" || cmd.Flags().Changed("from-file") || noteType != "" || status != "" || encrypt || decrypt {
 			req := &model.UpdateNoteRequest{}
 			if title != "" {
 				req.Title = &title
 			}
-			if content != "" {
-				req.Content = &content
+			if noteType != "" {
+				t := model.NoteType(noteType)
+				req.NoteType = &t
+			}
+			switch status {
+			case "":
+			case "none":
+				// Take the note off the board
+				req.Metadata = model.Metadata{model.MetadataStatus: nil}
+			default:
+				req.Metadata = model.Metadata{model.MetadataStatus: status}
+			}
+			if content != "" || encrypt || decrypt {
+				body, encrypted, err := prepareUpdatedContent(id, content, encrypt, decrypt)
+				if err != nil {
+					return err
+				}
+				req.Content = &body
+				req.Encrypted = &encrypted
 			}
 
 			if err := apiClient.UpdateNote(id, req); err != nil {
@@ -250,6 +632,16 @@ var noteUpdateCmd = &cobra.Command{
 			return fmt.Errorf("get note: %w", err)
 		}
 
+		// Edit encrypted notes as plaintext, they are re-encrypted before saving
+		if note.Encrypted {
+			if err := ensurePassphrase(false); err != nil {
+				return err
+			}
+			if err := apiClient.DecryptNote(note); err != nil {
+				return fmt.Errorf("decrypt note: %w", err)
+			}
+		}
+
 		fmt.Printf("Updating note: %s\n", note.Title)
 		fmt.Println("Current values shown - leave empty to keep existing value")
 		fmt.Println()
@@ -270,7 +662,7 @@ var noteUpdateCmd = &cobra.Command{
 		if confirmEdit != "n" {
 			// Create temp file with current content
 			tmpFile := filepath.Join(os.TempDir(), fmt.Sprintf("kg-cli-note-%s.md", id.String()))
-			if err := os.WriteFile(tmpFile, []byte(note.Content), 0644); err != nil {
+			if err := os.WriteFile(tmpFile, []byte(note.Content), 0600); err != nil {
 				return fmt.Errorf("create temp file: %w", err)
 			}
 			defer os.Remove(tmpFile)
@@ -326,6 +718,14 @@ var noteUpdateCmd = &cobra.Command{
 			return nil
 		}
 
+		if note.Encrypted && req.Content != nil {
+			encrypted, err := apiClient.EncryptContent(*req.Content)
+			if err != nil {
+				return fmt.Errorf("encrypt content: %w", err)
+			}
+			req.Content = &encrypted
+		}
+
 		// Perform update
 		if err := apiClient.UpdateNote(id, req); err != nil {
 			return fmt.Errorf("update note: %w", err)
@@ -336,6 +736,78 @@ var noteUpdateCmd = &cobra.Command{
 	},
 }
 
+// noteAppendCmd adds text to the end of a note
+var noteAppendCmd = &cobra.Command{
+	Use:   "append <id> [text...]",
+	Short: "Add text to the end of a note",
+	Long: `Add text to the end of a note, on a new line. The server joins the text to the
+current content, so appends from several scripts or terminals never overwrite each
+other. The text is the words after the ID, the --from-file file, or stdin:
+
+  kg-cli note append <id> "- 14:02 deploy finished"
+  make test 2>&1 | kg-cli note append <id>`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runNoteConcat(cmd, args, false)
+	},
+}
+
+// notePrependCmd adds text to the start of a note
+var notePrependCmd = &cobra.Command{
+	Use:   "prepend <id> [text...]",
+	Short: "Add text to the start of a note",
+	Long: `Add text to the start of a note, on a line of its own. Like 'note append', the
+text is the words after the ID, the --from-file file, or stdin.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runNoteConcat(cmd, args, true)
+	},
+}
+
+// runNoteConcat appends or prepends the text given to 'note append' or 'note prepend'
+func runNoteConcat(cmd *cobra.Command, args []string, prepend bool) error {
+	id, err := uuid.Parse(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid note ID: %w", err)
+	}
+
+	text := strings.Join(args[1:], " ")
+	if cmd.Flags().Changed("from-file") {
+		if text != "" {
+			return fmt.Errorf("text arguments and --from-file cannot be used together")
+		}
+		if text, err = contentFromFlags(cmd); err != nil {
+			return err
+		}
+	} else if text == "" && !term.IsTerminal(int(os.Stdin.Fd())) {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("read stdin: %w", err)
+		}
+		text = string(data)
+	}
+	if text == "" {
+		return fmt.Errorf("no text given, e.g. kg-cli note %s <id> \"some text\"", cmd.Name())
+	}
+
+	var note *model.Note
+	if prepend {
+		note, err = apiClient.PrependNote(id, text)
+	} else {
+		note, err = apiClient.AppendNote(id, text)
+	}
+	if err != nil {
+		return fmt.Errorf("%s note: %w", cmd.Name(), err)
+	}
+
+	if prepend {
+		fmt.Printf("Prepended to %s\n", note.Title)
+	} else {
+		fmt.Printf("Appended to %s\n", note.Title)
+	}
+	return nil
+}
+
 // noteDeleteCmd deletes a note
 var noteDeleteCmd = &cobra.Command{
 	Use:   "delete <id>",
@@ -348,13 +820,15 @@ var noteDeleteCmd = &cobra.Command{
 		}
 
 		// Confirm deletion
-		fmt.Printf("Are you sure you want to delete this note? (y/N): ")
-		var confirm string
-		fmt.Scanln(&confirm)
-
-		if confirm != "y" && confirm != "Y" {
-			fmt.Println("Deletion cancelled")
-			return nil
+		if yes, _ := cmd.Flags().GetBool("yes"); !yes {
+			fmt.Printf("Are you sure you want to delete this note? (y/N): ")
+			var confirm string
+			fmt.Scanln(&confirm)
+
+			if confirm != "y" && confirm != "Y" {
+				fmt.Println("Deletion cancelled")
+				return nil
+			}
 		}
 
 		if err := apiClient.DeleteNote(id); err != nil {
@@ -366,6 +840,27 @@ var noteDeleteCmd = &cobra.Command{
 	},
 }
 
+// noteRestoreCmd restores a deleted note
+var noteRestoreCmd = &cobra.Command{
+	Use:   "restore <id>",
+	Short: "Restore a deleted note",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := uuid.Parse(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid note ID: %w", err)
+		}
+
+		note, err := apiClient.RestoreNote(id)
+		if err != nil {
+			return fmt.Errorf("restore note: %w", err)
+		}
+
+		fmt.Printf("Note restored: %s\n", note.Title)
+		return nil
+	},
+}
+
 // noteLinksCmd shows outgoing links from a note
 var noteLinksCmd = &cobra.Command{
 	Use:   "links <id>",
@@ -408,6 +903,36 @@ var noteLinksCmd = &cobra.Command{
 	},
 }
 
+// noteForgottenCmd lists notes that haven't been opened in a while
+var noteForgottenCmd = &cobra.Command{
+	Use:   "forgotten",
+	Short: "List notes you haven't opened in a while (default: forgotten_days setting)",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		days, _ := cmd.Flags().GetInt("days")
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		forgotten, err := apiClient.GetForgottenNotes(days, limit)
+		if err != nil {
+			return fmt.Errorf("get forgotten notes: %w", err)
+		}
+
+		if len(forgotten) == 0 {
+			fmt.Println("No forgotten notes, you've visited them all recently")
+			return nil
+		}
+
+		fmt.Printf("Found %d forgotten note(s):\n\n", len(forgotten))
+		for _, f := range forgotten {
+			fmt.Printf("%s (ID: %s)\n", f.Note.Title, f.Note.ID)
+			fmt.Printf("Last opened: %s (%d days ago)\n", f.LastAccessedAt.Format("2006-01-02"), f.DaysSinceAccess)
+			fmt.Println("---")
+		}
+
+		return nil
+	},
+}
+
 // noteBacklinksCmd shows backlinks to a note
 var noteBacklinksCmd = &cobra.Command{
 	Use:   "backlinks <id>",
@@ -450,6 +975,48 @@ var noteBacklinksCmd = &cobra.Command{
 	},
 }
 
+// notePathCmd shows how two notes are connected
+var notePathCmd = &cobra.Command{
+	Use:   "path <from-id> <to-id>",
+	Short: "Show the shortest chain of links between two notes",
+	Long: `Show the shortest chain of links from one note to another. Links are followed
+in either direction, so a note linking to both connects them.`,
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, err := uuid.Parse(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid note ID format")
+		}
+		to, err := uuid.Parse(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid note ID format")
+		}
+
+		path, err := apiClient.GetGraphPath(from, to)
+		if err != nil {
+			return fmt.Errorf("find path: %w", err)
+		}
+
+		fmt.Printf("%d link(s) apart:\n\n", len(path.Links))
+		for i, note := range path.Path {
+			fmt.Printf("%s (ID: %s)\n", note.Title, note.ID)
+			if i < len(path.Links) {
+				if path.Links[i].Source == note.ID {
+					fmt.Println("  ↓ links to")
+				} else {
+					fmt.Println("  ↓ linked from")
+				}
+				if context := path.Links[i].Context; context != nil && *context != "" {
+					fmt.Printf("    \"%s\"\n", *context)
+				}
+			}
+		}
+
+		return nil
+	},
+}
+
 // noteTagsCmd shows tags on a note
 var noteTagsCmd = &cobra.Command{
 	Use:   "tags <id>",
@@ -485,37 +1052,599 @@ var noteTagsCmd = &cobra.Command{
 	},
 }
 
+// noteSummarizeCmd generates a TL;DR of a note
+var noteSummarizeCmd = &cobra.Command{
+	Use:   "summarize <id>",
+	Short: "Generate a short summary of a note",
+	Long: `Generate a TL;DR of a note with the server's language model.
+
+The summary is stored with the note, replacing an earlier one, and shown by
+'note get' and the TUI. Encrypted notes can't be summarized.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := uuid.Parse(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid note ID: %w", err)
+		}
+
+		fmt.Println("Summarizing...")
+		summary, err := apiClient.SummarizeNote(id)
+		if err != nil {
+			return fmt.Errorf("summarize note: %w", err)
+		}
+
+		fmt.Println()
+		fmt.Println(summary.Summary)
+		fmt.Printf("\n(%s)\n", summary.Model)
+		return nil
+	},
+}
+
+// noteExportCmd exports all notes as a zip of Markdown files
+var noteExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export all notes as a zip of Markdown files",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output, _ := cmd.Flags().GetString("output")
+		expandEmbeds, _ := cmd.Flags().GetBool("expand-embeds")
+		if output == "" {
+			output = fmt.Sprintf("kg-export-%s.zip", time.Now().Format("2006-01-02"))
+		}
+
+		f, err := os.Create(output)
+		if err != nil {
+			return fmt.Errorf("create output file: %w", err)
+		}
+		defer f.Close()
+
+		n, err := apiClient.ExportNotes(f, expandEmbeds)
+		if err != nil {
+			os.Remove(output)
+			return fmt.Errorf("export notes: %w", err)
+		}
+
+		fmt.Printf("Notes exported successfully!\n")
+		fmt.Printf("File: %s\n", output)
+		fmt.Printf("Size: %d bytes\n", n)
+
+		return nil
+	},
+}
+
+// noteAttachCmd uploads a file as an attachment of a note
+var noteAttachCmd = &cobra.Command{
+	Use:   "attach <id> <file>",
+	Short: "Attach a file to a note",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := uuid.Parse(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid note ID: %w", err)
+		}
+
+		attachment, err := apiClient.UploadAttachment(id, args[1])
+		if err != nil {
+			return fmt.Errorf("attach file: %w", err)
+		}
+
+		fmt.Printf("File attached successfully!\n")
+		fmt.Printf("ID: %s\n", attachment.ID)
+		fmt.Printf("Filename: %s\n", attachment.Filename)
+		fmt.Printf("Size: %d bytes\n", attachment.SizeBytes)
+
+		return nil
+	},
+}
+
+// noteAttachmentsCmd lists the attachments of a note
+var noteAttachmentsCmd = &cobra.Command{
+	Use:   "attachments <id>",
+	Short: "List the attachments of a note",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := uuid.Parse(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid note ID: %w", err)
+		}
+
+		attachments, err := apiClient.ListAttachments(id)
+		if err != nil {
+			return fmt.Errorf("list attachments: %w", err)
+		}
+
+		if len(attachments) == 0 {
+			fmt.Println("No attachments found")
+			return nil
+		}
+
+		fmt.Printf("Found %d attachment(s):\n\n", len(attachments))
+		for _, attachment := range attachments {
+			fmt.Printf("ID: %s\n", attachment.ID)
+			fmt.Printf("Filename: %s\n", attachment.Filename)
+			fmt.Printf("Type: %s\n", attachment.ContentType)
+			fmt.Printf("Size: %d bytes\n", attachment.SizeBytes)
+			fmt.Printf("Added: %s\n", attachment.CreatedAt.Format("2006-01-02 15:04"))
+			fmt.Println("---")
+		}
+
+		return nil
+	},
+}
+
+// noteDownloadCmd downloads an attachment of a note
+var noteDownloadCmd = &cobra.Command{
+	Use:   "download <id> <attachment-id>",
+	Short: "Download an attachment of a note",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := uuid.Parse(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid note ID: %w", err)
+		}
+		attachmentID, err := uuid.Parse(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid attachment ID: %w", err)
+		}
+
+		output, _ := cmd.Flags().GetString("output")
+		if output == "" {
+			// Default to the original filename
+			attachments, err := apiClient.ListAttachments(id)
+			if err != nil {
+				return fmt.Errorf("list attachments: %w", err)
+			}
+			for _, attachment := range attachments {
+				if attachment.ID == attachmentID {
+					output = filepath.Base(attachment.Filename)
+					break
+				}
+			}
+			if output == "" {
+				return fmt.Errorf("attachment not found")
+			}
+		}
+
+		f, err := os.Create(output)
+		if err != nil {
+			return fmt.Errorf("create output file: %w", err)
+		}
+		defer f.Close()
+
+		n, err := apiClient.DownloadAttachment(id, attachmentID, f)
+		if err != nil {
+			os.Remove(output)
+			return fmt.Errorf("download attachment: %w", err)
+		}
+
+		fmt.Printf("Attachment downloaded successfully!\n")
+		fmt.Printf("File: %s\n", output)
+		fmt.Printf("Size: %d bytes\n", n)
+
+		return nil
+	},
+}
+
+// noteDetachCmd removes an attachment from a note
+var noteDetachCmd = &cobra.Command{
+	Use:   "detach <id> <attachment-id>",
+	Short: "Remove an attachment from a note",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := uuid.Parse(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid note ID: %w", err)
+		}
+		attachmentID, err := uuid.Parse(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid attachment ID: %w", err)
+		}
+
+		if err := apiClient.DeleteAttachment(id, attachmentID); err != nil {
+			return fmt.Errorf("remove attachment: %w", err)
+		}
+
+		fmt.Println("Attachment removed successfully!")
+		return nil
+	},
+}
+
+// notePublishCmd publishes a note at a public, read-only link
+var notePublishCmd = &cobra.Command{
+	Use:   "publish <id>",
+	Short: "Publish a note at a public link",
+	Long: `Publish a note as a read-only web page and print its link. Anyone with the
+link can read the note; wiki links to your other published notes lead to their
+pages. Publishing a note again prints the link it already has.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := uuid.Parse(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid note ID: %w", err)
+		}
+
+		link, created, err := apiClient.PublishNote(id)
+		if err != nil {
+			return fmt.Errorf("publish note: %w", err)
+		}
+
+		if created {
+			fmt.Println("Note published successfully!")
+		} else {
+			fmt.Println("Note is already published")
+		}
+		fmt.Printf("Title: %s\n", link.Title)
+		fmt.Printf("URL: %s\n", link.URL)
+
+		if copyURL, _ := cmd.Flags().GetBool("copy"); copyURL {
+			if err := clipboard.Copy(link.URL); err != nil {
+				return fmt.Errorf("copy link: %w", err)
+			}
+			fmt.Println("Link copied to the clipboard")
+		}
+
+		return nil
+	},
+}
+
+// noteUnpublishCmd revokes the public link of a note
+var noteUnpublishCmd = &cobra.Command{
+	Use:   "unpublish <id>",
+	Short: "Revoke the public link of a note",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := uuid.Parse(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid note ID: %w", err)
+		}
+
+		if err := apiClient.UnpublishNote(id); err != nil {
+			return fmt.Errorf("revoke public link: %w", err)
+		}
+
+		fmt.Println("Public link revoked successfully!")
+		return nil
+	},
+}
+
+// notePublishedCmd lists the published notes
+var notePublishedCmd = &cobra.Command{
+	Use:   "published",
+	Short: "List published notes and their public links",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		links, err := apiClient.ListPublicLinks()
+		if err != nil {
+			return fmt.Errorf("list public links: %w", err)
+		}
+
+		if len(links) == 0 {
+			fmt.Println("No published notes")
+			return nil
+		}
+
+		fmt.Printf("Found %d published note(s):\n\n", len(links))
+		for _, link := range links {
+			fmt.Printf("ID: %s\n", link.NoteID)
+			fmt.Printf("Title: %s\n", link.Title)
+			fmt.Printf("URL: %s\n", link.URL)
+			fmt.Printf("Published: %s\n", link.CreatedAt.Format("2006-01-02 15:04"))
+			fmt.Println("---")
+		}
+
+		return nil
+	},
+}
+
+// noteShareCmd shares a note with another user
+var noteShareCmd = &cobra.Command{
+	Use:   "share <id> <user>",
+	Short: "Share a note with another user",
+	Long: `Share a note with another user of the server, by username or email. They can
+read it, or with --write also edit its title and content. Sharing a note again
+with the same user changes what they can do.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := uuid.Parse(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid note ID: %w", err)
+		}
+
+		permission := model.SharePermissionRead
+		if write, _ := cmd.Flags().GetBool("write"); write {
+			permission = model.SharePermissionWrite
+		}
+
+		share, created, err := apiClient.ShareNoteWith(id, args[1], permission)
+		if err != nil {
+			return fmt.Errorf("share note: %w", err)
+		}
+
+		if created {
+			fmt.Printf("Note shared with %s (%s)\n", share.Username, share.Permission)
+		} else {
+			fmt.Printf("Note is now shared with %s (%s)\n", share.Username, share.Permission)
+		}
+		return nil
+	},
+}
+
+// noteUnshareCmd stops sharing a note with a user
+var noteUnshareCmd = &cobra.Command{
+	Use:   "unshare <id> <user>",
+	Short: "Stop sharing a note with a user",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := uuid.Parse(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid note ID: %w", err)
+		}
+
+		shares, err := apiClient.ListNoteShares(id)
+		if err != nil {
+			return fmt.Errorf("list note shares: %w", err)
+		}
+
+		for _, share := range shares {
+			if strings.EqualFold(share.Username, args[1]) || strings.EqualFold(share.Email, args[1]) {
+				if err := apiClient.UnshareNoteWith(id, share.SharedWith); err != nil {
+					return fmt.Errorf("unshare note: %w", err)
+				}
+				fmt.Printf("Note is no longer shared with %s\n", share.Username)
+				return nil
+			}
+		}
+
+		return fmt.Errorf("note is not shared with %s", args[1])
+	},
+}
+
+// noteSharesCmd lists the users a note is shared with
+var noteSharesCmd = &cobra.Command{
+	Use:   "shares <id>",
+	Short: "List the users a note is shared with",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := uuid.Parse(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid note ID: %w", err)
+		}
+
+		shares, err := apiClient.ListNoteShares(id)
+		if err != nil {
+			return fmt.Errorf("list note shares: %w", err)
+		}
+
+		if len(shares) == 0 {
+			fmt.Println("Note isn't shared with anyone")
+			return nil
+		}
+
+		fmt.Printf("Shared with %d user(s):\n\n", len(shares))
+		for _, share := range shares {
+			fmt.Printf("%s <%s> - %s\n", share.Username, share.Email, share.Permission)
+		}
+
+		return nil
+	},
+}
+
+// noteSharedCmd lists the notes other users share with the current user
+var noteSharedCmd = &cobra.Command{
+	Use:   "shared",
+	Short: "List notes shared with me",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		notes, err := apiClient.ListSharedWithMe()
+		if err != nil {
+			return fmt.Errorf("list shared notes: %w", err)
+		}
+
+		if len(notes) == 0 {
+			fmt.Println("No notes are shared with you")
+			return nil
+		}
+
+		fmt.Printf("Found %d shared note(s):\n\n", len(notes))
+		for _, note := range notes {
+			fmt.Printf("ID: %s\n", note.ID)
+			fmt.Printf("Title: %s\n", note.Title)
+			fmt.Printf("Shared by: %s (%s)\n", note.SharedBy, note.Permission)
+			fmt.Printf("Updated: %s\n", note.UpdatedAt.Format("2006-01-02 15:04"))
+			fmt.Println("---")
+		}
+
+		return nil
+	},
+}
+
+// ensurePassphrase prompts for the encryption passphrase unless one is already set
+// When confirm is true the passphrase is asked twice, for encrypting with a new passphrase.
+func ensurePassphrase(confirm bool) error {
+	if apiClient.HasPassphrase() {
+		return nil
+	}
+
+	passphrase, err := readPassword("Encryption passphrase: ")
+	if err != nil {
+		return fmt.Errorf("read passphrase: %w", err)
+	}
+	if passphrase == "" {
+		return fmt.Errorf("passphrase cannot be empty")
+	}
+
+	if confirm {
+		again, err := readPassword("Confirm passphrase: ")
+		if err != nil {
+			return fmt.Errorf("read passphrase: %w", err)
+		}
+		if again != passphrase {
+			return fmt.Errorf("passphrases do not match")
+		}
+	}
+
+	apiClient.SetPassphrase(passphrase)
+	return nil
+}
+
+// contentFromFlags returns the note content given with --content or read from --from-file
+// A --from-file of "-" reads the content from stdin.
+func contentFromFlags(cmd *cobra.Command) (string, error) {
+	content, _ := cmd.Flags().GetString("content")
+	file, _ := cmd.Flags().GetString("from-file")
+	if file == "" {
+		return content, nil
+	}
+	if content != "" {
+		return "", fmt.Errorf("--content and --from-file cannot be used together")
+	}
+
+	var data []byte
+	var err error
+	if file == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(file)
+	}
+	if err != nil {
+		return "", fmt.Errorf("read content file: %w", err)
+	}
+	return string(data), nil
+}
+
+// prepareUpdatedContent returns the content to send for a flag-based update and whether
+// the note ends up encrypted. New content defaults to the current (decrypted) content.
+func prepareUpdatedContent(id uuid.UUID, content string, encrypt, decrypt bool) (string, bool, error) {
+	note, err := apiClient.GetNote(id)
+	if err != nil {
+		return "", false, fmt.Errorf("get note: %w", err)
+	}
+
+	encrypted := (note.Encrypted || encrypt) && !decrypt
+
+	if note.Encrypted || encrypted {
+		if err := ensurePassphrase(!note.Encrypted); err != nil {
+			return "", false, err
+		}
+	}
+	if err := apiClient.DecryptNote(note); err != nil {
+		return "", false, fmt.Errorf("decrypt note: %w", err)
+	}
+
+	if content == "" {
+		content = note.Content
+	}
+	if encrypted {
+		content, err = apiClient.EncryptContent(content)
+		if err != nil {
+			return "", false, fmt.Errorf("encrypt content: %w", err)
+		}
+	}
+
+	return content, encrypted, nil
+}
+
 func init() {
 	// Add flags to noteListCmd
 	noteListCmd.Flags().IntP("page", "p", 1, "Page number")
-	noteListCmd.Flags().IntP("limit", "l", 20, "Notes per page")
+	noteListCmd.Flags().IntP("limit", "l", 0, "Notes per page (default: page_size setting)")
+	noteListCmd.Flags().BoolP("all", "a", false, "List every note, streamed instead of paginated")
 	noteListCmd.Flags().StringP("search", "s", "", "Search query")
 	noteListCmd.Flags().StringP("tag", "t", "", "Filter by tag name or ID")
+	noteListCmd.Flags().String("sort", "", "Sort by created_at, updated_at, title, access_count, word_count or relevance (with --search)")
+	noteListCmd.Flags().String("order", "", "Sort order: asc or desc (default: desc)")
+
+	// Add flags to noteGetCmd
+	noteGetCmd.Flags().Bool("expand", false, "Show ![[Note]] embeds expanded inline")
+
+	// Add flags to noteMetaCmd
+	noteMetaCmd.Flags().StringSlice("unset", nil, "Metadata keys to remove")
 
 	// Add flags to noteCreateCmd
 	noteCreateCmd.Flags().StringP("title", "t", "", "Note title (required)")
 	noteCreateCmd.Flags().StringP("content", "c", "", "Note content")
-	noteCreateCmd.Flags().StringP("type", "T", "note", "Note type (note, daily, meeting, idea)")
+	noteCreateCmd.Flags().StringP("from-file", "f", "", "Read the content from a file (- for stdin)")
+	noteCreateCmd.Flags().StringP("type", "T", "", "Note type: note, daily, meeting, idea or a custom type (default: default_note_type setting)")
+	noteCreateCmd.Flags().Bool("encrypt", false, "Encrypt the content with your passphrase before sending it")
 
 	// Add flags to noteSearchCmd
 	noteSearchCmd.Flags().IntP("page", "p", 1, "Page number")
-	noteSearchCmd.Flags().IntP("limit", "l", 20, "Results per page")
+	noteSearchCmd.Flags().IntP("limit", "l", 0, "Results per page (default: page_size setting)")
+	noteSearchCmd.Flags().Bool("fuzzy", false, "When nothing matches, show notes with a similarly spelled title")
+	noteSearchCmd.Flags().Bool("semantic", false, "Find notes by meaning instead of keywords (needs an embeddings provider on the server)")
 
 	// Add flags to noteUpdateCmd
 	noteUpdateCmd.Flags().StringP("title", "t", "", "New note title")
 	noteUpdateCmd.Flags().StringP("content", "c", "", "New note content")
+	noteUpdateCmd.Flags().StringP("from-file", "f", "", "Read the new content from a file (- for stdin)")
+	noteUpdateCmd.Flags().StringP("type", "T", "", "New note type: note, daily, meeting, idea or a custom type")
+	noteUpdateCmd.Flags().String("status", "", "Board status: todo, doing, done, or none to take the note off the board")
+	noteUpdateCmd.Flags().Bool("encrypt", false, "Turn on client-side encryption for the note")
+	noteUpdateCmd.Flags().Bool("decrypt", false, "Turn off client-side encryption and store the note as plaintext")
+
+	// Add flags to noteDailyTemplateCmd
+	noteDailyTemplateCmd.Flags().StringP("file", "f", "", "Set the template from a Markdown file")
+	noteDailyTemplateCmd.Flags().BoolP("edit", "e", false, "Edit the template in $EDITOR")
+	noteDailyTemplateCmd.Flags().Bool("reset", false, "Reset to the default template")
+
+	// Add flags to noteAppendCmd and notePrependCmd
+	noteAppendCmd.Flags().StringP("from-file", "f", "", "Read the text from a file (- for stdin)")
+	notePrependCmd.Flags().StringP("from-file", "f", "", "Read the text from a file (- for stdin)")
+
+	// Add flags to noteDeleteCmd
+	noteDeleteCmd.Flags().BoolP("yes", "y", false, "Skip the confirmation prompt")
+
+	// Add flags to noteCopyCmd
+	noteCopyCmd.Flags().StringP("field", "f", "content", "What to copy: content, title, link or id")
+
+	// Add flags to noteForgottenCmd
+	noteForgottenCmd.Flags().IntP("days", "d", 0, "Days since a note was last opened (default: forgotten_days setting)")
+	noteForgottenCmd.Flags().IntP("limit", "l", 10, "Maximum number of notes (1-50)")
+
+	// Add flags to noteExportCmd
+	noteExportCmd.Flags().StringP("output", "o", "", "Output zip file (default kg-export-<date>.zip)")
+	noteExportCmd.Flags().Bool("expand-embeds", false, "Write ![[Note]] embeds expanded inline")
+
+	// Add flags to noteDownloadCmd
+	noteDownloadCmd.Flags().StringP("output", "o", "", "Output file (default the attachment's filename)")
+
+	// Add flags to notePublishCmd
+	notePublishCmd.Flags().BoolP("copy", "c", false, "Copy the link to the clipboard")
+
+	// Add flags to noteShareCmd
+	noteShareCmd.Flags().BoolP("write", "w", false, "Let the user edit the note too")
 
 	// Add subcommands to noteCmd
 	noteCmd.AddCommand(noteListCmd)
 	noteCmd.AddCommand(noteGetCmd)
+	noteCmd.AddCommand(noteMetaCmd)
+	noteCmd.AddCommand(noteCopyCmd)
 	noteCmd.AddCommand(noteCreateCmd)
 	noteCmd.AddCommand(noteUpdateCmd)
+	noteCmd.AddCommand(noteAppendCmd)
+	noteCmd.AddCommand(notePrependCmd)
 	noteCmd.AddCommand(noteDeleteCmd)
+	noteCmd.AddCommand(noteRestoreCmd)
 	noteCmd.AddCommand(noteSearchCmd)
 	noteCmd.AddCommand(noteDailyCmd)
+	noteCmd.AddCommand(noteDailyTemplateCmd)
 	noteCmd.AddCommand(noteLinksCmd)
 	noteCmd.AddCommand(noteBacklinksCmd)
+	noteCmd.AddCommand(notePathCmd)
+	noteCmd.AddCommand(noteForgottenCmd)
 	noteCmd.AddCommand(noteTagsCmd)
+	noteCmd.AddCommand(noteSummarizeCmd)
+	noteCmd.AddCommand(noteExportCmd)
+	noteCmd.AddCommand(noteAttachCmd)
+	noteCmd.AddCommand(noteAttachmentsCmd)
+	noteCmd.AddCommand(noteDownloadCmd)
+	noteCmd.AddCommand(noteDetachCmd)
+	noteCmd.AddCommand(notePublishCmd)
+	noteCmd.AddCommand(noteUnpublishCmd)
+	noteCmd.AddCommand(notePublishedCmd)
+	noteCmd.AddCommand(noteShareCmd)
+	noteCmd.AddCommand(noteUnshareCmd)
+	noteCmd.AddCommand(noteSharesCmd)
+	noteCmd.AddCommand(noteSharedCmd)
 
 	// Add noteCmd to rootCmd
 	rootCmd.AddCommand(noteCmd)
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+var noteTypeCmd = &cobra.Command{
+	Use:     "note-type",
+	Aliases: []string{"types"},
+	Short:   "Manage note types",
+}
+
+// noteTypeListCmd lists the built-in and custom note types
+var noteTypeListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List note types",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		noteTypes, err := apiClient.ListNoteTypes()
+		if err != nil {
+			return fmt.Errorf("list note types: %w", err)
+		}
+
+		fmt.Printf("Found %d note type(s):\n\n", len(noteTypes))
+		for _, noteType := range noteTypes {
+			kind := "custom"
+			if noteType.BuiltIn {
+				kind = "built-in"
+			}
+			fmt.Printf("%s %s (%s, %d note(s))", noteType.Icon, noteType.Name, kind, noteType.NoteCount)
+			if noteType.Color != nil {
+				fmt.Printf(" %s", *noteType.Color)
+			}
+			fmt.Println()
+			printNoteTypeFields(noteType.Fields)
+		}
+
+		return nil
+	},
+}
+
+// noteTypeCreateCmd defines a custom note type
+var noteTypeCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a custom note type",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		icon, _ := cmd.Flags().GetString("icon")
+
+		req := &model.CreateNoteTypeRequest{Name: args[0], Icon: icon}
+		if cmd.Flags().Changed("color") {
+			color, _ := cmd.Flags().GetString("color")
+			req.Color = &color
+		}
+
+		noteType, err := apiClient.CreateNoteType(req)
+		if err != nil {
+			return fmt.Errorf("create note type: %w", err)
+		}
+
+		fmt.Printf("Note type created successfully!\n")
+		fmt.Printf("Name: %s\n", noteType.Name)
+		if noteType.Icon != "" {
+			fmt.Printf("Icon: %s\n", noteType.Icon)
+		}
+		if noteType.Color != nil {
+			fmt.Printf("Color: %s\n", *noteType.Color)
+		}
+
+		return nil
+	},
+}
+
+// noteTypeUpdateCmd changes the icon or color of a custom note type
+var noteTypeUpdateCmd = &cobra.Command{
+	Use:   "update <name>",
+	Short: "Change the icon or color of a custom note type",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		req := &model.UpdateNoteTypeRequest{}
+		if cmd.Flags().Changed("icon") {
+			icon, _ := cmd.Flags().GetString("icon")
+			req.Icon = &icon
+		}
+		if cmd.Flags().Changed("color") {
+			color, _ := cmd.Flags().GetString("color")
+			req.Color = &color
+		}
+		if req.Icon == nil && req.Color == nil {
+			return fmt.Errorf("nothing to update, set --icon or --color")
+		}
+
+		noteType, err := apiClient.UpdateNoteType(args[0], req)
+		if err != nil {
+			return fmt.Errorf("update note type: %w", err)
+		}
+
+		fmt.Printf("Note type %s updated successfully!\n", noteType.Name)
+
+		return nil
+	},
+}
+
+// noteTypeDeleteCmd deletes a custom note type
+var noteTypeDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a custom note type no note uses",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Confirm deletion
+		if yes, _ := cmd.Flags().GetBool("yes"); !yes {
+			fmt.Printf("Are you sure you want to delete note type %s? (y/N): ", args[0])
+			var confirm string
+			fmt.Scanln(&confirm)
+
+			if strings.ToLower(confirm) != "y" {
+				fmt.Println("Deletion cancelled")
+				return nil
+			}
+		}
+
+		if err := apiClient.DeleteNoteType(args[0]); err != nil {
+			return fmt.Errorf("delete note type: %w", err)
+		}
+
+		fmt.Println("Note type deleted successfully!")
+
+		return nil
+	},
+}
+
+// noteTypeFieldsCmd shows or replaces the typed custom fields of a note type
+var noteTypeFieldsCmd = &cobra.Command{
+	Use:   "fields <name> [field:type[!]...]",
+	Short: "Show or set the custom fields of a note type",
+	Long: `Show or set the typed custom fields of a note type, built-in or custom.
+
+Fields are given as name:type, with type one of text, number, date, boolean or list.
+A trailing ! makes a field required, and number fields take a range as name:number:min..max.
+Giving fields replaces all of the type's fields; use --clear to remove them.
+
+Examples:
+  kg-cli note-type fields meeting attendees:list! date:date!
+  kg-cli note-type fields book author:text rating:number:1..5`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		clear, _ := cmd.Flags().GetBool("clear")
+		if len(args) == 1 && !clear {
+			for _, noteType := range apiClient.NoteTypes() {
+				if string(noteType.Name) == args[0] {
+					if len(noteType.Fields) == 0 {
+						fmt.Printf("Note type %s has no custom fields\n", noteType.Name)
+					}
+					printNoteTypeFields(noteType.Fields)
+					return nil
+				}
+			}
+			return fmt.Errorf("unknown note type %q", args[0])
+		}
+		if clear && len(args) > 1 {
+			return fmt.Errorf("--clear can't be combined with fields")
+		}
+
+		req := &model.SetNoteTypeFieldsRequest{Fields: []*model.NoteTypeField{}}
+		for _, spec := range args[1:] {
+			field, err := parseNoteTypeField(spec)
+			if err != nil {
+				return err
+			}
+			req.Fields = append(req.Fields, field)
+		}
+
+		noteType, err := apiClient.SetNoteTypeFields(args[0], req)
+		if err != nil {
+			return fmt.Errorf("set note type fields: %w", err)
+		}
+
+		fmt.Printf("Fields of note type %s updated successfully!\n", noteType.Name)
+		printNoteTypeFields(noteType.Fields)
+
+		return nil
+	},
+}
+
+// parseNoteTypeField reads a field given as name:type[!] or name:number:min..max[!]
+func parseNoteTypeField(spec string) (*model.NoteTypeField, error) {
+	field := &model.NoteTypeField{}
+	spec, field.Required = strings.CutSuffix(spec, "!")
+
+	parts := strings.Split(spec, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return nil, fmt.Errorf("invalid field %q, use name:type", spec)
+	}
+	field.Name, field.Type = parts[0], model.NoteFieldType(parts[1])
+
+	if len(parts) == 3 {
+		low, high, ok := strings.Cut(parts[2], "..")
+		var errLow, errHigh error
+		field.Min, errLow = parseFieldBound(low)
+		field.Max, errHigh = parseFieldBound(high)
+		if !ok || errLow != nil || errHigh != nil {
+			return nil, fmt.Errorf("invalid range %q of field %s, use min..max", parts[2], field.Name)
+		}
+	}
+
+	return field, nil
+}
+
+// parseFieldBound reads one end of a number field's range, nil when it is left open
+func parseFieldBound(text string) (*float64, error) {
+	if text == "" {
+		return nil, nil
+	}
+	n, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// printNoteTypeFields prints the custom fields of a note type, one per line
+func printNoteTypeFields(fields []*model.NoteTypeField) {
+	for _, field := range fields {
+		fmt.Printf("    %s: %s", field.Name, field.Type)
+		if field.Min != nil || field.Max != nil {
+			low, high := "", ""
+			if field.Min != nil {
+				low = strconv.FormatFloat(*field.Min, 'f', -1, 64)
+			}
+			if field.Max != nil {
+				high = strconv.FormatFloat(*field.Max, 'f', -1, 64)
+			}
+			fmt.Printf(" %s..%s", low, high)
+		}
+		if field.Required {
+			fmt.Print(" (required)")
+		}
+		fmt.Println()
+	}
+}
+
+func init() {
+	noteTypeCreateCmd.Flags().String("icon", "", "Icon shown next to the type, e.g. an emoji")
+	noteTypeCreateCmd.Flags().String("color", "", "Hex color, e.g. #00ADD8")
+	noteTypeUpdateCmd.Flags().String("icon", "", "New icon (empty to remove)")
+	noteTypeUpdateCmd.Flags().String("color", "", "New hex color")
+	noteTypeDeleteCmd.Flags().BoolP("yes", "y", false, "Skip the confirmation prompt")
+	noteTypeFieldsCmd.Flags().Bool("clear", false, "Remove all custom fields of the type")
+
+	noteTypeCmd.AddCommand(noteTypeListCmd)
+	noteTypeCmd.AddCommand(noteTypeCreateCmd)
+	noteTypeCmd.AddCommand(noteTypeUpdateCmd)
+	noteTypeCmd.AddCommand(noteTypeDeleteCmd)
+	noteTypeCmd.AddCommand(noteTypeFieldsCmd)
+	rootCmd.AddCommand(noteTypeCmd)
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/momokii/go-cli-notes/internal/util"
+)
+
+var reviewCmd = &cobra.Command{
+	Use:   "review",
+	Short: "Write review notes summarizing your work and review flashcards",
+}
+
+// reviewWeekCmd writes the weekly review note
+var reviewWeekCmd = &cobra.Command{
+	Use:   "week [date]",
+	Short: "Write the review note of a week (any day of it: YYYY-MM-DD, today, yesterday or +N/-N days)",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		expr := "today"
+		if len(args) > 0 {
+			expr = args[0]
+		}
+
+		// Resolve relative dates locally so "today" follows the timezone setting
+		date, err := util.ResolveDailyDate(expr, time.Now().In(apiClient.Settings().Location()))
+		if err != nil {
+			return err
+		}
+
+		note, review, isCreated, err := apiClient.CreateWeeklyReview(date)
+		if err != nil {
+			return fmt.Errorf("write weekly review: %w", err)
+		}
+
+		if isCreated {
+			fmt.Printf("Created weekly review for %s to %s\n", review.WeekStart, review.WeekEnd)
+		} else {
+			fmt.Printf("Updated weekly review for %s to %s\n", review.WeekStart, review.WeekEnd)
+		}
+
+		fmt.Printf("ID: %s\n", note.ID)
+		fmt.Printf("Title: %s\n\n", note.Title)
+		fmt.Printf("Notes created:   %d\n", len(review.NotesCreated))
+		fmt.Printf("Notes updated:   %d\n", len(review.NotesUpdated))
+		fmt.Printf("Tasks completed: %d\n", len(review.CompletedTasks))
+		if len(review.TopTags) > 0 {
+			fmt.Printf("Top tag:         %s (%d notes)\n", review.TopTags[0].Name, review.TopTags[0].NoteCount)
+		}
+		fmt.Printf("Resurfaced:      %d forgotten note(s)\n", len(review.Forgotten))
+
+		return nil
+	},
+}
+
+// reviewCardsCmd quizzes the user on the flashcards due today
+var reviewCardsCmd = &cobra.Command{
+	Use:   "cards",
+	Short: "Review the flashcards (Q:: ... A:: ... in notes) due today",
+	Long: `Review the flashcards due today. Each question is shown until Enter reveals the answer,
+then grade how well you remembered it from 0 (blank) to 5 (perfect). Grades below 3 bring
+the card back tomorrow; remembered cards come back after longer and longer intervals (SM-2).
+
+Flashcards are lines like "Q:: What is SM-2? A:: A spaced repetition algorithm" in any note,
+or a Q:: line followed by an A:: line.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		limit, _ := cmd.Flags().GetInt("limit")
+		list, _ := cmd.Flags().GetBool("list")
+
+		due, err := apiClient.GetDueCards(limit)
+		if err != nil {
+			return fmt.Errorf("get due cards: %w", err)
+		}
+		if len(due.Cards) == 0 {
+			fmt.Println("No cards due today")
+			return nil
+		}
+
+		if list {
+			fmt.Printf("%d card(s) due on %s:\n\n", due.Total, due.Today)
+			for _, card := range due.Cards {
+				fmt.Printf("%s  %s (%s)\n", card.ID, card.Question, card.NoteTitle)
+			}
+			return nil
+		}
+
+		reader := bufio.NewReader(os.Stdin)
+		reviewed := 0
+		for i, card := range due.Cards {
+			fmt.Printf("\n[%d/%d] %s\nQ: %s\n", i+1, len(due.Cards), card.NoteTitle, card.Question)
+			fmt.Print("(Enter to show the answer, q to stop) ")
+			if line, _ := reader.ReadString('\n'); strings.TrimSpace(line) == "q" {
+				break
+			}
+			fmt.Printf("A: %s\n", card.Answer)
+
+			grade, ok := promptGrade(reader)
+			if !ok {
+				break
+			}
+			reviewed++
+
+			next, err := apiClient.ReviewCard(card.ID, grade)
+			if err != nil {
+				return fmt.Errorf("review card: %w", err)
+			}
+			if next.DueOn != nil {
+				fmt.Printf("Next review on %s\n", *next.DueOn)
+			}
+		}
+
+		fmt.Printf("\nReviewed %d card(s), %d left today\n", reviewed, due.Total-int64(reviewed))
+		return nil
+	},
+}
+
+// promptGrade asks for a grade from 0 to 5 until one is given; false when the user stops
+func promptGrade(reader *bufio.Reader) (int, bool) {
+	for {
+		fmt.Print("Grade 0-5 (0 blank, 3 hard, 5 perfect; q to stop): ")
+		line, err := reader.ReadString('\n')
+		line = strings.TrimSpace(line)
+		if line == "q" || (err != nil && line == "") {
+			return 0, false
+		}
+		if grade, err := strconv.Atoi(line); err == nil && grade >= 0 && grade <= 5 {
+			return grade, true
+		}
+	}
+}
+
+func init() {
+	reviewCardsCmd.Flags().Int("limit", 20, "Cards to review in this session (max 100)")
+	reviewCardsCmd.Flags().Bool("list", false, "Only list the due cards")
+
+	reviewCmd.AddCommand(reviewWeekCmd)
+	reviewCmd.AddCommand(reviewCardsCmd)
+	rootCmd.AddCommand(reviewCmd)
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+)
+
+var sessionsCmd = &cobra.Command{
+	Use:   "sessions",
+	Short: "View and revoke devices signed in to your account",
+}
+
+// sessionsListCmd lists active sessions
+var sessionsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List devices signed in to your account",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sessions, err := apiClient.ListSessions()
+		if err != nil {
+			return fmt.Errorf("list sessions: %w", err)
+		}
+
+		if len(sessions) == 0 {
+			fmt.Println("No active sessions")
+			return nil
+		}
+
+		fmt.Printf("Found %d session(s):\n\n", len(sessions))
+		for _, session := range sessions {
+			device := session.UserAgent
+			if device == "" {
+				device = "(unknown)"
+			}
+			ip := session.IPAddress
+			if ip == "" {
+				ip = "(unknown)"
+			}
+
+			fmt.Printf("ID: %s\n", session.ID)
+			fmt.Printf("Device: %s\n", device)
+			fmt.Printf("IP: %s\n", ip)
+			fmt.Printf("Created: %s\n", session.CreatedAt.Format("2006-01-02 15:04"))
+			fmt.Printf("Expires: %s\n", session.ExpiresAt.Format("2006-01-02 15:04"))
+			fmt.Println("---")
+		}
+
+		return nil
+	},
+}
+
+// sessionsRevokeCmd signs out one device, or all of them
+var sessionsRevokeCmd = &cobra.Command{
+	Use:   "revoke [id]",
+	Short: "Sign out a device (or all devices with --all)",
+	Long: `Revoke a session so that device can no longer refresh its login.
+
+The device's current access token stays valid until it expires.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		all, _ := cmd.Flags().GetBool("all")
+
+		if all {
+			if len(args) > 0 {
+				return fmt.Errorf("pass either a session ID or --all, not both")
+			}
+			if err := apiClient.RevokeAllSessions(); err != nil {
+				return fmt.Errorf("revoke sessions: %w", err)
+			}
+			fmt.Println("Revoked all sessions")
+			return nil
+		}
+
+		if len(args) == 0 {
+			return fmt.Errorf("session ID is required (see 'kg-cli sessions list')")
+		}
+
+		id, err := uuid.Parse(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid session ID: %w", err)
+		}
+
+		if err := apiClient.RevokeSession(id); err != nil {
+			return fmt.Errorf("revoke session: %w", err)
+		}
+
+		fmt.Println("Session revoked")
+		return nil
+	},
+}
+
+func init() {
+	sessionsRevokeCmd.Flags().Bool("all", false, "Revoke every session, including this one")
+
+	sessionsCmd.AddCommand(sessionsListCmd)
+	sessionsCmd.AddCommand(sessionsRevokeCmd)
+	rootCmd.AddCommand(sessionsCmd)
+}
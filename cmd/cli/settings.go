@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+// settingsCmd shows the account preferences
+var settingsCmd = &cobra.Command{
+	Use:   "settings",
+	Short: "Show or change your account preferences",
+	Long: `Show or change the preferences stored with your account.
+
+They apply to every device: 'note list' and 'note search' use page_size,
+'note create' and 'note import' use default_note_type, 'note daily' resolves
+"today" in timezone, stats count weeks from week_start, writing streaks
+count the days on which you wrote daily_word_goal words, notes not opened
+for forgotten_days are resurfaced on the dashboard and in 'note forgotten',
+and 'capture' appends to the capture_target note (inbox or daily).
+
+Set digest_url to a Slack or Discord incoming webhook URL to get a daily
+digest of the day before (notes created, words written, forgotten notes)
+at digest_hour in your timezone; set it to "" to turn the digest off.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !authState.IsAuthenticated() {
+			return fmt.Errorf("not authenticated. Please run 'kg-cli login'")
+		}
+
+		settings, err := apiClient.GetSettings()
+		if err != nil {
+			return fmt.Errorf("get settings: %w", err)
+		}
+
+		printSettings(settings)
+		return nil
+	},
+}
+
+// settingsSetCmd changes one account preference
+var settingsSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Change a preference (default_note_type, page_size, timezone, week_start, theme, daily_word_goal, forgotten_days, capture_target, digest_url, digest_hour)",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !authState.IsAuthenticated() {
+			return fmt.Errorf("not authenticated. Please run 'kg-cli login'")
+		}
+
+		key, value := args[0], args[1]
+
+		req := &model.UpdateSettingsRequest{}
+		switch key {
+		case "default_note_type":
+			noteType := model.NoteType(value)
+			req.DefaultNoteType = &noteType
+		case "page_size":
+			size, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("page_size must be a number")
+			}
+			req.PageSize = &size
+		case "timezone":
+			req.Timezone = &value
+		case "week_start":
+			req.WeekStart = &value
+		case "theme":
+			req.Theme = &value
+		case "daily_word_goal":
+			goal, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("daily_word_goal must be a number")
+			}
+			req.DailyWordGoal = &goal
+		case "forgotten_days":
+			days, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("forgotten_days must be a number")
+			}
+			req.ForgottenDays = &days
+		case "capture_target":
+			req.CaptureTarget = &value
+		case "digest_url":
+			req.DigestURL = &value
+		case "digest_hour":
+			hour, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("digest_hour must be a number")
+			}
+			req.DigestHour = &hour
+		default:
+			return fmt.Errorf("unknown setting %q (use default_note_type, page_size, timezone, week_start, theme, daily_word_goal, forgotten_days, capture_target, digest_url or digest_hour)", key)
+		}
+
+		settings, err := apiClient.UpdateSettings(req)
+		if err != nil {
+			return fmt.Errorf("update settings: %w", err)
+		}
+
+		fmt.Println("Settings updated")
+		fmt.Println()
+		printSettings(settings)
+		return nil
+	},
+}
+
+// printSettings prints the account preferences
+func printSettings(settings *model.UserSettings) {
+	fmt.Printf("default_note_type: %s\n", settings.DefaultNoteType)
+	fmt.Printf("page_size:         %d\n", settings.PageSize)
+	fmt.Printf("timezone:          %s\n", settings.Timezone)
+	fmt.Printf("week_start:        %s\n", settings.WeekStart)
+	fmt.Printf("theme:             %s\n", settings.Theme)
+	fmt.Printf("daily_word_goal:   %d\n", settings.DailyWordGoal)
+	fmt.Printf("forgotten_days:    %d\n", settings.ForgottenDays)
+	fmt.Printf("capture_target:    %s\n", settings.CaptureTarget)
+	digestURL := "(off)"
+	if settings.DigestURL != nil {
+		digestURL = *settings.DigestURL
+	}
+	fmt.Printf("digest_url:        %s\n", digestURL)
+	fmt.Printf("digest_hour:       %d\n", settings.DigestHour)
+}
+
+func init() {
+	settingsCmd.AddCommand(settingsSetCmd)
+	rootCmd.AddCommand(settingsCmd)
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -26,6 +27,15 @@ var statsCmd = &cobra.Command{
 		fmt.Printf("Total Tags: %d\n", stats.TotalTags)
 		fmt.Printf("Total Links: %d\n", stats.TotalLinks)
 		fmt.Printf("Total Words: %d\n", stats.TotalWords)
+		if len(stats.NotesByType) > 0 {
+			var byType []string
+			for _, noteType := range apiClient.NoteTypes() {
+				if count := stats.NotesByType[noteType.Name]; count > 0 {
+					byType = append(byType, fmt.Sprintf("%s %d", noteType.Name, count))
+				}
+			}
+			fmt.Printf("Notes by Type: %s\n", strings.Join(byType, ", "))
+		}
 		fmt.Printf("Notes Created Today: %d\n", stats.NotesCreatedToday)
 		fmt.Printf("Notes Created This Week: %d\n", stats.NotesCreatedWeek)
 
@@ -35,6 +45,12 @@ var statsCmd = &cobra.Command{
 			fmt.Println("Last Activity: Never")
 		}
 
+		// Servers without word tracking don't have the streak endpoint
+		if streak, err := apiClient.GetWritingStreak(); err == nil {
+			fmt.Printf("Words Today: %d / %d\n", streak.WordsToday, streak.DailyGoal)
+			fmt.Printf("Writing Streak: %d day(s) (longest %d)\n", streak.CurrentStreak, streak.LongestStreak)
+		}
+
 		return nil
 	},
 }
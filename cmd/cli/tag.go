@@ -6,6 +6,8 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/spf13/cobra"
+
+	"github.com/momokii/go-cli-notes/internal/util"
 )
 
 var tagCmd = &cobra.Command{
@@ -112,10 +114,12 @@ var tagListCmd = &cobra.Command{
 		}
 
 		fmt.Printf("Found %d tag(s):\n\n", len(tags))
-		for _, tag := range tags {
-			fmt.Printf("ID: %s\n", tag.ID)
-			fmt.Printf("Name: %s\n", tag.Name)
-			fmt.Println("---")
+		// Nested tags are listed under their parent, indented by depth
+		for _, entry := range util.BuildTagTree(tags) {
+			indent := strings.Repeat("  ", entry.Depth)
+			fmt.Printf("%sID: %s\n", indent, entry.Tag.ID)
+			fmt.Printf("%sName: %s\n", indent, entry.Tag.Name)
+			fmt.Printf("%s---\n", indent)
 		}
 
 		return nil
@@ -181,13 +185,15 @@ var tagDeleteCmd = &cobra.Command{
 		}
 
 		// Confirm deletion
-		fmt.Printf("Are you sure you want to delete this tag? (y/N): ")
-		var confirm string
-		fmt.Scanln(&confirm)
+		if yes, _ := cmd.Flags().GetBool("yes"); !yes {
+			fmt.Printf("Are you sure you want to delete this tag? (y/N): ")
+			var confirm string
+			fmt.Scanln(&confirm)
 
-		if strings.ToLower(confirm) != "y" {
-			fmt.Println("Deletion cancelled")
-			return nil
+			if strings.ToLower(confirm) != "y" {
+				fmt.Println("Deletion cancelled")
+				return nil
+			}
 		}
 
 		if err := apiClient.DeleteTag(id); err != nil {
@@ -290,7 +296,54 @@ var tagRemoveCmd = &cobra.Command{
 	},
 }
 
+// tagAnalyticsCmd shows which tags are growing, going stale and used together
+var tagAnalyticsCmd = &cobra.Command{
+	Use:   "analytics",
+	Short: "Show tag usage over time and tags used together",
+	Long: `Show how many notes each tag was put on per month, whether it is growing,
+steady or stale (unused for 90 days), and the pairs of tags most often put on
+the same notes.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		months, _ := cmd.Flags().GetInt("months")
+		pairs, _ := cmd.Flags().GetInt("pairs")
+
+		analytics, err := apiClient.GetTagAnalytics(months, pairs)
+		if err != nil {
+			return fmt.Errorf("get tag analytics: %w", err)
+		}
+
+		if len(analytics.Tags) == 0 {
+			fmt.Println("No tags yet")
+			return nil
+		}
+
+		if len(analytics.Months) > 0 {
+			fmt.Printf("Notes tagged per month, %s to %s:\n\n", analytics.Months[0], analytics.Months[len(analytics.Months)-1])
+		}
+		for _, usage := range analytics.Tags {
+			counts := make([]string, len(usage.Monthly))
+			for i, count := range usage.Monthly {
+				counts[i] = fmt.Sprintf("%d", count)
+			}
+			fmt.Printf("%-24s %4d notes  %-8s %s\n", usage.Tag.Name, usage.NoteCount, usage.Trend, strings.Join(counts, " "))
+		}
+
+		if len(analytics.Pairs) > 0 {
+			fmt.Println("\nUsed together:")
+			for _, pair := range analytics.Pairs {
+				fmt.Printf("%s + %s: %d notes (%.0f%%)\n", pair.TagA.Name, pair.TagB.Name, pair.NoteCount, pair.Overlap*100)
+			}
+		}
+
+		return nil
+	},
+}
+
 func init() {
+	tagDeleteCmd.Flags().BoolP("yes", "y", false, "Skip the confirmation prompt")
+	tagAnalyticsCmd.Flags().IntP("months", "m", 6, "Number of months up to this one (max 24)")
+	tagAnalyticsCmd.Flags().IntP("pairs", "p", 10, "Number of tag pairs to show (max 50)")
+
 	tagCmd.AddCommand(tagListCmd)
 	tagCmd.AddCommand(tagGetCmd)
 	tagCmd.AddCommand(tagCreateCmd)
@@ -298,5 +351,6 @@ func init() {
 	tagCmd.AddCommand(tagDeleteCmd)
 	tagCmd.AddCommand(tagAddCmd)
 	tagCmd.AddCommand(tagRemoveCmd)
+	tagCmd.AddCommand(tagAnalyticsCmd)
 	rootCmd.AddCommand(tagCmd)
 }
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+)
+
+var taskCmd = &cobra.Command{
+	Use:   "task",
+	Short: "View tasks collected from note checkboxes",
+}
+
+// taskListCmd lists tasks across all notes
+var taskListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List tasks (- [ ] / - [x] items) from all notes",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		status, _ := cmd.Flags().GetString("status")
+
+		tasks, err := apiClient.GetTasks(status)
+		if err != nil {
+			return fmt.Errorf("list tasks: %w", err)
+		}
+
+		if len(tasks) == 0 {
+			fmt.Println("No tasks found")
+			return nil
+		}
+
+		fmt.Printf("Found %d task(s):\n", len(tasks))
+
+		// Tasks come ordered by note, print them grouped under the note title
+		currentNote := uuid.Nil
+		for _, task := range tasks {
+			if task.NoteID != currentNote {
+				currentNote = task.NoteID
+				fmt.Printf("\n%s (%s)\n", task.NoteTitle, task.NoteID)
+			}
+
+			box := "[ ]"
+			if task.Completed {
+				box = "[x]"
+			}
+			fmt.Printf("  %s %s\n", box, task.Content)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	taskListCmd.Flags().StringP("status", "s", "open", "Filter by status (open, done, all)")
+
+	taskCmd.AddCommand(taskListCmd)
+	rootCmd.AddCommand(taskCmd)
+}
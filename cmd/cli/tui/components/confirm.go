@@ -125,41 +125,41 @@ func (c *ConfirmDialog) View() string {
 	// Define styles
 	boxStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#f38ba8")). // Red for warning
+		BorderForeground(CurrentTheme().Error).
 		Padding(1, 2)
 
 	titleStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#fab387")). // Orange
+		Foreground(CurrentTheme().Secondary).
 		Bold(true).
 		MarginBottom(1)
 
 	subtextStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#cdd6f4")).
+		Foreground(CurrentTheme().Foreground).
 		MarginBottom(1)
 
 	yesStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#a6e3a1")). // Green
+		Foreground(CurrentTheme().Accent).
 		Bold(true).
 		Padding(0, 1)
 
 	noStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#6c7086")). // Gray
+		Foreground(CurrentTheme().Muted).
 		Padding(0, 1)
 
 	yesSelectedStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#1e1e2e")). // Dark background
-		Background(lipgloss.Color("#a6e3a1")). // Green
+		Foreground(CurrentTheme().Background).
+		Background(CurrentTheme().Accent).
 		Bold(true).
 		Padding(0, 1)
 
 	noSelectedStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#1e1e2e")). // Dark background
-		Background(lipgloss.Color("#6c7086")). // Gray
+		Foreground(CurrentTheme().Background).
+		Background(CurrentTheme().Muted).
 		Bold(true).
 		Padding(0, 1)
 
 	hintStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#6c7086")).
+		Foreground(CurrentTheme().Muted).
 		Faint(true).
 		MarginTop(1)
 
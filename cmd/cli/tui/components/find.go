@@ -0,0 +1,103 @@
+package components
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// FindMatch is an occurrence of a find query in rendered text
+// Start and End are terminal columns, so they stay valid across styling escape codes.
+type FindMatch struct {
+	Line  int
+	Start int
+	End   int
+}
+
+// findMatchStyle styles the matches of an in-note find
+func findMatchStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(CurrentTheme().Background).
+		Background(CurrentTheme().Warning)
+}
+
+// findCurrentStyle styles the current match of an in-note find
+func findCurrentStyle() lipgloss.Style {
+	return findMatchStyle().
+		Background(CurrentTheme().Secondary).
+		Bold(true)
+}
+
+// FindMatches returns the case-insensitive occurrences of query in rendered text, line by line
+func FindMatches(rendered, query string) []FindMatch {
+	needle := lowerRunes(query)
+	if len(needle) == 0 {
+		return nil
+	}
+
+	var matches []FindMatch
+	for lineNum, line := range strings.Split(rendered, "\n") {
+		text := lowerRunes(ansi.Strip(line))
+		for i := 0; i+len(needle) <= len(text); i++ {
+			if !runesEqual(text[i:i+len(needle)], needle) {
+				continue
+			}
+			start := ansi.StringWidth(string(text[:i]))
+			matches = append(matches, FindMatch{
+				Line:  lineNum,
+				Start: start,
+				End:   start + ansi.StringWidth(string(needle)),
+			})
+			i += len(needle) - 1
+		}
+	}
+	return matches
+}
+
+// HighlightMatches styles every match in rendered text, marking the one at current
+func HighlightMatches(rendered string, matches []FindMatch, current int) string {
+	if len(matches) == 0 {
+		return rendered
+	}
+
+	lines := strings.Split(rendered, "\n")
+	// Work from the last match back so earlier columns on a line stay valid
+	for i := len(matches) - 1; i >= 0; i-- {
+		match := matches[i]
+		if match.Line >= len(lines) {
+			continue
+		}
+
+		style := findMatchStyle()
+		if i == current {
+			style = findCurrentStyle()
+		}
+
+		line := lines[match.Line]
+		lines[match.Line] = ansi.Cut(line, 0, match.Start) +
+			style.Render(ansi.Strip(ansi.Cut(line, match.Start, match.End))) +
+			ansi.Cut(line, match.End, ansi.StringWidth(line))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// lowerRunes returns s as lower-cased runes, one per rune of s
+func lowerRunes(s string) []rune {
+	runes := []rune(s)
+	for i := range runes {
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return runes
+}
+
+// runesEqual reports whether two rune slices hold the same runes
+func runesEqual(a, b []rune) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
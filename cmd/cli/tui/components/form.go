@@ -9,9 +9,12 @@ import (
 type FormField struct {
 	ID          string
 	Label       string
-	InputType   FieldType // TextInput or Textarea
+	InputType   FieldType // TextInput, Textarea or Select
 	textInput   TextInput
 	textarea    Textarea
+	options     []string // For select only
+	selected    int      // For select only
+	focused     bool     // For select only
 	Required    bool
 	MinLength   int
 	MaxLength   int
@@ -26,6 +29,7 @@ type FieldType int
 const (
 	FieldInput FieldType = iota
 	FieldTextarea
+	FieldSelect // One of a few options, switched with left/right
 )
 
 // NewFormField creates a new form field
@@ -37,10 +41,11 @@ func NewFormField(id, label string, fieldType FieldType) FormField {
 		Required:  false,
 	}
 
-	if fieldType == FieldInput {
+	switch fieldType {
+	case FieldInput:
 		f.textInput = NewTextInput()
 		f.textInput.SetPrompt(label + ": ")
-	} else {
+	case FieldTextarea:
 		f.textarea = NewTextarea()
 		f.textarea.SetPlaceholder(label)
 	}
@@ -48,66 +53,131 @@ func NewFormField(id, label string, fieldType FieldType) FormField {
 	return f
 }
 
+// SetOptions sets the options of a select field, selecting the first one
+func (f *FormField) SetOptions(options []string) {
+	f.options = options
+	f.selected = 0
+}
+
 // SetValue sets the value of the field
+// A select field keeps its selection when the value isn't one of its options.
 func (f *FormField) SetValue(value string) {
-	if f.InputType == FieldInput {
+	switch f.InputType {
+	case FieldInput:
 		f.textInput.SetValue(value)
-	} else {
+	case FieldTextarea:
 		f.textarea.SetValue(value)
+	case FieldSelect:
+		for i, option := range f.options {
+			if option == value {
+				f.selected = i
+			}
+		}
 	}
 }
 
 // Value returns the current value of the field
 func (f *FormField) Value() string {
-	if f.InputType == FieldInput {
+	switch f.InputType {
+	case FieldInput:
 		return f.textInput.Value()
+	case FieldSelect:
+		if len(f.options) == 0 {
+			return ""
+		}
+		return f.options[f.selected]
 	}
 	return f.textarea.Value()
 }
 
 // Focus sets focus on this field
 func (f *FormField) Focus() {
-	if f.InputType == FieldInput {
+	switch f.InputType {
+	case FieldInput:
 		f.textInput.Focus()
-	} else {
+	case FieldTextarea:
 		f.textarea.Focus()
+	case FieldSelect:
+		f.focused = true
 	}
 }
 
 // Blur removes focus from this field
 func (f *FormField) Blur() {
-	if f.InputType == FieldInput {
+	switch f.InputType {
+	case FieldInput:
 		f.textInput.Blur()
-	} else {
+	case FieldTextarea:
 		f.textarea.Blur()
+	case FieldSelect:
+		f.focused = false
 	}
 }
 
 // Focused returns whether this field is focused
 func (f *FormField) Focused() bool {
-	if f.InputType == FieldInput {
+	switch f.InputType {
+	case FieldInput:
 		return f.textInput.Focused()
+	case FieldSelect:
+		return f.focused
 	}
 	return f.textarea.Focused()
 }
 
 // Validate validates the field value
+// A select field always holds one of its options.
 func (f *FormField) Validate() error {
-	if f.InputType == FieldInput {
+	switch f.InputType {
+	case FieldInput:
 		return f.textInput.Validate(f.Required, f.MinLength, f.MaxLength)
+	case FieldSelect:
+		return nil
 	}
 	return f.textarea.Validate(f.Required, f.MinWords)
 }
 
 // SetPlaceholder sets the placeholder for the field
 func (f *FormField) SetPlaceholder(text string) {
-	if f.InputType == FieldInput {
+	switch f.InputType {
+	case FieldInput:
 		f.textInput.SetPlaceholder(text)
-	} else {
+	case FieldTextarea:
 		f.textarea.SetPlaceholder(text)
 	}
 }
 
+// selectOption moves the selection of a select field by step, wrapping around
+func (f *FormField) selectOption(step int) {
+	if len(f.options) == 0 {
+		return
+	}
+	f.selected = (f.selected + step + len(f.options)) % len(f.options)
+}
+
+// selectView renders a select field with its options in a row
+func (f *FormField) selectView() string {
+	labelStyle := lipgloss.NewStyle().Foreground(CurrentTheme().Muted)
+	if f.focused {
+		labelStyle = lipgloss.NewStyle().Foreground(CurrentTheme().Accent)
+	}
+	optionStyle := lipgloss.NewStyle().Foreground(CurrentTheme().Muted)
+	selectedStyle := lipgloss.NewStyle().Foreground(CurrentTheme().Primary).Bold(true)
+
+	view := labelStyle.Render(f.Label + ": ")
+	for i, option := range f.options {
+		if i > 0 {
+			view += " "
+		}
+		if i == f.selected {
+			view += selectedStyle.Render("[" + option + "]")
+		} else {
+			view += optionStyle.Render(" " + option + " ")
+		}
+	}
+	return view
+}
+
 // Form represents a multi-field form
 type Form struct {
 	fields     []FormField
@@ -177,9 +247,10 @@ func (f *Form) CurrentIndex() int {
 func (f *Form) SetWidth(width int) {
 	f.width = width
 	for _, field := range f.fields {
-		if field.InputType == FieldInput {
+		switch field.InputType {
+		case FieldInput:
 			field.textInput.SetWidth(width - 20) // Account for label
-		} else {
+		case FieldTextarea:
 			field.textarea.SetWidth(width)
 		}
 	}
@@ -263,12 +334,33 @@ func (f *Form) Update(msg tea.Msg) tea.Cmd {
 
 	// Update current field
 	field := &f.fields[f.currentIdx]
-	if field.InputType == FieldInput {
+	switch field.InputType {
+	case FieldInput:
 		return field.textInput.Update(msg)
+	case FieldSelect:
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			switch msg.String() {
+			case "left", "h":
+				field.selectOption(-1)
+			case "right", "l", " ":
+				field.selectOption(1)
+			}
+		}
+		return nil
 	}
 	return field.textarea.Update(msg)
 }
 
+// VimInsertMode returns whether the current field is a textarea in vim insert mode
+// Esc then returns to normal mode instead of leaving the form.
+func (f *Form) VimInsertMode() bool {
+	if len(f.fields) == 0 || f.fields[f.currentIdx].InputType != FieldTextarea {
+		return false
+	}
+	mode, ok := f.fields[f.currentIdx].textarea.VimEditMode()
+	return ok && mode == VimInsert
+}
+
 // View renders the form
 func (f *Form) View() string {
 	if len(f.fields) == 0 {
@@ -278,21 +370,23 @@ func (f *Form) View() string {
 	var content string
 
 	labelStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#89b4fa")). // Blue
+		Foreground(CurrentTheme().Primary).
 		Bold(true)
 
 	errorStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#f38ba8")). // Red
+		Foreground(CurrentTheme().Error).
 		Italic(true)
 
 	hintStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#6c7086")). // Gray
+		Foreground(CurrentTheme().Muted).
 		Faint(true)
 
 	for _, field := range f.fields {
 		// Render field
 		if field.InputType == FieldInput {
 			content += field.textInput.View()
+		} else if field.InputType == FieldSelect {
+			content += field.selectView()
 		} else {
 			// For textarea, show label above it
 			label := labelStyle.Render(field.Label)
@@ -313,10 +407,15 @@ func (f *Form) View() string {
 
 	// Add hints at the bottom
 	hints := "TAB:next Shift+TAB:prev"
+	if current := f.CurrentField(); f.focused && current.InputType == FieldSelect {
+		hints += " ←→:change"
+	}
 	if f.submitText != "" {
 		hints += " Enter:" + f.submitText
 	}
-	if f.cancelText != "" {
+	if f.VimInsertMode() {
+		hints += " ESC:normal mode"
+	} else if f.cancelText != "" {
 		hints += " ESC:" + f.cancelText
 	}
 	content += hintStyle.Render(hints)
@@ -0,0 +1,352 @@
+package components
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/momokii/go-cli-notes/internal/util"
+)
+
+var (
+	headingPattern    = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	listItemPattern   = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+	taskPattern       = regexp.MustCompile(`^\[([ xX])\]\s+(.*)$`)
+	rulePattern       = regexp.MustCompile(`^\s*([-*_])(\s*([-*_])){2,}\s*$`)
+	blockquotePattern = regexp.MustCompile(`^\s*>\s?(.*)$`)
+
+	// Inline spans: `code`, [[wiki link]], **bold**, *italic*, [text](url)
+	inlinePattern = regexp.MustCompile("(`[^`]+`)" +
+		`|(\[\[[^\]|]+(?:\|[^\]]+)?\]\])` +
+		`|(\*\*[^*]+\*\*)` +
+		`|(\*[^*\s][^*]*\*)` +
+		`|(\[[^\]]+\]\([^)]+\))`)
+	mdLinkPattern = regexp.MustCompile(`\[([^\]]+)\]\(([^)]+)\)`)
+
+	linkParser = util.NewLinkParser()
+)
+
+// MarkdownRenderer renders note markdown for the terminal using lipgloss styles
+type MarkdownRenderer struct {
+	width        int
+	selectedLink int // Index of the highlighted wiki link (-1 = none)
+}
+
+// WikiLink is a [[wiki link]] found in rendered content
+type WikiLink struct {
+	Title   string // Target note title, empty for a heading or block of the same note
+	Heading string // Heading linked to, [[Note#Heading]]
+	Block   string // Block id linked to, [[Note^block-id]]
+	Display string // Text shown in the content
+	Line    int    // Rendered line the link's block starts on
+}
+
+// Names reports whether the link names a note titled title, ignoring case and spacing like the server
+func (l WikiLink) Names(title string) bool {
+	return l.Title != "" && linkParser.NormalizeTitle(l.Title) == linkParser.NormalizeTitle(title)
+}
+
+// NewMarkdownRenderer creates a new markdown renderer
+func NewMarkdownRenderer(width int) MarkdownRenderer {
+	return MarkdownRenderer{width: width, selectedLink: -1}
+}
+
+// SetWidth sets the wrap width
+func (r *MarkdownRenderer) SetWidth(width int) {
+	r.width = width
+}
+
+// SetSelectedLink highlights the wiki link at index (-1 clears the selection)
+func (r *MarkdownRenderer) SetSelectedLink(index int) {
+	r.selectedLink = index
+}
+
+// Render renders markdown content to styled terminal text
+func (r MarkdownRenderer) Render(content string) string {
+	out, _, _ := r.render(content)
+	return out
+}
+
+// Links returns the wiki links in content in the order they are rendered
+// Links inside code are ignored.
+func (r MarkdownRenderer) Links(content string) []WikiLink {
+	_, links, _ := r.render(content)
+	return links
+}
+
+// AnchorLine returns the rendered line of the heading or block a link points to
+// Headings match by their anchor, so case and punctuation don't matter; a block wins over a heading.
+func (r MarkdownRenderer) AnchorLine(content, heading, block string) (int, bool) {
+	_, _, anchors := r.render(content)
+	if block != "" {
+		line, ok := anchors["^"+block]
+		return line, ok
+	}
+	if heading != "" {
+		line, ok := anchors["#"+util.HeadingAnchor(heading)]
+		return line, ok
+	}
+	return 0, false
+}
+
+// render renders content and collects the wiki links it contains, and the line of each
+// heading ("#" and its anchor) and block ("^" and its id)
+func (r MarkdownRenderer) render(content string) (string, []WikiLink, map[string]int) {
+	width := r.width
+	if width < 20 {
+		width = 20
+	}
+
+	h1Style := lipgloss.NewStyle().
+		Foreground(CurrentTheme().Secondary).
+		Bold(true).
+		Underline(true)
+
+	h2Style := lipgloss.NewStyle().
+		Foreground(CurrentTheme().Warning).
+		Bold(true)
+
+	h3Style := lipgloss.NewStyle().
+		Foreground(CurrentTheme().Primary).
+		Bold(true)
+
+	bulletStyle := lipgloss.NewStyle().
+		Foreground(CurrentTheme().Primary)
+
+	doneStyle := lipgloss.NewStyle().
+		Foreground(CurrentTheme().Accent)
+
+	quoteStyle := lipgloss.NewStyle().
+		Foreground(CurrentTheme().Muted).
+		Italic(true)
+
+	ruleStyle := lipgloss.NewStyle().
+		Foreground(CurrentTheme().Border)
+
+	codeBlockStyle := lipgloss.NewStyle().
+		Foreground(CurrentTheme().Accent).
+		Background(CurrentTheme().Selected)
+
+	langStyle := lipgloss.NewStyle().
+		Foreground(CurrentTheme().Muted).
+		Faint(true)
+
+	textStyle := lipgloss.NewStyle().
+		Foreground(CurrentTheme().Foreground)
+
+	var out []string
+	var links []WikiLink
+	anchors := map[string]int{}
+	inCode := false
+	paragraph := -1 // Line the current paragraph starts on, a block id names the whole paragraph
+
+	// anchor records the first line a heading or block is on
+	anchor := func(key string, line int) {
+		if _, ok := anchors[key]; !ok {
+			anchors[key] = line
+		}
+	}
+
+	// inline renders inline spans, recording wiki links against the current line
+	inline := func(text string, base lipgloss.Style) string {
+		return r.renderInline(text, base, len(out), &links)
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		// Fenced code blocks are rendered verbatim
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inCode = !inCode
+			if inCode {
+				if lang := strings.Trim(trimmed, "`~ "); lang != "" {
+					out = append(out, langStyle.Render("  "+lang))
+				}
+			}
+			continue
+		}
+		if inCode {
+			out = append(out, "  "+codeBlockStyle.Render(" "+expandTabs(line)+" "))
+			continue
+		}
+
+		if trimmed == "" {
+			paragraph = -1
+			out = append(out, "")
+			continue
+		}
+
+		// The " ^block-id" naming a block isn't shown
+		var blockID string
+		line, blockID = util.BlockID(line)
+		trimmed = strings.TrimSpace(line)
+
+		if m := headingPattern.FindStringSubmatch(trimmed); m != nil {
+			paragraph = -1
+			anchor("#"+util.HeadingAnchor(m[2]), len(out))
+			if blockID != "" {
+				anchor("^"+blockID, len(out))
+			}
+			style := h3Style
+			switch len(m[1]) {
+			case 1:
+				style = h1Style
+			case 2:
+				style = h2Style
+			}
+			out = append(out, r.wrap("", "", inline(m[2], style), width)...)
+			continue
+		}
+
+		if rulePattern.MatchString(line) {
+			out = append(out, ruleStyle.Render(strings.Repeat("─", width)))
+			continue
+		}
+
+		if blockID != "" {
+			start := len(out)
+			if paragraph >= 0 && !listItemPattern.MatchString(line) {
+				start = paragraph
+			}
+			anchor("^"+blockID, start)
+		}
+
+		if m := blockquotePattern.FindStringSubmatch(line); m != nil {
+			prefix := quoteStyle.Render("│ ")
+			out = append(out, r.wrap(prefix, "  ", inline(m[1], quoteStyle), width)...)
+			continue
+		}
+
+		if m := listItemPattern.FindStringSubmatch(line); m != nil {
+			indent := strings.Repeat(" ", len(expandTabs(m[1])))
+			marker := m[2]
+			text := m[3]
+
+			var prefix string
+			if t := taskPattern.FindStringSubmatch(text); t != nil {
+				if t[1] == " " {
+					prefix = bulletStyle.Render("☐ ")
+				} else {
+					prefix = doneStyle.Render("☑ ")
+				}
+				text = t[2]
+			} else if marker == "-" || marker == "*" || marker == "+" {
+				prefix = bulletStyle.Render("• ")
+			} else {
+				prefix = bulletStyle.Render(marker + " ")
+			}
+
+			hanging := indent + strings.Repeat(" ", lipgloss.Width(prefix))
+			out = append(out, r.wrap(indent+prefix, hanging, inline(text, textStyle), width)...)
+			continue
+		}
+
+		if paragraph < 0 {
+			paragraph = len(out)
+		}
+		out = append(out, r.wrap("", "", inline(trimmed, textStyle), width)...)
+	}
+
+	return strings.Join(out, "\n"), links, anchors
+}
+
+// wrap word-wraps styled text, prefixing the first line and indenting the rest
+func (r MarkdownRenderer) wrap(first, rest, text string, width int) []string {
+	return WrapLines(first, rest, text, width)
+}
+
+// WrapLines word-wraps styled text to width, prefixing the first line and indenting the rest
+// The prefixes count towards width; lines are never narrower than 10 columns of text.
+func WrapLines(first, rest, text string, width int) []string {
+	textWidth := width - lipgloss.Width(first)
+	if textWidth < 10 {
+		textWidth = 10
+	}
+
+	wrapped := strings.Split(lipgloss.NewStyle().Width(textWidth).Render(text), "\n")
+	lines := make([]string, 0, len(wrapped))
+	for i, l := range wrapped {
+		l = strings.TrimRight(l, " ")
+		if i == 0 {
+			lines = append(lines, first+l)
+		} else {
+			lines = append(lines, rest+l)
+		}
+	}
+	return lines
+}
+
+// renderInline applies inline markdown styles (code, wiki links, emphasis, links)
+// on top of base and appends any wiki links found to links
+func (r MarkdownRenderer) renderInline(text string, base lipgloss.Style, line int, links *[]WikiLink) string {
+	codeStyle := lipgloss.NewStyle().
+		Foreground(CurrentTheme().Accent).
+		Background(CurrentTheme().Selected)
+
+	wikiStyle := lipgloss.NewStyle().
+		Foreground(CurrentTheme().Special).
+		Bold(true)
+
+	selectedWikiStyle := lipgloss.NewStyle().
+		Foreground(CurrentTheme().Background).
+		Background(CurrentTheme().Special).
+		Bold(true)
+
+	boldStyle := base.Bold(true)
+	italicStyle := base.Italic(true)
+
+	linkStyle := lipgloss.NewStyle().
+		Foreground(CurrentTheme().Primary).
+		Underline(true)
+
+	var sb strings.Builder
+	last := 0
+	for _, m := range inlinePattern.FindAllStringSubmatchIndex(text, -1) {
+		if m[0] > last {
+			sb.WriteString(base.Render(text[last:m[0]]))
+		}
+		span := text[m[0]:m[1]]
+
+		switch {
+		case m[2] >= 0:
+			sb.WriteString(codeStyle.Render(strings.Trim(span, "`")))
+		case m[4] >= 0:
+			link := parseWikiLink(span)
+			link.Line = line
+			style := wikiStyle
+			if len(*links) == r.selectedLink {
+				style = selectedWikiStyle
+			}
+			*links = append(*links, link)
+			sb.WriteString(style.Render("[[" + link.Display + "]]"))
+		case m[6] >= 0:
+			sb.WriteString(boldStyle.Render(strings.Trim(span, "*")))
+		case m[8] >= 0:
+			sb.WriteString(italicStyle.Render(strings.Trim(span, "*")))
+		case m[10] >= 0:
+			sub := mdLinkPattern.FindStringSubmatch(span)
+			sb.WriteString(linkStyle.Render(sub[1]))
+		}
+		last = m[1]
+	}
+	if last < len(text) {
+		sb.WriteString(base.Render(text[last:]))
+	}
+
+	return sb.String()
+}
+
+// parseWikiLink returns the target and display text of a [[Title#Heading|Display]] link
+func parseWikiLink(span string) WikiLink {
+	parsed := linkParser.ExtractLinks(span)
+	if len(parsed) == 0 {
+		return WikiLink{Title: span, Display: span}
+	}
+	link := parsed[0]
+	return WikiLink{Title: link.Title, Heading: link.Heading, Block: link.Block, Display: link.Display}
+}
+
+// expandTabs replaces tabs with spaces so widths are measured consistently
+func expandTabs(s string) string {
+	return strings.ReplaceAll(s, "\t", "    ")
+}
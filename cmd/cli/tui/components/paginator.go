@@ -19,8 +19,8 @@ func NewPaginator() Paginator {
 	p := paginator.New()
 	p.Type = paginator.Dots
 	p.PerPage = 20
-	p.ActiveDot = lipgloss.NewStyle().Foreground(lipgloss.Color("#89b4fa")).Render("•") // Blue
-	p.InactiveDot = lipgloss.NewStyle().Foreground(lipgloss.Color("#6c7086")).Render("•") // Gray
+	p.ActiveDot = lipgloss.NewStyle().Foreground(CurrentTheme().Primary).Render("•")
+	p.InactiveDot = lipgloss.NewStyle().Foreground(CurrentTheme().Muted).Render("•")
 
 	return Paginator{
 		paginator: p,
@@ -114,7 +114,7 @@ func (p *Paginator) View() string {
 	total := p.TotalPages()
 
 	style := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#6c7086")). // Gray
+		Foreground(CurrentTheme().Muted).
 		Faint(true)
 
 	info := style.Render(fmt.Sprintf("Page %d of %d", current, total))
@@ -131,7 +131,7 @@ func (p *Paginator) View() string {
 	var navText string
 	if len(navHints) > 0 {
 		navStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#89b4fa")) // Blue
+			Foreground(CurrentTheme().Primary)
 		navText = " | " + navStyle.Render(navHints[0])
 		if len(navHints) > 1 {
 			navText += " " + navStyle.Render(navHints[1])
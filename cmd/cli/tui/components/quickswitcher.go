@@ -0,0 +1,230 @@
+package components
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sahilm/fuzzy"
+)
+
+// CommandPrefix starts a query that matches commands instead of titles, as in "> theme"
+const CommandPrefix = ">"
+
+// QuickSwitcher is a fuzzy-matching picker over a list of titles
+// Starting the query with CommandPrefix turns it into a command palette.
+type QuickSwitcher struct {
+	input      TextInput
+	items      []string
+	commands   []string
+	matches    []fuzzy.Match
+	selected   int
+	maxResults int
+	width      int
+}
+
+// NewQuickSwitcher creates a new quick switcher component
+func NewQuickSwitcher() QuickSwitcher {
+	input := NewTextInput()
+	input.SetPrompt("> ")
+	input.SetPlaceholder("Jump to note...")
+	input.SetWidth(50)
+
+	return QuickSwitcher{
+		input:      input,
+		selected:   0,
+		maxResults: 10,
+		width:      60,
+	}
+}
+
+// SetItems sets the titles to match against, keeping the current query
+func (q *QuickSwitcher) SetItems(items []string) {
+	q.items = items
+	q.refresh()
+}
+
+// SetCommands sets the commands matched in command mode
+func (q *QuickSwitcher) SetCommands(commands []string) {
+	q.commands = commands
+	q.refresh()
+}
+
+// InCommandMode reports whether the query is matching commands
+func (q *QuickSwitcher) InCommandMode() bool {
+	return strings.HasPrefix(q.input.Value(), CommandPrefix)
+}
+
+// SetWidth sets the width of the switcher box
+func (q *QuickSwitcher) SetWidth(width int) {
+	q.width = width
+	q.input.SetWidth(width - 10)
+}
+
+// Focus focuses the query input
+func (q *QuickSwitcher) Focus() {
+	q.input.Focus()
+}
+
+// Reset clears the query and selection
+func (q *QuickSwitcher) Reset() {
+	q.input.SetValue("")
+	q.selected = 0
+	q.refresh()
+}
+
+// Query returns the current query
+func (q *QuickSwitcher) Query() string {
+	return q.input.Value()
+}
+
+// Selected returns the index into items (or commands, in command mode) of the highlighted match, or -1 if none
+func (q *QuickSwitcher) Selected() int {
+	if q.selected < 0 || q.selected >= len(q.matches) {
+		return -1
+	}
+	return q.matches[q.selected].Index
+}
+
+// refresh recomputes matches for the current query
+func (q *QuickSwitcher) refresh() {
+	query, items := q.input.Value(), q.items
+	if q.InCommandMode() {
+		query, items = strings.TrimSpace(strings.TrimPrefix(query, CommandPrefix)), q.commands
+	}
+
+	if query == "" {
+		// Show items in their original order (most recent first)
+		q.matches = make([]fuzzy.Match, 0, len(items))
+		for i, item := range items {
+			q.matches = append(q.matches, fuzzy.Match{Str: item, Index: i})
+		}
+	} else {
+		q.matches = fuzzy.Find(query, items)
+	}
+
+	if q.selected >= len(q.matches) {
+		q.selected = len(q.matches) - 1
+	}
+	if q.selected < 0 {
+		q.selected = 0
+	}
+}
+
+// Update handles key messages for the switcher
+func (q *QuickSwitcher) Update(msg tea.Msg) tea.Cmd {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "up", "ctrl+p":
+			if q.selected > 0 {
+				q.selected--
+			}
+			return nil
+		case "down", "ctrl+n":
+			if q.selected < len(q.matches)-1 {
+				q.selected++
+			}
+			return nil
+		}
+	}
+
+	before := q.input.Value()
+	cmd := q.input.Update(msg)
+	if q.input.Value() != before {
+		q.selected = 0
+		q.refresh()
+	}
+	return cmd
+}
+
+// View renders the switcher box
+func (q *QuickSwitcher) View() string {
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(CurrentTheme().Special).
+		Padding(0, 1).
+		Width(q.width)
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(CurrentTheme().Special).
+		Bold(true)
+
+	itemStyle := lipgloss.NewStyle().
+		Foreground(CurrentTheme().Foreground)
+
+	matchStyle := lipgloss.NewStyle().
+		Foreground(CurrentTheme().Secondary).
+		Bold(true)
+
+	selectedStyle := lipgloss.NewStyle().
+		Foreground(CurrentTheme().Background).
+		Background(CurrentTheme().Primary).
+		Bold(true)
+
+	mutedStyle := lipgloss.NewStyle().
+		Foreground(CurrentTheme().Muted).
+		Faint(true)
+
+	title, empty, hint := "Quick Switcher", "(no matching notes)", "↑↓:select Enter:open >:commands ESC:close"
+	if q.InCommandMode() {
+		title, empty, hint = "Command Palette", "(no matching commands)", "↑↓:select Enter:run ESC:close"
+	}
+
+	var content string
+	content += titleStyle.Render(title) + "\n"
+	content += q.input.View() + "\n\n"
+
+	if len(q.matches) == 0 {
+		content += mutedStyle.Render(empty)
+	}
+
+	// Scroll the result window so the selection stays visible
+	start := 0
+	if q.selected >= q.maxResults {
+		start = q.selected - q.maxResults + 1
+	}
+	end := min(start+q.maxResults, len(q.matches))
+
+	maxTitle := q.width - 6
+	for i := start; i < end; i++ {
+		match := q.matches[i]
+		title := []rune(match.Str)
+		if len(title) > maxTitle {
+			title = append(title[:maxTitle-1], '…')
+		}
+
+		if i == q.selected {
+			content += selectedStyle.Render("→ " + string(title))
+		} else {
+			content += "  " + highlightMatches(title, match.MatchedIndexes, itemStyle, matchStyle)
+		}
+		if i < end-1 {
+			content += "\n"
+		}
+	}
+
+	content += "\n\n" + mutedStyle.Render(hint)
+
+	return boxStyle.Render(content)
+}
+
+// highlightMatches styles the matched characters of a fuzzy match
+// MatchedIndexes are byte offsets into the original string.
+func highlightMatches(title []rune, matched []int, base, highlight lipgloss.Style) string {
+	hits := make(map[int]bool, len(matched))
+	for _, idx := range matched {
+		hits[idx] = true
+	}
+
+	var out string
+	offset := 0
+	for _, r := range title {
+		if hits[offset] {
+			out += highlight.Render(string(r))
+		} else {
+			out += base.Render(string(r))
+		}
+		offset += len(string(r))
+	}
+	return out
+}
@@ -14,6 +14,8 @@ type StatusBar struct {
 	errorMsg  string
 	showInfo  bool
 	infoMsg   string
+	action    string // Short-lived action offered to the user, such as undo
+	focus     string // Running focus timer, shown before the user info
 }
 
 // NewStatusBar creates a new status bar
@@ -56,6 +58,18 @@ func (s *StatusBar) ShowInfo(msg string) {
 	s.infoMsg = msg
 }
 
+// SetAction offers an action, such as undo, until it is set to ""
+// It is shown next to info messages and in place of the key help; clearing messages keeps it.
+func (s *StatusBar) SetAction(action string) {
+	s.action = action
+}
+
+// SetFocus shows a running focus timer until it is set to ""
+// Unlike messages and actions it doesn't hide anything, so it stays in view while the timer runs.
+func (s *StatusBar) SetFocus(focus string) {
+	s.focus = focus
+}
+
 // ClearError clears the error message
 func (s *StatusBar) ClearError() {
 	s.showError = false
@@ -69,30 +83,57 @@ func (s *StatusBar) View() string {
 	if s.showError && s.errorMsg != "" {
 		// Show error message in red
 		errorStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#f38ba8")). // Red
+			Foreground(CurrentTheme().Error).
 			Bold(true)
 		errorMsg := errorStyle.Render("⚠ " + s.errorMsg)
-		return renderStatusBar("Error", errorMsg, s.userInfo, s.width)
+		return renderStatusBar("Error", errorMsg, s.rightView(), s.width)
 	}
 
 	if s.showInfo && s.infoMsg != "" {
 		// Show info message in green
 		infoStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#a6e3a1")). // Green
+			Foreground(CurrentTheme().Accent).
 			Bold(true)
 		infoMsg := infoStyle.Render("✓ " + s.infoMsg)
-		return renderStatusBar("Info", infoMsg, s.userInfo, s.width)
+		if s.action != "" {
+			infoMsg += "  " + s.actionView()
+		}
+		return renderStatusBar("Info", infoMsg, s.rightView(), s.width)
+	}
+
+	if s.action != "" {
+		return renderStatusBar(s.viewName, s.actionView(), s.rightView(), s.width)
 	}
 
-	return renderStatusBar(s.viewName, s.keyHelp, s.userInfo, s.width)
+	return renderStatusBar(s.viewName, s.keyHelp, s.rightView(), s.width)
+}
+
+// rightView renders the focus timer, if one is running, and the user info
+func (s *StatusBar) rightView() string {
+	if s.focus == "" {
+		return s.userInfo
+	}
+	focus := lipgloss.NewStyle().
+		Foreground(CurrentTheme().Accent).
+		Bold(true).
+		Render(s.focus)
+	return focus + "  " + s.userInfo
+}
+
+// actionView renders the offered action
+func (s *StatusBar) actionView() string {
+	return lipgloss.NewStyle().
+		Foreground(CurrentTheme().Warning).
+		Bold(true).
+		Render(s.action)
 }
 
 // renderStatusBar renders the status bar with the given content
 func renderStatusBar(view string, keyHelp string, userInfo string, width int) string {
 	// Define colors
-	colorPrimary := lipgloss.Color("#89b4fa")   // Blue
-	colorMuted := lipgloss.Color("#6c7086")    // Gray
-	colorSelected := lipgloss.Color("#313244")  // Selected background
+	colorPrimary := CurrentTheme().Primary
+	colorMuted := CurrentTheme().Muted
+	colorSelected := CurrentTheme().Selected
 
 	// Create styles
 	leftStyle := lipgloss.NewStyle().Foreground(colorPrimary).Bold(true)
@@ -1,52 +1,51 @@
 package components
 
 import (
-	"github.com/charmbracelet/bubbles/list"
+	"strings"
+
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
+// TableColumn is a column of the table
+type TableColumn struct {
+	Title string
+	Width int // Fixed width; 0 takes the width left over by the other columns
+}
+
 // TableRow represents a single row in the table
 type TableRow struct {
-	Title       string
-	Description string
-	Metadata    string // Additional info to display (e.g., date, tags)
-	ID          string // Unique identifier for the row
+	ID          string   // Unique identifier for the row
+	Cells       []string // One value per column
+	Description string   // Shown under the row when descriptions are on
 }
 
-// Table wraps the Bubbles list component for displaying tabular data
+// columnGap is the space between two columns
+const columnGap = 2
+
+// Table renders rows in fixed-width columns under a header row
 type Table struct {
-	list     list.Model
+	columns  []TableColumn
+	rows     []TableRow
+	cursor   int
+	offset   int // First visible row
 	width    int
 	height   int
-	showDesc bool // Whether to show description column
+	showDesc bool // Whether to show the description under each row
+	// Column the rows are sorted by, -1 for none; shown with an arrow in the header
+	sortColumn int
+	sortDesc   bool
 }
 
 // NewTable creates a new table component
-func NewTable() Table {
-	// Define list styles
-	delegate := list.NewDefaultDelegate()
-	delegate.ShowDescription = false
-	delegate.Styles.SelectedTitle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#1e1e2e")). // Background color
-		Background(lipgloss.Color("#89b4fa")). // Blue
-		Bold(true)
-	delegate.Styles.SelectedDesc = delegate.Styles.SelectedTitle.Foreground(lipgloss.Color("#cdd6f4"))
-	delegate.Styles.NormalTitle = lipgloss.NewStyle().Foreground(lipgloss.Color("#cdd6f4"))
-	delegate.Styles.NormalDesc = lipgloss.NewStyle().Foreground(lipgloss.Color("#6c7086")).Faint(true)
-	delegate.SetSpacing(0)
-
-	l := list.New([]list.Item{}, delegate, 0, 0)
-	l.SetShowHelp(false)
-	l.SetFilteringEnabled(false)
-	l.SetShowPagination(false)
-	l.SetShowStatusBar(false)
-
+func NewTable(columns []TableColumn) Table {
 	return Table{
-		list:     l,
-		width:    80,
-		height:   20,
-		showDesc: true,
+		columns:    columns,
+		width:      80,
+		height:     20,
+		showDesc:   false,
+		sortColumn: -1,
 	}
 }
 
@@ -54,28 +53,19 @@ func NewTable() Table {
 func (t *Table) SetSize(width, height int) {
 	t.width = width
 	t.height = height
-	t.list.SetSize(width, height)
+	t.scroll()
 }
 
 // SetShowDescription sets whether to show the description
 func (t *Table) SetShowDescription(show bool) {
 	t.showDesc = show
-	t.list.SetDelegate(t.getDelegate())
+	t.scroll()
 }
 
-// getDelegate returns the delegate based on current settings
-func (t *Table) getDelegate() list.DefaultDelegate {
-	delegate := list.NewDefaultDelegate()
-	delegate.ShowDescription = t.showDesc
-	delegate.Styles.SelectedTitle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#1e1e2e")). // Background color
-		Background(lipgloss.Color("#89b4fa")). // Blue
-		Bold(true)
-	delegate.Styles.SelectedDesc = delegate.Styles.SelectedTitle.Copy().Foreground(lipgloss.Color("#cdd6f4"))
-	delegate.Styles.NormalTitle = lipgloss.NewStyle().Foreground(lipgloss.Color("#cdd6f4"))
-	delegate.Styles.NormalDesc = lipgloss.NewStyle().Foreground(lipgloss.Color("#6c7086")).Faint(true)
-	delegate.SetSpacing(0)
-	return delegate
+// SetSort marks the column the rows are sorted by in the header (-1 for none)
+func (t *Table) SetSort(column int, desc bool) {
+	t.sortColumn = column
+	t.sortDesc = desc
 }
 
 // SetItems sets the items in the table
@@ -83,83 +73,72 @@ func (t *Table) SetItems(rows []TableRow) {
 	t.SetRows(rows)
 }
 
-// SetItems converts table rows to list items and sets them
+// SetRows replaces the rows, keeping the cursor within them
 func (t *Table) SetRows(rows []TableRow) {
-	items := make([]list.Item, len(rows))
-	for i, row := range rows {
-		items[i] = tableItem{row: row}
-	}
-	t.list.SetItems(items)
+	t.rows = rows
+	t.cursor = min(t.cursor, max(len(rows)-1, 0))
+	t.scroll()
 }
 
 // AppendItem adds an item to the table
 func (t *Table) AppendItem(row TableRow) {
-	t.list.InsertItem(len(t.list.Items()), tableItem{row: row})
+	t.rows = append(t.rows, row)
 }
 
 // ClearItems removes all items from the table
 func (t *Table) ClearItems() {
-	t.list.SetItems([]list.Item{})
+	t.rows = nil
+	t.cursor = 0
+	t.offset = 0
 }
 
 // SelectedItem returns the selected table row
 func (t *Table) SelectedItem() *TableRow {
-	selected := t.list.SelectedItem()
-	if selected == nil {
+	if t.cursor < 0 || t.cursor >= len(t.rows) {
 		return nil
 	}
-	if item, ok := selected.(tableItem); ok {
-		return &item.row
-	}
-	return nil
+	return &t.rows[t.cursor]
 }
 
 // SelectedIndex returns the index of the selected row
 func (t *Table) SelectedIndex() int {
-	return t.list.Index()
+	return t.cursor
 }
 
 // SetCursor sets the cursor to the specified index
 func (t *Table) SetCursor(index int) {
-	t.list.Select(index)
+	t.cursor = min(max(index, 0), max(len(t.rows)-1, 0))
+	t.scroll()
 }
 
 // CursorUp moves the selection up
 func (t *Table) CursorUp() {
-	if t.list.Index() > 0 {
-		t.list.Select(t.list.Index() - 1)
-	}
+	t.SetCursor(t.cursor - 1)
 }
 
 // CursorDown moves the selection down
 func (t *Table) CursorDown() {
-	items := t.list.Items()
-	if t.list.Index() < len(items)-1 {
-		t.list.Select(t.list.Index() + 1)
-	}
+	t.SetCursor(t.cursor + 1)
 }
 
 // Top moves the cursor to the first item
 func (t *Table) Top() {
-	t.list.Select(0)
+	t.SetCursor(0)
 }
 
 // Bottom moves the cursor to the last item
 func (t *Table) Bottom() {
-	items := t.list.Items()
-	if len(items) > 0 {
-		t.list.Select(len(items) - 1)
-	}
+	t.SetCursor(len(t.rows) - 1)
 }
 
 // ItemsCount returns the number of items in the table
 func (t *Table) ItemsCount() int {
-	return len(t.list.Items())
+	return len(t.rows)
 }
 
 // IsEmpty returns true if the table has no items
 func (t *Table) IsEmpty() bool {
-	return len(t.list.Items()) == 0
+	return len(t.rows) == 0
 }
 
 // Init initializes the table component
@@ -169,43 +148,138 @@ func (t *Table) Init() tea.Cmd {
 
 // Update handles messages for the table
 func (t *Table) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	var cmd tea.Cmd
-	t.list, cmd = t.list.Update(msg)
-	return t, cmd
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "pgdown":
+			t.SetCursor(t.cursor + t.visibleRows())
+		case "pgup":
+			t.SetCursor(t.cursor - t.visibleRows())
+		case "home":
+			t.Top()
+		case "end":
+			t.Bottom()
+		}
+	}
+	return t, nil
 }
 
 // View renders the table
 func (t *Table) View() string {
-	if len(t.list.Items()) == 0 {
+	if len(t.rows) == 0 {
 		return t.emptyView()
 	}
-	return t.list.View()
+
+	widths := t.columnWidths()
+	headerStyle := lipgloss.NewStyle().Foreground(CurrentTheme().Secondary).Bold(true)
+	normalStyle := lipgloss.NewStyle().Foreground(CurrentTheme().Foreground)
+	selectedStyle := lipgloss.NewStyle().
+		Foreground(CurrentTheme().Background).
+		Background(CurrentTheme().Primary).
+		Bold(true)
+	descStyle := lipgloss.NewStyle().Foreground(CurrentTheme().Muted).Faint(true)
+	selectedDescStyle := selectedStyle.Copy().Foreground(CurrentTheme().Foreground).Bold(false)
+
+	headers := make([]string, len(t.columns))
+	for i, column := range t.columns {
+		headers[i] = column.Title
+		if i == t.sortColumn {
+			if t.sortDesc {
+				headers[i] += " ▼"
+			} else {
+				headers[i] += " ▲"
+			}
+		}
+	}
+	lines := []string{headerStyle.Render(t.renderCells(headers, widths))}
+
+	end := min(t.offset+t.visibleRows(), len(t.rows))
+	for i := t.offset; i < end; i++ {
+		row := t.rows[i]
+		rowStyle, rowDescStyle := normalStyle, descStyle
+		if i == t.cursor {
+			rowStyle, rowDescStyle = selectedStyle, selectedDescStyle
+		}
+
+		lines = append(lines, rowStyle.Render(t.renderCells(row.Cells, widths)))
+		if t.showDesc {
+			lines = append(lines, rowDescStyle.Render(fitCell("  "+row.Description, t.width)))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// renderCells lays out one value per column, padded or cut to the column widths
+func (t *Table) renderCells(cells []string, widths []int) string {
+	parts := make([]string, len(widths))
+	for i, width := range widths {
+		cell := ""
+		if i < len(cells) {
+			cell = cells[i]
+		}
+		parts[i] = fitCell(cell, width)
+	}
+	return fitCell(strings.Join(parts, strings.Repeat(" ", columnGap)), t.width)
 }
 
-// emptyView renders the table when empty
-func (t *Table) emptyView() string {
-	style := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#6c7086")). // Gray
-		Faint(true).
-		Italic(true)
+// columnWidths returns the width of every column for the table width
+// Columns without a fixed width share what is left, but never shrink below their title.
+func (t *Table) columnWidths() []int {
+	widths := make([]int, len(t.columns))
+	remaining := t.width - columnGap*(len(t.columns)-1)
+	flexible := 0
+	for i, column := range t.columns {
+		widths[i] = column.Width
+		remaining -= column.Width
+		if column.Width == 0 {
+			flexible++
+		}
+	}
 
-	emptyText := "No items found"
-	return style.Render(emptyText)
+	for i, column := range t.columns {
+		if column.Width == 0 {
+			widths[i] = max(remaining/flexible, ansi.StringWidth(column.Title)+2)
+		}
+	}
+	return widths
 }
 
-// tableItem implements list.Item for TableRow
-type tableItem struct {
-	row TableRow
+// visibleRows returns how many rows fit below the header
+func (t *Table) visibleRows() int {
+	rowHeight := 1
+	if t.showDesc {
+		rowHeight = 2
+	}
+	return max((t.height-1)/rowHeight, 1)
 }
 
-func (i tableItem) Title() string {
-	return i.row.Title
+// scroll moves the visible rows so the cursor stays in view
+func (t *Table) scroll() {
+	visible := t.visibleRows()
+	if t.cursor < t.offset {
+		t.offset = t.cursor
+	} else if t.cursor >= t.offset+visible {
+		t.offset = t.cursor - visible + 1
+	}
+	t.offset = min(t.offset, max(len(t.rows)-visible, 0))
 }
 
-func (i tableItem) Description() string {
-	return i.row.Description
+// fitCell pads or cuts text to exactly width cells
+func fitCell(text string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	text = ansi.Truncate(text, width, "…")
+	return text + strings.Repeat(" ", width-ansi.StringWidth(text))
 }
 
-func (i tableItem) FilterValue() string {
-	return i.row.Title + " " + i.row.Description + " " + i.row.Metadata
+// emptyView renders the table when empty
+func (t *Table) emptyView() string {
+	style := lipgloss.NewStyle().
+		Foreground(CurrentTheme().Muted).
+		Faint(true).
+		Italic(true)
+
+	emptyText := "No items found"
+	return style.Render(emptyText)
 }
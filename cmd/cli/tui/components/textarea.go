@@ -12,6 +12,7 @@ type Textarea struct {
 	focused  bool
 	width    int
 	height   int
+	vim      *vimState // Set when vim-style editing is on
 }
 
 // NewTextarea creates a new textarea component
@@ -20,16 +21,17 @@ func NewTextarea() Textarea {
 	ta.Placeholder = "Enter content here..."
 	ta.ShowLineNumbers = false
 	ta.CharLimit = 0 // No limit by default
-	ta.FocusedStyle.CursorLine = lipgloss.NewStyle().Background(lipgloss.Color("#313244"))
-	ta.FocusedStyle.Prompt = lipgloss.NewStyle().Foreground(lipgloss.Color("#a6e3a1"))
-	ta.BlurredStyle.Prompt = lipgloss.NewStyle().Foreground(lipgloss.Color("#6c7086"))
 
-	return Textarea{
+	t := Textarea{
 		textarea: ta,
 		focused:  false,
 		width:    60,
 		height:   10,
 	}
+	if vimMode {
+		t.vim = &vimState{}
+	}
+	return t
 }
 
 // SetPlaceholder sets the placeholder text
@@ -76,8 +78,6 @@ func (t *Textarea) SetCharLimit(limit int) {
 func (t *Textarea) Focus() {
 	t.focused = true
 	t.textarea.Focus()
-	// Update focused styles
-	t.textarea.FocusedStyle.CursorLine = lipgloss.NewStyle().Background(lipgloss.Color("#313244"))
 }
 
 // Blur removes focus from the textarea
@@ -128,6 +128,10 @@ func (t *Textarea) LineCount() int {
 
 // Update handles messages for the textarea
 func (t *Textarea) Update(msg tea.Msg) tea.Cmd {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && t.vim != nil && t.updateVim(keyMsg) {
+		return nil
+	}
+
 	var cmd tea.Cmd
 	t.textarea, cmd = t.textarea.Update(msg)
 	return cmd
@@ -135,7 +139,28 @@ func (t *Textarea) Update(msg tea.Msg) tea.Cmd {
 
 // View renders the textarea
 func (t *Textarea) View() string {
-	return t.textarea.View()
+	// The theme may have been switched since the last frame
+	t.applyTheme()
+	if t.vim == nil {
+		return t.textarea.View()
+	}
+
+	mode := "-- NORMAL --"
+	if t.vim.mode == VimInsert {
+		mode = "-- INSERT --"
+	}
+	if t.vim.pending != "" {
+		mode += " " + t.vim.pending
+	}
+	modeStyle := lipgloss.NewStyle().Foreground(CurrentTheme().Muted).Bold(true)
+	return t.textarea.View() + "\n" + modeStyle.Render(mode)
+}
+
+// applyTheme styles the textarea with the current theme
+func (t *Textarea) applyTheme() {
+	t.textarea.FocusedStyle.CursorLine = lipgloss.NewStyle().Background(CurrentTheme().Selected)
+	t.textarea.FocusedStyle.Prompt = lipgloss.NewStyle().Foreground(CurrentTheme().Accent)
+	t.textarea.BlurredStyle.Prompt = lipgloss.NewStyle().Foreground(CurrentTheme().Muted)
 }
 
 // Validate checks if the textarea content is valid
@@ -15,16 +15,20 @@ type TextInput struct {
 
 // NewTextInput creates a new text input component
 func NewTextInput() TextInput {
-	ti := textinput.New()
-	ti.PlaceholderStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#6c7086")).Faint(true)
-	ti.Cursor.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("#89b4fa"))
-	ti.PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#a6e3a1"))
-
-	return TextInput{
-		textInput: ti,
+	t := TextInput{
+		textInput: textinput.New(),
 		focused:   false,
 		width:     40,
 	}
+	t.applyTheme()
+	return t
+}
+
+// applyTheme styles the input with the current theme
+func (t *TextInput) applyTheme() {
+	t.textInput.PlaceholderStyle = lipgloss.NewStyle().Foreground(CurrentTheme().Muted).Faint(true)
+	t.textInput.Cursor.Style = lipgloss.NewStyle().Foreground(CurrentTheme().Primary)
+	t.textInput.PromptStyle = lipgloss.NewStyle().Foreground(CurrentTheme().Accent)
 }
 
 // SetPlaceholder sets the placeholder text
@@ -67,8 +71,6 @@ func (t *TextInput) SetCharLimit(limit int) {
 func (t *TextInput) Focus() {
 	t.focused = true
 	t.textInput.Focus()
-	// Update cursor style when focused
-	t.textInput.Cursor.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("#89b4fa"))
 }
 
 // Blur removes focus from the text input
@@ -96,6 +98,8 @@ func (t *TextInput) Update(msg tea.Msg) tea.Cmd {
 
 // View renders the text input
 func (t *TextInput) View() string {
+	// The theme may have been switched since the last frame
+	t.applyTheme()
 	return t.textInput.View()
 }
 
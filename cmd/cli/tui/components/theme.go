@@ -0,0 +1,199 @@
+package components
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme is the color palette of the TUI
+// Views look colors up with CurrentTheme() when they render, so switching themes
+// takes effect on the next frame.
+type Theme struct {
+	Name       string
+	Foreground lipgloss.Color // Body text
+	Subtext    lipgloss.Color // Secondary body text
+	Background lipgloss.Color // Background, and text on highlighted items
+	Header     lipgloss.Color // Header bar background
+	Selected   lipgloss.Color // Selected item and bar background
+	Border     lipgloss.Color // Borders and separators
+	Muted      lipgloss.Color // Hints, metadata and placeholders
+	Primary    lipgloss.Color // Emphasis, selection and focused inputs
+	Secondary  lipgloss.Color // Titles and headings
+	Accent     lipgloss.Color // Success and key bindings
+	Special    lipgloss.Color // Overlays and tags
+	Error      lipgloss.Color
+	Warning    lipgloss.Color // Warnings and matches
+
+	// Heatmap shades activity heatmap cells from no activity to the busiest days
+	Heatmap []lipgloss.Color
+}
+
+// Built-in themes
+var (
+	// DarkTheme is Catppuccin Mocha
+	DarkTheme = Theme{
+		Name:       "dark",
+		Foreground: "#cdd6f4",
+		Subtext:    "#bac2de",
+		Background: "#1e1e2e",
+		Header:     "#181825",
+		Selected:   "#313244",
+		Border:     "#45475a",
+		Muted:      "#6c7086",
+		Primary:    "#89b4fa",
+		Secondary:  "#fab387",
+		Accent:     "#a6e3a1",
+		Special:    "#cba6f7",
+		Error:      "#f38ba8",
+		Warning:    "#f9e2af",
+		Heatmap:    []lipgloss.Color{"#313244", "#40613e", "#5a8f4e", "#7fbf6a", "#a6e3a1"},
+	}
+
+	// LightTheme is Catppuccin Latte
+	LightTheme = Theme{
+		Name:       "light",
+		Foreground: "#4c4f69",
+		Subtext:    "#5c5f77",
+		Background: "#eff1f5",
+		Header:     "#e6e9ef",
+		Selected:   "#ccd0da",
+		Border:     "#bcc0cc",
+		Muted:      "#8c8fa1",
+		Primary:    "#1e66f5",
+		Secondary:  "#fe640b",
+		Accent:     "#40a02b",
+		Special:    "#8839ef",
+		Error:      "#d20f39",
+		Warning:    "#df8e1d",
+		Heatmap:    []lipgloss.Color{"#ccd0da", "#b5dbaa", "#8cc77c", "#63b453", "#40a02b"},
+	}
+
+	// SolarizedTheme is Solarized Dark
+	SolarizedTheme = Theme{
+		Name:       "solarized",
+		Foreground: "#839496",
+		Subtext:    "#93a1a1",
+		Background: "#002b36",
+		Header:     "#073642",
+		Selected:   "#073642",
+		Border:     "#586e75",
+		Muted:      "#657b83",
+		Primary:    "#268bd2",
+		Secondary:  "#cb4b16",
+		Accent:     "#859900",
+		Special:    "#6c71c4",
+		Error:      "#dc322f",
+		Warning:    "#b58900",
+		Heatmap:    []lipgloss.Color{"#073642", "#2b4a1d", "#4d6612", "#6a8008", "#859900"},
+	}
+)
+
+// CustomThemeName is the theme built from the custom_theme colors of the config
+const CustomThemeName = "custom"
+
+var (
+	currentTheme = DarkTheme
+	customTheme  *Theme
+)
+
+// CurrentTheme returns the theme in use
+func CurrentTheme() Theme {
+	return currentTheme
+}
+
+// SetTheme switches to the named theme, reporting whether it exists
+func SetTheme(name string) bool {
+	theme, ok := ThemeByName(name)
+	if ok {
+		currentTheme = theme
+	}
+	return ok
+}
+
+// ThemeByName returns a built-in theme, or the custom theme once SetCustomTheme was called
+func ThemeByName(name string) (Theme, bool) {
+	switch strings.ToLower(name) {
+	case DarkTheme.Name:
+		return DarkTheme, true
+	case LightTheme.Name:
+		return LightTheme, true
+	case SolarizedTheme.Name:
+		return SolarizedTheme, true
+	case CustomThemeName:
+		if customTheme != nil {
+			return *customTheme, true
+		}
+	}
+	return Theme{}, false
+}
+
+// ThemeNames returns the names of the themes that can be switched to
+func ThemeNames() []string {
+	names := []string{DarkTheme.Name, LightTheme.Name, SolarizedTheme.Name}
+	if customTheme != nil {
+		names = append(names, CustomThemeName)
+	}
+	return names
+}
+
+// SetCustomTheme defines the custom theme from color names to colors
+// Colors are hex values ("#89b4fa") or ANSI numbers ("12"); colors left out
+// come from the "base" theme, dark unless given.
+func SetCustomTheme(colors map[string]string) error {
+	base := DarkTheme
+	if name, ok := colors["base"]; ok {
+		theme, found := ThemeByName(name)
+		if !found || theme.Name == CustomThemeName {
+			return fmt.Errorf("unknown base theme %q", name)
+		}
+		base = theme
+	}
+
+	theme := base
+	theme.Name = CustomThemeName
+	theme.Heatmap = slices.Clone(base.Heatmap)
+	fields := map[string]*lipgloss.Color{
+		"foreground": &theme.Foreground,
+		"subtext":    &theme.Subtext,
+		"background": &theme.Background,
+		"header":     &theme.Header,
+		"selected":   &theme.Selected,
+		"border":     &theme.Border,
+		"muted":      &theme.Muted,
+		"primary":    &theme.Primary,
+		"secondary":  &theme.Secondary,
+		"accent":     &theme.Accent,
+		"special":    &theme.Special,
+		"error":      &theme.Error,
+		"warning":    &theme.Warning,
+	}
+
+	for name, value := range colors {
+		name = strings.ToLower(name)
+		if name == "base" {
+			continue
+		}
+		field, ok := fields[name]
+		if !ok {
+			return fmt.Errorf("unknown theme color %q", name)
+		}
+		if !isColor(value) {
+			return fmt.Errorf("invalid %s color %q (use a hex value like #89b4fa or an ANSI number)", name, value)
+		}
+		*field = lipgloss.Color(value)
+	}
+
+	customTheme = &theme
+	return nil
+}
+
+// isColor reports whether value is a #rrggbb hex color or an ANSI color number
+func isColor(value string) bool {
+	if hex, ok := strings.CutPrefix(value, "#"); ok {
+		return len(hex) == 6 && strings.Trim(strings.ToLower(hex), "0123456789abcdef") == ""
+	}
+	return value != "" && len(value) <= 3 && strings.Trim(value, "0123456789") == ""
+}
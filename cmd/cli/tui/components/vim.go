@@ -0,0 +1,296 @@
+package components
+
+import (
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// VimEditMode is the mode of a textarea with vim-style editing
+type VimEditMode int
+
+const (
+	VimNormal VimEditMode = iota
+	VimInsert
+)
+
+// vimMode enables vim-style editing for textareas created afterwards
+var vimMode bool
+
+// SetVimMode turns vim-style modal editing of textareas on or off
+func SetVimMode(enabled bool) {
+	vimMode = enabled
+}
+
+// vimState is the modal editing state of a textarea
+type vimState struct {
+	mode     VimEditMode
+	pending  string // First key of a two-key command: d, y or g
+	register string // Last deleted or yanked text
+	linewise bool   // The register holds whole lines
+}
+
+// vimKeyAliases lets arrow, home and end keys work like their vim counterparts in normal mode
+var vimKeyAliases = map[string]string{
+	"left":  "h",
+	"right": "l",
+	"home":  "0",
+	"end":   "$",
+}
+
+// updateVim handles a key of a vim-style textarea, reporting whether it was consumed
+// Insert mode only handles esc; other keys are left to the textarea.
+func (t *Textarea) updateVim(msg tea.KeyMsg) bool {
+	key := msg.String()
+
+	if t.vim.mode == VimInsert {
+		if key != "esc" {
+			return false
+		}
+		t.vim.mode = VimNormal
+		// Like vim, leave the cursor on the last inserted character
+		_, col := t.cursor()
+		t.textarea.SetCursor(col - 1)
+		return true
+	}
+
+	if alias, ok := vimKeyAliases[key]; ok {
+		key = alias
+	}
+
+	if t.vim.pending != "" {
+		command := t.vim.pending + key
+		t.vim.pending = ""
+		switch command {
+		case "dd":
+			t.deleteLine()
+		case "yy":
+			t.yankLine()
+		case "gg":
+			t.moveTo(0, 0)
+		}
+		return true
+	}
+
+	lines := t.lines()
+	row, col := t.cursor()
+	line := []rune(lines[row])
+
+	switch key {
+	// Motions
+	case "h":
+		t.textarea.SetCursor(col - 1)
+	case "l":
+		t.textarea.SetCursor(min(col+1, len(line)-1))
+	case "j":
+		if row < len(lines)-1 {
+			t.moveTo(row+1, min(col, len([]rune(lines[row+1]))-1))
+		}
+	case "k":
+		if row > 0 {
+			t.moveTo(row-1, min(col, len([]rune(lines[row-1]))-1))
+		}
+	case "0":
+		t.textarea.SetCursor(0)
+	case "^":
+		t.textarea.SetCursor(firstNonBlank(line))
+	case "$":
+		t.textarea.SetCursor(len(line) - 1)
+	case "w":
+		t.moveToOffset(nextWordStart([]rune(t.Value()), t.offset()))
+	case "b":
+		t.moveToOffset(prevWordStart([]rune(t.Value()), t.offset()))
+	case "G":
+		t.moveTo(len(lines)-1, 0)
+	case "d", "y", "g":
+		t.vim.pending = key
+
+	// Switching to insert mode
+	case "i":
+		t.vim.mode = VimInsert
+	case "a":
+		t.vim.mode = VimInsert
+		t.textarea.SetCursor(min(col+1, len(line)))
+	case "I":
+		t.vim.mode = VimInsert
+		t.textarea.SetCursor(firstNonBlank(line))
+	case "A":
+		t.vim.mode = VimInsert
+		t.textarea.CursorEnd()
+	case "o":
+		t.vim.mode = VimInsert
+		t.setLines(insertLines(lines, row+1, ""), row+1, 0)
+	case "O":
+		t.vim.mode = VimInsert
+		t.setLines(insertLines(lines, row, ""), row, 0)
+
+	// Editing
+	case "x":
+		if len(line) > 0 {
+			col = min(col, len(line)-1)
+			t.vim.register, t.vim.linewise = string(line[col]), false
+			lines[row] = string(line[:col]) + string(line[col+1:])
+			t.setLines(lines, row, min(col, len(line)-2))
+		}
+	case "p":
+		t.paste(true)
+	case "P":
+		t.paste(false)
+	}
+
+	// Normal mode never types into the text
+	return true
+}
+
+// VimEditMode returns the editing mode, and false when vim-style editing is off
+func (t *Textarea) VimEditMode() (VimEditMode, bool) {
+	if t.vim == nil {
+		return VimNormal, false
+	}
+	return t.vim.mode, true
+}
+
+// deleteLine cuts the line under the cursor into the register
+func (t *Textarea) deleteLine() {
+	lines := t.lines()
+	row, _ := t.cursor()
+	t.vim.register, t.vim.linewise = lines[row], true
+
+	if len(lines) == 1 {
+		t.setLines([]string{""}, 0, 0)
+		return
+	}
+	lines = append(lines[:row], lines[row+1:]...)
+	row = min(row, len(lines)-1)
+	t.setLines(lines, row, firstNonBlank([]rune(lines[row])))
+}
+
+// yankLine copies the line under the cursor into the register
+func (t *Textarea) yankLine() {
+	row, _ := t.cursor()
+	t.vim.register, t.vim.linewise = t.lines()[row], true
+}
+
+// paste puts the register after the cursor, or before it
+// Lines go below or above the current line, like in vim.
+func (t *Textarea) paste(after bool) {
+	if t.vim.register == "" && !t.vim.linewise {
+		return
+	}
+	lines := t.lines()
+	row, col := t.cursor()
+
+	if t.vim.linewise {
+		if after {
+			row++
+		}
+		t.setLines(insertLines(lines, row, t.vim.register), row, 0)
+		return
+	}
+
+	line := []rune(lines[row])
+	if after && len(line) > 0 {
+		col = min(col+1, len(line))
+	}
+	pasted := []rune(t.vim.register)
+	lines[row] = string(line[:col]) + string(pasted) + string(line[col:])
+	t.setLines(lines, row, col+len(pasted)-1)
+}
+
+// lines returns the text split into lines
+func (t *Textarea) lines() []string {
+	return strings.Split(t.Value(), "\n")
+}
+
+// cursor returns the line and column of the cursor
+func (t *Textarea) cursor() (row, col int) {
+	info := t.textarea.LineInfo()
+	return t.textarea.Line(), info.StartColumn + info.ColumnOffset
+}
+
+// offset returns the cursor position as a character offset into the text
+func (t *Textarea) offset() int {
+	row, col := t.cursor()
+	offset := col
+	for _, line := range t.lines()[:row] {
+		offset += len([]rune(line)) + 1
+	}
+	return offset
+}
+
+// moveToOffset moves the cursor to a character offset into the text
+func (t *Textarea) moveToOffset(offset int) {
+	for row, line := range t.lines() {
+		length := len([]rune(line))
+		if offset <= length {
+			t.moveTo(row, offset)
+			return
+		}
+		offset -= length + 1
+	}
+}
+
+// moveTo moves the cursor to a line and column
+// The textarea only moves between lines one (soft-wrapped) line at a time.
+func (t *Textarea) moveTo(row, col int) {
+	for t.textarea.Line() > row {
+		t.textarea.CursorUp()
+	}
+	for t.textarea.Line() < row {
+		t.textarea.CursorDown()
+	}
+	t.textarea.SetCursor(max(col, 0))
+}
+
+// setLines replaces the text and moves the cursor to a line and column
+func (t *Textarea) setLines(lines []string, row, col int) {
+	t.textarea.SetValue(strings.Join(lines, "\n"))
+	t.moveTo(row, col)
+}
+
+// insertLines inserts text as lines before index
+func insertLines(lines []string, index int, text string) []string {
+	inserted := strings.Split(text, "\n")
+	result := make([]string, 0, len(lines)+len(inserted))
+	result = append(result, lines[:index]...)
+	result = append(result, inserted...)
+	return append(result, lines[index:]...)
+}
+
+// firstNonBlank returns the column of the first non-blank character of a line
+func firstNonBlank(line []rune) int {
+	for i, r := range line {
+		if !unicode.IsSpace(r) {
+			return i
+		}
+	}
+	return 0
+}
+
+// nextWordStart returns the offset of the start of the next word after offset
+func nextWordStart(text []rune, offset int) int {
+	i := offset
+	for i < len(text) && !unicode.IsSpace(text[i]) {
+		i++
+	}
+	for i < len(text) && unicode.IsSpace(text[i]) {
+		i++
+	}
+	if i >= len(text) {
+		return offset
+	}
+	return i
+}
+
+// prevWordStart returns the offset of the start of the word before offset
+func prevWordStart(text []rune, offset int) int {
+	i := offset - 1
+	for i > 0 && unicode.IsSpace(text[i]) {
+		i--
+	}
+	for i > 0 && !unicode.IsSpace(text[i-1]) {
+		i--
+	}
+	return max(i, 0)
+}
@@ -0,0 +1,133 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/uuid"
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+// focusLength is how long a focus session runs, one pomodoro
+const focusLength = 25 * time.Minute
+
+// focusTitleWidth is how much of the note's title is shown next to the timer
+const focusTitleWidth = 20
+
+// focusSession is a running focus timer on a note
+type focusSession struct {
+	id      int
+	noteID  uuid.UUID
+	title   string
+	started time.Time
+}
+
+// focusTickMsg updates the timer of a focus session once a second
+type focusTickMsg struct {
+	id int
+}
+
+// focusLoggedMsg reports logging a completed focus session
+type focusLoggedMsg struct {
+	noteID uuid.UUID
+	title  string
+	err    error
+}
+
+// startFocus starts a focus session on a note
+// Returns the command that ticks the timer.
+func (m *MainModel) startFocus(note *model.Note) tea.Cmd {
+	m.nextFocusID++
+	m.focus = &focusSession{id: m.nextFocusID, noteID: note.ID, title: note.Title, started: time.Now()}
+	m.showFocus()
+	m.statusBar.ShowInfo(fmt.Sprintf("Focusing on %q for %d minutes · F:stop", note.Title, int(focusLength.Minutes())))
+	return tea.Batch(focusTickCmd(m.focus.id), clearInfoCmd())
+}
+
+// stopFocus stops the running focus session without logging it
+func (m *MainModel) stopFocus() tea.Cmd {
+	m.statusBar.ShowInfo(fmt.Sprintf("Stopped focusing on %q", m.focus.title))
+	m.focus = nil
+	m.showFocus()
+	return clearInfoCmd()
+}
+
+// tickFocus updates the timer, logging the session once it has run its full length
+func (m *MainModel) tickFocus(id int) tea.Cmd {
+	// Ticks of a stopped session are dropped
+	if m.focus == nil || m.focus.id != id {
+		return nil
+	}
+
+	if time.Since(m.focus.started) >= focusLength {
+		session := m.focus
+		m.focus = nil
+		m.showFocus()
+		return m.logFocusCmd(session)
+	}
+
+	m.showFocus()
+	return focusTickCmd(id)
+}
+
+// showFocus shows the time left of the running focus session in the status bar
+func (m *MainModel) showFocus() {
+	if m.focus == nil {
+		m.statusBar.SetFocus("")
+		return
+	}
+	left := max(0, (focusLength - time.Since(m.focus.started)).Round(time.Second))
+	title := []rune(m.focus.title)
+	if len(title) > focusTitleWidth {
+		title = append(title[:focusTitleWidth-1], '…')
+	}
+	m.statusBar.SetFocus(fmt.Sprintf("● %02d:%02d %s", int(left.Minutes()), int(left.Seconds())%60, string(title)))
+}
+
+// focusTickCmd returns a command that ticks a focus session's timer after a second
+func focusTickCmd(id int) tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return focusTickMsg{id: id}
+	})
+}
+
+// logFocusCmd returns a command that logs a completed focus session as activity on its note
+func (m MainModel) logFocusCmd(session *focusSession) tea.Cmd {
+	return func() tea.Msg {
+		_, err := m.client.LogFocus(session.noteID, focusLength)
+		return focusLoggedMsg{noteID: session.noteID, title: session.title, err: err}
+	}
+}
+
+// focusLogged reports a logged focus session, refreshing the note's stats if it is open
+func (m *MainModel) focusLogged(msg focusLoggedMsg) tea.Cmd {
+	if msg.err != nil {
+		m.statusBar.ShowError(fmt.Sprintf("Log focus session: %v", msg.err))
+		return nil
+	}
+	// Left in the status bar, so it is seen when coming back to the terminal
+	m.statusBar.ShowInfo(fmt.Sprintf("Focus session on %q complete (%d minutes)", msg.title, int(focusLength.Minutes())))
+
+	if note := m.noteDetailModel.GetNote(); note != nil && note.ID == msg.noteID {
+		var cmd tea.Cmd
+		m.noteDetailModel, cmd = m.noteDetailModel.ReloadStats()
+		return cmd
+	}
+	return nil
+}
+
+// clearInfoCmd clears the status bar message after a moment
+func clearInfoCmd() tea.Cmd {
+	return tea.Tick(time.Second*2, func(time.Time) tea.Msg {
+		return clearErrorMsg{}
+	})
+}
+
+// focusNote returns the note a focus session can be started on: the one open in the note view
+func (m MainModel) focusNote() *model.Note {
+	if m.currentView != NoteDetailView {
+		return nil
+	}
+	return m.noteDetailModel.GetNote()
+}
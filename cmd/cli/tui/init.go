@@ -29,6 +29,9 @@ func InitTUI(apiClient *client.APIClient, authState *client.AuthState) error {
 	// If we got here, the session is valid
 	_ = stats // We don't need the stats, just validation
 
+	// Load account preferences up front so models can read them synchronously
+	apiClient.Settings()
+
 	return nil
 }
 
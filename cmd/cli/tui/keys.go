@@ -18,6 +18,7 @@ var GlobalKeyBindings = []KeyBinding{
 	{Keys: "esc", Action: "back", Help: "esc:back"},
 	{Keys: "/", Action: "search", Help: "/:search"},
 	{Keys: "n", Action: "new", Help: "n:new"},
+	{Keys: "ctrl+k", Action: "quick_switch", Help: "ctrl+k:jump"},
 	{Keys: "ctrl+c", Action: "force_quit", Help: "ctrl+c:force quit"},
 }
 
@@ -43,6 +44,7 @@ var NoteListKeyBindings = []KeyBinding{
 	{Keys: "f", Action: "filter_menu", Help: "f:filter"},
 	{Keys: "s", Action: "sort", Help: "s:sort"},
 	{Keys: "t", Action: "tag_filter", Help: "t:tag"},
+	{Keys: "y", Action: "copy", Help: "y:copy"},
 }
 
 // NoteDetailKeyBindings are keys for viewing a note
@@ -56,6 +58,13 @@ var NoteDetailKeyBindings = []KeyBinding{
 	{Keys: "l", Action: "links", Help: "l:links"},
 	{Keys: "b", Action: "backlinks", Help: "b:backlinks"},
 	{Keys: "t", Action: "tags", Help: "t:tags"},
+	{Keys: "r", Action: "restore", Help: "r:restore"},
+	{Keys: "G", Action: "local_graph", Help: "G:local graph"},
+	{Keys: "y", Action: "copy", Help: "y:copy"},
+	{Keys: "F", Action: "focus", Help: "F:focus"},
+	{Keys: "pgup,pgdown", Action: "page", Help: "pgup/pgdn:page"},
+	{Keys: "ctrl+u,ctrl+d", Action: "half_page", Help: "ctrl+u/ctrl+d:half page"},
+	{Keys: "home,end", Action: "top_bottom", Help: "home/end:top/bottom"},
 	{Keys: "tab", Action: "next_tab", Help: "tab:next"},
 	{Keys: "shift+tab", Action: "prev_tab", Help: "shift+tab:prev"},
 }
@@ -106,7 +115,7 @@ func GetKeyHelp(bindings []KeyBinding) string {
 func GetViewKeyHelp(view View) string {
 	switch view {
 	case DashboardView:
-		return "n:new s:search l:list a:activity ?:help q:quit"
+		return "n:new s:search l:list a:activity 1-5:resurfaced i:inbox ?:help q:quit"
 	case NoteListView:
 		return GetKeyHelp(NoteListKeyBindings) + " q:back ?:help"
 	case NoteDetailView:
@@ -120,7 +129,21 @@ func GetViewKeyHelp(view View) string {
 	case ActivityView:
 		return "↑↓:scroll enter:open f:filter q:back ?:help"
 	case GraphView:
-		return "↑↓←→:nav enter:view d:details q:back ?:help"
+		return "tab:select ↑↓←→:pan +/-:zoom enter:view d:details v:list c:clusters h:hubs p:path q:back ?:help"
+	case TasksView:
+		return "↑↓:scroll enter:open f:filter q:back ?:help"
+	case SessionsView:
+		return "↑↓:scroll d:revoke r:refresh q:back ?:help"
+	case BoardView:
+		return "←→:column ↑↓:card h/l:move enter:open q:back ?:help"
+	case CalendarView:
+		return "←→↑↓:day [/]:month T:today enter:open q:back ?:help"
+	case PublishedView:
+		return "↑↓:scroll enter:open y:copy link d:revoke r:refresh q:back ?:help"
+	case TagAnalyticsView:
+		return "↑↓:scroll enter:notes with tag r:refresh q:back ?:help"
+	case ReviewView:
+		return "space:show answer 0-5:grade o:open note r:refresh q:back ?:help"
 	case HelpView:
 		return "↑↓:scroll q:close esc:close"
 	default:
@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -8,8 +9,19 @@ import (
 	"github.com/momokii/go-cli-notes/cmd/cli/client"
 	"github.com/momokii/go-cli-notes/cmd/cli/tui/components"
 	"github.com/momokii/go-cli-notes/cmd/cli/tui/models"
+	"github.com/momokii/go-cli-notes/internal/model"
 )
 
+// Live update tuning
+const (
+	liveRefreshDelay = 500 * time.Millisecond // Coalesce bursts of events into one refresh
+	eventsRetryMin   = 2 * time.Second
+	eventsRetryMax   = time.Minute
+)
+
+// layoutSaveDelay coalesces layout changes into one config write
+const layoutSaveDelay = time.Second
+
 // MainModel is the root model for the TUI application
 type MainModel struct {
 	// Shared state
@@ -28,10 +40,22 @@ type MainModel struct {
 	noteListModel   models.NoteListModel
 	noteDetailModel models.NoteDetailModel
 	noteCreateModel models.NoteCreateModel
+	drafts          *models.DraftManager // nil when drafts can't be stored
 	tagListModel    models.TagListModel
 	searchModel     models.SearchModel
 	activityModel   models.ActivityModel
 	graphModel      models.GraphModel
+	taskListModel   models.TaskListModel
+	sessionsModel   models.SessionsModel
+	boardModel      models.BoardModel
+	calendarModel   models.CalendarModel
+	publishedModel  models.PublishedModel
+	analyticsModel  models.TagAnalyticsModel
+	reviewModel     models.ReviewModel
+
+	// Quick switcher overlay (Ctrl+K)
+	quickSwitchModel models.QuickSwitchModel
+	showQuickSwitch  bool
 
 	// Track initialization of child models
 	dashboardInitialized  bool
@@ -42,6 +66,13 @@ type MainModel struct {
 	searchInitialized     bool
 	activityInitialized   bool
 	graphInitialized      bool
+	taskListInitialized   bool
+	sessionsInitialized   bool
+	boardInitialized      bool
+	calendarInitialized   bool
+	publishedInitialized  bool
+	analyticsInitialized  bool
+	reviewInitialized     bool
 
 	// Shared components
 	statusBar *components.StatusBar
@@ -53,17 +84,36 @@ type MainModel struct {
 	sessionWarningShown  bool
 	sessionExpiryWarning time.Duration // Warning threshold (e.g., 5 minutes)
 
+	// Live updates from the API event stream
+	events             <-chan *model.Event
+	eventsRetry        time.Duration
+	liveRefreshPending bool
+	liveNotesChanged   bool // A pending refresh includes note/tag changes, not just activity
+
 	// Error handling
 	currentError    error
 	clearErrorAfter time.Duration
 
+	// View layouts, saved a moment after they change
+	layout       models.Layout
+	saveLayout   func(models.Layout) error
+	layoutSaveID int
+
+	// Destructive actions that can still be undone, latest last
+	undoStack  []undoAction
+	nextUndoID int
+
+	// Running focus timer, nil when none
+	focus       *focusSession
+	nextFocusID int
+
 	// Dimensions
 	width  int
 	height int
 }
 
 // NewMainModel creates a new main TUI model
-func NewMainModel(apiClient *client.APIClient, authState *client.AuthState) MainModel {
+func NewMainModel(apiClient *client.APIClient, authState *client.AuthState, config Config, drafts *models.DraftManager, positions *models.ReadingPositions) MainModel {
 	// Get user info from auth state
 	userInfo := ""
 	if authState != nil && authState.Email != "" {
@@ -82,13 +132,24 @@ func NewMainModel(apiClient *client.APIClient, authState *client.AuthState) Main
 		quitting:              false,
 		helpModel:             models.NewHelpModel(),
 		dashboardModel:        models.NewDashboardModel(apiClient, authState),
-		noteListModel:         models.NewNoteListModel(apiClient, authState),
-		noteDetailModel:       models.NewNoteDetailModel(apiClient, authState),
-		noteCreateModel:       models.NewNoteCreateModel(apiClient, authState),
+		noteListModel:         models.NewNoteListModel(apiClient, authState, config.Layout.NoteList),
+		noteDetailModel:       models.NewNoteDetailModel(apiClient, authState, positions),
+		noteCreateModel:       models.NewNoteCreateModel(apiClient, authState, drafts),
+		drafts:                drafts,
+		layout:                config.Layout,
+		saveLayout:            config.SaveLayout,
 		tagListModel:          models.NewTagListModel(apiClient, authState),
 		searchModel:           models.NewSearchModel(apiClient, authState),
 		activityModel:         models.NewActivityModel(apiClient, authState),
 		graphModel:            models.NewGraphModel(apiClient, authState),
+		taskListModel:         models.NewTaskListModel(apiClient, authState),
+		sessionsModel:         models.NewSessionsModel(apiClient, authState),
+		boardModel:            models.NewBoardModel(apiClient, authState),
+		calendarModel:         models.NewCalendarModel(apiClient, authState),
+		publishedModel:        models.NewPublishedModel(apiClient, authState),
+		analyticsModel:        models.NewTagAnalyticsModel(apiClient, authState),
+		reviewModel:           models.NewReviewModel(apiClient, authState),
+		quickSwitchModel:      models.NewQuickSwitchModel(apiClient, authState),
 		dashboardInitialized:  false,
 		noteListInitialized:   false,
 		noteDetailInitialized: false,
@@ -97,12 +158,14 @@ func NewMainModel(apiClient *client.APIClient, authState *client.AuthState) Main
 		searchInitialized:     false,
 		activityInitialized:   false,
 		graphInitialized:      false,
+		taskListInitialized:   false,
 		statusBar:             sb,
 		sessionValid:          true,
 		lastSessionCheck:      time.Now(),
 		sessionCheckInterval:  5 * time.Minute,
 		sessionWarningShown:   false,
 		sessionExpiryWarning:  5 * time.Minute, // Show warning 5 minutes before expiry
+		eventsRetry:           eventsRetryMin,
 		currentError:          nil,
 		clearErrorAfter:       5 * time.Second,
 		width:                 80,
@@ -115,6 +178,7 @@ func (m MainModel) Init() tea.Cmd {
 	// Start with initial session validation and periodic checks
 	return tea.Batch(
 		m.checkSessionCmd(),
+		m.connectEventsCmd(),
 		m.dashboardModel.Init(),
 		tea.Tick(time.Second, func(t time.Time) tea.Msg {
 			return clearErrorMsg{}
@@ -136,18 +200,58 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		// Quick switcher overlay captures all keys while open
+		if m.showQuickSwitch {
+			model, cmd := m.quickSwitchModel.Update(msg)
+			m.quickSwitchModel = model.(models.QuickSwitchModel)
+			return m, cmd
+		}
+
+		// Ctrl+K opens the quick switcher from any view except the note editor
+		if msg.String() == "ctrl+k" && m.currentView != NoteCreateView && m.currentView != NoteEditView {
+			var cmd tea.Cmd
+			m.quickSwitchModel, cmd = m.quickSwitchModel.Open()
+			m.showQuickSwitch = true
+			return m, cmd
+		}
+
 		// Handle exit keys FIRST - these should always work regardless of focus
 		switch msg.String() {
 		case "q", "ctrl+c":
+			// "q" is typed into a focused input rather than quitting
+			if msg.String() == "q" && m.isInputFocused() {
+				break
+			}
 			if !m.quitting {
 				m.quitting = true
 				return m, tea.Quit
 			}
 		case "esc":
+			// An in-note find is closed by the note itself
+			if m.currentView == NoteDetailView && m.noteDetailModel.IsFindActive() {
+				break
+			}
+			// A pending copy is cancelled by the view it was started in
+			if (m.currentView == NoteDetailView && m.noteDetailModel.IsYankPending()) ||
+				(m.currentView == NoteListView && m.noteListModel.IsYankPending()) {
+				break
+			}
+			// In vim insert mode the note editor switches back to normal mode
+			if (m.currentView == NoteCreateView || m.currentView == NoteEditView) && m.noteCreateModel.VimInsertMode() {
+				break
+			}
 			// Go back to previous view
 			if m.currentView == HelpView {
 				m.currentView = m.prevView
+			} else if m.currentView == GraphView && m.graphModel.RootID() != nil {
+				// A local graph returns to the note it is centered on
+				return m.Update(models.OpenNoteMsg{NoteID: *m.graphModel.RootID()})
 			} else if m.currentView != DashboardView {
+				// Cancelling the note editor discards its draft
+				var discardCmd tea.Cmd
+				if m.currentView == NoteCreateView || m.currentView == NoteEditView {
+					discardCmd = m.noteCreateModel.DiscardDraft()
+				}
 				m.cleanupView(m.currentView)
 				m.prevView = m.currentView
 				m.currentView = DashboardView
@@ -157,8 +261,10 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.dashboardInitialized = true
 					initCmd := m.dashboardModel.Init()
 					m.updateStatusBar()
-					return m, initCmd
+					return m, tea.Batch(initCmd, discardCmd)
 				}
+				m.updateStatusBar()
+				return m, discardCmd
 			}
 			m.updateStatusBar()
 			return m, nil
@@ -173,6 +279,18 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// Handle other global keys (navigation, view switching, etc.)
 		switch msg.String() {
+		case "u":
+			if undo := m.popUndo(); undo != nil {
+				return m, undo
+			}
+		case "F":
+			// Start a focus session on the open note, or stop the running one from any view
+			if m.focus != nil {
+				return m, m.stopFocus()
+			}
+			if note := m.focusNote(); note != nil {
+				return m, m.startFocus(note)
+			}
 		case "?":
 			// Toggle help
 			if m.currentView != HelpView {
@@ -186,6 +304,10 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 
 		case "/":
+			// In a note's content tab "/" finds within the note
+			if m.currentView == NoteDetailView && m.noteDetailModel.GetCurrentTab() == models.NoteContentTab {
+				break
+			}
 			// Quick search
 			m.cleanupView(m.currentView)
 			m.prevView = m.currentView
@@ -202,6 +324,10 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 
 		case "s":
+			// In the note list, "s" changes the sort order
+			if m.currentView == NoteListView {
+				break
+			}
 			// Quick search (same as "/" - documented in help)
 			m.cleanupView(m.currentView)
 			m.prevView = m.currentView
@@ -262,14 +388,121 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.updateStatusBar()
 			return m, nil
 
+		case "x":
+			// Tasks view
+			m.cleanupView(m.currentView)
+			m.prevView = m.currentView
+			m.currentView = TasksView
+			if !m.taskListInitialized {
+				m.taskListInitialized = true
+				initCmd := m.taskListModel.Init()
+				m.updateStatusBar()
+				return m, initCmd
+			}
+			m.updateStatusBar()
+			return m, nil
+
+		case "B":
+			// Board view
+			m.cleanupView(m.currentView)
+			m.prevView = m.currentView
+			m.currentView = BoardView
+			if !m.boardInitialized {
+				m.boardInitialized = true
+				initCmd := m.boardModel.Init()
+				m.updateStatusBar()
+				return m, initCmd
+			}
+			m.updateStatusBar()
+			return m, nil
+
+		case "C":
+			// Calendar of daily notes
+			m.cleanupView(m.currentView)
+			m.prevView = m.currentView
+			m.currentView = CalendarView
+			if !m.calendarInitialized {
+				m.calendarInitialized = true
+				initCmd := m.calendarModel.Init()
+				m.updateStatusBar()
+				return m, initCmd
+			}
+			m.updateStatusBar()
+			return m, nil
+
+		case "S":
+			// In a note, "S" summarizes it
+			if m.currentView == NoteDetailView {
+				break
+			}
+			// Sessions view
+			m.cleanupView(m.currentView)
+			m.prevView = m.currentView
+			m.currentView = SessionsView
+			if !m.sessionsInitialized {
+				m.sessionsInitialized = true
+				initCmd := m.sessionsModel.Init()
+				m.updateStatusBar()
+				return m, initCmd
+			}
+			m.updateStatusBar()
+			return m, nil
+
+		case "P":
+			// Published notes view
+			m.cleanupView(m.currentView)
+			m.prevView = m.currentView
+			m.currentView = PublishedView
+			if !m.publishedInitialized {
+				m.publishedInitialized = true
+				initCmd := m.publishedModel.Init()
+				m.updateStatusBar()
+				return m, initCmd
+			}
+			m.updateStatusBar()
+			return m, nil
+
+		case "A":
+			// Tag analytics view
+			m.cleanupView(m.currentView)
+			m.prevView = m.currentView
+			m.currentView = TagAnalyticsView
+			if !m.analyticsInitialized {
+				m.analyticsInitialized = true
+				initCmd := m.analyticsModel.Init()
+				m.updateStatusBar()
+				return m, initCmd
+			}
+			m.updateStatusBar()
+			return m, nil
+
+		case "R":
+			// Flashcard review view
+			m.cleanupView(m.currentView)
+			m.prevView = m.currentView
+			m.currentView = ReviewView
+			if !m.reviewInitialized {
+				m.reviewInitialized = true
+				initCmd := m.reviewModel.Init()
+				m.updateStatusBar()
+				return m, initCmd
+			}
+			m.updateStatusBar()
+			return m, nil
+
 		case "n":
+			// While finding within a note, "n" jumps to the next match
+			if m.currentView == NoteDetailView && m.noteDetailModel.IsFindActive() {
+				break
+			}
 			// Quick new note
 			m.cleanupView(m.currentView)
 			m.prevView = m.currentView
 			m.currentView = NoteCreateView
 			// Create a fresh model to clear previous input, then focus it
-			m.noteCreateModel = models.NewNoteCreateModel(m.client, m.authState)
+			m.noteCreateModel = models.NewNoteCreateModel(m.client, m.authState, m.drafts)
 			m.noteCreateModel = m.noteCreateModel.FocusForm() // Focus the form
+			m.resizeViews()
 			m.noteCreateInitialized = false
 			if !m.noteCreateInitialized {
 				m.noteCreateInitialized = true
@@ -328,12 +561,26 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// causing noteID to remain nil and all API calls to use UUID 00000000-0000-0000-0000-000000000000
 		var cmd tea.Cmd
 		m.noteDetailModel, cmd = m.noteDetailModel.SetNoteID(msg.NoteID)
+		if msg.Heading != "" || msg.Block != "" {
+			m.noteDetailModel = m.noteDetailModel.SetAnchor(msg.Heading, msg.Block)
+		}
 		if !m.noteDetailInitialized {
 			m.noteDetailInitialized = true
 		}
 		m.updateStatusBar()
 		return m, cmd
 
+	case models.ShowLocalGraphMsg:
+		// Open the graph view scoped to the note's neighbors
+		m.cleanupView(m.currentView)
+		m.prevView = m.currentView
+		m.currentView = GraphView
+		m.graphModel = models.NewLocalGraphModel(m.client, m.authState, msg.NoteID, 2)
+		m.graphInitialized = true
+		m.resizeViews()
+		m.updateStatusBar()
+		return m, m.graphModel.Init()
+
 	case models.EditNoteMsg:
 		// Navigate to edit view
 		m.cleanupView(m.currentView)
@@ -355,7 +602,9 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}))
 		} else {
 			// Note already loaded, set edit mode and capture the returned model
-			m.noteCreateModel, _ = m.noteCreateModel.SetEditMode(note)
+			var cmd tea.Cmd
+			m.noteCreateModel, cmd = m.noteCreateModel.SetEditMode(note)
+			cmds = append(cmds, cmd)
 		}
 
 		if !m.noteCreateInitialized {
@@ -402,10 +651,66 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	// Handle note deleted message
 	case models.NoteDeletedMsg:
 		m.statusBar.ShowInfo("Note deleted")
+		undoCmd := m.pushUndo(fmt.Sprintf("Deleted %q", msg.Title), m.restoreNoteCmd(msg.NoteID))
 		// Use ShowDashboardMsg flow to properly clear notifications and handle initialization
 		// Also auto-clear the "Note deleted" notification after brief delay
-		return m, tea.Tick(time.Second*1, func(t time.Time) tea.Msg {
+		return m, tea.Batch(undoCmd, tea.Tick(time.Second*1, func(t time.Time) tea.Msg {
 			return models.ShowDashboardMsg{}
+		}))
+
+	// Removing a tag can be undone; the note detail refreshes its tags as usual
+	case models.NoteTagRemovedMsg:
+		cmds = append(cmds, m.pushUndo("Removed #"+msg.TagName, m.readdTagCmd(msg.NoteID, msg.TagID, msg.TagName)))
+
+	case models.NoteListLayoutMsg:
+		m.layout.NoteList = msg.Layout
+		return m, m.scheduleLayoutSave()
+
+	case layoutSaveMsg:
+		// Only the last of a burst of changes (e.g. resizing step by step) is written
+		if msg.id != m.layoutSaveID || m.saveLayout == nil {
+			return m, nil
+		}
+		return m, m.saveLayoutCmd()
+
+	case undoExpiredMsg:
+		m.expireUndo(msg.id)
+		return m, nil
+
+	case models.ReadProgressSavedMsg:
+		// The dashboard's continue reading list is fetched again when it is shown next
+		m.dashboardInitialized = false
+		return m, nil
+
+	case focusTickMsg:
+		return m, m.tickFocus(msg.id)
+
+	case focusLoggedMsg:
+		return m, m.focusLogged(msg)
+
+	case undoneMsg:
+		if msg.err != nil {
+			m.statusBar.ShowError(msg.err.Error())
+			return m, nil
+		}
+		m.statusBar.ShowInfo(msg.info)
+		clearCmd := tea.Tick(time.Second*2, func(t time.Time) tea.Msg {
+			return clearErrorMsg{}
+		})
+		if msg.refresh == nil {
+			return m, clearCmd
+		}
+		model, cmd := m.Update(msg.refresh)
+		return model, tea.Batch(cmd, clearCmd)
+
+	case models.NoteCopiedMsg:
+		if msg.Err != nil {
+			m.statusBar.ShowError(fmt.Sprintf("Copy failed: %v", msg.Err))
+		} else {
+			m.statusBar.ShowInfo(fmt.Sprintf("Copied %s of %q", msg.Field, msg.Title))
+		}
+		return m, tea.Tick(time.Second*2, func(t time.Time) tea.Msg {
+			return clearErrorMsg{}
 		})
 
 	// Handle note create error
@@ -452,6 +757,60 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.statusBar.ShowError(msg.Err.Error())
 		return m, nil
 
+	case models.TasksErrMsg:
+		m.statusBar.ShowError(msg.Err.Error())
+		return m, nil
+
+	case models.SessionsErrMsg:
+		m.statusBar.ShowError(msg.Err.Error())
+		return m, nil
+
+	case models.SessionRevokedMsg:
+		m.statusBar.ShowInfo("Session revoked")
+		model, cmd := m.sessionsModel.Update(msg)
+		m.sessionsModel = model.(models.SessionsModel)
+		// Clear notification after a brief delay
+		return m, tea.Batch(cmd, tea.Tick(time.Second*2, func(t time.Time) tea.Msg {
+			return clearErrorMsg{}
+		}))
+
+	case models.PublishedErrMsg:
+		m.statusBar.ShowError(msg.Err.Error())
+		model, cmd := m.publishedModel.Update(msg)
+		m.publishedModel = model.(models.PublishedModel)
+		return m, cmd
+
+	case models.PublicLinkRevokedMsg:
+		m.statusBar.ShowInfo(fmt.Sprintf("Public link of %q revoked", msg.Title))
+		model, cmd := m.publishedModel.Update(msg)
+		m.publishedModel = model.(models.PublishedModel)
+		// Clear notification after a brief delay
+		return m, tea.Batch(cmd, tea.Tick(time.Second*2, func(t time.Time) tea.Msg {
+			return clearErrorMsg{}
+		}))
+
+	case models.TagAnalyticsErrMsg:
+		m.statusBar.ShowError(msg.Err.Error())
+		model, cmd := m.analyticsModel.Update(msg)
+		m.analyticsModel = model.(models.TagAnalyticsModel)
+		return m, cmd
+
+	case models.ReviewErrMsg:
+		m.statusBar.ShowError(msg.Err.Error())
+		model, cmd := m.reviewModel.Update(msg)
+		m.reviewModel = model.(models.ReviewModel)
+		return m, cmd
+
+	case models.PublicLinkCopiedMsg:
+		if msg.Err != nil {
+			m.statusBar.ShowError(fmt.Sprintf("Copy failed: %v", msg.Err))
+		} else {
+			m.statusBar.ShowInfo("Copied " + msg.URL)
+		}
+		return m, tea.Tick(time.Second*2, func(t time.Time) tea.Msg {
+			return clearErrorMsg{}
+		})
+
 	// Handle filter notes by tag message
 	case models.FilterNotesByTagMsg:
 		m.prevView = m.currentView
@@ -481,7 +840,7 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.quitting = true
 		// Display session expired message before quitting
 		return m, tea.Sequence(
-			tea.Printf("\n"+FatalStyle.Render("Session Expired")+"\n"),
+			tea.Printf("\n%s\n", FatalStyle.Render("Session Expired")),
 			tea.Quit,
 		)
 
@@ -507,37 +866,81 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return clearErrorMsg{}
 		})
 
+	// Handle quick switcher
+	case models.QuickSwitchIndexMsg, models.QuickSwitchIndexErrMsg:
+		model, cmd := m.quickSwitchModel.Update(msg)
+		m.quickSwitchModel = model.(models.QuickSwitchModel)
+		return m, cmd
+
+	case models.CloseQuickSwitchMsg:
+		m.showQuickSwitch = false
+		return m, nil
+
+	case models.QuickSwitchSelectedMsg:
+		m.showQuickSwitch = false
+		return m.Update(models.OpenNoteMsg{NoteID: msg.NoteID})
+
+	case models.ThemeSelectedMsg:
+		m.showQuickSwitch = false
+		if !setTheme(msg.Name) {
+			m.statusBar.ShowError(fmt.Sprintf("Unknown theme %q", msg.Name))
+			return m, nil
+		}
+		m.statusBar.ShowInfo("Theme: " + msg.Name)
+		return m, tea.Batch(m.saveThemeCmd(msg.Name), tea.Tick(time.Second*2, func(t time.Time) tea.Msg {
+			return clearErrorMsg{}
+		}))
+
 	// Handle clearing errors
 	case clearErrorMsg:
 		m.currentError = nil
 		m.statusBar.ClearError()
 		return m, nil
 
+	// Handle live updates
+	case eventsConnectedMsg:
+		m.events = msg.Events
+		m.eventsRetry = eventsRetryMin
+		return m, waitForEventCmd(m.events)
+
+	case eventsDisconnectedMsg:
+		// Retry with backoff; views still refresh on re-entry meanwhile
+		m.events = nil
+		retry := m.eventsRetry
+		m.eventsRetry = min(m.eventsRetry*2, eventsRetryMax)
+		return m, tea.Tick(retry, func(t time.Time) tea.Msg {
+			return eventsReconnectMsg{}
+		})
+
+	case eventsReconnectMsg:
+		return m, m.connectEventsCmd()
+
+	case serverEventMsg:
+		next := []tea.Cmd{waitForEventCmd(m.events)}
+		if msg.Event.Type != model.EventActivity {
+			m.liveNotesChanged = true
+		}
+		if !m.liveRefreshPending {
+			m.liveRefreshPending = true
+			next = append(next, tea.Tick(liveRefreshDelay, func(t time.Time) tea.Msg {
+				return liveRefreshMsg{}
+			}))
+		}
+		return m, tea.Batch(next...)
+
+	case liveRefreshMsg:
+		notesChanged := m.liveNotesChanged
+		m.liveRefreshPending = false
+		m.liveNotesChanged = false
+		return m, m.liveRefreshCmd(notesChanged)
+
 	// Handle window resize
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
 		m.statusBar.SetWidth(msg.Width)
 		m.helpModel.SetSize(msg.Width, msg.Height)
-		// Also update child models' internal sizes
-		var model tea.Model
-		var _ tea.Cmd
-		model, _ = m.dashboardModel.Update(msg)
-		m.dashboardModel = model.(models.DashboardModel)
-		model, _ = m.noteListModel.Update(msg)
-		m.noteListModel = model.(models.NoteListModel)
-		model, _ = m.noteDetailModel.Update(msg)
-		m.noteDetailModel = model.(models.NoteDetailModel)
-		model, _ = m.noteCreateModel.Update(msg)
-		m.noteCreateModel = model.(models.NoteCreateModel)
-		model, _ = m.tagListModel.Update(msg)
-		m.tagListModel = model.(models.TagListModel)
-		model, _ = m.searchModel.Update(msg)
-		m.searchModel = model.(models.SearchModel)
-		model, _ = m.activityModel.Update(msg)
-		m.activityModel = model.(models.ActivityModel)
-		model, _ = m.graphModel.Update(msg)
-		m.graphModel = model.(models.GraphModel)
+		m.resizeViews()
 		return m, nil
 
 	// Handle tea.Quit (from child models)
@@ -608,6 +1011,40 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		model, cmd = m.graphModel.Update(msg)
 		m.graphModel = model.(models.GraphModel)
 
+	case TasksView:
+		// Let task list handle its own messages
+		model, cmd = m.taskListModel.Update(msg)
+		m.taskListModel = model.(models.TaskListModel)
+
+	case SessionsView:
+		// Let sessions handle their own messages
+		model, cmd = m.sessionsModel.Update(msg)
+		m.sessionsModel = model.(models.SessionsModel)
+
+	case BoardView:
+		// Let the board handle its own messages
+		model, cmd = m.boardModel.Update(msg)
+		m.boardModel = model.(models.BoardModel)
+
+	case CalendarView:
+		// Let the calendar handle its own messages
+		model, cmd = m.calendarModel.Update(msg)
+		m.calendarModel = model.(models.CalendarModel)
+
+	case PublishedView:
+		// Let the published notes list handle its own messages
+		model, cmd = m.publishedModel.Update(msg)
+		m.publishedModel = model.(models.PublishedModel)
+
+	case TagAnalyticsView:
+		// Let tag analytics handle its own messages
+		model, cmd = m.analyticsModel.Update(msg)
+		m.analyticsModel = model.(models.TagAnalyticsModel)
+	case ReviewView:
+		// Let the review handle its own messages
+		model, cmd = m.reviewModel.Update(msg)
+		m.reviewModel = model.(models.ReviewModel)
+
 	default:
 		// Unknown view, do nothing
 	}
@@ -660,12 +1097,31 @@ func (m MainModel) View() string {
 		content = m.activityModel.View()
 	case GraphView:
 		content = m.graphModel.View()
+	case TasksView:
+		content = m.taskListModel.View()
+	case SessionsView:
+		content = m.sessionsModel.View()
+	case BoardView:
+		content = m.boardModel.View()
+	case CalendarView:
+		content = m.calendarModel.View()
+	case PublishedView:
+		content = m.publishedModel.View()
+	case TagAnalyticsView:
+		content = m.analyticsModel.View()
+	case ReviewView:
+		content = m.reviewModel.View()
 	default:
 		// Unknown view
 		content := "\n  Unknown view\n  Press ? for help\n"
 		content = DimStyle.Render(content)
 	}
 
+	// Quick switcher overlays the current view
+	if m.showQuickSwitch {
+		content = m.quickSwitchModel.View()
+	}
+
 	// Calculate content height
 	headerLines := 2
 	statusBarLines := 1
@@ -715,6 +1171,59 @@ func (m MainModel) checkSessionCmd() tea.Cmd {
 	}
 }
 
+// connectEventsCmd returns a command that opens the live update stream
+// The stream stays open for the lifetime of the TUI.
+func (m MainModel) connectEventsCmd() tea.Cmd {
+	return func() tea.Msg {
+		events, err := m.client.SubscribeEvents(context.Background())
+		if err != nil {
+			return eventsDisconnectedMsg{}
+		}
+		return eventsConnectedMsg{Events: events}
+	}
+}
+
+// waitForEventCmd returns a command that waits for the next server event
+func waitForEventCmd(events <-chan *model.Event) tea.Cmd {
+	return func() tea.Msg {
+		event, ok := <-events
+		if !ok {
+			return eventsDisconnectedMsg{}
+		}
+		return serverEventMsg{Event: event}
+	}
+}
+
+// liveRefreshCmd refetches the data of the current view after server events
+// Other views pick up changes when they are opened again.
+func (m MainModel) liveRefreshCmd(notesChanged bool) tea.Cmd {
+	switch m.currentView {
+	case DashboardView:
+		return m.dashboardModel.Init()
+	case ActivityView:
+		if m.activityInitialized {
+			return m.activityModel.Init()
+		}
+	case NoteListView:
+		if notesChanged && m.noteListInitialized {
+			return m.noteListModel.Init()
+		}
+	case TasksView:
+		if notesChanged && m.taskListInitialized {
+			return m.taskListModel.Init()
+		}
+	case BoardView:
+		if notesChanged && m.boardInitialized {
+			return m.boardModel.Init()
+		}
+	case CalendarView:
+		if notesChanged && m.calendarInitialized {
+			return m.calendarModel.Init()
+		}
+	}
+	return nil
+}
+
 // formatDuration formats a duration in a human-readable way
 func formatDuration(d time.Duration) string {
 	if d < time.Minute {
@@ -738,13 +1247,16 @@ func formatDuration(d time.Duration) string {
 // This helps free memory for views with large data structures
 func (m *MainModel) cleanupView(view View) {
 	switch view {
+	case NoteDetailView:
+		// Keep the note open, only remember where it was left
+		m.noteDetailModel.SaveReadingPosition()
 	case DashboardView:
 		// Clear dashboard to force refresh on next visit
 		m.dashboardModel = models.NewDashboardModel(m.client, m.authState)
 		m.dashboardInitialized = false
 	case NoteListView:
 		// Clear note list to force refresh on next visit
-		m.noteListModel = models.NewNoteListModel(m.client, m.authState)
+		m.noteListModel = models.NewNoteListModel(m.client, m.authState, m.layout.NoteList)
 		m.noteListInitialized = false
 	case NoteCreateView:
 		// Clear create note form and remove focus
@@ -762,6 +1274,34 @@ func (m *MainModel) cleanupView(view View) {
 		// Clear activity feed (can accumulate over time)
 		m.activityModel = models.NewActivityModel(m.client, m.authState)
 		m.activityInitialized = false
+	case TasksView:
+		// Clear tasks so they are refetched on the next visit
+		m.taskListModel = models.NewTaskListModel(m.client, m.authState)
+		m.taskListInitialized = false
+	case SessionsView:
+		// Clear sessions so they are refetched on the next visit
+		m.sessionsModel = models.NewSessionsModel(m.client, m.authState)
+		m.sessionsInitialized = false
+	case BoardView:
+		// Clear the board so it is refetched on the next visit
+		m.boardModel = models.NewBoardModel(m.client, m.authState)
+		m.boardInitialized = false
+	case CalendarView:
+		// Clear the calendar so it opens on the current month next time
+		m.calendarModel = models.NewCalendarModel(m.client, m.authState)
+		m.calendarInitialized = false
+	case PublishedView:
+		// Clear published notes so they are refetched on the next visit
+		m.publishedModel = models.NewPublishedModel(m.client, m.authState)
+		m.publishedInitialized = false
+	case TagAnalyticsView:
+		// Clear tag analytics so they are refetched on the next visit
+		m.analyticsModel = models.NewTagAnalyticsModel(m.client, m.authState)
+		m.analyticsInitialized = false
+	case ReviewView:
+		// Fetch due cards again on the next visit, grades may have made some due tomorrow
+		m.reviewModel = models.NewReviewModel(m.client, m.authState)
+		m.reviewInitialized = false
 	case SearchView:
 		// Clear search and remove focus
 		m.searchModel = m.searchModel.BlurInput()
@@ -773,6 +1313,50 @@ func (m *MainModel) cleanupView(view View) {
 		// Note: We don't clear NoteDetailView
 		// as it is commonly used and clearing it would disrupt user workflow
 	}
+	m.resizeViews()
+}
+
+// resizeViews sizes the child models for the terminal
+// Models are recreated with 80x24 defaults, so new ones are resized too.
+func (m *MainModel) resizeViews() {
+	if m.width == 0 {
+		return // No size from the terminal yet
+	}
+	msg := tea.WindowSizeMsg{Width: m.width, Height: m.height}
+
+	var model tea.Model
+	model, _ = m.dashboardModel.Update(msg)
+	m.dashboardModel = model.(models.DashboardModel)
+	model, _ = m.noteListModel.Update(msg)
+	m.noteListModel = model.(models.NoteListModel)
+	model, _ = m.noteDetailModel.Update(msg)
+	m.noteDetailModel = model.(models.NoteDetailModel)
+	model, _ = m.noteCreateModel.Update(msg)
+	m.noteCreateModel = model.(models.NoteCreateModel)
+	model, _ = m.tagListModel.Update(msg)
+	m.tagListModel = model.(models.TagListModel)
+	model, _ = m.searchModel.Update(msg)
+	m.searchModel = model.(models.SearchModel)
+	model, _ = m.activityModel.Update(msg)
+	m.activityModel = model.(models.ActivityModel)
+	model, _ = m.graphModel.Update(msg)
+	m.graphModel = model.(models.GraphModel)
+	model, _ = m.taskListModel.Update(msg)
+	m.taskListModel = model.(models.TaskListModel)
+	model, _ = m.sessionsModel.Update(msg)
+	m.sessionsModel = model.(models.SessionsModel)
+	model, _ = m.boardModel.Update(msg)
+	m.boardModel = model.(models.BoardModel)
+	model, _ = m.calendarModel.Update(msg)
+	m.calendarModel = model.(models.CalendarModel)
+	model, _ = m.publishedModel.Update(msg)
+	m.publishedModel = model.(models.PublishedModel)
+	model, _ = m.analyticsModel.Update(msg)
+	m.analyticsModel = model.(models.TagAnalyticsModel)
+	model, _ = m.reviewModel.Update(msg)
+	m.reviewModel = model.(models.ReviewModel)
+	model, _ = m.quickSwitchModel.Update(msg)
+	m.quickSwitchModel = model.(models.QuickSwitchModel)
 }
 
 // countLines counts the number of lines in a string
@@ -799,6 +1383,36 @@ func (m MainModel) IsSessionValidForTesting() bool {
 	return m.sessionValid
 }
 
+// saveThemeCmd saves the theme to the account settings, so the next session starts with it
+func (m MainModel) saveThemeCmd(name string) tea.Cmd {
+	return func() tea.Msg {
+		if _, err := m.client.UpdateSettings(&model.UpdateSettingsRequest{Theme: &name}); err != nil {
+			return errorMsg{Error: fmt.Errorf("save theme: %w", err)}
+		}
+		return nil
+	}
+}
+
+// scheduleLayoutSave saves the layouts after a short delay
+func (m *MainModel) scheduleLayoutSave() tea.Cmd {
+	m.layoutSaveID++
+	id := m.layoutSaveID
+	return tea.Tick(layoutSaveDelay, func(time.Time) tea.Msg {
+		return layoutSaveMsg{id: id}
+	})
+}
+
+// saveLayoutCmd returns a command that saves the layouts to the CLI config
+func (m MainModel) saveLayoutCmd() tea.Cmd {
+	layout, save := m.layout, m.saveLayout
+	return func() tea.Msg {
+		if err := save(layout); err != nil {
+			return errorMsg{Error: fmt.Errorf("save layout: %w", err)}
+		}
+		return nil
+	}
+}
+
 // isInputFocused checks if the current view has a focused input component
 // FIX: This prevents global navigation keys from consuming typing input
 func (m MainModel) isInputFocused() bool {
@@ -809,6 +1423,8 @@ func (m MainModel) isInputFocused() bool {
 		return m.searchModel.IsInputFocused()
 	case TagListView:
 		return m.tagListModel.IsInputFocused()
+	case NoteListView:
+		return m.noteListModel.IsInputFocused()
 	case NoteDetailView:
 		return m.noteDetailModel.IsInputFocused()
 	default:
@@ -20,10 +20,24 @@ type ActivityModel struct {
 	err           error
 	selectedIndex int
 	paginator     components.Paginator
+	page          int
+	limit         int
+	total         int64
+	actionFilter  *model.ActionType // nil = all actions
 	width         int
 	height        int
 }
 
+// activityFilterActions are the action filters cycled with "f", after "all"
+var activityFilterActions = []model.ActionType{
+	model.ActionCreate,
+	model.ActionUpdate,
+	model.ActionDelete,
+	model.ActionView,
+	model.ActionSearch,
+	model.ActionFocus,
+}
+
 // NewActivityModel creates a new activity model
 func NewActivityModel(apiClient *client.APIClient, authState *client.AuthState) ActivityModel {
 	paginator := components.NewPaginator()
@@ -33,6 +47,8 @@ func NewActivityModel(apiClient *client.APIClient, authState *client.AuthState)
 		client:    apiClient,
 		authState: authState,
 		paginator: paginator,
+		page:      1,
+		limit:     20,
 		width:     80,
 		height:    24,
 	}
@@ -43,15 +59,34 @@ func (m ActivityModel) Init() tea.Cmd {
 	return m.fetchActivityCmd()
 }
 
-// fetchActivityCmd returns a command that fetches recent activity
+// fetchActivityCmd returns a command that fetches the current page of activity
 func (m ActivityModel) fetchActivityCmd() tea.Cmd {
 	return func() tea.Msg {
-		activities, err := m.client.GetRecentActivity(50) // Get last 50 activities
+		filter := model.ActivityFilter{
+			Page:   m.page,
+			Limit:  m.limit,
+			Action: m.actionFilter,
+		}
+
+		activities, total, err := m.client.ListActivity(filter)
 		if err != nil {
 			return ActivityErrMsg{Err: err}
 		}
-		return ActivityFetchedMsg{Activities: activities}
+		return ActivityFetchedMsg{Activities: activities, Total: total}
+	}
+}
+
+// nextActionFilter returns the action filter after the current one, wrapping back to all actions
+func (m ActivityModel) nextActionFilter() *model.ActionType {
+	if m.actionFilter == nil {
+		return &activityFilterActions[0]
+	}
+	for i, action := range activityFilterActions {
+		if action == *m.actionFilter && i+1 < len(activityFilterActions) {
+			return &activityFilterActions[i+1]
+		}
 	}
+	return nil
 }
 
 // Update handles messages for the activity model
@@ -70,8 +105,7 @@ func (m ActivityModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return ShowDashboardMsg{}
 			}
 		case "j", "down":
-			_, endIdx := m.paginator.ItemsOnPage(len(m.activities))
-			if m.selectedIndex < endIdx-1 {
+			if m.selectedIndex < len(m.activities)-1 {
 				m.selectedIndex++
 			}
 		case "k", "up":
@@ -81,20 +115,31 @@ func (m ActivityModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "ctrl+n", "right":
 			// Next page
 			if m.paginator.CanGoNext() {
+				m.page++
 				m.paginator.NextPage()
-				startIdx, _ := m.paginator.ItemsOnPage(len(m.activities))
-				m.selectedIndex = startIdx
+				m.selectedIndex = 0
+				return m, m.fetchActivityCmd()
 			}
+			return m, nil
 		case "ctrl+p", "left":
 			// Previous page
 			if m.paginator.CanGoPrev() {
+				m.page--
 				m.paginator.PrevPage()
-				startIdx, _ := m.paginator.ItemsOnPage(len(m.activities))
-				m.selectedIndex = startIdx
+				m.selectedIndex = 0
+				return m, m.fetchActivityCmd()
 			}
+			return m, nil
+		case "f":
+			// Cycle the action filter, starting over from the first page
+			m.actionFilter = m.nextActionFilter()
+			m.page = 1
+			m.selectedIndex = 0
+			m.loading = true
+			return m, m.fetchActivityCmd()
 		case "enter":
 			// Open the note associated with this activity
-			if len(m.activities) > 0 && m.selectedIndex >= 0 {
+			if m.selectedIndex >= 0 && m.selectedIndex < len(m.activities) {
 				activity := m.activities[m.selectedIndex]
 				if activity.NoteID != nil && *activity.NoteID != uuid.Nil {
 					return m, func() tea.Msg {
@@ -106,9 +151,16 @@ func (m ActivityModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case ActivityFetchedMsg:
 		m.activities = msg.Activities
+		m.total = msg.Total
 		m.loading = false
-		m.selectedIndex = 0
-		m.paginator.SetTotalItems(len(msg.Activities))
+		m.err = nil
+		// Keep the selection across live refreshes
+		if m.selectedIndex >= len(msg.Activities) {
+			m.selectedIndex = 0
+		}
+		m.paginator.SetPerPage(m.limit)
+		m.paginator.SetTotalItems(int(msg.Total))
+		m.paginator.SetPage(m.page)
 		return m, nil
 
 	case ActivityErrMsg:
@@ -122,9 +174,6 @@ func (m ActivityModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	// Update paginator
-	_ = m.paginator.Update(msg)
-
 	return m, nil
 }
 
@@ -144,7 +193,7 @@ func (m ActivityModel) View() string {
 // renderLoading renders the loading state
 func (m ActivityModel) renderLoading() string {
 	style := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#89b4fa")). // Blue
+		Foreground(theme().Primary).
 		Bold(true)
 
 	return style.Render("Loading activity...")
@@ -153,7 +202,7 @@ func (m ActivityModel) renderLoading() string {
 // renderError renders the error state
 func (m ActivityModel) renderError() string {
 	errorStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#f38ba8")). // Red
+		Foreground(theme().Error).
 		Bold(true)
 
 	return errorStyle.Render("Error: " + m.err.Error())
@@ -163,64 +212,64 @@ func (m ActivityModel) renderError() string {
 func (m ActivityModel) renderContent() string {
 	// Define styles
 	titleStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#fab387")). // Orange
+		Foreground(theme().Secondary).
 		Bold(true).
 		MarginBottom(1)
 
 	activityStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#cdd6f4")) // Light text
+		Foreground(theme().Foreground)
 
 	selectedStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#1e1e2e")). // Dark
-		Background(lipgloss.Color("#89b4fa")). // Blue
+		Foreground(theme().Background).
+		Background(theme().Primary).
 		Bold(true)
 
 	timestampStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#6c7086")). // Gray
+		Foreground(theme().Muted).
 		Faint(true)
 
 	actionCreateStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#a6e3a1")). // Green
+		Foreground(theme().Accent).
 	Bold(true)
 
 	actionUpdateStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#89b4fa")). // Blue
+		Foreground(theme().Primary).
 	Bold(true)
 
 	actionDeleteStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#f38ba8")). // Red
+		Foreground(theme().Error).
 	Bold(true)
 
 	actionViewStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#cba6f7")). // Purple
+		Foreground(theme().Special).
 	Faint(true)
 
 	mutedStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#6c7086")). // Gray
+		Foreground(theme().Muted).
 		Faint(true)
 
 	hintStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#6c7086")). // Gray
+		Foreground(theme().Muted).
 		Faint(true).
 		MarginTop(1)
 
 	var content string
 
 	// Title
-	content += titleStyle.Render("RECENT ACTIVITY") + "\n\n"
+	title := "RECENT ACTIVITY"
+	if m.actionFilter != nil {
+		title += " · " + formatActivityAction(*m.actionFilter)
+	}
+	content += titleStyle.Render(title) + "\n\n"
 
 	if len(m.activities) == 0 {
 		content += mutedStyle.Render("(no recent activity)")
 		content += "\n\n"
-		content += hintStyle.Render("ESC:back ?:help")
+		content += hintStyle.Render("f:filter ESC:back ?:help")
 		return content
 	}
 
-	// Get current page range
-	startIdx, endIdx := m.paginator.ItemsOnPage(len(m.activities))
-
-	for i := startIdx; i < endIdx; i++ {
-		activity := m.activities[i]
+	for i, activity := range m.activities {
 		var line string
 
 		// Selection indicator
@@ -243,6 +292,9 @@ func (m ActivityModel) renderContent() string {
 		if activity.NoteID != nil && *activity.NoteID != uuid.Nil {
 			noteInfo = " note"
 		}
+		if activity.Action == model.ActionFocus {
+			noteInfo += " for " + formatFocusTime(focusSeconds(activity.Metadata))
+		}
 
 		// Build full line
 		fullLine := fmt.Sprintf("%s%s%s", line, actionText, noteInfo)
@@ -257,13 +309,12 @@ func (m ActivityModel) renderContent() string {
 	}
 
 	// Paginator
-	perPage := 20
-	if len(m.activities) > perPage {
+	if m.paginator.TotalPages() > 1 {
 		content += "\n" + m.paginator.View()
 	}
 
 	// Hints
-	content += "\n" + hintStyle.Render("j/k:navigate Enter:open Ctrl+N/P:page ESC:back ?:help")
+	content += "\n" + hintStyle.Render("j/k:navigate Enter:open Ctrl+N/P:page f:filter ESC:back ?:help")
 
 	return content
 }
@@ -301,6 +352,8 @@ func formatActivityAction(action model.ActionType) string {
 		return "Logged in"
 	case model.ActionLogout:
 		return "Logged out"
+	case model.ActionFocus:
+		return "Focused"
 	default:
 		return string(action)
 	}
@@ -310,6 +363,7 @@ func formatActivityAction(action model.ActionType) string {
 
 type ActivityFetchedMsg struct {
 	Activities []*model.Activity
+	Total      int64
 }
 
 type ActivityErrMsg struct {
@@ -0,0 +1,332 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/google/uuid"
+	"github.com/momokii/go-cli-notes/cmd/cli/client"
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+// boardNoteLimit is how many notes the board loads, the most the API returns in one page
+const boardNoteLimit = 100
+
+// BoardModel lays out the notes with a status in todo, doing and done columns
+type BoardModel struct {
+	client    *client.APIClient
+	authState *client.AuthState
+	columns   [][]*model.Note // One column per model.NoteStatuses entry
+	column    int             // Column of the selected card
+	cursors   []int           // Selected card of each column
+	loading   bool
+	err       error
+	status    string
+	width     int
+	height    int
+}
+
+// NewBoardModel creates a new board model
+func NewBoardModel(apiClient *client.APIClient, authState *client.AuthState) BoardModel {
+	return BoardModel{
+		client:    apiClient,
+		authState: authState,
+		columns:   make([][]*model.Note, len(model.NoteStatuses)),
+		cursors:   make([]int, len(model.NoteStatuses)),
+		loading:   true,
+		width:     80,
+		height:    24,
+	}
+}
+
+// Init initializes the board model
+func (m BoardModel) Init() tea.Cmd {
+	return m.fetchNotesCmd()
+}
+
+// fetchNotesCmd returns a command that fetches every note with a status
+func (m BoardModel) fetchNotesCmd() tea.Cmd {
+	status := model.NoteStatusAny
+	return func() tea.Msg {
+		notes, _, err := m.client.ListNotes(model.NoteFilter{
+			Page:      1,
+			Limit:     boardNoteLimit,
+			Status:    &status,
+			SortBy:    model.SortUpdatedAt,
+			SortOrder: "desc",
+		})
+		if err != nil {
+			return BoardErrMsg{Err: err}
+		}
+		return BoardFetchedMsg{Notes: notes}
+	}
+}
+
+// moveCardCmd returns a command that saves the new status of a moved card
+func (m BoardModel) moveCardCmd(noteID uuid.UUID, status model.NoteStatus) tea.Cmd {
+	return func() tea.Msg {
+		err := m.client.UpdateNote(noteID, &model.UpdateNoteRequest{
+			Metadata: model.Metadata{model.MetadataStatus: string(status)},
+		})
+		return BoardCardMovedMsg{Status: status, Err: err}
+	}
+}
+
+// selected returns the selected card, or nil when its column is empty
+func (m BoardModel) selected() *model.Note {
+	cards := m.columns[m.column]
+	if len(cards) == 0 {
+		return nil
+	}
+	return cards[m.cursors[m.column]]
+}
+
+// moveCard moves the selected card by offset columns and saves its new status
+func (m BoardModel) moveCard(offset int) (BoardModel, tea.Cmd) {
+	note := m.selected()
+	target := m.column + offset
+	if note == nil || target < 0 || target >= len(m.columns) {
+		return m, nil
+	}
+
+	// Move the card right away; a failed save refetches the board
+	from := m.columns[m.column]
+	cursor := m.cursors[m.column]
+	m.columns[m.column] = append(from[:cursor:cursor], from[cursor+1:]...)
+	m.cursors[m.column] = min(cursor, max(len(m.columns[m.column])-1, 0))
+
+	status := model.NoteStatuses[target]
+	if note.Metadata == nil {
+		note.Metadata = model.Metadata{}
+	}
+	note.Metadata[model.MetadataStatus] = string(status)
+	m.columns[target] = append([]*model.Note{note}, m.columns[target]...)
+	m.column = target
+	m.cursors[target] = 0
+	m.status = ""
+
+	return m, m.moveCardCmd(note.ID, status)
+}
+
+// Update handles messages for the board model
+func (m BoardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c":
+			return m, tea.Quit
+		case "?":
+			return m, func() tea.Msg {
+				return ShowHelpMsg{}
+			}
+		case "esc":
+			return m, func() tea.Msg {
+				return ShowDashboardMsg{}
+			}
+		case "left", "shift+tab":
+			if m.column > 0 {
+				m.column--
+			}
+		case "right", "tab":
+			if m.column < len(m.columns)-1 {
+				m.column++
+			}
+		case "j", "down":
+			if m.cursors[m.column] < len(m.columns[m.column])-1 {
+				m.cursors[m.column]++
+			}
+		case "k", "up":
+			if m.cursors[m.column] > 0 {
+				m.cursors[m.column]--
+			}
+		case "h":
+			// Move the card to the previous column
+			return m.moveCard(-1)
+		case "l":
+			// Move the card to the next column
+			return m.moveCard(1)
+		case "r":
+			// Refresh
+			m.loading = true
+			m.err = nil
+			return m, m.fetchNotesCmd()
+		case "enter":
+			if note := m.selected(); note != nil {
+				noteID := note.ID
+				return m, func() tea.Msg {
+					return OpenNoteMsg{NoteID: noteID}
+				}
+			}
+		}
+
+	case BoardFetchedMsg:
+		m.loading = false
+		for i := range m.columns {
+			m.columns[i] = nil
+		}
+		for _, note := range msg.Notes {
+			for i, status := range model.NoteStatuses {
+				if note.Metadata.Status() == status {
+					m.columns[i] = append(m.columns[i], note)
+				}
+			}
+		}
+		// Keep the selection across live refreshes
+		for i := range m.cursors {
+			m.cursors[i] = min(m.cursors[i], max(len(m.columns[i])-1, 0))
+		}
+		return m, nil
+
+	case BoardErrMsg:
+		m.err = msg.Err
+		m.loading = false
+		return m, nil
+
+	case BoardCardMovedMsg:
+		if msg.Err != nil {
+			m.status = "Move failed: " + msg.Err.Error()
+			return m, m.fetchNotesCmd()
+		}
+		m.status = "Moved to " + string(msg.Status)
+		return m, nil
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// View renders the board view
+func (m BoardModel) View() string {
+	if m.loading {
+		return m.renderLoading()
+	}
+
+	if m.err != nil {
+		return m.renderError()
+	}
+
+	return m.renderContent()
+}
+
+// renderLoading renders the loading state
+func (m BoardModel) renderLoading() string {
+	style := lipgloss.NewStyle().
+		Foreground(theme().Primary).
+		Bold(true)
+
+	return style.Render("Loading board...")
+}
+
+// renderError renders the error state
+func (m BoardModel) renderError() string {
+	errorStyle := lipgloss.NewStyle().
+		Foreground(theme().Error).
+		Bold(true)
+
+	return errorStyle.Render("Error: " + m.err.Error())
+}
+
+// renderContent renders the columns side by side
+func (m BoardModel) renderContent() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(theme().Secondary).
+		Bold(true).
+		MarginBottom(1)
+
+	hintStyle := lipgloss.NewStyle().
+		Foreground(theme().Muted).
+		Faint(true).
+		MarginTop(1)
+
+	mutedStyle := lipgloss.NewStyle().
+		Foreground(theme().Muted).
+		Faint(true)
+
+	content := titleStyle.Render("BOARD") + "\n\n"
+
+	empty := true
+	for _, cards := range m.columns {
+		if len(cards) > 0 {
+			empty = false
+		}
+	}
+	if empty {
+		content += mutedStyle.Render("(no notes on the board - give a note a status with kg-cli note update <id> --status todo)")
+		content += "\n\n"
+		content += hintStyle.Render("r:refresh ESC:back ?:help")
+		return content
+	}
+
+	// Columns share the width, each followed by a 2 cell gap
+	columnWidth := max(m.width/len(m.columns)-2, 16)
+	columns := make([]string, len(m.columns))
+	for i := range m.columns {
+		columns[i] = m.renderColumn(i, columnWidth)
+	}
+	content += lipgloss.JoinHorizontal(lipgloss.Top, columns...)
+
+	if m.status != "" {
+		content += "\n" + mutedStyle.Render(m.status)
+	}
+	content += "\n" + hintStyle.Render("←→/tab:column j/k:card h/l:move card Enter:open r:refresh ESC:back ?:help")
+
+	return content
+}
+
+// renderColumn renders one status column with its cards
+func (m BoardModel) renderColumn(i, width int) string {
+	headerStyle := lipgloss.NewStyle().
+		Foreground(theme().Muted).
+		Bold(true)
+	if i == m.column {
+		headerStyle = headerStyle.Foreground(theme().Primary)
+	}
+
+	cardStyle := lipgloss.NewStyle().
+		Foreground(theme().Foreground)
+
+	selectedStyle := lipgloss.NewStyle().
+		Foreground(theme().Background).
+		Background(theme().Primary).
+		Bold(true)
+
+	cards := m.columns[i]
+	lines := []string{headerStyle.Render(fmt.Sprintf("%s (%d)", strings.ToUpper(string(model.NoteStatuses[i])), len(cards)))}
+
+	// Leave room for the title, header, status and hint lines
+	visible := max(m.height-8, 1)
+	start := max(m.cursors[i]-visible+1, 0)
+	end := min(start+visible, len(cards))
+	for j := start; j < end; j++ {
+		title := truncateText(cards[j].Title, width-2)
+		if i == m.column && j == m.cursors[i] {
+			lines = append(lines, selectedStyle.Render("→ "+title))
+		} else {
+			lines = append(lines, cardStyle.Render("  "+title))
+		}
+	}
+
+	return lipgloss.NewStyle().Width(width).MarginRight(2).Render(strings.Join(lines, "\n"))
+}
+
+// Message types for the board
+
+type BoardFetchedMsg struct {
+	Notes []*model.Note
+}
+
+type BoardErrMsg struct {
+	Err error
+}
+
+// BoardCardMovedMsg reports whether the new status of a moved card was saved
+type BoardCardMovedMsg struct {
+	Status model.NoteStatus
+	Err    error
+}
@@ -0,0 +1,328 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/momokii/go-cli-notes/cmd/cli/client"
+	"github.com/momokii/go-cli-notes/internal/model"
+	"github.com/momokii/go-cli-notes/internal/util"
+)
+
+// calendarCellWidth is how many cells each day of the month grid takes
+const calendarCellWidth = 7
+
+// CalendarModel shows a month grid with the days that have a daily note
+type CalendarModel struct {
+	client    *client.APIClient
+	authState *client.AuthState
+	month     time.Time                      // First day of the shown month
+	selected  time.Time                      // Selected day, always in month
+	days      map[string]*model.DailyNoteDay // Daily notes of the month by date
+	weekStart time.Weekday
+	loading   bool
+	err       error
+	status    string
+	width     int
+	height    int
+}
+
+// NewCalendarModel creates a new calendar model showing the current month
+func NewCalendarModel(apiClient *client.APIClient, authState *client.AuthState) CalendarModel {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	return CalendarModel{
+		client:    apiClient,
+		authState: authState,
+		month:     firstOfMonth(today),
+		selected:  today,
+		days:      map[string]*model.DailyNoteDay{},
+		weekStart: time.Monday,
+		loading:   true,
+		width:     80,
+		height:    24,
+	}
+}
+
+// firstOfMonth returns the first day of the month of t
+func firstOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.Local)
+}
+
+// Init initializes the calendar model
+func (m CalendarModel) Init() tea.Cmd {
+	return m.fetchMonthCmd()
+}
+
+// fetchMonthCmd returns a command that fetches the daily notes of the shown month
+func (m CalendarModel) fetchMonthCmd() tea.Cmd {
+	month := m.month
+	return func() tea.Msg {
+		days, err := m.client.ListDailyNotes(month.Format(util.DailyMonthLayout))
+		if err != nil {
+			return CalendarErrMsg{Err: err}
+		}
+
+		// The week starts on Monday unless the user's settings say otherwise
+		weekStart := time.Monday
+		if settings, err := m.client.GetSettings(); err == nil && settings.WeekStart == "sunday" {
+			weekStart = time.Sunday
+		}
+
+		return CalendarFetchedMsg{Month: month, Days: days, WeekStart: weekStart}
+	}
+}
+
+// openDayCmd returns a command that opens the daily note of a day, creating it if needed
+func (m CalendarModel) openDayCmd(day time.Time) tea.Cmd {
+	date := day.Format(util.DailyDateLayout)
+	return func() tea.Msg {
+		note, _, err := m.client.GetDailyNote(date)
+		if err != nil {
+			return CalendarOpenErrMsg{Err: err}
+		}
+		return OpenNoteMsg{NoteID: note.ID}
+	}
+}
+
+// moveSelection moves the selected day, switching month when it leaves the shown one
+func (m CalendarModel) moveSelection(day time.Time) (CalendarModel, tea.Cmd) {
+	m.selected = day
+	m.status = ""
+	if month := firstOfMonth(day); !month.Equal(m.month) {
+		m.month = month
+		m.days = map[string]*model.DailyNoteDay{}
+		m.loading = true
+		return m, m.fetchMonthCmd()
+	}
+	return m, nil
+}
+
+// Update handles messages for the calendar model
+func (m CalendarModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c":
+			return m, tea.Quit
+		case "?":
+			return m, func() tea.Msg {
+				return ShowHelpMsg{}
+			}
+		case "esc":
+			return m, func() tea.Msg {
+				return ShowDashboardMsg{}
+			}
+		case "left", "h":
+			return m.moveSelection(m.selected.AddDate(0, 0, -1))
+		case "right", "l":
+			return m.moveSelection(m.selected.AddDate(0, 0, 1))
+		case "up", "k":
+			return m.moveSelection(m.selected.AddDate(0, 0, -7))
+		case "down", "j":
+			return m.moveSelection(m.selected.AddDate(0, 0, 7))
+		case "[", "pgup":
+			// Same day of the previous month, or its last day
+			return m.moveSelection(m.shiftMonth(-1))
+		case "]", "pgdown":
+			// Same day of the next month, or its last day
+			return m.moveSelection(m.shiftMonth(1))
+		case "T":
+			// Jump to today
+			now := time.Now()
+			return m.moveSelection(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local))
+		case "r":
+			// Refresh
+			m.loading = true
+			m.err = nil
+			return m, m.fetchMonthCmd()
+		case "enter":
+			m.status = "Opening daily note..."
+			return m, m.openDayCmd(m.selected)
+		}
+
+	case CalendarFetchedMsg:
+		// Drop months the user already moved away from
+		if !msg.Month.Equal(m.month) {
+			return m, nil
+		}
+		m.loading = false
+		m.err = nil
+		m.weekStart = msg.WeekStart
+		m.days = make(map[string]*model.DailyNoteDay, len(msg.Days))
+		for _, day := range msg.Days {
+			m.days[day.Date] = day
+		}
+		return m, nil
+
+	case CalendarErrMsg:
+		m.err = msg.Err
+		m.loading = false
+		return m, nil
+
+	case CalendarOpenErrMsg:
+		m.status = "Could not open daily note: " + msg.Err.Error()
+		return m, nil
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// shiftMonth returns the selected day moved by months, clamped to the length of the target month
+func (m CalendarModel) shiftMonth(months int) time.Time {
+	month := m.month.AddDate(0, months, 0)
+	lastDay := month.AddDate(0, 1, -1).Day()
+	return time.Date(month.Year(), month.Month(), min(m.selected.Day(), lastDay), 0, 0, 0, 0, time.Local)
+}
+
+// View renders the calendar view
+func (m CalendarModel) View() string {
+	if m.loading {
+		return m.renderLoading()
+	}
+
+	if m.err != nil {
+		return m.renderError()
+	}
+
+	return m.renderContent()
+}
+
+// renderLoading renders the loading state
+func (m CalendarModel) renderLoading() string {
+	style := lipgloss.NewStyle().
+		Foreground(theme().Primary).
+		Bold(true)
+
+	return style.Render("Loading calendar...")
+}
+
+// renderError renders the error state
+func (m CalendarModel) renderError() string {
+	errorStyle := lipgloss.NewStyle().
+		Foreground(theme().Error).
+		Bold(true)
+
+	return errorStyle.Render("Error: " + m.err.Error())
+}
+
+// renderContent renders the month grid and the selected day
+func (m CalendarModel) renderContent() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(theme().Secondary).
+		Bold(true).
+		MarginBottom(1)
+
+	hintStyle := lipgloss.NewStyle().
+		Foreground(theme().Muted).
+		Faint(true).
+		MarginTop(1)
+
+	mutedStyle := lipgloss.NewStyle().
+		Foreground(theme().Muted).
+		Faint(true)
+
+	content := titleStyle.Render("CALENDAR · "+m.month.Format("January 2006")) + "\n\n"
+	content += m.renderGrid(mutedStyle) + "\n\n"
+
+	date := m.selected.Format(util.DailyDateLayout)
+	if day, ok := m.days[date]; ok {
+		content += fmt.Sprintf("%s%s · %d words", util.DailyTitlePrefix, date, day.WordCount)
+	} else {
+		content += mutedStyle.Render(fmt.Sprintf("No daily note for %s yet - Enter creates it", date))
+	}
+
+	if m.status != "" {
+		content += "\n" + mutedStyle.Render(m.status)
+	}
+	content += "\n" + hintStyle.Render("←→↑↓:day [/]:month T:today Enter:open r:refresh ESC:back ?:help")
+
+	return content
+}
+
+// renderGrid renders one row per week, each day showing a marker and the word count of its daily note
+func (m CalendarModel) renderGrid(mutedStyle lipgloss.Style) string {
+	cellStyle := lipgloss.NewStyle().
+		Width(calendarCellWidth).
+		Foreground(theme().Foreground)
+
+	noteStyle := cellStyle.
+		Foreground(theme().Primary).
+		Bold(true)
+
+	selectedStyle := lipgloss.NewStyle().
+		Width(calendarCellWidth).
+		Foreground(theme().Background).
+		Background(theme().Primary).
+		Bold(true)
+
+	headerStyle := mutedStyle.Width(calendarCellWidth)
+
+	var header []string
+	for i := 0; i < 7; i++ {
+		weekday := time.Weekday((int(m.weekStart) + i) % 7)
+		header = append(header, headerStyle.Render(weekday.String()[:2]))
+	}
+	rows := []string{strings.Join(header, "")}
+
+	// Blank cells before the first day so it lines up with its weekday
+	offset := (int(m.month.Weekday()) - int(m.weekStart) + 7) % 7
+	lastDay := m.month.AddDate(0, 1, -1).Day()
+
+	var days, counts []string
+	for i := 0; i < offset; i++ {
+		days = append(days, cellStyle.Render(""))
+		counts = append(counts, cellStyle.Render(""))
+	}
+	for d := 1; d <= lastDay; d++ {
+		date := time.Date(m.month.Year(), m.month.Month(), d, 0, 0, 0, 0, time.Local)
+		day, hasNote := m.days[date.Format(util.DailyDateLayout)]
+
+		label := fmt.Sprintf("%2d", d)
+		count := ""
+		style := cellStyle
+		if hasNote {
+			label += " •"
+			count = fmt.Sprintf("%dw", day.WordCount)
+			style = noteStyle
+		}
+		if date.Equal(m.selected) {
+			style = selectedStyle
+		}
+		days = append(days, style.Render(label))
+		counts = append(counts, mutedStyle.Width(calendarCellWidth).Render(count))
+
+		if len(days) == 7 || d == lastDay {
+			rows = append(rows, strings.Join(days, ""), strings.Join(counts, ""))
+			days, counts = nil, nil
+		}
+	}
+
+	return strings.Join(rows, "\n")
+}
+
+// Message types for the calendar
+
+// CalendarFetchedMsg carries the daily notes of a month
+type CalendarFetchedMsg struct {
+	Month     time.Time
+	Days      []*model.DailyNoteDay
+	WeekStart time.Weekday
+}
+
+type CalendarErrMsg struct {
+	Err error
+}
+
+// CalendarOpenErrMsg reports a daily note that could not be opened or created
+type CalendarOpenErrMsg struct {
+	Err error
+}
@@ -2,27 +2,36 @@ package models
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/momokii/go-cli-notes/cmd/cli/client"
+	"github.com/momokii/go-cli-notes/cmd/cli/tui/components"
 	"github.com/momokii/go-cli-notes/internal/model"
 )
 
 // DashboardModel is the model for the dashboard view
 type DashboardModel struct {
-	client      *client.APIClient
-	authState   *client.AuthState
-	stats       *model.UserStats
-	activity    []*model.Activity
-	trending    []*model.TrendingNote
-	loading     bool
-	err         error
-	width       int
-	height      int
-	lastUpdate  time.Time
+	client        *client.APIClient
+	authState     *client.AuthState
+	stats         *model.UserStats
+	activity      []*model.Activity
+	trending      []*model.TrendingNote
+	forgotten     []*model.ForgottenNote
+	forgottenDays int // Threshold the forgotten notes were fetched with
+	reading       []*model.Note
+	inbox         *model.InboxStatus
+	streak        *model.WritingStreak
+	heatmap       *model.ActivityHeatmap
+	loading       bool
+	err           error
+	width         int
+	height        int
+	lastUpdate    time.Time
 }
 
 // NewDashboardModel creates a new dashboard model
@@ -42,6 +51,11 @@ func (m DashboardModel) Init() tea.Cmd {
 		m.fetchStatsCmd(),
 		m.fetchActivityCmd(),
 		m.fetchTrendingCmd(),
+		m.fetchStreakCmd(),
+		m.fetchHeatmapCmd(),
+		m.fetchForgottenCmd(),
+		m.fetchReadingCmd(),
+		m.fetchInboxCmd(),
 	)
 }
 
@@ -78,6 +92,74 @@ func (m DashboardModel) fetchTrendingCmd() tea.Cmd {
 	}
 }
 
+// fetchStreakCmd returns a command that fetches the writing streak
+// Failures only hide the writing goal section, the rest of the dashboard still loads.
+func (m DashboardModel) fetchStreakCmd() tea.Cmd {
+	return func() tea.Msg {
+		streak, err := m.client.GetWritingStreak()
+		if err != nil {
+			return nil
+		}
+		return dashboardStreakMsg{streak}
+	}
+}
+
+// fetchHeatmapCmd returns a command that fetches the activity heatmap
+// Failures only hide the heatmap, the rest of the dashboard still loads.
+func (m DashboardModel) fetchHeatmapCmd() tea.Cmd {
+	return func() tea.Msg {
+		heatmap, err := m.client.GetActivityHeatmap(heatmapWeeks)
+		if err != nil {
+			return nil
+		}
+		return dashboardHeatmapMsg{heatmap}
+	}
+}
+
+// resurfaceLimit is how many forgotten notes the dashboard resurfaces
+const resurfaceLimit = 5
+
+// fetchForgottenCmd returns a command that fetches notes not opened for the forgotten_days setting
+// Failures only hide the resurface section, the rest of the dashboard still loads.
+func (m DashboardModel) fetchForgottenCmd() tea.Cmd {
+	return func() tea.Msg {
+		days := m.client.Settings().ForgottenDays
+		forgotten, err := m.client.GetForgottenNotes(days, resurfaceLimit)
+		if err != nil {
+			return nil
+		}
+		return dashboardForgottenMsg{forgotten, days}
+	}
+}
+
+// readingLimit is how many partly read notes the dashboard offers to continue
+// They are opened with the keys after the resurfaced notes, 6 to 8.
+const readingLimit = 3
+
+// fetchReadingCmd returns a command that fetches the notes left partly read, last read first
+// Failures only hide the continue reading section, the rest of the dashboard still loads.
+func (m DashboardModel) fetchReadingCmd() tea.Cmd {
+	return func() tea.Msg {
+		reading, err := m.client.GetReadingNotes(readingLimit)
+		if err != nil {
+			return nil
+		}
+		return dashboardReadingMsg{reading}
+	}
+}
+
+// fetchInboxCmd returns a command that counts the captured items waiting in the Inbox
+// Failures only hide the inbox counter, the rest of the dashboard still loads.
+func (m DashboardModel) fetchInboxCmd() tea.Cmd {
+	return func() tea.Msg {
+		inbox, err := m.client.GetInbox()
+		if err != nil {
+			return nil
+		}
+		return dashboardInboxMsg{inbox}
+	}
+}
+
 // Update handles messages for the dashboard model
 func (m DashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
@@ -107,6 +189,35 @@ func (m DashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "a":
 			// Activity feed - Phase D
 			return m, nil
+		case "1", "2", "3", "4", "5":
+			// Open a resurfaced note
+			i := int(msg.String()[0] - '1')
+			if i < len(m.forgotten) && m.forgotten[i].Note != nil {
+				noteID := m.forgotten[i].Note.ID
+				return m, func() tea.Msg {
+					return OpenNoteMsg{NoteID: noteID}
+				}
+			}
+			return m, nil
+		case "6", "7", "8":
+			// Continue reading a partly read note
+			i := int(msg.String()[0] - '6')
+			if i < len(m.reading) {
+				noteID := m.reading[i].ID
+				return m, func() tea.Msg {
+					return OpenNoteMsg{NoteID: noteID}
+				}
+			}
+			return m, nil
+		case "i":
+			// Open the Inbox to process captured items
+			if m.inbox != nil && m.inbox.NoteID != nil {
+				noteID := *m.inbox.NoteID
+				return m, func() tea.Msg {
+					return OpenNoteMsg{NoteID: noteID}
+				}
+			}
+			return m, nil
 		}
 
 	case dashboardStatsMsg:
@@ -122,6 +233,27 @@ func (m DashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.trending = msg.trending
 		return m, nil
 
+	case dashboardHeatmapMsg:
+		m.heatmap = msg.heatmap
+		return m, nil
+
+	case dashboardStreakMsg:
+		m.streak = msg.streak
+		return m, nil
+
+	case dashboardForgottenMsg:
+		m.forgotten = msg.forgotten
+		m.forgottenDays = msg.days
+		return m, nil
+
+	case dashboardReadingMsg:
+		m.reading = msg.reading
+		return m, nil
+
+	case dashboardInboxMsg:
+		m.inbox = msg.inbox
+		return m, nil
+
 	case dashboardErrMsg:
 		m.err = msg.err
 		m.loading = false
@@ -148,27 +280,28 @@ func (m DashboardModel) View() string {
 
 	// Define styles
 	titleStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#fab387")). // Orange
+		Foreground(theme().Secondary).
 		Bold(true).
 		MarginBottom(1)
 
 	labelStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#89b4fa")). // Blue
+		Foreground(theme().Primary).
 		Bold(true).
-		Width(20)
+		Width(dashboardLabelWidth)
 
 	valueStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#cdd6f4")) // Light text
+		Foreground(theme().Foreground)
 
 	mutedStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#6c7086")). // Gray
+		Foreground(theme().Muted).
 		Faint(true)
 
 	boxStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#45475a")). // Dark gray
+		BorderForeground(theme().Border).
 		Padding(0, 1).
-		MarginBottom(1)
+		MarginBottom(1).
+		Width(m.boxWidth())
 
 	// Build content
 	var content string
@@ -177,14 +310,31 @@ func (m DashboardModel) View() string {
 	content += titleStyle.Render("STATISTICS")
 	content += "\n"
 	statsBox := m.renderStats(labelStyle, valueStyle)
-	content += boxStyle.Width(m.width).Render(statsBox)
+	content += boxStyle.Render(statsBox)
 	content += "\n\n"
 
+	// Writing goal section
+	if m.streak != nil {
+		content += titleStyle.Render("WRITING GOAL")
+		content += "\n"
+		streakBox := m.renderStreak(labelStyle, valueStyle, mutedStyle)
+		content += boxStyle.Render(streakBox)
+		content += "\n\n"
+	}
+
+	// Activity heatmap section
+	if m.heatmap != nil && len(m.heatmap.Days) > 0 {
+		content += titleStyle.Render(fmt.Sprintf("ACTIVITY (LAST %d WEEKS)", heatmapWeeks))
+		content += "\n"
+		content += boxStyle.Render(m.renderHeatmap(mutedStyle))
+		content += "\n\n"
+	}
+
 	// Recent Activity section - always show (even when empty)
 	content += titleStyle.Render("RECENT ACTIVITY")
 	content += "\n"
 	activityBox := m.renderActivity(labelStyle, valueStyle, mutedStyle)
-	content += boxStyle.Width(m.width).Render(activityBox)
+	content += boxStyle.Render(activityBox)
 	content += "\n\n"
 
 	// Trending Notes section
@@ -192,7 +342,25 @@ func (m DashboardModel) View() string {
 		content += titleStyle.Render("TRENDING NOTES")
 		content += "\n"
 		trendingBox := m.renderTrending(labelStyle, valueStyle, mutedStyle)
-		content += boxStyle.Width(m.width).Render(trendingBox)
+		content += boxStyle.Render(trendingBox)
+		content += "\n\n"
+	}
+
+	// Continue reading section, notes left partly read
+	if len(m.reading) > 0 {
+		content += titleStyle.Render("CONTINUE READING")
+		content += "\n"
+		readingBox := m.renderReading(labelStyle, valueStyle, mutedStyle)
+		content += boxStyle.Render(readingBox)
+		content += "\n\n"
+	}
+
+	// Resurface section, notes not opened in a while
+	if len(m.forgotten) > 0 {
+		content += titleStyle.Render(fmt.Sprintf("RESURFACE (NOT OPENED FOR %d+ DAYS)", m.forgottenDays))
+		content += "\n"
+		forgottenBox := m.renderForgotten(labelStyle, valueStyle, mutedStyle)
+		content += boxStyle.Render(forgottenBox)
 		content += "\n\n"
 	}
 
@@ -205,7 +373,7 @@ func (m DashboardModel) View() string {
 // renderLoading renders the loading state
 func (m DashboardModel) renderLoading() string {
 	style := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#89b4fa")). // Blue
+		Foreground(theme().Primary).
 		Bold(true)
 
 	return style.Render("Loading dashboard...")
@@ -214,7 +382,7 @@ func (m DashboardModel) renderLoading() string {
 // renderError renders the error state
 func (m DashboardModel) renderError() string {
 	errorStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#f38ba8")). // Red
+		Foreground(theme().Error).
 		Bold(true)
 
 	return errorStyle.Render(fmt.Sprintf("Error loading dashboard: %v", m.err))
@@ -241,6 +409,16 @@ func (m DashboardModel) renderStats(labelStyle, valueStyle lipgloss.Style) strin
 	stats += labelStyle.Render("Total Words:")
 	stats += valueStyle.Render(fmt.Sprintf("%d\n", m.stats.TotalWords))
 
+	if m.inbox != nil && m.inbox.NoteID != nil {
+		stats += labelStyle.Render("Inbox:")
+		stats += valueStyle.Render(fmt.Sprintf("%d to process (i:open)\n", m.inbox.Items))
+	}
+
+	if byType := formatNotesByType(m.client.NoteTypes(), m.stats.NotesByType); byType != "" {
+		stats += labelStyle.Render("By Type:")
+		stats += valueStyle.Render(byType + "\n")
+	}
+
 	stats += labelStyle.Render("Created Today:")
 	stats += valueStyle.Render(fmt.Sprintf("%d\n", m.stats.NotesCreatedToday))
 
@@ -255,6 +433,125 @@ func (m DashboardModel) renderStats(labelStyle, valueStyle lipgloss.Style) strin
 	return stats
 }
 
+// renderStreak renders the writing streak and today's progress toward the daily word goal
+func (m DashboardModel) renderStreak(labelStyle, valueStyle, mutedStyle lipgloss.Style) string {
+	barStyle := lipgloss.NewStyle().
+		Foreground(theme().Accent)
+
+	var streak string
+
+	streak += labelStyle.Render("Today:")
+	streak += barStyle.Render(progressBar(m.streak.WordsToday, m.streak.DailyGoal, 20))
+	streak += valueStyle.Render(fmt.Sprintf(" %d / %d words", m.streak.WordsToday, m.streak.DailyGoal))
+	if m.streak.GoalMetToday {
+		streak += barStyle.Render(" goal met!")
+	}
+	streak += "\n"
+
+	streak += labelStyle.Render("Streak:")
+	streak += valueStyle.Render(pluralDays(m.streak.CurrentStreak))
+	if !m.streak.GoalMetToday && m.streak.CurrentStreak > 0 {
+		streak += mutedStyle.Render(" (reach today's goal to keep it)")
+	}
+	streak += "\n"
+
+	streak += labelStyle.Render("Longest Streak:")
+	streak += valueStyle.Render(pluralDays(m.streak.LongestStreak) + "\n")
+
+	return streak
+}
+
+// progressBar renders value out of total as a bar of width block characters
+func progressBar(value, total, width int) string {
+	filled := width
+	if total > 0 && value < total {
+		filled = value * width / total
+	}
+	if filled < 0 {
+		filled = 0
+	}
+	return strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+}
+
+// pluralDays formats a number of days, e.g. "1 day" or "3 days"
+func pluralDays(n int) string {
+	if n == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", n)
+}
+
+// dashboardLabelWidth is the width of the label column in the dashboard boxes
+const dashboardLabelWidth = 20
+
+// heatmapWeeks is how many weeks the activity heatmap covers
+const heatmapWeeks = 12
+
+// renderHeatmap renders the activity heatmap, one column per week and one row per weekday
+func (m DashboardModel) renderHeatmap(mutedStyle lipgloss.Style) string {
+	days := m.heatmap.Days
+	colors := theme().Heatmap
+
+	busiest := 0
+	for _, day := range days {
+		if day.Count > busiest {
+			busiest = day.Count
+		}
+	}
+
+	labels := []string{"Mon", "", "Wed", "", "Fri", "", "Sun"}
+	if m.heatmap.WeekStart == "sunday" {
+		labels = []string{"Sun", "", "Tue", "", "Thu", "", "Sat"}
+	}
+
+	var rows []string
+	for weekday := 0; weekday < 7; weekday++ {
+		row := mutedStyle.Render(fmt.Sprintf("%-4s", labels[weekday]))
+		for i := weekday; i < len(days); i += 7 {
+			style := lipgloss.NewStyle().Foreground(colors[heatmapLevel(days[i].Count, busiest, len(colors)-1)])
+			row += style.Render("■") + " "
+		}
+		rows = append(rows, row)
+	}
+
+	// Legend
+	legend := mutedStyle.Render("    Less ")
+	for _, color := range colors {
+		legend += lipgloss.NewStyle().Foreground(color).Render("■") + " "
+	}
+	legend += mutedStyle.Render("More")
+	rows = append(rows, "", legend)
+
+	return strings.Join(rows, "\n")
+}
+
+// heatmapLevel maps a day's count to a heatmap color index up to steps, relative to the busiest day
+func heatmapLevel(count, busiest, steps int) int {
+	if count <= 0 || busiest <= 0 {
+		return 0
+	}
+	level := (count*steps + busiest - 1) / busiest // Ceiling, so any activity shows
+	if level > steps {
+		level = steps
+	}
+	return level
+}
+
+// boxWidth is the width of the section boxes without their border, so the boxes fit the window
+func (m DashboardModel) boxWidth() int {
+	width := m.width - 2
+	if width < 40 {
+		width = 40
+	}
+	return width
+}
+
+// rowWidth is the width left for the text after a row's label
+func (m DashboardModel) rowWidth() int {
+	// Box padding, the label column and the space after it
+	return m.boxWidth() - 2 - dashboardLabelWidth - 1
+}
+
 // renderActivity renders the recent activity section
 func (m DashboardModel) renderActivity(labelStyle, valueStyle, mutedStyle lipgloss.Style) string {
 	if len(m.activity) == 0 {
@@ -264,9 +561,9 @@ func (m DashboardModel) renderActivity(labelStyle, valueStyle, mutedStyle lipglo
 	var activity string
 	for _, act := range m.activity {
 		timestamp := formatTimeAgo(act.CreatedAt)
-		activity += labelStyle.Render(timestamp)
-		activity += " "
-		activity += valueStyle.Render(formatActivity(act))
+		// Long activity lines continue under the text, not under the label
+		lines := components.WrapLines(labelStyle.Render(timestamp)+" ", strings.Repeat(" ", dashboardLabelWidth+1), valueStyle.Render(formatActivity(act)), m.boxWidth()-2)
+		activity += strings.Join(lines, "\n")
 		activity += "\n"
 	}
 
@@ -287,7 +584,7 @@ func (m DashboardModel) renderTrending(labelStyle, valueStyle, mutedStyle lipglo
 
 		trending += labelStyle.Render(fmt.Sprintf("%d.", i+1))
 		trending += " "
-		trending += valueStyle.Render(truncateText(note.Note.Title, 40))
+		trending += valueStyle.Render(truncateText(note.Note.Title, m.rowWidth()))
 		trending += "\n"
 
 		// Show access count
@@ -297,10 +594,52 @@ func (m DashboardModel) renderTrending(labelStyle, valueStyle, mutedStyle lipglo
 	return trending
 }
 
+// renderForgotten renders the resurfaced notes, numbered by the key that opens them
+func (m DashboardModel) renderForgotten(labelStyle, valueStyle, mutedStyle lipgloss.Style) string {
+	var forgotten string
+	for i, note := range m.forgotten {
+		if note.Note == nil {
+			continue
+		}
+
+		forgotten += labelStyle.Render(fmt.Sprintf("%d.", i+1))
+		forgotten += " "
+		forgotten += valueStyle.Render(truncateText(note.Note.Title, m.rowWidth()))
+		forgotten += "\n"
+
+		forgotten += mutedStyle.Render(fmt.Sprintf("   Last opened %s ago\n", pluralDays(note.DaysSinceAccess)))
+	}
+
+	forgotten += mutedStyle.Render(fmt.Sprintf("Press 1-%d to open one", len(m.forgotten)))
+
+	return forgotten
+}
+
+// renderReading renders the partly read notes, numbered by the key that opens them
+func (m DashboardModel) renderReading(labelStyle, valueStyle, mutedStyle lipgloss.Style) string {
+	var reading string
+	for i, note := range m.reading {
+		reading += labelStyle.Render(fmt.Sprintf("%d.", i+6))
+		reading += " "
+		reading += valueStyle.Render(truncateText(note.Title, m.rowWidth()))
+		reading += "\n"
+
+		percent := 0
+		if progress := note.Metadata.ReadProgress(); progress != nil {
+			percent = progress.Percent
+		}
+		reading += mutedStyle.Render(fmt.Sprintf("   %d%% read · %d min read\n", percent, note.ReadingTimeMinutes))
+	}
+
+	reading += mutedStyle.Render(fmt.Sprintf("Press 6-%d to continue one", len(m.reading)+5))
+
+	return reading
+}
+
 // renderQuickActions renders the quick actions section
 func (m DashboardModel) renderQuickActions() string {
 	quickActionsStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#a6e3a1")). // Green
+		Foreground(theme().Accent).
 		Bold(true)
 
 	actions := "Quick Actions: "
@@ -337,11 +676,29 @@ func formatActivity(act *model.Activity) string {
 		return "Deleted a note"
 	case "link":
 		return "Created a link between notes"
+	case model.ActionFocus:
+		return fmt.Sprintf("Focused on note: %s (%s)", getNoteTitleFromMetadata(act.Metadata), formatFocusTime(focusSeconds(act.Metadata)))
 	default:
 		return string(act.Action)
 	}
 }
 
+// focusSeconds extracts the length of a focus session, in seconds, from activity metadata
+func focusSeconds(metadata model.ActivityMetadata) int64 {
+	// Metadata is decoded from JSON, so numbers are float64
+	seconds, _ := metadata[model.FocusDurationKey].(float64)
+	return int64(seconds)
+}
+
+// formatFocusTime formats seconds of focus as hours and minutes, e.g. "1h 25m"
+func formatFocusTime(seconds int64) string {
+	minutes := int((seconds + 30) / 60)
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%dh %dm", minutes/60, minutes%60)
+}
+
 // getNoteTitleFromMetadata extracts the note title from activity metadata
 func getNoteTitleFromMetadata(metadata model.ActivityMetadata) string {
 	if title, ok := metadata["note_title"].(string); ok {
@@ -388,12 +745,24 @@ func formatTimeAgo(t time.Time) string {
 	return t.Format("Jan 2, 2006")
 }
 
-// truncateText truncates text to a maximum length
+// formatNotesByType formats the number of notes of each type, skipping types without notes
+func formatNotesByType(noteTypes []*model.NoteTypeDefinition, counts map[model.NoteType]int64) string {
+	var parts []string
+	for _, noteType := range noteTypes {
+		if count := counts[noteType.Name]; count > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", count, noteType.Name))
+		}
+	}
+	return strings.Join(parts, " · ")
+}
+
+// truncateText truncates text to a maximum display width
+// Width is measured in terminal cells, so wide characters and styles don't break the layout.
 func truncateText(text string, maxLen int) string {
-	if len(text) <= maxLen {
+	if ansi.StringWidth(text) <= maxLen {
 		return text
 	}
-	return text[:maxLen-3] + "..."
+	return ansi.Truncate(text, maxLen, "...")
 }
 
 // Message types for dashboard
@@ -410,6 +779,27 @@ type dashboardTrendingMsg struct {
 	trending []*model.TrendingNote
 }
 
+type dashboardHeatmapMsg struct {
+	heatmap *model.ActivityHeatmap
+}
+
+type dashboardStreakMsg struct {
+	streak *model.WritingStreak
+}
+
+type dashboardForgottenMsg struct {
+	forgotten []*model.ForgottenNote
+	days      int
+}
+
+type dashboardReadingMsg struct {
+	reading []*model.Note
+}
+
+type dashboardInboxMsg struct {
+	inbox *model.InboxStatus
+}
+
 type dashboardErrMsg struct {
 	err error
 }
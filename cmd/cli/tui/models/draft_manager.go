@@ -0,0 +1,117 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultDraftInterval is how often the note form is saved as a draft when no interval is configured
+const DefaultDraftInterval = 30 * time.Second
+
+// Draft is an unsaved create or edit form
+type Draft struct {
+	NoteID  uuid.UUID         `json:"note_id"` // uuid.Nil for a new note
+	Title   string            `json:"title"`
+	Content string            `json:"content"`
+	Type    string            `json:"note_type,omitempty"`
+	Fields  map[string]string `json:"fields,omitempty"` // Custom field values of the type, by field name
+	SavedAt time.Time         `json:"saved_at"`
+}
+
+// DraftManager handles automatic saving of note drafts
+// A draft outlives the TUI, so a crash or a closed terminal doesn't lose the note.
+type DraftManager struct {
+	draftsDir    string
+	saveInterval time.Duration
+}
+
+// NewDraftManager creates a new draft manager
+func NewDraftManager(saveInterval time.Duration) (*DraftManager, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("get home dir: %w", err)
+	}
+
+	draftsDir := filepath.Join(homeDir, ".config", "kg-cli", "drafts")
+
+	// Create drafts directory if it doesn't exist
+	if err := os.MkdirAll(draftsDir, 0700); err != nil {
+		return nil, fmt.Errorf("create drafts dir: %w", err)
+	}
+
+	if saveInterval <= 0 {
+		saveInterval = DefaultDraftInterval
+	}
+
+	return &DraftManager{
+		draftsDir:    draftsDir,
+		saveInterval: saveInterval,
+	}, nil
+}
+
+// SaveInterval returns how often drafts are saved
+func (dm *DraftManager) SaveInterval() time.Duration {
+	return dm.saveInterval
+}
+
+// Save saves a draft to disk, replacing the previous draft of the note
+func (dm *DraftManager) Save(draft Draft) error {
+	draft.SavedAt = time.Now()
+	data, err := json.Marshal(draft)
+	if err != nil {
+		return fmt.Errorf("encode draft: %w", err)
+	}
+
+	// Write to a temporary file first so a crash mid-write keeps the previous draft
+	draftPath := dm.getDraftPath(draft.NoteID)
+	tmpPath := draftPath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("write draft: %w", err)
+	}
+	if err := os.Rename(tmpPath, draftPath); err != nil {
+		return fmt.Errorf("write draft: %w", err)
+	}
+
+	return nil
+}
+
+// LoadDraft loads the draft of a note, or of a new note for uuid.Nil
+// Returns nil when there is no draft.
+func (dm *DraftManager) LoadDraft(noteID uuid.UUID) (*Draft, error) {
+	data, err := os.ReadFile(dm.getDraftPath(noteID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read draft: %w", err)
+	}
+
+	var draft Draft
+	if err := json.Unmarshal(data, &draft); err != nil {
+		return nil, fmt.Errorf("decode draft: %w", err)
+	}
+
+	return &draft, nil
+}
+
+// ClearDraft removes the draft of a note
+func (dm *DraftManager) ClearDraft(noteID uuid.UUID) error {
+	if err := os.Remove(dm.getDraftPath(noteID)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove draft: %w", err)
+	}
+	return nil
+}
+
+// getDraftPath returns the path to the draft file for a note
+func (dm *DraftManager) getDraftPath(noteID uuid.UUID) string {
+	if noteID == uuid.Nil {
+		return filepath.Join(dm.draftsDir, "new.json")
+	}
+	return filepath.Join(dm.draftsDir, noteID.String()+".json")
+}
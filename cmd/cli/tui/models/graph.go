@@ -2,6 +2,7 @@ package models
 
 import (
 	"fmt"
+	"sort"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -22,8 +23,29 @@ type GraphModel struct {
 	width      int
 	height     int
 	maxNodes   int
+	rootID     *uuid.UUID // Set for a local graph centered on a note
+	depth      int        // Hops from the root included in a local graph
+	showHubs   bool       // List the hub notes instead of every note
+	hubIndex   int        // Selected hub note
+	byCluster  bool       // Group the note list by cluster
+
+	// Drawing of the graph, shown unless listView lists the notes instead
+	listView       bool
+	layout         *graphLayout
+	canvasSelected int     // Selected note, by layout index
+	zoom           float64 // 1 shows the whole graph
+	panX, panY     float64 // Layout point at the center of the view
+
+	// Path between two notes, picked with "p" on each
+	pathFrom    *uuid.UUID
+	path        *model.GraphPath
+	pathErr     error
+	pathLoading bool
 }
 
+// localGraphMaxDepth is the largest depth the API accepts for a local graph
+const localGraphMaxDepth = 5
+
 // NewGraphModel creates a new graph model
 func NewGraphModel(apiClient *client.APIClient, authState *client.AuthState) GraphModel {
 	return GraphModel{
@@ -34,9 +56,25 @@ func NewGraphModel(apiClient *client.APIClient, authState *client.AuthState) Gra
 		width:    80,
 		height:   24,
 		maxNodes: 20, // Initial view shows 20 nodes
+		zoom:     1,
+		panX:     0.5,
+		panY:     0.5,
 	}
 }
 
+// NewLocalGraphModel creates a graph model scoped to notes within depth hops of rootID
+func NewLocalGraphModel(apiClient *client.APIClient, authState *client.AuthState, rootID uuid.UUID, depth int) GraphModel {
+	m := NewGraphModel(apiClient, authState)
+	m.rootID = &rootID
+	m.depth = depth
+	return m
+}
+
+// RootID returns the root note of a local graph, or nil for the full graph
+func (m GraphModel) RootID() *uuid.UUID {
+	return m.rootID
+}
+
 // Init initializes the graph model
 func (m GraphModel) Init() tea.Cmd {
 	return m.fetchGraphCmd()
@@ -45,7 +83,13 @@ func (m GraphModel) Init() tea.Cmd {
 // fetchGraphCmd returns a command that fetches the graph
 func (m GraphModel) fetchGraphCmd() tea.Cmd {
 	return func() tea.Msg {
-		graph, err := m.client.GetGraph()
+		var graph *model.GraphResponse
+		var err error
+		if m.rootID != nil {
+			graph, err = m.client.GetLocalGraph(*m.rootID, m.depth)
+		} else {
+			graph, err = m.client.GetGraph()
+		}
 		if err != nil {
 			return GraphErrMsg{Err: err}
 		}
@@ -57,6 +101,12 @@ func (m GraphModel) fetchGraphCmd() tea.Cmd {
 func (m GraphModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.canvasShown() {
+			if next, cmd, ok := m.canvasKey(msg.String()); ok {
+				return next, cmd
+			}
+		}
+
 		switch msg.String() {
 		case "q", "ctrl+c":
 			return m, tea.Quit
@@ -69,13 +119,34 @@ func (m GraphModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return ShowDashboardMsg{}
 			}
 		case "j", "down":
-			if m.graph != nil && m.selected < len(m.graph.Nodes)-1 {
+			if m.showHubs {
+				if m.hubIndex < len(m.hubs())-1 {
+					m.hubIndex++
+				}
+			} else if m.graph != nil && m.selected < len(m.graph.Nodes)-1 {
 				m.selected++
 			}
 		case "k", "up":
-			if m.selected > 0 {
+			if m.showHubs {
+				if m.hubIndex > 0 {
+					m.hubIndex--
+				}
+			} else if m.selected > 0 {
 				m.selected--
 			}
+		case "p":
+			return m.pickPathNote()
+		case "v":
+			// Switch between the drawing of the graph and the note list
+			m.listView = !m.listView
+		case "c":
+			// Group notes by cluster, or back to the graph's order
+			m.byCluster = !m.byCluster
+			m.selected = 0
+		case "h":
+			// Switch between every note and the hub notes
+			m.showHubs = !m.showHubs
+			m.hubIndex = 0
 		case "+", "=":
 			// Show more nodes
 			if m.maxNodes < 100 {
@@ -86,10 +157,35 @@ func (m GraphModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.maxNodes > 10 {
 				m.maxNodes -= 10
 			}
+		case "]":
+			// Widen the local graph by one hop
+			if m.rootID != nil && m.depth < localGraphMaxDepth {
+				m.depth++
+				m.loading = true
+				return m, m.fetchGraphCmd()
+			}
+		case "[":
+			// Narrow the local graph by one hop
+			if m.rootID != nil && m.depth > 1 {
+				m.depth--
+				m.loading = true
+				m.selected = 0
+				return m, m.fetchGraphCmd()
+			}
 		case "enter":
+			// Open the selected hub note
+			if m.showHubs {
+				if hubs := m.hubs(); m.hubIndex < len(hubs) {
+					noteID := hubs[m.hubIndex].ID
+					return m, func() tea.Msg {
+						return OpenNoteMsg{NoteID: noteID}
+					}
+				}
+				return m, nil
+			}
 			// Open selected note
 			if m.graph != nil && len(m.graph.Nodes) > 0 && m.selected >= 0 {
-				noteID := m.graph.Nodes[m.selected].ID
+				noteID := m.nodeAt(m.selected).ID
 				return m, func() tea.Msg {
 					return OpenNoteMsg{NoteID: noteID}
 				}
@@ -97,7 +193,7 @@ func (m GraphModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case " ":
 			// Toggle expand/collapse selected node
 			if m.graph != nil && len(m.graph.Nodes) > 0 && m.selected >= 0 {
-				nodeID := m.graph.Nodes[m.selected].ID
+				nodeID := m.nodeAt(m.selected).ID
 				if m.expanded[nodeID] {
 					delete(m.expanded, nodeID)
 				} else {
@@ -107,8 +203,26 @@ func (m GraphModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case GraphFetchedMsg:
+		// Keep the selected note selected in the new drawing
+		var selectedID *uuid.UUID
+		if m.layout != nil && m.canvasSelected < len(m.layout.nodes) {
+			selectedID = &m.layout.nodes[m.canvasSelected].ID
+		} else if m.rootID != nil {
+			selectedID = m.rootID
+		}
 		m.graph = msg.Graph
 		m.loading = false
+		m.layout = newGraphLayout(msg.Graph, m.rootID, graphLayoutMaxNodes)
+		m.canvasSelected = 0
+		if selectedID != nil {
+			m.canvasSelected = max(m.layout.indexOf(*selectedID), 0)
+		}
+		if m.hubIndex >= len(m.hubs()) {
+			m.hubIndex = 0
+		}
+		if m.selected >= len(m.graph.Nodes) {
+			m.selected = 0
+		}
 		if len(m.graph.Nodes) > 0 {
 			// Auto-expand first few nodes
 			for i := 0; i < min(3, len(m.graph.Nodes)); i++ {
@@ -122,6 +236,16 @@ func (m GraphModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.loading = false
 		return m, nil
 
+	case GraphPathMsg:
+		m.path = msg.Path
+		m.pathLoading = false
+		return m, nil
+
+	case GraphPathErrMsg:
+		m.pathErr = msg.Err
+		m.pathLoading = false
+		return m, nil
+
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
@@ -147,7 +271,7 @@ func (m GraphModel) View() string {
 // renderLoading renders the loading state
 func (m GraphModel) renderLoading() string {
 	style := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#89b4fa")). // Blue
+		Foreground(theme().Primary).
 		Bold(true)
 
 	return style.Render("Loading knowledge graph...")
@@ -156,7 +280,7 @@ func (m GraphModel) renderLoading() string {
 // renderError renders the error state
 func (m GraphModel) renderError() string {
 	errorStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#f38ba8")). // Red
+		Foreground(theme().Error).
 		Bold(true)
 
 	return errorStyle.Render("Error: " + m.err.Error())
@@ -166,35 +290,39 @@ func (m GraphModel) renderError() string {
 func (m GraphModel) renderContent() string {
 	// Define styles
 	titleStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#fab387")). // Orange
+		Foreground(theme().Secondary).
 		Bold(true).
 		MarginBottom(1)
 
 	nodeStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#cdd6f4")) // Light text
+		Foreground(theme().Foreground)
 
 	selectedStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#1e1e2e")). // Dark
-		Background(lipgloss.Color("#89b4fa")). // Blue
+		Foreground(theme().Background).
+		Background(theme().Primary).
 		Bold(true)
 
 	linkStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#6c7086")). // Gray
+		Foreground(theme().Muted).
 		Faint(true)
 
 	mutedStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#6c7086")). // Gray
+		Foreground(theme().Muted).
 		Faint(true)
 
 	hintStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#6c7086")). // Gray
+		Foreground(theme().Muted).
 		Faint(true).
 		MarginTop(1)
 
 	var content string
 
 	// Title
-	content += titleStyle.Render("KNOWLEDGE GRAPH") + "\n\n"
+	if m.rootID != nil {
+		content += titleStyle.Render(fmt.Sprintf("LOCAL GRAPH: %s (%d hops)", m.getNodeTitle(*m.rootID), m.depth)) + "\n\n"
+	} else {
+		content += titleStyle.Render("KNOWLEDGE GRAPH") + "\n\n"
+	}
 
 	if m.graph == nil || len(m.graph.Nodes) == 0 {
 		content += mutedStyle.Render("(no notes in knowledge graph)")
@@ -204,21 +332,58 @@ func (m GraphModel) renderContent() string {
 	}
 
 	// Stats
-	if m.graph.Stats != nil {
-		statsText := fmt.Sprintf("Nodes: %d | Links: %d",
-			m.graph.Stats.TotalNotes,
-			m.graph.Stats.TotalLinks)
-		content += mutedStyle.Render(statsText) + "\n\n"
+	if stats := m.graph.Stats; stats != nil {
+		statsText := fmt.Sprintf("Nodes: %d | Links: %d | Orphans: %d | Components: %d | Clusters: %d | Avg links: %.1f | Max depth: %d",
+			stats.TotalNotes,
+			stats.TotalLinks,
+			stats.Orphans,
+			stats.Components,
+			stats.Clusters,
+			stats.AverageDegree,
+			stats.MaxDepth)
+		content += mutedStyle.Render(truncateText(statsText, m.width-2)) + "\n\n"
+	}
+
+	content += m.renderPath()
+
+	if m.showHubs {
+		return content + m.renderHubs()
+	}
+
+	if m.canvasShown() {
+		content += m.renderCanvas(m.canvasHeight(content)) + "\n"
+		content += m.renderCanvasInfo()
+		if m.rootID != nil {
+			content += "\n" + hintStyle.Render("tab/j/k:select ←↑↓→:pan +/-:zoom 0:reset Enter:open [/]:depth v:list h:hubs p:path ESC:back to note ?:help")
+		} else {
+			content += "\n" + hintStyle.Render("tab/j/k:select ←↑↓→:pan +/-:zoom 0:reset Enter:open v:list h:hubs p:path ESC:back ?:help")
+		}
+		return content
 	}
 
 	// Build adjacency list for connections
 	connections := m.buildConnections()
 
+	clusterSizes := m.clusterSizes()
+	clusterStyle := lipgloss.NewStyle().
+		Foreground(theme().Secondary).
+		Bold(true)
+
 	// Display nodes (with connections)
-	displayCount := min(m.maxNodes, len(m.graph.Nodes))
+	listed := m.listedNodes()
+	displayCount := min(m.maxNodes, len(listed))
 	for i := 0; i < displayCount; i++ {
-		node := m.graph.Nodes[i]
+		node := listed[i]
 		isSelected := i == m.selected
+
+		// Cluster heading when grouped
+		if m.byCluster && (i == 0 || listed[i-1].Cluster != node.Cluster) {
+			if size := clusterSizes[node.Cluster]; size > 1 {
+				content += clusterStyle.Render(fmt.Sprintf("Cluster %d (%d notes)", node.Cluster, size)) + "\n"
+			} else if i == 0 || clusterSizes[listed[i-1].Cluster] > 1 {
+				content += clusterStyle.Render("Unclustered") + "\n"
+			}
+		}
 		isExpanded := m.expanded[node.ID]
 
 		// Node indicator
@@ -246,6 +411,9 @@ func (m GraphModel) renderContent() string {
 		if title == "" {
 			title = "(untitled)"
 		}
+		if m.rootID != nil && node.ID == *m.rootID {
+			title = "● " + title
+		}
 		if len(title) > 50 {
 			title = title[:47] + "..."
 		}
@@ -254,6 +422,8 @@ func (m GraphModel) renderContent() string {
 
 		if isSelected {
 			content += selectedStyle.Render(nodeLine)
+		} else if clusterSizes[node.Cluster] > 1 {
+			content += nodeStyle.Foreground(clusterColor(node.Cluster)).Render(nodeLine)
 		} else {
 			content += nodeStyle.Render(nodeLine)
 		}
@@ -274,7 +444,199 @@ func (m GraphModel) renderContent() string {
 	}
 
 	// Hints
-	content += "\n" + hintStyle.Render("j/k:navigate Enter:open +/-:zoom Space:expand ESC:back ?:help")
+	if m.rootID != nil {
+		content += "\n" + hintStyle.Render("j/k:navigate Enter:open +/-:more/fewer [/]:depth Space:expand c:clusters v:graph h:hubs p:path ESC:back to note ?:help")
+	} else {
+		content += "\n" + hintStyle.Render("j/k:navigate Enter:open +/-:more/fewer Space:expand c:clusters v:graph h:hubs p:path ESC:back ?:help")
+	}
+
+	return content
+}
+
+// nodeAt returns the node at a position of the note list
+func (m GraphModel) nodeAt(i int) *model.GraphNode {
+	return m.listedNodes()[i]
+}
+
+// listedNodes returns the nodes in note list order
+// Grouped by cluster they come largest cluster first, in the graph's order within one.
+func (m GraphModel) listedNodes() []*model.GraphNode {
+	if !m.byCluster {
+		return m.graph.Nodes
+	}
+	nodes := append([]*model.GraphNode(nil), m.graph.Nodes...)
+	sort.SliceStable(nodes, func(i, j int) bool { return nodes[i].Cluster < nodes[j].Cluster })
+	return nodes
+}
+
+// clusterSizes counts the notes in each cluster
+func (m GraphModel) clusterSizes() map[int]int {
+	sizes := map[int]int{}
+	if m.graph == nil {
+		return sizes
+	}
+	for _, node := range m.graph.Nodes {
+		if node.Cluster > 0 {
+			sizes[node.Cluster]++
+		}
+	}
+	return sizes
+}
+
+// clusterColor is the color notes of a cluster are drawn in, cycling through the theme's colors
+func clusterColor(cluster int) lipgloss.Color {
+	palette := []lipgloss.Color{theme().Primary, theme().Accent, theme().Special, theme().Warning, theme().Secondary, theme().Subtext}
+	return palette[(cluster-1+len(palette))%len(palette)]
+}
+
+// selectedNoteID returns the note under the cursor, in the hub list, the drawing or the note list
+func (m GraphModel) selectedNoteID() (uuid.UUID, bool) {
+	if m.showHubs {
+		if hubs := m.hubs(); m.hubIndex < len(hubs) {
+			return hubs[m.hubIndex].ID, true
+		}
+		return uuid.Nil, false
+	}
+	if m.canvasShown() {
+		return m.layout.nodes[m.canvasSelected].ID, true
+	}
+	if m.graph == nil || m.selected < 0 || m.selected >= len(m.graph.Nodes) {
+		return uuid.Nil, false
+	}
+	return m.nodeAt(m.selected).ID, true
+}
+
+// pickPathNote starts a path at the selected note, or finds the path from the start to it
+// With a path shown, or on the start note again, it clears the path instead.
+func (m GraphModel) pickPathNote() (GraphModel, tea.Cmd) {
+	id, ok := m.selectedNoteID()
+	if m.path != nil || m.pathErr != nil || m.pathLoading || !ok || (m.pathFrom != nil && *m.pathFrom == id) {
+		m.pathFrom = nil
+		m.path = nil
+		m.pathErr = nil
+		m.pathLoading = false
+		return m, nil
+	}
+
+	if m.pathFrom == nil {
+		m.pathFrom = &id
+		return m, nil
+	}
+
+	m.pathLoading = true
+	from := *m.pathFrom
+	return m, func() tea.Msg {
+		path, err := m.client.GetGraphPath(from, id)
+		if err != nil {
+			return GraphPathErrMsg{Err: err}
+		}
+		return GraphPathMsg{Path: path}
+	}
+}
+
+// renderPath renders the note a path starts at, or the chain of notes found
+func (m GraphModel) renderPath() string {
+	if m.pathFrom == nil {
+		return ""
+	}
+
+	sectionStyle := lipgloss.NewStyle().
+		Foreground(theme().Accent).
+		Bold(true)
+
+	nodeStyle := lipgloss.NewStyle().
+		Foreground(theme().Foreground)
+
+	linkStyle := lipgloss.NewStyle().
+		Foreground(theme().Muted).
+		Faint(true)
+
+	errorStyle := lipgloss.NewStyle().
+		Foreground(theme().Error)
+
+	switch {
+	case m.pathLoading:
+		return linkStyle.Render("Finding path...") + "\n\n"
+	case m.pathErr != nil:
+		return errorStyle.Render("Path: "+m.pathErr.Error()) + linkStyle.Render(" (p:clear)") + "\n\n"
+	case m.path == nil:
+		return linkStyle.Render(fmt.Sprintf("Path from %s: select another note and press p (p on it again cancels)", m.getNodeTitle(*m.pathFrom))) + "\n\n"
+	}
+
+	content := sectionStyle.Render(fmt.Sprintf("PATH (%d links)", len(m.path.Links))) + linkStyle.Render(" p:clear") + "\n"
+	for i, note := range m.path.Path {
+		title := note.Title
+		if title == "" {
+			title = "(untitled)"
+		}
+		content += "  " + nodeStyle.Render(truncateText(title, 50)) + "\n"
+		if i < len(m.path.Links) {
+			if m.path.Links[i].Source == note.ID {
+				content += linkStyle.Render("    ↓ links to") + "\n"
+			} else {
+				content += linkStyle.Render("    ↓ linked from") + "\n"
+			}
+		}
+	}
+
+	return content + "\n"
+}
+
+// hubs returns the hub notes of the graph, most central first
+func (m GraphModel) hubs() []*model.GraphNoteScore {
+	if m.graph == nil || m.graph.Stats == nil {
+		return nil
+	}
+	return m.graph.Stats.Hubs
+}
+
+// renderHubs renders the hub notes with their links and centrality
+func (m GraphModel) renderHubs() string {
+	sectionStyle := lipgloss.NewStyle().
+		Foreground(theme().Accent).
+		Bold(true)
+
+	nodeStyle := lipgloss.NewStyle().
+		Foreground(theme().Foreground)
+
+	selectedStyle := lipgloss.NewStyle().
+		Foreground(theme().Background).
+		Background(theme().Primary).
+		Bold(true)
+
+	mutedStyle := lipgloss.NewStyle().
+		Foreground(theme().Muted).
+		Faint(true)
+
+	hintStyle := lipgloss.NewStyle().
+		Foreground(theme().Muted).
+		Faint(true).
+		MarginTop(1)
+
+	content := sectionStyle.Render("HUB NOTES") + "\n"
+
+	hubs := m.hubs()
+	if len(hubs) == 0 {
+		content += mutedStyle.Render("(no note is linked to yet)") + "\n"
+	}
+	for i, hub := range hubs {
+		title := hub.Title
+		if title == "" {
+			title = "(untitled)"
+		}
+		title = truncateText(title, 50)
+		info := fmt.Sprintf(" · %d in, %d out · %.1f%%", hub.InDegree, hub.OutDegree, hub.Centrality*100)
+
+		if i == m.hubIndex {
+			content += selectedStyle.Render("→ "+title) + mutedStyle.Render(info)
+		} else {
+			content += "  " + nodeStyle.Render(title) + mutedStyle.Render(info)
+		}
+		content += "\n"
+	}
+
+	content += "\n" + mutedStyle.Render("Centrality is the chance of landing on a note when following links at random.")
+	content += "\n" + hintStyle.Render("j/k:navigate Enter:open h:all notes p:path ESC:back ?:help")
 
 	return content
 }
@@ -330,3 +692,11 @@ type GraphFetchedMsg struct {
 type GraphErrMsg struct {
 	Err error
 }
+
+type GraphPathMsg struct {
+	Path *model.GraphPath
+}
+
+type GraphPathErrMsg struct {
+	Err error
+}
@@ -0,0 +1,556 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/google/uuid"
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+const (
+	// graphLayoutMaxNodes is how many notes the graph drawing lays out; bigger graphs keep the most linked
+	graphLayoutMaxNodes = 150
+	// graphLayoutIterations is how many steps the force-directed layout runs
+	graphLayoutIterations = 150
+	// graphLayoutGravity is how strongly notes are pulled to the center of the layout
+	graphLayoutGravity = 1.0
+	// graphLabelWidth is the widest a note title is drawn next to its node
+	graphLabelWidth = 16
+	// graphMaxZoom is how far the drawing zooms in
+	graphMaxZoom = 16.0
+)
+
+// graphPoint is a position in the layout, both coordinates between 0 and 1
+type graphPoint struct {
+	x, y float64
+}
+
+// graphLayout is where the notes of a graph are drawn
+type graphLayout struct {
+	nodes  []*model.GraphNode
+	pos    []graphPoint
+	edges  [][2]int // Node indexes of each link, both ends laid out
+	degree []int
+	total  int // Notes in the graph, more than nodes when it was too big to lay out whole
+}
+
+// newGraphLayout lays out up to limit notes of a graph with a force-directed layout
+// Bigger graphs keep the root note of a local graph and the most linked notes.
+func newGraphLayout(graph *model.GraphResponse, rootID *uuid.UUID, limit int) *graphLayout {
+	degree := map[uuid.UUID]int{}
+	for _, edge := range graph.Edges {
+		degree[edge.Source]++
+		degree[edge.Target]++
+	}
+
+	nodes := graph.Nodes
+	if len(nodes) > limit {
+		ranked := append([]*model.GraphNode(nil), nodes...)
+		sort.SliceStable(ranked, func(i, j int) bool {
+			if rootID != nil && (ranked[i].ID == *rootID) != (ranked[j].ID == *rootID) {
+				return ranked[i].ID == *rootID
+			}
+			return degree[ranked[i].ID] > degree[ranked[j].ID]
+		})
+		keep := map[uuid.UUID]bool{}
+		for _, node := range ranked[:limit] {
+			keep[node.ID] = true
+		}
+		nodes = nil
+		for _, node := range graph.Nodes {
+			if keep[node.ID] {
+				nodes = append(nodes, node)
+			}
+		}
+	}
+
+	layout := &graphLayout{nodes: nodes, degree: make([]int, len(nodes)), total: len(graph.Nodes)}
+	index := make(map[uuid.UUID]int, len(nodes))
+	for i, node := range nodes {
+		index[node.ID] = i
+	}
+	for _, edge := range graph.Edges {
+		source, sourceIn := index[edge.Source]
+		target, targetIn := index[edge.Target]
+		if sourceIn && targetIn && source != target {
+			layout.edges = append(layout.edges, [2]int{source, target})
+			layout.degree[source]++
+			layout.degree[target]++
+		}
+	}
+
+	layout.pos = forceLayout(nodes, layout.edges)
+	return layout
+}
+
+// indexOf returns the layout index of a note, -1 when it isn't laid out
+func (l *graphLayout) indexOf(id uuid.UUID) int {
+	for i, node := range l.nodes {
+		if node.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// forceLayout places nodes so linked ones sit close and the rest spread out (Fruchterman-Reingold)
+// Nodes start on a circle in cluster order, so the same graph is always drawn the same way.
+func forceLayout(nodes []*model.GraphNode, edges [][2]int) []graphPoint {
+	n := len(nodes)
+	pos := make([]graphPoint, n)
+	if n == 0 {
+		return pos
+	}
+	if n == 1 {
+		pos[0] = graphPoint{0.5, 0.5}
+		return pos
+	}
+
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return nodes[order[i]].Cluster < nodes[order[j]].Cluster })
+	for slot, i := range order {
+		angle := 2 * math.Pi * float64(slot) / float64(n)
+		pos[i] = graphPoint{0.5 + 0.4*math.Cos(angle), 0.5 + 0.4*math.Sin(angle)}
+	}
+
+	k := math.Sqrt(1 / float64(n))
+	disp := make([]graphPoint, n)
+	for iteration := 0; iteration < graphLayoutIterations; iteration++ {
+		for i := range disp {
+			disp[i] = graphPoint{}
+		}
+
+		// Every pair pushes apart
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				dx, dy := pos[i].x-pos[j].x, pos[i].y-pos[j].y
+				dist := math.Max(math.Hypot(dx, dy), 0.001)
+				force := k * k / dist
+				disp[i].x += dx / dist * force
+				disp[i].y += dy / dist * force
+				disp[j].x -= dx / dist * force
+				disp[j].y -= dy / dist * force
+			}
+		}
+
+		// Links pull together
+		for _, edge := range edges {
+			a, b := edge[0], edge[1]
+			dx, dy := pos[a].x-pos[b].x, pos[a].y-pos[b].y
+			dist := math.Max(math.Hypot(dx, dy), 0.001)
+			force := dist * dist / k
+			disp[a].x -= dx / dist * force
+			disp[a].y -= dy / dist * force
+			disp[b].x += dx / dist * force
+			disp[b].y += dy / dist * force
+		}
+
+		// The center pulls a little, so notes without links stay near the rest
+		for i := range pos {
+			disp[i].x -= (pos[i].x - 0.5) * graphLayoutGravity
+			disp[i].y -= (pos[i].y - 0.5) * graphLayoutGravity
+		}
+
+		// Moves shrink as the layout cools
+		temperature := 0.1 * (1 - float64(iteration)/graphLayoutIterations)
+		for i := range pos {
+			length := math.Max(math.Hypot(disp[i].x, disp[i].y), 0.001)
+			step := math.Min(length, temperature)
+			pos[i].x += disp[i].x / length * step
+			pos[i].y += disp[i].y / length * step
+		}
+	}
+
+	// Stretch the layout over the whole frame
+	minX, maxX, minY, maxY := pos[0].x, pos[0].x, pos[0].y, pos[0].y
+	for _, p := range pos {
+		minX, maxX = math.Min(minX, p.x), math.Max(maxX, p.x)
+		minY, maxY = math.Min(minY, p.y), math.Max(maxY, p.y)
+	}
+	for i := range pos {
+		pos[i].x = (pos[i].x - minX) / math.Max(maxX-minX, 0.001)
+		pos[i].y = (pos[i].y - minY) / math.Max(maxY-minY, 0.001)
+	}
+
+	return pos
+}
+
+// Paints of canvas cells, each drawn in its own style
+const (
+	paintEdge = iota
+	paintPathEdge
+	paintLabel
+	paintSelected
+	paintCluster // Plus the node's cluster, nodes of a cluster share a color
+)
+
+// canvasCell is one terminal cell of the graph drawing
+type canvasCell struct {
+	dots  uint8 // Braille dots of the links crossing the cell
+	char  rune  // A node marker or label character, drawn over the dots
+	paint int
+}
+
+// brailleDots are the bits of the braille dots in a cell, by column and row
+var brailleDots = [2][4]uint8{
+	{0x01, 0x02, 0x04, 0x40},
+	{0x08, 0x10, 0x20, 0x80},
+}
+
+// graphCanvas draws links with braille dots, two by four per cell, and nodes as characters
+type graphCanvas struct {
+	width, height int
+	cells         [][]canvasCell
+}
+
+func newGraphCanvas(width, height int) *graphCanvas {
+	cells := make([][]canvasCell, height)
+	for y := range cells {
+		cells[y] = make([]canvasCell, width)
+	}
+	return &graphCanvas{width: width, height: height, cells: cells}
+}
+
+// line draws a line between two dot positions, dots off the canvas are left out
+func (c *graphCanvas) line(x0, y0, x1, y1 int, paint int) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+
+	// Bresenham's line algorithm
+	err := dx + dy
+	for {
+		c.dot(x0, y0, paint)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+// dot sets one braille dot
+func (c *graphCanvas) dot(x, y int, paint int) {
+	if x < 0 || y < 0 || x >= c.width*2 || y >= c.height*4 {
+		return
+	}
+	cell := &c.cells[y/4][x/2]
+	cell.dots |= brailleDots[x%2][y%4]
+	if cell.char == 0 && paint > cell.paint {
+		cell.paint = paint
+	}
+}
+
+// text writes characters from a cell on, cut at the canvas edge
+func (c *graphCanvas) text(x, y int, s string, paint int) {
+	if y < 0 || y >= c.height {
+		return
+	}
+	for _, r := range s {
+		if x >= c.width {
+			return
+		}
+		if x >= 0 {
+			c.cells[y][x] = canvasCell{char: r, paint: paint}
+		}
+		x++
+	}
+}
+
+// free reports whether the cells from x on hold no node or label yet
+func (c *graphCanvas) free(x, y, width int) bool {
+	if y < 0 || y >= c.height {
+		return false
+	}
+	for i := x; i < x+width && i < c.width; i++ {
+		if i >= 0 && c.cells[y][i].char != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// render writes the canvas as lines, styling each run of cells of the same paint
+func (c *graphCanvas) render(style func(paint int) lipgloss.Style) string {
+	lines := make([]string, c.height)
+	for y, row := range c.cells {
+		var line, run strings.Builder
+		runPaint := -1
+		flush := func() {
+			if run.Len() > 0 {
+				line.WriteString(style(runPaint).Render(run.String()))
+				run.Reset()
+			}
+		}
+		for _, cell := range row {
+			char := cell.char
+			if char == 0 {
+				char = ' '
+				if cell.dots != 0 {
+					char = rune(0x2800 + int(cell.dots))
+				}
+			}
+			if cell.paint != runPaint {
+				flush()
+				runPaint = cell.paint
+			}
+			run.WriteRune(char)
+		}
+		flush()
+		lines[y] = line.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// abs returns the absolute value of an int
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// graphLabel is the short title drawn next to a node
+func graphLabel(node *model.GraphNode) string {
+	title := node.Title
+	if title == "" {
+		title = "(untitled)"
+	}
+	if ansi.StringWidth(title) > graphLabelWidth {
+		title = ansi.Truncate(title, graphLabelWidth-1, "") + "…"
+	}
+	return title
+}
+
+// canvasShown reports whether the graph is drawn, rather than listed
+func (m GraphModel) canvasShown() bool {
+	return !m.listView && !m.showHubs && m.layout != nil && len(m.layout.nodes) > 0
+}
+
+// canvasKey handles the keys of the graph drawing, reporting whether it used the key
+func (m GraphModel) canvasKey(key string) (GraphModel, tea.Cmd, bool) {
+	step := 0.25 / m.zoom
+	switch key {
+	case "left":
+		m.panX -= step
+	case "right":
+		m.panX += step
+	case "up":
+		m.panY -= step
+	case "down":
+		m.panY += step
+	case "+", "=":
+		m.zoom = math.Min(m.zoom*1.5, graphMaxZoom)
+	case "-", "_":
+		m.zoom = math.Max(m.zoom/1.5, 1)
+	case "0":
+		// Back to the whole graph
+		m.zoom = 1
+	case "j", "tab":
+		m.canvasSelected = (m.canvasSelected + 1) % len(m.layout.nodes)
+		m.panToSelected()
+	case "k", "shift+tab":
+		m.canvasSelected = (m.canvasSelected - 1 + len(m.layout.nodes)) % len(m.layout.nodes)
+		m.panToSelected()
+	case "enter":
+		noteID := m.layout.nodes[m.canvasSelected].ID
+		return m, func() tea.Msg {
+			return OpenNoteMsg{NoteID: noteID}
+		}, true
+	default:
+		return m, nil, false
+	}
+	m.clampPan()
+	return m, nil, true
+}
+
+// clampPan keeps the view over the graph, the whole of it when not zoomed in
+func (m *GraphModel) clampPan() {
+	half := 0.5 / m.zoom
+	m.panX = math.Min(math.Max(m.panX, half), 1-half)
+	m.panY = math.Min(math.Max(m.panY, half), 1-half)
+}
+
+// panToSelected centers the view on the selected note when it is out of sight
+func (m *GraphModel) panToSelected() {
+	p := m.layout.pos[m.canvasSelected]
+	x, y := m.viewPoint(p)
+	if x < 0 || x > 1 || y < 0 || y > 1 {
+		m.panX, m.panY = p.x, p.y
+		m.clampPan()
+	}
+}
+
+// viewPoint is where a layout point is in the view, inside it when both are between 0 and 1
+func (m GraphModel) viewPoint(p graphPoint) (float64, float64) {
+	return (p.x-m.panX)*m.zoom + 0.5, (p.y-m.panY)*m.zoom + 0.5
+}
+
+// canvasHeight is how many rows the drawing takes, below the text above it
+func (m GraphModel) canvasHeight(above string) int {
+	// Header and status bar of the TUI, the line about the selected note and the hints
+	return max(m.height-lipgloss.Height(above)-7, 8)
+}
+
+// renderCanvas draws the laid out graph: links as braille lines, notes as dots in their
+// cluster's color with their titles where they fit
+func (m GraphModel) renderCanvas(height int) string {
+	layout := m.layout
+	width := max(m.width-2, 20)
+	canvas := newGraphCanvas(width, height)
+	dotsWidth, dotsHeight := width*2, height*4
+
+	// Notes sit left of the space their title takes to the right
+	points := make([][2]int, len(layout.nodes))
+	for i, p := range layout.pos {
+		x, y := m.viewPoint(p)
+		points[i] = [2]int{
+			int(math.Round((0.02 + x*0.84) * float64(dotsWidth-1))),
+			int(math.Round((0.05 + y*0.9) * float64(dotsHeight-1))),
+		}
+	}
+
+	onPath := map[uuid.UUID]bool{}
+	pathLinks := map[[2]uuid.UUID]bool{}
+	if m.path != nil {
+		for _, note := range m.path.Path {
+			onPath[note.ID] = true
+		}
+		for _, link := range m.path.Links {
+			pathLinks[[2]uuid.UUID{link.Source, link.Target}] = true
+		}
+	}
+
+	for _, edge := range layout.edges {
+		a, b := points[edge[0]], points[edge[1]]
+		paint := paintEdge
+		if pathLinks[[2]uuid.UUID{layout.nodes[edge[0]].ID, layout.nodes[edge[1]].ID}] {
+			paint = paintPathEdge
+		}
+		canvas.line(a[0], a[1], b[0], b[1], paint)
+	}
+
+	clusterSizes := m.clusterSizes()
+	nodePaint := func(i int) int {
+		node := layout.nodes[i]
+		switch {
+		case i == m.canvasSelected:
+			return paintSelected
+		case clusterSizes[node.Cluster] > 1:
+			return paintCluster + node.Cluster
+		default:
+			return paintLabel
+		}
+	}
+	for i, node := range layout.nodes {
+		marker := "●"
+		if m.rootID != nil && node.ID == *m.rootID {
+			marker = "◉"
+		}
+		canvas.text(points[i][0]/2, points[i][1]/4, marker, nodePaint(i))
+	}
+
+	// Titles of the selected note and the path first, then of the most linked notes
+	order := make([]int, len(layout.nodes))
+	for i := range order {
+		order[i] = i
+	}
+	rank := func(i int) int {
+		switch {
+		case i == m.canvasSelected:
+			return 0
+		case onPath[layout.nodes[i].ID]:
+			return 1
+		default:
+			return 2
+		}
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		if rank(order[i]) != rank(order[j]) {
+			return rank(order[i]) < rank(order[j])
+		}
+		return layout.degree[order[i]] > layout.degree[order[j]]
+	})
+	for _, i := range order {
+		x, y := points[i][0]/2, points[i][1]/4
+		if x < 0 || x >= width || y < 0 || y >= height {
+			continue
+		}
+		label := " " + graphLabel(layout.nodes[i])
+		if !canvas.free(x+1, y, ansi.StringWidth(label)) {
+			continue
+		}
+		paint := nodePaint(i)
+		if onPath[layout.nodes[i].ID] && i != m.canvasSelected {
+			paint = paintPathEdge
+		}
+		canvas.text(x+1, y, label, paint)
+	}
+
+	edgeStyle := lipgloss.NewStyle().Foreground(theme().Muted).Faint(true)
+	pathStyle := lipgloss.NewStyle().Foreground(theme().Warning).Bold(true)
+	labelStyle := lipgloss.NewStyle().Foreground(theme().Foreground)
+	selectedStyle := lipgloss.NewStyle().
+		Foreground(theme().Background).
+		Background(theme().Primary).
+		Bold(true)
+
+	return canvas.render(func(paint int) lipgloss.Style {
+		switch {
+		case paint == paintPathEdge:
+			return pathStyle
+		case paint == paintLabel:
+			return labelStyle
+		case paint == paintSelected:
+			return selectedStyle
+		case paint >= paintCluster:
+			return labelStyle.Foreground(clusterColor(paint - paintCluster))
+		default:
+			return edgeStyle
+		}
+	})
+}
+
+// renderCanvasInfo describes the selected note and how much of the graph is drawn
+func (m GraphModel) renderCanvasInfo() string {
+	mutedStyle := lipgloss.NewStyle().
+		Foreground(theme().Muted).
+		Faint(true)
+
+	node := m.layout.nodes[m.canvasSelected]
+	title := node.Title
+	if title == "" {
+		title = "(untitled)"
+	}
+	info := fmt.Sprintf("%s · %d links", truncateText(title, 40), m.layout.degree[m.canvasSelected])
+	if m.clusterSizes()[node.Cluster] > 1 {
+		info += fmt.Sprintf(" · cluster %d", node.Cluster)
+	}
+	info += fmt.Sprintf(" · zoom %.1fx", m.zoom)
+	if m.layout.total > len(m.layout.nodes) {
+		info += fmt.Sprintf(" · %d most linked of %d notes (v:list shows all)", len(m.layout.nodes), m.layout.total)
+	}
+
+	return mutedStyle.Render(truncateText(info, m.width-2))
+}
@@ -11,8 +11,6 @@ type HelpModel struct {
 	viewport viewport.Model
 	width    int
 	height   int
-	// Styles passed from parent
-	styles   helpStyles
 }
 
 // helpStyles contains the styles needed for help rendering
@@ -27,34 +25,30 @@ type helpStyles struct {
 func NewHelpModel() HelpModel {
 	vp := viewport.New(0, 0)
 
-	// Define styles inline to avoid import cycle
-	sectionStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#fab387")). // Orange
-		Bold(true).
-		MarginTop(1).
-		MarginBottom(0)
-
-	keyStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#a6e3a1")). // Green
-		Bold(true).
-		Width(12)
-
-	descStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#cdd6f4")) // Light text
-
-	codeStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#a6e3a1")). // Green
-		Background(lipgloss.Color("#313244")).  // Selected background
-		Padding(0, 1)
-
 	return HelpModel{
 		viewport: vp,
-		styles: helpStyles{
-			SectionStyle: sectionStyle,
-			KeyStyle:     keyStyle,
-			DescStyle:    descStyle,
-			CodeStyle:    codeStyle,
-		},
+	}
+}
+
+// newHelpStyles builds the help styles from the current theme
+// Defined here rather than in the tui package to avoid an import cycle.
+func newHelpStyles() helpStyles {
+	return helpStyles{
+		SectionStyle: lipgloss.NewStyle().
+			Foreground(theme().Secondary).
+			Bold(true).
+			MarginTop(1).
+			MarginBottom(0),
+		KeyStyle: lipgloss.NewStyle().
+			Foreground(theme().Accent).
+			Bold(true).
+			Width(12),
+		DescStyle: lipgloss.NewStyle().
+			Foreground(theme().Foreground),
+		CodeStyle: lipgloss.NewStyle().
+			Foreground(theme().Accent).
+			Background(theme().Selected).
+			Padding(0, 1),
 	}
 }
 
@@ -96,94 +90,210 @@ func (m HelpModel) View() string {
 // getHelpContent returns the help text content
 func (m HelpModel) getHelpContent() string {
 	joinHorizontal := lipgloss.JoinHorizontal
+	styles := newHelpStyles()
 
 	content := `
-` + m.styles.SectionStyle.Render("KEY BINDINGS") + `
+` + styles.SectionStyle.Render("KEY BINDINGS") + `
 
 ` + joinHorizontal(lipgloss.Top,
-		m.styles.KeyStyle.Render("q"),
-		m.styles.DescStyle.Render("Quit TUI"),
+		styles.KeyStyle.Render("q"),
+		styles.DescStyle.Render("Quit TUI"),
+	) + `
+` + joinHorizontal(lipgloss.Top,
+		styles.KeyStyle.Render("? / F1"),
+		styles.DescStyle.Render("Show this help screen"),
 	) + `
 ` + joinHorizontal(lipgloss.Top,
-		m.styles.KeyStyle.Render("? / F1"),
-		m.styles.DescStyle.Render("Show this help screen"),
+		styles.KeyStyle.Render("ESC"),
+		styles.DescStyle.Render("Go back / Cancel current operation"),
 	) + `
 ` + joinHorizontal(lipgloss.Top,
-		m.styles.KeyStyle.Render("ESC"),
-		m.styles.DescStyle.Render("Go back / Cancel current operation"),
+		styles.KeyStyle.Render("/"),
+		styles.DescStyle.Render("Quick search (from any view)"),
 	) + `
 ` + joinHorizontal(lipgloss.Top,
-		m.styles.KeyStyle.Render("/"),
-		m.styles.DescStyle.Render("Quick search (from any view)"),
+		styles.KeyStyle.Render("n"),
+		styles.DescStyle.Render("Create new note (from any view)"),
 	) + `
 ` + joinHorizontal(lipgloss.Top,
-		m.styles.KeyStyle.Render("n"),
-		m.styles.DescStyle.Render("Create new note (from any view)"),
+		styles.KeyStyle.Render("Ctrl+K"),
+		styles.DescStyle.Render("Quick switcher - jump to a note by title, > for commands (themes)"),
 	) + `
 ` + joinHorizontal(lipgloss.Top,
-		m.styles.KeyStyle.Render("Ctrl+C"),
-		m.styles.DescStyle.Render("Force quit (no confirmation)"),
+		styles.KeyStyle.Render("u"),
+		styles.DescStyle.Render("Undo a note deletion or tag removal (for 10 seconds)"),
+	) + `
+` + joinHorizontal(lipgloss.Top,
+		styles.KeyStyle.Render("Ctrl+C"),
+		styles.DescStyle.Render("Force quit (no confirmation)"),
 	) + `
 
-` + m.styles.SectionStyle.Render("NAVIGATION") + `
+` + styles.SectionStyle.Render("NAVIGATION") + `
 
 ` + joinHorizontal(lipgloss.Top,
-		m.styles.KeyStyle.Render("h / ←"),
-		m.styles.DescStyle.Render("Move left / Previous item"),
+		styles.KeyStyle.Render("h / ←"),
+		styles.DescStyle.Render("Move left / Previous item"),
 	) + `
 ` + joinHorizontal(lipgloss.Top,
-		m.styles.KeyStyle.Render("j / ↓"),
-		m.styles.DescStyle.Render("Move down / Next item"),
+		styles.KeyStyle.Render("j / ↓"),
+		styles.DescStyle.Render("Move down / Next item"),
 	) + `
 ` + joinHorizontal(lipgloss.Top,
-		m.styles.KeyStyle.Render("k / ↑"),
-		m.styles.DescStyle.Render("Move up / Previous item"),
+		styles.KeyStyle.Render("k / ↑"),
+		styles.DescStyle.Render("Move up / Previous item"),
 	) + `
 ` + joinHorizontal(lipgloss.Top,
-		m.styles.KeyStyle.Render("l / →"),
-		m.styles.DescStyle.Render("Move right / Select item"),
+		styles.KeyStyle.Render("l / →"),
+		styles.DescStyle.Render("Move right / Select item"),
 	) + `
 ` + joinHorizontal(lipgloss.Top,
-		m.styles.KeyStyle.Render("Enter"),
-		m.styles.DescStyle.Render("Open selected item / Confirm"),
+		styles.KeyStyle.Render("Enter"),
+		styles.DescStyle.Render("Open selected item / Confirm"),
 	) + `
 ` + joinHorizontal(lipgloss.Top,
-		m.styles.KeyStyle.Render("0"),
-		m.styles.DescStyle.Render("Go to top of list"),
+		styles.KeyStyle.Render("0"),
+		styles.DescStyle.Render("Go to top of list"),
 	) + `
 ` + joinHorizontal(lipgloss.Top,
-		m.styles.KeyStyle.Render("G"),
-		m.styles.DescStyle.Render("Go to bottom of list"),
+		styles.KeyStyle.Render("G"),
+		styles.DescStyle.Render("Go to bottom of list"),
 	) + `
 
-` + m.styles.SectionStyle.Render("DASHBOARD") + `
+` + styles.SectionStyle.Render("DASHBOARD") + `
 
 ` + joinHorizontal(lipgloss.Top,
-		m.styles.KeyStyle.Render("n"),
-		m.styles.DescStyle.Render("Create new note"),
+		styles.KeyStyle.Render("n"),
+		styles.DescStyle.Render("Create new note"),
+	) + `
+` + joinHorizontal(lipgloss.Top,
+		styles.KeyStyle.Render("s"),
+		styles.DescStyle.Render("Go to search"),
+	) + `
+` + joinHorizontal(lipgloss.Top,
+		styles.KeyStyle.Render("l"),
+		styles.DescStyle.Render("View all notes"),
+	) + `
+` + joinHorizontal(lipgloss.Top,
+		styles.KeyStyle.Render("a"),
+		styles.DescStyle.Render("View activity feed"),
+	) + `
+` + joinHorizontal(lipgloss.Top,
+		styles.KeyStyle.Render("1-5"),
+		styles.DescStyle.Render("Open a resurfaced note"),
+	) + `
+` + joinHorizontal(lipgloss.Top,
+		styles.KeyStyle.Render("6-8"),
+		styles.DescStyle.Render("Continue reading a partly read note"),
+	) + `
+` + joinHorizontal(lipgloss.Top,
+		styles.KeyStyle.Render("i"),
+		styles.DescStyle.Render("Open the Inbox of quick captures"),
+	) + `
+` + joinHorizontal(lipgloss.Top,
+		styles.KeyStyle.Render("x"),
+		styles.DescStyle.Render("View open tasks from all notes"),
+	) + `
+` + joinHorizontal(lipgloss.Top,
+		styles.KeyStyle.Render("B"),
+		styles.DescStyle.Render("View the board of notes by status"),
+	) + `
+` + joinHorizontal(lipgloss.Top,
+		styles.KeyStyle.Render("C"),
+		styles.DescStyle.Render("View the calendar of daily notes"),
+	) + `
+` + joinHorizontal(lipgloss.Top,
+		styles.KeyStyle.Render("S"),
+		styles.DescStyle.Render("View and revoke signed-in devices"),
+	) + `
+` + joinHorizontal(lipgloss.Top,
+		styles.KeyStyle.Render("P"),
+		styles.DescStyle.Render("View published notes, copy or revoke their links"),
+	) + `
+` + joinHorizontal(lipgloss.Top,
+		styles.KeyStyle.Render("A"),
+		styles.DescStyle.Render("View tags that are growing, stale or used together"),
+	) + `
+` + joinHorizontal(lipgloss.Top,
+		styles.KeyStyle.Render("R"),
+		styles.DescStyle.Render("Review the flashcards due today"),
+	) + `
+
+` + styles.SectionStyle.Render("NOTE LIST") + `
+
+` + joinHorizontal(lipgloss.Top,
+		styles.KeyStyle.Render("Tab"),
+		styles.DescStyle.Render("Switch between your notes and notes shared with you"),
+	) + `
+` + joinHorizontal(lipgloss.Top,
+		styles.KeyStyle.Render("Space"),
+		styles.DescStyle.Render("Mark / unmark note"),
+	) + `
+` + joinHorizontal(lipgloss.Top,
+		styles.KeyStyle.Render("T"),
+		styles.DescStyle.Render("Tag marked notes (-name removes the tag)"),
+	) + `
+` + joinHorizontal(lipgloss.Top,
+		styles.KeyStyle.Render("y + c/t/l/i"),
+		styles.DescStyle.Render("Copy content / title / [[link]] / ID"),
+	) + `
+` + joinHorizontal(lipgloss.Top,
+		styles.KeyStyle.Render("s"),
+		styles.DescStyle.Render("Cycle the sort order"),
+	) + `
+` + joinHorizontal(lipgloss.Top,
+		styles.KeyStyle.Render("1-9 / 0"),
+		styles.DescStyle.Render("Show one note type / every type"),
+	) + `
+` + joinHorizontal(lipgloss.Top,
+		styles.KeyStyle.Render("v / p"),
+		styles.DescStyle.Render("Toggle details under notes / preview pane"),
+	) + `
+` + joinHorizontal(lipgloss.Top,
+		styles.KeyStyle.Render("< / >"),
+		styles.DescStyle.Render("Widen / narrow the preview pane"),
+	) + `
+` + joinHorizontal(lipgloss.Top,
+		styles.KeyStyle.Render("+ / -"),
+		styles.DescStyle.Render("More / fewer notes per page"),
+	) + `
+
+` + styles.SectionStyle.Render("NOTE DETAIL") + `
+
+` + joinHorizontal(lipgloss.Top,
+		styles.KeyStyle.Render("/"),
+		styles.DescStyle.Render("Find within the note (Content tab)"),
+	) + `
+` + joinHorizontal(lipgloss.Top,
+		styles.KeyStyle.Render("n / N"),
+		styles.DescStyle.Render("Next / previous find match"),
+	) + `
+` + joinHorizontal(lipgloss.Top,
+		styles.KeyStyle.Render("S"),
+		styles.DescStyle.Render("Summarize the note"),
 	) + `
 ` + joinHorizontal(lipgloss.Top,
-		m.styles.KeyStyle.Render("s"),
-		m.styles.DescStyle.Render("Go to search"),
+		styles.KeyStyle.Render("M"),
+		styles.DescStyle.Render("Show / hide the note's metadata"),
 	) + `
 ` + joinHorizontal(lipgloss.Top,
-		m.styles.KeyStyle.Render("l"),
-		m.styles.DescStyle.Render("View all notes"),
+		styles.KeyStyle.Render("y + c/t/l/i"),
+		styles.DescStyle.Render("Copy content / title / [[link]] / ID (yy copies content)"),
 	) + `
 ` + joinHorizontal(lipgloss.Top,
-		m.styles.KeyStyle.Render("a"),
-		m.styles.DescStyle.Render("View activity feed"),
+		styles.KeyStyle.Render("F"),
+		styles.DescStyle.Render("Start a 25 minute focus session on the note (F again stops it)"),
 	) + `
 
-` + m.styles.SectionStyle.Render("TIPS") + `
+` + styles.SectionStyle.Render("TIPS") + `
 
-• Press ` + m.styles.CodeStyle.Render("?") + ` anytime to see this help
+• Press ` + styles.CodeStyle.Render("?") + ` anytime to see this help
 • Key hints are shown in the status bar (bottom of screen)
 • Vim navigation (h/j/k/l) works alongside arrow keys
+• Set ` + styles.CodeStyle.Render("editor.vim_mode: true") + ` in the config for vim-style note editing
 • Session expiry will auto-exit TUI to protect your data
 • All changes are saved before session expiry
-• Use ` + m.styles.CodeStyle.Render("ESC") + ` to go back from any view
-• Use ` + m.styles.CodeStyle.Render("q") + ` to quit TUI from any view
+• Use ` + styles.CodeStyle.Render("ESC") + ` to go back from any view
+• Use ` + styles.CodeStyle.Render("q") + ` to quit TUI from any view
 
 `
 
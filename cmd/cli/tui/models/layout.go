@@ -0,0 +1,33 @@
+package models
+
+// Layout holds the layout preferences of the views, kept in the CLI config between launches
+type Layout struct {
+	NoteList NoteListLayout
+}
+
+// NoteListLayout is the layout of the note list
+type NoteListLayout struct {
+	ShowDescription bool // Show tags or a content preview under each title
+	PageSize        int  // Notes per page; 0 uses the account's page_size setting
+	PreviewWidth    int  // Percentage of the width taken by the preview pane; 0 hides it
+}
+
+// Preview pane widths, as a percentage of the view width
+const (
+	DefaultPreviewWidth = 40
+	minPreviewWidth     = 20
+	maxPreviewWidth     = 70
+	previewWidthStep    = 10
+)
+
+// Note list page sizes; the API returns at most 100 notes per page
+const (
+	minNoteListPageSize  = 5
+	maxNoteListPageSize  = 100
+	noteListPageSizeStep = 5
+)
+
+// NoteListLayoutMsg is sent when the note list layout changes, so it can be saved
+type NoteListLayoutMsg struct {
+	Layout NoteListLayout
+}
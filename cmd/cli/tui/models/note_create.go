@@ -1,7 +1,11 @@
 package models
 
 import (
+	"errors"
 	"fmt"
+	"maps"
+	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -25,6 +29,7 @@ type NoteCreateModel struct {
 	authState  *client.AuthState
 	mode       NoteCreateMode
 	noteID     uuid.UUID // For edit mode
+	encrypted  bool      // Re-encrypt content before saving (edit mode)
 	form       components.Form
 	loading    bool
 	err        error
@@ -32,10 +37,23 @@ type NoteCreateModel struct {
 	hasChanges bool // Track unsaved changes
 	width      int
 	height     int
+
+	// Custom fields of the selected type, shown below the type field
+	typeFields  map[model.NoteType][]*model.NoteTypeField
+	shownType   model.NoteType
+	fieldValues map[string]string // Values typed into custom fields, kept when switching types
+	metadata    model.Metadata    // Metadata of the note being edited
+
+	// Drafts
+	drafts       *DraftManager // nil when drafts can't be stored
+	draftSession int64         // Tells the draft ticks of this form from those of earlier forms
+	original     Draft         // Form values when opened
+	lastDraft    Draft         // Form values when the draft was last saved
+	restore      *Draft        // Draft found when opening, until restored or discarded
 }
 
 // NewNoteCreateModel creates a new note create model
-func NewNoteCreateModel(apiClient *client.APIClient, authState *client.AuthState) NoteCreateModel {
+func NewNoteCreateModel(apiClient *client.APIClient, authState *client.AuthState, drafts *DraftManager) NoteCreateModel {
 	form := components.NewForm()
 	form.SetSubmitText("Save")
 	form.SetCancelText("Cancel")
@@ -56,16 +74,37 @@ func NewNoteCreateModel(apiClient *client.APIClient, authState *client.AuthState
 	contentField.SetPlaceholder("Enter note content...")
 	form.AddField(contentField)
 
-	return NoteCreateModel{
-		client:    apiClient,
-		authState: authState,
-		mode:      ModeCreate,
-		noteID:    uuid.Nil,
-		form:      form,
-		loading:   false,
-		width:     80,
-		height:    24,
+	// Type field, starting on the default type of new notes
+	typeField := components.NewFormField("type", "Type", components.FieldSelect)
+	noteTypes := apiClient.NoteTypes()
+	typeOptions := make([]string, len(noteTypes))
+	typeFields := make(map[model.NoteType][]*model.NoteTypeField, len(noteTypes))
+	for i, noteType := range noteTypes {
+		typeOptions[i] = string(noteType.Name)
+		typeFields[noteType.Name] = noteType.Fields
+	}
+	typeField.SetOptions(typeOptions)
+	typeField.SetValue(string(apiClient.Settings().DefaultNoteType))
+	form.AddField(typeField)
+
+	m := NoteCreateModel{
+		client:       apiClient,
+		authState:    authState,
+		mode:         ModeCreate,
+		noteID:       uuid.Nil,
+		form:         form,
+		loading:      false,
+		width:        80,
+		height:       24,
+		typeFields:   typeFields,
+		fieldValues:  make(map[string]string),
+		drafts:       drafts,
+		draftSession: time.Now().UnixNano(),
 	}
+	m = m.withTypeFields()
+	m.original = m.formDraft()
+	m.lastDraft = m.original
+	return m
 }
 
 // SetEditMode sets the model to edit mode with existing note data
@@ -73,19 +112,36 @@ func NewNoteCreateModel(apiClient *client.APIClient, authState *client.AuthState
 func (m NoteCreateModel) SetEditMode(note *model.Note) (NoteCreateModel, tea.Cmd) {
 	m.mode = ModeEdit
 	m.noteID = note.ID
+	m.encrypted = note.Encrypted
 	m.form.Fields()[0].SetValue(note.Title)    // Title
 	m.form.Fields()[1].SetValue(note.Content)  // Content
+	m.form.Fields()[2].SetValue(string(note.NoteType))
+	m.metadata = note.Metadata
+	m = m.withTypeFields()
+	values := make(map[string]string)
+	for _, field := range m.typeFields[note.NoteType] {
+		if value, ok := note.Metadata[field.Name]; ok {
+			values[field.Name] = formatTypeField(field, value)
+		}
+	}
+	m.setTypeFieldValues(values)
 	m.form.SetSubmitText("Update")
 	m.hasChanges = false
 	// Focus the form so user can edit
 	m = m.FocusForm()
-	return m, nil
+
+	// Start over with the drafts of this note
+	m.draftSession = time.Now().UnixNano()
+	m.original = m.formDraft()
+	m.lastDraft = m.original
+	m.restore = nil
+	return m, m.startDrafts()
 }
 
 // Init initializes the note create model
 func (m NoteCreateModel) Init() tea.Cmd {
 	m.form.Focus()
-	return nil
+	return m.startDrafts()
 }
 
 // FocusForm focuses the form and returns the updated model
@@ -99,11 +155,33 @@ func (m NoteCreateModel) FocusForm() NoteCreateModel {
 func (m NoteCreateModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		// A draft was found: restore or discard it before editing
+		if m.restore != nil && msg.String() != "esc" {
+			switch msg.String() {
+			case "y":
+				m.form.Fields()[0].SetValue(m.restore.Title)
+				m.form.Fields()[1].SetValue(m.restore.Content)
+				m.form.Fields()[2].SetValue(m.restore.Type)
+				m = m.withTypeFields()
+				m.setTypeFieldValues(m.restore.Fields)
+				m.lastDraft = *m.restore
+				m.hasChanges = true
+				m.restore = nil
+			case "n":
+				m.restore = nil
+				return m, m.clearDraftCmd()
+			}
+			return m, nil
+		}
+
 		// Handle form submission
 		if msg.String() == "enter" && m.form.Focused() {
 			// Validate and submit
 			if err := m.validateForm(); err != nil {
-				m.form.Fields()[m.form.CurrentIndex()].Error = err.Error()
+				var fieldErr *ValidationError
+				if !errors.As(err, &fieldErr) || !m.setFieldErrors(map[string]string{fieldErr.Field: err.Error()}) {
+					m.form.Fields()[m.form.CurrentIndex()].Error = err.Error()
+				}
 				return m, nil
 			}
 
@@ -115,14 +193,15 @@ func (m NoteCreateModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		// ESC to cancel - always allow exiting (discards unsaved changes)
-		if msg.String() == "esc" {
+		// In vim insert mode it returns to normal mode instead
+		if msg.String() == "esc" && !m.VimInsertMode() {
 			if m.hasChanges && !m.saved {
 				// TODO: Phase E - add proper unsaved changes dialog
 				// For now, just discard changes and go back
 			}
-			return m, func() tea.Msg {
+			return m, tea.Sequence(m.DiscardDraft(), func() tea.Msg {
 				return ShowDashboardMsg{}
-			}
+			})
 		}
 
 		// Track changes
@@ -130,6 +209,18 @@ func (m NoteCreateModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.hasChanges = true
 		}
 
+	case DraftFoundMsg:
+		if msg.session == m.draftSession {
+			m.restore = msg.Draft
+		}
+		return m, nil
+
+	case draftTickMsg:
+		if msg.session != m.draftSession {
+			return m, nil
+		}
+		return m.saveDraft()
+
 	case NoteCreatedMsg:
 		m.saved = true
 		m.loading = false
@@ -149,8 +240,13 @@ func (m NoteCreateModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case NoteCreateErrMsg:
-		m.err = msg.Err
 		m.loading = false
+		// Show what the server rejected next to the fields, so the note can be fixed and saved again
+		var apiErr *client.APIError
+		if errors.As(msg.Err, &apiErr) && m.setFieldErrors(apiErr.Fields) {
+			return m, nil
+		}
+		m.err = msg.Err
 		return m, nil
 
 	case tea.WindowSizeMsg:
@@ -160,11 +256,126 @@ func (m NoteCreateModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	// Update form
+	// Update form, then show the fields of the type it selects
 	cmd := m.form.Update(msg)
+	m = m.withTypeFields()
 	return m, cmd
 }
 
+// noteFormFields is the number of fields every note has: title, content and type
+const noteFormFields = 3
+
+// typeFieldPrefix starts the form field IDs of custom fields, followed by the field name
+const typeFieldPrefix = "field:"
+
+// typeFieldID returns the form field ID of a custom field
+func typeFieldID(name string) string {
+	return typeFieldPrefix + name
+}
+
+// withTypeFields shows the custom fields of the selected type below the type field
+func (m NoteCreateModel) withTypeFields() NoteCreateModel {
+	noteType := model.NoteType(m.form.Fields()[2].Value())
+	if noteType == m.shownType {
+		return m
+	}
+
+	// Keep what was typed, fields of another type with the same name start with it
+	formFields := m.form.Fields()
+	for _, field := range formFields[noteFormFields:] {
+		m.fieldValues[strings.TrimPrefix(field.ID, typeFieldPrefix)] = field.Value()
+	}
+
+	fields := append([]components.FormField{}, formFields[:noteFormFields]...)
+	for _, field := range m.typeFields[noteType] {
+		fields = append(fields, newTypeFormField(field, m.fieldValues[field.Name]))
+	}
+	m.form.SetFields(fields)
+	m.form.SetWidth(m.width - 10)
+	m.shownType = noteType
+	return m
+}
+
+// setTypeFieldValues fills in the shown custom fields, by field name
+func (m NoteCreateModel) setTypeFieldValues(values map[string]string) {
+	fields := m.form.Fields()
+	for i := noteFormFields; i < len(fields); i++ {
+		if value, ok := values[strings.TrimPrefix(fields[i].ID, typeFieldPrefix)]; ok {
+			fields[i].SetValue(value)
+		}
+	}
+}
+
+// newTypeFormField creates the form field of a custom field
+func newTypeFormField(field *model.NoteTypeField, value string) components.FormField {
+	label := field.Name
+	if field.Required {
+		label += "*"
+	}
+
+	if field.Type == model.NoteFieldBoolean {
+		formField := components.NewFormField(typeFieldID(field.Name), label, components.FieldSelect)
+		options := []string{"yes", "no"}
+		if !field.Required {
+			options = append([]string{"-"}, options...)
+		}
+		formField.SetOptions(options)
+		formField.SetValue(value)
+		return formField
+	}
+
+	formField := components.NewFormField(typeFieldID(field.Name), label, components.FieldInput)
+	switch field.Type {
+	case model.NoteFieldDate:
+		formField.SetPlaceholder("YYYY-MM-DD")
+	case model.NoteFieldList:
+		formField.SetPlaceholder("Comma-separated values...")
+	case model.NoteFieldNumber:
+		formField.SetPlaceholder("Number...")
+	}
+	formField.SetValue(value)
+	return formField
+}
+
+// formatTypeField returns a custom field value as the form shows it
+func formatTypeField(field *model.NoteTypeField, value any) string {
+	if b, ok := value.(bool); ok {
+		if b {
+			return "yes"
+		}
+		return "no"
+	}
+	return field.Format(value)
+}
+
+// typeFieldMetadata reads the custom fields of the selected type into metadata
+// Cleared fields of a note being edited are removed from its metadata.
+func (m NoteCreateModel) typeFieldMetadata() (model.Metadata, error) {
+	values := m.form.Values()
+	metadata := make(model.Metadata)
+	for _, field := range m.typeFields[model.NoteType(values["type"])] {
+		text := values[typeFieldID(field.Name)]
+		if field.Type == model.NoteFieldBoolean && text == "-" {
+			text = ""
+		}
+
+		value, err := field.Parse(text)
+		if err != nil {
+			return nil, &ValidationError{Field: typeFieldID(field.Name), Message: err.Error()}
+		}
+		if value == nil {
+			if field.Required {
+				return nil, &ValidationError{Field: typeFieldID(field.Name), Message: field.Name + " is required"}
+			}
+			if _, ok := m.metadata[field.Name]; !ok {
+				continue
+			}
+		}
+		metadata[field.Name] = value
+	}
+	return metadata, nil
+}
+
 // validateForm validates the form fields
 func (m NoteCreateModel) validateForm() error {
 	values := m.form.Values()
@@ -187,25 +398,49 @@ func (m NoteCreateModel) validateForm() error {
 		return &ValidationError{Field: "content", Message: "Content too long"}
 	}
 
+	// Validate custom fields
+	if _, err := m.typeFieldMetadata(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
+// setFieldErrors shows server validation errors on the matching form fields
+// Returns false when none of the fields is on the form.
+func (m NoteCreateModel) setFieldErrors(fields map[string]string) bool {
+	shown := false
+	for i := range m.form.Fields() {
+		field := &m.form.Fields()[i]
+		if msg, ok := fields[field.ID]; ok {
+			field.Error = msg
+			shown = true
+		}
+	}
+	return shown
+}
+
 // createNoteCmd returns a command that creates a new note
 func (m NoteCreateModel) createNoteCmd() tea.Cmd {
 	m.loading = true
 	values := m.form.Values()
+	metadata, _ := m.typeFieldMetadata()
 
 	return func() tea.Msg {
 		req := &model.CreateNoteRequest{
 			Title:    values["title"],
 			Content:  values["content"],
-			NoteType: "note", // Default to note type
+			NoteType: model.NoteType(values["type"]),
+			Metadata: metadata,
 		}
 
 		note, err := m.client.CreateNote(req)
 		if err != nil {
 			return NoteCreateErrMsg{Err: err}
 		}
+		if m.drafts != nil {
+			_ = m.drafts.ClearDraft(uuid.Nil)
+		}
 
 		return NoteCreatedMsg{NoteID: note.ID}
 	}
@@ -215,19 +450,37 @@ func (m NoteCreateModel) createNoteCmd() tea.Cmd {
 func (m NoteCreateModel) updateNoteCmd() tea.Cmd {
 	m.loading = true
 	values := m.form.Values()
+	metadata, _ := m.typeFieldMetadata()
 
 	return func() tea.Msg {
 		title := values["title"]
 		content := values["content"]
+		noteType := model.NoteType(values["type"])
+
+		// The note was decrypted for editing, so encrypt it again before it leaves the client
+		if m.encrypted {
+			encrypted, err := m.client.EncryptContent(content)
+			if err != nil {
+				return NoteCreateErrMsg{Err: err}
+			}
+			content = encrypted
+		}
 
 		req := &model.UpdateNoteRequest{
-			Title:   &title,
-			Content: &content,
+			Title:    &title,
+			Content:  &content,
+			NoteType: &noteType,
+		}
+		if len(metadata) > 0 {
+			req.Metadata = metadata
 		}
 
 		if err := m.client.UpdateNote(m.noteID, req); err != nil {
 			return NoteCreateErrMsg{Err: err}
 		}
+		if m.drafts != nil {
+			_ = m.drafts.ClearDraft(m.noteID)
+		}
 
 		return NoteUpdatedMsg{NoteID: m.noteID}
 	}
@@ -247,7 +500,7 @@ func (m NoteCreateModel) View() string {
 
 	// Header
 	titleStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#fab387")). // Orange
+		Foreground(theme().Secondary).
 		Bold(true)
 
 	if m.mode == ModeCreate {
@@ -258,12 +511,25 @@ func (m NoteCreateModel) View() string {
 
 	if m.hasChanges && !m.saved {
 		content += " " + lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#f38ba8")). // Red
+			Foreground(theme().Error).
 			Render("(unsaved)")
+		if !m.lastDraft.SavedAt.IsZero() {
+			content += " " + lipgloss.NewStyle().
+				Foreground(theme().Muted).
+				Render("draft saved "+m.lastDraft.SavedAt.Format("15:04"))
+		}
 	}
 
 	content += "\n\n"
 
+	if m.restore != nil {
+		content += lipgloss.NewStyle().
+			Foreground(theme().Warning).
+			Bold(true).
+			Render(fmt.Sprintf("Found an unsaved draft from %s. Restore it? (y/n)",
+				m.restore.SavedAt.Local().Format("Jan 2 15:04"))) + "\n\n"
+	}
+
 	// Form
 	content += m.form.View()
 
@@ -273,7 +539,7 @@ func (m NoteCreateModel) View() string {
 // renderLoading renders the loading state
 func (m NoteCreateModel) renderLoading() string {
 	style := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#89b4fa")). // Blue
+		Foreground(theme().Primary).
 		Bold(true)
 
 	if m.mode == ModeCreate {
@@ -285,7 +551,7 @@ func (m NoteCreateModel) renderLoading() string {
 // renderError renders the error state
 func (m NoteCreateModel) renderError() string {
 	errorStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#f38ba8")). // Red
+		Foreground(theme().Error).
 		Bold(true)
 
 	return errorStyle.Render(fmt.Sprintf("Error: %v\n\nESC to go back", m.err))
@@ -307,14 +573,129 @@ func (m NoteCreateModel) IsInputFocused() bool {
 	return false
 }
 
+// VimInsertMode returns whether the content is being edited in vim insert mode
+func (m NoteCreateModel) VimInsertMode() bool {
+	return m.form.Focused() && m.form.VimInsertMode()
+}
+
 // BlurForm removes focus from the form
 func (m NoteCreateModel) BlurForm() NoteCreateModel {
 	m.form.Blur()
 	return m
 }
 
+// startDrafts loads the draft of the note and starts saving drafts
+// Encrypted notes are left out: their drafts would be stored in plain text.
+func (m NoteCreateModel) startDrafts() tea.Cmd {
+	if m.drafts == nil || m.encrypted {
+		return nil
+	}
+
+	noteID, session, original := m.noteID, m.draftSession, m.original
+	loadDraft := func() tea.Msg {
+		draft, err := m.drafts.LoadDraft(noteID)
+		// An unreadable draft can't be restored anyway
+		if err != nil || draft == nil || sameDraft(*draft, original) {
+			return nil
+		}
+		return DraftFoundMsg{Draft: draft, session: session}
+	}
+
+	return tea.Batch(loadDraft, m.draftTickCmd())
+}
+
+// draftTickCmd waits for the next draft save
+func (m NoteCreateModel) draftTickCmd() tea.Cmd {
+	session := m.draftSession
+	return tea.Tick(m.drafts.SaveInterval(), func(time.Time) tea.Msg {
+		return draftTickMsg{session: session}
+	})
+}
+
+// saveDraft saves the form as a draft if it changed since the last save
+// Undoing all changes removes the draft instead.
+func (m NoteCreateModel) saveDraft() (NoteCreateModel, tea.Cmd) {
+	tick := m.draftTickCmd()
+	if m.saved || m.loading || m.restore != nil {
+		return m, tick
+	}
+
+	draft := m.formDraft()
+	if sameDraft(draft, m.lastDraft) {
+		return m, tick
+	}
+
+	if sameDraft(draft, m.original) {
+		m.lastDraft = m.original
+		return m, tea.Batch(m.clearDraftCmd(), tick)
+	}
+
+	draft.SavedAt = time.Now()
+	m.lastDraft = draft
+	return m, tea.Batch(func() tea.Msg {
+		if err := m.drafts.Save(draft); err != nil {
+			return NoteCreateErrMsg{Err: err}
+		}
+		return nil
+	}, tick)
+}
+
+// DiscardDraft returns a command removing the draft when the form is cancelled
+// Leaving without answering the restore prompt keeps the draft for next time.
+func (m NoteCreateModel) DiscardDraft() tea.Cmd {
+	if m.restore != nil {
+		return nil
+	}
+	return m.clearDraftCmd()
+}
+
+// clearDraftCmd removes the draft of the note
+func (m NoteCreateModel) clearDraftCmd() tea.Cmd {
+	if m.drafts == nil || m.encrypted {
+		return nil
+	}
+	noteID := m.noteID
+	return func() tea.Msg {
+		if err := m.drafts.ClearDraft(noteID); err != nil {
+			return NoteCreateErrMsg{Err: err}
+		}
+		return nil
+	}
+}
+
+// formDraft returns the form values as a draft
+func (m NoteCreateModel) formDraft() Draft {
+	values := m.form.Values()
+	draft := Draft{NoteID: m.noteID, Title: values["title"], Content: values["content"], Type: values["type"]}
+	for _, field := range m.form.Fields()[noteFormFields:] {
+		if value := field.Value(); value != "" {
+			if draft.Fields == nil {
+				draft.Fields = make(map[string]string)
+			}
+			draft.Fields[strings.TrimPrefix(field.ID, typeFieldPrefix)] = value
+		}
+	}
+	return draft
+}
+
+// sameDraft reports whether two drafts hold the same note
+func sameDraft(a, b Draft) bool {
+	return a.Title == b.Title && a.Content == b.Content && a.Type == b.Type && maps.Equal(a.Fields, b.Fields)
+}
+
 // Message types for note create/edit
 
+// DraftFoundMsg is sent when the note being opened has an unsaved draft
+type DraftFoundMsg struct {
+	Draft   *Draft
+	session int64
+}
+
+// draftTickMsg is sent when the form is due to be saved as a draft
+type draftTickMsg struct {
+	session int64
+}
+
 type NoteCreatedMsg struct {
 	NoteID uuid.UUID
 }
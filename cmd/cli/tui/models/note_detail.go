@@ -4,12 +4,14 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/google/uuid"
 	"github.com/momokii/go-cli-notes/cmd/cli/client"
 	"github.com/momokii/go-cli-notes/cmd/cli/tui/components"
 	"github.com/momokii/go-cli-notes/internal/model"
+	"github.com/momokii/go-cli-notes/internal/util"
 )
 
 // NoteDetailTab represents the different tabs in note detail view
@@ -20,8 +22,14 @@ const (
 	NoteTagsTab
 	NoteLinksTab
 	NoteBacklinksTab
+	NoteRelatedTab
+	NoteHistoryTab
+	NoteStatsTab
 )
 
+// noteDetailTabCount is the number of tabs in the note detail view
+const noteDetailTabCount = 7
+
 // String returns the string representation of a tab
 func (t NoteDetailTab) String() string {
 	switch t {
@@ -33,6 +41,12 @@ func (t NoteDetailTab) String() string {
 		return "Links"
 	case NoteBacklinksTab:
 		return "Backlinks"
+	case NoteRelatedTab:
+		return "Related"
+	case NoteHistoryTab:
+		return "History"
+	case NoteStatsTab:
+		return "Stats"
 	default:
 		return "Unknown"
 	}
@@ -67,15 +81,57 @@ type NoteDetailModel struct {
 	addTagFilter         string
 	filteredAvailableTags []*model.Tag
 	selectedAvailableIndex int
+	// Revision history fields
+	revisions             []*model.NoteRevision
+	revisionsErr          error
+	revisionsLoaded       bool
+	selectedRevisionIndex int
+	pendingRestore        int // Revision number awaiting confirmation (0 = none)
+	// Related notes fields
+	related              []*model.RelatedNote
+	relatedErr           error
+	relatedLoaded        bool
+	selectedRelatedIndex int
+	// Note statistics fields
+	stats       *model.NoteStats
+	statsErr    error
+	statsLoaded bool
+	// Content tab rendering
+	contentViewport viewport.Model
+	markdown        components.MarkdownRenderer
+	// Wiki-link navigation in the content tab
+	contentLinks      []components.WikiLink
+	selectedLinkIndex int    // -1 = no link selected
+	linkStatus        string // Shown when a link can't be opened
+	pendingAnchor     *components.WikiLink // Heading or block to scroll to once the note is loaded
+	yankPending       bool   // y was pressed, the next key picks what to copy
+	// In-note find in the content tab
+	findInput   components.TextInput
+	findQuery   string
+	findMatches []components.FindMatch
+	findIndex   int
+	// Generated summary shown above the content
+	summary     *model.NoteSummary
+	summarizing bool
+	// Metadata shown above the content, collapsed to a line until M opens it
+	metadataOpen bool
+	// Where each note was left, restored when it is opened again
+	positions *ReadingPositions // nil when positions can't be stored
+	// How far down the note is read, in percent, as last saved to its metadata
+	readProgress int
 }
 
 // NewNoteDetailModel creates a new note detail model
-func NewNoteDetailModel(apiClient *client.APIClient, authState *client.AuthState) NoteDetailModel {
+func NewNoteDetailModel(apiClient *client.APIClient, authState *client.AuthState, positions *ReadingPositions) NoteDetailModel {
 	addTagInput := components.NewTextInput()
 	addTagInput.SetPlaceholder("Type tag name or select from list...")
 	addTagInput.SetWidth(40)
 
-	return NoteDetailModel{
+	findInput := components.NewTextInput()
+	findInput.SetPlaceholder("Find in note...")
+	findInput.SetWidth(40)
+
+	m := NoteDetailModel{
 		client:               apiClient,
 		authState:            authState,
 		loading:              true,
@@ -88,11 +144,20 @@ func NewNoteDetailModel(apiClient *client.APIClient, authState *client.AuthState
 		addTagInput:          addTagInput,
 		addTagFilter:         "",
 		selectedAvailableIndex: -1,
+		selectedLinkIndex:    -1,
+		contentViewport:      viewport.New(78, 10),
+		markdown:             components.NewMarkdownRenderer(78),
+		findInput:            findInput,
+		positions:            positions,
 	}
+	m.resizeContentViewport()
+	return m
 }
 
 // SetNoteID sets the note ID to fetch
 func (m NoteDetailModel) SetNoteID(id uuid.UUID) (NoteDetailModel, tea.Cmd) {
+	m.SaveReadingPosition()
+
 	// Clear previous state
 	m.note = nil
 	m.noteID = id
@@ -115,6 +180,32 @@ func (m NoteDetailModel) SetNoteID(id uuid.UUID) (NoteDetailModel, tea.Cmd) {
 	m.addTagFilter = ""
 	m.filteredAvailableTags = nil
 	m.selectedAvailableIndex = -1
+	// Reset revision history state
+	m.revisions = nil
+	m.revisionsErr = nil
+	m.revisionsLoaded = false
+	m.selectedRevisionIndex = 0
+	m.pendingRestore = 0
+	// Reset related notes state
+	m.related = nil
+	m.relatedErr = nil
+	m.relatedLoaded = false
+	m.selectedRelatedIndex = 0
+	// Reset note statistics state
+	m.stats = nil
+	m.statsErr = nil
+	m.statsLoaded = false
+	m.contentViewport.SetContent("")
+	m.contentViewport.GotoTop()
+	m.contentLinks = nil
+	m.selectedLinkIndex = -1
+	m.linkStatus = ""
+	m.pendingAnchor = nil
+	m.yankPending = false
+	m.clearFind()
+	m.summary = nil
+	m.summarizing = false
+	m.metadataOpen = false
 	return m, m.fetchNoteCmd()
 }
 
@@ -130,10 +221,16 @@ func (m NoteDetailModel) Init() tea.Cmd {
 func (m NoteDetailModel) fetchNoteCmd() tea.Cmd {
 	noteID := m.noteID
 	return func() tea.Msg {
-		note, err := m.client.GetNote(noteID)
+		note, err := m.client.GetNoteExpanded(noteID)
 		if err != nil {
 			return NoteDetailErrMsg{Err: err}
 		}
+		// Without a passphrase the note stays locked and shows a placeholder
+		if note.Encrypted && m.client.HasPassphrase() {
+			if err := m.client.DecryptNote(note); err != nil {
+				return NoteDetailErrMsg{Err: err}
+			}
+		}
 		return NoteDetailFetchedMsg{Note: note}
 	}
 }
@@ -174,6 +271,30 @@ func (m NoteDetailModel) fetchBacklinksCmd() tea.Cmd {
 	}
 }
 
+// fetchRevisionsCmd returns a command that fetches the note revision history
+func (m NoteDetailModel) fetchRevisionsCmd() tea.Cmd {
+	noteID := m.noteID
+	return func() tea.Msg {
+		revisions, err := m.client.GetNoteRevisions(noteID)
+		if err != nil {
+			return NoteRevisionsErrMsg{Err: err}
+		}
+		return NoteRevisionsMsg{Revisions: revisions}
+	}
+}
+
+// restoreRevisionCmd returns a command that restores the note to a revision
+func (m NoteDetailModel) restoreRevisionCmd(revision int) tea.Cmd {
+	noteID := m.noteID
+	return func() tea.Msg {
+		note, err := m.client.RestoreNoteRevision(noteID, revision)
+		if err != nil {
+			return NoteRevisionsErrMsg{Err: err}
+		}
+		return NoteRevisionRestoredMsg{Note: note}
+	}
+}
+
 // fetchTagsCmdWithID returns a command that fetches note tags with a specific ID
 func (m NoteDetailModel) fetchTagsCmdWithID(noteID uuid.UUID) tea.Cmd {
 	return func() tea.Msg {
@@ -196,6 +317,42 @@ func (m NoteDetailModel) fetchLinksCmdWithID(noteID uuid.UUID) tea.Cmd {
 	}
 }
 
+// fetchRelatedCmd returns a command that fetches notes related to the note
+func (m NoteDetailModel) fetchRelatedCmd() tea.Cmd {
+	noteID := m.noteID
+	return func() tea.Msg {
+		related, err := m.client.GetRelatedNotes(noteID, 10)
+		if err != nil {
+			return NoteRelatedErrMsg{Err: err}
+		}
+		return NoteRelatedMsg{Related: related}
+	}
+}
+
+// fetchStatsCmd returns a command that fetches the view, edit and link statistics of the note
+func (m NoteDetailModel) fetchStatsCmd() tea.Cmd {
+	noteID := m.noteID
+	return func() tea.Msg {
+		stats, err := m.client.GetNoteStats(noteID)
+		if err != nil {
+			return NoteStatsErrMsg{Err: err}
+		}
+		return NoteStatsMsg{Stats: stats}
+	}
+}
+
+// summarizeCmd returns a command that generates a summary of the note
+func (m NoteDetailModel) summarizeCmd() tea.Cmd {
+	noteID := m.noteID
+	return func() tea.Msg {
+		summary, err := m.client.SummarizeNote(noteID)
+		if err != nil {
+			return NoteSummaryErrMsg{NoteID: noteID, Err: err}
+		}
+		return NoteSummarizedMsg{NoteID: noteID, Summary: summary}
+	}
+}
+
 // fetchBacklinksCmdWithID returns a command that fetches note backlinks with a specific ID
 func (m NoteDetailModel) fetchBacklinksCmdWithID(noteID uuid.UUID) tea.Cmd {
 	return func() tea.Msg {
@@ -231,14 +388,15 @@ func (m NoteDetailModel) addTagToNoteCmd(tagID uuid.UUID) tea.Cmd {
 }
 
 // removeTagFromNoteCmd returns a command that removes a tag from the note
-func (m NoteDetailModel) removeTagFromNoteCmd(tagID uuid.UUID) tea.Cmd {
+func (m NoteDetailModel) removeTagFromNoteCmd(tag *model.Tag) tea.Cmd {
 	// Capture noteID to prevent closure issues with model copying
 	noteID := m.noteID
+	tagID, tagName := tag.ID, tag.Name
 	return func() tea.Msg {
 		if err := m.client.RemoveTagFromNote(noteID, tagID); err != nil {
 			return NoteDetailTagsErrMsg{Err: err}
 		}
-		return NoteTagRemovedMsg{TagID: tagID}
+		return NoteTagRemovedMsg{NoteID: noteID, TagID: tagID, TagName: tagName}
 	}
 }
 
@@ -253,9 +411,16 @@ func (m NoteDetailModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.confirmDialog.Update(msg)
 			// Check if user confirmed
 			if m.confirmDialog.IsYesSelected() {
+				if m.pendingRestore > 0 {
+					revision := m.pendingRestore
+					m.pendingRestore = 0
+					m.showConfirm = false
+					return m, m.restoreRevisionCmd(revision)
+				}
 				return m, m.deleteNoteCmd()
 			} else if m.confirmDialog.IsNoSelected() || msg.String() == "esc" {
 				m.showConfirm = false
+				m.pendingRestore = 0
 				return m, nil
 			}
 			return m, nil
@@ -335,20 +500,108 @@ func (m NoteDetailModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, cmd
 		}
 
+		// Handle find input
+		if m.findInput.Focused() {
+			switch msg.String() {
+			case "enter":
+				m.findInput.Blur()
+				m.setFindQuery(m.findInput.Value())
+				return m, nil
+			case "esc":
+				m.clearFind()
+				return m, nil
+			}
+			cmd := m.findInput.Update(msg)
+			return m, cmd
+		}
+
+		// The key after y picks what to copy, any other key cancels the copy
+		if m.yankPending {
+			m.yankPending = false
+			if field, ok := yankField(msg.String()); ok && m.note != nil {
+				return m, copyNoteCmd(m.client, m.note, field)
+			}
+			return m, nil
+		}
+
 		switch msg.String() {
 		case "q", "ctrl+c":
 			return m, tea.Quit
+		case "y":
+			// Copy a part of the note, picked by the next key
+			if m.note != nil {
+				m.yankPending = true
+				return m, nil
+			}
 		case "?":
 			return m, func() tea.Msg {
 				return ShowHelpMsg{}
 			}
 		case "esc":
+			// Clear an active find before leaving the note
+			if m.findQuery != "" {
+				m.clearFind()
+				return m, nil
+			}
 			// Go back to dashboard
 			return m, func() tea.Msg {
 				return ShowDashboardMsg{}
 			}
+		case "/":
+			// Find in note (content tab only)
+			if m.currentTab == NoteContentTab && !m.isLocked() {
+				m.findInput.SetValue(m.findQuery)
+				m.findInput.Focus()
+				return m, nil
+			}
+		case "n":
+			if m.currentTab == NoteContentTab && len(m.findMatches) > 0 {
+				m.selectMatch(m.findIndex + 1)
+				return m, nil
+			}
+		case "N":
+			if m.currentTab == NoteContentTab && len(m.findMatches) > 0 {
+				m.selectMatch(m.findIndex - 1)
+				return m, nil
+			}
+		case "M":
+			// Open or collapse the metadata shown above the content
+			if m.note != nil && len(m.note.Metadata.Keys()) > 0 {
+				m.metadataOpen = !m.metadataOpen
+				m.currentTab = NoteContentTab
+				m.refreshContentViewport()
+			}
+			return m, nil
+		case "S":
+			// Summarize the note, the summary is shown above the content
+			if m.note == nil || m.summarizing {
+				return m, nil
+			}
+			if m.note.Encrypted {
+				m.linkStatus = "Encrypted notes can't be summarized"
+				m.currentTab = NoteContentTab
+				return m, nil
+			}
+			m.summarizing = true
+			m.linkStatus = ""
+			m.currentTab = NoteContentTab
+			m.refreshContentViewport()
+			m.contentViewport.GotoTop()
+			return m, m.summarizeCmd()
+		case "G":
+			// Open the local graph centered on this note
+			if m.note != nil {
+				noteID := m.note.ID
+				return m, func() tea.Msg {
+					return ShowLocalGraphMsg{NoteID: noteID}
+				}
+			}
 		case "e":
 			// Edit note - Phase C
+			if m.isLocked() {
+				m.linkStatus = "Encrypted note: set KG_CLI_PASSPHRASE to edit it"
+				return m, nil
+			}
 			return m, func() tea.Msg {
 				return EditNoteMsg{NoteID: m.noteID}
 			}
@@ -356,9 +609,9 @@ func (m NoteDetailModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Delete behavior depends on current tab
 			if m.currentTab == NoteTagsTab && m.selectedTagIndex >= 0 && len(m.tags) > 0 {
 				// Remove the selected tag
-				tagID := m.tags[m.selectedTagIndex].ID
+				tag := m.tags[m.selectedTagIndex]
 				m.selectedTagIndex = -1
-				return m, m.removeTagFromNoteCmd(tagID)
+				return m, m.removeTagFromNoteCmd(tag)
 			} else {
 				// Delete note - show confirmation
 				m.showConfirm = true
@@ -367,6 +620,17 @@ func (m NoteDetailModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.confirmDialog.Focus()
 				return m, nil
 			}
+		case "r":
+			// Restore selected revision - only works in history tab
+			if m.currentTab == NoteHistoryTab && m.selectedRevisionIndex < len(m.revisions) {
+				rev := m.revisions[m.selectedRevisionIndex]
+				m.pendingRestore = rev.RevisionNumber
+				m.showConfirm = true
+				m.confirmDialog = components.NewConfirmDialog(fmt.Sprintf("Restore revision #%d?", rev.RevisionNumber))
+				m.confirmDialog.SetSubtext("The current version will be saved to history.")
+				m.confirmDialog.Focus()
+				return m, nil
+			}
 		case "a":
 			// Add tag - only works in tags tab
 			if m.currentTab == NoteTagsTab {
@@ -380,9 +644,42 @@ func (m NoteDetailModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				return m, nil
 			}
+		case "enter":
+			// Open the selected related note
+			if m.currentTab == NoteRelatedTab && m.selectedRelatedIndex < len(m.related) {
+				noteID := m.related[m.selectedRelatedIndex].Note.ID
+				return m, func() tea.Msg {
+					return OpenNoteMsg{NoteID: noteID}
+				}
+			}
+			// Open the selected wiki link (content tab only)
+			if m.currentTab == NoteContentTab && m.selectedLinkIndex >= 0 && m.selectedLinkIndex < len(m.contentLinks) {
+				link := m.contentLinks[m.selectedLinkIndex]
+				// A heading or block of this note is scrolled to
+				if link.Title == "" || (m.note != nil && link.Names(m.note.Title)) {
+					m.scrollToAnchor(link)
+					return m, nil
+				}
+				return m, m.openLinkCmd(link)
+			}
+		case "c":
+			// Create the note the selected wiki link points to (content tab only)
+			if m.currentTab == NoteContentTab && m.selectedLinkIndex >= 0 && m.selectedLinkIndex < len(m.contentLinks) {
+				if link := m.contentLinks[m.selectedLinkIndex]; link.Title != "" {
+					return m, m.createLinkedNoteCmd(link)
+				}
+			}
 		case "tab", "l", "right":
+			// In the content tab, Tab walks through wiki links before moving to the next tab
+			if msg.String() == "tab" && m.currentTab == NoteContentTab && m.selectedLinkIndex < len(m.contentLinks)-1 {
+				m.selectLink(m.selectedLinkIndex + 1)
+				return m, nil
+			}
+			if m.currentTab == NoteContentTab {
+				m.selectLink(-1)
+			}
 			// Next tab
-			m.currentTab = (m.currentTab + 1) % 4
+			m.currentTab = (m.currentTab + 1) % noteDetailTabCount
 			// Reset tag selection when switching tabs
 			if m.currentTab != NoteTagsTab {
 				m.selectedTagIndex = -1
@@ -401,33 +698,126 @@ func (m NoteDetailModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if len(m.backlinks) == 0 && !m.loading {
 					cmds = append(cmds, m.fetchBacklinksCmd())
 				}
+			case NoteRelatedTab:
+				if !m.relatedLoaded && !m.loading {
+					cmds = append(cmds, m.fetchRelatedCmd())
+				}
+			case NoteHistoryTab:
+				if !m.revisionsLoaded && !m.loading {
+					cmds = append(cmds, m.fetchRevisionsCmd())
+				}
+			case NoteStatsTab:
+				if !m.statsLoaded && !m.loading {
+					cmds = append(cmds, m.fetchStatsCmd())
+				}
 			}
 		case "shift+tab", "h", "left":
+			// In the content tab, Shift+Tab walks back through wiki links first
+			if msg.String() == "shift+tab" && m.currentTab == NoteContentTab && m.selectedLinkIndex >= 0 {
+				m.selectLink(m.selectedLinkIndex - 1)
+				return m, nil
+			}
 			// Previous tab
-			m.currentTab = (m.currentTab - 1 + 4) % 4
+			m.currentTab = (m.currentTab - 1 + noteDetailTabCount) % noteDetailTabCount
 			// Reset tag selection when switching tabs
 			if m.currentTab != NoteTagsTab {
 				m.selectedTagIndex = -1
 			}
+			if m.currentTab == NoteHistoryTab && !m.revisionsLoaded && !m.loading {
+				cmds = append(cmds, m.fetchRevisionsCmd())
+			}
+			if m.currentTab == NoteRelatedTab && !m.relatedLoaded && !m.loading {
+				cmds = append(cmds, m.fetchRelatedCmd())
+			}
+			if m.currentTab == NoteStatsTab && !m.statsLoaded && !m.loading {
+				cmds = append(cmds, m.fetchStatsCmd())
+			}
+		case "pgup":
+			if m.currentTab == NoteContentTab {
+				m.contentViewport.PageUp()
+			}
+		case "pgdown":
+			if m.currentTab == NoteContentTab {
+				m.contentViewport.PageDown()
+			}
+		case "ctrl+u":
+			if m.currentTab == NoteContentTab {
+				m.contentViewport.HalfPageUp()
+			}
+		case "ctrl+d":
+			if m.currentTab == NoteContentTab {
+				m.contentViewport.HalfPageDown()
+			}
+		case "home":
+			if m.currentTab == NoteContentTab {
+				m.contentViewport.GotoTop()
+			}
+		case "end":
+			if m.currentTab == NoteContentTab {
+				m.contentViewport.GotoBottom()
+			}
 		case "up", "k":
+			// Scroll content (only in content tab)
+			if m.currentTab == NoteContentTab {
+				m.contentViewport.ScrollUp(1)
+			}
 			// Navigate up in tags list (only in tags tab)
 			if m.currentTab == NoteTagsTab && m.selectedTagIndex > 0 {
 				m.selectedTagIndex--
 			} else if m.currentTab == NoteTagsTab && m.selectedTagIndex == -1 && len(m.tags) > 0 {
 				m.selectedTagIndex = len(m.tags) - 1
 			}
+			// Navigate up in revisions list (only in history tab)
+			if m.currentTab == NoteHistoryTab && m.selectedRevisionIndex > 0 {
+				m.selectedRevisionIndex--
+			}
+			// Navigate up in related notes (only in related tab)
+			if m.currentTab == NoteRelatedTab && m.selectedRelatedIndex > 0 {
+				m.selectedRelatedIndex--
+			}
 		case "down", "j":
+			// Scroll content (only in content tab)
+			if m.currentTab == NoteContentTab {
+				m.contentViewport.ScrollDown(1)
+			}
 			// Navigate down in tags list (only in tags tab)
 			if m.currentTab == NoteTagsTab && m.selectedTagIndex < len(m.tags)-1 {
 				m.selectedTagIndex++
 			} else if m.currentTab == NoteTagsTab && m.selectedTagIndex == -1 && len(m.tags) > 0 {
 				m.selectedTagIndex = 0
 			}
+			// Navigate down in revisions list (only in history tab)
+			if m.currentTab == NoteHistoryTab && m.selectedRevisionIndex < len(m.revisions)-1 {
+				m.selectedRevisionIndex++
+			}
+			// Navigate down in related notes (only in related tab)
+			if m.currentTab == NoteRelatedTab && m.selectedRelatedIndex < len(m.related)-1 {
+				m.selectedRelatedIndex++
+			}
 		}
 
 	case NoteDetailFetchedMsg:
+		// A refetch of the open note keeps its scroll position
+		refetched := m.note != nil && m.note.ID == msg.Note.ID
 		m.note = msg.Note
+		m.summary = msg.Note.Metadata.Summary()
+		if progress := msg.Note.Metadata.ReadProgress(); progress != nil {
+			m.readProgress = progress.Percent
+		} else {
+			m.readProgress = 0
+		}
 		m.loading = false
+		m.selectedLinkIndex = -1
+		m.refreshContentViewport()
+		if !refetched {
+			m.contentViewport.GotoTop()
+			if m.pendingAnchor != nil {
+				m.scrollToAnchor(*m.pendingAnchor)
+				m.pendingAnchor = nil
+			} else if m.positions != nil {
+				m.contentViewport.SetYOffset(m.positions.Get(msg.Note.ID))
+			}
+		}
 		// FIX: Use note ID from fetched note to ensure it's valid
 		// Capture in local variable to avoid closure issues
 		noteID := msg.Note.ID
@@ -456,6 +846,87 @@ func (m NoteDetailModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.backlinksErr = nil // Clear error on success
 		return m, nil
 
+	case NoteRevisionsMsg:
+		m.revisions = msg.Revisions
+		m.revisionsErr = nil
+		m.revisionsLoaded = true
+		if m.selectedRevisionIndex >= len(m.revisions) {
+			m.selectedRevisionIndex = 0
+		}
+		return m, nil
+
+	case NoteRelatedMsg:
+		m.related = msg.Related
+		m.relatedErr = nil
+		m.relatedLoaded = true
+		if m.selectedRelatedIndex >= len(m.related) {
+			m.selectedRelatedIndex = 0
+		}
+		return m, nil
+
+	case NoteRelatedErrMsg:
+		m.relatedErr = msg.Err
+		m.relatedLoaded = true
+
+	case NoteStatsMsg:
+		m.stats = msg.Stats
+		m.statsErr = nil
+		m.statsLoaded = true
+
+	case NoteStatsErrMsg:
+		m.statsErr = msg.Err
+		m.statsLoaded = true
+		return m, nil
+
+	case NoteSummarizedMsg:
+		if msg.NoteID != m.noteID {
+			return m, nil
+		}
+		m.summary = msg.Summary
+		m.summarizing = false
+		m.refreshContentViewport()
+		return m, nil
+
+	case NoteSummaryErrMsg:
+		if msg.NoteID != m.noteID {
+			return m, nil
+		}
+		m.summarizing = false
+		m.linkStatus = fmt.Sprintf("Summarize failed: %v", msg.Err)
+		m.refreshContentViewport()
+		return m, nil
+
+	case NoteRevisionsErrMsg:
+		m.revisionsErr = msg.Err
+		m.revisionsLoaded = true
+		return m, nil
+
+	case NoteRevisionRestoredMsg:
+		// Show restored content and refresh history (restore adds a new revision)
+		m.note = msg.Note
+		m.selectedLinkIndex = -1
+		m.refreshContentViewport()
+		m.selectedRevisionIndex = 0
+		return m, tea.Batch(
+			m.fetchRevisionsCmd(),
+			m.fetchLinksCmd(),
+		)
+
+	case NoteLinkUnresolvedMsg:
+		m.linkStatus = fmt.Sprintf("No note titled %q (c:create it)", msg.Title)
+		return m, nil
+
+	case NoteLinkStubCreatedMsg:
+		m.linkStatus = ""
+		noteID := msg.Note.ID
+		return m, func() tea.Msg {
+			return OpenNoteMsg{NoteID: noteID}
+		}
+
+	case NoteLinkStubErrMsg:
+		m.linkStatus = fmt.Sprintf("Failed to create %q: %v", msg.Title, msg.Err)
+		return m, nil
+
 	case NoteAvailableTagsMsg:
 		m.availableTags = msg.Tags
 		m.availableTagsLoading = false
@@ -506,12 +977,58 @@ func (m NoteDetailModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 		m.addTagInput.SetWidth(msg.Width - 20)
+		m.findInput.SetWidth(msg.Width - 20)
+		m.resizeContentViewport()
 		return m, nil
 	}
 
+	// Scrolling the content saves how far the note is read
+	if _, ok := msg.(tea.KeyMsg); ok {
+		var cmd tea.Cmd
+		m, cmd = m.trackReadProgress()
+		cmds = append(cmds, cmd)
+	}
+
 	return m, tea.Batch(cmds...)
 }
 
+// readProgressStep is how many percent further, or back, a note is scrolled before its progress is saved again
+const readProgressStep = 10
+
+// trackReadProgress saves the note's read progress once scrolling moved it into another step
+// Only the owner's progress is kept in the note, so notes shared with the user aren't tracked.
+func (m NoteDetailModel) trackReadProgress() (NoteDetailModel, tea.Cmd) {
+	if m.note == nil || m.note.SharedBy != "" || m.loading || m.currentTab != NoteContentTab {
+		return m, nil
+	}
+
+	percent := m.readPercent()
+	if percent/readProgressStep == m.readProgress/readProgressStep {
+		return m, nil
+	}
+	m.readProgress = percent
+
+	noteID := m.note.ID
+	return m, func() tea.Msg {
+		progress, err := m.client.SetReadProgress(noteID, percent)
+		if err != nil {
+			// Not worth interrupting the reading for; the next step tries again
+			return nil
+		}
+		return ReadProgressSavedMsg{NoteID: noteID, Progress: progress}
+	}
+}
+
+// readPercent returns how much of the content has been scrolled into view, in percent
+func (m NoteDetailModel) readPercent() int {
+	total := m.contentViewport.TotalLineCount()
+	if total == 0 {
+		return 0
+	}
+	seen := min(total, m.contentViewport.YOffset+m.contentViewport.Height)
+	return seen * 100 / total
+}
+
 // updateFilteredAvailableTags updates the filtered list of available tags
 func (m *NoteDetailModel) updateFilteredAvailableTags() {
 	if m.addTagFilter == "" {
@@ -560,15 +1077,225 @@ func (m *NoteDetailModel) updateFilteredAvailableTags() {
 	}
 }
 
+// resizeContentViewport fits the content viewport to the window and re-renders the note
+func (m *NoteDetailModel) resizeContentViewport() {
+	// Reserve lines for the app header/status bar and the note title, metadata, tabs and hints
+	const reservedLines = 11
+
+	width := m.width - 2
+	if width < 20 {
+		width = 20
+	}
+	height := m.height - reservedLines
+	if height < 3 {
+		height = 3
+	}
+
+	m.contentViewport.Width = width
+	m.contentViewport.Height = height
+	m.markdown.SetWidth(width)
+	m.refreshContentViewport()
+}
+
+// refreshContentViewport renders the note markdown into the content viewport
+func (m *NoteDetailModel) refreshContentViewport() {
+	if m.note == nil {
+		return
+	}
+	if m.note.Content == "" {
+		m.contentLinks = nil
+		m.contentViewport.SetContent("(no content)")
+		return
+	}
+	if m.isLocked() {
+		m.contentLinks = nil
+		m.contentViewport.SetContent("(encrypted - set KG_CLI_PASSPHRASE before starting the TUI to read this note)")
+		return
+	}
+	m.markdown.SetSelectedLink(m.selectedLinkIndex)
+	m.contentLinks = m.markdown.Links(m.displayContent())
+	rendered := m.renderHeader() + m.markdown.Render(m.displayContent())
+
+	// Matches are found in the rendered text, so they follow wrapping and resizes
+	m.findMatches = components.FindMatches(rendered, m.findQuery)
+	if m.findIndex >= len(m.findMatches) {
+		m.findIndex = 0
+	}
+	m.contentViewport.SetContent(components.HighlightMatches(rendered, m.findMatches, m.findIndex))
+}
+
+// setFindQuery finds query in the note and jumps to the first match below the top of the view
+func (m *NoteDetailModel) setFindQuery(query string) {
+	m.findQuery = query
+	m.findIndex = 0
+	m.refreshContentViewport()
+
+	for i, match := range m.findMatches {
+		if match.Line >= m.contentViewport.YOffset {
+			m.selectMatch(i)
+			return
+		}
+	}
+	m.selectMatch(0)
+}
+
+// selectMatch marks the find match at index, wrapping around, and scrolls it into view
+func (m *NoteDetailModel) selectMatch(index int) {
+	if len(m.findMatches) == 0 {
+		return
+	}
+	m.findIndex = (index + len(m.findMatches)) % len(m.findMatches)
+	m.refreshContentViewport()
+
+	line := m.findMatches[m.findIndex].Line
+	if line < m.contentViewport.YOffset || line >= m.contentViewport.YOffset+m.contentViewport.Height {
+		// Keep some context above the match
+		m.contentViewport.SetYOffset(line - m.contentViewport.Height/3)
+	}
+}
+
+// clearFind closes the find input and removes the match highlights
+func (m *NoteDetailModel) clearFind() {
+	m.findInput.Blur()
+	m.findInput.SetValue("")
+	m.findQuery = ""
+	m.findMatches = nil
+	m.findIndex = 0
+	m.refreshContentViewport()
+}
+
+// IsFindActive returns whether a find is being typed or its matches are shown
+// The main TUI leaves "/", n and Esc to the note while it is.
+func (m NoteDetailModel) IsFindActive() bool {
+	return m.currentTab == NoteContentTab && (m.findInput.Focused() || m.findQuery != "")
+}
+
+// isLocked returns true if the note is encrypted and could not be decrypted
+func (m NoteDetailModel) isLocked() bool {
+	return m.note != nil && m.note.Encrypted && util.IsEncryptedContent(m.note.Content)
+}
+
+// selectLink highlights the wiki link at index and scrolls it into view
+func (m *NoteDetailModel) selectLink(index int) {
+	m.linkStatus = ""
+	if index < 0 || index >= len(m.contentLinks) {
+		index = -1
+	}
+	m.selectedLinkIndex = index
+	m.refreshContentViewport()
+
+	if index < 0 {
+		return
+	}
+	line := m.contentLinks[index].Line
+	if line < m.contentViewport.YOffset {
+		m.contentViewport.SetYOffset(line)
+	} else if line >= m.contentViewport.YOffset+m.contentViewport.Height {
+		m.contentViewport.SetYOffset(line - m.contentViewport.Height + 1)
+	}
+}
+
+// SetAnchor scrolls the note being opened to a heading or block once it is loaded
+func (m NoteDetailModel) SetAnchor(heading, block string) NoteDetailModel {
+	m.pendingAnchor = &components.WikiLink{Heading: heading, Block: block}
+	return m
+}
+
+// displayContent returns the note content shown, with its ![[...]] embeds expanded when the server did so
+// Edits always start from the note's own content.
+func (m *NoteDetailModel) displayContent() string {
+	if m.note.ExpandedContent != nil {
+		return *m.note.ExpandedContent
+	}
+	return m.note.Content
+}
+
+// scrollToAnchor scrolls the content to the heading or block a link points to, at the top of the view
+func (m *NoteDetailModel) scrollToAnchor(link components.WikiLink) {
+	if (link.Heading == "" && link.Block == "") || m.note == nil || m.isLocked() {
+		return
+	}
+
+	line, ok := m.markdown.AnchorLine(m.displayContent(), link.Heading, link.Block)
+	if !ok {
+		if link.Block != "" {
+			m.linkStatus = fmt.Sprintf("No block ^%s in this note", link.Block)
+		} else {
+			m.linkStatus = fmt.Sprintf("No heading %q in this note", link.Heading)
+		}
+		return
+	}
+	// The summary and metadata are rendered above the content
+	line += strings.Count(m.renderHeader(), "\n")
+	m.contentViewport.SetYOffset(line)
+}
+
+// openLinkCmd returns a command that opens the note a wiki link points to, at its heading or block
+func (m NoteDetailModel) openLinkCmd(target components.WikiLink) tea.Cmd {
+	title := target.Title
+
+	// Resolved links are already loaded for the Links tab
+	for _, link := range m.links {
+		if link.TargetNote != nil && target.Names(link.TargetNote.Title) {
+			noteID := link.TargetNote.ID
+			return func() tea.Msg {
+				return OpenNoteMsg{NoteID: noteID, Heading: target.Heading, Block: target.Block}
+			}
+		}
+	}
+
+	// Fall back to a title search (e.g. the target was created after this note)
+	return func() tea.Msg {
+		notes, _, err := m.client.ListNotes(model.NoteFilter{Page: 1, Limit: 20, Search: title})
+		if err != nil {
+			return NoteLinkUnresolvedMsg{Title: title}
+		}
+		// Pick the note the server would link to among the matches
+		titles := util.NewTitleIndex()
+		for i, note := range notes {
+			titles.Add(note.Title, i)
+		}
+		if i, ok := titles.Find(title); ok {
+			return OpenNoteMsg{NoteID: notes[i].ID, Heading: target.Heading, Block: target.Block}
+		}
+		return NoteLinkUnresolvedMsg{Title: title}
+	}
+}
+
+// createLinkedNoteCmd returns a command that creates an empty note for an unresolved wiki link
+// The server links the current note to it on creation. Already resolved links are just opened.
+func (m NoteDetailModel) createLinkedNoteCmd(target components.WikiLink) tea.Cmd {
+	title := target.Title
+	for _, link := range m.links {
+		if link.TargetNote != nil && target.Names(link.TargetNote.Title) {
+			return m.openLinkCmd(target)
+		}
+	}
+
+	return func() tea.Msg {
+		note, err := m.client.CreateNote(&model.CreateNoteRequest{Title: title})
+		if err != nil {
+			return NoteLinkStubErrMsg{Title: title, Err: err}
+		}
+		return NoteLinkStubCreatedMsg{Note: note}
+	}
+}
+
 // containsIgnoreCase checks if a string contains a substring (case-insensitive)
 func containsIgnoreCase(s, substr string) bool {
 	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
 }
 
-// IsInputFocused returns whether the add tag form is focused
-// This allows the main TUI to skip global key handlers when typing
+// IsInputFocused returns whether the add tag form or the find input is focused
+// This allows the main TUI to skip global key handlers when typing.
+// A pending copy counts too, since the key after y picks what to copy.
 func (m NoteDetailModel) IsInputFocused() bool {
-	return m.showAddTagForm && m.addTagInput.Focused()
+	return (m.showAddTagForm && m.addTagInput.Focused()) || m.findInput.Focused() || m.yankPending
+}
+
+// IsYankPending returns whether y was pressed and Esc should cancel the copy
+func (m NoteDetailModel) IsYankPending() bool {
+	return m.yankPending
 }
 
 // GetCurrentTab returns the current active tab
@@ -586,13 +1313,21 @@ func (m NoteDetailModel) deleteNoteCmd() tea.Cmd {
 			return NoteDetailErrMsg{Err: fmt.Errorf("no note loaded to delete")}
 		}
 	}
-	noteID := m.note.ID
+	noteID, title := m.note.ID, m.note.Title
 	return func() tea.Msg {
 		if err := m.client.DeleteNote(noteID); err != nil {
 			return NoteDetailErrMsg{Err: err}
 		}
-		return NoteDeletedMsg{}
+		return NoteDeletedMsg{NoteID: noteID, Title: title}
+	}
+}
+
+// SaveReadingPosition remembers how far the open note is scrolled, for the next time it is opened
+func (m NoteDetailModel) SaveReadingPosition() {
+	if m.positions == nil || m.note == nil {
+		return
 	}
+	_ = m.positions.Set(m.note.ID, m.contentViewport.YOffset)
 }
 
 // GetNote returns the current note (for accessing from parent models)
@@ -600,6 +1335,15 @@ func (m NoteDetailModel) GetNote() *model.Note {
 	return m.note
 }
 
+// ReloadStats fetches the note's stats again if they were already shown, e.g. after a focus session
+func (m NoteDetailModel) ReloadStats() (NoteDetailModel, tea.Cmd) {
+	if !m.statsLoaded {
+		return m, nil
+	}
+	m.statsLoaded = false
+	return m, m.fetchStatsCmd()
+}
+
 // View renders the note detail view
 func (m NoteDetailModel) View() string {
 	if m.showConfirm {
@@ -627,7 +1371,7 @@ func (m NoteDetailModel) View() string {
 // renderLoading renders the loading state
 func (m NoteDetailModel) renderLoading() string {
 	style := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#89b4fa")). // Blue
+		Foreground(theme().Primary).
 		Bold(true)
 
 	return style.Render("Loading note...")
@@ -636,7 +1380,7 @@ func (m NoteDetailModel) renderLoading() string {
 // renderError renders the error state
 func (m NoteDetailModel) renderError() string {
 	errorStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#f38ba8")). // Red
+		Foreground(theme().Error).
 		Bold(true)
 
 	return errorStyle.Render(fmt.Sprintf("Error: %v", m.err))
@@ -645,19 +1389,19 @@ func (m NoteDetailModel) renderError() string {
 // renderAddTagForm renders the add tag form
 func (m NoteDetailModel) renderAddTagForm() string {
 	formStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#89b4fa")). // Blue
+		Foreground(theme().Primary).
 		Bold(true)
 
 	hintStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#6c7086")). // Gray
+		Foreground(theme().Muted).
 		Faint(true)
 
 	tagStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#cdd6f4")) // Light text
+		Foreground(theme().Foreground)
 
 	selectedStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#1e1e2e")). // Dark
-		Background(lipgloss.Color("#89b4fa")). // Blue
+		Foreground(theme().Background).
+		Background(theme().Primary).
 		Bold(true)
 
 	var content string
@@ -668,17 +1412,17 @@ func (m NoteDetailModel) renderAddTagForm() string {
 	// Show available tags
 	if m.availableTagsLoading {
 		loadingStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#6c7086")). // Gray
+			Foreground(theme().Muted).
 			Faint(true)
 		content += loadingStyle.Render("Loading available tags...")
 	} else if m.availableTagsErr != nil {
 		errorStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#f38ba8")). // Red
+			Foreground(theme().Error).
 			Faint(true)
 		content += errorStyle.Render(fmt.Sprintf("Error: %v", m.availableTagsErr))
 	} else if len(m.filteredAvailableTags) == 0 {
 		mutedStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#6c7086")). // Gray
+			Foreground(theme().Muted).
 			Faint(true)
 		if m.addTagFilter == "" {
 			content += mutedStyle.Render("(No more tags available to add)")
@@ -711,39 +1455,39 @@ func (m NoteDetailModel) renderContent() string {
 
 	// Define styles
 	titleStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#fab387")). // Orange
+		Foreground(theme().Secondary).
 		Bold(true).
 		MarginBottom(1)
 
 	metaStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#6c7086")). // Gray
+		Foreground(theme().Muted).
 		Faint(true).
 		MarginBottom(1)
 
 	tabStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#89b4fa")). // Blue
+		Foreground(theme().Primary).
 		Bold(true)
 
 	tabActiveStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#1e1e2e")). // Dark
-		Background(lipgloss.Color("#89b4fa")). // Blue
+		Foreground(theme().Background).
+		Background(theme().Primary).
 		Bold(true).
 		Padding(0, 1)
 
 	contentStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#cdd6f4")). // Light text
+		Foreground(theme().Foreground).
 		MarginTop(1)
 
 	var content string
 
-	// Title and metadata
-	content += titleStyle.Render(m.note.Title)
+	// Title and metadata, kept to one line each as the viewport height reserves for them
+	content += titleStyle.Render(truncateText(m.note.Title, m.contentViewport.Width))
 	content += "\n"
-	content += metaStyle.Render(m.renderMetadata())
+	content += metaStyle.Render(truncateText(m.renderMetadata(), m.contentViewport.Width))
 	content += "\n"
 
 	// Tabs
-	tabs := []NoteDetailTab{NoteContentTab, NoteTagsTab, NoteLinksTab, NoteBacklinksTab}
+	tabs := []NoteDetailTab{NoteContentTab, NoteTagsTab, NoteLinksTab, NoteBacklinksTab, NoteRelatedTab, NoteHistoryTab, NoteStatsTab}
 	var tabViews []string
 	for _, tab := range tabs {
 		if tab == m.currentTab {
@@ -760,21 +1504,126 @@ func (m NoteDetailModel) renderContent() string {
 
 	// Action hints at bottom - dynamic based on current tab
 	hintStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#6c7086")). // Gray
+		Foreground(theme().Muted).
 		Faint(true).
 		MarginTop(1)
 
 	var hints string
 	if m.currentTab == NoteTagsTab {
 		hints = "a:add tag d:remove tag ↑↓:select TAB:tabs e:edit ESC:back"
+	} else if m.currentTab == NoteHistoryTab {
+		hints = "↑↓:select revision r:restore TAB:tabs e:edit ESC:back"
+	} else if m.currentTab == NoteRelatedTab {
+		hints = "↑↓:select Enter:open note TAB:tabs e:edit ESC:back"
+	} else if m.currentTab == NoteContentTab {
+		hints = "↑↓:scroll PgUp/PgDn:page /:find TAB:tabs e:edit d:delete y:copy S:summarize G:local graph ESC:back"
+		if len(m.contentLinks) > 0 {
+			hints = "↑↓:scroll PgUp/PgDn:page /:find TAB:next link Enter:open link c:create linked note ←→:tabs e:edit d:delete ESC:back"
+		}
+		if m.findInput.Focused() {
+			hints = "Enter:find ESC:cancel"
+		} else if m.findQuery != "" {
+			hints = "n/N:next/prev match /:find again ESC:clear find ↑↓:scroll"
+		}
+		if !m.contentViewport.AtTop() || !m.contentViewport.AtBottom() {
+			hints = fmt.Sprintf("%3.f%% ", m.contentViewport.ScrollPercent()*100) + hints
+		}
 	} else {
-		hints = "TAB:tabs e:edit d:delete ESC:back"
+		hints = "TAB:tabs e:edit d:delete S:summarize G:local graph ESC:back"
+	}
+	if m.currentTab == NoteContentTab && (m.findInput.Focused() || m.findQuery != "") {
+		content += "\n" + m.renderFindBar()
+	}
+	if m.linkStatus != "" && m.currentTab == NoteContentTab {
+		statusStyle := lipgloss.NewStyle().
+			Foreground(theme().Error).
+			MarginTop(1)
+		content += "\n" + statusStyle.Render(m.linkStatus)
+	}
+	if m.yankPending {
+		hints = yankHint
 	}
 	content += "\n" + hintStyle.Render(hints)
 
 	return content
 }
 
+// renderHeader renders what is shown above the note content: the summary and the metadata
+func (m NoteDetailModel) renderHeader() string {
+	return m.renderSummary() + m.renderMetadataKeys()
+}
+
+// renderMetadataKeys renders the note's metadata keys, or a line saying how many there are when collapsed
+func (m NoteDetailModel) renderMetadataKeys() string {
+	keys := m.note.Metadata.Keys()
+	if len(keys) == 0 {
+		return ""
+	}
+
+	labelStyle := lipgloss.NewStyle().
+		Foreground(theme().Special).
+		Bold(true)
+	mutedStyle := lipgloss.NewStyle().
+		Foreground(theme().Subtext)
+
+	if !m.metadataOpen {
+		count := fmt.Sprintf("%d keys", len(keys))
+		if len(keys) == 1 {
+			count = "1 key"
+		}
+		return labelStyle.Render("▸ Metadata") + " " + mutedStyle.Render("("+count+", M:show)") + "\n\n"
+	}
+
+	var b strings.Builder
+	b.WriteString(labelStyle.Render("▾ Metadata") + " " + mutedStyle.Render("(M:hide)") + "\n")
+	valueStyle := mutedStyle.Width(max(m.contentViewport.Width-2, 1))
+	for _, key := range keys {
+		b.WriteString(valueStyle.Render(key+": "+m.note.Metadata.Format(key)) + "\n")
+	}
+	return b.String() + "\n"
+}
+
+// renderSummary renders the generated summary shown above the note content
+// It returns an empty string when the note has no summary.
+func (m NoteDetailModel) renderSummary() string {
+	labelStyle := lipgloss.NewStyle().
+		Foreground(theme().Special).
+		Bold(true)
+
+	summaryStyle := lipgloss.NewStyle().
+		Foreground(theme().Subtext).
+		Italic(true).
+		Width(m.contentViewport.Width - 2)
+
+	switch {
+	case m.summarizing:
+		return labelStyle.Render("TL;DR") + " " + summaryStyle.UnsetWidth().Render("summarizing...") + "\n\n"
+	case m.summary != nil:
+		return labelStyle.Render("TL;DR") + "\n" + summaryStyle.Render(m.summary.Summary) + "\n\n"
+	default:
+		return ""
+	}
+}
+
+// renderFindBar renders the find input, or the match count of the current find
+func (m NoteDetailModel) renderFindBar() string {
+	labelStyle := lipgloss.NewStyle().
+		Foreground(theme().Primary).
+		Bold(true)
+
+	if m.findInput.Focused() {
+		return labelStyle.Render("Find: ") + m.findInput.View()
+	}
+
+	status := fmt.Sprintf("%d/%d", m.findIndex+1, len(m.findMatches))
+	statusStyle := lipgloss.NewStyle().Foreground(theme().Accent)
+	if len(m.findMatches) == 0 {
+		status = "no matches"
+		statusStyle = lipgloss.NewStyle().Foreground(theme().Error)
+	}
+	return labelStyle.Render("Find: ") + fmt.Sprintf("%q ", m.findQuery) + statusStyle.Render(status)
+}
+
 // renderMetadata renders note metadata
 func (m NoteDetailModel) renderMetadata() string {
 	if m.note == nil {
@@ -789,6 +1638,10 @@ func (m NoteDetailModel) renderMetadata() string {
 	var info string
 	info += fmt.Sprintf("Type: %s", noteType)
 	info += fmt.Sprintf(" | Words: %d", m.note.WordCount)
+	info += fmt.Sprintf(" | %d min read", m.note.ReadingTimeMinutes)
+	if m.readProgress > 0 {
+		info += fmt.Sprintf(" (%d%% read)", m.readProgress)
+	}
 	info += fmt.Sprintf(" | Created: %s", formatTimeAgo(m.note.CreatedAt))
 	if !m.note.UpdatedAt.IsZero() && m.note.UpdatedAt != m.note.CreatedAt {
 		info += fmt.Sprintf(" | Updated: %s", formatTimeAgo(m.note.UpdatedAt))
@@ -815,6 +1668,12 @@ func (m NoteDetailModel) renderTabContent() string {
 		return m.renderLinksTab()
 	case NoteBacklinksTab:
 		return m.renderBacklinksTab()
+	case NoteRelatedTab:
+		return m.renderRelatedTab()
+	case NoteHistoryTab:
+		return m.renderHistoryTab()
+	case NoteStatsTab:
+		return m.renderStatsTab()
 	default:
 		return ""
 	}
@@ -822,10 +1681,7 @@ func (m NoteDetailModel) renderTabContent() string {
 
 // renderContentTab renders the note content
 func (m NoteDetailModel) renderContentTab() string {
-	if m.note.Content == "" {
-		return "(no content)"
-	}
-	return m.note.Content
+	return m.contentViewport.View()
 }
 
 // renderTagsTab renders the tags tab with interactive selection
@@ -833,7 +1689,7 @@ func (m NoteDetailModel) renderTagsTab() string {
 	// Show empty state first (takes priority over errors)
 	if len(m.tags) == 0 {
 		mutedStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#6c7086")). // Gray
+			Foreground(theme().Muted).
 			Faint(true)
 		return mutedStyle.Render("(no tags - press 'a' to add)")
 	}
@@ -841,18 +1697,18 @@ func (m NoteDetailModel) renderTagsTab() string {
 	// Show error if tags fetch failed (only shown if we expected data but got error)
 	if m.tagsErr != nil {
 		errorStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#f38ba8")). // Red
+			Foreground(theme().Error).
 			Faint(true)
 		return errorStyle.Render(fmt.Sprintf("Error loading tags: %v", m.tagsErr))
 	}
 
 	// Show tags with selection
 	tagStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#cdd6f4")) // Light text
+		Foreground(theme().Foreground)
 
 	selectedStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#1e1e2e")). // Dark
-		Background(lipgloss.Color("#89b4fa")). // Blue
+		Foreground(theme().Background).
+		Background(theme().Primary).
 		Bold(true)
 
 	var content string
@@ -874,7 +1730,7 @@ func (m NoteDetailModel) renderLinksTab() string {
 	// Show empty state first (takes priority over errors)
 	if len(m.links) == 0 {
 		mutedStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#6c7086")). // Gray
+			Foreground(theme().Muted).
 			Faint(true)
 		return mutedStyle.Render("(no links from this note)")
 	}
@@ -882,7 +1738,7 @@ func (m NoteDetailModel) renderLinksTab() string {
 	// Show error if links fetch failed (only shown if we expected data but got error)
 	if m.linksErr != nil {
 		errorStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#f38ba8")). // Red
+			Foreground(theme().Error).
 			Faint(true)
 		return errorStyle.Render(fmt.Sprintf("Error loading links: %v", m.linksErr))
 	}
@@ -892,6 +1748,7 @@ func (m NoteDetailModel) renderLinksTab() string {
 	for _, link := range m.links {
 		if link.TargetNote != nil {
 			content += fmt.Sprintf("→ %s\n", link.TargetNote.Title)
+			content += m.renderLinkContext(link.LinkContext)
 		}
 	}
 	return content
@@ -902,7 +1759,7 @@ func (m NoteDetailModel) renderBacklinksTab() string {
 	// Show empty state first (takes priority over errors)
 	if len(m.backlinks) == 0 {
 		mutedStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#6c7086")). // Gray
+			Foreground(theme().Muted).
 			Faint(true)
 		return mutedStyle.Render("(no backlinks to this note)")
 	}
@@ -910,7 +1767,7 @@ func (m NoteDetailModel) renderBacklinksTab() string {
 	// Show error if backlinks fetch failed (only shown if we expected data but got error)
 	if m.backlinksErr != nil {
 		errorStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#f38ba8")). // Red
+			Foreground(theme().Error).
 			Faint(true)
 		return errorStyle.Render(fmt.Sprintf("Error loading backlinks: %v", m.backlinksErr))
 	}
@@ -920,11 +1777,265 @@ func (m NoteDetailModel) renderBacklinksTab() string {
 	for _, link := range m.backlinks {
 		if link.SourceNote != nil {
 			content += fmt.Sprintf("← %s\n", link.SourceNote.Title)
+			content += m.renderLinkContext(link.LinkContext)
+		}
+	}
+	return content
+}
+
+// renderLinkContext renders the sentence a link was written in, wrapped under the linked note
+func (m NoteDetailModel) renderLinkContext(context *string) string {
+	if context == nil || *context == "" {
+		return ""
+	}
+
+	contextStyle := lipgloss.NewStyle().
+		Foreground(theme().Muted).
+		Italic(true).
+		PaddingLeft(4).
+		Width(max(m.width-4, 20))
+
+	return contextStyle.Render("“"+*context+"”") + "\n"
+}
+
+// renderRelatedTab renders the related notes with what each shares with this note
+func (m NoteDetailModel) renderRelatedTab() string {
+	mutedStyle := lipgloss.NewStyle().
+		Foreground(theme().Muted).
+		Faint(true)
+
+	if !m.relatedLoaded {
+		return mutedStyle.Render("Finding related notes...")
+	}
+
+	if m.relatedErr != nil {
+		errorStyle := lipgloss.NewStyle().
+			Foreground(theme().Error).
+			Faint(true)
+		return errorStyle.Render(fmt.Sprintf("Error loading related notes: %v", m.relatedErr))
+	}
+
+	if len(m.related) == 0 {
+		return mutedStyle.Render("(no related notes yet - add tags and links to find some)")
+	}
+
+	selectedStyle := lipgloss.NewStyle().
+		Foreground(theme().Background).
+		Background(theme().Primary).
+		Bold(true)
+
+	var content string
+	for i, related := range m.related {
+		title := related.Note.Title
+		if title == "" {
+			title = "(untitled)"
+		}
+
+		// Say why the note is related
+		var reasons []string
+		if related.SharedTags > 0 {
+			reasons = append(reasons, fmt.Sprintf("%d shared tag(s)", related.SharedTags))
+		}
+		if related.SharedLinks > 0 {
+			reasons = append(reasons, fmt.Sprintf("%d shared link(s)", related.SharedLinks))
+		}
+		if related.TextSimilarity > 0 {
+			reasons = append(reasons, fmt.Sprintf("%.0f%% similar text", related.TextSimilarity*100))
+		}
+
+		line := fmt.Sprintf("%3.0f%%  %s", related.Score*100, title)
+		if i == m.selectedRelatedIndex {
+			content += selectedStyle.Render("→ "+line) + "\n"
+		} else {
+			content += "  " + line + "\n"
 		}
+		content += "       " + mutedStyle.Render(strings.Join(reasons, ", ")) + "\n"
 	}
 	return content
 }
 
+// renderStatsTab renders how often the note is viewed and edited, with a sparkline of the last 30 days
+func (m NoteDetailModel) renderStatsTab() string {
+	mutedStyle := lipgloss.NewStyle().
+		Foreground(theme().Muted).
+		Faint(true)
+
+	if !m.statsLoaded {
+		return mutedStyle.Render("Loading stats...")
+	}
+
+	if m.statsErr != nil {
+		errorStyle := lipgloss.NewStyle().
+			Foreground(theme().Error).
+			Faint(true)
+		return errorStyle.Render(fmt.Sprintf("Error loading stats: %v", m.statsErr))
+	}
+
+	labelStyle := lipgloss.NewStyle().
+		Foreground(theme().Muted)
+	valueStyle := lipgloss.NewStyle().
+		Foreground(theme().Foreground).
+		Bold(true)
+	sparkStyle := lipgloss.NewStyle().
+		Foreground(theme().Primary)
+
+	stats := m.stats
+	row := func(label, value string) string {
+		return labelStyle.Render(fmt.Sprintf("%-16s", label)) + valueStyle.Render(value) + "\n"
+	}
+
+	lastViewed := "never"
+	if stats.LastViewedAt != nil {
+		lastViewed = formatTimeAgo(*stats.LastViewedAt)
+	}
+
+	var content string
+	content += row("Views", fmt.Sprintf("%d (last %s)", stats.ViewCount, lastViewed))
+	content += row("Edits", fmt.Sprintf("%d (last %s)", stats.EditCount, formatTimeAgo(stats.LastEditedAt)))
+	content += row("Links out", fmt.Sprintf("%d", stats.OutgoingLinks))
+	content += row("Links in", fmt.Sprintf("%d", stats.IncomingLinks))
+	content += row("Focus time", fmt.Sprintf("%s (%d session(s))", formatFocusTime(stats.FocusSeconds), stats.FocusSessions))
+
+	// Views over the last 30 days, one column per day
+	counts := make([]int, len(stats.DailyViews))
+	total := 0
+	for i, day := range stats.DailyViews {
+		counts[i] = day.Count
+		total += day.Count
+	}
+	content += "\n" + labelStyle.Render(fmt.Sprintf("Views, last %d days: %d", len(counts), total)) + "\n"
+	content += sparkStyle.Render(sparkline(counts)) + "\n"
+
+	// Views per month, so a note that stopped being read stands out
+	maxViews := 0
+	for _, month := range stats.MonthlyViews {
+		if month.Count > maxViews {
+			maxViews = month.Count
+		}
+	}
+	if maxViews > 0 {
+		content += "\n" + labelStyle.Render("Views per month") + "\n"
+		for _, month := range stats.MonthlyViews {
+			content += fmt.Sprintf("%s  %s %d\n", month.Month, sparkStyle.Render(progressBar(month.Count, maxViews, 20)), month.Count)
+		}
+	}
+
+	return strings.TrimSuffix(content, "\n")
+}
+
+// sparkline renders counts as a row of block characters, scaled to the largest count
+func sparkline(counts []int) string {
+	levels := []rune("▁▂▃▄▅▆▇█")
+
+	maxCount := 0
+	for _, count := range counts {
+		if count > maxCount {
+			maxCount = count
+		}
+	}
+
+	var b strings.Builder
+	for _, count := range counts {
+		level := 0
+		if maxCount > 0 && count > 0 {
+			// Any view shows above the baseline
+			level = 1 + (count*(len(levels)-2))/maxCount
+		}
+		b.WriteRune(levels[level])
+	}
+	return b.String()
+}
+
+// renderHistoryTab renders the revision list and a diff of the selected revision
+func (m NoteDetailModel) renderHistoryTab() string {
+	mutedStyle := lipgloss.NewStyle().
+		Foreground(theme().Muted).
+		Faint(true)
+
+	if !m.revisionsLoaded {
+		return mutedStyle.Render("Loading history...")
+	}
+
+	if m.revisionsErr != nil {
+		errorStyle := lipgloss.NewStyle().
+			Foreground(theme().Error).
+			Faint(true)
+		return errorStyle.Render(fmt.Sprintf("Error loading history: %v", m.revisionsErr))
+	}
+
+	if len(m.revisions) == 0 {
+		return mutedStyle.Render("(no previous revisions - edits will appear here)")
+	}
+
+	revStyle := lipgloss.NewStyle().
+		Foreground(theme().Foreground)
+
+	selectedStyle := lipgloss.NewStyle().
+		Foreground(theme().Background).
+		Background(theme().Primary).
+		Bold(true)
+
+	var content string
+	for i, rev := range m.revisions {
+		line := fmt.Sprintf("#%d  %s  (%s)", rev.RevisionNumber, truncateText(rev.Title, 40), formatTimeAgo(rev.CreatedAt))
+		if i == m.selectedRevisionIndex {
+			content += selectedStyle.Render("→ " + line)
+		} else {
+			content += revStyle.Render("  " + line)
+		}
+		content += "\n"
+	}
+
+	// Diff between the selected revision and the current version
+	rev := m.revisions[m.selectedRevisionIndex]
+	oldText := "# " + rev.Title + "\n\n" + rev.Content
+	newText := "# " + m.note.Title + "\n\n" + m.note.Content
+	diff := util.UnifiedDiff(oldText, newText, fmt.Sprintf("revision #%d", rev.RevisionNumber), "current")
+
+	content += "\n"
+	if diff == "" {
+		return content + mutedStyle.Render("(identical to current version)")
+	}
+
+	return content + renderDiff(diff)
+}
+
+// renderDiff colors a unified diff for display
+func renderDiff(diff string) string {
+	addStyle := lipgloss.NewStyle().
+		Foreground(theme().Accent)
+
+	delStyle := lipgloss.NewStyle().
+		Foreground(theme().Error)
+
+	hunkStyle := lipgloss.NewStyle().
+		Foreground(theme().Primary).
+		Faint(true)
+
+	headerStyle := lipgloss.NewStyle().
+		Foreground(theme().Muted).
+		Bold(true)
+
+	lines := strings.Split(strings.TrimSuffix(diff, "\n"), "\n")
+	rendered := make([]string, 0, len(lines))
+	for _, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			rendered = append(rendered, headerStyle.Render(line))
+		case strings.HasPrefix(line, "@@"):
+			rendered = append(rendered, hunkStyle.Render(line))
+		case strings.HasPrefix(line, "+"):
+			rendered = append(rendered, addStyle.Render(line))
+		case strings.HasPrefix(line, "-"):
+			rendered = append(rendered, delStyle.Render(line))
+		default:
+			rendered = append(rendered, line)
+		}
+	}
+
+	return strings.Join(rendered, "\n")
+}
+
 // Message types for note detail
 
 type NoteDetailFetchedMsg struct {
@@ -960,7 +2071,45 @@ type NoteDetailBacklinksErrMsg struct {
 	Err error
 }
 
-type NoteDeletedMsg struct{}
+// Related notes messages
+type NoteRelatedMsg struct {
+	Related []*model.RelatedNote
+}
+
+type NoteRelatedErrMsg struct {
+	Err error
+}
+
+// Note statistics messages
+// ReadProgressSavedMsg reports that the read progress of a note was saved
+type ReadProgressSavedMsg struct {
+	NoteID   uuid.UUID
+	Progress *model.ReadProgress
+}
+
+type NoteStatsMsg struct {
+	Stats *model.NoteStats
+}
+
+type NoteStatsErrMsg struct {
+	Err error
+}
+
+// Summary messages
+type NoteSummarizedMsg struct {
+	NoteID  uuid.UUID
+	Summary *model.NoteSummary
+}
+
+type NoteSummaryErrMsg struct {
+	NoteID uuid.UUID
+	Err    error
+}
+
+type NoteDeletedMsg struct {
+	NoteID uuid.UUID
+	Title  string
+}
 
 // Available tags messages
 type NoteAvailableTagsMsg struct {
@@ -976,10 +2125,44 @@ type NoteTagAddedMsg struct {
 }
 
 type NoteTagRemovedMsg struct {
-	TagID uuid.UUID
+	NoteID  uuid.UUID
+	TagID   uuid.UUID
+	TagName string
+}
+
+// Revision history messages
+type NoteRevisionsMsg struct {
+	Revisions []*model.NoteRevision
+}
+
+type NoteRevisionsErrMsg struct {
+	Err error
+}
+
+type NoteRevisionRestoredMsg struct {
+	Note *model.Note
+}
+
+// Wiki link messages
+type NoteLinkUnresolvedMsg struct {
+	Title string
+}
+
+type NoteLinkStubCreatedMsg struct {
+	Note *model.Note
+}
+
+type NoteLinkStubErrMsg struct {
+	Title string
+	Err   error
 }
 
 // View request messages
 type EditNoteMsg struct {
 	NoteID uuid.UUID
 }
+
+// ShowLocalGraphMsg requests the graph view scoped to a note's neighborhood
+type ShowLocalGraphMsg struct {
+	NoteID uuid.UUID
+}
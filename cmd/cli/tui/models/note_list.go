@@ -2,6 +2,7 @@ package models
 
 import (
 	"fmt"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -23,29 +24,94 @@ type NoteListModel struct {
 	err       error
 	table     components.Table
 	paginator components.Paginator
+	layout    NoteListLayout
+	sort      int // Index into noteListSorts
 	width     int
 	height    int
 	// Filter state (for Phase D)
-	search    string
-	tagFilter *string
+	search     string
+	tagFilter  *string
+	typeFilter model.NoteType // Empty for every type
+	noteTypes  []*model.NoteTypeDefinition // Types offered by the type filter, in key order
+	// Multi-select state: marked notes can be tagged together
+	marked      map[uuid.UUID]bool
+	showTagForm bool
+	tagInput    components.TextInput
+	status      string
+	yankPending bool // y was pressed, the next key picks what to copy
+	// Shows the notes other users share instead of the user's own
+	sharedWithMe bool
+}
+
+// Note list columns
+const (
+	noteListTitleColumn = iota
+	noteListTypeColumn
+	noteListTagsColumn
+	noteListWordsColumn
+	noteListReadColumn
+	noteListUpdatedColumn
+)
+
+var noteListColumns = []components.TableColumn{
+	noteListTitleColumn:   {Title: "Title"},
+	noteListTypeColumn:    {Title: "Type", Width: 8},
+	noteListTagsColumn:    {Title: "Tags", Width: 20},
+	noteListWordsColumn:   {Title: "Words", Width: 7},
+	noteListReadColumn:    {Title: "Read", Width: 8},
+	noteListUpdatedColumn: {Title: "Updated", Width: 14},
+}
+
+// noteListSort is an order of the note list
+type noteListSort struct {
+	field  string
+	order  string
+	column int // Column marked in the header, -1 when it isn't shown
+	label  string
+}
+
+// noteListSorts are the orders "s" cycles through; the first is the API default
+var noteListSorts = []noteListSort{
+	{field: model.SortCreatedAt, order: "desc", column: -1, label: "newest first"},
+	{field: model.SortCreatedAt, order: "asc", column: -1, label: "oldest first"},
+	{field: model.SortUpdatedAt, order: "desc", column: noteListUpdatedColumn, label: "recently updated first"},
+	{field: model.SortUpdatedAt, order: "asc", column: noteListUpdatedColumn, label: "least recently updated first"},
+	{field: model.SortTitle, order: "asc", column: noteListTitleColumn, label: "title A-Z"},
+	{field: model.SortTitle, order: "desc", column: noteListTitleColumn, label: "title Z-A"},
+	{field: model.SortWordCount, order: "desc", column: noteListWordsColumn, label: "longest first"},
+	{field: model.SortWordCount, order: "asc", column: noteListWordsColumn, label: "shortest first"},
 }
 
 // NewNoteListModel creates a new note list model
-func NewNoteListModel(apiClient *client.APIClient, authState *client.AuthState) NoteListModel {
-	table := components.NewTable()
+func NewNoteListModel(apiClient *client.APIClient, authState *client.AuthState, layout NoteListLayout) NoteListModel {
+	table := components.NewTable(noteListColumns)
+	table.SetShowDescription(layout.ShowDescription)
 	paginator := components.NewPaginator()
 
+	limit := apiClient.Settings().PageSize
+	if layout.PageSize > 0 {
+		limit = layout.PageSize
+	}
+
+	tagInput := components.NewTextInput()
+	tagInput.SetPlaceholder("Tag name (prefix with - to remove)")
+	tagInput.SetWidth(40)
+
 	return NoteListModel{
 		client:    apiClient,
 		authState: authState,
 		notes:     []*model.Note{},
 		page:      1,
-		limit:     20,
+		limit:     limit,
 		loading:   true,
 		table:     table,
 		paginator: paginator,
+		layout:    layout,
 		width:     80,
 		height:    24,
+		marked:    make(map[uuid.UUID]bool),
+		tagInput:  tagInput,
+		noteTypes: apiClient.NoteTypes(),
 	}
 }
 
@@ -65,10 +131,24 @@ func (m NoteListModel) Init() tea.Cmd {
 
 // fetchNotesCmd returns a command that fetches notes
 func (m NoteListModel) fetchNotesCmd() tea.Cmd {
+	if m.sharedWithMe {
+		return func() tea.Msg {
+			notes, err := m.client.ListSharedWithMe()
+			if err != nil {
+				return noteListErrMsg{err}
+			}
+			return noteListFetchedMsg{notes: notes, total: int64(len(notes))}
+		}
+	}
+
 	return func() tea.Msg {
 		filter := model.NoteFilter{
-			Page:  m.page,
-			Limit: m.limit,
+			Page:              m.page,
+			Limit:             m.limit,
+			SortBy:            noteListSorts[m.sort].field,
+			SortOrder:         noteListSorts[m.sort].order,
+			IncludeTags:       true,
+			IncludeLinkCounts: true,
 		}
 		if m.search != "" {
 			filter.Search = m.search
@@ -76,6 +156,10 @@ func (m NoteListModel) fetchNotesCmd() tea.Cmd {
 		if m.tagFilter != nil {
 			filter.TagID = m.tagFilter
 		}
+		if m.typeFilter != "" {
+			noteType := m.typeFilter
+			filter.NoteType = &noteType
+		}
 
 		notes, total, err := m.client.ListNotes(filter)
 		if err != nil {
@@ -85,16 +169,191 @@ func (m NoteListModel) fetchNotesCmd() tea.Cmd {
 	}
 }
 
+// bulkTagCmd returns a command that adds or removes a tag on every marked note
+// A name starting with "-" removes the tag; otherwise the tag is created if needed and added.
+func (m NoteListModel) bulkTagCmd(input string) tea.Cmd {
+	noteIDs := make([]uuid.UUID, 0, len(m.marked))
+	for id := range m.marked {
+		noteIDs = append(noteIDs, id)
+	}
+
+	return func() tea.Msg {
+		action := model.BulkTagAdd
+		name := input
+		if strings.HasPrefix(name, "-") {
+			action = model.BulkTagRemove
+			name = strings.TrimSpace(strings.TrimPrefix(name, "-"))
+		}
+
+		tags, err := m.client.GetTags()
+		if err != nil {
+			return noteListTaggedMsg{err: err}
+		}
+
+		var tagID uuid.UUID
+		for _, tag := range tags {
+			if strings.EqualFold(tag.Name, name) {
+				tagID = tag.ID
+				break
+			}
+		}
+		if tagID == uuid.Nil {
+			if action == model.BulkTagRemove {
+				return noteListTaggedMsg{err: fmt.Errorf("tag %q not found", name)}
+			}
+			tag, err := m.client.CreateTag(name)
+			if err != nil {
+				return noteListTaggedMsg{err: err}
+			}
+			tagID = tag.ID
+		}
+
+		updated, err := m.client.BulkTagNotes(tagID, noteIDs, action)
+		if err != nil {
+			return noteListTaggedMsg{err: err}
+		}
+		return noteListTaggedMsg{name: name, action: action, updated: updated}
+	}
+}
+
+// toggleMark marks or unmarks the note under the cursor
+func (m NoteListModel) toggleMark() NoteListModel {
+	selected := m.table.SelectedItem()
+	if selected == nil {
+		return m
+	}
+	noteID, err := uuid.Parse(selected.ID)
+	if err != nil {
+		return m
+	}
+
+	m.status = ""
+	if m.marked[noteID] {
+		delete(m.marked, noteID)
+	} else {
+		m.marked[noteID] = true
+	}
+
+	m.updateRows()
+	m.table.CursorDown()
+	return m
+}
+
+// updateRows rebuilds the table rows, keeping the cursor in place
+func (m *NoteListModel) updateRows() {
+	cursor := m.table.SelectedIndex()
+
+	rows := make([]components.TableRow, len(m.notes))
+	for i, note := range m.notes {
+		title := note.Title
+		if len(m.marked) > 0 {
+			if m.marked[note.ID] {
+				title = "● " + title
+			} else {
+				title = "  " + title
+			}
+		}
+		noteType := string(note.NoteType)
+		if noteType == "" {
+			noteType = "note"
+		}
+		tags := formatNoteTags(note)
+		if note.SharedBy != "" {
+			// The owner's tags aren't listed, show whose note it is instead
+			tags = "@" + note.SharedBy
+		}
+		rows[i] = components.TableRow{
+			ID: note.ID.String(),
+			Cells: []string{
+				noteListTitleColumn:   title,
+				noteListTypeColumn:    noteType,
+				noteListTagsColumn:    tags,
+				noteListWordsColumn:   fmt.Sprint(note.WordCount),
+				noteListReadColumn:    fmt.Sprintf("%d min", note.ReadingTimeMinutes),
+				noteListUpdatedColumn: formatTimeAgo(note.UpdatedAt),
+			},
+			Description: m.formatNoteDescription(note),
+		}
+	}
+	m.table.SetItems(rows)
+	m.table.SetCursor(cursor)
+}
+
+// IsInputFocused returns whether the bulk tag form is focused
+// This allows the main TUI to skip global key handlers when typing.
+// A pending copy counts too, since the key after y picks what to copy.
+func (m NoteListModel) IsInputFocused() bool {
+	return (m.showTagForm && m.tagInput.Focused()) || m.yankPending
+}
+
+// IsYankPending returns whether y was pressed and Esc should cancel the copy
+func (m NoteListModel) IsYankPending() bool {
+	return m.yankPending
+}
+
 // Update handles messages for the note list model
 func (m NoteListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		// Handle bulk tag form
+		if m.showTagForm {
+			switch msg.String() {
+			case "enter":
+				input := strings.TrimSpace(m.tagInput.Value())
+				m.showTagForm = false
+				m.tagInput.SetValue("")
+				m.tagInput.Blur()
+				if input == "" || input == "-" {
+					return m, nil
+				}
+				m.status = "Updating tags..."
+				return m, m.bulkTagCmd(input)
+			case "esc":
+				m.showTagForm = false
+				m.tagInput.SetValue("")
+				m.tagInput.Blur()
+				return m, nil
+			}
+			cmd := m.tagInput.Update(msg)
+			return m, cmd
+		}
+
+		// The key after y picks what to copy from the selected note, any other key cancels
+		if m.yankPending {
+			m.yankPending = false
+			m.status = ""
+			if field, ok := yankField(msg.String()); ok {
+				if note := m.selectedNote(); note != nil {
+					return m, copyNoteCmd(m.client, note, field)
+				}
+			}
+			return m, nil
+		}
+
+		// Shared notes are listed in one page, as they are; they can't be sorted, filtered or tagged
+		if m.sharedWithMe {
+			switch msg.String() {
+			case "s", " ", "T", "0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "+", "-", "ctrl+n", "ctrl+p":
+				return m, nil
+			}
+		}
+
 		// Handle keyboard shortcuts
 		switch msg.String() {
 		case "q", "ctrl+c":
 			return m, tea.Quit
+		case "tab":
+			// Switch between the user's notes and the notes shared with them
+			m.sharedWithMe = !m.sharedWithMe
+			m.marked = make(map[uuid.UUID]bool)
+			m.status = ""
+			m.page = 1
+			m.paginator.SetPage(1)
+			m.loading = true
+			m.table.Top()
+			return m, m.fetchNotesCmd()
 		case "?":
 			// Request help view
 			return m, func() tea.Msg {
@@ -121,7 +380,28 @@ func (m NoteListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Go to bottom
 			m.table.Bottom()
 			return m, nil
-		case "enter", " ":
+		case "y":
+			// Copy a part of the selected note, picked by the next key
+			if m.selectedNote() != nil {
+				m.yankPending = true
+				m.status = yankHint
+			}
+			return m, nil
+		case " ":
+			// Mark or unmark the note for bulk tagging
+			m = m.toggleMark()
+			return m, nil
+		case "T":
+			// Tag every marked note
+			if len(m.marked) == 0 {
+				m.status = "Mark notes with space first"
+				return m, nil
+			}
+			m.showTagForm = true
+			m.status = ""
+			m.tagInput.Focus()
+			return m, nil
+		case "enter":
 			// Open selected note
 			if selected := m.table.SelectedItem(); selected != nil {
 				noteID, err := uuid.Parse(selected.ID)
@@ -132,6 +412,68 @@ func (m NoteListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 			return m, nil
+		case "s":
+			// Sort by the next field or direction, starting over from the first page
+			m.sort = (m.sort + 1) % len(noteListSorts)
+			m.table.SetSort(noteListSorts[m.sort].column, noteListSorts[m.sort].order == "desc")
+			m.page = 1
+			m.paginator.SetPage(1)
+			m.table.Top()
+			return m, m.fetchNotesCmd()
+		case "0", "1", "2", "3", "4", "5", "6", "7", "8", "9":
+			// Show only one type of note; the number of the shown type, or 0, shows every type
+			noteType := model.NoteType("")
+			if i := int(msg.String()[0] - '1'); i >= 0 && i < len(m.noteTypes) && m.noteTypes[i].Name != m.typeFilter {
+				noteType = m.noteTypes[i].Name
+			}
+			if noteType == m.typeFilter {
+				return m, nil
+			}
+			m.typeFilter = noteType
+			m.page = 1
+			m.paginator.SetPage(1)
+			m.table.Top()
+			return m, m.fetchNotesCmd()
+		case "v":
+			// Show or hide the description under each title
+			m.layout.ShowDescription = !m.layout.ShowDescription
+			m.table.SetShowDescription(m.layout.ShowDescription)
+			return m, m.layoutChangedCmd()
+		case "p":
+			// Show or hide the preview pane
+			if m.layout.PreviewWidth > 0 {
+				m.layout.PreviewWidth = 0
+			} else {
+				m.layout.PreviewWidth = DefaultPreviewWidth
+			}
+			m.resize()
+			return m, m.layoutChangedCmd()
+		case "<", ">":
+			// Resize the preview pane
+			if m.layout.PreviewWidth == 0 {
+				return m, nil
+			}
+			step := previewWidthStep
+			if msg.String() == ">" {
+				step = -step
+			}
+			m.layout.PreviewWidth = min(max(m.layout.PreviewWidth+step, minPreviewWidth), maxPreviewWidth)
+			m.resize()
+			return m, m.layoutChangedCmd()
+		case "+", "-":
+			// Change the page size, starting over from the first page
+			step := noteListPageSizeStep
+			if msg.String() == "-" {
+				step = -step
+			}
+			limit := min(max(m.limit+step, minNoteListPageSize), maxNoteListPageSize)
+			if limit == m.limit {
+				return m, nil
+			}
+			m.limit = limit
+			m.layout.PageSize = limit
+			m.page = 1
+			return m, tea.Batch(m.fetchNotesCmd(), m.layoutChangedCmd())
 		case "ctrl+n":
 			// Next page
 			if m.paginator.CanGoNext() {
@@ -156,16 +498,7 @@ func (m NoteListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.loading = false
 
 		// Update table rows
-		rows := make([]components.TableRow, len(msg.notes))
-		for i, note := range msg.notes {
-			rows[i] = components.TableRow{
-				ID:          note.ID.String(),
-				Title:       note.Title,
-				Description: m.formatNoteDescription(note),
-				Metadata:    m.formatNoteMetadata(note),
-			}
-		}
-		m.table.SetItems(rows)
+		m.updateRows()
 
 		// Update paginator
 		m.paginator.SetTotalItems(int(msg.total))
@@ -173,8 +506,7 @@ func (m NoteListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.paginator.SetPage(m.page)
 
 		// Update sizes
-		m.table.SetSize(m.width, m.height-3) // Leave room for header/paginator
-		m.paginator.SetWidth(m.width)
+		m.resize()
 
 		return m, nil
 
@@ -183,11 +515,23 @@ func (m NoteListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.loading = false
 		return m, nil
 
+	case noteListTaggedMsg:
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Tagging failed: %v", msg.err)
+			return m, nil
+		}
+		verb := "Tagged"
+		if msg.action == model.BulkTagRemove {
+			verb = "Untagged"
+		}
+		m.status = fmt.Sprintf("%s %d note(s) with #%s", verb, msg.updated, msg.name)
+		m.marked = make(map[uuid.UUID]bool)
+		return m, m.fetchNotesCmd()
+
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		m.table.SetSize(msg.Width, msg.Height-3)
-		m.paginator.SetWidth(msg.Width)
+		m.resize()
 		return m, nil
 	}
 
@@ -216,19 +560,41 @@ func (m NoteListModel) View() string {
 
 	// Header
 	headerStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#fab387")). // Orange
+		Foreground(theme().Secondary).
 		Bold(true)
 
-	content += headerStyle.Render("NOTES")
+	if m.sharedWithMe {
+		content += headerStyle.Render("SHARED WITH ME")
+	} else {
+		content += headerStyle.Render("NOTES")
+	}
 	if m.total > 0 {
 		content += lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#6c7086")). // Gray
+			Foreground(theme().Muted).
 			Render(fmt.Sprintf(" (%d total)", m.total))
 	}
+	if !m.sharedWithMe {
+		content += lipgloss.NewStyle().
+			Foreground(theme().Muted).
+			Render(" · " + noteListSorts[m.sort].label)
+	}
+	content += "\n" + m.renderSections()
+	if !m.sharedWithMe {
+		content += "  " + m.renderTypeFilter()
+	}
 	content += "\n\n"
+	if m.sharedWithMe && len(m.notes) == 0 {
+		content += lipgloss.NewStyle().
+			Foreground(theme().Muted).
+			Render("(no notes are shared with you yet)") + "\n"
+	}
 
-	// Table
-	content += m.table.View()
+	// Table, with the preview pane beside it
+	if m.layout.PreviewWidth > 0 {
+		content += lipgloss.JoinHorizontal(lipgloss.Top, m.table.View(), m.renderPreview())
+	} else {
+		content += m.table.View()
+	}
 	content += "\n"
 
 	// Paginator
@@ -239,6 +605,19 @@ func (m NoteListModel) View() string {
 		}
 	}
 
+	// Bulk tag form or status
+	if m.showTagForm {
+		content += "\n" + fmt.Sprintf("Tag %d marked note(s): ", len(m.marked)) + m.tagInput.View() + "\n"
+	} else if m.status != "" {
+		content += "\n" + lipgloss.NewStyle().
+			Foreground(theme().Accent).
+			Render(m.status) + "\n"
+	} else if len(m.marked) > 0 {
+		content += "\n" + lipgloss.NewStyle().
+			Foreground(theme().Warning).
+			Render(fmt.Sprintf("%d note(s) marked", len(m.marked))) + "\n"
+	}
+
 	// Quick actions hint
 	content += "\n"
 	content += m.renderQuickActions()
@@ -249,7 +628,7 @@ func (m NoteListModel) View() string {
 // renderLoading renders the loading state
 func (m NoteListModel) renderLoading() string {
 	style := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#89b4fa")). // Blue
+		Foreground(theme().Primary).
 		Bold(true)
 
 	return style.Render("Loading notes...")
@@ -258,7 +637,7 @@ func (m NoteListModel) renderLoading() string {
 // renderError renders the error state
 func (m NoteListModel) renderError() string {
 	errorStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#f38ba8")). // Red
+		Foreground(theme().Error).
 		Bold(true)
 
 	return errorStyle.Render(fmt.Sprintf("Error loading notes: %v", m.err))
@@ -267,53 +646,151 @@ func (m NoteListModel) renderError() string {
 // renderQuickActions renders the quick actions hint
 func (m NoteListModel) renderQuickActions() string {
 	hintStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#6c7086")). // Gray
+		Foreground(theme().Muted).
 		Faint(true)
 
-	return hintStyle.Render("↑↓:nav Enter:open Ctrl+N/P:page ?:help ESC:back q:quit")
+	if m.sharedWithMe {
+		return hintStyle.Render("↑↓:nav Enter:open Tab:my notes y:copy v:details p:preview </>:resize ?:help ESC:back q:quit")
+	}
+	return hintStyle.Render("↑↓:nav Enter:open Tab:shared with me s:sort 1-9:type Space:mark T:tag marked y:copy Ctrl+N/P:page v:details p:preview </>:resize +/-:page size ?:help ESC:back q:quit")
 }
 
-// formatNoteDescription formats the note description for the table
-func (m NoteListModel) formatNoteDescription(note *model.Note) string {
-	// Show tags if available
-	if len(note.Tags) > 0 {
-		tags := ""
-		for i, tag := range note.Tags {
-			if i > 0 {
-				tags += " "
-			}
-			tags += "#" + tag.Name
+// renderSections renders the sections Tab switches between, highlighting the one shown
+func (m NoteListModel) renderSections() string {
+	mutedStyle := lipgloss.NewStyle().Foreground(theme().Muted)
+	activeStyle := lipgloss.NewStyle().Foreground(theme().Primary).Bold(true)
+
+	mine, shared := mutedStyle.Render(" My notes "), mutedStyle.Render(" Shared with me ")
+	if m.sharedWithMe {
+		shared = activeStyle.Render("[Shared with me]")
+	} else {
+		mine = activeStyle.Render("[My notes]")
+	}
+	return mine + shared
+}
+
+// renderTypeFilter renders the note types, highlighting the one shown
+func (m NoteListModel) renderTypeFilter() string {
+	mutedStyle := lipgloss.NewStyle().Foreground(theme().Muted)
+	activeStyle := lipgloss.NewStyle().Foreground(theme().Primary).Bold(true)
+
+	render := func(key, label string, active bool) string {
+		if active {
+			return activeStyle.Render("[" + key + " " + label + "]")
 		}
-		return tags
+		return mutedStyle.Render(" " + key + " " + label + " ")
 	}
 
-	// Show preview of content
-	if len(note.Content) > 60 {
-		return note.Content[:60] + "..."
+	bar := mutedStyle.Render("Type:") + render("0", "all", m.typeFilter == "")
+	// Only the first nine types have a number key
+	for i, noteType := range m.noteTypes[:min(len(m.noteTypes), 9)] {
+		bar += render(fmt.Sprint(i+1), string(noteType.Name), m.typeFilter == noteType.Name)
 	}
-	return note.Content
+	return bar
+}
+
+// resize lays out the table and the preview pane for the current size
+func (m *NoteListModel) resize() {
+	m.table.SetSize(m.width-m.previewWidth(), m.height-4) // Leave room for header/type filter/paginator
+	m.paginator.SetWidth(m.width)
+}
+
+// previewWidth returns the width of the preview pane, 0 when it is hidden
+func (m NoteListModel) previewWidth() int {
+	return m.width * m.layout.PreviewWidth / 100
 }
 
-// formatNoteMetadata formats the note metadata for the table
-func (m NoteListModel) formatNoteMetadata(note *model.Note) string {
-	// Show type and access info
-	noteType := string(note.NoteType)
-	if noteType == "" {
-		noteType = "note"
+// renderPreview renders the content of the selected note
+func (m NoteListModel) renderPreview() string {
+	style := lipgloss.NewStyle().
+		Width(m.previewWidth()-2). // Border and padding
+		MaxHeight(m.height-3).
+		PaddingLeft(1).
+		Border(lipgloss.NormalBorder(), false, false, false, true).
+		BorderForeground(theme().Border)
+
+	note := m.selectedNote()
+	if note == nil {
+		return style.Foreground(theme().Muted).Render("No note selected")
+	}
+
+	content := note.Content
+	if note.Encrypted {
+		content = "(encrypted)"
+	}
+	title := lipgloss.NewStyle().Foreground(theme().Secondary).Bold(true).Render(note.Title)
+	return style.Render(title + "\n\n" + content)
+}
+
+// selectedNote returns the note under the cursor
+func (m NoteListModel) selectedNote() *model.Note {
+	selected := m.table.SelectedItem()
+	if selected == nil {
+		return nil
+	}
+	for _, note := range m.notes {
+		if note.ID.String() == selected.ID {
+			return note
+		}
+	}
+	return nil
+}
+
+// layoutChangedCmd reports the new layout so it is kept for the next launch
+func (m NoteListModel) layoutChangedCmd() tea.Cmd {
+	layout := m.layout
+	return func() tea.Msg {
+		return NoteListLayoutMsg{Layout: layout}
 	}
+}
+
+// formatNoteDescription formats the line shown under a note: views, links and a content preview
+func (m NoteListModel) formatNoteDescription(note *model.Note) string {
+	var parts []string
 
-	var info string
-	info += noteType
+	// Show who shared the note, and whether it can be edited
+	if note.SharedBy != "" {
+		access := "read only"
+		if note.Permission == model.SharePermissionWrite {
+			access = "can edit"
+		}
+		parts = append(parts, "shared by "+note.SharedBy+", "+access)
+	}
 
 	// Show access count if any
 	if note.AccessCount > 0 {
-		info += fmt.Sprintf(" • %d views", note.AccessCount)
+		parts = append(parts, fmt.Sprintf("%d views", note.AccessCount))
 	}
 
-	// Show time since last update
-	info += " • " + formatTimeAgo(note.UpdatedAt)
+	// Show how far a partly read note was read
+	if progress := note.Metadata.ReadProgress(); progress.Reading() {
+		parts = append(parts, fmt.Sprintf("%d%% read", progress.Percent))
+	}
+
+	// Show connections if any
+	if note.LinkCounts != nil {
+		if links := note.LinkCounts.Outgoing + note.LinkCounts.Incoming; links > 0 {
+			parts = append(parts, fmt.Sprintf("%d links", links))
+		}
+	}
 
-	return info
+	// Show preview of content
+	if note.Encrypted {
+		parts = append(parts, "(encrypted)")
+	} else if preview := strings.Join(strings.Fields(note.Content), " "); preview != "" {
+		parts = append(parts, preview)
+	}
+
+	return strings.Join(parts, " • ")
+}
+
+// formatNoteTags formats the tags of a note for the table
+func formatNoteTags(note *model.Note) string {
+	tags := make([]string, len(note.Tags))
+	for i, tag := range note.Tags {
+		tags[i] = "#" + tag.Name
+	}
+	return strings.Join(tags, " ")
 }
 
 // Message types for note list
@@ -327,8 +804,17 @@ type noteListErrMsg struct {
 	err error
 }
 
+type noteListTaggedMsg struct {
+	name    string
+	action  string
+	updated int64
+	err     error
+}
+
 // View request messages
 type ShowDashboardMsg struct{}
 type OpenNoteMsg struct {
-	NoteID uuid.UUID
+	NoteID  uuid.UUID
+	Heading string // Heading to scroll to, from a [[Note#Heading]] link
+	Block   string // Block to scroll to, from a [[Note^block-id]] link
 }
@@ -0,0 +1,301 @@
+package models
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/google/uuid"
+	"github.com/momokii/go-cli-notes/cmd/cli/client"
+	"github.com/momokii/go-cli-notes/cmd/cli/clipboard"
+	"github.com/momokii/go-cli-notes/cmd/cli/tui/components"
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+// PublishedModel is the model for the published notes view
+type PublishedModel struct {
+	client        *client.APIClient
+	authState     *client.AuthState
+	links         []*model.PublicLink
+	loading       bool
+	err           error
+	selectedIndex int
+	showConfirm   bool
+	confirmDialog components.ConfirmDialog
+	width         int
+	height        int
+}
+
+// NewPublishedModel creates a new published notes model
+func NewPublishedModel(apiClient *client.APIClient, authState *client.AuthState) PublishedModel {
+	return PublishedModel{
+		client:    apiClient,
+		authState: authState,
+		loading:   true,
+		width:     80,
+		height:    24,
+	}
+}
+
+// Init initializes the published notes model
+func (m PublishedModel) Init() tea.Cmd {
+	return m.fetchPublishedCmd()
+}
+
+// fetchPublishedCmd returns a command that fetches the published notes
+func (m PublishedModel) fetchPublishedCmd() tea.Cmd {
+	return func() tea.Msg {
+		links, err := m.client.ListPublicLinks()
+		if err != nil {
+			return PublishedErrMsg{Err: err}
+		}
+		return PublishedFetchedMsg{Links: links}
+	}
+}
+
+// selected returns the selected link, nil when there is none
+func (m PublishedModel) selected() *model.PublicLink {
+	if m.selectedIndex < 0 || m.selectedIndex >= len(m.links) {
+		return nil
+	}
+	return m.links[m.selectedIndex]
+}
+
+// revokeLinkCmd returns a command that revokes the link of the selected note
+func (m PublishedModel) revokeLinkCmd() tea.Cmd {
+	link := m.selected()
+	if link == nil {
+		return nil
+	}
+	noteID, title := link.NoteID, link.Title
+	return func() tea.Msg {
+		if err := m.client.UnpublishNote(noteID); err != nil {
+			return PublishedErrMsg{Err: err}
+		}
+		return PublicLinkRevokedMsg{NoteID: noteID, Title: title}
+	}
+}
+
+// copyLinkCmd returns a command that copies the link of the selected note to the clipboard
+func (m PublishedModel) copyLinkCmd() tea.Cmd {
+	link := m.selected()
+	if link == nil {
+		return nil
+	}
+	url := link.URL
+	return func() tea.Msg {
+		return PublicLinkCopiedMsg{URL: url, Err: clipboard.Copy(url)}
+	}
+}
+
+// Update handles messages for the published notes model
+func (m PublishedModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		// Handle confirmation dialog first
+		if m.showConfirm {
+			m.confirmDialog.Update(msg)
+			if m.confirmDialog.IsYesSelected() {
+				m.showConfirm = false
+				return m, m.revokeLinkCmd()
+			} else if m.confirmDialog.IsNoSelected() || msg.String() == "esc" {
+				m.showConfirm = false
+				return m, nil
+			}
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "q", "ctrl+c":
+			return m, tea.Quit
+		case "?":
+			return m, func() tea.Msg {
+				return ShowHelpMsg{}
+			}
+		case "esc":
+			return m, func() tea.Msg {
+				return ShowDashboardMsg{}
+			}
+		case "j", "down":
+			if m.selectedIndex < len(m.links)-1 {
+				m.selectedIndex++
+			}
+		case "k", "up":
+			if m.selectedIndex > 0 {
+				m.selectedIndex--
+			}
+		case "enter":
+			if link := m.selected(); link != nil {
+				noteID := link.NoteID
+				return m, func() tea.Msg {
+					return OpenNoteMsg{NoteID: noteID}
+				}
+			}
+		case "y":
+			return m, m.copyLinkCmd()
+		case "r":
+			// Refresh
+			m.loading = true
+			m.err = nil
+			return m, m.fetchPublishedCmd()
+		case "d":
+			// Revoke the link of the selected note
+			if link := m.selected(); link != nil {
+				m.showConfirm = true
+				m.confirmDialog = components.NewConfirmDialog("Revoke the link to \"" + truncateText(link.Title, 40) + "\"?")
+				m.confirmDialog.SetSubtext("The link stops working right away. Publishing the note again creates a new one.")
+				m.confirmDialog.Focus()
+			}
+			return m, nil
+		}
+
+	case PublishedFetchedMsg:
+		m.links = msg.Links
+		m.loading = false
+		if m.selectedIndex >= len(msg.Links) {
+			m.selectedIndex = len(msg.Links) - 1
+		}
+		if m.selectedIndex < 0 {
+			m.selectedIndex = 0
+		}
+		return m, nil
+
+	case PublicLinkRevokedMsg:
+		return m, m.fetchPublishedCmd()
+
+	case PublishedErrMsg:
+		m.err = msg.Err
+		m.loading = false
+		return m, nil
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// View renders the published notes view
+func (m PublishedModel) View() string {
+	if m.loading {
+		return m.renderLoading()
+	}
+
+	if m.err != nil {
+		return m.renderError()
+	}
+
+	if m.showConfirm {
+		return m.renderContent() + "\n\n" + m.confirmDialog.View()
+	}
+
+	return m.renderContent()
+}
+
+// renderLoading renders the loading state
+func (m PublishedModel) renderLoading() string {
+	style := lipgloss.NewStyle().
+		Foreground(theme().Primary).
+		Bold(true)
+
+	return style.Render("Loading published notes...")
+}
+
+// renderError renders the error state
+func (m PublishedModel) renderError() string {
+	errorStyle := lipgloss.NewStyle().
+		Foreground(theme().Error).
+		Bold(true)
+
+	return errorStyle.Render("Error: " + m.err.Error())
+}
+
+// renderContent renders the published note list, with the link of the selected note below it
+func (m PublishedModel) renderContent() string {
+	// Define styles
+	titleStyle := lipgloss.NewStyle().
+		Foreground(theme().Secondary).
+		Bold(true).
+		MarginBottom(1)
+
+	noteStyle := lipgloss.NewStyle().
+		Foreground(theme().Foreground)
+
+	selectedStyle := lipgloss.NewStyle().
+		Foreground(theme().Background).
+		Background(theme().Primary).
+		Bold(true)
+
+	mutedStyle := lipgloss.NewStyle().
+		Foreground(theme().Muted).
+		Faint(true)
+
+	linkStyle := lipgloss.NewStyle().
+		Foreground(theme().Accent)
+
+	hintStyle := lipgloss.NewStyle().
+		Foreground(theme().Muted).
+		Faint(true).
+		MarginTop(1)
+
+	var content string
+
+	content += titleStyle.Render(fmt.Sprintf("PUBLISHED NOTES (%d)", len(m.links))) + "\n\n"
+
+	if len(m.links) == 0 {
+		content += mutedStyle.Render("(no published notes - run 'kg-cli note publish <id>' to publish one)")
+		content += "\n\n"
+		content += hintStyle.Render("r:refresh ESC:back ?:help")
+		return content
+	}
+
+	// Leave room for the age after the title
+	maxTitle := m.width - 20
+	if maxTitle < 20 {
+		maxTitle = 20
+	}
+
+	for i, link := range m.links {
+		title := truncateText(link.Title, maxTitle)
+		info := " · " + formatTimeAgo(link.CreatedAt)
+
+		if i == m.selectedIndex {
+			content += selectedStyle.Render("→ " + title + info)
+		} else {
+			content += "  " + noteStyle.Render(title) + mutedStyle.Render(info)
+		}
+
+		content += "\n"
+	}
+
+	if link := m.selected(); link != nil {
+		content += "\n" + linkStyle.Render(truncateText(link.URL, m.width-2)) + "\n"
+	}
+
+	// Hints
+	content += "\n" + hintStyle.Render("j/k:navigate enter:open y:copy link d:revoke r:refresh ESC:back ?:help")
+
+	return content
+}
+
+// Message types for the published notes view
+
+type PublishedFetchedMsg struct {
+	Links []*model.PublicLink
+}
+
+type PublicLinkRevokedMsg struct {
+	NoteID uuid.UUID
+	Title  string
+}
+
+type PublicLinkCopiedMsg struct {
+	URL string
+	Err error
+}
+
+type PublishedErrMsg struct {
+	Err error
+}
@@ -0,0 +1,192 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/google/uuid"
+	"github.com/momokii/go-cli-notes/cmd/cli/client"
+	"github.com/momokii/go-cli-notes/cmd/cli/tui/components"
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+// quickSwitchIndexTTL is how long the title index is reused before refetching
+const quickSwitchIndexTTL = time.Minute
+
+// quickSwitchPageSize is the page size used when building the title index (API max)
+const quickSwitchPageSize = 100
+
+// quickSwitchEntry is a note in the title index
+type quickSwitchEntry struct {
+	ID    uuid.UUID
+	Title string
+}
+
+// QuickSwitchModel is the model for the Ctrl+K quick switcher overlay
+// Typing ">" first turns it into a command palette.
+type QuickSwitchModel struct {
+	client    *client.APIClient
+	authState *client.AuthState
+	switcher  components.QuickSwitcher
+	entries   []quickSwitchEntry
+	commands  []paletteCommand
+	indexedAt time.Time
+	loading   bool
+	err       error
+	width     int
+	height    int
+}
+
+// NewQuickSwitchModel creates a new quick switcher model
+func NewQuickSwitchModel(apiClient *client.APIClient, authState *client.AuthState) QuickSwitchModel {
+	return QuickSwitchModel{
+		client:    apiClient,
+		authState: authState,
+		switcher:  components.NewQuickSwitcher(),
+		width:     80,
+		height:    24,
+	}
+}
+
+// Init initializes the quick switcher model
+func (m QuickSwitchModel) Init() tea.Cmd {
+	return nil
+}
+
+// Open resets the query and refreshes the title index if it is stale
+func (m QuickSwitchModel) Open() (QuickSwitchModel, tea.Cmd) {
+	m.switcher.Reset()
+	m.switcher.Focus()
+	m.err = nil
+
+	m.commands = paletteCommands()
+	labels := make([]string, 0, len(m.commands))
+	for _, command := range m.commands {
+		labels = append(labels, command.Label)
+	}
+	m.switcher.SetCommands(labels)
+
+	if m.loading || (len(m.entries) > 0 && time.Since(m.indexedAt) < quickSwitchIndexTTL) {
+		return m, nil
+	}
+	m.loading = true
+	return m, m.fetchIndexCmd()
+}
+
+// fetchIndexCmd returns a command that loads all note titles, most recently updated first
+func (m QuickSwitchModel) fetchIndexCmd() tea.Cmd {
+	return func() tea.Msg {
+		var all []*model.Note
+		for page := 1; ; page++ {
+			notes, total, err := m.client.ListNotes(model.NoteFilter{
+				Page:   page,
+				Limit:  quickSwitchPageSize,
+				SortBy: "updated_at",
+			})
+			if err != nil {
+				return QuickSwitchIndexErrMsg{Err: err}
+			}
+			all = append(all, notes...)
+			if len(notes) < quickSwitchPageSize || int64(len(all)) >= total {
+				break
+			}
+		}
+		return QuickSwitchIndexMsg{Notes: all}
+	}
+}
+
+// Update handles messages for the quick switcher model
+func (m QuickSwitchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "ctrl+c", "ctrl+k":
+			return m, func() tea.Msg {
+				return CloseQuickSwitchMsg{}
+			}
+		case "enter":
+			idx := m.switcher.Selected()
+			if m.switcher.InCommandMode() {
+				if idx < 0 || idx >= len(m.commands) {
+					return m, nil
+				}
+				commandMsg := m.commands[idx].Msg
+				return m, func() tea.Msg {
+					return commandMsg
+				}
+			}
+			if idx < 0 || idx >= len(m.entries) {
+				return m, nil
+			}
+			noteID := m.entries[idx].ID
+			return m, func() tea.Msg {
+				return QuickSwitchSelectedMsg{NoteID: noteID}
+			}
+		}
+		cmd := m.switcher.Update(msg)
+		return m, cmd
+
+	case QuickSwitchIndexMsg:
+		m.loading = false
+		m.err = nil
+		m.indexedAt = time.Now()
+		m.entries = make([]quickSwitchEntry, 0, len(msg.Notes))
+		titles := make([]string, 0, len(msg.Notes))
+		for _, note := range msg.Notes {
+			m.entries = append(m.entries, quickSwitchEntry{ID: note.ID, Title: note.Title})
+			titles = append(titles, note.Title)
+		}
+		m.switcher.SetItems(titles)
+		return m, nil
+
+	case QuickSwitchIndexErrMsg:
+		m.loading = false
+		m.err = msg.Err
+		return m, nil
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.switcher.SetWidth(min(70, msg.Width-10))
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// View renders the quick switcher overlay centered in the given area
+func (m QuickSwitchModel) View() string {
+	content := m.switcher.View()
+
+	if m.loading {
+		loadingStyle := lipgloss.NewStyle().
+			Foreground(theme().Primary).
+			Faint(true)
+		content += "\n" + loadingStyle.Render("Indexing note titles...")
+	} else if m.err != nil {
+		errorStyle := lipgloss.NewStyle().
+			Foreground(theme().Error).
+			Faint(true)
+		content += "\n" + errorStyle.Render(fmt.Sprintf("Error loading notes: %v", m.err))
+	}
+
+	// Leave room for the app header and status bar
+	return lipgloss.Place(m.width, m.height-3, lipgloss.Center, lipgloss.Center, content)
+}
+
+// Quick switcher messages
+type QuickSwitchIndexMsg struct {
+	Notes []*model.Note
+}
+
+type QuickSwitchIndexErrMsg struct {
+	Err error
+}
+
+type QuickSwitchSelectedMsg struct {
+	NoteID uuid.UUID
+}
+
+type CloseQuickSwitchMsg struct{}
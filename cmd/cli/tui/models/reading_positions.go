@@ -0,0 +1,129 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxReadingPositions is how many notes keep their reading position; the oldest are forgotten first
+const maxReadingPositions = 500
+
+// ReadingPosition is how far a note was scrolled when it was last left
+type ReadingPosition struct {
+	Offset  int       `json:"offset"` // First visible line of the rendered content
+	SavedAt time.Time `json:"saved_at"`
+}
+
+// tuiState is the layout of the state file
+type tuiState struct {
+	ReadingPositions map[uuid.UUID]ReadingPosition `json:"reading_positions"`
+}
+
+// ReadingPositions remembers the reading position of each note between sessions
+// Positions are kept in ~/.config/kg-cli/state.json, on this machine only.
+type ReadingPositions struct {
+	statePath string
+	positions map[uuid.UUID]ReadingPosition
+}
+
+// NewReadingPositions loads the reading positions of the last sessions
+func NewReadingPositions() (*ReadingPositions, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("get home dir: %w", err)
+	}
+
+	configDir := filepath.Join(homeDir, ".config", "kg-cli")
+	if err := os.MkdirAll(configDir, 0700); err != nil {
+		return nil, fmt.Errorf("create config dir: %w", err)
+	}
+
+	rp := &ReadingPositions{
+		statePath: filepath.Join(configDir, "state.json"),
+		positions: map[uuid.UUID]ReadingPosition{},
+	}
+
+	data, err := os.ReadFile(rp.statePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return rp, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read state: %w", err)
+	}
+
+	var state tuiState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("decode state: %w", err)
+	}
+	if state.ReadingPositions != nil {
+		rp.positions = state.ReadingPositions
+	}
+
+	return rp, nil
+}
+
+// Get returns the saved offset of a note, 0 when it was never scrolled
+func (rp *ReadingPositions) Get(noteID uuid.UUID) int {
+	return rp.positions[noteID].Offset
+}
+
+// Set saves the offset of a note; offset 0 forgets the note
+// The state file is only written when the position changed.
+func (rp *ReadingPositions) Set(noteID uuid.UUID, offset int) error {
+	if rp.positions[noteID].Offset == offset {
+		return nil
+	}
+
+	if offset <= 0 {
+		delete(rp.positions, noteID)
+	} else {
+		rp.positions[noteID] = ReadingPosition{Offset: offset, SavedAt: time.Now()}
+		rp.prune()
+	}
+
+	return rp.save()
+}
+
+// prune forgets the least recently saved positions beyond maxReadingPositions
+func (rp *ReadingPositions) prune() {
+	if len(rp.positions) <= maxReadingPositions {
+		return
+	}
+
+	ids := make([]uuid.UUID, 0, len(rp.positions))
+	for id := range rp.positions {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return rp.positions[ids[i]].SavedAt.After(rp.positions[ids[j]].SavedAt)
+	})
+	for _, id := range ids[maxReadingPositions:] {
+		delete(rp.positions, id)
+	}
+}
+
+// save writes the state file
+func (rp *ReadingPositions) save() error {
+	data, err := json.Marshal(tuiState{ReadingPositions: rp.positions})
+	if err != nil {
+		return fmt.Errorf("encode state: %w", err)
+	}
+
+	// Write to a temporary file first so a crash mid-write keeps the previous state
+	tmpPath := rp.statePath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("write state: %w", err)
+	}
+	if err := os.Rename(tmpPath, rp.statePath); err != nil {
+		return fmt.Errorf("write state: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,271 @@
+package models
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/momokii/go-cli-notes/cmd/cli/client"
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+// reviewBatchSize is how many due cards are fetched at a time
+const reviewBatchSize = 20
+
+// reviewGrades describes the SM-2 grades, from 0 to 5
+var reviewGrades = []string{"blank", "wrong", "familiar", "hard", "good", "perfect"}
+
+// ReviewModel is the model for the flashcard review view
+// It shows the question of each due card, then its answer, and grades how well it was remembered.
+type ReviewModel struct {
+	client    *client.APIClient
+	authState *client.AuthState
+	cards     []*model.Card
+	index     int   // Card being reviewed
+	total     int64 // Cards due today when last fetched
+	today     string
+	revealed  bool   // The answer of the current card is shown
+	reviewed  int    // Cards graded in this session
+	lastDue   string // When the card graded last is due again
+	loading   bool
+	grading   bool
+	err       error
+	width     int
+	height    int
+}
+
+// NewReviewModel creates a new review model
+func NewReviewModel(apiClient *client.APIClient, authState *client.AuthState) ReviewModel {
+	return ReviewModel{
+		client:    apiClient,
+		authState: authState,
+		loading:   true,
+		width:     80,
+		height:    24,
+	}
+}
+
+// Init initializes the review model
+func (m ReviewModel) Init() tea.Cmd {
+	return m.fetchDueCmd()
+}
+
+// fetchDueCmd returns a command that fetches the cards due today
+func (m ReviewModel) fetchDueCmd() tea.Cmd {
+	return func() tea.Msg {
+		due, err := m.client.GetDueCards(reviewBatchSize)
+		if err != nil {
+			return ReviewErrMsg{Err: err}
+		}
+		return DueCardsFetchedMsg{Due: due}
+	}
+}
+
+// reviewCardCmd returns a command that grades the current card
+func (m ReviewModel) reviewCardCmd(grade int) tea.Cmd {
+	card := m.cards[m.index]
+	return func() tea.Msg {
+		next, err := m.client.ReviewCard(card.ID, grade)
+		if err != nil {
+			return ReviewErrMsg{Err: err}
+		}
+		return CardReviewedMsg{Card: next}
+	}
+}
+
+// current returns the card being reviewed, nil when none are left
+func (m ReviewModel) current() *model.Card {
+	if m.index < len(m.cards) {
+		return m.cards[m.index]
+	}
+	return nil
+}
+
+// Update handles messages for the review model
+func (m ReviewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch key := msg.String(); key {
+		case "q", "ctrl+c":
+			return m, tea.Quit
+		case "?":
+			return m, func() tea.Msg {
+				return ShowHelpMsg{}
+			}
+		case "esc":
+			return m, func() tea.Msg {
+				return ShowDashboardMsg{}
+			}
+		case " ", "enter":
+			if m.current() != nil && !m.loading {
+				m.revealed = true
+			}
+		case "0", "1", "2", "3", "4", "5":
+			if m.current() != nil && m.revealed && !m.grading {
+				m.grading = true
+				return m, m.reviewCardCmd(int(key[0] - '0'))
+			}
+		case "o":
+			// Open the note the card is from
+			if card := m.current(); card != nil {
+				return m, func() tea.Msg {
+					return OpenNoteMsg{NoteID: card.NoteID}
+				}
+			}
+		case "r":
+			// Refresh
+			m.loading = true
+			m.err = nil
+			return m, m.fetchDueCmd()
+		}
+
+	case DueCardsFetchedMsg:
+		m.cards = msg.Due.Cards
+		m.total = msg.Due.Total
+		m.today = msg.Due.Today
+		m.index = 0
+		m.revealed = false
+		m.loading = false
+		return m, nil
+
+	case CardReviewedMsg:
+		m.grading = false
+		m.reviewed++
+		m.revealed = false
+		m.index++
+		m.total--
+		if msg.Card.DueOn != nil {
+			m.lastDue = *msg.Card.DueOn
+		}
+		// Fetch the next batch once this one is done and more are due
+		if m.index >= len(m.cards) && m.total > 0 {
+			m.loading = true
+			return m, m.fetchDueCmd()
+		}
+		return m, nil
+
+	case ReviewErrMsg:
+		m.err = msg.Err
+		m.loading = false
+		m.grading = false
+		return m, nil
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// View renders the review view
+func (m ReviewModel) View() string {
+	if m.loading {
+		return m.renderLoading()
+	}
+
+	if m.err != nil {
+		return m.renderError()
+	}
+
+	return m.renderContent()
+}
+
+// renderLoading renders the loading state
+func (m ReviewModel) renderLoading() string {
+	style := lipgloss.NewStyle().
+		Foreground(theme().Primary).
+		Bold(true)
+
+	return style.Render("Loading due cards...")
+}
+
+// renderError renders the error state
+func (m ReviewModel) renderError() string {
+	errorStyle := lipgloss.NewStyle().
+		Foreground(theme().Error).
+		Bold(true)
+
+	return errorStyle.Render("Error: " + m.err.Error() + "\n\nr:retry ESC:back")
+}
+
+// renderContent renders the current card, or the summary once no cards are left
+func (m ReviewModel) renderContent() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(theme().Secondary).
+		Bold(true).
+		MarginBottom(1)
+
+	labelStyle := lipgloss.NewStyle().
+		Foreground(theme().Primary).
+		Bold(true)
+
+	textStyle := lipgloss.NewStyle().
+		Foreground(theme().Foreground).
+		Width(max(20, m.width-4))
+
+	noteStyle := lipgloss.NewStyle().
+		Foreground(theme().Special).
+		Faint(true)
+
+	mutedStyle := lipgloss.NewStyle().
+		Foreground(theme().Muted).
+		Faint(true)
+
+	hintStyle := lipgloss.NewStyle().
+		Foreground(theme().Muted).
+		Faint(true).
+		MarginTop(1)
+
+	var content string
+
+	card := m.current()
+	if card == nil {
+		content += titleStyle.Render("REVIEW") + "\n\n"
+		if m.reviewed > 0 {
+			content += textStyle.Render(fmt.Sprintf("All caught up! You reviewed %d card(s) today.", m.reviewed)) + "\n"
+		} else {
+			content += mutedStyle.Render("(no cards due - add \"Q:: question A:: answer\" lines to a note)") + "\n"
+		}
+		content += hintStyle.Render("r:refresh ESC:back ?:help")
+		return content
+	}
+
+	content += titleStyle.Render(fmt.Sprintf("REVIEW (%d due on %s)", m.total, m.today)) + "\n\n"
+	content += noteStyle.Render("From: "+card.NoteTitle) + "\n\n"
+	content += labelStyle.Render("Q:") + "\n" + textStyle.Render(card.Question) + "\n\n"
+
+	if !m.revealed {
+		content += hintStyle.Render("Space/Enter:show answer o:open note ESC:back ?:help")
+		return content
+	}
+
+	content += labelStyle.Render("A:") + "\n" + textStyle.Render(card.Answer) + "\n\n"
+
+	grades := "How well did you remember it?\n"
+	for grade, desc := range reviewGrades {
+		grades += fmt.Sprintf("  %d %s", grade, desc)
+	}
+	content += mutedStyle.Render(grades)
+	if m.lastDue != "" {
+		content += "\n" + mutedStyle.Render("Previous card due again on "+m.lastDue)
+	}
+	content += "\n" + hintStyle.Render("0-5:grade o:open note ESC:back ?:help")
+
+	return content
+}
+
+// Message types for the review view
+
+type DueCardsFetchedMsg struct {
+	Due *model.DueCards
+}
+
+type CardReviewedMsg struct {
+	Card *model.Card
+}
+
+type ReviewErrMsg struct {
+	Err error
+}
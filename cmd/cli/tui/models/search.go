@@ -17,6 +17,9 @@ type SearchModel struct {
 	authState     *client.AuthState
 	query         string
 	results       []*model.SearchResult
+	fuzzy         bool // Results are similar titles, not matches
+	history       []*model.SearchHistoryEntry
+	historyIndex  int // Selected recent query while the input is empty
 	loading       bool
 	err           error
 	selectedIndex int
@@ -48,7 +51,7 @@ func NewSearchModel(apiClient *client.APIClient, authState *client.AuthState) Se
 // Init initializes the search model
 func (m SearchModel) Init() tea.Cmd {
 	m.input.Focus()
-	return nil
+	return m.historyCmd()
 }
 
 // FocusInput focuses the search input and returns the updated model
@@ -74,8 +77,25 @@ func (m SearchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Handle input mode
 		if m.input.Focused() {
 			switch msg.String() {
+			case "up", "down":
+				// Choose among the recent queries while nothing is typed
+				if m.input.Value() == "" && len(m.history) > 0 {
+					if msg.String() == "up" && m.historyIndex > 0 {
+						m.historyIndex--
+					}
+					if msg.String() == "down" && m.historyIndex < len(m.history)-1 {
+						m.historyIndex++
+					}
+					return m, nil
+				}
+				cmd := m.input.Update(msg)
+				return m, cmd
 			case "enter":
 				query := m.input.Value()
+				if query == "" && len(m.history) > 0 {
+					query = m.history[m.historyIndex].Query
+					m.input.SetValue(query)
+				}
 				if query != "" {
 					m.query = query
 					m.input.Blur()
@@ -113,7 +133,7 @@ func (m SearchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.results = nil
 				m.input.SetValue("")
 				m.input.Focus()
-				return m, nil
+				return m, m.historyCmd()
 			}
 			return m, func() tea.Msg {
 				return ShowDashboardMsg{}
@@ -154,6 +174,7 @@ func (m SearchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case SearchResultsMsg:
 		m.results = msg.Results
+		m.fuzzy = msg.Fuzzy
 		m.loading = false
 		m.selectedIndex = 0
 
@@ -167,6 +188,11 @@ func (m SearchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		return m, nil
 
+	case SearchHistoryMsg:
+		m.history = msg.Queries
+		m.historyIndex = 0
+		return m, nil
+
 	case SearchErrMsg:
 		m.err = msg.Err
 		m.loading = false
@@ -190,7 +216,7 @@ func (m SearchModel) searchCmd(query string) tea.Cmd {
 	m.loading = true
 	m.query = query
 	return func() tea.Msg {
-		resp, err := m.client.SearchNotes(query, 1, 10)
+		resp, err := m.client.SearchNotes(query, 1, 10, true)
 		if err != nil {
 			return SearchErrMsg{Err: err}
 		}
@@ -198,16 +224,29 @@ func (m SearchModel) searchCmd(query string) tea.Cmd {
 			Query:       resp.Query,
 			Results:     resp.Results,
 			Pagination:  resp.Pagination,
+			Fuzzy:       resp.Fuzzy,
 			CurrentPage: 1,
 		}
 	}
 }
 
+// historyCmd returns a command that loads the recent search queries
+// Failing to load them only means no suggestions, so errors are dropped.
+func (m SearchModel) historyCmd() tea.Cmd {
+	return func() tea.Msg {
+		resp, err := m.client.GetSearchHistory(8)
+		if err != nil {
+			return SearchHistoryMsg{}
+		}
+		return SearchHistoryMsg{Queries: resp.Queries}
+	}
+}
+
 // searchPageCmd returns a command that performs a search for a specific page
 func (m SearchModel) searchPageCmd(query string, page int) tea.Cmd {
 	m.loading = true
 	return func() tea.Msg {
-		resp, err := m.client.SearchNotes(query, page, 10)
+		resp, err := m.client.SearchNotes(query, page, 10, true)
 		if err != nil {
 			return SearchErrMsg{Err: err}
 		}
@@ -215,6 +254,7 @@ func (m SearchModel) searchPageCmd(query string, page int) tea.Cmd {
 			Query:       resp.Query,
 			Results:     resp.Results,
 			Pagination:  resp.Pagination,
+			Fuzzy:       resp.Fuzzy,
 			CurrentPage: page,
 		}
 	}
@@ -236,7 +276,7 @@ func (m SearchModel) View() string {
 // renderLoading renders the loading state
 func (m SearchModel) renderLoading() string {
 	style := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#89b4fa")). // Blue
+		Foreground(theme().Primary).
 		Bold(true)
 
 	return style.Render("Searching...")
@@ -245,7 +285,7 @@ func (m SearchModel) renderLoading() string {
 // renderError renders the error state
 func (m SearchModel) renderError() string {
 	errorStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#f38ba8")). // Red
+		Foreground(theme().Error).
 		Bold(true)
 
 	return errorStyle.Render("Error: " + m.err.Error())
@@ -255,32 +295,32 @@ func (m SearchModel) renderError() string {
 func (m SearchModel) renderContent() string {
 	// Define styles
 	titleStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#fab387")). // Orange
+		Foreground(theme().Secondary).
 		Bold(true).
 		MarginBottom(1)
 
 	labelStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#89b4fa")). // Blue
+		Foreground(theme().Primary).
 		Bold(true)
 
 	resultStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#cdd6f4")) // Light text
+		Foreground(theme().Foreground)
 
 	selectedStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#1e1e2e")). // Dark
-		Background(lipgloss.Color("#89b4fa")). // Blue
+		Foreground(theme().Background).
+		Background(theme().Primary).
 		Bold(true)
 
 	snippetStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#6c7086")). // Gray
+		Foreground(theme().Muted).
 		Faint(true)
 
 	mutedStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#6c7086")). // Gray
+		Foreground(theme().Muted).
 		Faint(true)
 
 	hintStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#6c7086")). // Gray
+		Foreground(theme().Muted).
 		Faint(true).
 		MarginTop(1)
 
@@ -296,6 +336,19 @@ func (m SearchModel) renderContent() string {
 
 	// Results
 	if m.query == "" {
+		if len(m.history) > 0 && m.input.Value() == "" {
+			content += labelStyle.Render("Recent searches") + "\n"
+			for i, entry := range m.history {
+				if i == m.historyIndex {
+					content += "→ " + selectedStyle.Render(entry.Query) + "\n"
+				} else {
+					content += "  " + resultStyle.Render(entry.Query) + "\n"
+				}
+			}
+			content += "\n"
+			content += hintStyle.Render("↑/↓:choose Enter:search ESC:back ?:help")
+			return content
+		}
 		content += mutedStyle.Render("Enter a search query and press Enter")
 		content += "\n\n"
 		content += hintStyle.Render("/:focus ESC:back ?:help")
@@ -309,6 +362,11 @@ func (m SearchModel) renderContent() string {
 		return content
 	}
 
+	if m.fuzzy {
+		content += mutedStyle.Render(fmt.Sprintf("No exact matches for \"%s\", showing similar titles", m.query))
+		content += "\n\n"
+	}
+
 	// Display all results (API already handles pagination)
 	// Don't apply local pagination since results are already paginated by API
 	for i := range m.results {
@@ -326,7 +384,8 @@ func (m SearchModel) renderContent() string {
 		if title == "" {
 			title = "(untitled)"
 		}
-		title = m.highlightText(title, m.query)
+		// Cut before highlighting so the cut can't split a highlight
+		title = m.highlightText(truncateText(title, m.width-4), m.query)
 
 		if i == m.selectedIndex {
 			line += selectedStyle.Render(title)
@@ -336,10 +395,10 @@ func (m SearchModel) renderContent() string {
 
 		content += line + "\n"
 
-		// Snippet
+		// Snippet, wrapped under the title
 		if result.Snippet != "" {
-			snippet := m.highlightText(result.Snippet, m.query)
-			content += "    " + snippetStyle.Render(snippet) + "\n"
+			lines := components.WrapLines("    ", "    ", m.renderSnippet(result.Snippet, snippetStyle), m.width-2)
+			content += strings.Join(lines, "\n") + "\n"
 		}
 	}
 
@@ -352,6 +411,41 @@ func (m SearchModel) renderContent() string {
 	return content
 }
 
+// renderSnippet renders a search snippet, styling the words the server marked as matches
+// Each segment is rendered on its own so the highlight doesn't reset the snippet style.
+func (m SearchModel) renderSnippet(snippet string, style lipgloss.Style) string {
+	highlightStyle := lipgloss.NewStyle().
+		Foreground(theme().Warning).
+		Bold(true)
+
+	// Fragments can span lines; join them so the snippet wraps as one paragraph
+	snippet = strings.Join(strings.Fields(snippet), " ")
+
+	var result strings.Builder
+	for {
+		start := strings.Index(snippet, model.HighlightStart)
+		if start == -1 {
+			break
+		}
+		end := strings.Index(snippet[start:], model.HighlightStop)
+		if end == -1 {
+			break
+		}
+		end += start
+
+		if start > 0 {
+			result.WriteString(style.Render(snippet[:start]))
+		}
+		result.WriteString(highlightStyle.Render(snippet[start+len(model.HighlightStart) : end]))
+		snippet = snippet[end+len(model.HighlightStop):]
+	}
+	if snippet != "" {
+		result.WriteString(style.Render(snippet))
+	}
+
+	return result.String()
+}
+
 // highlightText highlights search terms in text
 func (m SearchModel) highlightText(text, query string) string {
 	if query == "" {
@@ -389,7 +483,7 @@ func (m SearchModel) highlightText(text, query string) string {
 			matchEnd = len(text)
 		}
 		result.WriteString(lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#f9e2af")). // Yellow
+			Foreground(theme().Warning).
 			Bold(true).
 			Render(text[idx:matchEnd]))
 
@@ -417,9 +511,15 @@ type SearchResultsMsg struct {
 	Query       string
 	Results     []*model.SearchResult
 	Pagination  *model.Pagination
+	Fuzzy       bool // Nothing matched exactly, these are notes with a similar title
 	CurrentPage int
 }
 
+// SearchHistoryMsg carries the recent search queries
+type SearchHistoryMsg struct {
+	Queries []*model.SearchHistoryEntry
+}
+
 type SearchErrMsg struct {
 	Err error
 }
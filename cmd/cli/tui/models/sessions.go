@@ -0,0 +1,266 @@
+package models
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/google/uuid"
+	"github.com/momokii/go-cli-notes/cmd/cli/client"
+	"github.com/momokii/go-cli-notes/cmd/cli/tui/components"
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+// SessionsModel is the model for the active sessions view
+type SessionsModel struct {
+	client        *client.APIClient
+	authState     *client.AuthState
+	sessions      []*model.RefreshToken
+	loading       bool
+	err           error
+	selectedIndex int
+	showConfirm   bool
+	confirmDialog components.ConfirmDialog
+	width         int
+	height        int
+}
+
+// NewSessionsModel creates a new sessions model
+func NewSessionsModel(apiClient *client.APIClient, authState *client.AuthState) SessionsModel {
+	return SessionsModel{
+		client:    apiClient,
+		authState: authState,
+		loading:   true,
+		width:     80,
+		height:    24,
+	}
+}
+
+// Init initializes the sessions model
+func (m SessionsModel) Init() tea.Cmd {
+	return m.fetchSessionsCmd()
+}
+
+// fetchSessionsCmd returns a command that fetches active sessions
+func (m SessionsModel) fetchSessionsCmd() tea.Cmd {
+	return func() tea.Msg {
+		sessions, err := m.client.ListSessions()
+		if err != nil {
+			return SessionsErrMsg{Err: err}
+		}
+		return SessionsFetchedMsg{Sessions: sessions}
+	}
+}
+
+// revokeSessionCmd returns a command that revokes the selected session
+func (m SessionsModel) revokeSessionCmd() tea.Cmd {
+	if len(m.sessions) == 0 || m.selectedIndex < 0 || m.selectedIndex >= len(m.sessions) {
+		return nil
+	}
+	sessionID := m.sessions[m.selectedIndex].ID
+	return func() tea.Msg {
+		if err := m.client.RevokeSession(sessionID); err != nil {
+			return SessionsErrMsg{Err: err}
+		}
+		return SessionRevokedMsg{SessionID: sessionID}
+	}
+}
+
+// Update handles messages for the sessions model
+func (m SessionsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		// Handle confirmation dialog first
+		if m.showConfirm {
+			m.confirmDialog.Update(msg)
+			if m.confirmDialog.IsYesSelected() {
+				m.showConfirm = false
+				return m, m.revokeSessionCmd()
+			} else if m.confirmDialog.IsNoSelected() || msg.String() == "esc" {
+				m.showConfirm = false
+				return m, nil
+			}
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "q", "ctrl+c":
+			return m, tea.Quit
+		case "?":
+			return m, func() tea.Msg {
+				return ShowHelpMsg{}
+			}
+		case "esc":
+			return m, func() tea.Msg {
+				return ShowDashboardMsg{}
+			}
+		case "j", "down":
+			if m.selectedIndex < len(m.sessions)-1 {
+				m.selectedIndex++
+			}
+		case "k", "up":
+			if m.selectedIndex > 0 {
+				m.selectedIndex--
+			}
+		case "r":
+			// Refresh
+			m.loading = true
+			m.err = nil
+			return m, m.fetchSessionsCmd()
+		case "d":
+			// Revoke selected session
+			if len(m.sessions) > 0 {
+				m.showConfirm = true
+				m.confirmDialog = components.NewConfirmDialog("Revoke this session?")
+				m.confirmDialog.SetSubtext("The device will be signed out once its access token expires.")
+				m.confirmDialog.Focus()
+			}
+			return m, nil
+		}
+
+	case SessionsFetchedMsg:
+		m.sessions = msg.Sessions
+		m.loading = false
+		if m.selectedIndex >= len(msg.Sessions) {
+			m.selectedIndex = len(msg.Sessions) - 1
+		}
+		if m.selectedIndex < 0 {
+			m.selectedIndex = 0
+		}
+		return m, nil
+
+	case SessionRevokedMsg:
+		return m, m.fetchSessionsCmd()
+
+	case SessionsErrMsg:
+		m.err = msg.Err
+		m.loading = false
+		return m, nil
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// View renders the sessions view
+func (m SessionsModel) View() string {
+	if m.loading {
+		return m.renderLoading()
+	}
+
+	if m.err != nil {
+		return m.renderError()
+	}
+
+	if m.showConfirm {
+		return m.renderContent() + "\n\n" + m.confirmDialog.View()
+	}
+
+	return m.renderContent()
+}
+
+// renderLoading renders the loading state
+func (m SessionsModel) renderLoading() string {
+	style := lipgloss.NewStyle().
+		Foreground(theme().Primary).
+		Bold(true)
+
+	return style.Render("Loading sessions...")
+}
+
+// renderError renders the error state
+func (m SessionsModel) renderError() string {
+	errorStyle := lipgloss.NewStyle().
+		Foreground(theme().Error).
+		Bold(true)
+
+	return errorStyle.Render("Error: " + m.err.Error())
+}
+
+// renderContent renders the session list
+func (m SessionsModel) renderContent() string {
+	// Define styles
+	titleStyle := lipgloss.NewStyle().
+		Foreground(theme().Secondary).
+		Bold(true).
+		MarginBottom(1)
+
+	deviceStyle := lipgloss.NewStyle().
+		Foreground(theme().Foreground)
+
+	selectedStyle := lipgloss.NewStyle().
+		Foreground(theme().Background).
+		Background(theme().Primary).
+		Bold(true)
+
+	mutedStyle := lipgloss.NewStyle().
+		Foreground(theme().Muted).
+		Faint(true)
+
+	hintStyle := lipgloss.NewStyle().
+		Foreground(theme().Muted).
+		Faint(true).
+		MarginTop(1)
+
+	var content string
+
+	content += titleStyle.Render(fmt.Sprintf("ACTIVE SESSIONS (%d)", len(m.sessions))) + "\n\n"
+
+	if len(m.sessions) == 0 {
+		content += mutedStyle.Render("(no active sessions)")
+		content += "\n\n"
+		content += hintStyle.Render("r:refresh ESC:back ?:help")
+		return content
+	}
+
+	// Leave room for the IP and age after the device name
+	maxDevice := m.width - 35
+	if maxDevice < 20 {
+		maxDevice = 20
+	}
+
+	for i, session := range m.sessions {
+		device := session.UserAgent
+		if device == "" {
+			device = "unknown device"
+		}
+		ip := session.IPAddress
+		if ip == "" {
+			ip = "unknown IP"
+		}
+
+		device = truncateText(device, maxDevice)
+		info := fmt.Sprintf(" · %s · %s", ip, formatTimeAgo(session.CreatedAt))
+
+		if i == m.selectedIndex {
+			content += selectedStyle.Render("→ " + device + info)
+		} else {
+			content += "  " + deviceStyle.Render(device) + mutedStyle.Render(info)
+		}
+
+		content += "\n"
+	}
+
+	// Hints
+	content += "\n" + hintStyle.Render("j/k:navigate d:revoke r:refresh ESC:back ?:help")
+
+	return content
+}
+
+// Message types for the sessions view
+
+type SessionsFetchedMsg struct {
+	Sessions []*model.RefreshToken
+}
+
+type SessionRevokedMsg struct {
+	SessionID uuid.UUID
+}
+
+type SessionsErrMsg struct {
+	Err error
+}
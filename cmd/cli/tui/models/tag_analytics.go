@@ -0,0 +1,302 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/momokii/go-cli-notes/cmd/cli/client"
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+const (
+	// tagAnalyticsMonths is how many months of tag use the view charts
+	tagAnalyticsMonths = 6
+	// tagAnalyticsPairs is how many pairs of tags used together it lists
+	tagAnalyticsPairs = 10
+)
+
+// TagAnalyticsModel is the model for the tag analytics view
+// Tags are grouped by trend, growing first; the selected one opens its notes.
+type TagAnalyticsModel struct {
+	client        *client.APIClient
+	authState     *client.AuthState
+	analytics     *model.TagAnalytics
+	tags          []*model.TagStats // In display order: growing, steady, stale
+	loading       bool
+	err           error
+	selectedIndex int
+	width         int
+	height        int
+}
+
+// NewTagAnalyticsModel creates a new tag analytics model
+func NewTagAnalyticsModel(apiClient *client.APIClient, authState *client.AuthState) TagAnalyticsModel {
+	return TagAnalyticsModel{
+		client:    apiClient,
+		authState: authState,
+		loading:   true,
+		width:     80,
+		height:    24,
+	}
+}
+
+// Init initializes the tag analytics model
+func (m TagAnalyticsModel) Init() tea.Cmd {
+	return m.fetchAnalyticsCmd()
+}
+
+// fetchAnalyticsCmd returns a command that fetches the tag analytics
+func (m TagAnalyticsModel) fetchAnalyticsCmd() tea.Cmd {
+	return func() tea.Msg {
+		analytics, err := m.client.GetTagAnalytics(tagAnalyticsMonths, tagAnalyticsPairs)
+		if err != nil {
+			return TagAnalyticsErrMsg{Err: err}
+		}
+		return TagAnalyticsFetchedMsg{Analytics: analytics}
+	}
+}
+
+// tagTrendOrder is the order the trend sections are shown in
+var tagTrendOrder = []model.TagTrend{model.TagGrowing, model.TagSteady, model.TagStale}
+
+// groupByTrend orders tags by trend section, keeping the most used first within each
+func groupByTrend(tags []*model.TagStats) []*model.TagStats {
+	grouped := make([]*model.TagStats, 0, len(tags))
+	for _, trend := range tagTrendOrder {
+		for _, tag := range tags {
+			if tag.Trend == trend {
+				grouped = append(grouped, tag)
+			}
+		}
+	}
+	return grouped
+}
+
+// Update handles messages for the tag analytics model
+func (m TagAnalyticsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c":
+			return m, tea.Quit
+		case "?":
+			return m, func() tea.Msg {
+				return ShowHelpMsg{}
+			}
+		case "esc":
+			return m, func() tea.Msg {
+				return ShowDashboardMsg{}
+			}
+		case "j", "down":
+			if m.selectedIndex < len(m.tags)-1 {
+				m.selectedIndex++
+			}
+		case "k", "up":
+			if m.selectedIndex > 0 {
+				m.selectedIndex--
+			}
+		case "enter":
+			// Open the notes of the selected tag
+			if m.selectedIndex < len(m.tags) {
+				tag := m.tags[m.selectedIndex].Tag
+				return m, func() tea.Msg {
+					return FilterNotesByTagMsg{TagID: tag.ID, TagName: tag.Name}
+				}
+			}
+		case "r":
+			// Refresh
+			m.loading = true
+			m.err = nil
+			return m, m.fetchAnalyticsCmd()
+		}
+
+	case TagAnalyticsFetchedMsg:
+		m.analytics = msg.Analytics
+		m.tags = groupByTrend(msg.Analytics.Tags)
+		m.loading = false
+		if m.selectedIndex >= len(m.tags) {
+			m.selectedIndex = len(m.tags) - 1
+		}
+		if m.selectedIndex < 0 {
+			m.selectedIndex = 0
+		}
+		return m, nil
+
+	case TagAnalyticsErrMsg:
+		m.err = msg.Err
+		m.loading = false
+		return m, nil
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// View renders the tag analytics view
+func (m TagAnalyticsModel) View() string {
+	if m.loading {
+		return m.renderLoading()
+	}
+
+	if m.err != nil {
+		return m.renderError()
+	}
+
+	return m.renderContent()
+}
+
+// renderLoading renders the loading state
+func (m TagAnalyticsModel) renderLoading() string {
+	style := lipgloss.NewStyle().
+		Foreground(theme().Primary).
+		Bold(true)
+
+	return style.Render("Loading tag analytics...")
+}
+
+// renderError renders the error state
+func (m TagAnalyticsModel) renderError() string {
+	errorStyle := lipgloss.NewStyle().
+		Foreground(theme().Error).
+		Bold(true)
+
+	return errorStyle.Render("Error: " + m.err.Error())
+}
+
+// renderContent renders the tags by trend with their monthly use, then the tags used together
+// Lines scroll to keep the selected tag in sight.
+func (m TagAnalyticsModel) renderContent() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(theme().Secondary).
+		Bold(true).
+		MarginBottom(1)
+
+	sectionStyle := lipgloss.NewStyle().
+		Foreground(theme().Accent).
+		Bold(true)
+
+	tagStyle := lipgloss.NewStyle().
+		Foreground(theme().Foreground)
+
+	selectedStyle := lipgloss.NewStyle().
+		Foreground(theme().Background).
+		Background(theme().Primary).
+		Bold(true)
+
+	sparkStyle := lipgloss.NewStyle().
+		Foreground(theme().Primary)
+
+	mutedStyle := lipgloss.NewStyle().
+		Foreground(theme().Muted).
+		Faint(true)
+
+	hintStyle := lipgloss.NewStyle().
+		Foreground(theme().Muted).
+		Faint(true).
+		MarginTop(1)
+
+	var content string
+
+	title := "TAG ANALYTICS"
+	if months := m.analytics.Months; len(months) > 0 {
+		title += fmt.Sprintf(" (%s to %s)", months[0], months[len(months)-1])
+	}
+	content += titleStyle.Render(title) + "\n\n"
+
+	if len(m.tags) == 0 {
+		content += mutedStyle.Render("(no tags yet - press t to create one)")
+		content += "\n\n"
+		content += hintStyle.Render("r:refresh ESC:back ?:help")
+		return content
+	}
+
+	// Tag names share a column, sized for the longest one that fits
+	nameWidth := 12
+	for _, usage := range m.tags {
+		nameWidth = max(nameWidth, lipgloss.Width(usage.Tag.Name))
+	}
+	nameWidth = min(nameWidth, max(m.width/3, 12))
+
+	var lines []string
+	selectedLine := 0
+	index := 0
+	for _, trend := range tagTrendOrder {
+		start := index
+		for index < len(m.tags) && m.tags[index].Trend == trend {
+			index++
+		}
+		if index == start {
+			continue
+		}
+
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, sectionStyle.Render(fmt.Sprintf("%s (%d)", strings.ToUpper(string(trend)), index-start)))
+
+		for i := start; i < index; i++ {
+			usage := m.tags[i]
+			name := fmt.Sprintf("%-*s", nameWidth, truncateText(usage.Tag.Name, nameWidth))
+			count := fmt.Sprintf(" %4d notes ", usage.NoteCount)
+			info := " " + tagLastUsed(usage)
+
+			if i == m.selectedIndex {
+				selectedLine = len(lines)
+				lines = append(lines, selectedStyle.Render("→ "+name+count)+" "+sparkStyle.Render(sparkline(usage.Monthly))+mutedStyle.Render(info))
+			} else {
+				lines = append(lines, "  "+tagStyle.Render(name)+mutedStyle.Render(count)+" "+sparkStyle.Render(sparkline(usage.Monthly))+mutedStyle.Render(info))
+			}
+		}
+	}
+
+	lines = append(lines, "", sectionStyle.Render("USED TOGETHER"))
+	if len(m.analytics.Pairs) == 0 {
+		lines = append(lines, mutedStyle.Render("  (no two tags share more than one note)"))
+	}
+	for _, pair := range m.analytics.Pairs {
+		names := truncateText(pair.TagA.Name+" + "+pair.TagB.Name, max(m.width-24, 20))
+		lines = append(lines, "  "+tagStyle.Render(names)+mutedStyle.Render(fmt.Sprintf(" · %d notes · %.0f%% overlap", pair.NoteCount, pair.Overlap*100)))
+	}
+
+	// Title, hint and the status bar take the rest of the screen
+	visible := max(m.height-8, 5)
+	offset := 0
+	if selectedLine >= visible {
+		offset = selectedLine - visible + 1
+	}
+	if m.selectedIndex == len(m.tags)-1 {
+		// The last tag scrolls the pairs below it into sight
+		offset = max(offset, min(len(lines)-visible, selectedLine))
+	}
+	end := min(offset+visible, len(lines))
+	content += strings.Join(lines[offset:end], "\n") + "\n"
+
+	// Hints
+	content += "\n" + hintStyle.Render("j/k:navigate enter:notes with tag r:refresh ESC:back ?:help")
+
+	return content
+}
+
+// tagLastUsed describes when a tag was last put on a note
+func tagLastUsed(usage *model.TagStats) string {
+	if usage.LastTaggedAt == nil {
+		return "never used"
+	}
+	return "last tagged " + formatTimeAgo(*usage.LastTaggedAt)
+}
+
+// Message types for the tag analytics view
+
+type TagAnalyticsFetchedMsg struct {
+	Analytics *model.TagAnalytics
+}
+
+type TagAnalyticsErrMsg struct {
+	Err error
+}
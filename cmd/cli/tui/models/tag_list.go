@@ -2,6 +2,7 @@ package models
 
 import (
 	"fmt"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -9,6 +10,7 @@ import (
 	"github.com/momokii/go-cli-notes/cmd/cli/client"
 	"github.com/momokii/go-cli-notes/cmd/cli/tui/components"
 	"github.com/momokii/go-cli-notes/internal/model"
+	"github.com/momokii/go-cli-notes/internal/util"
 )
 
 // TagListModel is the model for the tag list view
@@ -16,6 +18,7 @@ type TagListModel struct {
 	client         *client.APIClient
 	authState      *client.AuthState
 	tags           []*model.TagWithCount
+	tree           []util.TagTreeEntry // Hierarchy position of each tag, parallel to tags
 	loading        bool
 	err            error
 	selectedIndex  int
@@ -33,7 +36,7 @@ type TagListModel struct {
 // NewTagListModel creates a new tag list model
 func NewTagListModel(apiClient *client.APIClient, authState *client.AuthState) TagListModel {
 	createForm := components.NewTextInput()
-	createForm.SetPlaceholder("Tag name...")
+	createForm.SetPlaceholder("Tag name (use parent/child to nest)...")
 	createForm.SetWidth(30)
 
 	editForm := components.NewTextInput()
@@ -66,21 +69,26 @@ func (m TagListModel) fetchTagsCmd() tea.Cmd {
 			return TagListErrMsg{err}
 		}
 
+		// Order tags as a tree so children are listed under their parent
+		tree := util.BuildTagTree(tags)
+
 		// Convert to TagWithCount (note count will be 0 for basic Tag)
 		// If API doesn't return counts, we'll display tags without counts
 		var tagsWithCount []*model.TagWithCount
-		for _, tag := range tags {
+		for _, entry := range tree {
+			tag := entry.Tag
 			tagsWithCount = append(tagsWithCount, &model.TagWithCount{
 				ID:        tag.ID,
 				UserID:    tag.UserID,
 				Name:      tag.Name,
 				Color:     tag.Color,
+				ParentID:  tag.ParentID,
 				CreatedAt: tag.CreatedAt,
 				NoteCount: 0, // Would need separate API call to get counts
 			})
 		}
 
-		return TagsFetchedMsg{Tags: tagsWithCount}
+		return TagsFetchedMsg{Tags: tagsWithCount, Tree: tree}
 	}
 }
 
@@ -191,6 +199,7 @@ func (m TagListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case TagsFetchedMsg:
 		m.tags = msg.Tags
+		m.tree = msg.Tree
 		m.loading = false
 		if len(m.tags) > 0 && m.selectedIndex >= len(m.tags) {
 			m.selectedIndex = len(m.tags) - 1
@@ -297,7 +306,7 @@ func (m TagListModel) View() string {
 // renderLoading renders the loading state
 func (m TagListModel) renderLoading() string {
 	style := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#89b4fa")). // Blue
+		Foreground(theme().Primary).
 		Bold(true)
 
 	return style.Render("Loading tags...")
@@ -306,7 +315,7 @@ func (m TagListModel) renderLoading() string {
 // renderError renders the error state
 func (m TagListModel) renderError() string {
 	errorStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#f38ba8")). // Red
+		Foreground(theme().Error).
 		Bold(true)
 
 	return errorStyle.Render("Error: " + m.err.Error())
@@ -316,24 +325,24 @@ func (m TagListModel) renderError() string {
 func (m TagListModel) renderList() string {
 	// Define styles
 	titleStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#fab387")). // Orange
+		Foreground(theme().Secondary).
 		Bold(true).
 		MarginBottom(1)
 
 	tagStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#cdd6f4")) // Light text
+		Foreground(theme().Foreground)
 
 	selectedStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#1e1e2e")). // Dark
-		Background(lipgloss.Color("#89b4fa")). // Blue
+		Foreground(theme().Background).
+		Background(theme().Primary).
 		Bold(true)
 
 	mutedStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#6c7086")). // Gray
+		Foreground(theme().Muted).
 		Faint(true)
 
 	hintStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#6c7086")). // Gray
+		Foreground(theme().Muted).
 		Faint(true).
 		MarginTop(1)
 
@@ -349,16 +358,17 @@ func (m TagListModel) renderList() string {
 		return content
 	}
 
-	// Tags list
+	// Tags list, indented by hierarchy depth
 	for i, tag := range m.tags {
+		name := m.treeLabel(i, tag.Name)
 		var line string
 		if i == m.selectedIndex {
-			line = selectedStyle.Render("→ " + tag.Name)
+			line = selectedStyle.Render("→ " + name)
 			if tag.NoteCount > 0 {
 				line += " (" + formatCount(tag.NoteCount) + ")"
 			}
 		} else {
-			line = tagStyle.Render("  "+tag.Name)
+			line = tagStyle.Render("  "+name)
 			if tag.NoteCount > 0 {
 				line += mutedStyle.Render(" (" + formatCount(tag.NoteCount) + ")")
 			}
@@ -372,14 +382,27 @@ func (m TagListModel) renderList() string {
 	return content
 }
 
+// treeLabel returns the display label of the tag at index i
+// Nested tags show only their last segment, indented under their parent.
+func (m TagListModel) treeLabel(i int, name string) string {
+	if i >= len(m.tree) || m.tree[i].Depth == 0 {
+		return name
+	}
+	connector := "├─ "
+	if m.tree[i].Last {
+		connector = "└─ "
+	}
+	return strings.Repeat("   ", m.tree[i].Depth-1) + connector + util.TagLeafName(name)
+}
+
 // renderCreateForm renders the create form
 func (m TagListModel) renderCreateForm() string {
 	formStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#89b4fa")). // Blue
+		Foreground(theme().Primary).
 		Bold(true)
 
 	hintStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#6c7086")). // Gray
+		Foreground(theme().Muted).
 		Faint(true)
 
 	var content string
@@ -394,11 +417,11 @@ func (m TagListModel) renderCreateForm() string {
 // renderEditForm renders the edit form
 func (m TagListModel) renderEditForm() string {
 	formStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#89b4fa")). // Blue
+		Foreground(theme().Primary).
 		Bold(true)
 
 	hintStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#6c7086")). // Gray
+		Foreground(theme().Muted).
 		Faint(true)
 
 	var content string
@@ -460,6 +483,7 @@ func (m TagListModel) BlurInput() TagListModel {
 
 type TagsFetchedMsg struct {
 	Tags []*model.TagWithCount
+	Tree []util.TagTreeEntry
 }
 
 type TagCreatedMsg struct {
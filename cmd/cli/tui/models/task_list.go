@@ -0,0 +1,287 @@
+package models
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/momokii/go-cli-notes/cmd/cli/client"
+	"github.com/momokii/go-cli-notes/cmd/cli/tui/components"
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+// taskStatusCycle is the order the status filter cycles through with 'f'
+var taskStatusCycle = []model.TaskStatus{model.TaskStatusOpen, model.TaskStatusDone, model.TaskStatusAll}
+
+// TaskListModel is the model for the tasks view
+type TaskListModel struct {
+	client        *client.APIClient
+	authState     *client.AuthState
+	tasks         []*model.Task
+	status        model.TaskStatus
+	loading       bool
+	err           error
+	selectedIndex int
+	paginator     components.Paginator
+	width         int
+	height        int
+}
+
+// NewTaskListModel creates a new task list model showing open tasks
+func NewTaskListModel(apiClient *client.APIClient, authState *client.AuthState) TaskListModel {
+	paginator := components.NewPaginator()
+	paginator.SetPerPage(20)
+
+	return TaskListModel{
+		client:    apiClient,
+		authState: authState,
+		status:    model.TaskStatusOpen,
+		loading:   true,
+		paginator: paginator,
+		width:     80,
+		height:    24,
+	}
+}
+
+// Init initializes the task list model
+func (m TaskListModel) Init() tea.Cmd {
+	return m.fetchTasksCmd()
+}
+
+// fetchTasksCmd returns a command that fetches tasks for the current status filter
+func (m TaskListModel) fetchTasksCmd() tea.Cmd {
+	status := m.status
+	return func() tea.Msg {
+		tasks, err := m.client.GetTasks(string(status))
+		if err != nil {
+			return TasksErrMsg{Err: err}
+		}
+		return TasksFetchedMsg{Tasks: tasks}
+	}
+}
+
+// nextStatus returns the filter that follows the current one
+func (m TaskListModel) nextStatus() model.TaskStatus {
+	for i, s := range taskStatusCycle {
+		if s == m.status {
+			return taskStatusCycle[(i+1)%len(taskStatusCycle)]
+		}
+	}
+	return model.TaskStatusOpen
+}
+
+// Update handles messages for the task list model
+func (m TaskListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c":
+			return m, tea.Quit
+		case "?":
+			return m, func() tea.Msg {
+				return ShowHelpMsg{}
+			}
+		case "esc":
+			return m, func() tea.Msg {
+				return ShowDashboardMsg{}
+			}
+		case "j", "down":
+			_, endIdx := m.paginator.ItemsOnPage(len(m.tasks))
+			if m.selectedIndex < endIdx-1 {
+				m.selectedIndex++
+			}
+		case "k", "up":
+			startIdx, _ := m.paginator.ItemsOnPage(len(m.tasks))
+			if m.selectedIndex > startIdx {
+				m.selectedIndex--
+			}
+		case "ctrl+n", "right":
+			// Next page
+			if m.paginator.CanGoNext() {
+				m.paginator.NextPage()
+				startIdx, _ := m.paginator.ItemsOnPage(len(m.tasks))
+				m.selectedIndex = startIdx
+			}
+		case "ctrl+p", "left":
+			// Previous page
+			if m.paginator.CanGoPrev() {
+				m.paginator.PrevPage()
+				startIdx, _ := m.paginator.ItemsOnPage(len(m.tasks))
+				m.selectedIndex = startIdx
+			}
+		case "f":
+			// Cycle status filter: open -> done -> all
+			m.status = m.nextStatus()
+			m.selectedIndex = 0
+			m.loading = true
+			m.err = nil
+			return m, m.fetchTasksCmd()
+		case "r":
+			// Refresh
+			m.loading = true
+			m.err = nil
+			return m, m.fetchTasksCmd()
+		case "enter":
+			// Open the note containing this task
+			if len(m.tasks) > 0 && m.selectedIndex >= 0 && m.selectedIndex < len(m.tasks) {
+				task := m.tasks[m.selectedIndex]
+				return m, func() tea.Msg {
+					return OpenNoteMsg{NoteID: task.NoteID}
+				}
+			}
+		}
+
+	case TasksFetchedMsg:
+		m.tasks = msg.Tasks
+		m.loading = false
+		// Keep the selection across live refreshes
+		if m.selectedIndex >= len(msg.Tasks) {
+			m.selectedIndex = 0
+		}
+		m.paginator.SetTotalItems(len(msg.Tasks))
+		return m, nil
+
+	case TasksErrMsg:
+		m.err = msg.Err
+		m.loading = false
+		return m, nil
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+	}
+
+	// Update paginator
+	_ = m.paginator.Update(msg)
+
+	return m, nil
+}
+
+// View renders the task list view
+func (m TaskListModel) View() string {
+	if m.loading {
+		return m.renderLoading()
+	}
+
+	if m.err != nil {
+		return m.renderError()
+	}
+
+	return m.renderContent()
+}
+
+// renderLoading renders the loading state
+func (m TaskListModel) renderLoading() string {
+	style := lipgloss.NewStyle().
+		Foreground(theme().Primary).
+		Bold(true)
+
+	return style.Render("Loading tasks...")
+}
+
+// renderError renders the error state
+func (m TaskListModel) renderError() string {
+	errorStyle := lipgloss.NewStyle().
+		Foreground(theme().Error).
+		Bold(true)
+
+	return errorStyle.Render("Error: " + m.err.Error())
+}
+
+// renderContent renders the task list content
+func (m TaskListModel) renderContent() string {
+	// Define styles
+	titleStyle := lipgloss.NewStyle().
+		Foreground(theme().Secondary).
+		Bold(true).
+		MarginBottom(1)
+
+	taskStyle := lipgloss.NewStyle().
+		Foreground(theme().Foreground)
+
+	doneStyle := lipgloss.NewStyle().
+		Foreground(theme().Muted).
+		Strikethrough(true)
+
+	selectedStyle := lipgloss.NewStyle().
+		Foreground(theme().Background).
+		Background(theme().Primary).
+		Bold(true)
+
+	noteStyle := lipgloss.NewStyle().
+		Foreground(theme().Special).
+		Faint(true)
+
+	mutedStyle := lipgloss.NewStyle().
+		Foreground(theme().Muted).
+		Faint(true)
+
+	hintStyle := lipgloss.NewStyle().
+		Foreground(theme().Muted).
+		Faint(true).
+		MarginTop(1)
+
+	var content string
+
+	// Title with current filter
+	content += titleStyle.Render(fmt.Sprintf("TASKS (%s)", m.status)) + "\n\n"
+
+	if len(m.tasks) == 0 {
+		content += mutedStyle.Render("(no tasks - add \"- [ ] item\" lines to a note)")
+		content += "\n\n"
+		content += hintStyle.Render("f:filter ESC:back ?:help")
+		return content
+	}
+
+	// Get current page range
+	startIdx, endIdx := m.paginator.ItemsOnPage(len(m.tasks))
+
+	// Leave room for the note title after the task text
+	maxContent := m.width - 30
+	if maxContent < 20 {
+		maxContent = 20
+	}
+
+	for i := startIdx; i < endIdx; i++ {
+		task := m.tasks[i]
+
+		box := "☐ "
+		if task.Completed {
+			box = "☑ "
+		}
+		text := truncateText(task.Content, maxContent)
+		noteInfo := " · " + truncateText(task.NoteTitle, 25)
+
+		if i == m.selectedIndex {
+			content += selectedStyle.Render("→ " + box + text + noteInfo)
+		} else if task.Completed {
+			content += "  " + box + doneStyle.Render(text) + noteStyle.Render(noteInfo)
+		} else {
+			content += "  " + taskStyle.Render(box+text) + noteStyle.Render(noteInfo)
+		}
+
+		content += "\n"
+	}
+
+	// Paginator
+	perPage := 20
+	if len(m.tasks) > perPage {
+		content += "\n" + m.paginator.View()
+	}
+
+	// Hints
+	content += "\n" + hintStyle.Render("j/k:navigate Enter:open note f:filter r:refresh Ctrl+N/P:page ESC:back ?:help")
+
+	return content
+}
+
+// Message types for the task list
+
+type TasksFetchedMsg struct {
+	Tasks []*model.Task
+}
+
+type TasksErrMsg struct {
+	Err error
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/momokii/go-cli-notes/cmd/cli/tui/components"
+)
+
+// theme returns the colors of the current TUI theme
+func theme() components.Theme {
+	return components.CurrentTheme()
+}
+
+// paletteCommand is a command of the Ctrl+K command palette
+type paletteCommand struct {
+	Label string
+	Msg   tea.Msg // Sent when the command is run
+}
+
+// paletteCommands lists the commands of the command palette
+// There is one per theme, so themes can be switched at runtime.
+func paletteCommands() []paletteCommand {
+	var commands []paletteCommand
+	for _, name := range components.ThemeNames() {
+		label := "Theme: " + name
+		if name == theme().Name {
+			label += " (current)"
+		}
+		commands = append(commands, paletteCommand{Label: label, Msg: ThemeSelectedMsg{Name: name}})
+	}
+	return commands
+}
+
+// ThemeSelectedMsg asks to switch the TUI to a theme
+type ThemeSelectedMsg struct {
+	Name string
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/momokii/go-cli-notes/cmd/cli/client"
+	"github.com/momokii/go-cli-notes/cmd/cli/clipboard"
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+// yankHint is shown after y while the key picking what to copy is awaited
+const yankHint = "copy: y/c:content t:title l:[[link]] i:id ESC:cancel"
+
+// yankField returns the part of the note copied by the key pressed after y
+func yankField(key string) (clipboard.Field, bool) {
+	switch key {
+	case "y", "c":
+		return clipboard.FieldContent, true
+	case "t":
+		return clipboard.FieldTitle, true
+	case "l":
+		return clipboard.FieldLink, true
+	case "i":
+		return clipboard.FieldID, true
+	}
+	return "", false
+}
+
+// copyNoteCmd returns a command that copies a part of the note to the clipboard
+// Encrypted content is decrypted on a copy of the note, leaving the caller's note as it was.
+func copyNoteCmd(apiClient *client.APIClient, note *model.Note, field clipboard.Field) tea.Cmd {
+	n := *note
+	return func() tea.Msg {
+		if field == clipboard.FieldContent {
+			if err := apiClient.DecryptNote(&n); err != nil {
+				return NoteCopiedMsg{Title: n.Title, Field: field, Err: err}
+			}
+		}
+		err := clipboard.Copy(clipboard.NoteText(&n, field))
+		return NoteCopiedMsg{Title: n.Title, Field: field, Err: err}
+	}
+}
+
+// NoteCopiedMsg reports a part of a note copied to the clipboard
+type NoteCopiedMsg struct {
+	Title string
+	Field clipboard.Field
+	Err   error
+}
@@ -2,210 +2,276 @@ package tui
 
 import (
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/momokii/go-cli-notes/cmd/cli/tui/components"
 )
 
-// Color palette for dark theme
+// Colors of the current theme, set by applyTheme
 var (
-	// Base colors
-	colorForeground  = lipgloss.Color("#cdd6f4")  // Light text
-	colorBackground  = lipgloss.Color("#1e1e2e")  // Dark background
-	colorPrimary     = lipgloss.Color("#89b4fa")  // Blue
-	colorSecondary   = lipgloss.Color("#fab387")  // Orange
-	colorAccent      = lipgloss.Color("#a6e3a1")  // Green
-	colorError       = lipgloss.Color("#f38ba8")  // Red
-	colorWarning     = lipgloss.Color("#f9e2af")  // Yellow
-	colorMuted       = lipgloss.Color("#6c7086")  // Gray
-	colorBorder      = lipgloss.Color("#45475a")  // Dark gray border
-	colorSelected    = lipgloss.Color("#313244")  // Selected background
-	colorHeader      = lipgloss.Color("#181825")  // Header background
+	colorForeground lipgloss.Color
+	colorBackground lipgloss.Color
+	colorPrimary    lipgloss.Color
+	colorSecondary  lipgloss.Color
+	colorAccent     lipgloss.Color
+	colorError      lipgloss.Color
+	colorWarning    lipgloss.Color
+	colorMuted      lipgloss.Color
+	colorBorder     lipgloss.Color
+	colorSelected   lipgloss.Color
+	colorHeader     lipgloss.Color
 )
 
 // Styles for various UI elements
 
 // BaseStyle is the base style for all text
-var BaseStyle = lipgloss.NewStyle().
-	Foreground(colorForeground).
-	Background(colorBackground)
+var BaseStyle lipgloss.Style
 
 // PrimaryStyle is for primary actions and emphasis
-var PrimaryStyle = lipgloss.NewStyle().
-	Foreground(colorBackground).
-	Background(colorPrimary).
-	Bold(true)
+var PrimaryStyle lipgloss.Style
 
 // SecondaryStyle is for secondary emphasis
-var SecondaryStyle = lipgloss.NewStyle().
-	Foreground(colorSecondary).
-	Bold(true)
+var SecondaryStyle lipgloss.Style
 
 // AccentStyle is for success states and positive feedback
-var AccentStyle = lipgloss.NewStyle().
-	Foreground(colorAccent).
-	Bold(true)
+var AccentStyle lipgloss.Style
 
 // ErrorStyle is for errors
-var ErrorStyle = lipgloss.NewStyle().
-	Foreground(colorError).
-	Bold(true)
+var ErrorStyle lipgloss.Style
 
 // WarningStyle is for warnings
-var WarningStyle = lipgloss.NewStyle().
-	Foreground(colorWarning).
-	Bold(true)
+var WarningStyle lipgloss.Style
 
 // MutedStyle is for secondary text
-var MutedStyle = lipgloss.NewStyle().
-	Foreground(colorMuted)
+var MutedStyle lipgloss.Style
 
 // BorderStyle is for borders
-var BorderStyle = lipgloss.NewStyle().
-	Foreground(colorBorder)
+var BorderStyle lipgloss.Style
 
 // SelectedStyle is for selected items
-var SelectedStyle = lipgloss.NewStyle().
-	Foreground(colorPrimary).
-	Background(colorSelected).
-	Bold(true)
+var SelectedStyle lipgloss.Style
 
 // HeaderStyle is for the top header bar
-var HeaderStyle = lipgloss.NewStyle().
-	Foreground(colorForeground).
-	Background(colorHeader).
-	Bold(true).
-	Padding(0, 1)
+var HeaderStyle lipgloss.Style
 
 // StatusBarStyle is for the bottom status bar
-var StatusBarStyle = lipgloss.NewStyle().
-	Foreground(colorMuted).
-	Background(colorSelected).
-	Padding(0, 1)
+var StatusBarStyle lipgloss.Style
 
 // KeyBindingStyle is for displaying key bindings
-var KeyBindingStyle = lipgloss.NewStyle().
-	Foreground(colorAccent).
-	Bold(true)
+var KeyBindingStyle lipgloss.Style
 
 // KeyDescStyle is for key binding descriptions
-var KeyDescStyle = lipgloss.NewStyle().
-	Foreground(colorMuted)
+var KeyDescStyle lipgloss.Style
 
 // TitleStyle is for titles and headings
-var TitleStyle = lipgloss.NewStyle().
-	Foreground(colorPrimary).
-	Bold(true).
-	MarginBottom(1)
+var TitleStyle lipgloss.Style
 
 // SubtitleStyle is for subtitles
-var SubtitleStyle = lipgloss.NewStyle().
-	Foreground(colorSecondary).
-	Bold(true).
-	MarginBottom(1)
+var SubtitleStyle lipgloss.Style
 
 // ItemStyle is for list items
-var ItemStyle = lipgloss.NewStyle().
-	Foreground(colorForeground).
-	Padding(0, 1)
+var ItemStyle lipgloss.Style
 
 // DimStyle is for dimmed text
-var DimStyle = lipgloss.NewStyle().
-	Foreground(colorMuted).
-	Faint(true)
+var DimStyle lipgloss.Style
 
 // BoxStyle is for drawing boxes/borders
-var BoxStyle = lipgloss.NewStyle().
-	Border(lipgloss.RoundedBorder()).
-	BorderForeground(colorBorder).
-	Padding(1)
+var BoxStyle lipgloss.Style
 
 // ModalStyle is for modal dialogs
-var ModalStyle = lipgloss.NewStyle().
-	Border(lipgloss.DoubleBorder()).
-	BorderForeground(colorPrimary).
-	Background(colorBackground).
-	Padding(2).
-	Width(60)
+var ModalStyle lipgloss.Style
 
 // HelpKeyStyle is for key names in help screen
-var HelpKeyStyle = lipgloss.NewStyle().
-	Foreground(colorAccent).
-	Bold(true).
-	Width(12)
+var HelpKeyStyle lipgloss.Style
 
 // HelpDescStyle is for key descriptions in help screen
-var HelpDescStyle = lipgloss.NewStyle().
-	Foreground(colorForeground)
+var HelpDescStyle lipgloss.Style
 
 // LinkStyle is for clickable links
-var LinkStyle = lipgloss.NewStyle().
-	Foreground(colorPrimary).
-	Underline(true)
+var LinkStyle lipgloss.Style
 
 // CodeStyle is for inline code
-var CodeStyle = lipgloss.NewStyle().
-	Foreground(colorAccent).
-	Background(colorSelected).
-	Padding(0, 1)
+var CodeStyle lipgloss.Style
 
 // MetadataStyle is for metadata (dates, counts, etc.)
-var MetadataStyle = lipgloss.NewStyle().
-	Foreground(colorMuted).
-	Faint(true)
+var MetadataStyle lipgloss.Style
 
 // TagStyle is for tag display
-var TagStyle = lipgloss.NewStyle().
-	Foreground(colorBackground).
-	Background(colorSecondary).
-	Bold(true).
-	Padding(0, 1)
+var TagStyle lipgloss.Style
 
 // ActiveTabStyle is for active tab in tabbed interfaces
-var ActiveTabStyle = lipgloss.NewStyle().
-	Foreground(colorPrimary).
-	Bold(true).
-	Underline(true)
+var ActiveTabStyle lipgloss.Style
 
 // InactiveTabStyle is for inactive tabs
-var InactiveTabStyle = lipgloss.NewStyle().
-	Foreground(colorMuted)
+var InactiveTabStyle lipgloss.Style
 
 // ScrollbarStyle is for custom scrollbars
-var ScrollbarStyle = lipgloss.NewStyle().
-	Foreground(colorMuted).
-	Faint(true)
+var ScrollbarStyle lipgloss.Style
 
 // HelpSectionStyle is for help screen sections
-var HelpSectionStyle = lipgloss.NewStyle().
-	Foreground(colorSecondary).
-	Bold(true).
-	MarginTop(1).
-	MarginBottom(0)
+var HelpSectionStyle lipgloss.Style
 
 // SeparatorStyle is for separators
-var SeparatorStyle = lipgloss.NewStyle().
-	Foreground(colorBorder).
-	SetString("─")
+var SeparatorStyle lipgloss.Style
 
 // SpinnerStyle is for loading spinners
-var SpinnerStyle = lipgloss.NewStyle().
-	Foreground(colorAccent)
+var SpinnerStyle lipgloss.Style
 
 // SuccessStyle is for success messages
-var SuccessStyle = lipgloss.NewStyle().
-	Foreground(colorAccent).
-	Bold(true)
+var SuccessStyle lipgloss.Style
 
 // InfoStyle is for informational messages
-var InfoStyle = lipgloss.NewStyle().
-	Foreground(colorPrimary)
+var InfoStyle lipgloss.Style
 
 // FatalStyle is for fatal errors
-var FatalStyle = lipgloss.NewStyle().
-	Foreground(colorError).
-	Background(colorSelected).
-	Bold(true).
-	Padding(1).
-	Width(80).
-	Align(lipgloss.Center)
+var FatalStyle lipgloss.Style
+
+// setTheme switches the TUI to the named theme, reporting whether it exists
+func setTheme(name string) bool {
+	if !components.SetTheme(name) {
+		return false
+	}
+	applyTheme()
+	return true
+}
+
+// applyTheme rebuilds the shared styles with the current theme
+// Views look up their own colors as they render, so they follow on the next frame.
+func applyTheme() {
+	theme := components.CurrentTheme()
+	colorForeground = theme.Foreground
+	colorBackground = theme.Background
+	colorPrimary = theme.Primary
+	colorSecondary = theme.Secondary
+	colorAccent = theme.Accent
+	colorError = theme.Error
+	colorWarning = theme.Warning
+	colorMuted = theme.Muted
+	colorBorder = theme.Border
+	colorSelected = theme.Selected
+	colorHeader = theme.Header
+
+	BaseStyle = lipgloss.NewStyle().
+		Foreground(colorForeground).
+		Background(colorBackground)
+	PrimaryStyle = lipgloss.NewStyle().
+		Foreground(colorBackground).
+		Background(colorPrimary).
+		Bold(true)
+	SecondaryStyle = lipgloss.NewStyle().
+		Foreground(colorSecondary).
+		Bold(true)
+	AccentStyle = lipgloss.NewStyle().
+		Foreground(colorAccent).
+		Bold(true)
+	ErrorStyle = lipgloss.NewStyle().
+		Foreground(colorError).
+		Bold(true)
+	WarningStyle = lipgloss.NewStyle().
+		Foreground(colorWarning).
+		Bold(true)
+	MutedStyle = lipgloss.NewStyle().
+		Foreground(colorMuted)
+	BorderStyle = lipgloss.NewStyle().
+		Foreground(colorBorder)
+	SelectedStyle = lipgloss.NewStyle().
+		Foreground(colorPrimary).
+		Background(colorSelected).
+		Bold(true)
+	HeaderStyle = lipgloss.NewStyle().
+		Foreground(colorForeground).
+		Background(colorHeader).
+		Bold(true).
+		Padding(0, 1)
+	StatusBarStyle = lipgloss.NewStyle().
+		Foreground(colorMuted).
+		Background(colorSelected).
+		Padding(0, 1)
+	KeyBindingStyle = lipgloss.NewStyle().
+		Foreground(colorAccent).
+		Bold(true)
+	KeyDescStyle = lipgloss.NewStyle().
+		Foreground(colorMuted)
+	TitleStyle = lipgloss.NewStyle().
+		Foreground(colorPrimary).
+		Bold(true).
+		MarginBottom(1)
+	SubtitleStyle = lipgloss.NewStyle().
+		Foreground(colorSecondary).
+		Bold(true).
+		MarginBottom(1)
+	ItemStyle = lipgloss.NewStyle().
+		Foreground(colorForeground).
+		Padding(0, 1)
+	DimStyle = lipgloss.NewStyle().
+		Foreground(colorMuted).
+		Faint(true)
+	BoxStyle = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colorBorder).
+		Padding(1)
+	ModalStyle = lipgloss.NewStyle().
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(colorPrimary).
+		Background(colorBackground).
+		Padding(2).
+		Width(60)
+	HelpKeyStyle = lipgloss.NewStyle().
+		Foreground(colorAccent).
+		Bold(true).
+		Width(12)
+	HelpDescStyle = lipgloss.NewStyle().
+		Foreground(colorForeground)
+	LinkStyle = lipgloss.NewStyle().
+		Foreground(colorPrimary).
+		Underline(true)
+	CodeStyle = lipgloss.NewStyle().
+		Foreground(colorAccent).
+		Background(colorSelected).
+		Padding(0, 1)
+	MetadataStyle = lipgloss.NewStyle().
+		Foreground(colorMuted).
+		Faint(true)
+	TagStyle = lipgloss.NewStyle().
+		Foreground(colorBackground).
+		Background(colorSecondary).
+		Bold(true).
+		Padding(0, 1)
+	ActiveTabStyle = lipgloss.NewStyle().
+		Foreground(colorPrimary).
+		Bold(true).
+		Underline(true)
+	InactiveTabStyle = lipgloss.NewStyle().
+		Foreground(colorMuted)
+	ScrollbarStyle = lipgloss.NewStyle().
+		Foreground(colorMuted).
+		Faint(true)
+	HelpSectionStyle = lipgloss.NewStyle().
+		Foreground(colorSecondary).
+		Bold(true).
+		MarginTop(1).
+		MarginBottom(0)
+	SeparatorStyle = lipgloss.NewStyle().
+		Foreground(colorBorder).
+		SetString("─")
+	SpinnerStyle = lipgloss.NewStyle().
+		Foreground(colorAccent)
+	SuccessStyle = lipgloss.NewStyle().
+		Foreground(colorAccent).
+		Bold(true)
+	InfoStyle = lipgloss.NewStyle().
+		Foreground(colorPrimary)
+	FatalStyle = lipgloss.NewStyle().
+		Foreground(colorError).
+		Background(colorSelected).
+		Bold(true).
+		Padding(1).
+		Width(80).
+		Align(lipgloss.Center)
+}
+
+func init() {
+	applyTheme()
+}
 
 // StyledKey returns a styled key binding string
 func StyledKey(key string) string {
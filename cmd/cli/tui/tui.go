@@ -3,22 +3,56 @@ package tui
 import (
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/momokii/go-cli-notes/cmd/cli/client"
+	"github.com/momokii/go-cli-notes/cmd/cli/tui/components"
+	"github.com/momokii/go-cli-notes/cmd/cli/tui/models"
 )
 
+// Layout holds the view layouts, see models.Layout
+type Layout = models.Layout
+
+// Config holds the local settings of the TUI
+type Config struct {
+	Theme       string             // dark, light, solarized or custom; empty uses the account's theme setting
+	ThemeColors map[string]string  // Colors of the custom theme, see components.SetCustomTheme
+	VimMode     bool               // Vim-style modal editing in the note editor
+	DraftSave   time.Duration      // How often the note editor saves a draft; zero uses models.DefaultDraftInterval
+	Layout      Layout             // View layouts from the last session
+	SaveLayout  func(Layout) error // Keeps layout changes for the next launch; nil doesn't keep them
+}
+
 // Run starts the TUI application
 // Returns an error if initialization fails or if the program exits with an error
-func Run(apiClient *client.APIClient, authState *client.AuthState) error {
+func Run(apiClient *client.APIClient, authState *client.AuthState, config Config) error {
 	// Validate session before starting TUI
 	if err := InitTUI(apiClient, authState); err != nil {
 		return fmt.Errorf("session validation failed: %w", err)
 	}
 
+	if err := loadTheme(apiClient, config); err != nil {
+		return err
+	}
+	components.SetVimMode(config.VimMode)
+
+	// Notes can still be written without drafts, so a missing drafts directory isn't fatal
+	drafts, err := models.NewDraftManager(config.DraftSave)
+	if err != nil {
+		fmt.Println(MutedStyle.Render("Drafts disabled: " + err.Error()))
+	}
+
+	// Notes can still be read without reading positions, they just open at the top
+	positions, err := models.NewReadingPositions()
+	if err != nil {
+		fmt.Println(MutedStyle.Render("Reading positions disabled: " + err.Error()))
+	}
+
 	// Create the main model
-	mainModel := NewMainModel(apiClient, authState)
+	mainModel := NewMainModel(apiClient, authState, config, drafts, positions)
 
 	// Create the Bubbletea program
 	p := tea.NewProgram(
@@ -35,6 +69,7 @@ func Run(apiClient *client.APIClient, authState *client.AuthState) error {
 
 	// Check if the session was valid on exit
 	if m, ok := finalModel.(MainModel); ok {
+		m.noteDetailModel.SaveReadingPosition()
 		if !m.IsSessionValidForTesting() {
 			fmt.Println("\n" + FatalStyle.Render("Session Expired"))
 			fmt.Println(MutedStyle.Render("Please run 'kg-cli login' to refresh your session"))
@@ -45,6 +80,28 @@ func Run(apiClient *client.APIClient, authState *client.AuthState) error {
 	return nil
 }
 
+// loadTheme switches to the configured theme
+// A theme named in the local config must exist; an unknown account setting falls back to dark.
+func loadTheme(apiClient *client.APIClient, config Config) error {
+	if len(config.ThemeColors) > 0 {
+		if err := components.SetCustomTheme(config.ThemeColors); err != nil {
+			return fmt.Errorf("custom theme: %w", err)
+		}
+	}
+
+	if config.Theme != "" {
+		if !setTheme(config.Theme) {
+			return fmt.Errorf("unknown theme %q (use %s)", config.Theme, strings.Join(components.ThemeNames(), ", "))
+		}
+		return nil
+	}
+
+	if !setTheme(apiClient.Settings().Theme) {
+		setTheme(components.DarkTheme.Name)
+	}
+	return nil
+}
+
 // CheckTerminalSize checks if the terminal is large enough for the TUI
 // Returns true if the terminal is sufficient size, false otherwise
 func CheckTerminalSize() (bool, int, int) {
@@ -75,7 +132,7 @@ func getTerminalSize() (int, int, error) {
 // ShowTerminalSizeWarning displays a warning if the terminal is too small
 func ShowTerminalSizeWarning(width, height int) {
 	warning := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("226")). // Yellow
+		Foreground(colorWarning).
 		Bold(true).
 		Render("⚠ Terminal Size Warning")
 
@@ -93,7 +150,7 @@ Press any key to continue anyway (some features may not work properly).`,
 
 	fmt.Println(warning)
 	fmt.Println(lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#cdd6f4")).
+		Foreground(colorForeground).
 		Render(content))
 }
 
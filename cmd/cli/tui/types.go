@@ -3,6 +3,7 @@ package tui
 
 import (
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/momokii/go-cli-notes/internal/model"
 )
 
 // View represents the different screens/views in the TUI
@@ -27,6 +28,20 @@ const (
 	ActivityView
 	// GraphView displays the knowledge graph
 	GraphView
+	// TasksView lists checkbox tasks collected from all notes
+	TasksView
+	// SessionsView lists devices signed in to the account
+	SessionsView
+	// BoardView lays out notes with a status in todo, doing and done columns
+	BoardView
+	// CalendarView shows a month grid of the days with a daily note
+	CalendarView
+	// PublishedView lists notes published at public links
+	PublishedView
+	// TagAnalyticsView shows which tags are growing, stale or used together
+	TagAnalyticsView
+	// ReviewView quizzes the user on flashcards due for review
+	ReviewView
 	// HelpView shows keyboard shortcuts and help
 	HelpView
 )
@@ -52,6 +67,20 @@ func (v View) String() string {
 		return "Activity"
 	case GraphView:
 		return "Knowledge Graph"
+	case TasksView:
+		return "Tasks"
+	case SessionsView:
+		return "Sessions"
+	case BoardView:
+		return "Board"
+	case CalendarView:
+		return "Calendar"
+	case PublishedView:
+		return "Published Notes"
+	case TagAnalyticsView:
+		return "Tag Analytics"
+	case ReviewView:
+		return "Review"
 	case HelpView:
 		return "Help"
 	default:
@@ -80,11 +109,35 @@ type sessionExpiringSoonMsg struct {
 	TimeRemaining string // Human-readable time remaining
 }
 
+// eventsConnectedMsg signals that the live update stream is open
+type eventsConnectedMsg struct {
+	Events <-chan *model.Event
+}
+
+// eventsDisconnectedMsg signals that the live update stream could not be opened or has ended
+type eventsDisconnectedMsg struct{}
+
+// eventsReconnectMsg signals that it is time to reopen the live update stream
+type eventsReconnectMsg struct{}
+
+// serverEventMsg carries a change pushed by the API
+type serverEventMsg struct {
+	Event *model.Event
+}
+
+// liveRefreshMsg signals that buffered server events should refresh the current view
+type liveRefreshMsg struct{}
+
 // errorMsg signals an error occurred
 type errorMsg struct {
 	Error error
 }
 
+// layoutSaveMsg signals that changed layouts are due to be saved
+type layoutSaveMsg struct {
+	id int
+}
+
 // clearErrorMsg signals to clear the current error
 type clearErrorMsg struct{}
 
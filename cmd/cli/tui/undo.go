@@ -0,0 +1,103 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/uuid"
+	"github.com/momokii/go-cli-notes/cmd/cli/tui/models"
+)
+
+// undoWindow is how long a destructive action can be undone with "u"
+const undoWindow = 10 * time.Second
+
+// undoAction reverts a destructive action
+type undoAction struct {
+	id      int
+	summary string  // What was done, shown in the status bar
+	run     tea.Cmd // Reverts the action, returning an undoneMsg
+}
+
+// undoneMsg reports the result of an undo
+type undoneMsg struct {
+	info    string
+	err     error
+	refresh tea.Msg // Sent through Update after a successful undo, to show the restored state
+}
+
+// undoExpiredMsg signals that an action can no longer be undone
+type undoExpiredMsg struct {
+	id int
+}
+
+// pushUndo makes an action undoable for the undo window
+// Returns the command that ends the window.
+func (m *MainModel) pushUndo(summary string, run tea.Cmd) tea.Cmd {
+	m.nextUndoID++
+	id := m.nextUndoID
+	m.undoStack = append(m.undoStack, undoAction{id: id, summary: summary, run: run})
+	m.showUndo()
+
+	return tea.Tick(undoWindow, func(time.Time) tea.Msg {
+		return undoExpiredMsg{id: id}
+	})
+}
+
+// popUndo takes the latest undoable action, returning nil when there is none
+func (m *MainModel) popUndo() tea.Cmd {
+	if len(m.undoStack) == 0 {
+		return nil
+	}
+	action := m.undoStack[len(m.undoStack)-1]
+	m.undoStack = m.undoStack[:len(m.undoStack)-1]
+	m.showUndo()
+	return action.run
+}
+
+// expireUndo drops an action whose undo window has passed
+func (m *MainModel) expireUndo(id int) {
+	for i, action := range m.undoStack {
+		if action.id == id {
+			m.undoStack = append(m.undoStack[:i], m.undoStack[i+1:]...)
+			break
+		}
+	}
+	m.showUndo()
+}
+
+// showUndo offers the latest undoable action in the status bar
+func (m *MainModel) showUndo() {
+	if len(m.undoStack) == 0 {
+		m.statusBar.SetAction("")
+		return
+	}
+	m.statusBar.SetAction(m.undoStack[len(m.undoStack)-1].summary + " · u:undo")
+}
+
+// restoreNoteCmd undoes a note deletion and opens the note again
+func (m MainModel) restoreNoteCmd(noteID uuid.UUID) tea.Cmd {
+	return func() tea.Msg {
+		note, err := m.client.RestoreNote(noteID)
+		if err != nil {
+			return undoneMsg{err: fmt.Errorf("restore note: %w", err)}
+		}
+		return undoneMsg{
+			info:    fmt.Sprintf("Restored %q", note.Title),
+			refresh: models.OpenNoteMsg{NoteID: note.ID},
+		}
+	}
+}
+
+// readdTagCmd undoes removing a tag from a note
+func (m MainModel) readdTagCmd(noteID, tagID uuid.UUID, tagName string) tea.Cmd {
+	return func() tea.Msg {
+		if err := m.client.AddTagToNote(noteID, tagID); err != nil {
+			return undoneMsg{err: fmt.Errorf("add tag back: %w", err)}
+		}
+		return undoneMsg{
+			info:    fmt.Sprintf("Added #%s back", tagName),
+			refresh: models.NoteTagAddedMsg{TagID: tagID},
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var twoFACmd = &cobra.Command{
+	Use:   "2fa",
+	Short: "Manage two-factor authentication",
+}
+
+// twoFASetupCmd enrolls the account in TOTP two-factor authentication
+var twoFASetupCmd = &cobra.Command{
+	Use:   "setup",
+	Short: "Enable two-factor authentication with an authenticator app",
+	Long: `Generate a TOTP secret, add it to an authenticator app (Google Authenticator,
+1Password, Authy, ...) and confirm with the 6-digit code it shows.
+
+Once enabled, 'kg-cli login' asks for a code after the password.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !authState.IsAuthenticated() {
+			return fmt.Errorf("not authenticated. Please run 'kg-cli login'")
+		}
+
+		setup, err := apiClient.SetupTOTP()
+		if err != nil {
+			return fmt.Errorf("start 2fa setup: %w", err)
+		}
+
+		fmt.Println("Add this account to your authenticator app:")
+		fmt.Printf("  Secret: %s\n", setup.Secret)
+		fmt.Printf("  URI:    %s\n", setup.URI)
+		fmt.Println()
+
+		var code string
+		fmt.Print("Enter the 6-digit code from the app: ")
+		fmt.Scanln(&code)
+
+		if err := apiClient.VerifyTOTP(strings.TrimSpace(code)); err != nil {
+			return fmt.Errorf("verify code: %w", err)
+		}
+
+		fmt.Println("Two-factor authentication enabled")
+		return nil
+	},
+}
+
+func init() {
+	twoFACmd.AddCommand(twoFASetupCmd)
+	rootCmd.AddCommand(twoFACmd)
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+var webhookCmd = &cobra.Command{
+	Use:   "webhook",
+	Short: "Send note and tag events to other services",
+}
+
+// webhookAddCmd registers a webhook
+var webhookAddCmd = &cobra.Command{
+	Use:   "add <url>",
+	Short: "Register a URL to receive events",
+	Long: `Register a URL that is sent a signed POST request for each event.
+
+Events: note.created, note.updated, note.deleted, tag.created, tag.updated, tag.deleted
+
+The payload's "text" field summarizes the event, so a Slack incoming webhook URL
+posts new notes to a channel as is. Other services can check the X-KG-Signature
+header, the HMAC-SHA256 of the body keyed by the secret printed here.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		events, _ := cmd.Flags().GetStringSlice("event")
+
+		hook, err := apiClient.CreateWebhook(args[0], events)
+		if err != nil {
+			return fmt.Errorf("create webhook: %w", err)
+		}
+
+		fmt.Println("Webhook registered successfully!")
+		fmt.Printf("ID: %s\n", hook.ID)
+		fmt.Printf("URL: %s\n", hook.URL)
+		fmt.Printf("Events: %s\n", strings.Join(hook.Events, ", "))
+		fmt.Printf("Secret: %s\n", hook.Secret)
+		fmt.Println("\nKeep the secret to verify signatures, it isn't shown again.")
+		return nil
+	},
+}
+
+// webhookListCmd lists the registered webhooks
+var webhookListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List registered webhooks",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		hooks, err := apiClient.ListWebhooks()
+		if err != nil {
+			return fmt.Errorf("list webhooks: %w", err)
+		}
+
+		if len(hooks) == 0 {
+			fmt.Println("No webhooks registered")
+			return nil
+		}
+
+		fmt.Printf("Found %d webhook(s):\n\n", len(hooks))
+		for _, hook := range hooks {
+			status := "active"
+			if !hook.Active {
+				status = "disabled"
+			}
+
+			fmt.Printf("ID: %s\n", hook.ID)
+			fmt.Printf("URL: %s\n", hook.URL)
+			fmt.Printf("Events: %s\n", strings.Join(hook.Events, ", "))
+			fmt.Printf("Status: %s\n", status)
+			fmt.Println("---")
+		}
+
+		return nil
+	},
+}
+
+// webhookRemoveCmd deletes a webhook
+var webhookRemoveCmd = &cobra.Command{
+	Use:   "remove <id>",
+	Short: "Delete a webhook and its delivery history",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := uuid.Parse(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid webhook ID: %w", err)
+		}
+
+		if err := apiClient.DeleteWebhook(id); err != nil {
+			return fmt.Errorf("delete webhook: %w", err)
+		}
+
+		fmt.Println("Webhook deleted")
+		return nil
+	},
+}
+
+// webhookEnableCmd and webhookDisableCmd switch a webhook on and off
+var webhookEnableCmd = &cobra.Command{
+	Use:   "enable <id>",
+	Short: "Resume sending events to a webhook",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setWebhookActive(args[0], true)
+	},
+}
+
+var webhookDisableCmd = &cobra.Command{
+	Use:   "disable <id>",
+	Short: "Stop sending events to a webhook, keeping it registered",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setWebhookActive(args[0], false)
+	},
+}
+
+// setWebhookActive enables or disables the webhook with the ID
+func setWebhookActive(idArg string, active bool) error {
+	id, err := uuid.Parse(idArg)
+	if err != nil {
+		return fmt.Errorf("invalid webhook ID: %w", err)
+	}
+
+	if _, err := apiClient.UpdateWebhook(id, &model.UpdateWebhookRequest{Active: &active}); err != nil {
+		return fmt.Errorf("update webhook: %w", err)
+	}
+
+	if active {
+		fmt.Println("Webhook enabled")
+	} else {
+		fmt.Println("Webhook disabled")
+	}
+	return nil
+}
+
+// webhookDeliveriesCmd shows the delivery history of a webhook
+var webhookDeliveriesCmd = &cobra.Command{
+	Use:   "deliveries <id>",
+	Short: "Show recent deliveries of a webhook",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := uuid.Parse(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid webhook ID: %w", err)
+		}
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		deliveries, err := apiClient.ListWebhookDeliveries(id, limit)
+		if err != nil {
+			return fmt.Errorf("list deliveries: %w", err)
+		}
+
+		if len(deliveries) == 0 {
+			fmt.Println("No deliveries yet")
+			return nil
+		}
+
+		for _, d := range deliveries {
+			line := fmt.Sprintf("%s  %-12s %-9s attempts: %d", d.CreatedAt.Format("2006-01-02 15:04:05"), d.Event, d.Status, d.Attempts)
+			if d.ResponseStatus != nil {
+				line += fmt.Sprintf("  HTTP %d", *d.ResponseStatus)
+			}
+			if d.Status == model.DeliveryPending && d.NextAttemptAt != nil && d.Attempts > 0 {
+				line += "  retry at " + d.NextAttemptAt.Local().Format("15:04:05")
+			}
+			fmt.Println(line)
+			if d.Error != nil && d.Status != model.DeliverySucceeded {
+				fmt.Printf("    %s\n", *d.Error)
+			}
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	webhookAddCmd.Flags().StringSliceP("event", "e", []string{string(model.EventNoteCreated)}, "Event to send, repeat or comma separate for several")
+	webhookDeliveriesCmd.Flags().IntP("limit", "l", 20, "Number of deliveries to show (max 100)")
+
+	webhookCmd.AddCommand(webhookAddCmd)
+	webhookCmd.AddCommand(webhookListCmd)
+	webhookCmd.AddCommand(webhookRemoveCmd)
+	webhookCmd.AddCommand(webhookEnableCmd)
+	webhookCmd.AddCommand(webhookDisableCmd)
+	webhookCmd.AddCommand(webhookDeliveriesCmd)
+	rootCmd.AddCommand(webhookCmd)
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"runtime/debug"
 	"strconv"
 	"strings"
 
@@ -33,7 +34,7 @@ func main() {
 	flags.Parse(os.Args[1:])
 
 	if *version {
-		fmt.Println("goose version:", goose.Version)
+		fmt.Println("goose version:", gooseVersion())
 		return
 	}
 
@@ -48,6 +49,11 @@ func main() {
 		os.Exit(1)
 	}
 
+	// The SQLite migrations are embedded in the API and run with `api migrate`
+	if os.Getenv("DB_DRIVER") == "sqlite" {
+		log.Fatal("goose: DB_DRIVER=sqlite is not supported here, run `api migrate` instead")
+	}
+
 	// Get database connection string from environment or use defaults
 	dbString := getDBString()
 
@@ -213,7 +219,7 @@ func bootstrap(db *sql.DB) error {
 
 func usage() {
 	fmt.Println("Usage:")
-	fmt.Println("  go run ./migrations [options] <command> [args]")
+	fmt.Println("  go run ./cmd/migrate [options] <command> [args]")
 	fmt.Println()
 	fmt.Println("Commands:")
 	fmt.Println("  up                   Migrate all pending migrations")
@@ -253,3 +259,15 @@ func usage() {
 	fmt.Println("  go run ./migrations down")
 	fmt.Println("  go run ./migrations bootstrap    # For existing databases")
 }
+
+// gooseVersion returns the goose module version this binary was built with
+func gooseVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, dep := range info.Deps {
+			if dep.Path == "github.com/pressly/goose/v3" {
+				return dep.Version
+			}
+		}
+	}
+	return "unknown"
+}
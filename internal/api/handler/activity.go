@@ -1,10 +1,16 @@
 package handler
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 
+	"github.com/momokii/go-cli-notes/internal/model"
 	"github.com/momokii/go-cli-notes/internal/repository"
+	"github.com/momokii/go-cli-notes/internal/service"
+	"github.com/momokii/go-cli-notes/internal/util"
 )
 
 // ActivityHandler handles activity HTTP requests
@@ -13,6 +19,120 @@ type ActivityHandler struct {
 	noteService    any // NoteService interface (for trending/forgotten with note details)
 }
 
+// ListActivity handles GET /api/v1/activity
+func (h *ActivityHandler) ListActivity(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	// Parse pagination
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 20)
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	filter := model.ActivityFilter{Page: page, Limit: limit}
+
+	if actionStr := c.Query("action"); actionStr != "" {
+		action := model.ActionType(actionStr)
+		if !action.IsValid() {
+			return sendError(c, fiber.StatusBadRequest, "Invalid action")
+		}
+		filter.Action = &action
+	}
+
+	if noteIDStr := c.Query("note_id"); noteIDStr != "" {
+		noteID, err := uuid.Parse(noteIDStr)
+		if err != nil {
+			return sendError(c, fiber.StatusBadRequest, "Invalid note ID")
+		}
+		filter.NoteID = &noteID
+	}
+
+	// Get activity repository
+	repo, ok := h.activityRepo.(repository.ActivityRepository)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	// Plain dates are days in the user's timezone
+	settings := model.DefaultUserSettings(userID)
+	if svc, ok := h.noteService.(*service.NoteService); ok {
+		if s, err := svc.GetSettings(c.Context(), userID); err == nil {
+			settings = s
+		}
+	}
+	loc, err := time.LoadLocation(settings.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	if fromStr := c.Query("from"); fromStr != "" {
+		from, err := parseActivityTime(fromStr, loc, false)
+		if err != nil {
+			return sendError(c, fiber.StatusBadRequest, "Invalid from date (use YYYY-MM-DD or RFC 3339)")
+		}
+		filter.From = &from
+	}
+
+	if toStr := c.Query("to"); toStr != "" {
+		to, err := parseActivityTime(toStr, loc, true)
+		if err != nil {
+			return sendError(c, fiber.StatusBadRequest, "Invalid to date (use YYYY-MM-DD or RFC 3339)")
+		}
+		filter.To = &to
+	}
+
+	activities, total, err := repo.List(c.Context(), userID, filter)
+	if err != nil {
+		return sendError(c, fiber.StatusInternalServerError, "Failed to list activity")
+	}
+
+	// Calculate pagination
+	totalPages := int(total) / limit
+	if int(total)%limit != 0 {
+		totalPages++
+	}
+
+	return sendJSON(c, fiber.StatusOK, fiber.Map{
+		"activities": activities,
+		"pagination": fiber.Map{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": totalPages,
+		},
+	})
+}
+
+// parseActivityTime parses a from/to bound of the activity feed
+// Accepts RFC 3339 timestamps and YYYY-MM-DD dates in loc. A date used as the
+// upper bound includes the whole day, so it resolves to the start of the next one.
+func parseActivityTime(value string, loc *time.Location, upper bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	date, err := time.ParseInLocation(util.DailyDateLayout, value, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q", value)
+	}
+	if upper {
+		date = date.AddDate(0, 0, 1)
+	}
+	return date, nil
+}
+
 // GetRecentActivity handles GET /api/v1/activity/recent
 func (h *ActivityHandler) GetRecentActivity(c *fiber.Ctx) error {
 	userIDStr, ok := getUserID(c)
@@ -47,6 +167,46 @@ func (h *ActivityHandler) GetRecentActivity(c *fiber.Ctx) error {
 	})
 }
 
+// GetActivityHeatmap handles GET /api/v1/activity/heatmap
+func (h *ActivityHandler) GetActivityHeatmap(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	// Parse weeks
+	weeks := c.QueryInt("weeks", 12)
+	if weeks < 1 || weeks > 53 {
+		weeks = 12
+	}
+
+	// Get activity repository
+	repo, ok := h.activityRepo.(repository.ActivityRepository)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	// Days and weeks follow the user's timezone and week start
+	settings := model.DefaultUserSettings(userID)
+	if svc, ok := h.noteService.(*service.NoteService); ok {
+		if s, err := svc.GetSettings(c.Context(), userID); err == nil {
+			settings = s
+		}
+	}
+
+	heatmap, err := repo.GetActivityHeatmap(c.Context(), userID, settings.Timezone, settings.WeekStart, weeks)
+	if err != nil {
+		return sendError(c, fiber.StatusInternalServerError, "Failed to get activity heatmap")
+	}
+
+	return sendJSON(c, fiber.StatusOK, heatmap)
+}
+
 // GetUserStats handles GET /api/v1/stats
 func (h *ActivityHandler) GetUserStats(c *fiber.Ctx) error {
 	userIDStr, ok := getUserID(c)
@@ -65,7 +225,15 @@ func (h *ActivityHandler) GetUserStats(c *fiber.Ctx) error {
 		return sendError(c, fiber.StatusInternalServerError, "Service error")
 	}
 
-	stats, err := repo.GetUserStats(c.Context(), userID)
+	// Count "today" and "this week" in the user's timezone
+	settings := model.DefaultUserSettings(userID)
+	if svc, ok := h.noteService.(*service.NoteService); ok {
+		if s, err := svc.GetSettings(c.Context(), userID); err == nil {
+			settings = s
+		}
+	}
+
+	stats, err := repo.GetUserStats(c.Context(), userID, settings.Timezone, settings.WeekStart)
 	if err != nil {
 		return sendError(c, fiber.StatusInternalServerError, "Failed to get user stats")
 	}
@@ -73,6 +241,82 @@ func (h *ActivityHandler) GetUserStats(c *fiber.Ctx) error {
 	return sendJSON(c, fiber.StatusOK, stats)
 }
 
+// GetWritingStreak handles GET /api/v1/stats/streak
+func (h *ActivityHandler) GetWritingStreak(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	// Get activity repository
+	repo, ok := h.activityRepo.(repository.ActivityRepository)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	// Days are counted in the user's timezone, against their own goal
+	settings := model.DefaultUserSettings(userID)
+	if svc, ok := h.noteService.(*service.NoteService); ok {
+		if s, err := svc.GetSettings(c.Context(), userID); err == nil {
+			settings = s
+		}
+	}
+
+	streak, err := repo.GetWritingStreak(c.Context(), userID, settings.Timezone, settings.DailyWordGoal)
+	if err != nil {
+		return sendError(c, fiber.StatusInternalServerError, "Failed to get writing streak")
+	}
+
+	return sendJSON(c, fiber.StatusOK, streak)
+}
+
+// GetDailyWords handles GET /api/v1/stats/daily-words
+func (h *ActivityHandler) GetDailyWords(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	// Parse days
+	days := c.QueryInt("days", 30)
+	if days < 1 || days > 365 {
+		days = 30
+	}
+
+	// Get activity repository
+	repo, ok := h.activityRepo.(repository.ActivityRepository)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	settings := model.DefaultUserSettings(userID)
+	if svc, ok := h.noteService.(*service.NoteService); ok {
+		if s, err := svc.GetSettings(c.Context(), userID); err == nil {
+			settings = s
+		}
+	}
+
+	daily, err := repo.GetDailyWords(c.Context(), userID, settings.Timezone, days)
+	if err != nil {
+		return sendError(c, fiber.StatusInternalServerError, "Failed to get daily words")
+	}
+
+	return sendJSON(c, fiber.StatusOK, fiber.Map{
+		"days":       daily,
+		"daily_goal": settings.DailyWordGoal,
+	})
+}
+
 // GetTrendingNotes handles GET /api/v1/notes/trending
 func (h *ActivityHandler) GetTrendingNotes(c *fiber.Ctx) error {
 	userIDStr, ok := getUserID(c)
@@ -119,12 +363,17 @@ func (h *ActivityHandler) GetForgottenNotes(c *fiber.Ctx) error {
 		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
 	}
 
-	// Parse parameters
-	days := c.QueryInt("days", 30)
+	// Parse parameters; days defaults to the user's forgotten_days setting
+	days := c.QueryInt("days", 0)
 	limit := c.QueryInt("limit", 10)
 
 	if days < 1 {
-		days = 30
+		days = model.DefaultForgottenDays
+		if svc, ok := h.noteService.(*service.NoteService); ok {
+			if settings, err := svc.GetSettings(c.Context(), userID); err == nil {
+				days = settings.ForgottenDays
+			}
+		}
 	}
 	if limit < 1 || limit > 50 {
 		limit = 10
@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+	"github.com/momokii/go-cli-notes/internal/service"
+)
+
+// AdminHandler handles instance administration HTTP requests
+// Its routes are only reachable by admins (see middleware.Admin).
+type AdminHandler struct {
+	adminService any // AdminService interface
+}
+
+// ListUsers handles GET /api/v1/admin/users
+func (h *AdminHandler) ListUsers(c *fiber.Ctx) error {
+	// Parse pagination
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 20)
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	svc, ok := h.adminService.(*service.AdminService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	users, total, err := svc.ListUsers(c.Context(), page, limit)
+	if err != nil {
+		return sendError(c, fiber.StatusInternalServerError, "Failed to list users")
+	}
+
+	// Calculate pagination
+	totalPages := int(total) / limit
+	if int(total)%limit != 0 {
+		totalPages++
+	}
+
+	return sendJSON(c, fiber.StatusOK, fiber.Map{
+		"users": users,
+		"pagination": fiber.Map{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": totalPages,
+		},
+	})
+}
+
+// DeactivateUser handles POST /api/v1/admin/users/:id/deactivate
+func (h *AdminHandler) DeactivateUser(c *fiber.Ctx) error {
+	adminIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	adminID, err := uuid.Parse(adminIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	svc, ok := h.adminService.(*service.AdminService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	if err := svc.DeactivateUser(c.Context(), adminID, userID, clientInfo(c)); err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return sendError(c, fiber.StatusNotFound, "User not found")
+		}
+		return handleError(c, err)
+	}
+
+	return sendJSON(c, fiber.StatusOK, fiber.Map{"message": "User deactivated"})
+}
+
+// ActivateUser handles POST /api/v1/admin/users/:id/activate
+func (h *AdminHandler) ActivateUser(c *fiber.Ctx) error {
+	adminIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	adminID, err := uuid.Parse(adminIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	svc, ok := h.adminService.(*service.AdminService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	if err := svc.ActivateUser(c.Context(), adminID, userID, clientInfo(c)); err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return sendError(c, fiber.StatusNotFound, "User not found")
+		}
+		return handleError(c, err)
+	}
+
+	return sendJSON(c, fiber.StatusOK, fiber.Map{"message": "User activated"})
+}
+
+// ListAuditLog handles GET /api/v1/admin/audit
+// Dates without a time are UTC days.
+func (h *AdminHandler) ListAuditLog(c *fiber.Ctx) error {
+	filter, err := parseAuditFilter(c, time.UTC)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	if userIDStr := c.Query("user_id"); userIDStr != "" {
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+		}
+		filter.UserID = &userID
+	}
+
+	svc, ok := h.adminService.(*service.AdminService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	entries, total, err := svc.ListAuditLog(c.Context(), filter)
+	if err != nil {
+		return sendError(c, fiber.StatusInternalServerError, "Failed to list audit log")
+	}
+
+	return sendAuditPage(c, entries, total, filter)
+}
+
+// GetStats handles GET /api/v1/admin/stats
+func (h *AdminHandler) GetStats(c *fiber.Ctx) error {
+	svc, ok := h.adminService.(*service.AdminService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	stats, err := svc.GetStats(c.Context())
+	if err != nil {
+		return sendError(c, fiber.StatusInternalServerError, "Failed to get instance stats")
+	}
+
+	return sendJSON(c, fiber.StatusOK, stats)
+}
@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+	"github.com/momokii/go-cli-notes/internal/repository"
+	"github.com/momokii/go-cli-notes/internal/service"
+)
+
+// AttachmentHandler handles note attachment HTTP requests
+type AttachmentHandler struct {
+	attachmentService any // AttachmentService interface
+}
+
+// UploadAttachment handles POST /api/v1/notes/:id/attachments
+// Expects a multipart form with the file in the "file" field
+func (h *AttachmentHandler) UploadAttachment(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	noteID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid note ID")
+	}
+
+	svc, ok := h.attachmentService.(*service.AttachmentService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Missing file field")
+	}
+	if fileHeader.Size > svc.MaxSize() {
+		return sendError(c, fiber.StatusRequestEntityTooLarge, fmt.Sprintf("File exceeds the %d byte limit", svc.MaxSize()))
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Failed to read file")
+	}
+	defer file.Close()
+
+	attachment, err := svc.Upload(c.Context(), userID, noteID, fileHeader.Filename, fileHeader.Header.Get("Content-Type"), fileHeader.Size, file)
+	if err != nil {
+		return attachmentError(c, err, "Failed to upload attachment")
+	}
+
+	return sendJSON(c, fiber.StatusCreated, attachment)
+}
+
+// ListAttachments handles GET /api/v1/notes/:id/attachments
+func (h *AttachmentHandler) ListAttachments(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	noteID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid note ID")
+	}
+
+	svc, ok := h.attachmentService.(*service.AttachmentService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	attachments, err := svc.List(c.Context(), userID, noteID)
+	if err != nil {
+		return attachmentError(c, err, "Failed to list attachments")
+	}
+
+	return sendJSON(c, fiber.StatusOK, fiber.Map{
+		"attachments": attachments,
+		"count":       len(attachments),
+	})
+}
+
+// DownloadAttachment handles GET /api/v1/notes/:id/attachments/:attachment_id
+func (h *AttachmentHandler) DownloadAttachment(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	noteID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid note ID")
+	}
+
+	attachmentID, err := uuid.Parse(c.Params("attachment_id"))
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid attachment ID")
+	}
+
+	svc, ok := h.attachmentService.(*service.AttachmentService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	attachment, body, err := svc.Open(c.Context(), userID, noteID, attachmentID)
+	if err != nil {
+		return attachmentError(c, err, "Failed to download attachment")
+	}
+
+	c.Set(fiber.HeaderContentType, attachment.ContentType)
+	c.Set(fiber.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}))
+
+	// Fiber closes the body once it has been streamed
+	return c.SendStream(body, int(attachment.SizeBytes))
+}
+
+// DeleteAttachment handles DELETE /api/v1/notes/:id/attachments/:attachment_id
+func (h *AttachmentHandler) DeleteAttachment(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	noteID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid note ID")
+	}
+
+	attachmentID, err := uuid.Parse(c.Params("attachment_id"))
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid attachment ID")
+	}
+
+	svc, ok := h.attachmentService.(*service.AttachmentService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	if err := svc.Delete(c.Context(), userID, noteID, attachmentID); err != nil {
+		return attachmentError(c, err, "Failed to delete attachment")
+	}
+
+	return sendJSON(c, fiber.StatusOK, fiber.Map{
+		"message": "Attachment deleted successfully",
+	})
+}
+
+// attachmentError maps attachment service errors to HTTP responses
+func attachmentError(c *fiber.Ctx, err error, fallback string) error {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return sendError(c, fiber.StatusNotFound, "Resource not found")
+	case errors.Is(err, model.ErrValidation):
+		return sendValidationError(c, err)
+	default:
+		return sendError(c, fiber.StatusInternalServerError, fallback)
+	}
+}
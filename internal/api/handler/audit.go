@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+	"github.com/momokii/go-cli-notes/internal/service"
+)
+
+// AuditHandler handles audit log HTTP requests of the signed-in user
+type AuditHandler struct {
+	authService any // AuthService interface
+	noteService any // NoteService interface (for the user's timezone)
+}
+
+// ListAuditLog handles GET /api/v1/audit
+func (h *AuditHandler) ListAuditLog(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	// Plain dates are days in the user's timezone
+	settings := model.DefaultUserSettings(userID)
+	if svc, ok := h.noteService.(*service.NoteService); ok {
+		if s, err := svc.GetSettings(c.Context(), userID); err == nil {
+			settings = s
+		}
+	}
+
+	filter, err := parseAuditFilter(c, settings.Location())
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	svc, ok := h.authService.(*service.AuthService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	entries, total, err := svc.ListAuditLog(c.Context(), userID, filter)
+	if err != nil {
+		return sendError(c, fiber.StatusInternalServerError, "Failed to list audit log")
+	}
+
+	return sendAuditPage(c, entries, total, filter)
+}
+
+// parseAuditFilter reads the page, limit, event, from and to query parameters of an audit log listing
+func parseAuditFilter(c *fiber.Ctx, loc *time.Location) (model.AuditFilter, error) {
+	// Parse pagination
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 20)
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	filter := model.AuditFilter{Page: page, Limit: limit}
+
+	if eventStr := c.Query("event"); eventStr != "" {
+		event := model.AuditEvent(eventStr)
+		if !event.IsValid() {
+			return filter, errors.New("Invalid event")
+		}
+		filter.Event = &event
+	}
+
+	if fromStr := c.Query("from"); fromStr != "" {
+		from, err := parseActivityTime(fromStr, loc, false)
+		if err != nil {
+			return filter, errors.New("Invalid from date (use YYYY-MM-DD or RFC 3339)")
+		}
+		filter.From = &from
+	}
+
+	if toStr := c.Query("to"); toStr != "" {
+		to, err := parseActivityTime(toStr, loc, true)
+		if err != nil {
+			return filter, errors.New("Invalid to date (use YYYY-MM-DD or RFC 3339)")
+		}
+		filter.To = &to
+	}
+
+	return filter, nil
+}
+
+// sendAuditPage sends a page of audit log entries with its pagination
+func sendAuditPage(c *fiber.Ctx, entries []*model.AuditEntry, total int64, filter model.AuditFilter) error {
+	// Calculate pagination
+	totalPages := int(total) / filter.Limit
+	if int(total)%filter.Limit != 0 {
+		totalPages++
+	}
+
+	return sendJSON(c, fiber.StatusOK, fiber.Map{
+		"entries": entries,
+		"pagination": fiber.Map{
+			"page":        filter.Page,
+			"limit":       filter.Limit,
+			"total":       total,
+			"total_pages": totalPages,
+		},
+	})
+}
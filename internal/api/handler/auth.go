@@ -1,11 +1,15 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 	"github.com/momokii/go-cli-notes/internal/model"
+	"github.com/momokii/go-cli-notes/internal/repository"
 	"github.com/momokii/go-cli-notes/internal/service"
+	"github.com/momokii/go-cli-notes/internal/util"
 )
 
 // Register handles user registration
@@ -42,7 +46,7 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 		return sendError(c, fiber.StatusInternalServerError, "Service error")
 	}
 
-	resp, err := svc.Login(c.Context(), &req)
+	resp, err := svc.Login(c.Context(), &req, clientInfo(c))
 	if err != nil {
 		return handleError(c, err)
 	}
@@ -63,7 +67,7 @@ func (h *AuthHandler) RefreshToken(c *fiber.Ctx) error {
 		return sendError(c, fiber.StatusInternalServerError, "Service error")
 	}
 
-	resp, err := svc.RefreshToken(c.Context(), req.RefreshToken)
+	resp, err := svc.RefreshToken(c.Context(), req.RefreshToken, clientInfo(c))
 	if err != nil {
 		return handleError(c, err)
 	}
@@ -72,19 +76,283 @@ func (h *AuthHandler) RefreshToken(c *fiber.Ctx) error {
 }
 
 // Logout handles user logout
+// Body (optional): {"refresh_token": "..."}, the token to revoke
 func (h *AuthHandler) Logout(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	var req model.RefreshRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return sendError(c, fiber.StatusBadRequest, "Invalid request body")
+		}
+	}
+
 	// Call service
 	svc, ok := h.authService.(*service.AuthService)
 	if !ok {
 		return sendError(c, fiber.StatusInternalServerError, "Service error")
 	}
 
-	// TODO: Get user ID from context and revoke token
-	_ = svc
+	if err := svc.Logout(c.Context(), userID, req.RefreshToken, clientInfo(c)); err != nil {
+		return handleError(c, err)
+	}
 
 	return sendJSON(c, fiber.StatusOK, fiber.Map{"message": "Logged out"})
 }
 
+// ListSessions handles GET /api/v1/auth/sessions
+func (h *AuthHandler) ListSessions(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	// Call service
+	svc, ok := h.authService.(*service.AuthService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	sessions, err := svc.ListSessions(c.Context(), userID)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	return sendJSON(c, fiber.StatusOK, fiber.Map{
+		"sessions": sessions,
+		"count":    len(sessions),
+	})
+}
+
+// RevokeSession handles DELETE /api/v1/auth/sessions/:id
+func (h *AuthHandler) RevokeSession(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	sessionID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid session ID")
+	}
+
+	// Call service
+	svc, ok := h.authService.(*service.AuthService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	if err := svc.RevokeSession(c.Context(), userID, sessionID, clientInfo(c)); err != nil {
+		return handleError(c, err)
+	}
+
+	return sendJSON(c, fiber.StatusOK, fiber.Map{"message": "Session revoked"})
+}
+
+// RevokeAllSessions handles DELETE /api/v1/auth/sessions
+func (h *AuthHandler) RevokeAllSessions(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	// Call service
+	svc, ok := h.authService.(*service.AuthService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	if err := svc.RevokeAllSessions(c.Context(), userID, clientInfo(c)); err != nil {
+		return handleError(c, err)
+	}
+
+	return sendJSON(c, fiber.StatusOK, fiber.Map{"message": "All sessions revoked"})
+}
+
+// VerifyEmail handles POST /api/v1/auth/verify-email
+func (h *AuthHandler) VerifyEmail(c *fiber.Ctx) error {
+	var req model.VerifyEmailRequest
+	if err := c.BodyParser(&req); err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	// Call service
+	svc, ok := h.authService.(*service.AuthService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	if err := svc.VerifyEmail(c.Context(), &req); err != nil {
+		return handleError(c, err)
+	}
+
+	return sendJSON(c, fiber.StatusOK, fiber.Map{"message": "Email verified"})
+}
+
+// ResendVerification handles POST /api/v1/auth/resend-verification
+// Always answers the same way so the response doesn't reveal whether the email is registered.
+func (h *AuthHandler) ResendVerification(c *fiber.Ctx) error {
+	var req model.ResendVerificationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	// Call service
+	svc, ok := h.authService.(*service.AuthService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	if err := svc.ResendVerification(c.Context(), &req); err != nil {
+		return handleError(c, err)
+	}
+
+	return sendJSON(c, fiber.StatusOK, fiber.Map{"message": "If the email is registered and unverified, a verification token has been sent"})
+}
+
+// ForgotPassword handles POST /api/v1/auth/forgot-password
+// Always answers the same way so the response doesn't reveal whether the email is registered.
+func (h *AuthHandler) ForgotPassword(c *fiber.Ctx) error {
+	var req model.ForgotPasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	// Call service
+	svc, ok := h.authService.(*service.AuthService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	if err := svc.ForgotPassword(c.Context(), &req); err != nil {
+		return handleError(c, err)
+	}
+
+	return sendJSON(c, fiber.StatusOK, fiber.Map{"message": "If the email is registered, a reset token has been sent"})
+}
+
+// ResetPassword handles POST /api/v1/auth/reset-password
+func (h *AuthHandler) ResetPassword(c *fiber.Ctx) error {
+	var req model.ResetPasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	// Call service
+	svc, ok := h.authService.(*service.AuthService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	if err := svc.ResetPassword(c.Context(), &req, clientInfo(c)); err != nil {
+		return handleError(c, err)
+	}
+
+	return sendJSON(c, fiber.StatusOK, fiber.Map{"message": "Password has been reset"})
+}
+
+// SetupTOTP handles POST /api/v1/auth/2fa/setup
+func (h *AuthHandler) SetupTOTP(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	// Call service
+	svc, ok := h.authService.(*service.AuthService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	resp, err := svc.SetupTOTP(c.Context(), userID)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	return sendJSON(c, fiber.StatusOK, resp)
+}
+
+// VerifyTOTP handles POST /api/v1/auth/2fa/verify
+func (h *AuthHandler) VerifyTOTP(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	var req model.TOTPVerifyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	// Call service
+	svc, ok := h.authService.(*service.AuthService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	if err := svc.VerifyTOTP(c.Context(), userID, &req, clientInfo(c)); err != nil {
+		return handleError(c, err)
+	}
+
+	return sendJSON(c, fiber.StatusOK, fiber.Map{"message": "Two-factor authentication enabled"})
+}
+
+// clientInfo describes the device making the request, recorded with its session
+func clientInfo(c *fiber.Ctx) model.ClientInfo {
+	return model.ClientInfo{
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+		IPAddress: c.IP(),
+	}
+}
+
+// sendValidationError sends a validation error, listing the invalid fields when known
+func sendValidationError(c *fiber.Ctx, err error) error {
+	resp := fiber.Map{
+		"error": err.Error(),
+		"code":  model.CodeValidation,
+	}
+
+	var validationErr *util.ValidationError
+	if errors.As(err, &validationErr) {
+		resp["fields"] = validationErr.Fields
+	}
+
+	return sendJSON(c, fiber.StatusBadRequest, resp)
+}
+
 // handleError maps service errors to HTTP status codes
 func handleError(c *fiber.Ctx, err error) error {
 	if err == nil {
@@ -101,6 +369,44 @@ func handleError(c *fiber.Ctx, err error) error {
 		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
 	case errMsg == "invalid credentials":
 		return sendError(c, fiber.StatusUnauthorized, "Invalid email or password")
+	case errors.Is(err, model.ErrInvalidToken):
+		return sendError(c, fiber.StatusUnauthorized, "Invalid or expired refresh token")
+	case errors.Is(err, model.ErrTOTPRequired):
+		return sendErrorCode(c, fiber.StatusUnauthorized, model.CodeTOTPRequired, "Two-factor code required")
+	case errors.Is(err, model.ErrInvalidTOTP):
+		return sendError(c, fiber.StatusUnauthorized, "Invalid two-factor code")
+	case errors.Is(err, model.ErrTOTPEnabled):
+		return sendError(c, fiber.StatusConflict, "Two-factor authentication already enabled")
+	case errors.Is(err, model.ErrTOTPNotSetup):
+		return sendError(c, fiber.StatusBadRequest, "Two-factor authentication not set up, call /auth/2fa/setup first")
+	case errors.Is(err, model.ErrWrongPassword):
+		return sendError(c, fiber.StatusForbidden, "Incorrect password")
+	case errors.Is(err, model.ErrEmailNotVerified):
+		return sendErrorCode(c, fiber.StatusForbidden, model.CodeEmailNotVerified, "Email not verified")
+	case errors.Is(err, model.ErrInvalidVerificationToken):
+		return sendError(c, fiber.StatusBadRequest, "Invalid or expired verification token")
+	case errors.Is(err, model.ErrAuthorizationPending):
+		return sendErrorCode(c, fiber.StatusBadRequest, model.CodeAuthorizationPending, "Waiting for the code to be approved")
+	case errors.Is(err, model.ErrSlowDown):
+		return sendErrorCode(c, fiber.StatusBadRequest, model.CodeSlowDown, "Polling too fast, wait longer between requests")
+	case errors.Is(err, model.ErrDeviceCodeExpired):
+		return sendErrorCode(c, fiber.StatusBadRequest, model.CodeExpiredToken, "Device code expired, start a new login")
+	case errors.Is(err, model.ErrAccessDenied):
+		return sendErrorCode(c, fiber.StatusBadRequest, model.CodeAccessDenied, "Login was denied")
+	case errors.Is(err, model.ErrInvalidUserCode):
+		return sendError(c, fiber.StatusBadRequest, "Invalid or expired code")
+	case errors.Is(err, model.ErrInvalidResetToken):
+		return sendError(c, fiber.StatusBadRequest, "Invalid or expired reset token")
+	case errors.Is(err, model.ErrNoteChanged):
+		return sendErrorCode(c, fiber.StatusConflict, model.CodeNoteChanged, "Note changed since the version the update is based on")
+	case errors.Is(err, model.ErrValidation):
+		return sendValidationError(c, err)
+	case errors.Is(err, model.ErrAPIEmailExists):
+		return sendErrorCode(c, fiber.StatusConflict, model.ErrAPIEmailExists.Code, model.ErrAPIEmailExists.Message)
+	case errors.Is(err, model.ErrAPIUsernameExists):
+		return sendErrorCode(c, fiber.StatusConflict, model.ErrAPIUsernameExists.Code, model.ErrAPIUsernameExists.Message)
+	case errors.Is(err, repository.ErrPoolTimeout):
+		return sendError(c, fiber.StatusServiceUnavailable, "Server is busy, please retry")
 	case errMsg == "check email exists: resource not found" || errMsg == "check username exists: resource not found":
 		// These are actually success cases (user doesn't exist yet)
 		return sendError(c, fiber.StatusInternalServerError, "Internal error")
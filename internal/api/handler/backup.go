@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+	"github.com/momokii/go-cli-notes/internal/service"
+)
+
+// BackupHandler handles backup HTTP requests
+// Its routes are only reachable by admins (see middleware.Admin).
+type BackupHandler struct {
+	backupService any // BackupService interface
+}
+
+// ListBackups handles GET /api/v1/admin/backups
+func (h *BackupHandler) ListBackups(c *fiber.Ctx) error {
+	svc, ok := h.backupService.(*service.BackupService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	backups, err := svc.List(c.Context())
+	if err != nil {
+		return sendError(c, fiber.StatusInternalServerError, "Failed to list backups")
+	}
+
+	return sendJSON(c, fiber.StatusOK, fiber.Map{
+		"backups": backups,
+		"count":   len(backups),
+	})
+}
+
+// CreateBackup handles POST /api/v1/admin/backups
+func (h *BackupHandler) CreateBackup(c *fiber.Ctx) error {
+	svc, ok := h.backupService.(*service.BackupService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	result, err := svc.Create(c.Context())
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	return sendJSON(c, fiber.StatusCreated, result)
+}
+
+// RestoreBackup handles POST /api/v1/admin/backups/:name/restore
+func (h *BackupHandler) RestoreBackup(c *fiber.Ctx) error {
+	svc, ok := h.backupService.(*service.BackupService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	result, err := svc.Restore(c.Context(), c.Params("name"))
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return sendError(c, fiber.StatusNotFound, "Backup not found")
+		}
+		return handleError(c, err)
+	}
+
+	return sendJSON(c, fiber.StatusOK, result)
+}
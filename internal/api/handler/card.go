@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+	"github.com/momokii/go-cli-notes/internal/repository"
+	"github.com/momokii/go-cli-notes/internal/service"
+)
+
+// CardHandler handles flashcard review HTTP requests
+type CardHandler struct {
+	noteService any // NoteService interface
+}
+
+// ListDueCards handles GET /api/v1/review/due
+// Query params: limit (default 20, max 100)
+func (h *CardHandler) ListDueCards(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	svc, ok := h.noteService.(*service.NoteService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	due, err := svc.ListDueCards(c.Context(), userID, c.QueryInt("limit", model.DefaultDueCardsLimit))
+	if err != nil {
+		if errors.Is(err, model.ErrValidation) {
+			return sendValidationError(c, err)
+		}
+		return sendError(c, fiber.StatusInternalServerError, "Failed to list due cards")
+	}
+
+	return sendJSON(c, fiber.StatusOK, due)
+}
+
+// ReviewCard handles POST /api/v1/review/due/:id
+func (h *CardHandler) ReviewCard(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	cardID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid card ID")
+	}
+
+	var req model.ReviewCardRequest
+	if err := c.BodyParser(&req); err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	svc, ok := h.noteService.(*service.NoteService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	card, err := svc.ReviewCard(c.Context(), userID, cardID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrValidation):
+			return sendValidationError(c, err)
+		case errors.Is(err, repository.ErrNotFound):
+			return sendError(c, fiber.StatusNotFound, "Card not found")
+		default:
+			return sendError(c, fiber.StatusInternalServerError, "Failed to review card")
+		}
+	}
+
+	return sendJSON(c, fiber.StatusOK, card)
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+	"github.com/momokii/go-cli-notes/internal/service"
+)
+
+// ClipHandler handles web clipper HTTP requests
+type ClipHandler struct {
+	clipService any // ClipService interface
+}
+
+// Clip handles POST /api/v1/clip
+func (h *ClipHandler) Clip(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	var req model.ClipRequest
+	if err := c.BodyParser(&req); err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	svc, ok := h.clipService.(*service.ClipService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	note, err := svc.Clip(c.Context(), userID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrClipFailed):
+			// The reason is about the page the user asked for, e.g. "server returned 404 Not Found"
+			return sendError(c, fiber.StatusUnprocessableEntity, err.Error())
+		case errors.Is(err, model.ErrValidation):
+			return handleError(c, err)
+		default:
+			slog.Error("Failed to clip page", "url", req.URL, "error", err)
+			return sendError(c, fiber.StatusInternalServerError, "Failed to clip page")
+		}
+	}
+
+	return sendJSON(c, fiber.StatusCreated, note)
+}
@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"html/template"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+	"github.com/momokii/go-cli-notes/internal/service"
+)
+
+// devicePage is where a user signs in to approve a device login, at the verification URI
+var devicePage = template.Must(template.New("device").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <meta name="robots" content="noindex">
+  <title>Sign in a device · Knowledge Garden</title>
+  <style>
+    body { max-width: 24rem; margin: 3rem auto; padding: 0 1rem; font: 17px/1.5 system-ui, sans-serif; color: #222; }
+    label { display: block; margin-top: 1rem; font-size: .9rem; color: #555; }
+    input { box-sizing: border-box; width: 100%; padding: .5rem; font: inherit; border: 1px solid #ccc; border-radius: 4px; }
+    input[name=code] { text-transform: uppercase; letter-spacing: .15em; font-family: monospace; font-size: 1.2rem; }
+    button { margin-top: 1.5rem; width: 100%; padding: .6rem; font: inherit; color: #fff; background: #2f6f4f; border: 0; border-radius: 4px; cursor: pointer; }
+    .error { padding: .5rem .75rem; color: #8a1f11; background: #fbe3e4; border-radius: 4px; }
+    .hint { font-size: .9rem; color: #666; }
+  </style>
+</head>
+<body>
+{{if .Done}}
+  <h1>Device signed in</h1>
+  <p>{{if .Device}}<strong>{{.Device}}</strong> is{{else}}The device is{{end}} now signed in to your account. You can close this page and return to your terminal.</p>
+  <p class="hint">Don't recognize it? Revoke it with <code>kg-cli sessions</code>.</p>
+{{else}}
+  <h1>Sign in a device</h1>
+  <p class="hint">Enter the code shown by <code>kg-cli login --device</code>, then sign in to approve it. Only do this for a device you started the login on.</p>
+  {{if .Error}}<p class="error">{{.Error}}</p>{{end}}
+  <form method="post" action="/device">
+    <label for="code">Code</label>
+    <input id="code" name="code" value="{{.Code}}" placeholder="XXXX-XXXX" autocomplete="off" required>
+    <label for="email">Email</label>
+    <input id="email" name="email" type="email" value="{{.Email}}" autocomplete="username" required>
+    <label for="password">Password</label>
+    <input id="password" name="password" type="password" autocomplete="current-password" required>
+    <label for="totp_code">Two-factor code (if enabled)</label>
+    <input id="totp_code" name="totp_code" inputmode="numeric" autocomplete="one-time-code">
+    <button type="submit">Approve device</button>
+  </form>
+{{end}}
+</body>
+</html>
+`))
+
+// devicePageData fills in the device login page
+type devicePageData struct {
+	Code   string
+	Email  string
+	Error  string
+	Done   bool
+	Device string // User agent of the device that was signed in
+}
+
+// StartDeviceAuth handles POST /api/v1/auth/device
+func (h *AuthHandler) StartDeviceAuth(c *fiber.Ctx) error {
+	svc, ok := h.authService.(*service.AuthService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	resp, err := svc.StartDeviceAuth(c.Context(), clientInfo(c), c.BaseURL())
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	return sendJSON(c, fiber.StatusOK, resp)
+}
+
+// DeviceToken handles POST /api/v1/auth/device/token, polled by the device until its login is approved
+func (h *AuthHandler) DeviceToken(c *fiber.Ctx) error {
+	var req model.DeviceTokenRequest
+	if err := c.BodyParser(&req); err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	svc, ok := h.authService.(*service.AuthService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	resp, err := svc.PollDeviceAuth(c.Context(), &req, clientInfo(c))
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	return sendJSON(c, fiber.StatusOK, resp)
+}
+
+// ApproveDevice handles POST /api/v1/auth/device/approve, from a device already signed in
+func (h *AuthHandler) ApproveDevice(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	var req model.DeviceApproveRequest
+	if err := c.BodyParser(&req); err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	svc, ok := h.authService.(*service.AuthService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	auth, err := svc.ApproveDevice(c.Context(), userID, &req, clientInfo(c))
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	return sendJSON(c, fiber.StatusOK, auth)
+}
+
+// DevicePage handles GET /device, the verification URI of device logins
+func (h *AuthHandler) DevicePage(c *fiber.Ctx) error {
+	return renderDevicePage(c, fiber.StatusOK, devicePageData{Code: c.Query("code")})
+}
+
+// DeviceLogin handles POST /device, the form of the device login page
+func (h *AuthHandler) DeviceLogin(c *fiber.Ctx) error {
+	data := devicePageData{
+		Code:  strings.TrimSpace(c.FormValue("code")),
+		Email: strings.TrimSpace(c.FormValue("email")),
+	}
+
+	svc, ok := h.authService.(*service.AuthService)
+	if !ok {
+		data.Error = "Service error"
+		return renderDevicePage(c, fiber.StatusInternalServerError, data)
+	}
+
+	login := &model.LoginRequest{
+		Email:    data.Email,
+		Password: c.FormValue("password"),
+		TOTPCode: strings.TrimSpace(c.FormValue("totp_code")),
+	}
+	auth, err := svc.ApproveDeviceWithLogin(c.Context(), data.Code, login, clientInfo(c))
+	if err != nil {
+		data.Error = devicePageError(err)
+		return renderDevicePage(c, fiber.StatusBadRequest, data)
+	}
+
+	return renderDevicePage(c, fiber.StatusOK, devicePageData{Done: true, Device: auth.UserAgent})
+}
+
+// renderDevicePage writes the device login page
+func renderDevicePage(c *fiber.Ctx, status int, data devicePageData) error {
+	// The page takes a password: never cache it or let other sites frame it
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	c.Set("Referrer-Policy", "no-referrer")
+	c.Set("X-Frame-Options", "DENY")
+	c.Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; form-action 'self'; frame-ancestors 'none'")
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+
+	var buf bytes.Buffer
+	if err := devicePage.Execute(&buf, data); err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to render page")
+	}
+	return c.Status(status).Send(buf.Bytes())
+}
+
+// devicePageError is the message shown on the device login page for a failed approval
+func devicePageError(err error) string {
+	switch {
+	case errors.Is(err, model.ErrValidation):
+		return "Enter the code, your email and your password."
+	case errors.Is(err, model.ErrInvalidCredentials):
+		return "Invalid email or password."
+	case errors.Is(err, model.ErrTOTPRequired):
+		return "Your account has two-factor authentication enabled, enter the code from your authenticator app."
+	case errors.Is(err, model.ErrInvalidTOTP):
+		return "Invalid two-factor code."
+	case errors.Is(err, model.ErrEmailNotVerified):
+		return "Verify your email before signing in."
+	case errors.Is(err, model.ErrUnauthorized):
+		return "This account is deactivated."
+	case errors.Is(err, model.ErrInvalidUserCode):
+		return "That code is invalid or has expired. Start a new login on the device."
+	default:
+		return "Something went wrong, please try again."
+	}
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// swaggerUIPage renders the OpenAPI document with Swagger UI (assets loaded from unpkg)
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Knowledge Garden API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.onload = () => {
+      window.ui = SwaggerUIBundle({ url: "/api/v1/openapi.json", dom_id: "#swagger-ui", persistAuthorization: true });
+    };
+  </script>
+</body>
+</html>
+`
+
+// DocsHandler serves the API documentation
+type DocsHandler struct {
+	spec []byte // Generated OpenAPI document (JSON)
+}
+
+// OpenAPI handles GET /api/v1/openapi.json
+func (h *DocsHandler) OpenAPI(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSONCharsetUTF8)
+	return c.Send(h.spec)
+}
+
+// SwaggerUI handles GET /api/v1/docs
+func (h *DocsHandler) SwaggerUI(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	return c.SendString(swaggerUIPage)
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/valyala/fasthttp"
+
+	"github.com/momokii/go-cli-notes/internal/events"
+)
+
+// eventHeartbeat is how often an idle stream sends a keep-alive comment
+// It also bounds how long a dead client keeps its subscription.
+const eventHeartbeat = 15 * time.Second
+
+// EventHandler handles the live update stream
+type EventHandler struct {
+	broker any // *events.Broker
+}
+
+// Stream handles GET /api/v1/events
+// Sends the user's note, tag and activity changes as server-sent events until the client disconnects
+func (h *EventHandler) Stream(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	// Get event broker
+	broker, ok := h.broker.(*events.Broker)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	c.Set("Content-Type", "text/event-stream")
+	c.Set("Cache-Control", "no-cache")
+	c.Set("Connection", "keep-alive")
+	c.Set("X-Accel-Buffering", "no") // Disable proxy buffering (nginx)
+
+	ch, unsubscribe := broker.Subscribe(userID)
+	conn := c.Context().Conn()
+
+	c.Context().SetBodyStreamWriter(fasthttp.StreamWriter(func(w *bufio.Writer) {
+		defer unsubscribe()
+
+		ticker := time.NewTicker(eventHeartbeat)
+		defer ticker.Stop()
+
+		// The server write timeout would otherwise cut the stream; a write that
+		// stalls for two heartbeats means the client is gone
+		extendDeadline := func() {
+			_ = conn.SetWriteDeadline(time.Now().Add(2 * eventHeartbeat))
+		}
+
+		extendDeadline()
+		fmt.Fprintf(w, "retry: %d\n: connected\n\n", (5 * time.Second).Milliseconds())
+		if err := w.Flush(); err != nil {
+			return
+		}
+
+		for {
+			select {
+			case event, ok := <-ch:
+				if !ok {
+					return // Broker closed (server shutting down)
+				}
+
+				data, err := json.Marshal(event)
+				if err != nil {
+					continue
+				}
+
+				extendDeadline()
+				fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+
+			case <-ticker.C:
+				extendDeadline()
+				fmt.Fprint(w, ": ping\n\n")
+			}
+
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	}))
+
+	return nil
+}
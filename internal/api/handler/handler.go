@@ -2,6 +2,8 @@ package handler
 
 import (
 	"github.com/gofiber/fiber/v2"
+
+	"github.com/momokii/go-cli-notes/internal/model"
 )
 
 // AuthHandler handles authentication HTTP requests
@@ -16,12 +18,29 @@ type NoteHandler struct {
 
 // Handlers holds all handlers
 type Handlers struct {
-	Auth     *AuthHandler
-	Note     *NoteHandler
-	Tag      *TagHandler
-	Search   *SearchHandler
-	Link     *LinkHandler
-	Activity *ActivityHandler
+	Auth       *AuthHandler
+	Note       *NoteHandler
+	Tag        *TagHandler
+	Search     *SearchHandler
+	Link       *LinkHandler
+	Activity   *ActivityHandler
+	Audit      *AuditHandler
+	Settings   *SettingsHandler
+	Attachment *AttachmentHandler
+	Task       *TaskHandler
+	Card       *CardHandler
+	NoteType   *NoteTypeHandler
+	Event      *EventHandler
+	Docs       *DocsHandler
+	User       *UserHandler
+	Summary    *SummaryHandler
+	Admin      *AdminHandler
+	Backup     *BackupHandler
+	Clip       *ClipHandler
+	PublicLink *PublicLinkHandler
+	NoteShare  *NoteShareHandler
+	Webhook    *WebhookHandler
+	Metrics    *MetricsHandler
 }
 
 // NewAuthHandler creates a new auth handler
@@ -46,9 +65,10 @@ func NewTagHandler(tagService any) *TagHandler {
 }
 
 // NewSearchHandler creates a new search handler
-func NewSearchHandler(noteService any) *SearchHandler {
+func NewSearchHandler(noteService any, embeddingService any) *SearchHandler {
 	return &SearchHandler{
-		noteService: noteService,
+		noteService:      noteService,
+		embeddingService: embeddingService,
 	}
 }
 
@@ -67,18 +87,166 @@ func NewActivityHandler(activityRepo any, noteService any) *ActivityHandler {
 	}
 }
 
+// NewAuditHandler creates a new audit log handler
+func NewAuditHandler(authService any, noteService any) *AuditHandler {
+	return &AuditHandler{
+		authService: authService,
+		noteService: noteService,
+	}
+}
+
+// NewSettingsHandler creates a new settings handler
+func NewSettingsHandler(noteService any) *SettingsHandler {
+	return &SettingsHandler{
+		noteService: noteService,
+	}
+}
+
+// NewAttachmentHandler creates a new attachment handler
+func NewAttachmentHandler(attachmentService any) *AttachmentHandler {
+	return &AttachmentHandler{
+		attachmentService: attachmentService,
+	}
+}
+
+// NewTaskHandler creates a new task handler
+func NewTaskHandler(noteService any) *TaskHandler {
+	return &TaskHandler{
+		noteService: noteService,
+	}
+}
+
+// NewCardHandler creates a new flashcard review handler
+func NewCardHandler(noteService any) *CardHandler {
+	return &CardHandler{
+		noteService: noteService,
+	}
+}
+
+// NewNoteTypeHandler creates a new note type handler
+func NewNoteTypeHandler(noteService any) *NoteTypeHandler {
+	return &NoteTypeHandler{
+		noteService: noteService,
+	}
+}
+
+// NewEventHandler creates a new event handler
+func NewEventHandler(broker any) *EventHandler {
+	return &EventHandler{
+		broker: broker,
+	}
+}
+
+// NewUserHandler creates a new user account handler
+func NewUserHandler(authService any) *UserHandler {
+	return &UserHandler{
+		authService: authService,
+	}
+}
+
+// NewSummaryHandler creates a new note summary handler
+func NewSummaryHandler(summaryService any) *SummaryHandler {
+	return &SummaryHandler{
+		summaryService: summaryService,
+	}
+}
+
+// NewAdminHandler creates a new instance administration handler
+func NewAdminHandler(adminService any) *AdminHandler {
+	return &AdminHandler{
+		adminService: adminService,
+	}
+}
+
+// NewBackupHandler creates a new backup handler
+func NewBackupHandler(backupService any) *BackupHandler {
+	return &BackupHandler{
+		backupService: backupService,
+	}
+}
+
+// NewClipHandler creates a new web clipper handler
+func NewClipHandler(clipService any) *ClipHandler {
+	return &ClipHandler{
+		clipService: clipService,
+	}
+}
+
+// NewPublicLinkHandler creates a new public link handler
+func NewPublicLinkHandler(publicLinkService any) *PublicLinkHandler {
+	return &PublicLinkHandler{
+		publicLinkService: publicLinkService,
+	}
+}
+
+// NewNoteShareHandler creates a new note share handler
+func NewNoteShareHandler(noteShareService any) *NoteShareHandler {
+	return &NoteShareHandler{
+		noteShareService: noteShareService,
+	}
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(webhookService any) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// NewMetricsHandler creates a new metrics handler
+func NewMetricsHandler(db any) *MetricsHandler {
+	return &MetricsHandler{
+		db: db,
+	}
+}
+
+// NewDocsHandler creates a new docs handler for a generated OpenAPI document
+func NewDocsHandler(spec []byte) *DocsHandler {
+	return &DocsHandler{
+		spec: spec,
+	}
+}
+
 // sendJSON sends a JSON response
 func sendJSON(c *fiber.Ctx, status int, data any) error {
 	return c.Status(status).JSON(data)
 }
 
-// sendError sends an error response
+// sendError sends an error response with the generic error code of its status
 func sendError(c *fiber.Ctx, status int, message string) error {
+	return sendErrorCode(c, status, errorCode(status), message)
+}
+
+// sendErrorCode sends an error response with a specific error code
+func sendErrorCode(c *fiber.Ctx, status int, code, message string) error {
 	return c.Status(status).JSON(fiber.Map{
 		"error": message,
+		"code":  code,
 	})
 }
 
+// errorCode returns the generic error code for an HTTP status
+func errorCode(status int) string {
+	switch status {
+	case fiber.StatusBadRequest, fiber.StatusRequestEntityTooLarge, fiber.StatusUnsupportedMediaType:
+		return model.CodeValidation
+	case fiber.StatusUnauthorized:
+		return model.CodeUnauthorized
+	case fiber.StatusForbidden:
+		return model.CodeForbidden
+	case fiber.StatusNotFound:
+		return model.CodeNotFound
+	case fiber.StatusConflict:
+		return model.CodeConflict
+	case fiber.StatusTooManyRequests:
+		return model.CodeRateLimited
+	case fiber.StatusServiceUnavailable:
+		return model.CodeUnavailable
+	default:
+		return model.CodeInternal
+	}
+}
+
 // getUserID gets the user ID from the request context
 func getUserID(c *fiber.Ctx) (string, bool) {
 	userID := c.Locals("user_id")
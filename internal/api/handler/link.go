@@ -1,10 +1,13 @@
 package handler
 
 import (
+	"errors"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 
 	"github.com/momokii/go-cli-notes/internal/model"
+	"github.com/momokii/go-cli-notes/internal/repository"
 	"github.com/momokii/go-cli-notes/internal/service"
 )
 
@@ -110,6 +113,7 @@ func (h *LinkHandler) GetBacklinks(c *fiber.Ctx) error {
 }
 
 // GetLinkGraph handles GET /api/v1/notes/graph
+// With ?root=<id>&depth=N only notes within N hops of the root are returned
 func (h *LinkHandler) GetLinkGraph(c *fiber.Ctx) error {
 	userIDStr, ok := getUserID(c)
 	if !ok {
@@ -127,6 +131,29 @@ func (h *LinkHandler) GetLinkGraph(c *fiber.Ctx) error {
 		return sendError(c, fiber.StatusInternalServerError, "Service error")
 	}
 
+	// Get the local graph around a note if a root is given
+	if rootStr := c.Query("root"); rootStr != "" {
+		rootID, err := uuid.Parse(rootStr)
+		if err != nil {
+			return sendError(c, fiber.StatusBadRequest, "Invalid root note ID")
+		}
+
+		depth := c.QueryInt("depth", 2)
+		if depth < 1 || depth > 5 {
+			depth = 2
+		}
+
+		graph, err := svc.GetLocalGraph(c.Context(), userID, rootID, depth)
+		if errors.Is(err, repository.ErrNotFound) {
+			return sendError(c, fiber.StatusNotFound, "Note not found")
+		}
+		if err != nil {
+			return sendError(c, fiber.StatusInternalServerError, "Failed to get link graph")
+		}
+
+		return sendJSON(c, fiber.StatusOK, graph)
+	}
+
 	// Get the full knowledge graph
 	graph, err := svc.GetLinkGraph(c.Context(), userID)
 	if err != nil {
@@ -135,3 +162,70 @@ func (h *LinkHandler) GetLinkGraph(c *fiber.Ctx) error {
 
 	return sendJSON(c, fiber.StatusOK, graph)
 }
+
+// GetUnresolvedLinks handles GET /api/v1/links/unresolved
+func (h *LinkHandler) GetUnresolvedLinks(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	// Get note service
+	svc, ok := h.noteService.(*service.NoteService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	// Get links whose target note doesn't exist yet
+	links, err := svc.ListUnresolvedLinks(c.Context(), userID)
+	if err != nil {
+		return sendError(c, fiber.StatusInternalServerError, "Failed to get unresolved links")
+	}
+
+	return sendJSON(c, fiber.StatusOK, links)
+}
+
+// GetGraphPath handles GET /api/v1/notes/graph/path?from=<id>&to=<id>
+func (h *LinkHandler) GetGraphPath(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	fromID, err := uuid.Parse(c.Query("from"))
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid from note ID")
+	}
+	toID, err := uuid.Parse(c.Query("to"))
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid to note ID")
+	}
+
+	svc, ok := h.noteService.(*service.NoteService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	path, err := svc.FindPath(c.Context(), userID, fromID, toID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return sendError(c, fiber.StatusNotFound, "Note not found")
+		}
+		if errors.Is(err, model.ErrNoPath) {
+			return sendError(c, fiber.StatusNotFound, "No path between the notes")
+		}
+		return sendError(c, fiber.StatusInternalServerError, "Failed to find path")
+	}
+
+	return sendJSON(c, fiber.StatusOK, path)
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/momokii/go-cli-notes/internal/repository"
+)
+
+// MetricsHandler serves operational metrics for monitoring
+type MetricsHandler struct {
+	db any // *repository.DB
+}
+
+// poolMetric is a connection pool statistic in the Prometheus text format
+type poolMetric struct {
+	name  string
+	kind  string // gauge or counter
+	help  string
+	value func(repository.PoolStats) float64
+}
+
+// poolMetrics are the exported connection pool statistics
+var poolMetrics = []poolMetric{
+	{"kg_db_pool_max_conns", "gauge", "Maximum connections in the pool.",
+		func(s repository.PoolStats) float64 { return float64(s.MaxConns) }},
+	{"kg_db_pool_total_conns", "gauge", "Open connections.",
+		func(s repository.PoolStats) float64 { return float64(s.TotalConns) }},
+	{"kg_db_pool_acquired_conns", "gauge", "Connections in use.",
+		func(s repository.PoolStats) float64 { return float64(s.AcquiredConns) }},
+	{"kg_db_pool_idle_conns", "gauge", "Idle connections.",
+		func(s repository.PoolStats) float64 { return float64(s.IdleConns) }},
+	{"kg_db_pool_acquires_total", "counter", "Connections handed out.",
+		func(s repository.PoolStats) float64 { return float64(s.AcquireCount) }},
+	{"kg_db_pool_empty_acquires_total", "counter", "Acquires that waited for a connection to free up.",
+		func(s repository.PoolStats) float64 { return float64(s.EmptyAcquireCount) }},
+	{"kg_db_pool_canceled_acquires_total", "counter", "Acquires given up on before a connection freed up.",
+		func(s repository.PoolStats) float64 { return float64(s.CanceledAcquireCount) }},
+	{"kg_db_pool_acquire_timeouts_total", "counter", "Acquires that hit DB_ACQUIRE_TIMEOUT.",
+		func(s repository.PoolStats) float64 { return float64(s.AcquireTimeouts) }},
+	{"kg_db_pool_acquire_seconds_total", "counter", "Time spent acquiring connections.",
+		func(s repository.PoolStats) float64 { return s.AcquireDuration.Seconds() }},
+	{"kg_db_pool_empty_acquire_wait_seconds_total", "counter", "Time spent waiting for a connection to free up.",
+		func(s repository.PoolStats) float64 { return s.EmptyAcquireWaitTime.Seconds() }},
+}
+
+// Metrics handles GET /metrics in the Prometheus text format
+func (h *MetricsHandler) Metrics(c *fiber.Ctx) error {
+	db, ok := h.db.(*repository.DB)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	pools := db.PoolStats()
+
+	var b strings.Builder
+	for _, metric := range poolMetrics {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", metric.name, metric.help, metric.name, metric.kind)
+		for _, pool := range pools {
+			fmt.Fprintf(&b, "%s{pool=%q} %g\n", metric.name, pool.Name, metric.value(pool))
+		}
+	}
+
+	c.Set(fiber.HeaderContentType, "text/plain; version=0.0.4; charset=utf-8")
+	return c.SendString(b.String())
+}
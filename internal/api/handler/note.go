@@ -1,10 +1,23 @@
 package handler
 
 import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 	"github.com/momokii/go-cli-notes/internal/model"
+	"github.com/momokii/go-cli-notes/internal/repository"
 	"github.com/momokii/go-cli-notes/internal/service"
+	"github.com/momokii/go-cli-notes/internal/util"
 )
 
 // Create handles note creation
@@ -38,6 +51,36 @@ func (h *NoteHandler) Create(c *fiber.Ctx) error {
 	return sendJSON(c, fiber.StatusCreated, note)
 }
 
+// CreateBatch handles creating many notes in one request
+func (h *NoteHandler) CreateBatch(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	var reqs []*model.CreateNoteRequest
+	if err := c.BodyParser(&reqs); err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	svc, ok := h.noteService.(*service.NoteService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	resp, err := svc.CreateBatch(c.Context(), userID, reqs)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	return sendJSON(c, fiber.StatusOK, resp)
+}
+
 // List handles note listing
 func (h *NoteHandler) List(c *fiber.Ctx) error {
 	userIDStr, ok := getUserID(c)
@@ -58,9 +101,11 @@ func (h *NoteHandler) List(c *fiber.Ctx) error {
 	tagID := c.Query("tag")
 
 	filter := model.NoteFilter{
-		Page:   page,
-		Limit:  limit,
-		Search: search,
+		Page:      page,
+		Limit:     limit,
+		Search:    search,
+		SortBy:    c.Query("sort_by"),
+		SortOrder: c.Query("sort_order"),
 	}
 
 	if noteType != "" {
@@ -72,12 +117,44 @@ func (h *NoteHandler) List(c *fiber.Ctx) error {
 		filter.TagID = &tagID
 	}
 
+	// status=todo|doing|done lists the notes in one board column, status=any every note on the board
+	if status := model.NoteStatus(c.Query("status")); status != "" {
+		switch status {
+		case model.NoteStatusTodo, model.NoteStatusDoing, model.NoteStatusDone, model.NoteStatusAny:
+			filter.Status = &status
+		default:
+			return sendError(c, fiber.StatusBadRequest, "Invalid status value: "+string(status))
+		}
+	}
+
+	// include=tags,link_counts returns related data with each note
+	for _, field := range strings.Split(c.Query("include"), ",") {
+		switch strings.TrimSpace(field) {
+		case "":
+		case "tags":
+			filter.IncludeTags = true
+		case "link_counts":
+			filter.IncludeLinkCounts = true
+		default:
+			return sendError(c, fiber.StatusBadRequest, "Invalid include value: "+field)
+		}
+	}
+
 	// Call service
 	svc, ok := h.noteService.(*service.NoteService)
 	if !ok {
 		return sendError(c, fiber.StatusInternalServerError, "Service error")
 	}
 
+	// limit=all streams every matching note instead of a page
+	if c.Query("limit") == "all" {
+		// Reject bad options here, a streamed response can't change its status later
+		if err := filter.ValidateSort(); err != nil {
+			return handleError(c, err)
+		}
+		return streamNotes(c, svc, userID, filter)
+	}
+
 	notes, total, err := svc.List(c.Context(), userID, filter)
 	if err != nil {
 		return handleError(c, err)
@@ -100,6 +177,85 @@ func (h *NoteHandler) List(c *fiber.Ctx) error {
 	})
 }
 
+// streamFlushEvery is how many streamed notes are buffered before flushing to the client
+const streamFlushEvery = 100
+
+// streamNotes writes every note matching the filter as NDJSON, one note per line
+// Notes are read from the database as they are written, so large vaults don't have to fit in memory.
+// A failure after the response has started is reported as a final {"error": "..."} line.
+func streamNotes(c *fiber.Ctx, svc *service.NoteService, userID uuid.UUID, filter model.NoteFilter) error {
+	c.Set(fiber.HeaderContentType, "application/x-ndjson")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		enc := json.NewEncoder(w)
+		count := 0
+
+		// The request context can't be used once the handler has returned
+		err := svc.Stream(context.Background(), userID, filter, func(note *model.Note) error {
+			if err := enc.Encode(note); err != nil {
+				return err
+			}
+			count++
+			if count%streamFlushEvery == 0 {
+				return w.Flush()
+			}
+			return nil
+		})
+		if err != nil {
+			slog.Error("Failed to stream notes", "user_id", userID, "streamed", count, "error", err)
+			_ = enc.Encode(fiber.Map{"error": "Failed to stream notes"})
+		}
+		_ = w.Flush()
+	})
+
+	return nil
+}
+
+// Export handles GET /api/v1/notes/export
+// Streams all notes as a zip archive of Markdown files with YAML frontmatter
+func (h *NoteHandler) Export(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	// Call service
+	svc, ok := h.noteService.(*service.NoteService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	expandEmbeds, ok := parseExpandEmbeds(c)
+	if !ok {
+		return sendError(c, fiber.StatusBadRequest, "Invalid expand value: "+c.Query("expand"))
+	}
+
+	// Load notes up front so errors can still be reported as JSON
+	notes, err := svc.Export(c.Context(), userID, expandEmbeds)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	filename := fmt.Sprintf("kg-export-%s.zip", time.Now().Format("2006-01-02"))
+	c.Set(fiber.HeaderContentType, "application/zip")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := util.WriteMarkdownArchive(w, notes); err != nil {
+			slog.Error("Failed to write export archive", "user_id", userIDStr, "error", err)
+			return
+		}
+		_ = w.Flush()
+	})
+
+	return nil
+}
+
 // GetByID handles getting a single note
 func (h *NoteHandler) GetByID(c *fiber.Ctx) error {
 	userIDStr, ok := getUserID(c)
@@ -124,14 +280,96 @@ func (h *NoteHandler) GetByID(c *fiber.Ctx) error {
 		return sendError(c, fiber.StatusInternalServerError, "Service error")
 	}
 
-	note, err := svc.GetByID(c.Context(), userID, noteID)
+	expandEmbeds, ok := parseExpandEmbeds(c)
+	if !ok {
+		return sendError(c, fiber.StatusBadRequest, "Invalid expand value: "+c.Query("expand"))
+	}
+
+	note, err := svc.Find(c.Context(), userID, noteID)
 	if err != nil {
 		return handleError(c, err)
 	}
 
+	if expandEmbeds {
+		if err := svc.ExpandEmbeds(c.Context(), userID, note); err != nil {
+			return handleError(c, err)
+		}
+	}
+
+	etag, err := noteETag(note)
+	if err != nil {
+		return sendError(c, fiber.StatusInternalServerError, "Failed to tag note")
+	}
+
+	// A revalidated note was still opened, so the view is counted either way
+	svc.RecordView(c.Context(), userID, note)
+
+	c.Set(fiber.HeaderETag, etag)
+	if etagMatches(c.Get(fiber.HeaderIfNoneMatch), etag) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
 	return sendJSON(c, fiber.StatusOK, note)
 }
 
+// noteETag returns a weak ETag of a note, a hash of its JSON without the access count and time
+// Those change on every view, so leaving them out lets an unchanged note be answered with 304.
+func noteETag(note *model.Note) (string, error) {
+	tagged := *note
+	tagged.AccessCount = 0
+	tagged.LastAccessedAt = nil
+
+	body, err := json.Marshal(&tagged)
+	if err != nil {
+		return "", fmt.Errorf("marshal note: %w", err)
+	}
+
+	sum := sha256.Sum256(body)
+	return `W/"` + hex.EncodeToString(sum[:16]) + `"`, nil
+}
+
+// etagMatches reports whether an If-None-Match header names etag, comparing weakly
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// parseIfMatch reads an If-Match header naming the note version an update is based on, as model.NoteVersion tags it
+// No header, or "*" for any version, returns nil.
+func parseIfMatch(ifMatch string) (*time.Time, bool) {
+	ifMatch = strings.TrimSpace(ifMatch)
+	if ifMatch == "" || ifMatch == "*" {
+		return nil, true
+	}
+
+	tag := strings.TrimPrefix(ifMatch, "W/")
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return nil, false
+	}
+	updatedAt, err := time.Parse(time.RFC3339Nano, tag[1:len(tag)-1])
+	if err != nil {
+		return nil, false
+	}
+	return &updatedAt, true
+}
+
+// parseExpandEmbeds reads the expand query parameter, expand=embeds expands ![[...]] embeds inline
+func parseExpandEmbeds(c *fiber.Ctx) (bool, bool) {
+	switch c.Query("expand") {
+	case "":
+		return false, true
+	case "embeds":
+		return true, true
+	default:
+		return false, false
+	}
+}
+
 // Update handles note update
 func (h *NoteHandler) Update(c *fiber.Ctx) error {
 	userIDStr, ok := getUserID(c)
@@ -154,6 +392,9 @@ func (h *NoteHandler) Update(c *fiber.Ctx) error {
 	if err := c.BodyParser(&req); err != nil {
 		return sendError(c, fiber.StatusBadRequest, "Invalid request body")
 	}
+	if req.IfUpdatedAt, ok = parseIfMatch(c.Get(fiber.HeaderIfMatch)); !ok {
+		return sendError(c, fiber.StatusBadRequest, "Invalid If-Match header, want the quoted updated_at of the note")
+	}
 
 	// Call service
 	svc, ok := h.noteService.(*service.NoteService)
@@ -169,6 +410,94 @@ func (h *NoteHandler) Update(c *fiber.Ctx) error {
 	return sendJSON(c, fiber.StatusOK, note)
 }
 
+// Append handles PATCH /api/v1/notes/:id/append
+func (h *NoteHandler) Append(c *fiber.Ctx) error {
+	return h.concat(c, false)
+}
+
+// Prepend handles PATCH /api/v1/notes/:id/prepend
+func (h *NoteHandler) Prepend(c *fiber.Ctx) error {
+	return h.concat(c, true)
+}
+
+// concat adds the request content to the end, or the start, of a note
+func (h *NoteHandler) concat(c *fiber.Ctx, prepend bool) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	noteID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid note ID")
+	}
+
+	var req model.AppendNoteRequest
+	if err := c.BodyParser(&req); err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	// Call service
+	svc, ok := h.noteService.(*service.NoteService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	var note *model.Note
+	if prepend {
+		note, err = svc.Prepend(c.Context(), userID, noteID, &req)
+	} else {
+		note, err = svc.Append(c.Context(), userID, noteID, &req)
+	}
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	return sendJSON(c, fiber.StatusOK, note)
+}
+
+// UpdateMetadata handles PATCH /api/v1/notes/:id/metadata
+// The body is an object of metadata keys to set, null removes a key
+func (h *NoteHandler) UpdateMetadata(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	noteID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid note ID")
+	}
+
+	var metadata model.Metadata
+	if err := c.BodyParser(&metadata); err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	// Call service
+	svc, ok := h.noteService.(*service.NoteService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	note, err := svc.UpdateMetadata(c.Context(), userID, noteID, metadata)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	return sendJSON(c, fiber.StatusOK, note)
+}
+
 // Delete handles note deletion
 func (h *NoteHandler) Delete(c *fiber.Ctx) error {
 	userIDStr, ok := getUserID(c)
@@ -200,6 +529,37 @@ func (h *NoteHandler) Delete(c *fiber.Ctx) error {
 	return sendJSON(c, fiber.StatusOK, fiber.Map{"message": "Note deleted"})
 }
 
+// Restore handles POST /api/v1/notes/:id/restore
+func (h *NoteHandler) Restore(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	noteID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid note ID")
+	}
+
+	// Call service
+	svc, ok := h.noteService.(*service.NoteService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	note, err := svc.Restore(c.Context(), userID, noteID)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	return sendJSON(c, fiber.StatusOK, note)
+}
+
 // GetOrCreateDailyNote handles GET /api/v1/notes/daily/:date
 func (h *NoteHandler) GetOrCreateDailyNote(c *fiber.Ctx) error {
 	userIDStr, ok := getUserID(c)
@@ -212,11 +572,15 @@ func (h *NoteHandler) GetOrCreateDailyNote(c *fiber.Ctx) error {
 		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
 	}
 
-	// Get date from path parameter (YYYY-MM-DD format)
+	// Get date from path parameter (YYYY-MM-DD, today, yesterday, tomorrow or +N/-N)
 	dateStr := c.Params("date")
 	if dateStr == "" {
 		return sendError(c, fiber.StatusBadRequest, "Date parameter is required")
 	}
+	dateStr, err = util.ResolveDailyDate(dateStr, time.Now())
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, err.Error())
+	}
 
 	// Call service
 	svc, ok := h.noteService.(*service.NoteService)
@@ -235,3 +599,404 @@ func (h *NoteHandler) GetOrCreateDailyNote(c *fiber.Ctx) error {
 		"date":       dateStr,
 	})
 }
+
+// ListDailyNotes handles GET /api/v1/notes/daily
+// Query params: month (YYYY-MM; default the current month)
+func (h *NoteHandler) ListDailyNotes(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	month := c.Query("month", time.Now().Format(util.DailyMonthLayout))
+
+	// Call service
+	svc, ok := h.noteService.(*service.NoteService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	days, err := svc.ListDailyNotes(c.Context(), userID, month)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	return sendJSON(c, fiber.StatusOK, fiber.Map{
+		"month": month,
+		"days":  days,
+	})
+}
+
+// Capture handles POST /api/v1/notes/capture
+// The text is appended to the Inbox note or today's daily note; 201 when that note was created
+func (h *NoteHandler) Capture(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	var req model.CaptureRequest
+	if err := c.BodyParser(&req); err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	// Call service
+	svc, ok := h.noteService.(*service.NoteService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	note, isCreated, err := svc.Capture(c.Context(), userID, &req)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	status := fiber.StatusOK
+	if isCreated {
+		status = fiber.StatusCreated
+	}
+
+	return sendJSON(c, status, fiber.Map{
+		"note":       note,
+		"is_created": isCreated,
+	})
+}
+
+// GetInbox handles GET /api/v1/notes/inbox
+func (h *NoteHandler) GetInbox(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	// Call service
+	svc, ok := h.noteService.(*service.NoteService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	inbox, err := svc.GetInbox(c.Context(), userID)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	return sendJSON(c, fiber.StatusOK, inbox)
+}
+
+// ListReading handles GET /api/v1/notes/reading, the notes to continue reading
+// Query params: limit (default 5, max 50)
+func (h *NoteHandler) ListReading(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	// Call service
+	svc, ok := h.noteService.(*service.NoteService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	notes, err := svc.ListReading(c.Context(), userID, c.QueryInt("limit", model.DefaultReadingLimit))
+	if err != nil {
+		if errors.Is(err, model.ErrValidation) {
+			return sendValidationError(c, err)
+		}
+		return sendError(c, fiber.StatusInternalServerError, "Failed to list notes being read")
+	}
+
+	return sendJSON(c, fiber.StatusOK, fiber.Map{
+		"notes": notes,
+		"count": len(notes),
+	})
+}
+
+// SetReadProgress handles PUT /api/v1/notes/:id/progress
+func (h *NoteHandler) SetReadProgress(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	noteID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid note ID")
+	}
+
+	var req model.SetReadProgressRequest
+	if err := c.BodyParser(&req); err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	// Call service
+	svc, ok := h.noteService.(*service.NoteService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	progress, err := svc.SetReadProgress(c.Context(), userID, noteID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return sendError(c, fiber.StatusNotFound, "Note not found")
+		case errors.Is(err, model.ErrValidation):
+			return sendValidationError(c, err)
+		}
+		return sendError(c, fiber.StatusInternalServerError, "Failed to save read progress")
+	}
+
+	return sendJSON(c, fiber.StatusOK, progress)
+}
+
+// CreateWeeklyReview handles POST /api/v1/notes/review
+// The body is optional; date picks the week to review (default the current week)
+func (h *NoteHandler) CreateWeeklyReview(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	var req model.WeeklyReviewRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return sendError(c, fiber.StatusBadRequest, "Invalid request body")
+		}
+	}
+
+	// Call service
+	svc, ok := h.noteService.(*service.NoteService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	note, review, isCreated, err := svc.CreateWeeklyReview(c.Context(), userID, &req)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	status := fiber.StatusOK
+	if isCreated {
+		status = fiber.StatusCreated
+	}
+
+	return sendJSON(c, status, fiber.Map{
+		"note":       note,
+		"review":     review,
+		"is_created": isCreated,
+	})
+}
+
+// GetRelated handles GET /api/v1/notes/:id/related
+func (h *NoteHandler) GetRelated(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	noteID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid note ID")
+	}
+
+	limit := c.QueryInt("limit", 10)
+	if limit < 1 || limit > 50 {
+		limit = 10
+	}
+
+	// Call service
+	svc, ok := h.noteService.(*service.NoteService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	related, err := svc.GetRelated(c.Context(), userID, noteID, limit)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return sendError(c, fiber.StatusNotFound, "Note not found")
+		}
+		return sendError(c, fiber.StatusInternalServerError, "Failed to get related notes")
+	}
+
+	return sendJSON(c, fiber.StatusOK, related)
+}
+
+// GetStats handles GET /api/v1/notes/:id/stats
+func (h *NoteHandler) GetStats(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	noteID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid note ID")
+	}
+
+	// Call service
+	svc, ok := h.noteService.(*service.NoteService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	stats, err := svc.GetStats(c.Context(), userID, noteID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return sendError(c, fiber.StatusNotFound, "Note not found")
+		}
+		return sendError(c, fiber.StatusInternalServerError, "Failed to get note stats")
+	}
+
+	return sendJSON(c, fiber.StatusOK, stats)
+}
+
+// LogFocus handles logging a completed focus session on a note
+func (h *NoteHandler) LogFocus(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	noteID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid note ID")
+	}
+
+	var req model.LogFocusRequest
+	if err := c.BodyParser(&req); err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	// Call service
+	svc, ok := h.noteService.(*service.NoteService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	activity, err := svc.LogFocus(c.Context(), userID, noteID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return sendError(c, fiber.StatusNotFound, "Note not found")
+		case errors.Is(err, model.ErrValidation):
+			return sendValidationError(c, err)
+		}
+		return sendError(c, fiber.StatusInternalServerError, "Failed to log focus session")
+	}
+
+	return sendJSON(c, fiber.StatusCreated, activity)
+}
+
+// GetRevisions handles GET /api/v1/notes/:id/revisions
+func (h *NoteHandler) GetRevisions(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	noteID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid note ID")
+	}
+
+	// Call service
+	svc, ok := h.noteService.(*service.NoteService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	revisions, err := svc.ListRevisions(c.Context(), userID, noteID)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	return sendJSON(c, fiber.StatusOK, fiber.Map{
+		"revisions": revisions,
+		"count":     len(revisions),
+	})
+}
+
+// RestoreRevision handles POST /api/v1/notes/:id/revisions/:rev/restore
+func (h *NoteHandler) RestoreRevision(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	noteID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid note ID")
+	}
+
+	revNumber, err := c.ParamsInt("rev")
+	if err != nil || revNumber < 1 {
+		return sendError(c, fiber.StatusBadRequest, "Invalid revision number")
+	}
+
+	// Call service
+	svc, ok := h.noteService.(*service.NoteService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	note, err := svc.RestoreRevision(c.Context(), userID, noteID, revNumber)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	return sendJSON(c, fiber.StatusOK, note)
+}
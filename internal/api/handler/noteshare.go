@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+	"github.com/momokii/go-cli-notes/internal/repository"
+	"github.com/momokii/go-cli-notes/internal/service"
+)
+
+// NoteShareHandler handles HTTP requests for notes shared between users
+type NoteShareHandler struct {
+	noteShareService any // NoteShareService interface
+}
+
+// ShareNote handles POST /api/v1/notes/:id/shares
+// Returns 201 for a new share, or 200 when the permission of an existing one was changed
+func (h *NoteShareHandler) ShareNote(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	noteID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid note ID")
+	}
+
+	var req model.ShareNoteRequest
+	if err := c.BodyParser(&req); err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	svc, ok := h.noteShareService.(*service.NoteShareService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	share, created, err := svc.Share(c.Context(), userID, noteID, &req)
+	if err != nil {
+		return noteShareError(c, err, "Failed to share note")
+	}
+
+	status := fiber.StatusOK
+	if created {
+		status = fiber.StatusCreated
+	}
+	return sendJSON(c, status, share)
+}
+
+// ListShares handles GET /api/v1/notes/:id/shares
+func (h *NoteShareHandler) ListShares(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	noteID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid note ID")
+	}
+
+	svc, ok := h.noteShareService.(*service.NoteShareService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	shares, err := svc.List(c.Context(), userID, noteID)
+	if err != nil {
+		return noteShareError(c, err, "Failed to list note shares")
+	}
+
+	return sendJSON(c, fiber.StatusOK, fiber.Map{
+		"shares": shares,
+		"count":  len(shares),
+	})
+}
+
+// UnshareNote handles DELETE /api/v1/notes/:id/shares/:user_id
+func (h *NoteShareHandler) UnshareNote(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	noteID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid note ID")
+	}
+
+	sharedWith, err := uuid.Parse(c.Params("user_id"))
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	svc, ok := h.noteShareService.(*service.NoteShareService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	if err := svc.Unshare(c.Context(), userID, noteID, sharedWith); err != nil {
+		return noteShareError(c, err, "Failed to stop sharing note")
+	}
+
+	return sendJSON(c, fiber.StatusOK, fiber.Map{
+		"message": "Note is no longer shared with the user",
+	})
+}
+
+// SharedWithMe handles GET /api/v1/notes/shared
+func (h *NoteShareHandler) SharedWithMe(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	svc, ok := h.noteShareService.(*service.NoteShareService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	notes, err := svc.SharedWithMe(c.Context(), userID)
+	if err != nil {
+		return noteShareError(c, err, "Failed to list shared notes")
+	}
+
+	return sendJSON(c, fiber.StatusOK, fiber.Map{
+		"notes": notes,
+		"count": len(notes),
+	})
+}
+
+// noteShareError maps note share service errors to HTTP responses
+func noteShareError(c *fiber.Ctx, err error, fallback string) error {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return sendError(c, fiber.StatusNotFound, "Resource not found")
+	case errors.Is(err, model.ErrValidation):
+		return sendValidationError(c, err)
+	default:
+		return sendError(c, fiber.StatusInternalServerError, fallback)
+	}
+}
@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+	"github.com/momokii/go-cli-notes/internal/repository"
+	"github.com/momokii/go-cli-notes/internal/service"
+)
+
+// NoteTypeHandler handles note type HTTP requests
+type NoteTypeHandler struct {
+	noteService any // NoteService interface
+}
+
+// ListNoteTypes handles GET /api/v1/note-types
+func (h *NoteTypeHandler) ListNoteTypes(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	svc, ok := h.noteService.(*service.NoteService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	noteTypes, err := svc.ListNoteTypes(c.Context(), userID)
+	if err != nil {
+		return sendError(c, fiber.StatusInternalServerError, "Failed to list note types")
+	}
+
+	return sendJSON(c, fiber.StatusOK, fiber.Map{
+		"note_types": noteTypes,
+		"count":      len(noteTypes),
+	})
+}
+
+// CreateNoteType handles POST /api/v1/note-types
+func (h *NoteTypeHandler) CreateNoteType(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	var req model.CreateNoteTypeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	svc, ok := h.noteService.(*service.NoteService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	noteType, err := svc.CreateNoteType(c.Context(), userID, &req)
+	if err != nil {
+		return noteTypeError(c, err, "Failed to create note type")
+	}
+
+	return sendJSON(c, fiber.StatusCreated, noteType)
+}
+
+// UpdateNoteType handles PUT /api/v1/note-types/:name
+func (h *NoteTypeHandler) UpdateNoteType(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	var req model.UpdateNoteTypeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	svc, ok := h.noteService.(*service.NoteService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	noteType, err := svc.UpdateNoteType(c.Context(), userID, model.NoteType(c.Params("name")), &req)
+	if err != nil {
+		return noteTypeError(c, err, "Failed to update note type")
+	}
+
+	return sendJSON(c, fiber.StatusOK, noteType)
+}
+
+// SetNoteTypeFields handles PUT /api/v1/note-types/:name/fields
+func (h *NoteTypeHandler) SetNoteTypeFields(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	var req model.SetNoteTypeFieldsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	svc, ok := h.noteService.(*service.NoteService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	noteType, err := svc.SetNoteTypeFields(c.Context(), userID, model.NoteType(c.Params("name")), &req)
+	if err != nil {
+		return noteTypeError(c, err, "Failed to set note type fields")
+	}
+
+	return sendJSON(c, fiber.StatusOK, noteType)
+}
+
+// DeleteNoteType handles DELETE /api/v1/note-types/:name
+func (h *NoteTypeHandler) DeleteNoteType(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	svc, ok := h.noteService.(*service.NoteService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	if err := svc.DeleteNoteType(c.Context(), userID, model.NoteType(c.Params("name"))); err != nil {
+		return noteTypeError(c, err, "Failed to delete note type")
+	}
+
+	return sendJSON(c, fiber.StatusNoContent, nil)
+}
+
+// noteTypeError maps note type service errors to responses
+func noteTypeError(c *fiber.Ctx, err error, message string) error {
+	switch {
+	case errors.Is(err, model.ErrValidation):
+		return sendValidationError(c, err)
+	case errors.Is(err, repository.ErrNotFound):
+		return sendError(c, fiber.StatusNotFound, "Note type not found")
+	case errors.Is(err, model.ErrDuplicate):
+		return sendError(c, fiber.StatusConflict, "Note type with this name already exists")
+	case errors.Is(err, model.ErrNoteTypeInUse):
+		return sendError(c, fiber.StatusConflict, err.Error())
+	default:
+		return sendError(c, fiber.StatusInternalServerError, message)
+	}
+}
@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"html/template"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+	"github.com/momokii/go-cli-notes/internal/repository"
+	"github.com/momokii/go-cli-notes/internal/service"
+)
+
+// publicPage renders a published note as a standalone, read-only HTML page
+var publicPage = template.Must(template.New("public").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <meta name="robots" content="noindex">
+  <title>{{.Title}}</title>
+  <style>
+    body { max-width: 46rem; margin: 2rem auto; padding: 0 1rem; font: 17px/1.6 system-ui, sans-serif; color: #222; }
+    h1, h2, h3 { line-height: 1.25; }
+    a { color: #2f6f4f; }
+    pre { background: #f4f4f4; padding: .75rem; overflow-x: auto; }
+    code { background: #f4f4f4; padding: 0 .2rem; }
+    pre code { padding: 0; }
+    blockquote { margin: 0; padding-left: 1rem; border-left: 3px solid #ccc; color: #555; }
+    table { border-collapse: collapse; }
+    th, td { border: 1px solid #ccc; padding: .25rem .5rem; }
+    img { max-width: 100%; }
+    .backlinks, footer { margin-top: 3rem; padding-top: 1rem; border-top: 1px solid #ddd; font-size: .9rem; color: #666; }
+  </style>
+</head>
+<body>
+  <article>
+    <h1>{{.Title}}</h1>
+    {{.HTML}}
+  </article>
+  {{if .Backlinks}}
+  <section class="backlinks">
+    <h2>Linked from</h2>
+    <ul>
+      {{range .Backlinks}}<li><a href="{{.Path}}">{{.Title}}</a></li>
+      {{end}}
+    </ul>
+  </section>
+  {{end}}
+  <footer>Updated {{.UpdatedAt.Format "January 2, 2006"}} · Published from Knowledge Garden</footer>
+</body>
+</html>
+`))
+
+// publicNotFoundPage is shown for revoked and unknown public links
+const publicNotFoundPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <meta name="robots" content="noindex">
+  <title>Note not found</title>
+</head>
+<body style="font: 17px/1.6 system-ui, sans-serif; text-align: center; margin-top: 4rem;">
+  <h1>Note not found</h1>
+  <p>This link was revoked, or never existed.</p>
+</body>
+</html>
+`
+
+// PublicLinkHandler handles public note link HTTP requests
+type PublicLinkHandler struct {
+	publicLinkService any // PublicLinkService interface
+}
+
+// PublishNote handles POST /api/v1/notes/:id/share
+// Returns 201 with a new link, or 200 with the link the note already had
+func (h *PublicLinkHandler) PublishNote(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	noteID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid note ID")
+	}
+
+	svc, ok := h.publicLinkService.(*service.PublicLinkService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	link, created, err := svc.Publish(c.Context(), userID, noteID, c.BaseURL())
+	if err != nil {
+		return publicLinkError(c, err, "Failed to publish note")
+	}
+
+	status := fiber.StatusOK
+	if created {
+		status = fiber.StatusCreated
+	}
+	return sendJSON(c, status, link)
+}
+
+// UnpublishNote handles DELETE /api/v1/notes/:id/share
+func (h *PublicLinkHandler) UnpublishNote(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	noteID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid note ID")
+	}
+
+	svc, ok := h.publicLinkService.(*service.PublicLinkService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	if err := svc.Unpublish(c.Context(), userID, noteID); err != nil {
+		return publicLinkError(c, err, "Failed to revoke public link")
+	}
+
+	return sendJSON(c, fiber.StatusOK, fiber.Map{
+		"message": "Public link revoked successfully",
+	})
+}
+
+// ListPublicLinks handles GET /api/v1/public-links
+func (h *PublicLinkHandler) ListPublicLinks(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	svc, ok := h.publicLinkService.(*service.PublicLinkService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	links, err := svc.List(c.Context(), userID, c.BaseURL())
+	if err != nil {
+		return publicLinkError(c, err, "Failed to list public links")
+	}
+
+	return sendJSON(c, fiber.StatusOK, fiber.Map{
+		"links": links,
+		"count": len(links),
+	})
+}
+
+// PublicNote handles GET /s/:token, the public page of a published note
+func (h *PublicLinkHandler) PublicNote(c *fiber.Ctx) error {
+	svc, ok := h.publicLinkService.(*service.PublicLinkService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	// Pages may link to other published notes: don't pass their tokens on as referrers
+	c.Set("Referrer-Policy", "no-referrer")
+	c.Set("X-Robots-Tag", "noindex")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set("Content-Security-Policy", "default-src 'none'; img-src * data:; style-src 'unsafe-inline'")
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+
+	page, err := svc.Page(c.Context(), c.Params("token"))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).SendString(publicNotFoundPage)
+		}
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to load note")
+	}
+
+	var buf bytes.Buffer
+	err = publicPage.Execute(&buf, struct {
+		*model.PublicNotePage
+		HTML template.HTML
+	}{page, template.HTML(page.HTML)}) // Rendered by goldmark, which leaves raw HTML out
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to render note")
+	}
+
+	return c.Send(buf.Bytes())
+}
+
+// publicLinkError maps public link service errors to HTTP responses
+func publicLinkError(c *fiber.Ctx, err error, fallback string) error {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return sendError(c, fiber.StatusNotFound, "Resource not found")
+	case errors.Is(err, model.ErrValidation):
+		return sendValidationError(c, err)
+	default:
+		return sendError(c, fiber.StatusInternalServerError, fallback)
+	}
+}
@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"errors"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 
@@ -10,7 +12,8 @@ import (
 
 // SearchHandler handles search requests
 type SearchHandler struct {
-	noteService any // NoteService interface
+	noteService      any // NoteService interface
+	embeddingService any // EmbeddingService interface
 }
 
 // Search handles GET /api/v1/search
@@ -35,6 +38,7 @@ func (h *SearchHandler) Search(c *fiber.Ctx) error {
 	limit := c.QueryInt("limit", 20)
 	noteType := c.Query("type")
 	tagID := c.Query("tag_id")
+	fragmentSize := c.QueryInt("fragment_size", model.DefaultFragmentSize)
 
 	if page < 1 {
 		page = 1
@@ -44,11 +48,16 @@ func (h *SearchHandler) Search(c *fiber.Ctx) error {
 	}
 
 	// Build filter
+	// Best matches first unless another order is asked for
 	filter := model.NoteFilter{
-		Page:   page,
-		Limit:  limit,
-		Search: query,
-		SortBy: "created_at",
+		Page:      page,
+		Limit:     limit,
+		Search:    query,
+		SortBy:    c.Query("sort_by", model.SortRelevance),
+		SortOrder: c.Query("sort_order"),
+	}
+	if err := filter.ValidateSort(); err != nil {
+		return handleError(c, err)
 	}
 
 	if noteType != "" {
@@ -66,20 +75,27 @@ func (h *SearchHandler) Search(c *fiber.Ctx) error {
 		return sendError(c, fiber.StatusInternalServerError, "Service error")
 	}
 
-	// Search notes (uses List method internally with search filter)
-	notes, total, err := svc.Search(c.Context(), userID, filter)
+	// Search notes, ranked and with highlighted snippets
+	results, total, err := svc.Search(c.Context(), userID, filter, fragmentSize)
 	if err != nil {
+		if errors.Is(err, model.ErrValidation) {
+			return handleError(c, err)
+		}
 		return sendError(c, fiber.StatusInternalServerError, "Failed to search notes")
 	}
 
-	// Build search results
-	results := make([]*model.SearchResult, 0, len(notes))
-	for _, note := range notes {
-		results = append(results, &model.SearchResult{
-			Note:    note,
-			Rank:    1.0, // Default rank (can be improved with ts_rank)
-			Snippet: generateSnippet(note.Content, query),
-		})
+	// Nothing matched the words themselves, so look for titles spelled similarly
+	fuzzy := total == 0 && c.QueryBool("fuzzy")
+	if fuzzy {
+		results, total, err = svc.FuzzySearch(c.Context(), userID, filter, fragmentSize)
+		if err != nil {
+			return sendError(c, fiber.StatusInternalServerError, "Failed to search notes")
+		}
+	}
+
+	// Paging through results isn't a new search
+	if page == 1 {
+		svc.RecordSearch(c.Context(), userID, query, total)
 	}
 
 	// Calculate pagination
@@ -91,6 +107,7 @@ func (h *SearchHandler) Search(c *fiber.Ctx) error {
 	response := &model.SearchResponse{
 		Query:   query,
 		Results: results,
+		Fuzzy:   fuzzy,
 		Pagination: &model.Pagination{
 			Page:       page,
 			Limit:      limit,
@@ -102,13 +119,67 @@ func (h *SearchHandler) Search(c *fiber.Ctx) error {
 	return sendJSON(c, fiber.StatusOK, response)
 }
 
-// generateSnippet creates a highlighted snippet from content
-func generateSnippet(content, query string) string {
-	// Simple snippet generation - take first 200 chars
-	// In production, use ts_headline from PostgreSQL
-	maxLen := 200
-	if len(content) <= maxLen {
-		return content
+// History handles GET /api/v1/search/history
+func (h *SearchHandler) History(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	svc, ok := h.noteService.(*service.NoteService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
 	}
-	return content[:maxLen] + "..."
+
+	entries, err := svc.SearchHistory(c.Context(), userID, c.QueryInt("limit", model.DefaultSearchHistoryLimit))
+	if err != nil {
+		if errors.Is(err, model.ErrValidation) {
+			return handleError(c, err)
+		}
+		return sendError(c, fiber.StatusInternalServerError, "Failed to get search history")
+	}
+
+	return sendJSON(c, fiber.StatusOK, &model.SearchHistoryResponse{Queries: entries})
+}
+
+// SemanticSearch handles POST /api/v1/search/semantic
+func (h *SearchHandler) SemanticSearch(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	var req model.SemanticSearchRequest
+	if err := c.BodyParser(&req); err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	svc, ok := h.embeddingService.(*service.EmbeddingService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	response, err := svc.Search(c.Context(), userID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrSemanticSearchDisabled):
+			return sendError(c, fiber.StatusServiceUnavailable, "Semantic search is not enabled on this server")
+		case errors.Is(err, model.ErrValidation):
+			return handleError(c, err)
+		default:
+			return sendError(c, fiber.StatusInternalServerError, "Failed to search notes")
+		}
+	}
+
+	return sendJSON(c, fiber.StatusOK, response)
 }
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+	"github.com/momokii/go-cli-notes/internal/service"
+)
+
+// SettingsHandler handles user settings HTTP requests
+type SettingsHandler struct {
+	noteService any // NoteService interface
+}
+
+// GetSettings handles GET /api/v1/settings
+func (h *SettingsHandler) GetSettings(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	// Get note service
+	svc, ok := h.noteService.(*service.NoteService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	settings, err := svc.GetSettings(c.Context(), userID)
+	if err != nil {
+		return sendError(c, fiber.StatusInternalServerError, "Failed to get settings")
+	}
+
+	return sendJSON(c, fiber.StatusOK, settings)
+}
+
+// UpdateSettings handles PUT /api/v1/settings
+func (h *SettingsHandler) UpdateSettings(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	var req model.UpdateSettingsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	// Get note service
+	svc, ok := h.noteService.(*service.NoteService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	settings, err := svc.UpdateSettings(c.Context(), userID, &req)
+	if err != nil {
+		if errors.Is(err, model.ErrValidation) {
+			return sendValidationError(c, err)
+		}
+		return sendError(c, fiber.StatusInternalServerError, "Failed to update settings")
+	}
+
+	return sendJSON(c, fiber.StatusOK, settings)
+}
+
+// GetDailyTemplate handles GET /api/v1/settings/daily-template
+func (h *SettingsHandler) GetDailyTemplate(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	// Get note service
+	svc, ok := h.noteService.(*service.NoteService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	template, isDefault, err := svc.GetDailyTemplate(c.Context(), userID)
+	if err != nil {
+		return sendError(c, fiber.StatusInternalServerError, "Failed to get daily template")
+	}
+
+	return sendJSON(c, fiber.StatusOK, &model.DailyTemplateResponse{
+		Template:  template,
+		IsDefault: isDefault,
+	})
+}
+
+// UpdateDailyTemplate handles PUT /api/v1/settings/daily-template
+func (h *SettingsHandler) UpdateDailyTemplate(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	var req model.UpdateDailyTemplateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	// Get note service
+	svc, ok := h.noteService.(*service.NoteService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	if err := svc.SetDailyTemplate(c.Context(), userID, &req); err != nil {
+		if errors.Is(err, model.ErrValidation) {
+			return sendValidationError(c, err)
+		}
+		return sendError(c, fiber.StatusInternalServerError, "Failed to update daily template")
+	}
+
+	// Return the effective template (the default after a reset)
+	template, isDefault, err := svc.GetDailyTemplate(c.Context(), userID)
+	if err != nil {
+		return sendError(c, fiber.StatusInternalServerError, "Failed to get daily template")
+	}
+
+	return sendJSON(c, fiber.StatusOK, &model.DailyTemplateResponse{
+		Template:  template,
+		IsDefault: isDefault,
+	})
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+	"github.com/momokii/go-cli-notes/internal/repository"
+	"github.com/momokii/go-cli-notes/internal/service"
+)
+
+// SummaryHandler handles note summary HTTP requests
+type SummaryHandler struct {
+	summaryService any // SummaryService interface
+}
+
+// Summarize handles POST /api/v1/notes/:id/summarize
+func (h *SummaryHandler) Summarize(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	noteID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid note ID")
+	}
+
+	svc, ok := h.summaryService.(*service.SummaryService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	summary, err := svc.Summarize(c.Context(), userID, noteID)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrSummarizationDisabled):
+			return sendError(c, fiber.StatusServiceUnavailable, "Summarization is not enabled on this server")
+		case errors.Is(err, repository.ErrNotFound):
+			return sendError(c, fiber.StatusNotFound, "Note not found")
+		case errors.Is(err, model.ErrValidation):
+			return handleError(c, err)
+		default:
+			slog.Error("Failed to summarize note", "note_id", noteID, "error", err)
+			return sendError(c, fiber.StatusInternalServerError, "Failed to summarize note")
+		}
+	}
+
+	return sendJSON(c, fiber.StatusOK, summary)
+}
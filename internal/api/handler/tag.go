@@ -1,12 +1,14 @@
 package handler
 
 import (
+	"errors"
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 
 	"github.com/momokii/go-cli-notes/internal/model"
+	"github.com/momokii/go-cli-notes/internal/repository"
 	"github.com/momokii/go-cli-notes/internal/service"
 )
 
@@ -68,6 +70,40 @@ func (h *TagHandler) ListTags(c *fiber.Ctx) error {
 	return sendJSON(c, fiber.StatusOK, response)
 }
 
+// GetAnalytics handles GET /api/v1/tags/analytics
+func (h *TagHandler) GetAnalytics(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	months := c.QueryInt("months", 6)
+	if months < 1 || months > 24 {
+		months = 6
+	}
+	pairs := c.QueryInt("pairs", 10)
+	if pairs < 1 || pairs > 50 {
+		pairs = 10
+	}
+
+	svc, ok := h.tagService.(*service.TagService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	analytics, err := svc.GetAnalytics(c.Context(), userID, months, pairs)
+	if err != nil {
+		return sendError(c, fiber.StatusInternalServerError, "Failed to get tag analytics")
+	}
+
+	return sendJSON(c, fiber.StatusOK, analytics)
+}
+
 // GetTag handles GET /api/v1/tags/:id
 func (h *TagHandler) GetTag(c *fiber.Ctx) error {
 	userIDStr, ok := getUserID(c)
@@ -266,6 +302,43 @@ func (h *TagHandler) RemoveTagFromNote(c *fiber.Ctx) error {
 	return sendJSON(c, fiber.StatusOK, fiber.Map{"message": "Tag removed from note"})
 }
 
+// BulkTagNotes handles POST /api/v1/notes/tags/bulk
+func (h *TagHandler) BulkTagNotes(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	var req model.BulkTagRequest
+	if err := c.BodyParser(&req); err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	svc, ok := h.tagService.(*service.TagService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	resp, err := svc.BulkUpdateNotes(c.Context(), userID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrValidation):
+			return sendValidationError(c, err)
+		case errors.Is(err, repository.ErrNotFound):
+			return sendError(c, fiber.StatusNotFound, "Tag or note not found")
+		default:
+			return sendError(c, fiber.StatusInternalServerError, "Failed to update note tags")
+		}
+	}
+
+	return sendJSON(c, fiber.StatusOK, resp)
+}
+
 // GetNoteTags handles GET /api/v1/notes/:id/tags
 func (h *TagHandler) GetNoteTags(c *fiber.Ctx) error {
 	userIDStr, ok := getUserID(c)
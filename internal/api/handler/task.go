@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+	"github.com/momokii/go-cli-notes/internal/service"
+)
+
+// TaskHandler handles task HTTP requests
+type TaskHandler struct {
+	noteService any // NoteService interface
+}
+
+// ListTasks handles GET /api/v1/tasks
+// Query params: status (open, done or all; default open)
+func (h *TaskHandler) ListTasks(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	// Get note service
+	svc, ok := h.noteService.(*service.NoteService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	req := &model.ListTasksRequest{
+		Status: model.TaskStatus(c.Query("status")),
+	}
+
+	tasks, err := svc.ListTasks(c.Context(), userID, req)
+	if err != nil {
+		if errors.Is(err, model.ErrValidation) {
+			return sendError(c, fiber.StatusBadRequest, "Invalid status (use open, done or all)")
+		}
+		return sendError(c, fiber.StatusInternalServerError, "Failed to list tasks")
+	}
+
+	return sendJSON(c, fiber.StatusOK, fiber.Map{
+		"tasks": tasks,
+		"count": len(tasks),
+	})
+}
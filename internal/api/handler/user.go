@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+	"github.com/momokii/go-cli-notes/internal/service"
+)
+
+// UserHandler handles account management HTTP requests for the signed-in user
+type UserHandler struct {
+	authService any // AuthService interface
+}
+
+// ChangePassword handles PUT /api/v1/users/me/password
+func (h *UserHandler) ChangePassword(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	var req model.ChangePasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	// Call service
+	svc, ok := h.authService.(*service.AuthService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	resp, err := svc.ChangePassword(c.Context(), userID, &req, clientInfo(c))
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	return sendJSON(c, fiber.StatusOK, resp)
+}
+
+// DeleteAccount handles DELETE /api/v1/users/me
+func (h *UserHandler) DeleteAccount(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	var req model.DeleteAccountRequest
+	if err := c.BodyParser(&req); err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	// Call service
+	svc, ok := h.authService.(*service.AuthService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	if err := svc.DeleteAccount(c.Context(), userID, &req, clientInfo(c)); err != nil {
+		return handleError(c, err)
+	}
+
+	return sendJSON(c, fiber.StatusOK, fiber.Map{"message": "Account deleted"})
+}
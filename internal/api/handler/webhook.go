@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+	"github.com/momokii/go-cli-notes/internal/repository"
+	"github.com/momokii/go-cli-notes/internal/service"
+)
+
+// WebhookHandler handles webhook HTTP requests
+type WebhookHandler struct {
+	webhookService any // WebhookService interface
+}
+
+// CreateWebhook handles POST /api/v1/webhooks
+// The response is the only one that includes the signing secret
+func (h *WebhookHandler) CreateWebhook(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	var req model.CreateWebhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	svc, ok := h.webhookService.(*service.WebhookService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	hook, err := svc.Create(c.Context(), userID, &req)
+	if err != nil {
+		return webhookError(c, err, "Failed to create webhook")
+	}
+
+	return sendJSON(c, fiber.StatusCreated, hook)
+}
+
+// ListWebhooks handles GET /api/v1/webhooks
+func (h *WebhookHandler) ListWebhooks(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	svc, ok := h.webhookService.(*service.WebhookService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	hooks, err := svc.List(c.Context(), userID)
+	if err != nil {
+		return sendError(c, fiber.StatusInternalServerError, "Failed to list webhooks")
+	}
+
+	return sendJSON(c, fiber.StatusOK, fiber.Map{
+		"webhooks": hooks,
+		"count":    len(hooks),
+	})
+}
+
+// UpdateWebhook handles PUT /api/v1/webhooks/:id
+func (h *WebhookHandler) UpdateWebhook(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	webhookID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid webhook ID")
+	}
+
+	var req model.UpdateWebhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	svc, ok := h.webhookService.(*service.WebhookService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	hook, err := svc.Update(c.Context(), userID, webhookID, &req)
+	if err != nil {
+		return webhookError(c, err, "Failed to update webhook")
+	}
+
+	return sendJSON(c, fiber.StatusOK, hook)
+}
+
+// DeleteWebhook handles DELETE /api/v1/webhooks/:id
+func (h *WebhookHandler) DeleteWebhook(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	webhookID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid webhook ID")
+	}
+
+	svc, ok := h.webhookService.(*service.WebhookService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	if err := svc.Delete(c.Context(), userID, webhookID); err != nil {
+		return webhookError(c, err, "Failed to delete webhook")
+	}
+
+	return sendJSON(c, fiber.StatusOK, fiber.Map{
+		"message": "Webhook deleted",
+	})
+}
+
+// ListDeliveries handles GET /api/v1/webhooks/:id/deliveries
+// Newest first, ?limit= defaults to 20 (max 100)
+func (h *WebhookHandler) ListDeliveries(c *fiber.Ctx) error {
+	userIDStr, ok := getUserID(c)
+	if !ok {
+		return sendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	webhookID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid webhook ID")
+	}
+
+	limit := c.QueryInt("limit", 20)
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	svc, ok := h.webhookService.(*service.WebhookService)
+	if !ok {
+		return sendError(c, fiber.StatusInternalServerError, "Service error")
+	}
+
+	deliveries, err := svc.Deliveries(c.Context(), userID, webhookID, limit)
+	if err != nil {
+		return webhookError(c, err, "Failed to list webhook deliveries")
+	}
+
+	return sendJSON(c, fiber.StatusOK, fiber.Map{
+		"deliveries": deliveries,
+		"count":      len(deliveries),
+	})
+}
+
+// webhookError maps webhook service errors to HTTP responses
+func webhookError(c *fiber.Ctx, err error, fallback string) error {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return sendError(c, fiber.StatusNotFound, "Webhook not found")
+	case errors.Is(err, model.ErrValidation):
+		return sendValidationError(c, err)
+	default:
+		return sendError(c, fiber.StatusInternalServerError, fallback)
+	}
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+	"github.com/momokii/go-cli-notes/internal/service"
+)
+
+// Admin middleware only lets active admins through
+// It must run after Auth, which puts the user ID in the context.
+func Admin(adminService any) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userIDStr, ok := c.Locals("user_id").(string)
+		if !ok {
+			return unauthorized(c, "Missing user")
+		}
+
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			return unauthorized(c, "Invalid user ID in token")
+		}
+
+		// Type assertion for admin service
+		svc, ok := adminService.(*service.AdminService)
+		if !ok {
+			return forbidden(c, "Invalid admin service")
+		}
+
+		isAdmin, err := svc.IsAdmin(c.Context(), userID)
+		if err != nil {
+			slog.Error("Failed to check admin role", "user_id", userID, "error", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error":   "internal_error",
+				"code":    model.CodeInternal,
+				"message": "Failed to check permissions",
+			})
+		}
+		if !isAdmin {
+			return forbidden(c, "Admin access required")
+		}
+
+		return c.Next()
+	}
+}
+
+// forbidden returns a forbidden error response
+func forbidden(c *fiber.Ctx, message string) error {
+	return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+		"error":   "forbidden",
+		"code":    model.CodeForbidden,
+		"message": message,
+	})
+}
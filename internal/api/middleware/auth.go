@@ -6,6 +6,7 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 
+	"github.com/momokii/go-cli-notes/internal/model"
 	"github.com/momokii/go-cli-notes/internal/util"
 )
 
@@ -87,6 +88,7 @@ func isPublicPath(path string) bool {
 func unauthorized(c *fiber.Ctx, message string) error {
 	return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 		"error": "unauthorized",
+		"code": model.CodeUnauthorized,
 		"message": message,
 	})
 }
@@ -2,49 +2,100 @@ package middleware
 
 import (
 	"log/slog"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/compress"
+	"github.com/gofiber/fiber/v2/middleware/etag"
 	"github.com/google/uuid"
+
+	"github.com/momokii/go-cli-notes/internal/config"
 )
 
 // Logger is a middleware that logs HTTP requests
+// Entries carry the request ID and, after authentication, the user ID;
+// server errors are logged at error level and client errors at warn level.
 func Logger() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		start := time.Now()
 
-		// Get or generate request ID
-		requestID := c.Get("X-Request-ID")
-		if requestID == "" {
-			requestID = uuid.New().String()
-			c.Set("X-Request-ID", requestID)
-		}
-
 		// Process request
 		err := c.Next()
 
+		// Let the error handler write the response, so its status is logged
+		if err != nil {
+			if handlerErr := c.App().ErrorHandler(c, err); handlerErr != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
 		// Log request
-		duration := time.Since(start)
-		slog.Info("HTTP request",
-			"request_id", requestID,
+		status := c.Response().StatusCode()
+		attrs := []any{
+			"request_id", c.Locals("request_id"),
 			"method", c.Method(),
 			"path", c.Path(),
-			"status", c.Response().StatusCode(),
-			"duration", duration,
-		)
+			"status", status,
+			"duration", time.Since(start),
+			"ip", c.IP(),
+		}
+		if userID, ok := c.Locals("user_id").(string); ok {
+			attrs = append(attrs, "user_id", userID)
+		}
+		if err != nil {
+			attrs = append(attrs, "error", err)
+		}
 
-		return err
+		switch {
+		case status >= fiber.StatusInternalServerError:
+			slog.Error("HTTP request", attrs...)
+		case status >= fiber.StatusBadRequest:
+			slog.Warn("HTTP request", attrs...)
+		default:
+			slog.Info("HTTP request", attrs...)
+		}
+
+		return nil
 	}
 }
 
 // CORS is a middleware that handles CORS
-func CORS() fiber.Handler {
+// With explicit origins, only requests from those origins get the CORS headers.
+func CORS(cfg config.CORSConfig) fiber.Handler {
+	anyOrigin := cfg.AllowsAnyOrigin()
+	origins := make(map[string]bool, len(cfg.AllowedOrigins))
+	for _, origin := range cfg.AllowedOrigins {
+		origins[strings.TrimSuffix(strings.TrimSpace(origin), "/")] = true
+	}
+	headers := strings.Join(cfg.AllowedHeaders, ", ")
+	maxAge := strconv.Itoa(int(cfg.MaxAge.Seconds()))
+
 	return func(c *fiber.Ctx) error {
-		c.Set("Access-Control-Allow-Origin", "*")
-		c.Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Set("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization")
+		origin := c.Get(fiber.HeaderOrigin)
+		allowed := anyOrigin || origins[origin]
+
+		if allowed {
+			if anyOrigin {
+				c.Set("Access-Control-Allow-Origin", "*")
+			} else {
+				c.Set("Access-Control-Allow-Origin", origin)
+				c.Vary(fiber.HeaderOrigin)
+			}
+			if cfg.AllowCredentials {
+				c.Set("Access-Control-Allow-Credentials", "true")
+			}
+			c.Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+			c.Set("Access-Control-Allow-Headers", headers)
+			c.Set("Access-Control-Expose-Headers", "ETag, X-Request-ID, Retry-After")
+			if cfg.MaxAge > 0 {
+				c.Set("Access-Control-Max-Age", maxAge)
+			}
+		}
 
-		if c.Method() == "OPTIONS" {
+		// Answer preflight requests here; a disallowed origin gets no CORS headers, so the browser blocks it
+		if c.Method() == "OPTIONS" && c.Get("Access-Control-Request-Method") != "" {
 			return c.SendStatus(fiber.StatusNoContent)
 		}
 
@@ -66,3 +117,20 @@ func RequestID() fiber.Handler {
 		return c.Next()
 	}
 }
+
+// Compress is a middleware that compresses responses (gzip, deflate or brotli, per Accept-Encoding)
+// The live update stream is skipped, since buffering it for compression would hold back events.
+func Compress() fiber.Handler {
+	return compress.New(compress.Config{
+		Next: func(c *fiber.Ctx) bool {
+			return strings.HasSuffix(c.Path(), "/events")
+		},
+		Level: compress.LevelDefault,
+	})
+}
+
+// ETag is a middleware that tags GET responses with a hash of their body
+// A request whose If-None-Match matches the tag gets 304 Not Modified without the body.
+func ETag() fiber.Handler {
+	return etag.New()
+}
@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/momokii/go-cli-notes/internal/config"
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+// bucket is a token bucket for one client
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// rateLimiter is an in-memory token bucket limiter
+// Each client may burst up to limit requests, refilled at limit per window.
+// State lives in process memory, so every API instance limits independently.
+type rateLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	limit     float64
+	rate      float64 // Tokens per second
+	window    time.Duration
+	lastSweep time.Time
+}
+
+// RateLimit limits requests per user, or per IP for unauthenticated requests
+// Place it after Auth so authenticated requests are counted against the user, not the IP.
+// Responses carry RateLimit-Limit/Remaining/Reset headers; over the limit it returns 429 with Retry-After.
+func RateLimit(cfg config.RateLimitConfig) fiber.Handler {
+	if !cfg.Enabled || cfg.Requests <= 0 || cfg.Window <= 0 {
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+
+	rl := &rateLimiter{
+		buckets:   make(map[string]*bucket),
+		limit:     float64(cfg.Requests),
+		rate:      float64(cfg.Requests) / cfg.Window.Seconds(),
+		window:    cfg.Window,
+		lastSweep: time.Now(),
+	}
+
+	return func(c *fiber.Ctx) error {
+		key := "ip:" + c.IP()
+		if userID, ok := c.Locals("user_id").(string); ok && userID != "" {
+			key = "user:" + userID
+		}
+
+		allowed, remaining, reset, retryAfter := rl.take(key, time.Now())
+
+		c.Set("RateLimit-Limit", strconv.Itoa(cfg.Requests))
+		c.Set("RateLimit-Remaining", strconv.Itoa(remaining))
+		c.Set("RateLimit-Reset", strconv.Itoa(reset))
+
+		if !allowed {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfter))
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"error":   "rate limit exceeded",
+				"code":    model.CodeRateLimited,
+				"message": "Too many requests, retry in " + strconv.Itoa(retryAfter) + "s",
+			})
+		}
+
+		return c.Next()
+	}
+}
+
+// take consumes a token for key if one is available
+// It returns whether the request is allowed, the tokens left, seconds until the
+// bucket is full again and seconds until the next token is available.
+func (rl *rateLimiter) take(key string, now time.Time) (bool, int, int, int) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	rl.sweep(now)
+
+	b, ok := rl.buckets[key]
+	if !ok {
+		b = &bucket{tokens: rl.limit, last: now}
+		rl.buckets[key] = b
+	}
+
+	// Refill for the time since the last request
+	b.tokens = math.Min(rl.limit, b.tokens+now.Sub(b.last).Seconds()*rl.rate)
+	b.last = now
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+
+	reset := int(math.Ceil((rl.limit - b.tokens) / rl.rate))
+	retryAfter := 0
+	if !allowed {
+		retryAfter = int(math.Ceil((1 - b.tokens) / rl.rate))
+	}
+
+	return allowed, int(b.tokens), reset, retryAfter
+}
+
+// sweep drops buckets that have refilled completely, at most once per window
+func (rl *rateLimiter) sweep(now time.Time) {
+	if now.Sub(rl.lastSweep) < rl.window {
+		return
+	}
+	rl.lastSweep = now
+
+	for key, b := range rl.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*rl.rate >= rl.limit {
+			delete(rl.buckets, key)
+		}
+	}
+}
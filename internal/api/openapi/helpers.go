@@ -0,0 +1,121 @@
+package openapi
+
+import "strconv"
+
+// statusResponse pairs a response with its HTTP status code
+type statusResponse struct {
+	status   int
+	response *Response
+}
+
+// responses builds an operation's response map
+func responses(rs ...statusResponse) map[string]*Response {
+	m := make(map[string]*Response, len(rs))
+	for _, r := range rs {
+		m[strconv.Itoa(r.status)] = r.response
+	}
+	return m
+}
+
+// raw uses a hand-built response for a status code
+func raw(status int, r *Response) statusResponse {
+	return statusResponse{status: status, response: r}
+}
+
+// jsonResponse is a 200 response with a JSON body
+func jsonResponse(description string, schema *Schema) statusResponse {
+	return raw(200, &Response{
+		Description: description,
+		Content:     map[string]MediaType{"application/json": {Schema: schema}},
+	})
+}
+
+// created is a 201 response with a JSON body
+func created(description string, schema *Schema) statusResponse {
+	r := jsonResponse(description, schema)
+	r.status = 201
+	return r
+}
+
+// message is a 200 response with a {"message": "..."} body
+func message(text string) statusResponse {
+	return jsonResponse(text, object("message", str()))
+}
+
+// errorResponse is an error response with a {"error": "..."} body
+func errorResponse(status int, description string) statusResponse {
+	return raw(status, &Response{
+		Description: description,
+		Content:     map[string]MediaType{"application/json": {Schema: &Schema{Ref: "#/components/schemas/Error"}}},
+	})
+}
+
+func notFound(description string) statusResponse {
+	return errorResponse(404, description)
+}
+
+func unauthorized() statusResponse {
+	return errorResponse(401, "Missing or invalid access token")
+}
+
+// jsonBody is a required JSON request body
+func jsonBody(schema *Schema) *RequestBody {
+	return &RequestBody{
+		Required: true,
+		Content:  map[string]MediaType{"application/json": {Schema: schema}},
+	}
+}
+
+// optionalBody marks a request body as optional
+func optionalBody(body *RequestBody) *RequestBody {
+	body.Required = false
+	return body
+}
+
+// public overrides the document-wide bearer auth for an operation
+func public() *[]SecurityRequirement {
+	return &[]SecurityRequirement{}
+}
+
+// pathID is a required UUID path parameter
+func pathID(name, description string) *Parameter {
+	return &Parameter{Name: name, In: "path", Required: true, Description: description, Schema: uuidSchema()}
+}
+
+// queryParam is an optional query parameter
+func queryParam(name string, schema *Schema, description string) *Parameter {
+	return &Parameter{Name: name, In: "query", Description: description, Schema: schema}
+}
+
+// headerParam is an optional request header
+func headerParam(name string, schema *Schema, description string) *Parameter {
+	return &Parameter{Name: name, In: "header", Description: description, Schema: schema}
+}
+
+// pagination returns the page and limit query parameters
+func pagination() []*Parameter {
+	return []*Parameter{
+		queryParam("page", &Schema{Type: "integer", Minimum: intPtr(1), Default: 1}, "Page number"),
+		queryParam("limit", &Schema{Type: "integer", Minimum: intPtr(1), Maximum: intPtr(100), Default: 20}, "Items per page"),
+	}
+}
+
+// object builds an inline object schema from name/schema pairs
+func object(props ...any) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema, len(props)/2)}
+	for i := 0; i+1 < len(props); i += 2 {
+		s.Properties[props[i].(string)] = props[i+1].(*Schema)
+	}
+	return s
+}
+
+func arrayOf(items *Schema) *Schema {
+	return &Schema{Type: "array", Items: items}
+}
+
+func str() *Schema        { return &Schema{Type: "string"} }
+func integer() *Schema    { return &Schema{Type: "integer"} }
+func boolean() *Schema    { return &Schema{Type: "boolean"} }
+func uuidSchema() *Schema { return &Schema{Type: "string", Format: "uuid"} }
+
+func intPtr(n int) *int { return &n }
@@ -0,0 +1,208 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+// enumValues lists the allowed values of the model's string enum types
+var enumValues = map[reflect.Type][]string{
+	reflect.TypeOf(model.ActionType("")):       {"create", "update", "view", "search", "delete", "login", "logout", "focus"},
+	reflect.TypeOf(model.TaskStatus("")):       {"open", "done", "all"},
+	reflect.TypeOf(model.Role("")):             {"user", "admin"},
+	reflect.TypeOf(model.SharePermission("")):  {"read", "write"},
+	reflect.TypeOf(model.EventType("")):        {"note.created", "note.updated", "note.deleted", "tag.created", "tag.updated", "tag.deleted", "activity"},
+	reflect.TypeOf(model.DeliveryStatus("")):   {"pending", "succeeded", "failed"},
+	reflect.TypeOf(model.DeviceAuthStatus("")): {"pending", "approved", "denied", "used"},
+	reflect.TypeOf(model.AuditEvent("")):       auditEventNames(),
+	reflect.TypeOf(model.TagTrend("")):         {"growing", "steady", "stale"},
+	reflect.TypeOf(model.NoteFieldType("")):    {"text", "number", "date", "boolean", "list"},
+}
+
+// auditEventNames lists the audit events as strings
+func auditEventNames() []string {
+	names := make([]string, len(model.AuditEvents))
+	for i, event := range model.AuditEvents {
+		names[i] = string(event)
+	}
+	return names
+}
+
+var (
+	uuidType = reflect.TypeOf(uuid.UUID{})
+	timeType = reflect.TypeOf(time.Time{})
+	rawType  = reflect.TypeOf(json.RawMessage{})
+)
+
+// schemaRegistry derives schemas from Go types and collects named structs as components
+type schemaRegistry struct {
+	schemas map[string]*Schema
+}
+
+func newSchemaRegistry() *schemaRegistry {
+	return &schemaRegistry{schemas: make(map[string]*Schema)}
+}
+
+// ref returns a schema for the type of v, registering named structs as components
+func (r *schemaRegistry) ref(v any) *Schema {
+	return r.schemaFor(reflect.TypeOf(v))
+}
+
+// schemaFor builds the schema of a Go type the way encoding/json serializes it
+func (r *schemaRegistry) schemaFor(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t {
+	case uuidType:
+		return &Schema{Type: "string", Format: "uuid"}
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case rawType:
+		return &Schema{} // Embedded JSON document
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string", Enum: enumValues[t]}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: r.schemaFor(t.Elem())}
+	case reflect.Map:
+		if t.Elem().Kind() == reflect.Interface {
+			return &Schema{Type: "object", AdditionalProperties: true}
+		}
+		return &Schema{Type: "object", AdditionalProperties: r.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return r.structSchema(t)
+		}
+		if _, ok := r.schemas[t.Name()]; !ok {
+			r.schemas[t.Name()] = &Schema{} // Placeholder so self-references terminate
+			r.schemas[t.Name()] = r.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + t.Name()}
+	default:
+		// interface{} and anything else: any JSON value
+		return &Schema{}
+	}
+}
+
+// structSchema builds an object schema from a struct's json and validate tags
+func (r *schemaRegistry) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" && field.Anonymous && field.Type.Kind() == reflect.Struct {
+			// encoding/json promotes the fields of embedded structs
+			embedded := r.structSchema(field.Type)
+			for propName, prop := range embedded.Properties {
+				schema.Properties[propName] = prop
+			}
+			schema.Required = append(schema.Required, embedded.Required...)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		prop := r.schemaFor(field.Type)
+		if required := applyValidation(prop, field.Tag.Get("validate")); required {
+			schema.Required = append(schema.Required, name)
+		}
+		if field.Type.Kind() == reflect.Pointer && prop.Ref == "" {
+			prop.Nullable = true
+		}
+
+		schema.Properties[name] = prop
+	}
+
+	return schema
+}
+
+// applyValidation maps go-playground/validator rules onto schema constraints
+// It reports whether the field is required.
+func applyValidation(s *Schema, rules string) bool {
+	required := false
+
+	all := strings.Split(rules, ",")
+	for i, rule := range all {
+		key, value, _ := strings.Cut(rule, "=")
+		n, err := strconv.Atoi(value)
+		hasNumber := err == nil
+
+		switch key {
+		case "required":
+			required = true
+		case "dive":
+			// The rules after dive apply to each element
+			if s.Items != nil {
+				applyValidation(s.Items, strings.Join(all[i+1:], ","))
+			}
+			return required
+		case "email":
+			s.Format = "email"
+		case "uuid":
+			s.Format = "uuid"
+		case "url", "http_url":
+			s.Format = "uri"
+		case "oneof":
+			s.Enum = strings.Fields(value)
+		case "min", "max", "len":
+			if !hasNumber {
+				continue
+			}
+			if s.Type == "integer" || s.Type == "number" {
+				if key != "max" {
+					s.Minimum = &n
+				}
+				if key != "min" {
+					s.Maximum = &n
+				}
+			} else if s.Type == "array" {
+				if key != "max" {
+					s.MinItems = &n
+				}
+				if key != "min" {
+					s.MaxItems = &n
+				}
+			} else {
+				if key != "max" {
+					s.MinLength = &n
+				}
+				if key != "min" {
+					s.MaxLength = &n
+				}
+			}
+		}
+	}
+
+	return required
+}
@@ -0,0 +1,1158 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+// errorSchema is the body of every error response: {"error": "...", "code": "..."}
+// Auth and rate limit errors add a human-readable message, validation errors the invalid fields.
+var errorSchema = &Schema{
+	Type: "object",
+	Properties: map[string]*Schema{
+		"error": {Type: "string"},
+		"code": {Type: "string", Enum: []string{
+			model.CodeValidation, model.CodeUnauthorized, model.CodeForbidden, model.CodeNotFound,
+			model.CodeConflict, model.CodeRateLimited, model.CodeUnavailable, model.CodeInternal,
+			model.CodeTOTPRequired, model.CodeEmailNotVerified, model.CodeNoteChanged,
+			model.CodeAuthorizationPending, model.CodeSlowDown, model.CodeExpiredToken, model.CodeAccessDenied,
+			model.ErrAPIEmailExists.Code, model.ErrAPIUsernameExists.Code,
+		}},
+		"message": {Type: "string"},
+		"fields":  {Type: "object", AdditionalProperties: &Schema{Type: "string"}, Description: "Problem per invalid JSON field"},
+	},
+	Required: []string{"error"},
+}
+
+// builder collects operations and the schemas they reference
+type builder struct {
+	doc *Document
+	reg *schemaRegistry
+}
+
+// Generate returns the OpenAPI document as indented JSON
+func Generate(version string) ([]byte, error) {
+	return json.MarshalIndent(Build(version), "", "  ")
+}
+
+// Build builds the OpenAPI document for every route registered in the router
+// Keep it in sync with internal/api/router when adding endpoints.
+func Build(version string) *Document {
+	b := &builder{
+		doc: &Document{
+			OpenAPI: "3.0.3",
+			Info: Info{
+				Title:       "Knowledge Garden API",
+				Description: "REST API for notes, wiki links, tags, search and activity. Authenticate with `POST /api/v1/auth/login` and send the access token as a Bearer token. Requests are rate limited per user (per IP for login and register); see the `RateLimit-*` response headers, and retry after `Retry-After` seconds on 429.",
+				Version:     version,
+			},
+			Tags: []Tag{
+				{Name: "auth", Description: "Registration and tokens"},
+				{Name: "users", Description: "Account management for the signed-in user"},
+				{Name: "notes", Description: "Notes, revisions and daily notes"},
+				{Name: "tags", Description: "Tags and note tagging"},
+				{Name: "note-types", Description: "Built-in and custom note types"},
+				{Name: "links", Description: "Wiki links, backlinks and the knowledge graph"},
+				{Name: "search", Description: "Full-text search"},
+				{Name: "attachments", Description: "Files attached to notes"},
+				{Name: "public-links", Description: "Public, read-only links to notes"},
+				{Name: "sharing", Description: "Notes shared with other users of the instance"},
+				{Name: "webhooks", Description: "Signed HTTP callbacks on note and tag events"},
+				{Name: "tasks", Description: "Checkbox tasks extracted from notes"},
+				{Name: "review", Description: "Spaced repetition of flashcards extracted from notes"},
+				{Name: "activity", Description: "Activity, statistics and live updates"},
+				{Name: "settings", Description: "Per-user settings"},
+				{Name: "admin", Description: "Instance administration, admins only"},
+				{Name: "system", Description: "Health and documentation"},
+			},
+			Paths: make(map[string]map[string]*Operation),
+			Components: Components{
+				SecuritySchemes: map[string]*SecurityScheme{
+					"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+				},
+			},
+			Security: []SecurityRequirement{{"bearerAuth": {}}},
+		},
+		reg: newSchemaRegistry(),
+	}
+
+	b.systemRoutes()
+	b.authRoutes()
+	b.userRoutes()
+	b.noteRoutes()
+	b.tagRoutes()
+	b.noteTypeRoutes()
+	b.linkRoutes()
+	b.searchRoutes()
+	b.attachmentRoutes()
+	b.publicLinkRoutes()
+	b.noteShareRoutes()
+	b.webhookRoutes()
+	b.taskRoutes()
+	b.reviewRoutes()
+	b.activityRoutes()
+	b.settingsRoutes()
+	b.adminRoutes()
+
+	b.reg.schemas["Error"] = errorSchema
+	b.doc.Components.Schemas = b.reg.schemas
+
+	return b.doc
+}
+
+// add registers an operation; path uses Fiber syntax (/notes/:id)
+func (b *builder) add(method, path string, op *Operation) {
+	path = toOpenAPIPath(path)
+	if b.doc.Paths[path] == nil {
+		b.doc.Paths[path] = make(map[string]*Operation)
+	}
+	b.doc.Paths[path][strings.ToLower(method)] = op
+}
+
+// toOpenAPIPath converts Fiber path params (:id) to OpenAPI templates ({id})
+func toOpenAPIPath(path string) string {
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		if strings.HasPrefix(part, ":") {
+			parts[i] = "{" + part[1:] + "}"
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+func (b *builder) systemRoutes() {
+	b.add("GET", "/health", &Operation{
+		Tags: []string{"system"}, Summary: "Health check", OperationID: "health",
+		Security:  public(),
+		Responses: responses(jsonResponse("Server is up", object("status", str()))),
+	})
+	b.add("GET", "/metrics", &Operation{
+		Tags: []string{"system"}, Summary: "Prometheus metrics (when METRICS_ENABLED=true)", OperationID: "getMetrics",
+		Security: public(),
+		Responses: responses(raw(200, &Response{
+			Description: "Metrics in the Prometheus text format",
+			Content:     map[string]MediaType{"text/plain": {Schema: str()}},
+		}), notFound("Metrics are disabled")),
+	})
+	b.add("GET", "/api/v1/openapi.json", &Operation{
+		Tags: []string{"system"}, Summary: "This OpenAPI document", OperationID: "getOpenAPI",
+		Security:  public(),
+		Responses: responses(jsonResponse("OpenAPI 3.0 document", &Schema{Type: "object"})),
+	})
+	b.add("GET", "/api/v1/docs", &Operation{
+		Tags: []string{"system"}, Summary: "Swagger UI for this document", OperationID: "getDocs",
+		Security: public(),
+		Responses: responses(raw(200, &Response{
+			Description: "HTML page",
+			Content:     map[string]MediaType{"text/html": {Schema: str()}},
+		})),
+	})
+}
+
+func (b *builder) authRoutes() {
+	b.add("POST", "/api/v1/auth/register", &Operation{
+		Tags: []string{"auth"}, Summary: "Register a new user", OperationID: "register",
+		Security:    public(),
+		RequestBody: jsonBody(b.reg.ref(model.RegisterRequest{})),
+		Responses: responses(
+			created("The new user", b.reg.ref(model.User{})),
+			errorResponse(400, "Invalid request"),
+		),
+	})
+	b.add("POST", "/api/v1/auth/login", &Operation{
+		Tags: []string{"auth"}, Summary: "Log in and get tokens", OperationID: "login",
+		Security:    public(),
+		RequestBody: jsonBody(b.reg.ref(model.LoginRequest{})),
+		Responses: responses(
+			jsonResponse("Access and refresh tokens", b.reg.ref(model.AuthResponse{})),
+			errorResponse(401, "Invalid email or password, or missing/invalid two-factor code"),
+			errorResponse(403, "Email not verified (when verification is required)"),
+		),
+	})
+	b.add("POST", "/api/v1/auth/refresh", &Operation{
+		Tags: []string{"auth"}, Summary: "Exchange a refresh token for new tokens", OperationID: "refreshToken",
+		Security:    public(),
+		RequestBody: jsonBody(b.reg.ref(model.RefreshRequest{})),
+		Responses: responses(
+			jsonResponse("New access and refresh tokens", b.reg.ref(model.AuthResponse{})),
+			errorResponse(401, "Invalid, expired, revoked or reused refresh token"),
+		),
+	})
+	b.add("POST", "/api/v1/auth/verify-email", &Operation{
+		Tags: []string{"auth"}, Summary: "Verify an email address with the emailed token", OperationID: "verifyEmail",
+		Security:    public(),
+		RequestBody: jsonBody(b.reg.ref(model.VerifyEmailRequest{})),
+		Responses:   responses(message("Email verified"), errorResponse(400, "Invalid or expired verification token")),
+	})
+	b.add("POST", "/api/v1/auth/resend-verification", &Operation{
+		Tags: []string{"auth"}, Summary: "Email a new verification token", OperationID: "resendVerification",
+		Description: "Responds the same whether or not the email is registered or already verified.",
+		Security:    public(),
+		RequestBody: jsonBody(b.reg.ref(model.ResendVerificationRequest{})),
+		Responses:   responses(message("Verification token sent if the email is registered and unverified"), errorResponse(400, "Invalid request")),
+	})
+	b.add("POST", "/api/v1/auth/forgot-password", &Operation{
+		Tags: []string{"auth"}, Summary: "Email a password reset token", OperationID: "forgotPassword",
+		Description: "Responds the same whether or not the email is registered.",
+		Security:    public(),
+		RequestBody: jsonBody(b.reg.ref(model.ForgotPasswordRequest{})),
+		Responses:   responses(message("Reset token sent if the email is registered"), errorResponse(400, "Invalid request")),
+	})
+	b.add("POST", "/api/v1/auth/reset-password", &Operation{
+		Tags: []string{"auth"}, Summary: "Set a new password with a reset token", OperationID: "resetPassword",
+		Description: "Revokes all refresh tokens of the account.",
+		Security:    public(),
+		RequestBody: jsonBody(b.reg.ref(model.ResetPasswordRequest{})),
+		Responses:   responses(message("Password has been reset"), errorResponse(400, "Invalid or expired reset token")),
+	})
+	b.add("POST", "/api/v1/auth/device", &Operation{
+		Tags: []string{"auth"}, Summary: "Start a device login", OperationID: "startDeviceLogin",
+		Description: "For devices without a browser, as in the OAuth device flow (RFC 8628). Show the user code and `verification_uri`, where a signed-in user approves it, then poll `/api/v1/auth/device/token` with the device code every `interval` seconds.",
+		Security:    public(),
+		Responses:   responses(jsonResponse("The codes of the login", b.reg.ref(model.DeviceCodeResponse{}))),
+	})
+	b.add("POST", "/api/v1/auth/device/token", &Operation{
+		Tags: []string{"auth"}, Summary: "Poll for the tokens of a device login", OperationID: "pollDeviceLogin",
+		Description: "Answers 400 with code `AUTHORIZATION_PENDING` until the login is approved, `SLOW_DOWN` when polled faster than `interval` (add 5 seconds to it), `ACCESS_DENIED` once denied and `EXPIRED_TOKEN` once the code expired or its tokens were handed out. Tokens are only returned once.",
+		Security:    public(),
+		RequestBody: jsonBody(b.reg.ref(model.DeviceTokenRequest{})),
+		Responses:   responses(jsonResponse("Tokens", b.reg.ref(model.AuthResponse{})), errorResponse(400, "Not approved yet, denied or expired")),
+	})
+	b.add("POST", "/api/v1/auth/device/approve", &Operation{
+		Tags: []string{"auth"}, Summary: "Approve or deny a device login", OperationID: "approveDeviceLogin",
+		Description: "Signs the device showing the user code in to the caller's account; with `deny` its login fails instead. The code is accepted in any case, with or without the dash.",
+		RequestBody: jsonBody(b.reg.ref(model.DeviceApproveRequest{})),
+		Responses:   responses(jsonResponse("The device authorization", b.reg.ref(model.DeviceAuthorization{})), errorResponse(400, "Invalid or expired code"), unauthorized()),
+	})
+	b.add("GET", "/device", &Operation{
+		Tags: []string{"auth"}, Summary: "Device login page", OperationID: "getDevicePage",
+		Description: "The `verification_uri` of device logins: an HTML form to enter the user code and sign in, which posts to `POST /device`.",
+		Security:    public(),
+		Parameters:  []*Parameter{queryParam("code", str(), "User code to fill in")},
+		Responses: responses(raw(200, &Response{
+			Description: "HTML page",
+			Content:     map[string]MediaType{"text/html": {Schema: str()}},
+		})),
+	})
+	b.add("POST", "/device", &Operation{
+		Tags: []string{"auth"}, Summary: "Approve a device login with credentials", OperationID: "submitDevicePage",
+		Security: public(),
+		RequestBody: &RequestBody{
+			Required: true,
+			Content: map[string]MediaType{"application/x-www-form-urlencoded": {Schema: &Schema{
+				Type: "object",
+				Properties: map[string]*Schema{
+					"code": str(), "email": str(), "password": str(),
+					"totp_code": {Type: "string", Description: "Required when 2FA is enabled"},
+				},
+				Required: []string{"code", "email", "password"},
+			}}},
+		},
+		Responses: responses(
+			raw(200, &Response{Description: "HTML page confirming the device is signed in", Content: map[string]MediaType{"text/html": {Schema: str()}}}),
+			raw(400, &Response{Description: "The form again, with the error", Content: map[string]MediaType{"text/html": {Schema: str()}}}),
+		),
+	})
+	b.add("POST", "/api/v1/auth/logout", &Operation{
+		Tags: []string{"auth"}, Summary: "Log out and revoke a refresh token", OperationID: "logout",
+		RequestBody: optionalBody(jsonBody(b.reg.ref(model.RefreshRequest{}))),
+		Responses:   responses(message("Logged out"), unauthorized()),
+	})
+	b.add("GET", "/api/v1/auth/sessions", &Operation{
+		Tags: []string{"auth"}, Summary: "List active sessions (unrevoked refresh tokens)", OperationID: "listSessions",
+		Responses: responses(
+			jsonResponse("Active sessions, newest first", object(
+				"sessions", arrayOf(b.reg.ref(model.RefreshToken{})),
+				"count", integer(),
+			)),
+			unauthorized(),
+		),
+	})
+	b.add("DELETE", "/api/v1/auth/sessions/:id", &Operation{
+		Tags: []string{"auth"}, Summary: "Revoke one session", OperationID: "revokeSession",
+		Parameters: []*Parameter{pathID("id", "Session ID")},
+		Responses:  responses(message("Session revoked"), unauthorized(), notFound("Session not found")),
+	})
+	b.add("DELETE", "/api/v1/auth/sessions", &Operation{
+		Tags: []string{"auth"}, Summary: "Revoke all refresh tokens (sign out every device)", OperationID: "revokeAllSessions",
+		Description: "Access tokens already issued stay valid until they expire.",
+		Responses:   responses(message("All sessions revoked"), unauthorized()),
+	})
+	b.add("POST", "/api/v1/auth/2fa/setup", &Operation{
+		Tags: []string{"auth"}, Summary: "Start two-factor setup and get a TOTP secret", OperationID: "setupTOTP",
+		Responses: responses(
+			jsonResponse("TOTP secret and otpauth URI", b.reg.ref(model.TOTPSetupResponse{})),
+			unauthorized(),
+			errorResponse(409, "Two-factor authentication already enabled"),
+		),
+	})
+	b.add("POST", "/api/v1/auth/2fa/verify", &Operation{
+		Tags: []string{"auth"}, Summary: "Confirm a TOTP code and enable two-factor authentication", OperationID: "verifyTOTP",
+		RequestBody: jsonBody(b.reg.ref(model.TOTPVerifyRequest{})),
+		Responses: responses(
+			message("Two-factor authentication enabled"),
+			errorResponse(400, "Invalid code or setup not started"),
+			errorResponse(401, "Invalid two-factor code"),
+			errorResponse(409, "Two-factor authentication already enabled"),
+		),
+	})
+}
+
+func (b *builder) userRoutes() {
+	b.add("PUT", "/api/v1/users/me/password", &Operation{
+		Tags: []string{"users"}, Summary: "Change the password", OperationID: "changePassword",
+		Description: "Revokes every session of the account and returns new tokens for the calling device.",
+		RequestBody: jsonBody(b.reg.ref(model.ChangePasswordRequest{})),
+		Responses: responses(
+			jsonResponse("New access and refresh tokens", b.reg.ref(model.AuthResponse{})),
+			errorResponse(400, "Invalid request"),
+			unauthorized(),
+			errorResponse(403, "Incorrect current password"),
+		),
+	})
+	b.add("DELETE", "/api/v1/users/me", &Operation{
+		Tags: []string{"users"}, Summary: "Delete the account", OperationID: "deleteAccount",
+		Description: "Deactivates the account, soft-deletes all of its notes and revokes every session. Export notes first with `GET /api/v1/notes/export` to keep a copy.",
+		RequestBody: jsonBody(b.reg.ref(model.DeleteAccountRequest{})),
+		Responses: responses(
+			message("Account deleted"),
+			errorResponse(400, "Invalid request"),
+			unauthorized(),
+			errorResponse(403, "Incorrect password"),
+		),
+	})
+	b.add("GET", "/api/v1/audit", &Operation{
+		Tags: []string{"users"}, Summary: "List the account's security events", OperationID: "listAuditLog",
+		Description: "Logins, failed logins, token refreshes, password changes and other security events of the account, newest first. " +
+			"Kept apart from the note activity log. `from` and `to` take RFC 3339 timestamps or YYYY-MM-DD dates in the user's timezone; a `to` date includes the whole day.",
+		Parameters: auditFilterParams(),
+		Responses: responses(
+			jsonResponse("A page of audit log entries", object("entries", arrayOf(b.reg.ref(model.AuditEntry{})), "pagination", b.reg.ref(model.Pagination{}))),
+			errorResponse(400, "Invalid filter"),
+			unauthorized(),
+		),
+	})
+}
+
+// auditFilterParams are the query parameters of audit log listings
+func auditFilterParams() []*Parameter {
+	return []*Parameter{
+		queryParam("event", &Schema{Type: "string", Enum: enumValues[reflect.TypeOf(model.AuditEvent(""))]}, "Filter by event"),
+		queryParam("from", &Schema{Type: "string"}, "Earliest entry to include"),
+		queryParam("to", &Schema{Type: "string"}, "Latest entry to include"),
+		queryParam("page", &Schema{Type: "integer", Minimum: intPtr(1), Default: 1}, "Page number"),
+		queryParam("limit", &Schema{Type: "integer", Minimum: intPtr(1), Maximum: intPtr(100), Default: 20}, "Items per page"),
+	}
+}
+
+func (b *builder) noteRoutes() {
+	note := b.reg.ref(model.Note{})
+
+	b.add("GET", "/api/v1/notes", &Operation{
+		Tags: []string{"notes"}, Summary: "List notes", OperationID: "listNotes",
+		Description: "With `limit=all` every matching note is streamed as NDJSON (one note per line) instead of a page. " +
+			"If streaming fails midway, the last line is an `{\"error\": \"...\"}` object.",
+		Parameters: []*Parameter{
+			queryParam("page", &Schema{Type: "integer", Minimum: intPtr(1), Default: 1}, "Page number"),
+			queryParam("limit", &Schema{Type: "string", Default: "20"}, "Items per page (1-100), or `all` to stream every note"),
+			queryParam("type", b.reg.ref(model.NoteType("")), "Filter by note type, built-in or custom"),
+			queryParam("search", str(), "Full-text search query"),
+			queryParam("tag", uuidSchema(), "Filter by tag ID"),
+			queryParam("status", &Schema{Type: "string", Enum: []string{"todo", "doing", "done", "any"}}, "Filter by board status in the metadata; `any` lists every note with a status"),
+			queryParam("include", str(), "Comma-separated related data to return with each note: `tags`, `link_counts`"),
+			queryParam("sort_by", &Schema{Type: "string", Enum: []string{"created_at", "updated_at", "title", "access_count", "word_count", "relevance"}, Default: "created_at"}, "Sort field; `relevance` ranks full-text matches and needs `search`"),
+			queryParam("sort_order", &Schema{Type: "string", Enum: []string{"asc", "desc"}, Default: "desc"}, "Sort direction"),
+		},
+		Responses: responses(
+			raw(200, &Response{
+				Description: "A page of notes, or every note as NDJSON with limit=all",
+				Content: map[string]MediaType{
+					"application/json":     {Schema: object("notes", arrayOf(note), "pagination", b.reg.ref(model.Pagination{}))},
+					"application/x-ndjson": {Schema: note},
+				},
+			}),
+			unauthorized(),
+		),
+	})
+	b.add("POST", "/api/v1/notes", &Operation{
+		Tags: []string{"notes"}, Summary: "Create a note", OperationID: "createNote",
+		Description: "Wiki links and checkbox tasks in the content are extracted on save. Set `encrypted` when the content is client-side ciphertext. " +
+			"A YAML frontmatter header at the top of the content is removed from it: its `type` is used when `note_type` isn't given " +
+			"and its other keys, besides id, title, tags and timestamps, are stored in the metadata, where `metadata` keys win.",
+		RequestBody: jsonBody(b.reg.ref(model.CreateNoteRequest{})),
+		Responses:   responses(created("The new note", note), errorResponse(400, "Invalid request"), unauthorized()),
+	})
+	b.add("POST", "/api/v1/notes/batch", &Operation{
+		Tags: []string{"notes"}, Summary: "Create many notes", OperationID: "createNotesBatch",
+		Description: "Creates up to 500 notes in one transaction. Each item is reported by its index in the request; invalid items fail on their own without rolling back the rest.",
+		RequestBody: jsonBody(arrayOf(b.reg.ref(model.CreateNoteRequest{}))),
+		Responses:   responses(jsonResponse("Per-item results", b.reg.ref(model.BatchCreateResponse{})), errorResponse(400, "Empty or oversized batch"), unauthorized()),
+	})
+	b.add("GET", "/api/v1/notes/export", &Operation{
+		Tags: []string{"notes"}, Summary: "Export all notes", OperationID: "exportNotes",
+		Description: "Zip archive of Markdown files with YAML frontmatter.",
+		Parameters: []*Parameter{
+			queryParam("expand", &Schema{Type: "string", Enum: []string{"embeds"}}, "`embeds` writes each note with its `![[Note]]` embeds expanded inline as quotes"),
+		},
+		Responses: responses(raw(200, &Response{
+			Description: "Zip archive",
+			Content:     map[string]MediaType{"application/zip": {Schema: &Schema{Type: "string", Format: "binary"}}},
+		}), errorResponse(400, "Invalid expand value"), unauthorized()),
+	})
+	b.add("GET", "/api/v1/notes/daily", &Operation{
+		Tags: []string{"notes"}, Summary: "List the days of a month with a daily note", OperationID: "listDailyNotes",
+		Parameters: []*Parameter{queryParam("month", str(), "Month as YYYY-MM (default the current month)")},
+		Responses: responses(
+			jsonResponse("Days with a daily note, oldest first", object("month", str(), "days", arrayOf(b.reg.ref(model.DailyNoteDay{})))),
+			errorResponse(400, "Invalid month"),
+			unauthorized(),
+		),
+	})
+	b.add("GET", "/api/v1/notes/daily/:date", &Operation{
+		Tags: []string{"notes"}, Summary: "Get or create the daily note for a date", OperationID: "getDailyNote",
+		Parameters: []*Parameter{{Name: "date", In: "path", Required: true, Description: "Date as YYYY-MM-DD", Schema: &Schema{Type: "string", Format: "date"}}},
+		Responses: responses(
+			jsonResponse("The daily note", object("note", note, "is_created", boolean(), "date", str())),
+			errorResponse(400, "Invalid date"),
+			unauthorized(),
+		),
+	})
+	b.add("POST", "/api/v1/notes/review", &Operation{
+		Tags: []string{"notes"}, Summary: "Write the weekly review note", OperationID: "createWeeklyReview",
+		Description: "Summarizes the notes created and updated in a week, its top tags, completed tasks and forgotten notes. " +
+			"Running it again for the same week rewrites that week's review, keeping the text under its Reflection heading.",
+		RequestBody: optionalBody(jsonBody(b.reg.ref(model.WeeklyReviewRequest{}))),
+		Responses: responses(
+			jsonResponse("The week's review note was rewritten", object("note", note, "review", b.reg.ref(model.WeeklyReview{}), "is_created", boolean())),
+			created("The week's review note was created", object("note", note, "review", b.reg.ref(model.WeeklyReview{}), "is_created", boolean())),
+			errorResponse(400, "Invalid date"),
+			unauthorized(),
+		),
+	})
+	b.add("POST", "/api/v1/notes/capture", &Operation{
+		Tags: []string{"notes"}, Summary: "Quick capture", OperationID: "captureNote",
+		Description: "Appends the text as a list item to the note titled `Inbox` or to today's daily note " +
+			"(default: the `capture_target` setting). Either note is created when it doesn't exist yet.",
+		RequestBody: jsonBody(b.reg.ref(model.CaptureRequest{})),
+		Responses: responses(
+			jsonResponse("The text was appended", object("note", note, "is_created", boolean())),
+			created("The note was created with the text", object("note", note, "is_created", boolean())),
+			errorResponse(400, "Empty text, or the target note is encrypted"),
+			unauthorized(),
+		),
+	})
+	b.add("GET", "/api/v1/notes/inbox", &Operation{
+		Tags: []string{"notes"}, Summary: "Count the Inbox items", OperationID: "getInbox",
+		Description: "Counts the top-level list items of the `Inbox` note that aren't checked off.",
+		Responses:   responses(jsonResponse("The Inbox", b.reg.ref(model.InboxStatus{})), unauthorized()),
+	})
+	b.add("GET", "/api/v1/notes/reading", &Operation{
+		Tags: []string{"notes"}, Summary: "List notes to continue reading", OperationID: "listReadingNotes",
+		Description: "Notes whose `read_progress` is between 1 and 99 percent, most recently read first.",
+		Parameters: []*Parameter{
+			queryParam("limit", integer(), "Maximum notes (default 5, max 50)"),
+		},
+		Responses: responses(
+			jsonResponse("Notes being read", object("notes", arrayOf(note), "count", integer())),
+			errorResponse(400, "Invalid limit"),
+			unauthorized(),
+		),
+	})
+	b.add("POST", "/api/v1/clip", &Operation{
+		Tags: []string{"notes"}, Summary: "Clip a web page", OperationID: "clipPage",
+		Description: "Fetches the page server-side, extracts its main article as Markdown and saves it as a note " +
+			"tagged `clipped` (plus any `tags`). The note opens with a line crediting the page. " +
+			"Pages on loopback and private networks are refused unless the server sets `CLIP_ALLOW_PRIVATE`.",
+		RequestBody: jsonBody(b.reg.ref(model.ClipRequest{})),
+		Responses: responses(
+			created("The clipped note", note),
+			errorResponse(400, "Invalid URL"),
+			errorResponse(422, "The page couldn't be fetched, isn't HTML, is too large or has no article"),
+			unauthorized(),
+		),
+	})
+	b.add("GET", "/api/v1/notes/:id", &Operation{
+		Tags: []string{"notes"}, Summary: "Get a note", OperationID: "getNote",
+		Description: "Notes other users share with you are found too, with `shared_by` and `permission` set. " +
+			"With `expand=embeds`, `expanded_content` holds the content with `![[Note]]`, `![[Note#Heading]]` and `![[Note^block-id]]` " +
+			"embeds replaced by what they name, up to 3 levels deep; embeds that can't be resolved are left as links.",
+		Parameters: []*Parameter{
+			pathID("id", "Note ID"),
+			queryParam("expand", &Schema{Type: "string", Enum: []string{"embeds"}}, "`embeds` sets `expanded_content`"),
+		},
+		Responses: responses(jsonResponse("The note", note), errorResponse(400, "Invalid expand value"), notFound("Note not found"), unauthorized()),
+	})
+	b.add("PUT", "/api/v1/notes/:id", &Operation{
+		Tags: []string{"notes"}, Summary: "Update a note", OperationID: "updateNote",
+		Description: "The previous version is saved as a revision. Renaming a note rewrites `[[Old Title]]` links in other notes. " +
+			"Users the note is shared with for writing can change its title and content. " +
+			"With `If-Match` set to the quoted `updated_at` of the version the update is based on, a note changed since isn't updated.",
+		Parameters: []*Parameter{
+			pathID("id", "Note ID"),
+			headerParam("If-Match", &Schema{Type: "string"}, "Quoted `updated_at` of the version the update is based on, e.g. `\"2025-01-10T12:00:00.123456Z\"`"),
+		},
+		RequestBody: jsonBody(b.reg.ref(model.UpdateNoteRequest{})),
+		Responses: responses(
+			jsonResponse("The updated note", note),
+			errorResponse(400, "Invalid request or If-Match header"),
+			notFound("Note not found"),
+			errorResponse(409, "The note changed since the If-Match version (`NOTE_CHANGED`)"),
+			unauthorized(),
+		),
+	})
+	b.add("PATCH", "/api/v1/notes/:id/append", &Operation{
+		Tags: []string{"notes"}, Summary: "Append to a note", OperationID: "appendNote",
+		Description: "Adds the content to the end of the note, on a new line, in one step on the server. " +
+			"Concurrent appends are applied in turn, none is lost. The previous version is saved as a revision.",
+		Parameters:  []*Parameter{pathID("id", "Note ID")},
+		RequestBody: jsonBody(b.reg.ref(model.AppendNoteRequest{})),
+		Responses:   responses(jsonResponse("The updated note", note), errorResponse(400, "Invalid request, or the note is encrypted"), notFound("Note not found"), unauthorized()),
+	})
+	b.add("PATCH", "/api/v1/notes/:id/prepend", &Operation{
+		Tags: []string{"notes"}, Summary: "Prepend to a note", OperationID: "prependNote",
+		Description: "Like append, but adds the content to the start of the note.",
+		Parameters:  []*Parameter{pathID("id", "Note ID")},
+		RequestBody: jsonBody(b.reg.ref(model.AppendNoteRequest{})),
+		Responses:   responses(jsonResponse("The updated note", note), errorResponse(400, "Invalid request, or the note is encrypted"), notFound("Note not found"), unauthorized()),
+	})
+	b.add("PATCH", "/api/v1/notes/:id/metadata", &Operation{
+		Tags: []string{"notes"}, Summary: "Update a note's metadata", OperationID: "updateNoteMetadata",
+		Description: "Sets the given keys of the note's metadata to any JSON value; `null` removes a key and keys not given are kept. " +
+			"`status` must be `todo`, `doing` or `done`, and `summary` and `read_progress` are set by the server. Only the owner of a shared note can change it.",
+		Parameters:  []*Parameter{pathID("id", "Note ID")},
+		RequestBody: jsonBody(&Schema{Type: "object", AdditionalProperties: true}),
+		Responses:   responses(jsonResponse("The updated note", note), errorResponse(400, "Invalid metadata"), notFound("Note not found"), unauthorized()),
+	})
+	b.add("PUT", "/api/v1/notes/:id/progress", &Operation{
+		Tags: []string{"notes"}, Summary: "Save how far a note has been read", OperationID: "setReadProgress",
+		Description: "Stores the percent read in the note's `metadata.read_progress`. Reading isn't an edit: " +
+			"the note keeps its `updated_at`, and no revision or activity is recorded.",
+		Parameters:  []*Parameter{pathID("id", "Note ID")},
+		RequestBody: jsonBody(b.reg.ref(model.SetReadProgressRequest{})),
+		Responses: responses(
+			jsonResponse("The saved progress", b.reg.ref(model.ReadProgress{})),
+			errorResponse(400, "Invalid percent"),
+			notFound("Note not found"),
+			unauthorized(),
+		),
+	})
+	b.add("DELETE", "/api/v1/notes/:id", &Operation{
+		Tags: []string{"notes"}, Summary: "Delete a note", OperationID: "deleteNote",
+		Parameters: []*Parameter{pathID("id", "Note ID")},
+		Responses:  responses(message("Note deleted"), notFound("Note not found"), unauthorized()),
+	})
+	b.add("POST", "/api/v1/notes/:id/restore", &Operation{
+		Tags: []string{"notes"}, Summary: "Restore a deleted note", OperationID: "restoreNote",
+		Description: "Undoes a delete. The note's links are extracted again from its content.",
+		Parameters:  []*Parameter{pathID("id", "Note ID")},
+		Responses:   responses(jsonResponse("The restored note", note), notFound("Deleted note not found"), unauthorized()),
+	})
+	b.add("GET", "/api/v1/notes/:id/stats", &Operation{
+		Tags: []string{"notes"}, Summary: "Get a note's view, edit and link statistics", OperationID: "getNoteStats",
+		Description: "Views per day over the last 30 days and per month over the last 12, in the user's timezone, oldest first. " +
+			"Views by users the note is shared with aren't counted; links to and from deleted notes are left out.",
+		Parameters: []*Parameter{pathID("id", "Note ID")},
+		Responses:  responses(jsonResponse("Note statistics", b.reg.ref(model.NoteStats{})), notFound("Note not found"), unauthorized()),
+	})
+	b.add("POST", "/api/v1/notes/:id/focus", &Operation{
+		Tags: []string{"notes"}, Summary: "Log a completed focus session on a note", OperationID: "logFocusSession",
+		Description: "Logged as a `focus` activity with the session's `duration_seconds` in its metadata, " +
+			"and counted in the note's stats.",
+		Parameters:  []*Parameter{pathID("id", "Note ID")},
+		RequestBody: jsonBody(b.reg.ref(model.LogFocusRequest{})),
+		Responses: responses(
+			created("The logged activity", b.reg.ref(model.Activity{})),
+			errorResponse(400, "Invalid duration"),
+			notFound("Note not found"),
+			unauthorized(),
+		),
+	})
+	b.add("GET", "/api/v1/notes/:id/revisions", &Operation{
+		Tags: []string{"notes"}, Summary: "List a note's revisions, newest first", OperationID: "listRevisions",
+		Parameters: []*Parameter{pathID("id", "Note ID")},
+		Responses: responses(
+			jsonResponse("Revisions", object("revisions", arrayOf(b.reg.ref(model.NoteRevision{})), "count", integer())),
+			notFound("Note not found"),
+			unauthorized(),
+		),
+	})
+	b.add("POST", "/api/v1/notes/:id/revisions/:rev/restore", &Operation{
+		Tags: []string{"notes"}, Summary: "Restore a revision", OperationID: "restoreRevision",
+		Parameters: []*Parameter{
+			pathID("id", "Note ID"),
+			{Name: "rev", In: "path", Required: true, Description: "Revision number", Schema: integer()},
+		},
+		Responses: responses(jsonResponse("The restored note", note), notFound("Revision not found"), unauthorized()),
+	})
+	b.add("POST", "/api/v1/notes/:id/summarize", &Operation{
+		Tags: []string{"notes"}, Summary: "Summarize a note", OperationID: "summarizeNote",
+		Description: "Generates a TL;DR with the server's LLM provider and stores it under `metadata.summary`, replacing an earlier one. Encrypted notes can't be summarized. Returns 503 when the server has no LLM provider.",
+		Parameters:  []*Parameter{pathID("id", "Note ID")},
+		Responses: responses(
+			jsonResponse("The summary", b.reg.ref(model.NoteSummary{})),
+			errorResponse(400, "Note is encrypted or empty"),
+			notFound("Note not found"),
+			errorResponse(503, "Summarization not enabled"),
+			unauthorized(),
+		),
+	})
+}
+
+func (b *builder) tagRoutes() {
+	tag := b.reg.ref(model.Tag{})
+
+	b.add("GET", "/api/v1/tags", &Operation{
+		Tags: []string{"tags"}, Summary: "List tags with note counts", OperationID: "listTags",
+		Parameters: pagination(),
+		Responses: responses(
+			jsonResponse("A page of tags", object("tags", arrayOf(b.reg.ref(model.TagWithCount{})), "pagination", b.reg.ref(model.Pagination{}))),
+			unauthorized(),
+		),
+	})
+	b.add("POST", "/api/v1/tags", &Operation{
+		Tags: []string{"tags"}, Summary: "Create a tag", OperationID: "createTag",
+		Description: "Names containing `/` create nested tags; missing parents are created.",
+		RequestBody: jsonBody(b.reg.ref(model.CreateTagRequest{})),
+		Responses:   responses(created("The new tag", tag), errorResponse(400, "Invalid request"), unauthorized()),
+	})
+	b.add("GET", "/api/v1/tags/analytics", &Operation{
+		Tags: []string{"tags"}, Summary: "Get tag usage over time and tags used together", OperationID: "getTagAnalytics",
+		Description: "Counts each tag's notes per month in the user's timezone. A tag is growing when it was put on more notes in the recent half of the months than the earlier half, and stale when it went unused for 90 days. Pairs are tags sharing at least two notes.",
+		Parameters: []*Parameter{
+			queryParam("months", &Schema{Type: "integer", Minimum: intPtr(1), Maximum: intPtr(24), Default: 6}, "Number of months up to this one"),
+			queryParam("pairs", &Schema{Type: "integer", Minimum: intPtr(1), Maximum: intPtr(50), Default: 10}, "Number of tag pairs"),
+		},
+		Responses: responses(jsonResponse("Tag analytics", b.reg.ref(model.TagAnalytics{})), unauthorized()),
+	})
+	b.add("GET", "/api/v1/tags/:id", &Operation{
+		Tags: []string{"tags"}, Summary: "Get a tag", OperationID: "getTag",
+		Parameters: []*Parameter{pathID("id", "Tag ID")},
+		Responses:  responses(jsonResponse("The tag", tag), notFound("Tag not found"), unauthorized()),
+	})
+	b.add("PUT", "/api/v1/tags/:id", &Operation{
+		Tags: []string{"tags"}, Summary: "Update a tag", OperationID: "updateTag",
+		Parameters:  []*Parameter{pathID("id", "Tag ID")},
+		RequestBody: jsonBody(b.reg.ref(model.UpdateTagRequest{})),
+		Responses:   responses(jsonResponse("The updated tag", tag), errorResponse(400, "Invalid request"), notFound("Tag not found"), unauthorized()),
+	})
+	b.add("DELETE", "/api/v1/tags/:id", &Operation{
+		Tags: []string{"tags"}, Summary: "Delete a tag", OperationID: "deleteTag",
+		Parameters: []*Parameter{pathID("id", "Tag ID")},
+		Responses:  responses(raw(204, &Response{Description: "Tag deleted"}), notFound("Tag not found"), unauthorized()),
+	})
+	b.add("GET", "/api/v1/tags/:id/notes", &Operation{
+		Tags: []string{"tags"}, Summary: "List notes with a tag", OperationID: "getTagNotes",
+		Parameters: []*Parameter{pathID("id", "Tag ID")},
+		Responses:  responses(jsonResponse("Notes", object("notes", arrayOf(b.reg.ref(model.Note{})))), notFound("Tag not found"), unauthorized()),
+	})
+	b.add("GET", "/api/v1/notes/:id/tags", &Operation{
+		Tags: []string{"tags"}, Summary: "List a note's tags", OperationID: "getNoteTags",
+		Parameters: []*Parameter{pathID("id", "Note ID")},
+		Responses:  responses(jsonResponse("Tags", object("tags", arrayOf(tag))), notFound("Note not found"), unauthorized()),
+	})
+	b.add("POST", "/api/v1/notes/:id/tags/:tag_id", &Operation{
+		Tags: []string{"tags"}, Summary: "Add a tag to a note", OperationID: "addTagToNote",
+		Parameters: []*Parameter{pathID("id", "Note ID"), pathID("tag_id", "Tag ID")},
+		Responses:  responses(message("Tag added to note"), notFound("Note or tag not found"), unauthorized()),
+	})
+	b.add("POST", "/api/v1/notes/tags/bulk", &Operation{
+		Tags: []string{"tags"}, Summary: "Add or remove a tag on many notes", OperationID: "bulkTagNotes",
+		Description: "Runs in one transaction: if any note is missing, no note is changed. `updated` counts the notes whose tags actually changed.",
+		RequestBody: jsonBody(b.reg.ref(model.BulkTagRequest{})),
+		Responses:   responses(jsonResponse("Number of notes changed", b.reg.ref(model.BulkTagResponse{})), errorResponse(400, "Invalid request"), notFound("Tag or note not found"), unauthorized()),
+	})
+	b.add("DELETE", "/api/v1/notes/:id/tags/:tag_id", &Operation{
+		Tags: []string{"tags"}, Summary: "Remove a tag from a note", OperationID: "removeTagFromNote",
+		Parameters: []*Parameter{pathID("id", "Note ID"), pathID("tag_id", "Tag ID")},
+		Responses:  responses(message("Tag removed from note"), notFound("Note not found"), unauthorized()),
+	})
+}
+
+func (b *builder) noteTypeRoutes() {
+	noteType := b.reg.ref(model.NoteTypeDefinition{})
+	name := &Parameter{Name: "name", In: "path", Required: true, Description: "Note type name", Schema: str()}
+
+	b.add("GET", "/api/v1/note-types", &Operation{
+		Tags: []string{"note-types"}, Summary: "List note types with note counts", OperationID: "listNoteTypes",
+		Description: "The built-in types come first, followed by the user's custom types.",
+		Responses: responses(
+			jsonResponse("Note types", object("note_types", arrayOf(noteType), "count", integer())),
+			unauthorized(),
+		),
+	})
+	b.add("POST", "/api/v1/note-types", &Operation{
+		Tags: []string{"note-types"}, Summary: "Create a custom note type", OperationID: "createNoteType",
+		Description: "Names are lowercased and may use letters, digits, `-` and `_`.",
+		RequestBody: jsonBody(b.reg.ref(model.CreateNoteTypeRequest{})),
+		Responses:   responses(created("The new note type", noteType), errorResponse(400, "Invalid request"), errorResponse(409, "Note type already exists"), unauthorized()),
+	})
+	b.add("PUT", "/api/v1/note-types/:name", &Operation{
+		Tags: []string{"note-types"}, Summary: "Change the icon or color of a custom note type", OperationID: "updateNoteType",
+		Parameters:  []*Parameter{name},
+		RequestBody: jsonBody(b.reg.ref(model.UpdateNoteTypeRequest{})),
+		Responses:   responses(jsonResponse("The updated note type", noteType), errorResponse(400, "Invalid request or built-in type"), notFound("Note type not found"), unauthorized()),
+	})
+	b.add("PUT", "/api/v1/note-types/:name/fields", &Operation{
+		Tags: []string{"note-types"}, Summary: "Replace the typed custom fields of a note type", OperationID: "setNoteTypeFields",
+		Description: "Fields apply to built-in and custom types. Their values are kept in note metadata under the field name and checked when a note of the type is created, or its type or metadata changes. An empty list removes the fields.",
+		Parameters:  []*Parameter{name},
+		RequestBody: jsonBody(b.reg.ref(model.SetNoteTypeFieldsRequest{})),
+		Responses:   responses(jsonResponse("The note type with its fields", noteType), errorResponse(400, "Invalid fields"), notFound("Note type not found"), unauthorized()),
+	})
+	b.add("DELETE", "/api/v1/note-types/:name", &Operation{
+		Tags: []string{"note-types"}, Summary: "Delete a custom note type", OperationID: "deleteNoteType",
+		Parameters: []*Parameter{name},
+		Responses: responses(
+			raw(204, &Response{Description: "Note type deleted"}),
+			errorResponse(400, "Built-in types can't be deleted"),
+			notFound("Note type not found"),
+			errorResponse(409, "Notes still have the type, or it is the default type"),
+			unauthorized(),
+		),
+	})
+}
+
+func (b *builder) linkRoutes() {
+	linkDetail := b.reg.ref(model.LinkDetail{})
+
+	b.add("GET", "/api/v1/notes/:id/links", &Operation{
+		Tags: []string{"links"}, Summary: "List outgoing links of a note", OperationID: "getOutgoingLinks",
+		Parameters: []*Parameter{pathID("id", "Note ID")},
+		Responses:  responses(jsonResponse("Links with target notes", arrayOf(linkDetail)), unauthorized()),
+	})
+	b.add("GET", "/api/v1/notes/:id/backlinks", &Operation{
+		Tags: []string{"links"}, Summary: "List backlinks of a note", OperationID: "getBacklinks",
+		Parameters: []*Parameter{pathID("id", "Note ID")},
+		Responses:  responses(jsonResponse("Links with source notes", arrayOf(linkDetail)), unauthorized()),
+	})
+	b.add("GET", "/api/v1/notes/:id/related", &Operation{
+		Tags: []string{"links"}, Summary: "Suggest notes related to a note", OperationID: "getRelatedNotes",
+		Description: "Notes are scored 0-1 by shared tags, notes both link with (or a direct link) and the cosine similarity of their indexed words, best first. Notes with nothing in common are left out.",
+		Parameters: []*Parameter{
+			pathID("id", "Note ID"),
+			queryParam("limit", &Schema{Type: "integer", Minimum: intPtr(1), Maximum: intPtr(50), Default: 10}, "Maximum number of notes"),
+		},
+		Responses: responses(jsonResponse("Related notes", arrayOf(b.reg.ref(model.RelatedNote{}))), notFound("Note not found"), unauthorized()),
+	})
+	b.add("GET", "/api/v1/notes/graph", &Operation{
+		Tags: []string{"links"}, Summary: "Get the knowledge graph", OperationID: "getLinkGraph",
+		Description: "Without `root` the whole graph is returned; with `root` only notes within `depth` links of it. `stats` describe the graph returned: degrees, components and depth count links in both directions, and `hubs` rank notes by PageRank over the links' direction. Each node's `cluster` comes from label propagation over the links, numbered from 1 largest first.",
+		Parameters: []*Parameter{
+			queryParam("root", uuidSchema(), "Center note ID for a local graph"),
+			queryParam("depth", &Schema{Type: "integer", Minimum: intPtr(1), Maximum: intPtr(5), Default: 2}, "Hops from the root note"),
+		},
+		Responses: responses(jsonResponse("Nodes and edges", b.reg.ref(model.GraphResponse{})), notFound("Root note not found"), unauthorized()),
+	})
+	b.add("GET", "/api/v1/notes/graph/path", &Operation{
+		Tags: []string{"links"}, Summary: "Find the shortest chain of links between two notes", OperationID: "getGraphPath",
+		Description: "Links are followed in either direction, up to 10 of them. The path starts with the `from` note and ends with the `to` note.",
+		Parameters: []*Parameter{
+			{Name: "from", In: "query", Required: true, Description: "Start note ID", Schema: uuidSchema()},
+			{Name: "to", In: "query", Required: true, Description: "End note ID", Schema: uuidSchema()},
+		},
+		Responses: responses(jsonResponse("The notes on the path and the links between them", b.reg.ref(model.GraphPath{})), errorResponse(400, "Invalid note ID"), notFound("Note not found, or no path between the notes"), unauthorized()),
+	})
+	b.add("GET", "/api/v1/links/unresolved", &Operation{
+		Tags: []string{"links"}, Summary: "List links to notes that don't exist yet", OperationID: "getUnresolvedLinks",
+		Responses: responses(jsonResponse("Unresolved links", arrayOf(b.reg.ref(model.UnresolvedLink{}))), unauthorized()),
+	})
+}
+
+func (b *builder) searchRoutes() {
+	b.add("GET", "/api/v1/search", &Operation{
+		Tags: []string{"search"}, Summary: "Full-text search", OperationID: "search",
+		Parameters: append([]*Parameter{
+			{Name: "q", In: "query", Required: true, Description: "Search query", Schema: str()},
+		}, append(pagination(),
+			queryParam("type", b.reg.ref(model.NoteType("")), "Filter by note type, built-in or custom"),
+			queryParam("tag_id", uuidSchema(), "Filter by tag ID"),
+			queryParam("sort_by", &Schema{Type: "string", Enum: []string{"created_at", "updated_at", "title", "access_count", "word_count", "relevance"}, Default: "relevance"}, "Sort field"),
+			queryParam("sort_order", &Schema{Type: "string", Enum: []string{"asc", "desc"}, Default: "desc"}, "Sort direction"),
+			queryParam("fragment_size", &Schema{Type: "integer", Minimum: intPtr(model.MinFragmentSize), Maximum: intPtr(model.MaxFragmentSize), Default: model.DefaultFragmentSize}, "Words per snippet fragment; matches are wrapped in `<b></b>`"),
+			queryParam("fuzzy", &Schema{Type: "boolean", Default: false}, "When nothing matches, list notes with similarly spelled titles instead (`fuzzy` is set in the response)"),
+		)...),
+		Responses: responses(jsonResponse("Search results", b.reg.ref(model.SearchResponse{})), errorResponse(400, "Missing query or invalid parameters"), unauthorized()),
+	})
+	b.add("GET", "/api/v1/search/history", &Operation{
+		Tags: []string{"search"}, Summary: "Recent search queries", OperationID: "searchHistory",
+		Description: "Lists the distinct queries of `GET /api/v1/search`, most recently searched first. Only the first page of a search is recorded.",
+		Parameters: []*Parameter{
+			queryParam("limit", &Schema{Type: "integer", Minimum: intPtr(1), Maximum: intPtr(model.MaxSearchHistoryLimit), Default: model.DefaultSearchHistoryLimit}, "Number of queries"),
+		},
+		Responses: responses(jsonResponse("Recent queries", b.reg.ref(model.SearchHistoryResponse{})), errorResponse(400, "Invalid limit"), unauthorized()),
+	})
+	b.add("POST", "/api/v1/search/semantic", &Operation{
+		Tags: []string{"search"}, Summary: "Semantic search", OperationID: "semanticSearch",
+		Description: "Finds notes closest in meaning to the query using embeddings, even without shared keywords. `rank` is the cosine similarity. Notes are embedded in the background, so recent edits can take a moment to show up. Returns 503 when the server has no embeddings provider or pgvector.",
+		RequestBody: jsonBody(b.reg.ref(model.SemanticSearchRequest{})),
+		Responses:   responses(jsonResponse("Closest notes first", b.reg.ref(model.SemanticSearchResponse{})), errorResponse(400, "Invalid request"), errorResponse(503, "Semantic search not enabled"), unauthorized()),
+	})
+}
+
+func (b *builder) attachmentRoutes() {
+	attachment := b.reg.ref(model.Attachment{})
+	params := []*Parameter{pathID("id", "Note ID"), pathID("attachment_id", "Attachment ID")}
+
+	b.add("POST", "/api/v1/notes/:id/attachments", &Operation{
+		Tags: []string{"attachments"}, Summary: "Upload an attachment", OperationID: "uploadAttachment",
+		Parameters: []*Parameter{pathID("id", "Note ID")},
+		RequestBody: &RequestBody{
+			Required: true,
+			Content: map[string]MediaType{"multipart/form-data": {Schema: &Schema{
+				Type:       "object",
+				Properties: map[string]*Schema{"file": {Type: "string", Format: "binary"}},
+				Required:   []string{"file"},
+			}}},
+		},
+		Responses: responses(created("The new attachment", attachment), errorResponse(400, "Missing file or invalid name"), notFound("Note not found"), errorResponse(413, "File too large"), unauthorized()),
+	})
+	b.add("GET", "/api/v1/notes/:id/attachments", &Operation{
+		Tags: []string{"attachments"}, Summary: "List a note's attachments", OperationID: "listAttachments",
+		Parameters: []*Parameter{pathID("id", "Note ID")},
+		Responses:  responses(jsonResponse("Attachments", object("attachments", arrayOf(attachment), "count", integer())), notFound("Note not found"), unauthorized()),
+	})
+	b.add("GET", "/api/v1/notes/:id/attachments/:attachment_id", &Operation{
+		Tags: []string{"attachments"}, Summary: "Download an attachment", OperationID: "downloadAttachment",
+		Parameters: params,
+		Responses: responses(raw(200, &Response{
+			Description: "File contents",
+			Content:     map[string]MediaType{"application/octet-stream": {Schema: &Schema{Type: "string", Format: "binary"}}},
+		}), notFound("Attachment not found"), unauthorized()),
+	})
+	b.add("DELETE", "/api/v1/notes/:id/attachments/:attachment_id", &Operation{
+		Tags: []string{"attachments"}, Summary: "Delete an attachment", OperationID: "deleteAttachment",
+		Parameters: params,
+		Responses:  responses(message("Attachment deleted successfully"), notFound("Attachment not found"), unauthorized()),
+	})
+}
+
+func (b *builder) publicLinkRoutes() {
+	link := b.reg.ref(model.PublicLink{})
+
+	b.add("POST", "/api/v1/notes/:id/share", &Operation{
+		Tags: []string{"public-links"}, Summary: "Publish a note at a public link", OperationID: "publishNote",
+		Description: "Returns a signed link to a read-only HTML page of the note. Its wiki links to other published notes " +
+			"lead to their pages, the rest show as plain text. Publishing a published note returns its existing link.",
+		Parameters: []*Parameter{pathID("id", "Note ID")},
+		Responses: responses(
+			jsonResponse("The note was already published", link),
+			created("The new public link", link),
+			errorResponse(400, "Encrypted notes can't be published"),
+			notFound("Note not found"),
+			unauthorized(),
+		),
+	})
+	b.add("DELETE", "/api/v1/notes/:id/share", &Operation{
+		Tags: []string{"public-links"}, Summary: "Revoke a note's public link", OperationID: "unpublishNote",
+		Parameters: []*Parameter{pathID("id", "Note ID")},
+		Responses:  responses(message("Public link revoked successfully"), notFound("Note is not published"), unauthorized()),
+	})
+	b.add("GET", "/api/v1/public-links", &Operation{
+		Tags: []string{"public-links"}, Summary: "List public links", OperationID: "listPublicLinks",
+		Responses: responses(jsonResponse("Published notes by title", object("links", arrayOf(link), "count", integer())), unauthorized()),
+	})
+	b.add("GET", "/s/:token", &Operation{
+		Tags: []string{"public-links"}, Summary: "Public page of a published note", OperationID: "getPublicNote",
+		Description: "Lists the other published notes linking to it. Revoked and tampered links answer 404.",
+		Security:    public(),
+		Parameters:  []*Parameter{{Name: "token", In: "path", Required: true, Schema: str(), Description: "Signed link token"}},
+		Responses: responses(raw(200, &Response{
+			Description: "HTML page",
+			Content:     map[string]MediaType{"text/html": {Schema: str()}},
+		}), notFound("Link revoked or invalid")),
+	})
+}
+
+func (b *builder) noteShareRoutes() {
+	share := b.reg.ref(model.NoteShare{})
+	params := []*Parameter{pathID("id", "Note ID")}
+
+	b.add("GET", "/api/v1/notes/shared", &Operation{
+		Tags: []string{"sharing"}, Summary: "List notes shared with me", OperationID: "listSharedWithMe",
+		Description: "Notes other users share with you, recently updated first, with `shared_by` and `permission` set. " +
+			"Notes their owner encrypted are left out.",
+		Responses: responses(jsonResponse("Shared notes", object("notes", arrayOf(b.reg.ref(model.Note{})), "count", integer())), unauthorized()),
+	})
+	b.add("GET", "/api/v1/notes/:id/shares", &Operation{
+		Tags: []string{"sharing"}, Summary: "List who a note is shared with", OperationID: "listNoteShares",
+		Parameters: params,
+		Responses:  responses(jsonResponse("Users by username", object("shares", arrayOf(share), "count", integer())), notFound("Note not found"), unauthorized()),
+	})
+	b.add("POST", "/api/v1/notes/:id/shares", &Operation{
+		Tags: []string{"sharing"}, Summary: "Share a note with a user", OperationID: "shareNoteWithUser",
+		Description: "`user` is a username or email. With `read` the user can open the note, with `write` also edit its " +
+			"title and content; tags, links, revisions and attachments stay the owner's. Sharing again changes the permission.",
+		Parameters:  params,
+		RequestBody: jsonBody(b.reg.ref(model.ShareNoteRequest{})),
+		Responses: responses(
+			jsonResponse("The permission of an existing share was changed", share),
+			created("The new share", share),
+			errorResponse(400, "Unknown user, the note is encrypted, or it is your own account"),
+			notFound("Note not found"),
+			unauthorized(),
+		),
+	})
+	b.add("DELETE", "/api/v1/notes/:id/shares/:user_id", &Operation{
+		Tags: []string{"sharing"}, Summary: "Stop sharing a note with a user", OperationID: "unshareNoteWithUser",
+		Parameters: append(params, pathID("user_id", "ID of the user the note is shared with")),
+		Responses:  responses(message("Note is no longer shared with the user"), notFound("Note is not shared with the user"), unauthorized()),
+	})
+}
+
+func (b *builder) webhookRoutes() {
+	hook := b.reg.ref(model.Webhook{})
+	params := []*Parameter{pathID("id", "Webhook ID")}
+
+	b.add("GET", "/api/v1/webhooks", &Operation{
+		Tags: []string{"webhooks"}, Summary: "List webhooks", OperationID: "listWebhooks",
+		Responses: responses(jsonResponse("Webhooks, oldest first, without secrets", object("webhooks", arrayOf(hook), "count", integer())), unauthorized()),
+	})
+	b.add("POST", "/api/v1/webhooks", &Operation{
+		Tags: []string{"webhooks"}, Summary: "Register a webhook", OperationID: "createWebhook",
+		Description: "Each event is POSTed to `url` as a `WebhookPayload`, with the `X-KG-Event` and `X-KG-Delivery` headers " +
+			"and `X-KG-Signature: sha256=<hex>`, the HMAC-SHA256 of the body keyed by `secret`. The payload's `text` " +
+			"summarizes the event, so Slack incoming webhooks can post it as is. Responses other than 2xx are retried " +
+			"with backoff. The secret is only returned here.",
+		RequestBody: jsonBody(b.reg.ref(model.CreateWebhookRequest{})),
+		Responses:   responses(created("The webhook, with its secret", hook), errorResponse(400, "Invalid URL or events, or too many webhooks"), unauthorized()),
+	})
+	b.add("PUT", "/api/v1/webhooks/:id", &Operation{
+		Tags: []string{"webhooks"}, Summary: "Update a webhook", OperationID: "updateWebhook",
+		Description: "Fields left out are kept. Inactive webhooks don't receive events.",
+		Parameters:  params,
+		RequestBody: jsonBody(b.reg.ref(model.UpdateWebhookRequest{})),
+		Responses:   responses(jsonResponse("The webhook", hook), errorResponse(400, "Invalid URL or events"), notFound("Webhook not found"), unauthorized()),
+	})
+	b.add("DELETE", "/api/v1/webhooks/:id", &Operation{
+		Tags: []string{"webhooks"}, Summary: "Delete a webhook", OperationID: "deleteWebhook",
+		Parameters: params,
+		Responses:  responses(message("Webhook deleted"), notFound("Webhook not found"), unauthorized()),
+	})
+	b.add("GET", "/api/v1/webhooks/:id/deliveries", &Operation{
+		Tags: []string{"webhooks"}, Summary: "List webhook deliveries", OperationID: "listWebhookDeliveries",
+		Description: "Deliveries of the last 30 days, newest first, with the outcome of their last attempt. " +
+			"The `payload` is a `WebhookPayload`.",
+		Parameters: append(params,
+			queryParam("limit", &Schema{Type: "integer", Minimum: intPtr(1), Maximum: intPtr(100), Default: 20}, "Number of deliveries"),
+		),
+		Responses: responses(jsonResponse("Deliveries", object("deliveries", arrayOf(b.reg.ref(model.WebhookDelivery{})), "count", integer())), notFound("Webhook not found"), unauthorized()),
+	})
+	// Documents the body of deliveries, which aren't an API response
+	b.reg.ref(model.WebhookPayload{})
+}
+
+func (b *builder) taskRoutes() {
+	b.add("GET", "/api/v1/tasks", &Operation{
+		Tags: []string{"tasks"}, Summary: "List tasks from note checkboxes", OperationID: "listTasks",
+		Parameters: []*Parameter{queryParam("status", &Schema{Type: "string", Enum: enumValues[reflect.TypeOf(model.TaskStatus(""))], Default: "open"}, "Filter by completion")},
+		Responses: responses(
+			jsonResponse("Tasks", object("tasks", arrayOf(b.reg.ref(model.Task{})), "count", integer())),
+			errorResponse(400, "Invalid status"),
+			unauthorized(),
+		),
+	})
+}
+
+func (b *builder) reviewRoutes() {
+	b.add("GET", "/api/v1/review/due", &Operation{
+		Tags: []string{"review"}, Summary: "List flashcards due for review today", OperationID: "listDueCards",
+		Description: "Cards are Q:: ... A:: ... pairs in note content. Cards due today or earlier in the user's timezone come first, then new cards.",
+		Parameters:  []*Parameter{queryParam("limit", &Schema{Type: "integer", Minimum: intPtr(1), Maximum: intPtr(model.MaxDueCardsLimit), Default: model.DefaultDueCardsLimit}, "Cards to return")},
+		Responses:   responses(jsonResponse("Due cards", b.reg.ref(model.DueCards{})), errorResponse(400, "Invalid limit"), unauthorized()),
+	})
+	b.add("POST", "/api/v1/review/due/:id", &Operation{
+		Tags: []string{"review"}, Summary: "Grade a flashcard review and schedule the next one (SM-2)", OperationID: "reviewCard",
+		Parameters:  []*Parameter{pathID("id", "Card ID")},
+		RequestBody: jsonBody(b.reg.ref(model.ReviewCardRequest{})),
+		Responses:   responses(jsonResponse("The rescheduled card", b.reg.ref(model.Card{})), errorResponse(400, "Invalid grade"), notFound("Card not found"), unauthorized()),
+	})
+}
+
+func (b *builder) activityRoutes() {
+	b.add("GET", "/api/v1/activity", &Operation{
+		Tags: []string{"activity"}, Summary: "List activity", OperationID: "listActivity",
+		Description: "Activity entries matching every given filter, newest first. `from` and `to` take RFC 3339 timestamps or YYYY-MM-DD dates in the user's timezone; a `to` date includes the whole day.",
+		Parameters: []*Parameter{
+			queryParam("action", &Schema{Type: "string", Enum: enumValues[reflect.TypeOf(model.ActionType(""))]}, "Filter by action"),
+			queryParam("note_id", &Schema{Type: "string", Format: "uuid"}, "Filter by note"),
+			queryParam("from", &Schema{Type: "string"}, "Earliest entry to include"),
+			queryParam("to", &Schema{Type: "string"}, "Latest entry to include"),
+			queryParam("page", &Schema{Type: "integer", Minimum: intPtr(1), Default: 1}, "Page number"),
+			queryParam("limit", &Schema{Type: "integer", Minimum: intPtr(1), Maximum: intPtr(100), Default: 20}, "Items per page"),
+		},
+		Responses: responses(
+			jsonResponse("A page of activity entries", object("activities", arrayOf(b.reg.ref(model.Activity{})), "pagination", b.reg.ref(model.Pagination{}))),
+			errorResponse(400, "Invalid filter"),
+			unauthorized(),
+		),
+	})
+	b.add("GET", "/api/v1/activity/recent", &Operation{
+		Tags: []string{"activity"}, Summary: "Recent activity", OperationID: "getRecentActivity",
+		Parameters: []*Parameter{queryParam("limit", &Schema{Type: "integer", Minimum: intPtr(1), Maximum: intPtr(100), Default: 20}, "Maximum number of entries")},
+		Responses:  responses(jsonResponse("Activity entries", object("activities", arrayOf(b.reg.ref(model.Activity{})))), unauthorized()),
+	})
+	b.add("GET", "/api/v1/activity/heatmap", &Operation{
+		Tags: []string{"activity"}, Summary: "Daily activity heatmap", OperationID: "getActivityHeatmap",
+		Description: "Notes created or updated per day, oldest first. Days start on the user's `week_start` and run through today, in their timezone, so they split into columns of 7.",
+		Parameters:  []*Parameter{queryParam("weeks", &Schema{Type: "integer", Minimum: intPtr(1), Maximum: intPtr(53), Default: 12}, "Number of weeks, including this one")},
+		Responses:   responses(jsonResponse("Activity per day", b.reg.ref(model.ActivityHeatmap{})), unauthorized()),
+	})
+	b.add("GET", "/api/v1/stats", &Operation{
+		Tags: []string{"activity"}, Summary: "User statistics", OperationID: "getUserStats",
+		Description: "Notes created today and this week are counted in the user's timezone and week start (see `/api/v1/settings`).",
+		Responses:   responses(jsonResponse("Statistics", b.reg.ref(model.UserStats{})), unauthorized()),
+	})
+	b.add("GET", "/api/v1/stats/streak", &Operation{
+		Tags: []string{"activity"}, Summary: "Writing streak", OperationID: "getWritingStreak",
+		Description: "Consecutive days on which the user wrote at least `daily_word_goal` words (see `/api/v1/settings`). New notes count all their words, edits only the words they add. A streak whose last day is yesterday is still current.",
+		Responses:   responses(jsonResponse("Streak and today's progress", b.reg.ref(model.WritingStreak{})), unauthorized()),
+	})
+	b.add("GET", "/api/v1/stats/daily-words", &Operation{
+		Tags: []string{"activity"}, Summary: "Words written per day", OperationID: "getDailyWords",
+		Description: "One entry per day, oldest first and including days without writing, in the user's timezone.",
+		Parameters:  []*Parameter{queryParam("days", &Schema{Type: "integer", Minimum: intPtr(1), Maximum: intPtr(365), Default: 30}, "Number of days up to today")},
+		Responses: responses(
+			jsonResponse("Words per day", object("days", arrayOf(b.reg.ref(model.DailyWords{})), "daily_goal", integer())),
+			unauthorized(),
+		),
+	})
+	b.add("GET", "/api/v1/notes/trending", &Operation{
+		Tags: []string{"activity"}, Summary: "Most accessed notes", OperationID: "getTrendingNotes",
+		Parameters: []*Parameter{queryParam("limit", &Schema{Type: "integer", Default: 10}, "Maximum number of notes")},
+		Responses:  responses(jsonResponse("Trending notes", object("trending", arrayOf(b.reg.ref(model.TrendingNote{})))), unauthorized()),
+	})
+	b.add("GET", "/api/v1/notes/forgotten", &Operation{
+		Tags: []string{"activity"}, Summary: "Notes not accessed in a while", OperationID: "getForgottenNotes",
+		Parameters: []*Parameter{
+			queryParam("days", integer(), "Minimum days since last access (default the forgotten_days setting)"),
+			queryParam("limit", &Schema{Type: "integer", Default: 10}, "Maximum number of notes"),
+		},
+		Responses: responses(jsonResponse("Forgotten notes", object("forgotten", arrayOf(b.reg.ref(model.ForgottenNote{})), "days", integer())), unauthorized()),
+	})
+	b.add("GET", "/api/v1/events", &Operation{
+		Tags: []string{"activity"}, Summary: "Live update stream", OperationID: "streamEvents",
+		Description: "Server-sent events. Each `data:` line is a JSON Event; idle streams receive a `: ping` comment every 15 seconds.",
+		Responses: responses(raw(200, &Response{
+			Description: "Event stream",
+			Content:     map[string]MediaType{"text/event-stream": {Schema: b.reg.ref(model.Event{})}},
+		}), unauthorized()),
+	})
+}
+
+func (b *builder) settingsRoutes() {
+	template := b.reg.ref(model.DailyTemplateResponse{})
+	settings := b.reg.ref(model.UserSettings{})
+
+	b.add("GET", "/api/v1/settings", &Operation{
+		Tags: []string{"settings"}, Summary: "Get the user's preferences", OperationID: "getSettings",
+		Description: "Users that never changed a preference get the defaults.",
+		Responses:   responses(jsonResponse("The user's preferences", settings), unauthorized()),
+	})
+	b.add("PUT", "/api/v1/settings", &Operation{
+		Tags: []string{"settings"}, Summary: "Update the user's preferences", OperationID: "updateSettings",
+		Description: "Only the fields present in the body are changed. `timezone` is an IANA name such as `Europe/Berlin`. A `digest_url` (Slack or Discord incoming webhook) is posted yesterday's notes created, words written and forgotten notes every day at `digest_hour` in the user's timezone; an empty string turns the digest off.",
+		RequestBody: jsonBody(b.reg.ref(model.UpdateSettingsRequest{})),
+		Responses:   responses(jsonResponse("The updated preferences", settings), errorResponse(400, "Invalid preference"), unauthorized()),
+	})
+	b.add("GET", "/api/v1/settings/daily-template", &Operation{
+		Tags: []string{"settings"}, Summary: "Get the daily note template", OperationID: "getDailyTemplate",
+		Responses: responses(jsonResponse("The effective template", template), unauthorized()),
+	})
+	b.add("PUT", "/api/v1/settings/daily-template", &Operation{
+		Tags: []string{"settings"}, Summary: "Set the daily note template", OperationID: "updateDailyTemplate",
+		Description: "An empty template resets to the default.",
+		RequestBody: jsonBody(b.reg.ref(model.UpdateDailyTemplateRequest{})),
+		Responses:   responses(jsonResponse("The effective template", template), errorResponse(400, "Invalid template"), unauthorized()),
+	})
+}
+
+func (b *builder) adminRoutes() {
+	forbidden := errorResponse(403, "Admin access required")
+
+	b.add("GET", "/api/v1/admin/users", &Operation{
+		Tags: []string{"admin"}, Summary: "List all users", OperationID: "adminListUsers",
+		Description: "Every account on the instance, oldest first, including deactivated and deleted ones.",
+		Parameters: []*Parameter{
+			queryParam("page", &Schema{Type: "integer", Minimum: intPtr(1), Default: 1}, "Page number"),
+			queryParam("limit", &Schema{Type: "integer", Minimum: intPtr(1), Maximum: intPtr(100), Default: 20}, "Items per page"),
+		},
+		Responses: responses(
+			jsonResponse("A page of users", object("users", arrayOf(b.reg.ref(model.AdminUser{})), "pagination", b.reg.ref(model.Pagination{}))),
+			unauthorized(),
+			forbidden,
+		),
+	})
+	b.add("POST", "/api/v1/admin/users/:id/deactivate", &Operation{
+		Tags: []string{"admin"}, Summary: "Deactivate a user", OperationID: "adminDeactivateUser",
+		Description: "Blocks the user from signing in and revokes their sessions; access tokens already issued stay valid until they expire. Their notes are kept. Admins can't deactivate themselves.",
+		Parameters:  []*Parameter{pathID("id", "User ID")},
+		Responses: responses(
+			message("User deactivated"),
+			errorResponse(400, "Invalid user ID or own account"),
+			unauthorized(),
+			forbidden,
+			notFound("User not found or deleted"),
+		),
+	})
+	b.add("POST", "/api/v1/admin/users/:id/activate", &Operation{
+		Tags: []string{"admin"}, Summary: "Reactivate a user", OperationID: "adminActivateUser",
+		Description: "Lets a deactivated user sign in again. Accounts their owner deleted can't be reactivated.",
+		Parameters:  []*Parameter{pathID("id", "User ID")},
+		Responses: responses(
+			message("User activated"),
+			errorResponse(400, "Invalid user ID"),
+			unauthorized(),
+			forbidden,
+			notFound("User not found or deleted"),
+		),
+	})
+	b.add("GET", "/api/v1/admin/stats", &Operation{
+		Tags: []string{"admin"}, Summary: "Instance statistics", OperationID: "adminGetStats",
+		Description: "Totals across all users. Weekly counts cover the last 7 days.",
+		Responses:   responses(jsonResponse("Statistics", b.reg.ref(model.AdminStats{})), unauthorized(), forbidden),
+	})
+	b.add("GET", "/api/v1/admin/audit", &Operation{
+		Tags: []string{"admin"}, Summary: "List the security events of all users", OperationID: "adminListAuditLog",
+		Description: "The audit log across the instance, newest first, including failed logins with unknown emails. Dates without a time are UTC days.",
+		Parameters: append([]*Parameter{
+			queryParam("user_id", &Schema{Type: "string", Format: "uuid"}, "Filter by user"),
+		}, auditFilterParams()...),
+		Responses: responses(
+			jsonResponse("A page of audit log entries", object("entries", arrayOf(b.reg.ref(model.AuditEntry{})), "pagination", b.reg.ref(model.Pagination{}))),
+			errorResponse(400, "Invalid filter"),
+			unauthorized(),
+			forbidden,
+		),
+	})
+	b.add("GET", "/api/v1/admin/backups", &Operation{
+		Tags: []string{"admin"}, Summary: "List backups", OperationID: "adminListBackups",
+		Description: "Backups in the backup store (`BACKUP_DRIVER`), newest first.",
+		Responses: responses(
+			jsonResponse("Backups", object("backups", arrayOf(b.reg.ref(model.Backup{})), "count", integer())),
+			unauthorized(),
+			forbidden,
+		),
+	})
+	b.add("POST", "/api/v1/admin/backups", &Operation{
+		Tags: []string{"admin"}, Summary: "Back up all user data now", OperationID: "adminCreateBackup",
+		Description: "Writes users, settings, notes, tags, links, revisions, tasks, attachment records and activity " +
+			"from one snapshot to a gzipped tar of JSON Lines files. Attachment files themselves are not included.",
+		Responses: responses(created("The backup written", b.reg.ref(model.BackupResult{})), unauthorized(), forbidden),
+	})
+	b.add("POST", "/api/v1/admin/backups/:name/restore", &Operation{
+		Tags: []string{"admin"}, Summary: "Restore a backup", OperationID: "adminRestoreBackup",
+		Description: "Adds the rows of the backup that are missing from the database, in one transaction. " +
+			"Rows that still exist are kept as they are, so newer edits are never overwritten.",
+		Parameters: []*Parameter{{Name: "name", In: "path", Required: true, Description: "Backup name", Schema: str()}},
+		Responses: responses(
+			jsonResponse("Rows restored per table", b.reg.ref(model.RestoreResult{})),
+			errorResponse(400, "Invalid backup name or archive"),
+			unauthorized(),
+			forbidden,
+			notFound("Backup not found"),
+		),
+	})
+}
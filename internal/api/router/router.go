@@ -4,51 +4,114 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/momokii/go-cli-notes/internal/api/handler"
 	"github.com/momokii/go-cli-notes/internal/api/middleware"
+	"github.com/momokii/go-cli-notes/internal/config"
 )
 
 // Setup configures all routes for the API
-func Setup(app *fiber.App, h *handler.Handlers, jwtManager any) {
+func Setup(app *fiber.App, h *handler.Handlers, jwtManager any, adminService any, rateLimit config.RateLimitConfig) {
+	// One limiter shared by all routes: keyed by user after Auth, by IP before it
+	limiter := middleware.RateLimit(rateLimit)
+
 	// Health check
 	app.Get("/health", func(c *fiber.Ctx) error {
 		return c.JSON(fiber.Map{"status": "ok"})
 	})
 
+	// Prometheus metrics, only registered when enabled
+	if h.Metrics != nil {
+		app.Get("/metrics", h.Metrics.Metrics)
+	}
+
+	// Public pages of shared notes, behind signed links
+	app.Get("/s/:token", limiter, h.PublicLink.PublicNote)
+
+	// Device login page, where a code shown by 'kg-cli login --device' is approved
+	app.Get("/device", limiter, h.Auth.DevicePage)
+	app.Post("/device", limiter, h.Auth.DeviceLogin)
+
 	// API v1 routes
 	v1 := app.Group("/api/v1")
 
+	// API documentation (public)
+	v1.Get("/openapi.json", h.Docs.OpenAPI)
+	v1.Get("/docs", h.Docs.SwaggerUI)
+
 	// Auth routes (public)
 	auth := v1.Group("/auth")
-	auth.Post("/register", h.Auth.Register)
-	auth.Post("/login", h.Auth.Login)
-	auth.Post("/refresh", h.Auth.RefreshToken)
-	auth.Post("/logout", middleware.Auth(jwtManager), h.Auth.Logout)
+	auth.Post("/register", limiter, h.Auth.Register)
+	auth.Post("/login", limiter, h.Auth.Login)
+	auth.Post("/refresh", limiter, h.Auth.RefreshToken)
+	auth.Post("/verify-email", limiter, h.Auth.VerifyEmail)
+	auth.Post("/resend-verification", limiter, h.Auth.ResendVerification)
+	auth.Post("/forgot-password", limiter, h.Auth.ForgotPassword)
+	auth.Post("/reset-password", limiter, h.Auth.ResetPassword)
+	auth.Post("/device", limiter, h.Auth.StartDeviceAuth)
+	auth.Post("/device/token", limiter, h.Auth.DeviceToken)
+	auth.Post("/device/approve", middleware.Auth(jwtManager), limiter, h.Auth.ApproveDevice)
+	auth.Post("/logout", middleware.Auth(jwtManager), limiter, h.Auth.Logout)
+	auth.Get("/sessions", middleware.Auth(jwtManager), limiter, h.Auth.ListSessions)
+	auth.Delete("/sessions", middleware.Auth(jwtManager), limiter, h.Auth.RevokeAllSessions)
+	auth.Delete("/sessions/:id", middleware.Auth(jwtManager), limiter, h.Auth.RevokeSession)
+	auth.Post("/2fa/setup", middleware.Auth(jwtManager), limiter, h.Auth.SetupTOTP)
+	auth.Post("/2fa/verify", middleware.Auth(jwtManager), limiter, h.Auth.VerifyTOTP)
+
+	// User account routes (authenticated)
+	users := v1.Group("/users")
+	users.Use(middleware.Auth(jwtManager), limiter)
+	users.Put("/me/password", h.User.ChangePassword)
+	users.Delete("/me", h.User.DeleteAccount)
 
 	// Tag routes (authenticated)
 	tags := v1.Group("/tags")
-	tags.Use(middleware.Auth(jwtManager))
+	tags.Use(middleware.Auth(jwtManager), limiter)
 	tags.Get("/", h.Tag.ListTags)
 	tags.Post("/", h.Tag.CreateTag)
+	tags.Get("/analytics", h.Tag.GetAnalytics)
 	tags.Get("/:id/notes", h.Tag.GetTagNotes)
 	tags.Get("/:id", h.Tag.GetTag)
 	tags.Put("/:id", h.Tag.UpdateTag)
 	tags.Delete("/:id", h.Tag.DeleteTag)
 
+	// Note type routes (authenticated)
+	noteTypes := v1.Group("/note-types")
+	noteTypes.Use(middleware.Auth(jwtManager), limiter)
+	noteTypes.Get("/", h.NoteType.ListNoteTypes)
+	noteTypes.Post("/", h.NoteType.CreateNoteType)
+	noteTypes.Put("/:name", h.NoteType.UpdateNoteType)
+	noteTypes.Put("/:name/fields", h.NoteType.SetNoteTypeFields)
+	noteTypes.Delete("/:name", h.NoteType.DeleteNoteType)
+
 	// Note routes (authenticated)
 	notes := v1.Group("/notes")
-	notes.Use(middleware.Auth(jwtManager))
+	notes.Use(middleware.Auth(jwtManager), limiter)
 
 	// Define specific routes BEFORE parameterized routes
-	notes.Get("/graph", h.Link.GetLinkGraph)
+	notes.Get("/graph", middleware.ETag(), h.Link.GetLinkGraph)
+	notes.Get("/graph/path", h.Link.GetGraphPath)
+	notes.Get("/export", h.Note.Export)
+	notes.Get("/daily", h.Note.ListDailyNotes)
 	notes.Get("/daily/:date", h.Note.GetOrCreateDailyNote)
 	notes.Get("/trending", h.Activity.GetTrendingNotes)
 	notes.Get("/forgotten", h.Activity.GetForgottenNotes)
+	notes.Post("/review", h.Note.CreateWeeklyReview)
+	notes.Post("/capture", h.Note.Capture)
+	notes.Get("/inbox", h.Note.GetInbox)
+	notes.Get("/reading", h.Note.ListReading)
+	notes.Get("/shared", h.NoteShare.SharedWithMe)
+	notes.Post("/batch", h.Note.CreateBatch)
+	notes.Post("/tags/bulk", h.Tag.BulkTagNotes)
 
 	// General note routes
 	notes.Post("/", h.Note.Create)
 	notes.Get("/", h.Note.List)
 	notes.Get("/:id", h.Note.GetByID)
 	notes.Put("/:id", h.Note.Update)
+	notes.Patch("/:id/append", h.Note.Append)
+	notes.Patch("/:id/prepend", h.Note.Prepend)
+	notes.Patch("/:id/metadata", h.Note.UpdateMetadata)
+	notes.Put("/:id/progress", h.Note.SetReadProgress)
 	notes.Delete("/:id", h.Note.Delete)
+	notes.Post("/:id/restore", h.Note.Restore)
 
 	// Note-Tag association routes
 	notes.Get("/:id/tags", h.Tag.GetNoteTags)
@@ -58,19 +121,109 @@ func Setup(app *fiber.App, h *handler.Handlers, jwtManager any) {
 	// Note-Link association routes
 	notes.Get("/:id/links", h.Link.GetOutgoingLinks)
 	notes.Get("/:id/backlinks", h.Link.GetBacklinks)
+	notes.Get("/:id/related", h.Note.GetRelated)
+	notes.Get("/:id/stats", h.Note.GetStats)
+	notes.Post("/:id/focus", h.Note.LogFocus)
+
+	// Note summary routes
+	notes.Post("/:id/summarize", h.Summary.Summarize)
+
+	// Note revision history routes
+	notes.Get("/:id/revisions", h.Note.GetRevisions)
+	notes.Post("/:id/revisions/:rev/restore", h.Note.RestoreRevision)
+
+	// Note attachments
+	notes.Post("/:id/attachments", h.Attachment.UploadAttachment)
+	notes.Get("/:id/attachments", h.Attachment.ListAttachments)
+	notes.Get("/:id/attachments/:attachment_id", h.Attachment.DownloadAttachment)
+	notes.Delete("/:id/attachments/:attachment_id", h.Attachment.DeleteAttachment)
+
+	// Public share links
+	notes.Post("/:id/share", h.PublicLink.PublishNote)
+	notes.Delete("/:id/share", h.PublicLink.UnpublishNote)
+
+	// Sharing with other users
+	notes.Get("/:id/shares", h.NoteShare.ListShares)
+	notes.Post("/:id/shares", h.NoteShare.ShareNote)
+	notes.Delete("/:id/shares/:user_id", h.NoteShare.UnshareNote)
+
+	// Link routes (authenticated)
+	links := v1.Group("/links")
+	links.Use(middleware.Auth(jwtManager), limiter)
+	links.Get("/unresolved", h.Link.GetUnresolvedLinks)
+
+	// Task routes (authenticated)
+	tasks := v1.Group("/tasks")
+	tasks.Use(middleware.Auth(jwtManager), limiter)
+	tasks.Get("/", h.Task.ListTasks)
+
+	// Flashcard review routes (authenticated)
+	review := v1.Group("/review")
+	review.Use(middleware.Auth(jwtManager), limiter)
+	review.Get("/due", h.Card.ListDueCards)
+	review.Post("/due/:id", h.Card.ReviewCard)
+
+	// Public links (authenticated)
+	v1.Get("/public-links", middleware.Auth(jwtManager), limiter, h.PublicLink.ListPublicLinks)
+
+	// Webhook routes (authenticated)
+	webhooks := v1.Group("/webhooks")
+	webhooks.Use(middleware.Auth(jwtManager), limiter)
+	webhooks.Get("/", h.Webhook.ListWebhooks)
+	webhooks.Post("/", h.Webhook.CreateWebhook)
+	webhooks.Put("/:id", h.Webhook.UpdateWebhook)
+	webhooks.Delete("/:id", h.Webhook.DeleteWebhook)
+	webhooks.Get("/:id/deliveries", h.Webhook.ListDeliveries)
+
+	// Web clipper (authenticated): saves the article of a web page as a note
+	v1.Post("/clip", middleware.Auth(jwtManager), limiter, h.Clip.Clip)
+
+	// Live update stream (authenticated, server-sent events)
+	v1.Get("/events", middleware.Auth(jwtManager), limiter, h.Event.Stream)
 
 	// Search routes (authenticated)
 	search := v1.Group("/search")
-	search.Use(middleware.Auth(jwtManager))
+	search.Use(middleware.Auth(jwtManager), limiter)
 	search.Get("/", h.Search.Search)
+	search.Get("/history", h.Search.History)
+	search.Post("/semantic", h.Search.SemanticSearch)
 
 	// Activity routes (authenticated)
 	activity := v1.Group("/activity")
-	activity.Use(middleware.Auth(jwtManager))
+	activity.Use(middleware.Auth(jwtManager), limiter)
+	activity.Get("/", h.Activity.ListActivity)
 	activity.Get("/recent", h.Activity.GetRecentActivity)
+	activity.Get("/heatmap", h.Activity.GetActivityHeatmap)
+
+	// Settings routes (authenticated)
+	settings := v1.Group("/settings")
+	settings.Use(middleware.Auth(jwtManager), limiter)
+	settings.Get("/", h.Settings.GetSettings)
+	settings.Put("/", h.Settings.UpdateSettings)
+	settings.Get("/daily-template", h.Settings.GetDailyTemplate)
+	settings.Put("/daily-template", h.Settings.UpdateDailyTemplate)
 
 	// Stats routes (authenticated)
 	stats := v1.Group("/stats")
-	stats.Use(middleware.Auth(jwtManager))
+	stats.Use(middleware.Auth(jwtManager), limiter)
 	stats.Get("/", h.Activity.GetUserStats)
+	stats.Get("/streak", h.Activity.GetWritingStreak)
+	stats.Get("/daily-words", h.Activity.GetDailyWords)
+
+	// Audit log routes (authenticated)
+	audit := v1.Group("/audit")
+	audit.Use(middleware.Auth(jwtManager), limiter)
+	audit.Get("/", h.Audit.ListAuditLog)
+
+	// Admin routes (authenticated, admins only)
+	admin := v1.Group("/admin")
+	admin.Use(middleware.Auth(jwtManager), limiter, middleware.Admin(adminService))
+	admin.Get("/users", h.Admin.ListUsers)
+	admin.Post("/users/:id/deactivate", h.Admin.DeactivateUser)
+	admin.Post("/users/:id/activate", h.Admin.ActivateUser)
+	admin.Get("/stats", h.Admin.GetStats)
+	admin.Get("/audit", h.Admin.ListAuditLog)
+	admin.Get("/backups", h.Backup.ListBackups)
+	admin.Post("/backups", h.Backup.CreateBackup)
+	admin.Post("/backups/:name/restore", h.Backup.RestoreBackup)
 }
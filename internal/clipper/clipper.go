@@ -0,0 +1,151 @@
+// Package clipper fetches web pages and extracts their main article as Markdown
+package clipper
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+
+	"github.com/momokii/go-cli-notes/internal/config"
+	"github.com/momokii/go-cli-notes/internal/util"
+)
+
+// maxRedirects is how many redirects a page may go through
+const maxRedirects = 5
+
+var (
+	// ErrBlockedAddress is returned for pages on loopback or private networks
+	ErrBlockedAddress = util.ErrBlockedAddress
+	// ErrUnsupportedPage is returned for responses that aren't HTML
+	ErrUnsupportedPage = errors.New("page is not HTML")
+	// ErrPageTooLarge is returned for pages above the configured size
+	ErrPageTooLarge = errors.New("page is too large")
+)
+
+// Article is the main content of a web page
+type Article struct {
+	URL      string // Final URL, after redirects
+	Title    string
+	Byline   string // Author, when the page names one
+	SiteName string
+	Content  string // Markdown
+}
+
+// Clipper fetches and extracts web pages
+type Clipper struct {
+	client  *http.Client
+	maxSize int64
+}
+
+// New creates a clipper with the configured limits
+// Unless private addresses are allowed, connections to them are refused after DNS
+// resolution, so a hostname can't be pointed at an internal service.
+func New(cfg config.ClipConfig) *Clipper {
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	if !cfg.AllowPrivate {
+		dialer.Control = util.BlockInternalAddresses
+	}
+
+	transport := &http.Transport{
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: cfg.Timeout,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       90 * time.Second,
+	}
+
+	return &Clipper{
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   cfg.Timeout,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= maxRedirects {
+					return fmt.Errorf("stopped after %d redirects", maxRedirects)
+				}
+				if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+					return fmt.Errorf("redirect to unsupported scheme %q", req.URL.Scheme)
+				}
+				return nil
+			},
+		},
+		maxSize: cfg.MaxSize,
+	}
+}
+
+// Clip fetches the page at rawURL and extracts its article
+func (c *Clipper) Clip(ctx context.Context, rawURL string) (*Article, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid URL %q: only http and https pages can be clipped", rawURL)
+	}
+
+	doc, final, err := c.fetch(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+
+	return extract(doc, final), nil
+}
+
+// fetch downloads and parses a page, returning it with its final URL
+func (c *Clipper) fetch(ctx context.Context, u *url.URL) (*html.Node, *url.URL, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; KnowledgeGarden-Clipper/1.0)")
+	req.Header.Set("Accept", "text/html,application/xhtml+xml;q=0.9,*/*;q=0.1")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("fetch page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, nil, fmt.Errorf("fetch page: server returned %s", resp.Status)
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil &&
+		mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return nil, nil, fmt.Errorf("%w (%s)", ErrUnsupportedPage, mediaType)
+	}
+	if c.maxSize > 0 && resp.ContentLength > c.maxSize {
+		return nil, nil, ErrPageTooLarge
+	}
+
+	body := io.Reader(resp.Body)
+	if c.maxSize > 0 {
+		body = io.LimitReader(resp.Body, c.maxSize+1)
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read page: %w", err)
+	}
+	if c.maxSize > 0 && int64(len(data)) > c.maxSize {
+		return nil, nil, ErrPageTooLarge
+	}
+
+	// Pages in legacy encodings are converted to UTF-8, by header or <meta charset>
+	r, err := charset.NewReader(bytes.NewReader(data), contentType)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decode page: %w", err)
+	}
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse page: %w", err)
+	}
+
+	return doc, resp.Request.URL, nil
+}
@@ -0,0 +1,238 @@
+package clipper
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	// unlikelyCandidate matches the class or id of page furniture around an article
+	unlikelyCandidate = regexp.MustCompile(`(?i)banner|breadcrumb|combx|comment|community|cookie|disqus|extra|foot|header|menu|modal|nav|newsletter|pager|pagination|popup|promo|related|remark|rss|share|shoutbox|sidebar|skyscraper|social|sponsor|subscribe|tags|tool|widget|\bad\b|ad-|ads\b`)
+	// maybeCandidate matches the class or id of elements that hold content despite the above
+	maybeCandidate = regexp.MustCompile(`(?i)and|article|body|column|content|main|post|story|text`)
+)
+
+// removedElements never hold article content
+var removedElements = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Noscript: true, atom.Iframe: true,
+	atom.Svg: true, atom.Form: true, atom.Button: true, atom.Input: true, atom.Select: true,
+	atom.Textarea: true, atom.Nav: true, atom.Aside: true, atom.Footer: true,
+	atom.Object: true, atom.Embed: true, atom.Canvas: true, atom.Template: true, atom.Dialog: true,
+}
+
+// extract finds the main content of a parsed page, readability-style
+// An <article> or <main> element is used when the page has one. Otherwise the
+// element whose paragraphs hold the most text, with few links, wins.
+func extract(doc *html.Node, base *url.URL) *Article {
+	article := &Article{URL: base.String()}
+	if href := findAttr(doc, atom.Base, "href"); href != "" {
+		if u, err := base.Parse(href); err == nil {
+			base = u
+		}
+	}
+
+	article.Title = metaContent(doc, "og:title")
+	if article.Title == "" {
+		if title := findFirst(doc, atom.Title); title != nil {
+			article.Title = cleanText(textContent(title))
+		}
+	}
+	article.Byline = metaContent(doc, "author")
+	article.SiteName = metaContent(doc, "og:site_name")
+
+	body := findFirst(doc, atom.Body)
+	if body == nil {
+		body = doc
+	}
+	prune(body)
+
+	root := findFirst(body, atom.Article)
+	if root == nil || len(cleanText(textContent(root))) < 200 {
+		root = findFirst(body, atom.Main)
+	}
+	if root == nil || len(cleanText(textContent(root))) < 200 {
+		root = bestCandidate(body)
+	}
+
+	article.Content = toMarkdown(root, base)
+
+	// The title usually opens the article as a heading too
+	if article.Title != "" {
+		if rest, ok := strings.CutPrefix(article.Content, "# "+article.Title); ok {
+			article.Content = strings.TrimSpace(rest)
+		}
+	}
+	return article
+}
+
+// prune removes scripts, navigation, hidden elements and page furniture
+func prune(n *html.Node) {
+	for child := n.FirstChild; child != nil; {
+		next := child.NextSibling
+		if child.Type == html.CommentNode || child.Type == html.ElementNode && isClutter(child) {
+			n.RemoveChild(child)
+		} else {
+			prune(child)
+		}
+		child = next
+	}
+}
+
+// isClutter reports whether an element is not part of the article
+func isClutter(n *html.Node) bool {
+	if removedElements[n.DataAtom] {
+		return true
+	}
+	if _, hidden := attrValue(n, "hidden"); hidden || getAttr(n, "aria-hidden") == "true" {
+		return true
+	}
+	if style := strings.ReplaceAll(getAttr(n, "style"), " ", ""); strings.Contains(style, "display:none") || strings.Contains(style, "visibility:hidden") {
+		return true
+	}
+	if n.DataAtom == atom.Body || n.DataAtom == atom.Article || n.DataAtom == atom.Main || n.DataAtom == atom.A {
+		return false
+	}
+
+	match := getAttr(n, "class") + " " + getAttr(n, "id") + " " + getAttr(n, "role")
+	return unlikelyCandidate.MatchString(match) && !maybeCandidate.MatchString(match)
+}
+
+// bestCandidate scores the parents of paragraphs by the text they hold
+// Each paragraph adds to its parent, and half as much to its grandparent.
+func bestCandidate(body *html.Node) *html.Node {
+	scores := make(map[*html.Node]float64)
+	var order []*html.Node
+
+	add := func(n *html.Node, score float64) {
+		if n == nil || n.Type != html.ElementNode {
+			return
+		}
+		if _, ok := scores[n]; !ok {
+			order = append(order, n)
+		}
+		scores[n] += score
+	}
+
+	walk(body, func(n *html.Node) {
+		if n.DataAtom != atom.P && n.DataAtom != atom.Pre && n.DataAtom != atom.Td {
+			return
+		}
+		text := cleanText(textContent(n))
+		if len(text) < 25 {
+			return
+		}
+		score := 1 + float64(strings.Count(text, ",")) + min(float64(len(text))/100, 3)
+		add(n.Parent, score)
+		if n.Parent != nil {
+			add(n.Parent.Parent, score/2)
+		}
+	})
+
+	best, bestScore := body, 0.0
+	for _, n := range order {
+		score := scores[n] * (1 - linkDensity(n))
+		if score > bestScore {
+			best, bestScore = n, score
+		}
+	}
+	return best
+}
+
+// linkDensity is the share of an element's text that is inside links
+func linkDensity(n *html.Node) float64 {
+	total := len(cleanText(textContent(n)))
+	if total == 0 {
+		return 0
+	}
+	links := 0
+	walk(n, func(child *html.Node) {
+		if child.DataAtom == atom.A {
+			links += len(cleanText(textContent(child)))
+		}
+	})
+	return float64(links) / float64(total)
+}
+
+// metaContent returns the content of a <meta> tag by property or name
+func metaContent(doc *html.Node, key string) string {
+	var content string
+	walk(doc, func(n *html.Node) {
+		if content != "" || n.DataAtom != atom.Meta {
+			return
+		}
+		if strings.EqualFold(getAttr(n, "property"), key) || strings.EqualFold(getAttr(n, "name"), key) {
+			content = cleanText(getAttr(n, "content"))
+		}
+	})
+	return content
+}
+
+// walk calls fn for every element below n, in document order
+func walk(n *html.Node, fn func(*html.Node)) {
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode {
+			fn(child)
+		}
+		walk(child, fn)
+	}
+}
+
+// findFirst returns the first element of a kind below n
+func findFirst(n *html.Node, a atom.Atom) *html.Node {
+	var found *html.Node
+	walk(n, func(child *html.Node) {
+		if found == nil && child.DataAtom == a {
+			found = child
+		}
+	})
+	return found
+}
+
+// findAttr returns an attribute of the first element of a kind below n
+func findAttr(n *html.Node, a atom.Atom, key string) string {
+	if el := findFirst(n, a); el != nil {
+		return getAttr(el, key)
+	}
+	return ""
+}
+
+// getAttr returns an attribute of an element, "" if it isn't set
+func getAttr(n *html.Node, key string) string {
+	value, _ := attrValue(n, key)
+	return value
+}
+
+// attrValue returns an attribute of an element and whether it is set
+func attrValue(n *html.Node, key string) (string, bool) {
+	for _, a := range n.Attr {
+		if a.Namespace == "" && a.Key == key {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
+
+// textContent returns all the text below n
+func textContent(n *html.Node) string {
+	var b strings.Builder
+	var collect func(*html.Node)
+	collect = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			collect(child)
+		}
+	}
+	collect(n)
+	return b.String()
+}
+
+// cleanText collapses whitespace and trims it
+func cleanText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
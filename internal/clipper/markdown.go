@@ -0,0 +1,313 @@
+package clipper
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// blankLines matches the gap between blocks, however wide
+var blankLines = regexp.MustCompile(`\n{3,}`)
+
+// doubleSpaces matches the runs of spaces left where inline elements meet
+var doubleSpaces = regexp.MustCompile(` {2,}`)
+
+// mdRenderer converts an HTML subtree to Markdown, resolving links against base
+type mdRenderer struct {
+	base *url.URL
+}
+
+// toMarkdown converts an element and everything below it to Markdown
+func toMarkdown(n *html.Node, base *url.URL) string {
+	r := &mdRenderer{base: base}
+	return tidy(r.children(n))
+}
+
+// children renders the children of n one after the other
+func (r *mdRenderer) children(n *html.Node) string {
+	var b strings.Builder
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		b.WriteString(r.node(child))
+	}
+	return b.String()
+}
+
+// node renders one node; block elements are surrounded by blank lines
+func (r *mdRenderer) node(n *html.Node) string {
+	switch n.Type {
+	case html.TextNode:
+		return collapseSpace(n.Data)
+	case html.ElementNode:
+	default:
+		return ""
+	}
+
+	switch n.DataAtom {
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		text := cleanText(r.children(n))
+		if text == "" {
+			return ""
+		}
+		level, _ := strconv.Atoi(n.Data[1:])
+		return block(strings.Repeat("#", level) + " " + text)
+	case atom.P, atom.Div, atom.Section, atom.Article, atom.Main, atom.Header, atom.Figure, atom.Dl:
+		return block(strings.TrimSpace(r.children(n)))
+	case atom.Figcaption, atom.Dt, atom.Dd:
+		return block(strings.TrimSpace(r.children(n)))
+	case atom.Br:
+		return "\n"
+	case atom.Hr:
+		return block("---")
+	case atom.Blockquote:
+		inner := strings.TrimSpace(tidy(r.children(n)))
+		if inner == "" {
+			return ""
+		}
+		lines := strings.Split(inner, "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimRight("> "+line, " ")
+		}
+		return block(strings.Join(lines, "\n"))
+	case atom.Pre:
+		code := strings.Trim(textContent(n), "\n")
+		if code == "" {
+			return ""
+		}
+		fence := "```"
+		for strings.Contains(code, fence) {
+			fence += "`"
+		}
+		return block(fence + codeLanguage(n) + "\n" + code + "\n" + fence)
+	case atom.Ul, atom.Ol:
+		return block(r.list(n))
+	case atom.Li:
+		// Outside a list
+		return block("- " + strings.TrimSpace(r.children(n)))
+	case atom.Table:
+		return block(r.table(n))
+	case atom.Strong, atom.B:
+		return wrapInline(r.children(n), "**")
+	case atom.Em, atom.I:
+		return wrapInline(r.children(n), "*")
+	case atom.S, atom.Del, atom.Strike:
+		return wrapInline(r.children(n), "~~")
+	case atom.Code, atom.Kbd, atom.Samp:
+		text := cleanText(textContent(n))
+		if text == "" {
+			return ""
+		}
+		tick := "`"
+		if strings.Contains(text, "`") {
+			tick = "``"
+		}
+		return tick + text + tick
+	case atom.A:
+		return r.link(n)
+	case atom.Img:
+		return r.image(n)
+	default:
+		return r.children(n)
+	}
+}
+
+// list renders a <ul> or <ol>, indenting nested lists under their item
+func (r *mdRenderer) list(n *html.Node) string {
+	var items []string
+	number := 1
+	if start, err := strconv.Atoi(getAttr(n, "start")); err == nil {
+		number = start
+	}
+
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type != html.ElementNode || child.DataAtom != atom.Li {
+			continue
+		}
+
+		marker := "- "
+		if n.DataAtom == atom.Ol {
+			marker = strconv.Itoa(number) + ". "
+			number++
+		}
+
+		// Items are kept tight: paragraphs within an item are only a line apart
+		inner := blankLines.ReplaceAllString(tidy(r.children(child)), "\n")
+		inner = strings.ReplaceAll(strings.TrimSpace(inner), "\n\n", "\n")
+		if inner == "" {
+			continue
+		}
+		lines := strings.Split(inner, "\n")
+		for i := 1; i < len(lines); i++ {
+			lines[i] = strings.Repeat(" ", len(marker)) + lines[i]
+		}
+		items = append(items, marker+strings.Join(lines, "\n"))
+	}
+	return strings.Join(items, "\n")
+}
+
+// table renders a table as a Markdown table, its first row as the header
+func (r *mdRenderer) table(n *html.Node) string {
+	var rows [][]string
+	walk(n, func(el *html.Node) {
+		if el.DataAtom != atom.Tr {
+			return
+		}
+		var cells []string
+		for cell := el.FirstChild; cell != nil; cell = cell.NextSibling {
+			if cell.DataAtom == atom.Td || cell.DataAtom == atom.Th {
+				cells = append(cells, strings.ReplaceAll(cleanText(r.children(cell)), "|", `\|`))
+			}
+		}
+		if len(cells) > 0 {
+			rows = append(rows, cells)
+		}
+	})
+	if len(rows) == 0 {
+		return ""
+	}
+
+	width := 0
+	for _, row := range rows {
+		width = max(width, len(row))
+	}
+
+	var b strings.Builder
+	for i, row := range rows {
+		for len(row) < width {
+			row = append(row, "")
+		}
+		b.WriteString("| " + strings.Join(row, " | ") + " |\n")
+		if i == 0 {
+			b.WriteString("|" + strings.Repeat(" --- |", width) + "\n")
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// link renders a link, dropping in-page and script links but keeping their text
+func (r *mdRenderer) link(n *html.Node) string {
+	text := r.children(n)
+	trimmed := cleanText(text)
+	href := strings.TrimSpace(getAttr(n, "href"))
+	if trimmed == "" {
+		return text
+	}
+	if href == "" || strings.HasPrefix(href, "#") || strings.HasPrefix(strings.ToLower(href), "javascript:") {
+		return text
+	}
+
+	target := r.resolve(href)
+	if target == "" {
+		return text
+	}
+
+	// Keep the spacing around the text outside the link
+	lead := text[:len(text)-len(strings.TrimLeft(text, " "))]
+	trail := text[len(strings.TrimRight(text, " ")):]
+	return lead + "[" + trimmed + "](" + target + ")" + trail
+}
+
+// image renders an image with an absolute URL, lazy-loaded ones included
+func (r *mdRenderer) image(n *html.Node) string {
+	src := getAttr(n, "src")
+	if src == "" || strings.HasPrefix(src, "data:") {
+		src = getAttr(n, "data-src")
+	}
+	target := r.resolve(src)
+	if target == "" {
+		return ""
+	}
+	alt := strings.NewReplacer("[", "", "]", "").Replace(cleanText(getAttr(n, "alt")))
+	return "![" + alt + "](" + target + ")"
+}
+
+// resolve makes a link absolute, returning "" for anything but http(s) URLs
+func (r *mdRenderer) resolve(ref string) string {
+	u, err := r.base.Parse(strings.TrimSpace(ref))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+	return strings.NewReplacer(" ", "%20", ")", "%29", "(", "%28").Replace(u.String())
+}
+
+// codeLanguage returns the language of a code block from its class, e.g. "language-go"
+func codeLanguage(pre *html.Node) string {
+	classes := getAttr(pre, "class")
+	if code := findFirst(pre, atom.Code); code != nil {
+		classes += " " + getAttr(code, "class")
+	}
+	for _, class := range strings.Fields(classes) {
+		for _, prefix := range []string{"language-", "lang-"} {
+			if lang, ok := strings.CutPrefix(class, prefix); ok && lang != "" {
+				return lang
+			}
+		}
+	}
+	return ""
+}
+
+// block surrounds a block of Markdown with blank lines
+func block(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "\n\n" + s + "\n\n"
+}
+
+// wrapInline wraps text in an emphasis marker, keeping surrounding spaces outside it
+func wrapInline(text, marker string) string {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return text
+	}
+	lead := text[:len(text)-len(strings.TrimLeft(text, " \n"))]
+	trail := text[len(strings.TrimRight(text, " \n")):]
+	return lead + marker + trimmed + marker + trail
+}
+
+// collapseSpace collapses runs of whitespace in text to single spaces, like browsers do
+func collapseSpace(s string) string {
+	collapsed := cleanText(s)
+	if collapsed == "" {
+		if s != "" {
+			return " "
+		}
+		return ""
+	}
+	if strings.TrimLeft(s, " \t\r\n") != s {
+		collapsed = " " + collapsed
+	}
+	if strings.TrimRight(s, " \t\r\n") != s {
+		collapsed += " "
+	}
+	return collapsed
+}
+
+// tidy trims the lines of rendered Markdown and collapses blank lines
+// Lines inside code fences are left as they are.
+func tidy(s string) string {
+	lines := strings.Split(s, "\n")
+	inFence := false
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			lines[i] = strings.TrimSpace(line)
+			continue
+		}
+		if inFence {
+			continue
+		}
+
+		line = strings.TrimRight(line, " \t")
+		// A single leading space is left over from inline whitespace, deeper ones are indentation
+		if strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "  ") {
+			line = line[1:]
+		}
+		text := strings.TrimLeft(line, " ")
+		lines[i] = line[:len(line)-len(text)] + doubleSpaces.ReplaceAllString(text, " ")
+	}
+	return strings.TrimSpace(blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
@@ -10,8 +10,17 @@ type Config struct {
 	Server    ServerConfig
 	Database  DatabaseConfig
 	JWT       JWTConfig
+	Auth      AuthConfig
 	RateLimit RateLimitConfig
+	CORS      CORSConfig
 	Log       LogConfig
+	Storage   StorageConfig
+	Backup    BackupConfig
+	Mail      MailConfig
+	Embedding EmbeddingConfig
+	LLM       LLMConfig
+	Clip      ClipConfig
+	Webhook   WebhookConfig
 	Env       string
 }
 
@@ -21,11 +30,21 @@ type ServerConfig struct {
 	Port         int           `env:"SERVER_PORT" envDefault:"8080"`
 	ReadTimeout  time.Duration `env:"SERVER_READ_TIMEOUT" envDefault:"30s"`
 	WriteTimeout time.Duration `env:"SERVER_WRITE_TIMEOUT" envDefault:"30s"`
+	ProxyHeader  string        `env:"SERVER_PROXY_HEADER"` // e.g. X-Forwarded-For when behind a reverse proxy
+	// Proxy IPs or CIDR ranges allowed to set ProxyHeader; empty = none, ProxyHeader is ignored
+	TrustedProxies []string `env:"SERVER_TRUSTED_PROXIES" envSeparator:","`
+	// Base of public note links, e.g. https://notes.example.com; empty = the address of the request
+	PublicURL string `env:"SERVER_PUBLIC_URL"`
+	// Serve Prometheus metrics at /metrics, without authentication
+	MetricsEnabled bool `env:"METRICS_ENABLED" envDefault:"false"`
 }
 
 // DatabaseConfig holds database configuration
 type DatabaseConfig struct {
-	DatabaseURL     string        `env:"DATABASE_URL"` // Full database URL (takes precedence)
+	Driver          string        `env:"DB_DRIVER" envDefault:"postgres"` // postgres or sqlite
+	SQLitePath      string        `env:"DB_SQLITE_PATH" envDefault:"./data/knowledge_garden.db"`
+	DatabaseURL     string        `env:"DATABASE_URL"`   // Full database URL (takes precedence)
+	ReplicaURL      string        `env:"DB_REPLICA_URL"` // Read replica URL for list, search, graph and stats queries
 	Host            string        `env:"DB_HOST" envDefault:"localhost"`
 	Port            int           `env:"DB_PORT" envDefault:"5432"`
 	User            string        `env:"DB_USER" envDefault:"kg_user"`
@@ -35,6 +54,11 @@ type DatabaseConfig struct {
 	MaxOpenConns    int           `env:"DB_MAX_OPEN_CONNS" envDefault:"25"`
 	MaxIdleConns    int           `env:"DB_MAX_IDLE_CONNS" envDefault:"5"`
 	ConnMaxLifetime time.Duration `env:"DB_CONN_MAX_LIFETIME" envDefault:"5m"`
+	AcquireTimeout  time.Duration `env:"DB_ACQUIRE_TIMEOUT" envDefault:"5s"` // Max wait for a free connection, 0 = no limit
+	// Queries slower than this are logged as warnings, 0 = off
+	SlowQueryThreshold time.Duration `env:"DB_SLOW_QUERY_THRESHOLD" envDefault:"200ms"`
+	// Apply pending migrations when the API starts
+	AutoMigrate bool `env:"DB_AUTO_MIGRATE" envDefault:"false"`
 }
 
 // DSN returns the PostgreSQL data source name
@@ -56,6 +80,15 @@ type JWTConfig struct {
 	RefreshExpiration time.Duration `env:"JWT_REFRESH_EXPIRATION" envDefault:"720h"` // 30 days
 }
 
+// AuthConfig holds account verification and recovery configuration
+type AuthConfig struct {
+	RequireEmailVerification bool          `env:"AUTH_REQUIRE_EMAIL_VERIFICATION" envDefault:"false"` // Block login until the email is verified
+	VerificationExpiration   time.Duration `env:"EMAIL_VERIFICATION_EXPIRATION" envDefault:"24h"`
+	ResetExpiration          time.Duration `env:"PASSWORD_RESET_EXPIRATION" envDefault:"1h"`
+	DeviceCodeExpiration     time.Duration `env:"DEVICE_CODE_EXPIRATION" envDefault:"10m"` // How long a device login code can be approved
+	AdminEmails              []string      `env:"ADMIN_EMAILS" envSeparator:","`           // Promoted to admin on startup
+}
+
 // RateLimitConfig holds rate limiting configuration
 type RateLimitConfig struct {
 	Enabled  bool          `env:"RATE_LIMIT_ENABLED" envDefault:"true"`
@@ -63,12 +96,119 @@ type RateLimitConfig struct {
 	Window   time.Duration `env:"RATE_LIMIT_WINDOW" envDefault:"1m"`
 }
 
+// CORSConfig holds cross-origin request configuration, for browser clients
+type CORSConfig struct {
+	AllowedOrigins   []string      `env:"CORS_ALLOWED_ORIGINS" envSeparator:"," envDefault:"*"` // * = any origin
+	AllowedHeaders   []string      `env:"CORS_ALLOWED_HEADERS" envSeparator:"," envDefault:"Origin,Content-Type,Accept,Authorization,If-None-Match,If-Match"`
+	AllowCredentials bool          `env:"CORS_ALLOW_CREDENTIALS" envDefault:"false"` // Requires explicit origins
+	MaxAge           time.Duration `env:"CORS_MAX_AGE" envDefault:"10m"`             // How long browsers may cache a preflight
+}
+
+// AllowsAnyOrigin reports whether every origin is allowed
+func (c *CORSConfig) AllowsAnyOrigin() bool {
+	for _, origin := range c.AllowedOrigins {
+		if origin == "*" {
+			return true
+		}
+	}
+	return false
+}
+
 // LogConfig holds logging configuration
 type LogConfig struct {
 	Level  string `env:"LOG_LEVEL" envDefault:"info"`
 	Format string `env:"LOG_FORMAT" envDefault:"json"`
 }
 
+// StorageConfig holds attachment storage configuration
+type StorageConfig struct {
+	Driver        string `env:"STORAGE_DRIVER" envDefault:"local"` // local or s3
+	LocalDir      string `env:"STORAGE_LOCAL_DIR" envDefault:"./data/attachments"`
+	MaxUploadSize int64  `env:"STORAGE_MAX_UPLOAD_SIZE" envDefault:"26214400"` // 25 MB
+	S3Endpoint    string `env:"S3_ENDPOINT"`                                   // Empty = AWS; set for MinIO, R2, etc.
+	S3Region      string `env:"S3_REGION" envDefault:"us-east-1"`
+	S3Bucket      string `env:"S3_BUCKET"`
+	S3AccessKey   string `env:"S3_ACCESS_KEY_ID"`
+	S3SecretKey   string `env:"S3_SECRET_ACCESS_KEY"`
+	S3PathStyle   bool   `env:"S3_PATH_STYLE" envDefault:"false"` // Required by most S3-compatible servers
+}
+
+// BackupConfig holds the schedule and destination of database backups
+// The s3 driver uses the S3_* credentials of StorageConfig.
+type BackupConfig struct {
+	Schedule string `env:"BACKUP_SCHEDULE"`                  // Time of day (HH:MM, UTC) of the nightly backup, empty = no scheduled backups
+	Driver   string `env:"BACKUP_DRIVER" envDefault:"local"` // local or s3
+	LocalDir string `env:"BACKUP_LOCAL_DIR" envDefault:"./data/backups"`
+	S3Bucket string `env:"BACKUP_S3_BUCKET"`           // Empty = S3_BUCKET
+	Keep     int    `env:"BACKUP_KEEP" envDefault:"7"` // Newest backups kept after a scheduled backup, 0 = keep all
+}
+
+// ScheduleTime returns the hour and minute of BACKUP_SCHEDULE
+func (c *BackupConfig) ScheduleTime() (int, int, error) {
+	t, err := time.Parse("15:04", c.Schedule)
+	if err != nil {
+		return 0, 0, fmt.Errorf("BACKUP_SCHEDULE must be a time of day like 03:00, got %q", c.Schedule)
+	}
+	return t.Hour(), t.Minute(), nil
+}
+
+// Store returns the storage configuration of the backup destination
+func (c *BackupConfig) Store(storage StorageConfig) StorageConfig {
+	storage.Driver = c.Driver
+	storage.LocalDir = c.LocalDir
+	if c.S3Bucket != "" {
+		storage.S3Bucket = c.S3Bucket
+	}
+	return storage
+}
+
+// MailConfig holds outgoing email configuration (verification and password reset emails)
+type MailConfig struct {
+	Driver       string `env:"MAIL_DRIVER" envDefault:"log"` // log or smtp
+	From         string `env:"MAIL_FROM" envDefault:"Knowledge Garden <noreply@localhost>"`
+	SMTPHost     string `env:"SMTP_HOST"`
+	SMTPPort     int    `env:"SMTP_PORT" envDefault:"587"`
+	SMTPUsername string `env:"SMTP_USERNAME"`
+	SMTPPassword string `env:"SMTP_PASSWORD"`
+}
+
+// EmbeddingConfig holds the embeddings provider used for semantic search
+type EmbeddingConfig struct {
+	Provider      string        `env:"EMBEDDING_PROVIDER"` // Empty = semantic search disabled, openai or ollama
+	Model         string        `env:"EMBEDDING_MODEL"`    // Empty = the provider's default model
+	OpenAIAPIKey  string        `env:"OPENAI_API_KEY"`
+	OpenAIBaseURL string        `env:"OPENAI_BASE_URL" envDefault:"https://api.openai.com/v1"` // Any OpenAI-compatible API
+	OllamaURL     string        `env:"OLLAMA_URL" envDefault:"http://localhost:11434"`
+	IndexInterval time.Duration `env:"EMBEDDING_INDEX_INTERVAL" envDefault:"30s"` // How often new and changed notes are embedded
+	BatchSize     int           `env:"EMBEDDING_BATCH_SIZE" envDefault:"32"`
+}
+
+// LLMConfig holds the chat model provider used to summarize notes
+// The provider credentials are shared with EmbeddingConfig.
+type LLMConfig struct {
+	Provider      string `env:"LLM_PROVIDER"` // Empty = summarization disabled, openai or ollama
+	Model         string `env:"LLM_MODEL"`    // Empty = the provider's default model
+	OpenAIAPIKey  string `env:"OPENAI_API_KEY"`
+	OpenAIBaseURL string `env:"OPENAI_BASE_URL" envDefault:"https://api.openai.com/v1"`
+	OllamaURL     string `env:"OLLAMA_URL" envDefault:"http://localhost:11434"`
+}
+
+// ClipConfig holds the limits of the web clipper, which fetches pages server-side
+type ClipConfig struct {
+	Timeout time.Duration `env:"CLIP_TIMEOUT" envDefault:"15s"`
+	MaxSize int64         `env:"CLIP_MAX_SIZE" envDefault:"5242880"` // Largest page fetched, in bytes
+	// Allow clipping pages on loopback and private networks, which would expose internal services
+	AllowPrivate bool `env:"CLIP_ALLOW_PRIVATE" envDefault:"false"`
+}
+
+// WebhookConfig holds how webhook deliveries are sent and retried
+type WebhookConfig struct {
+	Timeout     time.Duration `env:"WEBHOOK_TIMEOUT" envDefault:"10s"`
+	MaxAttempts int           `env:"WEBHOOK_MAX_ATTEMPTS" envDefault:"8"` // A delivery fails after this many attempts
+	// Allow webhooks to loopback and private networks, which would expose internal services
+	AllowPrivate bool `env:"WEBHOOK_ALLOW_PRIVATE" envDefault:"false"`
+}
+
 // Address returns the server address
 func (c *ServerConfig) Address() string {
 	return fmt.Sprintf("%s:%d", c.Host, c.Port)
@@ -13,6 +13,32 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("parse config: %w", err)
 	}
 
+	switch cfg.Database.Driver {
+	case "postgres":
+	case "sqlite":
+		// The database is a single file, there is no replica to read from
+		if cfg.Database.ReplicaURL != "" {
+			return nil, fmt.Errorf("DB_REPLICA_URL is not supported with DB_DRIVER=sqlite")
+		}
+	default:
+		return nil, fmt.Errorf("DB_DRIVER must be postgres or sqlite, got %q", cfg.Database.Driver)
+	}
+
+	// Browsers reject credentialed responses that allow any origin
+	if cfg.CORS.AllowCredentials && cfg.CORS.AllowsAnyOrigin() {
+		return nil, fmt.Errorf("CORS_ALLOW_CREDENTIALS requires CORS_ALLOWED_ORIGINS to list the allowed origins")
+	}
+
+	if cfg.Backup.Schedule != "" {
+		if _, _, err := cfg.Backup.ScheduleTime(); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.Webhook.MaxAttempts < 1 {
+		return nil, fmt.Errorf("WEBHOOK_MAX_ATTEMPTS must be at least 1")
+	}
+
 	// Set default environment if not specified
 	if cfg.Env == "" {
 		cfg.Env = "development"
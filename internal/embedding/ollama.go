@@ -0,0 +1,67 @@
+package embedding
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultOllamaModel is used when no model is configured
+const defaultOllamaModel = "nomic-embed-text"
+
+// OllamaOptions configures an OllamaProvider
+type OllamaOptions struct {
+	URL   string // e.g. http://localhost:11434
+	Model string
+}
+
+// OllamaProvider computes embeddings with a local Ollama server
+// Note text never leaves the machine running Ollama.
+type OllamaProvider struct {
+	opts       OllamaOptions
+	httpClient *http.Client
+}
+
+// NewOllamaProvider creates an Ollama embeddings provider
+func NewOllamaProvider(opts OllamaOptions) (*OllamaProvider, error) {
+	if opts.URL == "" {
+		opts.URL = "http://localhost:11434"
+	}
+	if opts.Model == "" {
+		opts.Model = defaultOllamaModel
+	}
+	opts.URL = strings.TrimRight(opts.URL, "/")
+
+	return &OllamaProvider{
+		opts: opts,
+		// Local models can be slow to load on the first request
+		httpClient: &http.Client{Timeout: 5 * time.Minute},
+	}, nil
+}
+
+// Model returns the embedding model name
+func (p *OllamaProvider) Model() string {
+	return p.opts.Model
+}
+
+// Embed computes the embeddings of texts in one request
+func (p *OllamaProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	var resp struct {
+		Embeddings [][]float32 `json:"embeddings"`
+	}
+
+	err := postJSON(ctx, p.httpClient, p.opts.URL+"/api/embed", nil,
+		map[string]any{"model": p.opts.Model, "input": texts},
+		&resp)
+	if err != nil {
+		return nil, fmt.Errorf("ollama embeddings: %w", err)
+	}
+
+	if len(resp.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("ollama embeddings: got %d embeddings for %d texts", len(resp.Embeddings), len(texts))
+	}
+
+	return resp.Embeddings, nil
+}
@@ -0,0 +1,84 @@
+package embedding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultOpenAIModel is used when no model is configured
+const defaultOpenAIModel = "text-embedding-3-small"
+
+// OpenAIOptions configures an OpenAIProvider
+type OpenAIOptions struct {
+	BaseURL string // e.g. https://api.openai.com/v1, or any OpenAI-compatible API
+	APIKey  string
+	Model   string
+}
+
+// OpenAIProvider computes embeddings with the OpenAI embeddings API
+type OpenAIProvider struct {
+	opts       OpenAIOptions
+	httpClient *http.Client
+}
+
+// NewOpenAIProvider creates an OpenAI embeddings provider
+func NewOpenAIProvider(opts OpenAIOptions) (*OpenAIProvider, error) {
+	if opts.APIKey == "" {
+		return nil, errors.New("OpenAI API key is required")
+	}
+	if opts.BaseURL == "" {
+		opts.BaseURL = "https://api.openai.com/v1"
+	}
+	if opts.Model == "" {
+		opts.Model = defaultOpenAIModel
+	}
+	opts.BaseURL = strings.TrimRight(opts.BaseURL, "/")
+
+	return &OpenAIProvider{
+		opts:       opts,
+		httpClient: &http.Client{Timeout: time.Minute},
+	}, nil
+}
+
+// Model returns the embedding model name
+func (p *OpenAIProvider) Model() string {
+	return p.opts.Model
+}
+
+// Embed computes the embeddings of texts in one request
+func (p *OpenAIProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	var resp struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+	}
+
+	err := postJSON(ctx, p.httpClient, p.opts.BaseURL+"/embeddings",
+		map[string]string{"Authorization": "Bearer " + p.opts.APIKey},
+		map[string]any{"model": p.opts.Model, "input": texts},
+		&resp)
+	if err != nil {
+		return nil, fmt.Errorf("openai embeddings: %w", err)
+	}
+
+	// Results carry their input index; don't rely on their order
+	vectors := make([][]float32, len(texts))
+	for _, d := range resp.Data {
+		if d.Index < 0 || d.Index >= len(texts) {
+			return nil, fmt.Errorf("openai embeddings: unexpected index %d", d.Index)
+		}
+		vectors[d.Index] = d.Embedding
+	}
+	for i, v := range vectors {
+		if v == nil {
+			return nil, fmt.Errorf("openai embeddings: missing embedding %d", i)
+		}
+	}
+
+	return vectors, nil
+}
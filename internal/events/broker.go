@@ -0,0 +1,117 @@
+// Package events fans out change notifications to a user's connected clients
+package events
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+// subscriberBuffer is how many events a slow subscriber can fall behind before events are dropped
+const subscriberBuffer = 16
+
+// Broker is an in-memory publish/subscribe hub keyed by user
+// Events are only delivered to clients connected to the same API instance.
+type Broker struct {
+	mu          sync.RWMutex
+	subscribers map[uuid.UUID]map[chan model.Event]struct{}
+	listeners   []func(uuid.UUID, model.Event)
+	closed      bool
+}
+
+// NewBroker creates a new event broker
+func NewBroker() *Broker {
+	return &Broker{
+		subscribers: make(map[uuid.UUID]map[chan model.Event]struct{}),
+	}
+}
+
+// Subscribe registers a subscriber for a user's events
+// The returned function unsubscribes and must be called when the subscriber goes away.
+// The channel is closed on unsubscribe or when the broker is closed.
+func (b *Broker) Subscribe(userID uuid.UUID) (<-chan model.Event, func()) {
+	ch := make(chan model.Event, subscriberBuffer)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		close(ch)
+		return ch, func() {}
+	}
+
+	if b.subscribers[userID] == nil {
+		b.subscribers[userID] = make(map[chan model.Event]struct{})
+	}
+	b.subscribers[userID][ch] = struct{}{}
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+
+			subs := b.subscribers[userID]
+			if _, ok := subs[ch]; !ok {
+				return // Already closed by Close
+			}
+			delete(subs, ch)
+			if len(subs) == 0 {
+				delete(b.subscribers, userID)
+			}
+			close(ch)
+		})
+	}
+}
+
+// Listen registers a function called with every published event, of every user
+// It is called on the publisher's goroutine, so it must not block.
+func (b *Broker) Listen(fn func(userID uuid.UUID, event model.Event)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.listeners = append(b.listeners, fn)
+}
+
+// Publish sends an event to all of a user's subscribers and to the listeners
+// It never blocks: subscribers whose buffer is full miss the event.
+// Publishing on a nil broker is a no-op, so services work without live updates.
+func (b *Broker) Publish(userID uuid.UUID, event model.Event) {
+	if b == nil {
+		return
+	}
+
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for ch := range b.subscribers[userID] {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+	for _, fn := range b.listeners {
+		fn(userID, event)
+	}
+}
+
+// Close closes every subscriber channel so open streams can finish
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for userID, subs := range b.subscribers {
+		for ch := range subs {
+			close(ch)
+		}
+		delete(b.subscribers, userID)
+	}
+}
@@ -0,0 +1,109 @@
+package importer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// enexTimeLayout is the format of dates in ENEX files
+const enexTimeLayout = "20060102T150405Z"
+
+// ENEXImporter reads Evernote exports (.enex)
+// An ENEX file holds the notes of one notebook and is named after it, so the
+// file name becomes a tag on every note, next to the note's own tags.
+type ENEXImporter struct{}
+
+// enexNote is a <note> element of an ENEX file
+type enexNote struct {
+	Title   string   `xml:"title"`
+	Content string   `xml:"content"` // ENML, an XHTML dialect
+	Created string   `xml:"created"`
+	Tags    []string `xml:"tag"`
+}
+
+// Read returns the notes of an .enex file, or of every .enex file in a directory
+func (ENEXImporter) Read(path string) ([]*Note, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		files, err = filepath.Glob(filepath.Join(path, "*.enex"))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(files)
+	}
+
+	var notes []*Note
+	for _, file := range files {
+		fileNotes, err := readENEXFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(file), err)
+		}
+		notes = append(notes, fileNotes...)
+	}
+	return notes, nil
+}
+
+// readENEXFile decodes the notes of one ENEX file, one at a time
+func readENEXFile(path string) ([]*Note, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	notebook := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+
+	var notes []*Note
+	dec := xml.NewDecoder(f)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse: %w", err)
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "note" {
+			continue
+		}
+
+		var raw enexNote
+		if err := dec.DecodeElement(&raw, &start); err != nil {
+			return nil, fmt.Errorf("parse note %d: %w", len(notes)+1, err)
+		}
+
+		content, err := enmlToMarkdown(raw.Content)
+		if err != nil {
+			return nil, fmt.Errorf("note %q: %w", raw.Title, err)
+		}
+
+		note := &Note{
+			Title:   noteTitle(raw.Title),
+			Content: content,
+			Tags:    addTag(nil, notebook),
+			Source:  path,
+		}
+		for _, tag := range raw.Tags {
+			note.Tags = addTag(note.Tags, tag)
+		}
+		if created, err := time.Parse(enexTimeLayout, strings.TrimSpace(raw.Created)); err == nil {
+			note.Created = created
+		}
+		notes = append(notes, note)
+	}
+
+	return notes, nil
+}
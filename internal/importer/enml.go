@@ -0,0 +1,308 @@
+package importer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// enmlList is an open <ul> or <ol>
+type enmlList struct {
+	ordered bool
+	todo    bool // Evernote checklist (--en-todo style)
+	items   int
+}
+
+// enmlLink is an open <a>
+type enmlLink struct {
+	href  string
+	start int // Length of the output before the link text
+}
+
+// enmlConverter turns ENML into Markdown as the elements stream by
+type enmlConverter struct {
+	out    strings.Builder
+	lists  []enmlList
+	links  []enmlLink
+	pre    int // Depth of open <pre> elements
+	cells  int // Cells written in the current table row
+	rows   int // Rows written in the current table
+	inCell bool
+}
+
+// enmlToMarkdown converts the content of an Evernote note to Markdown
+// Attachments (<en-media>) and encrypted text are replaced by a placeholder.
+func enmlToMarkdown(enml string) (string, error) {
+	if strings.TrimSpace(enml) == "" {
+		return "", nil
+	}
+
+	dec := xml.NewDecoder(strings.NewReader(enml))
+	dec.Strict = false
+	dec.AutoClose = xml.HTMLAutoClose
+	dec.Entity = xml.HTMLEntity
+
+	c := &enmlConverter{}
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("parse content: %w", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			c.start(t)
+		case xml.EndElement:
+			c.end(t.Name.Local)
+		case xml.CharData:
+			c.text(string(t))
+		}
+	}
+
+	return tidyMarkdown(c.out.String()), nil
+}
+
+func (c *enmlConverter) start(el xml.StartElement) {
+	switch name := strings.ToLower(el.Name.Local); name {
+	case "div", "tr":
+		c.lineBreak()
+		if name == "tr" {
+			c.cells = 0
+		}
+	case "p", "blockquote", "table":
+		c.paragraph()
+		if name == "blockquote" {
+			c.write("> ")
+		}
+		if name == "table" {
+			c.rows = 0
+		}
+	case "br":
+		// Evernote writes empty lines as <div><br/></div>
+		if strings.HasSuffix(c.out.String(), "\n") && !c.inCell {
+			c.write("\n")
+		}
+		c.lineBreak()
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		c.paragraph()
+		c.write(strings.Repeat("#", int(name[1]-'0')) + " ")
+	case "hr":
+		c.paragraph()
+		c.write("---")
+		c.paragraph()
+	case "ul", "ol":
+		if len(c.lists) == 0 {
+			c.paragraph()
+		}
+		c.lists = append(c.lists, enmlList{
+			ordered: name == "ol",
+			todo:    strings.Contains(attr(el, "style"), "--en-todo:true"),
+		})
+	case "li":
+		c.lineBreak()
+		if len(c.lists) == 0 {
+			c.write("- ")
+			break
+		}
+		list := &c.lists[len(c.lists)-1]
+		list.items++
+		c.write(strings.Repeat("  ", len(c.lists)-1))
+		switch {
+		case list.todo && strings.Contains(attr(el, "style"), "--en-checked:true"):
+			c.write("- [x] ")
+		case list.todo:
+			c.write("- [ ] ")
+		case list.ordered:
+			c.write(strconv.Itoa(list.items) + ". ")
+		default:
+			c.write("- ")
+		}
+	case "en-todo":
+		if attr(el, "checked") == "true" {
+			c.write("- [x] ")
+		} else {
+			c.write("- [ ] ")
+		}
+	case "td", "th":
+		if c.cells == 0 {
+			c.write("|")
+		}
+		c.write(" ")
+		c.inCell = true
+	case "pre":
+		c.paragraph()
+		c.write("```\n")
+		c.pre++
+	case "code":
+		if c.pre == 0 {
+			c.write("`")
+		}
+	case "b", "strong":
+		c.write("**")
+	case "i", "em":
+		c.write("*")
+	case "s", "strike", "del":
+		c.write("~~")
+	case "a":
+		c.links = append(c.links, enmlLink{href: attr(el, "href"), start: c.out.Len()})
+	case "en-media":
+		c.write("*[attachment: " + attr(el, "type") + "]*")
+	case "en-crypt":
+		c.write("*[encrypted text]*")
+	}
+}
+
+func (c *enmlConverter) end(name string) {
+	switch name = strings.ToLower(name); name {
+	case "div":
+		c.lineBreak()
+	case "p", "blockquote", "table":
+		c.paragraph()
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		c.paragraph()
+	case "ul", "ol":
+		if len(c.lists) > 0 {
+			c.lists = c.lists[:len(c.lists)-1]
+		}
+		if len(c.lists) == 0 {
+			c.paragraph()
+		}
+	case "td", "th":
+		if !strings.HasSuffix(c.out.String(), " ") {
+			c.write(" ")
+		}
+		c.write("|")
+		c.cells++
+		c.inCell = false
+	case "tr":
+		if c.rows == 0 && c.cells > 0 {
+			c.write("\n|" + strings.Repeat(" --- |", c.cells))
+		}
+		c.rows++
+		c.lineBreak()
+	case "pre":
+		if c.pre > 0 {
+			c.pre--
+			c.lineBreak()
+			c.write("```")
+			c.paragraph()
+		}
+	case "code":
+		if c.pre == 0 {
+			c.write("`")
+		}
+	case "b", "strong":
+		c.write("**")
+	case "i", "em":
+		c.write("*")
+	case "s", "strike", "del":
+		c.write("~~")
+	case "a":
+		if len(c.links) == 0 {
+			return
+		}
+		link := c.links[len(c.links)-1]
+		c.links = c.links[:len(c.links)-1]
+		c.closeLink(link)
+	}
+}
+
+// closeLink turns the text written since the link opened into a Markdown link
+// Links to other Evernote notes become wiki links, by the title in their text.
+func (c *enmlConverter) closeLink(link enmlLink) {
+	s := c.out.String()
+	before, text := s[:link.start], strings.TrimSpace(s[link.start:])
+
+	c.out.Reset()
+	c.out.WriteString(before)
+	switch {
+	case text == "":
+	case strings.HasPrefix(link.href, "evernote:"):
+		c.write("[[" + strings.NewReplacer("[", "", "]", "", "|", "").Replace(text) + "]]")
+	case link.href == "" || link.href == text:
+		c.write(text)
+	default:
+		c.write("[" + text + "](" + link.href + ")")
+	}
+}
+
+func (c *enmlConverter) text(s string) {
+	if c.pre > 0 {
+		c.write(s)
+		return
+	}
+
+	// Whitespace collapses like in HTML, and never starts a line
+	collapsed := strings.Join(strings.Fields(s), " ")
+	if s != "" && strings.TrimSpace(s) == "" {
+		collapsed = " "
+	} else {
+		if strings.TrimLeftFunc(s, unicode.IsSpace) != s {
+			collapsed = " " + collapsed
+		}
+		if strings.TrimRightFunc(s, unicode.IsSpace) != s {
+			collapsed += " "
+		}
+	}
+	if c.atLineStart() || strings.HasSuffix(c.out.String(), " ") {
+		collapsed = strings.TrimLeft(collapsed, " ")
+	}
+	c.write(collapsed)
+}
+
+func (c *enmlConverter) write(s string) {
+	c.out.WriteString(s)
+}
+
+// atLineStart reports whether nothing but list markers was written on the current line
+func (c *enmlConverter) atLineStart() bool {
+	s := c.out.String()
+	line := s[strings.LastIndex(s, "\n")+1:]
+	line = strings.TrimLeft(line, " ")
+	return line == "" || line == "- " || line == "- [ ] " || line == "- [x] " || line == "> " ||
+		strings.HasSuffix(line, ". ") && strings.Trim(line, "0123456789. ") == "" ||
+		strings.HasSuffix(line, "# ") && strings.Trim(line, "# ") == ""
+}
+
+// lineBreak ends the current line, unless it is empty
+// Inside a table cell, where Markdown can't break lines, it writes a space.
+func (c *enmlConverter) lineBreak() {
+	if c.inCell {
+		if !strings.HasSuffix(c.out.String(), " ") {
+			c.write(" ")
+		}
+		return
+	}
+	s := c.out.String()
+	if s != "" && !strings.HasSuffix(s, "\n") {
+		c.write("\n")
+	}
+}
+
+// paragraph ends the current block with a blank line
+func (c *enmlConverter) paragraph() {
+	c.lineBreak()
+	if c.inCell {
+		return
+	}
+	s := c.out.String()
+	if s != "" && !strings.HasSuffix(s, "\n\n") {
+		c.write("\n")
+	}
+}
+
+// attr returns the value of an element's attribute, "" if it isn't set
+func attr(el xml.StartElement, name string) string {
+	for _, a := range el.Attr {
+		if strings.EqualFold(a.Name.Local, name) {
+			return a.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,99 @@
+// Package importer reads notes from the exports of other note-taking apps
+package importer
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Export formats that can be imported
+const (
+	FormatENEX   = "enex"   // Evernote .enex files
+	FormatNotion = "notion" // Notion "Markdown & CSV" export zip
+)
+
+// Formats lists the supported export formats
+var Formats = []string{FormatENEX, FormatNotion}
+
+// Note is a note read from an export, its content converted to Markdown
+type Note struct {
+	Title     string
+	Content   string
+	Type      string         // Note type, empty for the user's default
+	Encrypted bool           // Content is client-side ciphertext
+	Tags      []string       // Includes the notebook the note was in
+	Created   time.Time      // Zero when the export doesn't record it
+	Metadata  map[string]any // Other frontmatter keys, for Markdown files
+	Source    string         // File the note was read from, for messages
+}
+
+// Importer reads the notes of one export format
+type Importer interface {
+	// Read returns the notes of the export at path, a file or a directory
+	Read(path string) ([]*Note, error)
+}
+
+// New returns the importer for an export format
+func New(format string) (Importer, error) {
+	switch strings.ToLower(format) {
+	case FormatENEX:
+		return ENEXImporter{}, nil
+	case FormatNotion:
+		return NotionImporter{}, nil
+	default:
+		return nil, fmt.Errorf("unknown import format %q (supported: %s)", format, strings.Join(Formats, ", "))
+	}
+}
+
+// addTag appends a tag unless the note already has it (case-insensitively)
+func addTag(tags []string, name string) []string {
+	name = truncate(strings.TrimSpace(name), 100)
+	if name == "" {
+		return tags
+	}
+	for _, tag := range tags {
+		if strings.EqualFold(tag, name) {
+			return tags
+		}
+	}
+	return append(tags, name)
+}
+
+// noteTitle cleans up a title, falling back to "Untitled"
+// Titles are limited to 500 characters by the API.
+func noteTitle(title string) string {
+	title = truncate(strings.Join(strings.Fields(title), " "), 500)
+	if title == "" {
+		return "Untitled"
+	}
+	return title
+}
+
+// truncate shortens s to at most n characters
+func truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return strings.TrimSpace(string(r[:n]))
+	}
+	return s
+}
+
+// tidyMarkdown trims trailing spaces and collapses runs of blank lines
+func tidyMarkdown(s string) string {
+	lines := strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	out := make([]string, 0, len(lines))
+	blank := false
+	for _, line := range lines {
+		line = strings.TrimRight(line, " \t")
+		if line == "" {
+			if blank {
+				continue
+			}
+			blank = true
+		} else {
+			blank = false
+		}
+		out = append(out, line)
+	}
+	return strings.TrimSpace(strings.Join(out, "\n"))
+}
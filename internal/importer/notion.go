@@ -0,0 +1,270 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// NotionImporter reads Notion "Markdown & CSV" exports, as a zip or an unzipped directory
+// Pages nested under a top-level page (Notion's closest thing to a notebook) are
+// tagged with its title. Database properties named Tags and Created are kept as
+// tags and the creation date, and links between pages become wiki links.
+type NotionImporter struct{}
+
+var (
+	// notionIDSuffix is the page ID Notion appends to file and directory names
+	notionIDSuffix = regexp.MustCompile(`\s+[0-9a-f]{32}$`)
+	// notionPageLink is a Markdown link to another page of the export
+	notionPageLink = regexp.MustCompile(`\[([^\]]*)\]\(([^)\s]+\.md)\)`)
+	// notionProperty is a "Name: value" line of a database page's property block
+	notionProperty = regexp.MustCompile(`^([A-Za-z][\w ]{0,40}):\s*(.*)$`)
+)
+
+// notionDateLayouts are the formats Notion writes dates in, depending on the workspace settings
+var notionDateLayouts = []string{
+	"January 2, 2006 3:04 PM",
+	"January 2, 2006 15:04",
+	"January 2, 2006",
+	"2006/01/02 3:04 PM",
+	"2006/01/02 15:04",
+	"2006/01/02",
+	"02/01/2006 15:04",
+	"01/02/2006 3:04 PM",
+	"2006-01-02T15:04:05Z07:00",
+	"2006-01-02",
+}
+
+// notionPage is a Markdown file of the export
+type notionPage struct {
+	path string // Slash-separated path inside the export
+	data []byte
+}
+
+// Read returns the pages of a Notion export
+func (NotionImporter) Read(p string) ([]*Note, error) {
+	info, err := os.Stat(p)
+	if err != nil {
+		return nil, err
+	}
+
+	var pages []notionPage
+	if info.IsDir() {
+		pages, err = readNotionDir(p)
+	} else {
+		var data []byte
+		data, err = os.ReadFile(p)
+		if err == nil {
+			pages, err = readNotionZip(data)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(pages, func(i, j int) bool { return pages[i].path < pages[j].path })
+	trimNotionRoot(pages)
+
+	// Titles by file name, so links between pages can be rewritten
+	titles := make(map[string]string, len(pages))
+	for _, page := range pages {
+		titles[path.Base(page.path)] = notionPageTitle(page)
+	}
+
+	notes := make([]*Note, 0, len(pages))
+	for _, page := range pages {
+		notes = append(notes, parseNotionPage(page, titles))
+	}
+	return notes, nil
+}
+
+// readNotionDir returns the Markdown files of an unzipped export
+func readNotionDir(dir string) ([]notionPage, error) {
+	var pages []notionPage
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.EqualFold(filepath.Ext(p), ".md") {
+			return nil
+		}
+
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		pages = append(pages, notionPage{path: filepath.ToSlash(rel), data: data})
+		return nil
+	})
+	return pages, err
+}
+
+// readNotionZip returns the Markdown files of an export zip
+// Large exports come as a zip of zips, which are read too.
+func readNotionZip(data []byte) ([]notionPage, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("read zip: %w", err)
+	}
+
+	var pages []notionPage
+	for _, f := range archive.File {
+		ext := strings.ToLower(path.Ext(f.Name))
+		if f.FileInfo().IsDir() || (ext != ".md" && ext != ".zip") {
+			continue
+		}
+
+		r, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("open %s: %w", f.Name, err)
+		}
+		content, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", f.Name, err)
+		}
+
+		if ext == ".zip" {
+			inner, err := readNotionZip(content)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", f.Name, err)
+			}
+			pages = append(pages, inner...)
+			continue
+		}
+		pages = append(pages, notionPage{path: f.Name, data: content})
+	}
+	return pages, nil
+}
+
+// trimNotionRoot strips directories that wrap the whole export, so they don't become tags
+func trimNotionRoot(pages []notionPage) {
+	for len(pages) > 0 {
+		root, _, ok := strings.Cut(pages[0].path, "/")
+		if !ok {
+			return
+		}
+		for _, page := range pages {
+			if !strings.HasPrefix(page.path, root+"/") {
+				return
+			}
+		}
+		for i := range pages {
+			pages[i].path = strings.TrimPrefix(pages[i].path, root+"/")
+		}
+	}
+}
+
+// parseNotionPage turns an exported page into a note
+func parseNotionPage(page notionPage, titles map[string]string) *Note {
+	note := &Note{
+		Title:  notionPageTitle(page),
+		Source: page.path,
+	}
+
+	// Pages below a top-level page are in a directory named after it
+	if parts := strings.Split(page.path, "/"); len(parts) > 1 {
+		note.Tags = addTag(note.Tags, notionName(parts[0]))
+	}
+
+	lines := strings.Split(strings.ReplaceAll(string(page.data), "\r\n", "\n"), "\n")
+
+	if len(lines) > 0 && strings.HasPrefix(lines[0], "# ") {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+
+	// Database pages then list their properties, one per line
+	end := 0
+	for end < len(lines) && notionProperty.MatchString(lines[end]) {
+		end++
+	}
+	if end > 0 && (end == len(lines) || strings.TrimSpace(lines[end]) == "") {
+		var kept []string
+		for _, line := range lines[:end] {
+			m := notionProperty.FindStringSubmatch(line)
+			switch strings.ToLower(m[1]) {
+			case "tags", "tag", "labels":
+				for _, tag := range strings.Split(m[2], ",") {
+					note.Tags = addTag(note.Tags, tag)
+				}
+			case "created", "created time", "created at", "date created":
+				if created, ok := parseNotionDate(m[2]); ok {
+					note.Created = created
+				} else {
+					kept = append(kept, line)
+				}
+			default:
+				kept = append(kept, line)
+			}
+		}
+		lines = append(kept, lines[end:]...)
+	}
+
+	content := strings.Join(lines, "\n")
+	content = notionPageLink.ReplaceAllStringFunc(content, func(link string) string {
+		m := notionPageLink.FindStringSubmatch(link)
+		target, err := url.PathUnescape(m[2])
+		if err != nil {
+			return link
+		}
+		title, ok := titles[path.Base(target)]
+		if !ok {
+			return link
+		}
+		if m[1] == "" || m[1] == title {
+			return "[[" + title + "]]"
+		}
+		return "[[" + title + "|" + m[1] + "]]"
+	})
+
+	note.Content = tidyMarkdown(content)
+	return note
+}
+
+// notionPageTitle returns the title of a page: its first heading, or else its file name
+func notionPageTitle(page notionPage) string {
+	first, _, _ := strings.Cut(string(page.data), "\n")
+	if title, ok := strings.CutPrefix(strings.TrimSpace(first), "# "); ok {
+		return noteTitle(title)
+	}
+	return noteTitle(notionName(path.Base(page.path)))
+}
+
+// notionName strips the extension and page ID from an exported file or directory name
+func notionName(name string) string {
+	if strings.EqualFold(path.Ext(name), ".md") {
+		name = name[:len(name)-len(".md")]
+	}
+	return strings.TrimSpace(notionIDSuffix.ReplaceAllString(name, ""))
+}
+
+// parseNotionDate parses a date property, in local time like Notion writes them
+func parseNotionDate(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	// Date ranges are written as "start → end"
+	if i := strings.Index(value, "→"); i >= 0 {
+		value = strings.TrimSpace(value[:i])
+	}
+	for _, layout := range notionDateLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
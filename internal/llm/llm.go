@@ -0,0 +1,77 @@
+// Package llm generates text with a large language model, e.g. note summaries
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/momokii/go-cli-notes/internal/config"
+)
+
+// Provider generates text with a chat model
+type Provider interface {
+	// Complete returns the model's reply to prompt, following the system instructions
+	Complete(ctx context.Context, system, prompt string) (string, error)
+	// Model names the chat model
+	Model() string
+}
+
+// New creates the provider selected by the LLM configuration
+// It returns nil when no provider is configured, which disables summarization.
+func New(cfg config.LLMConfig) (Provider, error) {
+	switch cfg.Provider {
+	case "":
+		return nil, nil
+	case "openai":
+		return NewOpenAIProvider(OpenAIOptions{
+			BaseURL: cfg.OpenAIBaseURL,
+			APIKey:  cfg.OpenAIAPIKey,
+			Model:   cfg.Model,
+		})
+	case "ollama":
+		return NewOllamaProvider(OllamaOptions{
+			URL:   cfg.OllamaURL,
+			Model: cfg.Model,
+		})
+	default:
+		return nil, fmt.Errorf("unknown LLM provider %q", cfg.Provider)
+	}
+}
+
+// postJSON sends body as JSON to url and decodes the JSON response into out
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, body, out any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("LLM API returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultOllamaModel is used when no model is configured
+const defaultOllamaModel = "llama3.2"
+
+// OllamaOptions configures an OllamaProvider
+type OllamaOptions struct {
+	URL   string // e.g. http://localhost:11434
+	Model string
+}
+
+// OllamaProvider generates text with a local Ollama server
+// Note text never leaves the machine running Ollama.
+type OllamaProvider struct {
+	opts       OllamaOptions
+	httpClient *http.Client
+}
+
+// NewOllamaProvider creates an Ollama chat provider
+func NewOllamaProvider(opts OllamaOptions) (*OllamaProvider, error) {
+	if opts.URL == "" {
+		opts.URL = "http://localhost:11434"
+	}
+	if opts.Model == "" {
+		opts.Model = defaultOllamaModel
+	}
+	opts.URL = strings.TrimRight(opts.URL, "/")
+
+	return &OllamaProvider{
+		opts: opts,
+		// Local models are slow to load and generate on modest hardware
+		httpClient: &http.Client{Timeout: 5 * time.Minute},
+	}, nil
+}
+
+// Model returns the chat model name
+func (p *OllamaProvider) Model() string {
+	return p.opts.Model
+}
+
+// Complete sends one system and one user message and returns the reply
+func (p *OllamaProvider) Complete(ctx context.Context, system, prompt string) (string, error) {
+	var resp struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+	}
+
+	err := postJSON(ctx, p.httpClient, p.opts.URL+"/api/chat", nil,
+		map[string]any{
+			"model": p.opts.Model,
+			"messages": []map[string]string{
+				{"role": "system", "content": system},
+				{"role": "user", "content": prompt},
+			},
+			"stream": false,
+		},
+		&resp)
+	if err != nil {
+		return "", fmt.Errorf("ollama chat: %w", err)
+	}
+
+	return strings.TrimSpace(resp.Message.Content), nil
+}
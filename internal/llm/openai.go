@@ -0,0 +1,81 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultOpenAIModel is used when no model is configured
+const defaultOpenAIModel = "gpt-4o-mini"
+
+// OpenAIOptions configures an OpenAIProvider
+type OpenAIOptions struct {
+	BaseURL string // e.g. https://api.openai.com/v1, or any OpenAI-compatible API
+	APIKey  string
+	Model   string
+}
+
+// OpenAIProvider generates text with the OpenAI chat completions API
+type OpenAIProvider struct {
+	opts       OpenAIOptions
+	httpClient *http.Client
+}
+
+// NewOpenAIProvider creates an OpenAI chat provider
+func NewOpenAIProvider(opts OpenAIOptions) (*OpenAIProvider, error) {
+	if opts.APIKey == "" {
+		return nil, errors.New("OpenAI API key is required")
+	}
+	if opts.BaseURL == "" {
+		opts.BaseURL = "https://api.openai.com/v1"
+	}
+	if opts.Model == "" {
+		opts.Model = defaultOpenAIModel
+	}
+	opts.BaseURL = strings.TrimRight(opts.BaseURL, "/")
+
+	return &OpenAIProvider{
+		opts:       opts,
+		httpClient: &http.Client{Timeout: 2 * time.Minute},
+	}, nil
+}
+
+// Model returns the chat model name
+func (p *OpenAIProvider) Model() string {
+	return p.opts.Model
+}
+
+// Complete sends one system and one user message and returns the reply
+func (p *OpenAIProvider) Complete(ctx context.Context, system, prompt string) (string, error) {
+	var resp struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+
+	err := postJSON(ctx, p.httpClient, p.opts.BaseURL+"/chat/completions",
+		map[string]string{"Authorization": "Bearer " + p.opts.APIKey},
+		map[string]any{
+			"model": p.opts.Model,
+			"messages": []map[string]string{
+				{"role": "system", "content": system},
+				{"role": "user", "content": prompt},
+			},
+		},
+		&resp)
+	if err != nil {
+		return "", fmt.Errorf("openai chat: %w", err)
+	}
+
+	if len(resp.Choices) == 0 {
+		return "", errors.New("openai chat: no reply")
+	}
+
+	return strings.TrimSpace(resp.Choices[0].Message.Content), nil
+}
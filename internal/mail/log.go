@@ -0,0 +1,21 @@
+package mail
+
+import (
+	"context"
+	"log/slog"
+)
+
+// LogSender writes emails to the server log instead of sending them
+// Useful in development and for single-user setups without a mail server.
+type LogSender struct{}
+
+// NewLogSender creates a sender that logs every message
+func NewLogSender() *LogSender {
+	return &LogSender{}
+}
+
+// Send logs the message, including its body
+func (s *LogSender) Send(ctx context.Context, msg *Message) error {
+	slog.Info("Email (log driver, not sent)", "to", msg.To, "subject", msg.Subject, "body", msg.Body)
+	return nil
+}
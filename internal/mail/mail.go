@@ -0,0 +1,40 @@
+// Package mail sends transactional emails such as password reset tokens
+package mail
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/momokii/go-cli-notes/internal/config"
+)
+
+// Message is a plain-text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender delivers emails
+type Sender interface {
+	// Send delivers msg, returning once the message has been handed off
+	Send(ctx context.Context, msg *Message) error
+}
+
+// New creates the sender selected by the mail configuration
+func New(cfg config.MailConfig) (Sender, error) {
+	switch cfg.Driver {
+	case "", "log":
+		return NewLogSender(), nil
+	case "smtp":
+		return NewSMTPSender(SMTPOptions{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.From,
+		})
+	default:
+		return nil, fmt.Errorf("unknown mail driver %q", cfg.Driver)
+	}
+}
@@ -17,8 +17,18 @@ const (
 	ActionDelete ActionType = "delete"
 	ActionLogin  ActionType = "login"
 	ActionLogout ActionType = "logout"
+	ActionFocus  ActionType = "focus" // A completed focus session on a note; metadata holds its duration_seconds
 )
 
+// IsValid reports whether a is a known action type
+func (a ActionType) IsValid() bool {
+	switch a {
+	case ActionCreate, ActionUpdate, ActionView, ActionSearch, ActionDelete, ActionLogin, ActionLogout, ActionFocus:
+		return true
+	}
+	return false
+}
+
 // Activity represents a user activity log entry
 type Activity struct {
 	ID        uuid.UUID          `json:"id" db:"id"`
@@ -39,6 +49,24 @@ type LogActivityRequest struct {
 	Metadata ActivityMetadata
 }
 
+// FocusDurationKey is the activity metadata key holding the length of a focus session, in seconds
+const FocusDurationKey = "duration_seconds"
+
+// LogFocusRequest represents a request to log a completed focus session on a note
+type LogFocusRequest struct {
+	DurationSeconds int `json:"duration_seconds" validate:"required,min=1,max=86400"`
+}
+
+// ActivityFilter represents filter options for listing activities
+type ActivityFilter struct {
+	Page   int
+	Limit  int
+	Action *ActionType
+	NoteID *uuid.UUID
+	From   *time.Time // Inclusive
+	To     *time.Time // Exclusive
+}
+
 // UserStats represents user statistics
 type UserStats struct {
 	TotalNotes       int64     `json:"total_notes"`
@@ -47,9 +75,66 @@ type UserStats struct {
 	TotalWords       int64     `json:"total_words"`
 	NotesCreatedToday int64    `json:"notes_created_today"`
 	NotesCreatedWeek int64     `json:"notes_created_week"`
+	NotesByType      map[NoteType]int64 `json:"notes_by_type"` // Types without notes are left out
 	LastActivity     *time.Time `json:"last_activity,omitempty"`
 }
 
+// DailyWords is the number of words a user wrote on one day
+type DailyWords struct {
+	Date  string `json:"date"` // YYYY-MM-DD in the user's timezone
+	Words int    `json:"words"`
+}
+
+// HeatmapDay is the number of notes a user created or updated on one day
+type HeatmapDay struct {
+	Date  string `json:"date"` // YYYY-MM-DD in the user's timezone
+	Count int    `json:"count"`
+}
+
+// NoteStats shows how alive a note is: how often it is read, edited and linked
+type NoteStats struct {
+	NoteID        uuid.UUID     `json:"note_id"`
+	ViewCount     int           `json:"view_count"` // Times the owner opened the note
+	EditCount     int           `json:"edit_count"`
+	LastViewedAt  *time.Time    `json:"last_viewed_at,omitempty"`
+	LastEditedAt  time.Time     `json:"last_edited_at"`
+	OutgoingLinks int           `json:"outgoing_links"`
+	IncomingLinks int           `json:"incoming_links"`
+	FocusSessions int           `json:"focus_sessions"`
+	FocusSeconds  int64         `json:"focus_seconds"` // Total length of the note's focus sessions
+	DailyViews    []*DayCount   `json:"daily_views"`   // The last 30 days, oldest first, in the user's timezone
+	MonthlyViews  []*MonthCount `json:"monthly_views"` // The last 12 months, oldest first
+}
+
+// DayCount is a number of events on one day
+type DayCount struct {
+	Date  string `json:"date"` // YYYY-MM-DD in the user's timezone
+	Count int    `json:"count"`
+}
+
+// MonthCount is a number of events in one month
+type MonthCount struct {
+	Month string `json:"month"` // YYYY-MM in the user's timezone
+	Count int    `json:"count"`
+}
+
+// ActivityHeatmap is a user's daily note activity over whole weeks, oldest day first
+// Days starts on a week_start day, so it splits into columns of 7 days; the last week ends today.
+type ActivityHeatmap struct {
+	WeekStart string        `json:"week_start"`
+	Days      []*HeatmapDay `json:"days"`
+}
+
+// WritingStreak represents a user's progress toward their daily word goal
+// A streak counts consecutive days on which the goal was met; today only ends it once the day is over.
+type WritingStreak struct {
+	CurrentStreak int  `json:"current_streak"`
+	LongestStreak int  `json:"longest_streak"`
+	DailyGoal     int  `json:"daily_goal"`
+	WordsToday    int  `json:"words_today"`
+	GoalMetToday  bool `json:"goal_met_today"`
+}
+
 // TrendingNote represents a note that's trending (frequently accessed)
 type TrendingNote struct {
 	Note         *Note `json:"note"`
@@ -63,3 +148,35 @@ type ForgottenNote struct {
 	LastAccessedAt    time.Time `json:"last_accessed_at"`
 	DaysSinceAccess   int       `json:"days_since_access"`
 }
+
+// TagUsage is how many notes of a period have a tag
+type TagUsage struct {
+	Name      string `json:"name"`
+	NoteCount int    `json:"note_count"`
+}
+
+// WeeklyReview summarizes a user's notes over one week
+type WeeklyReview struct {
+	WeekStart      string           `json:"week_start"` // YYYY-MM-DD in the user's timezone
+	WeekEnd        string           `json:"week_end"`   // YYYY-MM-DD, the last day of the week
+	NotesCreated   []*Note          `json:"notes_created"`
+	NotesUpdated   []*Note          `json:"notes_updated"` // Created before the week
+	TopTags        []*TagUsage      `json:"top_tags"`
+	CompletedTasks []*Task          `json:"completed_tasks"`
+	Forgotten      []*ForgottenNote `json:"forgotten"`
+}
+
+// DailyDigest sums up a user's day, posted to their Slack or Discord webhook the next day
+type DailyDigest struct {
+	Date          string           `json:"date"` // YYYY-MM-DD in the user's timezone
+	NotesCreated  int              `json:"notes_created"`
+	WordsWritten  int              `json:"words_written"`
+	DailyWordGoal int              `json:"daily_word_goal"`
+	Streak        int              `json:"streak"`    // Current writing streak, in days
+	Forgotten     []*ForgottenNote `json:"forgotten"` // Notes resurfaced for the user to revisit
+}
+
+// WeeklyReviewRequest represents a request to generate a weekly review note
+type WeeklyReviewRequest struct {
+	Date string `json:"date" validate:"omitempty,datetime=2006-01-02"` // Any day of the week to review; default today
+}
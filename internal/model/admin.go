@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+)
+
+// AdminUser is a user account as listed to instance admins
+type AdminUser struct {
+	User
+	NoteCount int64      `json:"note_count"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"` // Set when the user deleted their own account
+}
+
+// AdminStats represents instance-wide statistics
+type AdminStats struct {
+	TotalUsers       int64 `json:"total_users"`
+	ActiveUsers      int64 `json:"active_users"`      // Not deactivated or deleted
+	NewUsersWeek     int64 `json:"new_users_week"`    // Registered in the last 7 days
+	ActiveUsersWeek  int64 `json:"active_users_week"` // Logged in during the last 7 days
+	TotalNotes       int64 `json:"total_notes"`
+	TotalWords       int64 `json:"total_words"`
+	TotalTags        int64 `json:"total_tags"`
+	TotalLinks       int64 `json:"total_links"`
+	TotalAttachments int64 `json:"total_attachments"`
+	AttachmentBytes  int64 `json:"attachment_bytes"`
+}
@@ -0,0 +1,19 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Attachment represents a file attached to a note
+type Attachment struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	UserID      uuid.UUID `json:"user_id" db:"user_id"`
+	NoteID      uuid.UUID `json:"note_id" db:"note_id"`
+	Filename    string    `json:"filename" db:"filename"`
+	ContentType string    `json:"content_type" db:"content_type"`
+	SizeBytes   int64     `json:"size_bytes" db:"size_bytes"`
+	StorageKey  string    `json:"-" db:"storage_key"` // Internal object store key
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
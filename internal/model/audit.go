@@ -0,0 +1,82 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditEvent is a security event recorded in the audit log
+type AuditEvent string
+
+const (
+	AuditLogin           AuditEvent = "login"
+	AuditLoginFailed     AuditEvent = "login_failed"
+	AuditLogout          AuditEvent = "logout"
+	AuditTokenRefresh    AuditEvent = "token_refresh"
+	AuditTokenReuse      AuditEvent = "token_reuse" // A revoked refresh token was presented again, every session was revoked
+	AuditPasswordChanged AuditEvent = "password_changed"
+	AuditPasswordReset   AuditEvent = "password_reset"
+	AuditTOTPEnabled     AuditEvent = "2fa_enabled"
+	AuditSessionRevoked  AuditEvent = "session_revoked"
+	AuditSessionsRevoked AuditEvent = "sessions_revoked"
+	AuditDeviceApproved  AuditEvent = "device_approved"
+	AuditDeviceDenied    AuditEvent = "device_denied"
+	AuditAccountDeleted  AuditEvent = "account_deleted"
+	AuditUserDeactivated AuditEvent = "user_deactivated" // By an admin
+	AuditUserActivated   AuditEvent = "user_activated"   // By an admin
+)
+
+// AuditEvents are all the audit event types
+var AuditEvents = []AuditEvent{
+	AuditLogin,
+	AuditLoginFailed,
+	AuditLogout,
+	AuditTokenRefresh,
+	AuditTokenReuse,
+	AuditPasswordChanged,
+	AuditPasswordReset,
+	AuditTOTPEnabled,
+	AuditSessionRevoked,
+	AuditSessionsRevoked,
+	AuditDeviceApproved,
+	AuditDeviceDenied,
+	AuditAccountDeleted,
+	AuditUserDeactivated,
+	AuditUserActivated,
+}
+
+// IsValid reports whether e is a known audit event
+func (e AuditEvent) IsValid() bool {
+	for _, event := range AuditEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// AuditEntry is one security event in the audit log
+type AuditEntry struct {
+	ID        uuid.UUID     `json:"id" db:"id"`
+	UserID    *uuid.UUID    `json:"user_id,omitempty" db:"user_id"` // Empty for failed logins with an unknown email
+	Event     AuditEvent    `json:"event" db:"event"`
+	Email     string        `json:"email,omitempty" db:"email"` // The email signed in with, or of the user at the time
+	IPAddress string        `json:"ip_address,omitempty" db:"ip_address"`
+	UserAgent string        `json:"user_agent,omitempty" db:"user_agent"`
+	Metadata  AuditMetadata `json:"metadata,omitempty" db:"metadata"`
+	CreatedAt time.Time     `json:"created_at" db:"created_at"`
+}
+
+// AuditMetadata holds event details, e.g. why a login failed
+type AuditMetadata map[string]any
+
+// AuditFilter represents filter options for listing the audit log
+type AuditFilter struct {
+	Page   int
+	Limit  int
+	UserID *uuid.UUID // Every user's entries when nil
+	Event  *AuditEvent
+	From   *time.Time // Inclusive
+	To     *time.Time // Exclusive
+}
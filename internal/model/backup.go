@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+// BackupFormat is the version of the backup archive layout
+const BackupFormat = 1
+
+// Backup is a backup archive in the backup store
+type Backup struct {
+	Name      string    `json:"name"`
+	Size      int64     `json:"size"` // Bytes
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// BackupManifest describes the contents of a backup archive, stored as manifest.json
+type BackupManifest struct {
+	Format    int              `json:"format"`
+	CreatedAt time.Time        `json:"created_at"`
+	Tables    map[string]int64 `json:"tables"` // Rows per table
+}
+
+// BackupResult is a backup that was just written
+type BackupResult struct {
+	Backup
+	Tables map[string]int64 `json:"tables"` // Rows per table
+}
+
+// RestoreResult reports what a restore added to the database
+type RestoreResult struct {
+	Name     string           `json:"name"`
+	Restored map[string]int64 `json:"restored"` // Rows inserted per table
+	Skipped  map[string]int64 `json:"skipped"`  // Rows already present per table
+}
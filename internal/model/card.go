@@ -0,0 +1,52 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Card is a "Q:: ... A:: ..." flashcard extracted from a note, scheduled for review with SM-2
+type Card struct {
+	ID             uuid.UUID  `json:"id" db:"id"`
+	UserID         uuid.UUID  `json:"user_id" db:"user_id"`
+	NoteID         uuid.UUID  `json:"note_id" db:"note_id"`
+	NoteTitle      string     `json:"note_title"` // Populated when listing
+	LineNumber     int        `json:"line_number" db:"line_number"`
+	Question       string     `json:"question" db:"question"`
+	Answer         string     `json:"answer" db:"answer"`
+	EaseFactor     float64    `json:"ease_factor" db:"ease_factor"`
+	IntervalDays   int        `json:"interval_days" db:"interval_days"`
+	Repetitions    int        `json:"repetitions" db:"repetitions"` // Successful reviews in a row
+	DueOn          *string    `json:"due_on,omitempty" db:"due_on"` // YYYY-MM-DD in the user's timezone, unset for new cards
+	LastReviewedAt *time.Time `json:"last_reviewed_at,omitempty" db:"last_reviewed_at"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+}
+
+// SM-2 scheduling constants
+const (
+	CardInitialEase = 2.5 // Ease factor of a new card
+	CardMinEase     = 1.3 // Lowest ease factor, so hard cards still come back less often over time
+	CardPassGrade   = 3   // Lowest grade that counts as remembered
+	CardMaxGrade    = 5
+)
+
+// DefaultDueCardsLimit and MaxDueCardsLimit bound how many due cards one request returns
+const (
+	DefaultDueCardsLimit = 20
+	MaxDueCardsLimit     = 100
+)
+
+// ReviewCardRequest grades how well a card was remembered, SM-2 style:
+// 5 perfect, 4 after some thought, 3 with difficulty, 2 wrong but familiar, 1 wrong, 0 blank
+type ReviewCardRequest struct {
+	Grade *int `json:"grade" validate:"required,min=0,max=5"`
+}
+
+// DueCards lists the cards due for review today
+type DueCards struct {
+	Cards []*Card `json:"cards"`
+	Count int     `json:"count"`
+	Total int64   `json:"total"` // Due cards, including those past the limit
+	Today string  `json:"today"` // YYYY-MM-DD in the user's timezone
+}
@@ -15,6 +15,47 @@ var (
 	ErrInvalidToken  = errors.New("invalid token")
 	ErrExpiredToken  = errors.New("token expired")
 	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrTOTPRequired  = errors.New("two-factor code required")
+	ErrInvalidTOTP   = errors.New("invalid two-factor code")
+	ErrTOTPEnabled   = errors.New("two-factor authentication already enabled")
+	ErrTOTPNotSetup  = errors.New("two-factor authentication not set up")
+	ErrInvalidResetToken = errors.New("invalid or expired reset token")
+	ErrInvalidVerificationToken = errors.New("invalid or expired verification token")
+	ErrEmailNotVerified = errors.New("email not verified")
+	ErrWrongPassword    = errors.New("incorrect password")
+	ErrSemanticSearchDisabled = errors.New("semantic search is not enabled")
+	ErrSummarizationDisabled  = errors.New("summarization is not enabled")
+	ErrNoteTypeInUse          = errors.New("note type in use")
+	ErrClipFailed             = errors.New("could not clip page")
+	ErrAuthorizationPending   = errors.New("device authorization pending")
+	ErrSlowDown               = errors.New("device polling too fast")
+	ErrDeviceCodeExpired      = errors.New("device code expired")
+	ErrAccessDenied           = errors.New("device authorization denied")
+	ErrInvalidUserCode        = errors.New("invalid or expired device code")
+	ErrNoPath                 = errors.New("no path between the notes")
+	ErrNoteChanged            = errors.New("note changed since the version the update is based on")
+)
+
+// Error codes sent in the "code" field of API error responses
+// Clients can branch on these instead of matching error messages.
+const (
+	CodeValidation       = "VALIDATION_FAILED" // Also lists the invalid "fields" when known
+	CodeUnauthorized     = "UNAUTHORIZED"
+	CodeForbidden        = "FORBIDDEN"
+	CodeNotFound         = "NOT_FOUND"
+	CodeConflict         = "CONFLICT"
+	CodeRateLimited      = "RATE_LIMITED"
+	CodeUnavailable      = "UNAVAILABLE"
+	CodeInternal         = "INTERNAL_ERROR"
+	CodeTOTPRequired     = "TOTP_REQUIRED"
+	CodeEmailNotVerified = "EMAIL_NOT_VERIFIED"
+	CodeNoteChanged      = "NOTE_CHANGED" // An If-Match update of a note that changed since
+
+	// Device login polling, as in the OAuth device flow (RFC 8628)
+	CodeAuthorizationPending = "AUTHORIZATION_PENDING"
+	CodeSlowDown             = "SLOW_DOWN"
+	CodeExpiredToken         = "EXPIRED_TOKEN"
+	CodeAccessDenied         = "ACCESS_DENIED"
 )
 
 // APIError represents an API error response
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventType identifies what changed in a live update event
+type EventType string
+
+const (
+	EventNoteCreated EventType = "note.created"
+	EventNoteUpdated EventType = "note.updated"
+	EventNoteDeleted EventType = "note.deleted"
+	EventTagCreated  EventType = "tag.created"
+	EventTagUpdated  EventType = "tag.updated"
+	EventTagDeleted  EventType = "tag.deleted"
+	EventActivity    EventType = "activity" // Activity logged without a content change (e.g. a note view)
+)
+
+// Event is a change notification pushed to a user's connected clients
+type Event struct {
+	Type      EventType  `json:"type"`
+	NoteID    *uuid.UUID `json:"note_id,omitempty"`
+	TagID     *uuid.UUID `json:"tag_id,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
@@ -32,7 +32,8 @@ type GraphNeighbor struct {
 
 // GraphPath represents a path between two notes
 type GraphPath struct {
-	Path []*Note `json:"path"` // Ordered list of notes from source to target
+	Path  []*Note      `json:"path"`  // Ordered list of notes from source to target
+	Links []*GraphEdge `json:"links"` // Links[i] joins Path[i] and Path[i+1], in either direction
 }
 
 // GraphStats represents statistics about the knowledge graph
@@ -41,8 +42,29 @@ type GraphStats struct {
 	TotalLinks    int64 `json:"total_links"`
 	Connected     int64 `json:"connected"`     // Notes with at least one link
 	Orphans       int64 `json:"orphans"`        // Notes with no links
-	MaxDepth      int   `json:"max_depth"`      // Longest shortest path
+	MaxDepth      int   `json:"max_depth"`      // Longest shortest path, estimated
 	AverageDegree float64 `json:"average_degree"` // Average links per note
+
+	Components         int               `json:"components"`          // Groups of notes linked to each other, an orphan is one on its own
+	Clusters           int               `json:"clusters"`            // Clusters of two or more notes
+	DegreeDistribution []*DegreeCount    `json:"degree_distribution"` // Ascending degree
+	MostConnected      []*GraphNoteScore `json:"most_connected"`      // Most links first
+	Hubs               []*GraphNoteScore `json:"hubs"`                // Highest centrality first
+}
+
+// DegreeCount is how many notes have a number of links, counting both directions
+type DegreeCount struct {
+	Degree int `json:"degree"`
+	Notes  int `json:"notes"`
+}
+
+// GraphNoteScore is how central a note is in the knowledge graph
+type GraphNoteScore struct {
+	ID         uuid.UUID `json:"id"`
+	Title      string    `json:"title"`
+	InDegree   int       `json:"in_degree"`  // Links from other notes
+	OutDegree  int       `json:"out_degree"` // Links to other notes
+	Centrality float64   `json:"centrality"` // PageRank, the scores of all notes add up to 1
 }
 
 // GraphNode represents a node in the knowledge graph
@@ -51,6 +73,7 @@ type GraphNode struct {
 	Title  string    `json:"title"`
 	Type   NoteType  `json:"type"`
 	TagIDs []string  `json:"tag_ids,omitempty"`
+	Cluster int      `json:"cluster"` // Community of notes more linked to each other than to the rest, largest first from 1
 }
 
 // GraphEdge represents an edge in the knowledge graph
@@ -79,3 +102,14 @@ type LinkDetail struct {
 	CreatedAt    time.Time  `json:"created_at"`
 }
 
+
+// UnresolvedLink represents a wiki link whose target note does not exist yet
+type UnresolvedLink struct {
+	ID           uuid.UUID `json:"id" db:"id"`
+	UserID       uuid.UUID `json:"user_id" db:"user_id"`
+	SourceNoteID uuid.UUID `json:"source_note_id" db:"source_note_id"`
+	TargetTitle  string    `json:"target_title" db:"target_title"`
+	LinkContext  *string   `json:"link_context,omitempty" db:"link_context"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	SourceTitle  string    `json:"source_title,omitempty"` // Populated when listing
+}
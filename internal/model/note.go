@@ -1,6 +1,9 @@
 package model
 
 import (
+	"encoding/json"
+	"fmt"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
@@ -22,6 +25,7 @@ type Note struct {
 	UserID               uuid.UUID  `json:"user_id" db:"user_id"`
 	Title                string     `json:"title" db:"title"`
 	Content              string     `json:"content" db:"content"`
+	ExpandedContent      *string    `json:"expanded_content,omitempty"` // Content with ![[...]] embeds expanded, with expand=embeds
 	NoteType             NoteType   `json:"note_type" db:"note_type"`
 	WordCount            int        `json:"word_count" db:"word_count"`
 	ReadingTimeMinutes   int        `json:"reading_time_minutes" db:"reading_time_minutes"`
@@ -32,33 +36,246 @@ type Note struct {
 	LastAccessedAt       *time.Time `json:"last_accessed_at,omitempty" db:"last_accessed_at"`
 	AccessCount          int        `json:"access_count" db:"access_count"`
 	Metadata             Metadata   `json:"metadata" db:"metadata"`
+	Encrypted            bool       `json:"encrypted" db:"encrypted"` // Content is client-side ciphertext
 	Tags                 []*Tag     `json:"tags,omitempty"` // Populated when needed
+	LinkCounts           *LinkCounts `json:"link_counts,omitempty"` // Populated with include=link_counts
+	SharedBy             string          `json:"shared_by,omitempty"`  // Owner's username, for notes shared with the user
+	Permission           SharePermission `json:"permission,omitempty"` // For notes shared with the user
+}
+
+// LinkCounts holds how many links a note has to and from other notes
+type LinkCounts struct {
+	Outgoing int `json:"outgoing"`
+	Incoming int `json:"incoming"`
+}
+
+// RelatedNote is a note suggested as related to another, with what the two have in common
+type RelatedNote struct {
+	Note           *Note   `json:"note"`
+	Score          float64 `json:"score"`
+	SharedTags     int     `json:"shared_tags"`
+	SharedLinks    int     `json:"shared_links"`    // Notes both link with, plus a direct link between them
+	TextSimilarity float64 `json:"text_similarity"` // Cosine similarity of the indexed words (0-1)
 }
 
 // Metadata represents flexible JSONB metadata for notes
 type Metadata map[string]any
 
+// MetadataSummary is the metadata key a note's generated summary is stored under
+const MetadataSummary = "summary"
+
+// MetadataStatus is the metadata key of a note's board status
+const MetadataStatus = "status"
+
+// MetadataReadProgress is the metadata key of how far the owner has read a note
+const MetadataReadProgress = "read_progress"
+
+// NoteStatus is the board column of a note that tracks work
+type NoteStatus string
+
+const (
+	NoteStatusTodo  NoteStatus = "todo"
+	NoteStatusDoing NoteStatus = "doing"
+	NoteStatusDone  NoteStatus = "done"
+)
+
+// NoteStatuses lists every status, in the order boards show them
+var NoteStatuses = []NoteStatus{NoteStatusTodo, NoteStatusDoing, NoteStatusDone}
+
+// NoteStatusAny filters notes that have any status
+const NoteStatusAny NoteStatus = "any"
+
+// Status returns the board status stored in the metadata, or "" when there is none
+func (m Metadata) Status() NoteStatus {
+	status, _ := m[MetadataStatus].(string)
+	return NoteStatus(status)
+}
+
+// Keys returns the metadata keys to show, sorted, leaving out the generated summary and the
+// read progress, which are shown on their own
+func (m Metadata) Keys() []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		if key != MetadataSummary && key != MetadataReadProgress {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Format returns the value of a metadata key as text: strings as they are, other values as JSON
+func (m Metadata) Format(key string) string {
+	if s, ok := m[key].(string); ok {
+		return s
+	}
+	data, err := json.Marshal(m[key])
+	if err != nil {
+		return fmt.Sprint(m[key])
+	}
+	return string(data)
+}
+
+// ReadProgress is how far down a note has been read, saved as it is scrolled
+type ReadProgress struct {
+	Percent   int       `json:"percent"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Reading reports whether the note was started but not read to the end
+func (p *ReadProgress) Reading() bool {
+	return p != nil && p.Percent > 0 && p.Percent < 100
+}
+
+// ReadProgress returns the read progress stored in the metadata, or nil when there is none
+func (m Metadata) ReadProgress() *ReadProgress {
+	raw, ok := m[MetadataReadProgress].(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	// Metadata is decoded from JSON, so numbers are float64
+	percent, ok := raw["percent"].(float64)
+	if !ok {
+		return nil
+	}
+	progress := &ReadProgress{Percent: int(percent)}
+	if at, ok := raw["updated_at"].(string); ok {
+		progress.UpdatedAt, _ = time.Parse(time.RFC3339Nano, at)
+	}
+	return progress
+}
+
+// SetReadProgressRequest represents a request to save how far a note has been read
+type SetReadProgressRequest struct {
+	Percent *int `json:"percent" validate:"required,min=0,max=100"`
+}
+
+// DefaultReadingLimit and MaxReadingLimit bound how many notes being read are listed
+const (
+	DefaultReadingLimit = 5
+	MaxReadingLimit     = 50
+)
+
+// NoteSummary is a generated TL;DR of a note
+type NoteSummary struct {
+	Summary     string    `json:"summary"`
+	Model       string    `json:"model"`
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+// Summary returns the summary stored in the metadata, or nil when there is none
+func (m Metadata) Summary() *NoteSummary {
+	raw, ok := m[MetadataSummary].(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	summary := &NoteSummary{}
+	summary.Summary, _ = raw["summary"].(string)
+	summary.Model, _ = raw["model"].(string)
+	if at, ok := raw["generated_at"].(string); ok {
+		summary.GeneratedAt, _ = time.Parse(time.RFC3339Nano, at)
+	}
+	if summary.Summary == "" {
+		return nil
+	}
+	return summary
+}
+
+// DailyNoteDay is a day that has a daily note
+type DailyNoteDay struct {
+	Date      string    `json:"date"` // YYYY-MM-DD
+	NoteID    uuid.UUID `json:"note_id"`
+	WordCount int       `json:"word_count"`
+}
+
+// Quick capture targets
+const (
+	CaptureTargetInbox = "inbox" // The note titled InboxTitle
+	CaptureTargetDaily = "daily" // Today's daily note
+)
+
+// InboxTitle is the title of the note quick captures are collected in
+const InboxTitle = "Inbox"
+
+// CaptureRequest represents a quick capture, appended to a note as a list item
+type CaptureRequest struct {
+	Text   string `json:"text" validate:"required,min=1,max=10000"`
+	Target string `json:"target,omitempty" validate:"omitempty,oneof=inbox daily"` // Default: capture_target setting
+}
+
+// ClippedTag is the tag of notes saved from web pages
+const ClippedTag = "clipped"
+
+// ClipRequest asks the server to save the article of a web page as a note
+type ClipRequest struct {
+	URL  string   `json:"url" validate:"required,url,max=2048"`
+	Tags []string `json:"tags,omitempty" validate:"max=20,dive,min=1,max=100"` // Added next to the clipped tag
+}
+
+// InboxStatus is how much is waiting in the Inbox note
+type InboxStatus struct {
+	NoteID *uuid.UUID `json:"note_id"` // Nil until something is captured
+	Items  int        `json:"items"`   // List items not yet checked off
+}
+
 // CreateNoteRequest represents a note creation request
 type CreateNoteRequest struct {
-	Title    string   `json:"title" validate:"required,min=1,max=500"`
-	Content  string   `json:"content" validate:"max=100000"` // Large limit for markdown
-	NoteType NoteType `json:"note_type" validate:"omitempty,oneof=note daily meeting idea"`
+	Title     string   `json:"title" validate:"required,min=1,max=500"`
+	Content   string   `json:"content" validate:"max=100000"` // Large limit for markdown
+	NoteType  NoteType `json:"note_type" validate:"omitempty,max=50"` // A built-in or custom note type
+	Encrypted bool     `json:"encrypted"` // Content is already encrypted by the client
+	// Metadata of the note, set next to the keys of a YAML frontmatter header at the top of the content
+	Metadata Metadata `json:"metadata,omitempty"`
+	// CreatedAt backdates a note brought over from elsewhere, defaults to now
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+// BatchNoteResult is the outcome of one note of a batch create, in request order
+type BatchNoteResult struct {
+	Index int    `json:"index"`
+	Note  *Note  `json:"note,omitempty"`  // Set when the note was created
+	Error string `json:"error,omitempty"` // Set when it wasn't
+}
+
+// BatchCreateResponse represents the results of a batch note creation
+type BatchCreateResponse struct {
+	Results []*BatchNoteResult `json:"results"`
+	Created int                `json:"created"`
+	Failed  int                `json:"failed"`
 }
 
 // UpdateNoteRequest represents a note update request
 type UpdateNoteRequest struct {
-	Title   *string `json:"title" validate:"omitempty,min=1,max=500"`
-	Content *string `json:"content" validate:"omitempty,max=100000"`
+	Title     *string `json:"title" validate:"omitempty,min=1,max=500"`
+	Content   *string `json:"content" validate:"omitempty,max=100000"`
+	NoteType  *NoteType `json:"note_type" validate:"omitempty,max=50"`
+	Encrypted *bool   `json:"encrypted"` // Switch client-side encryption on or off
+	// Keys to set in the metadata, null removes a key; keys not given are kept
+	Metadata  Metadata `json:"metadata,omitempty"`
+	// updated_at of the version the update is based on, from If-Match; a note updated since isn't changed
+	IfUpdatedAt *time.Time `json:"-"`
+}
+
+// NoteVersion returns the If-Match tag of the note version updated at updatedAt
+func NoteVersion(updatedAt time.Time) string {
+	return `"` + updatedAt.UTC().Format(time.RFC3339Nano) + `"`
+}
+
+// AppendNoteRequest represents text added to the end, or the start, of a note's content
+type AppendNoteRequest struct {
+	Content string `json:"content" validate:"required,max=100000"`
 }
 
 // ListNotesRequest represents a note list request with filters
 type ListNotesRequest struct {
 	Page      int      `query:"page" validate:"min=1"`
 	Limit     int      `query:"limit" validate:"min=1,max=100"`
-	Type      NoteType `query:"type" validate:"omitempty,oneof=note daily meeting idea"`
+	Type      NoteType `query:"type" validate:"omitempty,max=50"`
 	TagID     *string  `query:"tag_id"`
 	Search    string   `query:"search"`
-	SortBy    string   `query:"sort_by" validate:"omitempty,oneof=created_at updated_at title access_count"`
+	SortBy    string   `query:"sort_by" validate:"omitempty,oneof=created_at updated_at title access_count word_count relevance"`
 	SortOrder string   `query:"sort_order" validate:"omitempty,oneof=asc desc"`
 }
 
@@ -76,13 +293,44 @@ type NoteListResponse struct {
 	Pagination *Pagination `json:"pagination"`
 }
 
+// Note sort fields accepted in sort_by
+const (
+	SortCreatedAt   = "created_at"
+	SortUpdatedAt   = "updated_at"
+	SortTitle       = "title"
+	SortAccessCount = "access_count"
+	SortWordCount   = "word_count"
+	SortRelevance   = "relevance" // Full-text rank, only applies with a search term
+)
+
 // NoteFilter represents filters for listing notes (used by repository)
 type NoteFilter struct {
 	Page      int
 	Limit     int
 	NoteType  *NoteType
 	TagID     *string
+	Status    *NoteStatus // A status, or NoteStatusAny for every note with one
 	Search    string
 	SortBy    string
 	SortOrder string
+	// Related data to return with each note
+	IncludeTags       bool
+	IncludeLinkCounts bool
+}
+
+// ValidateSort checks SortBy and SortOrder against the accepted values
+func (f NoteFilter) ValidateSort() error {
+	switch f.SortBy {
+	case "", SortCreatedAt, SortUpdatedAt, SortTitle, SortAccessCount, SortWordCount, SortRelevance:
+	default:
+		return fmt.Errorf("%w: sort_by must be one of created_at, updated_at, title, access_count, word_count, relevance", ErrValidation)
+	}
+
+	switch f.SortOrder {
+	case "", "asc", "desc":
+	default:
+		return fmt.Errorf("%w: sort_order must be asc or desc", ErrValidation)
+	}
+
+	return nil
 }
@@ -0,0 +1,34 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SharePermission is what a user a note is shared with may do with it
+type SharePermission string
+
+const (
+	SharePermissionRead  SharePermission = "read"
+	SharePermissionWrite SharePermission = "write" // Read and edit the title and content
+)
+
+// NoteShare is a note shared with another user of the instance
+type NoteShare struct {
+	ID         uuid.UUID       `json:"id" db:"id"`
+	NoteID     uuid.UUID       `json:"note_id" db:"note_id"`
+	OwnerID    uuid.UUID       `json:"owner_id" db:"owner_id"`
+	SharedWith uuid.UUID       `json:"shared_with" db:"shared_with"`
+	Username   string          `json:"username"` // Of the user the note is shared with
+	Email      string          `json:"email"`
+	Permission SharePermission `json:"permission" db:"permission"`
+	CreatedAt  time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// ShareNoteRequest shares a note with a user, or changes the permission it is shared with
+type ShareNoteRequest struct {
+	User       string          `json:"user" validate:"required,max=255"`                 // Username or email
+	Permission SharePermission `json:"permission" validate:"omitempty,oneof=read write"` // Default: read
+}
@@ -0,0 +1,239 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NoteTypeDefinition describes a note type: one of the built-in types or one defined by the user
+type NoteTypeDefinition struct {
+	Name      NoteType         `json:"name"`
+	Icon      string           `json:"icon,omitempty"`
+	Color     *string          `json:"color,omitempty"` // Hex color, e.g., #00ADD8
+	BuiltIn   bool             `json:"built_in"`
+	NoteCount int64            `json:"note_count"`
+	Fields    []*NoteTypeField `json:"fields,omitempty"`     // Custom fields of notes of the type
+	CreatedAt *time.Time       `json:"created_at,omitempty"` // Unset for built-in types
+}
+
+// BuiltInNoteTypes are the note types every user has, in the order clients show them
+var BuiltInNoteTypes = []NoteTypeDefinition{
+	{Name: NoteTypeNote, Icon: "📝", BuiltIn: true},
+	{Name: NoteTypeDaily, Icon: "📅", BuiltIn: true},
+	{Name: NoteTypeMeeting, Icon: "👥", BuiltIn: true},
+	{Name: NoteTypeIdea, Icon: "💡", BuiltIn: true},
+}
+
+// IsBuiltIn returns whether the note type is one every user has
+func (t NoteType) IsBuiltIn() bool {
+	for _, builtIn := range BuiltInNoteTypes {
+		if builtIn.Name == t {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateNoteTypeRequest represents a custom note type creation request
+type CreateNoteTypeRequest struct {
+	Name  string  `json:"name" validate:"required,min=1,max=50"` // Lowercase letters, digits, "-" and "_"
+	Icon  string  `json:"icon" validate:"max=16"`
+	Color *string `json:"color" validate:"omitempty,len=7"` // Hex color, e.g., #00ADD8
+}
+
+// UpdateNoteTypeRequest represents a custom note type update request
+// Types are referenced by name, so a type can't be renamed.
+type UpdateNoteTypeRequest struct {
+	Icon  *string `json:"icon" validate:"omitempty,max=16"`
+	Color *string `json:"color" validate:"omitempty,len=7"`
+}
+
+// NoteFieldType is the kind of value a custom field holds
+type NoteFieldType string
+
+const (
+	NoteFieldText    NoteFieldType = "text"
+	NoteFieldNumber  NoteFieldType = "number"
+	NoteFieldDate    NoteFieldType = "date" // YYYY-MM-DD
+	NoteFieldBoolean NoteFieldType = "boolean"
+	NoteFieldList    NoteFieldType = "list" // List of strings
+)
+
+// NoteFieldTypes lists every custom field type
+var NoteFieldTypes = []NoteFieldType{NoteFieldText, NoteFieldNumber, NoteFieldDate, NoteFieldBoolean, NoteFieldList}
+
+// noteFieldDateLayout is the layout of date field values
+const noteFieldDateLayout = "2006-01-02"
+
+// NoteTypeField is a typed custom field of a note type
+// Its value is stored in the metadata of notes of the type, under the field's name.
+type NoteTypeField struct {
+	Name     string        `json:"name"`
+	Type     NoteFieldType `json:"type"`
+	Required bool          `json:"required,omitempty"`
+	Min      *float64      `json:"min,omitempty"` // Number fields only
+	Max      *float64      `json:"max,omitempty"` // Number fields only
+}
+
+// SetNoteTypeFieldsRequest replaces the custom fields of a note type, an empty list removes them
+type SetNoteTypeFieldsRequest struct {
+	Fields []*NoteTypeField `json:"fields"`
+}
+
+// Check returns the value normalized for the field's type, or an error when it doesn't fit
+// Numbers become float64, dates YYYY-MM-DD strings and lists []any of strings, as JSON decodes them.
+func (f *NoteTypeField) Check(value any) (any, error) {
+	switch f.Type {
+	case NoteFieldText:
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+		return nil, fmt.Errorf("%s must be text", f.Name)
+
+	case NoteFieldNumber:
+		var n float64
+		switch v := value.(type) {
+		case float64:
+			n = v
+		case int:
+			n = float64(v)
+		case int64:
+			n = float64(v)
+		case uint64:
+			n = float64(v)
+		default:
+			return nil, fmt.Errorf("%s must be a number", f.Name)
+		}
+		if f.Min != nil && n < *f.Min {
+			return nil, fmt.Errorf("%s must be at least %s", f.Name, strconv.FormatFloat(*f.Min, 'f', -1, 64))
+		}
+		if f.Max != nil && n > *f.Max {
+			return nil, fmt.Errorf("%s must be at most %s", f.Name, strconv.FormatFloat(*f.Max, 'f', -1, 64))
+		}
+		return n, nil
+
+	case NoteFieldDate:
+		switch v := value.(type) {
+		case time.Time:
+			// YAML frontmatter reads unquoted dates as timestamps
+			return v.Format(noteFieldDateLayout), nil
+		case string:
+			if _, err := time.Parse(noteFieldDateLayout, v); err == nil {
+				return v, nil
+			}
+		}
+		return nil, fmt.Errorf("%s must be a date (YYYY-MM-DD)", f.Name)
+
+	case NoteFieldBoolean:
+		if b, ok := value.(bool); ok {
+			return b, nil
+		}
+		return nil, fmt.Errorf("%s must be true or false", f.Name)
+
+	case NoteFieldList:
+		var items []any
+		switch v := value.(type) {
+		case []any:
+			items = v
+		case []string:
+			for _, item := range v {
+				items = append(items, item)
+			}
+		default:
+			return nil, fmt.Errorf("%s must be a list of strings", f.Name)
+		}
+		list := make([]any, 0, len(items))
+		for _, item := range items {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s must be a list of strings", f.Name)
+			}
+			if s = strings.TrimSpace(s); s != "" {
+				list = append(list, s)
+			}
+		}
+		return list, nil
+	}
+
+	return nil, fmt.Errorf("%s has unknown type %q", f.Name, f.Type)
+}
+
+// Parse reads a value for the field typed as text, as forms and flags give it
+// Lists are comma-separated. Empty text is no value, returned as nil.
+func (f *NoteTypeField) Parse(text string) (any, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, nil
+	}
+
+	switch f.Type {
+	case NoteFieldNumber:
+		n, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a number", f.Name)
+		}
+		return f.Check(n)
+	case NoteFieldBoolean:
+		switch strings.ToLower(text) {
+		case "true", "yes", "y", "1":
+			return true, nil
+		case "false", "no", "n", "0":
+			return false, nil
+		}
+		return nil, fmt.Errorf("%s must be true or false", f.Name)
+	case NoteFieldList:
+		return f.Check(strings.Split(text, ","))
+	}
+	return f.Check(text)
+}
+
+// Format returns a value of the field as text Parse reads back
+func (f *NoteTypeField) Format(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = fmt.Sprint(item)
+		}
+		return strings.Join(items, ", ")
+	}
+	return fmt.Sprint(value)
+}
+
+// CheckFields checks the metadata against the custom fields of its note's type, normalizing
+// their values in place. Required fields must have a value; keys that aren't fields are left alone.
+func (m Metadata) CheckFields(fields []*NoteTypeField) error {
+	for _, field := range fields {
+		value, ok := m[field.Name]
+		if !ok || value == nil || value == "" {
+			if field.Required {
+				return fmt.Errorf("%s is required", field.Name)
+			}
+			continue
+		}
+
+		value, err := field.Check(value)
+		if err != nil {
+			return err
+		}
+		if list, ok := value.([]any); ok && len(list) == 0 && field.Required {
+			return fmt.Errorf("%s is required", field.Name)
+		}
+		m[field.Name] = value
+	}
+	return nil
+}
+
+// IsValid returns whether the field type is one of NoteFieldTypes
+func (t NoteFieldType) IsValid() bool {
+	return slices.Contains(NoteFieldTypes, t)
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PublicLink is a note published at a public, read-only link
+type PublicLink struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	UserID    uuid.UUID `json:"user_id" db:"user_id"`
+	NoteID    uuid.UUID `json:"note_id" db:"note_id"`
+	Title     string    `json:"title"` // Title of the published note
+	URL       string    `json:"url"`   // Public link, signed so it can't be guessed
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// PublicNotePage is the public rendering of a published note
+type PublicNotePage struct {
+	Title     string
+	HTML      string            // Content rendered from Markdown, raw HTML left out
+	Backlinks []*PublicNoteLink // Other published notes that link here
+	UpdatedAt time.Time
+}
+
+// PublicNoteLink points at the public page of a published note
+type PublicNoteLink struct {
+	Title string
+	Path  string
+}
@@ -0,0 +1,18 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NoteRevision represents a snapshot of a note taken before it was updated
+type NoteRevision struct {
+	ID             uuid.UUID `json:"id" db:"id"`
+	NoteID         uuid.UUID `json:"note_id" db:"note_id"`
+	UserID         uuid.UUID `json:"user_id" db:"user_id"`
+	RevisionNumber int       `json:"revision_number" db:"revision_number"`
+	Title          string    `json:"title" db:"title"`
+	Content        string    `json:"content" db:"content"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+}
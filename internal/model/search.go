@@ -1,10 +1,25 @@
 package model
 
+import "time"
+
+// Markers wrapped around matched words in search snippets
+const (
+	HighlightStart = "<b>"
+	HighlightStop  = "</b>"
+)
+
+// Snippet fragment size, in words
+const (
+	DefaultFragmentSize = 30
+	MinFragmentSize     = 5
+	MaxFragmentSize     = 100
+)
+
 // SearchResult represents a single search result
 type SearchResult struct {
 	Note    *Note  `json:"note"`
 	Rank    float64 `json:"rank"`    // Relevance score (0-1)
-	Snippet string  `json:"snippet"` // Highlighted text excerpt
+	Snippet string  `json:"snippet"` // Text excerpt with matches wrapped in <b></b>
 }
 
 // SearchRequest represents a search request
@@ -20,9 +35,44 @@ type SearchRequest struct {
 type SearchResponse struct {
 	Query      string          `json:"query"`
 	Results    []*SearchResult `json:"results"`
+	Fuzzy      bool            `json:"fuzzy,omitempty"` // Results are titles similar to the query, nothing matched it exactly
 	Pagination *Pagination     `json:"pagination"`
 }
 
+// Search history size, in queries
+const (
+	DefaultSearchHistoryLimit = 10
+	MaxSearchHistoryLimit     = 50
+)
+
+// SearchHistoryEntry is a query the user searched for
+type SearchHistoryEntry struct {
+	Query          string    `json:"query"`
+	Count          int64     `json:"count"` // Times searched
+	LastSearchedAt time.Time `json:"last_searched_at"`
+}
+
+// SearchHistoryResponse lists a user's recent search queries, most recent first
+type SearchHistoryResponse struct {
+	Queries []*SearchHistoryEntry `json:"queries"`
+}
+
+// SemanticSearchRequest represents a semantic (embedding) search request
+type SemanticSearchRequest struct {
+	Query    string    `json:"query" validate:"required,min=1,max=2000"`
+	Limit    int       `json:"limit" validate:"omitempty,min=1,max=50"`
+	NoteType *NoteType `json:"type,omitempty"`
+	TagID    *string   `json:"tag_id,omitempty" validate:"omitempty,uuid"`
+}
+
+// SemanticSearchResponse represents a semantic search response
+// Rank is the cosine similarity between the query and the note (higher is closer).
+type SemanticSearchResponse struct {
+	Query   string          `json:"query"`
+	Model   string          `json:"model"`
+	Results []*SearchResult `json:"results"`
+}
+
 // SuggestionRequest represents an autocomplete suggestion request
 type SuggestionRequest struct {
 	Query string `query:"q" validate:"required,min=1,max=100"`
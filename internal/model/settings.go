@@ -0,0 +1,88 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Default preferences for users that haven't changed them
+const (
+	DefaultPageSize      = 20
+	DefaultTimezone      = "UTC"
+	DefaultWeekStart     = "monday"
+	DefaultTheme         = "dark"
+	DefaultDailyWordGoal = 500
+	DefaultForgottenDays = 30
+	DefaultCaptureTarget = CaptureTargetInbox
+	DefaultDigestHour    = 8
+)
+
+// UserSettings represents per-user preferences stored on the server
+type UserSettings struct {
+	UserID          uuid.UUID `json:"user_id" db:"user_id"`
+	DailyTemplate   *string   `json:"daily_template,omitempty" db:"daily_template"` // nil = default template
+	DefaultNoteType NoteType  `json:"default_note_type" db:"default_note_type"`
+	PageSize        int       `json:"page_size" db:"page_size"`
+	Timezone        string    `json:"timezone" db:"timezone"`               // IANA name, e.g. Europe/Berlin
+	WeekStart       string    `json:"week_start" db:"week_start"`           // monday or sunday
+	Theme           string    `json:"theme" db:"theme"`                     // TUI theme name
+	DailyWordGoal   int       `json:"daily_word_goal" db:"daily_word_goal"` // Words to write per day to keep a streak
+	ForgottenDays   int       `json:"forgotten_days" db:"forgotten_days"`   // Days unopened before a note is resurfaced
+	CaptureTarget   string    `json:"capture_target" db:"capture_target"`   // inbox or daily, where quick captures go
+	DigestURL       *string   `json:"digest_url,omitempty" db:"digest_url"` // Slack or Discord webhook for the daily digest, nil = off
+	DigestHour      int       `json:"digest_hour" db:"digest_hour"`         // Hour of the day the digest is posted at (0-23)
+	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// DefaultUserSettings returns the settings of a user that never changed them
+func DefaultUserSettings(userID uuid.UUID) *UserSettings {
+	return &UserSettings{
+		UserID:          userID,
+		DefaultNoteType: NoteTypeNote,
+		PageSize:        DefaultPageSize,
+		Timezone:        DefaultTimezone,
+		WeekStart:       DefaultWeekStart,
+		Theme:           DefaultTheme,
+		DailyWordGoal:   DefaultDailyWordGoal,
+		ForgottenDays:   DefaultForgottenDays,
+		CaptureTarget:   DefaultCaptureTarget,
+		DigestHour:      DefaultDigestHour,
+	}
+}
+
+// Location returns the user's timezone, falling back to UTC if it can't be loaded
+func (s *UserSettings) Location() *time.Location {
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// UpdateSettingsRequest represents a partial update of user preferences
+// Fields left out keep their current value
+type UpdateSettingsRequest struct {
+	DefaultNoteType *NoteType `json:"default_note_type" validate:"omitempty,max=50"`
+	PageSize        *int      `json:"page_size" validate:"omitempty,min=1,max=100"`
+	Timezone        *string   `json:"timezone" validate:"omitempty,min=1,max=64"`
+	WeekStart       *string   `json:"week_start" validate:"omitempty,oneof=monday sunday"`
+	Theme           *string   `json:"theme" validate:"omitempty,min=1,max=50"`
+	DailyWordGoal   *int      `json:"daily_word_goal" validate:"omitempty,min=1,max=100000"`
+	ForgottenDays   *int      `json:"forgotten_days" validate:"omitempty,min=1,max=3650"`
+	CaptureTarget   *string   `json:"capture_target" validate:"omitempty,oneof=inbox daily"`
+	DigestURL       *string   `json:"digest_url" validate:"omitempty,max=2048"` // Empty turns the digest off
+	DigestHour      *int      `json:"digest_hour" validate:"omitempty,min=0,max=23"`
+}
+
+// UpdateDailyTemplateRequest represents a daily note template update request
+// An empty template resets to the default
+type UpdateDailyTemplateRequest struct {
+	Template string `json:"template" validate:"max=100000"`
+}
+
+// DailyTemplateResponse represents the daily note template of a user
+type DailyTemplateResponse struct {
+	Template  string `json:"template"`
+	IsDefault bool   `json:"is_default"`
+}
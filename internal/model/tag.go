@@ -6,23 +6,28 @@ import (
 	"github.com/google/uuid"
 )
 
+// TagSeparator separates levels in nested tag names, e.g. "work/project"
+const TagSeparator = "/"
+
 // Tag represents a tag in the system
 type Tag struct {
-	ID        uuid.UUID `json:"id" db:"id"`
-	UserID    uuid.UUID `json:"user_id" db:"user_id"`
-	Name      string    `json:"name" db:"name"`
-	Color     *string   `json:"color,omitempty" db:"color"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	ID        uuid.UUID  `json:"id" db:"id"`
+	UserID    uuid.UUID  `json:"user_id" db:"user_id"`
+	Name      string     `json:"name" db:"name"`
+	Color     *string    `json:"color,omitempty" db:"color"`
+	ParentID  *uuid.UUID `json:"parent_id,omitempty" db:"parent_id"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
 }
 
 // TagWithCount represents a tag with note count
 type TagWithCount struct {
-	ID        uuid.UUID `json:"id" db:"id"`
-	UserID    uuid.UUID `json:"user_id" db:"user_id"`
-	Name      string    `json:"name" db:"name"`
-	Color     *string   `json:"color,omitempty" db:"color"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	NoteCount int       `json:"note_count" db:"note_count"`
+	ID        uuid.UUID  `json:"id" db:"id"`
+	UserID    uuid.UUID  `json:"user_id" db:"user_id"`
+	Name      string     `json:"name" db:"name"`
+	Color     *string    `json:"color,omitempty" db:"color"`
+	ParentID  *uuid.UUID `json:"parent_id,omitempty" db:"parent_id"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	NoteCount int        `json:"note_count" db:"note_count"`
 }
 
 // CreateTagRequest represents a tag creation request
@@ -42,8 +47,62 @@ type AddTagRequest struct {
 	TagID string `json:"tag_id" validate:"required,uuid"`
 }
 
+// Bulk tag actions
+const (
+	BulkTagAdd    = "add"
+	BulkTagRemove = "remove"
+)
+
+// BulkTagRequest represents a request to add or remove a tag on many notes
+type BulkTagRequest struct {
+	TagID   string   `json:"tag_id" validate:"required,uuid"`
+	NoteIDs []string `json:"note_ids" validate:"required,min=1,max=500,dive,uuid"`
+	Action  string   `json:"action" validate:"required,oneof=add remove"`
+}
+
+// BulkTagResponse reports how many notes a bulk tag request changed
+type BulkTagResponse struct {
+	Updated int64 `json:"updated"`
+}
+
 // TagListResponse represents a paginated list of tags
 type TagListResponse struct {
 	Tags       []*Tag      `json:"tags"`
 	Pagination *Pagination `json:"pagination"`
 }
+
+// TagTrend is where a tag's use is heading
+type TagTrend string
+
+const (
+	TagGrowing TagTrend = "growing" // Put on more notes in the recent half of the window than in the earlier half
+	TagSteady  TagTrend = "steady"
+	TagStale   TagTrend = "stale" // Not put on a note for TagStaleDays, or never
+)
+
+// TagStaleDays is how long a tag goes unused before it counts as stale
+const TagStaleDays = 90
+
+// TagAnalytics is how a user's tags are used over time and together
+type TagAnalytics struct {
+	Months []string    `json:"months"` // YYYY-MM, oldest first, the months of each tag's Monthly counts
+	Tags   []*TagStats `json:"tags"`   // Most used first
+	Pairs  []*TagPair  `json:"pairs"`  // Most shared notes first
+}
+
+// TagStats is how a tag is used over time
+type TagStats struct {
+	Tag          *Tag       `json:"tag"`
+	NoteCount    int        `json:"note_count"`
+	Monthly      []int      `json:"monthly"` // Notes tagged in each of TagAnalytics.Months
+	LastTaggedAt *time.Time `json:"last_tagged_at,omitempty"`
+	Trend        TagTrend   `json:"trend"`
+}
+
+// TagPair is two tags often put on the same notes
+type TagPair struct {
+	TagA      *Tag    `json:"tag_a"`
+	TagB      *Tag    `json:"tag_b"`
+	NoteCount int     `json:"note_count"` // Notes carrying both
+	Overlap   float64 `json:"overlap"`    // Share of the rarer tag's notes that carry the other one too
+}
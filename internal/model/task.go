@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TaskStatus filters tasks by completion
+type TaskStatus string
+
+const (
+	TaskStatusOpen TaskStatus = "open"
+	TaskStatusDone TaskStatus = "done"
+	TaskStatusAll  TaskStatus = "all"
+)
+
+// Task represents a "- [ ]" checkbox item extracted from a note
+type Task struct {
+	ID         uuid.UUID `json:"id" db:"id"`
+	UserID     uuid.UUID `json:"user_id" db:"user_id"`
+	NoteID     uuid.UUID `json:"note_id" db:"note_id"`
+	NoteTitle  string    `json:"note_title"` // Populated when listing
+	LineNumber int       `json:"line_number" db:"line_number"`
+	Content    string    `json:"content" db:"content"`
+	Completed  bool      `json:"completed" db:"completed"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// ListTasksRequest represents a task list request
+type ListTasksRequest struct {
+	Status TaskStatus `query:"status" validate:"omitempty,oneof=open done all"`
+}
@@ -6,6 +6,14 @@ import (
 	"github.com/google/uuid"
 )
 
+// Role is a user's permission level on the instance
+type Role string
+
+const (
+	RoleUser  Role = "user"
+	RoleAdmin Role = "admin" // Can administer all accounts
+)
+
 // User represents a user in the system
 type User struct {
 	ID           uuid.UUID `json:"id" db:"id"`
@@ -16,6 +24,10 @@ type User struct {
 	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
 	LastLoginAt  *time.Time `json:"last_login_at,omitempty" db:"last_login_at"`
 	IsActive     bool      `json:"is_active" db:"is_active"`
+	IsVerified   bool      `json:"is_verified" db:"is_verified"` // Email address confirmed
+	TOTPSecret   *string   `json:"-" db:"totp_secret"` // Never expose
+	TOTPEnabled  bool      `json:"totp_enabled" db:"totp_enabled"`
+	Role         Role      `json:"role" db:"role"`
 }
 
 // RegisterRequest represents a user registration request
@@ -29,6 +41,7 @@ type RegisterRequest struct {
 type LoginRequest struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required"`
+	TOTPCode string `json:"totp_code,omitempty"` // Required when 2FA is enabled
 }
 
 // AuthResponse represents an authentication response
@@ -39,6 +52,28 @@ type AuthResponse struct {
 	User         *User     `json:"user"`
 }
 
+// TOTPSetupResponse holds a new TOTP secret to add to an authenticator app
+type TOTPSetupResponse struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"` // otpauth:// URI, can be rendered as a QR code
+}
+
+// TOTPVerifyRequest represents a TOTP code confirming 2FA setup
+type TOTPVerifyRequest struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+// ChangePasswordRequest represents a password change by a signed-in user
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,min=8,max=128"`
+}
+
+// DeleteAccountRequest represents an account deletion, confirmed with the user's password
+type DeleteAccountRequest struct {
+	Password string `json:"password" validate:"required"`
+}
+
 // RefreshRequest represents a token refresh request
 type RefreshRequest struct {
 	RefreshToken string `json:"refresh_token" validate:"required"`
@@ -52,4 +87,98 @@ type RefreshToken struct {
 	ExpiresAt time.Time  `json:"expires_at" db:"expires_at"`
 	CreatedAt time.Time  `json:"created_at" db:"created_at"`
 	IsRevoked bool       `json:"is_revoked" db:"is_revoked"`
+	UserAgent string     `json:"user_agent" db:"user_agent"` // Device the token was issued to
+	IPAddress string     `json:"ip_address" db:"ip_address"`
+}
+
+// ClientInfo identifies the device making an authentication request
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
+// VerifyEmailRequest represents an email verification with a token from the verification email
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// ResendVerificationRequest represents a request for a new verification email
+type ResendVerificationRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// EmailVerificationToken represents a single-use email verification token in the database
+type EmailVerificationToken struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	UserID    uuid.UUID `json:"user_id" db:"user_id"`
+	TokenHash string    `json:"-" db:"token_hash"` // Never expose
+	ExpiresAt time.Time `json:"expires_at" db:"expires_at"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// ForgotPasswordRequest represents a request for a password reset token
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// ResetPasswordRequest represents a password reset with a token from the reset email
+type ResetPasswordRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,min=8,max=128"`
+}
+
+// PasswordResetToken represents a single-use password reset token in the database
+type PasswordResetToken struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	UserID    uuid.UUID  `json:"user_id" db:"user_id"`
+	TokenHash string     `json:"-" db:"token_hash"` // Never expose
+	ExpiresAt time.Time  `json:"expires_at" db:"expires_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty" db:"used_at"`
+}
+
+// DeviceAuthStatus is where a device authorization stands
+type DeviceAuthStatus string
+
+const (
+	DeviceAuthPending  DeviceAuthStatus = "pending" // Waiting for a user to enter the code
+	DeviceAuthApproved DeviceAuthStatus = "approved"
+	DeviceAuthDenied   DeviceAuthStatus = "denied"
+	DeviceAuthUsed     DeviceAuthStatus = "used" // Tokens were handed out to the device
+)
+
+// DeviceAuthorization is a login started on a device, completed by a signed-in user entering its code
+type DeviceAuthorization struct {
+	ID             uuid.UUID        `json:"id" db:"id"`
+	DeviceCodeHash string           `json:"-" db:"device_code_hash"` // Never expose
+	UserCode       string           `json:"user_code" db:"user_code"`
+	UserID         *uuid.UUID       `json:"user_id,omitempty" db:"user_id"`
+	Status         DeviceAuthStatus `json:"status" db:"status"`
+	UserAgent      string           `json:"user_agent" db:"user_agent"`
+	IPAddress      string           `json:"ip_address" db:"ip_address"`
+	ExpiresAt      time.Time        `json:"expires_at" db:"expires_at"`
+	LastPolledAt   *time.Time       `json:"last_polled_at,omitempty" db:"last_polled_at"`
+	CreatedAt      time.Time        `json:"created_at" db:"created_at"`
+}
+
+// DeviceCodeResponse starts a device login: the device shows the user code and the
+// verification URI, then polls with the device code
+type DeviceCodeResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"` // With the user code filled in
+	ExpiresIn               int    `json:"expires_in"`                // seconds
+	Interval                int    `json:"interval"`                  // seconds to wait between polls
+}
+
+// DeviceTokenRequest polls for the tokens of a device login
+type DeviceTokenRequest struct {
+	DeviceCode string `json:"device_code" validate:"required"`
+}
+
+// DeviceApproveRequest approves, or denies, the device login showing the user code
+type DeviceApproveRequest struct {
+	UserCode string `json:"user_code" validate:"required"`
+	Deny     bool   `json:"deny,omitempty"`
 }
@@ -0,0 +1,99 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookEvents are the event types webhooks can subscribe to
+var WebhookEvents = []EventType{
+	EventNoteCreated,
+	EventNoteUpdated,
+	EventNoteDeleted,
+	EventTagCreated,
+	EventTagUpdated,
+	EventTagDeleted,
+}
+
+// DeliveryStatus is where a webhook delivery stands
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending" // Waiting for its first attempt or a retry
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	DeliveryFailed    DeliveryStatus = "failed" // Out of attempts
+)
+
+// Webhook is a URL a user registered to receive events
+type Webhook struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	UserID    uuid.UUID `json:"user_id" db:"user_id"`
+	URL       string    `json:"url" db:"url"`
+	Events    []string  `json:"events" db:"events"`
+	Secret    string    `json:"secret,omitempty" db:"secret"` // Signs deliveries, only returned when created
+	Active    bool      `json:"active" db:"active"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// CreateWebhookRequest registers a webhook
+type CreateWebhookRequest struct {
+	URL    string   `json:"url" validate:"required,http_url,max=2048"`
+	Events []string `json:"events" validate:"required,min=1,dive,oneof=note.created note.updated note.deleted tag.created tag.updated tag.deleted"`
+}
+
+// UpdateWebhookRequest changes a webhook, fields left out are kept
+type UpdateWebhookRequest struct {
+	URL    *string  `json:"url,omitempty" validate:"omitempty,http_url,max=2048"`
+	Events []string `json:"events,omitempty" validate:"omitempty,min=1,dive,oneof=note.created note.updated note.deleted tag.created tag.updated tag.deleted"`
+	Active *bool    `json:"active,omitempty"`
+}
+
+// WebhookDelivery is one event sent, or to be sent, to a webhook
+type WebhookDelivery struct {
+	ID             uuid.UUID       `json:"id" db:"id"`
+	WebhookID      uuid.UUID       `json:"webhook_id" db:"webhook_id"`
+	Event          EventType       `json:"event" db:"event"`
+	Payload        json.RawMessage `json:"payload" db:"payload"`
+	Status         DeliveryStatus  `json:"status" db:"status"`
+	Attempts       int             `json:"attempts" db:"attempts"`
+	ResponseStatus *int            `json:"response_status,omitempty" db:"response_status"` // HTTP status of the last attempt
+	Error          *string         `json:"error,omitempty" db:"error"`                     // Why the last attempt failed
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty" db:"next_attempt_at"` // Only while pending
+	LastAttemptAt  *time.Time      `json:"last_attempt_at,omitempty" db:"last_attempt_at"`
+	CreatedAt      time.Time       `json:"created_at" db:"created_at"`
+}
+
+// QueuedDelivery is a delivery due for an attempt, with the webhook to send it to
+type QueuedDelivery struct {
+	WebhookDelivery
+	URL    string
+	Secret string
+}
+
+// WebhookPayload is the JSON body of a webhook delivery
+type WebhookPayload struct {
+	Event     EventType    `json:"event"`
+	Text      string       `json:"text"` // One-line summary, which Slack incoming webhooks post as the message
+	Note      *WebhookNote `json:"note,omitempty"`
+	Tag       *WebhookTag  `json:"tag,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// WebhookNote is the note an event is about
+type WebhookNote struct {
+	ID        uuid.UUID  `json:"id"`
+	Title     string     `json:"title,omitempty"`
+	Content   string     `json:"content,omitempty"` // Left out for encrypted notes
+	NoteType  NoteType   `json:"note_type,omitempty"`
+	Encrypted bool       `json:"encrypted,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// WebhookTag is the tag an event is about
+type WebhookTag struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name,omitempty"`
+}
@@ -13,26 +13,50 @@ import (
 )
 
 // ActivityRepository handles activity log operations
-type ActivityRepository struct {
+type ActivityRepository interface {
+	Create(ctx context.Context, activity *model.Activity) error
+	GetRecent(ctx context.Context, userID uuid.UUID, limit int) ([]*model.Activity, error)
+	GetSearchHistory(ctx context.Context, userID uuid.UUID, limit int) ([]*model.SearchHistoryEntry, error)
+	List(ctx context.Context, userID uuid.UUID, filter model.ActivityFilter) ([]*model.Activity, int64, error)
+	GetLastActivity(ctx context.Context, userID uuid.UUID) (*time.Time, error)
+	GetUserStats(ctx context.Context, userID uuid.UUID, timezone, weekStart string) (*model.UserStats, error)
+	GetActivityHeatmap(ctx context.Context, userID uuid.UUID, timezone, weekStart string, weeks int) (*model.ActivityHeatmap, error)
+	GetNoteStats(ctx context.Context, noteID uuid.UUID, timezone string, days, months int) (*model.NoteStats, error)
+	AddWordsWritten(ctx context.Context, userID uuid.UUID, timezone string, words int) error
+	GetDailyWords(ctx context.Context, userID uuid.UUID, timezone string, days int) ([]*model.DailyWords, error)
+	GetWritingStreak(ctx context.Context, userID uuid.UUID, timezone string, goal int) (*model.WritingStreak, error)
+	GetTrendingNotes(ctx context.Context, userID uuid.UUID, limit int) ([]*model.TrendingNote, error)
+	GetForgottenNotes(ctx context.Context, userID uuid.UUID, days int, limit int) ([]*model.ForgottenNote, error)
+	CountNotesCreated(ctx context.Context, userID uuid.UUID, from, to time.Time) (int, error)
+	GetWeeklyReview(ctx context.Context, userID uuid.UUID, from, to time.Time, limit int) (*model.WeeklyReview, error)
+}
+
+// activityRepository implements ActivityRepository
+type activityRepository struct {
 	db *DB
 }
 
 // NewActivityRepository creates a new activity repository
 func NewActivityRepository(db *DB) ActivityRepository {
-	return ActivityRepository{db: db}
+	if db.sqlite != nil {
+		return &sqliteActivityRepository{activityRepository: &activityRepository{db: db}}
+	}
+	return &activityRepository{db: db}
 }
 
 // Create logs a new activity
-func (r *ActivityRepository) Create(ctx context.Context, activity *model.Activity) error {
+func (r *activityRepository) Create(ctx context.Context, activity *model.Activity) error {
 	query := `
 		INSERT INTO activity_log (id, user_id, note_id, action, metadata, created_at)
 		VALUES ($1, $2, $3, $4, $5, $6)
 		RETURNING id, user_id, note_id, action, metadata, created_at
 	`
 
-	now := time.Now()
 	activity.ID = uuid.New()
-	activity.CreatedAt = now
+	// Keep a preset time, e.g. for seeded history
+	if activity.CreatedAt.IsZero() {
+		activity.CreatedAt = time.Now()
+	}
 
 	// Handle nil note_id
 	var noteID pgtype.UUID
@@ -42,7 +66,7 @@ func (r *ActivityRepository) Create(ctx context.Context, activity *model.Activit
 		noteID = pgtype.UUID{Valid: false}
 	}
 
-	err := r.db.Pool.QueryRow(ctx, query,
+	err := r.db.conn().QueryRow(ctx, query,
 		activity.ID,
 		activity.UserID,
 		noteID,
@@ -72,7 +96,7 @@ func (r *ActivityRepository) Create(ctx context.Context, activity *model.Activit
 }
 
 // GetRecent gets recent activities for a user
-func (r *ActivityRepository) GetRecent(ctx context.Context, userID uuid.UUID, limit int) ([]*model.Activity, error) {
+func (r *activityRepository) GetRecent(ctx context.Context, userID uuid.UUID, limit int) ([]*model.Activity, error) {
 	query := `
 		SELECT id, user_id, note_id, action, metadata, created_at
 		FROM activity_log
@@ -81,10 +105,114 @@ func (r *ActivityRepository) GetRecent(ctx context.Context, userID uuid.UUID, li
 		LIMIT $2
 	`
 
-	rows, err := r.db.Pool.Query(ctx, query, userID, limit)
+	rows, err := r.db.readConn().Query(ctx, query, userID, limit)
 	if err != nil {
 		return nil, fmt.Errorf("get recent activities: %w", err)
 	}
+
+	return collectActivities(rows)
+}
+
+// GetSearchHistory lists the distinct queries a user searched for, most recently searched first
+func (r *activityRepository) GetSearchHistory(ctx context.Context, userID uuid.UUID, limit int) ([]*model.SearchHistoryEntry, error) {
+	query := `
+		SELECT metadata->>'query', COUNT(*), MAX(created_at)
+		FROM activity_log
+		WHERE user_id = $1 AND action = $2 AND metadata->>'query' <> ''
+		GROUP BY metadata->>'query'
+		ORDER BY MAX(created_at) DESC
+		LIMIT $3
+	`
+
+	rows, err := r.db.readConn().Query(ctx, query, userID, model.ActionSearch, limit)
+	if err != nil {
+		return nil, fmt.Errorf("get search history: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []*model.SearchHistoryEntry{}
+	for rows.Next() {
+		entry := &model.SearchHistoryEntry{}
+		if err := rows.Scan(&entry.Query, &entry.Count, &entry.LastSearchedAt); err != nil {
+			return nil, fmt.Errorf("scan search history: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("iterate search history: %w", rows.Err())
+	}
+
+	return entries, nil
+}
+
+// List lists a user's activities matching filter, newest first, with the total count
+func (r *activityRepository) List(ctx context.Context, userID uuid.UUID, filter model.ActivityFilter) ([]*model.Activity, int64, error) {
+	clause := ""
+	args := []any{userID}
+	argPos := 2
+
+	if filter.Action != nil {
+		clause += fmt.Sprintf(" AND action = $%d", argPos)
+		args = append(args, *filter.Action)
+		argPos++
+	}
+
+	if filter.NoteID != nil {
+		clause += fmt.Sprintf(" AND note_id = $%d", argPos)
+		args = append(args, *filter.NoteID)
+		argPos++
+	}
+
+	if filter.From != nil {
+		clause += fmt.Sprintf(" AND created_at >= $%d", argPos)
+		args = append(args, *filter.From)
+		argPos++
+	}
+
+	if filter.To != nil {
+		clause += fmt.Sprintf(" AND created_at < $%d", argPos)
+		args = append(args, *filter.To)
+		argPos++
+	}
+
+	var total int64
+	err := r.db.readConn().QueryRow(ctx, "SELECT COUNT(*) FROM activity_log WHERE user_id = $1"+clause, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("count activities: %w", err)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	page := filter.Page
+	if page <= 0 {
+		page = 1
+	}
+
+	query := `
+		SELECT id, user_id, note_id, action, metadata, created_at
+		FROM activity_log
+		WHERE user_id = $1` + clause +
+		fmt.Sprintf(" ORDER BY created_at DESC LIMIT $%d OFFSET $%d", argPos, argPos+1)
+	args = append(args, limit, (page-1)*limit)
+
+	rows, err := r.db.readConn().Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list activities: %w", err)
+	}
+
+	activities, err := collectActivities(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return activities, total, nil
+}
+
+// collectActivities scans and closes rows of activities
+func collectActivities(rows pgx.Rows) ([]*model.Activity, error) {
 	defer rows.Close()
 
 	activities := []*model.Activity{}
@@ -120,7 +248,7 @@ func (r *ActivityRepository) GetRecent(ctx context.Context, userID uuid.UUID, li
 }
 
 // GetLastActivity gets the last activity timestamp for a user
-func (r *ActivityRepository) GetLastActivity(ctx context.Context, userID uuid.UUID) (*time.Time, error) {
+func (r *activityRepository) GetLastActivity(ctx context.Context, userID uuid.UUID) (*time.Time, error) {
 	query := `
 		SELECT created_at
 		FROM activity_log
@@ -130,7 +258,7 @@ func (r *ActivityRepository) GetLastActivity(ctx context.Context, userID uuid.UU
 	`
 
 	var createdAt time.Time
-	err := r.db.Pool.QueryRow(ctx, query, userID).Scan(&createdAt)
+	err := r.db.conn().QueryRow(ctx, query, userID).Scan(&createdAt)
 	if err == pgx.ErrNoRows {
 		return nil, nil
 	}
@@ -142,11 +270,17 @@ func (r *ActivityRepository) GetLastActivity(ctx context.Context, userID uuid.UU
 }
 
 // GetUserStats gets statistics for a user
-func (r *ActivityRepository) GetUserStats(ctx context.Context, userID uuid.UUID) (*model.UserStats, error) {
+// "Today" and "this week" are counted in the user's timezone, with weeks starting on weekStart.
+func (r *activityRepository) GetUserStats(ctx context.Context, userID uuid.UUID, timezone, weekStart string) (*model.UserStats, error) {
 	stats := &model.UserStats{}
 
+	today, week, err := localToday(timezone, weekStart)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get total notes (non-deleted)
-	err := r.db.Pool.QueryRow(ctx, `
+	err = r.db.readConn().QueryRow(ctx, `
 		SELECT COUNT(*) FROM notes WHERE user_id = $1 AND is_deleted = false
 	`, userID).Scan(&stats.TotalNotes)
 	if err != nil {
@@ -154,7 +288,7 @@ func (r *ActivityRepository) GetUserStats(ctx context.Context, userID uuid.UUID)
 	}
 
 	// Get total tags
-	err = r.db.Pool.QueryRow(ctx, `
+	err = r.db.readConn().QueryRow(ctx, `
 		SELECT COUNT(*) FROM tags WHERE user_id = $1
 	`, userID).Scan(&stats.TotalTags)
 	if err != nil {
@@ -162,7 +296,7 @@ func (r *ActivityRepository) GetUserStats(ctx context.Context, userID uuid.UUID)
 	}
 
 	// Get total links
-	err = r.db.Pool.QueryRow(ctx, `
+	err = r.db.readConn().QueryRow(ctx, `
 		SELECT COUNT(*) FROM links WHERE user_id = $1
 	`, userID).Scan(&stats.TotalLinks)
 	if err != nil {
@@ -170,29 +304,53 @@ func (r *ActivityRepository) GetUserStats(ctx context.Context, userID uuid.UUID)
 	}
 
 	// Get total words
-	err = r.db.Pool.QueryRow(ctx, `
+	err = r.db.readConn().QueryRow(ctx, `
 		SELECT COALESCE(SUM(word_count), 0) FROM notes WHERE user_id = $1 AND is_deleted = false
 	`, userID).Scan(&stats.TotalWords)
 	if err != nil {
 		return nil, fmt.Errorf("get total words: %w", err)
 	}
 
+	// Get notes per type
+	rows, err := r.db.readConn().Query(ctx, `
+		SELECT note_type, COUNT(*) FROM notes
+		WHERE user_id = $1 AND is_deleted = false
+		GROUP BY note_type
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get notes by type: %w", err)
+	}
+	defer rows.Close()
+
+	stats.NotesByType = make(map[model.NoteType]int64)
+	for rows.Next() {
+		var noteType model.NoteType
+		var count int64
+		if err := rows.Scan(&noteType, &count); err != nil {
+			return nil, fmt.Errorf("scan notes by type: %w", err)
+		}
+		stats.NotesByType[noteType] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get notes by type: %w", err)
+	}
+
 	// Get notes created today
-	err = r.db.Pool.QueryRow(ctx, `
+	err = r.db.readConn().QueryRow(ctx, `
 		SELECT COUNT(*) FROM notes
 		WHERE user_id = $1 AND is_deleted = false
-		AND DATE(created_at) = CURRENT_DATE
-	`, userID).Scan(&stats.NotesCreatedToday)
+		AND created_at >= $2
+	`, userID, today).Scan(&stats.NotesCreatedToday)
 	if err != nil {
 		return nil, fmt.Errorf("get notes created today: %w", err)
 	}
 
 	// Get notes created this week
-	err = r.db.Pool.QueryRow(ctx, `
+	err = r.db.readConn().QueryRow(ctx, `
 		SELECT COUNT(*) FROM notes
 		WHERE user_id = $1 AND is_deleted = false
-		AND created_at >= DATE_TRUNC('week', CURRENT_DATE)
-	`, userID).Scan(&stats.NotesCreatedWeek)
+		AND created_at >= $2
+	`, userID, week).Scan(&stats.NotesCreatedWeek)
 	if err != nil {
 		return nil, fmt.Errorf("get notes created this week: %w", err)
 	}
@@ -203,8 +361,285 @@ func (r *ActivityRepository) GetUserStats(ctx context.Context, userID uuid.UUID)
 	return stats, nil
 }
 
+// localToday returns the start of today in timezone, and the start of the week it's in
+// Weeks start on Monday, or on Sunday when weekStart is sunday.
+func localToday(timezone, weekStart string) (today, week time.Time, err error) {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("load timezone: %w", err)
+	}
+
+	now := time.Now().In(loc)
+	today = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	daysIntoWeek := (int(today.Weekday()) + 6) % 7
+	if weekStart == "sunday" {
+		daysIntoWeek = int(today.Weekday())
+	}
+	return today, today.AddDate(0, 0, -daysIntoWeek), nil
+}
+
+// GetActivityHeatmap counts the notes created or updated on each day of the last weeks weeks
+// The first day starts a week (Monday, or Sunday when weekStart is sunday) and the last is today.
+func (r *activityRepository) GetActivityHeatmap(ctx context.Context, userID uuid.UUID, timezone, weekStart string, weeks int) (*model.ActivityHeatmap, error) {
+	// DATE_TRUNC('week') starts weeks on Monday, $3 shifts them by a day for Sunday weeks
+	query := `
+		WITH bounds AS (
+			SELECT DATE(DATE_TRUNC('week', NOW() AT TIME ZONE $2 + make_interval(days => $3))) - $3 - ($4::int - 1) * 7 AS first_day,
+			       DATE(NOW() AT TIME ZONE $2) AS last_day
+		),
+		counts AS (
+			SELECT DATE(a.created_at AT TIME ZONE $2) AS day, COUNT(*) AS count
+			FROM activity_log a, bounds
+			WHERE a.user_id = $1
+			  AND a.action IN ('create', 'update')
+			  AND a.created_at >= (bounds.first_day::timestamp AT TIME ZONE $2)
+			GROUP BY 1
+		)
+		SELECT TO_CHAR(d.day, 'YYYY-MM-DD'), COALESCE(c.count, 0)
+		FROM bounds, generate_series(bounds.first_day, bounds.last_day, INTERVAL '1 day') AS d(day)
+		LEFT JOIN counts c ON c.day = d.day::date
+		ORDER BY d.day
+	`
+
+	return r.heatmap(ctx, query, userID, timezone, weekStart, weeks)
+}
+
+// heatmap runs a heatmap query taking the user, timezone, week shift and number of weeks
+func (r *activityRepository) heatmap(ctx context.Context, query string, userID uuid.UUID, timezone, weekStart string, weeks int) (*model.ActivityHeatmap, error) {
+	weekShift := 0
+	if weekStart == "sunday" {
+		weekShift = 1
+	}
+
+	rows, err := r.db.readConn().Query(ctx, query, userID, timezone, weekShift, weeks)
+	if err != nil {
+		return nil, fmt.Errorf("get activity heatmap: %w", err)
+	}
+	defer rows.Close()
+
+	heatmap := &model.ActivityHeatmap{WeekStart: weekStart, Days: []*model.HeatmapDay{}}
+	for rows.Next() {
+		day := &model.HeatmapDay{}
+		if err := rows.Scan(&day.Date, &day.Count); err != nil {
+			return nil, fmt.Errorf("scan heatmap day: %w", err)
+		}
+		heatmap.Days = append(heatmap.Days, day)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("iterate heatmap days: %w", rows.Err())
+	}
+
+	return heatmap, nil
+}
+
+// GetNoteStats counts the edits and links of a note, and its views on each of the last days days
+// and months months, oldest first. Views and edits come from the activity log; the caller fills in
+// the totals kept on the note itself.
+func (r *activityRepository) GetNoteStats(ctx context.Context, noteID uuid.UUID, timezone string, days, months int) (*model.NoteStats, error) {
+	return r.noteStats(ctx, noteStatsQueries{
+		// Links to and from deleted notes don't count, as in the note list's link counts
+		counts: `
+		SELECT
+			(SELECT COUNT(*) FROM activity_log WHERE note_id = $1 AND action = $2),
+			(SELECT COUNT(*) FROM links l INNER JOIN notes tn ON tn.id = l.target_note_id AND tn.is_deleted = false
+			 WHERE l.source_note_id = $1),
+			(SELECT COUNT(*) FROM links l INNER JOIN notes sn ON sn.id = l.source_note_id AND sn.is_deleted = false
+			 WHERE l.target_note_id = $1),
+			(SELECT COUNT(*) FROM activity_log WHERE note_id = $1 AND action = $3),
+			(SELECT COALESCE(SUM((metadata->>$4::text)::bigint), 0) FROM activity_log WHERE note_id = $1 AND action = $3)
+	`,
+		daily: `
+		WITH views AS (
+			SELECT DATE(created_at AT TIME ZONE $2) AS day, COUNT(*) AS count
+			FROM activity_log
+			WHERE note_id = $1 AND action = $4 AND created_at >= NOW() - make_interval(days => $3::int + 1)
+			GROUP BY 1
+		)
+		SELECT TO_CHAR(d.day, 'YYYY-MM-DD'), COALESCE(v.count, 0)
+		FROM generate_series(
+			DATE(NOW() AT TIME ZONE $2) - ($3::int - 1),
+			DATE(NOW() AT TIME ZONE $2),
+			INTERVAL '1 day'
+		) AS d(day)
+		LEFT JOIN views v ON v.day = d.day::date
+		ORDER BY d.day
+	`,
+		monthly: `
+		WITH bounds AS (
+			SELECT DATE_TRUNC('month', NOW() AT TIME ZONE $2) AS last_month
+		),
+		views AS (
+			SELECT DATE_TRUNC('month', a.created_at AT TIME ZONE $2) AS month, COUNT(*) AS count
+			FROM activity_log a, bounds
+			WHERE a.note_id = $1 AND a.action = $4
+			  AND a.created_at >= ((bounds.last_month - make_interval(months => $3::int - 1)) AT TIME ZONE $2)
+			GROUP BY 1
+		)
+		SELECT TO_CHAR(m.month, 'YYYY-MM'), COALESCE(v.count, 0)
+		FROM bounds, generate_series(bounds.last_month - make_interval(months => $3::int - 1), bounds.last_month, INTERVAL '1 month') AS m(month)
+		LEFT JOIN views v ON v.month = m.month
+		ORDER BY m.month
+	`,
+	}, noteID, timezone, days, months)
+}
+
+// noteStatsQueries are the queries of GetNoteStats
+// counts takes the note, the update and focus actions and the focus duration key; daily and
+// monthly take the note, timezone, number of days or months and the view action.
+type noteStatsQueries struct {
+	counts, daily, monthly string
+}
+
+// noteStats runs the queries of GetNoteStats
+func (r *activityRepository) noteStats(ctx context.Context, queries noteStatsQueries, noteID uuid.UUID, timezone string, days, months int) (*model.NoteStats, error) {
+	stats := &model.NoteStats{NoteID: noteID, DailyViews: []*model.DayCount{}, MonthlyViews: []*model.MonthCount{}}
+
+	err := r.db.readConn().QueryRow(ctx, queries.counts, noteID, model.ActionUpdate, model.ActionFocus, model.FocusDurationKey).Scan(
+		&stats.EditCount, &stats.OutgoingLinks, &stats.IncomingLinks, &stats.FocusSessions, &stats.FocusSeconds,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("count note edits, links and focus sessions: %w", err)
+	}
+
+	rows, err := r.db.readConn().Query(ctx, queries.daily, noteID, timezone, days, model.ActionView)
+	if err != nil {
+		return nil, fmt.Errorf("get daily note views: %w", err)
+	}
+	for rows.Next() {
+		day := &model.DayCount{}
+		if err := rows.Scan(&day.Date, &day.Count); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan daily note views: %w", err)
+		}
+		stats.DailyViews = append(stats.DailyViews, day)
+	}
+	rows.Close()
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("iterate daily note views: %w", rows.Err())
+	}
+
+	rows, err = r.db.readConn().Query(ctx, queries.monthly, noteID, timezone, months, model.ActionView)
+	if err != nil {
+		return nil, fmt.Errorf("get monthly note views: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		month := &model.MonthCount{}
+		if err := rows.Scan(&month.Month, &month.Count); err != nil {
+			return nil, fmt.Errorf("scan monthly note views: %w", err)
+		}
+		stats.MonthlyViews = append(stats.MonthlyViews, month)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("iterate monthly note views: %w", rows.Err())
+	}
+
+	return stats, nil
+}
+
+// AddWordsWritten adds words to what the user wrote today, in their timezone
+func (r *activityRepository) AddWordsWritten(ctx context.Context, userID uuid.UUID, timezone string, words int) error {
+	query := `
+		INSERT INTO daily_words (user_id, day, words)
+		VALUES ($1, DATE(NOW() AT TIME ZONE $2), $3)
+		ON CONFLICT (user_id, day) DO UPDATE
+		SET words = daily_words.words + EXCLUDED.words
+	`
+
+	_, err := r.db.conn().Exec(ctx, query, userID, timezone, words)
+	if err != nil {
+		return fmt.Errorf("add words written: %w", err)
+	}
+
+	return nil
+}
+
+// GetDailyWords gets the words written on each of the last days days, oldest first
+// Days without writing are included with zero words.
+func (r *activityRepository) GetDailyWords(ctx context.Context, userID uuid.UUID, timezone string, days int) ([]*model.DailyWords, error) {
+	query := `
+		SELECT TO_CHAR(d.day, 'YYYY-MM-DD'), COALESCE(w.words, 0)
+		FROM generate_series(
+			DATE(NOW() AT TIME ZONE $2) - ($3::int - 1),
+			DATE(NOW() AT TIME ZONE $2),
+			INTERVAL '1 day'
+		) AS d(day)
+		LEFT JOIN daily_words w ON w.user_id = $1 AND w.day = d.day::date
+		ORDER BY d.day
+	`
+
+	return r.dailyWords(ctx, query, userID, timezone, days)
+}
+
+// dailyWords runs a daily words query taking the user, timezone and number of days
+func (r *activityRepository) dailyWords(ctx context.Context, query string, userID uuid.UUID, timezone string, days int) ([]*model.DailyWords, error) {
+	rows, err := r.db.readConn().Query(ctx, query, userID, timezone, days)
+	if err != nil {
+		return nil, fmt.Errorf("get daily words: %w", err)
+	}
+	defer rows.Close()
+
+	result := []*model.DailyWords{}
+	for rows.Next() {
+		day := &model.DailyWords{}
+		if err := rows.Scan(&day.Date, &day.Words); err != nil {
+			return nil, fmt.Errorf("scan daily words: %w", err)
+		}
+		result = append(result, day)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("iterate daily words: %w", rows.Err())
+	}
+
+	return result, nil
+}
+
+// GetWritingStreak gets the current and longest runs of days on which the user wrote at least goal words
+// The current streak is still running if its last day is yesterday, since today isn't over yet.
+func (r *activityRepository) GetWritingStreak(ctx context.Context, userID uuid.UUID, timezone string, goal int) (*model.WritingStreak, error) {
+	// Consecutive days share day - row_number, which groups them into runs
+	query := `
+		WITH today AS (
+			SELECT DATE(NOW() AT TIME ZONE $2) AS day
+		),
+		runs AS (
+			SELECT MAX(day) AS last_day, COUNT(*) AS length
+			FROM (
+				SELECT day, day - (ROW_NUMBER() OVER (ORDER BY day))::int AS run
+				FROM daily_words
+				WHERE user_id = $1 AND words >= $3
+			) met
+			GROUP BY run
+		)
+		SELECT
+			COALESCE((SELECT length FROM runs, today WHERE runs.last_day >= today.day - 1), 0),
+			COALESCE((SELECT MAX(length) FROM runs), 0),
+			COALESCE((SELECT words FROM daily_words, today WHERE user_id = $1 AND daily_words.day = today.day), 0)
+	`
+
+	return r.writingStreak(ctx, query, userID, timezone, goal)
+}
+
+// writingStreak runs a writing streak query taking the user, timezone and goal
+func (r *activityRepository) writingStreak(ctx context.Context, query string, userID uuid.UUID, timezone string, goal int) (*model.WritingStreak, error) {
+	streak := &model.WritingStreak{DailyGoal: goal}
+	err := r.db.readConn().QueryRow(ctx, query, userID, timezone, goal).Scan(
+		&streak.CurrentStreak,
+		&streak.LongestStreak,
+		&streak.WordsToday,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get writing streak: %w", err)
+	}
+	streak.GoalMetToday = streak.WordsToday >= goal
+
+	return streak, nil
+}
+
 // GetTrendingNotes gets frequently accessed notes
-func (r *ActivityRepository) GetTrendingNotes(ctx context.Context, userID uuid.UUID, limit int) ([]*model.TrendingNote, error) {
+func (r *activityRepository) GetTrendingNotes(ctx context.Context, userID uuid.UUID, limit int) ([]*model.TrendingNote, error) {
 	query := `
 		SELECT id, user_id, title, access_count, last_accessed_at
 		FROM notes
@@ -213,7 +648,7 @@ func (r *ActivityRepository) GetTrendingNotes(ctx context.Context, userID uuid.U
 		LIMIT $2
 	`
 
-	rows, err := r.db.Pool.Query(ctx, query, userID, limit)
+	rows, err := r.db.readConn().Query(ctx, query, userID, limit)
 	if err != nil {
 		return nil, fmt.Errorf("get trending notes: %w", err)
 	}
@@ -247,17 +682,18 @@ func (r *ActivityRepository) GetTrendingNotes(ctx context.Context, userID uuid.U
 }
 
 // GetForgottenNotes gets notes that haven't been accessed in a while
-func (r *ActivityRepository) GetForgottenNotes(ctx context.Context, userID uuid.UUID, days int, limit int) ([]*model.ForgottenNote, error) {
+func (r *activityRepository) GetForgottenNotes(ctx context.Context, userID uuid.UUID, days int, limit int) ([]*model.ForgottenNote, error) {
 	query := `
 		SELECT id, user_id, title, COALESCE(last_accessed_at, created_at) as last_accessed
 		FROM notes
 		WHERE user_id = $1 AND is_deleted = false
-		AND (last_accessed_at < NOW() - INTERVAL '1 day' * $2 OR last_accessed_at IS NULL)
+		AND (last_accessed_at < $2 OR last_accessed_at IS NULL)
 		ORDER BY last_accessed ASC
 		LIMIT $3
 	`
 
-	rows, err := r.db.Pool.Query(ctx, query, userID, days, limit)
+	cutoff := time.Now().AddDate(0, 0, -days)
+	rows, err := r.db.readConn().Query(ctx, query, userID, cutoff, limit)
 	if err != nil {
 		return nil, fmt.Errorf("get forgotten notes: %w", err)
 	}
@@ -292,3 +728,160 @@ func (r *ActivityRepository) GetForgottenNotes(ctx context.Context, userID uuid.
 
 	return forgotten, nil
 }
+
+// CountNotesCreated counts the notes a user created from from (inclusive) to to (exclusive)
+func (r *activityRepository) CountNotesCreated(ctx context.Context, userID uuid.UUID, from, to time.Time) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM notes
+		WHERE user_id = $1 AND is_deleted = false
+		AND created_at >= $2 AND created_at < $3
+	`
+
+	var count int
+	if err := r.db.readConn().QueryRow(ctx, query, userID, from, to).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count notes created: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetWeeklyReview collects what a user did with their notes from from (inclusive) to to (exclusive)
+// Each list holds at most limit entries. Forgotten notes are left for the caller to add.
+func (r *activityRepository) GetWeeklyReview(ctx context.Context, userID uuid.UUID, from, to time.Time, limit int) (*model.WeeklyReview, error) {
+	review := &model.WeeklyReview{}
+
+	// Notes created during the week
+	rows, err := r.db.readConn().Query(ctx, `
+		SELECT id, user_id, title, note_type, word_count, created_at, updated_at
+		FROM notes
+		WHERE user_id = $1 AND is_deleted = false
+		AND created_at >= $2 AND created_at < $3
+		ORDER BY created_at ASC
+		LIMIT $4
+	`, userID, from, to, limit)
+	if err != nil {
+		return nil, fmt.Errorf("get notes created: %w", err)
+	}
+	review.NotesCreated, err = collectReviewNotes(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	// Older notes updated during the week, from the activity log
+	rows, err = r.db.readConn().Query(ctx, `
+		SELECT n.id, n.user_id, n.title, n.note_type, n.word_count, n.created_at, n.updated_at
+		FROM notes n
+		JOIN activity_log a ON a.note_id = n.id
+		WHERE n.user_id = $1 AND n.is_deleted = false AND n.created_at < $2
+		AND a.action = $4 AND a.created_at >= $2 AND a.created_at < $3
+		GROUP BY n.id
+		ORDER BY MAX(a.created_at) DESC
+		LIMIT $5
+	`, userID, from, to, model.ActionUpdate, limit)
+	if err != nil {
+		return nil, fmt.Errorf("get notes updated: %w", err)
+	}
+	review.NotesUpdated, err = collectReviewNotes(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	// Tags of the notes created or updated during the week
+	rows, err = r.db.readConn().Query(ctx, `
+		SELECT t.name, COUNT(*) AS note_count
+		FROM tags t
+		JOIN note_tags nt ON nt.tag_id = t.id
+		JOIN notes n ON n.id = nt.note_id
+		WHERE t.user_id = $1 AND n.is_deleted = false
+		AND n.updated_at >= $2 AND n.updated_at < $3
+		GROUP BY t.name
+		ORDER BY note_count DESC, t.name ASC
+		LIMIT $4
+	`, userID, from, to, limit)
+	if err != nil {
+		return nil, fmt.Errorf("get top tags: %w", err)
+	}
+	defer rows.Close()
+
+	review.TopTags = []*model.TagUsage{}
+	for rows.Next() {
+		tag := &model.TagUsage{}
+		if err := rows.Scan(&tag.Name, &tag.NoteCount); err != nil {
+			return nil, fmt.Errorf("scan top tag: %w", err)
+		}
+		review.TopTags = append(review.TopTags, tag)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("iterate top tags: %w", rows.Err())
+	}
+
+	// Tasks are extracted again on every save, so a completed task dated this
+	// week was ticked in a note edited during the week
+	rows, err = r.db.readConn().Query(ctx, `
+		SELECT t.id, t.user_id, t.note_id, n.title, t.line_number, t.content, t.completed, t.created_at
+		FROM tasks t
+		JOIN notes n ON n.id = t.note_id
+		WHERE t.user_id = $1 AND n.is_deleted = false AND t.completed = true
+		AND t.created_at >= $2 AND t.created_at < $3
+		ORDER BY n.title ASC, t.line_number ASC
+		LIMIT $4
+	`, userID, from, to, limit)
+	if err != nil {
+		return nil, fmt.Errorf("get completed tasks: %w", err)
+	}
+	defer rows.Close()
+
+	review.CompletedTasks = []*model.Task{}
+	for rows.Next() {
+		task := &model.Task{}
+		err := rows.Scan(
+			&task.ID,
+			&task.UserID,
+			&task.NoteID,
+			&task.NoteTitle,
+			&task.LineNumber,
+			&task.Content,
+			&task.Completed,
+			&task.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan completed task: %w", err)
+		}
+		review.CompletedTasks = append(review.CompletedTasks, task)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("iterate completed tasks: %w", rows.Err())
+	}
+
+	return review, nil
+}
+
+// collectReviewNotes scans and closes rows of weekly review notes
+func collectReviewNotes(rows pgx.Rows) ([]*model.Note, error) {
+	defer rows.Close()
+
+	notes := []*model.Note{}
+	for rows.Next() {
+		note := &model.Note{}
+		err := rows.Scan(
+			&note.ID,
+			&note.UserID,
+			&note.Title,
+			&note.NoteType,
+			&note.WordCount,
+			&note.CreatedAt,
+			&note.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan review note: %w", err)
+		}
+		notes = append(notes, note)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("iterate review notes: %w", rows.Err())
+	}
+
+	return notes, nil
+}
@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+// sqliteActivityRepository is the ActivityRepository of SQLite databases
+// Days and months are counted in the user's timezone with local_day and local_month, and
+// the series of days are generated by recursive CTEs. Stored times are UTC, so the rough
+// created_at bounds are a day wider than the local ones.
+type sqliteActivityRepository struct {
+	*activityRepository
+}
+
+// GetActivityHeatmap counts the notes created or updated on each day of the last weeks weeks
+// The first day starts a week (Monday, or Sunday when weekStart is sunday) and the last is today.
+func (r *sqliteActivityRepository) GetActivityHeatmap(ctx context.Context, userID uuid.UUID, timezone, weekStart string, weeks int) (*model.ActivityHeatmap, error) {
+	// %w is 0 on Sundays, so (%w + 6 + $3) % 7 is the number of days since the week started
+	query := `
+		WITH RECURSIVE bounds AS (
+			SELECT date(today, '-' || ((CAST(strftime('%w', today) AS INTEGER) + 6 + $3) % 7 + ($4 - 1) * 7) || ' days') AS first_day,
+			       today AS last_day
+			FROM (SELECT local_day(NOW(), $2) AS today)
+		),
+		days(day) AS (
+			SELECT first_day FROM bounds WHERE first_day <= last_day
+			UNION ALL
+			SELECT date(day, '+1 day') FROM days, bounds WHERE day < last_day
+		),
+		counts AS (
+			SELECT local_day(a.created_at, $2) AS day, COUNT(*) AS count
+			FROM activity_log a, bounds
+			WHERE a.user_id = $1
+			  AND a.action IN ('create', 'update')
+			  AND a.created_at >= date(bounds.first_day, '-1 day')
+			GROUP BY 1
+		)
+		SELECT d.day, COALESCE(c.count, 0)
+		FROM days d
+		LEFT JOIN counts c ON c.day = d.day
+		ORDER BY d.day
+	`
+
+	return r.heatmap(ctx, query, userID, timezone, weekStart, weeks)
+}
+
+// GetNoteStats counts the edits and links of a note, and its views on each of the last days days
+// and months months, oldest first
+func (r *sqliteActivityRepository) GetNoteStats(ctx context.Context, noteID uuid.UUID, timezone string, days, months int) (*model.NoteStats, error) {
+	return r.noteStats(ctx, noteStatsQueries{
+		// Links to and from deleted notes don't count, as in the note list's link counts
+		counts: `
+		SELECT
+			(SELECT COUNT(*) FROM activity_log WHERE note_id = $1 AND action = $2),
+			(SELECT COUNT(*) FROM links l INNER JOIN notes tn ON tn.id = l.target_note_id AND tn.is_deleted = false
+			 WHERE l.source_note_id = $1),
+			(SELECT COUNT(*) FROM links l INNER JOIN notes sn ON sn.id = l.source_note_id AND sn.is_deleted = false
+			 WHERE l.target_note_id = $1),
+			(SELECT COUNT(*) FROM activity_log WHERE note_id = $1 AND action = $3),
+			(SELECT COALESCE(SUM(CAST(metadata ->> $4 AS INTEGER)), 0) FROM activity_log WHERE note_id = $1 AND action = $3)
+	`,
+		daily: `
+		WITH RECURSIVE bounds AS (
+			SELECT local_day(NOW(), $2) AS last_day
+		),
+		days(day) AS (
+			SELECT date(last_day, '-' || ($3 - 1) || ' days') FROM bounds WHERE $3 > 0
+			UNION ALL
+			SELECT date(day, '+1 day') FROM days, bounds WHERE day < last_day
+		),
+		views AS (
+			SELECT local_day(created_at, $2) AS day, COUNT(*) AS count
+			FROM activity_log
+			WHERE note_id = $1 AND action = $4 AND created_at >= date(NOW(), '-' || ($3 + 1) || ' days')
+			GROUP BY 1
+		)
+		SELECT d.day, COALESCE(v.count, 0)
+		FROM days d
+		LEFT JOIN views v ON v.day = d.day
+		ORDER BY d.day
+	`,
+		monthly: `
+		WITH RECURSIVE bounds AS (
+			SELECT substr(local_day(NOW(), $2), 1, 7) || '-01' AS last_month
+		),
+		months(month) AS (
+			SELECT date(last_month, '-' || ($3 - 1) || ' months') FROM bounds WHERE $3 > 0
+			UNION ALL
+			SELECT date(month, '+1 month') FROM months, bounds WHERE month < last_month
+		),
+		views AS (
+			SELECT local_month(a.created_at, $2) AS month, COUNT(*) AS count
+			FROM activity_log a, bounds
+			WHERE a.note_id = $1 AND a.action = $4
+			  AND a.created_at >= date(bounds.last_month, '-' || $3 || ' months')
+			GROUP BY 1
+		)
+		SELECT substr(m.month, 1, 7), COALESCE(v.count, 0)
+		FROM months m
+		LEFT JOIN views v ON v.month = substr(m.month, 1, 7)
+		ORDER BY m.month
+	`,
+	}, noteID, timezone, days, months)
+}
+
+// AddWordsWritten adds words to what the user wrote today, in their timezone
+func (r *sqliteActivityRepository) AddWordsWritten(ctx context.Context, userID uuid.UUID, timezone string, words int) error {
+	query := `
+		INSERT INTO daily_words (user_id, day, words)
+		VALUES ($1, local_day(NOW(), $2), $3)
+		ON CONFLICT (user_id, day) DO UPDATE
+		SET words = daily_words.words + EXCLUDED.words
+	`
+
+	_, err := r.db.conn().Exec(ctx, query, userID, timezone, words)
+	if err != nil {
+		return fmt.Errorf("add words written: %w", err)
+	}
+
+	return nil
+}
+
+// GetDailyWords gets the words written on each of the last days days, oldest first
+// Days without writing are included with zero words.
+func (r *sqliteActivityRepository) GetDailyWords(ctx context.Context, userID uuid.UUID, timezone string, days int) ([]*model.DailyWords, error) {
+	query := `
+		WITH RECURSIVE bounds AS (
+			SELECT local_day(NOW(), $2) AS last_day
+		),
+		days(day) AS (
+			SELECT date(last_day, '-' || ($3 - 1) || ' days') FROM bounds WHERE $3 > 0
+			UNION ALL
+			SELECT date(day, '+1 day') FROM days, bounds WHERE day < last_day
+		)
+		SELECT d.day, COALESCE(w.words, 0)
+		FROM days d
+		LEFT JOIN daily_words w ON w.user_id = $1 AND w.day = d.day
+		ORDER BY d.day
+	`
+
+	return r.dailyWords(ctx, query, userID, timezone, days)
+}
+
+// GetWritingStreak gets the current and longest runs of days on which the user wrote at least goal words
+// The current streak is still running if its last day is yesterday, since today isn't over yet.
+func (r *sqliteActivityRepository) GetWritingStreak(ctx context.Context, userID uuid.UUID, timezone string, goal int) (*model.WritingStreak, error) {
+	// Consecutive days share julianday(day) - row_number, which groups them into runs
+	query := `
+		WITH today AS (
+			SELECT local_day(NOW(), $2) AS day
+		),
+		runs AS (
+			SELECT MAX(day) AS last_day, COUNT(*) AS length
+			FROM (
+				SELECT day, julianday(day) - ROW_NUMBER() OVER (ORDER BY day) AS run
+				FROM daily_words
+				WHERE user_id = $1 AND words >= $3
+			) met
+			GROUP BY run
+		)
+		SELECT
+			COALESCE((SELECT length FROM runs, today WHERE runs.last_day >= date(today.day, '-1 day')), 0),
+			COALESCE((SELECT MAX(length) FROM runs), 0),
+			COALESCE((SELECT words FROM daily_words, today WHERE user_id = $1 AND daily_words.day = today.day), 0)
+	`
+
+	return r.writingStreak(ctx, query, userID, timezone, goal)
+}
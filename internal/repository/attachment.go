@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+// AttachmentRepository handles attachment data operations
+type AttachmentRepository struct {
+	db *DB
+}
+
+// NewAttachmentRepository creates a new attachment repository
+func NewAttachmentRepository(db *DB) AttachmentRepository {
+	return AttachmentRepository{db: db}
+}
+
+// Create inserts a new attachment (ID and storage key are set by the caller)
+func (r *AttachmentRepository) Create(ctx context.Context, attachment *model.Attachment) error {
+	query := `
+		INSERT INTO attachments (id, user_id, note_id, filename, content_type, size_bytes, storage_key, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	attachment.CreatedAt = time.Now()
+
+	_, err := r.db.conn().Exec(ctx, query,
+		attachment.ID,
+		attachment.UserID,
+		attachment.NoteID,
+		attachment.Filename,
+		attachment.ContentType,
+		attachment.SizeBytes,
+		attachment.StorageKey,
+		attachment.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("create attachment: %w", err)
+	}
+
+	return nil
+}
+
+// FindByID finds an attachment of a note by ID
+func (r *AttachmentRepository) FindByID(ctx context.Context, userID, noteID, id uuid.UUID) (*model.Attachment, error) {
+	query := `
+		SELECT id, user_id, note_id, filename, content_type, size_bytes, storage_key, created_at
+		FROM attachments
+		WHERE id = $1 AND note_id = $2 AND user_id = $3
+	`
+
+	attachment := &model.Attachment{}
+	err := r.db.conn().QueryRow(ctx, query, id, noteID, userID).Scan(
+		&attachment.ID,
+		&attachment.UserID,
+		&attachment.NoteID,
+		&attachment.Filename,
+		&attachment.ContentType,
+		&attachment.SizeBytes,
+		&attachment.StorageKey,
+		&attachment.CreatedAt,
+	)
+
+	if err == pgx.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find attachment by id: %w", err)
+	}
+
+	return attachment, nil
+}
+
+// ListByNote lists the attachments of a note, oldest first
+func (r *AttachmentRepository) ListByNote(ctx context.Context, userID, noteID uuid.UUID) ([]*model.Attachment, error) {
+	query := `
+		SELECT id, user_id, note_id, filename, content_type, size_bytes, storage_key, created_at
+		FROM attachments
+		WHERE note_id = $1 AND user_id = $2
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.conn().Query(ctx, query, noteID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list attachments: %w", err)
+	}
+	defer rows.Close()
+
+	attachments := []*model.Attachment{}
+	for rows.Next() {
+		attachment := &model.Attachment{}
+		err := rows.Scan(
+			&attachment.ID,
+			&attachment.UserID,
+			&attachment.NoteID,
+			&attachment.Filename,
+			&attachment.ContentType,
+			&attachment.SizeBytes,
+			&attachment.StorageKey,
+			&attachment.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan attachment: %w", err)
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("iterate attachments: %w", rows.Err())
+	}
+
+	return attachments, nil
+}
+
+// Delete deletes an attachment
+func (r *AttachmentRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	query := `DELETE FROM attachments WHERE id = $1 AND user_id = $2`
+
+	result, err := r.db.conn().Exec(ctx, query, id, userID)
+	if err != nil {
+		return fmt.Errorf("delete attachment: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+// AuditRepository handles audit log data operations
+type AuditRepository interface {
+	Create(ctx context.Context, entry *model.AuditEntry) error
+	List(ctx context.Context, filter model.AuditFilter) ([]*model.AuditEntry, int64, error)
+}
+
+// auditRepository implements AuditRepository
+type auditRepository struct {
+	db *DB
+}
+
+// NewAuditRepository creates a new audit log repository
+func NewAuditRepository(db *DB) AuditRepository {
+	return &auditRepository{db: db}
+}
+
+// Create inserts a new audit log entry
+func (r *auditRepository) Create(ctx context.Context, entry *model.AuditEntry) error {
+	query := `
+		INSERT INTO audit_log (id, user_id, event, email, ip_address, user_agent, metadata, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	entry.ID = uuid.New()
+	entry.CreatedAt = time.Now()
+
+	_, err := r.db.conn().Exec(ctx, query,
+		entry.ID,
+		entry.UserID,
+		entry.Event,
+		entry.Email,
+		entry.IPAddress,
+		entry.UserAgent,
+		entry.Metadata,
+		entry.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("create audit entry: %w", err)
+	}
+
+	return nil
+}
+
+// List lists the audit log entries matching filter, newest first, with the total count
+func (r *auditRepository) List(ctx context.Context, filter model.AuditFilter) ([]*model.AuditEntry, int64, error) {
+	clause := " WHERE TRUE"
+	args := []any{}
+	argPos := 1
+
+	if filter.UserID != nil {
+		clause += fmt.Sprintf(" AND user_id = $%d", argPos)
+		args = append(args, *filter.UserID)
+		argPos++
+	}
+
+	if filter.Event != nil {
+		clause += fmt.Sprintf(" AND event = $%d", argPos)
+		args = append(args, *filter.Event)
+		argPos++
+	}
+
+	if filter.From != nil {
+		clause += fmt.Sprintf(" AND created_at >= $%d", argPos)
+		args = append(args, *filter.From)
+		argPos++
+	}
+
+	if filter.To != nil {
+		clause += fmt.Sprintf(" AND created_at < $%d", argPos)
+		args = append(args, *filter.To)
+		argPos++
+	}
+
+	var total int64
+	err := r.db.readConn().QueryRow(ctx, "SELECT COUNT(*) FROM audit_log"+clause, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("count audit entries: %w", err)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	page := filter.Page
+	if page <= 0 {
+		page = 1
+	}
+
+	query := `
+		SELECT id, user_id, event, email, ip_address, user_agent, metadata, created_at
+		FROM audit_log` + clause +
+		fmt.Sprintf(" ORDER BY created_at DESC LIMIT $%d OFFSET $%d", argPos, argPos+1)
+	args = append(args, limit, (page-1)*limit)
+
+	rows, err := r.db.readConn().Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []*model.AuditEntry{}
+	for rows.Next() {
+		entry := &model.AuditEntry{}
+		err := rows.Scan(
+			&entry.ID,
+			&entry.UserID,
+			&entry.Event,
+			&entry.Email,
+			&entry.IPAddress,
+			&entry.UserAgent,
+			&entry.Metadata,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scan audit entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if rows.Err() != nil {
+		return nil, 0, fmt.Errorf("iterate audit entries: %w", rows.Err())
+	}
+
+	return entries, total, nil
+}
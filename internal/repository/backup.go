@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// backupTable is a table included in backups
+type backupTable struct {
+	name string
+	from string   // FROM clause naming the table t, ordered so restored rows satisfy foreign keys
+	omit []string // Columns derived by triggers, rebuilt on restore
+}
+
+// backupTables lists the tables with user data, parents before children
+// Sessions, one-time tokens, embeddings and webhook deliveries are left out: they expire or are rebuilt.
+var backupTables = []backupTable{
+	{name: "users"},
+	{name: "user_settings"},
+	{name: "note_types"},
+	{name: "notes", omit: []string{"content_tsv"}},
+	{name: "tags", from: `
+		tags t JOIN (
+			WITH RECURSIVE tree AS (
+				SELECT id, 0 AS depth FROM tags WHERE parent_id IS NULL
+				UNION ALL
+				SELECT tags.id, tree.depth + 1 FROM tags JOIN tree ON tags.parent_id = tree.id
+			)
+			SELECT id, depth FROM tree
+		) tree USING (id)
+		ORDER BY tree.depth`},
+	{name: "note_tags"},
+	{name: "links"},
+	{name: "unresolved_links"},
+	{name: "note_revisions"},
+	{name: "tasks"},
+	{name: "attachments"},
+	{name: "public_links"},
+	{name: "note_shares"},
+	{name: "webhooks"},
+	{name: "activity_log"},
+	{name: "daily_words"},
+}
+
+// BackupTables returns the names of the tables included in backups, in restore order
+func BackupTables() []string {
+	names := make([]string, len(backupTables))
+	for i, table := range backupTables {
+		names[i] = table.name
+	}
+	return names
+}
+
+// BackupRepository reads and writes whole tables for backups
+type BackupRepository interface {
+	Snapshot(ctx context.Context) error
+	Export(ctx context.Context, table string, fn func(row []byte) error) (int64, error)
+	Columns(ctx context.Context, table string) ([]string, error)
+	Import(ctx context.Context, table string, columns []string, rows []json.RawMessage) (int64, error)
+}
+
+// backupRepository implements BackupRepository
+type backupRepository struct {
+	db *DB
+}
+
+// NewBackupRepository creates a new backup repository
+func NewBackupRepository(db *DB) BackupRepository {
+	if db.sqlite != nil {
+		return &sqliteBackupRepository{backupRepository: &backupRepository{db: db}}
+	}
+	return &backupRepository{db: db}
+}
+
+// Snapshot makes the rest of the transaction read from one consistent snapshot
+// It must be the first statement of a transaction started with InTx.
+func (r *backupRepository) Snapshot(ctx context.Context) error {
+	if _, err := r.db.conn().Exec(ctx, `SET TRANSACTION ISOLATION LEVEL REPEATABLE READ, READ ONLY`); err != nil {
+		return fmt.Errorf("start snapshot: %w", err)
+	}
+	return nil
+}
+
+// Export calls fn with every row of a backup table as a JSON object
+// Rows are read one at a time. It returns the number of rows exported.
+func (r *backupRepository) Export(ctx context.Context, table string, fn func(row []byte) error) (int64, error) {
+	spec, ok := findBackupTable(table)
+	if !ok {
+		return 0, fmt.Errorf("table %q is not backed up", table)
+	}
+
+	row := "to_jsonb(t)"
+	for _, column := range spec.omit {
+		row += " - '" + column + "'"
+	}
+	from := spec.from
+	if from == "" {
+		from = pgx.Identifier{spec.name}.Sanitize() + " t"
+	}
+
+	rows, err := r.db.conn().Query(ctx, `SELECT (`+row+`)::text FROM `+from)
+	if err != nil {
+		return 0, fmt.Errorf("export %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	var count int64
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return 0, fmt.Errorf("scan %s row: %w", table, err)
+		}
+		if err := fn(data); err != nil {
+			return 0, err
+		}
+		count++
+	}
+
+	if rows.Err() != nil {
+		return 0, fmt.Errorf("iterate %s: %w", table, rows.Err())
+	}
+
+	return count, nil
+}
+
+// Columns returns the columns of a backup table in the current schema, nil if it doesn't exist
+func (r *backupRepository) Columns(ctx context.Context, table string) ([]string, error) {
+	if _, ok := findBackupTable(table); !ok {
+		return nil, fmt.Errorf("table %q is not backed up", table)
+	}
+
+	rows, err := r.db.conn().Query(ctx, `
+		SELECT column_name FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = $1
+		ORDER BY ordinal_position
+	`, table)
+	if err != nil {
+		return nil, fmt.Errorf("list %s columns: %w", table, err)
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return nil, fmt.Errorf("scan column: %w", err)
+		}
+		columns = append(columns, column)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("iterate columns: %w", rows.Err())
+	}
+
+	return columns, nil
+}
+
+// Import inserts exported rows into a backup table, filling only the given columns
+// Rows that already exist (by any unique key) are kept as they are.
+// It returns the number of rows inserted.
+func (r *backupRepository) Import(ctx context.Context, table string, columns []string, rows []json.RawMessage) (int64, error) {
+	if _, ok := findBackupTable(table); !ok {
+		return 0, fmt.Errorf("table %q is not backed up", table)
+	}
+	if len(rows) == 0 || len(columns) == 0 {
+		return 0, nil
+	}
+
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = pgx.Identifier{column}.Sanitize()
+	}
+	list := strings.Join(quoted, ", ")
+	name := pgx.Identifier{table}.Sanitize()
+
+	data, err := json.Marshal(rows)
+	if err != nil {
+		return 0, fmt.Errorf("encode %s rows: %w", table, err)
+	}
+
+	query := `INSERT INTO ` + name + ` (` + list + `)
+		SELECT ` + list + ` FROM jsonb_populate_recordset(NULL::` + name + `, $1::jsonb)
+		ON CONFLICT DO NOTHING`
+	result, err := r.db.conn().Exec(ctx, query, string(data))
+	if err != nil {
+		return 0, fmt.Errorf("import %s: %w", table, err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// findBackupTable returns the backup table named name
+func findBackupTable(name string) (backupTable, bool) {
+	for _, table := range backupTables {
+		if table.name == name {
+			return table, true
+		}
+	}
+	return backupTable{}, false
+}
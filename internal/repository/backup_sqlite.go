@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// sqliteBackupRepository is the BackupRepository of SQLite databases
+// Rows are exported in the JSON form of the Postgres backups, so backups restore into either database.
+type sqliteBackupRepository struct {
+	*backupRepository
+}
+
+// sqliteColumn is a column of a SQLite table and its declared type
+type sqliteColumn struct {
+	name     string
+	declared string
+}
+
+// Snapshot does nothing: SQLite transactions hold the write lock from the start, so nothing
+// changes under the rest of the transaction
+func (r *sqliteBackupRepository) Snapshot(ctx context.Context) error {
+	return nil
+}
+
+// Export calls fn with every row of a backup table as a JSON object
+// Rows are read one at a time. It returns the number of rows exported.
+func (r *sqliteBackupRepository) Export(ctx context.Context, table string, fn func(row []byte) error) (int64, error) {
+	spec, ok := findBackupTable(table)
+	if !ok {
+		return 0, fmt.Errorf("table %q is not backed up", table)
+	}
+
+	columns, err := r.tableInfo(ctx, table)
+	if err != nil {
+		return 0, err
+	}
+
+	// JSONB columns are nested as JSON and booleans, stored as 0 or 1, written as true or false
+	var fields []string
+	for _, column := range columns {
+		if slices.Contains(spec.omit, column.name) {
+			continue
+		}
+		value := "t." + pgx.Identifier{column.name}.Sanitize()
+		switch column.declared {
+		case "JSONB":
+			value = "json(" + value + ")"
+		case "BOOLEAN":
+			value = "CASE WHEN " + value + " IS NULL THEN NULL WHEN " + value + " THEN json('true') ELSE json('false') END"
+		}
+		fields = append(fields, "'"+column.name+"', "+value)
+	}
+	from := spec.from
+	if from == "" {
+		from = pgx.Identifier{spec.name}.Sanitize() + " t"
+	}
+
+	rows, err := r.db.conn().Query(ctx, `SELECT json_object(`+strings.Join(fields, ", ")+`) FROM `+from)
+	if err != nil {
+		return 0, fmt.Errorf("export %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	var count int64
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return 0, fmt.Errorf("scan %s row: %w", table, err)
+		}
+		if err := fn(data); err != nil {
+			return 0, err
+		}
+		count++
+	}
+
+	if rows.Err() != nil {
+		return 0, fmt.Errorf("iterate %s: %w", table, rows.Err())
+	}
+
+	return count, nil
+}
+
+// Columns returns the columns of a backup table in the current schema, nil if it doesn't exist
+func (r *sqliteBackupRepository) Columns(ctx context.Context, table string) ([]string, error) {
+	if _, ok := findBackupTable(table); !ok {
+		return nil, fmt.Errorf("table %q is not backed up", table)
+	}
+
+	info, err := r.tableInfo(ctx, table)
+	if err != nil {
+		return nil, err
+	}
+
+	var columns []string
+	for _, column := range info {
+		columns = append(columns, column.name)
+	}
+	return columns, nil
+}
+
+// Import inserts exported rows into a backup table, filling only the given columns
+// Rows that already exist (by any unique key) are kept as they are. Times are converted to the
+// stored form, so rows exported from Postgres import too. It returns the number of rows inserted.
+func (r *sqliteBackupRepository) Import(ctx context.Context, table string, columns []string, rows []json.RawMessage) (int64, error) {
+	if _, ok := findBackupTable(table); !ok {
+		return 0, fmt.Errorf("table %q is not backed up", table)
+	}
+	if len(rows) == 0 || len(columns) == 0 {
+		return 0, nil
+	}
+
+	info, err := r.tableInfo(ctx, table)
+	if err != nil {
+		return 0, err
+	}
+	declared := make(map[string]string, len(info))
+	for _, column := range info {
+		declared[column.name] = column.declared
+	}
+
+	quoted := make([]string, len(columns))
+	placeholders := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = pgx.Identifier{column}.Sanitize()
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	}
+	query := `INSERT INTO ` + pgx.Identifier{table}.Sanitize() + ` (` + strings.Join(quoted, ", ") + `)
+		VALUES (` + strings.Join(placeholders, ", ") + `)
+		ON CONFLICT DO NOTHING`
+
+	var inserted int64
+	for _, row := range rows {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(row, &fields); err != nil {
+			return 0, fmt.Errorf("decode %s row: %w", table, err)
+		}
+
+		args := make([]any, len(columns))
+		for i, column := range columns {
+			args[i], err = importValue(fields[column], declared[column])
+			if err != nil {
+				return 0, fmt.Errorf("decode %s.%s: %w", table, column, err)
+			}
+		}
+
+		result, err := r.db.conn().Exec(ctx, query, args...)
+		if err != nil {
+			return 0, fmt.Errorf("import %s: %w", table, err)
+		}
+		inserted += result.RowsAffected()
+	}
+
+	return inserted, nil
+}
+
+// tableInfo lists the columns of a table in order
+func (r *sqliteBackupRepository) tableInfo(ctx context.Context, table string) ([]sqliteColumn, error) {
+	rows, err := r.db.conn().Query(ctx, `SELECT name, upper(type) FROM pragma_table_info($1) ORDER BY cid`, table)
+	if err != nil {
+		return nil, fmt.Errorf("list %s columns: %w", table, err)
+	}
+	defer rows.Close()
+
+	var columns []sqliteColumn
+	for rows.Next() {
+		var column sqliteColumn
+		if err := rows.Scan(&column.name, &column.declared); err != nil {
+			return nil, fmt.Errorf("scan column: %w", err)
+		}
+		columns = append(columns, column)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("iterate columns: %w", rows.Err())
+	}
+
+	return columns, nil
+}
+
+// importValue converts an exported JSON value to the argument stored in a column of the declared type
+// JSONB columns keep their JSON; time strings are parsed so they're stored in sqliteTimeLayout.
+func importValue(raw json.RawMessage, declared string) (any, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	if declared == "JSONB" {
+		return string(raw), nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	switch v := value.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n, nil
+		}
+		return v.Float64()
+	case string:
+		if declared == "TIMESTAMPTZ" {
+			return sqliteTime(v)
+		}
+		return v, nil
+	case bool:
+		return v, nil
+	}
+	// Arrays and objects outside JSONB columns, e.g. Postgres arrays
+	return string(raw), nil
+}
@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+// CardRepository handles flashcard data operations
+type CardRepository interface {
+	SyncNote(ctx context.Context, userID, noteID uuid.UUID, cards []*model.Card) error
+	Find(ctx context.Context, userID, cardID uuid.UUID) (*model.Card, error)
+	ListDue(ctx context.Context, userID uuid.UUID, today string, limit int) ([]*model.Card, int64, error)
+	UpdateSchedule(ctx context.Context, card *model.Card) error
+}
+
+// cardRepository implements CardRepository
+type cardRepository struct {
+	db *DB
+}
+
+// NewCardRepository creates a new card repository
+func NewCardRepository(db *DB) CardRepository {
+	if db.sqlite != nil {
+		return &sqliteCardRepository{cardRepository: &cardRepository{db: db}}
+	}
+	return &cardRepository{db: db}
+}
+
+// cardColumns are the columns scanned by scanCard, for a query joining notes as n
+const cardColumns = `c.id, c.user_id, c.note_id, n.title, c.line_number, c.question, c.answer,
+		       c.ease_factor, c.interval_days, c.repetitions, c.due_on::text, c.last_reviewed_at, c.created_at`
+
+// scanCard scans a row of cardColumns
+func scanCard(row pgx.Row) (*model.Card, error) {
+	card := &model.Card{}
+	err := row.Scan(
+		&card.ID,
+		&card.UserID,
+		&card.NoteID,
+		&card.NoteTitle,
+		&card.LineNumber,
+		&card.Question,
+		&card.Answer,
+		&card.EaseFactor,
+		&card.IntervalDays,
+		&card.Repetitions,
+		&card.DueOn,
+		&card.LastReviewedAt,
+		&card.CreatedAt,
+	)
+	return card, err
+}
+
+// SyncNote makes a note's cards match the given ones
+// Cards are matched by question, so editing an answer or moving a card keeps its schedule.
+func (r *cardRepository) SyncNote(ctx context.Context, userID, noteID uuid.UUID, cards []*model.Card) error {
+	questions := make([]string, len(cards))
+	for i, card := range cards {
+		questions[i] = card.Question
+	}
+
+	query := `DELETE FROM cards WHERE user_id = $1 AND note_id = $2 AND NOT (question = ANY($3))`
+	if _, err := r.db.conn().Exec(ctx, query, userID, noteID, questions); err != nil {
+		return fmt.Errorf("delete cards: %w", err)
+	}
+
+	return r.saveCards(ctx, userID, noteID, cards)
+}
+
+// saveCards inserts a note's cards, updating the ones it already has
+func (r *cardRepository) saveCards(ctx context.Context, userID, noteID uuid.UUID, cards []*model.Card) error {
+	query := `
+		INSERT INTO cards (id, user_id, note_id, line_number, question, answer, ease_factor, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+		ON CONFLICT (note_id, question) DO UPDATE
+		SET line_number = EXCLUDED.line_number, answer = EXCLUDED.answer
+	`
+	for _, card := range cards {
+		_, err := r.db.conn().Exec(ctx, query,
+			uuid.New(),
+			userID,
+			noteID,
+			card.LineNumber,
+			card.Question,
+			card.Answer,
+			model.CardInitialEase,
+		)
+		if err != nil {
+			return fmt.Errorf("save card: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Find finds a card of a user by ID
+func (r *cardRepository) Find(ctx context.Context, userID, cardID uuid.UUID) (*model.Card, error) {
+	query := `
+		SELECT ` + cardColumns + `
+		FROM cards c
+		INNER JOIN notes n ON n.id = c.note_id
+		WHERE c.user_id = $1 AND c.id = $2 AND n.is_deleted = false
+	`
+
+	card, err := scanCard(r.db.conn().QueryRow(ctx, query, userID, cardID))
+	if err == pgx.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find card: %w", err)
+	}
+
+	return card, nil
+}
+
+// ListDue lists a user's cards due on or before today (YYYY-MM-DD), then new cards
+// Returns the cards up to limit and the number of due cards.
+func (r *cardRepository) ListDue(ctx context.Context, userID uuid.UUID, today string, limit int) ([]*model.Card, int64, error) {
+	where := `
+		FROM cards c
+		INNER JOIN notes n ON n.id = c.note_id
+		WHERE c.user_id = $1 AND n.is_deleted = false AND (c.due_on IS NULL OR c.due_on <= $2::date)
+	`
+
+	var total int64
+	if err := r.db.readConn().QueryRow(ctx, `SELECT COUNT(*) `+where, userID, today).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count due cards: %w", err)
+	}
+
+	query := `SELECT ` + cardColumns + where + `
+		ORDER BY c.due_on ASC NULLS LAST, c.created_at ASC, c.line_number ASC
+		LIMIT $3
+	`
+	rows, err := r.db.readConn().Query(ctx, query, userID, today, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list due cards: %w", err)
+	}
+	defer rows.Close()
+
+	cards := []*model.Card{}
+	for rows.Next() {
+		card, err := scanCard(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scan card: %w", err)
+		}
+		cards = append(cards, card)
+	}
+
+	if rows.Err() != nil {
+		return nil, 0, fmt.Errorf("iterate cards: %w", rows.Err())
+	}
+
+	return cards, total, nil
+}
+
+// UpdateSchedule saves the SM-2 schedule of a card after a review
+func (r *cardRepository) UpdateSchedule(ctx context.Context, card *model.Card) error {
+	query := `
+		UPDATE cards
+		SET ease_factor = $1, interval_days = $2, repetitions = $3, due_on = $4::date, last_reviewed_at = $5
+		WHERE user_id = $6 AND id = $7
+	`
+
+	result, err := r.db.conn().Exec(ctx, query,
+		card.EaseFactor,
+		card.IntervalDays,
+		card.Repetitions,
+		card.DueOn,
+		card.LastReviewedAt,
+		card.UserID,
+		card.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("update card schedule: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
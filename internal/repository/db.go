@@ -2,32 +2,94 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"sync/atomic"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/momokii/go-cli-notes/internal/config"
 )
 
-// DB wraps the pgxpool for database operations
+// Querier is implemented by both the pool and transactions
+type Querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// DB wraps the pgxpool for database operations, or the SQLite database when DB_DRIVER is sqlite
 type DB struct {
 	Pool *pgxpool.Pool
+	// Replica serves read-only queries when a replica is configured, nil = Pool serves them
+	Replica *pgxpool.Pool
+	// sqlite is the database when DB_DRIVER is sqlite, with Pool and Replica nil
+	sqlite *sql.DB
+	// slowQuery is the slow query log threshold of the SQLite database
+	slowQuery time.Duration
+	// tx is set on the DB passed to InTx callbacks
+	tx pgx.Tx
+	// acquireTimeout bounds the wait for a free connection, 0 = as long as the query context allows
+	acquireTimeout time.Duration
+	timeouts       *poolTimeouts
+}
+
+// poolTimeouts counts the acquire timeouts of each pool
+type poolTimeouts struct {
+	primary atomic.Int64
+	replica atomic.Int64
 }
 
-// NewDB creates a new database connection pool
-func NewDB(dsn string, maxOpenConns, maxIdleConns int, connMaxLifetime time.Duration) (*DB, error) {
-	config, err := pgxpool.ParseConfig(dsn)
+// NewDB creates a new database connection pool, plus one for the read replica when configured
+// With DB_DRIVER=sqlite it opens the SQLite database file instead.
+func NewDB(cfg config.DatabaseConfig) (*DB, error) {
+	if cfg.Driver == "sqlite" {
+		sqliteDB, err := newSQLite(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return &DB{sqlite: sqliteDB, slowQuery: cfg.SlowQueryThreshold, timeouts: &poolTimeouts{}}, nil
+	}
+
+	pool, err := newPool(cfg.DSN(), cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	db := &DB{Pool: pool, acquireTimeout: cfg.AcquireTimeout, timeouts: &poolTimeouts{}}
+	if cfg.ReplicaURL != "" {
+		if db.Replica, err = newPool(cfg.ReplicaURL, cfg); err != nil {
+			pool.Close()
+			return nil, fmt.Errorf("replica: %w", err)
+		}
+	}
+
+	return db, nil
+}
+
+// newPool creates a connection pool for dsn
+// Queries taking at least cfg.SlowQueryThreshold are logged; zero disables the log.
+func newPool(dsn string, cfg config.DatabaseConfig) (*pgxpool.Pool, error) {
+	poolConfig, err := pgxpool.ParseConfig(dsn)
 	if err != nil {
 		return nil, fmt.Errorf("parse dsn: %w", err)
 	}
 
 	// Set pool configuration
-	config.MaxConns = int32(maxOpenConns)
-	config.MaxConnIdleTime = connMaxLifetime
-	config.MaxConnLifetime = connMaxLifetime
-	config.HealthCheckPeriod = 1 * time.Minute
+	poolConfig.MaxConns = int32(cfg.MaxOpenConns)
+	poolConfig.MaxConnIdleTime = cfg.ConnMaxLifetime
+	poolConfig.MaxConnLifetime = cfg.ConnMaxLifetime
+	poolConfig.HealthCheckPeriod = 1 * time.Minute
+	if cfg.SlowQueryThreshold > 0 {
+		poolConfig.ConnConfig.Tracer = &slowQueryTracer{threshold: cfg.SlowQueryThreshold}
+	}
 
 	// Create the pool
-	pool, err := pgxpool.NewWithConfig(context.Background(), config)
+	pool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
 	if err != nil {
 		return nil, fmt.Errorf("create pool: %w", err)
 	}
@@ -38,39 +100,128 @@ func NewDB(dsn string, maxOpenConns, maxIdleConns int, connMaxLifetime time.Dura
 		return nil, fmt.Errorf("ping database: %w", err)
 	}
 
-	return &DB{Pool: pool}, nil
+	return pool, nil
+}
+
+// conn returns the current transaction, or the pool outside of one
+func (db *DB) conn() Querier {
+	if db.tx != nil {
+		return db.tx
+	}
+	if db.sqlite != nil {
+		return &sqliteConn{sqliteQuerier: sqliteQuerier{exec: db.sqlite, slow: db.slowQuery}, db: db.sqlite}
+	}
+	return db.querier(db.Pool, &db.timeouts.primary)
+}
+
+// readConn returns the connection for read-only queries: the replica when configured
+// Replicas lag behind the primary, so use it only where a slightly stale answer is fine,
+// never to read back a write. Inside a transaction it's the transaction.
+func (db *DB) readConn() Querier {
+	if db.tx == nil && db.Replica != nil {
+		return db.querier(db.Replica, &db.timeouts.replica)
+	}
+	return db.conn()
+}
+
+// querier returns pool, bounded by the acquire timeout when one is set
+func (db *DB) querier(pool *pgxpool.Pool, timeouts *atomic.Int64) Querier {
+	if db.acquireTimeout > 0 {
+		return &timeoutPool{pool: pool, timeout: db.acquireTimeout, timeouts: timeouts}
+	}
+	return pool
+}
+
+// InTx runs fn in a transaction, committing it if fn returns nil and rolling it back otherwise
+// Repositories created from the DB passed to fn run their queries in the transaction.
+// Nested calls use a savepoint, so a failing inner call only undoes its own changes.
+func (db *DB) InTx(ctx context.Context, fn func(tx *DB) error) error {
+	tx, err := db.conn().Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	txDB := *db
+	txDB.tx = tx
+	if err := fn(&txDB); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
 }
 
 // Close closes the database connection pool
 func (db *DB) Close() {
+	if db.sqlite != nil {
+		db.sqlite.Close()
+	}
 	if db.Pool != nil {
 		db.Pool.Close()
 	}
+	if db.Replica != nil {
+		db.Replica.Close()
+	}
 }
 
 // Ping checks if the database is accessible
 func (db *DB) Ping(ctx context.Context) error {
+	if db.sqlite != nil {
+		return db.sqlite.PingContext(ctx)
+	}
 	return db.Pool.Ping(ctx)
 }
 
 // Repository holds all repositories
 type Repository struct {
-	User          UserRepository
-	Note          NoteRepository
-	Tag           TagRepository
-	Link          LinkRepository
-	Activity      ActivityRepository
-	RefreshToken  RefreshTokenRepository
+	User              UserRepository
+	Note              NoteRepository
+	Tag               TagRepository
+	Link              LinkRepository
+	Activity          ActivityRepository
+	RefreshToken      RefreshTokenRepository
+	PasswordReset     PasswordResetRepository
+	EmailVerification EmailVerificationRepository
+	DeviceAuth        DeviceAuthRepository
+	Audit             AuditRepository
+	Revision          RevisionRepository
+	Settings          SettingsRepository
+	Attachment        AttachmentRepository
+	Task              TaskRepository
+	Card              CardRepository
+	Embedding         EmbeddingRepository
+	NoteType          NoteTypeRepository
+	PublicLink        PublicLinkRepository
+	NoteShare         NoteShareRepository
+	Webhook           WebhookRepository
 }
 
 // NewRepository creates a new repository with all sub-repositories
 func NewRepository(db *DB) *Repository {
 	return &Repository{
-		User:         NewUserRepository(db),
-		Note:         NewNoteRepository(db),
-		Tag:          NewTagRepository(db),
-		Link:         NewLinkRepository(db),
-		Activity:     NewActivityRepository(db),
-		RefreshToken: NewRefreshTokenRepository(db),
+		User:              NewUserRepository(db),
+		Note:              NewNoteRepository(db),
+		Tag:               NewTagRepository(db),
+		Link:              NewLinkRepository(db),
+		Activity:          NewActivityRepository(db),
+		RefreshToken:      NewRefreshTokenRepository(db),
+		PasswordReset:     NewPasswordResetRepository(db),
+		EmailVerification: NewEmailVerificationRepository(db),
+		DeviceAuth:        NewDeviceAuthRepository(db),
+		Audit:             NewAuditRepository(db),
+		Revision:          NewRevisionRepository(db),
+		Settings:          NewSettingsRepository(db),
+		Attachment:        NewAttachmentRepository(db),
+		Task:              NewTaskRepository(db),
+		Card:              NewCardRepository(db),
+		Embedding:         NewEmbeddingRepository(db),
+		NoteType:          NewNoteTypeRepository(db),
+		PublicLink:        NewPublicLinkRepository(db),
+		NoteShare:         NewNoteShareRepository(db),
+		Webhook:           NewWebhookRepository(db),
 	}
 }
@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+// DeviceAuthRepository handles device authorization data operations
+type DeviceAuthRepository interface {
+	Create(ctx context.Context, auth *model.DeviceAuthorization) error
+	FindByDeviceCodeHash(ctx context.Context, deviceCodeHash string) (*model.DeviceAuthorization, error)
+	FindPendingByUserCode(ctx context.Context, userCode string) (*model.DeviceAuthorization, error)
+	SetPolled(ctx context.Context, id uuid.UUID, at time.Time) error
+	Approve(ctx context.Context, id, userID uuid.UUID) error
+	Deny(ctx context.Context, id uuid.UUID) error
+	MarkUsed(ctx context.Context, id uuid.UUID) error
+	DeleteExpired(ctx context.Context) error
+}
+
+// deviceAuthRepository implements DeviceAuthRepository
+type deviceAuthRepository struct {
+	db *DB
+}
+
+// NewDeviceAuthRepository creates a new device authorization repository
+func NewDeviceAuthRepository(db *DB) DeviceAuthRepository {
+	return &deviceAuthRepository{db: db}
+}
+
+const deviceAuthColumns = `id, device_code_hash, user_code, user_id, status, user_agent, ip_address, expires_at, last_polled_at, created_at`
+
+// Create inserts a new pending device authorization
+func (r *deviceAuthRepository) Create(ctx context.Context, auth *model.DeviceAuthorization) error {
+	query := `
+		INSERT INTO device_authorizations (id, device_code_hash, user_code, status, user_agent, ip_address, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	auth.ID = uuid.New()
+	auth.Status = model.DeviceAuthPending
+	auth.CreatedAt = time.Now()
+
+	_, err := r.db.conn().Exec(ctx, query,
+		auth.ID,
+		auth.DeviceCodeHash,
+		auth.UserCode,
+		auth.Status,
+		auth.UserAgent,
+		auth.IPAddress,
+		auth.ExpiresAt,
+		auth.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("create device authorization: %w", err)
+	}
+
+	return nil
+}
+
+// FindByDeviceCodeHash finds a device authorization by the hash of its device code, whatever its status
+func (r *deviceAuthRepository) FindByDeviceCodeHash(ctx context.Context, deviceCodeHash string) (*model.DeviceAuthorization, error) {
+	query := `SELECT ` + deviceAuthColumns + ` FROM device_authorizations WHERE device_code_hash = $1`
+
+	return r.scan(r.db.conn().QueryRow(ctx, query, deviceCodeHash))
+}
+
+// FindPendingByUserCode finds the unexpired, pending device authorization showing a user code
+func (r *deviceAuthRepository) FindPendingByUserCode(ctx context.Context, userCode string) (*model.DeviceAuthorization, error) {
+	query := `
+		SELECT ` + deviceAuthColumns + `
+		FROM device_authorizations
+		WHERE user_code = $1 AND status = 'pending' AND expires_at > NOW()
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	return r.scan(r.db.conn().QueryRow(ctx, query, userCode))
+}
+
+// SetPolled records when the device last polled for its tokens
+func (r *deviceAuthRepository) SetPolled(ctx context.Context, id uuid.UUID, at time.Time) error {
+	query := `UPDATE device_authorizations SET last_polled_at = $2 WHERE id = $1`
+
+	if _, err := r.db.conn().Exec(ctx, query, id, at); err != nil {
+		return fmt.Errorf("update device authorization: %w", err)
+	}
+
+	return nil
+}
+
+// Approve signs the device in as userID once it polls
+// Returns ErrNotFound if the authorization is no longer pending or has expired.
+func (r *deviceAuthRepository) Approve(ctx context.Context, id, userID uuid.UUID) error {
+	query := `
+		UPDATE device_authorizations
+		SET status = 'approved', user_id = $2
+		WHERE id = $1 AND status = 'pending' AND expires_at > NOW()
+	`
+
+	return r.setStatus(ctx, query, id, userID)
+}
+
+// Deny refuses the device login
+// Returns ErrNotFound if the authorization is no longer pending.
+func (r *deviceAuthRepository) Deny(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE device_authorizations
+		SET status = 'denied'
+		WHERE id = $1 AND status = 'pending'
+	`
+
+	return r.setStatus(ctx, query, id)
+}
+
+// MarkUsed marks an approved authorization as redeemed so its tokens are only handed out once
+// Returns ErrNotFound if it was already used, so concurrent polls can't both get tokens.
+func (r *deviceAuthRepository) MarkUsed(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE device_authorizations
+		SET status = 'used'
+		WHERE id = $1 AND status = 'approved'
+	`
+
+	return r.setStatus(ctx, query, id)
+}
+
+// DeleteExpired deletes all expired device authorizations
+func (r *deviceAuthRepository) DeleteExpired(ctx context.Context) error {
+	query := `DELETE FROM device_authorizations WHERE expires_at < NOW()`
+
+	if _, err := r.db.conn().Exec(ctx, query); err != nil {
+		return fmt.Errorf("delete expired device authorizations: %w", err)
+	}
+
+	return nil
+}
+
+// setStatus runs a status update, ErrNotFound when no row was in the expected status
+func (r *deviceAuthRepository) setStatus(ctx context.Context, query string, args ...any) error {
+	result, err := r.db.conn().Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("update device authorization: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// scan reads a device authorization row
+func (r *deviceAuthRepository) scan(row pgx.Row) (*model.DeviceAuthorization, error) {
+	auth := &model.DeviceAuthorization{}
+	err := row.Scan(
+		&auth.ID,
+		&auth.DeviceCodeHash,
+		&auth.UserCode,
+		&auth.UserID,
+		&auth.Status,
+		&auth.UserAgent,
+		&auth.IPAddress,
+		&auth.ExpiresAt,
+		&auth.LastPolledAt,
+		&auth.CreatedAt,
+	)
+
+	if err == pgx.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find device authorization: %w", err)
+	}
+
+	return auth, nil
+}
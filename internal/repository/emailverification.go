@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+// EmailVerificationRepository handles email verification token data operations
+type EmailVerificationRepository interface {
+	Create(ctx context.Context, token *model.EmailVerificationToken) error
+	FindValidByTokenHash(ctx context.Context, tokenHash string) (*model.EmailVerificationToken, error)
+	DeleteForUser(ctx context.Context, userID uuid.UUID) error
+	DeleteExpired(ctx context.Context) error
+}
+
+// emailVerificationRepository implements EmailVerificationRepository
+type emailVerificationRepository struct {
+	db *DB
+}
+
+// NewEmailVerificationRepository creates a new email verification token repository
+func NewEmailVerificationRepository(db *DB) EmailVerificationRepository {
+	return &emailVerificationRepository{db: db}
+}
+
+// Create inserts a new email verification token
+func (r *emailVerificationRepository) Create(ctx context.Context, token *model.EmailVerificationToken) error {
+	query := `
+		INSERT INTO email_verification_tokens (id, user_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	token.ID = uuid.New()
+	token.CreatedAt = time.Now()
+
+	_, err := r.db.conn().Exec(ctx, query,
+		token.ID,
+		token.UserID,
+		token.TokenHash,
+		token.ExpiresAt,
+		token.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("create email verification token: %w", err)
+	}
+
+	return nil
+}
+
+// FindValidByTokenHash finds an unexpired token by its hash
+func (r *emailVerificationRepository) FindValidByTokenHash(ctx context.Context, tokenHash string) (*model.EmailVerificationToken, error) {
+	query := `
+		SELECT id, user_id, token_hash, expires_at, created_at
+		FROM email_verification_tokens
+		WHERE token_hash = $1 AND expires_at > NOW()
+	`
+
+	token := &model.EmailVerificationToken{}
+	err := r.db.conn().QueryRow(ctx, query, tokenHash).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.CreatedAt,
+	)
+
+	if err == pgx.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find email verification token: %w", err)
+	}
+
+	return token, nil
+}
+
+// DeleteForUser deletes all email verification tokens of a user
+func (r *emailVerificationRepository) DeleteForUser(ctx context.Context, userID uuid.UUID) error {
+	query := `DELETE FROM email_verification_tokens WHERE user_id = $1`
+
+	_, err := r.db.conn().Exec(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("delete email verification tokens: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteExpired deletes all expired email verification tokens
+func (r *emailVerificationRepository) DeleteExpired(ctx context.Context) error {
+	query := `DELETE FROM email_verification_tokens WHERE expires_at < NOW()`
+
+	_, err := r.db.conn().Exec(ctx, query)
+	if err != nil {
+		return fmt.Errorf("delete expired email verification tokens: %w", err)
+	}
+
+	return nil
+}
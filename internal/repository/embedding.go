@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+// EmbeddingRepository handles note embedding data operations
+// Embeddings live in the pgvector-backed note_embeddings table, which only exists
+// when the vector extension was available to the migration (see Available).
+type EmbeddingRepository interface {
+	Available(ctx context.Context) (bool, error)
+	ListStale(ctx context.Context, modelName string, limit int) ([]*model.Note, error)
+	Upsert(ctx context.Context, noteID, userID uuid.UUID, modelName string, vector []float32, noteUpdatedAt time.Time) error
+	Search(ctx context.Context, userID uuid.UUID, modelName string, vector []float32, query string, filter model.NoteFilter, limit int) ([]*model.SearchResult, error)
+}
+
+// embeddingRepository implements EmbeddingRepository
+type embeddingRepository struct {
+	db *DB
+}
+
+// NewEmbeddingRepository creates a new embedding repository
+func NewEmbeddingRepository(db *DB) EmbeddingRepository {
+	if db.sqlite != nil {
+		return &sqliteEmbeddingRepository{embeddingRepository: &embeddingRepository{db: db}}
+	}
+	return &embeddingRepository{db: db}
+}
+
+// Available reports whether the note_embeddings table exists
+func (r *embeddingRepository) Available(ctx context.Context) (bool, error) {
+	var available bool
+	err := r.db.conn().QueryRow(ctx, `SELECT to_regclass('note_embeddings') IS NOT NULL`).Scan(&available)
+	if err != nil {
+		return false, fmt.Errorf("check note embeddings: %w", err)
+	}
+	return available, nil
+}
+
+// ListStale lists notes of every user without an up-to-date embedding from this model
+// A note is stale when it has no embedding, or was changed after it was embedded.
+func (r *embeddingRepository) ListStale(ctx context.Context, modelName string, limit int) ([]*model.Note, error) {
+	query := `
+		SELECT n.id, n.user_id, n.title, n.content, n.encrypted, n.updated_at
+		FROM notes n
+		LEFT JOIN note_embeddings e ON e.note_id = n.id AND e.model = $1
+		WHERE n.is_deleted = false
+		  AND (e.note_id IS NULL OR e.note_updated_at <> n.updated_at)
+		ORDER BY n.updated_at ASC
+		LIMIT $2
+	`
+
+	rows, err := r.db.conn().Query(ctx, query, modelName, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list stale notes: %w", err)
+	}
+	defer rows.Close()
+
+	notes := []*model.Note{}
+	for rows.Next() {
+		note := &model.Note{}
+		if err := rows.Scan(&note.ID, &note.UserID, &note.Title, &note.Content, &note.Encrypted, &note.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan note: %w", err)
+		}
+		notes = append(notes, note)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("iterate notes: %w", rows.Err())
+	}
+
+	return notes, nil
+}
+
+// Upsert stores the embedding of a note, replacing an earlier one
+// noteUpdatedAt is the version of the note that was embedded.
+func (r *embeddingRepository) Upsert(ctx context.Context, noteID, userID uuid.UUID, modelName string, vector []float32, noteUpdatedAt time.Time) error {
+	query := `
+		INSERT INTO note_embeddings (note_id, user_id, model, embedding, note_updated_at)
+		VALUES ($1, $2, $3, $4::vector, $5)
+		ON CONFLICT (note_id) DO UPDATE
+		SET model = EXCLUDED.model, embedding = EXCLUDED.embedding, note_updated_at = EXCLUDED.note_updated_at
+	`
+
+	_, err := r.db.conn().Exec(ctx, query, noteID, userID, modelName, formatVector(vector), noteUpdatedAt)
+	if err != nil {
+		return fmt.Errorf("upsert note embedding: %w", err)
+	}
+
+	return nil
+}
+
+// Search lists the user's notes closest to vector by cosine distance, closest first
+// Only embeddings from modelName are compared. Results get a snippet for query like
+// full-text search does, which falls back to the start of the note when no word matches.
+func (r *embeddingRepository) Search(ctx context.Context, userID uuid.UUID, modelName string, vector []float32, query string, filter model.NoteFilter, limit int) ([]*model.SearchResult, error) {
+	filterClause, args := noteFilterClause(userID, filter)
+
+	args = append(args, modelName, formatVector(vector), query, headlineOptions(model.DefaultFragmentSize), limit)
+	modelPos, vectorPos, queryPos, optionsPos, limitPos := len(args)-4, len(args)-3, len(args)-2, len(args)-1, len(args)
+
+	sql := fmt.Sprintf(`
+		SELECT id, notes.user_id, title, content, note_type, word_count, reading_time_minutes,
+		       is_deleted, deleted_at, created_at, updated_at, last_accessed_at, access_count, metadata, encrypted,
+		       1 - (e.embedding <=> $%[2]d::vector),
+		       CASE WHEN encrypted THEN ''
+		            ELSE ts_headline('english', content, plainto_tsquery('english', $%[3]d), $%[4]d)
+		       END
+		FROM notes
+		INNER JOIN note_embeddings e ON e.note_id = notes.id AND e.model = $%[1]d
+		WHERE notes.user_id = $1 AND is_deleted = false`, modelPos, vectorPos, queryPos, optionsPos) +
+		filterClause +
+		fmt.Sprintf(" ORDER BY e.embedding <=> $%d::vector LIMIT $%d", vectorPos, limitPos)
+
+	rows, err := r.db.conn().Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("semantic search: %w", err)
+	}
+
+	return collectSearchResults(rows)
+}
+
+// formatVector formats a vector in pgvector's text form, e.g. [0.1,0.2]
+func formatVector(vector []float32) string {
+	var b strings.Builder
+	b.WriteByte('[')
+	for i, v := range vector {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.FormatFloat(float64(v), 'g', -1, 32))
+	}
+	b.WriteByte(']')
+	return b.String()
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+// sqliteEmbeddingRepository is the EmbeddingRepository of SQLite databases
+// Vectors are stored as JSON arrays and compared with cosine_similarity, without an index.
+type sqliteEmbeddingRepository struct {
+	*embeddingRepository
+}
+
+// Available reports true, the SQLite schema always has the note_embeddings table
+func (r *sqliteEmbeddingRepository) Available(ctx context.Context) (bool, error) {
+	return true, nil
+}
+
+// Upsert stores the embedding of a note, replacing an earlier one
+// noteUpdatedAt is the version of the note that was embedded.
+func (r *sqliteEmbeddingRepository) Upsert(ctx context.Context, noteID, userID uuid.UUID, modelName string, vector []float32, noteUpdatedAt time.Time) error {
+	query := `
+		INSERT INTO note_embeddings (note_id, user_id, model, embedding, note_updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (note_id) DO UPDATE
+		SET model = EXCLUDED.model, embedding = EXCLUDED.embedding, note_updated_at = EXCLUDED.note_updated_at
+	`
+
+	_, err := r.db.conn().Exec(ctx, query, noteID, userID, modelName, formatVector(vector), noteUpdatedAt)
+	if err != nil {
+		return fmt.Errorf("upsert note embedding: %w", err)
+	}
+
+	return nil
+}
+
+// Search lists the user's notes closest to vector by cosine distance, closest first
+// Only embeddings from modelName are compared. Results get a snippet for query like
+// full-text search does, which falls back to the start of the note when no word matches.
+func (r *sqliteEmbeddingRepository) Search(ctx context.Context, userID uuid.UUID, modelName string, vector []float32, query string, filter model.NoteFilter, limit int) ([]*model.SearchResult, error) {
+	filterClause, args := sqliteNoteFilterClause(userID, filter)
+
+	args = append(args, modelName, formatVector(vector), sqliteMatchQuery(query), model.DefaultFragmentSize, limit)
+	modelPos, vectorPos, matchPos, sizePos, limitPos := len(args)-4, len(args)-3, len(args)-2, len(args)-1, len(args)
+
+	sql := fmt.Sprintf(`
+		SELECT id, notes.user_id, title, content, note_type, word_count, reading_time_minutes,
+		       is_deleted, deleted_at, created_at, updated_at, last_accessed_at, access_count, metadata, encrypted,
+		       cosine_similarity(e.embedding, $%[2]d),
+		       %[3]s
+		FROM notes
+		INNER JOIN note_embeddings e ON e.note_id = notes.id AND e.model = $%[1]d
+		WHERE notes.user_id = $1 AND is_deleted = false`, modelPos, vectorPos, sqliteSnippet(matchPos, sizePos)) +
+		filterClause +
+		fmt.Sprintf(" ORDER BY cosine_similarity(e.embedding, $%d) DESC LIMIT $%d", vectorPos, limitPos)
+
+	rows, err := r.db.conn().Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("semantic search: %w", err)
+	}
+
+	results, err := collectSearchResults(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, result := range results {
+		if result.Snippet == "" && !result.Note.Encrypted {
+			result.Snippet = leadingWords(result.Note.Content, model.DefaultFragmentSize)
+		}
+	}
+
+	return results, nil
+}
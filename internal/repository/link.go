@@ -12,17 +12,36 @@ import (
 )
 
 // LinkRepository handles link data operations
-type LinkRepository struct {
+type LinkRepository interface {
+	Create(ctx context.Context, link *model.Link) error
+	GetBySource(ctx context.Context, userID, noteID uuid.UUID) ([]*model.Link, error)
+	GetByTarget(ctx context.Context, userID, noteID uuid.UUID) ([]*model.Link, error)
+	GetLinksForUser(ctx context.Context, userID uuid.UUID) ([]*model.Link, error)
+	GetByNotes(ctx context.Context, userID uuid.UUID, noteIDs []uuid.UUID) ([]*model.Link, error)
+	Delete(ctx context.Context, userID, sourceID, targetID uuid.UUID) error
+	DeleteBySource(ctx context.Context, userID, noteID uuid.UUID) error
+	DeleteByNote(ctx context.Context, userID, noteID uuid.UUID) error
+	CreateUnresolved(ctx context.Context, link *model.UnresolvedLink) error
+	ListUnresolved(ctx context.Context, userID uuid.UUID) ([]*model.UnresolvedLink, error)
+	ResolveByTitle(ctx context.Context, userID uuid.UUID, title string) ([]*model.UnresolvedLink, error)
+	DeleteUnresolvedBySource(ctx context.Context, userID, noteID uuid.UUID) error
+}
+
+// linkRepository implements LinkRepository
+type linkRepository struct {
 	db *DB
 }
 
 // NewLinkRepository creates a new link repository
 func NewLinkRepository(db *DB) LinkRepository {
-	return LinkRepository{db: db}
+	if db.sqlite != nil {
+		return &sqliteLinkRepository{linkRepository: &linkRepository{db: db}}
+	}
+	return &linkRepository{db: db}
 }
 
 // Create inserts a new link
-func (r *LinkRepository) Create(ctx context.Context, link *model.Link) error {
+func (r *linkRepository) Create(ctx context.Context, link *model.Link) error {
 	query := `
 		INSERT INTO links (id, user_id, source_note_id, target_note_id, link_context, created_at)
 		VALUES ($1, $2, $3, $4, $5, $6)
@@ -34,7 +53,7 @@ func (r *LinkRepository) Create(ctx context.Context, link *model.Link) error {
 	link.ID = uuid.New()
 	link.CreatedAt = now
 
-	err := r.db.Pool.QueryRow(ctx, query,
+	err := r.db.conn().QueryRow(ctx, query,
 		link.ID,
 		link.UserID,
 		link.SourceNoteID,
@@ -62,7 +81,7 @@ func (r *LinkRepository) Create(ctx context.Context, link *model.Link) error {
 }
 
 // GetBySource gets all outgoing links from a note
-func (r *LinkRepository) GetBySource(ctx context.Context, userID, noteID uuid.UUID) ([]*model.Link, error) {
+func (r *linkRepository) GetBySource(ctx context.Context, userID, noteID uuid.UUID) ([]*model.Link, error) {
 	query := `
 		SELECT l.id, l.user_id, l.source_note_id, l.target_note_id, l.link_context, l.created_at
 		FROM links l
@@ -70,7 +89,7 @@ func (r *LinkRepository) GetBySource(ctx context.Context, userID, noteID uuid.UU
 		ORDER BY l.created_at DESC
 	`
 
-	rows, err := r.db.Pool.Query(ctx, query, userID, noteID)
+	rows, err := r.db.conn().Query(ctx, query, userID, noteID)
 	if err != nil {
 		return nil, fmt.Errorf("get links by source: %w", err)
 	}
@@ -101,7 +120,7 @@ func (r *LinkRepository) GetBySource(ctx context.Context, userID, noteID uuid.UU
 }
 
 // GetByTarget gets all incoming links to a note (backlinks)
-func (r *LinkRepository) GetByTarget(ctx context.Context, userID, noteID uuid.UUID) ([]*model.Link, error) {
+func (r *linkRepository) GetByTarget(ctx context.Context, userID, noteID uuid.UUID) ([]*model.Link, error) {
 	query := `
 		SELECT l.id, l.user_id, l.source_note_id, l.target_note_id, l.link_context, l.created_at
 		FROM links l
@@ -109,7 +128,7 @@ func (r *LinkRepository) GetByTarget(ctx context.Context, userID, noteID uuid.UU
 		ORDER BY l.created_at DESC
 	`
 
-	rows, err := r.db.Pool.Query(ctx, query, userID, noteID)
+	rows, err := r.db.conn().Query(ctx, query, userID, noteID)
 	if err != nil {
 		return nil, fmt.Errorf("get links by target: %w", err)
 	}
@@ -139,14 +158,84 @@ func (r *LinkRepository) GetByTarget(ctx context.Context, userID, noteID uuid.UU
 	return links, nil
 }
 
+// GetLinksForUser gets every link of a user between notes that are not deleted
+func (r *linkRepository) GetLinksForUser(ctx context.Context, userID uuid.UUID) ([]*model.Link, error) {
+	query := `
+		SELECT l.id, l.user_id, l.source_note_id, l.target_note_id, l.link_context, l.created_at
+		FROM links l
+		INNER JOIN notes s ON s.id = l.source_note_id AND s.is_deleted = false
+		INNER JOIN notes t ON t.id = l.target_note_id AND t.is_deleted = false
+		WHERE l.user_id = $1
+		ORDER BY l.created_at DESC
+	`
+
+	rows, err := r.db.readConn().Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get links for user: %w", err)
+	}
+
+	return collectLinks(rows)
+}
+
+// GetByNotes gets the links from or to any of the given notes, skipping links to deleted notes
+func (r *linkRepository) GetByNotes(ctx context.Context, userID uuid.UUID, noteIDs []uuid.UUID) ([]*model.Link, error) {
+	if len(noteIDs) == 0 {
+		return []*model.Link{}, nil
+	}
+
+	query := `
+		SELECT l.id, l.user_id, l.source_note_id, l.target_note_id, l.link_context, l.created_at
+		FROM links l
+		INNER JOIN notes s ON s.id = l.source_note_id AND s.is_deleted = false
+		INNER JOIN notes t ON t.id = l.target_note_id AND t.is_deleted = false
+		WHERE l.user_id = $1 AND (l.source_note_id = ANY($2) OR l.target_note_id = ANY($2))
+		ORDER BY l.created_at DESC
+	`
+
+	rows, err := r.db.readConn().Query(ctx, query, userID, noteIDs)
+	if err != nil {
+		return nil, fmt.Errorf("get links by notes: %w", err)
+	}
+
+	return collectLinks(rows)
+}
+
+// collectLinks scans and closes rows of links
+func collectLinks(rows pgx.Rows) ([]*model.Link, error) {
+	defer rows.Close()
+
+	links := []*model.Link{}
+	for rows.Next() {
+		link := &model.Link{}
+		err := rows.Scan(
+			&link.ID,
+			&link.UserID,
+			&link.SourceNoteID,
+			&link.TargetNoteID,
+			&link.LinkContext,
+			&link.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan link: %w", err)
+		}
+		links = append(links, link)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("iterate links: %w", rows.Err())
+	}
+
+	return links, nil
+}
+
 // Delete deletes a link
-func (r *LinkRepository) Delete(ctx context.Context, userID, sourceID, targetID uuid.UUID) error {
+func (r *linkRepository) Delete(ctx context.Context, userID, sourceID, targetID uuid.UUID) error {
 	query := `
 		DELETE FROM links
 		WHERE user_id = $1 AND source_note_id = $2 AND target_note_id = $3
 	`
 
-	result, err := r.db.Pool.Exec(ctx, query, userID, sourceID, targetID)
+	result, err := r.db.conn().Exec(ctx, query, userID, sourceID, targetID)
 	if err != nil {
 		return fmt.Errorf("delete link: %w", err)
 	}
@@ -158,17 +247,153 @@ func (r *LinkRepository) Delete(ctx context.Context, userID, sourceID, targetID
 	return nil
 }
 
+// DeleteBySource deletes all outgoing links from a note
+func (r *linkRepository) DeleteBySource(ctx context.Context, userID, noteID uuid.UUID) error {
+	query := `
+		DELETE FROM links
+		WHERE user_id = $1 AND source_note_id = $2
+	`
+
+	_, err := r.db.conn().Exec(ctx, query, userID, noteID)
+	if err != nil {
+		return fmt.Errorf("delete links by source: %w", err)
+	}
+
+	return nil
+}
+
 // DeleteByNote deletes all links associated with a note (both incoming and outgoing)
-func (r *LinkRepository) DeleteByNote(ctx context.Context, userID, noteID uuid.UUID) error {
+func (r *linkRepository) DeleteByNote(ctx context.Context, userID, noteID uuid.UUID) error {
 	query := `
 		DELETE FROM links
 		WHERE user_id = $1 AND (source_note_id = $2 OR target_note_id = $2)
 	`
 
-	_, err := r.db.Pool.Exec(ctx, query, userID, noteID)
+	_, err := r.db.conn().Exec(ctx, query, userID, noteID)
 	if err != nil {
 		return fmt.Errorf("delete links by note: %w", err)
 	}
 
 	return nil
 }
+
+// CreateUnresolved records a wiki link whose target note does not exist yet
+func (r *linkRepository) CreateUnresolved(ctx context.Context, link *model.UnresolvedLink) error {
+	query := `
+		INSERT INTO unresolved_links (id, user_id, source_note_id, target_title, link_context, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (source_note_id, target_title) DO NOTHING
+	`
+
+	link.ID = uuid.New()
+	link.CreatedAt = time.Now()
+
+	_, err := r.db.conn().Exec(ctx, query,
+		link.ID,
+		link.UserID,
+		link.SourceNoteID,
+		link.TargetTitle,
+		link.LinkContext,
+		link.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("create unresolved link: %w", err)
+	}
+
+	return nil
+}
+
+// ListUnresolved lists all unresolved links for a user with their source note titles
+func (r *linkRepository) ListUnresolved(ctx context.Context, userID uuid.UUID) ([]*model.UnresolvedLink, error) {
+	query := `
+		SELECT u.id, u.user_id, u.source_note_id, u.target_title, u.link_context, u.created_at, n.title
+		FROM unresolved_links u
+		INNER JOIN notes n ON n.id = u.source_note_id
+		WHERE u.user_id = $1 AND n.is_deleted = false
+		ORDER BY u.target_title ASC, u.created_at DESC
+	`
+
+	rows, err := r.db.readConn().Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list unresolved links: %w", err)
+	}
+	defer rows.Close()
+
+	links := []*model.UnresolvedLink{}
+	for rows.Next() {
+		link := &model.UnresolvedLink{}
+		err := rows.Scan(
+			&link.ID,
+			&link.UserID,
+			&link.SourceNoteID,
+			&link.TargetTitle,
+			&link.LinkContext,
+			&link.CreatedAt,
+			&link.SourceTitle,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan unresolved link: %w", err)
+		}
+		links = append(links, link)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("iterate unresolved links: %w", rows.Err())
+	}
+
+	return links, nil
+}
+
+// ResolveByTitle deletes and returns the unresolved links pointing at a title, matched like FindByLinkTitle
+// Called once a note with that title exists so the links can be created for real
+func (r *linkRepository) ResolveByTitle(ctx context.Context, userID uuid.UUID, title string) ([]*model.UnresolvedLink, error) {
+	query := `
+		DELETE FROM unresolved_links
+		WHERE user_id = $1 AND note_title_key(target_title) = note_title_key($2)
+		RETURNING id, user_id, source_note_id, target_title, link_context, created_at
+	`
+
+	rows, err := r.db.conn().Query(ctx, query, userID, title)
+	if err != nil {
+		return nil, fmt.Errorf("resolve unresolved links: %w", err)
+	}
+	defer rows.Close()
+
+	links := []*model.UnresolvedLink{}
+	for rows.Next() {
+		link := &model.UnresolvedLink{}
+		err := rows.Scan(
+			&link.ID,
+			&link.UserID,
+			&link.SourceNoteID,
+			&link.TargetTitle,
+			&link.LinkContext,
+			&link.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan unresolved link: %w", err)
+		}
+		links = append(links, link)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("iterate unresolved links: %w", rows.Err())
+	}
+
+	return links, nil
+}
+
+// DeleteUnresolvedBySource deletes all unresolved links from a note
+func (r *linkRepository) DeleteUnresolvedBySource(ctx context.Context, userID, noteID uuid.UUID) error {
+	query := `
+		DELETE FROM unresolved_links
+		WHERE user_id = $1 AND source_note_id = $2
+	`
+
+	_, err := r.db.conn().Exec(ctx, query, userID, noteID)
+	if err != nil {
+		return fmt.Errorf("delete unresolved links by source: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+// sqliteLinkRepository is the LinkRepository of SQLite databases
+type sqliteLinkRepository struct {
+	*linkRepository
+}
+
+// GetByNotes gets the links from or to any of the given notes, skipping links to deleted notes
+func (r *sqliteLinkRepository) GetByNotes(ctx context.Context, userID uuid.UUID, noteIDs []uuid.UUID) ([]*model.Link, error) {
+	if len(noteIDs) == 0 {
+		return []*model.Link{}, nil
+	}
+
+	query := `
+		SELECT l.id, l.user_id, l.source_note_id, l.target_note_id, l.link_context, l.created_at
+		FROM links l
+		INNER JOIN notes s ON s.id = l.source_note_id AND s.is_deleted = false
+		INNER JOIN notes t ON t.id = l.target_note_id AND t.is_deleted = false
+		WHERE l.user_id = $1
+		  AND (l.source_note_id IN (SELECT value FROM json_each($2)) OR l.target_note_id IN (SELECT value FROM json_each($2)))
+		ORDER BY l.created_at DESC
+	`
+
+	rows, err := r.db.readConn().Query(ctx, query, userID, noteIDs)
+	if err != nil {
+		return nil, fmt.Errorf("get links by notes: %w", err)
+	}
+
+	return collectLinks(rows)
+}
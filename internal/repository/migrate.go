@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/lock"
+
+	"github.com/momokii/go-cli-notes/migrations"
+)
+
+// Migrator applies the embedded migrations to the database
+// A Postgres advisory lock keeps API instances starting together from migrating at the same time.
+// SQLite databases get the SQLite migrations; the file has a single writer, so there's no lock.
+type Migrator struct {
+	provider *goose.Provider
+	// shared is set when the provider uses the SQLite database itself, which Close leaves open
+	shared bool
+}
+
+// NewMigrator creates a migrator for the database
+func (db *DB) NewMigrator() (*Migrator, error) {
+	if db.sqlite != nil {
+		sqliteFS, err := fs.Sub(migrations.SQLiteFS, "sqlite")
+		if err != nil {
+			return nil, fmt.Errorf("load migrations: %w", err)
+		}
+		provider, err := goose.NewProvider(goose.DialectSQLite3, db.sqlite, sqliteFS)
+		if err != nil {
+			return nil, fmt.Errorf("load migrations: %w", err)
+		}
+		return &Migrator{provider: provider, shared: true}, nil
+	}
+
+	locker, err := lock.NewPostgresSessionLocker()
+	if err != nil {
+		return nil, fmt.Errorf("create migration lock: %w", err)
+	}
+
+	// Closing the *sql.DB leaves the pool open
+	provider, err := goose.NewProvider(
+		goose.DialectPostgres,
+		stdlib.OpenDBFromPool(db.Pool),
+		migrations.FS,
+		goose.WithSessionLocker(locker),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("load migrations: %w", err)
+	}
+
+	return &Migrator{provider: provider}, nil
+}
+
+// Up applies all pending migrations and returns the versions applied
+func (m *Migrator) Up(ctx context.Context) ([]int64, error) {
+	results, err := m.provider.Up(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("apply migrations: %w", err)
+	}
+
+	versions := make([]int64, 0, len(results))
+	for _, result := range results {
+		versions = append(versions, result.Source.Version)
+	}
+	return versions, nil
+}
+
+// Down rolls back the most recent migration and returns its version
+func (m *Migrator) Down(ctx context.Context) (int64, error) {
+	result, err := m.provider.Down(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("roll back migration: %w", err)
+	}
+	return result.Source.Version, nil
+}
+
+// Status lists every migration and whether it has been applied
+func (m *Migrator) Status(ctx context.Context) ([]*goose.MigrationStatus, error) {
+	statuses, err := m.provider.Status(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get migration status: %w", err)
+	}
+	return statuses, nil
+}
+
+// Close releases the migrator's database handle
+func (m *Migrator) Close() error {
+	if m.shared {
+		return nil
+	}
+	return m.provider.Close()
+}
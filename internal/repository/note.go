@@ -3,6 +3,8 @@ package repository
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,37 +14,116 @@ import (
 )
 
 // NoteRepository handles note data operations
-type NoteRepository struct {
+type NoteRepository interface {
+	Create(ctx context.Context, note *model.Note) error
+	CreateBatch(ctx context.Context, notes []*model.Note) ([]error, error)
+	FindByID(ctx context.Context, userID, id uuid.UUID) (*model.Note, error)
+	FindAccessible(ctx context.Context, userID, id uuid.UUID, permission model.SharePermission) (*model.Note, error)
+	ListSharedWith(ctx context.Context, userID uuid.UUID) ([]*model.Note, error)
+	LockByID(ctx context.Context, userID, id uuid.UUID) error
+	FindByIDs(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) (map[uuid.UUID]*model.Note, error)
+	FindByTitle(ctx context.Context, userID uuid.UUID, title string) (*model.Note, error)
+	FindByLinkTitle(ctx context.Context, userID uuid.UUID, title string) (*model.Note, error)
+	ListDailyNotes(ctx context.Context, userID uuid.UUID, titlePrefix, from, to string) ([]*model.DailyNoteDay, error)
+	List(ctx context.Context, userID uuid.UUID, filter model.NoteFilter) ([]*model.Note, int64, error)
+	Stream(ctx context.Context, userID uuid.UUID, filter model.NoteFilter, fn func(*model.Note) error) error
+	FindRelated(ctx context.Context, userID, noteID uuid.UUID, limit int) ([]*model.RelatedNote, error)
+	Search(ctx context.Context, userID uuid.UUID, filter model.NoteFilter, fragmentSize int) ([]*model.SearchResult, int64, error)
+	FuzzySearch(ctx context.Context, userID uuid.UUID, filter model.NoteFilter, fragmentSize int) ([]*model.SearchResult, int64, error)
+	ListReading(ctx context.Context, userID uuid.UUID, limit int) ([]*model.Note, error)
+	ListAll(ctx context.Context, userID uuid.UUID) ([]*model.Note, error)
+	Update(ctx context.Context, userID uuid.UUID, note *model.Note) error
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+	Restore(ctx context.Context, userID, id uuid.UUID) error
+	SetMetadata(ctx context.Context, userID, id uuid.UUID, key string, value any) error
+	UpdateAccessCount(ctx context.Context, userID, id uuid.UUID) error
+}
+
+// noteRepository implements NoteRepository
+type noteRepository struct {
 	db *DB
 }
 
 // NewNoteRepository creates a new note repository
 func NewNoteRepository(db *DB) NoteRepository {
-	return NoteRepository{db: db}
+	if db.sqlite != nil {
+		return &sqliteNoteRepository{noteRepository: &noteRepository{db: db}}
+	}
+	return &noteRepository{db: db}
 }
 
 // Create inserts a new note
-func (r *NoteRepository) Create(ctx context.Context, note *model.Note) error {
+func (r *noteRepository) Create(ctx context.Context, note *model.Note) error {
+	return createNote(ctx, r.db.conn(), note)
+}
+
+// CreateBatch inserts notes in a single transaction
+// Each note is inserted under its own savepoint, so a failing note doesn't roll back the others.
+// The returned slice holds the error of each note, nil for the ones that were inserted.
+func (r *noteRepository) CreateBatch(ctx context.Context, notes []*model.Note) ([]error, error) {
+	tx, err := r.db.conn().Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	errs := make([]error, len(notes))
+	for i, note := range notes {
+		savepoint, err := tx.Begin(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("create savepoint: %w", err)
+		}
+
+		if err := createNote(ctx, savepoint, note); err != nil {
+			if rbErr := savepoint.Rollback(ctx); rbErr != nil {
+				return nil, fmt.Errorf("rollback savepoint: %w", rbErr)
+			}
+			errs[i] = err
+			continue
+		}
+
+		if err := savepoint.Commit(ctx); err != nil {
+			return nil, fmt.Errorf("release savepoint: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return errs, nil
+}
+
+// createNote inserts a note through q
+func createNote(ctx context.Context, q Querier, note *model.Note) error {
 	query := `
-		INSERT INTO notes (id, user_id, title, content, note_type, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO notes (id, user_id, title, content, note_type, encrypted, created_at, updated_at,
+		                   word_count, reading_time_minutes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 		RETURNING id, user_id, title, content, note_type, word_count, reading_time_minutes,
-		          is_deleted, deleted_at, created_at, updated_at, last_accessed_at, access_count, metadata
+		          is_deleted, deleted_at, created_at, updated_at, last_accessed_at, access_count, metadata, encrypted
 	`
 
+	// Imported notes keep the creation time they had elsewhere
 	now := time.Now()
 	note.ID = uuid.New()
-	note.CreatedAt = now
+	if note.CreatedAt.IsZero() {
+		note.CreatedAt = now
+	}
 	note.UpdatedAt = now
+	words, minutes := noteMetrics(note.Content)
 
-	err := r.db.Pool.QueryRow(ctx, query,
+	err := q.QueryRow(ctx, query,
 		note.ID,
 		note.UserID,
 		note.Title,
 		note.Content,
 		note.NoteType,
+		note.Encrypted,
 		note.CreatedAt,
 		note.UpdatedAt,
+		words,
+		minutes,
 	).Scan(
 		&note.ID,
 		&note.UserID,
@@ -58,6 +139,7 @@ func (r *NoteRepository) Create(ctx context.Context, note *model.Note) error {
 		&note.LastAccessedAt,
 		&note.AccessCount,
 		&note.Metadata,
+		&note.Encrypted,
 	)
 
 	if err != nil {
@@ -67,17 +149,31 @@ func (r *NoteRepository) Create(ctx context.Context, note *model.Note) error {
 	return nil
 }
 
+// wordBreak splits note content into words, like the Postgres calculate_note_metrics trigger
+var wordBreak = regexp.MustCompile(`\s+`)
+
+// noteMetrics counts the words of note content and its reading time at 200 words a minute
+// The Postgres triggers recompute both on write; SQLite stores them as given.
+func noteMetrics(content string) (words, minutes int) {
+	if content == "" {
+		return 0, 0
+	}
+	words = len(wordBreak.Split(content, -1))
+	return words, (words + 199) / 200
+}
+
 // FindByID finds a note by ID (with user scoping)
-func (r *NoteRepository) FindByID(ctx context.Context, userID, id uuid.UUID) (*model.Note, error) {
+// Only the owner finds the note; FindAccessible also finds notes shared with the user.
+func (r *noteRepository) FindByID(ctx context.Context, userID, id uuid.UUID) (*model.Note, error) {
 	query := `
 		SELECT id, user_id, title, content, note_type, word_count, reading_time_minutes,
-		       is_deleted, deleted_at, created_at, updated_at, last_accessed_at, access_count, metadata
+		       is_deleted, deleted_at, created_at, updated_at, last_accessed_at, access_count, metadata, encrypted
 		FROM notes
 		WHERE id = $1 AND user_id = $2 AND is_deleted = false
 	`
 
 	note := &model.Note{}
-	err := r.db.Pool.QueryRow(ctx, query, id, userID).Scan(
+	err := r.db.conn().QueryRow(ctx, query, id, userID).Scan(
 		&note.ID,
 		&note.UserID,
 		&note.Title,
@@ -92,6 +188,7 @@ func (r *NoteRepository) FindByID(ctx context.Context, userID, id uuid.UUID) (*m
 		&note.LastAccessedAt,
 		&note.AccessCount,
 		&note.Metadata,
+		&note.Encrypted,
 	)
 
 	if err == pgx.ErrNoRows {
@@ -104,11 +201,192 @@ func (r *NoteRepository) FindByID(ctx context.Context, userID, id uuid.UUID) (*m
 	return note, nil
 }
 
+// FindAccessible finds a note the user owns, or that is shared with them with the permission
+// Notes reached through a share have SharedBy and Permission set.
+func (r *noteRepository) FindAccessible(ctx context.Context, userID, id uuid.UUID, permission model.SharePermission) (*model.Note, error) {
+	query := `
+		SELECT id, user_id, title, content, note_type, word_count, reading_time_minutes,
+		       is_deleted, deleted_at, created_at, updated_at, last_accessed_at, access_count, metadata, encrypted,
+		       COALESCE((SELECT u.username FROM users u WHERE u.id = notes.user_id AND notes.user_id <> $2), ''),
+		       COALESCE((SELECT ns.permission FROM note_shares ns WHERE ns.note_id = notes.id AND ns.shared_with = $2), '')
+		FROM notes
+		WHERE id = $1 AND is_deleted = false AND ` + noteAccessCondition("$2", permission)
+
+	note := &model.Note{}
+	err := r.db.conn().QueryRow(ctx, query, id, userID).Scan(
+		&note.ID,
+		&note.UserID,
+		&note.Title,
+		&note.Content,
+		&note.NoteType,
+		&note.WordCount,
+		&note.ReadingTimeMinutes,
+		&note.IsDeleted,
+		&note.DeletedAt,
+		&note.CreatedAt,
+		&note.UpdatedAt,
+		&note.LastAccessedAt,
+		&note.AccessCount,
+		&note.Metadata,
+		&note.Encrypted,
+		&note.SharedBy,
+		&note.Permission,
+	)
+
+	if err == pgx.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find accessible note: %w", err)
+	}
+
+	return note, nil
+}
+
+// ListSharedWith lists the notes other users share with the user, recently updated first
+func (r *noteRepository) ListSharedWith(ctx context.Context, userID uuid.UUID) ([]*model.Note, error) {
+	query := `
+		SELECT n.id, n.user_id, n.title, n.content, n.note_type, n.word_count, n.reading_time_minutes,
+		       n.is_deleted, n.deleted_at, n.created_at, n.updated_at, n.last_accessed_at, n.access_count,
+		       n.metadata, n.encrypted, u.username, ns.permission
+		FROM note_shares ns
+		INNER JOIN notes n ON n.id = ns.note_id AND n.is_deleted = false AND n.encrypted = false
+		INNER JOIN users u ON u.id = n.user_id
+		WHERE ns.shared_with = $1
+		ORDER BY n.updated_at DESC
+	`
+
+	rows, err := r.db.readConn().Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list shared notes: %w", err)
+	}
+	defer rows.Close()
+
+	notes := []*model.Note{}
+	for rows.Next() {
+		note := &model.Note{}
+		err := rows.Scan(
+			&note.ID,
+			&note.UserID,
+			&note.Title,
+			&note.Content,
+			&note.NoteType,
+			&note.WordCount,
+			&note.ReadingTimeMinutes,
+			&note.IsDeleted,
+			&note.DeletedAt,
+			&note.CreatedAt,
+			&note.UpdatedAt,
+			&note.LastAccessedAt,
+			&note.AccessCount,
+			&note.Metadata,
+			&note.Encrypted,
+			&note.SharedBy,
+			&note.Permission,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan note: %w", err)
+		}
+		notes = append(notes, note)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("iterate notes: %w", rows.Err())
+	}
+
+	return notes, nil
+}
+
+// noteAccessCondition matches notes the user (placeholder userArg) owns, or that are shared
+// with them with the permission; write shares also grant read. Notes encrypted after they were
+// shared are left out, since only their owner can read them.
+func noteAccessCondition(userArg string, permission model.SharePermission) string {
+	shared := "ns.note_id = notes.id AND ns.shared_with = " + userArg
+	if permission == model.SharePermissionWrite {
+		shared += " AND ns.permission = 'write'"
+	}
+	return "(notes.user_id = " + userArg + " OR (notes.encrypted = false AND EXISTS (SELECT 1 FROM note_shares ns WHERE " + shared + ")))"
+}
+
+// LockByID locks a note the user may edit until the end of the transaction
+// Concurrent writers of the note wait for the lock; outside a transaction it has no effect.
+func (r *noteRepository) LockByID(ctx context.Context, userID, id uuid.UUID) error {
+	query := `
+		SELECT id FROM notes
+		WHERE id = $1 AND is_deleted = false AND ` + noteAccessCondition("$2", model.SharePermissionWrite) + `
+		FOR UPDATE
+	`
+
+	var locked uuid.UUID
+	err := r.db.conn().QueryRow(ctx, query, id, userID).Scan(&locked)
+	if err == pgx.ErrNoRows {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("lock note: %w", err)
+	}
+
+	return nil
+}
+
+// FindByIDs finds the user's notes with the given IDs in a single query
+// Missing or deleted notes are left out of the returned map.
+func (r *noteRepository) FindByIDs(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) (map[uuid.UUID]*model.Note, error) {
+	notes := make(map[uuid.UUID]*model.Note, len(ids))
+	if len(ids) == 0 {
+		return notes, nil
+	}
+
+	query := `
+		SELECT id, user_id, title, content, note_type, word_count, reading_time_minutes,
+		       is_deleted, deleted_at, created_at, updated_at, last_accessed_at, access_count, metadata, encrypted
+		FROM notes
+		WHERE id = ANY($1) AND user_id = $2 AND is_deleted = false
+	`
+
+	rows, err := r.db.conn().Query(ctx, query, ids, userID)
+	if err != nil {
+		return nil, fmt.Errorf("find notes by ids: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		note := &model.Note{}
+		err := rows.Scan(
+			&note.ID,
+			&note.UserID,
+			&note.Title,
+			&note.Content,
+			&note.NoteType,
+			&note.WordCount,
+			&note.ReadingTimeMinutes,
+			&note.IsDeleted,
+			&note.DeletedAt,
+			&note.CreatedAt,
+			&note.UpdatedAt,
+			&note.LastAccessedAt,
+			&note.AccessCount,
+			&note.Metadata,
+			&note.Encrypted,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan note: %w", err)
+		}
+		notes[note.ID] = note
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("iterate notes: %w", rows.Err())
+	}
+
+	return notes, nil
+}
+
 // FindByTitle finds a note by title and user
-func (r *NoteRepository) FindByTitle(ctx context.Context, userID uuid.UUID, title string) (*model.Note, error) {
+func (r *noteRepository) FindByTitle(ctx context.Context, userID uuid.UUID, title string) (*model.Note, error) {
 	query := `
 		SELECT id, user_id, title, content, note_type, word_count, reading_time_minutes,
-		       is_deleted, deleted_at, created_at, updated_at, last_accessed_at, access_count, metadata
+		       is_deleted, deleted_at, created_at, updated_at, last_accessed_at, access_count, metadata, encrypted
 		FROM notes
 		WHERE user_id = $1 AND title = $2 AND is_deleted = false
 		ORDER BY created_at DESC
@@ -116,7 +394,7 @@ func (r *NoteRepository) FindByTitle(ctx context.Context, userID uuid.UUID, titl
 	`
 
 	note := &model.Note{}
-	err := r.db.Pool.QueryRow(ctx, query, userID, title).Scan(
+	err := r.db.conn().QueryRow(ctx, query, userID, title).Scan(
 		&note.ID,
 		&note.UserID,
 		&note.Title,
@@ -131,6 +409,7 @@ func (r *NoteRepository) FindByTitle(ctx context.Context, userID uuid.UUID, titl
 		&note.LastAccessedAt,
 		&note.AccessCount,
 		&note.Metadata,
+		&note.Encrypted,
 	)
 
 	if err == pgx.ErrNoRows {
@@ -143,12 +422,100 @@ func (r *NoteRepository) FindByTitle(ctx context.Context, userID uuid.UUID, titl
 	return note, nil
 }
 
+// FindByLinkTitle finds the note a [[link]] title names, ignoring case, whitespace, dashes and underscores
+// When several notes match, the one with the exact title wins, then one matching but for case, then the newest.
+func (r *noteRepository) FindByLinkTitle(ctx context.Context, userID uuid.UUID, title string) (*model.Note, error) {
+	query := `
+		SELECT id, user_id, title, content, note_type, word_count, reading_time_minutes,
+		       is_deleted, deleted_at, created_at, updated_at, last_accessed_at, access_count, metadata, encrypted
+		FROM notes
+		WHERE user_id = $1 AND note_title_key(title) = note_title_key($2) AND is_deleted = false
+		ORDER BY title = $2 DESC, lower(title) = lower($2) DESC, created_at DESC
+		LIMIT 1
+	`
+
+	note := &model.Note{}
+	err := r.db.conn().QueryRow(ctx, query, userID, title).Scan(
+		&note.ID,
+		&note.UserID,
+		&note.Title,
+		&note.Content,
+		&note.NoteType,
+		&note.WordCount,
+		&note.ReadingTimeMinutes,
+		&note.IsDeleted,
+		&note.DeletedAt,
+		&note.CreatedAt,
+		&note.UpdatedAt,
+		&note.LastAccessedAt,
+		&note.AccessCount,
+		&note.Metadata,
+		&note.Encrypted,
+	)
+
+	if err == pgx.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find note by link title: %w", err)
+	}
+
+	return note, nil
+}
+
+// ListDailyNotes lists the daily notes dated from..to (YYYY-MM-DD), oldest first
+// Daily notes are found by their title, the newest one when a date has several.
+func (r *noteRepository) ListDailyNotes(ctx context.Context, userID uuid.UUID, titlePrefix, from, to string) ([]*model.DailyNoteDay, error) {
+	query := `
+		SELECT DISTINCT ON (title) id, title, word_count
+		FROM notes
+		WHERE user_id = $1 AND is_deleted = false
+		  AND title BETWEEN $2 AND $3 AND length(title) = length($2)
+		ORDER BY title ASC, created_at DESC
+	`
+
+	rows, err := r.db.readConn().Query(ctx, query, userID, titlePrefix+from, titlePrefix+to)
+	if err != nil {
+		return nil, fmt.Errorf("list daily notes: %w", err)
+	}
+
+	return collectDailyNotes(rows, titlePrefix)
+}
+
+// collectDailyNotes scans the ID, title and word count of daily notes titled titlePrefix + date
+func collectDailyNotes(rows pgx.Rows, titlePrefix string) ([]*model.DailyNoteDay, error) {
+	defer rows.Close()
+
+	days := []*model.DailyNoteDay{}
+	for rows.Next() {
+		day := &model.DailyNoteDay{}
+		var title string
+		if err := rows.Scan(&day.NoteID, &title, &day.WordCount); err != nil {
+			return nil, fmt.Errorf("scan daily note: %w", err)
+		}
+		day.Date = strings.TrimPrefix(title, titlePrefix)
+		days = append(days, day)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("iterate daily notes: %w", rows.Err())
+	}
+
+	return days, nil
+}
+
 // List lists notes for a user with pagination
-func (r *NoteRepository) List(ctx context.Context, userID uuid.UUID, filter model.NoteFilter) ([]*model.Note, int64, error) {
+func (r *noteRepository) List(ctx context.Context, userID uuid.UUID, filter model.NoteFilter) ([]*model.Note, int64, error) {
+	return r.list(ctx, userID, filter, postgresNoteQueries)
+}
+
+// list runs List with the query builders of the database
+func (r *noteRepository) list(ctx context.Context, userID uuid.UUID, filter model.NoteFilter, q noteQueries) ([]*model.Note, int64, error) {
 	// Build the base query
 	baseQuery := `
 		SELECT id, user_id, title, content, note_type, word_count, reading_time_minutes,
-		       is_deleted, deleted_at, created_at, updated_at, last_accessed_at, access_count, metadata
+		       is_deleted, deleted_at, created_at, updated_at, last_accessed_at, access_count, metadata, encrypted` +
+		q.includeColumns(filter) + `
 		FROM notes
 		WHERE user_id = $1 AND is_deleted = false
 	`
@@ -159,64 +526,608 @@ func (r *NoteRepository) List(ctx context.Context, userID uuid.UUID, filter mode
 		WHERE user_id = $1 AND is_deleted = false
 	`
 
+	filterClause, args := q.filterClause(userID, filter)
+	baseQuery += filterClause
+	countQuery += filterClause
+
+	// Get total count (use same args as base query, before sorting and pagination)
+	var total int64
+	countArgs := args
+	countErr := r.db.readConn().QueryRow(ctx, countQuery, countArgs...).Scan(&total)
+	if countErr != nil {
+		return nil, 0, fmt.Errorf("count notes: %w", countErr)
+	}
+
+	// Add sorting
+	orderClause, args := q.orderClause(filter, args)
+	baseQuery += orderClause
+	argPos := len(args) + 1
+
+	// Add pagination
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	offset := (filter.Page - 1) * limit
+	baseQuery += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argPos, argPos+1)
+	args = append(args, limit, offset)
+
+	// Execute query
+	rows, err := r.db.readConn().Query(ctx, baseQuery, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list notes: %w", err)
+	}
+	defer rows.Close()
+
+	notes := []*model.Note{}
+	for rows.Next() {
+		note := &model.Note{}
+		err := rows.Scan(append([]any{
+			&note.ID,
+			&note.UserID,
+			&note.Title,
+			&note.Content,
+			&note.NoteType,
+			&note.WordCount,
+			&note.ReadingTimeMinutes,
+			&note.IsDeleted,
+			&note.DeletedAt,
+			&note.CreatedAt,
+			&note.UpdatedAt,
+			&note.LastAccessedAt,
+			&note.AccessCount,
+			&note.Metadata,
+			&note.Encrypted,
+		}, noteIncludeDest(note, filter)...)...)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scan note: %w", err)
+		}
+		notes = append(notes, note)
+	}
+
+	if rows.Err() != nil {
+		return nil, 0, fmt.Errorf("iterate notes: %w", rows.Err())
+	}
+
+	return notes, total, nil
+}
+
+// Stream calls fn for every note matching the filter, without pagination
+// Rows are read from the database one at a time, so memory use doesn't grow with the number of notes.
+// Iteration stops at the first error returned by fn.
+func (r *noteRepository) Stream(ctx context.Context, userID uuid.UUID, filter model.NoteFilter, fn func(*model.Note) error) error {
+	return r.stream(ctx, userID, filter, postgresNoteQueries, fn)
+}
+
+// stream runs Stream with the query builders of the database
+func (r *noteRepository) stream(ctx context.Context, userID uuid.UUID, filter model.NoteFilter, q noteQueries, fn func(*model.Note) error) error {
+	query := `
+		SELECT id, user_id, title, content, note_type, word_count, reading_time_minutes,
+		       is_deleted, deleted_at, created_at, updated_at, last_accessed_at, access_count, metadata, encrypted` +
+		q.includeColumns(filter) + `
+		FROM notes
+		WHERE user_id = $1 AND is_deleted = false
+	`
+
+	filterClause, args := q.filterClause(userID, filter)
+	orderClause, args := q.orderClause(filter, args)
+	query += filterClause + orderClause
+
+	rows, err := r.db.readConn().Query(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("stream notes: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		note := &model.Note{}
+		err := rows.Scan(append([]any{
+			&note.ID,
+			&note.UserID,
+			&note.Title,
+			&note.Content,
+			&note.NoteType,
+			&note.WordCount,
+			&note.ReadingTimeMinutes,
+			&note.IsDeleted,
+			&note.DeletedAt,
+			&note.CreatedAt,
+			&note.UpdatedAt,
+			&note.LastAccessedAt,
+			&note.AccessCount,
+			&note.Metadata,
+			&note.Encrypted,
+		}, noteIncludeDest(note, filter)...)...)
+		if err != nil {
+			return fmt.Errorf("scan note: %w", err)
+		}
+		if err := fn(note); err != nil {
+			return err
+		}
+	}
+
+	if rows.Err() != nil {
+		return fmt.Errorf("iterate notes: %w", rows.Err())
+	}
+
+	return nil
+}
+
+// Weights of the related note signals, summing to 1
+const (
+	relatedWeightTags  = 0.3
+	relatedWeightLinks = 0.3
+	relatedWeightText  = 0.4
+)
+
+// FindRelated finds the notes most related to a note, best first
+// Notes score by the tags they share, the notes both link with (a direct link between
+// the two counts too) and the cosine similarity of their tsvectors, using term frequencies.
+// Only the title and content words are compared, since shared tags are scored already.
+// Each signal adds up to relatedWeight* of the 0-1 score, with the counts saturating.
+// Notes with nothing in common are left out.
+func (r *noteRepository) FindRelated(ctx context.Context, userID, noteID uuid.UUID, limit int) ([]*model.RelatedNote, error) {
+	query := `
+		WITH target_terms AS (
+			SELECT t.lexeme, COALESCE(array_length(t.positions, 1), 1) AS tf
+			FROM notes n, unnest(ts_filter(n.content_tsv, '{a,b}')) t
+			WHERE n.id = $2 AND n.user_id = $1 AND n.is_deleted = false
+		),
+		target_norm AS (
+			SELECT sqrt(SUM(tf * tf)) AS norm FROM target_terms
+		),
+		text_similarity AS (
+			SELECT n.id,
+			       SUM(COALESCE(tt.tf, 0) * COALESCE(array_length(c.positions, 1), 1))::float8 /
+			       NULLIF(sqrt(SUM(COALESCE(array_length(c.positions, 1), 1) ^ 2)) * (SELECT norm FROM target_norm), 0) AS similarity
+			FROM notes n
+			CROSS JOIN unnest(ts_filter(n.content_tsv, '{a,b}')) c
+			LEFT JOIN target_terms tt ON tt.lexeme = c.lexeme
+			WHERE n.user_id = $1 AND n.is_deleted = false AND n.id <> $2
+			GROUP BY n.id
+		),
+		shared_tags AS (
+			SELECT nt.note_id AS id, COUNT(*) AS shared
+			FROM note_tags nt
+			WHERE nt.tag_id IN (SELECT tag_id FROM note_tags WHERE note_id = $2) AND nt.note_id <> $2
+			GROUP BY nt.note_id
+		),
+		neighbors AS (
+			SELECT target_note_id AS id FROM links WHERE source_note_id = $2 AND target_note_id IS NOT NULL
+			UNION
+			SELECT source_note_id FROM links WHERE target_note_id = $2 AND source_note_id IS NOT NULL
+		),
+		shared_links AS (
+			SELECT other AS id, COUNT(DISTINCT via) AS shared
+			FROM (
+				SELECT l.source_note_id AS other, l.target_note_id AS via
+				FROM links l WHERE l.target_note_id IN (SELECT id FROM neighbors)
+				UNION ALL
+				SELECT l.target_note_id, l.source_note_id
+				FROM links l WHERE l.source_note_id IN (SELECT id FROM neighbors)
+				UNION ALL
+				SELECT id, $2 FROM neighbors
+			) s
+			WHERE other IS NOT NULL AND other <> $2
+			GROUP BY other
+		)
+		SELECT * FROM (
+			SELECT n.id, n.user_id, n.title, n.content, n.note_type, n.word_count, n.reading_time_minutes,
+			       n.is_deleted, n.deleted_at, n.created_at, n.updated_at, n.last_accessed_at, n.access_count, n.metadata, n.encrypted,
+			       $3::float8 * (1 - 1.0 / (1 + COALESCE(st.shared, 0))) +
+			       $4::float8 * (1 - 1.0 / (1 + COALESCE(sl.shared, 0))) +
+			       $5::float8 * COALESCE(ts.similarity, 0) AS score,
+			       COALESCE(st.shared, 0) AS shared_tags,
+			       COALESCE(sl.shared, 0) AS shared_links,
+			       COALESCE(ts.similarity, 0) AS text_similarity
+			FROM notes n
+			LEFT JOIN shared_tags st ON st.id = n.id
+			LEFT JOIN shared_links sl ON sl.id = n.id
+			LEFT JOIN text_similarity ts ON ts.id = n.id
+			WHERE n.user_id = $1 AND n.is_deleted = false AND n.id <> $2
+		) related
+		WHERE score > 0
+		ORDER BY score DESC, updated_at DESC
+		LIMIT $6
+	`
+
+	rows, err := r.db.readConn().Query(ctx, query, userID, noteID,
+		relatedWeightTags, relatedWeightLinks, relatedWeightText, limit)
+	if err != nil {
+		return nil, fmt.Errorf("find related notes: %w", err)
+	}
+
+	return collectRelated(rows)
+}
+
+// collectRelated scans related notes followed by their score and what they have in common
+func collectRelated(rows pgx.Rows) ([]*model.RelatedNote, error) {
+	defer rows.Close()
+
+	related := []*model.RelatedNote{}
+	for rows.Next() {
+		note := &model.Note{}
+		result := &model.RelatedNote{Note: note}
+		err := rows.Scan(
+			&note.ID,
+			&note.UserID,
+			&note.Title,
+			&note.Content,
+			&note.NoteType,
+			&note.WordCount,
+			&note.ReadingTimeMinutes,
+			&note.IsDeleted,
+			&note.DeletedAt,
+			&note.CreatedAt,
+			&note.UpdatedAt,
+			&note.LastAccessedAt,
+			&note.AccessCount,
+			&note.Metadata,
+			&note.Encrypted,
+			&result.Score,
+			&result.SharedTags,
+			&result.SharedLinks,
+			&result.TextSimilarity,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan related note: %w", err)
+		}
+		related = append(related, result)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("iterate related notes: %w", rows.Err())
+	}
+
+	return related, nil
+}
+
+// Search lists the notes matching filter.Search with their rank and a highlighted snippet
+// Snippets come from ts_headline: up to two fragments of about fragmentSize words, with
+// the matched words wrapped in model.HighlightStart and model.HighlightStop.
+// Encrypted notes only have their title indexed, so they get an empty snippet.
+func (r *noteRepository) Search(ctx context.Context, userID uuid.UUID, filter model.NoteFilter, fragmentSize int) ([]*model.SearchResult, int64, error) {
+	countQuery := `
+		SELECT COUNT(*)
+		FROM notes
+		WHERE user_id = $1 AND is_deleted = false
+	`
+
+	filterClause, args := noteFilterClause(userID, filter)
+	countQuery += filterClause
+
+	var total int64
+	if err := r.db.readConn().QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count notes: %w", err)
+	}
+
+	// ts_rank normalization 32 scales the rank into 0-1
+	args = append(args, filter.Search, headlineOptions(fragmentSize))
+	queryPos, optionsPos := len(args)-1, len(args)
+	query := fmt.Sprintf(`
+		SELECT id, user_id, title, content, note_type, word_count, reading_time_minutes,
+		       is_deleted, deleted_at, created_at, updated_at, last_accessed_at, access_count, metadata, encrypted,
+		       ts_rank(%[3]s, content_tsv, plainto_tsquery('english', $%[1]d), 32)::float8,
+		       CASE WHEN encrypted THEN ''
+		            ELSE ts_headline('english', content, plainto_tsquery('english', $%[1]d), $%[2]d)
+		       END
+		FROM notes
+		WHERE user_id = $1 AND is_deleted = false
+	`, queryPos, optionsPos, searchRankWeights) + filterClause
+
+	orderClause, args := noteOrderClause(filter, args)
+	query += orderClause
+	argPos := len(args) + 1
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	offset := (filter.Page - 1) * limit
+	query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argPos, argPos+1)
+	args = append(args, limit, offset)
+
+	rows, err := r.db.readConn().Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("search notes: %w", err)
+	}
+
+	results, err := collectSearchResults(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return results, total, nil
+}
+
+// collectSearchResults scans notes followed by their rank and snippet
+func collectSearchResults(rows pgx.Rows) ([]*model.SearchResult, error) {
+	defer rows.Close()
+
+	results := []*model.SearchResult{}
+	for rows.Next() {
+		note := &model.Note{}
+		result := &model.SearchResult{Note: note}
+		err := rows.Scan(
+			&note.ID,
+			&note.UserID,
+			&note.Title,
+			&note.Content,
+			&note.NoteType,
+			&note.WordCount,
+			&note.ReadingTimeMinutes,
+			&note.IsDeleted,
+			&note.DeletedAt,
+			&note.CreatedAt,
+			&note.UpdatedAt,
+			&note.LastAccessedAt,
+			&note.AccessCount,
+			&note.Metadata,
+			&note.Encrypted,
+			&result.Rank,
+			&result.Snippet,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan search result: %w", err)
+		}
+		results = append(results, result)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("iterate search results: %w", rows.Err())
+	}
+
+	return results, nil
+}
+
+// FuzzySearch lists the notes whose title is similar to filter.Search, for typo-tolerant search
+// Titles score by pg_trgm's word_similarity, the best match of the query against any part of
+// the title, so "kubernets" still finds "Kubernetes cluster notes" while a whole-title
+// similarity would be diluted by the other words. Only titles at or above the
+// pg_trgm.word_similarity_threshold (0.6 by default) are listed, using the title trigram index.
+// Snippets are the start of the note, since nothing matched its content.
+func (r *noteRepository) FuzzySearch(ctx context.Context, userID uuid.UUID, filter model.NoteFilter, fragmentSize int) ([]*model.SearchResult, int64, error) {
+	query := filter.Search
+	filter.Search = ""
+	filterClause, args := noteFilterClause(userID, filter)
+
+	args = append(args, query)
+	queryPos := len(args)
+	filterClause += fmt.Sprintf(" AND $%d <%% title", queryPos)
+
+	var total int64
+	countQuery := `
+		SELECT COUNT(*)
+		FROM notes
+		WHERE user_id = $1 AND is_deleted = false
+	` + filterClause
+	if err := r.db.readConn().QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count notes: %w", err)
+	}
+
+	args = append(args, headlineOptions(fragmentSize))
+	sql := fmt.Sprintf(`
+		SELECT id, user_id, title, content, note_type, word_count, reading_time_minutes,
+		       is_deleted, deleted_at, created_at, updated_at, last_accessed_at, access_count, metadata, encrypted,
+		       word_similarity($%[1]d, title)::float8,
+		       CASE WHEN encrypted THEN ''
+		            ELSE ts_headline('english', content, plainto_tsquery('english', $%[1]d), $%[2]d)
+		       END
+		FROM notes
+		WHERE user_id = $1 AND is_deleted = false
+	`, queryPos, len(args)) + filterClause
+
+	// Relevance means the most similar titles first
+	if filter.SortBy == model.SortRelevance {
+		sortOrder := "DESC"
+		if filter.SortOrder == "asc" {
+			sortOrder = "ASC"
+		}
+		sql += fmt.Sprintf(" ORDER BY word_similarity($%d, title) %s, created_at DESC", queryPos, sortOrder)
+	} else {
+		var orderClause string
+		orderClause, args = noteOrderClause(filter, args)
+		sql += orderClause
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	offset := (filter.Page - 1) * limit
+	sql += fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	args = append(args, limit, offset)
+
+	rows, err := r.db.readConn().Query(ctx, sql, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("fuzzy search notes: %w", err)
+	}
+
+	results, err := collectSearchResults(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return results, total, nil
+}
+
+// searchRankWeights are the ts_rank weights of the search vector's D, C, B and A labels
+// A title match counts well above a body match, so it ranks first even against a
+// long note that mentions the term a few times; tags (C) and attachment filenames (D)
+// lift a note a little.
+const searchRankWeights = "'{0.05, 0.2, 0.3, 1.0}'"
+
+// headlineOptions builds the ts_headline options for snippets of about fragmentSize words
+func headlineOptions(fragmentSize int) string {
+	return fmt.Sprintf("StartSel=%s, StopSel=%s, MaxFragments=2, MaxWords=%d, MinWords=%d, FragmentDelimiter=\" ... \"",
+		model.HighlightStart, model.HighlightStop, fragmentSize, fragmentSize/2)
+}
+
+// noteQueries build the parts of note list queries written differently for each database
+type noteQueries struct {
+	filterClause   func(userID uuid.UUID, filter model.NoteFilter) (string, []any)
+	orderClause    func(filter model.NoteFilter, args []any) (string, []any)
+	includeColumns func(filter model.NoteFilter) string
+}
+
+// postgresNoteQueries are the note query builders of PostgreSQL
+var postgresNoteQueries = noteQueries{
+	filterClause:   noteFilterClause,
+	orderClause:    noteOrderClause,
+	includeColumns: noteIncludeColumns,
+}
+
+// noteIncludeColumns returns the extra select columns for the related data a filter asks for
+// Tags are aggregated as JSON and link counts are subqueries, so the list stays one query.
+func noteIncludeColumns(filter model.NoteFilter) string {
+	columns := ""
+	if filter.IncludeTags {
+		columns += `,
+		       COALESCE((
+		           SELECT json_agg(json_build_object(
+		               'id', t.id, 'user_id', t.user_id, 'name', t.name, 'color', t.color,
+		               'parent_id', t.parent_id, 'created_at', t.created_at
+		           ) ORDER BY t.name)
+		           FROM note_tags nt
+		           INNER JOIN tags t ON t.id = nt.tag_id
+		           WHERE nt.note_id = notes.id
+		       ), '[]'::json)`
+	}
+	if filter.IncludeLinkCounts {
+		columns += `,
+		       (SELECT COUNT(*) FROM links l INNER JOIN notes tn ON tn.id = l.target_note_id AND tn.is_deleted = false
+		        WHERE l.source_note_id = notes.id),
+		       (SELECT COUNT(*) FROM links l INNER JOIN notes sn ON sn.id = l.source_note_id AND sn.is_deleted = false
+		        WHERE l.target_note_id = notes.id)`
+	}
+	return columns
+}
+
+// noteIncludeDest returns the scan destinations matching noteIncludeColumns
+func noteIncludeDest(note *model.Note, filter model.NoteFilter) []any {
+	var dest []any
+	if filter.IncludeTags {
+		dest = append(dest, &note.Tags)
+	}
+	if filter.IncludeLinkCounts {
+		note.LinkCounts = &model.LinkCounts{}
+		dest = append(dest, &note.LinkCounts.Outgoing, &note.LinkCounts.Incoming)
+	}
+	return dest
+}
+
+// noteFilterClause builds the WHERE conditions of a note filter
+// The returned args start with userID ($1), matching the base list query.
+func noteFilterClause(userID uuid.UUID, filter model.NoteFilter) (string, []any) {
+	clause := ""
 	args := []any{userID}
 	argPos := 2
 
-	// Add filters
 	if filter.NoteType != nil {
-		baseQuery += fmt.Sprintf(" AND note_type = $%d", argPos)
-		countQuery += fmt.Sprintf(" AND note_type = $%d", argPos)
+		clause += fmt.Sprintf(" AND note_type = $%d", argPos)
 		args = append(args, *filter.NoteType)
 		argPos++
 	}
 
 	if filter.TagID != nil {
-		baseQuery += fmt.Sprintf(" AND id IN (SELECT note_id FROM note_tags WHERE tag_id = $%d)", argPos)
-		countQuery += fmt.Sprintf(" AND id IN (SELECT note_id FROM note_tags WHERE tag_id = $%d)", argPos)
+		// Filtering by a parent tag includes notes tagged with any descendant
+		clause += " AND id IN (SELECT note_id FROM note_tags WHERE tag_id IN (" + tagTreeQuery(fmt.Sprintf("$%d", argPos)) + "))"
 		args = append(args, *filter.TagID)
 		argPos++
 	}
 
+	if filter.Status != nil {
+		if *filter.Status == model.NoteStatusAny {
+			clause += " AND metadata->>'status' IS NOT NULL"
+		} else {
+			clause += fmt.Sprintf(" AND metadata->>'status' = $%d", argPos)
+			args = append(args, *filter.Status)
+			argPos++
+		}
+	}
+
 	if filter.Search != "" {
-		baseQuery += fmt.Sprintf(" AND content_tsv @@ plainto_tsquery('english', $%d)", argPos)
-		countQuery += fmt.Sprintf(" AND content_tsv @@ plainto_tsquery('english', $%d)", argPos)
+		clause += fmt.Sprintf(" AND content_tsv @@ plainto_tsquery('english', $%d)", argPos)
 		args = append(args, filter.Search)
-		argPos++
 	}
 
-	// Get total count (use same args as base query, before pagination)
-	var total int64
-	countArgs := args
-	countErr := r.db.Pool.QueryRow(ctx, countQuery, countArgs...).Scan(&total)
-	if countErr != nil {
-		return nil, 0, fmt.Errorf("count notes: %w", countErr)
-	}
+	return clause, args
+}
 
-	// Add sorting
-	sortBy := "created_at"
-	if filter.SortBy != "" {
-		sortBy = filter.SortBy
-	}
+// noteSortColumns maps the accepted sort_by values to their SQL expression
+// Only these are ever written into a query, so SortBy can't inject SQL.
+var noteSortColumns = map[string]string{
+	model.SortCreatedAt:   "created_at",
+	model.SortUpdatedAt:   "updated_at",
+	model.SortTitle:       "title",
+	model.SortAccessCount: "access_count",
+	model.SortWordCount:   "word_count",
+}
+
+// noteOrderClause builds the ORDER BY clause of a note filter
+// Sorting by relevance ranks full-text matches, adding the search term to args;
+// without a search term it falls back to the newest notes first.
+func noteOrderClause(filter model.NoteFilter, args []any) (string, []any) {
 	sortOrder := "DESC"
 	if filter.SortOrder == "asc" {
 		sortOrder = "ASC"
 	}
-	baseQuery += fmt.Sprintf(" ORDER BY %s %s", sortBy, sortOrder)
 
-	// Add pagination
-	limit := filter.Limit
-	if limit <= 0 {
-		limit = 20
+	if filter.SortBy == model.SortRelevance && filter.Search != "" {
+		args = append(args, filter.Search)
+		return fmt.Sprintf(" ORDER BY ts_rank(%s, content_tsv, plainto_tsquery('english', $%d)) %s, created_at DESC",
+			searchRankWeights, len(args), sortOrder), args
 	}
-	offset := (filter.Page - 1) * limit
-	baseQuery += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argPos, argPos+1)
-	args = append(args, limit, offset)
 
-	// Execute query
-	rows, err := r.db.Pool.Query(ctx, baseQuery, args...)
+	sortBy, ok := noteSortColumns[filter.SortBy]
+	if !ok {
+		sortBy = "created_at"
+	}
+	return fmt.Sprintf(" ORDER BY %s %s", sortBy, sortOrder), args
+}
+
+// ListReading lists the notes a user started reading but didn't finish, most recently read first
+// The jsonpath match skips read_progress values that aren't numbers, e.g. ones set by hand.
+// Progress is saved with second precision in UTC, so its timestamps sort as text.
+func (r *noteRepository) ListReading(ctx context.Context, userID uuid.UUID, limit int) ([]*model.Note, error) {
+	query := `
+		SELECT id, user_id, title, content, note_type, word_count, reading_time_minutes,
+		       is_deleted, deleted_at, created_at, updated_at, last_accessed_at, access_count, metadata, encrypted
+		FROM notes
+		WHERE user_id = $1 AND is_deleted = false
+		  AND metadata @? '$.` + model.MetadataReadProgress + `.percent ? (@ > 0 && @ < 100)'
+		ORDER BY metadata->'` + model.MetadataReadProgress + `'->>'updated_at' DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.readConn().Query(ctx, query, userID, limit)
 	if err != nil {
-		return nil, 0, fmt.Errorf("list notes: %w", err)
+		return nil, fmt.Errorf("list notes being read: %w", err)
 	}
+
+	return collectNotes(rows)
+}
+
+// ListAll lists every non-deleted note for a user without pagination
+func (r *noteRepository) ListAll(ctx context.Context, userID uuid.UUID) ([]*model.Note, error) {
+	query := `
+		SELECT id, user_id, title, content, note_type, word_count, reading_time_minutes,
+		       is_deleted, deleted_at, created_at, updated_at, last_accessed_at, access_count, metadata, encrypted
+		FROM notes
+		WHERE user_id = $1 AND is_deleted = false
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.readConn().Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list all notes: %w", err)
+	}
+
+	return collectNotes(rows)
+}
+
+// collectNotes scans rows of the note columns
+func collectNotes(rows pgx.Rows) ([]*model.Note, error) {
 	defer rows.Close()
 
 	notes := []*model.Note{}
@@ -237,37 +1148,49 @@ func (r *NoteRepository) List(ctx context.Context, userID uuid.UUID, filter mode
 			&note.LastAccessedAt,
 			&note.AccessCount,
 			&note.Metadata,
+			&note.Encrypted,
 		)
 		if err != nil {
-			return nil, 0, fmt.Errorf("scan note: %w", err)
+			return nil, fmt.Errorf("scan note: %w", err)
 		}
 		notes = append(notes, note)
 	}
 
 	if rows.Err() != nil {
-		return nil, 0, fmt.Errorf("iterate notes: %w", rows.Err())
+		return nil, fmt.Errorf("iterate notes: %w", rows.Err())
 	}
 
-	return notes, total, nil
+	return notes, nil
 }
 
-// Update updates a note
-func (r *NoteRepository) Update(ctx context.Context, note *model.Note) error {
+// Update updates a note the user owns or may edit through a share
+func (r *noteRepository) Update(ctx context.Context, userID uuid.UUID, note *model.Note) error {
+	words, minutes := noteMetrics(note.Content)
 	query := `
 		UPDATE notes
 		SET title = COALESCE($1, title),
 		    content = COALESCE($2, content),
+		    encrypted = $5,
+		    note_type = $6,
+		    metadata = $7,
+		    word_count = $8,
+		    reading_time_minutes = $9,
 		    updated_at = NOW()
-		WHERE id = $3 AND user_id = $4 AND is_deleted = false
+		WHERE id = $3 AND is_deleted = false AND ` + noteAccessCondition("$4", model.SharePermissionWrite) + `
 		RETURNING id, user_id, title, content, note_type, word_count, reading_time_minutes,
-		          is_deleted, deleted_at, created_at, updated_at, last_accessed_at, access_count, metadata
+		          is_deleted, deleted_at, created_at, updated_at, last_accessed_at, access_count, metadata, encrypted
 	`
 
-	err := r.db.Pool.QueryRow(ctx, query,
+	err := r.db.conn().QueryRow(ctx, query,
 		note.Title,
 		note.Content,
 		note.ID,
-		note.UserID,
+		userID,
+		note.Encrypted,
+		note.NoteType,
+		note.Metadata,
+		words,
+		minutes,
 	).Scan(
 		&note.ID,
 		&note.UserID,
@@ -283,6 +1206,7 @@ func (r *NoteRepository) Update(ctx context.Context, note *model.Note) error {
 		&note.LastAccessedAt,
 		&note.AccessCount,
 		&note.Metadata,
+		&note.Encrypted,
 	)
 
 	if err == pgx.ErrNoRows {
@@ -296,14 +1220,14 @@ func (r *NoteRepository) Update(ctx context.Context, note *model.Note) error {
 }
 
 // Delete soft deletes a note
-func (r *NoteRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
+func (r *noteRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
 	query := `
 		UPDATE notes
 		SET is_deleted = true, deleted_at = NOW()
 		WHERE id = $1 AND user_id = $2 AND is_deleted = false
 	`
 
-	result, err := r.db.Pool.Exec(ctx, query, id, userID)
+	result, err := r.db.conn().Exec(ctx, query, id, userID)
 	if err != nil {
 		return fmt.Errorf("delete note: %w", err)
 	}
@@ -316,14 +1240,14 @@ func (r *NoteRepository) Delete(ctx context.Context, userID, id uuid.UUID) error
 }
 
 // Restore restores a soft deleted note
-func (r *NoteRepository) Restore(ctx context.Context, userID, id uuid.UUID) error {
+func (r *noteRepository) Restore(ctx context.Context, userID, id uuid.UUID) error {
 	query := `
 		UPDATE notes
 		SET is_deleted = false, deleted_at = NULL
 		WHERE id = $1 AND user_id = $2 AND is_deleted = true
 	`
 
-	result, err := r.db.Pool.Exec(ctx, query, id, userID)
+	result, err := r.db.conn().Exec(ctx, query, id, userID)
 	if err != nil {
 		return fmt.Errorf("restore note: %w", err)
 	}
@@ -335,8 +1259,28 @@ func (r *NoteRepository) Restore(ctx context.Context, userID, id uuid.UUID) erro
 	return nil
 }
 
+// SetMetadata stores value under key in a note's metadata, keeping the other keys
+func (r *noteRepository) SetMetadata(ctx context.Context, userID, id uuid.UUID, key string, value any) error {
+	query := `
+		UPDATE notes
+		SET metadata = COALESCE(metadata, '{}'::jsonb) || jsonb_build_object($3::text, $4::jsonb)
+		WHERE id = $1 AND user_id = $2 AND is_deleted = false
+	`
+
+	result, err := r.db.conn().Exec(ctx, query, id, userID, key, value)
+	if err != nil {
+		return fmt.Errorf("set note metadata: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
 // UpdateAccessCount updates the access count and last accessed time
-func (r *NoteRepository) UpdateAccessCount(ctx context.Context, userID, id uuid.UUID) error {
+func (r *noteRepository) UpdateAccessCount(ctx context.Context, userID, id uuid.UUID) error {
 	query := `
 		UPDATE notes
 		SET access_count = access_count + 1,
@@ -344,7 +1288,7 @@ func (r *NoteRepository) UpdateAccessCount(ctx context.Context, userID, id uuid.
 		WHERE id = $1 AND user_id = $2
 	`
 
-	_, err := r.db.Pool.Exec(ctx, query, id, userID)
+	_, err := r.db.conn().Exec(ctx, query, id, userID)
 	if err != nil {
 		return fmt.Errorf("update access count: %w", err)
 	}
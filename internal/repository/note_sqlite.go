@@ -0,0 +1,481 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+// sqliteNoteRepository is the NoteRepository of SQLite databases
+// Full-text search uses the notes_fts FTS5 index of the SQLite schema in place of content_tsv.
+type sqliteNoteRepository struct {
+	*noteRepository
+}
+
+// sqliteNoteQueries are the note query builders of SQLite
+var sqliteNoteQueries = noteQueries{
+	filterClause:   sqliteNoteFilterClause,
+	orderClause:    sqliteNoteOrderClause,
+	includeColumns: sqliteNoteIncludeColumns,
+}
+
+// sqliteFuzzyThreshold is the least word similarity a title needs to be found by FuzzySearch,
+// pg_trgm's default word_similarity_threshold
+const sqliteFuzzyThreshold = 0.6
+
+// sqliteSnippetTokens is the most tokens FTS5 puts in a snippet
+const sqliteSnippetTokens = 64
+
+// LockByID checks that the user may edit a note
+// SQLite transactions hold the write lock of the whole database from the start, so there's no
+// row to lock.
+func (r *sqliteNoteRepository) LockByID(ctx context.Context, userID, id uuid.UUID) error {
+	query := `
+		SELECT id FROM notes
+		WHERE id = $1 AND is_deleted = false AND ` + noteAccessCondition("$2", model.SharePermissionWrite)
+
+	var found uuid.UUID
+	err := r.db.conn().QueryRow(ctx, query, id, userID).Scan(&found)
+	if err == pgx.ErrNoRows {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("lock note: %w", err)
+	}
+
+	return nil
+}
+
+// FindByIDs finds the user's notes with the given IDs in a single query
+// Missing or deleted notes are left out of the returned map.
+func (r *sqliteNoteRepository) FindByIDs(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) (map[uuid.UUID]*model.Note, error) {
+	notes := make(map[uuid.UUID]*model.Note, len(ids))
+	if len(ids) == 0 {
+		return notes, nil
+	}
+
+	query := `
+		SELECT id, user_id, title, content, note_type, word_count, reading_time_minutes,
+		       is_deleted, deleted_at, created_at, updated_at, last_accessed_at, access_count, metadata, encrypted
+		FROM notes
+		WHERE id IN (SELECT value FROM json_each($1)) AND user_id = $2 AND is_deleted = false
+	`
+
+	rows, err := r.db.conn().Query(ctx, query, ids, userID)
+	if err != nil {
+		return nil, fmt.Errorf("find notes by ids: %w", err)
+	}
+
+	found, err := collectNotes(rows)
+	if err != nil {
+		return nil, err
+	}
+	for _, note := range found {
+		notes[note.ID] = note
+	}
+
+	return notes, nil
+}
+
+// ListDailyNotes lists the daily notes dated from..to (YYYY-MM-DD), oldest first
+// Daily notes are found by their title, the newest one when a date has several.
+func (r *sqliteNoteRepository) ListDailyNotes(ctx context.Context, userID uuid.UUID, titlePrefix, from, to string) ([]*model.DailyNoteDay, error) {
+	query := `
+		SELECT id, title, word_count
+		FROM (
+			SELECT id, title, word_count, ROW_NUMBER() OVER (PARTITION BY title ORDER BY created_at DESC) AS newest
+			FROM notes
+			WHERE user_id = $1 AND is_deleted = false
+			  AND title BETWEEN $2 AND $3 AND length(title) = length($2)
+		) daily
+		WHERE newest = 1
+		ORDER BY title ASC
+	`
+
+	rows, err := r.db.readConn().Query(ctx, query, userID, titlePrefix+from, titlePrefix+to)
+	if err != nil {
+		return nil, fmt.Errorf("list daily notes: %w", err)
+	}
+
+	return collectDailyNotes(rows, titlePrefix)
+}
+
+// List lists notes for a user with pagination
+func (r *sqliteNoteRepository) List(ctx context.Context, userID uuid.UUID, filter model.NoteFilter) ([]*model.Note, int64, error) {
+	return r.list(ctx, userID, filter, sqliteNoteQueries)
+}
+
+// Stream calls fn for every note matching the filter, without pagination
+func (r *sqliteNoteRepository) Stream(ctx context.Context, userID uuid.UUID, filter model.NoteFilter, fn func(*model.Note) error) error {
+	return r.stream(ctx, userID, filter, sqliteNoteQueries, fn)
+}
+
+// FindRelated finds the notes most related to a note, best first
+// Notes score as in the Postgres FindRelated, with the words of their title and content
+// read from the notes_fts_terms vocabulary of the search index.
+func (r *sqliteNoteRepository) FindRelated(ctx context.Context, userID, noteID uuid.UUID, limit int) ([]*model.RelatedNote, error) {
+	query := `
+		WITH target_terms AS (
+			SELECT t.term, COUNT(*) AS tf
+			FROM notes n
+			INNER JOIN notes_fts_keys k ON k.note_id = n.id
+			INNER JOIN notes_fts_terms t ON t.doc = k.id AND t.col IN ('title', 'content')
+			WHERE n.id = $2 AND n.user_id = $1 AND n.is_deleted = false
+			GROUP BY t.term
+		),
+		target_norm AS (
+			SELECT sqrt(SUM(tf * tf)) AS norm FROM target_terms
+		),
+		note_terms AS (
+			SELECT n.id, t.term, COUNT(*) AS tf
+			FROM notes n
+			INNER JOIN notes_fts_keys k ON k.note_id = n.id
+			INNER JOIN notes_fts_terms t ON t.doc = k.id AND t.col IN ('title', 'content')
+			WHERE n.user_id = $1 AND n.is_deleted = false AND n.id <> $2
+			GROUP BY n.id, t.term
+		),
+		text_similarity AS (
+			SELECT c.id,
+			       CAST(SUM(COALESCE(tt.tf, 0) * c.tf) AS REAL) /
+			       NULLIF(sqrt(SUM(c.tf * c.tf)) * (SELECT norm FROM target_norm), 0) AS similarity
+			FROM note_terms c
+			LEFT JOIN target_terms tt ON tt.term = c.term
+			GROUP BY c.id
+		),
+		shared_tags AS (
+			SELECT nt.note_id AS id, COUNT(*) AS shared
+			FROM note_tags nt
+			WHERE nt.tag_id IN (SELECT tag_id FROM note_tags WHERE note_id = $2) AND nt.note_id <> $2
+			GROUP BY nt.note_id
+		),
+		neighbors AS (
+			SELECT target_note_id AS id FROM links WHERE source_note_id = $2 AND target_note_id IS NOT NULL
+			UNION
+			SELECT source_note_id FROM links WHERE target_note_id = $2 AND source_note_id IS NOT NULL
+		),
+		shared_links AS (
+			SELECT other AS id, COUNT(DISTINCT via) AS shared
+			FROM (
+				SELECT l.source_note_id AS other, l.target_note_id AS via
+				FROM links l WHERE l.target_note_id IN (SELECT id FROM neighbors)
+				UNION ALL
+				SELECT l.target_note_id, l.source_note_id
+				FROM links l WHERE l.source_note_id IN (SELECT id FROM neighbors)
+				UNION ALL
+				SELECT id, $2 FROM neighbors
+			) s
+			WHERE other IS NOT NULL AND other <> $2
+			GROUP BY other
+		)
+		SELECT * FROM (
+			SELECT n.id, n.user_id, n.title, n.content, n.note_type, n.word_count, n.reading_time_minutes,
+			       n.is_deleted, n.deleted_at, n.created_at, n.updated_at, n.last_accessed_at, n.access_count, n.metadata, n.encrypted,
+			       $3 * (1 - 1.0 / (1 + COALESCE(st.shared, 0))) +
+			       $4 * (1 - 1.0 / (1 + COALESCE(sl.shared, 0))) +
+			       $5 * COALESCE(ts.similarity, 0) AS score,
+			       COALESCE(st.shared, 0) AS shared_tags,
+			       COALESCE(sl.shared, 0) AS shared_links,
+			       COALESCE(ts.similarity, 0) AS text_similarity
+			FROM notes n
+			LEFT JOIN shared_tags st ON st.id = n.id
+			LEFT JOIN shared_links sl ON sl.id = n.id
+			LEFT JOIN text_similarity ts ON ts.id = n.id
+			WHERE n.user_id = $1 AND n.is_deleted = false AND n.id <> $2
+		) related
+		WHERE score > 0
+		ORDER BY score DESC, updated_at DESC
+		LIMIT $6
+	`
+
+	rows, err := r.db.readConn().Query(ctx, query, userID, noteID,
+		relatedWeightTags, relatedWeightLinks, relatedWeightText, limit)
+	if err != nil {
+		return nil, fmt.Errorf("find related notes: %w", err)
+	}
+
+	return collectRelated(rows)
+}
+
+// Search lists the notes matching filter.Search with their rank and a highlighted snippet
+// Snippets come from FTS5's snippet: the fragment of up to fragmentSize words (at most 64)
+// with the most matches, the matched words wrapped in model.HighlightStart and
+// model.HighlightStop. Encrypted notes only have their title indexed, so they get an empty snippet.
+func (r *sqliteNoteRepository) Search(ctx context.Context, userID uuid.UUID, filter model.NoteFilter, fragmentSize int) ([]*model.SearchResult, int64, error) {
+	countQuery := `
+		SELECT COUNT(*)
+		FROM notes
+		WHERE user_id = $1 AND is_deleted = false
+	`
+
+	filterClause, args := sqliteNoteFilterClause(userID, filter)
+	countQuery += filterClause
+
+	var total int64
+	if err := r.db.readConn().QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count notes: %w", err)
+	}
+
+	args = append(args, sqliteMatchQuery(filter.Search), min(fragmentSize, sqliteSnippetTokens))
+	matchPos, sizePos := len(args)-1, len(args)
+	query := `
+		SELECT id, user_id, title, content, note_type, word_count, reading_time_minutes,
+		       is_deleted, deleted_at, created_at, updated_at, last_accessed_at, access_count, metadata, encrypted,
+		       ` + sqliteSearchRank(matchPos) + `,
+		       ` + sqliteSnippet(matchPos, sizePos) + `
+		FROM notes
+		WHERE user_id = $1 AND is_deleted = false
+	` + filterClause
+
+	orderClause, args := sqliteNoteOrderClause(filter, args)
+	query += orderClause
+	argPos := len(args) + 1
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	offset := (filter.Page - 1) * limit
+	query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argPos, argPos+1)
+	args = append(args, limit, offset)
+
+	rows, err := r.db.readConn().Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("search notes: %w", err)
+	}
+
+	results, err := collectSearchResults(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return results, total, nil
+}
+
+// FuzzySearch lists the notes whose title is similar to filter.Search, for typo-tolerant search
+// Titles score by word_similarity, like the Postgres FuzzySearch, and need at least
+// sqliteFuzzyThreshold. Without an index every title of the user is compared.
+// Snippets highlight the query words the content has, or are the start of the note.
+func (r *sqliteNoteRepository) FuzzySearch(ctx context.Context, userID uuid.UUID, filter model.NoteFilter, fragmentSize int) ([]*model.SearchResult, int64, error) {
+	query := filter.Search
+	filter.Search = ""
+	filterClause, args := sqliteNoteFilterClause(userID, filter)
+
+	args = append(args, query)
+	queryPos := len(args)
+	filterClause += fmt.Sprintf(" AND word_similarity($%d, title) >= %v", queryPos, sqliteFuzzyThreshold)
+
+	var total int64
+	countQuery := `
+		SELECT COUNT(*)
+		FROM notes
+		WHERE user_id = $1 AND is_deleted = false
+	` + filterClause
+	if err := r.db.readConn().QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count notes: %w", err)
+	}
+
+	fragmentSize = min(fragmentSize, sqliteSnippetTokens)
+	args = append(args, sqliteMatchQuery(query), fragmentSize)
+	sql := fmt.Sprintf(`
+		SELECT id, user_id, title, content, note_type, word_count, reading_time_minutes,
+		       is_deleted, deleted_at, created_at, updated_at, last_accessed_at, access_count, metadata, encrypted,
+		       word_similarity($%d, title),
+		       %s
+		FROM notes
+		WHERE user_id = $1 AND is_deleted = false
+	`, queryPos, sqliteSnippet(len(args)-1, len(args))) + filterClause
+
+	// Relevance means the most similar titles first
+	if filter.SortBy == model.SortRelevance {
+		sortOrder := "DESC"
+		if filter.SortOrder == "asc" {
+			sortOrder = "ASC"
+		}
+		sql += fmt.Sprintf(" ORDER BY word_similarity($%d, title) %s, created_at DESC", queryPos, sortOrder)
+	} else {
+		var orderClause string
+		orderClause, args = sqliteNoteOrderClause(filter, args)
+		sql += orderClause
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	offset := (filter.Page - 1) * limit
+	sql += fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	args = append(args, limit, offset)
+
+	rows, err := r.db.readConn().Query(ctx, sql, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("fuzzy search notes: %w", err)
+	}
+
+	results, err := collectSearchResults(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// The typo may be the only thing the query has, leaving nothing to highlight
+	for _, result := range results {
+		if result.Snippet == "" && !result.Note.Encrypted {
+			result.Snippet = leadingWords(result.Note.Content, fragmentSize)
+		}
+	}
+
+	return results, total, nil
+}
+
+// ListReading lists the notes a user started reading but didn't finish, most recently read first
+// Progress values that aren't numbers, e.g. ones set by hand, are skipped.
+func (r *sqliteNoteRepository) ListReading(ctx context.Context, userID uuid.UUID, limit int) ([]*model.Note, error) {
+	percent := "'$." + model.MetadataReadProgress + ".percent'"
+	query := `
+		SELECT id, user_id, title, content, note_type, word_count, reading_time_minutes,
+		       is_deleted, deleted_at, created_at, updated_at, last_accessed_at, access_count, metadata, encrypted
+		FROM notes
+		WHERE user_id = $1 AND is_deleted = false
+		  AND json_type(metadata, ` + percent + `) IN ('integer', 'real')
+		  AND json_extract(metadata, ` + percent + `) > 0 AND json_extract(metadata, ` + percent + `) < 100
+		ORDER BY json_extract(metadata, '$.` + model.MetadataReadProgress + `.updated_at') DESC NULLS FIRST
+		LIMIT $2
+	`
+
+	rows, err := r.db.readConn().Query(ctx, query, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list notes being read: %w", err)
+	}
+
+	return collectNotes(rows)
+}
+
+// SetMetadata stores value under key in a note's metadata, keeping the other keys
+func (r *sqliteNoteRepository) SetMetadata(ctx context.Context, userID, id uuid.UUID, key string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("encode note metadata: %w", err)
+	}
+
+	query := `
+		UPDATE notes
+		SET metadata = json_set(COALESCE(metadata, '{}'), '$."' || $3 || '"', json($4))
+		WHERE id = $1 AND user_id = $2 AND is_deleted = false
+	`
+
+	result, err := r.db.conn().Exec(ctx, query, id, userID, key, string(data))
+	if err != nil {
+		return fmt.Errorf("set note metadata: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// sqliteNoteFilterClause builds the WHERE conditions of a note filter, like noteFilterClause
+func sqliteNoteFilterClause(userID uuid.UUID, filter model.NoteFilter) (string, []any) {
+	search := filter.Search
+	filter.Search = ""
+	clause, args := noteFilterClause(userID, filter)
+
+	if search != "" {
+		args = append(args, sqliteMatchQuery(search))
+		clause += fmt.Sprintf(` AND id IN (
+			SELECT k.note_id FROM notes_fts INNER JOIN notes_fts_keys k ON k.id = notes_fts.rowid
+			WHERE notes_fts MATCH $%d
+		)`, len(args))
+	}
+
+	return clause, args
+}
+
+// sqliteNoteOrderClause builds the ORDER BY clause of a note filter, like noteOrderClause
+func sqliteNoteOrderClause(filter model.NoteFilter, args []any) (string, []any) {
+	if filter.SortBy != model.SortRelevance || filter.Search == "" {
+		return noteOrderClause(filter, args)
+	}
+
+	sortOrder := "DESC"
+	if filter.SortOrder == "asc" {
+		sortOrder = "ASC"
+	}
+	args = append(args, sqliteMatchQuery(filter.Search))
+	return fmt.Sprintf(" ORDER BY %s %s, created_at DESC", sqliteSearchRank(len(args)), sortOrder), args
+}
+
+// sqliteNoteIncludeColumns returns the extra select columns for the related data a filter asks for
+func sqliteNoteIncludeColumns(filter model.NoteFilter) string {
+	columns := ""
+	if filter.IncludeTags {
+		columns += `,
+		       (
+		           SELECT json_group_array(json_object(
+		               'id', t.id, 'user_id', t.user_id, 'name', t.name, 'color', t.color,
+		               'parent_id', t.parent_id, 'created_at', t.created_at
+		           ) ORDER BY t.name)
+		           FROM note_tags nt
+		           INNER JOIN tags t ON t.id = nt.tag_id
+		           WHERE nt.note_id = notes.id
+		       )`
+	}
+
+	// Link counts are the same subqueries in both databases
+	filter.IncludeTags = false
+	return columns + noteIncludeColumns(filter)
+}
+
+// sqliteSearchRank is the 0-1 rank of a note for the FTS5 query at placeholder matchPos
+// The bm25 weights of the title, content, tags and attachments match searchRankWeights, and
+// the score s is scaled by s / (s + 1) like ts_rank normalization 32.
+func sqliteSearchRank(matchPos int) string {
+	return fmt.Sprintf(`(
+		SELECT score / (score + 1) FROM (
+			SELECT -bm25(notes_fts, 1.0, 0.3, 0.2, 0.05) AS score
+			FROM notes_fts INNER JOIN notes_fts_keys k ON k.id = notes_fts.rowid
+			WHERE notes_fts MATCH $%d AND k.note_id = notes.id
+		)
+	)`, matchPos)
+}
+
+// sqliteSnippet is the highlighted content snippet of a note for the FTS5 query at placeholder
+// matchPos, with up to the number of tokens at sizePos; empty when the note doesn't match
+func sqliteSnippet(matchPos, sizePos int) string {
+	return fmt.Sprintf(`CASE WHEN encrypted THEN '' ELSE COALESCE((
+		SELECT snippet(notes_fts, 1, '%s', '%s', ' ... ', $%d)
+		FROM notes_fts INNER JOIN notes_fts_keys k ON k.id = notes_fts.rowid
+		WHERE notes_fts MATCH $%d AND k.note_id = notes.id
+	), '') END`, model.HighlightStart, model.HighlightStop, sizePos, matchPos)
+}
+
+// sqliteMatchQuery turns a search into an FTS5 query matching notes with all of its words,
+// like plainto_tsquery. A search without words matches nothing.
+func sqliteMatchQuery(search string) string {
+	words := strings.FieldsFunc(search, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return `""`
+	}
+	for i, word := range words {
+		words[i] = `"` + word + `"`
+	}
+	return strings.Join(words, " ")
+}
+
+// leadingWords returns the first n words of content
+func leadingWords(content string, n int) string {
+	words := strings.Fields(content)
+	if len(words) > n {
+		words = words[:n]
+	}
+	return strings.Join(words, " ")
+}
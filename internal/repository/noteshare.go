@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+// NoteShareRepository handles data operations of notes shared between users
+type NoteShareRepository interface {
+	Upsert(ctx context.Context, share *model.NoteShare) (bool, error)
+	ListByNote(ctx context.Context, ownerID, noteID uuid.UUID) ([]*model.NoteShare, error)
+	Delete(ctx context.Context, ownerID, noteID, sharedWith uuid.UUID) error
+}
+
+// noteShareRepository implements NoteShareRepository
+type noteShareRepository struct {
+	db *DB
+}
+
+// NewNoteShareRepository creates a new note share repository
+func NewNoteShareRepository(db *DB) NoteShareRepository {
+	if db.sqlite != nil {
+		return &sqliteNoteShareRepository{noteShareRepository: &noteShareRepository{db: db}}
+	}
+	return &noteShareRepository{db: db}
+}
+
+// Upsert shares a note with a user, or changes the permission it is already shared with
+// It returns whether the share was created.
+func (r *noteShareRepository) Upsert(ctx context.Context, share *model.NoteShare) (bool, error) {
+	query := `
+		INSERT INTO note_shares (id, note_id, owner_id, shared_with, permission, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+		ON CONFLICT (note_id, shared_with) DO UPDATE
+		SET permission = EXCLUDED.permission, updated_at = EXCLUDED.updated_at
+		RETURNING id, created_at, updated_at, xmax = 0
+	`
+
+	var created bool
+	err := r.db.conn().QueryRow(ctx, query,
+		uuid.New(),
+		share.NoteID,
+		share.OwnerID,
+		share.SharedWith,
+		share.Permission,
+		time.Now(),
+	).Scan(&share.ID, &share.CreatedAt, &share.UpdatedAt, &created)
+	if err != nil {
+		return false, fmt.Errorf("upsert note share: %w", err)
+	}
+
+	return created, nil
+}
+
+// ListByNote lists the users a note of the owner is shared with, by username
+func (r *noteShareRepository) ListByNote(ctx context.Context, ownerID, noteID uuid.UUID) ([]*model.NoteShare, error) {
+	query := `
+		SELECT ns.id, ns.note_id, ns.owner_id, ns.shared_with, u.username, u.email,
+		       ns.permission, ns.created_at, ns.updated_at
+		FROM note_shares ns
+		INNER JOIN users u ON u.id = ns.shared_with
+		WHERE ns.note_id = $1 AND ns.owner_id = $2
+		ORDER BY u.username ASC
+	`
+
+	rows, err := r.db.readConn().Query(ctx, query, noteID, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("list note shares: %w", err)
+	}
+	defer rows.Close()
+
+	shares := []*model.NoteShare{}
+	for rows.Next() {
+		share := &model.NoteShare{}
+		err := rows.Scan(
+			&share.ID,
+			&share.NoteID,
+			&share.OwnerID,
+			&share.SharedWith,
+			&share.Username,
+			&share.Email,
+			&share.Permission,
+			&share.CreatedAt,
+			&share.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan note share: %w", err)
+		}
+		shares = append(shares, share)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("iterate note shares: %w", rows.Err())
+	}
+
+	return shares, nil
+}
+
+// Delete stops sharing a note of the owner with a user
+func (r *noteShareRepository) Delete(ctx context.Context, ownerID, noteID, sharedWith uuid.UUID) error {
+	query := `DELETE FROM note_shares WHERE note_id = $1 AND owner_id = $2 AND shared_with = $3`
+
+	result, err := r.db.conn().Exec(ctx, query, noteID, ownerID, sharedWith)
+	if err != nil {
+		return fmt.Errorf("delete note share: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
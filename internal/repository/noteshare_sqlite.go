@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+// sqliteNoteShareRepository is the NoteShareRepository of SQLite databases
+type sqliteNoteShareRepository struct {
+	*noteShareRepository
+}
+
+// Upsert shares a note with a user, or changes the permission it is already shared with
+// It returns whether the share was created. SQLite has no xmax, but a new share is the only
+// one updated at the time it was created.
+func (r *sqliteNoteShareRepository) Upsert(ctx context.Context, share *model.NoteShare) (bool, error) {
+	query := `
+		INSERT INTO note_shares (id, note_id, owner_id, shared_with, permission, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+		ON CONFLICT (note_id, shared_with) DO UPDATE
+		SET permission = excluded.permission, updated_at = excluded.updated_at
+		RETURNING id, created_at, updated_at, created_at = $6
+	`
+
+	var created bool
+	err := r.db.conn().QueryRow(ctx, query,
+		uuid.New(),
+		share.NoteID,
+		share.OwnerID,
+		share.SharedWith,
+		share.Permission,
+		time.Now(),
+	).Scan(&share.ID, &share.CreatedAt, &share.UpdatedAt, &created)
+	if err != nil {
+		return false, fmt.Errorf("upsert note share: %w", err)
+	}
+
+	return created, nil
+}
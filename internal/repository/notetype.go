@@ -0,0 +1,239 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+// NoteTypeRepository handles the custom note types of users
+type NoteTypeRepository struct {
+	db *DB
+}
+
+// NewNoteTypeRepository creates a new note type repository
+func NewNoteTypeRepository(db *DB) NoteTypeRepository {
+	return NoteTypeRepository{db: db}
+}
+
+// Create inserts a custom note type
+func (r *NoteTypeRepository) Create(ctx context.Context, userID uuid.UUID, noteType *model.NoteTypeDefinition) error {
+	query := `
+		INSERT INTO note_types (user_id, name, icon, color, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING created_at
+	`
+
+	createdAt := time.Now()
+	err := r.db.conn().QueryRow(ctx, query,
+		userID,
+		noteType.Name,
+		noteType.Icon,
+		noteType.Color,
+		createdAt,
+	).Scan(&createdAt)
+	if err != nil {
+		return fmt.Errorf("create note type: %w", err)
+	}
+
+	noteType.CreatedAt = &createdAt
+	return nil
+}
+
+// Find finds a custom note type by name
+func (r *NoteTypeRepository) Find(ctx context.Context, userID uuid.UUID, name model.NoteType) (*model.NoteTypeDefinition, error) {
+	query := `
+		SELECT t.name, t.icon, t.color, t.created_at,
+		       (SELECT COUNT(*) FROM notes n WHERE n.user_id = t.user_id AND n.note_type = t.name AND n.is_deleted = false)
+		FROM note_types t
+		WHERE t.user_id = $1 AND t.name = $2
+	`
+
+	noteType := &model.NoteTypeDefinition{}
+	err := r.db.conn().QueryRow(ctx, query, userID, name).Scan(
+		&noteType.Name,
+		&noteType.Icon,
+		&noteType.Color,
+		&noteType.CreatedAt,
+		&noteType.NoteCount,
+	)
+
+	if err == pgx.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find note type: %w", err)
+	}
+
+	return noteType, nil
+}
+
+// List lists the custom note types of a user, oldest first
+func (r *NoteTypeRepository) List(ctx context.Context, userID uuid.UUID) ([]*model.NoteTypeDefinition, error) {
+	query := `
+		SELECT name, icon, color, created_at
+		FROM note_types
+		WHERE user_id = $1
+		ORDER BY created_at ASC, name ASC
+	`
+
+	rows, err := r.db.readConn().Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list note types: %w", err)
+	}
+	defer rows.Close()
+
+	noteTypes := []*model.NoteTypeDefinition{}
+	for rows.Next() {
+		noteType := &model.NoteTypeDefinition{}
+		if err := rows.Scan(&noteType.Name, &noteType.Icon, &noteType.Color, &noteType.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan note type: %w", err)
+		}
+		noteTypes = append(noteTypes, noteType)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("iterate note types: %w", rows.Err())
+	}
+
+	return noteTypes, nil
+}
+
+// CountNotes counts the non-deleted notes of a user per type
+func (r *NoteTypeRepository) CountNotes(ctx context.Context, userID uuid.UUID) (map[model.NoteType]int64, error) {
+	query := `
+		SELECT note_type, COUNT(*) FROM notes
+		WHERE user_id = $1 AND is_deleted = false
+		GROUP BY note_type
+	`
+
+	rows, err := r.db.readConn().Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("count notes by type: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[model.NoteType]int64)
+	for rows.Next() {
+		var noteType model.NoteType
+		var count int64
+		if err := rows.Scan(&noteType, &count); err != nil {
+			return nil, fmt.Errorf("scan note type count: %w", err)
+		}
+		counts[noteType] = count
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("iterate note type counts: %w", rows.Err())
+	}
+
+	return counts, nil
+}
+
+// Update updates the icon and color of a custom note type
+func (r *NoteTypeRepository) Update(ctx context.Context, userID uuid.UUID, noteType *model.NoteTypeDefinition) error {
+	query := `
+		UPDATE note_types
+		SET icon = $1, color = $2
+		WHERE user_id = $3 AND name = $4
+	`
+
+	result, err := r.db.conn().Exec(ctx, query, noteType.Icon, noteType.Color, userID, noteType.Name)
+	if err != nil {
+		return fmt.Errorf("update note type: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// Delete deletes a custom note type
+func (r *NoteTypeRepository) Delete(ctx context.Context, userID uuid.UUID, name model.NoteType) error {
+	query := `DELETE FROM note_types WHERE user_id = $1 AND name = $2`
+
+	result, err := r.db.conn().Exec(ctx, query, userID, name)
+	if err != nil {
+		return fmt.Errorf("delete note type: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// ListFields returns the custom fields of each of a user's note types that has any
+func (r *NoteTypeRepository) ListFields(ctx context.Context, userID uuid.UUID) (map[model.NoteType][]*model.NoteTypeField, error) {
+	query := `SELECT note_type, fields FROM note_type_fields WHERE user_id = $1`
+
+	rows, err := r.db.readConn().Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list note type fields: %w", err)
+	}
+	defer rows.Close()
+
+	fields := make(map[model.NoteType][]*model.NoteTypeField)
+	for rows.Next() {
+		var noteType model.NoteType
+		var typeFields []*model.NoteTypeField
+		if err := rows.Scan(&noteType, &typeFields); err != nil {
+			return nil, fmt.Errorf("scan note type fields: %w", err)
+		}
+		fields[noteType] = typeFields
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("iterate note type fields: %w", rows.Err())
+	}
+
+	return fields, nil
+}
+
+// FindFields returns the custom fields of a note type, none when it has no fields
+func (r *NoteTypeRepository) FindFields(ctx context.Context, userID uuid.UUID, name model.NoteType) ([]*model.NoteTypeField, error) {
+	query := `SELECT fields FROM note_type_fields WHERE user_id = $1 AND note_type = $2`
+
+	var fields []*model.NoteTypeField
+	err := r.db.conn().QueryRow(ctx, query, userID, name).Scan(&fields)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find note type fields: %w", err)
+	}
+
+	return fields, nil
+}
+
+// SetFields replaces the custom fields of a note type, removing them when there are none
+func (r *NoteTypeRepository) SetFields(ctx context.Context, userID uuid.UUID, name model.NoteType, fields []*model.NoteTypeField) error {
+	if len(fields) == 0 {
+		query := `DELETE FROM note_type_fields WHERE user_id = $1 AND note_type = $2`
+		if _, err := r.db.conn().Exec(ctx, query, userID, name); err != nil {
+			return fmt.Errorf("delete note type fields: %w", err)
+		}
+		return nil
+	}
+
+	query := `
+		INSERT INTO note_type_fields (user_id, note_type, fields, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (user_id, note_type) DO UPDATE
+		SET fields = EXCLUDED.fields, updated_at = EXCLUDED.updated_at
+	`
+
+	if _, err := r.db.conn().Exec(ctx, query, userID, name, fields); err != nil {
+		return fmt.Errorf("set note type fields: %w", err)
+	}
+
+	return nil
+}
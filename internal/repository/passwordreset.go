@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+// PasswordResetRepository handles password reset token data operations
+type PasswordResetRepository interface {
+	Create(ctx context.Context, token *model.PasswordResetToken) error
+	FindValidByTokenHash(ctx context.Context, tokenHash string) (*model.PasswordResetToken, error)
+	MarkUsed(ctx context.Context, tokenID uuid.UUID) error
+	DeleteForUser(ctx context.Context, userID uuid.UUID) error
+	DeleteExpired(ctx context.Context) error
+}
+
+// passwordResetRepository implements PasswordResetRepository
+type passwordResetRepository struct {
+	db *DB
+}
+
+// NewPasswordResetRepository creates a new password reset token repository
+func NewPasswordResetRepository(db *DB) PasswordResetRepository {
+	return &passwordResetRepository{db: db}
+}
+
+// Create inserts a new password reset token
+func (r *passwordResetRepository) Create(ctx context.Context, token *model.PasswordResetToken) error {
+	query := `
+		INSERT INTO password_reset_tokens (id, user_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	token.ID = uuid.New()
+	token.CreatedAt = time.Now()
+
+	_, err := r.db.conn().Exec(ctx, query,
+		token.ID,
+		token.UserID,
+		token.TokenHash,
+		token.ExpiresAt,
+		token.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("create password reset token: %w", err)
+	}
+
+	return nil
+}
+
+// FindValidByTokenHash finds an unused, unexpired token by its hash
+func (r *passwordResetRepository) FindValidByTokenHash(ctx context.Context, tokenHash string) (*model.PasswordResetToken, error) {
+	query := `
+		SELECT id, user_id, token_hash, expires_at, created_at, used_at
+		FROM password_reset_tokens
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+	`
+
+	token := &model.PasswordResetToken{}
+	err := r.db.conn().QueryRow(ctx, query, tokenHash).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.CreatedAt,
+		&token.UsedAt,
+	)
+
+	if err == pgx.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find password reset token: %w", err)
+	}
+
+	return token, nil
+}
+
+// MarkUsed marks a token as used so it cannot be redeemed again
+// Returns ErrNotFound if the token was already used, so concurrent resets can't both succeed.
+func (r *passwordResetRepository) MarkUsed(ctx context.Context, tokenID uuid.UUID) error {
+	query := `
+		UPDATE password_reset_tokens
+		SET used_at = NOW()
+		WHERE id = $1 AND used_at IS NULL
+	`
+
+	result, err := r.db.conn().Exec(ctx, query, tokenID)
+	if err != nil {
+		return fmt.Errorf("mark password reset token used: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteForUser deletes all password reset tokens of a user
+func (r *passwordResetRepository) DeleteForUser(ctx context.Context, userID uuid.UUID) error {
+	query := `DELETE FROM password_reset_tokens WHERE user_id = $1`
+
+	_, err := r.db.conn().Exec(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("delete password reset tokens: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteExpired deletes all expired password reset tokens
+func (r *passwordResetRepository) DeleteExpired(ctx context.Context) error {
+	query := `DELETE FROM password_reset_tokens WHERE expires_at < NOW()`
+
+	_, err := r.db.conn().Exec(ctx, query)
+	if err != nil {
+		return fmt.Errorf("delete expired password reset tokens: %w", err)
+	}
+
+	return nil
+}
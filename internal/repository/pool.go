@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrPoolTimeout is returned when no connection frees up within the acquire timeout
+var ErrPoolTimeout = errors.New("timed out waiting for a database connection")
+
+// PoolStats is a snapshot of a connection pool
+type PoolStats struct {
+	Name                 string // primary or replica
+	MaxConns             int32
+	TotalConns           int32
+	AcquiredConns        int32
+	IdleConns            int32
+	AcquireCount         int64         // Connections handed out
+	EmptyAcquireCount    int64         // Of which had to wait for one to free up
+	CanceledAcquireCount int64         // Waits given up on, including acquire timeouts
+	AcquireTimeouts      int64         // Waits that hit the acquire timeout
+	AcquireDuration      time.Duration // Total time spent acquiring connections
+	EmptyAcquireWaitTime time.Duration // Total time spent waiting for a free connection
+}
+
+// PoolStats returns a snapshot of the primary pool, and the replica pool when configured
+func (db *DB) PoolStats() []PoolStats {
+	if db.sqlite != nil {
+		return []PoolStats{sqliteStats(db.sqlite, &db.timeouts.primary)}
+	}
+	stats := []PoolStats{poolStats("primary", db.Pool, &db.timeouts.primary)}
+	if db.Replica != nil {
+		stats = append(stats, poolStats("replica", db.Replica, &db.timeouts.replica))
+	}
+	return stats
+}
+
+// poolStats converts the pgxpool statistics of a pool
+func poolStats(name string, pool *pgxpool.Pool, timeouts *atomic.Int64) PoolStats {
+	stat := pool.Stat()
+	return PoolStats{
+		Name:                 name,
+		MaxConns:             stat.MaxConns(),
+		TotalConns:           stat.TotalConns(),
+		AcquiredConns:        stat.AcquiredConns(),
+		IdleConns:            stat.IdleConns(),
+		AcquireCount:         stat.AcquireCount(),
+		EmptyAcquireCount:    stat.EmptyAcquireCount(),
+		CanceledAcquireCount: stat.CanceledAcquireCount(),
+		AcquireTimeouts:      timeouts.Load(),
+		AcquireDuration:      stat.AcquireDuration(),
+		EmptyAcquireWaitTime: stat.EmptyAcquireWaitTime(),
+	}
+}
+
+// MonitorPool logs a warning whenever queries had to wait for a free connection, until ctx is done
+// Waiting queries mean the pool is exhausted: raise DB_MAX_OPEN_CONNS or find the slow queries.
+func (db *DB) MonitorPool(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last := db.PoolStats()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		current := db.PoolStats()
+		for i, stats := range current {
+			waits := stats.EmptyAcquireCount - last[i].EmptyAcquireCount
+			if waits == 0 {
+				continue
+			}
+			slog.Warn("Database pool exhausted, queries waited for a connection",
+				"pool", stats.Name,
+				"waits", waits,
+				"wait_time", stats.EmptyAcquireWaitTime-last[i].EmptyAcquireWaitTime,
+				"timeouts", stats.AcquireTimeouts-last[i].AcquireTimeouts,
+				"acquired", stats.AcquiredConns,
+				"max", stats.MaxConns,
+			)
+		}
+		last = current
+	}
+}
+
+// timeoutPool runs queries on a pool, giving up on acquiring a connection after timeout
+// pgxpool waits as long as the query context allows, so without it a saturated pool
+// only shows up as slow requests.
+type timeoutPool struct {
+	pool     *pgxpool.Pool
+	timeout  time.Duration
+	timeouts *atomic.Int64
+}
+
+// acquire gets a connection from the pool within the timeout
+func (p *timeoutPool) acquire(ctx context.Context) (*pgxpool.Conn, error) {
+	acquireCtx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	conn, err := p.pool.Acquire(acquireCtx)
+	if err != nil && ctx.Err() == nil && errors.Is(acquireCtx.Err(), context.DeadlineExceeded) {
+		p.timeouts.Add(1)
+		return nil, ErrPoolTimeout
+	}
+	return conn, err
+}
+
+// Exec acquires a connection and executes a statement
+func (p *timeoutPool) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	conn, err := p.acquire(ctx)
+	if err != nil {
+		return pgconn.CommandTag{}, err
+	}
+	defer conn.Release()
+
+	return conn.Exec(ctx, sql, args...)
+}
+
+// Query acquires a connection and runs a query, releasing it once the rows are closed
+func (p *timeoutPool) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	conn, err := p.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.Query(ctx, sql, args...)
+	if err != nil {
+		conn.Release()
+		return nil, err
+	}
+
+	return &releaseRows{Rows: rows, conn: conn}, nil
+}
+
+// QueryRow acquires a connection and runs a query, releasing it once the row is scanned
+func (p *timeoutPool) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	conn, err := p.acquire(ctx)
+	if err != nil {
+		return errRow{err: err}
+	}
+
+	return &releaseRow{row: conn.QueryRow(ctx, sql, args...), conn: conn}
+}
+
+// Begin acquires a connection and starts a transaction, releasing it when the transaction ends
+func (p *timeoutPool) Begin(ctx context.Context) (pgx.Tx, error) {
+	conn, err := p.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		conn.Release()
+		return nil, err
+	}
+
+	return &releaseTx{Tx: tx, conn: conn}, nil
+}
+
+// releaseRows releases its connection when the rows are closed
+type releaseRows struct {
+	pgx.Rows
+	conn *pgxpool.Conn
+}
+
+// Close closes the rows and releases the connection
+func (r *releaseRows) Close() {
+	r.Rows.Close()
+	if r.conn != nil {
+		r.conn.Release()
+		r.conn = nil
+	}
+}
+
+// Next advances to the next row, closing the rows after the last one
+func (r *releaseRows) Next() bool {
+	if !r.Rows.Next() {
+		r.Close()
+		return false
+	}
+	return true
+}
+
+// releaseRow releases its connection once scanned
+type releaseRow struct {
+	row  pgx.Row
+	conn *pgxpool.Conn
+}
+
+// Scan scans the row and releases the connection
+func (r *releaseRow) Scan(dest ...any) error {
+	defer r.conn.Release()
+	return r.row.Scan(dest...)
+}
+
+// errRow is a row that failed before the query ran
+type errRow struct {
+	err error
+}
+
+// Scan returns the error
+func (r errRow) Scan(dest ...any) error {
+	return r.err
+}
+
+// releaseTx releases its connection when the transaction is committed or rolled back
+type releaseTx struct {
+	pgx.Tx
+	conn *pgxpool.Conn
+}
+
+// Commit commits the transaction and releases the connection
+func (t *releaseTx) Commit(ctx context.Context) error {
+	err := t.Tx.Commit(ctx)
+	t.release()
+	return err
+}
+
+// Rollback rolls back the transaction and releases the connection
+// Rolling back a finished transaction, as InTx's deferred call does, is a no-op.
+func (t *releaseTx) Rollback(ctx context.Context) error {
+	err := t.Tx.Rollback(ctx)
+	t.release()
+	return err
+}
+
+// release returns the connection to the pool, once
+func (t *releaseTx) release() {
+	if t.conn != nil {
+		t.conn.Release()
+		t.conn = nil
+	}
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+// PublicLinkRepository handles public link data operations
+type PublicLinkRepository struct {
+	db *DB
+}
+
+// NewPublicLinkRepository creates a new public link repository
+func NewPublicLinkRepository(db *DB) PublicLinkRepository {
+	return PublicLinkRepository{db: db}
+}
+
+// Create inserts a new public link of a note
+func (r *PublicLinkRepository) Create(ctx context.Context, link *model.PublicLink) error {
+	query := `
+		INSERT INTO public_links (id, user_id, note_id, created_at)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	link.ID = uuid.New()
+	link.CreatedAt = time.Now()
+
+	_, err := r.db.conn().Exec(ctx, query, link.ID, link.UserID, link.NoteID, link.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("create public link: %w", err)
+	}
+
+	return nil
+}
+
+// FindByID finds a public link by ID, skipping links of deleted notes
+// Used for the public pages, so it isn't scoped to a user.
+func (r *PublicLinkRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.PublicLink, error) {
+	query := `
+		SELECT pl.id, pl.user_id, pl.note_id, n.title, pl.created_at
+		FROM public_links pl
+		INNER JOIN notes n ON n.id = pl.note_id AND n.is_deleted = false
+		WHERE pl.id = $1
+	`
+
+	return r.scanOne(r.db.conn().QueryRow(ctx, query, id), "find public link by id")
+}
+
+// FindByNote finds the public link of a note
+func (r *PublicLinkRepository) FindByNote(ctx context.Context, userID, noteID uuid.UUID) (*model.PublicLink, error) {
+	query := `
+		SELECT pl.id, pl.user_id, pl.note_id, n.title, pl.created_at
+		FROM public_links pl
+		INNER JOIN notes n ON n.id = pl.note_id
+		WHERE pl.note_id = $1 AND pl.user_id = $2
+	`
+
+	return r.scanOne(r.db.conn().QueryRow(ctx, query, noteID, userID), "find public link by note")
+}
+
+// scanOne scans a single public link, mapping no rows to ErrNotFound
+func (r *PublicLinkRepository) scanOne(row pgx.Row, op string) (*model.PublicLink, error) {
+	link := &model.PublicLink{}
+	err := row.Scan(&link.ID, &link.UserID, &link.NoteID, &link.Title, &link.CreatedAt)
+	if err == pgx.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return link, nil
+}
+
+// ListByUser lists the public links of a user's notes by title, skipping deleted notes
+func (r *PublicLinkRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*model.PublicLink, error) {
+	query := `
+		SELECT pl.id, pl.user_id, pl.note_id, n.title, pl.created_at
+		FROM public_links pl
+		INNER JOIN notes n ON n.id = pl.note_id AND n.is_deleted = false
+		WHERE pl.user_id = $1
+		ORDER BY n.title ASC
+	`
+
+	rows, err := r.db.readConn().Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list public links: %w", err)
+	}
+	defer rows.Close()
+
+	links := []*model.PublicLink{}
+	for rows.Next() {
+		link := &model.PublicLink{}
+		if err := rows.Scan(&link.ID, &link.UserID, &link.NoteID, &link.Title, &link.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan public link: %w", err)
+		}
+		links = append(links, link)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("iterate public links: %w", rows.Err())
+	}
+
+	return links, nil
+}
+
+// DeleteByNote deletes the public link of a note, revoking it
+func (r *PublicLinkRepository) DeleteByNote(ctx context.Context, userID, noteID uuid.UUID) error {
+	query := `DELETE FROM public_links WHERE note_id = $1 AND user_id = $2`
+
+	result, err := r.db.conn().Exec(ctx, query, noteID, userID)
+	if err != nil {
+		return fmt.Errorf("delete public link: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
@@ -16,7 +16,9 @@ type RefreshTokenRepository interface {
 	Create(ctx context.Context, token *model.RefreshToken) error
 	FindByTokenHash(ctx context.Context, tokenHash string) (*model.RefreshToken, error)
 	Revoke(ctx context.Context, tokenID uuid.UUID) error
+	RevokeForUser(ctx context.Context, userID, tokenID uuid.UUID) error
 	RevokeAllForUser(ctx context.Context, userID uuid.UUID) error
+	ListActiveForUser(ctx context.Context, userID uuid.UUID) ([]*model.RefreshToken, error)
 	DeleteExpired(ctx context.Context) error
 }
 
@@ -33,21 +35,23 @@ func NewRefreshTokenRepository(db *DB) RefreshTokenRepository {
 // Create inserts a new refresh token
 func (r *refreshTokenRepository) Create(ctx context.Context, token *model.RefreshToken) error {
 	query := `
-		INSERT INTO refresh_tokens (id, user_id, token_hash, expires_at, created_at)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, user_id, token_hash, expires_at, created_at, is_revoked
+		INSERT INTO refresh_tokens (id, user_id, token_hash, expires_at, created_at, user_agent, ip_address)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, user_id, token_hash, expires_at, created_at, is_revoked, user_agent, ip_address
 	`
 
 	now := time.Now()
 	token.ID = uuid.New()
 	token.CreatedAt = now
 
-	err := r.db.Pool.QueryRow(ctx, query,
+	err := r.db.conn().QueryRow(ctx, query,
 		token.ID,
 		token.UserID,
 		token.TokenHash,
 		token.ExpiresAt,
 		token.CreatedAt,
+		token.UserAgent,
+		token.IPAddress,
 	).Scan(
 		&token.ID,
 		&token.UserID,
@@ -55,6 +59,8 @@ func (r *refreshTokenRepository) Create(ctx context.Context, token *model.Refres
 		&token.ExpiresAt,
 		&token.CreatedAt,
 		&token.IsRevoked,
+		&token.UserAgent,
+		&token.IPAddress,
 	)
 
 	if err != nil {
@@ -67,19 +73,21 @@ func (r *refreshTokenRepository) Create(ctx context.Context, token *model.Refres
 // FindByTokenHash finds a refresh token by its hash
 func (r *refreshTokenRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*model.RefreshToken, error) {
 	query := `
-		SELECT id, user_id, token_hash, expires_at, created_at, is_revoked
+		SELECT id, user_id, token_hash, expires_at, created_at, is_revoked, user_agent, ip_address
 		FROM refresh_tokens
 		WHERE token_hash = $1
 	`
 
 	token := &model.RefreshToken{}
-	err := r.db.Pool.QueryRow(ctx, query, tokenHash).Scan(
+	err := r.db.conn().QueryRow(ctx, query, tokenHash).Scan(
 		&token.ID,
 		&token.UserID,
 		&token.TokenHash,
 		&token.ExpiresAt,
 		&token.CreatedAt,
 		&token.IsRevoked,
+		&token.UserAgent,
+		&token.IPAddress,
 	)
 
 	if err == pgx.ErrNoRows {
@@ -93,14 +101,35 @@ func (r *refreshTokenRepository) FindByTokenHash(ctx context.Context, tokenHash
 }
 
 // Revoke marks a refresh token as revoked
+// Returns ErrNotFound if the token doesn't exist or is already revoked.
 func (r *refreshTokenRepository) Revoke(ctx context.Context, tokenID uuid.UUID) error {
 	query := `
 		UPDATE refresh_tokens
 		SET is_revoked = true
-		WHERE id = $1
+		WHERE id = $1 AND is_revoked = false
 	`
 
-	result, err := r.db.Pool.Exec(ctx, query, tokenID)
+	result, err := r.db.conn().Exec(ctx, query, tokenID)
+	if err != nil {
+		return fmt.Errorf("revoke refresh token: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// RevokeForUser revokes one refresh token, only if it belongs to the user
+func (r *refreshTokenRepository) RevokeForUser(ctx context.Context, userID, tokenID uuid.UUID) error {
+	query := `
+		UPDATE refresh_tokens
+		SET is_revoked = true
+		WHERE id = $1 AND user_id = $2 AND is_revoked = false
+	`
+
+	result, err := r.db.conn().Exec(ctx, query, tokenID, userID)
 	if err != nil {
 		return fmt.Errorf("revoke refresh token: %w", err)
 	}
@@ -117,10 +146,10 @@ func (r *refreshTokenRepository) RevokeAllForUser(ctx context.Context, userID uu
 	query := `
 		UPDATE refresh_tokens
 		SET is_revoked = true
-		WHERE user_id = $1
+		WHERE user_id = $1 AND is_revoked = false
 	`
 
-	_, err := r.db.Pool.Exec(ctx, query, userID)
+	_, err := r.db.conn().Exec(ctx, query, userID)
 	if err != nil {
 		return fmt.Errorf("revoke all refresh tokens: %w", err)
 	}
@@ -128,11 +157,51 @@ func (r *refreshTokenRepository) RevokeAllForUser(ctx context.Context, userID uu
 	return nil
 }
 
+// ListActiveForUser lists the user's unrevoked, unexpired refresh tokens, newest first
+func (r *refreshTokenRepository) ListActiveForUser(ctx context.Context, userID uuid.UUID) ([]*model.RefreshToken, error) {
+	query := `
+		SELECT id, user_id, token_hash, expires_at, created_at, is_revoked, user_agent, ip_address
+		FROM refresh_tokens
+		WHERE user_id = $1 AND is_revoked = false AND expires_at > NOW()
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.conn().Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list refresh tokens: %w", err)
+	}
+	defer rows.Close()
+
+	tokens := make([]*model.RefreshToken, 0)
+	for rows.Next() {
+		token := &model.RefreshToken{}
+		if err := rows.Scan(
+			&token.ID,
+			&token.UserID,
+			&token.TokenHash,
+			&token.ExpiresAt,
+			&token.CreatedAt,
+			&token.IsRevoked,
+			&token.UserAgent,
+			&token.IPAddress,
+		); err != nil {
+			return nil, fmt.Errorf("scan refresh token: %w", err)
+		}
+		tokens = append(tokens, token)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("iterate refresh tokens: %w", rows.Err())
+	}
+
+	return tokens, nil
+}
+
 // DeleteExpired deletes all expired refresh tokens
 func (r *refreshTokenRepository) DeleteExpired(ctx context.Context) error {
 	query := `DELETE FROM refresh_tokens WHERE expires_at < NOW()`
 
-	_, err := r.db.Pool.Exec(ctx, query)
+	_, err := r.db.conn().Exec(ctx, query)
 	if err != nil {
 		return fmt.Errorf("delete expired tokens: %w", err)
 	}
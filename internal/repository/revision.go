@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+// RevisionRepository handles note revision data operations
+type RevisionRepository struct {
+	db *DB
+}
+
+// NewRevisionRepository creates a new revision repository
+func NewRevisionRepository(db *DB) RevisionRepository {
+	return RevisionRepository{db: db}
+}
+
+// Create inserts a new revision with the next revision number for the note
+func (r *RevisionRepository) Create(ctx context.Context, rev *model.NoteRevision) error {
+	query := `
+		INSERT INTO note_revisions (id, note_id, user_id, revision_number, title, content, created_at)
+		VALUES (
+			$1, $2, $3,
+			(SELECT COALESCE(MAX(revision_number), 0) + 1 FROM note_revisions WHERE note_id = $2),
+			$4, $5, $6
+		)
+		RETURNING id, note_id, user_id, revision_number, title, content, created_at
+	`
+
+	now := time.Now()
+	rev.ID = uuid.New()
+	rev.CreatedAt = now
+
+	err := r.db.conn().QueryRow(ctx, query,
+		rev.ID,
+		rev.NoteID,
+		rev.UserID,
+		rev.Title,
+		rev.Content,
+		rev.CreatedAt,
+	).Scan(
+		&rev.ID,
+		&rev.NoteID,
+		&rev.UserID,
+		&rev.RevisionNumber,
+		&rev.Title,
+		&rev.Content,
+		&rev.CreatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("create revision: %w", err)
+	}
+
+	return nil
+}
+
+// ListByNote lists all revisions for a note, newest first
+func (r *RevisionRepository) ListByNote(ctx context.Context, userID, noteID uuid.UUID) ([]*model.NoteRevision, error) {
+	query := `
+		SELECT id, note_id, user_id, revision_number, title, content, created_at
+		FROM note_revisions
+		WHERE user_id = $1 AND note_id = $2
+		ORDER BY revision_number DESC
+	`
+
+	rows, err := r.db.conn().Query(ctx, query, userID, noteID)
+	if err != nil {
+		return nil, fmt.Errorf("list revisions: %w", err)
+	}
+	defer rows.Close()
+
+	revisions := []*model.NoteRevision{}
+	for rows.Next() {
+		rev := &model.NoteRevision{}
+		err := rows.Scan(
+			&rev.ID,
+			&rev.NoteID,
+			&rev.UserID,
+			&rev.RevisionNumber,
+			&rev.Title,
+			&rev.Content,
+			&rev.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan revision: %w", err)
+		}
+		revisions = append(revisions, rev)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("iterate revisions: %w", rows.Err())
+	}
+
+	return revisions, nil
+}
+
+// FindByNumber finds a specific revision of a note
+func (r *RevisionRepository) FindByNumber(ctx context.Context, userID, noteID uuid.UUID, number int) (*model.NoteRevision, error) {
+	query := `
+		SELECT id, note_id, user_id, revision_number, title, content, created_at
+		FROM note_revisions
+		WHERE user_id = $1 AND note_id = $2 AND revision_number = $3
+	`
+
+	rev := &model.NoteRevision{}
+	err := r.db.conn().QueryRow(ctx, query, userID, noteID, number).Scan(
+		&rev.ID,
+		&rev.NoteID,
+		&rev.UserID,
+		&rev.RevisionNumber,
+		&rev.Title,
+		&rev.Content,
+		&rev.CreatedAt,
+	)
+
+	if err == pgx.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find revision: %w", err)
+	}
+
+	return rev, nil
+}
+
+// DeleteByNote deletes all revisions of a note
+func (r *RevisionRepository) DeleteByNote(ctx context.Context, userID, noteID uuid.UUID) error {
+	query := `DELETE FROM note_revisions WHERE user_id = $1 AND note_id = $2`
+
+	_, err := r.db.conn().Exec(ctx, query, userID, noteID)
+	if err != nil {
+		return fmt.Errorf("delete revisions: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+// SettingsRepository handles user settings data operations
+type SettingsRepository interface {
+	Get(ctx context.Context, userID uuid.UUID) (*model.UserSettings, error)
+	SetDailyTemplate(ctx context.Context, userID uuid.UUID, template *string) error
+	SetPreferences(ctx context.Context, settings *model.UserSettings) error
+	ListDigestDue(ctx context.Context) ([]*model.UserSettings, error)
+	MarkDigestSent(ctx context.Context, userID uuid.UUID, day string) error
+}
+
+// settingsRepository implements SettingsRepository
+type settingsRepository struct {
+	db *DB
+}
+
+// NewSettingsRepository creates a new settings repository
+func NewSettingsRepository(db *DB) SettingsRepository {
+	if db.sqlite != nil {
+		return &sqliteSettingsRepository{settingsRepository: &settingsRepository{db: db}}
+	}
+	return &settingsRepository{db: db}
+}
+
+// Get gets the settings of a user
+// Users that never changed a setting get the defaults rather than ErrNotFound
+func (r *settingsRepository) Get(ctx context.Context, userID uuid.UUID) (*model.UserSettings, error) {
+	query := `
+		SELECT user_id, daily_template, default_note_type, page_size,
+		       timezone, week_start, theme, daily_word_goal, forgotten_days, capture_target,
+		       digest_url, digest_hour, updated_at
+		FROM user_settings
+		WHERE user_id = $1
+	`
+
+	settings := &model.UserSettings{}
+	err := r.db.conn().QueryRow(ctx, query, userID).Scan(
+		&settings.UserID,
+		&settings.DailyTemplate,
+		&settings.DefaultNoteType,
+		&settings.PageSize,
+		&settings.Timezone,
+		&settings.WeekStart,
+		&settings.Theme,
+		&settings.DailyWordGoal,
+		&settings.ForgottenDays,
+		&settings.CaptureTarget,
+		&settings.DigestURL,
+		&settings.DigestHour,
+		&settings.UpdatedAt,
+	)
+
+	if err == pgx.ErrNoRows {
+		return model.DefaultUserSettings(userID), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get user settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+// SetDailyTemplate sets the daily note template of a user (nil resets to the default)
+func (r *settingsRepository) SetDailyTemplate(ctx context.Context, userID uuid.UUID, template *string) error {
+	query := `
+		INSERT INTO user_settings (user_id, daily_template, updated_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE
+		SET daily_template = EXCLUDED.daily_template,
+		    updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.db.conn().Exec(ctx, query, userID, template, time.Now())
+	if err != nil {
+		return fmt.Errorf("set daily template: %w", err)
+	}
+
+	return nil
+}
+
+// SetPreferences stores the preferences of a user, leaving the daily template untouched
+func (r *settingsRepository) SetPreferences(ctx context.Context, settings *model.UserSettings) error {
+	query := `
+		INSERT INTO user_settings (user_id, default_note_type, page_size, timezone, week_start, theme, daily_word_goal, forgotten_days, capture_target, digest_url, digest_hour, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (user_id) DO UPDATE
+		SET default_note_type = EXCLUDED.default_note_type,
+		    page_size = EXCLUDED.page_size,
+		    timezone = EXCLUDED.timezone,
+		    week_start = EXCLUDED.week_start,
+		    theme = EXCLUDED.theme,
+		    daily_word_goal = EXCLUDED.daily_word_goal,
+		    forgotten_days = EXCLUDED.forgotten_days,
+		    capture_target = EXCLUDED.capture_target,
+		    digest_url = EXCLUDED.digest_url,
+		    digest_hour = EXCLUDED.digest_hour,
+		    updated_at = EXCLUDED.updated_at
+	`
+
+	settings.UpdatedAt = time.Now()
+	_, err := r.db.conn().Exec(ctx, query,
+		settings.UserID,
+		settings.DefaultNoteType,
+		settings.PageSize,
+		settings.Timezone,
+		settings.WeekStart,
+		settings.Theme,
+		settings.DailyWordGoal,
+		settings.ForgottenDays,
+		settings.CaptureTarget,
+		settings.DigestURL,
+		settings.DigestHour,
+		settings.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("set preferences: %w", err)
+	}
+
+	return nil
+}
+
+// ListDigestDue lists the settings of active users with a daily digest not posted on their today yet
+// Whether it is the digest hour in each user's timezone is left to the caller.
+func (r *settingsRepository) ListDigestDue(ctx context.Context) ([]*model.UserSettings, error) {
+	return r.listDigestDue(ctx, "COALESCE(TO_CHAR(s.digest_sent_on, 'YYYY-MM-DD'), '')")
+}
+
+// listDigestDue is ListDigestDue, with sentOn the SQL of the day the last digest was posted, or an empty string
+func (r *settingsRepository) listDigestDue(ctx context.Context, sentOn string) ([]*model.UserSettings, error) {
+	// The day is compared in Go, timezone names Go accepts may be unknown to PostgreSQL
+	query := `
+		SELECT s.user_id, s.timezone, s.forgotten_days, s.daily_word_goal, s.digest_url, s.digest_hour,
+		       ` + sentOn + `
+		FROM user_settings s
+		INNER JOIN users u ON u.id = s.user_id AND u.is_active = true AND u.deleted_at IS NULL
+		WHERE s.digest_url IS NOT NULL
+	`
+
+	rows, err := r.db.conn().Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("list digest subscribers: %w", err)
+	}
+	defer rows.Close()
+
+	due := []*model.UserSettings{}
+	for rows.Next() {
+		settings := &model.UserSettings{}
+		var sentOn string
+		err := rows.Scan(
+			&settings.UserID,
+			&settings.Timezone,
+			&settings.ForgottenDays,
+			&settings.DailyWordGoal,
+			&settings.DigestURL,
+			&settings.DigestHour,
+			&sentOn,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan digest subscriber: %w", err)
+		}
+		if sentOn != time.Now().In(settings.Location()).Format("2006-01-02") {
+			due = append(due, settings)
+		}
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("iterate digest subscribers: %w", rows.Err())
+	}
+
+	return due, nil
+}
+
+// MarkDigestSent records the day, YYYY-MM-DD in the user's timezone, a digest was posted on
+func (r *settingsRepository) MarkDigestSent(ctx context.Context, userID uuid.UUID, day string) error {
+	query := `UPDATE user_settings SET digest_sent_on = $2::date WHERE user_id = $1`
+
+	if _, err := r.db.conn().Exec(ctx, query, userID, day); err != nil {
+		return fmt.Errorf("mark digest sent: %w", err)
+	}
+
+	return nil
+}
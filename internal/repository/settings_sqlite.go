@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+// sqliteSettingsRepository is the SettingsRepository of SQLite databases
+// digest_sent_on is YYYY-MM-DD text there.
+type sqliteSettingsRepository struct {
+	*settingsRepository
+}
+
+// ListDigestDue lists the settings of active users with a daily digest not posted on their today yet
+func (r *sqliteSettingsRepository) ListDigestDue(ctx context.Context) ([]*model.UserSettings, error) {
+	return r.listDigestDue(ctx, "COALESCE(s.digest_sent_on, '')")
+}
+
+// MarkDigestSent records the day, YYYY-MM-DD in the user's timezone, a digest was posted on
+func (r *sqliteSettingsRepository) MarkDigestSent(ctx context.Context, userID uuid.UUID, day string) error {
+	query := `UPDATE user_settings SET digest_sent_on = $2 WHERE user_id = $1`
+
+	if _, err := r.db.conn().Exec(ctx, query, userID, day); err != nil {
+		return fmt.Errorf("mark digest sent: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,652 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	_ "modernc.org/sqlite" // Registers the "sqlite" database/sql driver
+
+	"github.com/momokii/go-cli-notes/internal/config"
+)
+
+// sqliteTimeLayout is how times are stored in SQLite: UTC with microseconds and a fixed width,
+// so stored times sort as text and SQLite's date functions still read them
+const sqliteTimeLayout = "2006-01-02T15:04:05.000000Z"
+
+// sqliteTimeLayouts are the layouts times read from SQLite are parsed with, stored ones first
+var sqliteTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+}
+
+// errSQLiteUnsupported is returned by the pgx features the SQLite connection doesn't have
+var errSQLiteUnsupported = errors.New("not supported by the sqlite driver")
+
+// newSQLite opens the SQLite database file of cfg, creating it and its directory if needed
+// Writes are serialized by SQLite: transactions take the write lock when they begin, and
+// statements wait up to the busy timeout for it instead of failing. WAL lets reads go on
+// during a write.
+func newSQLite(cfg config.DatabaseConfig) (*sql.DB, error) {
+	if dir := filepath.Dir(cfg.SQLitePath); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("create database directory: %w", err)
+		}
+	}
+
+	dsn := cfg.SQLitePath +
+		"?_pragma=busy_timeout(10000)&_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)&_pragma=synchronous(NORMAL)" +
+		"&_txlock=immediate"
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+
+	if err := db.PingContext(context.Background()); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("ping database: %w", err)
+	}
+
+	return db, nil
+}
+
+// sqlExecutor is implemented by both *sql.DB and *sql.Tx
+type sqlExecutor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// sqliteQuerier runs queries through database/sql behind the pgx interfaces the repositories use
+// Arguments and scanned values are converted between Go and SQLite types: see sqliteArg and
+// sqliteAssign. Queries taking at least slow are logged like slowQueryTracer does, 0 = never.
+type sqliteQuerier struct {
+	exec sqlExecutor
+	slow time.Duration
+}
+
+// Exec executes a statement
+func (q *sqliteQuerier) Exec(ctx context.Context, query string, args ...any) (pgconn.CommandTag, error) {
+	start := time.Now()
+	tag, err := q.execRows(ctx, query, args)
+	q.logSlow(ctx, query, start, tag, err)
+	return tag, err
+}
+
+// execRows executes a statement and reports the rows it affected in a command tag
+func (q *sqliteQuerier) execRows(ctx context.Context, query string, args []any) (pgconn.CommandTag, error) {
+	converted, err := sqliteArgs(args)
+	if err != nil {
+		return pgconn.CommandTag{}, err
+	}
+
+	result, err := q.exec.ExecContext(ctx, query, converted...)
+	if err != nil {
+		return pgconn.CommandTag{}, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return pgconn.CommandTag{}, err
+	}
+	return pgconn.NewCommandTag("UPDATE " + strconv.FormatInt(affected, 10)), nil
+}
+
+// Query runs a query
+func (q *sqliteQuerier) Query(ctx context.Context, query string, args ...any) (pgx.Rows, error) {
+	converted, err := sqliteArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	rows, err := q.exec.QueryContext(ctx, query, converted...)
+	if err != nil {
+		q.logSlow(ctx, query, start, pgconn.CommandTag{}, err)
+		return nil, err
+	}
+
+	return &sqliteRows{rows: rows, querier: q, ctx: ctx, query: query, start: start}, nil
+}
+
+// QueryRow runs a query expected to return at most one row
+func (q *sqliteQuerier) QueryRow(ctx context.Context, query string, args ...any) pgx.Row {
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		return errRow{err: err}
+	}
+	return &sqliteRow{rows: rows}
+}
+
+// logSlow logs the query if it ran for at least the slow query threshold
+func (q *sqliteQuerier) logSlow(ctx context.Context, query string, start time.Time, tag pgconn.CommandTag, err error) {
+	if q.slow <= 0 {
+		return
+	}
+	if duration := time.Since(start); duration >= q.slow {
+		logSlowQuery(ctx, query, duration, tag, err)
+	}
+}
+
+// sqliteConn is the SQLite database outside of a transaction
+type sqliteConn struct {
+	sqliteQuerier
+	db *sql.DB
+}
+
+// Begin starts a transaction, waiting for the write lock
+func (c *sqliteConn) Begin(ctx context.Context) (pgx.Tx, error) {
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &sqliteTx{sqliteQuerier: sqliteQuerier{exec: tx, slow: c.slow}, tx: tx}, nil
+}
+
+// sqliteTx is a SQLite transaction, or a savepoint within one
+type sqliteTx struct {
+	sqliteQuerier
+	tx        *sql.Tx
+	savepoint string // Empty for the transaction itself
+	depth     int
+	closed    bool
+}
+
+// Begin starts a savepoint within the transaction
+func (t *sqliteTx) Begin(ctx context.Context) (pgx.Tx, error) {
+	if t.closed {
+		return nil, pgx.ErrTxClosed
+	}
+
+	name := "sp_" + strconv.Itoa(t.depth+1)
+	if _, err := t.tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return nil, err
+	}
+	return &sqliteTx{sqliteQuerier: t.sqliteQuerier, tx: t.tx, savepoint: name, depth: t.depth + 1}, nil
+}
+
+// Commit commits the transaction, or releases the savepoint
+func (t *sqliteTx) Commit(ctx context.Context) error {
+	if t.closed {
+		return pgx.ErrTxClosed
+	}
+	t.closed = true
+
+	if t.savepoint != "" {
+		_, err := t.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+t.savepoint)
+		return err
+	}
+	return t.tx.Commit()
+}
+
+// Rollback rolls back the transaction, or to the start of the savepoint
+// Rolling back a finished transaction, as InTx's deferred call does, is a no-op.
+func (t *sqliteTx) Rollback(ctx context.Context) error {
+	if t.closed {
+		return pgx.ErrTxClosed
+	}
+	t.closed = true
+
+	if t.savepoint != "" {
+		if _, err := t.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+t.savepoint); err != nil {
+			return err
+		}
+		_, err := t.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+t.savepoint)
+		return err
+	}
+	return t.tx.Rollback()
+}
+
+// CopyFrom is not supported
+func (t *sqliteTx) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	return 0, fmt.Errorf("copy from: %w", errSQLiteUnsupported)
+}
+
+// SendBatch is not supported, every result of the batch fails
+func (t *sqliteTx) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+	return sqliteBatchResults{}
+}
+
+// sqliteBatchResults are the results of a batch sent to SQLite
+type sqliteBatchResults struct{}
+
+// Exec returns errSQLiteUnsupported
+func (sqliteBatchResults) Exec() (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, fmt.Errorf("send batch: %w", errSQLiteUnsupported)
+}
+
+// Query returns errSQLiteUnsupported
+func (sqliteBatchResults) Query() (pgx.Rows, error) {
+	return nil, fmt.Errorf("send batch: %w", errSQLiteUnsupported)
+}
+
+// QueryRow returns a row that fails with errSQLiteUnsupported
+func (sqliteBatchResults) QueryRow() pgx.Row {
+	return errRow{err: fmt.Errorf("send batch: %w", errSQLiteUnsupported)}
+}
+
+// Close returns errSQLiteUnsupported
+func (sqliteBatchResults) Close() error {
+	return fmt.Errorf("send batch: %w", errSQLiteUnsupported)
+}
+
+// LargeObjects is not supported
+func (t *sqliteTx) LargeObjects() pgx.LargeObjects {
+	return pgx.LargeObjects{}
+}
+
+// Prepare is not supported
+func (t *sqliteTx) Prepare(ctx context.Context, name, sql string) (*pgconn.StatementDescription, error) {
+	return nil, fmt.Errorf("prepare: %w", errSQLiteUnsupported)
+}
+
+// Conn returns nil, there is no pgx connection under a SQLite transaction
+func (t *sqliteTx) Conn() *pgx.Conn {
+	return nil
+}
+
+// sqliteRows are the rows of a SQLite query
+type sqliteRows struct {
+	rows    *sql.Rows
+	querier *sqliteQuerier
+	ctx     context.Context
+	query   string
+	start   time.Time
+	count   int64
+	closed  bool
+	err     error
+	values  []any
+}
+
+// Close closes the rows
+func (r *sqliteRows) Close() {
+	if r.closed {
+		return
+	}
+	r.closed = true
+
+	if err := r.rows.Close(); err != nil && r.err == nil {
+		r.err = err
+	}
+	if r.err == nil {
+		r.err = r.rows.Err()
+	}
+	r.querier.logSlow(r.ctx, r.query, r.start, r.CommandTag(), r.err)
+}
+
+// Err returns the error that ended the iteration, if any
+func (r *sqliteRows) Err() error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.rows.Err()
+}
+
+// CommandTag reports the number of rows read so far
+func (r *sqliteRows) CommandTag() pgconn.CommandTag {
+	return pgconn.NewCommandTag("SELECT " + strconv.FormatInt(r.count, 10))
+}
+
+// FieldDescriptions returns the names of the columns
+func (r *sqliteRows) FieldDescriptions() []pgconn.FieldDescription {
+	columns, err := r.rows.Columns()
+	if err != nil {
+		return nil
+	}
+	fields := make([]pgconn.FieldDescription, len(columns))
+	for i, column := range columns {
+		fields[i] = pgconn.FieldDescription{Name: column}
+	}
+	return fields
+}
+
+// Next advances to the next row, closing the rows after the last one
+func (r *sqliteRows) Next() bool {
+	if r.closed {
+		return false
+	}
+	if !r.rows.Next() {
+		r.Close()
+		return false
+	}
+
+	columns, err := r.rows.Columns()
+	if err != nil {
+		r.err = err
+		r.Close()
+		return false
+	}
+
+	r.values = make([]any, len(columns))
+	dest := make([]any, len(columns))
+	for i := range r.values {
+		dest[i] = &r.values[i]
+	}
+	if err := r.rows.Scan(dest...); err != nil {
+		r.err = err
+		r.Close()
+		return false
+	}
+
+	r.count++
+	return true
+}
+
+// Scan converts the values of the current row into dest
+func (r *sqliteRows) Scan(dest ...any) error {
+	if len(dest) != len(r.values) {
+		return fmt.Errorf("scan: %d destinations for %d columns", len(dest), len(r.values))
+	}
+	for i, d := range dest {
+		if err := sqliteAssign(d, r.values[i]); err != nil {
+			return fmt.Errorf("scan column %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Values returns the values of the current row as SQLite returned them
+func (r *sqliteRows) Values() ([]any, error) {
+	return r.values, nil
+}
+
+// RawValues is not supported, it returns nil
+func (r *sqliteRows) RawValues() [][]byte {
+	return nil
+}
+
+// Conn returns nil, there is no pgx connection under SQLite rows
+func (r *sqliteRows) Conn() *pgx.Conn {
+	return nil
+}
+
+// sqliteRow is the first row of a SQLite query
+type sqliteRow struct {
+	rows pgx.Rows
+}
+
+// Scan scans the first row, failing with pgx.ErrNoRows when there is none
+func (r *sqliteRow) Scan(dest ...any) error {
+	defer r.rows.Close()
+
+	if !r.rows.Next() {
+		if err := r.rows.Err(); err != nil {
+			return err
+		}
+		return pgx.ErrNoRows
+	}
+	return r.rows.Scan(dest...)
+}
+
+// sqliteArgs converts query arguments to values SQLite stores
+func sqliteArgs(args []any) ([]any, error) {
+	converted := make([]any, len(args))
+	for i, arg := range args {
+		value, err := sqliteArg(arg)
+		if err != nil {
+			return nil, fmt.Errorf("argument $%d: %w", i+1, err)
+		}
+		converted[i] = value
+	}
+	return converted, nil
+}
+
+// sqliteArg converts a query argument to a value SQLite stores
+// Times are stored as sqliteTimeLayout text, and slices, maps and structs as JSON, like Postgres
+// arrays and JSONB columns. Nil pointers, maps and slices are NULL.
+func sqliteArg(arg any) (any, error) {
+	switch v := arg.(type) {
+	case nil:
+		return nil, nil
+	case time.Time:
+		return v.UTC().Format(sqliteTimeLayout), nil
+	case []byte, string, bool, int64, float64:
+		return v, nil
+	case json.RawMessage:
+		if v == nil {
+			return nil, nil
+		}
+		return string(v), nil
+	}
+
+	value := reflect.ValueOf(arg)
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if value.IsNil() {
+			return nil, nil
+		}
+		if valuer, ok := arg.(driver.Valuer); ok {
+			return valuer.Value()
+		}
+		return sqliteArg(value.Elem().Interface())
+	}
+
+	if valuer, ok := arg.(driver.Valuer); ok {
+		return valuer.Value()
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		return value.String(), nil
+	case reflect.Bool:
+		return value.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(value.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), nil
+	case reflect.Slice, reflect.Map:
+		if value.IsNil() {
+			return nil, nil
+		}
+	}
+
+	data, err := json.Marshal(arg)
+	if err != nil {
+		return nil, fmt.Errorf("encode %T as JSON: %w", arg, err)
+	}
+	return string(data), nil
+}
+
+// scannerType is the type of sql.Scanner implementations
+var scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+
+// sqliteAssign stores a value read from SQLite in dest, a pointer
+// Text is parsed into times and UUIDs, integers into booleans, and JSON text into the slices,
+// maps and structs it was stored from. NULL sets pointers to nil and other values to their zero.
+func sqliteAssign(dest, src any) error {
+	if scanner, ok := dest.(sql.Scanner); ok {
+		return scanner.Scan(src)
+	}
+	if d, ok := dest.(*any); ok {
+		*d = src
+		return nil
+	}
+
+	target := reflect.ValueOf(dest)
+	if target.Kind() != reflect.Pointer || target.IsNil() {
+		return fmt.Errorf("destination %T is not a pointer", dest)
+	}
+	target = target.Elem()
+
+	if src == nil {
+		target.Set(reflect.Zero(target.Type()))
+		return nil
+	}
+
+	// Nullable columns are scanned into pointers
+	if target.Kind() == reflect.Pointer {
+		value := reflect.New(target.Type().Elem())
+		if err := sqliteAssign(value.Interface(), src); err != nil {
+			return err
+		}
+		target.Set(value)
+		return nil
+	}
+	if reflect.PointerTo(target.Type()).Implements(scannerType) {
+		return target.Addr().Interface().(sql.Scanner).Scan(src)
+	}
+
+	switch d := target.Addr().Interface().(type) {
+	case *time.Time:
+		t, err := sqliteTime(src)
+		if err != nil {
+			return err
+		}
+		*d = t
+		return nil
+	case *uuid.UUID:
+		return d.Scan(src)
+	case *json.RawMessage:
+		*d = append(json.RawMessage(nil), sqliteBytes(src)...)
+		return nil
+	}
+
+	switch target.Kind() {
+	case reflect.String:
+		switch v := src.(type) {
+		case string:
+			target.SetString(v)
+		case []byte:
+			target.SetString(string(v))
+		case int64:
+			target.SetString(strconv.FormatInt(v, 10))
+		case float64:
+			target.SetString(strconv.FormatFloat(v, 'g', -1, 64))
+		default:
+			return fmt.Errorf("cannot scan %T into %s", src, target.Type())
+		}
+		return nil
+	case reflect.Bool:
+		switch v := src.(type) {
+		case int64:
+			target.SetBool(v != 0)
+		case float64:
+			target.SetBool(v != 0)
+		case bool:
+			target.SetBool(v)
+		case string:
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("cannot scan %q into %s", v, target.Type())
+			}
+			target.SetBool(b)
+		default:
+			return fmt.Errorf("cannot scan %T into %s", src, target.Type())
+		}
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch v := src.(type) {
+		case int64:
+			target.SetInt(v)
+		case float64:
+			target.SetInt(int64(v))
+		case string:
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return fmt.Errorf("cannot scan %q into %s", v, target.Type())
+			}
+			target.SetInt(n)
+		default:
+			return fmt.Errorf("cannot scan %T into %s", src, target.Type())
+		}
+		return nil
+	case reflect.Float32, reflect.Float64:
+		switch v := src.(type) {
+		case float64:
+			target.SetFloat(v)
+		case int64:
+			target.SetFloat(float64(v))
+		case string:
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return fmt.Errorf("cannot scan %q into %s", v, target.Type())
+			}
+			target.SetFloat(f)
+		default:
+			return fmt.Errorf("cannot scan %T into %s", src, target.Type())
+		}
+		return nil
+	case reflect.Slice:
+		if target.Type().Elem().Kind() == reflect.Uint8 {
+			target.SetBytes(append([]byte(nil), sqliteBytes(src)...))
+			return nil
+		}
+	}
+
+	// Slices, maps and structs were stored as JSON
+	switch src.(type) {
+	case string, []byte:
+		if err := json.Unmarshal(sqliteBytes(src), dest); err != nil {
+			return fmt.Errorf("decode JSON into %s: %w", target.Type(), err)
+		}
+		return nil
+	}
+	return fmt.Errorf("cannot scan %T into %s", src, target.Type())
+}
+
+// sqliteBytes returns text or blob values as bytes
+func sqliteBytes(src any) []byte {
+	switch v := src.(type) {
+	case string:
+		return []byte(v)
+	case []byte:
+		return v
+	}
+	return []byte(fmt.Sprint(src))
+}
+
+// sqliteTime parses a time read from SQLite
+func sqliteTime(src any) (time.Time, error) {
+	switch v := src.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		for _, layout := range sqliteTimeLayouts {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("cannot parse %q as a time", v)
+	case []byte:
+		return sqliteTime(string(v))
+	case int64:
+		return time.Unix(v, 0).UTC(), nil
+	}
+	return time.Time{}, fmt.Errorf("cannot scan %T into a time", src)
+}
+
+// sqliteStats converts the database/sql statistics of the SQLite database
+// database/sql doesn't count acquires or canceled waits, so those stay zero.
+func sqliteStats(db *sql.DB, timeouts *atomic.Int64) PoolStats {
+	stat := db.Stats()
+	return PoolStats{
+		Name:                 "primary",
+		MaxConns:             int32(stat.MaxOpenConnections),
+		TotalConns:           int32(stat.OpenConnections),
+		AcquiredConns:        int32(stat.InUse),
+		IdleConns:            int32(stat.Idle),
+		EmptyAcquireCount:    stat.WaitCount,
+		AcquireTimeouts:      timeouts.Load(),
+		EmptyAcquireWaitTime: stat.WaitDuration,
+	}
+}
@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"modernc.org/sqlite"
+
+	"github.com/momokii/go-cli-notes/internal/util"
+)
+
+// Functions the SQLite queries use in place of PostgreSQL built-ins and the functions of the
+// Postgres migrations. They're registered for every SQLite connection the process opens.
+// The SQLite schema doesn't use them, so the database file stays usable from the sqlite3 shell.
+func init() {
+	// now() returns the current time in sqliteTimeLayout, comparable with stored times
+	sqlite.MustRegisterScalarFunction("now", 0, func(_ *sqlite.FunctionContext, _ []driver.Value) (driver.Value, error) {
+		return time.Now().UTC().Format(sqliteTimeLayout), nil
+	})
+
+	// note_title_key(title) is the key [[link]] titles are matched by, see util.LinkParser.NormalizeTitle
+	sqlite.MustRegisterDeterministicScalarFunction("note_title_key", 1, func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		title, ok := sqliteText(args[0])
+		if !ok {
+			return nil, nil
+		}
+		return titleKeyParser.NormalizeTitle(title), nil
+	})
+
+	// word_similarity(query, text) is pg_trgm's similarity of query to the best matching part of text
+	sqlite.MustRegisterDeterministicScalarFunction("word_similarity", 2, func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		query, ok := sqliteText(args[0])
+		if !ok {
+			return nil, nil
+		}
+		text, ok := sqliteText(args[1])
+		if !ok {
+			return nil, nil
+		}
+		return wordSimilarity(query, text), nil
+	})
+
+	// cosine_similarity(a, b) is the cosine similarity of two vectors stored as JSON arrays,
+	// 1 - pgvector's cosine distance
+	sqlite.MustRegisterDeterministicScalarFunction("cosine_similarity", 2, func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		a, ok := sqliteText(args[0])
+		if !ok {
+			return nil, nil
+		}
+		b, ok := sqliteText(args[1])
+		if !ok {
+			return nil, nil
+		}
+		return cosineSimilarity(a, b)
+	})
+
+	// local_day(time, timezone) is the YYYY-MM-DD day of a stored time in timezone
+	sqlite.MustRegisterDeterministicScalarFunction("local_day", 2, func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		return localTime(args, "2006-01-02")
+	})
+
+	// local_month(time, timezone) is the YYYY-MM month of a stored time in timezone
+	sqlite.MustRegisterDeterministicScalarFunction("local_month", 2, func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		return localTime(args, "2006-01")
+	})
+}
+
+// titleKeyParser computes link title keys for note_title_key
+var titleKeyParser = util.NewLinkParser()
+
+// sqliteText returns a text argument of a SQLite function, false for NULL
+func sqliteText(arg driver.Value) (string, bool) {
+	switch v := arg.(type) {
+	case string:
+		return v, true
+	case []byte:
+		return string(v), true
+	case nil:
+		return "", false
+	}
+	return fmt.Sprint(arg), true
+}
+
+// localTime formats the time of args[0] in the timezone named by args[1]
+func localTime(args []driver.Value, layout string) (driver.Value, error) {
+	if args[0] == nil {
+		return nil, nil
+	}
+	t, err := sqliteTime(args[0])
+	if err != nil {
+		return nil, err
+	}
+	name, _ := sqliteText(args[1])
+	loc, err := loadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	return t.In(loc).Format(layout), nil
+}
+
+// locations caches the time zones loaded by loadLocation
+var locations sync.Map
+
+// loadLocation loads the time zone named name, once
+func loadLocation(name string) (*time.Location, error) {
+	if loc, ok := locations.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q: %w", name, err)
+	}
+	locations.Store(name, loc)
+	return loc, nil
+}
+
+// cosineSimilarity is the cosine similarity of the JSON vectors a and b, NULL if either is all zeros
+func cosineSimilarity(a, b string) (driver.Value, error) {
+	var x, y []float64
+	if err := json.Unmarshal([]byte(a), &x); err != nil {
+		return nil, fmt.Errorf("decode vector: %w", err)
+	}
+	if err := json.Unmarshal([]byte(b), &y); err != nil {
+		return nil, fmt.Errorf("decode vector: %w", err)
+	}
+	if len(x) != len(y) {
+		return nil, fmt.Errorf("different vector dimensions %d and %d", len(x), len(y))
+	}
+
+	var dot, normX, normY float64
+	for i := range x {
+		dot += x[i] * y[i]
+		normX += x[i] * x[i]
+		normY += y[i] * y[i]
+	}
+	if normX == 0 || normY == 0 {
+		return nil, nil
+	}
+	return dot / (math.Sqrt(normX) * math.Sqrt(normY)), nil
+}
+
+// wordSimilarity is pg_trgm's word_similarity: the greatest similarity between the trigrams of
+// query and those of any run of consecutive trigrams of text, from 0 to 1
+func wordSimilarity(query, text string) float64 {
+	want := map[string]bool{}
+	for _, trigram := range trigrams(query) {
+		want[trigram] = true
+	}
+	if len(want) == 0 {
+		return 0
+	}
+
+	have := trigrams(text)
+	best := 0.0
+	for start := range have {
+		extent := map[string]bool{}
+		shared := 0
+		for _, trigram := range have[start:] {
+			if !extent[trigram] {
+				extent[trigram] = true
+				if want[trigram] {
+					shared++
+				}
+			}
+			if shared == 0 {
+				continue
+			}
+			if similarity := float64(shared) / float64(len(want)+len(extent)-shared); similarity > best {
+				best = similarity
+			}
+		}
+	}
+	return best
+}
+
+// trigrams lists the trigrams of the words of s in order, the way pg_trgm splits them:
+// lowercased alphanumeric words padded with two spaces before and one after
+func trigrams(s string) []string {
+	var list []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		padded := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			list = append(list, string(padded[i:i+3]))
+		}
+	}
+	return list
+}
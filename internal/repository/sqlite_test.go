@@ -0,0 +1,587 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/momokii/go-cli-notes/internal/config"
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+// openSQLite opens a migrated SQLite database in a temporary directory
+func openSQLite(t *testing.T) *DB {
+	t.Helper()
+
+	db, err := NewDB(config.DatabaseConfig{
+		Driver:       "sqlite",
+		SQLitePath:   filepath.Join(t.TempDir(), "notes.db"),
+		MaxOpenConns: 4,
+		MaxIdleConns: 2,
+	})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(db.Close)
+
+	migrator, err := db.NewMigrator()
+	if err != nil {
+		t.Fatalf("create migrator: %v", err)
+	}
+	defer migrator.Close()
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	return db
+}
+
+// createUser creates a user named after the test
+func createUser(t *testing.T, repo *Repository, name string) *model.User {
+	t.Helper()
+
+	user := &model.User{Email: name + "@example.com", Username: name, PasswordHash: "hash"}
+	if err := repo.User.Create(context.Background(), user); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	return user
+}
+
+// createNotes creates a note for each title and content pair
+func createNotes(t *testing.T, repo *Repository, userID uuid.UUID, pairs ...string) []*model.Note {
+	t.Helper()
+
+	var notes []*model.Note
+	for i := 0; i+1 < len(pairs); i += 2 {
+		note := &model.Note{UserID: userID, Title: pairs[i], Content: pairs[i+1], NoteType: model.NoteTypeNote}
+		if err := repo.Note.Create(context.Background(), note); err != nil {
+			t.Fatalf("create note %q: %v", pairs[i], err)
+		}
+		notes = append(notes, note)
+	}
+	return notes
+}
+
+func TestSQLiteNotes(t *testing.T) {
+	ctx := context.Background()
+	repo := NewRepository(openSQLite(t))
+	user := createUser(t, repo, "alice")
+
+	notes := createNotes(t, repo, user.ID,
+		"Gardening", "Tomatoes need sun and water every morning",
+		"Cooking", "A tomato sauce simmers for an hour",
+		"2025-01-10", "Planted tomatoes today",
+	)
+	if notes[0].WordCount != 7 || notes[0].ReadingTimeMinutes != 1 {
+		t.Errorf("word count = %d, reading time = %d, want 7 and 1", notes[0].WordCount, notes[0].ReadingTimeMinutes)
+	}
+
+	notes[1].Content = "A tomato sauce simmers for an hour with basil"
+	if err := repo.Note.Update(ctx, user.ID, notes[1]); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if err := repo.Note.UpdateAccessCount(ctx, user.ID, notes[1].ID); err != nil {
+		t.Fatalf("update access count: %v", err)
+	}
+	found, err := repo.Note.FindByID(ctx, user.ID, notes[1].ID)
+	if err != nil {
+		t.Fatalf("find: %v", err)
+	}
+	if found.WordCount != 9 || found.AccessCount != 1 {
+		t.Errorf("word count = %d, access count = %d, want 9 and 1", found.WordCount, found.AccessCount)
+	}
+	// A view isn't an edit
+	if !found.UpdatedAt.Equal(notes[1].UpdatedAt) {
+		t.Errorf("updated at = %v after a view, want %v", found.UpdatedAt, notes[1].UpdatedAt)
+	}
+
+	if err := repo.Note.LockByID(ctx, user.ID, notes[0].ID); err != nil {
+		t.Errorf("lock: %v", err)
+	}
+	byID, err := repo.Note.FindByIDs(ctx, user.ID, []uuid.UUID{notes[0].ID, notes[2].ID})
+	if err != nil {
+		t.Fatalf("find by ids: %v", err)
+	}
+	if len(byID) != 2 {
+		t.Errorf("found %d notes by id, want 2", len(byID))
+	}
+
+	results, total, err := repo.Note.Search(ctx, user.ID, model.NoteFilter{Search: "tomatoes", Limit: 10}, 20)
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	// Porter stemming matches "tomato" too
+	if total != 3 || len(results) != 3 {
+		t.Fatalf("search found %d of %d results, want 3", len(results), total)
+	}
+	for _, result := range results {
+		if result.Rank <= 0 || result.Rank >= 1 {
+			t.Errorf("rank of %q = %v, want between 0 and 1", result.Note.Title, result.Rank)
+		}
+		if !strings.Contains(result.Snippet, "<b>") {
+			t.Errorf("snippet of %q = %q, want a highlighted match", result.Note.Title, result.Snippet)
+		}
+	}
+
+	if _, total, err := repo.Note.Search(ctx, user.ID, model.NoteFilter{Search: "!!!", Limit: 10}, 20); err != nil || total != 0 {
+		t.Errorf("search without words found %d results, err %v", total, err)
+	}
+
+	fuzzy, _, err := repo.Note.FuzzySearch(ctx, user.ID, model.NoteFilter{Search: "Gardenin", Limit: 10}, 20)
+	if err != nil {
+		t.Fatalf("fuzzy search: %v", err)
+	}
+	if len(fuzzy) != 1 || fuzzy[0].Note.ID != notes[0].ID {
+		t.Errorf("fuzzy search found %d results, want Gardening", len(fuzzy))
+	}
+
+	tag := &model.Tag{UserID: user.ID, Name: "food"}
+	if err := repo.Tag.Create(ctx, tag); err != nil {
+		t.Fatalf("create tag: %v", err)
+	}
+	added, err := repo.Tag.BulkUpdateNotes(ctx, user.ID, tag.ID, []uuid.UUID{notes[0].ID, notes[1].ID}, true)
+	if err != nil {
+		t.Fatalf("bulk tag: %v", err)
+	}
+	if added != 2 {
+		t.Errorf("tagged %d notes, want 2", added)
+	}
+	if _, total, err := repo.Note.Search(ctx, user.ID, model.NoteFilter{Search: "food", Limit: 10}, 20); err != nil || total != 2 {
+		t.Errorf("search by tag found %d results, err %v, want 2", total, err)
+	}
+	link := &model.Link{UserID: user.ID, SourceNoteID: notes[2].ID, TargetNoteID: notes[0].ID}
+	if err := repo.Link.Create(ctx, link); err != nil {
+		t.Fatalf("create link: %v", err)
+	}
+
+	listed, total, err := repo.Note.List(ctx, user.ID, model.NoteFilter{
+		Search: "tomato", SortBy: "relevance", Limit: 10, IncludeTags: true, IncludeLinkCounts: true,
+	})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if total != 3 {
+		t.Errorf("list found %d notes, want 3", total)
+	}
+	for _, note := range listed {
+		if note.ID == notes[0].ID {
+			if len(note.Tags) != 1 || note.Tags[0].Name != "food" {
+				t.Errorf("tags of %q = %v, want food", note.Title, note.Tags)
+			}
+			if note.LinkCounts == nil || note.LinkCounts.Incoming != 1 {
+				t.Errorf("link counts of %q = %+v, want 1 incoming", note.Title, note.LinkCounts)
+			}
+		}
+	}
+
+	links, err := repo.Link.GetByNotes(ctx, user.ID, []uuid.UUID{notes[0].ID})
+	if err != nil {
+		t.Fatalf("get links by notes: %v", err)
+	}
+	if len(links) != 1 {
+		t.Errorf("got %d links, want 1", len(links))
+	}
+
+	related, err := repo.Note.FindRelated(ctx, user.ID, notes[0].ID, 5)
+	if err != nil {
+		t.Fatalf("find related: %v", err)
+	}
+	if len(related) == 0 {
+		t.Errorf("found no related notes")
+	}
+
+	days, err := repo.Note.ListDailyNotes(ctx, user.ID, "", "2025-01-01", "2025-01-31")
+	if err != nil {
+		t.Fatalf("list daily notes: %v", err)
+	}
+	if len(days) != 1 || days[0].Date != "2025-01-10" {
+		t.Errorf("daily notes = %v, want 2025-01-10", days)
+	}
+
+	linked, err := repo.Note.FindByLinkTitle(ctx, user.ID, "  cooking ")
+	if err != nil {
+		t.Fatalf("find by link title: %v", err)
+	}
+	if linked.ID != notes[1].ID {
+		t.Errorf("link title found %q, want Cooking", linked.Title)
+	}
+
+	if err := repo.Note.SetMetadata(ctx, user.ID, notes[0].ID, model.MetadataSummary, "Tomatoes need sun"); err != nil {
+		t.Fatalf("set metadata: %v", err)
+	}
+	found, err = repo.Note.FindByID(ctx, user.ID, notes[0].ID)
+	if err != nil {
+		t.Fatalf("find: %v", err)
+	}
+	if found.Metadata[model.MetadataSummary] != "Tomatoes need sun" {
+		t.Errorf("metadata = %v, want the summary", found.Metadata)
+	}
+
+	if err := repo.Note.SetMetadata(ctx, user.ID, notes[0].ID, model.MetadataReadProgress, map[string]any{"percent": 40}); err != nil {
+		t.Fatalf("set metadata: %v", err)
+	}
+	reading, err := repo.Note.ListReading(ctx, user.ID, 10)
+	if err != nil {
+		t.Fatalf("list reading: %v", err)
+	}
+	if len(reading) != 1 || reading[0].ID != notes[0].ID {
+		t.Fatalf("reading %d notes, want Gardening", len(reading))
+	}
+	// Reading isn't an edit
+	if !reading[0].UpdatedAt.Equal(found.UpdatedAt) {
+		t.Errorf("updated at = %v after saving read progress, want %v", reading[0].UpdatedAt, found.UpdatedAt)
+	}
+
+	month, err := repo.Tag.CountTaggedByMonth(ctx, user.ID, "Europe/Berlin", time.Now().AddDate(0, -1, 0))
+	if err != nil {
+		t.Fatalf("count tagged by month: %v", err)
+	}
+	if counts := month[tag.ID]; len(counts) != 1 {
+		t.Errorf("tagged by month = %v, want one month", counts)
+	}
+
+	child := &model.Tag{UserID: user.ID, Name: "food/fruit"}
+	if err := repo.Tag.Create(ctx, child); err != nil {
+		t.Fatalf("create tag: %v", err)
+	}
+	if err := repo.Tag.RenameDescendants(ctx, user.ID, "food", "kitchen"); err != nil {
+		t.Fatalf("rename descendants: %v", err)
+	}
+	if _, err := repo.Tag.FindByName(ctx, user.ID, "kitchen/fruit"); err != nil {
+		t.Errorf("find renamed tag: %v", err)
+	}
+}
+
+func TestSQLiteActivity(t *testing.T) {
+	ctx := context.Background()
+	repo := NewRepository(openSQLite(t))
+	user := createUser(t, repo, "bob")
+	note := createNotes(t, repo, user.ID, "Journal", "Some words")[0]
+
+	for _, action := range []model.ActionType{model.ActionView, model.ActionView, model.ActionUpdate} {
+		if err := repo.Activity.Create(ctx, &model.Activity{UserID: user.ID, NoteID: &note.ID, Action: action}); err != nil {
+			t.Fatalf("create activity: %v", err)
+		}
+	}
+
+	for _, query := range []string{"garden", "tomato", "garden"} {
+		search := &model.Activity{UserID: user.ID, Action: model.ActionSearch, Metadata: model.ActivityMetadata{"query": query}}
+		if err := repo.Activity.Create(ctx, search); err != nil {
+			t.Fatalf("create search activity: %v", err)
+		}
+	}
+	history, err := repo.Activity.GetSearchHistory(ctx, user.ID, 10)
+	if err != nil {
+		t.Fatalf("search history: %v", err)
+	}
+	if len(history) != 2 || history[0].Query != "garden" || history[0].Count != 2 {
+		t.Errorf("search history = %v, want garden twice, then tomato", history)
+	}
+
+	focus := &model.Activity{UserID: user.ID, NoteID: &note.ID, Action: model.ActionFocus, Metadata: model.ActivityMetadata{model.FocusDurationKey: 1500}}
+	if err := repo.Activity.Create(ctx, focus); err != nil {
+		t.Fatalf("create focus activity: %v", err)
+	}
+
+	heatmap, err := repo.Activity.GetActivityHeatmap(ctx, user.ID, "America/New_York", "sunday", 4)
+	if err != nil {
+		t.Fatalf("heatmap: %v", err)
+	}
+	if len(heatmap.Days) < 22 || len(heatmap.Days) > 28 {
+		t.Errorf("heatmap has %d days, want 22 to 28", len(heatmap.Days))
+	}
+	if first, _ := time.Parse("2006-01-02", heatmap.Days[0].Date); first.Weekday() != time.Sunday {
+		t.Errorf("heatmap starts on %v, want Sunday", first.Weekday())
+	}
+
+	stats, err := repo.Activity.GetNoteStats(ctx, note.ID, "UTC", 30, 12)
+	if err != nil {
+		t.Fatalf("note stats: %v", err)
+	}
+	if stats.EditCount != 1 || len(stats.DailyViews) != 30 || len(stats.MonthlyViews) != 12 {
+		t.Errorf("stats = %d edits, %d days, %d months, want 1, 30 and 12",
+			stats.EditCount, len(stats.DailyViews), len(stats.MonthlyViews))
+	}
+	if stats.FocusSessions != 1 || stats.FocusSeconds != 1500 {
+		t.Errorf("focus = %d sessions, %d seconds, want 1 and 1500", stats.FocusSessions, stats.FocusSeconds)
+	}
+	if last := stats.DailyViews[len(stats.DailyViews)-1]; last.Count != 2 {
+		t.Errorf("views today = %d, want 2", last.Count)
+	}
+	if last := stats.MonthlyViews[len(stats.MonthlyViews)-1]; last.Count != 2 {
+		t.Errorf("views this month = %d, want 2", last.Count)
+	}
+
+	userStats, err := repo.Activity.GetUserStats(ctx, user.ID, "Asia/Tokyo", "monday")
+	if err != nil {
+		t.Fatalf("user stats: %v", err)
+	}
+	if userStats.TotalNotes != 1 || userStats.NotesCreatedToday != 1 || userStats.LastActivity == nil {
+		t.Errorf("user stats = %+v, want 1 note created today and a last activity", userStats)
+	}
+
+	for _, words := range []int{120, 200} {
+		if err := repo.Activity.AddWordsWritten(ctx, user.ID, "UTC", words); err != nil {
+			t.Fatalf("add words: %v", err)
+		}
+	}
+	daily, err := repo.Activity.GetDailyWords(ctx, user.ID, "UTC", 7)
+	if err != nil {
+		t.Fatalf("daily words: %v", err)
+	}
+	if len(daily) != 7 || daily[6].Words != 320 {
+		t.Errorf("daily words = %d days, %v today, want 7 days and 320 today", len(daily), daily)
+	}
+
+	streak, err := repo.Activity.GetWritingStreak(ctx, user.ID, "UTC", 300)
+	if err != nil {
+		t.Fatalf("writing streak: %v", err)
+	}
+	if !streak.GoalMetToday || streak.CurrentStreak != 1 || streak.WordsToday != 320 {
+		t.Errorf("streak = %+v, want the goal met today and a streak of 1", streak)
+	}
+}
+
+func TestSQLiteUsers(t *testing.T) {
+	ctx := context.Background()
+	repo := NewRepository(openSQLite(t))
+	admin := createUser(t, repo, "carol")
+	other := createUser(t, repo, "dave")
+	note := createNotes(t, repo, admin.ID, "Shared", "Shared content")[0]
+
+	promoted, err := repo.User.PromoteAdmins(ctx, []string{admin.Email, "nobody@example.com"})
+	if err != nil {
+		t.Fatalf("promote admins: %v", err)
+	}
+	if promoted != 1 {
+		t.Errorf("promoted %d users, want 1", promoted)
+	}
+	if _, err := repo.User.GetAdminStats(ctx); err != nil {
+		t.Errorf("admin stats: %v", err)
+	}
+
+	share := &model.NoteShare{NoteID: note.ID, OwnerID: admin.ID, SharedWith: other.ID, Permission: model.SharePermissionRead}
+	if _, err := repo.NoteShare.Upsert(ctx, share); err != nil {
+		t.Fatalf("share: %v", err)
+	}
+	share.Permission = model.SharePermissionWrite
+	if _, err := repo.NoteShare.Upsert(ctx, share); err != nil {
+		t.Fatalf("update share: %v", err)
+	}
+	if _, err := repo.Note.FindAccessible(ctx, other.ID, note.ID, model.SharePermissionWrite); err != nil {
+		t.Errorf("find shared note: %v", err)
+	}
+
+	if err := repo.User.SoftDelete(ctx, admin.ID); err != nil {
+		t.Fatalf("soft delete: %v", err)
+	}
+	if _, err := repo.Note.FindByID(ctx, admin.ID, note.ID); err != ErrNotFound {
+		t.Errorf("find note of deleted user: %v, want ErrNotFound", err)
+	}
+	if err := repo.User.SoftDelete(ctx, admin.ID); err != ErrNotFound {
+		t.Errorf("delete twice: %v, want ErrNotFound", err)
+	}
+}
+
+func TestSQLiteCardsAndSettings(t *testing.T) {
+	ctx := context.Background()
+	repo := NewRepository(openSQLite(t))
+	user := createUser(t, repo, "erin")
+	note := createNotes(t, repo, user.ID, "Flashcards", "Q: 2+2? A: 4")[0]
+
+	card := &model.Card{UserID: user.ID, NoteID: note.ID, LineNumber: 1, Question: "2+2?", Answer: "4"}
+	if err := repo.Card.SyncNote(ctx, user.ID, note.ID, []*model.Card{card}); err != nil {
+		t.Fatalf("sync cards: %v", err)
+	}
+	due, total, err := repo.Card.ListDue(ctx, user.ID, "2025-01-10", 10)
+	if err != nil {
+		t.Fatalf("list due: %v", err)
+	}
+	if total != 1 || len(due) != 1 {
+		t.Fatalf("%d cards due, want 1", total)
+	}
+
+	day, reviewed := "2025-01-12", time.Now()
+	due[0].IntervalDays, due[0].Repetitions, due[0].DueOn, due[0].LastReviewedAt = 2, 1, &day, &reviewed
+	if err := repo.Card.UpdateSchedule(ctx, due[0]); err != nil {
+		t.Fatalf("update schedule: %v", err)
+	}
+	found, err := repo.Card.Find(ctx, user.ID, due[0].ID)
+	if err != nil {
+		t.Fatalf("find card: %v", err)
+	}
+	if found.DueOn == nil || *found.DueOn != day {
+		t.Errorf("card due on %v, want %s", found.DueOn, day)
+	}
+	if found.LastReviewedAt == nil || !found.LastReviewedAt.Equal(reviewed.Truncate(time.Microsecond)) {
+		t.Errorf("card reviewed at %v, want %v", found.LastReviewedAt, reviewed)
+	}
+
+	settings, err := repo.Settings.Get(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("get settings: %v", err)
+	}
+	url := "https://hooks.example.com/digest"
+	settings.DigestURL, settings.DigestHour = &url, 0
+	if err := repo.Settings.SetPreferences(ctx, settings); err != nil {
+		t.Fatalf("set preferences: %v", err)
+	}
+	digests, err := repo.Settings.ListDigestDue(ctx)
+	if err != nil {
+		t.Fatalf("list digests: %v", err)
+	}
+	if len(digests) != 1 {
+		t.Errorf("%d digests due, want 1", len(digests))
+	}
+	if err := repo.Settings.MarkDigestSent(ctx, user.ID, time.Now().UTC().Format("2006-01-02")); err != nil {
+		t.Fatalf("mark digest sent: %v", err)
+	}
+	if digests, _ := repo.Settings.ListDigestDue(ctx); len(digests) != 0 {
+		t.Errorf("%d digests due after sending, want 0", len(digests))
+	}
+}
+
+func TestSQLiteWebhooks(t *testing.T) {
+	ctx := context.Background()
+	repo := NewRepository(openSQLite(t))
+	user := createUser(t, repo, "frank")
+
+	webhook := &model.Webhook{
+		UserID: user.ID, URL: "https://example.com/hook", Secret: "secret", Active: true,
+		Events: []string{string(model.EventNoteCreated)},
+	}
+	if err := repo.Webhook.Create(ctx, webhook); err != nil {
+		t.Fatalf("create webhook: %v", err)
+	}
+
+	subscribed, err := repo.Webhook.ListSubscribed(ctx, user.ID, model.EventNoteCreated)
+	if err != nil {
+		t.Fatalf("list subscribed: %v", err)
+	}
+	if len(subscribed) != 1 {
+		t.Fatalf("%d webhooks subscribed, want 1", len(subscribed))
+	}
+	if others, _ := repo.Webhook.ListSubscribed(ctx, user.ID, model.EventNoteDeleted); len(others) != 0 {
+		t.Errorf("%d webhooks subscribed to note.deleted, want 0", len(others))
+	}
+
+	payload := []byte(`{"event":"note.created"}`)
+	if err := repo.Webhook.CreateDeliveries(ctx, []uuid.UUID{webhook.ID}, model.EventNoteCreated, payload); err != nil {
+		t.Fatalf("create deliveries: %v", err)
+	}
+	claimed, err := repo.Webhook.ClaimDue(ctx, 10, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("claim due: %v", err)
+	}
+	if len(claimed) != 1 || claimed[0].URL != webhook.URL || claimed[0].Secret != webhook.Secret {
+		t.Fatalf("claimed %d deliveries, want 1 to %s", len(claimed), webhook.URL)
+	}
+	if string(claimed[0].Payload) != string(payload) {
+		t.Errorf("payload = %s, want %s", claimed[0].Payload, payload)
+	}
+	if again, _ := repo.Webhook.ClaimDue(ctx, 10, time.Now().Add(time.Minute)); len(again) != 0 {
+		t.Errorf("claimed %d leased deliveries, want 0", len(again))
+	}
+}
+
+func TestSQLiteEmbeddings(t *testing.T) {
+	ctx := context.Background()
+	repo := NewRepository(openSQLite(t))
+	user := createUser(t, repo, "grace")
+	notes := createNotes(t, repo, user.ID, "North", "Points up", "East", "Points right")
+
+	vectors := [][]float32{{0, 1}, {1, 0}}
+	for i, note := range notes {
+		if err := repo.Embedding.Upsert(ctx, note.ID, user.ID, "test", vectors[i], note.UpdatedAt); err != nil {
+			t.Fatalf("upsert embedding: %v", err)
+		}
+	}
+
+	results, err := repo.Embedding.Search(ctx, user.ID, "test", []float32{0.1, 0.9}, "", model.NoteFilter{}, 10)
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(results) != 2 || results[0].Note.ID != notes[0].ID {
+		t.Fatalf("search found %d results, want North first", len(results))
+	}
+	if results[0].Rank <= results[1].Rank {
+		t.Errorf("ranks = %v and %v, want the closest first", results[0].Rank, results[1].Rank)
+	}
+}
+
+func TestSQLiteSendBatch(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLite(t)
+
+	err := db.InTx(ctx, func(tx *DB) error {
+		batch := &pgx.Batch{}
+		batch.Queue("SELECT 1")
+		results := tx.tx.SendBatch(ctx, batch)
+		if results == nil {
+			t.Fatal("send batch returned nil results")
+		}
+
+		if _, err := results.Exec(); !errors.Is(err, errSQLiteUnsupported) {
+			t.Errorf("exec error = %v, want errSQLiteUnsupported", err)
+		}
+		if _, err := results.Query(); !errors.Is(err, errSQLiteUnsupported) {
+			t.Errorf("query error = %v, want errSQLiteUnsupported", err)
+		}
+		var n int
+		if err := results.QueryRow().Scan(&n); !errors.Is(err, errSQLiteUnsupported) {
+			t.Errorf("query row error = %v, want errSQLiteUnsupported", err)
+		}
+		if err := results.Close(); !errors.Is(err, errSQLiteUnsupported) {
+			t.Errorf("close error = %v, want errSQLiteUnsupported", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("transaction: %v", err)
+	}
+}
+
+func TestSQLiteBackupRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	sourceDB, targetDB := openSQLite(t), openSQLite(t)
+	source, target := NewBackupRepository(sourceDB), NewBackupRepository(targetDB)
+	user := createUser(t, NewRepository(sourceDB), "heidi")
+	createNotes(t, NewRepository(sourceDB), user.ID, "Backed up", "Kept across databases")
+
+	for _, table := range []string{"users", "notes"} {
+		var rows []json.RawMessage
+		if _, err := source.Export(ctx, table, func(row []byte) error {
+			rows = append(rows, json.RawMessage(row))
+			return nil
+		}); err != nil {
+			t.Fatalf("export %s: %v", table, err)
+		}
+
+		columns, err := target.Columns(ctx, table)
+		if err != nil {
+			t.Fatalf("columns of %s: %v", table, err)
+		}
+		inserted, err := target.Import(ctx, table, columns, rows)
+		if err != nil {
+			t.Fatalf("import %s: %v", table, err)
+		}
+		if inserted != int64(len(rows)) {
+			t.Errorf("imported %d of %d %s rows", inserted, len(rows), table)
+		}
+	}
+
+	notes, err := NewRepository(targetDB).Note.ListAll(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("list restored notes: %v", err)
+	}
+	if len(notes) != 1 || notes[0].Title != "Backed up" || notes[0].WordCount != 3 {
+		t.Errorf("restored notes = %v, want the backed up note", notes)
+	}
+}
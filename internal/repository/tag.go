@@ -11,39 +11,77 @@ import (
 	"github.com/momokii/go-cli-notes/internal/model"
 )
 
+// tagTreeQuery returns a query selecting the IDs of a tag and all its descendants
+// param is the placeholder holding the root tag ID (e.g. "$1")
+func tagTreeQuery(param string) string {
+	return `
+		WITH RECURSIVE tag_tree AS (
+			SELECT id FROM tags WHERE id = ` + param + `
+			UNION ALL
+			SELECT t.id FROM tags t INNER JOIN tag_tree tt ON t.parent_id = tt.id
+		)
+		SELECT id FROM tag_tree
+	`
+}
+
 // TagRepository handles tag data operations
-type TagRepository struct {
+type TagRepository interface {
+	Create(ctx context.Context, tag *model.Tag) error
+	FindByID(ctx context.Context, userID, id uuid.UUID) (*model.Tag, error)
+	FindByName(ctx context.Context, userID uuid.UUID, name string) (*model.Tag, error)
+	List(ctx context.Context, userID uuid.UUID) ([]*model.Tag, error)
+	ListWithNoteCount(ctx context.Context, userID uuid.UUID, page, limit int) ([]*model.TagWithCount, int64, error)
+	Update(ctx context.Context, tag *model.Tag) error
+	RenameDescendants(ctx context.Context, userID uuid.UUID, oldName, newName string) error
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+	AddToNote(ctx context.Context, noteID, tagID uuid.UUID) error
+	RemoveFromNote(ctx context.Context, noteID, tagID uuid.UUID) error
+	BulkUpdateNotes(ctx context.Context, userID, tagID uuid.UUID, noteIDs []uuid.UUID, add bool) (int64, error)
+	GetByNote(ctx context.Context, noteID uuid.UUID) ([]*model.Tag, error)
+	GetNotesByTag(ctx context.Context, userID, tagID uuid.UUID) ([]*model.Note, error)
+	ListUsage(ctx context.Context, userID uuid.UUID) ([]*model.TagStats, error)
+	CountTaggedByMonth(ctx context.Context, userID uuid.UUID, timezone string, since time.Time) (map[uuid.UUID]map[string]int, error)
+	ListPairs(ctx context.Context, userID uuid.UUID, limit int) ([]*model.TagPair, error)
+}
+
+// tagRepository implements TagRepository
+type tagRepository struct {
 	db *DB
 }
 
 // NewTagRepository creates a new tag repository
 func NewTagRepository(db *DB) TagRepository {
-	return TagRepository{db: db}
+	if db.sqlite != nil {
+		return &sqliteTagRepository{tagRepository: &tagRepository{db: db}}
+	}
+	return &tagRepository{db: db}
 }
 
 // Create inserts a new tag
-func (r *TagRepository) Create(ctx context.Context, tag *model.Tag) error {
+func (r *tagRepository) Create(ctx context.Context, tag *model.Tag) error {
 	query := `
-		INSERT INTO tags (id, user_id, name, color, created_at)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, user_id, name, color, created_at
+		INSERT INTO tags (id, user_id, name, color, parent_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, user_id, name, color, parent_id, created_at
 	`
 
 	now := time.Now()
 	tag.ID = uuid.New()
 	tag.CreatedAt = now
 
-	err := r.db.Pool.QueryRow(ctx, query,
+	err := r.db.conn().QueryRow(ctx, query,
 		tag.ID,
 		tag.UserID,
 		tag.Name,
 		tag.Color,
+		tag.ParentID,
 		tag.CreatedAt,
 	).Scan(
 		&tag.ID,
 		&tag.UserID,
 		&tag.Name,
 		&tag.Color,
+		&tag.ParentID,
 		&tag.CreatedAt,
 	)
 
@@ -55,19 +93,20 @@ func (r *TagRepository) Create(ctx context.Context, tag *model.Tag) error {
 }
 
 // FindByID finds a tag by ID
-func (r *TagRepository) FindByID(ctx context.Context, userID, id uuid.UUID) (*model.Tag, error) {
+func (r *tagRepository) FindByID(ctx context.Context, userID, id uuid.UUID) (*model.Tag, error) {
 	query := `
-		SELECT id, user_id, name, color, created_at
+		SELECT id, user_id, name, color, parent_id, created_at
 		FROM tags
 		WHERE id = $1 AND user_id = $2
 	`
 
 	tag := &model.Tag{}
-	err := r.db.Pool.QueryRow(ctx, query, id, userID).Scan(
+	err := r.db.conn().QueryRow(ctx, query, id, userID).Scan(
 		&tag.ID,
 		&tag.UserID,
 		&tag.Name,
 		&tag.Color,
+		&tag.ParentID,
 		&tag.CreatedAt,
 	)
 
@@ -82,19 +121,20 @@ func (r *TagRepository) FindByID(ctx context.Context, userID, id uuid.UUID) (*mo
 }
 
 // FindByName finds a tag by name for a user
-func (r *TagRepository) FindByName(ctx context.Context, userID uuid.UUID, name string) (*model.Tag, error) {
+func (r *tagRepository) FindByName(ctx context.Context, userID uuid.UUID, name string) (*model.Tag, error) {
 	query := `
-		SELECT id, user_id, name, color, created_at
+		SELECT id, user_id, name, color, parent_id, created_at
 		FROM tags
 		WHERE user_id = $1 AND name = $2
 	`
 
 	tag := &model.Tag{}
-	err := r.db.Pool.QueryRow(ctx, query, userID, name).Scan(
+	err := r.db.conn().QueryRow(ctx, query, userID, name).Scan(
 		&tag.ID,
 		&tag.UserID,
 		&tag.Name,
 		&tag.Color,
+		&tag.ParentID,
 		&tag.CreatedAt,
 	)
 
@@ -109,15 +149,15 @@ func (r *TagRepository) FindByName(ctx context.Context, userID uuid.UUID, name s
 }
 
 // List lists all tags for a user
-func (r *TagRepository) List(ctx context.Context, userID uuid.UUID) ([]*model.Tag, error) {
+func (r *tagRepository) List(ctx context.Context, userID uuid.UUID) ([]*model.Tag, error) {
 	query := `
-		SELECT id, user_id, name, color, created_at
+		SELECT id, user_id, name, color, parent_id, created_at
 		FROM tags
 		WHERE user_id = $1
 		ORDER BY name ASC
 	`
 
-	rows, err := r.db.Pool.Query(ctx, query, userID)
+	rows, err := r.db.conn().Query(ctx, query, userID)
 	if err != nil {
 		return nil, fmt.Errorf("list tags: %w", err)
 	}
@@ -131,6 +171,7 @@ func (r *TagRepository) List(ctx context.Context, userID uuid.UUID) ([]*model.Ta
 			&tag.UserID,
 			&tag.Name,
 			&tag.Color,
+			&tag.ParentID,
 			&tag.CreatedAt,
 		)
 		if err != nil {
@@ -147,10 +188,10 @@ func (r *TagRepository) List(ctx context.Context, userID uuid.UUID) ([]*model.Ta
 }
 
 // ListWithNoteCount lists tags with note count for a user
-func (r *TagRepository) ListWithNoteCount(ctx context.Context, userID uuid.UUID, page, limit int) ([]*model.TagWithCount, int64, error) {
+func (r *tagRepository) ListWithNoteCount(ctx context.Context, userID uuid.UUID, page, limit int) ([]*model.TagWithCount, int64, error) {
 	// Get total count
 	var total int64
-	countErr := r.db.Pool.QueryRow(ctx, "SELECT COUNT(*) FROM tags WHERE user_id = $1", userID).Scan(&total)
+	countErr := r.db.readConn().QueryRow(ctx, "SELECT COUNT(*) FROM tags WHERE user_id = $1", userID).Scan(&total)
 	if countErr != nil {
 		return nil, 0, fmt.Errorf("count tags: %w", countErr)
 	}
@@ -160,16 +201,16 @@ func (r *TagRepository) ListWithNoteCount(ctx context.Context, userID uuid.UUID,
 
 	// Get tags with note count
 	query := `
-		SELECT t.id, t.user_id, t.name, t.color, t.created_at, COUNT(nt.note_id) as note_count
+		SELECT t.id, t.user_id, t.name, t.color, t.parent_id, t.created_at, COUNT(nt.note_id) as note_count
 		FROM tags t
 		LEFT JOIN note_tags nt ON t.id = nt.tag_id
 		WHERE t.user_id = $1
-		GROUP BY t.id, t.user_id, t.name, t.color, t.created_at
+		GROUP BY t.id, t.user_id, t.name, t.color, t.parent_id, t.created_at
 		ORDER BY t.name ASC
 		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := r.db.Pool.Query(ctx, query, userID, limit, offset)
+	rows, err := r.db.readConn().Query(ctx, query, userID, limit, offset)
 	if err != nil {
 		return nil, 0, fmt.Errorf("list tags with count: %w", err)
 	}
@@ -183,6 +224,7 @@ func (r *TagRepository) ListWithNoteCount(ctx context.Context, userID uuid.UUID,
 			&tag.UserID,
 			&tag.Name,
 			&tag.Color,
+			&tag.ParentID,
 			&tag.CreatedAt,
 			&tag.NoteCount,
 		)
@@ -200,18 +242,20 @@ func (r *TagRepository) ListWithNoteCount(ctx context.Context, userID uuid.UUID,
 }
 
 // Update updates a tag
-func (r *TagRepository) Update(ctx context.Context, tag *model.Tag) error {
+func (r *tagRepository) Update(ctx context.Context, tag *model.Tag) error {
 	query := `
 		UPDATE tags
 		SET name = COALESCE($1, name),
-		    color = COALESCE($2, color)
-		WHERE id = $3 AND user_id = $4
-		RETURNING id, user_id, name, color, created_at
+		    color = COALESCE($2, color),
+		    parent_id = $3
+		WHERE id = $4 AND user_id = $5
+		RETURNING id, user_id, name, color, parent_id, created_at
 	`
 
-	err := r.db.Pool.QueryRow(ctx, query,
+	err := r.db.conn().QueryRow(ctx, query,
 		tag.Name,
 		tag.Color,
+		tag.ParentID,
 		tag.ID,
 		tag.UserID,
 	).Scan(
@@ -219,6 +263,7 @@ func (r *TagRepository) Update(ctx context.Context, tag *model.Tag) error {
 		&tag.UserID,
 		&tag.Name,
 		&tag.Color,
+		&tag.ParentID,
 		&tag.CreatedAt,
 	)
 
@@ -232,11 +277,28 @@ func (r *TagRepository) Update(ctx context.Context, tag *model.Tag) error {
 	return nil
 }
 
+// RenameDescendants rewrites the name prefix of all descendants after a tag is renamed
+// e.g. renaming "work" to "job" turns "work/project" into "job/project"
+func (r *tagRepository) RenameDescendants(ctx context.Context, userID uuid.UUID, oldName, newName string) error {
+	query := `
+		UPDATE tags
+		SET name = $3 || substring(name FROM length($2) + 1)
+		WHERE user_id = $1 AND left(name, length($2) + 1) = $2 || '/'
+	`
+
+	_, err := r.db.conn().Exec(ctx, query, userID, oldName, newName)
+	if err != nil {
+		return fmt.Errorf("rename tag descendants: %w", err)
+	}
+
+	return nil
+}
+
 // Delete deletes a tag
-func (r *TagRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
+func (r *tagRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
 	query := `DELETE FROM tags WHERE id = $1 AND user_id = $2`
 
-	result, err := r.db.Pool.Exec(ctx, query, id, userID)
+	result, err := r.db.conn().Exec(ctx, query, id, userID)
 	if err != nil {
 		return fmt.Errorf("delete tag: %w", err)
 	}
@@ -249,14 +311,14 @@ func (r *TagRepository) Delete(ctx context.Context, userID, id uuid.UUID) error
 }
 
 // AddToNote adds a tag to a note
-func (r *TagRepository) AddToNote(ctx context.Context, noteID, tagID uuid.UUID) error {
+func (r *tagRepository) AddToNote(ctx context.Context, noteID, tagID uuid.UUID) error {
 	query := `
 		INSERT INTO note_tags (note_id, tag_id, created_at)
 		VALUES ($1, $2, NOW())
 		ON CONFLICT (note_id, tag_id) DO NOTHING
 	`
 
-	_, err := r.db.Pool.Exec(ctx, query, noteID, tagID)
+	_, err := r.db.conn().Exec(ctx, query, noteID, tagID)
 	if err != nil {
 		return fmt.Errorf("add tag to note: %w", err)
 	}
@@ -265,10 +327,10 @@ func (r *TagRepository) AddToNote(ctx context.Context, noteID, tagID uuid.UUID)
 }
 
 // RemoveFromNote removes a tag from a note
-func (r *TagRepository) RemoveFromNote(ctx context.Context, noteID, tagID uuid.UUID) error {
+func (r *tagRepository) RemoveFromNote(ctx context.Context, noteID, tagID uuid.UUID) error {
 	query := `DELETE FROM note_tags WHERE note_id = $1 AND tag_id = $2`
 
-	result, err := r.db.Pool.Exec(ctx, query, noteID, tagID)
+	result, err := r.db.conn().Exec(ctx, query, noteID, tagID)
 	if err != nil {
 		return fmt.Errorf("remove tag from note: %w", err)
 	}
@@ -280,17 +342,64 @@ func (r *TagRepository) RemoveFromNote(ctx context.Context, noteID, tagID uuid.U
 	return nil
 }
 
+// BulkUpdateNotes adds or removes a tag on many notes in a single transaction
+// It fails with ErrNotFound, changing nothing, if any note doesn't belong to the user.
+// The returned count only includes notes whose tags actually changed.
+func (r *tagRepository) BulkUpdateNotes(ctx context.Context, userID, tagID uuid.UUID, noteIDs []uuid.UUID, add bool) (int64, error) {
+	tx, err := r.db.conn().Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Lock the notes so none of them can be deleted before the tags are written
+	var owned int
+	err = tx.QueryRow(ctx, `
+		SELECT COUNT(*) FROM (
+			SELECT id FROM notes
+			WHERE id = ANY($1) AND user_id = $2 AND is_deleted = false
+			FOR UPDATE
+		) owned
+	`, noteIDs, userID).Scan(&owned)
+	if err != nil {
+		return 0, fmt.Errorf("check notes: %w", err)
+	}
+	if owned != len(noteIDs) {
+		return 0, ErrNotFound
+	}
+
+	query := `DELETE FROM note_tags WHERE note_id = ANY($1) AND tag_id = $2`
+	if add {
+		query = `
+			INSERT INTO note_tags (note_id, tag_id, created_at)
+			SELECT note_id, $2, NOW() FROM UNNEST($1::uuid[]) AS note_id
+			ON CONFLICT (note_id, tag_id) DO NOTHING
+		`
+	}
+
+	result, err := tx.Exec(ctx, query, noteIDs, tagID)
+	if err != nil {
+		return 0, fmt.Errorf("update note tags: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
 // GetByNote gets all tags for a note
-func (r *TagRepository) GetByNote(ctx context.Context, noteID uuid.UUID) ([]*model.Tag, error) {
+func (r *tagRepository) GetByNote(ctx context.Context, noteID uuid.UUID) ([]*model.Tag, error) {
 	query := `
-		SELECT t.id, t.user_id, t.name, t.color, t.created_at
+		SELECT t.id, t.user_id, t.name, t.color, t.parent_id, t.created_at
 		FROM tags t
 		INNER JOIN note_tags nt ON t.id = nt.tag_id
 		WHERE nt.note_id = $1
 		ORDER BY t.name ASC
 	`
 
-	rows, err := r.db.Pool.Query(ctx, query, noteID)
+	rows, err := r.db.conn().Query(ctx, query, noteID)
 	if err != nil {
 		return nil, fmt.Errorf("get tags by note: %w", err)
 	}
@@ -304,6 +413,7 @@ func (r *TagRepository) GetByNote(ctx context.Context, noteID uuid.UUID) ([]*mod
 			&tag.UserID,
 			&tag.Name,
 			&tag.Color,
+			&tag.ParentID,
 			&tag.CreatedAt,
 		)
 		if err != nil {
@@ -319,18 +429,18 @@ func (r *TagRepository) GetByNote(ctx context.Context, noteID uuid.UUID) ([]*mod
 	return tags, nil
 }
 
-// GetNotesByTag gets all notes for a tag
-func (r *TagRepository) GetNotesByTag(ctx context.Context, userID, tagID uuid.UUID) ([]*model.Note, error) {
+// GetNotesByTag gets all notes for a tag, including notes tagged with any descendant tag
+func (r *tagRepository) GetNotesByTag(ctx context.Context, userID, tagID uuid.UUID) ([]*model.Note, error) {
 	query := `
 		SELECT n.id, n.user_id, n.title, n.content, n.note_type, n.word_count, n.reading_time_minutes,
-		       n.is_deleted, n.deleted_at, n.created_at, n.updated_at, n.last_accessed_at, n.access_count, n.metadata
+		       n.is_deleted, n.deleted_at, n.created_at, n.updated_at, n.last_accessed_at, n.access_count, n.metadata, n.encrypted
 		FROM notes n
-		INNER JOIN note_tags nt ON n.id = nt.note_id
-		WHERE nt.tag_id = $1 AND n.user_id = $2 AND n.is_deleted = false
+		WHERE n.id IN (SELECT note_id FROM note_tags WHERE tag_id IN (` + tagTreeQuery("$1") + `))
+		  AND n.user_id = $2 AND n.is_deleted = false
 		ORDER BY n.updated_at DESC
 	`
 
-	rows, err := r.db.Pool.Query(ctx, query, tagID, userID)
+	rows, err := r.db.conn().Query(ctx, query, tagID, userID)
 	if err != nil {
 		return nil, fmt.Errorf("get notes by tag: %w", err)
 	}
@@ -354,6 +464,7 @@ func (r *TagRepository) GetNotesByTag(ctx context.Context, userID, tagID uuid.UU
 			&note.LastAccessedAt,
 			&note.AccessCount,
 			&note.Metadata,
+			&note.Encrypted,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("scan note: %w", err)
@@ -367,3 +478,141 @@ func (r *TagRepository) GetNotesByTag(ctx context.Context, userID, tagID uuid.UU
 
 	return notes, nil
 }
+
+// ListUsage lists a user's tags with how many live notes carry each and when one was last tagged, most used first
+func (r *tagRepository) ListUsage(ctx context.Context, userID uuid.UUID) ([]*model.TagStats, error) {
+	query := `
+		SELECT t.id, t.user_id, t.name, t.color, t.parent_id, t.created_at, COUNT(nt.note_id), MAX(nt.created_at)
+		FROM tags t
+		LEFT JOIN (note_tags nt INNER JOIN notes n ON n.id = nt.note_id AND n.is_deleted = false) ON nt.tag_id = t.id
+		WHERE t.user_id = $1
+		GROUP BY t.id, t.user_id, t.name, t.color, t.parent_id, t.created_at
+		ORDER BY COUNT(nt.note_id) DESC, t.name ASC
+	`
+
+	rows, err := r.db.readConn().Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list tag usage: %w", err)
+	}
+	defer rows.Close()
+
+	usage := []*model.TagStats{}
+	for rows.Next() {
+		u := &model.TagStats{Tag: &model.Tag{}}
+		err := rows.Scan(
+			&u.Tag.ID,
+			&u.Tag.UserID,
+			&u.Tag.Name,
+			&u.Tag.Color,
+			&u.Tag.ParentID,
+			&u.Tag.CreatedAt,
+			&u.NoteCount,
+			&u.LastTaggedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan tag usage: %w", err)
+		}
+		usage = append(usage, u)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("iterate tag usage: %w", rows.Err())
+	}
+
+	return usage, nil
+}
+
+// CountTaggedByMonth counts the live notes each of a user's tags was put on in each month since since,
+// keyed by tag ID and then YYYY-MM in timezone
+func (r *tagRepository) CountTaggedByMonth(ctx context.Context, userID uuid.UUID, timezone string, since time.Time) (map[uuid.UUID]map[string]int, error) {
+	query := `
+		SELECT nt.tag_id, TO_CHAR(DATE_TRUNC('month', nt.created_at AT TIME ZONE $2), 'YYYY-MM'), COUNT(*)
+		FROM note_tags nt
+		INNER JOIN tags t ON t.id = nt.tag_id AND t.user_id = $1
+		INNER JOIN notes n ON n.id = nt.note_id AND n.is_deleted = false
+		WHERE nt.created_at >= $3
+		GROUP BY 1, 2
+	`
+
+	rows, err := r.db.readConn().Query(ctx, query, userID, timezone, since)
+	if err != nil {
+		return nil, fmt.Errorf("count tagged notes by month: %w", err)
+	}
+
+	return collectTaggedByMonth(rows)
+}
+
+// collectTaggedByMonth scans tag ID, month and count rows into counts by tag and month
+func collectTaggedByMonth(rows pgx.Rows) (map[uuid.UUID]map[string]int, error) {
+	defer rows.Close()
+
+	counts := map[uuid.UUID]map[string]int{}
+	for rows.Next() {
+		var tagID uuid.UUID
+		var month string
+		var count int
+		if err := rows.Scan(&tagID, &month, &count); err != nil {
+			return nil, fmt.Errorf("scan tagged notes by month: %w", err)
+		}
+		if counts[tagID] == nil {
+			counts[tagID] = map[string]int{}
+		}
+		counts[tagID][month] = count
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("iterate tagged notes by month: %w", rows.Err())
+	}
+
+	return counts, nil
+}
+
+// ListPairs lists the pairs of a user's tags that share at least two live notes, most shared first
+// The tags of a pair come in no particular order.
+func (r *tagRepository) ListPairs(ctx context.Context, userID uuid.UUID, limit int) ([]*model.TagPair, error) {
+	query := `
+		WITH pairs AS (
+			SELECT a.tag_id AS tag_a, b.tag_id AS tag_b, COUNT(*) AS note_count
+			FROM note_tags a
+			INNER JOIN note_tags b ON b.note_id = a.note_id AND a.tag_id < b.tag_id
+			INNER JOIN tags t ON t.id = a.tag_id AND t.user_id = $1
+			INNER JOIN notes n ON n.id = a.note_id AND n.is_deleted = false
+			GROUP BY 1, 2
+			HAVING COUNT(*) >= 2
+		)
+		SELECT ta.id, ta.user_id, ta.name, ta.color, ta.parent_id, ta.created_at,
+		       tb.id, tb.user_id, tb.name, tb.color, tb.parent_id, tb.created_at,
+		       p.note_count
+		FROM pairs p
+		INNER JOIN tags ta ON ta.id = p.tag_a AND ta.user_id = $1
+		INNER JOIN tags tb ON tb.id = p.tag_b AND tb.user_id = $1
+		ORDER BY p.note_count DESC, ta.name ASC, tb.name ASC
+		LIMIT $2
+	`
+
+	rows, err := r.db.readConn().Query(ctx, query, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list tag pairs: %w", err)
+	}
+	defer rows.Close()
+
+	pairs := []*model.TagPair{}
+	for rows.Next() {
+		p := &model.TagPair{TagA: &model.Tag{}, TagB: &model.Tag{}}
+		err := rows.Scan(
+			&p.TagA.ID, &p.TagA.UserID, &p.TagA.Name, &p.TagA.Color, &p.TagA.ParentID, &p.TagA.CreatedAt,
+			&p.TagB.ID, &p.TagB.UserID, &p.TagB.Name, &p.TagB.Color, &p.TagB.ParentID, &p.TagB.CreatedAt,
+			&p.NoteCount,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan tag pair: %w", err)
+		}
+		pairs = append(pairs, p)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("iterate tag pairs: %w", rows.Err())
+	}
+
+	return pairs, nil
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// sqliteTagRepository is the TagRepository of SQLite databases
+type sqliteTagRepository struct {
+	*tagRepository
+}
+
+// RenameDescendants renames the tags nested under oldName to sit under newName
+func (r *sqliteTagRepository) RenameDescendants(ctx context.Context, userID uuid.UUID, oldName, newName string) error {
+	query := `
+		UPDATE tags
+		SET name = $3 || substr(name, length($2) + 1)
+		WHERE user_id = $1 AND substr(name, 1, length($2) + 1) = $2 || '/'
+	`
+
+	_, err := r.db.conn().Exec(ctx, query, userID, oldName, newName)
+	if err != nil {
+		return fmt.Errorf("rename tag descendants: %w", err)
+	}
+
+	return nil
+}
+
+// BulkUpdateNotes adds the tag to, or removes it from, all of the given notes
+// The transaction holds SQLite's write lock from the start, so no note can be deleted
+// between the check and the write.
+func (r *sqliteTagRepository) BulkUpdateNotes(ctx context.Context, userID, tagID uuid.UUID, noteIDs []uuid.UUID, add bool) (int64, error) {
+	tx, err := r.db.conn().Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var owned int
+	err = tx.QueryRow(ctx, `
+		SELECT COUNT(*) FROM notes
+		WHERE id IN (SELECT value FROM json_each($1)) AND user_id = $2 AND is_deleted = false
+	`, noteIDs, userID).Scan(&owned)
+	if err != nil {
+		return 0, fmt.Errorf("check notes: %w", err)
+	}
+	if owned != len(noteIDs) {
+		return 0, ErrNotFound
+	}
+
+	query := `DELETE FROM note_tags WHERE note_id IN (SELECT value FROM json_each($1)) AND tag_id = $2`
+	if add {
+		query = `
+			INSERT INTO note_tags (note_id, tag_id, created_at)
+			SELECT value, $2, NOW() FROM json_each($1) WHERE true
+			ON CONFLICT (note_id, tag_id) DO NOTHING
+		`
+	}
+
+	result, err := tx.Exec(ctx, query, noteIDs, tagID)
+	if err != nil {
+		return 0, fmt.Errorf("update note tags: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// CountTaggedByMonth counts the live notes each of a user's tags was put on in each month since since,
+// keyed by tag ID and then YYYY-MM in timezone
+func (r *sqliteTagRepository) CountTaggedByMonth(ctx context.Context, userID uuid.UUID, timezone string, since time.Time) (map[uuid.UUID]map[string]int, error) {
+	query := `
+		SELECT nt.tag_id, local_month(nt.created_at, $2), COUNT(*)
+		FROM note_tags nt
+		INNER JOIN tags t ON t.id = nt.tag_id AND t.user_id = $1
+		INNER JOIN notes n ON n.id = nt.note_id AND n.is_deleted = false
+		WHERE nt.created_at >= $3
+		GROUP BY 1, 2
+	`
+
+	rows, err := r.db.readConn().Query(ctx, query, userID, timezone, since)
+	if err != nil {
+		return nil, fmt.Errorf("count tagged notes by month: %w", err)
+	}
+
+	return collectTaggedByMonth(rows)
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+// TaskRepository handles task data operations
+type TaskRepository struct {
+	db *DB
+}
+
+// NewTaskRepository creates a new task repository
+func NewTaskRepository(db *DB) TaskRepository {
+	return TaskRepository{db: db}
+}
+
+// Create inserts a new task
+func (r *TaskRepository) Create(ctx context.Context, task *model.Task) error {
+	query := `
+		INSERT INTO tasks (id, user_id, note_id, line_number, content, completed, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	task.ID = uuid.New()
+	task.CreatedAt = time.Now()
+
+	_, err := r.db.conn().Exec(ctx, query,
+		task.ID,
+		task.UserID,
+		task.NoteID,
+		task.LineNumber,
+		task.Content,
+		task.Completed,
+		task.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("create task: %w", err)
+	}
+
+	return nil
+}
+
+// List lists a user's tasks filtered by status, most recently updated notes first
+func (r *TaskRepository) List(ctx context.Context, userID uuid.UUID, status model.TaskStatus) ([]*model.Task, error) {
+	query := `
+		SELECT t.id, t.user_id, t.note_id, n.title, t.line_number, t.content, t.completed, t.created_at
+		FROM tasks t
+		INNER JOIN notes n ON n.id = t.note_id
+		WHERE t.user_id = $1 AND n.is_deleted = false
+	`
+	switch status {
+	case model.TaskStatusOpen:
+		query += " AND t.completed = false"
+	case model.TaskStatusDone:
+		query += " AND t.completed = true"
+	}
+	query += " ORDER BY n.updated_at DESC, t.line_number ASC"
+
+	rows, err := r.db.readConn().Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list tasks: %w", err)
+	}
+	defer rows.Close()
+
+	tasks := []*model.Task{}
+	for rows.Next() {
+		task := &model.Task{}
+		err := rows.Scan(
+			&task.ID,
+			&task.UserID,
+			&task.NoteID,
+			&task.NoteTitle,
+			&task.LineNumber,
+			&task.Content,
+			&task.Completed,
+			&task.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan task: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("iterate tasks: %w", rows.Err())
+	}
+
+	return tasks, nil
+}
+
+// DeleteByNote deletes all tasks extracted from a note
+func (r *TaskRepository) DeleteByNote(ctx context.Context, userID, noteID uuid.UUID) error {
+	query := `DELETE FROM tasks WHERE user_id = $1 AND note_id = $2`
+
+	_, err := r.db.conn().Exec(ctx, query, userID, noteID)
+	if err != nil {
+		return fmt.Errorf("delete tasks by note: %w", err)
+	}
+
+	return nil
+}
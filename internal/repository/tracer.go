@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// slowQueryTracer logs queries that take at least threshold to run
+// Query arguments are left out of the log, since they hold note content.
+type slowQueryTracer struct {
+	threshold time.Duration
+}
+
+// queryStartKey is the context key of the start of a traced query
+type queryStartKey struct{}
+
+// queryStart is a query being traced
+type queryStart struct {
+	sql   string
+	start time.Time
+}
+
+// TraceQueryStart records when a query starts
+func (t *slowQueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, queryStartKey{}, queryStart{sql: data.SQL, start: time.Now()})
+}
+
+// TraceQueryEnd logs the query if it was slow
+func (t *slowQueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	query, ok := ctx.Value(queryStartKey{}).(queryStart)
+	if !ok {
+		return
+	}
+
+	duration := time.Since(query.start)
+	if duration < t.threshold {
+		return
+	}
+
+	logSlowQuery(ctx, query.sql, duration, data.CommandTag, data.Err)
+}
+
+// logSlowQuery logs a query that took duration to run
+func logSlowQuery(ctx context.Context, sql string, duration time.Duration, tag pgconn.CommandTag, err error) {
+	attrs := []any{
+		"duration", duration,
+		"sql", strings.Join(strings.Fields(sql), " "),
+		"rows", tag.RowsAffected(),
+	}
+	// Handlers pass the Fiber request context, whose values are the request's locals
+	if requestID, ok := ctx.Value("request_id").(string); ok {
+		attrs = append(attrs, "request_id", requestID)
+	}
+	if err != nil {
+		attrs = append(attrs, "error", err)
+	}
+	slog.Warn("Slow database query", attrs...)
+}
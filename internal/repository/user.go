@@ -12,21 +12,44 @@ import (
 )
 
 // UserRepository handles user data operations
-type UserRepository struct {
+type UserRepository interface {
+	Create(ctx context.Context, user *model.User) error
+	FindByID(ctx context.Context, id uuid.UUID) (*model.User, error)
+	FindByEmail(ctx context.Context, email string) (*model.User, error)
+	FindByUsername(ctx context.Context, username string) (*model.User, error)
+	UpdateLastLogin(ctx context.Context, userID uuid.UUID) error
+	MarkVerified(ctx context.Context, userID uuid.UUID) error
+	UpdatePassword(ctx context.Context, userID uuid.UUID, passwordHash string) error
+	SetTOTPSecret(ctx context.Context, userID uuid.UUID, secret string) error
+	EnableTOTP(ctx context.Context, userID uuid.UUID) error
+	SoftDelete(ctx context.Context, userID uuid.UUID) error
+	List(ctx context.Context, page, limit int) ([]*model.AdminUser, int64, error)
+	SetActive(ctx context.Context, userID uuid.UUID, active bool) error
+	PromoteAdmins(ctx context.Context, emails []string) (int64, error)
+	GetAdminStats(ctx context.Context) (*model.AdminStats, error)
+	ExistsByEmail(ctx context.Context, email string) (bool, error)
+	ExistsByUsername(ctx context.Context, username string) (bool, error)
+}
+
+// userRepository implements UserRepository
+type userRepository struct {
 	db *DB
 }
 
 // NewUserRepository creates a new user repository
 func NewUserRepository(db *DB) UserRepository {
-	return UserRepository{db: db}
+	if db.sqlite != nil {
+		return &sqliteUserRepository{userRepository: &userRepository{db: db}}
+	}
+	return &userRepository{db: db}
 }
 
 // Create inserts a new user
-func (r *UserRepository) Create(ctx context.Context, user *model.User) error {
+func (r *userRepository) Create(ctx context.Context, user *model.User) error {
 	query := `
 		INSERT INTO users (id, email, password_hash, username, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING id, email, username, created_at, updated_at, is_active
+		RETURNING id, email, username, created_at, updated_at, is_active, is_verified, role
 	`
 
 	now := time.Now()
@@ -34,7 +57,7 @@ func (r *UserRepository) Create(ctx context.Context, user *model.User) error {
 	user.CreatedAt = now
 	user.UpdatedAt = now
 
-	err := r.db.Pool.QueryRow(ctx, query,
+	err := r.db.conn().QueryRow(ctx, query,
 		user.ID,
 		user.Email,
 		user.PasswordHash,
@@ -48,6 +71,8 @@ func (r *UserRepository) Create(ctx context.Context, user *model.User) error {
 		&user.CreatedAt,
 		&user.UpdatedAt,
 		&user.IsActive,
+		&user.IsVerified,
+		&user.Role,
 	)
 
 	if err != nil {
@@ -58,16 +83,16 @@ func (r *UserRepository) Create(ctx context.Context, user *model.User) error {
 }
 
 // FindByID finds a user by ID
-func (r *UserRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.User, error) {
+func (r *userRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.User, error) {
 	query := `
 		SELECT id, email, password_hash, username, created_at, updated_at,
-		       last_login_at, is_active
+		       last_login_at, is_active, is_verified, totp_secret, totp_enabled, role
 		FROM users
 		WHERE id = $1
 	`
 
 	user := &model.User{}
-	err := r.db.Pool.QueryRow(ctx, query, id).Scan(
+	err := r.db.conn().QueryRow(ctx, query, id).Scan(
 		&user.ID,
 		&user.Email,
 		&user.PasswordHash,
@@ -76,6 +101,10 @@ func (r *UserRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.Use
 		&user.UpdatedAt,
 		&user.LastLoginAt,
 		&user.IsActive,
+		&user.IsVerified,
+		&user.TOTPSecret,
+		&user.TOTPEnabled,
+		&user.Role,
 	)
 
 	if err == pgx.ErrNoRows {
@@ -89,16 +118,16 @@ func (r *UserRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.Use
 }
 
 // FindByEmail finds a user by email
-func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*model.User, error) {
+func (r *userRepository) FindByEmail(ctx context.Context, email string) (*model.User, error) {
 	query := `
 		SELECT id, email, password_hash, username, created_at, updated_at,
-		       last_login_at, is_active
+		       last_login_at, is_active, is_verified, totp_secret, totp_enabled, role
 		FROM users
 		WHERE email = $1
 	`
 
 	user := &model.User{}
-	err := r.db.Pool.QueryRow(ctx, query, email).Scan(
+	err := r.db.conn().QueryRow(ctx, query, email).Scan(
 		&user.ID,
 		&user.Email,
 		&user.PasswordHash,
@@ -107,6 +136,10 @@ func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*model.
 		&user.UpdatedAt,
 		&user.LastLoginAt,
 		&user.IsActive,
+		&user.IsVerified,
+		&user.TOTPSecret,
+		&user.TOTPEnabled,
+		&user.Role,
 	)
 
 	if err == pgx.ErrNoRows {
@@ -120,16 +153,16 @@ func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*model.
 }
 
 // FindByUsername finds a user by username
-func (r *UserRepository) FindByUsername(ctx context.Context, username string) (*model.User, error) {
+func (r *userRepository) FindByUsername(ctx context.Context, username string) (*model.User, error) {
 	query := `
 		SELECT id, email, password_hash, username, created_at, updated_at,
-		       last_login_at, is_active
+		       last_login_at, is_active, is_verified, totp_secret, totp_enabled, role
 		FROM users
 		WHERE username = $1
 	`
 
 	user := &model.User{}
-	err := r.db.Pool.QueryRow(ctx, query, username).Scan(
+	err := r.db.conn().QueryRow(ctx, query, username).Scan(
 		&user.ID,
 		&user.Email,
 		&user.PasswordHash,
@@ -138,6 +171,10 @@ func (r *UserRepository) FindByUsername(ctx context.Context, username string) (*
 		&user.UpdatedAt,
 		&user.LastLoginAt,
 		&user.IsActive,
+		&user.IsVerified,
+		&user.TOTPSecret,
+		&user.TOTPEnabled,
+		&user.Role,
 	)
 
 	if err == pgx.ErrNoRows {
@@ -151,14 +188,14 @@ func (r *UserRepository) FindByUsername(ctx context.Context, username string) (*
 }
 
 // UpdateLastLogin updates the user's last login timestamp
-func (r *UserRepository) UpdateLastLogin(ctx context.Context, userID uuid.UUID) error {
+func (r *userRepository) UpdateLastLogin(ctx context.Context, userID uuid.UUID) error {
 	query := `
 		UPDATE users
 		SET last_login_at = NOW()
 		WHERE id = $1
 	`
 
-	_, err := r.db.Pool.Exec(ctx, query, userID)
+	_, err := r.db.conn().Exec(ctx, query, userID)
 	if err != nil {
 		return fmt.Errorf("update last login: %w", err)
 	}
@@ -166,12 +203,245 @@ func (r *UserRepository) UpdateLastLogin(ctx context.Context, userID uuid.UUID)
 	return nil
 }
 
+// MarkVerified marks the user's email address as verified
+func (r *userRepository) MarkVerified(ctx context.Context, userID uuid.UUID) error {
+	query := `
+		UPDATE users
+		SET is_verified = true, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	result, err := r.db.conn().Exec(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("mark verified: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// UpdatePassword replaces the user's password hash
+func (r *userRepository) UpdatePassword(ctx context.Context, userID uuid.UUID, passwordHash string) error {
+	query := `
+		UPDATE users
+		SET password_hash = $2, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	result, err := r.db.conn().Exec(ctx, query, userID, passwordHash)
+	if err != nil {
+		return fmt.Errorf("update password: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// SetTOTPSecret stores a pending TOTP secret; 2FA stays disabled until EnableTOTP
+func (r *userRepository) SetTOTPSecret(ctx context.Context, userID uuid.UUID, secret string) error {
+	query := `
+		UPDATE users
+		SET totp_secret = $2, totp_enabled = false, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	result, err := r.db.conn().Exec(ctx, query, userID, secret)
+	if err != nil {
+		return fmt.Errorf("set totp secret: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// EnableTOTP turns on two-factor authentication for a user with a stored secret
+func (r *userRepository) EnableTOTP(ctx context.Context, userID uuid.UUID) error {
+	query := `
+		UPDATE users
+		SET totp_enabled = true, updated_at = NOW()
+		WHERE id = $1 AND totp_secret IS NOT NULL
+	`
+
+	result, err := r.db.conn().Exec(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("enable totp: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// SoftDelete deactivates a user and soft-deletes all of their notes
+func (r *userRepository) SoftDelete(ctx context.Context, userID uuid.UUID) error {
+	query := `
+		WITH deleted_notes AS (
+			UPDATE notes
+			SET is_deleted = true, deleted_at = NOW()
+			WHERE user_id = $1 AND is_deleted = false
+		)
+		UPDATE users
+		SET is_active = false, deleted_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	result, err := r.db.conn().Exec(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("soft delete user: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// List lists all users, oldest first, with the total count
+// Deleted accounts are included so admins can see them.
+func (r *userRepository) List(ctx context.Context, page, limit int) ([]*model.AdminUser, int64, error) {
+	var total int64
+	if err := r.db.readConn().QueryRow(ctx, `SELECT COUNT(*) FROM users`).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count users: %w", err)
+	}
+
+	query := `
+		SELECT u.id, u.email, u.username, u.created_at, u.updated_at, u.last_login_at,
+		       u.is_active, u.is_verified, u.totp_enabled, u.role, u.deleted_at,
+		       (SELECT COUNT(*) FROM notes n WHERE n.user_id = u.id AND n.is_deleted = false)
+		FROM users u
+		ORDER BY u.created_at ASC
+		LIMIT $1 OFFSET $2
+	`
+
+	rows, err := r.db.readConn().Query(ctx, query, limit, (page-1)*limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list users: %w", err)
+	}
+	defer rows.Close()
+
+	users := []*model.AdminUser{}
+	for rows.Next() {
+		user := &model.AdminUser{}
+		err := rows.Scan(
+			&user.ID,
+			&user.Email,
+			&user.Username,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+			&user.LastLoginAt,
+			&user.IsActive,
+			&user.IsVerified,
+			&user.TOTPEnabled,
+			&user.Role,
+			&user.DeletedAt,
+			&user.NoteCount,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scan user: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	if rows.Err() != nil {
+		return nil, 0, fmt.Errorf("iterate users: %w", rows.Err())
+	}
+
+	return users, total, nil
+}
+
+// SetActive activates or deactivates a user
+// Deleted accounts stay deactivated.
+func (r *userRepository) SetActive(ctx context.Context, userID uuid.UUID, active bool) error {
+	query := `
+		UPDATE users
+		SET is_active = $2, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	result, err := r.db.conn().Exec(ctx, query, userID, active)
+	if err != nil {
+		return fmt.Errorf("set user active: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// PromoteAdmins gives the admin role to the users with these emails
+// It returns how many users were promoted; emails without an account are ignored.
+func (r *userRepository) PromoteAdmins(ctx context.Context, emails []string) (int64, error) {
+	query := `
+		UPDATE users
+		SET role = 'admin', updated_at = NOW()
+		WHERE email = ANY($1) AND role <> 'admin'
+	`
+
+	result, err := r.db.conn().Exec(ctx, query, emails)
+	if err != nil {
+		return 0, fmt.Errorf("promote admins: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// GetAdminStats gets statistics across all users
+func (r *userRepository) GetAdminStats(ctx context.Context) (*model.AdminStats, error) {
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM users),
+			(SELECT COUNT(*) FROM users WHERE is_active = true AND deleted_at IS NULL),
+			(SELECT COUNT(*) FROM users WHERE created_at >= $1),
+			(SELECT COUNT(*) FROM users WHERE last_login_at >= $1),
+			(SELECT COUNT(*) FROM notes WHERE is_deleted = false),
+			(SELECT COALESCE(SUM(word_count), 0) FROM notes WHERE is_deleted = false),
+			(SELECT COUNT(*) FROM tags),
+			(SELECT COUNT(*) FROM links),
+			(SELECT COUNT(*) FROM attachments),
+			(SELECT COALESCE(SUM(size_bytes), 0) FROM attachments)
+	`
+
+	stats := &model.AdminStats{}
+	weekAgo := time.Now().AddDate(0, 0, -7)
+	err := r.db.readConn().QueryRow(ctx, query, weekAgo).Scan(
+		&stats.TotalUsers,
+		&stats.ActiveUsers,
+		&stats.NewUsersWeek,
+		&stats.ActiveUsersWeek,
+		&stats.TotalNotes,
+		&stats.TotalWords,
+		&stats.TotalTags,
+		&stats.TotalLinks,
+		&stats.TotalAttachments,
+		&stats.AttachmentBytes,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get admin stats: %w", err)
+	}
+
+	return stats, nil
+}
+
 // ExistsByEmail checks if a user exists by email
-func (r *UserRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+func (r *userRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
 	query := `SELECT EXISTS(SELECT 1 FROM users WHERE email = $1)`
 
 	var exists bool
-	err := r.db.Pool.QueryRow(ctx, query, email).Scan(&exists)
+	err := r.db.conn().QueryRow(ctx, query, email).Scan(&exists)
 	if err != nil {
 		return false, fmt.Errorf("check email exists: %w", err)
 	}
@@ -180,11 +450,11 @@ func (r *UserRepository) ExistsByEmail(ctx context.Context, email string) (bool,
 }
 
 // ExistsByUsername checks if a user exists by username
-func (r *UserRepository) ExistsByUsername(ctx context.Context, username string) (bool, error) {
+func (r *userRepository) ExistsByUsername(ctx context.Context, username string) (bool, error) {
 	query := `SELECT EXISTS(SELECT 1 FROM users WHERE username = $1)`
 
 	var exists bool
-	err := r.db.Pool.QueryRow(ctx, query, username).Scan(&exists)
+	err := r.db.conn().QueryRow(ctx, query, username).Scan(&exists)
 	if err != nil {
 		return false, fmt.Errorf("check username exists: %w", err)
 	}
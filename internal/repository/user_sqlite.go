@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// sqliteUserRepository is the UserRepository of SQLite databases
+type sqliteUserRepository struct {
+	*userRepository
+}
+
+// SoftDelete deactivates a user and soft-deletes all of their notes
+// SQLite has no data-modifying CTEs, so it's two statements in one transaction.
+func (r *sqliteUserRepository) SoftDelete(ctx context.Context, userID uuid.UUID) error {
+	return r.db.InTx(ctx, func(tx *DB) error {
+		result, err := tx.conn().Exec(ctx, `
+			UPDATE users
+			SET is_active = false, deleted_at = NOW(), updated_at = NOW()
+			WHERE id = $1 AND deleted_at IS NULL
+		`, userID)
+		if err != nil {
+			return fmt.Errorf("soft delete user: %w", err)
+		}
+
+		if result.RowsAffected() == 0 {
+			return ErrNotFound
+		}
+
+		_, err = tx.conn().Exec(ctx, `
+			UPDATE notes
+			SET is_deleted = true, deleted_at = NOW()
+			WHERE user_id = $1 AND is_deleted = false
+		`, userID)
+		if err != nil {
+			return fmt.Errorf("soft delete user notes: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// PromoteAdmins gives the admin role to the users with these emails
+// It returns how many users were promoted; emails without an account are ignored.
+func (r *sqliteUserRepository) PromoteAdmins(ctx context.Context, emails []string) (int64, error) {
+	query := `
+		UPDATE users
+		SET role = 'admin', updated_at = NOW()
+		WHERE email IN (SELECT value FROM json_each($1)) AND role <> 'admin'
+	`
+
+	result, err := r.db.conn().Exec(ctx, query, emails)
+	if err != nil {
+		return 0, fmt.Errorf("promote admins: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,347 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+// webhookColumns are the columns scanned by scanWebhook
+const webhookColumns = `id, user_id, url, events, secret, active, created_at, updated_at`
+
+// deliveryColumns are the columns scanned by scanDelivery
+const deliveryColumns = `id, webhook_id, event, payload, status, attempts, response_status, error,
+	next_attempt_at, last_attempt_at, created_at`
+
+// WebhookRepository handles webhook and delivery data operations
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *model.Webhook) error
+	FindByID(ctx context.Context, userID, id uuid.UUID) (*model.Webhook, error)
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*model.Webhook, error)
+	ListSubscribed(ctx context.Context, userID uuid.UUID, event model.EventType) ([]*model.Webhook, error)
+	Update(ctx context.Context, webhook *model.Webhook) error
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+	CreateDeliveries(ctx context.Context, webhookIDs []uuid.UUID, event model.EventType, payload []byte) error
+	ListDeliveries(ctx context.Context, webhookID uuid.UUID, limit int) ([]*model.WebhookDelivery, error)
+	ClaimDue(ctx context.Context, limit int, leaseUntil time.Time) ([]*model.QueuedDelivery, error)
+	SaveAttempt(ctx context.Context, delivery *model.WebhookDelivery) error
+	DeleteDeliveriesBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// webhookRepository implements WebhookRepository
+type webhookRepository struct {
+	db *DB
+}
+
+// NewWebhookRepository creates a new webhook repository
+func NewWebhookRepository(db *DB) WebhookRepository {
+	if db.sqlite != nil {
+		return &sqliteWebhookRepository{webhookRepository: &webhookRepository{db: db}}
+	}
+	return &webhookRepository{db: db}
+}
+
+// Create inserts a new webhook
+func (r *webhookRepository) Create(ctx context.Context, webhook *model.Webhook) error {
+	query := `
+		INSERT INTO webhooks (id, user_id, url, events, secret, active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $7)
+	`
+
+	webhook.ID = uuid.New()
+	webhook.CreatedAt = time.Now()
+	webhook.UpdatedAt = webhook.CreatedAt
+
+	_, err := r.db.conn().Exec(ctx, query,
+		webhook.ID,
+		webhook.UserID,
+		webhook.URL,
+		webhook.Events,
+		webhook.Secret,
+		webhook.Active,
+		webhook.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("create webhook: %w", err)
+	}
+
+	return nil
+}
+
+// FindByID finds a webhook of the user by ID
+func (r *webhookRepository) FindByID(ctx context.Context, userID, id uuid.UUID) (*model.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE id = $1 AND user_id = $2`
+
+	webhook, err := scanWebhook(r.db.conn().QueryRow(ctx, query, id, userID))
+	if err == pgx.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find webhook by id: %w", err)
+	}
+
+	return webhook, nil
+}
+
+// ListByUser lists the webhooks of a user, oldest first
+func (r *webhookRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*model.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE user_id = $1 ORDER BY created_at ASC`
+
+	return r.list(ctx, r.db.readConn(), query, "list webhooks", userID)
+}
+
+// ListSubscribed lists the active webhooks of a user subscribed to an event
+func (r *webhookRepository) ListSubscribed(ctx context.Context, userID uuid.UUID, event model.EventType) ([]*model.Webhook, error) {
+	query := `
+		SELECT ` + webhookColumns + `
+		FROM webhooks
+		WHERE user_id = $1 AND active = true AND $2 = ANY(events)
+	`
+
+	return r.list(ctx, r.db.conn(), query, "list subscribed webhooks", userID, string(event))
+}
+
+// list runs a query returning webhooks
+func (r *webhookRepository) list(ctx context.Context, q Querier, query, op string, args ...any) ([]*model.Webhook, error) {
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	webhooks := []*model.Webhook{}
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan webhook: %w", err)
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("iterate webhooks: %w", rows.Err())
+	}
+
+	return webhooks, nil
+}
+
+// Update saves the URL, events and active flag of a webhook
+func (r *webhookRepository) Update(ctx context.Context, webhook *model.Webhook) error {
+	query := `
+		UPDATE webhooks
+		SET url = $1, events = $2, active = $3, updated_at = $4
+		WHERE id = $5 AND user_id = $6
+	`
+
+	webhook.UpdatedAt = time.Now()
+	result, err := r.db.conn().Exec(ctx, query,
+		webhook.URL,
+		webhook.Events,
+		webhook.Active,
+		webhook.UpdatedAt,
+		webhook.ID,
+		webhook.UserID,
+	)
+	if err != nil {
+		return fmt.Errorf("update webhook: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// Delete deletes a webhook of the user with its deliveries
+func (r *webhookRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	query := `DELETE FROM webhooks WHERE id = $1 AND user_id = $2`
+
+	result, err := r.db.conn().Exec(ctx, query, id, userID)
+	if err != nil {
+		return fmt.Errorf("delete webhook: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// CreateDeliveries queues the same event for each of the webhooks
+func (r *webhookRepository) CreateDeliveries(ctx context.Context, webhookIDs []uuid.UUID, event model.EventType, payload []byte) error {
+	query := `
+		INSERT INTO webhook_deliveries (webhook_id, event, payload, next_attempt_at, created_at)
+		SELECT webhook_id, $2, $3, $4, $4
+		FROM unnest($1::uuid[]) AS webhook_id
+	`
+
+	_, err := r.db.conn().Exec(ctx, query, webhookIDs, string(event), payload, time.Now())
+	if err != nil {
+		return fmt.Errorf("create webhook deliveries: %w", err)
+	}
+
+	return nil
+}
+
+// ListDeliveries lists the latest deliveries of a webhook, newest first
+func (r *webhookRepository) ListDeliveries(ctx context.Context, webhookID uuid.UUID, limit int) ([]*model.WebhookDelivery, error) {
+	query := `
+		SELECT ` + deliveryColumns + `
+		FROM webhook_deliveries
+		WHERE webhook_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.readConn().Query(ctx, query, webhookID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []*model.WebhookDelivery{}
+	for rows.Next() {
+		delivery := &model.WebhookDelivery{}
+		if err := scanDelivery(rows, delivery); err != nil {
+			return nil, fmt.Errorf("scan webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("iterate webhook deliveries: %w", rows.Err())
+	}
+
+	return deliveries, nil
+}
+
+// ClaimDue claims up to limit pending deliveries of active webhooks that are due
+// Claimed deliveries aren't due again until leaseUntil, so API instances sharing the
+// database don't send the same delivery twice.
+func (r *webhookRepository) ClaimDue(ctx context.Context, limit int, leaseUntil time.Time) ([]*model.QueuedDelivery, error) {
+	query := `
+		WITH due AS (
+			SELECT d.id
+			FROM webhook_deliveries d
+			INNER JOIN webhooks w ON w.id = d.webhook_id AND w.active = true
+			WHERE d.status = 'pending' AND d.next_attempt_at <= NOW()
+			ORDER BY d.next_attempt_at ASC
+			LIMIT $1
+			FOR UPDATE OF d SKIP LOCKED
+		)
+		UPDATE webhook_deliveries d
+		SET next_attempt_at = $2
+		FROM due, webhooks w
+		WHERE d.id = due.id AND w.id = d.webhook_id
+		RETURNING d.id, d.webhook_id, d.event, d.payload, d.status, d.attempts, d.response_status, d.error,
+		          d.next_attempt_at, d.last_attempt_at, d.created_at, w.url, w.secret
+	`
+
+	rows, err := r.db.conn().Query(ctx, query, limit, leaseUntil)
+	if err != nil {
+		return nil, fmt.Errorf("claim webhook deliveries: %w", err)
+	}
+
+	return collectQueued(rows)
+}
+
+// collectQueued scans claimed deliveries along with the URL and secret of their webhook
+func collectQueued(rows pgx.Rows) ([]*model.QueuedDelivery, error) {
+	defer rows.Close()
+
+	queued := []*model.QueuedDelivery{}
+	for rows.Next() {
+		delivery := &model.QueuedDelivery{}
+		if err := scanDelivery(rows, &delivery.WebhookDelivery, &delivery.URL, &delivery.Secret); err != nil {
+			return nil, fmt.Errorf("scan webhook delivery: %w", err)
+		}
+		queued = append(queued, delivery)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("iterate webhook deliveries: %w", rows.Err())
+	}
+
+	return queued, nil
+}
+
+// SaveAttempt saves the outcome of a delivery attempt
+func (r *webhookRepository) SaveAttempt(ctx context.Context, delivery *model.WebhookDelivery) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $1, attempts = $2, response_status = $3, error = $4,
+		    next_attempt_at = $5, last_attempt_at = $6
+		WHERE id = $7
+	`
+
+	_, err := r.db.conn().Exec(ctx, query,
+		delivery.Status,
+		delivery.Attempts,
+		delivery.ResponseStatus,
+		delivery.Error,
+		delivery.NextAttemptAt,
+		delivery.LastAttemptAt,
+		delivery.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("save webhook delivery attempt: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteDeliveriesBefore deletes finished deliveries created before a time
+func (r *webhookRepository) DeleteDeliveriesBefore(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM webhook_deliveries WHERE status <> 'pending' AND created_at < $1`
+
+	result, err := r.db.conn().Exec(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("delete old webhook deliveries: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// scanWebhook scans a row of webhookColumns
+func scanWebhook(row pgx.Row) (*model.Webhook, error) {
+	webhook := &model.Webhook{}
+	err := row.Scan(
+		&webhook.ID,
+		&webhook.UserID,
+		&webhook.URL,
+		&webhook.Events,
+		&webhook.Secret,
+		&webhook.Active,
+		&webhook.CreatedAt,
+		&webhook.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// scanDelivery scans a row of deliveryColumns, followed by any extra columns
+func scanDelivery(row pgx.Row, delivery *model.WebhookDelivery, extra ...any) error {
+	dest := []any{
+		&delivery.ID,
+		&delivery.WebhookID,
+		&delivery.Event,
+		&delivery.Payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.ResponseStatus,
+		&delivery.Error,
+		&delivery.NextAttemptAt,
+		&delivery.LastAttemptAt,
+		&delivery.CreatedAt,
+	}
+	return row.Scan(append(dest, extra...)...)
+}
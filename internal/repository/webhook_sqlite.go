@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+// sqliteWebhookRepository is the WebhookRepository of SQLite databases
+type sqliteWebhookRepository struct {
+	*webhookRepository
+}
+
+// ListSubscribed lists the active webhooks of a user subscribed to an event
+func (r *sqliteWebhookRepository) ListSubscribed(ctx context.Context, userID uuid.UUID, event model.EventType) ([]*model.Webhook, error) {
+	query := `
+		SELECT ` + webhookColumns + `
+		FROM webhooks
+		WHERE user_id = $1 AND active = true
+		  AND EXISTS (SELECT 1 FROM json_each(events) WHERE value = $2)
+	`
+
+	return r.list(ctx, r.db.conn(), query, "list subscribed webhooks", userID, string(event))
+}
+
+// CreateDeliveries queues one pending delivery of an event per webhook
+func (r *sqliteWebhookRepository) CreateDeliveries(ctx context.Context, webhookIDs []uuid.UUID, event model.EventType, payload []byte) error {
+	query := `
+		INSERT INTO webhook_deliveries (webhook_id, event, payload, next_attempt_at, created_at)
+		SELECT value, $2, $3, $4, $4
+		FROM json_each($1)
+	`
+
+	// The payload is stored as JSON text, a blob would be read as binary JSONB
+	_, err := r.db.conn().Exec(ctx, query, webhookIDs, string(event), string(payload), time.Now())
+	if err != nil {
+		return fmt.Errorf("create webhook deliveries: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimDue leases up to limit due deliveries of active webhooks until leaseUntil
+// SQLite allows one writer at a time, so there are no concurrent claims to skip.
+func (r *sqliteWebhookRepository) ClaimDue(ctx context.Context, limit int, leaseUntil time.Time) ([]*model.QueuedDelivery, error) {
+	query := `
+		UPDATE webhook_deliveries
+		SET next_attempt_at = $2
+		WHERE id IN (
+			SELECT d.id
+			FROM webhook_deliveries d
+			INNER JOIN webhooks w ON w.id = d.webhook_id AND w.active = true
+			WHERE d.status = 'pending' AND d.next_attempt_at <= NOW()
+			ORDER BY d.next_attempt_at ASC
+			LIMIT $1
+		)
+		RETURNING ` + deliveryColumns + `,
+		          (SELECT url FROM webhooks WHERE webhooks.id = webhook_id),
+		          (SELECT secret FROM webhooks WHERE webhooks.id = webhook_id)
+	`
+
+	rows, err := r.db.conn().Query(ctx, query, limit, leaseUntil)
+	if err != nil {
+		return nil, fmt.Errorf("claim webhook deliveries: %w", err)
+	}
+
+	return collectQueued(rows)
+}
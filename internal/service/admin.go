@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+	"github.com/momokii/go-cli-notes/internal/repository"
+)
+
+// AdminService handles instance administration
+type AdminService struct {
+	userRepo         repository.UserRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	auditRepo        repository.AuditRepository
+}
+
+// NewAdminService creates a new admin service
+func NewAdminService(
+	userRepo repository.UserRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+	auditRepo repository.AuditRepository,
+) *AdminService {
+	return &AdminService{
+		userRepo:         userRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		auditRepo:        auditRepo,
+	}
+}
+
+// IsAdmin reports whether a user is an active admin
+// The role is read from the database on every call, so a demotion takes effect at once.
+func (s *AdminService) IsAdmin(ctx context.Context, userID uuid.UUID) (bool, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return false, nil
+		}
+		return false, fmt.Errorf("find user: %w", err)
+	}
+
+	return user.IsActive && user.Role == model.RoleAdmin, nil
+}
+
+// PromoteAdmins gives the admin role to the users with these emails
+func (s *AdminService) PromoteAdmins(ctx context.Context, emails []string) (int64, error) {
+	cleaned := make([]string, 0, len(emails))
+	for _, email := range emails {
+		if email = strings.TrimSpace(email); email != "" {
+			cleaned = append(cleaned, email)
+		}
+	}
+	if len(cleaned) == 0 {
+		return 0, nil
+	}
+	return s.userRepo.PromoteAdmins(ctx, cleaned)
+}
+
+// ListUsers lists a page of all users with the total count
+func (s *AdminService) ListUsers(ctx context.Context, page, limit int) ([]*model.AdminUser, int64, error) {
+	return s.userRepo.List(ctx, page, limit)
+}
+
+// DeactivateUser blocks a user from signing in and ends their sessions
+// Access tokens already issued stay valid until they expire.
+func (s *AdminService) DeactivateUser(ctx context.Context, adminID, userID uuid.UUID, client model.ClientInfo) error {
+	// An instance must keep at least the admin doing the deactivating
+	if adminID == userID {
+		return fmt.Errorf("%w: you can't deactivate your own account", model.ErrValidation)
+	}
+
+	if err := s.userRepo.SetActive(ctx, userID, false); err != nil {
+		if err == repository.ErrNotFound {
+			return model.ErrNotFound
+		}
+		return fmt.Errorf("deactivate user: %w", err)
+	}
+
+	if err := s.refreshTokenRepo.RevokeAllForUser(ctx, userID); err != nil {
+		return fmt.Errorf("revoke sessions: %w", err)
+	}
+	s.audit(ctx, model.AuditUserDeactivated, adminID, userID, client)
+
+	return nil
+}
+
+// ActivateUser lets a deactivated user sign in again
+func (s *AdminService) ActivateUser(ctx context.Context, adminID, userID uuid.UUID, client model.ClientInfo) error {
+	if err := s.userRepo.SetActive(ctx, userID, true); err != nil {
+		if err == repository.ErrNotFound {
+			return model.ErrNotFound
+		}
+		return fmt.Errorf("activate user: %w", err)
+	}
+	s.audit(ctx, model.AuditUserActivated, adminID, userID, client)
+
+	return nil
+}
+
+// audit records an admin's action on a user as an event of that user
+func (s *AdminService) audit(ctx context.Context, event model.AuditEvent, adminID, userID uuid.UUID, client model.ClientInfo) {
+	recordAudit(ctx, s.auditRepo, &model.AuditEntry{
+		Event:    event,
+		UserID:   &userID,
+		Metadata: model.AuditMetadata{"admin_id": adminID},
+	}, client)
+}
+
+// GetStats gets statistics across all users
+func (s *AdminService) GetStats(ctx context.Context) (*model.AdminStats, error) {
+	return s.userRepo.GetAdminStats(ctx)
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+	"github.com/momokii/go-cli-notes/internal/repository"
+	"github.com/momokii/go-cli-notes/internal/storage"
+)
+
+// AttachmentService handles note attachment business logic
+type AttachmentService struct {
+	attachmentRepo repository.AttachmentRepository
+	noteRepo       repository.NoteRepository
+	store          storage.ObjectStore
+	maxSize        int64
+}
+
+// NewAttachmentService creates a new attachment service
+func NewAttachmentService(
+	attachmentRepo repository.AttachmentRepository,
+	noteRepo repository.NoteRepository,
+	store storage.ObjectStore,
+	maxSize int64,
+) *AttachmentService {
+	return &AttachmentService{
+		attachmentRepo: attachmentRepo,
+		noteRepo:       noteRepo,
+		store:          store,
+		maxSize:        maxSize,
+	}
+}
+
+// MaxSize returns the largest accepted upload in bytes
+func (s *AttachmentService) MaxSize() int64 {
+	return s.maxSize
+}
+
+// Upload stores a file and attaches it to a note
+func (s *AttachmentService) Upload(ctx context.Context, userID, noteID uuid.UUID, filename, contentType string, size int64, r io.Reader) (*model.Attachment, error) {
+	// Verify note ownership
+	if _, err := s.noteRepo.FindByID(ctx, userID, noteID); err != nil {
+		return nil, fmt.Errorf("note not found: %w", err)
+	}
+
+	filename = filepath.Base(strings.ReplaceAll(filename, "\\", "/"))
+	if filename == "" || filename == "." || filename == "/" {
+		return nil, fmt.Errorf("%w: filename is required", model.ErrValidation)
+	}
+	if len(filename) > 255 {
+		return nil, fmt.Errorf("%w: filename must be at most 255 characters", model.ErrValidation)
+	}
+	if size <= 0 {
+		return nil, fmt.Errorf("%w: file is empty", model.ErrValidation)
+	}
+	if size > s.maxSize {
+		return nil, fmt.Errorf("%w: file exceeds the %d byte limit", model.ErrValidation, s.maxSize)
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	attachment := &model.Attachment{
+		ID:          uuid.New(),
+		UserID:      userID,
+		NoteID:      noteID,
+		Filename:    filename,
+		ContentType: contentType,
+		SizeBytes:   size,
+	}
+	attachment.StorageKey = fmt.Sprintf("%s/%s/%s", userID, noteID, attachment.ID)
+
+	if err := s.store.Put(ctx, attachment.StorageKey, r, size, contentType); err != nil {
+		return nil, fmt.Errorf("store attachment: %w", err)
+	}
+
+	if err := s.attachmentRepo.Create(ctx, attachment); err != nil {
+		// Don't leave an orphaned object behind
+		_ = s.store.Delete(ctx, attachment.StorageKey)
+		return nil, fmt.Errorf("create attachment: %w", err)
+	}
+
+	return attachment, nil
+}
+
+// List lists the attachments of a note
+func (s *AttachmentService) List(ctx context.Context, userID, noteID uuid.UUID) ([]*model.Attachment, error) {
+	// Verify note ownership
+	if _, err := s.noteRepo.FindByID(ctx, userID, noteID); err != nil {
+		return nil, fmt.Errorf("note not found: %w", err)
+	}
+
+	attachments, err := s.attachmentRepo.ListByNote(ctx, userID, noteID)
+	if err != nil {
+		return nil, fmt.Errorf("list attachments: %w", err)
+	}
+
+	return attachments, nil
+}
+
+// Open returns an attachment and a reader for its contents; the caller must close the reader
+func (s *AttachmentService) Open(ctx context.Context, userID, noteID, attachmentID uuid.UUID) (*model.Attachment, io.ReadCloser, error) {
+	attachment, err := s.attachmentRepo.FindByID(ctx, userID, noteID, attachmentID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("find attachment: %w", err)
+	}
+
+	body, err := s.store.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open attachment: %w", err)
+	}
+
+	return attachment, body, nil
+}
+
+// Delete removes an attachment and its stored file
+func (s *AttachmentService) Delete(ctx context.Context, userID, noteID, attachmentID uuid.UUID) error {
+	attachment, err := s.attachmentRepo.FindByID(ctx, userID, noteID, attachmentID)
+	if err != nil {
+		return fmt.Errorf("find attachment: %w", err)
+	}
+
+	if err := s.attachmentRepo.Delete(ctx, userID, attachment.ID); err != nil {
+		return fmt.Errorf("delete attachment: %w", err)
+	}
+
+	// The record is gone, so a failed object delete only leaks storage
+	_ = s.store.Delete(ctx, attachment.StorageKey)
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+	"github.com/momokii/go-cli-notes/internal/repository"
+)
+
+// recordAudit writes a security event to the audit log with the client's address
+// Like activity logging it doesn't fail the request when the entry can't be stored.
+func recordAudit(ctx context.Context, repo repository.AuditRepository, entry *model.AuditEntry, client model.ClientInfo) {
+	entry.IPAddress = client.IPAddress
+	entry.UserAgent = client.UserAgent
+	if err := repo.Create(ctx, entry); err != nil {
+		fmt.Printf("warning: write audit log failed: %v\n", err)
+	}
+}
+
+// audit records an event of the user
+func (s *AuthService) audit(ctx context.Context, event model.AuditEvent, userID uuid.UUID, client model.ClientInfo, metadata model.AuditMetadata) {
+	recordAudit(ctx, s.auditRepo, &model.AuditEntry{
+		Event:    event,
+		UserID:   &userID,
+		Metadata: metadata,
+	}, client)
+}
+
+// auditLogin records a login attempt, err being the reason it failed
+// Attempts stopped before the credentials were checked, or only asking for the
+// TOTP code, aren't failures worth recording.
+func (s *AuthService) auditLogin(ctx context.Context, email string, user *model.User, err error, client model.ClientInfo, metadata model.AuditMetadata) {
+	entry := &model.AuditEntry{
+		Event:    model.AuditLogin,
+		Email:    email,
+		Metadata: metadata,
+	}
+	if user != nil {
+		entry.UserID = &user.ID
+		entry.Email = user.Email
+	}
+
+	if err != nil {
+		reason := loginFailureReason(user, err)
+		if reason == "" {
+			return
+		}
+		entry.Event = model.AuditLoginFailed
+		if entry.Metadata == nil {
+			entry.Metadata = model.AuditMetadata{}
+		}
+		entry.Metadata["reason"] = reason
+	}
+
+	recordAudit(ctx, s.auditRepo, entry, client)
+}
+
+// loginFailureReason names why a login failed in the audit log, empty when it isn't recorded
+func loginFailureReason(user *model.User, err error) string {
+	switch {
+	case errors.Is(err, model.ErrInvalidCredentials) && user == nil:
+		return "unknown_email"
+	case errors.Is(err, model.ErrInvalidCredentials):
+		return "wrong_password"
+	case errors.Is(err, model.ErrInvalidTOTP):
+		return "invalid_2fa_code"
+	case errors.Is(err, model.ErrUnauthorized):
+		return "account_inactive"
+	case errors.Is(err, model.ErrEmailNotVerified):
+		return "email_not_verified"
+	}
+	return ""
+}
+
+// ListAuditLog lists a page of the user's own security events
+func (s *AuthService) ListAuditLog(ctx context.Context, userID uuid.UUID, filter model.AuditFilter) ([]*model.AuditEntry, int64, error) {
+	filter.UserID = &userID
+	return s.auditRepo.List(ctx, filter)
+}
+
+// ListAuditLog lists a page of the security events of every user, or of filter.UserID
+func (s *AdminService) ListAuditLog(ctx context.Context, filter model.AuditFilter) ([]*model.AuditEntry, int64, error) {
+	return s.auditRepo.List(ctx, filter)
+}
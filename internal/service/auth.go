@@ -2,37 +2,75 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
 	"crypto/sha256"
+	"encoding/base64"
 	"encoding/hex"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 
+	"github.com/momokii/go-cli-notes/internal/mail"
 	"github.com/momokii/go-cli-notes/internal/model"
 	"github.com/momokii/go-cli-notes/internal/repository"
 	"github.com/momokii/go-cli-notes/internal/util"
 )
 
+// totpIssuer is shown as the account name prefix in authenticator apps
+const totpIssuer = "Knowledge Garden"
+
 // AuthService handles authentication business logic
 type AuthService struct {
 	userRepo       repository.UserRepository
 	refreshTokenRepo repository.RefreshTokenRepository
+	passwordResetRepo repository.PasswordResetRepository
+	verificationRepo repository.EmailVerificationRepository
+	deviceAuthRepo repository.DeviceAuthRepository
+	auditRepo      repository.AuditRepository
 	hasher         *util.PasswordHasher
 	jwtManager     *util.JWTManager
+	mailer         mail.Sender
+	resetExpiration time.Duration
+	verificationExpiration time.Duration
+	deviceExpiration time.Duration
+	requireVerification bool
+	publicURL      string // Base of the device login page; the request's address when empty
 }
 
 // NewAuthService creates a new authentication service
 func NewAuthService(
 	userRepo repository.UserRepository,
 	refreshTokenRepo repository.RefreshTokenRepository,
+	passwordResetRepo repository.PasswordResetRepository,
+	verificationRepo repository.EmailVerificationRepository,
+	deviceAuthRepo repository.DeviceAuthRepository,
+	auditRepo repository.AuditRepository,
 	hasher *util.PasswordHasher,
 	jwtManager *util.JWTManager,
+	mailer mail.Sender,
+	resetExpiration time.Duration,
+	verificationExpiration time.Duration,
+	deviceExpiration time.Duration,
+	requireVerification bool,
+	publicURL string,
 ) *AuthService {
 	return &AuthService{
 		userRepo:       userRepo,
 		refreshTokenRepo: refreshTokenRepo,
+		passwordResetRepo: passwordResetRepo,
+		verificationRepo: verificationRepo,
+		deviceAuthRepo: deviceAuthRepo,
+		auditRepo:      auditRepo,
 		hasher:         hasher,
 		jwtManager:     jwtManager,
+		mailer:         mailer,
+		resetExpiration: resetExpiration,
+		verificationExpiration: verificationExpiration,
+		deviceExpiration: deviceExpiration,
+		requireVerification: requireVerification,
+		publicURL:      strings.TrimSuffix(publicURL, "/"),
 	}
 }
 
@@ -40,7 +78,7 @@ func NewAuthService(
 func (s *AuthService) Register(ctx context.Context, req *model.RegisterRequest) (*model.User, error) {
 	// Validate request
 	if err := util.ValidateStruct(req); err != nil {
-		return nil, fmt.Errorf("%w: %s", model.ErrValidation, err)
+		return nil, fmt.Errorf("%w: %w", model.ErrValidation, err)
 	}
 
 	// Check if email already exists
@@ -78,6 +116,11 @@ func (s *AuthService) Register(ctx context.Context, req *model.RegisterRequest)
 		return nil, fmt.Errorf("create user: %w", err)
 	}
 
+	// The account exists either way; a lost email can be sent again with ResendVerification
+	if err := s.sendVerificationEmail(ctx, user); err != nil {
+		fmt.Printf("warning: send verification email failed: %v\n", err)
+	}
+
 	// Log activity
 	// TODO: Log registration activity
 
@@ -85,10 +128,35 @@ func (s *AuthService) Register(ctx context.Context, req *model.RegisterRequest)
 }
 
 // Login authenticates a user and returns tokens
-func (s *AuthService) Login(ctx context.Context, req *model.LoginRequest) (*model.AuthResponse, error) {
+func (s *AuthService) Login(ctx context.Context, req *model.LoginRequest, client model.ClientInfo) (*model.AuthResponse, error) {
+	user, err := s.authenticate(ctx, req)
+	if err != nil {
+		s.auditLogin(ctx, req.Email, user, err, client, nil)
+		return nil, err
+	}
+
+	// Generate and store tokens
+	resp, err := s.issueTokens(ctx, user, client)
+	if err != nil {
+		return nil, err
+	}
+	s.auditLogin(ctx, user.Email, user, nil, client, model.AuditMetadata{"method": "password"})
+
+	// Update last login
+	if err := s.userRepo.UpdateLastLogin(ctx, user.ID); err != nil {
+		// Log but don't fail the request
+		fmt.Printf("warning: update last login failed: %v\n", err)
+	}
+
+	return resp, nil
+}
+
+// authenticate checks the credentials of a login, and its TOTP code when 2FA is enabled
+// When the email belongs to a user, they are returned along with the error of a failed check.
+func (s *AuthService) authenticate(ctx context.Context, req *model.LoginRequest) (*model.User, error) {
 	// Validate request
 	if err := util.ValidateStruct(req); err != nil {
-		return nil, fmt.Errorf("%w: %s", model.ErrValidation, err)
+		return nil, fmt.Errorf("%w: %w", model.ErrValidation, err)
 	}
 
 	// Find user by email
@@ -106,7 +174,63 @@ func (s *AuthService) Login(ctx context.Context, req *model.LoginRequest) (*mode
 		return nil, fmt.Errorf("verify password: %w", err)
 	}
 	if !valid {
-		return nil, model.ErrInvalidCredentials
+		return user, model.ErrInvalidCredentials
+	}
+
+	// Check if user is active
+	if !user.IsActive {
+		return user, model.ErrUnauthorized
+	}
+
+	if s.requireVerification && !user.IsVerified {
+		return user, model.ErrEmailNotVerified
+	}
+
+	// Require a TOTP code once 2FA is enabled
+	if user.TOTPEnabled {
+		if req.TOTPCode == "" {
+			return user, model.ErrTOTPRequired
+		}
+		if user.TOTPSecret == nil || !util.ValidateTOTP(*user.TOTPSecret, req.TOTPCode, time.Now()) {
+			return user, model.ErrInvalidTOTP
+		}
+	}
+
+	return user, nil
+}
+
+// RefreshToken rotates a refresh token: the old token is revoked and a new pair is issued
+// Presenting an already revoked token means it was stolen or replayed, so every session
+// of the user is revoked.
+func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string, client model.ClientInfo) (*model.AuthResponse, error) {
+	// Validate refresh token
+	_, err := s.jwtManager.ValidateRefreshToken(refreshToken)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", model.ErrInvalidToken, err)
+	}
+
+	// The token must have been issued by us and not revoked
+	stored, err := s.refreshTokenRepo.FindByTokenHash(ctx, s.hashToken(refreshToken))
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil, fmt.Errorf("%w: unknown refresh token", model.ErrInvalidToken)
+		}
+		return nil, fmt.Errorf("find refresh token: %w", err)
+	}
+
+	if stored.IsRevoked {
+		_ = s.refreshTokenRepo.RevokeAllForUser(ctx, stored.UserID)
+		s.audit(ctx, model.AuditTokenReuse, stored.UserID, client, nil)
+		return nil, fmt.Errorf("%w: refresh token reuse detected", model.ErrInvalidToken)
+	}
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, fmt.Errorf("%w: refresh token expired", model.ErrInvalidToken)
+	}
+
+	// Get user
+	user, err := s.userRepo.FindByID(ctx, stored.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("find user: %w", err)
 	}
 
 	// Check if user is active
@@ -114,7 +238,89 @@ func (s *AuthService) Login(ctx context.Context, req *model.LoginRequest) (*mode
 		return nil, model.ErrUnauthorized
 	}
 
-	// Generate tokens
+	// Revoke the old token; losing this race means it was used twice concurrently
+	if err := s.refreshTokenRepo.Revoke(ctx, stored.ID); err != nil {
+		if err == repository.ErrNotFound {
+			_ = s.refreshTokenRepo.RevokeAllForUser(ctx, stored.UserID)
+			s.audit(ctx, model.AuditTokenReuse, stored.UserID, client, nil)
+			return nil, fmt.Errorf("%w: refresh token reuse detected", model.ErrInvalidToken)
+		}
+		return nil, fmt.Errorf("revoke refresh token: %w", err)
+	}
+
+	resp, err := s.issueTokens(ctx, user, client)
+	if err != nil {
+		return nil, err
+	}
+	s.audit(ctx, model.AuditTokenRefresh, user.ID, client, nil)
+
+	return resp, nil
+}
+
+// Logout revokes a refresh token of the user
+// An empty or unknown token is not an error; the client drops its tokens either way.
+func (s *AuthService) Logout(ctx context.Context, userID uuid.UUID, refreshToken string, client model.ClientInfo) error {
+	if refreshToken == "" {
+		return nil
+	}
+
+	stored, err := s.refreshTokenRepo.FindByTokenHash(ctx, s.hashToken(refreshToken))
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil
+		}
+		return fmt.Errorf("find refresh token: %w", err)
+	}
+
+	// Only revoke the caller's own tokens
+	if stored.UserID != userID {
+		return nil
+	}
+
+	if err := s.refreshTokenRepo.Revoke(ctx, stored.ID); err != nil && err != repository.ErrNotFound {
+		return fmt.Errorf("revoke refresh token: %w", err)
+	}
+	s.audit(ctx, model.AuditLogout, userID, client, nil)
+
+	return nil
+}
+
+// ListSessions lists the user's active sessions (unrevoked refresh tokens)
+func (s *AuthService) ListSessions(ctx context.Context, userID uuid.UUID) ([]*model.RefreshToken, error) {
+	sessions, err := s.refreshTokenRepo.ListActiveForUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession revokes one of the user's sessions
+func (s *AuthService) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID, client model.ClientInfo) error {
+	if err := s.refreshTokenRepo.RevokeForUser(ctx, userID, sessionID); err != nil {
+		if err == repository.ErrNotFound {
+			return model.ErrNotFound
+		}
+		return fmt.Errorf("revoke session: %w", err)
+	}
+	s.audit(ctx, model.AuditSessionRevoked, userID, client, model.AuditMetadata{"session_id": sessionID})
+
+	return nil
+}
+
+// RevokeAllSessions revokes every refresh token of the user, signing out all devices
+// Access tokens already handed out stay valid until they expire.
+func (s *AuthService) RevokeAllSessions(ctx context.Context, userID uuid.UUID, client model.ClientInfo) error {
+	if err := s.refreshTokenRepo.RevokeAllForUser(ctx, userID); err != nil {
+		return fmt.Errorf("revoke sessions: %w", err)
+	}
+	s.audit(ctx, model.AuditSessionsRevoked, userID, client, nil)
+
+	return nil
+}
+
+// issueTokens generates an access/refresh token pair and stores the refresh token hash
+func (s *AuthService) issueTokens(ctx context.Context, user *model.User, client model.ClientInfo) (*model.AuthResponse, error) {
 	accessToken, err := s.jwtManager.GenerateAccessToken(user.ID.String(), user.Email)
 	if err != nil {
 		return nil, fmt.Errorf("generate access token: %w", err)
@@ -125,18 +331,16 @@ func (s *AuthService) Login(ctx context.Context, req *model.LoginRequest) (*mode
 		return nil, fmt.Errorf("generate refresh token: %w", err)
 	}
 
-	// Store refresh token hash
-	_ = s.hashToken(refreshToken)
-	// TODO: Store refresh token in database
-
-	// Update last login
-	if err := s.userRepo.UpdateLastLogin(ctx, user.ID); err != nil {
-		// Log but don't fail the request
-		fmt.Printf("warning: update last login failed: %v\n", err)
+	stored := &model.RefreshToken{
+		UserID:    user.ID,
+		TokenHash: s.hashToken(refreshToken),
+		ExpiresAt: time.Now().Add(s.jwtManager.GetRefreshExpiration()),
+		UserAgent: client.UserAgent,
+		IPAddress: client.IPAddress,
+	}
+	if err := s.refreshTokenRepo.Create(ctx, stored); err != nil {
+		return nil, fmt.Errorf("store refresh token: %w", err)
 	}
-
-	// Log activity
-	// TODO: Log login activity
 
 	return &model.AuthResponse{
 		AccessToken:  accessToken,
@@ -146,61 +350,335 @@ func (s *AuthService) Login(ctx context.Context, req *model.LoginRequest) (*mode
 	}, nil
 }
 
-// RefreshToken refreshes an access token using a refresh token
-func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*model.AuthResponse, error) {
-	// Validate refresh token
-	claims, err := s.jwtManager.ValidateRefreshToken(refreshToken)
+// VerifyEmail confirms the user's email address with a token from the verification email
+func (s *AuthService) VerifyEmail(ctx context.Context, req *model.VerifyEmailRequest) error {
+	if err := util.ValidateStruct(req); err != nil {
+		return fmt.Errorf("%w: %w", model.ErrValidation, err)
+	}
+
+	token, err := s.verificationRepo.FindValidByTokenHash(ctx, s.hashToken(req.Token))
 	if err != nil {
-		return nil, fmt.Errorf("%w: %s", model.ErrInvalidToken, err)
+		if err == repository.ErrNotFound {
+			return model.ErrInvalidVerificationToken
+		}
+		return fmt.Errorf("find verification token: %w", err)
+	}
+
+	if err := s.userRepo.MarkVerified(ctx, token.UserID); err != nil {
+		return fmt.Errorf("mark verified: %w", err)
 	}
 
-	// Check if refresh token is revoked
-	_ = s.hashToken(refreshToken)
-	// TODO: Check if token is revoked in database
+	_ = s.verificationRepo.DeleteForUser(ctx, token.UserID)
 
-	// Get user
-	user, err := s.userRepo.FindByID(ctx, uuid.MustParse(claims.UserID))
+	return nil
+}
+
+// ResendVerification sends a new verification email
+// Unknown or already verified emails are not an error, so the endpoint can't be used to probe for accounts.
+func (s *AuthService) ResendVerification(ctx context.Context, req *model.ResendVerificationRequest) error {
+	if err := util.ValidateStruct(req); err != nil {
+		return fmt.Errorf("%w: %w", model.ErrValidation, err)
+	}
+
+	user, err := s.userRepo.FindByEmail(ctx, req.Email)
 	if err != nil {
-		return nil, fmt.Errorf("find user: %w", err)
+		if err == repository.ErrNotFound {
+			return nil
+		}
+		return fmt.Errorf("find user: %w", err)
+	}
+	if user.IsVerified || !user.IsActive {
+		return nil
 	}
 
-	// Check if user is active
+	return s.sendVerificationEmail(ctx, user)
+}
+
+// sendVerificationEmail replaces the user's verification token and emails the new one
+func (s *AuthService) sendVerificationEmail(ctx context.Context, user *model.User) error {
+	token, err := generateEmailToken()
+	if err != nil {
+		return err
+	}
+
+	// Drop any earlier tokens so only the newest email works
+	_ = s.verificationRepo.DeleteForUser(ctx, user.ID)
+
+	verification := &model.EmailVerificationToken{
+		UserID:    user.ID,
+		TokenHash: s.hashToken(token),
+		ExpiresAt: time.Now().Add(s.verificationExpiration),
+	}
+	if err := s.verificationRepo.Create(ctx, verification); err != nil {
+		return fmt.Errorf("store verification token: %w", err)
+	}
+
+	msg := &mail.Message{
+		To:      user.Email,
+		Subject: "Verify your Knowledge Garden email",
+		Body: fmt.Sprintf(`Hi %s,
+
+Welcome to Knowledge Garden! To confirm this is your email address, run:
+
+    kg-cli verify-email --token %s
+
+The token expires in %s.
+If you didn't create an account, you can ignore this email.
+`, user.Username, token, s.verificationExpiration),
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		return fmt.Errorf("send verification email: %w", err)
+	}
+
+	return nil
+}
+
+// ForgotPassword emails a single-use password reset token to the account
+// Unknown emails are not an error, so the endpoint can't be used to probe for accounts.
+func (s *AuthService) ForgotPassword(ctx context.Context, req *model.ForgotPasswordRequest) error {
+	if err := util.ValidateStruct(req); err != nil {
+		return fmt.Errorf("%w: %w", model.ErrValidation, err)
+	}
+
+	user, err := s.userRepo.FindByEmail(ctx, req.Email)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil
+		}
+		return fmt.Errorf("find user: %w", err)
+	}
 	if !user.IsActive {
-		return nil, model.ErrUnauthorized
+		return nil
 	}
 
-	// Generate new tokens
-	newAccessToken, err := s.jwtManager.GenerateAccessToken(user.ID.String(), user.Email)
+	// Random token for the user, only its hash is stored
+	token, err := generateEmailToken()
 	if err != nil {
-		return nil, fmt.Errorf("generate access token: %w", err)
+		return err
+	}
+
+	// Drop any earlier tokens so only the newest email works
+	_ = s.passwordResetRepo.DeleteForUser(ctx, user.ID)
+
+	resetToken := &model.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: s.hashToken(token),
+		ExpiresAt: time.Now().Add(s.resetExpiration),
+	}
+	if err := s.passwordResetRepo.Create(ctx, resetToken); err != nil {
+		return fmt.Errorf("store reset token: %w", err)
+	}
+
+	msg := &mail.Message{
+		To:      user.Email,
+		Subject: "Reset your Knowledge Garden password",
+		Body: fmt.Sprintf(`Hi %s,
+
+Someone asked to reset the password of your Knowledge Garden account.
+To choose a new password, run:
+
+    kg-cli reset-password --token %s
+
+The token expires in %s and can only be used once.
+If you didn't ask for this, you can ignore this email.
+`, user.Username, token, s.resetExpiration),
 	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		return fmt.Errorf("send reset email: %w", err)
+	}
+
+	return nil
+}
 
-	newRefreshToken, err := s.jwtManager.GenerateRefreshToken(user.ID.String(), user.Email)
+// ResetPassword sets a new password using a token from ForgotPassword
+// All refresh tokens of the user are revoked, signing out every other device.
+func (s *AuthService) ResetPassword(ctx context.Context, req *model.ResetPasswordRequest, client model.ClientInfo) error {
+	if err := util.ValidateStruct(req); err != nil {
+		return fmt.Errorf("%w: %w", model.ErrValidation, err)
+	}
+
+	resetToken, err := s.passwordResetRepo.FindValidByTokenHash(ctx, s.hashToken(req.Token))
 	if err != nil {
-		return nil, fmt.Errorf("generate refresh token: %w", err)
+		if err == repository.ErrNotFound {
+			return model.ErrInvalidResetToken
+		}
+		return fmt.Errorf("find reset token: %w", err)
+	}
+
+	// Claim the token first so it can't be redeemed twice
+	if err := s.passwordResetRepo.MarkUsed(ctx, resetToken.ID); err != nil {
+		if err == repository.ErrNotFound {
+			return model.ErrInvalidResetToken
+		}
+		return fmt.Errorf("mark reset token used: %w", err)
 	}
 
-	// TODO: Revoke old refresh token and store new one
+	hash, err := s.hasher.Hash(req.Password)
+	if err != nil {
+		return fmt.Errorf("hash password: %w", err)
+	}
 
-	return &model.AuthResponse{
-		AccessToken:  newAccessToken,
-		RefreshToken: newRefreshToken,
-		ExpiresIn:    s.jwtManager.GetAccessExpiration(),
-		User:         user,
+	if err := s.userRepo.UpdatePassword(ctx, resetToken.UserID, hash); err != nil {
+		return fmt.Errorf("update password: %w", err)
+	}
+
+	_ = s.passwordResetRepo.DeleteForUser(ctx, resetToken.UserID)
+	_ = s.refreshTokenRepo.RevokeAllForUser(ctx, resetToken.UserID)
+
+	// Redeeming an emailed token proves the address works
+	_ = s.userRepo.MarkVerified(ctx, resetToken.UserID)
+
+	s.audit(ctx, model.AuditPasswordReset, resetToken.UserID, client, nil)
+
+	return nil
+}
+
+// ChangePassword replaces the password of a signed-in user after checking the current one
+// Every session is revoked and a fresh token pair is issued, so only the calling device stays signed in.
+func (s *AuthService) ChangePassword(ctx context.Context, userID uuid.UUID, req *model.ChangePasswordRequest, client model.ClientInfo) (*model.AuthResponse, error) {
+	if err := util.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("%w: %w", model.ErrValidation, err)
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("find user: %w", err)
+	}
+
+	if err := s.checkPassword(user, req.CurrentPassword); err != nil {
+		return nil, err
+	}
+
+	hash, err := s.hasher.Hash(req.NewPassword)
+	if err != nil {
+		return nil, fmt.Errorf("hash password: %w", err)
+	}
+
+	if err := s.userRepo.UpdatePassword(ctx, userID, hash); err != nil {
+		return nil, fmt.Errorf("update password: %w", err)
+	}
+
+	if err := s.refreshTokenRepo.RevokeAllForUser(ctx, userID); err != nil {
+		return nil, fmt.Errorf("revoke sessions: %w", err)
+	}
+	_ = s.passwordResetRepo.DeleteForUser(ctx, userID)
+	s.audit(ctx, model.AuditPasswordChanged, userID, client, nil)
+
+	return s.issueTokens(ctx, user, client)
+}
+
+// DeleteAccount soft-deletes the user and their notes after checking the password
+// The account is deactivated and every session revoked; data is kept until purged.
+func (s *AuthService) DeleteAccount(ctx context.Context, userID uuid.UUID, req *model.DeleteAccountRequest, client model.ClientInfo) error {
+	if err := util.ValidateStruct(req); err != nil {
+		return fmt.Errorf("%w: %w", model.ErrValidation, err)
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("find user: %w", err)
+	}
+
+	if err := s.checkPassword(user, req.Password); err != nil {
+		return err
+	}
+
+	if err := s.userRepo.SoftDelete(ctx, userID); err != nil {
+		if err == repository.ErrNotFound {
+			return model.ErrNotFound
+		}
+		return fmt.Errorf("delete account: %w", err)
+	}
+
+	if err := s.refreshTokenRepo.RevokeAllForUser(ctx, userID); err != nil {
+		return fmt.Errorf("revoke sessions: %w", err)
+	}
+	_ = s.passwordResetRepo.DeleteForUser(ctx, userID)
+	_ = s.verificationRepo.DeleteForUser(ctx, userID)
+	s.audit(ctx, model.AuditAccountDeleted, userID, client, nil)
+
+	return nil
+}
+
+// checkPassword verifies a password re-entered by a signed-in user
+func (s *AuthService) checkPassword(user *model.User, password string) error {
+	valid, err := s.hasher.Verify(password, user.PasswordHash)
+	if err != nil {
+		return fmt.Errorf("verify password: %w", err)
+	}
+	if !valid {
+		return model.ErrWrongPassword
+	}
+
+	return nil
+}
+
+// SetupTOTP generates a new TOTP secret for the user
+// 2FA is not enforced until the secret is confirmed with VerifyTOTP.
+func (s *AuthService) SetupTOTP(ctx context.Context, userID uuid.UUID) (*model.TOTPSetupResponse, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("find user: %w", err)
+	}
+
+	if user.TOTPEnabled {
+		return nil, model.ErrTOTPEnabled
+	}
+
+	secret, err := util.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.SetTOTPSecret(ctx, userID, secret); err != nil {
+		return nil, fmt.Errorf("store totp secret: %w", err)
+	}
+
+	return &model.TOTPSetupResponse{
+		Secret: secret,
+		URI:    util.TOTPURI(totpIssuer, user.Email, secret),
 	}, nil
 }
 
-// Logout revokes a refresh token
-func (s *AuthService) Logout(ctx context.Context, userID uuid.UUID, refreshToken string) error {
-	_ = s.hashToken(refreshToken)
-	// TODO: Revoke refresh token in database
+// VerifyTOTP checks a code against the pending secret and enables 2FA
+func (s *AuthService) VerifyTOTP(ctx context.Context, userID uuid.UUID, req *model.TOTPVerifyRequest, client model.ClientInfo) error {
+	if err := util.ValidateStruct(req); err != nil {
+		return fmt.Errorf("%w: %w", model.ErrValidation, err)
+	}
 
-	// Log activity
-	// TODO: Log logout activity
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("find user: %w", err)
+	}
+
+	if user.TOTPEnabled {
+		return model.ErrTOTPEnabled
+	}
+	if user.TOTPSecret == nil {
+		return model.ErrTOTPNotSetup
+	}
+
+	if !util.ValidateTOTP(*user.TOTPSecret, req.Code, time.Now()) {
+		return model.ErrInvalidTOTP
+	}
+
+	if err := s.userRepo.EnableTOTP(ctx, userID); err != nil {
+		return fmt.Errorf("enable totp: %w", err)
+	}
+	s.audit(ctx, model.AuditTOTPEnabled, userID, client, nil)
 
 	return nil
 }
 
+// generateEmailToken returns a random URL-safe token to send by email
+func generateEmailToken() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("generate token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
 // hashToken creates a SHA256 hash of a token for storage
 func (s *AuthService) hashToken(token string) string {
 	hash := sha256.Sum256([]byte(token))
@@ -0,0 +1,431 @@
+package service
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+	"github.com/momokii/go-cli-notes/internal/repository"
+	"github.com/momokii/go-cli-notes/internal/storage"
+)
+
+const (
+	backupPrefix     = "kg-backup-"
+	backupSuffix     = ".tar.gz"
+	backupTimeLayout = "20060102T150405Z"
+	backupManifest   = "manifest.json"
+	// restoreBatchSize is how many rows are inserted per statement on restore
+	restoreBatchSize = 500
+)
+
+// BackupService writes and restores backups of all user data
+// A backup is a gzipped tar of manifest.json and one JSON Lines file per table.
+type BackupService struct {
+	db    *repository.DB
+	store storage.ObjectStore
+	keep  int
+}
+
+// NewBackupService creates a new backup service storing archives in store
+// After each scheduled backup only the newest keep archives are kept, 0 keeps all.
+func NewBackupService(db *repository.DB, store storage.ObjectStore, keep int) *BackupService {
+	return &BackupService{
+		db:    db,
+		store: store,
+		keep:  keep,
+	}
+}
+
+// Run writes a backup every day at hour:minute UTC until ctx is cancelled
+func (s *BackupService) Run(ctx context.Context, hour, minute int) {
+	for {
+		now := time.Now().UTC()
+		next := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, time.UTC)
+		if !next.After(now) {
+			next = next.AddDate(0, 0, 1)
+		}
+
+		timer := time.NewTimer(next.Sub(now))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		result, err := s.Create(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				slog.Error("Scheduled backup failed", "error", err)
+			}
+			continue
+		}
+		slog.Info("Backup written", "name", result.Name, "bytes", result.Size)
+
+		if removed, err := s.Prune(ctx); err != nil {
+			slog.Warn("Failed to remove old backups", "error", err)
+		} else if removed > 0 {
+			slog.Info("Removed old backups", "count", removed)
+		}
+	}
+}
+
+// Create writes a backup of every user-data table, read from one consistent snapshot
+func (s *BackupService) Create(ctx context.Context) (*model.BackupResult, error) {
+	// Tables are spooled to disk first, tar needs each file's size before its contents
+	dir, err := os.MkdirTemp("", "kg-backup-")
+	if err != nil {
+		return nil, fmt.Errorf("create temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	createdAt := time.Now().UTC()
+	manifest := model.BackupManifest{
+		Format:    model.BackupFormat,
+		CreatedAt: createdAt,
+		Tables:    make(map[string]int64),
+	}
+
+	err = s.db.InTx(ctx, func(tx *repository.DB) error {
+		repo := repository.NewBackupRepository(tx)
+		if err := repo.Snapshot(ctx); err != nil {
+			return err
+		}
+		for _, table := range repository.BackupTables() {
+			count, err := spoolTable(ctx, repo, table, filepath.Join(dir, table+".jsonl"))
+			if err != nil {
+				return err
+			}
+			manifest.Tables[table] = count
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	archive, err := os.CreateTemp(dir, "archive-*")
+	if err != nil {
+		return nil, fmt.Errorf("create archive: %w", err)
+	}
+	defer archive.Close()
+
+	if err := writeBackupArchive(archive, dir, &manifest); err != nil {
+		return nil, err
+	}
+
+	size, err := archive.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, fmt.Errorf("size archive: %w", err)
+	}
+	if _, err := archive.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("rewind archive: %w", err)
+	}
+
+	name := backupPrefix + createdAt.Format(backupTimeLayout) + backupSuffix
+	if err := s.store.Put(ctx, name, archive, size, "application/gzip"); err != nil {
+		return nil, fmt.Errorf("store backup: %w", err)
+	}
+
+	return &model.BackupResult{
+		Backup: model.Backup{Name: name, Size: size, CreatedAt: createdAt},
+		Tables: manifest.Tables,
+	}, nil
+}
+
+// spoolTable writes the rows of a table to path as JSON Lines
+func spoolTable(ctx context.Context, repo repository.BackupRepository, table, path string) (int64, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, fmt.Errorf("create %s file: %w", table, err)
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	count, err := repo.Export(ctx, table, func(row []byte) error {
+		if _, err := w.Write(row); err != nil {
+			return err
+		}
+		return w.WriteByte('\n')
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err := w.Flush(); err != nil {
+		return 0, fmt.Errorf("write %s file: %w", table, err)
+	}
+
+	return count, nil
+}
+
+// writeBackupArchive writes the manifest and then the spooled tables, in restore order
+func writeBackupArchive(w io.Writer, dir string, manifest *model.BackupManifest) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode manifest: %w", err)
+	}
+	header := &tar.Header{Name: backupManifest, Mode: 0o600, Size: int64(len(data)), ModTime: manifest.CreatedAt}
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("write manifest: %w", err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("write manifest: %w", err)
+	}
+
+	for _, table := range repository.BackupTables() {
+		if err := addArchiveFile(tw, filepath.Join(dir, table+".jsonl"), table+".jsonl", manifest.CreatedAt); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("write archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("write archive: %w", err)
+	}
+	return nil
+}
+
+// addArchiveFile copies a file into the archive under name
+func addArchiveFile(tw *tar.Writer, path, name string, modTime time.Time) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", name, err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("stat %s: %w", name, err)
+	}
+	header := &tar.Header{Name: name, Mode: 0o600, Size: info.Size(), ModTime: modTime}
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	if _, err := io.Copy(tw, f); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	return nil
+}
+
+// List returns the backups in the store, newest first
+func (s *BackupService) List(ctx context.Context) ([]*model.Backup, error) {
+	objects, err := s.store.List(ctx, backupPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("list backups: %w", err)
+	}
+
+	backups := make([]*model.Backup, 0, len(objects))
+	for _, object := range objects {
+		if !isBackupName(object.Key) {
+			continue
+		}
+		createdAt, err := time.Parse(backupTimeLayout, strings.TrimSuffix(strings.TrimPrefix(object.Key, backupPrefix), backupSuffix))
+		if err != nil {
+			createdAt = object.LastModified
+		}
+		backups = append(backups, &model.Backup{Name: object.Key, Size: object.Size, CreatedAt: createdAt})
+	}
+
+	sort.Slice(backups, func(i, j int) bool { return backups[i].CreatedAt.After(backups[j].CreatedAt) })
+	return backups, nil
+}
+
+// Prune deletes all but the newest keep backups and returns how many were deleted
+func (s *BackupService) Prune(ctx context.Context) (int, error) {
+	if s.keep <= 0 {
+		return 0, nil
+	}
+
+	backups, err := s.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if len(backups) <= s.keep {
+		return 0, nil
+	}
+
+	removed := 0
+	for _, backup := range backups[s.keep:] {
+		if err := s.store.Delete(ctx, backup.Name); err != nil {
+			return removed, fmt.Errorf("delete backup %s: %w", backup.Name, err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// Restore adds the rows of a backup that are missing from the database, in one transaction
+// Rows that still exist are left as they are, so restoring never overwrites newer edits.
+// Columns missing from an older backup get their default, columns since dropped are ignored.
+func (s *BackupService) Restore(ctx context.Context, name string) (*model.RestoreResult, error) {
+	if !isBackupName(name) {
+		return nil, fmt.Errorf("%w: invalid backup name %q", model.ErrValidation, name)
+	}
+
+	r, err := s.store.Get(ctx, name)
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		return nil, fmt.Errorf("backup %s: %w", name, model.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open backup: %w", err)
+	}
+	defer r.Close()
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s is not a backup archive: %w", model.ErrValidation, name, err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+
+	if err := readBackupManifest(tr); err != nil {
+		return nil, err
+	}
+
+	result := &model.RestoreResult{
+		Name:     name,
+		Restored: make(map[string]int64),
+		Skipped:  make(map[string]int64),
+	}
+	known := make(map[string]bool)
+	for _, table := range repository.BackupTables() {
+		known[table] = true
+	}
+
+	err = s.db.InTx(ctx, func(tx *repository.DB) error {
+		repo := repository.NewBackupRepository(tx)
+		for {
+			header, err := tr.Next()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("read backup: %w", err)
+			}
+
+			table := strings.TrimSuffix(header.Name, ".jsonl")
+			if !known[table] || table == header.Name {
+				continue
+			}
+			restored, total, err := restoreTable(ctx, repo, table, tr)
+			if err != nil {
+				return err
+			}
+			result.Restored[table] = restored
+			result.Skipped[table] = total - restored
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// readBackupManifest reads the manifest, the first file of an archive, and checks its format
+func readBackupManifest(tr *tar.Reader) error {
+	header, err := tr.Next()
+	if err != nil || header.Name != backupManifest {
+		return fmt.Errorf("%w: backup archive has no manifest", model.ErrValidation)
+	}
+
+	var manifest model.BackupManifest
+	if err := json.NewDecoder(tr).Decode(&manifest); err != nil {
+		return fmt.Errorf("%w: invalid backup manifest: %w", model.ErrValidation, err)
+	}
+	if manifest.Format < 1 || manifest.Format > model.BackupFormat {
+		return fmt.Errorf("%w: backup format %d is not supported, upgrade the server", model.ErrValidation, manifest.Format)
+	}
+	return nil
+}
+
+// restoreTable imports the JSON Lines rows of one table
+// It returns the rows inserted and the rows read.
+func restoreTable(ctx context.Context, repo repository.BackupRepository, table string, r io.Reader) (int64, int64, error) {
+	current, err := repo.Columns(ctx, table)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	dec := json.NewDecoder(r)
+	var columns []string
+	var batch []json.RawMessage
+	var restored, total int64
+	flush := func() error {
+		n, err := repo.Import(ctx, table, columns, batch)
+		if err != nil {
+			return err
+		}
+		restored += n
+		batch = batch[:0]
+		return nil
+	}
+
+	for {
+		var row json.RawMessage
+		if err := dec.Decode(&row); err == io.EOF {
+			break
+		} else if err != nil {
+			return 0, 0, fmt.Errorf("%w: invalid %s row: %w", model.ErrValidation, table, err)
+		}
+		total++
+
+		// All rows of a table have the same keys, the first one decides the columns
+		if columns == nil {
+			if columns, err = backupColumns(row, current); err != nil {
+				return 0, 0, fmt.Errorf("%w: invalid %s row: %w", model.ErrValidation, table, err)
+			}
+		}
+
+		batch = append(batch, row)
+		if len(batch) == restoreBatchSize {
+			if err := flush(); err != nil {
+				return 0, 0, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return 0, 0, err
+	}
+
+	return restored, total, nil
+}
+
+// backupColumns returns the columns of the current schema that the row has a value for
+func backupColumns(row json.RawMessage, current []string) ([]string, error) {
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(row, &values); err != nil {
+		return nil, err
+	}
+
+	columns := []string{}
+	for _, column := range current {
+		if _, ok := values[column]; ok {
+			columns = append(columns, column)
+		}
+	}
+	return columns, nil
+}
+
+// isBackupName reports whether name is the name of a backup archive
+func isBackupName(name string) bool {
+	return strings.HasPrefix(name, backupPrefix) && strings.HasSuffix(name, backupSuffix) &&
+		!strings.ContainsAny(name, `/\`)
+}
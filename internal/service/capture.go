@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+	"github.com/momokii/go-cli-notes/internal/repository"
+	"github.com/momokii/go-cli-notes/internal/util"
+)
+
+// Capture appends req.Text as a list item to the Inbox note or to today's daily note
+// The target defaults to the user's capture_target setting; the Inbox note is
+// created on the first capture. The bool is true when the note was created.
+func (s *NoteService) Capture(ctx context.Context, userID uuid.UUID, req *model.CaptureRequest) (*model.Note, bool, error) {
+	if err := util.ValidateStruct(req); err != nil {
+		return nil, false, fmt.Errorf("%w: %w", model.ErrValidation, err)
+	}
+	text := strings.TrimSpace(req.Text)
+	if text == "" {
+		return nil, false, fmt.Errorf("%w: nothing to capture", model.ErrValidation)
+	}
+	item := captureItem(text)
+
+	settings, err := s.settingsRepo.Get(ctx, userID)
+	if err != nil {
+		return nil, false, fmt.Errorf("get settings: %w", err)
+	}
+	target := req.Target
+	if target == "" {
+		target = settings.CaptureTarget
+	}
+
+	var note *model.Note
+	isCreated := false
+	if target == model.CaptureTargetDaily {
+		today := time.Now().In(settings.Location()).Format(util.DailyDateLayout)
+		note, isCreated, err = s.GetOrCreateDailyNote(ctx, userID, today)
+		if err != nil {
+			return nil, false, err
+		}
+	} else {
+		note, err = s.noteRepo.FindByTitle(ctx, userID, model.InboxTitle)
+		if errors.Is(err, repository.ErrNotFound) {
+			note, err = s.Create(ctx, userID, &model.CreateNoteRequest{
+				Title:   model.InboxTitle,
+				Content: "# " + model.InboxTitle + "\n\n" + item,
+			})
+			if err != nil {
+				return nil, false, fmt.Errorf("create inbox: %w", err)
+			}
+			return note, true, nil
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("find inbox: %w", err)
+		}
+	}
+
+	// Encrypted content can only be changed by the client holding the passphrase
+	if note.Encrypted {
+		return nil, false, fmt.Errorf("%w: %q is encrypted, captures can't be added to it", model.ErrValidation, note.Title)
+	}
+
+	note, err = s.Append(ctx, userID, note.ID, &model.AppendNoteRequest{Content: item})
+	if err != nil {
+		return nil, false, fmt.Errorf("append capture: %w", err)
+	}
+
+	return note, isCreated, nil
+}
+
+// GetInbox returns how many captured items in the Inbox note are still waiting
+// Items are top-level list items; checked-off tasks don't count.
+func (s *NoteService) GetInbox(ctx context.Context, userID uuid.UUID) (*model.InboxStatus, error) {
+	note, err := s.noteRepo.FindByTitle(ctx, userID, model.InboxTitle)
+	if errors.Is(err, repository.ErrNotFound) {
+		return &model.InboxStatus{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find inbox: %w", err)
+	}
+
+	status := &model.InboxStatus{NoteID: &note.ID}
+	if note.Encrypted {
+		return status, nil
+	}
+	for _, line := range strings.Split(note.Content, "\n") {
+		if !strings.HasPrefix(line, "- ") && !strings.HasPrefix(line, "* ") {
+			continue
+		}
+		if checked := strings.ToLower(line[2:]); strings.HasPrefix(checked, "[x]") {
+			continue
+		}
+		status.Items++
+	}
+
+	return status, nil
+}
+
+// captureItem formats captured text as one list item, indenting its further lines
+func captureItem(text string) string {
+	lines := strings.Split(text, "\n")
+	for i := 1; i < len(lines); i++ {
+		if line := strings.TrimRight(lines[i], " \t"); line != "" {
+			lines[i] = "  " + line
+		} else {
+			lines[i] = ""
+		}
+	}
+	return "- " + strings.Join(lines, "\n") + "\n"
+}
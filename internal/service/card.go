@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+	"github.com/momokii/go-cli-notes/internal/util"
+)
+
+// processCards syncs the Q:: / A:: flashcards of a note with its content
+func (s *NoteService) processCards(ctx context.Context, userID uuid.UUID, note *model.Note) error {
+	// Cards can't be read from ciphertext, and those kept from before encryption would be readable
+	var cards []*model.Card
+	if !note.Encrypted {
+		for _, card := range util.ExtractCards(note.Content) {
+			cards = append(cards, &model.Card{
+				LineNumber: card.Line,
+				Question:   card.Question,
+				Answer:     card.Answer,
+			})
+		}
+	}
+
+	return s.cardRepo.SyncNote(ctx, userID, note.ID, cards)
+}
+
+// ListDueCards lists the cards due for review today in the user's timezone, new cards last
+func (s *NoteService) ListDueCards(ctx context.Context, userID uuid.UUID, limit int) (*model.DueCards, error) {
+	if limit <= 0 || limit > model.MaxDueCardsLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", model.ErrValidation, model.MaxDueCardsLimit)
+	}
+
+	today, err := s.reviewDay(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	cards, total, err := s.cardRepo.ListDue(ctx, userID, today.Format(util.DailyDateLayout), limit)
+	if err != nil {
+		return nil, fmt.Errorf("list due cards: %w", err)
+	}
+
+	return &model.DueCards{
+		Cards: cards,
+		Count: len(cards),
+		Total: total,
+		Today: today.Format(util.DailyDateLayout),
+	}, nil
+}
+
+// ReviewCard grades a review of a card and schedules its next review with SM-2
+func (s *NoteService) ReviewCard(ctx context.Context, userID, cardID uuid.UUID, req *model.ReviewCardRequest) (*model.Card, error) {
+	if err := util.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("%w: %w", model.ErrValidation, err)
+	}
+
+	card, err := s.cardRepo.Find(ctx, userID, cardID)
+	if err != nil {
+		return nil, fmt.Errorf("find card: %w", err)
+	}
+
+	today, err := s.reviewDay(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduleCard(card, *req.Grade, today)
+	now := time.Now()
+	card.LastReviewedAt = &now
+
+	if err := s.cardRepo.UpdateSchedule(ctx, card); err != nil {
+		return nil, fmt.Errorf("update card: %w", err)
+	}
+
+	return card, nil
+}
+
+// reviewDay returns the start of today in the user's timezone, the day reviews are due on
+func (s *NoteService) reviewDay(ctx context.Context, userID uuid.UUID) (time.Time, error) {
+	settings, err := s.settingsRepo.Get(ctx, userID)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("get settings: %w", err)
+	}
+
+	now := time.Now().In(settings.Location())
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()), nil
+}
+
+// scheduleCard applies the SM-2 algorithm to a card reviewed today with a grade of 0 to 5
+// A grade below 3 starts the card over, due again tomorrow; remembered cards come back
+// after 1 day, then 6, then their previous interval times their ease factor.
+func scheduleCard(card *model.Card, grade int, today time.Time) {
+	if grade < model.CardPassGrade {
+		card.Repetitions = 0
+		card.IntervalDays = 1
+	} else {
+		card.Repetitions++
+		switch card.Repetitions {
+		case 1:
+			card.IntervalDays = 1
+		case 2:
+			card.IntervalDays = 6
+		default:
+			card.IntervalDays = int(math.Round(float64(card.IntervalDays) * card.EaseFactor))
+		}
+	}
+
+	miss := float64(model.CardMaxGrade - grade)
+	card.EaseFactor = max(model.CardMinEase, card.EaseFactor+0.1-miss*(0.08+miss*0.02))
+
+	dueOn := today.AddDate(0, 0, card.IntervalDays).Format(util.DailyDateLayout)
+	card.DueOn = &dueOn
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+func TestScheduleCard(t *testing.T) {
+	today := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		card  model.Card // Schedule before the review
+		grade int
+
+		wantRepetitions int
+		wantInterval    int
+		wantEase        float64
+	}{
+		// A new card graded 0 to 5
+		{"new card, grade 0", newCard(), 0, 0, 1, 1.7},
+		{"new card, grade 1", newCard(), 1, 0, 1, 1.96},
+		{"new card, grade 2", newCard(), 2, 0, 1, 2.18},
+		{"new card, grade 3", newCard(), 3, 1, 1, 2.36},
+		{"new card, grade 4", newCard(), 4, 1, 1, 2.5},
+		{"new card, grade 5", newCard(), 5, 1, 1, 2.6},
+
+		// Repetitions after the first
+		{"second repetition", model.Card{EaseFactor: 2.5, Repetitions: 1, IntervalDays: 1}, 4, 2, 6, 2.5},
+		{"third repetition uses the ease before the review", model.Card{EaseFactor: 2.5, Repetitions: 2, IntervalDays: 6}, 5, 3, 15, 2.6},
+		{"later repetition rounds the interval", model.Card{EaseFactor: 1.3, Repetitions: 3, IntervalDays: 15}, 4, 4, 20, 1.3},
+
+		// Forgetting starts the card over
+		{"grade below 3 resets the interval", model.Card{EaseFactor: 2.0, Repetitions: 5, IntervalDays: 40}, 2, 0, 1, 1.68},
+		{"grade 3 keeps the streak", model.Card{EaseFactor: 2.0, Repetitions: 5, IntervalDays: 40}, 3, 6, 80, 1.86},
+
+		// The ease factor never drops below 1.3
+		{"ease floor on a blank", model.Card{EaseFactor: model.CardMinEase, Repetitions: 2, IntervalDays: 6}, 0, 0, 1, model.CardMinEase},
+		{"ease floor on a hard recall", model.Card{EaseFactor: 1.4, Repetitions: 2, IntervalDays: 6}, 3, 3, 8, model.CardMinEase},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			card := tt.card
+			scheduleCard(&card, tt.grade, today)
+
+			if card.Repetitions != tt.wantRepetitions {
+				t.Errorf("repetitions = %d, want %d", card.Repetitions, tt.wantRepetitions)
+			}
+			if card.IntervalDays != tt.wantInterval {
+				t.Errorf("interval = %d days, want %d", card.IntervalDays, tt.wantInterval)
+			}
+			if math.Abs(card.EaseFactor-tt.wantEase) > 1e-9 {
+				t.Errorf("ease factor = %v, want %v", card.EaseFactor, tt.wantEase)
+			}
+
+			wantDue := today.AddDate(0, 0, tt.wantInterval).Format("2006-01-02")
+			if card.DueOn == nil || *card.DueOn != wantDue {
+				t.Errorf("due on = %v, want %s", card.DueOn, wantDue)
+			}
+		})
+	}
+}
+
+// newCard returns the schedule of a card never reviewed
+func newCard() model.Card {
+	return model.Card{EaseFactor: model.CardInitialEase}
+}
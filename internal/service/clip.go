@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/momokii/go-cli-notes/internal/clipper"
+	"github.com/momokii/go-cli-notes/internal/model"
+	"github.com/momokii/go-cli-notes/internal/util"
+)
+
+// maxClipChars keeps clipped articles within the note content limit
+const maxClipChars = 99000
+
+// ClipService saves web pages as notes
+type ClipService struct {
+	noteService *NoteService
+	tagService  *TagService
+	clipper     *clipper.Clipper
+}
+
+// NewClipService creates a new clip service
+func NewClipService(noteService *NoteService, tagService *TagService, c *clipper.Clipper) *ClipService {
+	return &ClipService{
+		noteService: noteService,
+		tagService:  tagService,
+		clipper:     c,
+	}
+}
+
+// Clip fetches the article at req.URL and saves it as a note tagged clipped
+// The note opens with a line crediting the page. Clipped text isn't counted
+// toward the words the user wrote.
+func (s *ClipService) Clip(ctx context.Context, userID uuid.UUID, req *model.ClipRequest) (*model.Note, error) {
+	if err := util.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("%w: %w", model.ErrValidation, err)
+	}
+
+	article, err := s.clipper.Clip(ctx, req.URL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", model.ErrClipFailed, err)
+	}
+	if strings.TrimSpace(article.Content) == "" {
+		return nil, fmt.Errorf("%w: no article found on the page", model.ErrClipFailed)
+	}
+
+	settings, err := s.noteService.settingsRepo.Get(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get settings: %w", err)
+	}
+	today := time.Now().In(settings.Location()).Format(util.DailyDateLayout)
+
+	body := article.Content
+	if runes := []rune(body); len(runes) > maxClipChars {
+		body = string(runes[:maxClipChars]) + "\n\n*(clipped article truncated)*"
+	}
+
+	note, err := s.noteService.create(ctx, userID, &model.CreateNoteRequest{
+		Title:   clipTitle(article),
+		Content: clipSourceLine(article, today) + "\n\n" + body + "\n",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The note is saved even if a tag can't be added
+	names := append([]string{model.ClippedTag}, req.Tags...)
+	for _, name := range names {
+		tag, err := s.tagService.Ensure(ctx, userID, name)
+		if err == nil {
+			err = s.tagService.AddToNote(ctx, userID, note.ID, tag.ID)
+		}
+		if err != nil {
+			slog.Warn("Failed to tag clipped note", "note_id", note.ID, "tag", name, "error", err)
+			continue
+		}
+		note.Tags = append(note.Tags, tag)
+	}
+
+	return note, nil
+}
+
+// clipTitle returns the title of a clipped note: the article's, or else the page address
+func clipTitle(article *clipper.Article) string {
+	title := strings.Join(strings.Fields(article.Title), " ")
+	if title == "" {
+		if u, err := url.Parse(article.URL); err == nil {
+			title = u.Host + strings.TrimSuffix(u.Path, "/")
+		}
+	}
+	if runes := []rune(title); len(runes) > 500 {
+		title = string(runes[:500])
+	}
+	return title
+}
+
+// clipSourceLine credits the page an article was clipped from
+// e.g. "> Clipped from [Garden Blog](https://…) · Jane Doe · 2026-10-14"
+func clipSourceLine(article *clipper.Article, date string) string {
+	site := article.SiteName
+	if site == "" {
+		if u, err := url.Parse(article.URL); err == nil {
+			site = u.Host
+		}
+	}
+	site = strings.NewReplacer("[", "", "]", "").Replace(site)
+
+	parts := []string{"Clipped from [" + site + "](" + article.URL + ")"}
+	if article.Byline != "" {
+		parts = append(parts, article.Byline)
+	}
+	parts = append(parts, date)
+	return "> " + strings.Join(parts, " · ")
+}
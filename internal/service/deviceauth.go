@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+	"github.com/momokii/go-cli-notes/internal/repository"
+	"github.com/momokii/go-cli-notes/internal/util"
+)
+
+const (
+	// deviceUserCodeAlphabet has no vowels, so codes don't spell words, and nothing
+	// that looks like a digit
+	deviceUserCodeAlphabet = "BCDFGHJKLMNPQRSTVWXZ"
+	deviceUserCodeLength   = 8
+	// devicePollInterval is how long a device waits between polls for its tokens
+	devicePollInterval = 5 * time.Second
+)
+
+// StartDeviceAuth starts a login for a device that can't show a login form
+// The device displays the user code and the verification URI, where a user signs
+// in and approves it, and polls PollDeviceAuth with the device code meanwhile.
+func (s *AuthService) StartDeviceAuth(ctx context.Context, client model.ClientInfo, requestBaseURL string) (*model.DeviceCodeResponse, error) {
+	// Keep the table small, nothing else cleans it up
+	_ = s.deviceAuthRepo.DeleteExpired(ctx)
+
+	deviceCode, err := generateEmailToken()
+	if err != nil {
+		return nil, err
+	}
+	userCode, err := generateUserCode()
+	if err != nil {
+		return nil, err
+	}
+
+	auth := &model.DeviceAuthorization{
+		DeviceCodeHash: s.hashToken(deviceCode),
+		UserCode:       userCode,
+		UserAgent:      client.UserAgent,
+		IPAddress:      client.IPAddress,
+		ExpiresAt:      time.Now().Add(s.deviceExpiration),
+	}
+	if err := s.deviceAuthRepo.Create(ctx, auth); err != nil {
+		return nil, fmt.Errorf("store device authorization: %w", err)
+	}
+
+	base := s.publicURL
+	if base == "" {
+		base = strings.TrimSuffix(requestBaseURL, "/")
+	}
+	verificationURI := base + "/device"
+
+	return &model.DeviceCodeResponse{
+		DeviceCode:              deviceCode,
+		UserCode:                userCode,
+		VerificationURI:         verificationURI,
+		VerificationURIComplete: verificationURI + "?code=" + url.QueryEscape(userCode),
+		ExpiresIn:               int(s.deviceExpiration.Seconds()),
+		Interval:                int(devicePollInterval.Seconds()),
+	}, nil
+}
+
+// PollDeviceAuth returns the tokens of an approved device login
+// Until then it fails with ErrAuthorizationPending, or ErrSlowDown when polled more
+// often than the interval; the tokens are only handed out once.
+func (s *AuthService) PollDeviceAuth(ctx context.Context, req *model.DeviceTokenRequest, client model.ClientInfo) (*model.AuthResponse, error) {
+	if err := util.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("%w: %w", model.ErrValidation, err)
+	}
+
+	auth, err := s.deviceAuthRepo.FindByDeviceCodeHash(ctx, s.hashToken(req.DeviceCode))
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil, model.ErrDeviceCodeExpired
+		}
+		return nil, fmt.Errorf("find device authorization: %w", err)
+	}
+
+	now := time.Now()
+	switch {
+	case auth.Status == model.DeviceAuthDenied:
+		return nil, model.ErrAccessDenied
+	case auth.Status == model.DeviceAuthUsed || now.After(auth.ExpiresAt):
+		return nil, model.ErrDeviceCodeExpired
+	case auth.Status == model.DeviceAuthPending:
+		// A second of slack for network latency between polls
+		tooSoon := auth.LastPolledAt != nil && now.Sub(*auth.LastPolledAt) < devicePollInterval-time.Second
+		if err := s.deviceAuthRepo.SetPolled(ctx, auth.ID, now); err != nil {
+			return nil, err
+		}
+		if tooSoon {
+			return nil, model.ErrSlowDown
+		}
+		return nil, model.ErrAuthorizationPending
+	}
+
+	// Approved: losing this race means another poll already got the tokens
+	if err := s.deviceAuthRepo.MarkUsed(ctx, auth.ID); err != nil {
+		if err == repository.ErrNotFound {
+			return nil, model.ErrDeviceCodeExpired
+		}
+		return nil, fmt.Errorf("redeem device authorization: %w", err)
+	}
+
+	user, err := s.userRepo.FindByID(ctx, *auth.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("find user: %w", err)
+	}
+	if !user.IsActive {
+		return nil, model.ErrUnauthorized
+	}
+
+	resp, err := s.issueTokens(ctx, user, client)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.UpdateLastLogin(ctx, user.ID); err != nil {
+		// Log but don't fail the request
+		fmt.Printf("warning: update last login failed: %v\n", err)
+	}
+	s.auditLogin(ctx, user.Email, user, nil, client, model.AuditMetadata{"method": "device"})
+
+	return resp, nil
+}
+
+// ApproveDevice signs the device showing the user code in as the user, or denies its login
+func (s *AuthService) ApproveDevice(ctx context.Context, userID uuid.UUID, req *model.DeviceApproveRequest, client model.ClientInfo) (*model.DeviceAuthorization, error) {
+	if err := util.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("%w: %w", model.ErrValidation, err)
+	}
+
+	auth, err := s.deviceAuthRepo.FindPendingByUserCode(ctx, normalizeUserCode(req.UserCode))
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil, model.ErrInvalidUserCode
+		}
+		return nil, fmt.Errorf("find device authorization: %w", err)
+	}
+
+	if req.Deny {
+		err = s.deviceAuthRepo.Deny(ctx, auth.ID)
+		auth.Status = model.DeviceAuthDenied
+	} else {
+		err = s.deviceAuthRepo.Approve(ctx, auth.ID, userID)
+		auth.Status = model.DeviceAuthApproved
+		auth.UserID = &userID
+	}
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil, model.ErrInvalidUserCode
+		}
+		return nil, fmt.Errorf("update device authorization: %w", err)
+	}
+
+	event := model.AuditDeviceApproved
+	if req.Deny {
+		event = model.AuditDeviceDenied
+	}
+	s.audit(ctx, event, userID, client, model.AuditMetadata{
+		"device_ip_address": auth.IPAddress,
+		"device_user_agent": auth.UserAgent,
+	})
+
+	return auth, nil
+}
+
+// ApproveDeviceWithLogin approves a device login from the device login page, where the
+// user signs in with their credentials instead of a token
+func (s *AuthService) ApproveDeviceWithLogin(ctx context.Context, userCode string, login *model.LoginRequest, client model.ClientInfo) (*model.DeviceAuthorization, error) {
+	user, err := s.authenticate(ctx, login)
+	if err != nil {
+		s.auditLogin(ctx, login.Email, user, err, client, model.AuditMetadata{"method": "device_page"})
+		return nil, err
+	}
+
+	return s.ApproveDevice(ctx, user.ID, &model.DeviceApproveRequest{UserCode: userCode}, client)
+}
+
+// generateUserCode returns a random code to type in, e.g. "WDJB-MJHT"
+func generateUserCode() (string, error) {
+	size := big.NewInt(int64(len(deviceUserCodeAlphabet)))
+	code := make([]byte, deviceUserCodeLength)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, size)
+		if err != nil {
+			return "", fmt.Errorf("generate user code: %w", err)
+		}
+		code[i] = deviceUserCodeAlphabet[n.Int64()]
+	}
+
+	half := deviceUserCodeLength / 2
+	return string(code[:half]) + "-" + string(code[half:]), nil
+}
+
+// normalizeUserCode accepts a user code typed in any case, with or without the dash
+func normalizeUserCode(code string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(code) {
+		if r >= 'A' && r <= 'Z' {
+			b.WriteRune(r)
+		}
+	}
+
+	code = b.String()
+	if len(code) != deviceUserCodeLength {
+		return code
+	}
+	half := deviceUserCodeLength / 2
+	return code[:half] + "-" + code[half:]
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+	"github.com/momokii/go-cli-notes/internal/repository"
+	"github.com/momokii/go-cli-notes/internal/util"
+	"github.com/momokii/go-cli-notes/internal/webhook"
+)
+
+const (
+	// digestCheckInterval is how often due digests are looked for; a failed post is
+	// retried at the next check within the digest hour
+	digestCheckInterval = 10 * time.Minute
+	// digestForgottenLimit is how many forgotten notes a digest resurfaces
+	digestForgottenLimit = 3
+)
+
+// DigestService posts a daily digest of each user's writing to their Slack or Discord webhook
+// Users opt in with the digest_url setting; the digest of yesterday is posted at digest_hour
+// in their timezone.
+type DigestService struct {
+	settingsRepo repository.SettingsRepository
+	activityRepo repository.ActivityRepository
+	sender       *webhook.Sender
+}
+
+// NewDigestService creates a new digest service
+func NewDigestService(settingsRepo repository.SettingsRepository, activityRepo repository.ActivityRepository, sender *webhook.Sender) *DigestService {
+	return &DigestService{
+		settingsRepo: settingsRepo,
+		activityRepo: activityRepo,
+		sender:       sender,
+	}
+}
+
+// Run posts the digests that are due until ctx is cancelled
+func (s *DigestService) Run(ctx context.Context) {
+	ticker := time.NewTicker(digestCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		s.postDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// postDue posts the digest of every user whose digest hour it is and who didn't get today's yet
+func (s *DigestService) postDue(ctx context.Context) {
+	due, err := s.settingsRepo.ListDigestDue(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			slog.Error("Failed to list due digests", "error", err)
+		}
+		return
+	}
+
+	for _, settings := range due {
+		now := time.Now().In(settings.Location())
+		if now.Hour() != settings.DigestHour {
+			continue
+		}
+
+		if err := s.post(ctx, settings, now); err != nil {
+			if ctx.Err() == nil {
+				slog.Warn("Failed to post daily digest", "user_id", settings.UserID, "error", err)
+			}
+			continue
+		}
+		if err := s.settingsRepo.MarkDigestSent(ctx, settings.UserID, now.Format(util.DailyDateLayout)); err != nil {
+			slog.Error("Failed to record daily digest", "user_id", settings.UserID, "error", err)
+		}
+	}
+}
+
+// post builds the digest of the day before now and posts it to the user's webhook
+func (s *DigestService) post(ctx context.Context, settings *model.UserSettings, now time.Time) error {
+	digest, err := s.Build(ctx, settings, now.AddDate(0, 0, -1))
+	if err != nil {
+		return err
+	}
+
+	body, err := digestBody(*settings.DigestURL, digest)
+	if err != nil {
+		return fmt.Errorf("marshal digest: %w", err)
+	}
+
+	if _, err := s.sender.Post(ctx, *settings.DigestURL, body); err != nil {
+		return fmt.Errorf("post digest: %w", err)
+	}
+	return nil
+}
+
+// Build collects the digest of the day of day, in the user's timezone
+func (s *DigestService) Build(ctx context.Context, settings *model.UserSettings, day time.Time) (*model.DailyDigest, error) {
+	loc := settings.Location()
+	day = day.In(loc)
+	from := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+
+	digest := &model.DailyDigest{
+		Date:          from.Format(util.DailyDateLayout),
+		DailyWordGoal: settings.DailyWordGoal,
+	}
+
+	var err error
+	digest.NotesCreated, err = s.activityRepo.CountNotesCreated(ctx, settings.UserID, from, from.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	// The last days up to today, the digest day is the first of the ones since it
+	days := int(time.Since(from).Hours()/24) + 1
+	words, err := s.activityRepo.GetDailyWords(ctx, settings.UserID, settings.Timezone, days)
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range words {
+		if w.Date == digest.Date {
+			digest.WordsWritten = w.Words
+		}
+	}
+
+	streak, err := s.activityRepo.GetWritingStreak(ctx, settings.UserID, settings.Timezone, settings.DailyWordGoal)
+	if err != nil {
+		return nil, err
+	}
+	digest.Streak = streak.CurrentStreak
+
+	digest.Forgotten, err = s.activityRepo.GetForgottenNotes(ctx, settings.UserID, settings.ForgottenDays, digestForgottenLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	return digest, nil
+}
+
+// digestBody is the incoming webhook payload of a digest, in the format of the service the URL belongs to
+func digestBody(webhookURL string, digest *model.DailyDigest) ([]byte, error) {
+	if isDiscordWebhook(webhookURL) {
+		// Note titles must not ping anyone in the channel
+		return json.Marshal(map[string]any{
+			"content":          renderDigest(digest, "**", func(s string) string { return s }),
+			"allowed_mentions": map[string]any{"parse": []string{}},
+		})
+	}
+
+	// Slack, and services that accept its format (Mattermost, Rocket.Chat...)
+	escape := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace
+	return json.Marshal(map[string]any{
+		"text": renderDigest(digest, "*", escape),
+	})
+}
+
+// isDiscordWebhook reports whether a URL is a Discord webhook, which takes "content" instead of "text"
+func isDiscordWebhook(webhookURL string) bool {
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, domain := range []string{"discord.com", "discordapp.com"} {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// renderDigest writes a digest as a chat message, bold marking the heading and escape applied to note titles
+func renderDigest(digest *model.DailyDigest, bold string, escape func(string) string) string {
+	var b strings.Builder
+
+	heading := digest.Date
+	if day, err := time.Parse(util.DailyDateLayout, digest.Date); err == nil {
+		heading = day.Format("Monday, January 2")
+	}
+	fmt.Fprintf(&b, "%sYour notes on %s%s\n", bold, heading, bold)
+
+	fmt.Fprintf(&b, "• %s created\n", countNoun(digest.NotesCreated, "note", "notes"))
+	fmt.Fprintf(&b, "• %s written (goal %d)", countNoun(digest.WordsWritten, "word", "words"), digest.DailyWordGoal)
+	if digest.Streak > 0 {
+		fmt.Fprintf(&b, ", %d-day writing streak", digest.Streak)
+	}
+	b.WriteString("\n")
+
+	if len(digest.Forgotten) > 0 {
+		b.WriteString("\nWorth another look:\n")
+		for _, forgotten := range digest.Forgotten {
+			fmt.Fprintf(&b, "• %s (not opened for %d days)\n", escape(forgotten.Note.Title), forgotten.DaysSinceAccess)
+		}
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// countNoun writes n with the singular or plural noun, e.g. "1 note", "3 notes"
+func countNoun(n int, singular, plural string) string {
+	if n == 1 {
+		return "1 " + singular
+	}
+	return fmt.Sprintf("%d %s", n, plural)
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/momokii/go-cli-notes/internal/embedding"
+	"github.com/momokii/go-cli-notes/internal/model"
+	"github.com/momokii/go-cli-notes/internal/repository"
+	"github.com/momokii/go-cli-notes/internal/util"
+)
+
+// maxEmbeddingChars caps the text sent to the provider per note
+// Long notes are embedded from their beginning, where the title and summary usually are.
+const maxEmbeddingChars = 8000
+
+// EmbeddingService keeps note embeddings up to date and runs semantic searches
+type EmbeddingService struct {
+	embeddingRepo repository.EmbeddingRepository
+	provider      embedding.Provider // nil = semantic search disabled
+	batchSize     int
+}
+
+// NewEmbeddingService creates a new embedding service
+// A nil provider disables semantic search.
+func NewEmbeddingService(
+	embeddingRepo repository.EmbeddingRepository,
+	provider embedding.Provider,
+	batchSize int,
+) *EmbeddingService {
+	if batchSize <= 0 {
+		batchSize = 32
+	}
+	return &EmbeddingService{
+		embeddingRepo: embeddingRepo,
+		provider:      provider,
+		batchSize:     batchSize,
+	}
+}
+
+// Enabled reports whether semantic search is available
+func (s *EmbeddingService) Enabled() bool {
+	return s.provider != nil
+}
+
+// Run embeds new and changed notes every interval until ctx is cancelled
+// Notes are picked up from the database, so edits made while the provider was
+// unreachable are embedded once it is back.
+func (s *EmbeddingService) Run(ctx context.Context, interval time.Duration) {
+	if !s.Enabled() {
+		return
+	}
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// Work through the backlog before waiting for the next tick
+		for {
+			n, err := s.IndexPending(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					slog.Warn("Failed to embed notes", "error", err)
+				}
+				break
+			}
+			if n < s.batchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// IndexPending embeds one batch of notes without an up-to-date embedding
+// It returns how many notes were embedded.
+func (s *EmbeddingService) IndexPending(ctx context.Context) (int, error) {
+	if !s.Enabled() {
+		return 0, model.ErrSemanticSearchDisabled
+	}
+
+	notes, err := s.embeddingRepo.ListStale(ctx, s.provider.Model(), s.batchSize)
+	if err != nil {
+		return 0, err
+	}
+	if len(notes) == 0 {
+		return 0, nil
+	}
+
+	texts := make([]string, len(notes))
+	for i, note := range notes {
+		texts[i] = embeddingText(note)
+	}
+
+	vectors, err := s.provider.Embed(ctx, texts)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, note := range notes {
+		if err := s.embeddingRepo.Upsert(ctx, note.ID, note.UserID, s.provider.Model(), vectors[i], note.UpdatedAt); err != nil {
+			return i, err
+		}
+	}
+
+	return len(notes), nil
+}
+
+// Search finds the user's notes closest in meaning to the query
+func (s *EmbeddingService) Search(ctx context.Context, userID uuid.UUID, req *model.SemanticSearchRequest) (*model.SemanticSearchResponse, error) {
+	if !s.Enabled() {
+		return nil, model.ErrSemanticSearchDisabled
+	}
+
+	if err := util.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("%w: %w", model.ErrValidation, err)
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = 10
+	}
+
+	vectors, err := s.provider.Embed(ctx, []string{req.Query})
+	if err != nil {
+		return nil, fmt.Errorf("embed query: %w", err)
+	}
+
+	filter := model.NoteFilter{NoteType: req.NoteType, TagID: req.TagID}
+	results, err := s.embeddingRepo.Search(ctx, userID, s.provider.Model(), vectors[0], req.Query, filter, limit)
+	if err != nil {
+		return nil, fmt.Errorf("semantic search: %w", err)
+	}
+
+	return &model.SemanticSearchResponse{
+		Query:   req.Query,
+		Model:   s.provider.Model(),
+		Results: results,
+	}, nil
+}
+
+// embeddingText returns the text a note is embedded from
+// Encrypted notes only contribute their title, their content is ciphertext.
+func embeddingText(note *model.Note) string {
+	text := note.Title
+	if !note.Encrypted && note.Content != "" {
+		text += "\n\n" + note.Content
+	}
+	if text == "" {
+		// Providers reject empty input
+		text = "(untitled)"
+	}
+
+	if runes := []rune(text); len(runes) > maxEmbeddingChars {
+		text = string(runes[:maxEmbeddingChars])
+	}
+	return text
+}
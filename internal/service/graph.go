@@ -0,0 +1,356 @@
+package service
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+const (
+	// graphTopNotes is how many notes the most connected and hub lists hold
+	graphTopNotes = 10
+	// pageRankDamping is the chance a reader follows a link rather than jumping to any note
+	pageRankDamping = 0.85
+	// pageRankIterations caps the power iterations; scores usually settle well before
+	pageRankIterations = 100
+	// pageRankTolerance is the total change in scores below which they count as settled
+	pageRankTolerance = 1e-9
+	// labelPropagationRounds caps the rounds of cluster detection; labels usually settle in a few
+	labelPropagationRounds = 20
+)
+
+// graphStats computes the statistics of a graph and puts each node in its cluster
+// Degrees, components, depth and clusters treat links as undirected; centrality follows their direction.
+// A note's links to itself connect it to nothing, so they only count in the total of links.
+func graphStats(nodes []*model.GraphNode, edges []*model.GraphEdge) *model.GraphStats {
+	index := make(map[uuid.UUID]int, len(nodes))
+	for i, node := range nodes {
+		index[node.ID] = i
+	}
+
+	in := make([]int, len(nodes))
+	out := make([]int, len(nodes))
+	outLinks := make([][]int, len(nodes))
+	neighbors := make([]map[int]bool, len(nodes))
+	for i := range neighbors {
+		neighbors[i] = map[int]bool{}
+	}
+	connections := 0
+	for _, edge := range edges {
+		source, target := index[edge.Source], index[edge.Target]
+		if source == target {
+			continue
+		}
+		connections++
+		out[source]++
+		in[target]++
+		outLinks[source] = append(outLinks[source], target)
+		neighbors[source][target] = true
+		neighbors[target][source] = true
+	}
+
+	stats := &model.GraphStats{
+		TotalNotes:         int64(len(nodes)),
+		TotalLinks:         int64(len(edges)),
+		DegreeDistribution: []*model.DegreeCount{},
+		MostConnected:      []*model.GraphNoteScore{},
+		Hubs:               []*model.GraphNoteScore{},
+	}
+	if len(nodes) == 0 {
+		return stats
+	}
+	stats.AverageDegree = float64(2*connections) / float64(len(nodes))
+
+	distribution := map[int]int{}
+	for i := range nodes {
+		degree := in[i] + out[i]
+		distribution[degree]++
+		if degree > 0 {
+			stats.Connected++
+		} else {
+			stats.Orphans++
+		}
+	}
+	for degree, count := range distribution {
+		stats.DegreeDistribution = append(stats.DegreeDistribution, &model.DegreeCount{Degree: degree, Notes: count})
+	}
+	sort.Slice(stats.DegreeDistribution, func(i, j int) bool {
+		return stats.DegreeDistribution[i].Degree < stats.DegreeDistribution[j].Degree
+	})
+
+	stats.Components, stats.MaxDepth = graphComponents(neighbors)
+
+	clusters, sizes := labelPropagation(neighbors)
+	for i, node := range nodes {
+		node.Cluster = clusters[i]
+	}
+	for _, size := range sizes {
+		if size > 1 {
+			stats.Clusters++
+		}
+	}
+
+	centrality := pageRank(outLinks)
+	scores := make([]*model.GraphNoteScore, len(nodes))
+	for i, node := range nodes {
+		scores[i] = &model.GraphNoteScore{
+			ID:         node.ID,
+			Title:      node.Title,
+			InDegree:   in[i],
+			OutDegree:  out[i],
+			Centrality: centrality[i],
+		}
+	}
+
+	byDegree := append([]*model.GraphNoteScore(nil), scores...)
+	sort.SliceStable(byDegree, func(i, j int) bool {
+		return byDegree[i].InDegree+byDegree[i].OutDegree > byDegree[j].InDegree+byDegree[j].OutDegree
+	})
+	for _, score := range byDegree[:min(graphTopNotes, len(byDegree))] {
+		if score.InDegree+score.OutDegree > 0 {
+			stats.MostConnected = append(stats.MostConnected, score)
+		}
+	}
+
+	// A note nothing links to only has the score of a random jump, it is no hub
+	sort.SliceStable(scores, func(i, j int) bool { return scores[i].Centrality > scores[j].Centrality })
+	for _, score := range scores {
+		if len(stats.Hubs) == graphTopNotes {
+			break
+		}
+		if score.InDegree > 0 {
+			stats.Hubs = append(stats.Hubs, score)
+		}
+	}
+
+	return stats
+}
+
+// graphComponents counts the connected components of an undirected graph and estimates the
+// longest shortest path within any of them by a double sweep: a breadth-first walk from any
+// node of a component finds its farthest node, and a walk from that one gives the depth.
+// The depth is exact for trees and never more than the true one, in time linear in the graph.
+func graphComponents(neighbors []map[int]bool) (int, int) {
+	distance := make([]int, len(neighbors))
+	for i := range distance {
+		distance[i] = -1
+	}
+	visited := make([]bool, len(neighbors))
+
+	components, maxDepth := 0, 0
+	for start := range neighbors {
+		if visited[start] {
+			continue
+		}
+		components++
+
+		reached := graphWalk(neighbors, start, distance)
+		far := reached[len(reached)-1]
+		for _, node := range reached {
+			visited[node] = true
+			distance[node] = -1
+		}
+
+		reached = graphWalk(neighbors, far, distance)
+		maxDepth = max(maxDepth, distance[reached[len(reached)-1]])
+		for _, node := range reached {
+			distance[node] = -1
+		}
+	}
+
+	return components, maxDepth
+}
+
+// graphWalk walks the graph breadth-first from start, setting the distance of every node it
+// reaches, and returns them in the order reached, so the last one is the farthest
+// distance must be -1 for every node of start's component.
+func graphWalk(neighbors []map[int]bool, start int, distance []int) []int {
+	distance[start] = 0
+	queue := []int{start}
+	for i := 0; i < len(queue); i++ {
+		current := queue[i]
+		for next := range neighbors[current] {
+			if distance[next] < 0 {
+				distance[next] = distance[current] + 1
+				queue = append(queue, next)
+			}
+		}
+	}
+	return queue
+}
+
+// pageRank scores each node by the chance a reader following links at random is on it
+// A node without links out passes its score to every node evenly.
+func pageRank(outLinks [][]int) []float64 {
+	n := float64(len(outLinks))
+	rank := make([]float64, len(outLinks))
+	for i := range rank {
+		rank[i] = 1 / n
+	}
+
+	next := make([]float64, len(outLinks))
+	for iteration := 0; iteration < pageRankIterations; iteration++ {
+		dangling := 0.0
+		for i, links := range outLinks {
+			if len(links) == 0 {
+				dangling += rank[i]
+			}
+		}
+
+		base := (1-pageRankDamping)/n + pageRankDamping*dangling/n
+		for i := range next {
+			next[i] = base
+		}
+		for i, links := range outLinks {
+			if len(links) == 0 {
+				continue
+			}
+			share := pageRankDamping * rank[i] / float64(len(links))
+			for _, target := range links {
+				next[target] += share
+			}
+		}
+
+		change := 0.0
+		for i := range rank {
+			change += math.Abs(next[i] - rank[i])
+		}
+		rank, next = next, rank
+		if change < pageRankTolerance {
+			break
+		}
+	}
+
+	return rank
+}
+
+// labelPropagation finds clusters of nodes more linked to each other than to the rest
+// Every node starts in a cluster of its own and repeatedly joins the one most of its
+// neighbors are in, keeping its own when that is one of them. Clusters are numbered from 1,
+// largest first; sizes[c-1] is the size of cluster c.
+func labelPropagation(neighbors []map[int]bool) ([]int, []int) {
+	labels := make([]int, len(neighbors))
+	for i := range labels {
+		labels[i] = i
+	}
+
+	// Nodes are visited in a shuffled order and ties broken at random, from a fixed seed
+	// so the same graph always gets the same clusters
+	rng := rand.New(rand.NewSource(1))
+	order := rng.Perm(len(neighbors))
+	for round := 0; round < labelPropagationRounds; round++ {
+		changed := false
+		rng.Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+		for _, i := range order {
+			adjacent := neighbors[i]
+			if len(adjacent) == 0 {
+				continue
+			}
+
+			counts := map[int]int{}
+			most := 0
+			for j := range adjacent {
+				counts[labels[j]]++
+				most = max(most, counts[labels[j]])
+			}
+			if counts[labels[i]] == most {
+				continue
+			}
+			var tied []int
+			for label, count := range counts {
+				if count == most {
+					tied = append(tied, label)
+				}
+			}
+			sort.Ints(tied)
+			best := tied[rng.Intn(len(tied))]
+			if best != labels[i] {
+				labels[i] = best
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+
+	// Number the clusters by size, then by their first node
+	size := map[int]int{}
+	var found []int
+	for _, label := range labels {
+		if size[label] == 0 {
+			found = append(found, label)
+		}
+		size[label]++
+	}
+	sort.SliceStable(found, func(i, j int) bool { return size[found[i]] > size[found[j]] })
+
+	number := make(map[int]int, len(found))
+	sizes := make([]int, len(found))
+	for i, label := range found {
+		number[label] = i + 1
+		sizes[i] = size[label]
+	}
+
+	clusters := make([]int, len(labels))
+	for i, label := range labels {
+		clusters[i] = number[label]
+	}
+	return clusters, sizes
+}
+
+// linkPath finds a shortest chain of links from one note to another by a breadth-first search
+// Links are followed in both directions; hop returns the links of the notes reached last.
+// Returns the notes along the path and the links between them, or nil notes when the notes
+// aren't connected within maxHops links.
+func linkPath(from, to uuid.UUID, maxHops int, hop func(frontier []uuid.UUID) ([]*model.Link, error)) ([]uuid.UUID, []*model.Link, error) {
+	// The link each reached note was first reached by
+	via := map[uuid.UUID]*model.Link{}
+	reached := map[uuid.UUID]bool{from: true}
+	frontier := []uuid.UUID{from}
+	for i := 0; i < maxHops && len(frontier) > 0 && !reached[to]; i++ {
+		links, err := hop(frontier)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var next []uuid.UUID
+		for _, link := range links {
+			for _, pair := range [][2]uuid.UUID{{link.SourceNoteID, link.TargetNoteID}, {link.TargetNoteID, link.SourceNoteID}} {
+				known, found := pair[0], pair[1]
+				if reached[known] && !reached[found] {
+					reached[found] = true
+					via[found] = link
+					next = append(next, found)
+				}
+			}
+		}
+		frontier = next
+	}
+	if !reached[to] {
+		return nil, nil, nil
+	}
+
+	// Walk back from the target to the start
+	ids := []uuid.UUID{to}
+	links := []*model.Link{}
+	for id := to; id != from; {
+		link := via[id]
+		links = append(links, link)
+		if link.SourceNoteID == id {
+			id = link.TargetNoteID
+		} else {
+			id = link.SourceNoteID
+		}
+		ids = append(ids, id)
+	}
+	slices.Reverse(ids)
+	slices.Reverse(links)
+
+	return ids, links, nil
+}
@@ -0,0 +1,327 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+// testGraph builds the nodes and edges of a graph of n notes with links given as [source, target] indexes
+func testGraph(n int, links [][2]int) ([]*model.GraphNode, []*model.GraphEdge) {
+	nodes := make([]*model.GraphNode, n)
+	for i := range nodes {
+		nodes[i] = &model.GraphNode{ID: uuid.New()}
+	}
+	edges := make([]*model.GraphEdge, len(links))
+	for i, link := range links {
+		edges[i] = &model.GraphEdge{Source: nodes[link[0]].ID, Target: nodes[link[1]].ID}
+	}
+	return nodes, edges
+}
+
+// testNeighbors builds the undirected adjacency of n nodes, leaving out self-links like graphStats
+func testNeighbors(n int, links [][2]int) []map[int]bool {
+	neighbors := make([]map[int]bool, n)
+	for i := range neighbors {
+		neighbors[i] = map[int]bool{}
+	}
+	for _, link := range links {
+		if link[0] != link[1] {
+			neighbors[link[0]][link[1]] = true
+			neighbors[link[1]][link[0]] = true
+		}
+	}
+	return neighbors
+}
+
+// testOutLinks builds the directed links out of n nodes
+func testOutLinks(n int, links [][2]int) [][]int {
+	outLinks := make([][]int, n)
+	for _, link := range links {
+		outLinks[link[0]] = append(outLinks[link[0]], link[1])
+	}
+	return outLinks
+}
+
+func TestPageRank(t *testing.T) {
+	tests := []struct {
+		name  string
+		n     int
+		links [][2]int
+	}{
+		{"single note", 1, nil},
+		{"disconnected notes", 4, nil},
+		{"chain", 4, [][2]int{{0, 1}, {1, 2}, {2, 3}}},
+		{"cycle", 3, [][2]int{{0, 1}, {1, 2}, {2, 0}}},
+		{"star", 5, [][2]int{{1, 0}, {2, 0}, {3, 0}, {4, 0}}},
+		{"self-links", 3, [][2]int{{0, 0}, {1, 1}, {1, 2}}},
+		{"duplicate links", 3, [][2]int{{0, 1}, {0, 1}, {1, 2}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rank := pageRank(testOutLinks(tt.n, tt.links))
+			if len(rank) != tt.n {
+				t.Fatalf("got %d scores, want %d", len(rank), tt.n)
+			}
+
+			sum := 0.0
+			for i, score := range rank {
+				if score <= 0 {
+					t.Errorf("score of node %d = %v, want > 0", i, score)
+				}
+				sum += score
+			}
+			if math.Abs(sum-1) > 1e-6 {
+				t.Errorf("scores sum to %v, want 1", sum)
+			}
+		})
+	}
+}
+
+func TestPageRankOrder(t *testing.T) {
+	// Every note links to note 0
+	star := pageRank(testOutLinks(5, [][2]int{{1, 0}, {2, 0}, {3, 0}, {4, 0}}))
+	for i := 1; i < len(star); i++ {
+		if star[0] <= star[i] {
+			t.Errorf("star center scores %v, not above leaf %d with %v", star[0], i, star[i])
+		}
+	}
+
+	// A cycle gives no note an edge, and unlinked notes are all alike
+	for name, rank := range map[string][]float64{
+		"cycle":        pageRank(testOutLinks(3, [][2]int{{0, 1}, {1, 2}, {2, 0}})),
+		"disconnected": pageRank(testOutLinks(4, nil)),
+	} {
+		for i, score := range rank {
+			if want := 1 / float64(len(rank)); math.Abs(score-want) > 1e-6 {
+				t.Errorf("%s: score of node %d = %v, want %v", name, i, score, want)
+			}
+		}
+	}
+}
+
+func TestGraphComponents(t *testing.T) {
+	tests := []struct {
+		name           string
+		n              int
+		links          [][2]int
+		wantComponents int
+		wantDepth      int
+	}{
+		{"no notes", 0, nil, 0, 0},
+		{"disconnected notes", 3, nil, 3, 0},
+		{"self-link only", 2, [][2]int{{0, 0}}, 2, 0},
+		{"chain", 4, [][2]int{{0, 1}, {1, 2}, {2, 3}}, 1, 3},
+		{"chain followed against its links", 4, [][2]int{{1, 0}, {2, 1}, {3, 2}}, 1, 3},
+		{"cycle", 4, [][2]int{{0, 1}, {1, 2}, {2, 3}, {3, 0}}, 1, 2},
+		{"tree walked from its middle", 5, [][2]int{{1, 0}, {1, 2}, {1, 3}, {3, 4}}, 1, 3},
+		{"two islands and an orphan", 6, [][2]int{{0, 1}, {2, 3}, {3, 4}}, 3, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			components, depth := graphComponents(testNeighbors(tt.n, tt.links))
+			if components != tt.wantComponents {
+				t.Errorf("components = %d, want %d", components, tt.wantComponents)
+			}
+			if depth != tt.wantDepth {
+				t.Errorf("max depth = %d, want %d", depth, tt.wantDepth)
+			}
+		})
+	}
+}
+
+func TestLabelPropagation(t *testing.T) {
+	t.Run("two triangles joined by a link", func(t *testing.T) {
+		links := [][2]int{{0, 1}, {1, 2}, {2, 0}, {3, 4}, {4, 5}, {5, 3}, {2, 3}}
+		clusters, sizes := labelPropagation(testNeighbors(6, links))
+
+		if !slices.Equal(sizes, []int{3, 3}) {
+			t.Fatalf("cluster sizes = %v, want [3 3]", sizes)
+		}
+		if clusters[0] != clusters[1] || clusters[1] != clusters[2] {
+			t.Errorf("first triangle split: %v", clusters)
+		}
+		if clusters[3] != clusters[4] || clusters[4] != clusters[5] {
+			t.Errorf("second triangle split: %v", clusters)
+		}
+		if clusters[0] == clusters[3] {
+			t.Errorf("triangles merged: %v", clusters)
+		}
+	})
+
+	t.Run("disconnected notes keep clusters of their own", func(t *testing.T) {
+		clusters, sizes := labelPropagation(testNeighbors(3, nil))
+		if !slices.Equal(sizes, []int{1, 1, 1}) {
+			t.Errorf("cluster sizes = %v, want [1 1 1]", sizes)
+		}
+		if !slices.Equal(clusters, []int{1, 2, 3}) {
+			t.Errorf("clusters = %v, want [1 2 3]", clusters)
+		}
+	})
+
+	t.Run("largest cluster first", func(t *testing.T) {
+		// Note 0 alone, then a chain of three
+		clusters, sizes := labelPropagation(testNeighbors(4, [][2]int{{1, 2}, {2, 3}}))
+		if !slices.Equal(sizes, []int{3, 1}) {
+			t.Fatalf("cluster sizes = %v, want [3 1]", sizes)
+		}
+		if !slices.Equal(clusters, []int{2, 1, 1, 1}) {
+			t.Errorf("clusters = %v, want [2 1 1 1]", clusters)
+		}
+	})
+
+	t.Run("same graph, same clusters", func(t *testing.T) {
+		links := [][2]int{{0, 1}, {1, 2}, {2, 3}, {3, 4}, {4, 5}, {5, 0}, {0, 3}}
+		first, _ := labelPropagation(testNeighbors(6, links))
+		second, _ := labelPropagation(testNeighbors(6, links))
+		if !slices.Equal(first, second) {
+			t.Errorf("clusters differ between runs: %v and %v", first, second)
+		}
+	})
+
+	t.Run("no notes", func(t *testing.T) {
+		clusters, sizes := labelPropagation(nil)
+		if len(clusters) != 0 || len(sizes) != 0 {
+			t.Errorf("got clusters %v and sizes %v, want none", clusters, sizes)
+		}
+	})
+}
+
+func TestGraphStats(t *testing.T) {
+	// 0 -> 1 -> 2, 3 only links to itself and 4 is an orphan
+	nodes, edges := testGraph(5, [][2]int{{0, 1}, {1, 2}, {3, 3}})
+	stats := graphStats(nodes, edges)
+
+	if stats.TotalNotes != 5 || stats.TotalLinks != 3 {
+		t.Errorf("got %d notes and %d links, want 5 and 3", stats.TotalNotes, stats.TotalLinks)
+	}
+	if stats.Orphans != 2 {
+		t.Errorf("orphans = %d, want 2", stats.Orphans)
+	}
+	if math.Abs(stats.AverageDegree-0.8) > 1e-9 {
+		t.Errorf("average degree = %v, want 0.8", stats.AverageDegree)
+	}
+	if stats.Components != 3 {
+		t.Errorf("components = %d, want 3", stats.Components)
+	}
+	if stats.MaxDepth != 2 {
+		t.Errorf("max depth = %d, want 2", stats.MaxDepth)
+	}
+	if stats.Clusters != 1 {
+		t.Errorf("clusters = %d, want 1", stats.Clusters)
+	}
+	if nodes[3].Cluster == nodes[0].Cluster || nodes[4].Cluster == nodes[0].Cluster {
+		t.Errorf("unlinked notes share the chain's cluster %d: %d and %d", nodes[0].Cluster, nodes[3].Cluster, nodes[4].Cluster)
+	}
+
+	// Only notes other notes link to are hubs, the end of the chain first
+	if len(stats.Hubs) != 2 {
+		t.Fatalf("got %d hubs, want 2", len(stats.Hubs))
+	}
+	if stats.Hubs[0].ID != nodes[2].ID {
+		t.Errorf("top hub is %s, want the end of the chain %s", stats.Hubs[0].ID, nodes[2].ID)
+	}
+
+	empty := graphStats(nil, nil)
+	if empty.TotalNotes != 0 || empty.Components != 0 || len(empty.Hubs) != 0 {
+		t.Errorf("empty graph: got %+v", empty)
+	}
+}
+
+// testHop returns a linkPath hop over links between notes, the way LinkRepository.GetByNotes finds them
+func testHop(notes []uuid.UUID, links [][2]int) func([]uuid.UUID) ([]*model.Link, error) {
+	all := make([]*model.Link, len(links))
+	for i, link := range links {
+		all[i] = &model.Link{SourceNoteID: notes[link[0]], TargetNoteID: notes[link[1]]}
+	}
+	return func(frontier []uuid.UUID) ([]*model.Link, error) {
+		var found []*model.Link
+		for _, link := range all {
+			if slices.Contains(frontier, link.SourceNoteID) || slices.Contains(frontier, link.TargetNoteID) {
+				found = append(found, link)
+			}
+		}
+		return found, nil
+	}
+}
+
+func TestLinkPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		n       int
+		links   [][2]int
+		from    int
+		to      int
+		maxHops int
+		want    []int // Indexes of the notes along the path, nil when there is none
+	}{
+		{"direct link", 2, [][2]int{{0, 1}}, 0, 1, 10, []int{0, 1}},
+		{"against the link", 2, [][2]int{{1, 0}}, 0, 1, 10, []int{0, 1}},
+		{"chain", 4, [][2]int{{0, 1}, {2, 1}, {2, 3}}, 0, 3, 10, []int{0, 1, 2, 3}},
+		{"shortest of two routes", 5, [][2]int{{0, 1}, {1, 2}, {2, 3}, {0, 4}, {4, 3}}, 0, 3, 10, []int{0, 4, 3}},
+		{"self-links are skipped", 3, [][2]int{{0, 0}, {0, 1}, {1, 1}, {1, 2}}, 0, 2, 10, []int{0, 1, 2}},
+		{"same note", 1, nil, 0, 0, 10, []int{0}},
+		{"disconnected", 4, [][2]int{{0, 1}, {2, 3}}, 0, 3, 10, nil},
+		{"unlinked notes", 2, nil, 0, 1, 10, nil},
+		{"only a self-link", 2, [][2]int{{0, 0}}, 0, 1, 10, nil},
+		{"farther than max hops", 4, [][2]int{{0, 1}, {1, 2}, {2, 3}}, 0, 3, 2, nil},
+		{"exactly max hops", 4, [][2]int{{0, 1}, {1, 2}, {2, 3}}, 0, 3, 3, []int{0, 1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notes := make([]uuid.UUID, tt.n)
+			for i := range notes {
+				notes[i] = uuid.New()
+			}
+
+			ids, links, err := linkPath(notes[tt.from], notes[tt.to], tt.maxHops, testHop(notes, tt.links))
+			if err != nil {
+				t.Fatalf("linkPath: %v", err)
+			}
+
+			if tt.want == nil {
+				if ids != nil {
+					t.Errorf("got path %v, want none", ids)
+				}
+				return
+			}
+
+			want := make([]uuid.UUID, len(tt.want))
+			for i, index := range tt.want {
+				want[i] = notes[index]
+			}
+			if !slices.Equal(ids, want) {
+				t.Fatalf("path = %v, want %v", ids, want)
+			}
+
+			// Each link joins the notes on either side of it, in either direction
+			if len(links) != len(ids)-1 {
+				t.Fatalf("got %d links for %d notes", len(links), len(ids))
+			}
+			for i, link := range links {
+				a, b := ids[i], ids[i+1]
+				if !(link.SourceNoteID == a && link.TargetNoteID == b) && !(link.SourceNoteID == b && link.TargetNoteID == a) {
+					t.Errorf("link %d joins %s and %s, want %s and %s", i, link.SourceNoteID, link.TargetNoteID, a, b)
+				}
+			}
+		})
+	}
+}
+
+func TestLinkPathHopError(t *testing.T) {
+	failed := errors.New("database down")
+	_, _, err := linkPath(uuid.New(), uuid.New(), 10, func([]uuid.UUID) ([]*model.Link, error) {
+		return nil, failed
+	})
+	if !errors.Is(err, failed) {
+		t.Errorf("err = %v, want %v", err, failed)
+	}
+}
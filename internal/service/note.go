@@ -3,68 +3,317 @@ package service
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 
+	"github.com/momokii/go-cli-notes/internal/events"
 	"github.com/momokii/go-cli-notes/internal/model"
 	"github.com/momokii/go-cli-notes/internal/repository"
 	"github.com/momokii/go-cli-notes/internal/util"
 )
 
+// noteEmbedMaxDepth is how many levels of ![[...]] embeds are expanded
+const noteEmbedMaxDepth = 3
+
 // NoteService handles note business logic
 type NoteService struct {
+	db          *repository.DB
 	noteRepo    repository.NoteRepository
 	tagRepo     repository.TagRepository
 	linkRepo    repository.LinkRepository
 	activityRepo repository.ActivityRepository
+	revisionRepo repository.RevisionRepository
+	settingsRepo repository.SettingsRepository
+	taskRepo     repository.TaskRepository
+	cardRepo     repository.CardRepository
+	noteTypeRepo repository.NoteTypeRepository
 	linkParser  *util.LinkParser
+	broker       *events.Broker
 }
 
 // NewNoteService creates a new note service
 func NewNoteService(
+	db *repository.DB,
 	noteRepo repository.NoteRepository,
 	tagRepo repository.TagRepository,
 	linkRepo repository.LinkRepository,
 	activityRepo repository.ActivityRepository,
+	revisionRepo repository.RevisionRepository,
+	settingsRepo repository.SettingsRepository,
+	taskRepo repository.TaskRepository,
+	cardRepo repository.CardRepository,
+	noteTypeRepo repository.NoteTypeRepository,
 	linkParser *util.LinkParser,
+	broker *events.Broker,
 ) *NoteService {
 	return &NoteService{
+		db:          db,
 		noteRepo:    noteRepo,
 		tagRepo:     tagRepo,
 		linkRepo:    linkRepo,
 		activityRepo: activityRepo,
+		revisionRepo: revisionRepo,
+		settingsRepo: settingsRepo,
+		taskRepo:     taskRepo,
+		cardRepo:     cardRepo,
+		noteTypeRepo: noteTypeRepo,
 		linkParser:  linkParser,
+		broker:       broker,
 	}
 }
 
+// inTx runs fn with a copy of the service whose repositories share one transaction
+func (s *NoteService) inTx(ctx context.Context, fn func(tx *NoteService) error) error {
+	return s.db.InTx(ctx, func(db *repository.DB) error {
+		tx := *s
+		tx.db = db
+		tx.noteRepo = repository.NewNoteRepository(db)
+		tx.tagRepo = repository.NewTagRepository(db)
+		tx.linkRepo = repository.NewLinkRepository(db)
+		tx.activityRepo = repository.NewActivityRepository(db)
+		tx.revisionRepo = repository.NewRevisionRepository(db)
+		tx.settingsRepo = repository.NewSettingsRepository(db)
+		tx.taskRepo = repository.NewTaskRepository(db)
+		tx.cardRepo = repository.NewCardRepository(db)
+		tx.noteTypeRepo = repository.NewNoteTypeRepository(db)
+		return fn(&tx)
+	})
+}
+
 // Create creates a new note
+// Its words count toward the user's daily words written.
 func (s *NoteService) Create(ctx context.Context, userID uuid.UUID, req *model.CreateNoteRequest) (*model.Note, error) {
+	note, err := s.create(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	_ = s.addWordsWritten(ctx, userID, note, note.WordCount)
+
+	return note, nil
+}
+
+// create creates a new note without counting its words as written
+func (s *NoteService) create(ctx context.Context, userID uuid.UUID, req *model.CreateNoteRequest) (*model.Note, error) {
+	noteTypes, err := s.noteTypeFields(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	note, err := newNote(userID, req, s.defaultNoteType(ctx, userID), noteTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.noteRepo.Create(ctx, note); err != nil {
+		return nil, fmt.Errorf("create note: %w", err)
+	}
+
+	s.afterCreate(ctx, userID, note)
+
+	return note, nil
+}
+
+// addWordsWritten counts words toward what the user wrote today
+// Encrypted notes are skipped, their word count is that of the ciphertext.
+func (s *NoteService) addWordsWritten(ctx context.Context, userID uuid.UUID, note *model.Note, words int) error {
+	if words <= 0 || note.Encrypted {
+		return nil
+	}
+
+	settings, err := s.settingsRepo.Get(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("get settings: %w", err)
+	}
+
+	return s.activityRepo.AddWordsWritten(ctx, userID, settings.Timezone, words)
+}
+
+// CreateBatch creates many notes in one transaction
+// Invalid notes are reported in their result and skipped; the others are still created.
+func (s *NoteService) CreateBatch(ctx context.Context, userID uuid.UUID, reqs []*model.CreateNoteRequest) (*model.BatchCreateResponse, error) {
+	if len(reqs) == 0 {
+		return nil, fmt.Errorf("%w: no notes to create", model.ErrValidation)
+	}
+	if len(reqs) > maxBatchNotes {
+		return nil, fmt.Errorf("%w: at most %d notes per batch", model.ErrValidation, maxBatchNotes)
+	}
+
+	defaultType := s.defaultNoteType(ctx, userID)
+	noteTypes, err := s.noteTypeFields(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &model.BatchCreateResponse{Results: make([]*model.BatchNoteResult, len(reqs))}
+
+	// Validate everything first so only valid notes reach the transaction
+	var notes []*model.Note
+	var indexes []int
+	for i, req := range reqs {
+		resp.Results[i] = &model.BatchNoteResult{Index: i}
+		if req == nil {
+			resp.Results[i].Error = "note is required"
+			continue
+		}
+
+		note, err := newNote(userID, req, defaultType, noteTypes)
+		if err != nil {
+			resp.Results[i].Error = err.Error()
+			continue
+		}
+		notes = append(notes, note)
+		indexes = append(indexes, i)
+	}
+
+	if len(notes) > 0 {
+		errs, err := s.noteRepo.CreateBatch(ctx, notes)
+		if err != nil {
+			return nil, fmt.Errorf("create notes: %w", err)
+		}
+
+		for j, note := range notes {
+			result := resp.Results[indexes[j]]
+			if errs[j] != nil {
+				// Database errors aren't meant for clients
+				result.Error = "failed to create note"
+				continue
+			}
+			result.Note = note
+		}
+	}
+
+	// Links and tasks are processed once every note exists, so links within the batch resolve
+	for _, result := range resp.Results {
+		if result.Note == nil {
+			resp.Failed++
+			continue
+		}
+		resp.Created++
+		s.afterCreate(ctx, userID, result.Note)
+	}
+
+	return resp, nil
+}
+
+// mergeMetadata sets the keys of an update in the note's metadata, removing keys set to null
+// The generated summary and the read progress are managed by the server and can't be set this way.
+func mergeMetadata(note *model.Note, metadata model.Metadata) error {
+	if note.Metadata == nil {
+		note.Metadata = model.Metadata{}
+	}
+
+	for key, value := range metadata {
+		switch key {
+		case "":
+			return fmt.Errorf("%w: metadata keys can't be empty", model.ErrValidation)
+		case model.MetadataSummary, model.MetadataReadProgress:
+			return fmt.Errorf("%w: metadata key %q is set by the server", model.ErrValidation, key)
+		case model.MetadataStatus:
+			status, ok := value.(string)
+			if value != nil && (!ok || !slices.Contains(model.NoteStatuses, model.NoteStatus(status))) {
+				return fmt.Errorf("%w: status must be one of todo, doing, done", model.ErrValidation)
+			}
+		}
+
+		if value == nil {
+			delete(note.Metadata, key)
+		} else {
+			note.Metadata[key] = value
+		}
+	}
+
+	return nil
+}
+
+// maxBatchNotes limits how many notes one batch create may contain
+const maxBatchNotes = 500
+
+// newNote validates a create request and builds the note to insert
+// noteTypes holds the note types the user has with their custom fields, which the metadata must fit.
+func newNote(userID uuid.UUID, req *model.CreateNoteRequest, defaultType model.NoteType, noteTypes map[model.NoteType][]*model.NoteTypeField) (*model.Note, error) {
 	// Validate request
 	if err := util.ValidateStruct(req); err != nil {
-		return nil, fmt.Errorf("%w: %s", model.ErrValidation, err)
+		return nil, fmt.Errorf("%w: %w", model.ErrValidation, err)
+	}
+
+	// The server can't read encrypted notes, so make sure it never receives their plaintext
+	if req.Encrypted && req.Content != "" && !util.IsEncryptedContent(req.Content) {
+		return nil, fmt.Errorf("%w: content of an encrypted note must be encrypted by the client", model.ErrValidation)
+	}
+
+	// A frontmatter header is moved into the metadata, like an import does; one that can't be parsed stays content
+	content := req.Content
+	var header util.Frontmatter
+	if !req.Encrypted && strings.HasPrefix(content, "---\n") {
+		if fm, body, err := util.ParseMarkdown([]byte(content)); err == nil {
+			header, content = fm, body
+		}
 	}
 
-	// Set default note type
 	noteType := req.NoteType
 	if noteType == "" {
-		noteType = model.NoteTypeNote
+		noteType = model.NoteType(header.Type)
+	}
+	if noteType == "" {
+		noteType = defaultType
+	}
+	fields, ok := noteTypes[noteType]
+	if !ok {
+		return nil, unknownNoteTypeError(noteType)
 	}
 
-	// Create note
 	note := &model.Note{
-		UserID:   userID,
-		Title:    req.Title,
-		Content:  req.Content,
-		NoteType: noteType,
-		Metadata: make(model.Metadata),
+		UserID:    userID,
+		Title:     req.Title,
+		Content:   content,
+		NoteType:  noteType,
+		Metadata:  make(model.Metadata),
+		Encrypted: req.Encrypted,
+	}
+	if err := mergeMetadata(note, header.Metadata); err != nil {
+		return nil, err
+	}
+	if err := mergeMetadata(note, req.Metadata); err != nil {
+		return nil, err
+	}
+	if err := checkNoteFields(note, fields); err != nil {
+		return nil, err
+	}
+	if req.CreatedAt != nil {
+		if req.CreatedAt.After(time.Now()) {
+			return nil, fmt.Errorf("%w: created_at can't be in the future", model.ErrValidation)
+		}
+		note.CreatedAt = *req.CreatedAt
 	}
 
-	if err := s.noteRepo.Create(ctx, note); err != nil {
-		return nil, fmt.Errorf("create note: %w", err)
+	return note, nil
+}
+
+// defaultNoteType returns the user's default note type
+func (s *NoteService) defaultNoteType(ctx context.Context, userID uuid.UUID) model.NoteType {
+	settings, err := s.settingsRepo.Get(ctx, userID)
+	if err != nil {
+		return model.NoteTypeNote
 	}
+	return settings.DefaultNoteType
+}
 
+// afterCreate processes the links and tasks of a new note, logs it and notifies listeners
+func (s *NoteService) afterCreate(ctx context.Context, userID uuid.UUID, note *model.Note) {
 	// Extract and create links
-	s.processLinks(ctx, userID, note)
+	_ = s.processLinks(ctx, userID, note)
+
+	// Extract checkbox tasks
+	_ = s.processTasks(ctx, userID, note)
+
+	// Extract Q:: / A:: flashcards
+	_ = s.processCards(ctx, userID, note)
+
+	// Connect notes that were already linking to this title
+	_ = s.resolvePendingLinks(ctx, userID, note)
 
 	// Log activity
 	_ = s.activityRepo.Create(ctx, &model.Activity{
@@ -76,18 +325,39 @@ func (s *NoteService) Create(ctx context.Context, userID uuid.UUID, req *model.C
 		},
 	})
 
-	return note, nil
+	s.broker.Publish(userID, model.Event{Type: model.EventNoteCreated, NoteID: &note.ID})
 }
 
-// GetByID gets a note by ID
+// GetByID gets a note by ID, also finding notes other users share with the user, and counts the view
 func (s *NoteService) GetByID(ctx context.Context, userID, noteID uuid.UUID) (*model.Note, error) {
-	note, err := s.noteRepo.FindByID(ctx, userID, noteID)
+	note, err := s.Find(ctx, userID, noteID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.RecordView(ctx, userID, note)
+
+	return note, nil
+}
+
+// Find gets a note by ID like GetByID, without counting a view
+func (s *NoteService) Find(ctx context.Context, userID, noteID uuid.UUID) (*model.Note, error) {
+	note, err := s.noteRepo.FindAccessible(ctx, userID, noteID, model.SharePermissionRead)
 	if err != nil {
 		return nil, fmt.Errorf("find note: %w", err)
 	}
+	return note, nil
+}
+
+// RecordView counts a view of a note found with Find, bumping its access count and logging it
+func (s *NoteService) RecordView(ctx context.Context, userID uuid.UUID, note *model.Note) {
+	// Views of shared notes aren't counted, the count tracks the owner's reading
+	if note.UserID != userID {
+		return
+	}
 
 	// Update access count
-	_ = s.noteRepo.UpdateAccessCount(ctx, userID, noteID)
+	_ = s.noteRepo.UpdateAccessCount(ctx, userID, note.ID)
 
 	// Log activity
 	_ = s.activityRepo.Create(ctx, &model.Activity{
@@ -96,11 +366,50 @@ func (s *NoteService) GetByID(ctx context.Context, userID, noteID uuid.UUID) (*m
 		Action:  model.ActionView,
 	})
 
-	return note, nil
+	s.broker.Publish(userID, model.Event{Type: model.EventActivity, NoteID: &note.ID})
+}
+
+// ExpandEmbeds sets the note's ExpandedContent, its content with the notes its ![[...]] embeds name
+// expanded inline. Embeds are resolved among the user's own notes, a shared note is left unexpanded.
+func (s *NoteService) ExpandEmbeds(ctx context.Context, userID uuid.UUID, note *model.Note) error {
+	// Embeds can't be read from ciphertext, and the owner's other notes aren't shared
+	if note.Encrypted || note.UserID != userID {
+		return nil
+	}
+
+	found := make(map[string]*model.Note)
+	var findErr error
+	resolve := func(title string) (string, bool) {
+		target, ok := found[title]
+		if !ok && findErr == nil {
+			var err error
+			target, err = s.noteRepo.FindByLinkTitle(ctx, userID, title)
+			if err != nil && err != repository.ErrNotFound {
+				findErr = err
+			}
+			found[title] = target
+		}
+		if target == nil || target.Encrypted {
+			return "", false
+		}
+		return target.Content, true
+	}
+
+	expanded := s.linkParser.ExpandEmbeds(note.Title, note.Content, noteEmbedMaxDepth, resolve)
+	if findErr != nil {
+		return fmt.Errorf("expand embeds: %w", findErr)
+	}
+	note.ExpandedContent = &expanded
+
+	return nil
 }
 
 // List lists notes for a user
 func (s *NoteService) List(ctx context.Context, userID uuid.UUID, filter model.NoteFilter) ([]*model.Note, int64, error) {
+	if err := filter.ValidateSort(); err != nil {
+		return nil, 0, err
+	}
+
 	notes, total, err := s.noteRepo.List(ctx, userID, filter)
 	if err != nil {
 		return nil, 0, fmt.Errorf("list notes: %w", err)
@@ -109,29 +418,237 @@ func (s *NoteService) List(ctx context.Context, userID uuid.UUID, filter model.N
 	return notes, total, nil
 }
 
+// Stream calls fn for every note matching the filter, ignoring pagination
+func (s *NoteService) Stream(ctx context.Context, userID uuid.UUID, filter model.NoteFilter, fn func(*model.Note) error) error {
+	if err := filter.ValidateSort(); err != nil {
+		return err
+	}
+
+	if err := s.noteRepo.Stream(ctx, userID, filter, fn); err != nil {
+		return fmt.Errorf("stream notes: %w", err)
+	}
+
+	return nil
+}
+
+// Export returns every note for a user with its tags populated
+// With expandEmbeds, each note's content has its ![[...]] embeds expanded inline.
+func (s *NoteService) Export(ctx context.Context, userID uuid.UUID, expandEmbeds bool) ([]*model.Note, error) {
+	notes, err := s.noteRepo.ListAll(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("export notes: %w", err)
+	}
+
+	if expandEmbeds {
+		// Notes come oldest first, a title names its newest note as it does for links
+		titles := util.NewTitleIndex()
+		contents := make([]string, len(notes))
+		for i := len(notes) - 1; i >= 0; i-- {
+			titles.Add(notes[i].Title, i)
+			contents[i] = notes[i].Content
+		}
+		resolve := func(title string) (string, bool) {
+			i, ok := titles.Find(title)
+			if !ok || notes[i].Encrypted {
+				return "", false
+			}
+			return contents[i], true
+		}
+
+		for _, note := range notes {
+			if !note.Encrypted {
+				note.Content = s.linkParser.ExpandEmbeds(note.Title, note.Content, noteEmbedMaxDepth, resolve)
+			}
+		}
+	}
+
+	for _, note := range notes {
+		tags, err := s.tagRepo.GetByNote(ctx, note.ID)
+		if err != nil {
+			return nil, fmt.Errorf("get note tags: %w", err)
+		}
+		note.Tags = tags
+	}
+
+	return notes, nil
+}
+
 // Search searches notes using full-text search
-func (s *NoteService) Search(ctx context.Context, userID uuid.UUID, filter model.NoteFilter) ([]*model.Note, int64, error) {
-	// Search uses the same List method with the Search filter
-	notes, total, err := s.noteRepo.List(ctx, userID, filter)
+// Each result carries its rank and a snippet of about fragmentSize words around the matches.
+func (s *NoteService) Search(ctx context.Context, userID uuid.UUID, filter model.NoteFilter, fragmentSize int) ([]*model.SearchResult, int64, error) {
+	if err := filter.ValidateSort(); err != nil {
+		return nil, 0, err
+	}
+
+	if fragmentSize < model.MinFragmentSize || fragmentSize > model.MaxFragmentSize {
+		return nil, 0, fmt.Errorf("%w: fragment_size must be between %d and %d", model.ErrValidation, model.MinFragmentSize, model.MaxFragmentSize)
+	}
+
+	results, total, err := s.noteRepo.Search(ctx, userID, filter, fragmentSize)
 	if err != nil {
 		return nil, 0, fmt.Errorf("search notes: %w", err)
 	}
 
-	return notes, total, nil
+	return results, total, nil
+}
+
+// RecordSearch logs a search in the user's activity, for their search history
+func (s *NoteService) RecordSearch(ctx context.Context, userID uuid.UUID, query string, results int64) {
+	_ = s.activityRepo.Create(ctx, &model.Activity{
+		UserID: userID,
+		Action: model.ActionSearch,
+		Metadata: model.ActivityMetadata{
+			"query":   strings.TrimSpace(query),
+			"results": results,
+		},
+	})
+
+	s.broker.Publish(userID, model.Event{Type: model.EventActivity})
+}
+
+// SearchHistory lists the distinct queries the user searched for recently, most recent first
+func (s *NoteService) SearchHistory(ctx context.Context, userID uuid.UUID, limit int) ([]*model.SearchHistoryEntry, error) {
+	if limit < 1 || limit > model.MaxSearchHistoryLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", model.ErrValidation, model.MaxSearchHistoryLimit)
+	}
+
+	entries, err := s.activityRepo.GetSearchHistory(ctx, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("get search history: %w", err)
+	}
+
+	return entries, nil
+}
+
+// FuzzySearch searches note titles by trigram similarity, tolerating typos in the query
+// It's the fallback for a full-text search that found nothing; each result's rank is the
+// title's similarity to the query (0-1).
+func (s *NoteService) FuzzySearch(ctx context.Context, userID uuid.UUID, filter model.NoteFilter, fragmentSize int) ([]*model.SearchResult, int64, error) {
+	if err := filter.ValidateSort(); err != nil {
+		return nil, 0, err
+	}
+
+	if fragmentSize < model.MinFragmentSize || fragmentSize > model.MaxFragmentSize {
+		return nil, 0, fmt.Errorf("%w: fragment_size must be between %d and %d", model.ErrValidation, model.MinFragmentSize, model.MaxFragmentSize)
+	}
+
+	results, total, err := s.noteRepo.FuzzySearch(ctx, userID, filter, fragmentSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("fuzzy search notes: %w", err)
+	}
+
+	return results, total, nil
 }
 
 // Update updates a note
+// The note, its revision, links, tasks and activity entry are saved in one transaction.
+// With req.IfUpdatedAt set, a note updated since fails with model.ErrNoteChanged.
 func (s *NoteService) Update(ctx context.Context, userID, noteID uuid.UUID, req *model.UpdateNoteRequest) (*model.Note, error) {
 	// Validate request
 	if err := util.ValidateStruct(req); err != nil {
-		return nil, fmt.Errorf("%w: %s", model.ErrValidation, err)
+		return nil, fmt.Errorf("%w: %w", model.ErrValidation, err)
+	}
+
+	var note *model.Note
+	var rewritten []uuid.UUID
+	err := s.inTx(ctx, func(tx *NoteService) error {
+		// Locked, the note can't change between checking its version and updating it
+		if req.IfUpdatedAt != nil {
+			if err := tx.noteRepo.LockByID(ctx, userID, noteID); err != nil {
+				return fmt.Errorf("find note: %w", err)
+			}
+		}
+
+		var err error
+		note, rewritten, err = tx.update(ctx, userID, noteID, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Only announce the changes once they are committed
+	s.publishUpdated(userID, note, rewritten)
+
+	return note, nil
+}
+
+// UpdateMetadata sets keys of a note's metadata, a null value removes a key and keys not given are kept
+// It is an update of just the metadata, so only the owner of a shared note can do it.
+func (s *NoteService) UpdateMetadata(ctx context.Context, userID, noteID uuid.UUID, metadata model.Metadata) (*model.Note, error) {
+	if len(metadata) == 0 {
+		return nil, fmt.Errorf("%w: no metadata keys given", model.ErrValidation)
+	}
+
+	return s.Update(ctx, userID, noteID, &model.UpdateNoteRequest{Metadata: metadata})
+}
+
+// SetReadProgress saves how far the owner has read a note, in its metadata
+// Reading isn't an edit: the note keeps its updated time, and no revision or activity is recorded.
+func (s *NoteService) SetReadProgress(ctx context.Context, userID, noteID uuid.UUID, req *model.SetReadProgressRequest) (*model.ReadProgress, error) {
+	if err := util.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("%w: %w", model.ErrValidation, err)
+	}
+
+	// Whole seconds in UTC, so notes being read can be ordered by the timestamp's text
+	progress := &model.ReadProgress{Percent: *req.Percent, UpdatedAt: time.Now().UTC().Truncate(time.Second)}
+	if err := s.noteRepo.SetMetadata(ctx, userID, noteID, model.MetadataReadProgress, progress); err != nil {
+		return nil, fmt.Errorf("set read progress: %w", err)
 	}
 
+	return progress, nil
+}
+
+// ListReading lists the notes the user started reading but didn't finish, most recently read first
+func (s *NoteService) ListReading(ctx context.Context, userID uuid.UUID, limit int) ([]*model.Note, error) {
+	if limit <= 0 || limit > model.MaxReadingLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", model.ErrValidation, model.MaxReadingLimit)
+	}
+
+	notes, err := s.noteRepo.ListReading(ctx, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list notes being read: %w", err)
+	}
+
+	return notes, nil
+}
+
+// publishUpdated announces an updated note and the linking notes rewritten with it
+// Edits through a share are announced to the owner as well.
+func (s *NoteService) publishUpdated(userID uuid.UUID, note *model.Note, rewritten []uuid.UUID) {
+	s.broker.Publish(userID, model.Event{Type: model.EventNoteUpdated, NoteID: &note.ID})
+	if note.UserID != userID {
+		s.broker.Publish(note.UserID, model.Event{Type: model.EventNoteUpdated, NoteID: &note.ID})
+	}
+	for _, id := range rewritten {
+		s.broker.Publish(note.UserID, model.Event{Type: model.EventNoteUpdated, NoteID: &id})
+	}
+}
+
+// update applies an update request; it returns the note and the IDs of linking notes
+// whose content was rewritten after a rename. Callers run it inside a transaction.
+// editorID may be a user the note is shared with for writing: links, tasks and revisions
+// stay the owner's, and only the owner can change the type, metadata or encryption.
+func (s *NoteService) update(ctx context.Context, editorID, noteID uuid.UUID, req *model.UpdateNoteRequest) (*model.Note, []uuid.UUID, error) {
 	// Get existing note
-	note, err := s.noteRepo.FindByID(ctx, userID, noteID)
+	note, err := s.noteRepo.FindAccessible(ctx, editorID, noteID, model.SharePermissionWrite)
 	if err != nil {
-		return nil, fmt.Errorf("find note: %w", err)
+		return nil, nil, fmt.Errorf("find note: %w", err)
 	}
+	userID := note.UserID
+	if userID != editorID && ((req.NoteType != nil && *req.NoteType != note.NoteType) ||
+		(req.Encrypted != nil && *req.Encrypted != note.Encrypted) || len(req.Metadata) > 0) {
+		return nil, nil, fmt.Errorf("%w: only the owner can change the type, metadata or encryption of a shared note", model.ErrValidation)
+	}
+	if req.IfUpdatedAt != nil && !note.UpdatedAt.Equal(*req.IfUpdatedAt) {
+		return nil, nil, model.ErrNoteChanged
+	}
+
+	// Snapshot the current version before it is overwritten
+	previousTitle, previousContent := note.Title, note.Content
+	previousWords := note.WordCount
+	wasEncrypted := note.Encrypted
+	previousType := note.NoteType
 
 	// Update fields
 	if req.Title != nil {
@@ -140,34 +657,302 @@ func (s *NoteService) Update(ctx context.Context, userID, noteID uuid.UUID, req
 	if req.Content != nil {
 		note.Content = *req.Content
 	}
+	if req.NoteType != nil && *req.NoteType != note.NoteType {
+		if err := s.checkNoteType(ctx, userID, *req.NoteType); err != nil {
+			return nil, nil, err
+		}
+		note.NoteType = *req.NoteType
+	}
+	if req.Encrypted != nil {
+		note.Encrypted = *req.Encrypted
+	}
+	if req.Metadata != nil {
+		if err := mergeMetadata(note, req.Metadata); err != nil {
+			return nil, nil, err
+		}
+	}
+	// Notes are checked against the fields of their type when the type or metadata changes,
+	// so notes written before a field was added can still be edited
+	if (req.NoteType != nil && *req.NoteType != previousType) || req.Metadata != nil {
+		fields, err := s.noteTypeRepo.FindFields(ctx, userID, note.NoteType)
+		if err != nil {
+			return nil, nil, fmt.Errorf("find note type fields: %w", err)
+		}
+		if err := checkNoteFields(note, fields); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if note.Encrypted && note.Content != "" && !util.IsEncryptedContent(note.Content) {
+		return nil, nil, fmt.Errorf("%w: content of an encrypted note must be encrypted by the client", model.ErrValidation)
+	}
+	if !note.Encrypted && wasEncrypted && util.IsEncryptedContent(note.Content) {
+		return nil, nil, fmt.Errorf("%w: decrypt the content before turning encryption off", model.ErrValidation)
+	}
+
+	if note.Encrypted && !wasEncrypted {
+		// Older revisions hold plaintext, drop them instead of keeping a readable history
+		if err := s.revisionRepo.DeleteByNote(ctx, userID, noteID); err != nil {
+			return nil, nil, fmt.Errorf("delete revisions: %w", err)
+		}
+	} else if note.Title != previousTitle || note.Content != previousContent {
+		if err := s.revisionRepo.Create(ctx, &model.NoteRevision{
+			NoteID:  note.ID,
+			UserID:  userID,
+			Title:   previousTitle,
+			Content: previousContent,
+		}); err != nil {
+			return nil, nil, fmt.Errorf("save revision: %w", err)
+		}
+	}
+
+	// Save changes
+	if err := s.noteRepo.Update(ctx, editorID, note); err != nil {
+		return nil, nil, fmt.Errorf("update note: %w", err)
+	}
+
+	// Process outgoing links (delete old, create new); backlinks are kept
+	if err := s.linkRepo.DeleteBySource(ctx, userID, noteID); err != nil {
+		return nil, nil, err
+	}
+	if err := s.linkRepo.DeleteUnresolvedBySource(ctx, userID, noteID); err != nil {
+		return nil, nil, err
+	}
+	if err := s.processLinks(ctx, userID, note); err != nil {
+		return nil, nil, err
+	}
+
+	// Rebuild the note's tasks from the new content
+	if err := s.taskRepo.DeleteByNote(ctx, userID, noteID); err != nil {
+		return nil, nil, err
+	}
+	if err := s.processTasks(ctx, userID, note); err != nil {
+		return nil, nil, err
+	}
+
+	// Sync the note's flashcards, keeping the schedule of unchanged questions
+	if err := s.processCards(ctx, userID, note); err != nil {
+		return nil, nil, err
+	}
+
+	var rewritten []uuid.UUID
+	if note.Title != previousTitle {
+		// Point [[OldTitle]] references in linking notes at the new title
+		rewritten, err = s.rewriteBacklinks(ctx, userID, note, previousTitle)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// A new title may satisfy links that were waiting for it
+		if err := s.resolvePendingLinks(ctx, userID, note); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Log activity
+	if err := s.activityRepo.Create(ctx, &model.Activity{
+		UserID:  userID,
+		NoteID:  &note.ID,
+		Action:  model.ActionUpdate,
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	// Only added words count as written. The old word count of an encrypted note is that
+	// of its ciphertext, so there is nothing to compare a just-decrypted note against.
+	// Words written in someone else's note count for neither user.
+	if !wasEncrypted && editorID == userID {
+		if err := s.addWordsWritten(ctx, userID, note, note.WordCount-previousWords); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return note, rewritten, nil
+}
+
+// Append adds content to the end of a note, on a line of its own
+func (s *NoteService) Append(ctx context.Context, userID, noteID uuid.UUID, req *model.AppendNoteRequest) (*model.Note, error) {
+	return s.concat(ctx, userID, noteID, req, false)
+}
+
+// Prepend adds content to the start of a note, on a line of its own
+func (s *NoteService) Prepend(ctx context.Context, userID, noteID uuid.UUID, req *model.AppendNoteRequest) (*model.Note, error) {
+	return s.concat(ctx, userID, noteID, req, true)
+}
+
+// concat joins content to a note's current content inside one transaction
+// The note is locked before it is read, so concurrent appends are applied one after
+// the other instead of overwriting each other like a read-modify-write by the client.
+func (s *NoteService) concat(ctx context.Context, userID, noteID uuid.UUID, req *model.AppendNoteRequest, prepend bool) (*model.Note, error) {
+	if err := util.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("%w: %w", model.ErrValidation, err)
+	}
+
+	var note *model.Note
+	var rewritten []uuid.UUID
+	err := s.inTx(ctx, func(tx *NoteService) error {
+		if err := tx.noteRepo.LockByID(ctx, userID, noteID); err != nil {
+			return fmt.Errorf("find note: %w", err)
+		}
+		current, err := tx.noteRepo.FindAccessible(ctx, userID, noteID, model.SharePermissionWrite)
+		if err != nil {
+			return fmt.Errorf("find note: %w", err)
+		}
+		// Ciphertext can't be joined with plaintext, only the client can change it
+		if current.Encrypted {
+			return fmt.Errorf("%w: content of an encrypted note can't be changed by the server", model.ErrValidation)
+		}
+
+		content := joinContent(current.Content, req.Content)
+		if prepend {
+			content = joinContent(req.Content, current.Content)
+		}
+		update := &model.UpdateNoteRequest{Content: &content}
+		if err := util.ValidateStruct(update); err != nil {
+			return fmt.Errorf("%w: %w", model.ErrValidation, err)
+		}
+
+		note, rewritten, err = tx.update(ctx, userID, noteID, update)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishUpdated(userID, note, rewritten)
+
+	return note, nil
+}
+
+// joinContent puts second after first, starting it on a new line
+func joinContent(first, second string) string {
+	if first == "" || strings.HasSuffix(first, "\n") {
+		return first + second
+	}
+	return first + "\n" + second
+}
+
+// GetRelated returns up to limit notes related to a note, best first
+func (s *NoteService) GetRelated(ctx context.Context, userID, noteID uuid.UUID, limit int) ([]*model.RelatedNote, error) {
+	// Verify note exists and belongs to user
+	if _, err := s.noteRepo.FindByID(ctx, userID, noteID); err != nil {
+		return nil, fmt.Errorf("find note: %w", err)
+	}
+
+	related, err := s.noteRepo.FindRelated(ctx, userID, noteID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("find related notes: %w", err)
+	}
+
+	return related, nil
+}
+
+// Days and months of views returned by GetStats
+const (
+	noteStatsDays   = 30
+	noteStatsMonths = 12
+)
+
+// GetStats gets how often a note was viewed and edited, and how many links it has
+// Views are counted per day over the last 30 days and per month over the last 12.
+func (s *NoteService) GetStats(ctx context.Context, userID, noteID uuid.UUID) (*model.NoteStats, error) {
+	// Verify note exists and belongs to user
+	note, err := s.noteRepo.FindByID(ctx, userID, noteID)
+	if err != nil {
+		return nil, fmt.Errorf("find note: %w", err)
+	}
+
+	settings, err := s.GetSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := s.activityRepo.GetNoteStats(ctx, noteID, settings.Timezone, noteStatsDays, noteStatsMonths)
+	if err != nil {
+		return nil, err
+	}
+	stats.ViewCount = note.AccessCount
+	stats.LastViewedAt = note.LastAccessedAt
+	stats.LastEditedAt = note.UpdatedAt
+
+	return stats, nil
+}
+
+// LogFocus logs a completed focus session on a note as activity, with its duration
+func (s *NoteService) LogFocus(ctx context.Context, userID, noteID uuid.UUID, req *model.LogFocusRequest) (*model.Activity, error) {
+	if err := util.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("%w: %w", model.ErrValidation, err)
+	}
+
+	// Verify note exists and belongs to user
+	note, err := s.noteRepo.FindByID(ctx, userID, noteID)
+	if err != nil {
+		return nil, fmt.Errorf("find note: %w", err)
+	}
+
+	activity := &model.Activity{
+		UserID: userID,
+		NoteID: &noteID,
+		Action: model.ActionFocus,
+		Metadata: model.ActivityMetadata{
+			"title":                note.Title,
+			model.FocusDurationKey: req.DurationSeconds,
+		},
+	}
+	if err := s.activityRepo.Create(ctx, activity); err != nil {
+		return nil, fmt.Errorf("log focus session: %w", err)
+	}
+
+	s.broker.Publish(userID, model.Event{Type: model.EventActivity, NoteID: &noteID})
+
+	return activity, nil
+}
+
+// ListRevisions lists the revision history of a note, newest first
+func (s *NoteService) ListRevisions(ctx context.Context, userID, noteID uuid.UUID) ([]*model.NoteRevision, error) {
+	// Verify note exists and belongs to user
+	if _, err := s.noteRepo.FindByID(ctx, userID, noteID); err != nil {
+		return nil, fmt.Errorf("find note: %w", err)
+	}
 
-	// Save changes
-	if err := s.noteRepo.Update(ctx, note); err != nil {
-		return nil, fmt.Errorf("update note: %w", err)
+	revisions, err := s.revisionRepo.ListByNote(ctx, userID, noteID)
+	if err != nil {
+		return nil, fmt.Errorf("list revisions: %w", err)
 	}
 
-	// Process links (delete old, create new)
-	_ = s.linkRepo.DeleteByNote(ctx, userID, noteID)
-	s.processLinks(ctx, userID, note)
+	return revisions, nil
+}
 
-	// Log activity
-	_ = s.activityRepo.Create(ctx, &model.Activity{
-		UserID:  userID,
-		NoteID:  &note.ID,
-		Action:  model.ActionUpdate,
-	})
+// RestoreRevision restores a note to the title and content of a previous revision
+// The current version is itself snapshotted, so a restore can always be undone
+func (s *NoteService) RestoreRevision(ctx context.Context, userID, noteID uuid.UUID, number int) (*model.Note, error) {
+	rev, err := s.revisionRepo.FindByNumber(ctx, userID, noteID, number)
+	if err != nil {
+		return nil, fmt.Errorf("find revision: %w", err)
+	}
 
-	return note, nil
+	return s.Update(ctx, userID, noteID, &model.UpdateNoteRequest{
+		Title:   &rev.Title,
+		Content: &rev.Content,
+	})
 }
 
 // Delete soft deletes a note
+// Links to it are kept as unresolved links to its title, so a note created (or restored) with
+// that title later is linked again.
 func (s *NoteService) Delete(ctx context.Context, userID, noteID uuid.UUID) error {
+	note, _ := s.noteRepo.FindByID(ctx, userID, noteID)
 	if err := s.noteRepo.Delete(ctx, userID, noteID); err != nil {
 		return fmt.Errorf("delete note: %w", err)
 	}
 
+	if note != nil {
+		_ = s.unresolveBacklinks(ctx, userID, note)
+	}
+
 	// Delete associated links
 	_ = s.linkRepo.DeleteByNote(ctx, userID, noteID)
+	_ = s.linkRepo.DeleteUnresolvedBySource(ctx, userID, noteID)
 
 	// Log activity
 	_ = s.activityRepo.Create(ctx, &model.Activity{
@@ -176,22 +961,42 @@ func (s *NoteService) Delete(ctx context.Context, userID, noteID uuid.UUID) erro
 		Action:  model.ActionDelete,
 	})
 
+	s.broker.Publish(userID, model.Event{Type: model.EventNoteDeleted, NoteID: &noteID})
+
 	return nil
 }
 
 // Restore restores a soft deleted note
-func (s *NoteService) Restore(ctx context.Context, userID, noteID uuid.UUID) error {
+// Deleting dropped the note's links, so its outgoing links are extracted again and notes
+// linking to its title, before it was deleted or since, are connected.
+func (s *NoteService) Restore(ctx context.Context, userID, noteID uuid.UUID) (*model.Note, error) {
 	if err := s.noteRepo.Restore(ctx, userID, noteID); err != nil {
-		return fmt.Errorf("restore note: %w", err)
+		return nil, fmt.Errorf("restore note: %w", err)
 	}
 
-	return nil
+	note, err := s.noteRepo.FindByID(ctx, userID, noteID)
+	if err != nil {
+		return nil, fmt.Errorf("get note: %w", err)
+	}
+
+	_ = s.processLinks(ctx, userID, note)
+	_ = s.resolvePendingLinks(ctx, userID, note)
+
+	s.broker.Publish(userID, model.Event{Type: model.EventNoteCreated, NoteID: &noteID})
+
+	return note, nil
 }
 
 // GetOrCreateDailyNote gets or creates a daily note for a given date
+// dateStr is a YYYY-MM-DD date; new notes are filled from the user's daily template
 func (s *NoteService) GetOrCreateDailyNote(ctx context.Context, userID uuid.UUID, dateStr string) (*model.Note, bool, error) {
+	date, err := time.Parse(util.DailyDateLayout, dateStr)
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: invalid date '%s'", model.ErrValidation, dateStr)
+	}
+
 	// Try to find existing daily note for this date
-	title := "Daily Note - " + dateStr
+	title := util.DailyTitlePrefix + dateStr
 	note, err := s.noteRepo.FindByTitle(ctx, userID, title)
 
 	// If found, return it
@@ -201,14 +1006,20 @@ func (s *NoteService) GetOrCreateDailyNote(ctx context.Context, userID uuid.UUID
 
 	// If not found (and not a different error), create it
 	if err == repository.ErrNotFound {
+		template, _, err := s.GetDailyTemplate(ctx, userID)
+		if err != nil {
+			return nil, false, fmt.Errorf("create daily note: %w", err)
+		}
+
 		noteType := model.NoteTypeDaily
 		req := &model.CreateNoteRequest{
 			Title:    title,
-			Content:  "",
+			Content:  util.RenderDailyTemplate(template, title, date),
 			NoteType: noteType,
 		}
 
-		note, err = s.Create(ctx, userID, req)
+		// The template isn't writing of the user's own
+		note, err = s.create(ctx, userID, req)
 		if err != nil {
 			return nil, false, fmt.Errorf("create daily note: %w", err)
 		}
@@ -219,6 +1030,156 @@ func (s *NoteService) GetOrCreateDailyNote(ctx context.Context, userID uuid.UUID
 	return nil, false, fmt.Errorf("find daily note: %w", err)
 }
 
+// ListUnresolvedLinks lists wiki links that point at notes which don't exist yet
+func (s *NoteService) ListUnresolvedLinks(ctx context.Context, userID uuid.UUID) ([]*model.UnresolvedLink, error) {
+	links, err := s.linkRepo.ListUnresolved(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list unresolved links: %w", err)
+	}
+	return links, nil
+}
+
+// ListTasks lists the tasks found in a user's notes
+func (s *NoteService) ListTasks(ctx context.Context, userID uuid.UUID, req *model.ListTasksRequest) ([]*model.Task, error) {
+	if err := util.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("%w: %w", model.ErrValidation, err)
+	}
+
+	status := req.Status
+	if status == "" {
+		status = model.TaskStatusOpen
+	}
+
+	tasks, err := s.taskRepo.List(ctx, userID, status)
+	if err != nil {
+		return nil, fmt.Errorf("list tasks: %w", err)
+	}
+
+	return tasks, nil
+}
+
+// ListDailyNotes lists the days of a month (YYYY-MM) that have a daily note
+func (s *NoteService) ListDailyNotes(ctx context.Context, userID uuid.UUID, monthStr string) ([]*model.DailyNoteDay, error) {
+	month, err := time.Parse(util.DailyMonthLayout, monthStr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid month '%s'", model.ErrValidation, monthStr)
+	}
+
+	from := month.Format(util.DailyDateLayout)
+	to := month.AddDate(0, 1, -1).Format(util.DailyDateLayout)
+	days, err := s.noteRepo.ListDailyNotes(ctx, userID, util.DailyTitlePrefix, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("list daily notes: %w", err)
+	}
+
+	return days, nil
+}
+
+// GetDailyTemplate gets the daily note template of a user
+// Returns the default template (and true) if the user hasn't set one
+func (s *NoteService) GetDailyTemplate(ctx context.Context, userID uuid.UUID) (string, bool, error) {
+	settings, err := s.settingsRepo.Get(ctx, userID)
+	if err != nil {
+		return "", false, fmt.Errorf("get settings: %w", err)
+	}
+
+	if settings.DailyTemplate == nil {
+		return util.DefaultDailyTemplate, true, nil
+	}
+	return *settings.DailyTemplate, false, nil
+}
+
+// SetDailyTemplate sets the daily note template of a user
+// An empty template resets to the default
+func (s *NoteService) SetDailyTemplate(ctx context.Context, userID uuid.UUID, req *model.UpdateDailyTemplateRequest) error {
+	if err := util.ValidateStruct(req); err != nil {
+		return fmt.Errorf("%w: %w", model.ErrValidation, err)
+	}
+
+	var template *string
+	if req.Template != "" {
+		template = &req.Template
+	}
+
+	if err := s.settingsRepo.SetDailyTemplate(ctx, userID, template); err != nil {
+		return fmt.Errorf("set daily template: %w", err)
+	}
+
+	return nil
+}
+
+// GetSettings gets the preferences of a user (defaults if never changed)
+func (s *NoteService) GetSettings(ctx context.Context, userID uuid.UUID) (*model.UserSettings, error) {
+	settings, err := s.settingsRepo.Get(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+// UpdateSettings changes the preferences set in the request and returns the result
+func (s *NoteService) UpdateSettings(ctx context.Context, userID uuid.UUID, req *model.UpdateSettingsRequest) (*model.UserSettings, error) {
+	if err := util.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("%w: %w", model.ErrValidation, err)
+	}
+
+	settings, err := s.settingsRepo.Get(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get settings: %w", err)
+	}
+
+	if req.DefaultNoteType != nil {
+		if err := s.checkNoteType(ctx, userID, *req.DefaultNoteType); err != nil {
+			return nil, err
+		}
+		settings.DefaultNoteType = *req.DefaultNoteType
+	}
+	if req.PageSize != nil {
+		settings.PageSize = *req.PageSize
+	}
+	if req.Timezone != nil {
+		// "Local" would mean the server's timezone, which clients can't know
+		if _, err := time.LoadLocation(*req.Timezone); err != nil || *req.Timezone == "Local" {
+			return nil, fmt.Errorf("%w: unknown timezone %q", model.ErrValidation, *req.Timezone)
+		}
+		settings.Timezone = *req.Timezone
+	}
+	if req.WeekStart != nil {
+		settings.WeekStart = *req.WeekStart
+	}
+	if req.Theme != nil {
+		settings.Theme = *req.Theme
+	}
+	if req.DailyWordGoal != nil {
+		settings.DailyWordGoal = *req.DailyWordGoal
+	}
+	if req.ForgottenDays != nil {
+		settings.ForgottenDays = *req.ForgottenDays
+	}
+	if req.CaptureTarget != nil {
+		settings.CaptureTarget = *req.CaptureTarget
+	}
+	if req.DigestURL != nil {
+		settings.DigestURL = nil
+		if *req.DigestURL != "" {
+			if u, err := url.Parse(*req.DigestURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return nil, fmt.Errorf("%w: digest_url must be an http or https URL", model.ErrValidation)
+			}
+			settings.DigestURL = req.DigestURL
+		}
+	}
+	if req.DigestHour != nil {
+		settings.DigestHour = *req.DigestHour
+	}
+
+	if err := s.settingsRepo.SetPreferences(ctx, settings); err != nil {
+		return nil, fmt.Errorf("set preferences: %w", err)
+	}
+
+	return settings, nil
+}
+
 // GetOutgoingLinks gets all outgoing links from a note
 func (s *NoteService) GetOutgoingLinks(ctx context.Context, userID, noteID uuid.UUID) ([]*model.Link, error) {
 	// Verify note exists and belongs to user
@@ -234,11 +1195,16 @@ func (s *NoteService) GetOutgoingLinks(ctx context.Context, userID, noteID uuid.
 	}
 
 	// Populate target note details
+	targetIDs := make([]uuid.UUID, len(links))
+	for i, link := range links {
+		targetIDs[i] = link.TargetNoteID
+	}
+	targets, err := s.noteRepo.FindByIDs(ctx, userID, targetIDs)
+	if err != nil {
+		return nil, fmt.Errorf("find target notes: %w", err)
+	}
 	for _, link := range links {
-		targetNote, err := s.noteRepo.FindByID(ctx, userID, link.TargetNoteID)
-		if err == nil {
-			link.TargetNote = targetNote
-		}
+		link.TargetNote = targets[link.TargetNoteID]
 	}
 
 	return links, nil
@@ -259,11 +1225,16 @@ func (s *NoteService) GetBacklinks(ctx context.Context, userID, noteID uuid.UUID
 	}
 
 	// Populate source note details
+	sourceIDs := make([]uuid.UUID, len(links))
+	for i, link := range links {
+		sourceIDs[i] = link.SourceNoteID
+	}
+	sources, err := s.noteRepo.FindByIDs(ctx, userID, sourceIDs)
+	if err != nil {
+		return nil, fmt.Errorf("find source notes: %w", err)
+	}
 	for _, link := range links {
-		sourceNote, err := s.noteRepo.FindByID(ctx, userID, link.SourceNoteID)
-		if err == nil {
-			link.SourceNote = sourceNote
-		}
+		link.SourceNote = sources[link.SourceNoteID]
 	}
 
 	return links, nil
@@ -294,51 +1265,397 @@ func (s *NoteService) GetLinkGraph(ctx context.Context, userID uuid.UUID) (*mode
 	}
 
 	// Get all links for the user
+	links, err := s.linkRepo.GetLinksForUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get links: %w", err)
+	}
+
+	edges := make([]*model.GraphEdge, 0, len(links))
+	for _, link := range links {
+		// Only include edges where both nodes exist
+		if _, sourceExists := nodeMap[link.SourceNoteID]; sourceExists {
+			if _, targetExists := nodeMap[link.TargetNoteID]; targetExists {
+				edges = append(edges, &model.GraphEdge{
+					Source:    link.SourceNoteID,
+					Target:    link.TargetNoteID,
+					Context:   link.LinkContext,
+					CreatedAt: link.CreatedAt,
+				})
+			}
+		}
+	}
+
+	return &model.GraphResponse{
+		Nodes: nodes,
+		Edges: edges,
+		Stats: graphStats(nodes, edges),
+	}, nil
+}
+
+// GetLocalGraph gets the part of the knowledge graph within depth hops of a root note
+// Links are followed in both directions; the root note is always the first node.
+func (s *NoteService) GetLocalGraph(ctx context.Context, userID, rootID uuid.UUID, depth int) (*model.GraphResponse, error) {
+	root, err := s.noteRepo.FindByID(ctx, userID, rootID)
+	if err != nil {
+		return nil, fmt.Errorf("find note: %w", err)
+	}
+
+	nodeMap := map[uuid.UUID]*model.GraphNode{
+		root.ID: {ID: root.ID, Title: root.Title, Type: root.NoteType},
+	}
+	nodes := []*model.GraphNode{nodeMap[root.ID]}
 	edges := make([]*model.GraphEdge, 0)
-	for _, note := range notes {
-		links, err := s.linkRepo.GetBySource(ctx, userID, note.ID)
+	edgeSeen := make(map[[2]uuid.UUID]bool)
+
+	addEdge := func(link *model.Link) {
+		key := [2]uuid.UUID{link.SourceNoteID, link.TargetNoteID}
+		if edgeSeen[key] {
+			return
+		}
+		edgeSeen[key] = true
+		edges = append(edges, &model.GraphEdge{
+			Source:    link.SourceNoteID,
+			Target:    link.TargetNoteID,
+			Context:   link.LinkContext,
+			CreatedAt: link.CreatedAt,
+		})
+	}
+
+	// Adds the links whose notes are both in the graph
+	addEdges := func(links []*model.Link) {
+		for _, link := range links {
+			_, sourceIn := nodeMap[link.SourceNoteID]
+			_, targetIn := nodeMap[link.TargetNoteID]
+			if sourceIn && targetIn {
+				addEdge(link)
+			}
+		}
+	}
+
+	// Breadth-first walk, one hop per iteration and two queries per hop
+	frontier := []uuid.UUID{root.ID}
+	for hop := 0; hop < depth && len(frontier) > 0; hop++ {
+		links, err := s.linkRepo.GetByNotes(ctx, userID, frontier)
 		if err != nil {
-			continue
+			return nil, fmt.Errorf("get links: %w", err)
 		}
 
+		// Notes reached for the first time, in link order
+		var reached []uuid.UUID
+		queued := make(map[uuid.UUID]bool)
 		for _, link := range links {
-			// Only include edges where both nodes exist
-			if _, sourceExists := nodeMap[link.SourceNoteID]; sourceExists {
-				if _, targetExists := nodeMap[link.TargetNoteID]; targetExists {
-					edges = append(edges, &model.GraphEdge{
-						Source:    link.SourceNoteID,
-						Target:    link.TargetNoteID,
-						Context:   link.LinkContext,
-						CreatedAt: link.CreatedAt,
-					})
+			for _, id := range []uuid.UUID{link.SourceNoteID, link.TargetNoteID} {
+				if _, seen := nodeMap[id]; !seen && !queued[id] {
+					queued[id] = true
+					reached = append(reached, id)
 				}
 			}
 		}
+
+		neighbors, err := s.noteRepo.FindByIDs(ctx, userID, reached)
+		if err != nil {
+			return nil, fmt.Errorf("find notes: %w", err)
+		}
+
+		var next []uuid.UUID
+		for _, id := range reached {
+			neighbor, ok := neighbors[id]
+			if !ok {
+				// Deleted notes are not part of the graph
+				continue
+			}
+			node := &model.GraphNode{
+				ID:    neighbor.ID,
+				Title: neighbor.Title,
+				Type:  neighbor.NoteType,
+			}
+			nodeMap[neighbor.ID] = node
+			nodes = append(nodes, node)
+			next = append(next, neighbor.ID)
+		}
+
+		addEdges(links)
+		frontier = next
+	}
+
+	// Links between notes on the outer ring are not visited by the walk
+	links, err := s.linkRepo.GetByNotes(ctx, userID, frontier)
+	if err != nil {
+		return nil, fmt.Errorf("get links: %w", err)
 	}
+	addEdges(links)
 
 	return &model.GraphResponse{
 		Nodes: nodes,
 		Edges: edges,
+		Stats: graphStats(nodes, edges),
 	}, nil
 }
 
+// graphPathMaxHops is the longest path between two notes FindPath looks for
+const graphPathMaxHops = 10
+
+// FindPath finds a shortest chain of links between two notes
+// Links are followed in both directions, one breadth-first hop per query. Returns
+// model.ErrNoPath when the notes aren't connected within graphPathMaxHops links.
+func (s *NoteService) FindPath(ctx context.Context, userID, fromID, toID uuid.UUID) (*model.GraphPath, error) {
+	from, err := s.noteRepo.FindByID(ctx, userID, fromID)
+	if err != nil {
+		return nil, fmt.Errorf("find note: %w", err)
+	}
+	to, err := s.noteRepo.FindByID(ctx, userID, toID)
+	if err != nil {
+		return nil, fmt.Errorf("find note: %w", err)
+	}
+	if from.ID == to.ID {
+		return &model.GraphPath{Path: []*model.Note{from}, Links: []*model.GraphEdge{}}, nil
+	}
+
+	ids, links, err := linkPath(from.ID, to.ID, graphPathMaxHops, func(frontier []uuid.UUID) ([]*model.Link, error) {
+		return s.linkRepo.GetByNotes(ctx, userID, frontier)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get links: %w", err)
+	}
+	if ids == nil {
+		return nil, model.ErrNoPath
+	}
+
+	notes, err := s.noteRepo.FindByIDs(ctx, userID, ids)
+	if err != nil {
+		return nil, fmt.Errorf("find notes: %w", err)
+	}
+
+	path := &model.GraphPath{Path: make([]*model.Note, 0, len(ids)), Links: make([]*model.GraphEdge, 0, len(links))}
+	for _, id := range ids {
+		note, ok := notes[id]
+		if !ok {
+			// Deleted since the walk
+			return nil, model.ErrNoPath
+		}
+		path.Path = append(path.Path, note)
+	}
+	for _, link := range links {
+		path.Links = append(path.Links, &model.GraphEdge{
+			Source:    link.SourceNoteID,
+			Target:    link.TargetNoteID,
+			Context:   link.LinkContext,
+			CreatedAt: link.CreatedAt,
+		})
+	}
+
+	return path, nil
+}
+
 // processLinks extracts wiki-style links and creates them in the database
-func (s *NoteService) processLinks(ctx context.Context, userID uuid.UUID, note *model.Note) {
+func (s *NoteService) processLinks(ctx context.Context, userID uuid.UUID, note *model.Note) error {
+	// Links can't be read from ciphertext
+	if note.Encrypted {
+		return nil
+	}
+
 	links := s.linkParser.ExtractLinks(note.Content)
 	for _, link := range links {
-		// Try to find target note by title
-		targetNote, err := s.noteRepo.FindByTitle(ctx, userID, link.Title)
+		// [[#Heading]] points within the note itself
+		if link.Title == "" {
+			continue
+		}
+
+		// Try to find target note by title, ignoring case and spacing
+		targetNote, err := s.noteRepo.FindByLinkTitle(ctx, userID, link.Title)
 		if err != nil {
-			// Target note doesn't exist, skip
+			// Target note doesn't exist yet, remember the link so it can be resolved later
+			if err := s.linkRepo.CreateUnresolved(ctx, &model.UnresolvedLink{
+				UserID:       userID,
+				SourceNoteID: note.ID,
+				TargetTitle:  link.Title,
+				LinkContext:  &link.Context,
+			}); err != nil {
+				return err
+			}
 			continue
 		}
 
 		// Create link
-		_ = s.linkRepo.Create(ctx, &model.Link{
+		if err := s.linkRepo.Create(ctx, &model.Link{
 			UserID:       userID,
 			SourceNoteID: note.ID,
 			TargetNoteID: targetNote.ID,
 			LinkContext:  &link.Context,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// processTasks extracts "- [ ]" / "- [x]" checkboxes and creates them as tasks
+func (s *NoteService) processTasks(ctx context.Context, userID uuid.UUID, note *model.Note) error {
+	// Tasks can't be read from ciphertext
+	if note.Encrypted {
+		return nil
+	}
+
+	for _, task := range util.ExtractTasks(note.Content) {
+		if err := s.taskRepo.Create(ctx, &model.Task{
+			UserID:     userID,
+			NoteID:     note.ID,
+			LineNumber: task.Line,
+			Content:    task.Content,
+			Completed:  task.Completed,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rewriteBacklinks updates the content of notes linking to a renamed note and returns their IDs
+// Each source note goes through update, so the rewrite is saved in its revision history.
+// Every rewrite runs under its own savepoint: a failing source note is skipped without undoing the rename.
+func (s *NoteService) rewriteBacklinks(ctx context.Context, userID uuid.UUID, note *model.Note, oldTitle string) ([]uuid.UUID, error) {
+	backlinks, err := s.linkRepo.GetByTarget(ctx, userID, note.ID)
+	if err != nil {
+		return nil, fmt.Errorf("get backlinks: %w", err)
+	}
+
+	// Link titles are resolved with the note's old title, once for all the source notes
+	named := make(map[string]bool)
+	renamed := func(title string) bool {
+		result, ok := named[title]
+		if !ok {
+			result = s.namedBeforeRename(ctx, userID, note, oldTitle, title)
+			named[title] = result
+		}
+		return result
+	}
+
+	var rewritten []uuid.UUID
+	seen := make(map[uuid.UUID]bool, len(backlinks))
+	for _, link := range backlinks {
+		if link.SourceNoteID == note.ID || seen[link.SourceNoteID] {
+			continue
+		}
+		seen[link.SourceNoteID] = true
+
+		source, err := s.noteRepo.FindByID(ctx, userID, link.SourceNoteID)
+		if err != nil {
+			continue
+		}
+
+		content := s.linkParser.RenameLinks(source.Content, note.Title, renamed)
+		if content == source.Content {
+			continue
+		}
+
+		err = s.inTx(ctx, func(tx *NoteService) error {
+			_, _, err := tx.update(ctx, userID, source.ID, &model.UpdateNoteRequest{Content: &content})
+			return err
 		})
+		if err == nil {
+			rewritten = append(rewritten, source.ID)
+		}
+	}
+
+	return rewritten, nil
+}
+
+// namedBeforeRename reports whether a [[link]] title named note while it was titled oldTitle
+// A title matching oldTitle may name another note, one matching it better or as well and newer.
+func (s *NoteService) namedBeforeRename(ctx context.Context, userID uuid.UUID, note *model.Note, oldTitle, title string) bool {
+	if s.linkParser.NormalizeTitle(title) != s.linkParser.NormalizeTitle(oldTitle) {
+		return false
+	}
+
+	other, err := s.noteRepo.FindByLinkTitle(ctx, userID, title)
+	if err == repository.ErrNotFound {
+		return true
 	}
+	if err != nil {
+		return false
+	}
+	return linkNamesRenamed(title, oldTitle, note, other)
+}
+
+// linkNamesRenamed reports whether a [[link]] title names note under its old title rather than other,
+// the note the title names now, picking between them the way FindByLinkTitle does
+func linkNamesRenamed(title, oldTitle string, note, other *model.Note) bool {
+	if other.ID == note.ID {
+		return true
+	}
+
+	// Among titles matching alike, the newest note wins
+	titles := util.NewTitleIndex()
+	if other.CreatedAt.After(note.CreatedAt) {
+		titles.Add(other.Title, 1)
+		titles.Add(oldTitle, 0)
+	} else {
+		titles.Add(oldTitle, 0)
+		titles.Add(other.Title, 1)
+	}
+	index, ok := titles.Find(title)
+	return ok && index == 0
+}
+
+// unresolveBacklinks moves the links to a deleted note to another note its title names, or keeps them
+// as unresolved links to its title when there is none
+func (s *NoteService) unresolveBacklinks(ctx context.Context, userID uuid.UUID, note *model.Note) error {
+	backlinks, err := s.linkRepo.GetByTarget(ctx, userID, note.ID)
+	if err != nil {
+		return fmt.Errorf("get backlinks: %w", err)
+	}
+
+	other, err := s.noteRepo.FindByLinkTitle(ctx, userID, note.Title)
+	if err != nil && err != repository.ErrNotFound {
+		return err
+	}
+
+	for _, link := range backlinks {
+		if link.SourceNoteID == note.ID {
+			continue
+		}
+		if other != nil {
+			err = s.linkRepo.Create(ctx, &model.Link{
+				UserID:       userID,
+				SourceNoteID: link.SourceNoteID,
+				TargetNoteID: other.ID,
+				LinkContext:  link.LinkContext,
+			})
+		} else {
+			err = s.linkRepo.CreateUnresolved(ctx, &model.UnresolvedLink{
+				UserID:       userID,
+				SourceNoteID: link.SourceNoteID,
+				TargetTitle:  note.Title,
+				LinkContext:  link.LinkContext,
+			})
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resolvePendingLinks turns unresolved links targeting the note's title into real links
+func (s *NoteService) resolvePendingLinks(ctx context.Context, userID uuid.UUID, note *model.Note) error {
+	pending, err := s.linkRepo.ResolveByTitle(ctx, userID, note.Title)
+	if err != nil {
+		return err
+	}
+
+	for _, link := range pending {
+		if err := s.linkRepo.Create(ctx, &model.Link{
+			UserID:       userID,
+			SourceNoteID: link.SourceNoteID,
+			TargetNoteID: note.ID,
+			LinkContext:  link.LinkContext,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+func TestLinkNamesRenamed(t *testing.T) {
+	older := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+
+	// The note was "Go Notes" before the rename, other is the note the link title names now
+	tests := []struct {
+		name    string
+		title   string
+		created time.Time   // Of the renamed note
+		other   *model.Note // nil for the renamed note itself
+		want    bool
+	}{
+		{"title names the renamed note still", "Go Notes", older, nil, true},
+		{"exact old title", "Go Notes", older, &model.Note{Title: "go-notes", CreatedAt: newer}, true},
+		{"colliding title of another note", "go-notes", newer, &model.Note{Title: "go-notes", CreatedAt: older}, false},
+		{"old title differing in case beats a key match", "go notes", older, &model.Note{Title: "go_notes", CreatedAt: newer}, true},
+		{"another note differing in case beats a key match", "GO-NOTES", newer, &model.Note{Title: "go-Notes", CreatedAt: older}, false},
+		{"key match of both, the renamed note newer", "go  notes", newer, &model.Note{Title: "go-notes", CreatedAt: older}, true},
+		{"key match of both, the other note newer", "go  notes", older, &model.Note{Title: "go-notes", CreatedAt: newer}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			note := &model.Note{ID: uuid.New(), Title: "Golang", CreatedAt: tt.created}
+			other := note
+			if tt.other != nil {
+				other = tt.other
+				other.ID = uuid.New()
+			}
+
+			if got := linkNamesRenamed(tt.title, "Go Notes", note, other); got != tt.want {
+				t.Errorf("linkNamesRenamed(%q) = %v, want %v", tt.title, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+	"github.com/momokii/go-cli-notes/internal/repository"
+	"github.com/momokii/go-cli-notes/internal/util"
+)
+
+// NoteShareService shares notes with other users of the instance
+type NoteShareService struct {
+	shareRepo repository.NoteShareRepository
+	noteRepo  repository.NoteRepository
+	userRepo  repository.UserRepository
+}
+
+// NewNoteShareService creates a new note share service
+func NewNoteShareService(
+	shareRepo repository.NoteShareRepository,
+	noteRepo repository.NoteRepository,
+	userRepo repository.UserRepository,
+) *NoteShareService {
+	return &NoteShareService{
+		shareRepo: shareRepo,
+		noteRepo:  noteRepo,
+		userRepo:  userRepo,
+	}
+}
+
+// Share shares a note of the owner with the user named by username or email
+// Sharing with a user the note is already shared with changes the permission. The bool is
+// true when the share was created.
+func (s *NoteShareService) Share(ctx context.Context, ownerID, noteID uuid.UUID, req *model.ShareNoteRequest) (*model.NoteShare, bool, error) {
+	if err := util.ValidateStruct(req); err != nil {
+		return nil, false, fmt.Errorf("%w: %w", model.ErrValidation, err)
+	}
+	if req.Permission == "" {
+		req.Permission = model.SharePermissionRead
+	}
+
+	note, err := s.noteRepo.FindByID(ctx, ownerID, noteID)
+	if err != nil {
+		return nil, false, fmt.Errorf("find note: %w", err)
+	}
+	// The passphrase never leaves the owner's devices, so nobody else could read it
+	if note.Encrypted {
+		return nil, false, fmt.Errorf("%w: encrypted notes can't be shared", model.ErrValidation)
+	}
+
+	user, err := s.findUser(ctx, strings.TrimSpace(req.User))
+	if err != nil {
+		return nil, false, err
+	}
+	if user.ID == ownerID {
+		return nil, false, fmt.Errorf("%w: you can't share a note with yourself", model.ErrValidation)
+	}
+
+	share := &model.NoteShare{
+		NoteID:     noteID,
+		OwnerID:    ownerID,
+		SharedWith: user.ID,
+		Username:   user.Username,
+		Email:      user.Email,
+		Permission: req.Permission,
+	}
+	created, err := s.shareRepo.Upsert(ctx, share)
+	if err != nil {
+		return nil, false, fmt.Errorf("share note: %w", err)
+	}
+
+	return share, created, nil
+}
+
+// findUser finds an active user by email, or else by username
+func (s *NoteShareService) findUser(ctx context.Context, name string) (*model.User, error) {
+	var user *model.User
+	var err error
+	if strings.Contains(name, "@") {
+		user, err = s.userRepo.FindByEmail(ctx, name)
+	} else {
+		user, err = s.userRepo.FindByUsername(ctx, name)
+	}
+	if repository.IsNotFound(err) || (err == nil && !user.IsActive) {
+		return nil, fmt.Errorf("%w: no user %q on this instance", model.ErrValidation, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find user: %w", err)
+	}
+
+	return user, nil
+}
+
+// List lists the users a note of the owner is shared with
+func (s *NoteShareService) List(ctx context.Context, ownerID, noteID uuid.UUID) ([]*model.NoteShare, error) {
+	if _, err := s.noteRepo.FindByID(ctx, ownerID, noteID); err != nil {
+		return nil, fmt.Errorf("find note: %w", err)
+	}
+
+	shares, err := s.shareRepo.ListByNote(ctx, ownerID, noteID)
+	if err != nil {
+		return nil, fmt.Errorf("list note shares: %w", err)
+	}
+
+	return shares, nil
+}
+
+// Unshare stops sharing a note of the owner with a user
+func (s *NoteShareService) Unshare(ctx context.Context, ownerID, noteID, userID uuid.UUID) error {
+	if err := s.shareRepo.Delete(ctx, ownerID, noteID, userID); err != nil {
+		return fmt.Errorf("delete note share: %w", err)
+	}
+	return nil
+}
+
+// SharedWithMe lists the notes other users share with the user
+func (s *NoteShareService) SharedWithMe(ctx context.Context, userID uuid.UUID) ([]*model.Note, error) {
+	notes, err := s.noteRepo.ListSharedWith(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list shared notes: %w", err)
+	}
+	return notes, nil
+}
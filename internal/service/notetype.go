@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+	"github.com/momokii/go-cli-notes/internal/repository"
+	"github.com/momokii/go-cli-notes/internal/util"
+)
+
+// noteTypeNamePattern limits custom note type names to what fits in a query string or a flag
+var noteTypeNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// ListNoteTypes lists the built-in note types followed by the user's custom types
+func (s *NoteService) ListNoteTypes(ctx context.Context, userID uuid.UUID) ([]*model.NoteTypeDefinition, error) {
+	custom, err := s.noteTypeRepo.List(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list note types: %w", err)
+	}
+
+	counts, err := s.noteTypeRepo.CountNotes(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list note types: %w", err)
+	}
+
+	fields, err := s.noteTypeRepo.ListFields(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list note types: %w", err)
+	}
+
+	noteTypes := make([]*model.NoteTypeDefinition, 0, len(model.BuiltInNoteTypes)+len(custom))
+	for _, builtIn := range model.BuiltInNoteTypes {
+		noteType := builtIn
+		noteTypes = append(noteTypes, &noteType)
+	}
+	noteTypes = append(noteTypes, custom...)
+
+	for _, noteType := range noteTypes {
+		noteType.NoteCount = counts[noteType.Name]
+		noteType.Fields = fields[noteType.Name]
+	}
+
+	return noteTypes, nil
+}
+
+// CreateNoteType defines a custom note type
+func (s *NoteService) CreateNoteType(ctx context.Context, userID uuid.UUID, req *model.CreateNoteTypeRequest) (*model.NoteTypeDefinition, error) {
+	if err := util.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("%w: %w", model.ErrValidation, err)
+	}
+
+	name := model.NoteType(strings.ToLower(strings.TrimSpace(req.Name)))
+	if !noteTypeNamePattern.MatchString(string(name)) {
+		return nil, fmt.Errorf("%w: note type names use lowercase letters, digits, - and _", model.ErrValidation)
+	}
+
+	if name.IsBuiltIn() {
+		return nil, fmt.Errorf("%w: note type %q", model.ErrDuplicate, name)
+	}
+	if _, err := s.noteTypeRepo.Find(ctx, userID, name); err == nil {
+		return nil, fmt.Errorf("%w: note type %q", model.ErrDuplicate, name)
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("find note type: %w", err)
+	}
+
+	noteType := &model.NoteTypeDefinition{
+		Name:  name,
+		Icon:  strings.TrimSpace(req.Icon),
+		Color: req.Color,
+	}
+	if err := s.noteTypeRepo.Create(ctx, userID, noteType); err != nil {
+		return nil, fmt.Errorf("create note type: %w", err)
+	}
+
+	return noteType, nil
+}
+
+// UpdateNoteType changes the icon or color of a custom note type
+func (s *NoteService) UpdateNoteType(ctx context.Context, userID uuid.UUID, name model.NoteType, req *model.UpdateNoteTypeRequest) (*model.NoteTypeDefinition, error) {
+	if err := util.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("%w: %w", model.ErrValidation, err)
+	}
+	if name.IsBuiltIn() {
+		return nil, fmt.Errorf("%w: built-in note types can't be changed", model.ErrValidation)
+	}
+
+	noteType, err := s.noteTypeRepo.Find(ctx, userID, name)
+	if err != nil {
+		return nil, fmt.Errorf("find note type: %w", err)
+	}
+
+	if req.Icon != nil {
+		noteType.Icon = strings.TrimSpace(*req.Icon)
+	}
+	if req.Color != nil {
+		noteType.Color = req.Color
+	}
+
+	if err := s.noteTypeRepo.Update(ctx, userID, noteType); err != nil {
+		return nil, fmt.Errorf("update note type: %w", err)
+	}
+
+	return noteType, nil
+}
+
+// DeleteNoteType deletes a custom note type
+// Types still given to notes, or used as the default type, are kept.
+func (s *NoteService) DeleteNoteType(ctx context.Context, userID uuid.UUID, name model.NoteType) error {
+	if name.IsBuiltIn() {
+		return fmt.Errorf("%w: built-in note types can't be deleted", model.ErrValidation)
+	}
+
+	noteType, err := s.noteTypeRepo.Find(ctx, userID, name)
+	if err != nil {
+		return fmt.Errorf("find note type: %w", err)
+	}
+	if noteType.NoteCount > 0 {
+		return fmt.Errorf("%w: %d note(s) are of type %q", model.ErrNoteTypeInUse, noteType.NoteCount, name)
+	}
+	if s.defaultNoteType(ctx, userID) == name {
+		return fmt.Errorf("%w: %q is the default note type", model.ErrNoteTypeInUse, name)
+	}
+
+	if err := s.noteTypeRepo.Delete(ctx, userID, name); err != nil {
+		return fmt.Errorf("delete note type: %w", err)
+	}
+	if err := s.noteTypeRepo.SetFields(ctx, userID, name, nil); err != nil {
+		return fmt.Errorf("delete note type fields: %w", err)
+	}
+
+	return nil
+}
+
+// SetNoteTypeFields replaces the custom fields of a note type, built-in or custom
+// Notes already of the type are checked against the fields the next time their type or metadata changes.
+func (s *NoteService) SetNoteTypeFields(ctx context.Context, userID uuid.UUID, name model.NoteType, req *model.SetNoteTypeFieldsRequest) (*model.NoteTypeDefinition, error) {
+	noteType, err := s.findNoteType(ctx, userID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateNoteTypeFields(req.Fields); err != nil {
+		return nil, err
+	}
+	if err := s.noteTypeRepo.SetFields(ctx, userID, name, req.Fields); err != nil {
+		return nil, fmt.Errorf("set note type fields: %w", err)
+	}
+
+	noteType.Fields = req.Fields
+	return noteType, nil
+}
+
+// findNoteType returns a note type the user has, built-in or custom
+func (s *NoteService) findNoteType(ctx context.Context, userID uuid.UUID, name model.NoteType) (*model.NoteTypeDefinition, error) {
+	for _, builtIn := range model.BuiltInNoteTypes {
+		if builtIn.Name == name {
+			noteType := builtIn
+			return &noteType, nil
+		}
+	}
+
+	noteType, err := s.noteTypeRepo.Find(ctx, userID, name)
+	if err != nil {
+		return nil, fmt.Errorf("find note type: %w", err)
+	}
+	return noteType, nil
+}
+
+// validateNoteTypeFields checks the custom fields of a note type, trimming their names
+func validateNoteTypeFields(fields []*model.NoteTypeField) error {
+	names := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		if field == nil {
+			return fmt.Errorf("%w: fields can't be null", model.ErrValidation)
+		}
+
+		field.Name = strings.TrimSpace(field.Name)
+		switch field.Name {
+		case "":
+			return fmt.Errorf("%w: field names can't be empty", model.ErrValidation)
+		case model.MetadataSummary, model.MetadataStatus, model.MetadataReadProgress:
+			return fmt.Errorf("%w: metadata key %q is reserved", model.ErrValidation, field.Name)
+		}
+		if names[field.Name] {
+			return fmt.Errorf("%w: field %q is defined twice", model.ErrValidation, field.Name)
+		}
+		names[field.Name] = true
+
+		if !field.Type.IsValid() {
+			return fmt.Errorf("%w: field %q must have type text, number, date, boolean or list", model.ErrValidation, field.Name)
+		}
+		if field.Type != model.NoteFieldNumber && (field.Min != nil || field.Max != nil) {
+			return fmt.Errorf("%w: only number fields can have min and max", model.ErrValidation)
+		}
+		if field.Min != nil && field.Max != nil && *field.Min > *field.Max {
+			return fmt.Errorf("%w: min of field %q is above its max", model.ErrValidation, field.Name)
+		}
+	}
+	return nil
+}
+
+// noteTypeFields returns every note type the user has, each with its custom fields
+func (s *NoteService) noteTypeFields(ctx context.Context, userID uuid.UUID) (map[model.NoteType][]*model.NoteTypeField, error) {
+	custom, err := s.noteTypeRepo.List(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list note types: %w", err)
+	}
+
+	fields, err := s.noteTypeRepo.ListFields(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list note types: %w", err)
+	}
+
+	noteTypes := make(map[model.NoteType][]*model.NoteTypeField, len(model.BuiltInNoteTypes)+len(custom))
+	for _, builtIn := range model.BuiltInNoteTypes {
+		noteTypes[builtIn.Name] = fields[builtIn.Name]
+	}
+	for _, noteType := range custom {
+		noteTypes[noteType.Name] = fields[noteType.Name]
+	}
+	return noteTypes, nil
+}
+
+// checkNoteFields checks a note's metadata against the custom fields of its type
+func checkNoteFields(note *model.Note, fields []*model.NoteTypeField) error {
+	if err := note.Metadata.CheckFields(fields); err != nil {
+		return fmt.Errorf("%w: %s note: %w", model.ErrValidation, note.NoteType, err)
+	}
+	return nil
+}
+
+// checkNoteType returns a validation error unless the user has the note type
+func (s *NoteService) checkNoteType(ctx context.Context, userID uuid.UUID, noteType model.NoteType) error {
+	if noteType.IsBuiltIn() {
+		return nil
+	}
+
+	_, err := s.noteTypeRepo.Find(ctx, userID, noteType)
+	if errors.Is(err, repository.ErrNotFound) {
+		return unknownNoteTypeError(noteType)
+	}
+	if err != nil {
+		return fmt.Errorf("find note type: %w", err)
+	}
+	return nil
+}
+
+// unknownNoteTypeError reports a note type the user doesn't have
+func unknownNoteTypeError(noteType model.NoteType) error {
+	return fmt.Errorf("%w: unknown note type %q, create it with POST /api/v1/note-types first", model.ErrValidation, noteType)
+}
@@ -0,0 +1,263 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	gmutil "github.com/yuin/goldmark/util"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+	"github.com/momokii/go-cli-notes/internal/repository"
+	"github.com/momokii/go-cli-notes/internal/util"
+)
+
+// publicMarkdown renders published notes; raw HTML in notes is left out of the page
+// Headings and blocks ending in " ^block-id" get ids, so [[Note#Heading]] and [[Note^block-id]] links land on them.
+var publicMarkdown = goldmark.New(
+	goldmark.WithExtensions(extension.GFM),
+	goldmark.WithParserOptions(
+		parser.WithAutoHeadingID(),
+		parser.WithASTTransformers(gmutil.Prioritized(blockIDTransformer{}, 500)),
+	),
+)
+
+// blockIDTransformer moves the " ^block-id" ending a paragraph or list item into the block's id
+type blockIDTransformer struct{}
+
+// Transform implements parser.ASTTransformer
+func (blockIDTransformer) Transform(doc *ast.Document, reader text.Reader, _ parser.Context) {
+	source := reader.Source()
+	_ = ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering || (node.Kind() != ast.KindParagraph && node.Kind() != ast.KindTextBlock) {
+			return ast.WalkContinue, nil
+		}
+		last, ok := node.LastChild().(*ast.Text)
+		if !ok {
+			return ast.WalkContinue, nil
+		}
+
+		value := string(last.Segment.Value(source))
+		rest, id := util.BlockID(value)
+		if id == "" {
+			return ast.WalkContinue, nil
+		}
+		last.Segment = last.Segment.WithStop(last.Segment.Start + len(rest))
+
+		// A tight list item's text has no element of its own, the item gets the id
+		target := node
+		if node.Kind() == ast.KindTextBlock && node.Parent() != nil {
+			target = node.Parent()
+		}
+		target.SetAttributeString("id", []byte("^"+id))
+		return ast.WalkSkipChildren, nil
+	})
+}
+
+// PublicLinkService publishes notes at public, read-only links
+type PublicLinkService struct {
+	publicLinkRepo repository.PublicLinkRepository
+	noteRepo       repository.NoteRepository
+	linkRepo       repository.LinkRepository
+	linkParser     *util.LinkParser
+	signer         *util.LinkSigner
+	publicURL      string
+}
+
+// NewPublicLinkService creates a new public link service
+// Links are built on publicURL, or on the address of the request when it is empty.
+func NewPublicLinkService(
+	publicLinkRepo repository.PublicLinkRepository,
+	noteRepo repository.NoteRepository,
+	linkRepo repository.LinkRepository,
+	linkParser *util.LinkParser,
+	signer *util.LinkSigner,
+	publicURL string,
+) *PublicLinkService {
+	return &PublicLinkService{
+		publicLinkRepo: publicLinkRepo,
+		noteRepo:       noteRepo,
+		linkRepo:       linkRepo,
+		linkParser:     linkParser,
+		signer:         signer,
+		publicURL:      strings.TrimSuffix(publicURL, "/"),
+	}
+}
+
+// Publish publishes a note, returning its public link and whether it was just created
+// Publishing a note that is already published returns the existing link.
+func (s *PublicLinkService) Publish(ctx context.Context, userID, noteID uuid.UUID, requestURL string) (*model.PublicLink, bool, error) {
+	note, err := s.noteRepo.FindByID(ctx, userID, noteID)
+	if err != nil {
+		return nil, false, fmt.Errorf("note not found: %w", err)
+	}
+	if note.Encrypted {
+		return nil, false, fmt.Errorf("%w: encrypted notes can't be published", model.ErrValidation)
+	}
+
+	publicLink, err := s.publicLinkRepo.FindByNote(ctx, userID, noteID)
+	if err == nil {
+		s.setURL(publicLink, requestURL)
+		return publicLink, false, nil
+	}
+	if !repository.IsNotFound(err) {
+		return nil, false, fmt.Errorf("find public link: %w", err)
+	}
+
+	publicLink = &model.PublicLink{UserID: userID, NoteID: noteID, Title: note.Title}
+	if err := s.publicLinkRepo.Create(ctx, publicLink); err != nil {
+		return nil, false, fmt.Errorf("create public link: %w", err)
+	}
+
+	s.setURL(publicLink, requestURL)
+	return publicLink, true, nil
+}
+
+// Unpublish revokes the public link of a note
+func (s *PublicLinkService) Unpublish(ctx context.Context, userID, noteID uuid.UUID) error {
+	if err := s.publicLinkRepo.DeleteByNote(ctx, userID, noteID); err != nil {
+		return fmt.Errorf("delete public link: %w", err)
+	}
+	return nil
+}
+
+// List lists the public links of a user
+func (s *PublicLinkService) List(ctx context.Context, userID uuid.UUID, requestURL string) ([]*model.PublicLink, error) {
+	published, err := s.publicLinkRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list public links: %w", err)
+	}
+
+	for _, publicLink := range published {
+		s.setURL(publicLink, requestURL)
+	}
+	return published, nil
+}
+
+// Page renders the published note behind a public link token
+// Wiki links to other published notes of the same user point at their pages; links to
+// notes that aren't published are kept as plain text, so private titles stay hidden.
+// ![[...]] embeds of published notes are expanded inline.
+func (s *PublicLinkService) Page(ctx context.Context, token string) (*model.PublicNotePage, error) {
+	// Tampered links look the same as revoked ones
+	id, err := s.signer.Verify(token)
+	if err != nil {
+		return nil, repository.ErrNotFound
+	}
+
+	publicLink, err := s.publicLinkRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("find public link: %w", err)
+	}
+
+	note, err := s.noteRepo.FindByID(ctx, publicLink.UserID, publicLink.NoteID)
+	if err != nil {
+		return nil, fmt.Errorf("find note: %w", err)
+	}
+	// Encrypted after it was published: the server can't read it
+	if note.Encrypted {
+		return nil, repository.ErrNotFound
+	}
+
+	published, err := s.publicLinkRepo.ListByUser(ctx, publicLink.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("list public links: %w", err)
+	}
+	titles := util.NewTitleIndex()
+	byNote := make(map[uuid.UUID]*model.PublicLink, len(published))
+	for i, other := range published {
+		titles.Add(other.Title, i)
+		byNote[other.NoteID] = other
+	}
+	byTitle := func(title string) (*model.PublicLink, bool) {
+		i, ok := titles.Find(title)
+		if !ok {
+			return nil, false
+		}
+		return published[i], true
+	}
+
+	// Only published notes are embedded, like links the rest of the vault stays private
+	var findErr error
+	content := s.linkParser.ExpandEmbeds(note.Title, note.Content, noteEmbedMaxDepth, func(title string) (string, bool) {
+		other, ok := byTitle(title)
+		if !ok || findErr != nil {
+			return "", false
+		}
+		embedded, err := s.noteRepo.FindByID(ctx, publicLink.UserID, other.NoteID)
+		if err != nil {
+			if err != repository.ErrNotFound {
+				findErr = err
+			}
+			return "", false
+		}
+		return embedded.Content, !embedded.Encrypted
+	})
+	if findErr != nil {
+		return nil, fmt.Errorf("expand embeds: %w", findErr)
+	}
+
+	content = s.linkParser.ReplaceLinks(content, func(link *util.Link) string {
+		label := strings.NewReplacer("[", `\[`, "]", `\]`).Replace(link.Display)
+		if link.Title == "" {
+			return "[" + label + "](" + link.Anchor() + ")"
+		}
+		target, ok := byTitle(link.Title)
+		if !ok {
+			return link.Display
+		}
+		return "[" + label + "](" + s.path(target) + link.Anchor() + ")"
+	})
+
+	var html bytes.Buffer
+	if err := publicMarkdown.Convert([]byte(content), &html); err != nil {
+		return nil, fmt.Errorf("render note: %w", err)
+	}
+
+	links, err := s.linkRepo.GetByTarget(ctx, publicLink.UserID, note.ID)
+	if err != nil {
+		return nil, fmt.Errorf("get backlinks: %w", err)
+	}
+	backlinks := []*model.PublicNoteLink{}
+	seen := make(map[uuid.UUID]bool)
+	for _, link := range links {
+		source, ok := byNote[link.SourceNoteID]
+		if !ok || source.NoteID == note.ID || seen[source.NoteID] {
+			continue
+		}
+		seen[source.NoteID] = true
+		backlinks = append(backlinks, &model.PublicNoteLink{Title: source.Title, Path: s.path(source)})
+	}
+	sort.Slice(backlinks, func(i, j int) bool {
+		return strings.ToLower(backlinks[i].Title) < strings.ToLower(backlinks[j].Title)
+	})
+
+	return &model.PublicNotePage{
+		Title:     note.Title,
+		HTML:      html.String(),
+		Backlinks: backlinks,
+		UpdatedAt: note.UpdatedAt,
+	}, nil
+}
+
+// path returns the path of a link's public page
+func (s *PublicLinkService) path(publicLink *model.PublicLink) string {
+	return "/s/" + s.signer.Sign(publicLink.ID)
+}
+
+// setURL sets the URL of a public link
+func (s *PublicLinkService) setURL(publicLink *model.PublicLink, requestURL string) {
+	base := s.publicURL
+	if base == "" {
+		base = strings.TrimSuffix(requestURL, "/")
+	}
+	publicLink.URL = base + s.path(publicLink)
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+	"github.com/momokii/go-cli-notes/internal/repository"
+	"github.com/momokii/go-cli-notes/internal/util"
+)
+
+// Weekly review limits
+const (
+	weeklyReviewListLimit      = 20 // Entries per section
+	weeklyReviewForgottenLimit = 5
+)
+
+// weeklyReviewTitlePrefix starts the title of every weekly review, followed by the first day of its week
+const weeklyReviewTitlePrefix = "Weekly Review - "
+
+// weeklyReviewReflection heads the last section of a review, left for the user to write
+const weeklyReviewReflection = "\n## Reflection\n"
+
+// CreateWeeklyReview writes a note summarizing the week of req.Date
+// Weeks start on the user's week_start day. Running it again for the same week
+// rewrites that week's review note instead of adding another one, keeping its reflection.
+func (s *NoteService) CreateWeeklyReview(ctx context.Context, userID uuid.UUID, req *model.WeeklyReviewRequest) (*model.Note, *model.WeeklyReview, bool, error) {
+	if err := util.ValidateStruct(req); err != nil {
+		return nil, nil, false, fmt.Errorf("%w: %w", model.ErrValidation, err)
+	}
+
+	settings, err := s.settingsRepo.Get(ctx, userID)
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("get settings: %w", err)
+	}
+	loc := settings.Location()
+
+	day := time.Now().In(loc)
+	if req.Date != "" {
+		day, err = time.ParseInLocation(util.DailyDateLayout, req.Date, loc)
+		if err != nil {
+			return nil, nil, false, fmt.Errorf("%w: invalid date '%s'", model.ErrValidation, req.Date)
+		}
+	}
+
+	weekStart := time.Monday
+	if settings.WeekStart == "sunday" {
+		weekStart = time.Sunday
+	}
+	offset := (int(day.Weekday()) - int(weekStart) + 7) % 7
+	from := time.Date(day.Year(), day.Month(), day.Day()-offset, 0, 0, 0, 0, loc)
+	to := from.AddDate(0, 0, 7)
+
+	review, err := s.activityRepo.GetWeeklyReview(ctx, userID, from, to, weeklyReviewListLimit)
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("get weekly review: %w", err)
+	}
+	review.WeekStart = from.Format(util.DailyDateLayout)
+	review.WeekEnd = to.AddDate(0, 0, -1).Format(util.DailyDateLayout)
+
+	// Earlier reviews aren't part of what the user did this week
+	review.NotesCreated = withoutWeeklyReviews(review.NotesCreated)
+	review.NotesUpdated = withoutWeeklyReviews(review.NotesUpdated)
+
+	review.Forgotten, err = s.activityRepo.GetForgottenNotes(ctx, userID, settings.ForgottenDays, weeklyReviewForgottenLimit)
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("get forgotten notes: %w", err)
+	}
+
+	title := weeklyReviewTitlePrefix + review.WeekStart
+	content := renderWeeklyReview(review)
+
+	existing, err := s.noteRepo.FindByTitle(ctx, userID, title)
+	if err == nil {
+		if existing.Encrypted {
+			return nil, nil, false, fmt.Errorf("%w: the review of this week is encrypted and can't be rewritten", model.ErrValidation)
+		}
+		if _, reflection, ok := strings.Cut(existing.Content, weeklyReviewReflection); ok {
+			content += strings.TrimPrefix(reflection, "\n")
+		}
+
+		note, err := s.Update(ctx, userID, existing.ID, &model.UpdateNoteRequest{Content: &content})
+		if err != nil {
+			return nil, nil, false, fmt.Errorf("update weekly review: %w", err)
+		}
+		return note, review, false, nil
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return nil, nil, false, fmt.Errorf("find weekly review: %w", err)
+	}
+
+	// The summary isn't writing of the user's own
+	note, err := s.create(ctx, userID, &model.CreateNoteRequest{
+		Title:    title,
+		Content:  content,
+		NoteType: model.NoteTypeNote,
+	})
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("create weekly review: %w", err)
+	}
+
+	return note, review, true, nil
+}
+
+// withoutWeeklyReviews drops weekly review notes from notes
+func withoutWeeklyReviews(notes []*model.Note) []*model.Note {
+	kept := notes[:0]
+	for _, note := range notes {
+		if !strings.HasPrefix(note.Title, weeklyReviewTitlePrefix) {
+			kept = append(kept, note)
+		}
+	}
+	return kept
+}
+
+// renderWeeklyReview writes the Markdown content of a weekly review note
+// Notes are wiki links so the review shows up in their backlinks. Completed
+// tasks are plain list items, as checkboxes they would become tasks of the review.
+func renderWeeklyReview(review *model.WeeklyReview) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Week of %s to %s\n", review.WeekStart, review.WeekEnd)
+
+	fmt.Fprintf(&b, "\n## Notes created (%d)\n", len(review.NotesCreated))
+	for _, note := range review.NotesCreated {
+		fmt.Fprintf(&b, "- [[%s]]\n", note.Title)
+	}
+	if len(review.NotesCreated) == 0 {
+		b.WriteString("- None\n")
+	}
+
+	fmt.Fprintf(&b, "\n## Notes updated (%d)\n", len(review.NotesUpdated))
+	for _, note := range review.NotesUpdated {
+		fmt.Fprintf(&b, "- [[%s]]\n", note.Title)
+	}
+	if len(review.NotesUpdated) == 0 {
+		b.WriteString("- None\n")
+	}
+
+	b.WriteString("\n## Top tags\n")
+	for _, tag := range review.TopTags {
+		fmt.Fprintf(&b, "- #%s (%d notes)\n", tag.Name, tag.NoteCount)
+	}
+	if len(review.TopTags) == 0 {
+		b.WriteString("- None\n")
+	}
+
+	fmt.Fprintf(&b, "\n## Completed tasks (%d)\n", len(review.CompletedTasks))
+	for _, task := range review.CompletedTasks {
+		fmt.Fprintf(&b, "- %s (in [[%s]])\n", task.Content, task.NoteTitle)
+	}
+	if len(review.CompletedTasks) == 0 {
+		b.WriteString("- None\n")
+	}
+
+	b.WriteString("\n## Resurfaced\n")
+	for _, forgotten := range review.Forgotten {
+		fmt.Fprintf(&b, "- [[%s]] (not opened for %d days)\n", forgotten.Note.Title, forgotten.DaysSinceAccess)
+	}
+	if len(review.Forgotten) == 0 {
+		b.WriteString("- None\n")
+	}
+
+	b.WriteString(weeklyReviewReflection + "\n")
+
+	return b.String()
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/momokii/go-cli-notes/internal/events"
+	"github.com/momokii/go-cli-notes/internal/llm"
+	"github.com/momokii/go-cli-notes/internal/model"
+	"github.com/momokii/go-cli-notes/internal/repository"
+)
+
+// maxSummaryInputChars caps the note text sent to the model
+// It keeps long notes within the context window of small local models.
+const maxSummaryInputChars = 24000
+
+// summarySystemPrompt instructs the model how to summarize a note
+const summarySystemPrompt = `You write short TL;DR summaries of personal notes.
+Summarize the note in at most 3 sentences or 5 bullet points, whichever reads better.
+Keep decisions, action items and owners. Answer in the language of the note.
+Reply with the summary only, without a heading or preamble.`
+
+// SummaryService generates note summaries with an LLM provider
+type SummaryService struct {
+	noteRepo repository.NoteRepository
+	provider llm.Provider // nil = summarization disabled
+	broker   *events.Broker
+}
+
+// NewSummaryService creates a new summary service
+// A nil provider disables summarization.
+func NewSummaryService(
+	noteRepo repository.NoteRepository,
+	provider llm.Provider,
+	broker *events.Broker,
+) *SummaryService {
+	return &SummaryService{
+		noteRepo: noteRepo,
+		provider: provider,
+		broker:   broker,
+	}
+}
+
+// Enabled reports whether summarization is available
+func (s *SummaryService) Enabled() bool {
+	return s.provider != nil
+}
+
+// Summarize generates a summary of a note and stores it in the note's metadata
+// An earlier summary is replaced.
+func (s *SummaryService) Summarize(ctx context.Context, userID, noteID uuid.UUID) (*model.NoteSummary, error) {
+	if !s.Enabled() {
+		return nil, model.ErrSummarizationDisabled
+	}
+
+	note, err := s.noteRepo.FindByID(ctx, userID, noteID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The server only ever sees the ciphertext of encrypted notes
+	if note.Encrypted {
+		return nil, fmt.Errorf("%w: encrypted notes can't be summarized", model.ErrValidation)
+	}
+	if strings.TrimSpace(note.Content) == "" {
+		return nil, fmt.Errorf("%w: note has no content to summarize", model.ErrValidation)
+	}
+
+	text, err := s.provider.Complete(ctx, summarySystemPrompt, summaryPrompt(note))
+	if err != nil {
+		return nil, fmt.Errorf("generate summary: %w", err)
+	}
+	if text == "" {
+		return nil, fmt.Errorf("generate summary: model returned an empty summary")
+	}
+
+	summary := &model.NoteSummary{
+		Summary:     text,
+		Model:       s.provider.Model(),
+		GeneratedAt: time.Now().UTC(),
+	}
+
+	if err := s.noteRepo.SetMetadata(ctx, userID, noteID, model.MetadataSummary, summary); err != nil {
+		return nil, fmt.Errorf("store summary: %w", err)
+	}
+
+	s.broker.Publish(userID, model.Event{Type: model.EventNoteUpdated, NoteID: &noteID})
+
+	return summary, nil
+}
+
+// summaryPrompt returns the prompt asking for a summary of note
+func summaryPrompt(note *model.Note) string {
+	content := note.Content
+	if runes := []rune(content); len(runes) > maxSummaryInputChars {
+		content = string(runes[:maxSummaryInputChars])
+	}
+	return fmt.Sprintf("Title: %s\n\n%s", note.Title, content)
+}
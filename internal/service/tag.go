@@ -3,9 +3,12 @@ package service
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 
+	"github.com/momokii/go-cli-notes/internal/events"
 	"github.com/momokii/go-cli-notes/internal/model"
 	"github.com/momokii/go-cli-notes/internal/repository"
 	"github.com/momokii/go-cli-notes/internal/util"
@@ -16,6 +19,8 @@ type TagService struct {
 	tagRepo     repository.TagRepository
 	noteRepo    repository.NoteRepository
 	activityRepo repository.ActivityRepository
+	settingsRepo repository.SettingsRepository
+	broker       *events.Broker
 }
 
 // NewTagService creates a new tag service
@@ -23,11 +28,15 @@ func NewTagService(
 	tagRepo repository.TagRepository,
 	noteRepo repository.NoteRepository,
 	activityRepo repository.ActivityRepository,
+	settingsRepo repository.SettingsRepository,
+	broker *events.Broker,
 ) *TagService {
 	return &TagService{
 		tagRepo:     tagRepo,
 		noteRepo:    noteRepo,
 		activityRepo: activityRepo,
+		settingsRepo: settingsRepo,
+		broker:       broker,
 	}
 }
 
@@ -35,28 +44,55 @@ func NewTagService(
 func (s *TagService) Create(ctx context.Context, userID uuid.UUID, req *model.CreateTagRequest) (*model.Tag, error) {
 	// Validate request
 	if err := util.ValidateStruct(req); err != nil {
-		return nil, fmt.Errorf("%w: %s", model.ErrValidation, err)
+		return nil, fmt.Errorf("%w: %w", model.ErrValidation, err)
+	}
+
+	name := normalizeTagName(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: tag name is required", model.ErrValidation)
 	}
 
 	// Check if tag already exists
-	_, err := s.tagRepo.FindByName(ctx, userID, req.Name)
+	_, err := s.tagRepo.FindByName(ctx, userID, name)
 	if err == nil {
-		return nil, fmt.Errorf("tag with name '%s' already exists", req.Name)
+		return nil, fmt.Errorf("tag with name '%s' already exists", name)
+	}
+
+	// Nested tags ("parent/child") hang off their parent, which is created if missing
+	parentID, err := s.ensureParent(ctx, userID, name)
+	if err != nil {
+		return nil, err
 	}
 
 	tag := &model.Tag{
-		UserID: userID,
-		Name:   req.Name,
-		Color:  req.Color,
+		UserID:   userID,
+		Name:     name,
+		Color:    req.Color,
+		ParentID: parentID,
 	}
 
 	if err := s.tagRepo.Create(ctx, tag); err != nil {
 		return nil, fmt.Errorf("create tag: %w", err)
 	}
 
+	s.broker.Publish(userID, model.Event{Type: model.EventTagCreated, TagID: &tag.ID})
+
 	return tag, nil
 }
 
+// Ensure returns the tag with the given name, creating it if needed
+func (s *TagService) Ensure(ctx context.Context, userID uuid.UUID, name string) (*model.Tag, error) {
+	tag, err := s.tagRepo.FindByName(ctx, userID, normalizeTagName(name))
+	if err == nil {
+		return tag, nil
+	}
+	if err != repository.ErrNotFound {
+		return nil, fmt.Errorf("find tag: %w", err)
+	}
+
+	return s.Create(ctx, userID, &model.CreateTagRequest{Name: name})
+}
+
 // GetByID gets a tag by ID
 func (s *TagService) GetByID(ctx context.Context, userID, tagID uuid.UUID) (*model.Tag, error) {
 	tag, err := s.tagRepo.FindByID(ctx, userID, tagID)
@@ -79,7 +115,7 @@ func (s *TagService) List(ctx context.Context, userID uuid.UUID, page, limit int
 func (s *TagService) Update(ctx context.Context, userID, tagID uuid.UUID, req *model.UpdateTagRequest) (*model.Tag, error) {
 	// Validate request
 	if err := util.ValidateStruct(req); err != nil {
-		return nil, fmt.Errorf("%w: %s", model.ErrValidation, err)
+		return nil, fmt.Errorf("%w: %w", model.ErrValidation, err)
 	}
 
 	// Get existing tag
@@ -89,12 +125,28 @@ func (s *TagService) Update(ctx context.Context, userID, tagID uuid.UUID, req *m
 	}
 
 	// Check name uniqueness if updating name
-	if req.Name != nil && *req.Name != tag.Name {
-		existing, _ := s.tagRepo.FindByName(ctx, userID, *req.Name)
+	oldName := tag.Name
+	if req.Name != nil && normalizeTagName(*req.Name) != tag.Name {
+		name := normalizeTagName(*req.Name)
+		if name == "" {
+			return nil, fmt.Errorf("%w: tag name is required", model.ErrValidation)
+		}
+		if strings.HasPrefix(name, tag.Name+model.TagSeparator) {
+			return nil, fmt.Errorf("%w: a tag cannot be moved under itself", model.ErrValidation)
+		}
+
+		existing, _ := s.tagRepo.FindByName(ctx, userID, name)
 		if existing != nil {
-			return nil, fmt.Errorf("tag with name '%s' already exists", *req.Name)
+			return nil, fmt.Errorf("tag with name '%s' already exists", name)
+		}
+
+		// Renaming can move the tag to a different parent
+		parentID, err := s.ensureParent(ctx, userID, name)
+		if err != nil {
+			return nil, err
 		}
-		tag.Name = *req.Name
+		tag.Name = name
+		tag.ParentID = parentID
 	}
 
 	if req.Color != nil {
@@ -105,6 +157,15 @@ func (s *TagService) Update(ctx context.Context, userID, tagID uuid.UUID, req *m
 		return nil, fmt.Errorf("update tag: %w", err)
 	}
 
+	// Keep child names in sync with the new path
+	if tag.Name != oldName {
+		if err := s.tagRepo.RenameDescendants(ctx, userID, oldName, tag.Name); err != nil {
+			return nil, fmt.Errorf("update tag: %w", err)
+		}
+	}
+
+	s.broker.Publish(userID, model.Event{Type: model.EventTagUpdated, TagID: &tag.ID})
+
 	return tag, nil
 }
 
@@ -113,6 +174,9 @@ func (s *TagService) Delete(ctx context.Context, userID, tagID uuid.UUID) error
 	if err := s.tagRepo.Delete(ctx, userID, tagID); err != nil {
 		return fmt.Errorf("delete tag: %w", err)
 	}
+
+	s.broker.Publish(userID, model.Event{Type: model.EventTagDeleted, TagID: &tagID})
+
 	return nil
 }
 
@@ -134,6 +198,8 @@ func (s *TagService) AddToNote(ctx context.Context, userID, noteID, tagID uuid.U
 		return fmt.Errorf("add tag to note: %w", err)
 	}
 
+	s.broker.Publish(userID, model.Event{Type: model.EventNoteUpdated, NoteID: &noteID, TagID: &tagID})
+
 	return nil
 }
 
@@ -149,9 +215,53 @@ func (s *TagService) RemoveFromNote(ctx context.Context, userID, noteID, tagID u
 		return fmt.Errorf("remove tag from note: %w", err)
 	}
 
+	s.broker.Publish(userID, model.Event{Type: model.EventNoteUpdated, NoteID: &noteID, TagID: &tagID})
+
 	return nil
 }
 
+// BulkUpdateNotes adds or removes a tag on many notes at once
+func (s *TagService) BulkUpdateNotes(ctx context.Context, userID uuid.UUID, req *model.BulkTagRequest) (*model.BulkTagResponse, error) {
+	if err := util.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("%w: %w", model.ErrValidation, err)
+	}
+
+	tagID, err := uuid.Parse(req.TagID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid tag ID", model.ErrValidation)
+	}
+
+	// Verify tag ownership
+	if _, err := s.tagRepo.FindByID(ctx, userID, tagID); err != nil {
+		return nil, fmt.Errorf("tag not found: %w", err)
+	}
+
+	// Duplicate IDs would make the ownership check miscount
+	noteIDs := make([]uuid.UUID, 0, len(req.NoteIDs))
+	seen := make(map[uuid.UUID]bool, len(req.NoteIDs))
+	for _, idStr := range req.NoteIDs {
+		id, err := uuid.Parse(idStr)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid note ID %s", model.ErrValidation, idStr)
+		}
+		if !seen[id] {
+			seen[id] = true
+			noteIDs = append(noteIDs, id)
+		}
+	}
+
+	updated, err := s.tagRepo.BulkUpdateNotes(ctx, userID, tagID, noteIDs, req.Action == model.BulkTagAdd)
+	if err != nil {
+		return nil, fmt.Errorf("bulk update note tags: %w", err)
+	}
+
+	for _, noteID := range noteIDs {
+		s.broker.Publish(userID, model.Event{Type: model.EventNoteUpdated, NoteID: &noteID, TagID: &tagID})
+	}
+
+	return &model.BulkTagResponse{Updated: updated}, nil
+}
+
 // GetByNote gets all tags for a note
 func (s *TagService) GetByNote(ctx context.Context, userID, noteID uuid.UUID) ([]*model.Tag, error) {
 	// Verify note ownership
@@ -168,7 +278,7 @@ func (s *TagService) GetByNote(ctx context.Context, userID, noteID uuid.UUID) ([
 	return tags, nil
 }
 
-// GetNotesByTag gets all notes for a tag
+// GetNotesByTag gets all notes for a tag and its descendant tags
 func (s *TagService) GetNotesByTag(ctx context.Context, userID, tagID uuid.UUID) ([]*model.Note, error) {
 	// Verify tag ownership
 	_, err := s.tagRepo.FindByID(ctx, userID, tagID)
@@ -183,3 +293,127 @@ func (s *TagService) GetNotesByTag(ctx context.Context, userID, tagID uuid.UUID)
 
 	return notes, nil
 }
+
+// GetAnalytics reports how the user's tags were used over the last months months, in their
+// timezone, and the limit pairs of tags most often put on the same notes
+func (s *TagService) GetAnalytics(ctx context.Context, userID uuid.UUID, months, limit int) (*model.TagAnalytics, error) {
+	settings, err := s.settingsRepo.Get(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get settings: %w", err)
+	}
+	now := time.Now().In(settings.Location())
+	since := time.Date(now.Year(), now.Month()-time.Month(months-1), 1, 0, 0, 0, 0, now.Location())
+
+	analytics := &model.TagAnalytics{Months: make([]string, months)}
+	for i := range analytics.Months {
+		analytics.Months[i] = since.AddDate(0, i, 0).Format("2006-01")
+	}
+
+	analytics.Tags, err = s.tagRepo.ListUsage(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	monthly, err := s.tagRepo.CountTaggedByMonth(ctx, userID, settings.Timezone, since)
+	if err != nil {
+		return nil, err
+	}
+
+	noteCounts := make(map[uuid.UUID]int, len(analytics.Tags))
+	staleBefore := now.AddDate(0, 0, -model.TagStaleDays)
+	for _, usage := range analytics.Tags {
+		noteCounts[usage.Tag.ID] = usage.NoteCount
+		usage.Monthly = make([]int, months)
+		for i, month := range analytics.Months {
+			usage.Monthly[i] = monthly[usage.Tag.ID][month]
+		}
+		usage.Trend = tagTrend(usage, staleBefore)
+	}
+
+	analytics.Pairs, err = s.tagRepo.ListPairs(ctx, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	for _, pair := range analytics.Pairs {
+		if rarer := min(noteCounts[pair.TagA.ID], noteCounts[pair.TagB.ID]); rarer > 0 {
+			pair.Overlap = float64(pair.NoteCount) / float64(rarer)
+		}
+	}
+
+	return analytics, nil
+}
+
+// tagTrend tells whether a tag is growing, steady or stale from its monthly counts
+// A tag is growing when it was put on more notes in the recent half of the months than in the earlier half.
+func tagTrend(usage *model.TagStats, staleBefore time.Time) model.TagTrend {
+	if usage.LastTaggedAt == nil || usage.LastTaggedAt.Before(staleBefore) {
+		return model.TagStale
+	}
+
+	half := len(usage.Monthly) / 2
+	earlier, recent := 0, 0
+	for i, count := range usage.Monthly {
+		if i < half {
+			earlier += count
+		} else if i >= len(usage.Monthly)-half {
+			recent += count
+		}
+	}
+	if recent > earlier {
+		return model.TagGrowing
+	}
+	return model.TagSteady
+}
+
+// ensureParent returns the ID of the parent of a nested tag name, creating missing
+// ancestors along the way. Returns nil for top-level tags.
+func (s *TagService) ensureParent(ctx context.Context, userID uuid.UUID, name string) (*uuid.UUID, error) {
+	parentName := parentTagName(name)
+	if parentName == "" {
+		return nil, nil
+	}
+
+	parent, err := s.tagRepo.FindByName(ctx, userID, parentName)
+	if err == nil {
+		return &parent.ID, nil
+	}
+	if err != repository.ErrNotFound {
+		return nil, fmt.Errorf("find parent tag: %w", err)
+	}
+
+	grandparentID, err := s.ensureParent(ctx, userID, parentName)
+	if err != nil {
+		return nil, err
+	}
+
+	parent = &model.Tag{
+		UserID:   userID,
+		Name:     parentName,
+		ParentID: grandparentID,
+	}
+	if err := s.tagRepo.Create(ctx, parent); err != nil {
+		return nil, fmt.Errorf("create parent tag: %w", err)
+	}
+
+	return &parent.ID, nil
+}
+
+// normalizeTagName trims whitespace and drops empty levels from a nested tag name
+// e.g. " work / /project " becomes "work/project"
+func normalizeTagName(name string) string {
+	var parts []string
+	for _, part := range strings.Split(name, model.TagSeparator) {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, model.TagSeparator)
+}
+
+// parentTagName returns the parent path of a nested tag name ("" for top-level tags)
+func parentTagName(name string) string {
+	idx := strings.LastIndex(name, model.TagSeparator)
+	if idx < 0 {
+		return ""
+	}
+	return name[:idx]
+}
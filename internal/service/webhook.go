@@ -0,0 +1,416 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+	"github.com/momokii/go-cli-notes/internal/repository"
+	"github.com/momokii/go-cli-notes/internal/util"
+	"github.com/momokii/go-cli-notes/internal/webhook"
+)
+
+const (
+	// maxWebhooks is how many webhooks a user can register
+	maxWebhooks = 20
+	// webhookQueueSize is how many events can wait to be queued before new ones are dropped
+	webhookQueueSize = 256
+	// deliveryInterval is how often due deliveries are sent
+	deliveryInterval = 5 * time.Second
+	// deliveryBatchSize is how many deliveries are claimed at a time
+	deliveryBatchSize = 10
+	// deliveryRetention is how long finished deliveries are kept in the history
+	deliveryRetention = 30 * 24 * time.Hour
+	// maxRetryDelay caps the backoff between attempts
+	maxRetryDelay = 6 * time.Hour
+)
+
+// webhookEvent is an event published for a user, waiting to be queued for their webhooks
+type webhookEvent struct {
+	userID uuid.UUID
+	event  model.Event
+}
+
+// WebhookService manages webhooks and sends them the events they subscribe to
+// Events are queued as deliveries in the database and sent by Run, with retries.
+type WebhookService struct {
+	webhookRepo repository.WebhookRepository
+	noteRepo    repository.NoteRepository
+	tagRepo     repository.TagRepository
+	sender      *webhook.Sender
+	maxAttempts int
+	timeout     time.Duration
+	events      chan webhookEvent
+}
+
+// NewWebhookService creates a new webhook service
+// A delivery is marked failed after maxAttempts attempts of up to timeout each.
+func NewWebhookService(
+	webhookRepo repository.WebhookRepository,
+	noteRepo repository.NoteRepository,
+	tagRepo repository.TagRepository,
+	sender *webhook.Sender,
+	maxAttempts int,
+	timeout time.Duration,
+) *WebhookService {
+	return &WebhookService{
+		webhookRepo: webhookRepo,
+		noteRepo:    noteRepo,
+		tagRepo:     tagRepo,
+		sender:      sender,
+		maxAttempts: maxAttempts,
+		timeout:     timeout,
+		events:      make(chan webhookEvent, webhookQueueSize),
+	}
+}
+
+// Create registers a webhook, returning it with the secret its deliveries are signed with
+func (s *WebhookService) Create(ctx context.Context, userID uuid.UUID, req *model.CreateWebhookRequest) (*model.Webhook, error) {
+	if err := util.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("%w: %w", model.ErrValidation, err)
+	}
+
+	existing, err := s.webhookRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list webhooks: %w", err)
+	}
+	if len(existing) >= maxWebhooks {
+		return nil, fmt.Errorf("%w: at most %d webhooks can be registered", model.ErrValidation, maxWebhooks)
+	}
+
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	hook := &model.Webhook{
+		UserID: userID,
+		URL:    req.URL,
+		Events: uniqueStrings(req.Events),
+		Secret: secret,
+		Active: true,
+	}
+	if err := s.webhookRepo.Create(ctx, hook); err != nil {
+		return nil, fmt.Errorf("create webhook: %w", err)
+	}
+
+	return hook, nil
+}
+
+// List lists the webhooks of a user, without their secrets
+func (s *WebhookService) List(ctx context.Context, userID uuid.UUID) ([]*model.Webhook, error) {
+	hooks, err := s.webhookRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list webhooks: %w", err)
+	}
+	for _, hook := range hooks {
+		hook.Secret = ""
+	}
+	return hooks, nil
+}
+
+// Update changes the URL, events or active flag of a webhook
+func (s *WebhookService) Update(ctx context.Context, userID, id uuid.UUID, req *model.UpdateWebhookRequest) (*model.Webhook, error) {
+	if err := util.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("%w: %w", model.ErrValidation, err)
+	}
+
+	hook, err := s.webhookRepo.FindByID(ctx, userID, id)
+	if err != nil {
+		return nil, fmt.Errorf("find webhook: %w", err)
+	}
+
+	if req.URL != nil {
+		hook.URL = *req.URL
+	}
+	if req.Events != nil {
+		hook.Events = uniqueStrings(req.Events)
+	}
+	if req.Active != nil {
+		hook.Active = *req.Active
+	}
+
+	if err := s.webhookRepo.Update(ctx, hook); err != nil {
+		return nil, fmt.Errorf("update webhook: %w", err)
+	}
+
+	hook.Secret = ""
+	return hook, nil
+}
+
+// Delete deletes a webhook and its delivery history
+func (s *WebhookService) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	if err := s.webhookRepo.Delete(ctx, userID, id); err != nil {
+		return fmt.Errorf("delete webhook: %w", err)
+	}
+	return nil
+}
+
+// Deliveries lists the latest deliveries of a webhook of the user
+func (s *WebhookService) Deliveries(ctx context.Context, userID, id uuid.UUID, limit int) ([]*model.WebhookDelivery, error) {
+	if _, err := s.webhookRepo.FindByID(ctx, userID, id); err != nil {
+		return nil, fmt.Errorf("find webhook: %w", err)
+	}
+
+	deliveries, err := s.webhookRepo.ListDeliveries(ctx, id, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// Notify hands an event published for a user over to be queued for their webhooks
+// It never blocks: when the queue is full the event is dropped.
+func (s *WebhookService) Notify(userID uuid.UUID, event model.Event) {
+	if !isWebhookEvent(event.Type) {
+		return
+	}
+
+	select {
+	case s.events <- webhookEvent{userID: userID, event: event}:
+	default:
+		slog.Warn("Webhook queue full, event dropped", "user_id", userID, "event", event.Type)
+	}
+}
+
+// Run queues notified events and sends due deliveries until ctx is cancelled
+func (s *WebhookService) Run(ctx context.Context) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case e := <-s.events:
+				if err := s.enqueue(ctx, e.userID, e.event); err != nil && ctx.Err() == nil {
+					slog.Error("Failed to queue webhook deliveries", "user_id", e.userID, "event", e.event.Type, "error", err)
+				}
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(deliveryInterval)
+	defer ticker.Stop()
+	lastPrune := time.Time{}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		s.deliverDue(ctx)
+
+		if time.Since(lastPrune) >= time.Hour {
+			lastPrune = time.Now()
+			if removed, err := s.webhookRepo.DeleteDeliveriesBefore(ctx, time.Now().Add(-deliveryRetention)); err != nil {
+				slog.Warn("Failed to remove old webhook deliveries", "error", err)
+			} else if removed > 0 {
+				slog.Info("Removed old webhook deliveries", "count", removed)
+			}
+		}
+	}
+}
+
+// enqueue queues a delivery of an event for each webhook of the user subscribed to it
+func (s *WebhookService) enqueue(ctx context.Context, userID uuid.UUID, event model.Event) error {
+	hooks, err := s.webhookRepo.ListSubscribed(ctx, userID, event.Type)
+	if err != nil {
+		return fmt.Errorf("list subscribed webhooks: %w", err)
+	}
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(s.payload(ctx, userID, event))
+	if err != nil {
+		return fmt.Errorf("marshal webhook payload: %w", err)
+	}
+
+	ids := make([]uuid.UUID, len(hooks))
+	for i, hook := range hooks {
+		ids[i] = hook.ID
+	}
+
+	return s.webhookRepo.CreateDeliveries(ctx, ids, event.Type, payload)
+}
+
+// payload describes an event with the note or tag it is about, as they are now
+// Notes and tags deleted since the event only have their ID.
+func (s *WebhookService) payload(ctx context.Context, userID uuid.UUID, event model.Event) *model.WebhookPayload {
+	payload := &model.WebhookPayload{Event: event.Type, CreatedAt: event.CreatedAt}
+
+	if event.NoteID != nil {
+		payload.Note = &model.WebhookNote{ID: *event.NoteID}
+		if event.Type != model.EventNoteDeleted {
+			note, err := s.noteRepo.FindAccessible(ctx, userID, *event.NoteID, model.SharePermissionRead)
+			if err == nil {
+				payload.Note.Title = note.Title
+				payload.Note.NoteType = note.NoteType
+				payload.Note.Encrypted = note.Encrypted
+				payload.Note.UpdatedAt = &note.UpdatedAt
+				if !note.Encrypted {
+					payload.Note.Content = note.Content
+				}
+			} else if !repository.IsNotFound(err) {
+				slog.Warn("Failed to load note for webhook", "note_id", *event.NoteID, "error", err)
+			}
+		}
+	}
+
+	// The note is the subject of tag added and removed events, the tag is secondary
+	if event.TagID != nil {
+		payload.Tag = &model.WebhookTag{ID: *event.TagID}
+		if event.Type != model.EventTagDeleted {
+			tag, err := s.tagRepo.FindByID(ctx, userID, *event.TagID)
+			if err == nil {
+				payload.Tag.Name = tag.Name
+			} else if !repository.IsNotFound(err) {
+				slog.Warn("Failed to load tag for webhook", "tag_id", *event.TagID, "error", err)
+			}
+		}
+	}
+
+	payload.Text = webhookText(payload)
+	return payload
+}
+
+// webhookText summarizes a payload in one line, e.g. `New note: "Gardening Basics"`
+func webhookText(payload *model.WebhookPayload) string {
+	var note, tag string
+	if payload.Note != nil && payload.Note.Title != "" {
+		note = fmt.Sprintf(": %q", payload.Note.Title)
+	}
+	if payload.Tag != nil && payload.Tag.Name != "" {
+		tag = fmt.Sprintf(": %q", payload.Tag.Name)
+	}
+
+	switch payload.Event {
+	case model.EventNoteCreated:
+		return "New note" + note
+	case model.EventNoteUpdated:
+		if tag != "" {
+			return "Note tags changed" + note + " (#" + payload.Tag.Name + ")"
+		}
+		return "Note updated" + note
+	case model.EventNoteDeleted:
+		return "Note deleted"
+	case model.EventTagCreated:
+		return "New tag" + tag
+	case model.EventTagUpdated:
+		return "Tag updated" + tag
+	case model.EventTagDeleted:
+		return "Tag deleted"
+	default:
+		return string(payload.Event)
+	}
+}
+
+// deliverDue sends the deliveries that are due, one batch after another
+func (s *WebhookService) deliverDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		// Long enough to send the whole batch before another instance may claim it again
+		lease := time.Now().Add(deliveryBatchSize*s.timeout + time.Minute)
+		queued, err := s.webhookRepo.ClaimDue(ctx, deliveryBatchSize, lease)
+		if err != nil {
+			if ctx.Err() == nil {
+				slog.Error("Failed to claim webhook deliveries", "error", err)
+			}
+			return
+		}
+
+		for _, delivery := range queued {
+			s.attempt(ctx, delivery)
+		}
+		if len(queued) < deliveryBatchSize {
+			return
+		}
+	}
+}
+
+// attempt sends a delivery once and saves the outcome, scheduling a retry after a failure
+func (s *WebhookService) attempt(ctx context.Context, delivery *model.QueuedDelivery) {
+	status, err := s.sender.Send(ctx, delivery.URL, delivery.Secret, string(delivery.Event), delivery.ID.String(), delivery.Payload)
+	if ctx.Err() != nil {
+		return // Shutting down, the lease runs out and the delivery is sent again
+	}
+
+	now := time.Now()
+	d := &delivery.WebhookDelivery
+	d.Attempts++
+	d.LastAttemptAt = &now
+	d.ResponseStatus = nil
+	if status != 0 {
+		d.ResponseStatus = &status
+	}
+
+	switch {
+	case err == nil:
+		d.Status = model.DeliverySucceeded
+		d.Error = nil
+		d.NextAttemptAt = nil
+	case d.Attempts >= s.maxAttempts:
+		msg := err.Error()
+		d.Status = model.DeliveryFailed
+		d.Error = &msg
+		d.NextAttemptAt = nil
+	default:
+		msg := err.Error()
+		next := now.Add(retryDelay(d.Attempts))
+		d.Error = &msg
+		d.NextAttemptAt = &next
+	}
+
+	if err := s.webhookRepo.SaveAttempt(ctx, d); err != nil {
+		slog.Error("Failed to save webhook delivery", "delivery_id", d.ID, "error", err)
+	}
+}
+
+// retryDelay is how long to wait after a failed attempt: 1m, 3m, 9m... up to maxRetryDelay
+func retryDelay(attempts int) time.Duration {
+	delay := time.Minute
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 3
+	}
+	return min(delay, maxRetryDelay)
+}
+
+// isWebhookEvent reports whether webhooks can subscribe to an event type
+func isWebhookEvent(eventType model.EventType) bool {
+	for _, t := range model.WebhookEvents {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// uniqueStrings returns values without repeats, in their first order
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
+
+// generateWebhookSecret returns a random secret to sign deliveries with
+func generateWebhookSecret() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("generate webhook secret: %w", err)
+	}
+	return hex.EncodeToString(raw), nil
+}
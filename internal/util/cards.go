@@ -0,0 +1,67 @@
+package util
+
+import (
+	"regexp"
+	"strings"
+)
+
+// cardQuestionPattern matches the question line of a flashcard, "Q:: question", with the answer
+// after "A::" on the same line or starting on the next one
+var cardQuestionPattern = regexp.MustCompile(`^\s*Q::\s*(.*?)\s*(?:A::\s*(.*?))?\s*$`)
+
+// cardAnswerPattern matches an answer line following a question line
+var cardAnswerPattern = regexp.MustCompile(`^\s*A::\s*(.*?)\s*$`)
+
+// ParsedCard is a question and answer flashcard found in note content
+type ParsedCard struct {
+	Line     int // 1-based line number of the question
+	Question string
+	Answer   string
+}
+
+// ExtractCards returns the Q:: ... A:: ... flashcards in Markdown content, in document order
+// An answer on lines of its own runs until a blank line or the next question. Cards inside
+// fenced code blocks, without an answer, or repeating an earlier question are ignored.
+func ExtractCards(content string) []ParsedCard {
+	var cards []ParsedCard
+	seen := make(map[string]bool)
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	inCode := false
+
+	for i := 0; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			continue
+		}
+
+		m := cardQuestionPattern.FindStringSubmatch(lines[i])
+		if m == nil || m[1] == "" {
+			continue
+		}
+		card := ParsedCard{Line: i + 1, Question: m[1], Answer: m[2]}
+
+		if !strings.Contains(lines[i], "A::") && i+1 < len(lines) {
+			if a := cardAnswerPattern.FindStringSubmatch(lines[i+1]); a != nil {
+				i++
+				answer := []string{a[1]}
+				for i+1 < len(lines) && strings.TrimSpace(lines[i+1]) != "" && !cardQuestionPattern.MatchString(lines[i+1]) {
+					i++
+					answer = append(answer, strings.TrimSpace(lines[i]))
+				}
+				card.Answer = strings.TrimSpace(strings.Join(answer, "\n"))
+			}
+		}
+
+		if card.Answer == "" || seen[card.Question] {
+			continue
+		}
+		seen[card.Question] = true
+		cards = append(cards, card)
+	}
+
+	return cards
+}
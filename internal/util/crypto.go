@@ -0,0 +1,112 @@
+package util
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// EncryptedContentPrefix marks note content produced by EncryptContent
+// Format: kgenc:v1:base64(salt+nonce+ciphertext)
+const EncryptedContentPrefix = "kgenc:v1:"
+
+// Key derivation parameters (Argon2id, same cost as password hashing)
+const (
+	encSaltLen = 16
+	encTime    = 1
+	encMemory  = 64 * 1024 // 64 MB
+	encThreads = 4
+	encKeyLen  = 32 // AES-256
+)
+
+// ErrDecrypt is returned when content cannot be decrypted, usually because of a wrong passphrase
+var ErrDecrypt = errors.New("decryption failed: wrong passphrase or corrupted content")
+
+// IsEncryptedContent reports whether content looks like output of EncryptContent
+func IsEncryptedContent(content string) bool {
+	return strings.HasPrefix(content, EncryptedContentPrefix)
+}
+
+// EncryptContent encrypts plaintext with AES-256-GCM using a key derived from the passphrase
+// Every call uses a fresh salt and nonce, so the same input never encrypts to the same output.
+func EncryptContent(plaintext, passphrase string) (string, error) {
+	if passphrase == "" {
+		return "", fmt.Errorf("passphrase cannot be empty")
+	}
+
+	salt := make([]byte, encSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("generate salt: %w", err)
+	}
+
+	gcm, err := newContentCipher(passphrase, salt)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("generate nonce: %w", err)
+	}
+
+	combined := append(salt, nonce...)
+	combined = gcm.Seal(combined, nonce, []byte(plaintext), nil)
+
+	return EncryptedContentPrefix + base64.RawStdEncoding.EncodeToString(combined), nil
+}
+
+// DecryptContent reverses EncryptContent
+func DecryptContent(content, passphrase string) (string, error) {
+	if !IsEncryptedContent(content) {
+		return "", fmt.Errorf("content is not encrypted")
+	}
+
+	combined, err := base64.RawStdEncoding.DecodeString(strings.TrimSpace(strings.TrimPrefix(content, EncryptedContentPrefix)))
+	if err != nil {
+		return "", ErrDecrypt
+	}
+	if len(combined) < encSaltLen {
+		return "", ErrDecrypt
+	}
+
+	salt := combined[:encSaltLen]
+	gcm, err := newContentCipher(passphrase, salt)
+	if err != nil {
+		return "", err
+	}
+
+	rest := combined[encSaltLen:]
+	if len(rest) < gcm.NonceSize() {
+		return "", ErrDecrypt
+	}
+
+	plaintext, err := gcm.Open(nil, rest[:gcm.NonceSize()], rest[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", ErrDecrypt
+	}
+
+	return string(plaintext), nil
+}
+
+// newContentCipher derives the content key and returns an AES-GCM cipher for it
+func newContentCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key := argon2.IDKey([]byte(passphrase), salt, encTime, encMemory, encThreads, encKeyLen)
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("create cipher: %w", err)
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("create gcm: %w", err)
+	}
+
+	return gcm, nil
+}
@@ -0,0 +1,68 @@
+package util
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DailyDateLayout is the date format used in daily note titles
+const DailyDateLayout = "2006-01-02"
+
+// DailyMonthLayout is the format of a month of daily notes
+const DailyMonthLayout = "2006-01"
+
+// DailyTitlePrefix starts the title of every daily note, followed by its date
+const DailyTitlePrefix = "Daily Note - "
+
+// DefaultDailyTemplate is used for new daily notes until the user sets their own
+const DefaultDailyTemplate = `# {{weekday}}, {{date}}
+
+## Tasks
+- [ ] 
+
+## Notes
+`
+
+// ResolveDailyDate turns a daily note date expression into a YYYY-MM-DD date
+// Accepts "today", "yesterday", "tomorrow", day offsets like "+1" or "-3", and YYYY-MM-DD.
+func ResolveDailyDate(expr string, now time.Time) (string, error) {
+	expr = strings.ToLower(strings.TrimSpace(expr))
+
+	switch expr {
+	case "", "today":
+		return now.Format(DailyDateLayout), nil
+	case "yesterday":
+		return now.AddDate(0, 0, -1).Format(DailyDateLayout), nil
+	case "tomorrow":
+		return now.AddDate(0, 0, 1).Format(DailyDateLayout), nil
+	}
+
+	if strings.HasPrefix(expr, "+") || strings.HasPrefix(expr, "-") {
+		days, err := strconv.Atoi(expr)
+		if err != nil {
+			return "", fmt.Errorf("invalid day offset %q", expr)
+		}
+		return now.AddDate(0, 0, days).Format(DailyDateLayout), nil
+	}
+
+	date, err := time.Parse(DailyDateLayout, expr)
+	if err != nil {
+		return "", fmt.Errorf("invalid date %q (use YYYY-MM-DD, today, yesterday, tomorrow or +N/-N)", expr)
+	}
+	return date.Format(DailyDateLayout), nil
+}
+
+// RenderDailyTemplate fills in the placeholders of a daily note template
+// Supported placeholders: {{date}}, {{weekday}}, {{yesterday}}, {{tomorrow}}, {{title}}
+func RenderDailyTemplate(template, title string, date time.Time) string {
+	replacer := strings.NewReplacer(
+		"{{date}}", date.Format(DailyDateLayout),
+		"{{weekday}}", date.Weekday().String(),
+		"{{yesterday}}", date.AddDate(0, 0, -1).Format(DailyDateLayout),
+		"{{tomorrow}}", date.AddDate(0, 0, 1).Format(DailyDateLayout),
+		"{{title}}", title,
+	)
+	return replacer.Replace(template)
+}
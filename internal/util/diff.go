@@ -0,0 +1,192 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DiffOp is the kind of change a diff line represents
+type DiffOp int
+
+const (
+	DiffEqual DiffOp = iota
+	DiffInsert
+	DiffDelete
+)
+
+// DiffLine is a single line in a line-based diff
+type DiffLine struct {
+	Op   DiffOp
+	Text string
+}
+
+// diffContextLines is the number of unchanged lines shown around each hunk
+const diffContextLines = 3
+
+// DiffLines computes a line-based diff between two texts
+func DiffLines(oldText, newText string) []DiffLine {
+	a := splitLines(oldText)
+	b := splitLines(newText)
+
+	// Trim common prefix and suffix so the LCS table only covers the changed region
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	lines := make([]DiffLine, 0, len(a)+len(b))
+	for _, l := range a[:prefix] {
+		lines = append(lines, DiffLine{Op: DiffEqual, Text: l})
+	}
+
+	midA := a[prefix : len(a)-suffix]
+	midB := b[prefix : len(b)-suffix]
+
+	// Longest common subsequence over the middle section
+	n, m := len(midA), len(midB)
+	lcs := make([][]int32, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if midA[i] == midB[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case midA[i] == midB[j]:
+			lines = append(lines, DiffLine{Op: DiffEqual, Text: midA[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, DiffLine{Op: DiffDelete, Text: midA[i]})
+			i++
+		default:
+			lines = append(lines, DiffLine{Op: DiffInsert, Text: midB[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		lines = append(lines, DiffLine{Op: DiffDelete, Text: midA[i]})
+	}
+	for ; j < m; j++ {
+		lines = append(lines, DiffLine{Op: DiffInsert, Text: midB[j]})
+	}
+
+	for _, l := range a[len(a)-suffix:] {
+		lines = append(lines, DiffLine{Op: DiffEqual, Text: l})
+	}
+
+	return lines
+}
+
+// UnifiedDiff renders a unified diff between two texts
+// Returns an empty string when the texts are identical
+func UnifiedDiff(oldText, newText, oldLabel, newLabel string) string {
+	lines := DiffLines(oldText, newText)
+
+	changed := false
+	for _, l := range lines {
+		if l.Op != DiffEqual {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("--- " + oldLabel + "\n")
+	sb.WriteString("+++ " + newLabel + "\n")
+
+	// Walk the diff and emit hunks with surrounding context
+	oldLine, newLine := 1, 1
+	for idx := 0; idx < len(lines); {
+		if lines[idx].Op == DiffEqual {
+			idx++
+			oldLine++
+			newLine++
+			continue
+		}
+
+		// Start the hunk a few context lines before the first change
+		start := idx - diffContextLines
+		if start < 0 {
+			start = 0
+		}
+		hunkOld := oldLine - (idx - start)
+		hunkNew := newLine - (idx - start)
+
+		// Extend the hunk until there are more than 2*context unchanged lines in a row
+		end := idx
+		equalRun := 0
+		for end < len(lines) {
+			if lines[end].Op == DiffEqual {
+				if equalRun == 2*diffContextLines {
+					break
+				}
+				equalRun++
+			} else {
+				equalRun = 0
+			}
+			end++
+		}
+		// Keep at most diffContextLines of trailing context
+		end -= equalRun - min(equalRun, diffContextLines)
+
+		oldCount, newCount := 0, 0
+		var body strings.Builder
+		for _, l := range lines[start:end] {
+			switch l.Op {
+			case DiffEqual:
+				body.WriteString(" " + l.Text + "\n")
+				oldCount++
+				newCount++
+			case DiffDelete:
+				body.WriteString("-" + l.Text + "\n")
+				oldCount++
+			case DiffInsert:
+				body.WriteString("+" + l.Text + "\n")
+				newCount++
+			}
+		}
+
+		sb.WriteString(fmt.Sprintf("@@ -%d,%d +%d,%d @@\n", hunkOld, oldCount, hunkNew, newCount))
+		sb.WriteString(body.String())
+
+		// Advance line counters past the hunk
+		for _, l := range lines[idx:end] {
+			if l.Op != DiffInsert {
+				oldLine++
+			}
+			if l.Op != DiffDelete {
+				newLine++
+			}
+		}
+		idx = end
+	}
+
+	return sb.String()
+}
+
+// splitLines splits text into lines without trailing newline characters
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
@@ -125,3 +125,8 @@ func (j *JWTManager) ValidateRefreshToken(tokenString string) (*Claims, error) {
 func (j *JWTManager) GetAccessExpiration() int64 {
 	return int64(j.accessExpiration.Seconds())
 }
+
+// GetRefreshExpiration returns how long refresh tokens stay valid
+func (j *JWTManager) GetRefreshExpiration() time.Duration {
+	return j.refreshExpiration
+}
@@ -3,33 +3,133 @@ package util
 import (
 	"regexp"
 	"strings"
+	"unicode"
 )
 
+// linkContextMaxLen caps the context stored with a link, in characters; longer sentences are cut around the link
+const linkContextMaxLen = 300
+
+// headingLinePattern matches a Markdown heading line
+var headingLinePattern = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+
+// blockIDPattern matches the " ^block-id" ending a line, which names the block for [[Note^block-id]] links
+var blockIDPattern = regexp.MustCompile(`\s\^([A-Za-z0-9-]+)\s*$`)
+
+// linePrefixPattern matches the Markdown that starts a line: headings, quotes, list items and checkboxes
+var linePrefixPattern = regexp.MustCompile(`^(?:#{1,6}|>+|[-*+]|\d+[.)])\s+(?:\[[ xX]\]\s+)?`)
+
 // LinkParser parses wiki-style links from note content
 type LinkParser struct {
 	// Regex pattern matches [[Note Title]] or [[Note Title|Display Text]]
 	linkPattern *regexp.Regexp
+	// Regex pattern matches ![[Note Title]] embeds
+	embedPattern *regexp.Regexp
 }
 
 // NewLinkParser creates a new link parser
 func NewLinkParser() *LinkParser {
 	// Pattern matches [[...]] with optional display text
-	// [[Note Title]] or [[Note Title|Display Text]]
+	// [[Note Title]] or [[Note Title|Display Text]]; the title may end with #Heading or ^block-id
 	pattern := regexp.MustCompile(`\[\[([^\]|]+)(?:\|([^\]]+))?\]\]`)
 	return &LinkParser{
-		linkPattern: pattern,
+		linkPattern:  pattern,
+		embedPattern: regexp.MustCompile(`!` + pattern.String()),
 	}
 }
 
 // Link represents a parsed wiki-style link
 type Link struct {
-	Title      string   // The note title to link to
-	Display    string   // The display text (defaults to title)
-	Context    string   // Surrounding text context
+	Title      string   // The note title to link to, empty for a heading or block of the same note
+	Heading    string   // The heading linked to, [[Note#Heading]]
+	Block      string   // The block id linked to, [[Note^block-id]] or [[Note#^block-id]]
+	Display    string   // The display text (defaults to the title, then " > " and the heading or ^block-id)
+	Context    string   // The sentence the link is in
 	StartPos   int      // Position in content
 	EndPos     int      // End position in content
 }
 
+// Anchor returns the URL fragment of the heading or block the link points to, empty for a whole note
+func (l *Link) Anchor() string {
+	switch {
+	case l.Block != "":
+		return "#^" + l.Block
+	case l.Heading != "":
+		return "#" + HeadingAnchor(l.Heading)
+	}
+	return ""
+}
+
+// SplitLinkTarget splits the target of a wiki link into the note title and the heading or block id after it
+// "Note#Heading", "Note^block-id" and "Note#^block-id" are understood; the title is empty for "#Heading".
+func SplitLinkTarget(target string) (title, heading, block string) {
+	if i := strings.Index(target, "^"); i >= 0 {
+		block = strings.TrimSpace(target[i+1:])
+		target = strings.TrimSuffix(target[:i], "#")
+	}
+	if i := strings.Index(target, "#"); i >= 0 {
+		heading = strings.TrimSpace(target[i+1:])
+		target = target[:i]
+	}
+	return strings.TrimSpace(target), heading, block
+}
+
+// HeadingAnchor returns the id of a heading in rendered HTML, as generated for published notes
+// Letters and digits are kept lowercased and spaces become dashes, e.g. "Set up Go" is "set-up-go".
+func HeadingAnchor(heading string) string {
+	var b strings.Builder
+	for _, r := range strings.TrimSpace(heading) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r >= 'A' && r <= 'Z':
+			b.WriteRune(unicode.ToLower(r))
+		case r == ' ' || r == '\t' || r == '-' || r == '_':
+			b.WriteByte('-')
+		}
+	}
+	if b.Len() == 0 {
+		return "heading"
+	}
+	return b.String()
+}
+
+// BlockID splits the " ^block-id" off the end of a line, the id is empty when the line has none
+func BlockID(line string) (string, string) {
+	loc := blockIDPattern.FindStringSubmatchIndex(line)
+	if loc == nil {
+		return line, ""
+	}
+	return line[:loc[0]], line[loc[2]:loc[3]]
+}
+
+// parseLink reads the title, anchor and display text of a [[...]] match
+func (p *LinkParser) parseLink(match string) (*Link, bool) {
+	submatches := p.linkPattern.FindStringSubmatch(match)
+	if len(submatches) < 2 {
+		return nil, false
+	}
+
+	link := &Link{}
+	link.Title, link.Heading, link.Block = SplitLinkTarget(strings.TrimSpace(submatches[1]))
+	if len(submatches) > 2 && submatches[2] != "" {
+		link.Display = strings.TrimSpace(submatches[2])
+		return link, true
+	}
+
+	var parts []string
+	if link.Title != "" {
+		parts = append(parts, link.Title)
+	}
+	if link.Heading != "" {
+		parts = append(parts, link.Heading)
+	}
+	if link.Block != "" {
+		parts = append(parts, "^"+link.Block)
+	}
+	link.Display = strings.Join(parts, " > ")
+	return link, true
+}
+
 // ExtractLinks extracts all wiki-style links from note content
 func (p *LinkParser) ExtractLinks(content string) []*Link {
 	if content == "" {
@@ -44,47 +144,260 @@ func (p *LinkParser) ExtractLinks(content string) []*Link {
 	links := make([]*Link, 0, len(matches))
 
 	for _, match := range matches {
-		fullMatch := content[match[0]:match[1]]
+		link, ok := p.parseLink(content[match[0]:match[1]])
+		if !ok {
+			continue
+		}
+		link.Context = sentenceAround(content, match[0], match[1])
+		link.StartPos = match[0]
+		link.EndPos = match[1]
 
-		// Extract title and display text using the pattern
-		submatches := p.linkPattern.FindStringSubmatch(fullMatch)
-		if len(submatches) < 2 {
+		links = append(links, link)
+	}
+
+	return links
+}
+
+// sentenceAround returns the sentence of content holding the text from start to end
+// A sentence ends at ".", "!" or "?" followed by a space, or at a line break, so a list item or
+// heading is a sentence of its own. Markdown starting the line is left out.
+func sentenceAround(content string, start, end int) string {
+	from := 0
+	for i := start - 1; i >= 0; i-- {
+		if content[i] == '\n' {
+			from = i + 1
+			break
+		}
+		if endsSentence(content, i) {
+			from = i + 1
+			break
+		}
+	}
+
+	to := len(content)
+	for i := end; i < len(content); i++ {
+		if content[i] == '\n' {
+			to = i
+			break
+		}
+		if endsSentence(content, i) {
+			to = i + 1
+			break
+		}
+	}
+
+	before := strings.TrimLeftFunc(content[from:start], unicode.IsSpace)
+	before = before[len(linePrefixPattern.FindString(before)):]
+	after := strings.TrimRightFunc(content[end:to], unicode.IsSpace)
+
+	// Cut a long sentence evenly around the link
+	link := []rune(content[start:end])
+	beforeRunes, afterRunes := []rune(before), []rune(after)
+	room := max(linkContextMaxLen-len(link), 0)
+	keepBefore := min(len(beforeRunes), max(room/2, room-len(afterRunes)))
+	keepAfter := min(len(afterRunes), room-keepBefore)
+
+	context := string(link)
+	if keepBefore < len(beforeRunes) {
+		context = "…" + string(beforeRunes[len(beforeRunes)-keepBefore:]) + context
+	} else {
+		context = before + context
+	}
+	if keepAfter < len(afterRunes) {
+		context += string(afterRunes[:keepAfter]) + "…"
+	} else {
+		context += after
+	}
+
+	return strings.Join(strings.Fields(context), " ")
+}
+
+// endsSentence reports whether the character at i ends a sentence: ".", "!" or "?" followed by a space
+func endsSentence(content string, i int) bool {
+	switch content[i] {
+	case '.', '!', '?':
+		return i+1 < len(content) && (content[i+1] == ' ' || content[i+1] == '\t' || content[i+1] == '\n' || content[i+1] == '\r')
+	}
+	return false
+}
+
+// ExpandEmbeds replaces the ![[Note]] embeds of a note with the content of the notes they name, as quotes
+// resolve returns the content of a note by title. ![[Note#Heading]] and ![[Note^block-id]] embed just
+// that heading's section or that block. Embeds resolve can't find, embeds of a note already being
+// embedded (or of title, the note itself) and embeds more than depth levels deep are left as links.
+// Embeds in code blocks are left alone.
+func (p *LinkParser) ExpandEmbeds(title, content string, depth int, resolve func(title string) (string, bool)) string {
+	return p.expandEmbeds(content, depth, resolve, []string{title})
+}
+
+// expandEmbeds expands the embeds of content, embedding holds the titles of the notes being embedded
+func (p *LinkParser) expandEmbeds(content string, depth int, resolve func(string) (string, bool), embedding []string) string {
+	if !strings.Contains(content, "![[") {
+		return content
+	}
+
+	var out []string
+	inCode := false
+	needBlank := false // A quote swallows the next line unless a blank one ends it
+	add := func(line string) {
+		if needBlank && strings.TrimSpace(line) != "" {
+			out = append(out, "")
+		}
+		needBlank = false
+		out = append(out, line)
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inCode = !inCode
+		}
+		if inCode || !strings.Contains(line, "![[") {
+			add(line)
 			continue
 		}
 
-		title := strings.TrimSpace(submatches[1])
-		display := title
-		if len(submatches) > 2 && submatches[2] != "" {
-			display = strings.TrimSpace(submatches[2])
+		// Each embed becomes a block of its own, splitting the text around it
+		text, last := "", 0
+		upTo := func(end int) string {
+			if text == "" && last > 0 {
+				// Text after an embed starts a new line
+				return strings.TrimLeftFunc(line[last:end], unicode.IsSpace)
+			}
+			return line[last:end]
 		}
+		for _, match := range p.embedPattern.FindAllStringIndex(line, -1) {
+			link := line[match[0]+1 : match[1]]
+			embed, ok := p.embed(link, depth, resolve, embedding)
+			if !ok {
+				text += upTo(match[0]) + link
+				last = match[1]
+				continue
+			}
 
-		// Get context (50 chars before and after)
-		contextStart := match[0] - 50
-		if contextStart < 0 {
-			contextStart = 0
+			text += upTo(match[0])
+			if strings.TrimSpace(text) != "" {
+				add(strings.TrimRightFunc(text, unicode.IsSpace))
+				add("")
+			}
+			add(embed)
+			needBlank = true
+			text, last = "", match[1]
 		}
-		contextEnd := match[1] + 50
-		if contextEnd > len(content) {
-			contextEnd = len(content)
+		if text += upTo(len(line)); strings.TrimSpace(text) != "" {
+			add(text)
 		}
-		context := strings.TrimSpace(content[contextStart:contextEnd])
+	}
 
-		link := &Link{
-			Title:    title,
-			Display:  display,
-			Context:  context,
-			StartPos: match[0],
-			EndPos:   match[1],
+	return strings.Join(out, "\n")
+}
+
+// embed returns the quoted content a [[...]] embed link names, false to leave the link as it is
+func (p *LinkParser) embed(match string, depth int, resolve func(string) (string, bool), embedding []string) (string, bool) {
+	link, ok := p.parseLink(match)
+	if !ok || link.Title == "" || depth <= 0 {
+		return "", false
+	}
+	for _, title := range embedding {
+		if titleKey(title) == titleKey(link.Title) {
+			return "", false
 		}
+	}
 
-		links = append(links, link)
+	content, ok := resolve(link.Title)
+	if !ok {
+		return "", false
 	}
+	if link.Heading != "" || link.Block != "" {
+		if content, ok = noteSection(content, link.Heading, link.Block); !ok {
+			return "", false
+		}
+	}
+	content = p.expandEmbeds(strings.TrimSpace(content), depth-1, resolve, append(embedding[:len(embedding):len(embedding)], link.Title))
 
-	return links
+	lines := []string{"> **" + link.Display + "**", ">"}
+	for _, line := range strings.Split(content, "\n") {
+		if strings.TrimSpace(line) == "" {
+			lines = append(lines, ">")
+		} else {
+			lines = append(lines, "> "+line)
+		}
+	}
+	return strings.Join(lines, "\n"), true
+}
+
+// noteSection returns the part of content under a heading, up to the next heading as high, or the block with an id
+// A block is the paragraph ending in " ^block-id", or the list item, quote or heading line it ends.
+func noteSection(content, heading, block string) (string, bool) {
+	lines := strings.Split(content, "\n")
+	inCode := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inCode = !inCode
+		}
+		if inCode {
+			continue
+		}
+
+		text, id := BlockID(line)
+		if block != "" {
+			if id != block {
+				continue
+			}
+			start := i
+			if !linePrefixPattern.MatchString(strings.TrimSpace(text)) {
+				for start > 0 {
+					previous := strings.TrimSpace(lines[start-1])
+					if previous == "" || linePrefixPattern.MatchString(previous) {
+						break
+					}
+					start--
+				}
+			}
+			section := append(append([]string{}, lines[start:i]...), text)
+			return strings.Join(section, "\n"), true
+		}
+
+		m := headingLinePattern.FindStringSubmatch(strings.TrimSpace(text))
+		if m == nil || HeadingAnchor(m[2]) != HeadingAnchor(heading) {
+			continue
+		}
+		end := i + 1
+		for inFence := false; end < len(lines); end++ {
+			next := strings.TrimSpace(lines[end])
+			if strings.HasPrefix(next, "```") || strings.HasPrefix(next, "~~~") {
+				inFence = !inFence
+			}
+			if n := headingLinePattern.FindStringSubmatch(next); n != nil && !inFence && len(n[1]) <= len(m[1]) {
+				break
+			}
+		}
+		return strings.Join(lines[i:end], "\n"), true
+	}
+	return "", false
 }
 
 // ReplaceLinks replaces wiki-style links with markdown links
-func (p *LinkParser) ReplaceLinks(content string, replacer func(title, display string) string) string {
+func (p *LinkParser) ReplaceLinks(content string, replacer func(link *Link) string) string {
+	if content == "" {
+		return content
+	}
+
+	return p.linkPattern.ReplaceAllStringFunc(content, func(match string) string {
+		link, ok := p.parseLink(match)
+		if !ok {
+			return match
+		}
+		return replacer(link)
+	})
+}
+
+// RenameLinks points wiki-style links naming a renamed note at newTitle, keeping any heading, block and display text
+// [[Old]] becomes [[New]], [[Old#Heading]] becomes [[New#Heading]] and [[Old|Text]] becomes [[New|Text]].
+// renamed reports whether a link title named the note: matching its old title by NormalizeTitle isn't enough,
+// [[go-notes]] names a note titled "go-notes" before one titled "Go Notes".
+func (p *LinkParser) RenameLinks(content, newTitle string, renamed func(title string) bool) string {
 	if content == "" {
 		return content
 	}
@@ -94,31 +407,75 @@ func (p *LinkParser) ReplaceLinks(content string, replacer func(title, display s
 		if len(submatches) < 2 {
 			return match
 		}
+		target := strings.TrimSpace(submatches[1])
+		title, _, _ := SplitLinkTarget(target)
+		if title == "" || !renamed(title) {
+			return match
+		}
+		target = newTitle + strings.TrimSpace(strings.TrimPrefix(target, title))
 
-		title := strings.TrimSpace(submatches[1])
-		display := title
 		if len(submatches) > 2 && submatches[2] != "" {
-			display = strings.TrimSpace(submatches[2])
+			return "[[" + target + "|" + submatches[2] + "]]"
 		}
-
-		return replacer(title, display)
+		return "[[" + target + "]]"
 	})
 }
 
-// NormalizeTitle normalizes a note title for matching
+// NormalizeTitle returns the key a [[link]] title is matched by: lowercased, with each run of whitespace,
+// dashes and underscores as a single space, so [[go-lang]], [[Go_Lang]] and [[go  lang]] all name "Go Lang"
+// It is the note_title_key function of the database, which resolves links.
 func (p *LinkParser) NormalizeTitle(title string) string {
-	// Convert to lowercase and trim spaces
-	title = strings.ToLower(strings.TrimSpace(title))
-	// Remove special characters (keep alphanumeric, spaces, hyphens, underscores)
-	title = strings.Map(func(r rune) rune {
-		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == ' ' || r == '-' || r == '_' {
-			return r
-		}
-		return -1
-	}, title)
-	// Replace multiple spaces with single space
-	title = strings.Join(strings.Fields(title), " ")
-	return title
+	return titleKey(title)
+}
+
+// titleKey returns the NormalizeTitle key of title, for the matching that has no LinkParser at hand
+func titleKey(title string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return unicode.IsSpace(r) || r == '-' || r == '_'
+	}), " ")
+}
+
+// TitleIndex resolves [[link]] titles among a set of titles the way the database does
+// The exact title wins, then one differing only in case, then one with the same NormalizeTitle key;
+// among titles matching the same way, the one added first.
+type TitleIndex struct {
+	exact  map[string]int
+	folded map[string]int
+	keyed  map[string]int
+}
+
+// NewTitleIndex creates an empty title index
+func NewTitleIndex() *TitleIndex {
+	return &TitleIndex{
+		exact:  make(map[string]int),
+		folded: make(map[string]int),
+		keyed:  make(map[string]int),
+	}
+}
+
+// Add adds a title, found as index
+func (t *TitleIndex) Add(title string, index int) {
+	addTitle(t.exact, title, index)
+	addTitle(t.folded, strings.ToLower(title), index)
+	addTitle(t.keyed, titleKey(title), index)
+}
+
+func addTitle(titles map[string]int, key string, index int) {
+	if _, ok := titles[key]; !ok {
+		titles[key] = index
+	}
+}
+
+// Find returns the index of the title a link title names
+func (t *TitleIndex) Find(title string) (int, bool) {
+	if index, ok := t.exact[title]; ok {
+		return index, true
+	}
+	if index, ok := t.folded[strings.ToLower(title)]; ok {
+		return index, true
+	}
+	index, ok := t.keyed[titleKey(title)]
+	return index, ok
 }
 
 // StripLinks removes all wiki-style links from content
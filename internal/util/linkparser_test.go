@@ -0,0 +1,39 @@
+package util
+
+import "testing"
+
+func TestRenameLinks(t *testing.T) {
+	p := NewLinkParser()
+
+	// "Go Notes" is renamed to "Golang", while another note is titled "go-notes"
+	titles := NewTitleIndex()
+	titles.Add("Go Notes", 0)
+	titles.Add("go-notes", 1)
+	renamed := func(title string) bool {
+		index, ok := titles.Find(title)
+		return ok && index == 0
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"exact title", "See [[Go Notes]].", "See [[Golang]]."},
+		{"title differing in case", "See [[go notes]].", "See [[Golang]]."},
+		{"heading and display text", "See [[Go Notes#Setup|setup]].", "See [[Golang#Setup|setup]]."},
+		{"block", "See [[Go Notes#^intro]].", "See [[Golang#^intro]]."},
+		{"colliding title of another note", "See [[go-notes]].", "See [[go-notes]]."},
+		{"both notes", "[[Go Notes]] and [[go-notes]]", "[[Golang]] and [[go-notes]]"},
+		{"other title", "See [[Rust Notes]].", "See [[Rust Notes]]."},
+		{"heading of the note itself", "See [[#Go Notes]].", "See [[#Go Notes]]."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.RenameLinks(tt.content, "Golang", renamed); got != tt.want {
+				t.Errorf("RenameLinks(%q) = %q, want %q", tt.content, got, tt.want)
+			}
+		})
+	}
+}
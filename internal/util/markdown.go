@@ -0,0 +1,228 @@
+package util
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+// Frontmatter holds the YAML header written at the top of exported notes
+type Frontmatter struct {
+	ID        string    `yaml:"id,omitempty"`
+	Title     string    `yaml:"title,omitempty"`
+	Type      string    `yaml:"type,omitempty"`
+	Tags      []string  `yaml:"tags,omitempty"`
+	Created   time.Time `yaml:"created,omitempty"`
+	Updated   time.Time `yaml:"updated,omitempty"`
+	Encrypted bool      `yaml:"encrypted,omitempty"` // Body is client-side ciphertext
+	// Metadata holds every other key, the note's metadata
+	Metadata map[string]any `yaml:",inline"`
+}
+
+// frontmatterFields are the keys of the header that aren't metadata
+var frontmatterFields = map[string]bool{
+	"id": true, "title": true, "type": true, "tags": true, "created": true, "updated": true, "encrypted": true,
+}
+
+// frontmatterDelimiter separates the YAML header from the note body
+const frontmatterDelimiter = "---"
+
+// unsafeFileChars matches characters that are not allowed in file names on common filesystems
+var unsafeFileChars = regexp.MustCompile(`[<>:"/\\|?*\x00-\x1f]`)
+
+// NoteFrontmatter builds the frontmatter for a note
+func NoteFrontmatter(note *model.Note) Frontmatter {
+	tags := make([]string, 0, len(note.Tags))
+	for _, tag := range note.Tags {
+		tags = append(tags, tag.Name)
+	}
+
+	return Frontmatter{
+		ID:        note.ID.String(),
+		Title:     note.Title,
+		Type:      string(note.NoteType),
+		Tags:      tags,
+		Created:   note.CreatedAt.UTC(),
+		Updated:   note.UpdatedAt.UTC(),
+		Encrypted: note.Encrypted,
+		Metadata:  frontmatterMetadata(note.Metadata),
+	}
+}
+
+// frontmatterMetadata returns the metadata keys written to and read from frontmatter
+// The generated summary and read progress are left out, the server sets them, and so are keys clashing
+// with the header's own.
+// A status that isn't a board status (todo, doing, done) is left out too, other apps use the key freely.
+func frontmatterMetadata(metadata map[string]any) map[string]any {
+	out := make(map[string]any, len(metadata))
+	for key, value := range metadata {
+		if frontmatterFields[key] || key == model.MetadataSummary || key == model.MetadataReadProgress {
+			continue
+		}
+		if key == model.MetadataStatus {
+			if status, ok := value.(string); !ok || !slices.Contains(model.NoteStatuses, model.NoteStatus(status)) {
+				continue
+			}
+		}
+		out[key] = value
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// RenderMarkdown renders a note as Markdown with a YAML frontmatter header
+func RenderMarkdown(note *model.Note) ([]byte, error) {
+	header, err := yaml.Marshal(NoteFrontmatter(note))
+	if err != nil {
+		return nil, fmt.Errorf("marshal frontmatter: %w", err)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(frontmatterDelimiter + "\n")
+	buf.Write(header)
+	buf.WriteString(frontmatterDelimiter + "\n\n")
+	buf.WriteString(note.Content)
+	if !strings.HasSuffix(note.Content, "\n") {
+		buf.WriteString("\n")
+	}
+
+	return buf.Bytes(), nil
+}
+
+// ParseMarkdown splits a Markdown document into its frontmatter and body
+// Documents without a frontmatter header return an empty Frontmatter and the full content.
+// Keys other than the note's fields are returned as its Metadata. Tags may be given as a YAML list or a comma/space separated string (Obsidian style).
+func ParseMarkdown(data []byte) (Frontmatter, string, error) {
+	var fm Frontmatter
+
+	content := strings.ReplaceAll(string(data), "\r\n", "\n")
+	if !strings.HasPrefix(content, frontmatterDelimiter+"\n") {
+		return fm, content, nil
+	}
+
+	rest := content[len(frontmatterDelimiter)+1:]
+	end := strings.Index(rest, "\n"+frontmatterDelimiter)
+	if end == -1 {
+		// Unterminated header - treat the whole file as content
+		return fm, content, nil
+	}
+
+	header := rest[:end]
+	body := rest[end+len(frontmatterDelimiter)+1:]
+	body = strings.TrimLeft(body, "\n")
+
+	var raw map[string]any
+	if err := yaml.Unmarshal([]byte(header), &raw); err != nil {
+		return fm, content, fmt.Errorf("parse frontmatter: %w", err)
+	}
+
+	if v, ok := raw["id"].(string); ok {
+		fm.ID = v
+	}
+	if v, ok := raw["title"].(string); ok {
+		fm.Title = v
+	}
+	if v, ok := raw["type"].(string); ok {
+		fm.Type = v
+	}
+	if v, ok := raw["created"].(time.Time); ok {
+		fm.Created = v
+	}
+	if v, ok := raw["updated"].(time.Time); ok {
+		fm.Updated = v
+	}
+	if v, ok := raw["encrypted"].(bool); ok {
+		fm.Encrypted = v
+	}
+	fm.Tags = parseFrontmatterTags(raw["tags"])
+	fm.Metadata = frontmatterMetadata(raw)
+
+	return fm, body, nil
+}
+
+// parseFrontmatterTags normalizes the different ways tags are written in frontmatter
+func parseFrontmatterTags(v any) []string {
+	var tags []string
+
+	add := func(s string) {
+		s = strings.TrimPrefix(strings.TrimSpace(s), "#")
+		if s != "" {
+			tags = append(tags, s)
+		}
+	}
+
+	switch t := v.(type) {
+	case string:
+		for _, part := range strings.FieldsFunc(t, func(r rune) bool { return r == ',' || r == ' ' }) {
+			add(part)
+		}
+	case []any:
+		for _, item := range t {
+			if s, ok := item.(string); ok {
+				add(s)
+			}
+		}
+	}
+
+	return tags
+}
+
+// MarkdownFileName returns a filesystem-safe file name for a note title
+func MarkdownFileName(title string) string {
+	name := unsafeFileChars.ReplaceAllString(title, "-")
+	name = strings.Trim(strings.TrimSpace(name), ".")
+	if name == "" {
+		name = "untitled"
+	}
+	return name + ".md"
+}
+
+// WriteMarkdownArchive writes the notes as a zip archive of Markdown files
+// Duplicate titles are disambiguated with a numeric suffix
+func WriteMarkdownArchive(w io.Writer, notes []*model.Note) error {
+	zw := zip.NewWriter(w)
+	used := make(map[string]int)
+
+	for _, note := range notes {
+		data, err := RenderMarkdown(note)
+		if err != nil {
+			return fmt.Errorf("render note %s: %w", note.ID, err)
+		}
+
+		name := MarkdownFileName(note.Title)
+		key := strings.ToLower(name)
+		if count := used[key]; count > 0 {
+			name = fmt.Sprintf("%s (%d).md", strings.TrimSuffix(name, ".md"), count+1)
+		}
+		used[key]++
+
+		f, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     name,
+			Method:   zip.Deflate,
+			Modified: note.UpdatedAt,
+		})
+		if err != nil {
+			return fmt.Errorf("create archive entry: %w", err)
+		}
+		if _, err := f.Write(data); err != nil {
+			return fmt.Errorf("write archive entry: %w", err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("close archive: %w", err)
+	}
+
+	return nil
+}
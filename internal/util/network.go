@@ -0,0 +1,34 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+)
+
+// ErrBlockedAddress is returned for connections to loopback or private networks
+var ErrBlockedAddress = errors.New("address is not allowed")
+
+// sharedAddressSpace is the carrier-grade NAT range, private but not covered by net.IP.IsPrivate
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// BlockInternalAddresses is a net.Dialer Control function refusing internal networks
+// It runs after DNS resolution, so a hostname can't be pointed at an internal service.
+func BlockInternalAddresses(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || isInternalIP(ip) {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, host)
+	}
+	return nil
+}
+
+// isInternalIP reports whether ip is on a loopback, private or otherwise internal network
+func isInternalIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() || sharedAddressSpace.Contains(ip)
+}
@@ -0,0 +1,77 @@
+package util
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/momokii/go-cli-notes/internal/model"
+)
+
+// TagTreeEntry is a tag positioned in the tag hierarchy
+type TagTreeEntry struct {
+	Tag   *model.Tag
+	Depth int  // 0 for top-level tags
+	Last  bool // Whether this is the last child of its parent
+}
+
+// BuildTagTree orders tags depth-first by their parent links, siblings sorted by name
+// Tags whose parent is not in the list are treated as top-level tags.
+func BuildTagTree(tags []*model.Tag) []TagTreeEntry {
+	known := make(map[uuid.UUID]bool, len(tags))
+	for _, tag := range tags {
+		known[tag.ID] = true
+	}
+
+	children := make(map[uuid.UUID][]*model.Tag)
+	var roots []*model.Tag
+	for _, tag := range tags {
+		if tag.ParentID != nil && known[*tag.ParentID] && *tag.ParentID != tag.ID {
+			children[*tag.ParentID] = append(children[*tag.ParentID], tag)
+		} else {
+			roots = append(roots, tag)
+		}
+	}
+
+	byName := func(list []*model.Tag) {
+		sort.Slice(list, func(i, j int) bool {
+			return strings.ToLower(list[i].Name) < strings.ToLower(list[j].Name)
+		})
+	}
+
+	entries := make([]TagTreeEntry, 0, len(tags))
+	visited := make(map[uuid.UUID]bool, len(tags))
+
+	var walk func(list []*model.Tag, depth int)
+	walk = func(list []*model.Tag, depth int) {
+		byName(list)
+		for i, tag := range list {
+			if visited[tag.ID] {
+				continue
+			}
+			visited[tag.ID] = true
+			entries = append(entries, TagTreeEntry{Tag: tag, Depth: depth, Last: i == len(list)-1})
+			walk(children[tag.ID], depth+1)
+		}
+	}
+	walk(roots, 0)
+
+	// Tags caught in a parent cycle are never reached from a root; list them at the top level
+	for _, tag := range tags {
+		if !visited[tag.ID] {
+			walk([]*model.Tag{tag}, 0)
+		}
+	}
+
+	return entries
+}
+
+// TagLeafName returns the last segment of a nested tag name
+// e.g. "work/project/alpha" returns "alpha"
+func TagLeafName(name string) string {
+	if idx := strings.LastIndex(name, model.TagSeparator); idx >= 0 {
+		return name[idx+1:]
+	}
+	return name
+}
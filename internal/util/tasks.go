@@ -0,0 +1,47 @@
+package util
+
+import (
+	"regexp"
+	"strings"
+)
+
+// taskLinePattern matches Markdown task list items: "- [ ] todo", "* [x] done", "1. [ ] step"
+var taskLinePattern = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+\[([ xX])\]\s+(.*\S)\s*$`)
+
+// ParsedTask is a checkbox item found in note content
+type ParsedTask struct {
+	Line      int    // 1-based line number in the content
+	Content   string // Task text without the checkbox
+	Completed bool
+}
+
+// ExtractTasks returns the task list items in Markdown content, in document order
+// Checkboxes inside fenced code blocks are ignored.
+func ExtractTasks(content string) []ParsedTask {
+	var tasks []ParsedTask
+	inCode := false
+
+	for i, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			continue
+		}
+
+		m := taskLinePattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+
+		tasks = append(tasks, ParsedTask{
+			Line:      i + 1,
+			Content:   m[2],
+			Completed: m[1] != " ",
+		})
+	}
+
+	return tasks
+}
@@ -0,0 +1,120 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+// rfc6238Secret is the SHA1 key of the RFC 6238 test vectors, "12345678901234567890" in base32
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// rfc6238Vectors are the SHA1 test vectors of RFC 6238 appendix B, cut to the last six digits
+var rfc6238Vectors = []struct {
+	unix int64
+	code string
+}{
+	{59, "287082"},
+	{1111111109, "081804"},
+	{1111111111, "050471"},
+	{1234567890, "005924"},
+	{2000000000, "279037"},
+	{20000000000, "353130"},
+}
+
+func TestTOTPCodeRFC6238(t *testing.T) {
+	key, err := totpEncoding.DecodeString(rfc6238Secret)
+	if err != nil {
+		t.Fatalf("decode secret: %v", err)
+	}
+
+	for _, v := range rfc6238Vectors {
+		counter := uint64(v.unix / int64(totpPeriod.Seconds()))
+		if got := totpCode(key, counter); got != v.code {
+			t.Errorf("totpCode at %d = %s, want %s", v.unix, got, v.code)
+		}
+	}
+}
+
+func TestValidateTOTPRFC6238(t *testing.T) {
+	for _, v := range rfc6238Vectors {
+		if !ValidateTOTP(rfc6238Secret, v.code, time.Unix(v.unix, 0)) {
+			t.Errorf("ValidateTOTP(%s) at %d = false, want true", v.code, v.unix)
+		}
+	}
+}
+
+func TestValidateTOTPSkew(t *testing.T) {
+	key, err := totpEncoding.DecodeString(rfc6238Secret)
+	if err != nil {
+		t.Fatalf("decode secret: %v", err)
+	}
+
+	now := time.Unix(1111111111, 0)
+	counter := now.Unix() / int64(totpPeriod.Seconds())
+
+	tests := []struct {
+		steps int64
+		want  bool
+	}{
+		{-2, false},
+		{-1, true},
+		{0, true},
+		{1, true},
+		{2, false},
+	}
+
+	for _, tt := range tests {
+		code := totpCode(key, uint64(counter+tt.steps))
+		if got := ValidateTOTP(rfc6238Secret, code, now); got != tt.want {
+			t.Errorf("code %+d steps away: ValidateTOTP = %v, want %v", tt.steps, got, tt.want)
+		}
+	}
+}
+
+func TestValidateTOTPInput(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+
+	tests := []struct {
+		name   string
+		secret string
+		code   string
+		want   bool
+	}{
+		{"valid", rfc6238Secret, "050471", true},
+		{"surrounding spaces", rfc6238Secret, " 050471 ", true},
+		{"lowercase secret", "gezdgnbvgy3tqojqgezdgnbvgy3tqojq", "050471", true},
+		{"wrong code", rfc6238Secret, "050472", false},
+		{"too short", rfc6238Secret, "50471", false},
+		{"too long", rfc6238Secret, "0050471", false},
+		{"empty", rfc6238Secret, "", false},
+		{"invalid secret", "not base32!", "050471", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ValidateTOTP(tt.secret, tt.code, now); got != tt.want {
+				t.Errorf("ValidateTOTP(%q, %q) = %v, want %v", tt.secret, tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateTOTPSecret(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		t.Fatalf("GenerateTOTPSecret: %v", err)
+	}
+
+	key, err := totpEncoding.DecodeString(secret)
+	if err != nil {
+		t.Fatalf("decode generated secret: %v", err)
+	}
+	if len(key) != totpSecretLen {
+		t.Errorf("secret is %d bytes, want %d", len(key), totpSecretLen)
+	}
+
+	code := totpCode(key, uint64(time.Now().Unix()/int64(totpPeriod.Seconds())))
+	if !ValidateTOTP(secret, code, time.Now()) {
+		t.Errorf("ValidateTOTP rejected a current code for a generated secret")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
 )
@@ -13,10 +14,31 @@ type Validator struct {
 	validate *validator.Validate
 }
 
+// ValidationError lists the fields of a struct that failed validation
+type ValidationError struct {
+	Fields map[string]string // JSON field name -> what is wrong with it
+	msg    string
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	return e.msg
+}
+
 // NewValidator creates a new validator
 func NewValidator() *Validator {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	// Report fields by their JSON name, the one API clients know
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
 	return &Validator{
-		validate: validator.New(validator.WithRequiredStructEnabled()),
+		validate: validate,
 	}
 }
 
@@ -28,39 +50,49 @@ func (v *Validator) Validate(s any) error {
 	return nil
 }
 
-// formatValidationErr formats validation errors into a readable format
+// formatValidationErr formats validation errors into a *ValidationError
 func (v *Validator) formatValidationErr(err error) error {
-	var errMsg string
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return err
+	}
 
-	if validationErrors, ok := err.(validator.ValidationErrors); ok {
-		for _, e := range validationErrors {
-			field := e.Field()
-			tag := e.Tag()
+	result := &ValidationError{Fields: make(map[string]string, len(validationErrors))}
+	for _, e := range validationErrors {
+		field := e.Field()
+		tag := e.Tag()
+
+		// Custom error messages based on tag
+		var fieldMsg string
+		switch tag {
+		case "required":
+			fieldMsg = fmt.Sprintf("%s is required", field)
+		case "email":
+			fieldMsg = fmt.Sprintf("%s must be a valid email", field)
+		case "min":
+			fieldMsg = fmt.Sprintf("%s must be at least %s characters", field, e.Param())
+		case "max":
+			fieldMsg = fmt.Sprintf("%s must be at most %s characters", field, e.Param())
+		case "alphanum":
+			fieldMsg = fmt.Sprintf("%s must contain only alphanumeric characters", field)
+		case "oneof":
+			fieldMsg = fmt.Sprintf("%s must be one of: %s", field, e.Param())
+		case "uuid":
+			fieldMsg = fmt.Sprintf("%s must be a valid UUID", field)
+		case "http_url":
+			fieldMsg = fmt.Sprintf("%s must be an http or https URL", field)
+		default:
+			fieldMsg = fmt.Sprintf("%s failed validation: %s", field, tag)
+		}
 
-			// Custom error messages based on tag
-			switch tag {
-			case "required":
-				errMsg += fmt.Sprintf("%s is required; ", field)
-			case "email":
-				errMsg += fmt.Sprintf("%s must be a valid email; ", field)
-			case "min":
-				errMsg += fmt.Sprintf("%s must be at least %s characters; ", field, e.Param())
-			case "max":
-				errMsg += fmt.Sprintf("%s must be at most %s characters; ", field, e.Param())
-			case "alphanum":
-				errMsg += fmt.Sprintf("%s must contain only alphanumeric characters; ", field)
-			case "oneof":
-				errMsg += fmt.Sprintf("%s must be one of: %s; ", field, e.Param())
-			case "uuid":
-				errMsg += fmt.Sprintf("%s must be a valid UUID; ", field)
-			default:
-				errMsg += fmt.Sprintf("%s failed validation: %s; ", field, tag)
-			}
+		// Keep the first problem of each field
+		if _, ok := result.Fields[field]; !ok {
+			result.Fields[field] = fieldMsg
 		}
-		return errors.New(errMsg)
+		result.msg += fieldMsg + "; "
 	}
 
-	return err
+	return result
 }
 
 // ValidateStruct is a convenience function to validate a struct
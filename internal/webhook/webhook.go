@@ -0,0 +1,109 @@
+// Package webhook sends signed event payloads to URLs users registered
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/momokii/go-cli-notes/internal/config"
+	"github.com/momokii/go-cli-notes/internal/util"
+)
+
+// Headers sent with every delivery
+const (
+	EventHeader     = "X-KG-Event"
+	DeliveryHeader  = "X-KG-Delivery"
+	SignatureHeader = "X-KG-Signature" // sha256=<hex HMAC-SHA256 of the body, keyed by the webhook secret>
+)
+
+// Sender posts payloads to webhook URLs
+type Sender struct {
+	client *http.Client
+}
+
+// New creates a sender with the configured timeout
+// Unless private addresses are allowed, connections to them are refused after DNS
+// resolution, so a webhook can't be pointed at an internal service.
+func New(cfg config.WebhookConfig) *Sender {
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	if !cfg.AllowPrivate {
+		dialer.Control = util.BlockInternalAddresses
+	}
+
+	transport := &http.Transport{
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: cfg.Timeout,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       90 * time.Second,
+	}
+
+	return &Sender{
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   cfg.Timeout,
+			// A redirect is reported as the response, it could lead anywhere
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// Send posts body to url, signed with secret
+// It returns the response status, 0 when no response came back. Responses other
+// than 2xx are errors.
+func (s *Sender) Send(ctx context.Context, url, secret, event, deliveryID string, body []byte) (int, error) {
+	return s.post(ctx, url, body, map[string]string{
+		EventHeader:     event,
+		DeliveryHeader:  deliveryID,
+		SignatureHeader: Sign(secret, body),
+	})
+}
+
+// Post posts a JSON body to url without signing it, for services that don't check
+// signatures such as Slack and Discord incoming webhooks
+func (s *Sender) Post(ctx context.Context, url string, body []byte) (int, error) {
+	return s.post(ctx, url, body, nil)
+}
+
+// post sends a JSON POST request with the extra headers
+func (s *Sender) post(ctx context.Context, url string, body []byte, headers map[string]string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "KnowledgeGarden-Webhook/1.0")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// Drain a little of the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign returns the signature header value of body
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
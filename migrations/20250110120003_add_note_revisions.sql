@@ -0,0 +1,27 @@
+-- +goose Up
+-- Add note revision history
+-- NOTE: This migration is idempotent and can be safely re-run
+
+-- Note revisions table (snapshot of a note before each update)
+CREATE TABLE IF NOT EXISTS note_revisions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    note_id UUID NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    revision_number INT NOT NULL,
+    title VARCHAR(500) NOT NULL,
+    content TEXT DEFAULT '',
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- Unique revision number per note (idempotent)
+CREATE UNIQUE INDEX IF NOT EXISTS idx_note_revisions_note_number ON note_revisions(note_id, revision_number);
+
+-- Indexes for note_revisions (idempotent)
+CREATE INDEX IF NOT EXISTS idx_note_revisions_user_id ON note_revisions(user_id);
+
+-- +goose Down
+-- Rollback note revision history
+
+DROP INDEX IF EXISTS idx_note_revisions_user_id;
+DROP INDEX IF EXISTS idx_note_revisions_note_number;
+DROP TABLE IF EXISTS note_revisions;
@@ -0,0 +1,24 @@
+-- +goose Up
+-- Add tag hierarchy (nested "parent/child" tags)
+-- NOTE: This migration is idempotent and can be safely re-run
+
+-- Parent tag reference (children become top-level if the parent is deleted)
+ALTER TABLE tags ADD COLUMN IF NOT EXISTS parent_id UUID REFERENCES tags(id) ON DELETE SET NULL;
+
+-- Indexes for tag hierarchy (idempotent)
+CREATE INDEX IF NOT EXISTS idx_tags_parent_id ON tags(parent_id);
+
+-- Link existing "parent/child" tags to their parent when the parent tag exists
+UPDATE tags AS child
+SET parent_id = parent.id
+FROM tags AS parent
+WHERE child.parent_id IS NULL
+  AND parent.user_id = child.user_id
+  AND position('/' IN child.name) > 0
+  AND parent.name = regexp_replace(child.name, '/[^/]*$', '');
+
+-- +goose Down
+-- Rollback tag hierarchy
+
+DROP INDEX IF EXISTS idx_tags_parent_id;
+ALTER TABLE tags DROP COLUMN IF EXISTS parent_id;
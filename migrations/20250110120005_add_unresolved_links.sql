@@ -0,0 +1,26 @@
+-- +goose Up
+-- Track wiki links whose target note does not exist yet
+-- NOTE: This migration is idempotent and can be safely re-run
+
+-- Unresolved links table ([[Title]] references with no matching note)
+CREATE TABLE IF NOT EXISTS unresolved_links (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    source_note_id UUID NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+    target_title VARCHAR(500) NOT NULL,
+    link_context TEXT,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- Unique constraint to prevent duplicate unresolved links (idempotent)
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unresolved_links_source_title ON unresolved_links(source_note_id, target_title);
+
+-- Indexes for unresolved_links (idempotent)
+CREATE INDEX IF NOT EXISTS idx_unresolved_links_user_title ON unresolved_links(user_id, target_title);
+
+-- +goose Down
+-- Rollback unresolved link tracking
+
+DROP INDEX IF EXISTS idx_unresolved_links_user_title;
+DROP INDEX IF EXISTS idx_unresolved_links_source_title;
+DROP TABLE IF EXISTS unresolved_links;
@@ -0,0 +1,15 @@
+-- +goose Up
+-- Add per-user settings
+-- NOTE: This migration is idempotent and can be safely re-run
+
+-- User settings table (one row per user, created on first change)
+CREATE TABLE IF NOT EXISTS user_settings (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    daily_template TEXT,
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- +goose Down
+-- Rollback per-user settings
+
+DROP TABLE IF EXISTS user_settings;
@@ -0,0 +1,26 @@
+-- +goose Up
+-- Add file attachments on notes
+-- NOTE: This migration is idempotent and can be safely re-run
+
+-- Attachments table (file contents live in object storage under storage_key)
+CREATE TABLE IF NOT EXISTS attachments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    note_id UUID NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL DEFAULT 'application/octet-stream',
+    size_bytes BIGINT NOT NULL DEFAULT 0,
+    storage_key TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- Indexes for attachments (idempotent)
+CREATE INDEX IF NOT EXISTS idx_attachments_note_id ON attachments(note_id);
+CREATE INDEX IF NOT EXISTS idx_attachments_user_id ON attachments(user_id);
+
+-- +goose Down
+-- Rollback file attachments
+
+DROP INDEX IF EXISTS idx_attachments_user_id;
+DROP INDEX IF EXISTS idx_attachments_note_id;
+DROP TABLE IF EXISTS attachments;
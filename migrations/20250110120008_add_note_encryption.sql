@@ -0,0 +1,34 @@
+-- +goose Up
+-- Add client-side encrypted notes
+-- NOTE: This migration is idempotent and can be safely re-run
+
+-- Encrypted notes hold ciphertext produced by the client; the server never sees the passphrase
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS encrypted BOOLEAN NOT NULL DEFAULT false;
+
+-- Only index the title of encrypted notes, their content is ciphertext
+CREATE OR REPLACE FUNCTION notes_tsv_trigger() RETURNS trigger AS $$
+BEGIN
+    IF NEW.encrypted THEN
+        NEW.content_tsv := setweight(to_tsvector('english', coalesce(NEW.title, '')), 'A');
+    ELSE
+        NEW.content_tsv :=
+            setweight(to_tsvector('english', coalesce(NEW.title, '')), 'A') ||
+            setweight(to_tsvector('english', coalesce(NEW.content, '')), 'B');
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- +goose Down
+-- Rollback client-side encrypted notes
+
+CREATE OR REPLACE FUNCTION notes_tsv_trigger() RETURNS trigger AS $$
+BEGIN
+    NEW.content_tsv :=
+        setweight(to_tsvector('english', coalesce(NEW.title, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(NEW.content, '')), 'B');
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE notes DROP COLUMN IF EXISTS encrypted;
@@ -0,0 +1,36 @@
+-- +goose Up
+-- Add tasks extracted from note checkboxes
+-- NOTE: This migration is idempotent and can be safely re-run
+
+-- Tasks table ("- [ ]" / "- [x]" items, rebuilt whenever the note is saved)
+CREATE TABLE IF NOT EXISTS tasks (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    note_id UUID NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+    line_number INT NOT NULL,
+    content TEXT NOT NULL,
+    completed BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- Indexes for tasks (idempotent)
+CREATE INDEX IF NOT EXISTS idx_tasks_user_completed ON tasks(user_id, completed);
+CREATE INDEX IF NOT EXISTS idx_tasks_note_id ON tasks(note_id);
+
+-- Extract tasks from existing notes (notes that already have tasks are skipped)
+-- Unlike the API this does not skip checkboxes inside fenced code blocks
+INSERT INTO tasks (user_id, note_id, line_number, content, completed)
+SELECT n.user_id, n.id, l.line_number::int, m[2], m[1] <> ' '
+FROM notes n
+CROSS JOIN LATERAL regexp_split_to_table(n.content, E'\n') WITH ORDINALITY AS l(line, line_number)
+CROSS JOIN LATERAL regexp_match(l.line, '^\s*(?:[-*+]|\d+[.)])\s+\[([ xX])\]\s+(.*\S)\s*$') AS m
+WHERE m IS NOT NULL
+  AND n.encrypted = false
+  AND NOT EXISTS (SELECT 1 FROM tasks t WHERE t.note_id = n.id);
+
+-- +goose Down
+-- Rollback tasks
+
+DROP INDEX IF EXISTS idx_tasks_note_id;
+DROP INDEX IF EXISTS idx_tasks_user_completed;
+DROP TABLE IF EXISTS tasks;
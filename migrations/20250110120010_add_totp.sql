@@ -0,0 +1,13 @@
+-- +goose Up
+-- Add TOTP two-factor authentication
+-- NOTE: This migration is idempotent and can be safely re-run
+
+-- Secret is stored on setup; 2FA is only enforced once a code has been verified
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_secret TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_enabled BOOLEAN NOT NULL DEFAULT false;
+
+-- +goose Down
+-- Rollback TOTP two-factor authentication
+
+ALTER TABLE users DROP COLUMN IF EXISTS totp_enabled;
+ALTER TABLE users DROP COLUMN IF EXISTS totp_secret;
@@ -0,0 +1,21 @@
+-- +goose Up
+-- Add password reset tokens
+-- NOTE: This migration is idempotent and can be safely re-run
+
+-- Only a SHA256 hash of each token is stored; tokens are single-use and time-limited
+CREATE TABLE IF NOT EXISTS password_reset_tokens (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(255) NOT NULL UNIQUE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    used_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_password_reset_tokens_user_id ON password_reset_tokens(user_id);
+CREATE INDEX IF NOT EXISTS idx_password_reset_tokens_expires_at ON password_reset_tokens(expires_at);
+
+-- +goose Down
+-- Rollback password reset tokens
+
+DROP TABLE IF EXISTS password_reset_tokens;
@@ -0,0 +1,12 @@
+-- +goose Up
+-- Record which device a refresh token was issued to
+-- NOTE: This migration is idempotent and can be safely re-run
+
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS user_agent TEXT NOT NULL DEFAULT '';
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS ip_address VARCHAR(45) NOT NULL DEFAULT '';
+
+-- +goose Down
+-- Rollback session device info
+
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS ip_address;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS user_agent;
@@ -0,0 +1,25 @@
+-- +goose Up
+-- Add email verification
+-- NOTE: This migration is idempotent and can be safely re-run
+
+-- Accounts created before verification existed count as verified; new accounts start unverified
+ALTER TABLE users ADD COLUMN IF NOT EXISTS is_verified BOOLEAN NOT NULL DEFAULT true;
+ALTER TABLE users ALTER COLUMN is_verified SET DEFAULT false;
+
+-- Only a SHA256 hash of each token is stored; tokens are single-use and time-limited
+CREATE TABLE IF NOT EXISTS email_verification_tokens (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(255) NOT NULL UNIQUE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_email_verification_tokens_user_id ON email_verification_tokens(user_id);
+CREATE INDEX IF NOT EXISTS idx_email_verification_tokens_expires_at ON email_verification_tokens(expires_at);
+
+-- +goose Down
+-- Rollback email verification
+
+DROP TABLE IF EXISTS email_verification_tokens;
+ALTER TABLE users DROP COLUMN IF EXISTS is_verified;
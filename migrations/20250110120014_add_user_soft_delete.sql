@@ -0,0 +1,11 @@
+-- +goose Up
+-- Add soft-delete support for user accounts
+-- NOTE: This migration is idempotent and can be safely re-run
+
+-- Deleted accounts are deactivated and keep their data until purged
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+-- +goose Down
+-- Rollback soft-delete support for user accounts
+
+ALTER TABLE users DROP COLUMN IF EXISTS deleted_at;
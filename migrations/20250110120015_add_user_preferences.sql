@@ -0,0 +1,18 @@
+-- +goose Up
+-- Add user preferences to user settings
+-- NOTE: This migration is idempotent and can be safely re-run
+
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS default_note_type VARCHAR(50) NOT NULL DEFAULT 'note';
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS page_size INTEGER NOT NULL DEFAULT 20;
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS timezone TEXT NOT NULL DEFAULT 'UTC';
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS week_start VARCHAR(10) NOT NULL DEFAULT 'monday';
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS theme VARCHAR(50) NOT NULL DEFAULT 'dark';
+
+-- +goose Down
+-- Rollback user preferences
+
+ALTER TABLE user_settings DROP COLUMN IF EXISTS theme;
+ALTER TABLE user_settings DROP COLUMN IF EXISTS week_start;
+ALTER TABLE user_settings DROP COLUMN IF EXISTS timezone;
+ALTER TABLE user_settings DROP COLUMN IF EXISTS page_size;
+ALTER TABLE user_settings DROP COLUMN IF EXISTS default_note_type;
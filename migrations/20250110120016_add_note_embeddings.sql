@@ -0,0 +1,39 @@
+-- +goose Up
+-- Add note embeddings for semantic search
+-- NOTE: This migration is idempotent and can be safely re-run
+
+-- Semantic search is optional: without the pgvector extension (or the privilege to
+-- create it) the table is skipped and the rest of the API works as before
+-- +goose StatementBegin
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_available_extensions WHERE name = 'vector') THEN
+        RAISE NOTICE 'pgvector is not installed, skipping note embeddings';
+        RETURN;
+    END IF;
+
+    BEGIN
+        CREATE EXTENSION IF NOT EXISTS vector;
+    EXCEPTION WHEN insufficient_privilege THEN
+        RAISE NOTICE 'Not allowed to create the vector extension, skipping note embeddings';
+        RETURN;
+    END;
+
+    -- The vector size depends on the model, so it isn't fixed; vectors are only compared within one model
+    CREATE TABLE IF NOT EXISTS note_embeddings (
+        note_id UUID PRIMARY KEY REFERENCES notes(id) ON DELETE CASCADE,
+        user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+        model TEXT NOT NULL,
+        embedding vector NOT NULL,
+        note_updated_at TIMESTAMPTZ NOT NULL -- Version of the note that was embedded
+    );
+
+    CREATE INDEX IF NOT EXISTS idx_note_embeddings_user_model ON note_embeddings(user_id, model);
+END
+$$;
+-- +goose StatementEnd
+
+-- +goose Down
+-- Rollback note embeddings (the vector extension is left installed)
+
+DROP TABLE IF EXISTS note_embeddings;
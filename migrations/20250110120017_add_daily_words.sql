@@ -0,0 +1,20 @@
+-- +goose Up
+-- Add daily words written and the daily word goal
+-- NOTE: This migration is idempotent and can be safely re-run
+
+-- Words written per user per day (in the user's timezone), for goals and streaks
+-- New notes count all their words, edits only the words they add
+CREATE TABLE IF NOT EXISTS daily_words (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    words INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (user_id, day)
+);
+
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS daily_word_goal INTEGER NOT NULL DEFAULT 500;
+
+-- +goose Down
+-- Rollback daily words
+
+ALTER TABLE user_settings DROP COLUMN IF EXISTS daily_word_goal;
+DROP TABLE IF EXISTS daily_words;
@@ -0,0 +1,12 @@
+-- +goose Up
+-- Add user roles for instance administration
+-- NOTE: This migration is idempotent and can be safely re-run
+
+-- 'admin' users can list and deactivate accounts and see instance-wide stats
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'user'
+    CHECK (role IN ('user', 'admin'));
+
+-- +goose Down
+-- Rollback user roles
+
+ALTER TABLE users DROP COLUMN IF EXISTS role;
@@ -0,0 +1,110 @@
+-- +goose Up
+-- Weight the search vector by where a word appears: title A, content B, tag names C, attachment filenames D
+-- NOTE: This migration is idempotent and can be safely re-run
+
+-- Tag names of a note, with "parent/child" split into words
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION note_tag_text(p_note_id UUID) RETURNS TEXT AS $$
+    SELECT coalesce(string_agg(replace(t.name, '/', ' '), ' '), '')
+    FROM note_tags nt
+    JOIN tags t ON t.id = nt.tag_id
+    WHERE nt.note_id = p_note_id;
+$$ LANGUAGE sql STABLE;
+-- +goose StatementEnd
+
+-- Attachment filenames of a note, with separators like "-" and "." split into words
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION note_attachment_text(p_note_id UUID) RETURNS TEXT AS $$
+    SELECT coalesce(string_agg(regexp_replace(filename, '[._/-]+', ' ', 'g'), ' '), '')
+    FROM attachments
+    WHERE note_id = p_note_id;
+$$ LANGUAGE sql STABLE;
+-- +goose StatementEnd
+
+-- Tags and filenames are stored in plain text, so they're indexed for encrypted notes too
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION notes_tsv_trigger() RETURNS trigger AS $$
+BEGIN
+    NEW.content_tsv :=
+        setweight(to_tsvector('english', coalesce(NEW.title, '')), 'A') ||
+        setweight(to_tsvector('english', CASE WHEN NEW.encrypted THEN '' ELSE coalesce(NEW.content, '') END), 'B') ||
+        setweight(to_tsvector('english', note_tag_text(NEW.id)), 'C') ||
+        setweight(to_tsvector('english', note_attachment_text(NEW.id)), 'D');
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- Rebuild the search vector of notes whose tags or attachments change
+-- Updating a note runs notes_tsv_trigger, which reads the current tags and filenames.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION refresh_note_tsv() RETURNS trigger AS $$
+BEGIN
+    IF TG_TABLE_NAME = 'tags' THEN
+        UPDATE notes SET content_tsv = NULL
+        WHERE id IN (SELECT note_id FROM note_tags WHERE tag_id = NEW.id);
+    ELSIF TG_OP = 'DELETE' THEN
+        UPDATE notes SET content_tsv = NULL WHERE id = OLD.note_id;
+    ELSE
+        UPDATE notes SET content_tsv = NULL WHERE id = NEW.note_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+DROP TRIGGER IF EXISTS note_tags_refresh_tsv ON note_tags;
+CREATE TRIGGER note_tags_refresh_tsv AFTER INSERT OR DELETE ON note_tags
+    FOR EACH ROW EXECUTE FUNCTION refresh_note_tsv();
+
+DROP TRIGGER IF EXISTS tags_refresh_tsv ON tags;
+CREATE TRIGGER tags_refresh_tsv AFTER UPDATE OF name ON tags
+    FOR EACH ROW WHEN (OLD.name IS DISTINCT FROM NEW.name) EXECUTE FUNCTION refresh_note_tsv();
+
+DROP TRIGGER IF EXISTS attachments_refresh_tsv ON attachments;
+CREATE TRIGGER attachments_refresh_tsv AFTER INSERT OR DELETE OR UPDATE OF filename ON attachments
+    FOR EACH ROW EXECUTE FUNCTION refresh_note_tsv();
+
+-- Tagging a note or renaming a tag isn't an edit: only bump updated_at for direct updates
+DROP TRIGGER IF EXISTS update_notes_updated_at ON notes;
+CREATE TRIGGER update_notes_updated_at BEFORE UPDATE ON notes
+    FOR EACH ROW WHEN (pg_trigger_depth() = 0) EXECUTE FUNCTION update_updated_at_column();
+
+-- Rebuild the search vector of existing notes, keeping their updated_at
+ALTER TABLE notes DISABLE TRIGGER update_notes_updated_at;
+UPDATE notes SET content_tsv = NULL;
+ALTER TABLE notes ENABLE TRIGGER update_notes_updated_at;
+
+-- +goose Down
+-- Rollback weighted search
+
+DROP TRIGGER IF EXISTS note_tags_refresh_tsv ON note_tags;
+DROP TRIGGER IF EXISTS tags_refresh_tsv ON tags;
+DROP TRIGGER IF EXISTS attachments_refresh_tsv ON attachments;
+DROP FUNCTION IF EXISTS refresh_note_tsv();
+
+DROP TRIGGER IF EXISTS update_notes_updated_at ON notes;
+CREATE TRIGGER update_notes_updated_at BEFORE UPDATE ON notes
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION notes_tsv_trigger() RETURNS trigger AS $$
+BEGIN
+    IF NEW.encrypted THEN
+        NEW.content_tsv := setweight(to_tsvector('english', coalesce(NEW.title, '')), 'A');
+    ELSE
+        NEW.content_tsv :=
+            setweight(to_tsvector('english', coalesce(NEW.title, '')), 'A') ||
+            setweight(to_tsvector('english', coalesce(NEW.content, '')), 'B');
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+ALTER TABLE notes DISABLE TRIGGER update_notes_updated_at;
+UPDATE notes SET content_tsv = NULL;
+ALTER TABLE notes ENABLE TRIGGER update_notes_updated_at;
+
+DROP FUNCTION IF EXISTS note_attachment_text(UUID);
+DROP FUNCTION IF EXISTS note_tag_text(UUID);
@@ -0,0 +1,11 @@
+-- +goose Up
+-- Add the forgotten notes threshold
+-- NOTE: This migration is idempotent and can be safely re-run
+
+-- Days without being opened after which a note is resurfaced as forgotten
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS forgotten_days INTEGER NOT NULL DEFAULT 30;
+
+-- +goose Down
+-- Rollback the forgotten notes threshold
+
+ALTER TABLE user_settings DROP COLUMN IF EXISTS forgotten_days;
@@ -0,0 +1,11 @@
+-- +goose Up
+-- Add the quick capture target
+-- NOTE: This migration is idempotent and can be safely re-run
+
+-- Note that 'capture' appends to: the Inbox note or today's daily note
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS capture_target VARCHAR(10) NOT NULL DEFAULT 'inbox';
+
+-- +goose Down
+-- Rollback the quick capture target
+
+ALTER TABLE user_settings DROP COLUMN IF EXISTS capture_target;
@@ -0,0 +1,20 @@
+-- +goose Up
+-- Add public links for notes
+-- NOTE: This migration is idempotent and can be safely re-run
+
+-- Notes published at a public, read-only link (one link per note, revoked by deleting the row)
+CREATE TABLE IF NOT EXISTS public_links (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    note_id UUID NOT NULL UNIQUE REFERENCES notes(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- Indexes for public links (idempotent)
+CREATE INDEX IF NOT EXISTS idx_public_links_user_id ON public_links(user_id);
+
+-- +goose Down
+-- Rollback public links
+
+DROP INDEX IF EXISTS idx_public_links_user_id;
+DROP TABLE IF EXISTS public_links;
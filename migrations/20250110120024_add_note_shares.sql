@@ -0,0 +1,24 @@
+-- +goose Up
+-- Add note sharing between users
+-- NOTE: This migration is idempotent and can be safely re-run
+
+-- Notes shared with other users of the instance, to read or to read and edit
+CREATE TABLE IF NOT EXISTS note_shares (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    note_id UUID NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+    owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    shared_with UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    permission VARCHAR(10) NOT NULL DEFAULT 'read' CHECK (permission IN ('read', 'write')),
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    UNIQUE (note_id, shared_with)
+);
+
+-- Indexes for note shares (idempotent)
+CREATE INDEX IF NOT EXISTS idx_note_shares_shared_with ON note_shares(shared_with);
+
+-- +goose Down
+-- Rollback note sharing
+
+DROP INDEX IF EXISTS idx_note_shares_shared_with;
+DROP TABLE IF EXISTS note_shares;
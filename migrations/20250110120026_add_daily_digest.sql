@@ -0,0 +1,17 @@
+-- +goose Up
+-- Add the daily digest posted to Slack or Discord
+-- NOTE: This migration is idempotent and can be safely re-run
+
+-- Incoming webhook URL the digest is posted to, NULL = no digest
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS digest_url TEXT;
+-- Hour of the day, in the user's timezone, the digest is posted at
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS digest_hour INTEGER NOT NULL DEFAULT 8;
+-- Day, in the user's timezone, the last digest was posted on
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS digest_sent_on DATE;
+
+-- +goose Down
+-- Rollback the daily digest
+
+ALTER TABLE user_settings DROP COLUMN IF EXISTS digest_sent_on;
+ALTER TABLE user_settings DROP COLUMN IF EXISTS digest_hour;
+ALTER TABLE user_settings DROP COLUMN IF EXISTS digest_url;
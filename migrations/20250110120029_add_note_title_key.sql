@@ -0,0 +1,23 @@
+-- +goose Up
+-- Match wiki link titles ignoring case, spacing, dashes and underscores
+-- NOTE: This migration is idempotent and can be safely re-run
+
+-- The key a [[link]] title is matched by: lowercased, with each run of whitespace, '-' and '_'
+-- as a single space, so [[go-lang]], [[Go_Lang]] and [[go  lang]] all find "Go Lang".
+-- Letters are never split or joined: [[golang]] does not find "Go Lang".
+-- util.LinkParser.NormalizeTitle computes the same key in Go.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION note_title_key(title TEXT) RETURNS TEXT AS $$
+    SELECT btrim(regexp_replace(lower(title), '[[:space:]_-]+', ' ', 'g'))
+$$ LANGUAGE sql IMMUTABLE;
+-- +goose StatementEnd
+
+CREATE INDEX IF NOT EXISTS idx_notes_user_title_key ON notes(user_id, note_title_key(title)) WHERE is_deleted = false;
+CREATE INDEX IF NOT EXISTS idx_unresolved_links_user_title_key ON unresolved_links(user_id, note_title_key(target_title));
+
+-- +goose Down
+-- Rollback link title keys
+
+DROP INDEX IF EXISTS idx_unresolved_links_user_title_key;
+DROP INDEX IF EXISTS idx_notes_user_title_key;
+DROP FUNCTION IF EXISTS note_title_key(TEXT);
@@ -0,0 +1,30 @@
+-- +goose Up
+-- Connect unresolved links that name an existing note
+-- NOTE: This migration is idempotent and can be safely re-run
+
+-- Links are resolved when their note is saved or a note with their title is created, so links
+-- left unresolved before titles matched ignoring case and spacing wait for either. This links
+-- them now, to the note FindByLinkTitle would pick.
+INSERT INTO links (user_id, source_note_id, target_note_id, link_context, created_at)
+SELECT u.user_id, u.source_note_id, t.id, u.link_context, NOW()
+FROM unresolved_links u
+CROSS JOIN LATERAL (
+    SELECT n.id
+    FROM notes n
+    WHERE n.user_id = u.user_id AND n.is_deleted = false
+      AND note_title_key(n.title) = note_title_key(u.target_title)
+    ORDER BY n.title = u.target_title DESC, lower(n.title) = lower(u.target_title) DESC, n.created_at DESC
+    LIMIT 1
+) t
+ON CONFLICT (source_note_id, target_note_id) DO NOTHING;
+
+DELETE FROM unresolved_links u
+WHERE EXISTS (
+    SELECT 1 FROM notes n
+    WHERE n.user_id = u.user_id AND n.is_deleted = false
+      AND note_title_key(n.title) = note_title_key(u.target_title)
+);
+
+-- +goose Down
+-- Links connected here are regular links now, nothing to roll back
+SELECT 1;
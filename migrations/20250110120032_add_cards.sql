@@ -0,0 +1,41 @@
+-- +goose Up
+-- Add spaced repetition flashcards extracted from notes
+-- NOTE: This migration is idempotent and can be safely re-run
+
+-- Cards ("Q:: ... A:: ..." pairs, kept in sync whenever the note is saved). A card keeps its
+-- SM-2 schedule while its question stays the same; due_on is NULL until the first review.
+CREATE TABLE IF NOT EXISTS cards (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    note_id UUID NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+    line_number INT NOT NULL,
+    question TEXT NOT NULL,
+    answer TEXT NOT NULL,
+    ease_factor DOUBLE PRECISION NOT NULL DEFAULT 2.5,
+    interval_days INT NOT NULL DEFAULT 0,
+    repetitions INT NOT NULL DEFAULT 0,
+    due_on DATE,
+    last_reviewed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    UNIQUE (note_id, question)
+);
+
+-- Indexes for cards (idempotent)
+CREATE INDEX IF NOT EXISTS idx_cards_user_due ON cards(user_id, due_on);
+
+-- Extract single-line cards from existing notes; answers on lines of their own are
+-- picked up the next time their note is saved
+INSERT INTO cards (user_id, note_id, line_number, question, answer)
+SELECT n.user_id, n.id, l.line_number::int, m[1], m[2]
+FROM notes n
+CROSS JOIN LATERAL regexp_split_to_table(n.content, E'\n') WITH ORDINALITY AS l(line, line_number)
+CROSS JOIN LATERAL regexp_match(l.line, '^\s*Q::\s*(.*?\S)\s*A::\s*(.*\S)\s*$') AS m
+WHERE m IS NOT NULL
+  AND n.encrypted = false
+ON CONFLICT (note_id, question) DO NOTHING;
+
+-- +goose Down
+-- Rollback cards
+
+DROP INDEX IF EXISTS idx_cards_user_due;
+DROP TABLE IF EXISTS cards;
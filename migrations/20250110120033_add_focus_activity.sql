@@ -0,0 +1,16 @@
+-- +goose Up
+-- Allow logging focus sessions as activity
+-- NOTE: This migration is idempotent and can be safely re-run
+
+-- A focus session is logged against its note with its length in metadata.duration_seconds
+ALTER TABLE activity_log DROP CONSTRAINT IF EXISTS activity_log_action_check;
+ALTER TABLE activity_log ADD CONSTRAINT activity_log_action_check
+    CHECK (action IN ('create', 'update', 'view', 'search', 'delete', 'login', 'logout', 'focus'));
+
+-- +goose Down
+-- Rollback focus activity
+
+DELETE FROM activity_log WHERE action = 'focus';
+ALTER TABLE activity_log DROP CONSTRAINT IF EXISTS activity_log_action_check;
+ALTER TABLE activity_log ADD CONSTRAINT activity_log_action_check
+    CHECK (action IN ('create', 'update', 'view', 'search', 'delete', 'login', 'logout'));
@@ -0,0 +1,26 @@
+-- +goose Up
+-- Keep updated_at when only a note's read progress changes
+-- NOTE: This migration is idempotent and can be safely re-run
+
+-- Read progress is saved in metadata.read_progress as a note is scrolled; reading isn't an edit,
+-- so the rest of the row has to change for updated_at to be bumped
+DROP TRIGGER IF EXISTS update_notes_updated_at ON notes;
+CREATE TRIGGER update_notes_updated_at BEFORE UPDATE ON notes
+    FOR EACH ROW WHEN (
+        pg_trigger_depth() = 0 AND
+        (to_jsonb(OLD) - 'metadata') || jsonb_build_object('metadata', COALESCE(OLD.metadata, '{}'::jsonb) - 'read_progress')
+            IS DISTINCT FROM
+        (to_jsonb(NEW) - 'metadata') || jsonb_build_object('metadata', COALESCE(NEW.metadata, '{}'::jsonb) - 'read_progress')
+    )
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- +goose Down
+-- Rollback read progress
+
+DROP TRIGGER IF EXISTS update_notes_updated_at ON notes;
+CREATE TRIGGER update_notes_updated_at BEFORE UPDATE ON notes
+    FOR EACH ROW WHEN (pg_trigger_depth() = 0) EXECUTE FUNCTION update_updated_at_column();
+
+ALTER TABLE notes DISABLE TRIGGER update_notes_updated_at;
+UPDATE notes SET metadata = metadata - 'read_progress' WHERE metadata ? 'read_progress';
+ALTER TABLE notes ENABLE TRIGGER update_notes_updated_at;
@@ -0,0 +1,29 @@
+-- +goose Up
+-- Keep updated_at when a note is only viewed
+-- NOTE: This migration is idempotent and can be safely re-run
+
+-- Opening a note bumps access_count and last_accessed_at; a view isn't an edit, so like read
+-- progress they're left out when deciding whether the row changed. Otherwise every view would
+-- change the note's ETag and mark its embedding stale.
+DROP TRIGGER IF EXISTS update_notes_updated_at ON notes;
+CREATE TRIGGER update_notes_updated_at BEFORE UPDATE ON notes
+    FOR EACH ROW WHEN (
+        pg_trigger_depth() = 0 AND
+        (to_jsonb(OLD) - 'metadata' - 'access_count' - 'last_accessed_at') || jsonb_build_object('metadata', COALESCE(OLD.metadata, '{}'::jsonb) - 'read_progress')
+            IS DISTINCT FROM
+        (to_jsonb(NEW) - 'metadata' - 'access_count' - 'last_accessed_at') || jsonb_build_object('metadata', COALESCE(NEW.metadata, '{}'::jsonb) - 'read_progress')
+    )
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- +goose Down
+-- Rollback keeping updated_at on views
+
+DROP TRIGGER IF EXISTS update_notes_updated_at ON notes;
+CREATE TRIGGER update_notes_updated_at BEFORE UPDATE ON notes
+    FOR EACH ROW WHEN (
+        pg_trigger_depth() = 0 AND
+        (to_jsonb(OLD) - 'metadata') || jsonb_build_object('metadata', COALESCE(OLD.metadata, '{}'::jsonb) - 'read_progress')
+            IS DISTINCT FROM
+        (to_jsonb(NEW) - 'metadata') || jsonb_build_object('metadata', COALESCE(NEW.metadata, '{}'::jsonb) - 'read_progress')
+    )
+    EXECUTE FUNCTION update_updated_at_column();
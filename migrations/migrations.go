@@ -0,0 +1,17 @@
+// Package migrations holds the SQL migrations of the database schema
+// They are embedded in the API binary, which can apply them on start (DB_AUTO_MIGRATE)
+// or with `api migrate`; cmd/migrate is the full goose command line for development.
+// The sqlite directory holds the schema of SQLite databases (DB_DRIVER=sqlite).
+package migrations
+
+import "embed"
+
+// FS holds the migration files
+//
+//go:embed *.sql
+var FS embed.FS
+
+// SQLiteFS holds the SQLite migration files, under sqlite/
+//
+//go:embed sqlite/*.sql
+var SQLiteFS embed.FS
@@ -0,0 +1,329 @@
+-- +goose Up
+-- Database schema for Personal Knowledge Garden on SQLite (DB_DRIVER=sqlite)
+-- NOTE: This migration is idempotent and can be safely re-run
+
+-- The schema matches the one the Postgres migrations build, table for table and column for
+-- column, so backups restore into either database. Column types keep their Postgres names,
+-- which SQLite only reads for the type affinity; the repositories store UUIDs as text,
+-- times as UTC text that sorts in time order, dates as YYYY-MM-DD, and arrays and JSONB as
+-- JSON text. IDs and times default to values calculated in plain SQL, so the file stays
+-- usable from the sqlite3 shell.
+
+-- Users table
+CREATE TABLE IF NOT EXISTS users (
+    id UUID PRIMARY KEY DEFAULT (lower(hex(randomblob(4))) || '-' || lower(hex(randomblob(2))) || '-4' || substr(lower(hex(randomblob(2))), 2) || '-' || substr('89ab', abs(random()) % 4 + 1, 1) || substr(lower(hex(randomblob(2))), 2) || '-' || lower(hex(randomblob(6)))),
+    email VARCHAR(255) UNIQUE NOT NULL,
+    password_hash VARCHAR(255) NOT NULL,
+    username VARCHAR(100) UNIQUE NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT (strftime('%Y-%m-%dT%H:%M:%f', 'now') || '000Z'),
+    updated_at TIMESTAMPTZ DEFAULT (strftime('%Y-%m-%dT%H:%M:%f', 'now') || '000Z'),
+    last_login_at TIMESTAMPTZ,
+    is_active BOOLEAN DEFAULT TRUE,
+    totp_secret TEXT,
+    totp_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    is_verified BOOLEAN NOT NULL DEFAULT FALSE,
+    deleted_at TIMESTAMPTZ,
+    role VARCHAR(20) NOT NULL DEFAULT 'user' CHECK (role IN ('user', 'admin'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_users_created_at ON users(created_at);
+
+-- Refresh tokens table
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id UUID PRIMARY KEY DEFAULT (lower(hex(randomblob(4))) || '-' || lower(hex(randomblob(2))) || '-4' || substr(lower(hex(randomblob(2))), 2) || '-' || substr('89ab', abs(random()) % 4 + 1, 1) || substr(lower(hex(randomblob(2))), 2) || '-' || lower(hex(randomblob(6)))),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(255) NOT NULL UNIQUE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT (strftime('%Y-%m-%dT%H:%M:%f', 'now') || '000Z'),
+    is_revoked BOOLEAN DEFAULT FALSE,
+    user_agent TEXT NOT NULL DEFAULT '',
+    ip_address VARCHAR(45) NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_id ON refresh_tokens(user_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_expires_at ON refresh_tokens(expires_at);
+
+-- Notes table
+CREATE TABLE IF NOT EXISTS notes (
+    id UUID PRIMARY KEY DEFAULT (lower(hex(randomblob(4))) || '-' || lower(hex(randomblob(2))) || '-4' || substr(lower(hex(randomblob(2))), 2) || '-' || substr('89ab', abs(random()) % 4 + 1, 1) || substr(lower(hex(randomblob(2))), 2) || '-' || lower(hex(randomblob(6)))),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    title VARCHAR(500) NOT NULL,
+    content TEXT DEFAULT '',
+    note_type VARCHAR(50) DEFAULT 'note', -- Validated by the API, SQLite can't drop a CHECK later
+    word_count INT DEFAULT 0,
+    reading_time_minutes INT DEFAULT 0,
+    is_deleted BOOLEAN DEFAULT FALSE,
+    deleted_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT (strftime('%Y-%m-%dT%H:%M:%f', 'now') || '000Z'),
+    updated_at TIMESTAMPTZ DEFAULT (strftime('%Y-%m-%dT%H:%M:%f', 'now') || '000Z'),
+    last_accessed_at TIMESTAMPTZ,
+    access_count INT DEFAULT 0,
+    metadata JSONB DEFAULT '{}',
+    encrypted BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE INDEX IF NOT EXISTS idx_notes_created_at ON notes(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_notes_updated_at ON notes(updated_at DESC);
+CREATE INDEX IF NOT EXISTS idx_notes_note_type ON notes(note_type);
+CREATE INDEX IF NOT EXISTS idx_notes_user_id_is_deleted ON notes(user_id, is_deleted);
+
+-- Tags table
+CREATE TABLE IF NOT EXISTS tags (
+    id UUID PRIMARY KEY DEFAULT (lower(hex(randomblob(4))) || '-' || lower(hex(randomblob(2))) || '-4' || substr(lower(hex(randomblob(2))), 2) || '-' || substr('89ab', abs(random()) % 4 + 1, 1) || substr(lower(hex(randomblob(2))), 2) || '-' || lower(hex(randomblob(6)))),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    color VARCHAR(7),
+    created_at TIMESTAMPTZ DEFAULT (strftime('%Y-%m-%dT%H:%M:%f', 'now') || '000Z'),
+    parent_id UUID REFERENCES tags(id) ON DELETE SET NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_user_name ON tags(user_id, name);
+CREATE INDEX IF NOT EXISTS idx_tags_parent_id ON tags(parent_id);
+
+-- Note tags junction table
+CREATE TABLE IF NOT EXISTS note_tags (
+    note_id UUID REFERENCES notes(id) ON DELETE CASCADE,
+    tag_id UUID REFERENCES tags(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ DEFAULT (strftime('%Y-%m-%dT%H:%M:%f', 'now') || '000Z'),
+    PRIMARY KEY (note_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_note_tags_tag_id ON note_tags(tag_id);
+
+-- Links table for bidirectional linking
+CREATE TABLE IF NOT EXISTS links (
+    id UUID PRIMARY KEY DEFAULT (lower(hex(randomblob(4))) || '-' || lower(hex(randomblob(2))) || '-4' || substr(lower(hex(randomblob(2))), 2) || '-' || substr('89ab', abs(random()) % 4 + 1, 1) || substr(lower(hex(randomblob(2))), 2) || '-' || lower(hex(randomblob(6)))),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    source_note_id UUID REFERENCES notes(id) ON DELETE CASCADE,
+    target_note_id UUID REFERENCES notes(id) ON DELETE CASCADE,
+    link_context TEXT,
+    created_at TIMESTAMPTZ DEFAULT (strftime('%Y-%m-%dT%H:%M:%f', 'now') || '000Z')
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_links_source_target ON links(source_note_id, target_note_id);
+CREATE INDEX IF NOT EXISTS idx_links_user_id ON links(user_id);
+CREATE INDEX IF NOT EXISTS idx_links_target_note_id ON links(target_note_id);
+
+-- Links to notes that don't exist yet, resolved when a note with the title is created
+CREATE TABLE IF NOT EXISTS unresolved_links (
+    id UUID PRIMARY KEY DEFAULT (lower(hex(randomblob(4))) || '-' || lower(hex(randomblob(2))) || '-4' || substr(lower(hex(randomblob(2))), 2) || '-' || substr('89ab', abs(random()) % 4 + 1, 1) || substr(lower(hex(randomblob(2))), 2) || '-' || lower(hex(randomblob(6)))),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    source_note_id UUID NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+    target_title VARCHAR(500) NOT NULL,
+    link_context TEXT,
+    created_at TIMESTAMPTZ DEFAULT (strftime('%Y-%m-%dT%H:%M:%f', 'now') || '000Z')
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unresolved_links_source_title ON unresolved_links(source_note_id, target_title);
+CREATE INDEX IF NOT EXISTS idx_unresolved_links_user_title ON unresolved_links(user_id, target_title);
+
+-- Activity log table
+CREATE TABLE IF NOT EXISTS activity_log (
+    id UUID PRIMARY KEY DEFAULT (lower(hex(randomblob(4))) || '-' || lower(hex(randomblob(2))) || '-4' || substr(lower(hex(randomblob(2))), 2) || '-' || substr('89ab', abs(random()) % 4 + 1, 1) || substr(lower(hex(randomblob(2))), 2) || '-' || lower(hex(randomblob(6)))),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    note_id UUID REFERENCES notes(id) ON DELETE CASCADE,
+    action VARCHAR(50) NOT NULL CHECK (action IN ('create', 'update', 'view', 'search', 'delete', 'login', 'logout')),
+    metadata JSONB DEFAULT '{}',
+    created_at TIMESTAMPTZ DEFAULT (strftime('%Y-%m-%dT%H:%M:%f', 'now') || '000Z')
+);
+
+CREATE INDEX IF NOT EXISTS idx_activity_log_note_id ON activity_log(note_id);
+CREATE INDEX IF NOT EXISTS idx_activity_log_action ON activity_log(action);
+CREATE INDEX IF NOT EXISTS idx_activity_log_created_at ON activity_log(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_activity_log_user_created ON activity_log(user_id, created_at DESC);
+
+-- Note revisions table
+CREATE TABLE IF NOT EXISTS note_revisions (
+    id UUID PRIMARY KEY DEFAULT (lower(hex(randomblob(4))) || '-' || lower(hex(randomblob(2))) || '-4' || substr(lower(hex(randomblob(2))), 2) || '-' || substr('89ab', abs(random()) % 4 + 1, 1) || substr(lower(hex(randomblob(2))), 2) || '-' || lower(hex(randomblob(6)))),
+    note_id UUID NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    revision_number INT NOT NULL,
+    title VARCHAR(500) NOT NULL,
+    content TEXT DEFAULT '',
+    created_at TIMESTAMPTZ DEFAULT (strftime('%Y-%m-%dT%H:%M:%f', 'now') || '000Z')
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_note_revisions_note_number ON note_revisions(note_id, revision_number);
+CREATE INDEX IF NOT EXISTS idx_note_revisions_user_id ON note_revisions(user_id);
+
+-- User settings table
+CREATE TABLE IF NOT EXISTS user_settings (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    daily_template TEXT,
+    updated_at TIMESTAMPTZ DEFAULT (strftime('%Y-%m-%dT%H:%M:%f', 'now') || '000Z'),
+    default_note_type VARCHAR(50) NOT NULL DEFAULT 'note',
+    page_size INTEGER NOT NULL DEFAULT 20,
+    timezone TEXT NOT NULL DEFAULT 'UTC',
+    week_start VARCHAR(10) NOT NULL DEFAULT 'monday',
+    theme VARCHAR(50) NOT NULL DEFAULT 'dark',
+    daily_word_goal INTEGER NOT NULL DEFAULT 500
+);
+
+-- Attachments table
+CREATE TABLE IF NOT EXISTS attachments (
+    id UUID PRIMARY KEY DEFAULT (lower(hex(randomblob(4))) || '-' || lower(hex(randomblob(2))) || '-4' || substr(lower(hex(randomblob(2))), 2) || '-' || substr('89ab', abs(random()) % 4 + 1, 1) || substr(lower(hex(randomblob(2))), 2) || '-' || lower(hex(randomblob(6)))),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    note_id UUID NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL DEFAULT 'application/octet-stream',
+    size_bytes BIGINT NOT NULL DEFAULT 0,
+    storage_key TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT (strftime('%Y-%m-%dT%H:%M:%f', 'now') || '000Z')
+);
+
+CREATE INDEX IF NOT EXISTS idx_attachments_note_id ON attachments(note_id);
+CREATE INDEX IF NOT EXISTS idx_attachments_user_id ON attachments(user_id);
+
+-- Tasks table, the checklist items of notes
+CREATE TABLE IF NOT EXISTS tasks (
+    id UUID PRIMARY KEY DEFAULT (lower(hex(randomblob(4))) || '-' || lower(hex(randomblob(2))) || '-4' || substr(lower(hex(randomblob(2))), 2) || '-' || substr('89ab', abs(random()) % 4 + 1, 1) || substr(lower(hex(randomblob(2))), 2) || '-' || lower(hex(randomblob(6)))),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    note_id UUID NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+    line_number INT NOT NULL,
+    content TEXT NOT NULL,
+    completed BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ DEFAULT (strftime('%Y-%m-%dT%H:%M:%f', 'now') || '000Z')
+);
+
+CREATE INDEX IF NOT EXISTS idx_tasks_user_completed ON tasks(user_id, completed);
+CREATE INDEX IF NOT EXISTS idx_tasks_note_id ON tasks(note_id);
+
+-- Password reset tokens table
+CREATE TABLE IF NOT EXISTS password_reset_tokens (
+    id UUID PRIMARY KEY DEFAULT (lower(hex(randomblob(4))) || '-' || lower(hex(randomblob(2))) || '-4' || substr(lower(hex(randomblob(2))), 2) || '-' || substr('89ab', abs(random()) % 4 + 1, 1) || substr(lower(hex(randomblob(2))), 2) || '-' || lower(hex(randomblob(6)))),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(255) NOT NULL UNIQUE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT (strftime('%Y-%m-%dT%H:%M:%f', 'now') || '000Z'),
+    used_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_password_reset_tokens_user_id ON password_reset_tokens(user_id);
+CREATE INDEX IF NOT EXISTS idx_password_reset_tokens_expires_at ON password_reset_tokens(expires_at);
+
+-- Email verification tokens table
+CREATE TABLE IF NOT EXISTS email_verification_tokens (
+    id UUID PRIMARY KEY DEFAULT (lower(hex(randomblob(4))) || '-' || lower(hex(randomblob(2))) || '-4' || substr(lower(hex(randomblob(2))), 2) || '-' || substr('89ab', abs(random()) % 4 + 1, 1) || substr(lower(hex(randomblob(2))), 2) || '-' || lower(hex(randomblob(6)))),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(255) NOT NULL UNIQUE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT (strftime('%Y-%m-%dT%H:%M:%f', 'now') || '000Z')
+);
+
+CREATE INDEX IF NOT EXISTS idx_email_verification_tokens_user_id ON email_verification_tokens(user_id);
+CREATE INDEX IF NOT EXISTS idx_email_verification_tokens_expires_at ON email_verification_tokens(expires_at);
+
+-- Note embeddings table, for semantic search
+-- There's no vector extension to require, so the table always exists; vectors are JSON
+-- arrays compared in Go, and only within one model.
+CREATE TABLE IF NOT EXISTS note_embeddings (
+    note_id UUID PRIMARY KEY REFERENCES notes(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    model TEXT NOT NULL,
+    embedding TEXT NOT NULL,
+    note_updated_at TIMESTAMPTZ NOT NULL -- Version of the note that was embedded
+);
+
+CREATE INDEX IF NOT EXISTS idx_note_embeddings_user_model ON note_embeddings(user_id, model);
+
+-- Words written per user and day
+CREATE TABLE IF NOT EXISTS daily_words (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    day TEXT NOT NULL, -- YYYY-MM-DD
+    words INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (user_id, day)
+);
+
+-- updated_at follows the changes of a row, unless the update sets it itself
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS update_users_updated_at AFTER UPDATE ON users
+    FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+    UPDATE users SET updated_at = strftime('%Y-%m-%dT%H:%M:%f', 'now') || '000Z' WHERE id = NEW.id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS update_notes_updated_at AFTER UPDATE ON notes
+    FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+    UPDATE notes SET updated_at = strftime('%Y-%m-%dT%H:%M:%f', 'now') || '000Z' WHERE id = NEW.id;
+END;
+-- +goose StatementEnd
+
+-- Full-text search
+-- notes_fts indexes the title and content of each note. Its rowids are the ids of
+-- notes_fts_keys, since VACUUM may renumber the rowids of notes. notes_fts_text is the text a
+-- note is indexed with, encrypted notes only their title. The triggers below keep the index
+-- up to date.
+CREATE TABLE IF NOT EXISTS notes_fts_keys (
+    id INTEGER PRIMARY KEY,
+    note_id UUID NOT NULL UNIQUE
+);
+
+CREATE VIRTUAL TABLE IF NOT EXISTS notes_fts USING fts5(title, content, tokenize = 'porter unicode61');
+
+-- notes_fts_terms lists every indexed word of every note, FindRelated compares notes by them
+CREATE VIRTUAL TABLE IF NOT EXISTS notes_fts_terms USING fts5vocab(notes_fts, instance);
+
+CREATE VIEW IF NOT EXISTS notes_fts_text AS
+SELECT k.id AS fts_rowid,
+       n.id AS note_id,
+       n.title AS title,
+       CASE WHEN n.encrypted THEN '' ELSE COALESCE(n.content, '') END AS content
+FROM notes n
+JOIN notes_fts_keys k ON k.note_id = n.id;
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS notes_fts_insert AFTER INSERT ON notes
+BEGIN
+    INSERT INTO notes_fts_keys (note_id) VALUES (NEW.id);
+    INSERT INTO notes_fts (rowid, title, content)
+    SELECT fts_rowid, title, content FROM notes_fts_text WHERE note_id = NEW.id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS notes_fts_update AFTER UPDATE OF title, content, encrypted ON notes
+BEGIN
+    UPDATE notes_fts SET (title, content) = (SELECT title, content FROM notes_fts_text WHERE note_id = NEW.id)
+    WHERE rowid = (SELECT id FROM notes_fts_keys WHERE note_id = NEW.id);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS notes_fts_delete AFTER DELETE ON notes
+BEGIN
+    DELETE FROM notes_fts WHERE rowid = (SELECT id FROM notes_fts_keys WHERE note_id = OLD.id);
+    DELETE FROM notes_fts_keys WHERE note_id = OLD.id;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+-- Rollback the schema
+
+DROP TRIGGER IF EXISTS notes_fts_delete;
+DROP TRIGGER IF EXISTS notes_fts_update;
+DROP TRIGGER IF EXISTS notes_fts_insert;
+DROP VIEW IF EXISTS notes_fts_text;
+DROP TABLE IF EXISTS notes_fts_terms;
+DROP TABLE IF EXISTS notes_fts;
+DROP TABLE IF EXISTS notes_fts_keys;
+DROP TRIGGER IF EXISTS update_notes_updated_at;
+DROP TRIGGER IF EXISTS update_users_updated_at;
+DROP TABLE IF EXISTS daily_words;
+DROP TABLE IF EXISTS note_embeddings;
+DROP TABLE IF EXISTS email_verification_tokens;
+DROP TABLE IF EXISTS password_reset_tokens;
+DROP TABLE IF EXISTS tasks;
+DROP TABLE IF EXISTS attachments;
+DROP TABLE IF EXISTS user_settings;
+DROP TABLE IF EXISTS note_revisions;
+DROP TABLE IF EXISTS activity_log;
+DROP TABLE IF EXISTS unresolved_links;
+DROP TABLE IF EXISTS links;
+DROP TABLE IF EXISTS note_tags;
+DROP TABLE IF EXISTS tags;
+DROP TABLE IF EXISTS notes;
+DROP TABLE IF EXISTS refresh_tokens;
+DROP TABLE IF EXISTS users;
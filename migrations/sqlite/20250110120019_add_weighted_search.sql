@@ -0,0 +1,170 @@
+-- +goose Up
+-- Index tag names and attachment filenames with the title and content of each note
+-- NOTE: This migration is idempotent and can be safely re-run
+
+-- notes_fts gets a column for each; Search weights them like the Postgres search vector.
+-- Tags and filenames are stored in plain text, so they're indexed for encrypted notes too:
+-- tags with '/' as a word break and filenames split at '.', '_', '/' and '-'.
+DROP TRIGGER IF EXISTS notes_fts_insert;
+DROP TRIGGER IF EXISTS notes_fts_update;
+DROP TRIGGER IF EXISTS notes_fts_delete;
+DROP VIEW IF EXISTS notes_fts_text;
+DROP TABLE IF EXISTS notes_fts_terms;
+DROP TABLE IF EXISTS notes_fts;
+
+CREATE VIRTUAL TABLE IF NOT EXISTS notes_fts USING fts5(title, content, tags, attachments, tokenize = 'porter unicode61');
+
+-- notes_fts_terms lists every indexed word of every note, FindRelated compares notes by them
+CREATE VIRTUAL TABLE IF NOT EXISTS notes_fts_terms USING fts5vocab(notes_fts, instance);
+
+CREATE VIEW IF NOT EXISTS notes_fts_text AS
+SELECT k.id AS fts_rowid,
+       n.id AS note_id,
+       n.title AS title,
+       CASE WHEN n.encrypted THEN '' ELSE COALESCE(n.content, '') END AS content,
+       (SELECT COALESCE(group_concat(replace(t.name, '/', ' '), ' '), '')
+        FROM note_tags nt
+        JOIN tags t ON t.id = nt.tag_id
+        WHERE nt.note_id = n.id) AS tags,
+       (SELECT COALESCE(group_concat(replace(replace(replace(replace(a.filename, '.', ' '), '_', ' '), '/', ' '), '-', ' '), ' '), '')
+        FROM attachments a
+        WHERE a.note_id = n.id) AS attachments
+FROM notes n
+JOIN notes_fts_keys k ON k.note_id = n.id;
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS notes_fts_insert AFTER INSERT ON notes
+BEGIN
+    INSERT INTO notes_fts_keys (note_id) VALUES (NEW.id);
+    INSERT INTO notes_fts (rowid, title, content, tags, attachments)
+    SELECT fts_rowid, title, content, tags, attachments FROM notes_fts_text WHERE note_id = NEW.id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS notes_fts_update AFTER UPDATE OF title, content, encrypted ON notes
+BEGIN
+    UPDATE notes_fts SET (title, content) = (SELECT title, content FROM notes_fts_text WHERE note_id = NEW.id)
+    WHERE rowid = (SELECT id FROM notes_fts_keys WHERE note_id = NEW.id);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS notes_fts_delete AFTER DELETE ON notes
+BEGIN
+    DELETE FROM notes_fts WHERE rowid = (SELECT id FROM notes_fts_keys WHERE note_id = OLD.id);
+    DELETE FROM notes_fts_keys WHERE note_id = OLD.id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS note_tags_fts_insert AFTER INSERT ON note_tags
+BEGIN
+    UPDATE notes_fts SET tags = (SELECT tags FROM notes_fts_text WHERE note_id = NEW.note_id)
+    WHERE rowid = (SELECT id FROM notes_fts_keys WHERE note_id = NEW.note_id);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS note_tags_fts_delete AFTER DELETE ON note_tags
+BEGIN
+    UPDATE notes_fts SET tags = (SELECT tags FROM notes_fts_text WHERE note_id = OLD.note_id)
+    WHERE rowid = (SELECT id FROM notes_fts_keys WHERE note_id = OLD.note_id);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS tags_fts_update AFTER UPDATE OF name ON tags
+    FOR EACH ROW WHEN NEW.name IS NOT OLD.name
+BEGIN
+    UPDATE notes_fts SET tags = (SELECT tags FROM notes_fts_text WHERE notes_fts_text.fts_rowid = notes_fts.rowid)
+    WHERE rowid IN (
+        SELECT k.id FROM notes_fts_keys k JOIN note_tags nt ON nt.note_id = k.note_id WHERE nt.tag_id = NEW.id
+    );
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS attachments_fts_insert AFTER INSERT ON attachments
+BEGIN
+    UPDATE notes_fts SET attachments = (SELECT attachments FROM notes_fts_text WHERE note_id = NEW.note_id)
+    WHERE rowid = (SELECT id FROM notes_fts_keys WHERE note_id = NEW.note_id);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS attachments_fts_update AFTER UPDATE OF filename ON attachments
+BEGIN
+    UPDATE notes_fts SET attachments = (SELECT attachments FROM notes_fts_text WHERE note_id = NEW.note_id)
+    WHERE rowid = (SELECT id FROM notes_fts_keys WHERE note_id = NEW.note_id);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS attachments_fts_delete AFTER DELETE ON attachments
+BEGIN
+    UPDATE notes_fts SET attachments = (SELECT attachments FROM notes_fts_text WHERE note_id = OLD.note_id)
+    WHERE rowid = (SELECT id FROM notes_fts_keys WHERE note_id = OLD.note_id);
+END;
+-- +goose StatementEnd
+
+-- Index the existing notes
+INSERT INTO notes_fts (rowid, title, content, tags, attachments)
+SELECT fts_rowid, title, content, tags, attachments FROM notes_fts_text;
+
+-- +goose Down
+-- Index only the title and content again
+
+DROP TRIGGER IF EXISTS attachments_fts_delete;
+DROP TRIGGER IF EXISTS attachments_fts_update;
+DROP TRIGGER IF EXISTS attachments_fts_insert;
+DROP TRIGGER IF EXISTS tags_fts_update;
+DROP TRIGGER IF EXISTS note_tags_fts_delete;
+DROP TRIGGER IF EXISTS note_tags_fts_insert;
+DROP TRIGGER IF EXISTS notes_fts_delete;
+DROP TRIGGER IF EXISTS notes_fts_update;
+DROP TRIGGER IF EXISTS notes_fts_insert;
+DROP VIEW IF EXISTS notes_fts_text;
+DROP TABLE IF EXISTS notes_fts_terms;
+DROP TABLE IF EXISTS notes_fts;
+
+CREATE VIRTUAL TABLE IF NOT EXISTS notes_fts USING fts5(title, content, tokenize = 'porter unicode61');
+
+-- notes_fts_terms lists every indexed word of every note, FindRelated compares notes by them
+CREATE VIRTUAL TABLE IF NOT EXISTS notes_fts_terms USING fts5vocab(notes_fts, instance);
+
+CREATE VIEW IF NOT EXISTS notes_fts_text AS
+SELECT k.id AS fts_rowid,
+       n.id AS note_id,
+       n.title AS title,
+       CASE WHEN n.encrypted THEN '' ELSE COALESCE(n.content, '') END AS content
+FROM notes n
+JOIN notes_fts_keys k ON k.note_id = n.id;
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS notes_fts_insert AFTER INSERT ON notes
+BEGIN
+    INSERT INTO notes_fts_keys (note_id) VALUES (NEW.id);
+    INSERT INTO notes_fts (rowid, title, content)
+    SELECT fts_rowid, title, content FROM notes_fts_text WHERE note_id = NEW.id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS notes_fts_update AFTER UPDATE OF title, content, encrypted ON notes
+BEGIN
+    UPDATE notes_fts SET (title, content) = (SELECT title, content FROM notes_fts_text WHERE note_id = NEW.id)
+    WHERE rowid = (SELECT id FROM notes_fts_keys WHERE note_id = NEW.id);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS notes_fts_delete AFTER DELETE ON notes
+BEGIN
+    DELETE FROM notes_fts WHERE rowid = (SELECT id FROM notes_fts_keys WHERE note_id = OLD.id);
+    DELETE FROM notes_fts_keys WHERE note_id = OLD.id;
+END;
+-- +goose StatementEnd
+
+INSERT INTO notes_fts (rowid, title, content)
+SELECT fts_rowid, title, content FROM notes_fts_text;
@@ -0,0 +1,20 @@
+-- +goose Up
+-- Add custom note types per user
+-- NOTE: This migration is idempotent and can be safely re-run
+
+-- Note types defined by users, on top of the built-in note, daily, meeting and idea
+CREATE TABLE IF NOT EXISTS note_types (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    icon VARCHAR(16) NOT NULL DEFAULT '',
+    color VARCHAR(7),
+    created_at TIMESTAMPTZ DEFAULT (strftime('%Y-%m-%dT%H:%M:%f', 'now') || '000Z'),
+    PRIMARY KEY (user_id, name)
+);
+
+-- +goose Down
+-- Rollback custom note types
+
+UPDATE notes SET note_type = 'note' WHERE note_type NOT IN ('note', 'daily', 'meeting', 'idea');
+UPDATE user_settings SET default_note_type = 'note' WHERE default_note_type NOT IN ('note', 'daily', 'meeting', 'idea');
+DROP TABLE IF EXISTS note_types;
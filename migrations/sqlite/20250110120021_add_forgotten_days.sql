@@ -0,0 +1,11 @@
+-- +goose Up
+-- Add the forgotten notes threshold
+-- SQLite has no ADD COLUMN IF NOT EXISTS, so unlike the Postgres migration this one only runs once
+
+-- Days without being opened after which a note is resurfaced as forgotten
+ALTER TABLE user_settings ADD COLUMN forgotten_days INTEGER NOT NULL DEFAULT 30;
+
+-- +goose Down
+-- Rollback the forgotten notes threshold
+
+ALTER TABLE user_settings DROP COLUMN forgotten_days;
@@ -0,0 +1,11 @@
+-- +goose Up
+-- Add the quick capture target
+-- SQLite has no ADD COLUMN IF NOT EXISTS, so unlike the Postgres migration this one only runs once
+
+-- Note that 'capture' appends to: the Inbox note or today's daily note
+ALTER TABLE user_settings ADD COLUMN capture_target VARCHAR(10) NOT NULL DEFAULT 'inbox';
+
+-- +goose Down
+-- Rollback the quick capture target
+
+ALTER TABLE user_settings DROP COLUMN capture_target;
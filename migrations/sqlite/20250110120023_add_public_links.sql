@@ -0,0 +1,20 @@
+-- +goose Up
+-- Add public links for notes
+-- NOTE: This migration is idempotent and can be safely re-run
+
+-- Notes published at a public, read-only link (one link per note, revoked by deleting the row)
+CREATE TABLE IF NOT EXISTS public_links (
+    id UUID PRIMARY KEY DEFAULT (lower(hex(randomblob(4))) || '-' || lower(hex(randomblob(2))) || '-4' || substr(lower(hex(randomblob(2))), 2) || '-' || substr('89ab', abs(random()) % 4 + 1, 1) || substr(lower(hex(randomblob(2))), 2) || '-' || lower(hex(randomblob(6)))),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    note_id UUID NOT NULL UNIQUE REFERENCES notes(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ DEFAULT (strftime('%Y-%m-%dT%H:%M:%f', 'now') || '000Z')
+);
+
+-- Indexes for public links (idempotent)
+CREATE INDEX IF NOT EXISTS idx_public_links_user_id ON public_links(user_id);
+
+-- +goose Down
+-- Rollback public links
+
+DROP INDEX IF EXISTS idx_public_links_user_id;
+DROP TABLE IF EXISTS public_links;
@@ -0,0 +1,17 @@
+-- +goose Up
+-- Add the daily digest posted to Slack or Discord
+-- SQLite has no ADD COLUMN IF NOT EXISTS, so unlike the Postgres migration this one only runs once
+
+-- Incoming webhook URL the digest is posted to, NULL = no digest
+ALTER TABLE user_settings ADD COLUMN digest_url TEXT;
+-- Hour of the day, in the user's timezone, the digest is posted at
+ALTER TABLE user_settings ADD COLUMN digest_hour INTEGER NOT NULL DEFAULT 8;
+-- Day, in the user's timezone, the last digest was posted on, YYYY-MM-DD
+ALTER TABLE user_settings ADD COLUMN digest_sent_on TEXT;
+
+-- +goose Down
+-- Rollback the daily digest
+
+ALTER TABLE user_settings DROP COLUMN digest_sent_on;
+ALTER TABLE user_settings DROP COLUMN digest_hour;
+ALTER TABLE user_settings DROP COLUMN digest_url;
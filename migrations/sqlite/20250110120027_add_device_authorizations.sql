@@ -0,0 +1,26 @@
+-- +goose Up
+-- Add device authorizations, for logging in with a code on devices without a browser
+-- NOTE: This migration is idempotent and can be safely re-run
+
+-- A device polls with its secret device code (only its SHA256 hash is stored) until a
+-- signed-in user approves the short user code shown on it
+CREATE TABLE IF NOT EXISTS device_authorizations (
+    id UUID PRIMARY KEY DEFAULT (lower(hex(randomblob(4))) || '-' || lower(hex(randomblob(2))) || '-4' || substr(lower(hex(randomblob(2))), 2) || '-' || substr('89ab', abs(random()) % 4 + 1, 1) || substr(lower(hex(randomblob(2))), 2) || '-' || lower(hex(randomblob(6)))),
+    device_code_hash VARCHAR(255) NOT NULL UNIQUE,
+    user_code VARCHAR(16) NOT NULL,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE, -- Set once approved
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'denied', 'used')),
+    user_agent TEXT NOT NULL DEFAULT '', -- The device asking to be signed in
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    expires_at TIMESTAMPTZ NOT NULL,
+    last_polled_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT (strftime('%Y-%m-%dT%H:%M:%f', 'now') || '000Z')
+);
+
+CREATE INDEX IF NOT EXISTS idx_device_authorizations_user_code ON device_authorizations(user_code) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_device_authorizations_expires_at ON device_authorizations(expires_at);
+
+-- +goose Down
+-- Rollback device authorizations
+
+DROP TABLE IF EXISTS device_authorizations;
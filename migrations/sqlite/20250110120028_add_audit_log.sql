@@ -0,0 +1,25 @@
+-- +goose Up
+-- Add the audit log of security events, kept apart from the note activity log
+-- NOTE: This migration is idempotent and can be safely re-run
+
+-- user_id is empty for failed logins with an unknown email, which is kept in email.
+-- Entries outlive a deleted user so admins can still review what happened.
+CREATE TABLE IF NOT EXISTS audit_log (
+    id UUID PRIMARY KEY DEFAULT (lower(hex(randomblob(4))) || '-' || lower(hex(randomblob(2))) || '-4' || substr(lower(hex(randomblob(2))), 2) || '-' || substr('89ab', abs(random()) % 4 + 1, 1) || substr(lower(hex(randomblob(2))), 2) || '-' || lower(hex(randomblob(6)))),
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    event VARCHAR(50) NOT NULL,
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    metadata JSONB,
+    created_at TIMESTAMPTZ DEFAULT (strftime('%Y-%m-%dT%H:%M:%f', 'now') || '000Z')
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_user_created ON audit_log(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_log_event ON audit_log(event);
+
+-- +goose Down
+-- Rollback the audit log
+
+DROP TABLE IF EXISTS audit_log;
@@ -0,0 +1,12 @@
+-- +goose Up
+-- Match wiki link titles ignoring case, spacing, dashes and underscores
+-- NOTE: This migration is idempotent and can be safely re-run
+
+-- note_title_key is registered by the API rather than stored in the schema, so indexes can't
+-- use it; link titles are matched by scanning the user's live notes.
+CREATE INDEX IF NOT EXISTS idx_notes_user_title ON notes(user_id, title) WHERE is_deleted = FALSE;
+
+-- +goose Down
+-- Rollback link title keys
+
+DROP INDEX IF EXISTS idx_notes_user_title;
@@ -0,0 +1,18 @@
+-- +goose Up
+-- Add typed custom fields per note type
+-- NOTE: This migration is idempotent and can be safely re-run
+
+-- Custom fields of a note type, stored in the metadata of its notes. Keyed by type name
+-- rather than referencing note_types, so built-in types can have fields too.
+CREATE TABLE IF NOT EXISTS note_type_fields (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    note_type VARCHAR(50) NOT NULL,
+    fields JSONB NOT NULL DEFAULT '[]',
+    updated_at TIMESTAMPTZ DEFAULT (strftime('%Y-%m-%dT%H:%M:%f', 'now') || '000Z'),
+    PRIMARY KEY (user_id, note_type)
+);
+
+-- +goose Down
+-- Rollback note type fields
+
+DROP TABLE IF EXISTS note_type_fields;
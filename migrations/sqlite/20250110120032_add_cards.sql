@@ -0,0 +1,61 @@
+-- +goose Up
+-- Add spaced repetition flashcards extracted from notes
+-- NOTE: This migration is idempotent and can be safely re-run
+
+-- Cards ("Q:: ... A:: ..." pairs, kept in sync whenever the note is saved). A card keeps its
+-- SM-2 schedule while its question stays the same; due_on is NULL until the first review.
+CREATE TABLE IF NOT EXISTS cards (
+    id UUID PRIMARY KEY DEFAULT (lower(hex(randomblob(4))) || '-' || lower(hex(randomblob(2))) || '-4' || substr(lower(hex(randomblob(2))), 2) || '-' || substr('89ab', abs(random()) % 4 + 1, 1) || substr(lower(hex(randomblob(2))), 2) || '-' || lower(hex(randomblob(6)))),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    note_id UUID NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+    line_number INT NOT NULL,
+    question TEXT NOT NULL,
+    answer TEXT NOT NULL,
+    ease_factor DOUBLE PRECISION NOT NULL DEFAULT 2.5,
+    interval_days INT NOT NULL DEFAULT 0,
+    repetitions INT NOT NULL DEFAULT 0,
+    due_on TEXT, -- YYYY-MM-DD
+    last_reviewed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT (strftime('%Y-%m-%dT%H:%M:%f', 'now') || '000Z'),
+    UNIQUE (note_id, question)
+);
+
+-- Indexes for cards (idempotent)
+CREATE INDEX IF NOT EXISTS idx_cards_user_due ON cards(user_id, due_on);
+
+-- Extract single-line cards from existing notes; answers on lines of their own are
+-- picked up the next time their note is saved. SQLite has no regular expressions, so lines are
+-- split by a recursive CTE and cut at the first "A::".
+WITH RECURSIVE lines(user_id, note_id, line_number, line, rest) AS (
+    SELECT user_id, id, 1,
+           substr(content || char(10), 1, instr(content || char(10), char(10)) - 1),
+           substr(content || char(10), instr(content || char(10), char(10)) + 1)
+    FROM notes
+    WHERE encrypted = false
+    UNION ALL
+    SELECT user_id, note_id, line_number + 1,
+           substr(rest, 1, instr(rest, char(10)) - 1),
+           substr(rest, instr(rest, char(10)) + 1)
+    FROM lines
+    WHERE rest <> ''
+),
+questions AS (
+    SELECT user_id, note_id, line_number, substr(ltrim(line, char(9, 11, 12, 13, 32)), 4) AS body
+    FROM lines
+    WHERE substr(ltrim(line, char(9, 11, 12, 13, 32)), 1, 3) = 'Q::'
+)
+INSERT INTO cards (user_id, note_id, line_number, question, answer)
+SELECT user_id, note_id, line_number,
+       trim(substr(body, 1, instr(body, 'A::') - 1), char(9, 11, 12, 13, 32)),
+       trim(substr(body, instr(body, 'A::') + 3), char(9, 11, 12, 13, 32))
+FROM questions
+WHERE instr(body, 'A::') > 1
+  AND trim(substr(body, 1, instr(body, 'A::') - 1), char(9, 11, 12, 13, 32)) <> ''
+  AND trim(substr(body, instr(body, 'A::') + 3), char(9, 11, 12, 13, 32)) <> ''
+ON CONFLICT (note_id, question) DO NOTHING;
+
+-- +goose Down
+-- Rollback cards
+
+DROP INDEX IF EXISTS idx_cards_user_due;
+DROP TABLE IF EXISTS cards;
@@ -0,0 +1,47 @@
+-- +goose Up
+-- Allow logging focus sessions as activity
+-- NOTE: This migration is idempotent and can be safely re-run
+
+-- A focus session is logged against its note with its length in metadata.duration_seconds
+-- SQLite can't change a CHECK, so the table is rebuilt with the new one.
+DROP TABLE IF EXISTS activity_log_new;
+CREATE TABLE activity_log_new (
+    id UUID PRIMARY KEY DEFAULT (lower(hex(randomblob(4))) || '-' || lower(hex(randomblob(2))) || '-4' || substr(lower(hex(randomblob(2))), 2) || '-' || substr('89ab', abs(random()) % 4 + 1, 1) || substr(lower(hex(randomblob(2))), 2) || '-' || lower(hex(randomblob(6)))),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    note_id UUID REFERENCES notes(id) ON DELETE CASCADE,
+    action VARCHAR(50) NOT NULL CHECK (action IN ('create', 'update', 'view', 'search', 'delete', 'login', 'logout', 'focus')),
+    metadata JSONB DEFAULT '{}',
+    created_at TIMESTAMPTZ DEFAULT (strftime('%Y-%m-%dT%H:%M:%f', 'now') || '000Z')
+);
+INSERT INTO activity_log_new (id, user_id, note_id, action, metadata, created_at)
+SELECT id, user_id, note_id, action, metadata, created_at FROM activity_log;
+DROP TABLE activity_log;
+ALTER TABLE activity_log_new RENAME TO activity_log;
+
+CREATE INDEX IF NOT EXISTS idx_activity_log_note_id ON activity_log(note_id);
+CREATE INDEX IF NOT EXISTS idx_activity_log_action ON activity_log(action);
+CREATE INDEX IF NOT EXISTS idx_activity_log_created_at ON activity_log(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_activity_log_user_created ON activity_log(user_id, created_at DESC);
+
+-- +goose Down
+-- Rollback focus activity
+
+DELETE FROM activity_log WHERE action = 'focus';
+DROP TABLE IF EXISTS activity_log_new;
+CREATE TABLE activity_log_new (
+    id UUID PRIMARY KEY DEFAULT (lower(hex(randomblob(4))) || '-' || lower(hex(randomblob(2))) || '-4' || substr(lower(hex(randomblob(2))), 2) || '-' || substr('89ab', abs(random()) % 4 + 1, 1) || substr(lower(hex(randomblob(2))), 2) || '-' || lower(hex(randomblob(6)))),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    note_id UUID REFERENCES notes(id) ON DELETE CASCADE,
+    action VARCHAR(50) NOT NULL CHECK (action IN ('create', 'update', 'view', 'search', 'delete', 'login', 'logout')),
+    metadata JSONB DEFAULT '{}',
+    created_at TIMESTAMPTZ DEFAULT (strftime('%Y-%m-%dT%H:%M:%f', 'now') || '000Z')
+);
+INSERT INTO activity_log_new (id, user_id, note_id, action, metadata, created_at)
+SELECT id, user_id, note_id, action, metadata, created_at FROM activity_log;
+DROP TABLE activity_log;
+ALTER TABLE activity_log_new RENAME TO activity_log;
+
+CREATE INDEX IF NOT EXISTS idx_activity_log_note_id ON activity_log(note_id);
+CREATE INDEX IF NOT EXISTS idx_activity_log_action ON activity_log(action);
+CREATE INDEX IF NOT EXISTS idx_activity_log_created_at ON activity_log(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_activity_log_user_created ON activity_log(user_id, created_at DESC);
@@ -0,0 +1,43 @@
+-- +goose Up
+-- Keep updated_at when only a note's read progress changes
+-- NOTE: This migration is idempotent and can be safely re-run
+
+-- Read progress is saved in metadata.read_progress as a note is scrolled; reading isn't an edit,
+-- so another column or metadata key has to change for updated_at to be bumped
+DROP TRIGGER IF EXISTS update_notes_updated_at;
+-- +goose StatementBegin
+CREATE TRIGGER update_notes_updated_at AFTER UPDATE ON notes
+    FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at AND (
+        NEW.user_id IS NOT OLD.user_id OR
+        NEW.title IS NOT OLD.title OR
+        NEW.content IS NOT OLD.content OR
+        NEW.note_type IS NOT OLD.note_type OR
+        NEW.word_count IS NOT OLD.word_count OR
+        NEW.reading_time_minutes IS NOT OLD.reading_time_minutes OR
+        NEW.is_deleted IS NOT OLD.is_deleted OR
+        NEW.deleted_at IS NOT OLD.deleted_at OR
+        NEW.created_at IS NOT OLD.created_at OR
+        NEW.last_accessed_at IS NOT OLD.last_accessed_at OR
+        NEW.access_count IS NOT OLD.access_count OR
+        NEW.encrypted IS NOT OLD.encrypted OR
+        json_remove(COALESCE(NEW.metadata, '{}'), '$.read_progress') IS NOT json_remove(COALESCE(OLD.metadata, '{}'), '$.read_progress')
+    )
+BEGIN
+    UPDATE notes SET updated_at = strftime('%Y-%m-%dT%H:%M:%f', 'now') || '000Z' WHERE id = NEW.id;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+-- Rollback read progress
+
+-- Removed while the trigger above still ignores read progress, so updated_at is kept
+UPDATE notes SET metadata = json_remove(metadata, '$.read_progress') WHERE json_type(metadata, '$.read_progress') IS NOT NULL;
+
+DROP TRIGGER IF EXISTS update_notes_updated_at;
+-- +goose StatementBegin
+CREATE TRIGGER update_notes_updated_at AFTER UPDATE ON notes
+    FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+    UPDATE notes SET updated_at = strftime('%Y-%m-%dT%H:%M:%f', 'now') || '000Z' WHERE id = NEW.id;
+END;
+-- +goose StatementEnd
@@ -0,0 +1,53 @@
+-- +goose Up
+-- Keep updated_at when a note is only viewed
+-- NOTE: This migration is idempotent and can be safely re-run
+
+-- Opening a note bumps access_count and last_accessed_at; a view isn't an edit, so like read
+-- progress they're left out when deciding whether the row changed. Otherwise every view would
+-- change the note's ETag and mark its embedding stale.
+DROP TRIGGER IF EXISTS update_notes_updated_at;
+-- +goose StatementBegin
+CREATE TRIGGER update_notes_updated_at AFTER UPDATE ON notes
+    FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at AND (
+        NEW.user_id IS NOT OLD.user_id OR
+        NEW.title IS NOT OLD.title OR
+        NEW.content IS NOT OLD.content OR
+        NEW.note_type IS NOT OLD.note_type OR
+        NEW.word_count IS NOT OLD.word_count OR
+        NEW.reading_time_minutes IS NOT OLD.reading_time_minutes OR
+        NEW.is_deleted IS NOT OLD.is_deleted OR
+        NEW.deleted_at IS NOT OLD.deleted_at OR
+        NEW.created_at IS NOT OLD.created_at OR
+        NEW.encrypted IS NOT OLD.encrypted OR
+        json_remove(COALESCE(NEW.metadata, '{}'), '$.read_progress') IS NOT json_remove(COALESCE(OLD.metadata, '{}'), '$.read_progress')
+    )
+BEGIN
+    UPDATE notes SET updated_at = strftime('%Y-%m-%dT%H:%M:%f', 'now') || '000Z' WHERE id = NEW.id;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+-- Rollback keeping updated_at on views
+
+DROP TRIGGER IF EXISTS update_notes_updated_at;
+-- +goose StatementBegin
+CREATE TRIGGER update_notes_updated_at AFTER UPDATE ON notes
+    FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at AND (
+        NEW.user_id IS NOT OLD.user_id OR
+        NEW.title IS NOT OLD.title OR
+        NEW.content IS NOT OLD.content OR
+        NEW.note_type IS NOT OLD.note_type OR
+        NEW.word_count IS NOT OLD.word_count OR
+        NEW.reading_time_minutes IS NOT OLD.reading_time_minutes OR
+        NEW.is_deleted IS NOT OLD.is_deleted OR
+        NEW.deleted_at IS NOT OLD.deleted_at OR
+        NEW.created_at IS NOT OLD.created_at OR
+        NEW.last_accessed_at IS NOT OLD.last_accessed_at OR
+        NEW.access_count IS NOT OLD.access_count OR
+        NEW.encrypted IS NOT OLD.encrypted OR
+        json_remove(COALESCE(NEW.metadata, '{}'), '$.read_progress') IS NOT json_remove(COALESCE(OLD.metadata, '{}'), '$.read_progress')
+    )
+BEGIN
+    UPDATE notes SET updated_at = strftime('%Y-%m-%dT%H:%M:%f', 'now') || '000Z' WHERE id = NEW.id;
+END;
+-- +goose StatementEnd
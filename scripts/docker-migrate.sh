@@ -55,7 +55,7 @@ check_container() {
 
 # Execute command in container
 exec_in_container() {
-    docker exec "${CONTAINER_NAME}" ./migrate "$@"
+    docker exec "${CONTAINER_NAME}" ./api migrate "$@"
 }
 
 # Execute command
@@ -78,7 +78,8 @@ case $COMMAND in
     redo)
         check_container
         echo -e "${YELLOW}Redoing last migration in container...${NC}"
-        exec_in_container redo
+        exec_in_container down
+        exec_in_container up
         ;;
     create)
         echo -e "${RED}Error: Cannot create migrations inside Docker container${NC}"
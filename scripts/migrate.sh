@@ -67,7 +67,7 @@ fi
 # Build the migrate binary first
 echo -e "${YELLOW}Building migrate binary...${NC}"
 cd "$(dirname "$0")/.."
-go build -o /tmp/kg-migrate ./migrations
+go build -o /tmp/kg-migrate ./cmd/migrate
 
 # Execute command
 case $COMMAND in